@@ -0,0 +1,6 @@
+// Package datasleuthv1 is the gRPC API of DataSleuth and its generated Go
+// client; see datasleuth.proto. Clients in other languages are generated from
+// the same file.
+package datasleuthv1
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative datasleuth.proto
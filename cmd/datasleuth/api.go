@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/kamalm96/datasleuth/internal/server"
+	"github.com/spf13/cobra"
+)
+
+// apiTokenEnv holds the bearer token of the API when --token is not given.
+const apiTokenEnv = "DATASLEUTH_API_TOKEN"
+
+var apiCmd = &cobra.Command{
+	Use:   "api",
+	Short: "Serve profiling and validation as a JSON API",
+	Long: `Start an HTTP server with a JSON API, so that services can profile and
+validate datasets by calling DataSleuth as a sidecar instead of running the
+CLI. POST /profile profiles a path, URL or uploaded file, GET /profiles/{id}
+returns a profile as a JSON report and POST /validate checks a dataset
+against a baseline profile.
+
+With --grpc-addr the gRPC API of api/datasleuth/v1 is served as well: Profile,
+Validate and Compare calls that stream progress while sources are read, for
+typed clients generated from datasleuth.proto.`,
+	Example: `  datasleuth api
+  DATASLEUTH_API_TOKEN=s3cret datasleuth api --addr :8090
+  datasleuth api --grpc-addr 127.0.0.1:9090`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		addr, _ := cmd.Flags().GetString("addr")
+		grpcAddr, _ := cmd.Flags().GetString("grpc-addr")
+		token, _ := cmd.Flags().GetString("token")
+		if token == "" {
+			token = os.Getenv(apiTokenEnv)
+		}
+
+		fmt.Printf("DataSleuth v%s - Fast dataset profiling and validation\n", version)
+		fmt.Println("────────────────────────────────────────────────────────────────────────────────")
+
+		config := serverConfig(cmd)
+		if config.History != nil {
+			defer config.History.Close()
+		}
+		config.Token = token
+		srv := server.New(config)
+
+		fmt.Printf("\n🔌 API listening on http://%s (press Ctrl+C to stop)\n", displayAddr(addr))
+		if grpcAddr != "" {
+			listener, err := net.Listen("tcp", grpcAddr)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error listening on %s: %v\n", grpcAddr, err)
+				os.Exit(1)
+			}
+			fmt.Printf("🔌 gRPC API listening on %s\n", displayAddr(grpcAddr))
+			go func() {
+				if err := srv.GRPCServer().Serve(listener); err != nil {
+					fmt.Fprintf(os.Stderr, "Error serving gRPC: %v\n", err)
+					os.Exit(1)
+				}
+			}()
+		}
+		if token == "" {
+			fmt.Println("   No --token set: every client that can reach the address may call the API")
+		}
+		if err := http.ListenAndServe(addr, srv.APIHandler()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error serving: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(apiCmd)
+
+	apiCmd.Flags().String("addr", "127.0.0.1:8090", "Address to listen on; use :8090 to accept connections from other machines")
+	apiCmd.Flags().String("grpc-addr", "", "Also serve the gRPC API on this address, e.g. 127.0.0.1:9090")
+	apiCmd.Flags().String("token", "", "Bearer token clients must send; read from $"+apiTokenEnv+" when not set")
+	apiCmd.Flags().Bool("no-history", false, "Keep profiles in memory instead of recording them in the profile history")
+	apiCmd.Flags().String("max-upload", "512MB", "Largest file accepted for upload, e.g. 2GB")
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/batch"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/kamalm96/datasleuth/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch [manifest.yaml]",
+	Short: "Profile and validate the sources listed in a manifest",
+	Long: `Profile many sources in one run, as listed in a YAML manifest, instead of
+looping over profile and validate in a shell script. Each source has its own
+profile options, rules file, quality score weights, quality gate and report
+files:
+
+  jobs: 4
+  sources:
+    - source: exports/orders.csv
+      unique_key: [order_id]
+      rules: rules/orders.yaml
+      fail_below: 80
+      outputs: [reports/orders.json, reports/orders.html]
+    - name: users
+      source: app.db
+      table: users
+      sample: 100000
+      scoring: strict
+      max_missing: 5
+
+Sources are profiled concurrently, --jobs (or the jobs of the manifest) at a
+time, and a summary of them all is printed at the end; the json and markdown
+formats also write it to a file. Relative paths are relative to the manifest.
+A delimiter, null values, sample or rules file a source leaves out comes from
+the defaults and datasets of .datasleuth.yaml. The run exits with status 1
+when a source fails or breaks its rules, and 20 when the others pass but a
+quality gate fails.`,
+	Example: `  datasleuth batch manifest.yaml
+  datasleuth batch manifest.yaml --jobs 8
+  datasleuth batch manifest.yaml --output json --output-file batch.json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		manifestFile := args[0]
+		jobs, _ := cmd.Flags().GetInt("jobs")
+		outputFormat, _ := cmd.Flags().GetString("output")
+		outputFile, _ := cmd.Flags().GetString("output-file")
+		noHistory, _ := cmd.Flags().GetBool("no-history")
+
+		if jobs < 0 {
+			fmt.Fprintf(os.Stderr, "Invalid --jobs %d: must not be negative\n", jobs)
+			os.Exit(1)
+		}
+		if outputFormat != "terminal" && outputFormat != "json" && outputFormat != "markdown" {
+			fmt.Fprintf(os.Stderr, "Unsupported output format: %s\n", outputFormat)
+			os.Exit(1)
+		}
+		jsonToStdout(outputFile, outputFormat)
+
+		manifest, err := batch.Load(manifestFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading manifest: %v\n", err)
+			os.Exit(1)
+		}
+		cfg := readConfig(cmd)
+		slas := cfg.SLAs
+		if cfg.Scoring != nil {
+			manifest.UseScoring(cfg.Scoring)
+		}
+		manifest.UseDateFormats(cfg.DateFormats)
+		manifest.UseSettings(cfg)
+
+		if planning(cmd) {
+			plan := newCommandPlan(cmd)
+			plan.Jobs = manifest.Workers(jobs)
+			for _, source := range manifest.Sources {
+				p, err := profiler.PlanProfile(source.Source, source.Options())
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error planning %s: %v\n", source.Name, err)
+					os.Exit(1)
+				}
+				plan.Sources = append(plan.Sources, sourcePlan{Name: source.Name, Plan: p, Rules: source.Rules, Outputs: source.Outputs})
+			}
+			plan.print()
+			return
+		}
+
+		fmt.Printf("DataSleuth v%s - Fast dataset profiling and validation\n", version)
+		fmt.Println("────────────────────────────────────────────────────────────────────────────────")
+		fmt.Printf("\n📦 Batch: %s (%d sources)\n\n", manifestFile, len(manifest.Sources))
+
+		ctx, cancel := runContext(cmd)
+		defer cancel()
+
+		startTime := time.Now()
+		results := batch.Run(ctx, manifest, jobs, writeBatchReport, func(result batch.Result) {
+			mark := "✓"
+			if !result.Passed() {
+				mark = "✗"
+			}
+			fmt.Printf("   %s %s (%.2fs)\n", mark, result.Source.Name, result.Elapsed.Seconds())
+		})
+		fmt.Println()
+		report.PrintBatchSummary(results, time.Since(startTime))
+
+		failed, gateFailed := false, false
+		for _, result := range results {
+			if result.Profile != nil && !noHistory {
+				recordHistory(result.Source.Source, []*profiler.DatasetProfile{result.Profile}, slas)
+			}
+			if result.Err != nil || !result.RulesPassed() {
+				failed = true
+			} else if !result.GatePassed() {
+				gateFailed = true
+			}
+		}
+
+		switch outputFormat {
+		case "json", "markdown":
+			ext, generate := "json", report.GenerateBatchJSONReport
+			if outputFormat == "markdown" {
+				ext, generate = "md", report.GenerateBatchMarkdownReport
+			}
+			file := outputFile
+			if file == "" {
+				file = fmt.Sprintf("%s_batch.%s", strings.TrimSuffix(filepath.Base(manifestFile), filepath.Ext(manifestFile)), ext)
+			}
+			if err := generate(manifestFile, results, file); err != nil {
+				fmt.Fprintf(os.Stderr, "Error generating batch report: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Batch summary saved to: %s\n", file)
+		}
+
+		exitStopped(ctx)
+		if failed {
+			os.Exit(1)
+		}
+		if gateFailed {
+			os.Exit(gateExitCode)
+		}
+	},
+}
+
+// writeBatchReport writes the report of a source of a batch run in the
+// format of the file extension of path.
+func writeBatchReport(profile *profiler.DatasetProfile, path string) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return report.GenerateJSONReport(profile, path)
+	case ".html":
+		return report.GenerateHTMLReport(profile, path)
+	case ".md":
+		return report.GenerateMarkdownReport(profile, path)
+	}
+	return fmt.Errorf("unsupported report format: %s", path)
+}
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+
+	batchCmd.Flags().Int("jobs", 0, "Sources profiled at once (default: jobs of the manifest, or the number of CPUs)")
+	batchCmd.Flags().StringP("output", "o", "terminal", "Output format of the summary: terminal, json, markdown")
+	batchCmd.Flags().String("output-file", "", "Save the summary to this file, or - to write a JSON summary to stdout (default: <manifest>_batch.json or .md)")
+	batchCmd.Flags().Duration("timeout", 0, "Stop after this long, failing the sources not yet profiled (0 = no limit)")
+	batchCmd.Flags().Bool("no-history", false, "Do not record the profiles in the profile history")
+	batchCmd.Flags().String("project-config", "", projectConfigUsage)
+	addPlanFlag(batchCmd)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/kamalm96/datasleuth/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var docsCmd = &cobra.Command{
+	Use:   "docs [file]",
+	Short: "Generate Markdown documentation for a dataset",
+	Long: `Profile a dataset and write a README describing it: a schema table,
+column descriptions, example values and key quality statistics.
+Descriptions come from a data dictionary (JSON or CSV) and from
+--annotate flags, which take precedence.`,
+	Example: `  datasleuth docs data.csv -o dataset.md
+  datasleuth docs data.csv --dictionary columns.csv --redact email,phone
+  datasleuth docs data.parquet --annotate "id=Primary key" --annotate "amount=Order total in USD"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		source := args[0]
+		outputFile, _ := cmd.Flags().GetString("output")
+		dictionaryFile, _ := cmd.Flags().GetString("dictionary")
+		annotations, _ := cmd.Flags().GetStringArray("annotate")
+		redact, _ := cmd.Flags().GetStringSlice("redact")
+		examples, _ := cmd.Flags().GetInt("examples")
+		kAnonymity, _ := cmd.Flags().GetInt("k-anonymity")
+
+		fmt.Printf("DataSleuth v%s - Fast dataset profiling and validation\n", version)
+		fmt.Println("────────────────────────────────────────────────────────────────────────────────")
+		fmt.Printf("\n📝 Documenting dataset: %s\n\n", source)
+
+		descriptions := make(map[string]string)
+		if dictionaryFile != "" {
+			dictionary, err := report.LoadDataDictionary(dictionaryFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading data dictionary %s: %v\n", dictionaryFile, err)
+				os.Exit(1)
+			}
+			descriptions = dictionary
+		}
+
+		for _, annotation := range annotations {
+			name, description, ok := strings.Cut(annotation, "=")
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Invalid --annotate %q: use column=description\n", annotation)
+				os.Exit(1)
+			}
+			descriptions[strings.TrimSpace(name)] = strings.TrimSpace(description)
+		}
+
+		profile, err := profiler.ProfileDatasetWithOptions(source, profiler.Options{KAnonymity: kAnonymity})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error profiling dataset: %v\n", err)
+			os.Exit(1)
+		}
+
+		for name := range descriptions {
+			if _, ok := profile.Columns[name]; !ok {
+				fmt.Fprintf(os.Stderr, "Warning: description for unknown column %s ignored\n", name)
+			}
+		}
+
+		if outputFile == "" {
+			outputFile = fmt.Sprintf("%s_docs.md", profile.Filename)
+		}
+
+		opts := report.DocsOptions{
+			Descriptions: descriptions,
+			Redact:       redact,
+			Examples:     examples,
+		}
+		if err := report.GenerateDocsReport(profile, opts, outputFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating documentation: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Dataset documentation saved to: %s\n", outputFile)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(docsCmd)
+
+	docsCmd.Flags().StringP("output", "o", "", "Markdown file to write (default <file>_docs.md)")
+	docsCmd.Flags().String("dictionary", "", "Data dictionary with column descriptions (JSON object or CSV of column,description)")
+	docsCmd.Flags().StringArray("annotate", nil, "Column description as column=description (repeatable)")
+	docsCmd.Flags().StringSlice("redact", nil, "Columns whose example values are hidden, * for all")
+	docsCmd.Flags().Int("examples", report.DefaultDocsExamples, "Example values shown per column")
+	docsCmd.Flags().Int("k-anonymity", 0, "Hide example values seen fewer than this many times (0 = show all)")
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kamalm96/datasleuth/internal/events"
+	"github.com/kamalm96/datasleuth/internal/report"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// startEventFrontends subscribes the terminal progress line and the event
+// log of --event-log to the events the profiler publishes. The progress line
+// is only drawn when stderr is a terminal, so it never ends up in a log.
+func startEventFrontends(cmd *cobra.Command) error {
+	noProgress, _ := cmd.Flags().GetBool("no-progress")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	eventLog, _ := cmd.Flags().GetString("event-log")
+
+	if !noProgress && !quiet && isatty.IsTerminal(os.Stderr.Fd()) {
+		events.Default.Handle(report.NewProgressPrinter(os.Stderr).Handle)
+	}
+
+	if eventLog != "" {
+		// Left open for the life of the process; writes are unbuffered
+		file, err := os.OpenFile(eventLog, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to open event log: %w", err)
+		}
+		events.Default.Handle(report.NewEventLogger(file).Handle)
+	}
+
+	return nil
+}
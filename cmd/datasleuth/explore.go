@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/explore"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/kamalm96/datasleuth/internal/report"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+var exploreCmd = &cobra.Command{
+	Use:   "explore [file|url|report.json]",
+	Short: "Browse a profile interactively in the terminal",
+	Long: `Profile a dataset, or load a JSON report written by profile --output json,
+and browse it in a terminal UI: the columns are listed on the left with their
+missing values and issues, and the selected column's statistics, histogram
+and top values are shown on the right.
+
+Keys: ↑/↓ or j/k move, PgUp/PgDn page, g/G first/last, J/K scroll the
+details, / search column names, Esc clear the search, s sort by name,
+missing % or issue severity, q quit.`,
+	Example: `  datasleuth explore data.csv
+  datasleuth explore warehouse.db --table orders
+  datasleuth explore profile_report.json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		source := args[0]
+		table, _ := cmd.Flags().GetString("table")
+		sampleSize, _ := cmd.Flags().GetInt("sample")
+
+		if !isatty.IsTerminal(os.Stdout.Fd()) {
+			fmt.Fprintln(os.Stderr, "Error: explore needs a terminal; use profile --verbose for a static report")
+			os.Exit(1)
+		}
+
+		var profile *profiler.DatasetProfile
+		var err error
+		if strings.EqualFold(filepath.Ext(source), ".json") {
+			profile, err = report.LoadJSONReport(source)
+		} else {
+			opts := profiler.Options{SampleSize: sampleSize, Examples: 5, ExactRows: 1000}
+			if profiler.IsExcel(source) {
+				opts.Sheet = table
+			} else {
+				opts.Table = table
+			}
+			fmt.Fprintf(os.Stderr, "Profiling %s...\n", source)
+			profile, err = profiler.ProfileDatasetWithOptions(source, opts)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error profiling dataset: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := explore.Run(profile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running explorer: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exploreCmd)
+
+	exploreCmd.Flags().String("table", "", "Table of a SQLite database or sheet of an Excel workbook to explore, required when it has several")
+	exploreCmd.Flags().IntP("sample", "s", 0, "Use a sample of rows (0 = all rows)")
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/kamalm96/datasleuth/internal/report"
+	"github.com/kamalm96/datasleuth/internal/validate"
+)
+
+// fileJobs is how many of n files are profiled at once when asked for jobs,
+// one per CPU when 0.
+func fileJobs(jobs, n int) int {
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	return min(jobs, n)
+}
+
+// profileFiles profiles several files, jobs at a time, and prints a summary
+// of them all, writing a combined report for the other output formats. A
+// file that fails to profile is reported with the others and fails the run
+// at the end. Files are profiled until ctx is done; those not profiled by
+// then fail, and the run exits as stopped. Each file is profiled with the
+// options optionsFor gives it.
+func profileFiles(ctx context.Context, sources []string, optionsFor func(string) profiler.Options, jobs int, outputFormat, outputFile string, maxSeverity int, gate validate.Gate, slas []validate.SLA, signer crypto.Signer, record bool) {
+	startTime := time.Now()
+	jobs = fileJobs(jobs, len(sources))
+
+	files := make([]report.FileProfile, len(sources))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				files[i] = profileFile(ctx, sources[i], optionsFor(sources[i]))
+			}
+		}()
+	}
+	for i := range sources {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	var profiles []*profiler.DatasetProfile
+	failed := 0
+	for _, file := range files {
+		if file.Err != nil {
+			failed++
+			continue
+		}
+		profiles = append(profiles, file.Profiles...)
+		if record {
+			recordHistory(file.Source, file.Profiles, slas)
+		}
+	}
+
+	fmt.Println()
+	report.PrintFilesSummary(files, time.Since(startTime))
+	report.PrintConsensus(files)
+
+	writeFilesReport := func(kind, ext string, generate func([]report.FileProfile, string) error) string {
+		file := outputFile
+		if file == "" {
+			file = "combined_profile." + ext
+		}
+		if err := generate(files, file); err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating %s report: %v\n", kind, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Combined %s report saved to: %s\n", kind, file)
+		return file
+	}
+
+	switch outputFormat {
+	case "terminal":
+	case "html":
+		writeFilesReport("HTML", "html", report.GenerateFilesHTMLReport)
+	case "markdown":
+		writeFilesReport("Markdown", "md", report.GenerateFilesMarkdownReport)
+	case "json":
+		signReports(signer, writeFilesReport("JSON", "json", report.GenerateFilesJSONReport))
+	case "github":
+		report.WriteGitHubFilesAnnotations(stdout, files)
+		written, err := report.AppendGitHubSummary(outputFile, func(w io.Writer) error {
+			return report.WriteGitHubSummary(w, fmt.Sprintf("%d files", len(files)), profiles)
+		})
+		reportGitHubSummary(written, err)
+	default:
+		fmt.Fprintf(os.Stderr, "Unsupported output format: %s\n", outputFormat)
+		os.Exit(1)
+	}
+
+	printSLAs(profiles, slas)
+	exitStopped(ctx)
+	checkProfiles(profiles, maxSeverity, gate)
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d of %d files could not be profiled\n", failed, len(files))
+		os.Exit(1)
+	}
+}
+
+// profileFile profiles one of several files: every table of a database and
+// every sheet of a workbook, or the file itself. A file stopped part way is
+// kept with the profiles of the rows read.
+func profileFile(ctx context.Context, source string, opts profiler.Options) report.FileProfile {
+	file := report.FileProfile{Source: profiler.RedactSource(source)}
+	switch {
+	case profiler.IsSQLite(source):
+		file.Profiles, file.Err = profiler.ProfileSQLiteTables(ctx, source, opts)
+	case profiler.IsExcel(source):
+		file.Profiles, file.Err = profiler.ProfileExcelSheets(ctx, source, opts)
+	default:
+		var profile *profiler.DatasetProfile
+		if profile, file.Err = profiler.ProfileDatasetContext(ctx, source, opts); profile != nil {
+			file.Profiles = []*profiler.DatasetProfile{profile}
+		}
+	}
+	if len(file.Profiles) > 0 && stoppedEarly(file.Err) {
+		file.Err = nil
+	}
+	return file
+}
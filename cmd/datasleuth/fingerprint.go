@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/fingerprint"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/kamalm96/datasleuth/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var fingerprintCmd = &cobra.Command{
+	Use:   "fingerprint [file|url|-]",
+	Short: "Write salted MinHash signatures of the columns of a dataset for overlap checks",
+	Long: `Profile a dataset and write a JSON fingerprint holding, for each column, a
+MinHash signature of its distinct values: the smallest of their hashes salted
+with a secret. The fingerprint holds no values, so two parties can exchange
+fingerprints and estimate how many identifiers, such as customer emails,
+their datasets share without either handing over its data. Pass two
+fingerprints to compare to see the columns that share values.
+
+Both parties must fingerprint with the same salt, agreed on out of band and
+given with --salt or $DATASLEUTH_SALT. Anyone who knows the salt can test
+whether a guessed value is in a fingerprint, so keep it between the parties.
+Values are trimmed and lower cased before hashing. Overlaps of columns with
+fewer distinct values than --size are exact; larger ones are estimated, with
+an error that halves for every fourfold --size.`,
+	Example: `  export DATASLEUTH_SALT=<secret agreed with the partner>
+  datasleuth fingerprint customers.csv --columns email,phone -o ours.json
+  datasleuth compare ours.json partner_fingerprint.json
+  datasleuth fingerprint warehouse.db --table users --size 1024`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		source := args[0]
+		salt, _ := cmd.Flags().GetString("salt")
+		size, _ := cmd.Flags().GetInt("size")
+		columns, _ := cmd.Flags().GetStringSlice("columns")
+		table, _ := cmd.Flags().GetString("table")
+		format, _ := cmd.Flags().GetString("format")
+		outputFile, _ := cmd.Flags().GetString("output")
+
+		if salt == "" {
+			salt = os.Getenv(profiler.SaltEnv)
+		}
+		if salt == "" {
+			fmt.Fprintf(os.Stderr, "Missing salt: pass --salt or set %s to a secret agreed with whoever the fingerprint is compared with\n", profiler.SaltEnv)
+			os.Exit(1)
+		}
+		if size < 1 {
+			fmt.Fprintf(os.Stderr, "Invalid --size %d: keep at least 1 hash per column\n", size)
+			os.Exit(1)
+		}
+
+		fmt.Printf("DataSleuth v%s - Fast dataset profiling and validation\n", version)
+		fmt.Println("────────────────────────────────────────────────────────────────────────────────")
+
+		opts := profiler.Options{
+			Format:      format,
+			Password:    os.Getenv(profiler.PasswordEnv),
+			MinHash:     size,
+			MinHashSalt: salt,
+		}
+		if profiler.IsExcel(source) {
+			opts.Sheet = table
+		} else {
+			opts.Table = table
+		}
+
+		ctx, cancel := runContext(cmd)
+		defer cancel()
+
+		profile, err := profiler.ProfileDatasetContext(ctx, source, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error profiling %s: %v\n", source, err)
+			exitStopped(ctx)
+			os.Exit(1)
+		}
+		if profile.Interrupted {
+			// A partial fingerprint would understate the overlap
+			fmt.Fprintf(os.Stderr, "Error fingerprinting %s: stopped before the end of the data\n", source)
+			exitStopped(ctx)
+			os.Exit(1)
+		}
+
+		fp, err := fingerprint.FromProfile(profile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fingerprinting %s: %v\n", source, err)
+			os.Exit(1)
+		}
+		if len(columns) > 0 {
+			for _, name := range columns {
+				if _, ok := profile.Columns[name]; !ok {
+					fmt.Fprintf(os.Stderr, "Invalid --columns: %s has no column %s\n", source, name)
+					os.Exit(1)
+				}
+			}
+			fp.Columns = slices.DeleteFunc(fp.Columns, func(col fingerprint.Column) bool {
+				return !slices.Contains(columns, col.Name)
+			})
+		}
+
+		fmt.Printf("\n📊 Dataset: %s (%d rows)\n\n", fp.Source, fp.Rows)
+		report.PrintFingerprintSummary(fp)
+
+		if outputFile == "" {
+			outputFile = fingerprintFileName(source)
+		}
+		if err := fingerprint.Write(fp, outputFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing fingerprint: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Column fingerprint saved to: %s\n", outputFile)
+	},
+}
+
+// fingerprintFileName names the fingerprint after the source file.
+func fingerprintFileName(source string) string {
+	name := filepath.Base(source)
+	if source == "-" {
+		name = "stdin"
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name)) + "_fingerprint.json"
+}
+
+// compareFingerprints reports the columns of two fingerprints that share
+// values.
+func compareFingerprints(source1, source2 string) {
+	base, err := fingerprint.Load(source1)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading fingerprint %s: %v\n", source1, err)
+		os.Exit(1)
+	}
+
+	target, err := fingerprint.Load(source2)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading fingerprint %s: %v\n", source2, err)
+		os.Exit(1)
+	}
+
+	overlap, err := fingerprint.Compare(base, target)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error comparing fingerprints: %v\n", err)
+		os.Exit(1)
+	}
+	report.PrintOverlapReport(overlap)
+}
+
+func init() {
+	rootCmd.AddCommand(fingerprintCmd)
+
+	fingerprintCmd.Flags().String("salt", "", "Secret salt shared with whoever the fingerprint is compared with (default: $"+profiler.SaltEnv+")")
+	fingerprintCmd.Flags().Int("size", profiler.DefaultMinHashSize, "Hashes kept per column; the error of an estimate falls with its square root")
+	fingerprintCmd.Flags().StringSlice("columns", nil, "Columns to fingerprint (default: all)")
+	fingerprintCmd.Flags().String("table", "", "Table of a SQLite database or sheet of an Excel workbook to fingerprint")
+	fingerprintCmd.Flags().String("format", "", "Input format: csv, tsv, jsonl, delta or iceberg (default: from the file extension, csv for stdin)")
+	fingerprintCmd.Flags().StringP("output", "o", "", "JSON file to write (default <file>_fingerprint.json)")
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kamalm96/datasleuth/internal/fixture"
+	"github.com/kamalm96/datasleuth/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var genFixtureCmd = &cobra.Command{
+	Use:   "gen-fixture",
+	Short: "Generate test fixture code from a profile",
+	Long: `Generate source code for test fixtures from a JSON profile: a struct
+mirroring the dataset's columns and a function producing fake rows with the
+same shape - value frequencies, numeric ranges, uniqueness and missing rates.`,
+	Example: `  datasleuth profile orders.csv --output json --output-file orders.json
+  datasleuth gen-fixture --from orders.json --lang go -o orders_fixture.go
+  datasleuth gen-fixture --from orders.json --package testdata --type Order`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		from, _ := cmd.Flags().GetString("from")
+		lang, _ := cmd.Flags().GetString("lang")
+		outputFile, _ := cmd.Flags().GetString("output")
+		pkg, _ := cmd.Flags().GetString("package")
+		typeName, _ := cmd.Flags().GetString("type")
+
+		if from == "" {
+			fmt.Fprintln(os.Stderr, "Missing --from: a JSON profile from datasleuth profile --output json")
+			os.Exit(1)
+		}
+		if lang != fixture.LangGo {
+			fmt.Fprintf(os.Stderr, "Unsupported language: %s (supported: go)\n", lang)
+			os.Exit(1)
+		}
+
+		profile, err := report.LoadJSONReport(from)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading profile %s: %v\n", from, err)
+			os.Exit(1)
+		}
+
+		src, err := fixture.GenerateGo(profile, fixture.Options{Package: pkg, TypeName: typeName})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating fixture: %v\n", err)
+			os.Exit(1)
+		}
+
+		if outputFile == "" {
+			stdout.Write(src)
+			return
+		}
+
+		if err := os.WriteFile(outputFile, src, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing fixture: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Fixture code saved to: %s\n", outputFile)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(genFixtureCmd)
+
+	genFixtureCmd.Flags().String("from", "", "JSON profile to generate fixtures from")
+	genFixtureCmd.Flags().String("lang", fixture.LangGo, "Language of the generated code: go")
+	genFixtureCmd.Flags().StringP("output", "o", "", "File to write (default: stdout)")
+	genFixtureCmd.Flags().String("package", "fixtures", "Package name of the generated code")
+	genFixtureCmd.Flags().String("type", "", "Name of the generated struct (default: from the dataset file name)")
+}
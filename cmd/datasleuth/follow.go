@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/compare"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/kamalm96/datasleuth/internal/report"
+	"github.com/mattn/go-isatty"
+)
+
+// followAlerts is the number of most recent alerts kept on screen while
+// following a file.
+const followAlerts = 10
+
+// followProfile follows source until interrupted, printing a summary of the
+// latest window whenever records are appended. A shift from the window before
+// is alerted when it starts, and again while it holds only once the windows
+// no longer overlap the one that raised it. A positive timeout stops
+// following after that long.
+func followProfile(source string, opts profiler.Options, follow profiler.FollowOptions, timeout time.Duration) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	redraw := isatty.IsTerminal(os.Stdout.Fd())
+	fmt.Printf("\n📡 Following %s every %s, Ctrl+C to stop\n\n", source, follow.Interval)
+
+	raised := make(map[string]int) // records read when each holding shift was alerted
+	alerts := make([]string, 0)
+	err := profiler.Follow(ctx, source, opts, follow, func(update profiler.FollowUpdate) error {
+		holding := make(map[string]int)
+		if update.Previous != nil && !update.Reset {
+			for _, shift := range compare.Shifts(update.Previous, update.Window) {
+				at, ok := raised[shift.Key()]
+				if !ok || update.Records-at >= follow.Window {
+					alerts = append(alerts, fmt.Sprintf("%s %s", update.Time.Local().Format("15:04:05"), shift.Message))
+					at = update.Records
+				}
+				holding[shift.Key()] = at
+			}
+		}
+		raised = holding
+		if len(alerts) > followAlerts {
+			alerts = alerts[len(alerts)-followAlerts:]
+		}
+
+		if redraw {
+			// Move home and clear the screen
+			fmt.Print("\033[H\033[2J")
+		}
+		report.PrintFollowSummary(update, alerts)
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error following dataset: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kamalm96/datasleuth/internal/validate"
+	"github.com/spf13/cobra"
+)
+
+// gateExitCode is the exit code of a run that fails its quality gate.
+const gateExitCode = 20
+
+// addGateFlags adds the quality gate flags to cmd, with --max-drift for the
+// commands that compare two datasets.
+func addGateFlags(cmd *cobra.Command, drift bool) {
+	cmd.Flags().Int("fail-below", 0, "Fail when the quality score is below this (0-100, 0 = off)")
+	cmd.Flags().Float64("max-missing", 0, "Fail when a column has more than this percentage of missing values (default: off)")
+	cmd.Flags().Float64("max-duplicates", 0, "Fail when more than this percentage of rows are duplicates (default: off)")
+	if drift {
+		cmd.Flags().Float64("max-drift", 0, "Fail when more than this percentage of columns drifted (default: off)")
+	}
+}
+
+// readGate reads the quality gate flags of cmd. Percentages are only checked
+// when given, so that 0 can ask for no missing values at all.
+func readGate(cmd *cobra.Command) validate.Gate {
+	gate := validate.NoGate()
+	gate.MinScore, _ = cmd.Flags().GetInt("fail-below")
+	if gate.MinScore < 0 || gate.MinScore > 100 {
+		fmt.Fprintf(os.Stderr, "Invalid --fail-below %d: use a quality score from 0 to 100\n", gate.MinScore)
+		os.Exit(1)
+	}
+
+	for _, f := range []struct {
+		name   string
+		target *float64
+	}{
+		{"max-missing", &gate.MaxMissing},
+		{"max-duplicates", &gate.MaxDuplicate},
+		{"max-drift", &gate.MaxDrift},
+	} {
+		if cmd.Flags().Lookup(f.name) == nil || !cmd.Flags().Changed(f.name) {
+			continue
+		}
+		*f.target, _ = cmd.Flags().GetFloat64(f.name)
+		if *f.target < 0 || *f.target > 100 {
+			fmt.Fprintf(os.Stderr, "Invalid --%s %g: use a percentage from 0 to 100\n", f.name, *f.target)
+			os.Exit(1)
+		}
+	}
+	return gate
+}
+
+// checkGate prints the failed checks of the quality gate of name to stderr
+// and reports whether it passed.
+func checkGate(name string, checks []validate.Check) bool {
+	var failures []validate.Check
+	for _, check := range checks {
+		if !check.Passed {
+			failures = append(failures, check)
+		}
+	}
+
+	if len(failures) == 0 {
+		if len(checks) > 0 {
+			fmt.Printf("\n✅ %s passed the quality gate (%d check(s))\n", name, len(checks))
+		}
+		return true
+	}
+
+	fmt.Fprintf(os.Stderr, "\n%s failed the quality gate:\n", name)
+	for _, check := range failures {
+		if check.Column != "" {
+			fmt.Fprintf(os.Stderr, "  ✗ %s '%s': %s\n", check.Name, check.Column, check.Message)
+		} else {
+			fmt.Fprintf(os.Stderr, "  ✗ %s: %s\n", check.Name, check.Message)
+		}
+	}
+	return false
+}
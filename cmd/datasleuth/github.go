@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/kamalm96/datasleuth/internal/report"
+	"github.com/kamalm96/datasleuth/internal/validate"
+)
+
+// writeGitHubProfiles writes the issues of the profiles of source as GitHub
+// workflow commands, so they show up as annotations of the data file, and
+// their summary to summaryFile or $GITHUB_STEP_SUMMARY.
+func writeGitHubProfiles(source string, profiles []*profiler.DatasetProfile, summaryFile string) {
+	report.WriteGitHubAnnotations(stdout, report.GitHubFile(source), profiles)
+
+	written, err := report.AppendGitHubSummary(summaryFile, func(w io.Writer) error {
+		return report.WriteGitHubSummary(w, source, profiles)
+	})
+	reportGitHubSummary(written, err)
+}
+
+// writeGitHubValidation writes the failed checks of result as GitHub
+// workflow commands on the data file of source, and its summary to
+// $GITHUB_STEP_SUMMARY.
+func writeGitHubValidation(source string, result *validate.Result) {
+	report.WriteGitHubValidationAnnotations(stdout, report.GitHubFile(source), result)
+
+	written, err := report.AppendGitHubSummary("", func(w io.Writer) error {
+		return report.WriteGitHubValidationSummary(w, result)
+	})
+	reportGitHubSummary(written, err)
+}
+
+func reportGitHubSummary(written string, err error) {
+	switch {
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "Error writing step summary: %v\n", err)
+		os.Exit(1)
+	case written == "":
+		fmt.Printf("No step summary written: $%s is not set\n", report.GitHubSummaryEnv)
+	default:
+		fmt.Printf("Step summary written to: %s\n", written)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/config"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/spf13/cobra"
+)
+
+// readHistogram reads --histogram and --histogram-buckets of cmd over the
+// histogram settings of cfg. Either flag left unset keeps the config value.
+func readHistogram(cmd *cobra.Command, cfg *config.Config) profiler.Histograms {
+	var histograms profiler.Histograms
+	if cfg != nil && cfg.Histogram != nil {
+		histograms = *cfg.Histogram
+	}
+
+	if cmd.Flags().Changed("histogram") {
+		binning, _ := cmd.Flags().GetString("histogram")
+		histograms.Binning = strings.ToLower(binning)
+	}
+	if cmd.Flags().Changed("histogram-buckets") {
+		histograms.Buckets, _ = cmd.Flags().GetInt("histogram-buckets")
+		if histograms.Buckets <= 0 {
+			fmt.Fprintf(os.Stderr, "Invalid --histogram-buckets %d: use a positive number of buckets\n", histograms.Buckets)
+			os.Exit(1)
+		}
+	}
+
+	if err := histograms.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid histogram settings: %v\n", err)
+		os.Exit(1)
+	}
+	return histograms
+}
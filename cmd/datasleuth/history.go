@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/kamalm96/datasleuth/internal/compare"
+	"github.com/kamalm96/datasleuth/internal/history"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/kamalm96/datasleuth/internal/report"
+	"github.com/kamalm96/datasleuth/internal/validate"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [file|url]",
+	Short: "Show the recorded profile runs of a dataset and their trends",
+	Long: `Every profile run is recorded in a SQLite database in ~/.datasleuth
+(or $DATASLEUTH_HOME). Without arguments, history lists the datasets with
+recorded runs. Given a dataset, it lists its runs with row count, quality
+score and parse error trends, probable column renames and cardinality
+explosions between consecutive runs, and how often each column breached its
+completeness SLA.
+--diff compares any two recorded runs.`,
+	Example: `  datasleuth history
+  datasleuth history data.csv
+  datasleuth history app.db --table orders --limit 50
+  datasleuth history --diff 12,31`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		table, _ := cmd.Flags().GetString("table")
+		limit, _ := cmd.Flags().GetInt("limit")
+		diffIDs, _ := cmd.Flags().GetInt64Slice("diff")
+
+		if len(diffIDs) != 0 && len(diffIDs) != 2 {
+			fmt.Fprintln(os.Stderr, "Invalid --diff: give two run IDs, e.g. --diff 12,31")
+			os.Exit(1)
+		}
+
+		fmt.Printf("DataSleuth v%s - Fast dataset profiling and validation\n", version)
+		fmt.Println("────────────────────────────────────────────────────────────────────────────────")
+		fmt.Println()
+
+		store, err := openHistory()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening profile history: %v\n", err)
+			os.Exit(1)
+		}
+		defer store.Close()
+
+		if len(diffIDs) == 2 {
+			diffRuns(store, diffIDs[0], diffIDs[1])
+			return
+		}
+
+		if len(args) == 0 {
+			datasets, err := store.Datasets()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading profile history: %v\n", err)
+				os.Exit(1)
+			}
+			report.PrintHistoryDatasets(datasets)
+			return
+		}
+
+		dataset, err := store.Resolve(args[0], table)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading profile history: %v\n", err)
+			os.Exit(1)
+		}
+
+		runs, err := store.Runs(dataset, limit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading profile history: %v\n", err)
+			os.Exit(1)
+		}
+
+		profiles := make([]*profiler.DatasetProfile, 0, len(runs))
+		for _, run := range runs {
+			profile, err := loadRunProfile(store, run.ID)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading profile history: %v\n", err)
+				os.Exit(1)
+			}
+			profiles = append(profiles, profile)
+		}
+
+		slas, err := store.SLAs(runs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading profile history: %v\n", err)
+			os.Exit(1)
+		}
+
+		report.PrintHistoryRuns(dataset, runs, history.Changes(runs, profiles))
+		report.PrintHistorySLAs(runs, slas)
+	},
+}
+
+// diffRuns compares two recorded runs like compare does two datasets.
+func diffRuns(store *history.Store, baseID, targetID int64) {
+	base, err := loadRunProfile(store, baseID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading profile history: %v\n", err)
+		os.Exit(1)
+	}
+	target, err := loadRunProfile(store, targetID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading profile history: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Comparing runs:\n  1. #%d %s (%s)\n  2. #%d %s (%s)\n\n",
+		baseID, base.Filename, base.CreatedAt.Local().Format("2006-01-02 15:04"),
+		targetID, target.Filename, target.CreatedAt.Local().Format("2006-01-02 15:04"))
+
+	report.PrintComparisonReport(compare.Compare(base, target, compare.DefaultOptions()))
+}
+
+func loadRunProfile(store *history.Store, id int64) (*profiler.DatasetProfile, error) {
+	content, err := store.Report(id)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := report.DecodeJSONReport(content)
+	if err != nil {
+		return nil, fmt.Errorf("run %d: %w", id, err)
+	}
+	return profile, nil
+}
+
+func openHistory() (*history.Store, error) {
+	path, err := history.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return history.Open(path)
+}
+
+// recordHistory adds a run for each profile of source to the profile
+// history, with its attainment of the SLAs that apply to it. Failing to
+// record only warns: the profile itself succeeded.
+func recordHistory(source string, profiles []*profiler.DatasetProfile, slas []validate.SLA) {
+	if source == profiler.StdinSource {
+		return
+	}
+
+	store, err := openHistory()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record profile history: %v\n", err)
+		return
+	}
+	defer store.Close()
+
+	for _, profile := range profiles {
+		// A partial profile would read as a drop in the trends
+		if profile.Interrupted {
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := report.EncodeJSONReport(&buf, profile); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record profile history: %v\n", err)
+			return
+		}
+
+		id, err := store.Record(history.NewRun(history.DatasetKey(source, profile.Table), profile), buf.Bytes())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record profile history: %v\n", err)
+			return
+		}
+
+		results := validate.EvaluateSLAs(slas, profile)
+		attainment := make([]history.SLA, 0, len(results))
+		for _, result := range results {
+			attainment = append(attainment, history.SLA{RunID: id, Column: result.Column, Target: result.Completeness,
+				Actual: result.Actual, Met: result.Met})
+		}
+		if err := store.RecordSLAs(id, attainment); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record profile history: %v\n", err)
+			return
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().String("table", "", "Table or sheet of a database or workbook")
+	historyCmd.Flags().Int("limit", 20, "Latest runs to show (0 = all)")
+	historyCmd.Flags().Int64Slice("diff", nil, "Compare two runs by ID, e.g. --diff 12,31")
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of a run stopped early, as shells give an interrupted command
+// and timeout(1) one that ran out of time.
+const (
+	interruptExitCode = 130
+	timeoutExitCode   = 124
+)
+
+// runContext returns the context of a run that profiles: done on Ctrl+C or
+// SIGTERM, or once --timeout has passed. Profiling then stops before the
+// next row and reports on the rows read so far; a second Ctrl+C quits at
+// once.
+func runContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	timeout := readTimeout(cmd)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		signal.Stop(signals)
+		fmt.Fprintln(os.Stderr, "\nInterrupted: reporting on the rows read so far (Ctrl+C again to quit)")
+		cancel()
+	}()
+
+	if timeout == 0 {
+		return ctx, cancel
+	}
+	ctx, cancelTimeout := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		cancelTimeout()
+		cancel()
+	}
+}
+
+// readTimeout reads --timeout, 0 for no limit.
+func readTimeout(cmd *cobra.Command) time.Duration {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	if timeout < 0 {
+		fmt.Fprintln(os.Stderr, "Invalid --timeout: must not be negative")
+		os.Exit(1)
+	}
+	return timeout
+}
+
+// stoppedEarly reports whether err only says that profiling stopped because
+// the run context is done, leaving a partial profile to report.
+func stoppedEarly(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// exitStopped exits with the exit code of a run stopped early, once its
+// reports are written, and returns when the run was not stopped.
+func exitStopped(ctx context.Context) {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		fmt.Fprintln(os.Stderr, "\nTimed out (--timeout): the reports cover only the rows read")
+		os.Exit(timeoutExitCode)
+	case ctx.Err() != nil:
+		os.Exit(interruptExitCode)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kamalm96/datasleuth/internal/k8s"
+	"github.com/kamalm96/datasleuth/internal/remote"
+	"github.com/spf13/cobra"
+)
+
+var genK8sCmd = &cobra.Command{
+	Use:   "gen-k8s [flags] [-- profile flags]",
+	Short: "Generate a Kubernetes CronJob that profiles a source on a schedule",
+	Long: `Generate a Kubernetes CronJob manifest that runs datasleuth profile on a
+remote source on a schedule, writing the JSON report to the job logs.
+
+Credentials for the provider of the source are read from a Secret: the AWS
+keys for s3://, a service account key file for gs:// and a SAS token or
+account key for az://. The CPU and memory requests are sized from the size of
+the source, looked up with the credentials of this machine, or given with
+--size. Flags after -- are passed on to the profile command.`,
+	Example: `  datasleuth gen-k8s --source s3://bucket/orders.csv --schedule "0 2 * * *" > cronjob.yaml
+  datasleuth gen-k8s --source gs://bucket/events.parquet --schedule @hourly --namespace data --size 20GB
+  datasleuth gen-k8s --source s3://bucket/orders.csv --schedule "0 2 * * *" -- --max-severity 3 --sample 100000`,
+	Run: func(cmd *cobra.Command, args []string) {
+		source, _ := cmd.Flags().GetString("source")
+		schedule, _ := cmd.Flags().GetString("schedule")
+		name, _ := cmd.Flags().GetString("name")
+		namespace, _ := cmd.Flags().GetString("namespace")
+		image, _ := cmd.Flags().GetString("image")
+		secret, _ := cmd.Flags().GetString("secret")
+		sizeFlag, _ := cmd.Flags().GetString("size")
+		outputFile, _ := cmd.Flags().GetString("output")
+
+		if dash := cmd.ArgsLenAtDash(); dash != 0 && len(args) > 0 {
+			fmt.Fprintln(os.Stderr, "Unexpected arguments: pass profile flags after --")
+			os.Exit(1)
+		}
+		if source == "" || schedule == "" {
+			fmt.Fprintln(os.Stderr, "Missing --source or --schedule")
+			os.Exit(1)
+		}
+
+		size := int64(-1)
+		if sizeFlag != "" {
+			var err error
+			if size, err = parseByteSize(sizeFlag); err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid --size %s: %v\n", sizeFlag, err)
+				os.Exit(1)
+			}
+		} else if remote.IsURL(source) {
+			info, err := remote.Stat(source)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  Could not look up the size of %s: %v\n   Resources are sized for an unknown size; set --size to size them\n", source, err)
+			} else {
+				size = info.Size
+			}
+		}
+
+		manifest, err := k8s.CronJob(k8s.Options{
+			Source:    source,
+			Schedule:  schedule,
+			Name:      name,
+			Namespace: namespace,
+			Image:     image,
+			Secret:    secret,
+			Size:      size,
+			Args:      args,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating manifest: %v\n", err)
+			os.Exit(1)
+		}
+
+		if outputFile == "" {
+			stdout.Write(manifest)
+			return
+		}
+		if err := os.WriteFile(outputFile, manifest, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing manifest: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("CronJob manifest saved to: %s\n", outputFile)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(genK8sCmd)
+
+	genK8sCmd.Flags().String("source", "", "URL of the source to profile: s3://, gs://, az:// or https://")
+	genK8sCmd.Flags().String("schedule", "", `Cron schedule of the job, e.g. "0 2 * * *" or @daily`)
+	genK8sCmd.Flags().String("name", "", "Name of the CronJob (default: datasleuth- and the object name)")
+	genK8sCmd.Flags().String("namespace", "", "Namespace of the CronJob (default: none, the namespace of kubectl apply)")
+	genK8sCmd.Flags().String("image", "datasleuth:"+version, "Container image with the datasleuth binary on its PATH")
+	genK8sCmd.Flags().String("secret", k8s.DefaultSecret, "Secret holding the credentials of the source")
+	genK8sCmd.Flags().String("size", "", "Size of the source used to size resources, e.g. 20GB (default: look it up)")
+	genK8sCmd.Flags().StringP("output", "o", "", "File to write (default: stdout)")
+}
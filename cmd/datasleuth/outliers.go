@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/config"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/spf13/cobra"
+)
+
+// readOutliers reads --outlier-method of cmd over the outlier detection of
+// cfg, or nil for the default z-scores when neither sets one.
+func readOutliers(cmd *cobra.Command, cfg *config.Config) *profiler.OutlierDetection {
+	method, _ := cmd.Flags().GetString("outlier-method")
+
+	if method == "" {
+		if cfg == nil {
+			return nil
+		}
+		return cfg.Outliers
+	}
+
+	// The thresholds of the config file still apply to the method chosen
+	detection := &profiler.OutlierDetection{}
+	if cfg != nil && cfg.Outliers != nil {
+		*detection = *cfg.Outliers
+	}
+	detection.Method = strings.ToLower(method)
+	if err := detection.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --outlier-method %s: use %s\n", method, strings.Join(profiler.OutlierMethods(), ", "))
+		os.Exit(1)
+	}
+	return detection
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// secretFlags are withheld from plans, which are meant to be shared.
+var secretFlags = map[string]bool{"password": true, "token": true}
+
+// commandPlan is what a run of a command will do, printed by --plan.
+type commandPlan struct {
+	Command string                 `json:"command"`
+	Mode    string                 `json:"mode,omitempty"` // how the sources are run, e.g. files or follow
+	Flags   map[string]interface{} `json:"flags"`          // every flag with its effective value
+	Changed []string               `json:"changed_flags"`  // flags given on the command line
+	Jobs    int                    `json:"jobs,omitempty"` // sources profiled at once
+	Sources []sourcePlan           `json:"sources"`
+}
+
+// sourcePlan is the plan of a source, with what a batch run adds to it.
+type sourcePlan struct {
+	Name string `json:"name,omitempty"`
+	*profiler.Plan
+	Rules   string   `json:"rules,omitempty"`
+	Outputs []string `json:"outputs,omitempty"`
+}
+
+// addPlanFlag adds --plan to cmd.
+func addPlanFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("plan", false, "Print what the run would do as JSON and exit: effective flags, detected formats, algorithms and estimated cost")
+}
+
+// planning reports whether cmd was asked for its plan.
+func planning(cmd *cobra.Command) bool {
+	plan, _ := cmd.Flags().GetBool("plan")
+	return plan
+}
+
+// newCommandPlan is the plan of cmd with the effective value of each of its
+// flags, before its sources are added.
+func newCommandPlan(cmd *cobra.Command) *commandPlan {
+	plan := &commandPlan{
+		Command: cmd.Name(),
+		Flags:   make(map[string]interface{}),
+		Changed: make([]string, 0),
+		Sources: make([]sourcePlan, 0),
+	}
+	visit := func(f *pflag.Flag) {
+		if f.Name == "help" || f.Name == "plan" {
+			return
+		}
+		if f.Changed {
+			plan.Changed = append(plan.Changed, f.Name)
+		}
+		plan.Flags[f.Name] = flagValue(cmd, f)
+	}
+	cmd.Flags().VisitAll(visit)
+	cmd.InheritedFlags().VisitAll(visit)
+	sort.Strings(plan.Changed)
+	return plan
+}
+
+// flagValue is the value of f as JSON would hold it: numbers, booleans and
+// lists as such, everything else as the flag prints it.
+func flagValue(cmd *cobra.Command, f *pflag.Flag) interface{} {
+	if secretFlags[f.Name] {
+		if f.Value.String() == "" {
+			return ""
+		}
+		return "(withheld)"
+	}
+
+	flags := cmd.Flags()
+	if flags.Lookup(f.Name) == nil {
+		flags = cmd.InheritedFlags()
+	}
+	switch f.Value.Type() {
+	case "bool":
+		v, _ := flags.GetBool(f.Name)
+		return v
+	case "int":
+		v, _ := flags.GetInt(f.Name)
+		return v
+	case "float64":
+		v, _ := flags.GetFloat64(f.Name)
+		return v
+	case "stringSlice":
+		v, _ := flags.GetStringSlice(f.Name)
+		return v
+	case "stringArray":
+		v, _ := flags.GetStringArray(f.Name)
+		return v
+	}
+	return f.Value.String()
+}
+
+// planSources adds the plan of profiling each source with opts, exiting
+// when a source cannot be profiled with them.
+func (p *commandPlan) planSources(sources []string, opts profiler.Options) {
+	for _, source := range sources {
+		plan, err := profiler.PlanProfile(source, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error planning %s: %v\n", source, err)
+			os.Exit(1)
+		}
+		p.Sources = append(p.Sources, sourcePlan{Plan: plan})
+	}
+}
+
+// print writes the plan to stdout as indented JSON.
+func (p *commandPlan) print() {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing plan: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintln(stdout, string(data))
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/kamalm96/datasleuth/internal/report"
+	"github.com/spf13/cobra"
+)
+
+// stdout is the standard output the process started with. Machine-readable
+// output goes here: plans, manifests, generated code and rules, and GitHub
+// annotations. Everything else prints to os.Stdout, which quietStdout may
+// redirect.
+var stdout = os.Stdout
+
+// quietStdout redirects the banners, progress and terminal reports printed
+// to os.Stdout: away with --quiet, and to stderr when a report is written to
+// stdout with --output-file -, so that stdout holds the report alone.
+func quietStdout(cmd *cobra.Command) error {
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	outputFile, _ := cmd.Flags().GetString("output-file")
+
+	switch {
+	case quiet:
+		devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		if err != nil {
+			return fmt.Errorf("failed to silence output: %w", err)
+		}
+		os.Stdout = devNull
+	case outputFile == report.StdoutPath:
+		os.Stdout = os.Stderr
+	default:
+		return nil
+	}
+
+	// Styled output was bound to the original stdout on start
+	color.Output = os.Stdout
+	return nil
+}
+
+// jsonToStdout rejects --output-file - unless the report is JSON.
+func jsonToStdout(outputFile, outputFormat string) {
+	if outputFile == report.StdoutPath && outputFormat != "json" {
+		fmt.Fprintln(os.Stderr, "Invalid --output-file -: only JSON reports are written to stdout")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/kamalm96/datasleuth/internal/report"
+	"github.com/kamalm96/datasleuth/internal/validate"
+	"github.com/spf13/cobra"
+)
+
+var generateRulesCmd = &cobra.Command{
+	Use:   "generate-rules [file|url|report.json]",
+	Short: "Scaffold validation rules from a profile",
+	Long: `Profile a dataset, or load a JSON report written by profile --output json,
+and write a starter rules file for validate --config.
+
+Each column gets its observed type, not_null when it has no missing values,
+the observed range of numbers widened by --margin on each side, and the
+allowed values of categorical string columns with at most --max-values
+distinct values. The rules describe the data as it is: review and edit them
+before relying on them.`,
+	Example: `  datasleuth generate-rules data.csv -o rules.yaml
+  datasleuth validate next_week.csv --config rules.yaml
+  datasleuth generate-rules profile_report.json --margin 0.25
+  datasleuth generate-rules warehouse.db --table orders --max-values 50`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		source := args[0]
+		table, _ := cmd.Flags().GetString("table")
+		sampleSize, _ := cmd.Flags().GetInt("sample")
+		outputFile, _ := cmd.Flags().GetString("output")
+		opts := validate.DefaultRuleOptions()
+		opts.Margin, _ = cmd.Flags().GetFloat64("margin")
+		opts.MaxValues, _ = cmd.Flags().GetInt("max-values")
+
+		if opts.Margin < 0 {
+			fmt.Fprintf(os.Stderr, "Invalid --margin %g: must not be negative\n", opts.Margin)
+			os.Exit(1)
+		}
+		if opts.MaxValues < 0 {
+			fmt.Fprintf(os.Stderr, "Invalid --max-values %d: must not be negative\n", opts.MaxValues)
+			os.Exit(1)
+		}
+
+		var profile *profiler.DatasetProfile
+		var err error
+		if strings.EqualFold(filepath.Ext(source), ".json") {
+			profile, err = report.LoadJSONReport(source)
+		} else {
+			// List enough values to find the categoricals that are complete
+			profileOpts := profiler.Options{SampleSize: sampleSize, TopValues: opts.MaxValues + 1}
+			if profiler.IsExcel(source) {
+				profileOpts.Sheet = table
+			} else {
+				profileOpts.Table = table
+			}
+			fmt.Fprintf(os.Stderr, "Profiling %s...\n", source)
+			profile, err = profiler.ProfileDatasetWithOptions(source, profileOpts)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error profiling dataset: %v\n", err)
+			os.Exit(1)
+		}
+
+		content, err := validate.GenerateRules(profile, opts).Marshal()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating rules: %v\n", err)
+			os.Exit(1)
+		}
+		header := fmt.Sprintf("# Validation rules generated by DataSleuth from %s (%d rows)\n", profile.Filename, profile.RowCount)
+		if profile.SampleStrategy != "" {
+			header += fmt.Sprintf("# Inferred from a %s sample\n", profile.SampleStrategy)
+		}
+		header += fmt.Sprintf("# Ranges are widened by %g%% of the observed range; review before use\n", opts.Margin*100)
+		content = append([]byte(header), content...)
+
+		if outputFile == "" {
+			fmt.Fprint(stdout, string(content))
+			return
+		}
+		if err := os.WriteFile(outputFile, content, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing rules: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Rules saved to: %s\n", outputFile)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(generateRulesCmd)
+
+	generateRulesCmd.Flags().Float64("margin", validate.DefaultRuleOptions().Margin, "Widen numeric ranges by this fraction of the observed range on each side")
+	generateRulesCmd.Flags().Int("max-values", validate.DefaultRuleOptions().MaxValues, "Give allowed values to categorical columns with at most this many distinct values")
+	generateRulesCmd.Flags().String("table", "", "Table of a SQLite database or sheet of an Excel workbook to profile, required when it has several")
+	generateRulesCmd.Flags().IntP("sample", "s", 0, "Use a sample of rows (0 = all rows)")
+	generateRulesCmd.Flags().StringP("output", "o", "", "File to write (default: stdout)")
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/kamalm96/datasleuth/internal/report"
+	"github.com/kamalm96/datasleuth/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [file|url|report.json]",
+	Short: "Generate CREATE TABLE DDL, JSON Schema or Avro from the inferred column types",
+	Long: `Profile a dataset, or load a JSON report written by profile --output json,
+and write a CREATE TABLE statement for Postgres, MySQL, BigQuery or Snowflake,
+or with --format a JSON Schema or Avro record schema of its rows.
+
+Column types come from the inferred data types: integers that fit 32 bits are
+INTEGER and larger ones BIGINT, datetimes with day precision are DATE, and
+strings holding only true and false are BOOLEAN. A column without missing
+values is NOT NULL. Strings are VARCHAR sized from the longest value rounded
+up to the next power of two, or the longest value itself with
+--exact-lengths. Review the schema before loading later files: their values
+may be longer, or missing where this one had none.
+
+JSON Schema (draft 2020-12) has a property per column, required and not null
+when the column had no missing values, with dates and timestamps as strings
+of format date and date-time. Avro has a field per column, nullable ones as a
+union with null defaulting to it, and dates and timestamps with the date and
+timestamp-micros logical types; names that are not valid Avro names are
+rewritten, keeping the column name in the doc of the field.`,
+	Example: `  datasleuth schema data.csv --dialect postgres > create_table.sql
+  datasleuth schema events.parquet --dialect bigquery --name analytics.events
+  datasleuth schema warehouse.db --table orders --dialect snowflake -o orders.sql
+  datasleuth schema profile_report.json --dialect mysql
+  datasleuth schema data.csv --format json-schema -o data.schema.json
+  datasleuth schema events.parquet --format avro --name com.example.Event`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		source := args[0]
+		format, _ := cmd.Flags().GetString("format")
+		dialect, _ := cmd.Flags().GetString("dialect")
+		name, _ := cmd.Flags().GetString("name")
+		table, _ := cmd.Flags().GetString("table")
+		sampleSize, _ := cmd.Flags().GetInt("sample")
+		exactLengths, _ := cmd.Flags().GetBool("exact-lengths")
+		outputFile, _ := cmd.Flags().GetString("output")
+
+		format = strings.ToLower(format)
+		if !schema.IsFormat(format) {
+			fmt.Fprintf(os.Stderr, "Invalid --format %s: use %s\n", format, strings.Join(schema.Formats, ", "))
+			os.Exit(1)
+		}
+		dialect = strings.ToLower(dialect)
+		if !schema.IsDialect(dialect) {
+			fmt.Fprintf(os.Stderr, "Invalid --dialect %s: use %s\n", dialect, strings.Join(schema.Dialects, ", "))
+			os.Exit(1)
+		}
+
+		var profile *profiler.DatasetProfile
+		var err error
+		if strings.EqualFold(filepath.Ext(source), ".json") {
+			profile, err = report.LoadJSONReport(source)
+		} else {
+			opts := profiler.Options{SampleSize: sampleSize}
+			if profiler.IsExcel(source) {
+				opts.Sheet = table
+			} else {
+				opts.Table = table
+			}
+			fmt.Fprintf(os.Stderr, "Profiling %s...\n", source)
+			profile, err = profiler.ProfileDatasetWithOptions(source, opts)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error profiling dataset: %v\n", err)
+			os.Exit(1)
+		}
+
+		columnOpts := schema.Options{ExactLengths: exactLengths}
+		kind, content := "DDL", ""
+		switch format {
+		case schema.FormatJSONSchema:
+			kind = "JSON Schema"
+			content, err = schema.JSONSchema(profile, schema.DocumentOptions{Options: columnOpts, Name: name})
+		case schema.FormatAvro:
+			kind = "Avro schema"
+			content, err = schema.Avro(profile, schema.DocumentOptions{Options: columnOpts, Name: name})
+		default:
+			content, err = schema.DDL(profile, schema.DDLOptions{Options: columnOpts, Dialect: dialect, Table: name})
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating %s: %v\n", kind, err)
+			os.Exit(1)
+		}
+
+		if outputFile == "" {
+			fmt.Fprint(stdout, content)
+			return
+		}
+		if err := os.WriteFile(outputFile, []byte(content), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", kind, err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s saved to: %s\n", kind, outputFile)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+
+	schemaCmd.Flags().String("format", schema.FormatSQL, "Schema format: sql, json-schema, avro")
+	schemaCmd.Flags().String("dialect", schema.DialectPostgres, "SQL dialect of --format sql: postgres, mysql, bigquery, snowflake")
+	schemaCmd.Flags().String("name", "", "Name of the table, JSON Schema title or Avro record, optionally qualified (default: derived from the file name)")
+	schemaCmd.Flags().String("table", "", "Table of a SQLite database or sheet of an Excel workbook to profile, required when it has several")
+	schemaCmd.Flags().IntP("sample", "s", 0, "Use a sample of rows (0 = all rows)")
+	schemaCmd.Flags().Bool("exact-lengths", false, "Size VARCHAR columns to the longest value seen rather than the next power of two")
+	schemaCmd.Flags().StringP("output", "o", "", "File to write (default: stdout)")
+}
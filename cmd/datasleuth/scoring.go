@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/config"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/spf13/cobra"
+)
+
+// addScoringFlags adds the flags that weigh the quality score to cmd.
+func addScoringFlags(cmd *cobra.Command) {
+	cmd.Flags().String("scoring", "", "Quality score weights: a preset ("+strings.Join(profiler.ScoringPresets(), ", ")+") or a YAML scoring file (default: the scoring of the config file)")
+	cmd.Flags().StringSlice("score-weight", nil, "Weight of the missing values and issues of columns in the quality score as column=weight, e.g. notes=0.5")
+	cmd.Flags().StringSlice("score-ignore", nil, "Columns left out of the quality score, e.g. internal_*")
+}
+
+// readScoring reads the scoring flags of cmd over the scoring of cfg, or the
+// default scoring when neither sets one.
+func readScoring(cmd *cobra.Command, cfg *config.Config) *profiler.Scoring {
+	value, _ := cmd.Flags().GetString("scoring")
+	weights, _ := cmd.Flags().GetStringSlice("score-weight")
+	ignore, _ := cmd.Flags().GetStringSlice("score-ignore")
+
+	scoring := profiler.DefaultScoring()
+	if cfg != nil && cfg.Scoring != nil {
+		scoring = cfg.Scoring
+	}
+	if value != "" {
+		var err error
+		if scoring, err = config.LoadScoring(value); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid --scoring %s: %v\n", value, err)
+			os.Exit(1)
+		}
+	}
+
+	for _, weight := range weights {
+		name, text, ok := strings.Cut(weight, "=")
+		w, err := strconv.ParseFloat(text, 64)
+		if !ok || name == "" || err != nil || w < 0 {
+			fmt.Fprintf(os.Stderr, "Invalid --score-weight %q: use column=weight with a weight of 0 or more\n", weight)
+			os.Exit(1)
+		}
+		if scoring.Columns == nil {
+			scoring.Columns = make(map[string]float64)
+		}
+		scoring.Columns[name] = w
+	}
+	scoring.Ignore = append(scoring.Ignore, ignore...)
+
+	if err := scoring.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid scoring: %v\n", err)
+		os.Exit(1)
+	}
+	return scoring
+}
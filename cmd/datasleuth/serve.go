@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/kamalm96/datasleuth/internal/server"
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Browse profiles in a local web UI",
+	Long: `Start a web server for browsing profiles interactively. Point it at a
+file path or URL, or upload a file, then sort and filter the columns, open
+a column for its histogram and top values, and compare any two profiles,
+including the runs recorded in the profile history. Each dataset in the
+history has a page charting its quality score, rows, missing cells,
+duplicate rows and parse errors across its runs.`,
+	Example: `  datasleuth serve
+  datasleuth serve --addr :8080 --no-history`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		addr, _ := cmd.Flags().GetString("addr")
+
+		fmt.Printf("DataSleuth v%s - Fast dataset profiling and validation\n", version)
+		fmt.Println("────────────────────────────────────────────────────────────────────────────────")
+
+		config := serverConfig(cmd)
+		if config.History != nil {
+			defer config.History.Close()
+		}
+
+		fmt.Printf("\n🌐 Serving on http://%s (press Ctrl+C to stop)\n", displayAddr(addr))
+		if err := http.ListenAndServe(addr, server.New(config).Handler()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error serving: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// serverConfig reads the --no-history and --max-upload flags shared by serve
+// and api, opening the profile history unless it is turned off.
+func serverConfig(cmd *cobra.Command) server.Config {
+	noHistory, _ := cmd.Flags().GetBool("no-history")
+	maxUpload, _ := cmd.Flags().GetString("max-upload")
+
+	uploadLimit, err := parseByteSize(maxUpload)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --max-upload %s: %v\n", maxUpload, err)
+		os.Exit(1)
+	}
+
+	// The defaults of the profile command
+	config := server.Config{
+		Version:   version,
+		Options:   profiler.Options{Examples: 5, ExactRows: 1000},
+		MaxUpload: uploadLimit,
+	}
+	if !noHistory {
+		store, err := openHistory()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening profile history: %v\n", err)
+			os.Exit(1)
+		}
+		config.History = store
+	}
+	return config
+}
+
+// displayAddr turns a listen address such as :8080 into one to browse to.
+func displayAddr(addr string) string {
+	if len(addr) > 0 && addr[0] == ':' {
+		return "localhost" + addr
+	}
+	return addr
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().String("addr", "127.0.0.1:8080", "Address to listen on; use :8080 to accept connections from other machines")
+	serveCmd.Flags().Bool("no-history", false, "Keep profiles in memory instead of recording them in the profile history")
+	serveCmd.Flags().String("max-upload", "512MB", "Largest file accepted for upload, e.g. 2GB")
+}
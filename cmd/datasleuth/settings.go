@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kamalm96/datasleuth/internal/config"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/spf13/cobra"
+)
+
+// withSettings returns opts with the settings the config gives source, or
+// its table or sheet, in place of the flags not given: the delimiter of
+// delimited text, the null values and, for commands that sample, the
+// sample size.
+func withSettings(cmd *cobra.Command, cfg *config.Config, source string, opts profiler.Options) profiler.Options {
+	table := opts.Table
+	if table == "" {
+		table = opts.Sheet
+	}
+	settings := cfg.SettingsFor(source, table)
+
+	if settings.Delimiter != "" && !flagGiven(cmd, "delimiter") && profiler.IsDelimited(source, opts) {
+		delimiter, err := parseCharFlag(settings.Delimiter, false)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid delimiter %s in %s: %v\n", settings.Delimiter, cfg.Path, err)
+			os.Exit(1)
+		}
+		opts.Delimiter = delimiter
+	}
+	if settings.NullValues != nil && !flagGiven(cmd, "null-values") {
+		opts.NullValues = settings.NullValues
+	}
+	if f := cmd.Flags().Lookup("sample"); f != nil && !f.Changed && settings.Sample > 0 {
+		opts.SampleSize = settings.Sample
+	}
+	return opts
+}
+
+// readOutput reads --output, or the output the config gives source when it
+// is not given. Several sources take the output of the defaults.
+func readOutput(cmd *cobra.Command, cfg *config.Config, source, table string) string {
+	output, _ := cmd.Flags().GetString("output")
+	if flagGiven(cmd, "output") {
+		return output
+	}
+	if settings := cfg.SettingsFor(source, table); settings.Output != "" {
+		return settings.Output
+	}
+	return output
+}
+
+func flagGiven(cmd *cobra.Command, name string) bool {
+	f := cmd.Flags().Lookup(name)
+	return f != nil && f.Changed
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kamalm96/datasleuth/internal/config"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/kamalm96/datasleuth/internal/validate"
+	"github.com/spf13/cobra"
+)
+
+// projectConfigUsage is the help of --project-config, which means the same
+// on every command that reads the project config.
+const projectConfigUsage = "Config file with defaults, completeness SLAs and quality score weights (default: the nearest " + config.DefaultFile + " up from the current directory)"
+
+// readConfig reads the project config given with --project-config, or the
+// nearest .datasleuth.yaml up from the current directory when there is one.
+func readConfig(cmd *cobra.Command) *config.Config {
+	path, _ := cmd.Flags().GetString("project-config")
+
+	var cfg *config.Config
+	var err error
+	if path != "" {
+		cfg, err = config.Load(path)
+	} else {
+		cfg, err = config.LoadDefault()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	return cfg
+}
+
+// printSLAs prints the attainment of the SLAs that apply to each profile.
+// A breach is reported, and recorded in the history, but does not fail the
+// run; the quality gate is for that.
+func printSLAs(profiles []*profiler.DatasetProfile, slas []validate.SLA) {
+	for _, profile := range profiles {
+		results := validate.EvaluateSLAs(slas, profile)
+		if len(results) == 0 {
+			continue
+		}
+
+		name := profile.Filename
+		if profile.Table != "" {
+			name += " (" + profile.Table + ")"
+		}
+		fmt.Printf("\n🎯 Completeness SLAs of %s:\n", name)
+		for _, result := range results {
+			mark := "✓"
+			if !result.Met {
+				mark = "✗"
+			}
+			if result.Missing {
+				fmt.Printf("   %s %s: column missing (target ≥ %.1f%%)\n", mark, result.Column, result.Completeness)
+				continue
+			}
+			fmt.Printf("   %s %s: %.1f%% non-null (target ≥ %.1f%%)\n", mark, result.Column, result.Actual, result.Completeness)
+		}
+	}
+}
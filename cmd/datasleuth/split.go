@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/compare"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/kamalm96/datasleuth/internal/report"
+	"github.com/kamalm96/datasleuth/internal/split"
+	"github.com/spf13/cobra"
+)
+
+var splitCmd = &cobra.Command{
+	Use:   "split [file|url|-]",
+	Short: "Split a dataset into train and test sets, stratified by a column",
+	Long: `Split the rows of a dataset into a train and a test CSV file in a single
+pass, then profile both sets and compare them to check that they are
+comparable: the same columns and types, and no drift between their
+distributions. JSON profiles of both sets are written next to them.
+
+Rows are assigned at random with --seed, so the same seed repeats a split.
+With --stratify-by, every value of the column, such as each class of a label,
+keeps the --train share of its rows, within a row, so rare classes are not
+left out of either set. Any source profile reads can be split; the sets are
+always written as CSV.`,
+	Example: `  datasleuth split data.csv --train 0.8 --stratify-by label
+  datasleuth split events.jsonl --train 0.9 --seed 7 --output-dir splits/
+  datasleuth split data.csv --stratify-by label --max-drift 0`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		source := args[0]
+		train, _ := cmd.Flags().GetFloat64("train")
+		stratifyBy, _ := cmd.Flags().GetString("stratify-by")
+		seed, _ := cmd.Flags().GetInt64("seed")
+		outputDir, _ := cmd.Flags().GetString("output-dir")
+		table, _ := cmd.Flags().GetString("table")
+		format, _ := cmd.Flags().GetString("format")
+		gate := readGate(cmd)
+
+		splitOpts := split.Options{Train: train, StratifyBy: stratifyBy, Seed: seed}
+		if err := splitOpts.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid --train %g: use a share between 0 and 1, such as 0.8\n", train)
+			os.Exit(1)
+		}
+
+		fmt.Printf("DataSleuth v%s - Fast dataset profiling and validation\n", version)
+		fmt.Println("────────────────────────────────────────────────────────────────────────────────")
+
+		name := splitBaseName(source)
+		trainFile := filepath.Join(outputDir, name+"_train.csv")
+		testFile := filepath.Join(outputDir, name+"_test.csv")
+
+		ctx, cancel := runContext(cmd)
+		defer cancel()
+
+		splitter, err := writeSplit(ctx, source, trainFile, testFile, splitOpts, table, format)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error splitting %s: %v\n", source, err)
+			exitStopped(ctx)
+			os.Exit(1)
+		}
+
+		profiles := make([]*profiler.DatasetProfile, 2)
+		for i, file := range []string{trainFile, testFile} {
+			profiles[i], err = profiler.ProfileDatasetContext(ctx, file, profiler.Options{})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error profiling %s: %v\n", file, err)
+				exitStopped(ctx)
+				os.Exit(1)
+			}
+		}
+
+		result := &split.Result{
+			Source:     source,
+			TrainFile:  trainFile,
+			TestFile:   testFile,
+			StratifyBy: stratifyBy,
+			Seed:       seed,
+			Strata:     splitter.Strata(),
+			Comparison: compare.Compare(profiles[0], profiles[1], compare.DefaultOptions()),
+		}
+		result.TrainRows, result.TestRows = splitter.Totals()
+		result.Rows = result.TrainRows + result.TestRows
+
+		report.PrintSplitReport(result)
+
+		for i, file := range []string{trainFile, testFile} {
+			profileFile := strings.TrimSuffix(file, ".csv") + "_profile.json"
+			if err := report.GenerateJSONReport(profiles[i], profileFile); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing profile: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Profile of %s saved to: %s\n", file, profileFile)
+		}
+
+		passed := checkGate(profiles[0].Filename, gate.Check(profiles[0]))
+		if !checkGate(profiles[1].Filename, append(gate.Check(profiles[1]), gate.CheckDrift(result.Comparison)...)) || !passed {
+			os.Exit(gateExitCode)
+		}
+	},
+}
+
+// writeSplit reads source once, as profile does, writing each row to the
+// train or the test file. A split stopped before the end of the data is
+// removed rather than left holding some of the rows.
+func writeSplit(ctx context.Context, source, trainFile, testFile string, opts split.Options, table, format string) (*split.Splitter, error) {
+	train, err := os.Create(trainFile)
+	if err != nil {
+		return nil, err
+	}
+	defer train.Close()
+	test, err := os.Create(testFile)
+	if err != nil {
+		return nil, err
+	}
+	defer test.Close()
+
+	splitter, err := split.New(train, test, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	profileOpts := profiler.Options{
+		Format:   format,
+		Password: os.Getenv(profiler.PasswordEnv),
+		Rows:     splitter.Add,
+	}
+	if profiler.IsExcel(source) {
+		profileOpts.Sheet = table
+	} else {
+		profileOpts.Table = table
+	}
+
+	profile, err := profiler.ProfileDatasetContext(ctx, source, profileOpts)
+	if err == nil {
+		err = splitter.Flush()
+	}
+	if err == nil && profile.RowCount == 0 {
+		err = fmt.Errorf("no rows to split")
+	}
+	if err != nil {
+		os.Remove(trainFile)
+		os.Remove(testFile)
+		return nil, err
+	}
+	return splitter, nil
+}
+
+// splitBaseName names the sets after the source file.
+func splitBaseName(source string) string {
+	name := filepath.Base(source)
+	if source == "-" {
+		name = "stdin"
+	}
+	for ext := filepath.Ext(name); ext != ""; ext = filepath.Ext(name) {
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+
+	splitCmd.Flags().Float64("train", 0.8, "Share of the rows in the train set, the rest going to the test set")
+	splitCmd.Flags().String("stratify-by", "", "Column whose values keep the train share of their rows in both sets")
+	splitCmd.Flags().Int64("seed", 1, "Seed of the random assignment of rows; the same seed repeats a split")
+	splitCmd.Flags().String("output-dir", ".", "Directory to write <file>_train.csv, <file>_test.csv and their JSON profiles to")
+	splitCmd.Flags().String("table", "", "Table of a SQLite database or sheet of an Excel workbook to split")
+	splitCmd.Flags().String("format", "", "Input format: csv, tsv, jsonl, delta or iceberg (default: from the file extension, csv for stdin)")
+	splitCmd.Flags().Duration("timeout", 0, "Give up after this long, without writing the sets (0 = no limit)")
+	addGateFlags(splitCmd, true)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kamalm96/datasleuth/internal/report"
+	"github.com/kamalm96/datasleuth/internal/sign"
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [report.json...]",
+	Short: "Verify the signatures of JSON reports",
+	Long: `Check that JSON profiles and validation reports written with --sign have
+not changed since they were signed. Each report is checked against its
+signature, <report>.sig next to it, and the public key of the signer; the
+column files of a split report are checked with its index. The command
+fails when any signature does not hold.`,
+	Example: `  datasleuth profile orders.csv --output json --sign signing.pem
+  datasleuth verify orders.csv_profile.json --key signing.pub
+  datasleuth verify evidence/*.json --key signing.pub`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		keyFile, _ := cmd.Flags().GetString("key")
+		sigFile, _ := cmd.Flags().GetString("signature")
+
+		if keyFile == "" {
+			fmt.Fprintln(os.Stderr, "Missing --key: the public key, or private key, the reports were signed with")
+			os.Exit(1)
+		}
+		if sigFile != "" && len(args) > 1 {
+			fmt.Fprintln(os.Stderr, "Invalid --signature: give a single report with it")
+			os.Exit(1)
+		}
+		key, err := sign.LoadPublicKey(keyFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading key: %v\n", err)
+			os.Exit(1)
+		}
+
+		failed := 0
+		for _, path := range args {
+			files := append([]string{path}, splitColumnFiles(path)...)
+			for i, file := range files {
+				signature := ""
+				if i == 0 {
+					signature = sigFile
+				}
+				sig, err := sign.VerifyFile(file, signature, key)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "✗ %s: %v\n", file, err)
+					failed++
+					continue
+				}
+				fmt.Printf("✓ %s: signed %s with %s key %.16s\n", file, sig.SignedAt.Local().Format("2006-01-02 15:04:05"), sig.Algorithm, sig.KeyID)
+			}
+		}
+
+		if failed > 0 {
+			fmt.Fprintf(os.Stderr, "\n%d report file(s) failed verification\n", failed)
+			os.Exit(1)
+		}
+	},
+}
+
+// splitColumnFiles lists the column files of a split JSON report at path, or
+// nothing for any other file.
+func splitColumnFiles(path string) []string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var index struct {
+		ColumnFiles []report.JSONColumnFile `json:"column_files"`
+	}
+	if json.Unmarshal(content, &index) != nil {
+		return nil
+	}
+	files := make([]string, 0, len(index.ColumnFiles))
+	for _, file := range index.ColumnFiles {
+		files = append(files, filepath.Join(filepath.Dir(path), file.File))
+	}
+	return files
+}
+
+// readSigner loads the key of --sign, or returns nil when reports are not
+// signed. Only JSON reports are signed.
+func readSigner(cmd *cobra.Command, jsonOutput bool) crypto.Signer {
+	keyFile, _ := cmd.Flags().GetString("sign")
+	if keyFile == "" {
+		return nil
+	}
+	if !jsonOutput {
+		fmt.Fprintln(os.Stderr, "Invalid --sign: only JSON reports are signed; use --output json")
+		os.Exit(1)
+	}
+	if outputFile, _ := cmd.Flags().GetString("output-file"); outputFile == report.StdoutPath {
+		fmt.Fprintln(os.Stderr, "Invalid --sign: a report written to stdout cannot be signed")
+		os.Exit(1)
+	}
+	key, err := sign.LoadPrivateKey(keyFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading signing key: %v\n", err)
+		os.Exit(1)
+	}
+	return key
+}
+
+// signReports writes a signature next to each report file when key is set.
+func signReports(key crypto.Signer, files ...string) {
+	if key == nil {
+		return
+	}
+	for _, file := range files {
+		sig, err := sign.SignFile(file, key)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error signing report: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("🔏 Signed %s (%s key %.16s): %s\n", file, sig.Algorithm, sig.KeyID, sign.Path(file))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().String("key", "", "PEM file of the public key, or private key, the reports were signed with")
+	verifyCmd.Flags().String("signature", "", "Signature file of the report (default: <report>.sig)")
+}
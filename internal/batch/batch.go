@@ -0,0 +1,404 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/config"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/kamalm96/datasleuth/internal/validate"
+	"gopkg.in/yaml.v3"
+)
+
+// OutputExtensions are the report formats a source can write, by file
+// extension.
+var OutputExtensions = []string{".json", ".html", ".md"}
+
+// Manifest lists the sources of a batch run, each with its own options,
+// rules, quality gate and reports:
+//
+//	jobs: 4
+//	sources:
+//	  - source: exports/orders.csv
+//	    unique_key: [order_id]
+//	    rules: rules/orders.yaml
+//	    fail_below: 80
+//	    outputs: [reports/orders.json, reports/orders.html]
+//	  - name: users
+//	    source: app.db
+//	    table: users
+//	    sample: 100000
+//	    scoring: strict
+//	    max_missing: 5
+//
+// Relative paths of sources, rules, scoring files and outputs are relative to
+// the manifest.
+type Manifest struct {
+	Jobs    int      `yaml:"jobs,omitempty"`
+	Sources []Source `yaml:"sources"`
+}
+
+// Source is a dataset of a batch run. Unset options take the defaults of
+// profile, and an unset gate threshold is not checked.
+type Source struct {
+	Name           string        `yaml:"name,omitempty"` // default: the source
+	Source         string        `yaml:"source"`
+	Format         string        `yaml:"format,omitempty"`
+	Table          string        `yaml:"table,omitempty"`
+	Sheet          string        `yaml:"sheet,omitempty"`
+	Delimiter      string        `yaml:"delimiter,omitempty"`
+	NullValues     config.Tokens `yaml:"null_values,omitempty,flow"`
+	Encoding       string        `yaml:"encoding,omitempty"`
+	NumberFormat   string        `yaml:"number_format,omitempty"`
+	Locale         string        `yaml:"locale,omitempty"`
+	SkipRows       int           `yaml:"skip_rows,omitempty"`
+	Sample         int           `yaml:"sample,omitempty"`
+	SampleStrategy string        `yaml:"sample_strategy,omitempty"`
+	UniqueKey      []string      `yaml:"unique_key,omitempty,flow"`
+	WeightColumn   string        `yaml:"weight_column,omitempty"`
+	Redact         []string      `yaml:"redact,omitempty,flow"`
+	Scoring        string        `yaml:"scoring,omitempty"` // preset or scoring file
+
+	Rules         string   `yaml:"rules,omitempty"`
+	FailBelow     int      `yaml:"fail_below,omitempty"`
+	MaxMissing    *float64 `yaml:"max_missing,omitempty"`
+	MaxDuplicates *float64 `yaml:"max_duplicates,omitempty"`
+	Outputs       []string `yaml:"outputs,omitempty,flow"`
+
+	scoring     *profiler.Scoring
+	dateFormats []string
+}
+
+// Load reads a manifest and resolves its relative paths. Unknown keys are
+// rejected so that a misspelled option is not silently ignored.
+func Load(path string) (*Manifest, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m Manifest
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&m); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	for i := range m.Sources {
+		s := &m.Sources[i]
+		if s.Name == "" {
+			s.Name = s.Source
+		}
+		s.Source = resolve(dir, s.Source)
+		if s.Rules != "" {
+			s.Rules = resolve(dir, s.Rules)
+		}
+		if s.Scoring != "" {
+			if !slices.Contains(profiler.ScoringPresets(), s.Scoring) {
+				s.Scoring = resolve(dir, s.Scoring)
+			}
+			if s.scoring, err = config.LoadScoring(s.Scoring); err != nil {
+				return nil, fmt.Errorf("invalid manifest %s: source %s: %w", path, s.Name, err)
+			}
+		}
+		for j, output := range s.Outputs {
+			s.Outputs[j] = resolve(dir, output)
+		}
+	}
+	return &m, nil
+}
+
+// UseScoring weighs the quality score of the sources without a scoring of
+// their own with scoring.
+func (m *Manifest) UseScoring(scoring *profiler.Scoring) {
+	for i := range m.Sources {
+		if m.Sources[i].Scoring == "" {
+			m.Sources[i].scoring = scoring
+		}
+	}
+}
+
+// UseDateFormats has every source try formats before the built-in date
+// formats.
+func (m *Manifest) UseDateFormats(formats []string) {
+	for i := range m.Sources {
+		m.Sources[i].dateFormats = formats
+	}
+}
+
+// UseSettings fills in the delimiter, null values, sample and rules the
+// manifest leaves out of each source with those cfg gives it.
+func (m *Manifest) UseSettings(cfg *config.Config) {
+	for i := range m.Sources {
+		s := &m.Sources[i]
+		table := s.Table
+		if table == "" {
+			table = s.Sheet
+		}
+		settings := cfg.SettingsFor(s.Source, table)
+
+		if s.Delimiter == "" && profiler.IsDelimited(s.Source, s.Options()) {
+			s.Delimiter = settings.Delimiter
+		}
+		if s.NullValues == nil {
+			s.NullValues = settings.NullValues
+		}
+		if s.Sample == 0 {
+			s.Sample = settings.Sample
+		}
+		if s.Rules == "" {
+			s.Rules = cfg.RulesFor(s.Source, table)
+		}
+	}
+}
+
+// resolve joins a relative local path to dir, leaving URLs, connection
+// strings and stdin as they are.
+func resolve(dir, path string) string {
+	if path == profiler.StdinSource || filepath.IsAbs(path) || strings.Contains(path, "://") {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// Validate reports a manifest without sources, a source without a path, two
+// sources of the same name, or an option out of range.
+func (m *Manifest) Validate() error {
+	if len(m.Sources) == 0 {
+		return fmt.Errorf("no sources")
+	}
+	if m.Jobs < 0 {
+		return fmt.Errorf("jobs %d must not be negative", m.Jobs)
+	}
+
+	seen := make(map[string]bool, len(m.Sources))
+	for i, s := range m.Sources {
+		if s.Source == "" {
+			return fmt.Errorf("source %d has no source", i+1)
+		}
+		name := s.Name
+		if name == "" {
+			name = s.Source
+		}
+		if seen[name] {
+			return fmt.Errorf("source %s is listed more than once; tell them apart with name", name)
+		}
+		seen[name] = true
+
+		if s.Source == profiler.StdinSource {
+			return fmt.Errorf("source %s: stdin cannot be profiled in a batch", name)
+		}
+		if _, err := s.delimiter(); err != nil {
+			return fmt.Errorf("source %s: delimiter %s: %w", name, s.Delimiter, err)
+		}
+		if slices.Contains(s.NullValues, "") {
+			return fmt.Errorf("source %s: null values must not be empty", name)
+		}
+		if s.Sample < 0 || s.SkipRows < 0 {
+			return fmt.Errorf("source %s: sample and skip_rows must not be negative", name)
+		}
+		if s.FailBelow < 0 || s.FailBelow > 100 {
+			return fmt.Errorf("source %s: fail_below %d is not a quality score from 0 to 100", name, s.FailBelow)
+		}
+		for _, threshold := range []*float64{s.MaxMissing, s.MaxDuplicates} {
+			if threshold != nil && (*threshold < 0 || *threshold > 100) {
+				return fmt.Errorf("source %s: max_missing and max_duplicates are percentages from 0 to 100", name)
+			}
+		}
+		for _, output := range s.Outputs {
+			if !slices.Contains(OutputExtensions, strings.ToLower(filepath.Ext(output))) {
+				return fmt.Errorf("source %s: output %s is not a report (want %s)", name, output, strings.Join(OutputExtensions, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+func (s Source) delimiter() (rune, error) {
+	switch strings.ToLower(s.Delimiter) {
+	case "":
+		return 0, nil
+	case "tab", "\\t":
+		return '\t', nil
+	}
+	runes := []rune(s.Delimiter)
+	if len(runes) != 1 {
+		return 0, fmt.Errorf("expected a single character")
+	}
+	return runes[0], nil
+}
+
+// Options are the profiler options of the source.
+func (s Source) Options() profiler.Options {
+	delimiter, _ := s.delimiter()
+	return profiler.Options{
+		SampleSize:     s.Sample,
+		SampleStrategy: s.SampleStrategy,
+		Table:          s.Table,
+		Sheet:          s.Sheet,
+		Format:         s.Format,
+		Delimiter:      delimiter,
+		NullValues:     s.NullValues,
+		Encoding:       s.Encoding,
+		NumberFormat:   s.NumberFormat,
+		Locale:         s.Locale,
+		SkipRows:       s.SkipRows,
+		UniqueKey:      s.UniqueKey,
+		WeightColumn:   s.WeightColumn,
+		Redact:         s.Redact,
+		Scoring:        s.scoring,
+		DateFormats:    s.dateFormats,
+	}
+}
+
+// Gate is the quality gate of the source.
+func (s Source) Gate() validate.Gate {
+	gate := validate.NoGate()
+	gate.MinScore = s.FailBelow
+	if s.MaxMissing != nil {
+		gate.MaxMissing = *s.MaxMissing
+	}
+	if s.MaxDuplicates != nil {
+		gate.MaxDuplicate = *s.MaxDuplicates
+	}
+	return gate
+}
+
+// Result is the outcome of a source of a batch run.
+type Result struct {
+	Source     Source
+	Profile    *profiler.DatasetProfile
+	Validation *validate.Result // checks of the rules, nil without rules
+	Gate       []validate.Check
+	Outputs    []string // reports written
+	Elapsed    time.Duration
+	Err        error // the error that stopped the source
+}
+
+// RulesPassed reports whether the source met its rules, if any.
+func (r Result) RulesPassed() bool {
+	return r.Validation == nil || r.Validation.Passed()
+}
+
+// GatePassed reports whether the source passed its quality gate, if any.
+func (r Result) GatePassed() bool {
+	for _, check := range r.Gate {
+		if !check.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Passed reports whether the source was profiled and met its rules and
+// quality gate.
+func (r Result) Passed() bool {
+	return r.Err == nil && r.RulesPassed() && r.GatePassed()
+}
+
+// Workers is how many sources are profiled at once when asked for jobs: jobs,
+// or m.Jobs, or one per CPU, when 0, and no more than there are sources.
+func (m *Manifest) Workers(jobs int) int {
+	if jobs <= 0 {
+		jobs = m.Jobs
+	}
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	return min(jobs, len(m.Sources))
+}
+
+// Run profiles the sources of m, jobs at a time (m.Jobs, or one per CPU,
+// when jobs is 0), checks each against its rules and quality gate, and
+// writes its reports with write. The results are in the order of the
+// manifest; done, when set, is called as each source finishes. Once ctx is
+// done, the sources being profiled stop and the rest fail without being
+// profiled.
+func Run(ctx context.Context, m *Manifest, jobs int, write func(profile *profiler.DatasetProfile, path string) error, done func(Result)) []Result {
+	jobs = m.Workers(jobs)
+
+	results := make([]Result, len(m.Sources))
+	next := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = runSource(ctx, m.Sources[i], write)
+				if done != nil {
+					mu.Lock()
+					done(results[i])
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range m.Sources {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
+
+func runSource(ctx context.Context, s Source, write func(*profiler.DatasetProfile, string) error) Result {
+	startTime := time.Now()
+	result := Result{Source: s}
+
+	opts := s.Options()
+	var rules *validate.Rules
+	var rows *validate.RowChecker
+	if s.Rules != "" {
+		if rules, result.Err = validate.LoadRules(s.Rules); result.Err != nil {
+			result.Elapsed = time.Since(startTime)
+			return result
+		}
+		rows = rules.ApplyTo(&opts)
+	}
+
+	profile, err := profiler.ProfileDatasetContext(ctx, s.Source, opts)
+	if err != nil {
+		result.Err = err
+		result.Elapsed = time.Since(startTime)
+		return result
+	}
+	result.Profile = profile
+
+	if rules != nil {
+		result.Validation = validate.AgainstRules(profile, rules, s.Rules)
+		if rows != nil {
+			result.Validation.CheckRows(rows)
+		}
+	}
+	result.Gate = s.Gate().Check(profile)
+
+	for _, output := range s.Outputs {
+		if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+			result.Err = fmt.Errorf("failed to write report %s: %w", output, err)
+			break
+		}
+		if err := write(profile, output); err != nil {
+			result.Err = fmt.Errorf("failed to write report %s: %w", output, err)
+			break
+		}
+		result.Outputs = append(result.Outputs, output)
+	}
+	result.Elapsed = time.Since(startTime)
+	return result
+}
@@ -0,0 +1,215 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/config"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "manifest.yaml")
+	writeFile(t, path, `jobs: 3
+sources:
+  - source: data/orders.csv
+    delimiter: ";"
+    null_values: [NA]
+    unique_key: [order_id]
+    rules: rules/orders.yaml
+    fail_below: 80
+    max_missing: 0
+    outputs: [reports/orders.json]
+  - name: users
+    source: postgresql://localhost/app?table=users
+    sample: 1000
+    scoring: lenient
+`)
+
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if m.Jobs != 3 || len(m.Sources) != 2 {
+		t.Fatalf("Expected 3 jobs and 2 sources, got %+v", m)
+	}
+
+	orders, users := m.Sources[0], m.Sources[1]
+	if orders.Name != "data/orders.csv" || orders.Source != filepath.Join(dir, "data/orders.csv") {
+		t.Errorf("Expected the source resolved next to the manifest and named as listed, got %q and %q", orders.Name, orders.Source)
+	}
+	if orders.Rules != filepath.Join(dir, "rules/orders.yaml") || orders.Outputs[0] != filepath.Join(dir, "reports/orders.json") {
+		t.Errorf("Expected rules and outputs resolved next to the manifest, got %q and %v", orders.Rules, orders.Outputs)
+	}
+	if users.Source != "postgresql://localhost/app?table=users" {
+		t.Errorf("Expected a connection string to be left as is, got %q", users.Source)
+	}
+
+	opts := orders.Options()
+	if opts.Delimiter != ';' || len(opts.NullValues) != 1 || len(opts.UniqueKey) != 1 {
+		t.Errorf("Unexpected options %+v", opts)
+	}
+	gate := orders.Gate()
+	if gate.MinScore != 80 || gate.MaxMissing != 0 || gate.MaxDuplicate != -1 {
+		t.Errorf("Expected a gate of score 80 and no missing values, got %+v", gate)
+	}
+	if users.Options().SampleSize != 1000 || users.Gate().Enabled() {
+		t.Errorf("Expected a sample of 1000 and no gate, got %+v and %+v", users.Options(), users.Gate())
+	}
+
+	strict, _ := profiler.ScoringPreset(profiler.ScoringStrict)
+	m.UseScoring(strict)
+	if scoring := m.Sources[0].Options().Scoring; scoring == nil || scoring.Preset != profiler.ScoringStrict {
+		t.Errorf("Expected orders to take the strict scoring, got %+v", scoring)
+	}
+	if scoring := m.Sources[1].Options().Scoring; scoring == nil || scoring.Preset != profiler.ScoringLenient {
+		t.Errorf("Expected users to keep its lenient scoring, got %+v", scoring)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"sources: []\n", "no sources"},
+		{"sources:\n  - name: x\n", "source 1 has no source"},
+		{"sources:\n  - source: a.csv\n  - source: a.csv\n", "listed more than once"},
+		{"sources:\n  - source: a.csv\n    fail_below: 120\n", "fail_below 120"},
+		{"sources:\n  - source: a.csv\n    max_duplicates: -1\n", "percentages from 0 to 100"},
+		{"sources:\n  - source: a.csv\n    delimiter: ab\n", "expected a single character"},
+		{"sources:\n  - source: a.csv\n    null_values: [\"\"]\n", "null values must not be empty"},
+		{"sources:\n  - source: a.csv\n    outputs: [a.pdf]\n", "output a.pdf is not a report"},
+		{"sources:\n  - source: a.csv\n    sampel: 10\n", "field sampel not found"},
+		{"sources:\n  - source: a.csv\n    scoring: harsh\n", "neither a scoring preset"},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "manifest.yaml")
+		writeFile(t, path, tt.content)
+		if _, err := Load(path); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Expected an error containing %q for %q, got %v", tt.want, tt.content, err)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "people.csv"), "name,age\nAda,36\nBob,\nCy,41\n")
+	writeFile(t, filepath.Join(dir, "rules.yaml"), "columns:\n  - name: age\n    type: integer\n    max: 40\n")
+	path := filepath.Join(dir, "manifest.yaml")
+	writeFile(t, path, `sources:
+  - name: checked
+    source: people.csv
+    rules: rules.yaml
+    outputs: [out/people.json]
+  - name: gated
+    source: people.csv
+    max_missing: 10
+  - name: plain
+    source: people.csv
+  - source: missing.csv
+  - name: unwritable
+    source: people.csv
+    outputs: [bad.json]
+`)
+
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	var written []string
+	write := func(profile *profiler.DatasetProfile, path string) error {
+		if filepath.Base(path) == "bad.json" {
+			return errors.New("disk full")
+		}
+		written = append(written, path)
+		return nil
+	}
+	done := 0
+	results := Run(context.Background(), m, 2, write, func(Result) { done++ })
+
+	if len(results) != 5 || done != 5 {
+		t.Fatalf("Expected 5 results, reported as each finished, got %d and %d", len(results), done)
+	}
+	checked, gated, plain, missing, unwritable := results[0], results[1], results[2], results[3], results[4]
+
+	if checked.Source.Name != "checked" || checked.Validation == nil || checked.RulesPassed() || !checked.GatePassed() {
+		t.Errorf("Expected checked to break its max rule, got %+v", checked)
+	}
+	if len(checked.Outputs) != 1 || len(written) != 1 || written[0] != filepath.Join(dir, "out/people.json") {
+		t.Errorf("Expected the report of checked to be written, got %v and %v", checked.Outputs, written)
+	}
+	if gated.Validation != nil || gated.GatePassed() || gated.Passed() {
+		t.Errorf("Expected gated to fail its gate only, got %+v", gated)
+	}
+	if !plain.Passed() || plain.Profile.RowCount != 3 {
+		t.Errorf("Expected plain to pass with 3 rows, got %+v", plain)
+	}
+	if missing.Err == nil || missing.Profile != nil || missing.Passed() {
+		t.Errorf("Expected an error for the missing source, got %+v", missing)
+	}
+	if unwritable.Err == nil || !strings.Contains(unwritable.Err.Error(), "disk full") || unwritable.Profile == nil {
+		t.Errorf("Expected the failed report of unwritable, got %+v", unwritable)
+	}
+}
+
+func TestUseSettings(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "manifest.yaml")
+	writeFile(t, path, `sources:
+  - source: orders_2024.csv
+  - source: orders_2023.csv
+    delimiter: ","
+    sample: 10
+    rules: own.yaml
+  - source: events.jsonl
+`)
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	cfgPath := filepath.Join(dir, ".datasleuth.yaml")
+	writeFile(t, cfgPath, `defaults:
+  delimiter: ";"
+  null_values: [NA]
+  sample: 500
+datasets:
+  - dataset: orders_*.csv
+    null_values: [NULL]
+    rules: rules/orders.yaml
+`)
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	m.UseSettings(cfg)
+
+	filled, kept, events := m.Sources[0], m.Sources[1], m.Sources[2]
+	if filled.Delimiter != ";" || filled.Sample != 500 || len(filled.NullValues) != 1 || filled.NullValues[0] != "NULL" ||
+		filled.Rules != filepath.Join(dir, "rules/orders.yaml") {
+		t.Errorf("Expected the settings of the config, got %+v", filled)
+	}
+	if kept.Delimiter != "," || kept.Sample != 10 || kept.Rules != filepath.Join(dir, "own.yaml") {
+		t.Errorf("Expected the settings of the manifest kept, got %+v", kept)
+	}
+	if events.Delimiter != "" || events.Rules != "" || len(events.Options().NullValues) != 1 {
+		t.Errorf("Expected no delimiter for JSON Lines, got %+v", events)
+	}
+}
@@ -0,0 +1,95 @@
+package compare
+
+import (
+	"fmt"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+const (
+	cardinalityGrowthFactor = 2.0 // unique count must at least double
+	cardinalityRowGrowth    = 2.0 // and grow this many times faster than the values
+	cardinalityMinNewValues = 20
+	cardinalityLowRatio     = 0.5
+	cardinalityNearUnique   = 0.9
+)
+
+// CardinalityAlert is a column whose number of distinct values grew much
+// faster than its number of values between two runs, which usually means IDs
+// leaked into a categorical field or an upstream format changed.
+type CardinalityAlert struct {
+	Column       string
+	Base         string
+	Target       string
+	OldUnique    int
+	NewUnique    int
+	OldCount     int
+	NewCount     int
+	Growth       float64 // NewUnique / OldUnique
+	BecameUnique bool    // a low-cardinality column whose values are now nearly all distinct
+}
+
+func (a CardinalityAlert) Description() string {
+	if a.BecameUnique {
+		return fmt.Sprintf("'%s' went from %d to %d distinct values and is now nearly unique", a.Column, a.OldUnique, a.NewUnique)
+	}
+	return fmt.Sprintf("'%s' distinct values grew %.1fx (%d → %d) while values grew %.1fx", a.Column, a.Growth, a.OldUnique, a.NewUnique, growth(a.OldCount, a.NewCount))
+}
+
+// DetectCardinalityExplosions checks every column of each run against the run
+// before it. Runs must be ordered oldest first.
+func DetectCardinalityExplosions(runs []*profiler.DatasetProfile) []CardinalityAlert {
+	alerts := make([]CardinalityAlert, 0)
+
+	for i := 1; i < len(runs); i++ {
+		base, target := runs[i-1], runs[i]
+		for _, name := range sortedColumnNames(base) {
+			if alert, ok := cardinalityExplosion(base, target, name); ok {
+				alerts = append(alerts, alert)
+			}
+		}
+	}
+
+	return alerts
+}
+
+func cardinalityExplosion(base, target *profiler.DatasetProfile, name string) (CardinalityAlert, bool) {
+	oldCol, newCol := base.Columns[name], target.Columns[name]
+	if oldCol == nil || newCol == nil || oldCol.Count == 0 || newCol.Count == 0 || oldCol.UniqueCount == 0 {
+		return CardinalityAlert{}, false
+	}
+
+	alert := CardinalityAlert{
+		Column:    name,
+		Base:      base.Filename,
+		Target:    target.Filename,
+		OldUnique: oldCol.UniqueCount,
+		NewUnique: newCol.UniqueCount,
+		OldCount:  oldCol.Count,
+		NewCount:  newCol.Count,
+		Growth:    growth(oldCol.UniqueCount, newCol.UniqueCount),
+	}
+
+	if alert.NewUnique-alert.OldUnique < cardinalityMinNewValues {
+		return alert, false
+	}
+
+	oldRatio := float64(oldCol.UniqueCount) / float64(oldCol.Count)
+	newRatio := float64(newCol.UniqueCount) / float64(newCol.Count)
+	if oldRatio >= cardinalityNearUnique {
+		// Identifier-like columns are expected to grow with the data
+		return alert, false
+	}
+
+	alert.BecameUnique = oldRatio < cardinalityLowRatio && newRatio >= cardinalityNearUnique
+	exploded := alert.Growth >= cardinalityGrowthFactor && alert.Growth >= growth(oldCol.Count, newCol.Count)*cardinalityRowGrowth
+
+	return alert, exploded || alert.BecameUnique
+}
+
+func growth(old, new int) float64 {
+	if old == 0 {
+		return 0
+	}
+	return float64(new) / float64(old)
+}
@@ -0,0 +1,83 @@
+package compare
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func cardinalityRun(name string, count, unique int) *profiler.DatasetProfile {
+	return &profiler.DatasetProfile{
+		Filename: name,
+		RowCount: count,
+		Columns: map[string]*profiler.ColumnProfile{
+			"status": {Name: "status", DataType: "string", Count: count, UniqueCount: unique},
+			"id":     {Name: "id", DataType: "integer", Count: count, UniqueCount: count},
+		},
+	}
+}
+
+func TestDetectCardinalityExplosions(t *testing.T) {
+	runs := []*profiler.DatasetProfile{
+		cardinalityRun("day1.csv", 1000, 5),
+		cardinalityRun("day2.csv", 1100, 6),
+		cardinalityRun("day3.csv", 1200, 400),
+	}
+
+	alerts := DetectCardinalityExplosions(runs)
+
+	if len(alerts) != 1 {
+		t.Fatalf("Expected one alert, got %v", alerts)
+	}
+
+	alert := alerts[0]
+	if alert.Column != "status" || alert.Base != "day2.csv" || alert.Target != "day3.csv" {
+		t.Errorf("Expected status to explode between day2 and day3, got %+v", alert)
+	}
+	if alert.OldUnique != 6 || alert.NewUnique != 400 || alert.BecameUnique {
+		t.Errorf("Expected 6 → 400 distinct values without becoming unique, got %+v", alert)
+	}
+}
+
+func TestCardinalityBecameUnique(t *testing.T) {
+	alerts := DetectCardinalityExplosions([]*profiler.DatasetProfile{
+		cardinalityRun("before.csv", 100, 40),
+		cardinalityRun("after.csv", 100, 95),
+	})
+
+	if len(alerts) != 1 || !alerts[0].BecameUnique {
+		t.Fatalf("Expected status to be flagged as nearly unique, got %v", alerts)
+	}
+}
+
+func TestCardinalityProportionalGrowth(t *testing.T) {
+	// Distinct values growing in step with the data are not an explosion
+	alerts := DetectCardinalityExplosions([]*profiler.DatasetProfile{
+		cardinalityRun("small.csv", 1000, 100),
+		cardinalityRun("large.csv", 3000, 300),
+	})
+
+	if len(alerts) != 0 {
+		t.Errorf("Expected no alerts, got %v", alerts)
+	}
+}
+
+func TestCompareFlagsCardinality(t *testing.T) {
+	base := createProfile()
+	target := createProfile()
+	base.Columns["region"].UniqueCount = 2
+	target.Columns["region"].UniqueCount = 90
+
+	result := Compare(base, target, DefaultOptions())
+
+	if len(result.Cardinality) != 1 || result.Cardinality[0].Column != "region" {
+		t.Fatalf("Expected region cardinality alert, got %v", result.Cardinality)
+	}
+
+	for _, col := range result.Columns {
+		if col.Name == "region" && fmt.Sprint(col.Changes) != "[cardinality]" {
+			t.Errorf("Expected region changes to be [cardinality], got %v", col.Changes)
+		}
+	}
+}
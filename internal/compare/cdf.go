@@ -0,0 +1,175 @@
+package compare
+
+import (
+	"math"
+	"sort"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// cdf is the cumulative distribution of a numeric column through knots,
+// linear between them.
+type cdf struct {
+	x []float64
+	y []float64 // share of the values at or below x, non-decreasing
+}
+
+// histogramCDF runs through the bucket edges of a histogram, where the
+// shares are exact. It returns false for a histogram of no values.
+func histogramCDF(buckets []profiler.HistogramBucket) (cdf, bool) {
+	total := 0
+	for _, bucket := range buckets {
+		total += bucket.Count
+	}
+	if total == 0 {
+		return cdf{}, false
+	}
+
+	c := cdf{x: []float64{buckets[0].LowerBound}, y: []float64{0}}
+	cumulative := 0
+	for _, bucket := range buckets {
+		cumulative += bucket.Count
+		c.x = append(c.x, bucket.UpperBound)
+		c.y = append(c.y, float64(cumulative)/float64(total))
+	}
+	return c, true
+}
+
+// columnCDF is the cumulative distribution of col from its histogram and its
+// min, percentiles, median and max. The percentiles place the values inside
+// wide buckets: one outlier stretches an equal-width histogram until most
+// values share its first bucket, where the histogram alone would spread
+// them evenly. It returns false when col has neither.
+func columnCDF(col *profiler.ColumnProfile) (cdf, bool) {
+	hist, ok := histogramCDF(col.HistogramBuckets)
+
+	type knot struct{ x, y float64 }
+	var points []knot
+	if min, isFloat := col.Min.(float64); isFloat {
+		points = append(points, knot{min, 0})
+	}
+	for _, p := range col.Percentiles {
+		points = append(points, knot{p.Value, float64(p.Rank) / 100})
+	}
+	if len(col.Percentiles) > 0 {
+		points = append(points, knot{col.Median, 0.5})
+	}
+	if max, isFloat := col.Max.(float64); isFloat {
+		points = append(points, knot{max, 1})
+	}
+	if !ok && len(points) < 2 {
+		return cdf{}, false
+	}
+
+	if ok {
+		// The histogram counts are exact, so an estimated percentile stays
+		// between the shares at the edges of its bucket
+		for i := range points {
+			low, high := hist.bounds(points[i].x)
+			points[i].y = math.Min(math.Max(points[i].y, low), high)
+		}
+		for i := range hist.x {
+			points = append(points, knot{hist.x[i], hist.y[i]})
+		}
+	}
+
+	sort.SliceStable(points, func(i, j int) bool {
+		if points[i].x != points[j].x {
+			return points[i].x < points[j].x
+		}
+		return points[i].y < points[j].y
+	})
+	var c cdf
+	for _, p := range points {
+		if n := len(c.y); n > 0 {
+			p.y = math.Max(p.y, c.y[n-1])
+		}
+		c.x = append(c.x, p.x)
+		c.y = append(c.y, p.y)
+	}
+	return c, true
+}
+
+// bounds returns the shares at the knots either side of x, or at x itself
+// when it is a knot.
+func (c cdf) bounds(x float64) (float64, float64) {
+	i := sort.SearchFloat64s(c.x, x) // first knot at or after x
+	switch {
+	case i == len(c.x):
+		return c.y[i-1], c.y[i-1]
+	case c.x[i] == x:
+		// The last of the knots at x
+		j := i
+		for j+1 < len(c.x) && c.x[j+1] == x {
+			j++
+		}
+		return c.y[i], c.y[j]
+	case i == 0:
+		return 0, c.y[0]
+	default:
+		return c.y[i-1], c.y[i]
+	}
+}
+
+// at returns the share of the values at or below x.
+func (c cdf) at(x float64) float64 {
+	i := sort.Search(len(c.x), func(i int) bool { return c.x[i] > x }) - 1 // last knot at or before x
+	switch {
+	case i < 0:
+		return 0
+	case i == len(c.x)-1:
+		return c.y[i]
+	}
+	return c.y[i] + (c.y[i+1]-c.y[i])*(x-c.x[i])/(c.x[i+1]-c.x[i])
+}
+
+// quantile returns the value below which a share q of the values fall.
+func (c cdf) quantile(q float64) float64 {
+	i := sort.Search(len(c.y), func(i int) bool { return c.y[i] >= q }) // first knot reaching q
+	switch {
+	case i == 0:
+		return c.x[0]
+	case i == len(c.y):
+		return c.x[len(c.x)-1]
+	}
+	return c.x[i-1] + (q-c.y[i-1])/(c.y[i]-c.y[i-1])*(c.x[i]-c.x[i-1])
+}
+
+// quantileEdges returns the distinct values splitting c into count bins of
+// equal share.
+func (c cdf) quantileEdges(count int) []float64 {
+	edges := make([]float64, 0, count-1)
+	for i := 1; i < count; i++ {
+		edge := c.quantile(float64(i) / float64(count))
+		if len(edges) == 0 || edge > edges[len(edges)-1] {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+// shares returns the share of the values in each bin bounded by edges, from
+// below the first to above the last.
+func (c cdf) shares(edges []float64) []float64 {
+	shares := make([]float64, len(edges)+1)
+	below := 0.0
+	for i, edge := range edges {
+		at := c.at(edge)
+		shares[i] = at - below
+		below = at
+	}
+	shares[len(edges)] = c.y[len(c.y)-1] - below
+	return shares
+}
+
+// kolmogorovSmirnov is the largest gap between two cumulative distributions,
+// found at one of their knots since both are linear in between.
+func kolmogorovSmirnov(p, q cdf) float64 {
+	ks := 0.0
+	for _, c := range []cdf{p, q} {
+		for _, x := range c.x {
+			ks = math.Max(ks, math.Abs(p.at(x)-q.at(x)))
+		}
+	}
+	return ks
+}
@@ -0,0 +1,265 @@
+package compare
+
+import (
+	"math"
+	"sort"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+const (
+	meanShiftThreshold     = 0.5
+	stdDevChangeThreshold  = 0.25
+	missingRateThreshold   = 5.0
+	driftHistogramBuckets  = 20
+	otherValuesDriftBucket = "\x00other"
+)
+
+type Options struct {
+	SchemaOnly bool
+	Drift      DriftThresholds // all zero measures no drift
+}
+
+// DefaultOptions compares schemas and statistics, with the default drift
+// thresholds.
+func DefaultOptions() Options {
+	return Options{Drift: DefaultDriftThresholds()}
+}
+
+type Result struct {
+	Base           string
+	Target         string
+	BaseRowCount   int
+	TargetRowCount int
+	SchemaOnly     bool
+	Drift          DriftThresholds
+	AddedColumns   []ColumnSchema
+	RemovedColumns []ColumnSchema
+	RetypedColumns []TypeChange
+	Renames        []Rename
+	Columns        []ColumnDiff
+	Cardinality    []CardinalityAlert
+	Overlaps       []ValueOverlap // string columns of both, renamed ones included, when profiled with MinHash
+}
+
+type ColumnSchema struct {
+	Name     string
+	DataType string
+}
+
+type TypeChange struct {
+	Name    string
+	OldType string
+	NewType string
+}
+
+type ColumnDiff struct {
+	Name              string
+	IsNumeric         bool
+	OldMissingPercent float64
+	NewMissingPercent float64
+	OldMean           float64
+	NewMean           float64
+	OldStdDev         float64
+	NewStdDev         float64
+	MeanShift         float64 // difference in means, in units of the old standard deviation
+	Drift             float64 // total variation distance between the two distributions (0-1)
+	PSI               float64 // population stability index, numeric columns
+	KS                float64 // Kolmogorov–Smirnov statistic, numeric columns
+	JSDivergence      float64 // Jensen–Shannon divergence, categorical columns
+	ChiSquare         float64 // chi-square statistic, categorical columns
+	ChiSquarePValue   float64
+	DriftLowerBound   bool     // categorical metrics measured on the top values alone, which understates them
+	DriftMetrics      []string // metrics that reached their threshold
+	Changes           []string
+}
+
+func (r *Result) RowCountDelta() int {
+	return r.TargetRowCount - r.BaseRowCount
+}
+
+func (r *Result) SchemaChanged() bool {
+	return len(r.AddedColumns) > 0 || len(r.RemovedColumns) > 0 || len(r.RetypedColumns) > 0
+}
+
+// RenameOf returns the probable rename involving the named column, if any.
+func (r *Result) RenameOf(name string) (Rename, bool) {
+	for _, rename := range r.Renames {
+		if rename.OldName == name || rename.NewName == name {
+			return rename, true
+		}
+	}
+	return Rename{}, false
+}
+
+func (r *Result) ChangedColumns() []ColumnDiff {
+	changed := make([]ColumnDiff, 0)
+	for _, col := range r.Columns {
+		if col.Changed() {
+			changed = append(changed, col)
+		}
+	}
+	return changed
+}
+
+func (d ColumnDiff) Changed() bool {
+	return len(d.Changes) > 0
+}
+
+func (d ColumnDiff) Drifted() bool {
+	return len(d.DriftMetrics) > 0
+}
+
+func Compare(base, target *profiler.DatasetProfile, opts Options) *Result {
+	result := &Result{
+		Base:           base.Filename,
+		Target:         target.Filename,
+		BaseRowCount:   base.RowCount,
+		TargetRowCount: target.RowCount,
+		SchemaOnly:     opts.SchemaOnly,
+		Drift:          opts.Drift,
+		AddedColumns:   make([]ColumnSchema, 0),
+		RemovedColumns: make([]ColumnSchema, 0),
+		RetypedColumns: make([]TypeChange, 0),
+		Renames:        make([]Rename, 0),
+		Columns:        make([]ColumnDiff, 0),
+		Cardinality:    make([]CardinalityAlert, 0),
+		Overlaps:       make([]ValueOverlap, 0),
+	}
+
+	for _, name := range sortedColumnNames(base) {
+		baseCol := base.Columns[name]
+		targetCol, ok := target.Columns[name]
+		if !ok {
+			result.RemovedColumns = append(result.RemovedColumns, ColumnSchema{Name: name, DataType: baseCol.DataType})
+			continue
+		}
+
+		if baseCol.DataType != targetCol.DataType {
+			result.RetypedColumns = append(result.RetypedColumns, TypeChange{
+				Name:    name,
+				OldType: baseCol.DataType,
+				NewType: targetCol.DataType,
+			})
+		}
+
+		if !opts.SchemaOnly {
+			diff := compareColumn(baseCol, targetCol, base.RowCount, target.RowCount, opts.Drift)
+			if alert, ok := cardinalityExplosion(base, target, name); ok {
+				diff.Changes = append(diff.Changes, "cardinality")
+				result.Cardinality = append(result.Cardinality, alert)
+			}
+			if overlap, ok := valueOverlap(baseCol, targetCol); ok {
+				if overlap.Different() {
+					diff.Changes = append(diff.Changes, "values")
+				}
+				result.Overlaps = append(result.Overlaps, overlap)
+			}
+			result.Columns = append(result.Columns, diff)
+		}
+	}
+
+	for _, name := range sortedColumnNames(target) {
+		if _, ok := base.Columns[name]; !ok {
+			result.AddedColumns = append(result.AddedColumns, ColumnSchema{Name: name, DataType: target.Columns[name].DataType})
+		}
+	}
+
+	result.Renames = detectRenames(base, target, result.RemovedColumns, result.AddedColumns)
+	if !opts.SchemaOnly {
+		for _, rename := range result.Renames {
+			if overlap, ok := valueOverlap(base.Columns[rename.OldName], target.Columns[rename.NewName]); ok {
+				result.Overlaps = append(result.Overlaps, overlap)
+			}
+		}
+	}
+
+	return result
+}
+
+func compareColumn(baseCol, targetCol *profiler.ColumnProfile, baseRows, targetRows int, thresholds DriftThresholds) ColumnDiff {
+	diff := ColumnDiff{
+		Name:              baseCol.Name,
+		IsNumeric:         baseCol.IsNumeric && targetCol.IsNumeric,
+		OldMissingPercent: percent(baseCol.MissingCount, baseRows),
+		NewMissingPercent: percent(targetCol.MissingCount, targetRows),
+		Changes:           make([]string, 0),
+	}
+
+	if math.Abs(diff.NewMissingPercent-diff.OldMissingPercent) >= missingRateThreshold {
+		diff.Changes = append(diff.Changes, "missing_rate")
+	}
+
+	if diff.IsNumeric {
+		diff.OldMean = baseCol.Mean
+		diff.NewMean = targetCol.Mean
+		diff.OldStdDev = baseCol.StdDev
+		diff.NewStdDev = targetCol.StdDev
+
+		if baseCol.StdDev > 0 {
+			diff.MeanShift = (targetCol.Mean - baseCol.Mean) / baseCol.StdDev
+			if math.Abs(diff.MeanShift) >= meanShiftThreshold {
+				diff.Changes = append(diff.Changes, "mean")
+			}
+		} else if targetCol.Mean != baseCol.Mean {
+			diff.Changes = append(diff.Changes, "mean")
+		}
+
+		if relativeChange(baseCol.StdDev, targetCol.StdDev) >= stdDevChangeThreshold {
+			diff.Changes = append(diff.Changes, "stddev")
+		}
+
+		measureNumericDrift(&diff, baseCol, targetCol)
+	} else {
+		measureCategoricalDrift(&diff, baseCol, targetCol)
+	}
+
+	diff.DriftMetrics = driftMetrics(diff, thresholds)
+	if diff.Drifted() {
+		diff.Changes = append(diff.Changes, "distribution")
+	}
+
+	return diff
+}
+
+func valueProportions(col *profiler.ColumnProfile) map[string]float64 {
+	proportions := make(map[string]float64)
+
+	covered := 0
+	for _, val := range col.TopValues {
+		proportions[val.Value] = float64(val.Count) / float64(col.Count)
+		covered += val.Count
+	}
+
+	if covered < col.Count {
+		proportions[otherValuesDriftBucket] = float64(col.Count-covered) / float64(col.Count)
+	}
+
+	return proportions
+}
+
+func sortedColumnNames(profile *profiler.DatasetProfile) []string {
+	names := make([]string, 0, len(profile.Columns))
+	for name := range profile.Columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+func relativeChange(old, new float64) float64 {
+	if old == 0 {
+		if new == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return math.Abs(new-old) / math.Abs(old)
+}
@@ -0,0 +1,122 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func createProfile() *profiler.DatasetProfile {
+	return &profiler.DatasetProfile{
+		Filename:    "base.csv",
+		RowCount:    100,
+		ColumnCount: 3,
+		Columns: map[string]*profiler.ColumnProfile{
+			"amount": {
+				Name:      "amount",
+				DataType:  "float",
+				Count:     100,
+				IsNumeric: true,
+				Mean:      50,
+				StdDev:    10,
+				HistogramBuckets: []profiler.HistogramBucket{
+					{LowerBound: 0, UpperBound: 50, Count: 50},
+					{LowerBound: 50, UpperBound: 100, Count: 50},
+				},
+			},
+			"region": {
+				Name:     "region",
+				DataType: "string",
+				Count:    100,
+				TopValues: []profiler.ValueCount{
+					{Value: "east", Count: 50},
+					{Value: "west", Count: 50},
+				},
+			},
+			"legacy": {Name: "legacy", DataType: "string", Count: 100},
+		},
+	}
+}
+
+func TestCompareIdentical(t *testing.T) {
+	result := Compare(createProfile(), createProfile(), DefaultOptions())
+
+	if result.SchemaChanged() {
+		t.Error("Expected no schema changes")
+	}
+
+	if len(result.ChangedColumns()) != 0 {
+		t.Errorf("Expected no changed columns, got %v", result.ChangedColumns())
+	}
+}
+
+func TestCompareSchemaChanges(t *testing.T) {
+	target := createProfile()
+	delete(target.Columns, "legacy")
+	target.Columns["amount"].DataType = "integer"
+	target.Columns["created_at"] = &profiler.ColumnProfile{Name: "created_at", DataType: "datetime"}
+
+	result := Compare(createProfile(), target, Options{SchemaOnly: true})
+
+	if len(result.AddedColumns) != 1 || result.AddedColumns[0].Name != "created_at" {
+		t.Errorf("Expected created_at to be added, got %v", result.AddedColumns)
+	}
+
+	if len(result.RemovedColumns) != 1 || result.RemovedColumns[0].Name != "legacy" {
+		t.Errorf("Expected legacy to be removed, got %v", result.RemovedColumns)
+	}
+
+	if len(result.RetypedColumns) != 1 || result.RetypedColumns[0].NewType != "integer" {
+		t.Errorf("Expected amount to be retyped, got %v", result.RetypedColumns)
+	}
+
+	if len(result.Columns) != 0 {
+		t.Error("Expected no statistical comparison in schema-only mode")
+	}
+}
+
+func TestCompareDistributionShift(t *testing.T) {
+	target := createProfile()
+	target.RowCount = 120
+	target.Columns["amount"].Mean = 80
+	target.Columns["amount"].HistogramBuckets = []profiler.HistogramBucket{
+		{LowerBound: 50, UpperBound: 100, Count: 60},
+		{LowerBound: 100, UpperBound: 150, Count: 60},
+	}
+	target.Columns["region"].MissingCount = 20
+	target.Columns["region"].TopValues = []profiler.ValueCount{
+		{Value: "east", Count: 90},
+		{Value: "west", Count: 10},
+	}
+
+	result := Compare(createProfile(), target, DefaultOptions())
+
+	if result.RowCountDelta() != 20 {
+		t.Errorf("Expected row delta of 20, got %d", result.RowCountDelta())
+	}
+
+	diffs := make(map[string]ColumnDiff)
+	for _, diff := range result.Columns {
+		diffs[diff.Name] = diff
+	}
+
+	amount := diffs["amount"]
+	if amount.MeanShift != 3 {
+		t.Errorf("Expected mean shift of 3 stddevs, got %.2f", amount.MeanShift)
+	}
+	if !amount.Drifted() {
+		t.Errorf("Expected amount to drift, got %.3f", amount.Drift)
+	}
+
+	region := diffs["region"]
+	if !region.Drifted() {
+		t.Errorf("Expected region to drift, got %.3f", region.Drift)
+	}
+	if region.NewMissingPercent <= region.OldMissingPercent {
+		t.Error("Expected region missing rate to increase")
+	}
+
+	if len(result.ChangedColumns()) != 2 {
+		t.Errorf("Expected 2 changed columns, got %d", len(result.ChangedColumns()))
+	}
+}
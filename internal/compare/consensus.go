@@ -0,0 +1,330 @@
+package compare
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+const (
+	// ConsensusMinFiles is the number of files below which no file can be
+	// told apart from the rest.
+	ConsensusMinFiles = 3
+
+	consensusDrift         = 0.3  // total variation distance from the consensus distribution (0-1)
+	consensusDriftFactor   = 3.0  // and this many times the median distance of the files
+	consensusMissingPoints = 20.0 // missing rate above the median of the files, in percentage points
+)
+
+// Consensus is the schema most files of a batch agree on, and the files
+// that deviate from it in schema or in the distribution of a column.
+type Consensus struct {
+	Files    int
+	Columns  []ConsensusColumn
+	Outliers []FileDeviation // in the order the files were given
+}
+
+// ConsensusColumn is a column that more than half of the files have, with
+// the type most of those give it.
+type ConsensusColumn struct {
+	Name     string
+	DataType string
+	Present  int // files with the column
+}
+
+// FileDeviation lists how a file departs from the consensus.
+type FileDeviation struct {
+	Name           string
+	MissingColumns []string
+	ExtraColumns   []string
+	RetypedColumns []TypeChange // OldType is the consensus type
+	Columns        []ColumnDeviation
+}
+
+// ColumnDeviation is a consensus column whose values in one file are
+// distributed unlike in the other files, or missing far more often.
+type ColumnDeviation struct {
+	Name             string
+	Drift            float64 // total variation distance from the consensus distribution (0-1)
+	MedianDrift      float64 // median distance of the files from it
+	MissingPercent   float64
+	ConsensusMissing float64 // median missing rate of the files
+}
+
+// Description says how the column deviates.
+func (d ColumnDeviation) Description() string {
+	var reasons []string
+	if d.Drift > 0 {
+		reasons = append(reasons, fmt.Sprintf("distribution differs from the other files (TVD %.2f, typical %.2f)", d.Drift, d.MedianDrift))
+	}
+	if d.MissingDeviates() {
+		reasons = append(reasons, fmt.Sprintf("%.1f%% missing (median %.1f%%)", d.MissingPercent, d.ConsensusMissing))
+	}
+	return fmt.Sprintf("'%s': %s", d.Name, strings.Join(reasons, ", "))
+}
+
+// MissingDeviates reports whether the column is missing far more often than
+// in most files.
+func (d ColumnDeviation) MissingDeviates() bool {
+	return d.MissingPercent >= d.ConsensusMissing+consensusMissingPoints
+}
+
+// Descriptions lists every deviation of the file, schema first.
+func (f FileDeviation) Descriptions() []string {
+	var lines []string
+	if len(f.MissingColumns) > 0 {
+		lines = append(lines, "missing columns: "+strings.Join(f.MissingColumns, ", "))
+	}
+	if len(f.ExtraColumns) > 0 {
+		lines = append(lines, "extra columns: "+strings.Join(f.ExtraColumns, ", "))
+	}
+	for _, change := range f.RetypedColumns {
+		lines = append(lines, fmt.Sprintf("'%s' is %s, not %s", change.Name, change.NewType, change.OldType))
+	}
+	for _, col := range f.Columns {
+		lines = append(lines, col.Description())
+	}
+	return lines
+}
+
+// SchemaDeviates reports whether the columns or types of the file differ
+// from the consensus.
+func (f FileDeviation) SchemaDeviates() bool {
+	return len(f.MissingColumns) > 0 || len(f.ExtraColumns) > 0 || len(f.RetypedColumns) > 0
+}
+
+// BuildConsensus finds the consensus schema of profiles, named by names, and
+// the files that deviate from it. A column belongs to the consensus when
+// more than half of the files have it. Each file's distribution of a
+// consensus column is compared with the median distribution of the files;
+// it deviates when the distance is large both outright and against the
+// distances of the other files, so a batch that varies a lot throughout
+// flags nothing. It returns nil for fewer than ConsensusMinFiles profiles.
+func BuildConsensus(profiles []*profiler.DatasetProfile, names []string) *Consensus {
+	if len(profiles) < ConsensusMinFiles {
+		return nil
+	}
+
+	consensus := &Consensus{Files: len(profiles), Columns: consensusColumns(profiles), Outliers: make([]FileDeviation, 0)}
+	deviations := make([]FileDeviation, len(profiles))
+	for i, profile := range profiles {
+		deviations[i] = schemaDeviation(profile, names[i], consensus.Columns)
+	}
+
+	for _, col := range consensus.Columns {
+		for i, dev := range columnDeviations(profiles, col) {
+			if dev != nil {
+				deviations[i].Columns = append(deviations[i].Columns, *dev)
+			}
+		}
+	}
+
+	for _, dev := range deviations {
+		if dev.SchemaDeviates() || len(dev.Columns) > 0 {
+			consensus.Outliers = append(consensus.Outliers, dev)
+		}
+	}
+	return consensus
+}
+
+// consensusColumns returns the columns of more than half of the profiles, in
+// their average position.
+func consensusColumns(profiles []*profiler.DatasetProfile) []ConsensusColumn {
+	present := make(map[string]int)
+	positions := make(map[string]int)
+	types := make(map[string]map[string]int)
+	for _, profile := range profiles {
+		for name, col := range profile.Columns {
+			present[name]++
+			positions[name] += col.Position
+			if types[name] == nil {
+				types[name] = make(map[string]int)
+			}
+			types[name][col.DataType]++
+		}
+	}
+
+	columns := make([]ConsensusColumn, 0)
+	for name, n := range present {
+		if 2*n <= len(profiles) {
+			continue
+		}
+		columns = append(columns, ConsensusColumn{Name: name, DataType: mostCommon(types[name]), Present: n})
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		a := float64(positions[columns[i].Name]) / float64(columns[i].Present)
+		b := float64(positions[columns[j].Name]) / float64(columns[j].Present)
+		if a != b {
+			return a < b
+		}
+		return columns[i].Name < columns[j].Name
+	})
+	return columns
+}
+
+// mostCommon returns the key with the highest count, the first in order on
+// a tie.
+func mostCommon(counts map[string]int) string {
+	best, bestCount := "", 0
+	for key, n := range counts {
+		if n > bestCount || (n == bestCount && key < best) {
+			best, bestCount = key, n
+		}
+	}
+	return best
+}
+
+func schemaDeviation(profile *profiler.DatasetProfile, name string, columns []ConsensusColumn) FileDeviation {
+	dev := FileDeviation{Name: name}
+	known := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		known[col.Name] = true
+		fileCol, ok := profile.Columns[col.Name]
+		switch {
+		case !ok:
+			dev.MissingColumns = append(dev.MissingColumns, col.Name)
+		case fileCol.DataType != col.DataType:
+			dev.RetypedColumns = append(dev.RetypedColumns, TypeChange{Name: col.Name, OldType: col.DataType, NewType: fileCol.DataType})
+		}
+	}
+	for _, colName := range sortedColumnNames(profile) {
+		if !known[colName] {
+			dev.ExtraColumns = append(dev.ExtraColumns, colName)
+		}
+	}
+	return dev
+}
+
+// columnDeviations compares the distribution and missing rate of col in
+// each profile with the other profiles, returning a deviation per profile
+// or nil. Files where the column is missing or of another type are left
+// out; their schema deviation says enough.
+func columnDeviations(profiles []*profiler.DatasetProfile, col ConsensusColumn) []*ColumnDeviation {
+	var indexes []int
+	var cols []*profiler.ColumnProfile
+	for i, profile := range profiles {
+		if c, ok := profile.Columns[col.Name]; ok && c.DataType == col.DataType {
+			indexes = append(indexes, i)
+			cols = append(cols, c)
+		}
+	}
+	if len(cols) < ConsensusMinFiles {
+		return make([]*ColumnDeviation, len(profiles))
+	}
+
+	missing := make([]float64, len(cols))
+	for i, c := range cols {
+		missing[i] = percent(c.MissingCount, profiles[indexes[i]].RowCount)
+	}
+	medianMissing := median(missing)
+
+	drifts := consensusDrifts(cols)
+	medianDrift := median(drifts)
+
+	result := make([]*ColumnDeviation, len(profiles))
+	for i, c := range cols {
+		dev := ColumnDeviation{Name: c.Name, MedianDrift: medianDrift, MissingPercent: missing[i], ConsensusMissing: medianMissing}
+		if drifts[i] >= consensusDrift && drifts[i] >= consensusDriftFactor*medianDrift {
+			dev.Drift = drifts[i]
+		}
+		if dev.Drift > 0 || dev.MissingDeviates() {
+			result[indexes[i]] = &dev
+		}
+	}
+	return result
+}
+
+// consensusDrifts returns, for each column, the total variation distance of
+// its distribution from the consensus distribution: the median share of
+// each value or histogram bin across the columns, normalized to add up to
+// one. Unlike the pooled distribution of the other files, the median is not
+// pulled towards a deviating minority. Numeric columns are compared by their
+// cumulative distributions over shared bins, split at the median of the
+// columns' quantiles so that an outlier in one file does not stretch them,
+// others by their top values.
+func consensusDrifts(cols []*profiler.ColumnProfile) []float64 {
+	dists := make([]map[string]float64, len(cols))
+	cdfs := make([]cdf, len(cols))
+	numeric := true
+	for i, c := range cols {
+		var ok bool
+		cdfs[i], ok = columnCDF(c)
+		numeric = numeric && c.IsNumeric && ok
+	}
+
+	if numeric {
+		var edges []float64
+		quantiles := make([]float64, len(cols))
+		for j := 1; j < driftHistogramBuckets; j++ {
+			for i, c := range cdfs {
+				quantiles[i] = c.quantile(float64(j) / driftHistogramBuckets)
+			}
+			if edge := median(quantiles); len(edges) == 0 || edge > edges[len(edges)-1] {
+				edges = append(edges, edge)
+			}
+		}
+		for i, c := range cdfs {
+			dists[i] = make(map[string]float64, len(edges)+1)
+			for j, p := range c.shares(edges) {
+				dists[i][fmt.Sprint(j)] = p
+			}
+		}
+	} else {
+		for i, c := range cols {
+			if c.Count > 0 {
+				dists[i] = valueProportions(c)
+			}
+		}
+	}
+
+	keys := make(map[string]bool)
+	for _, dist := range dists {
+		for key := range dist {
+			keys[key] = true
+		}
+	}
+	consensus := make(map[string]float64, len(keys))
+	total := 0.0
+	for key := range keys {
+		shares := make([]float64, 0, len(dists))
+		for _, dist := range dists {
+			if dist != nil {
+				shares = append(shares, dist[key])
+			}
+		}
+		consensus[key] = median(shares)
+		total += consensus[key]
+	}
+
+	drifts := make([]float64, len(cols))
+	if total == 0 {
+		return drifts
+	}
+	for i, dist := range dists {
+		if dist == nil {
+			continue
+		}
+		distance := 0.0
+		for key := range keys {
+			distance += math.Abs(dist[key] - consensus[key]/total)
+		}
+		drifts[i] = distance / 2
+	}
+	return drifts
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,109 @@
+package compare
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func shipment(name string, statuses []profiler.ValueCount, amounts []int, missing int) *profiler.DatasetProfile {
+	count := 0
+	for _, n := range amounts {
+		count += n
+	}
+	buckets := make([]profiler.HistogramBucket, len(amounts))
+	for i, n := range amounts {
+		buckets[i] = profiler.HistogramBucket{LowerBound: float64(i * 10), UpperBound: float64(i*10 + 10), Count: n}
+	}
+	return &profiler.DatasetProfile{
+		Filename: name,
+		RowCount: count,
+		Columns: map[string]*profiler.ColumnProfile{
+			"id":     {Name: "id", Position: 0, DataType: "integer", IsNumeric: true, Count: count, UniqueCount: count},
+			"status": {Name: "status", Position: 1, DataType: "string", Count: count - missing, MissingCount: missing, TopValues: statuses},
+			"amount": {Name: "amount", Position: 2, DataType: "float", IsNumeric: true, Count: count, HistogramBuckets: buckets},
+		},
+	}
+}
+
+func TestBuildConsensus(t *testing.T) {
+	normal := []profiler.ValueCount{{Value: "paid", Count: 70}, {Value: "open", Count: 30}}
+	var profiles []*profiler.DatasetProfile
+	var names []string
+	for _, name := range []string{"a.csv", "b.csv", "c.csv", "d.csv"} {
+		profiles = append(profiles, shipment(name, normal, []int{25, 50, 25}, 0))
+		names = append(names, name)
+	}
+
+	// A partner renamed a column and sent refunds only
+	bad := shipment("e.csv", []profiler.ValueCount{{Value: "refunded", Count: 100}}, []int{25, 50, 25}, 0)
+	bad.Columns["total"] = bad.Columns["amount"]
+	delete(bad.Columns, "amount")
+	// Another sent amounts an order of magnitude off, with many statuses missing
+	shifted := shipment("f.csv", []profiler.ValueCount{{Value: "paid", Count: 35}, {Value: "open", Count: 15}}, []int{0, 0, 0, 0, 0, 0, 0, 0, 100}, 50)
+	shifted.Columns["id"].DataType = "string"
+	profiles = append(profiles, bad, shifted)
+	names = append(names, "e.csv", "f.csv")
+
+	consensus := BuildConsensus(profiles, names)
+	if consensus == nil {
+		t.Fatal("Expected a consensus for six files")
+	}
+
+	var schema []string
+	for _, col := range consensus.Columns {
+		schema = append(schema, col.Name+" "+col.DataType)
+	}
+	if got := strings.Join(schema, ", "); got != "id integer, status string, amount float" {
+		t.Errorf("Expected the consensus schema id integer, status string, amount float, got %s", got)
+	}
+
+	if len(consensus.Outliers) != 2 || consensus.Outliers[0].Name != "e.csv" || consensus.Outliers[1].Name != "f.csv" {
+		t.Fatalf("Expected e.csv and f.csv as outliers, got %+v", consensus.Outliers)
+	}
+
+	renamed := consensus.Outliers[0]
+	if strings.Join(renamed.MissingColumns, ",") != "amount" || strings.Join(renamed.ExtraColumns, ",") != "total" {
+		t.Errorf("Expected amount missing and total extra, got %v and %v", renamed.MissingColumns, renamed.ExtraColumns)
+	}
+	if len(renamed.Columns) != 1 || renamed.Columns[0].Name != "status" || renamed.Columns[0].Drift < 0.9 {
+		t.Errorf("Expected the statuses of e.csv to deviate, got %+v", renamed.Columns)
+	}
+
+	drifted := consensus.Outliers[1]
+	if len(drifted.RetypedColumns) != 1 || drifted.RetypedColumns[0] != (TypeChange{Name: "id", OldType: "integer", NewType: "string"}) {
+		t.Errorf("Expected id retyped to string, got %v", drifted.RetypedColumns)
+	}
+	if len(drifted.Columns) != 2 {
+		t.Fatalf("Expected status and amount of f.csv to deviate, got %+v", drifted.Columns)
+	}
+	if status := drifted.Columns[0]; status.Name != "status" || status.Drift != 0 || !status.MissingDeviates() {
+		t.Errorf("Expected status of f.csv to be missing too often, got %+v", status)
+	}
+	if amount := drifted.Columns[1]; amount.Name != "amount" || amount.Drift < 0.9 {
+		t.Errorf("Expected the amounts of f.csv to deviate, got %+v", amount)
+	}
+
+	lines := drifted.Descriptions()
+	if len(lines) != 3 || lines[0] != "'id' is string, not integer" || !strings.Contains(lines[1], "50.0% missing (median 0.0%)") {
+		t.Errorf("Unexpected descriptions %q", lines)
+	}
+}
+
+func TestConsensusVariedBatch(t *testing.T) {
+	// Files that all differ somewhat have no outlier
+	profiles := []*profiler.DatasetProfile{
+		shipment("a.csv", []profiler.ValueCount{{Value: "paid", Count: 60}, {Value: "open", Count: 40}}, []int{30, 40, 30}, 0),
+		shipment("b.csv", []profiler.ValueCount{{Value: "paid", Count: 75}, {Value: "open", Count: 25}}, []int{20, 50, 30}, 5),
+		shipment("c.csv", []profiler.ValueCount{{Value: "paid", Count: 80}, {Value: "open", Count: 20}}, []int{25, 45, 30}, 10),
+	}
+	consensus := BuildConsensus(profiles, []string{"a.csv", "b.csv", "c.csv"})
+	if consensus == nil || len(consensus.Outliers) != 0 {
+		t.Errorf("Expected no outliers, got %+v", consensus)
+	}
+
+	if BuildConsensus(profiles[:2], []string{"a.csv", "b.csv"}) != nil {
+		t.Error("Expected no consensus for two files")
+	}
+}
@@ -0,0 +1,280 @@
+package compare
+
+import (
+	"math"
+	"sort"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+const psiEpsilon = 1e-4 // floor for empty buckets, which would make PSI infinite
+
+// Drift metrics recorded in ColumnDiff.DriftMetrics when over their threshold.
+const (
+	MetricTVD          = "tvd"
+	MetricPSI          = "psi"
+	MetricKS           = "ks"
+	MetricJSDivergence = "js"
+	MetricChiSquare    = "chi_square"
+)
+
+// DriftThresholds decide when a column has drifted. A column drifts when any
+// of its metrics reaches its threshold; a zero threshold disables the metric.
+type DriftThresholds struct {
+	TVD            float64 // total variation distance, every column (0-1)
+	PSI            float64 // population stability index, numeric columns
+	KS             float64 // Kolmogorov–Smirnov statistic, numeric columns (0-1)
+	JSDivergence   float64 // Jensen–Shannon divergence, categorical columns (0-1)
+	ChiSquareAlpha float64 // chi-square p-value below which a categorical column drifted
+}
+
+// DefaultDriftThresholds flags a PSI of 0.2 or more, the usual "significant
+// shift" cut-off, and a TVD, KS or JS divergence of 0.1 or more. The
+// chi-square test is off: on large datasets it finds any change significant.
+func DefaultDriftThresholds() DriftThresholds {
+	return DriftThresholds{
+		TVD:          0.1,
+		PSI:          0.2,
+		KS:           0.1,
+		JSDivergence: 0.1,
+	}
+}
+
+// DriftScore is the percentage of compared columns that drifted. Columns
+// whose drift is a lower bound below its thresholds may have drifted or not,
+// so they are left out.
+func (r *Result) DriftScore() float64 {
+	measured, drifted := 0, 0
+	for _, col := range r.Columns {
+		switch {
+		case col.Drifted():
+			measured++
+			drifted++
+		case !col.DriftLowerBound:
+			measured++
+		}
+	}
+	if measured == 0 {
+		return 0
+	}
+	return float64(drifted) / float64(measured) * 100
+}
+
+// UndecidedDrift counts the columns left out of DriftScore: their drift is
+// a lower bound below its thresholds.
+func (r *Result) UndecidedDrift() int {
+	undecided := 0
+	for _, col := range r.Columns {
+		if col.DriftLowerBound && !col.Drifted() {
+			undecided++
+		}
+	}
+	return undecided
+}
+
+// measureNumericDrift fills the numeric drift metrics from the cumulative
+// distributions of the two columns. TVD and PSI compare the shares of the
+// values in bins of equal share of the base, so that an outlier stretching
+// the range of either column leaves the bins where the values are.
+func measureNumericDrift(diff *ColumnDiff, baseCol, targetCol *profiler.ColumnProfile) {
+	base, ok := columnCDF(baseCol)
+	if !ok {
+		return
+	}
+	target, ok := columnCDF(targetCol)
+	if !ok {
+		return
+	}
+
+	p, q := sharedDistributions(base, target)
+	diff.Drift = totalVariation(p, q)
+	diff.PSI = populationStability(p, q)
+	diff.KS = kolmogorovSmirnov(base, target)
+}
+
+// measureCategoricalDrift fills the categorical drift metrics from the value
+// frequencies. Those are complete in exact mode and for columns of few
+// distinct values; otherwise the values past the top ones are folded into
+// one "other" bucket, which hides any difference between them, and the
+// metrics are only lower bounds: two columns of disjoint dates read as
+// barely drifted.
+func measureCategoricalDrift(diff *ColumnDiff, baseCol, targetCol *profiler.ColumnProfile) {
+	if baseCol.Count == 0 || targetCol.Count == 0 {
+		return
+	}
+	diff.DriftLowerBound = !allValuesListed(baseCol) || !allValuesListed(targetCol)
+
+	p := valueProportions(baseCol)
+	q := valueProportions(targetCol)
+
+	keys := make([]string, 0, len(p)+len(q))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	for k := range q {
+		if _, ok := p[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	pv := make([]float64, len(keys))
+	qv := make([]float64, len(keys))
+	for i, k := range keys {
+		pv[i] = p[k]
+		qv[i] = q[k]
+	}
+
+	diff.Drift = totalVariation(pv, qv)
+	diff.JSDivergence = jensenShannon(pv, qv)
+	diff.ChiSquare, diff.ChiSquarePValue = chiSquare(pv, qv, baseCol.Count, targetCol.Count)
+}
+
+// allValuesListed reports whether the top values of col count every value.
+func allValuesListed(col *profiler.ColumnProfile) bool {
+	listed := 0
+	for _, val := range col.TopValues {
+		listed += val.Count
+	}
+	return listed == col.Count
+}
+
+// driftMetrics returns the metrics of diff that reached their threshold.
+func driftMetrics(diff ColumnDiff, thresholds DriftThresholds) []string {
+	metrics := make([]string, 0)
+	over := func(name string, value, threshold float64) {
+		if threshold > 0 && value >= threshold {
+			metrics = append(metrics, name)
+		}
+	}
+
+	over(MetricTVD, diff.Drift, thresholds.TVD)
+	if diff.IsNumeric {
+		over(MetricPSI, diff.PSI, thresholds.PSI)
+		over(MetricKS, diff.KS, thresholds.KS)
+	} else {
+		over(MetricJSDivergence, diff.JSDivergence, thresholds.JSDivergence)
+		if thresholds.ChiSquareAlpha > 0 && diff.ChiSquare > 0 && diff.ChiSquarePValue < thresholds.ChiSquareAlpha {
+			metrics = append(metrics, MetricChiSquare)
+		}
+	}
+
+	return metrics
+}
+
+// sharedDistributions returns the shares of base and target in bins of
+// equal share of base.
+func sharedDistributions(base, target cdf) ([]float64, []float64) {
+	edges := base.quantileEdges(driftHistogramBuckets)
+	return base.shares(edges), target.shares(edges)
+}
+
+func totalVariation(p, q []float64) float64 {
+	distance := 0.0
+	for i := range p {
+		distance += math.Abs(p[i] - q[i])
+	}
+	return distance / 2
+}
+
+// populationStability is sum((q - p) * ln(q / p)) over the buckets.
+func populationStability(p, q []float64) float64 {
+	psi := 0.0
+	for i := range p {
+		pi := math.Max(p[i], psiEpsilon)
+		qi := math.Max(q[i], psiEpsilon)
+		psi += (qi - pi) * math.Log(qi/pi)
+	}
+	return psi
+}
+
+// jensenShannon is the Jensen–Shannon divergence in bits, from 0 for
+// identical distributions to 1 for disjoint ones.
+func jensenShannon(p, q []float64) float64 {
+	divergence := 0.0
+	for i := range p {
+		m := (p[i] + q[i]) / 2
+		if p[i] > 0 {
+			divergence += p[i] * math.Log2(p[i]/m) / 2
+		}
+		if q[i] > 0 {
+			divergence += q[i] * math.Log2(q[i]/m) / 2
+		}
+	}
+	return divergence
+}
+
+// chiSquare tests whether the two samples of n1 and n2 values, with category
+// proportions p and q, come from the same distribution. It returns the
+// statistic and its p-value.
+func chiSquare(p, q []float64, n1, n2 int) (float64, float64) {
+	total := float64(n1 + n2)
+	statistic := 0.0
+	categories := 0
+
+	for i := range p {
+		o1 := p[i] * float64(n1)
+		o2 := q[i] * float64(n2)
+		column := o1 + o2
+		if column == 0 {
+			continue
+		}
+		categories++
+
+		e1 := column * float64(n1) / total
+		e2 := column * float64(n2) / total
+		statistic += (o1-e1)*(o1-e1)/e1 + (o2-e2)*(o2-e2)/e2
+	}
+
+	if categories < 2 {
+		return 0, 1
+	}
+	return statistic, upperGamma(float64(categories-1)/2, statistic/2)
+}
+
+// upperGamma is the regularized upper incomplete gamma function Q(a, x),
+// evaluated by its series below a+1 and by continued fraction above.
+func upperGamma(a, x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	lgamma, _ := math.Lgamma(a)
+	prefix := math.Exp(-x + a*math.Log(x) - lgamma)
+
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1; n < 500; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-15 {
+				break
+			}
+		}
+		return math.Max(0, 1-sum*prefix)
+	}
+
+	const tiny = 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < 500; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return prefix * h
+}
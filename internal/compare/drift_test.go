@@ -0,0 +1,239 @@
+package compare
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func TestDriftMetricsIdentical(t *testing.T) {
+	result := Compare(createProfile(), createProfile(), DefaultOptions())
+
+	for _, col := range result.Columns {
+		if col.PSI > 1e-9 || col.KS > 1e-9 || col.JSDivergence > 1e-9 || col.ChiSquare > 1e-9 {
+			t.Errorf("Expected no drift for %s, got psi %f ks %f js %f chi2 %f", col.Name, col.PSI, col.KS, col.JSDivergence, col.ChiSquare)
+		}
+	}
+	if result.DriftScore() != 0 {
+		t.Errorf("Expected drift score 0, got %f", result.DriftScore())
+	}
+}
+
+func TestDriftMetricsShift(t *testing.T) {
+	target := createProfile()
+	target.Columns["amount"].HistogramBuckets = []profiler.HistogramBucket{
+		{LowerBound: 0, UpperBound: 50, Count: 20},
+		{LowerBound: 50, UpperBound: 100, Count: 80},
+	}
+	target.Columns["region"].TopValues = []profiler.ValueCount{
+		{Value: "east", Count: 80},
+		{Value: "west", Count: 20},
+	}
+
+	result := Compare(createProfile(), target, DefaultOptions())
+	diffs := make(map[string]ColumnDiff)
+	for _, diff := range result.Columns {
+		diffs[diff.Name] = diff
+	}
+
+	amount := diffs["amount"]
+	if math.Abs(amount.KS-0.3) > 1e-9 {
+		t.Errorf("Expected KS of 0.3, got %f", amount.KS)
+	}
+	// PSI of 50/50 → 20/80 is -0.3 * ln(0.4) + 0.3 * ln(1.6) = 0.3 * ln(4)
+	if math.Abs(amount.PSI-0.3*math.Log(4)) > 1e-9 {
+		t.Errorf("Expected PSI of %f, got %f", 0.3*math.Log(4), amount.PSI)
+	}
+	if !amount.Drifted() {
+		t.Errorf("Expected amount to drift, got metrics %v", amount.DriftMetrics)
+	}
+
+	region := diffs["region"]
+	if region.JSDivergence <= 0.05 || region.JSDivergence >= 0.1 {
+		t.Errorf("Expected JS divergence between 0.05 and 0.1, got %f", region.JSDivergence)
+	}
+	// 2x2 table of 50/50 vs 80/20: expected counts 65/35, so 2*15²/65 + 2*15²/35
+	if expected := 450.0/65 + 450.0/35; math.Abs(region.ChiSquare-expected) > 1e-9 {
+		t.Errorf("Expected chi-square of %f, got %f", expected, region.ChiSquare)
+	}
+	if region.ChiSquarePValue > 1e-4 {
+		t.Errorf("Expected a tiny p-value, got %g", region.ChiSquarePValue)
+	}
+
+	// legacy lists none of its values, so whether it drifted is unknown
+	if result.DriftScore() != 100 || result.UndecidedDrift() != 1 {
+		t.Errorf("Expected both decided columns to drift and legacy left out, got %f with %d undecided", result.DriftScore(), result.UndecidedDrift())
+	}
+}
+
+// dateColumn profiles days distinct dates seen once each from start, with
+// the top five listed unless exact.
+func dateColumn(start time.Time, days int, exact bool) *profiler.ColumnProfile {
+	col := &profiler.ColumnProfile{Name: "day", DataType: "string", Count: days}
+	for i := 0; i < days; i++ {
+		if exact || i < 5 {
+			col.TopValues = append(col.TopValues, profiler.ValueCount{Value: start.AddDate(0, 0, i).Format("2006-01-02"), Count: 1})
+		}
+	}
+	return col
+}
+
+func TestCategoricalDriftDisjointValues(t *testing.T) {
+	march := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	april := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	// Every value counted: the months share none, so the drift is complete
+	var diff ColumnDiff
+	measureCategoricalDrift(&diff, dateColumn(march, 30, true), dateColumn(april, 30, true))
+	if diff.DriftLowerBound || math.Abs(diff.JSDivergence-1) > 1e-9 || math.Abs(diff.Drift-1) > 1e-9 {
+		t.Errorf("Expected complete drift measured exactly, got js %f tvd %f lower bound %v", diff.JSDivergence, diff.Drift, diff.DriftLowerBound)
+	}
+
+	// Top values alone: most of each month shares the "other" bucket
+	diff = ColumnDiff{}
+	measureCategoricalDrift(&diff, dateColumn(march, 30, false), dateColumn(april, 30, false))
+	if !diff.DriftLowerBound || diff.JSDivergence >= 0.5 {
+		t.Errorf("Expected a low JS divergence marked as a lower bound, got js %f lower bound %v", diff.JSDivergence, diff.DriftLowerBound)
+	}
+}
+
+func TestDriftScoreLowerBounds(t *testing.T) {
+	result := &Result{Columns: []ColumnDiff{
+		{Name: "exact", DriftMetrics: []string{MetricJSDivergence}},
+		{Name: "steady"},
+		{Name: "over", DriftLowerBound: true, DriftMetrics: []string{MetricTVD}},
+		{Name: "undecided", DriftLowerBound: true},
+	}}
+
+	// A lower bound over its threshold drifted for sure; one under it is
+	// left out rather than passing the gate
+	if math.Abs(result.DriftScore()-200.0/3) > 1e-9 || result.UndecidedDrift() != 1 {
+		t.Errorf("Expected 2 of 3 decided columns to drift, got %f with %d undecided", result.DriftScore(), result.UndecidedDrift())
+	}
+}
+
+func TestDriftThresholds(t *testing.T) {
+	target := createProfile()
+	target.Columns["region"].TopValues = []profiler.ValueCount{
+		{Value: "east", Count: 56},
+		{Value: "west", Count: 44},
+	}
+
+	result := Compare(createProfile(), target, DefaultOptions())
+	for _, col := range result.Columns {
+		if col.Name == "region" && col.Drifted() {
+			t.Errorf("Expected a small shift not to drift by default, got %v", col.DriftMetrics)
+		}
+	}
+
+	thresholds := DefaultDriftThresholds()
+	thresholds.TVD = 0.05
+	thresholds.ChiSquareAlpha = 0.5
+	result = Compare(createProfile(), target, Options{Drift: thresholds})
+	for _, col := range result.Columns {
+		if col.Name == "region" && (len(col.DriftMetrics) != 2 || col.DriftMetrics[0] != MetricTVD || col.DriftMetrics[1] != MetricChiSquare) {
+			t.Errorf("Expected tvd and chi_square to flag region, got %v", col.DriftMetrics)
+		}
+	}
+}
+
+func TestDriftThresholdsAllOff(t *testing.T) {
+	target := createProfile()
+	target.Columns["amount"].HistogramBuckets = []profiler.HistogramBucket{
+		{LowerBound: 0, UpperBound: 50, Count: 5},
+		{LowerBound: 50, UpperBound: 100, Count: 95},
+	}
+	target.Columns["region"].TopValues = []profiler.ValueCount{{Value: "north", Count: 100}}
+
+	// Zero thresholds turn every metric off rather than bringing back the
+	// defaults
+	result := Compare(createProfile(), target, Options{})
+	for _, col := range result.Columns {
+		if col.Drifted() {
+			t.Errorf("Expected %s not to drift with every metric off, got %v", col.Name, col.DriftMetrics)
+		}
+	}
+	if result.DriftScore() != 0 {
+		t.Errorf("Expected drift score 0, got %f", result.DriftScore())
+	}
+}
+
+// normalColumn profiles n values drawn from N(100, 10), plus extra values,
+// with a histogram of 20 equal-width buckets like the profiler's.
+func normalColumn(seed int64, n int, extra ...float64) *profiler.ColumnProfile {
+	r := rand.New(rand.NewSource(seed))
+	values := append([]float64(nil), extra...)
+	for i := 0; i < n; i++ {
+		values = append(values, 100+10*r.NormFloat64())
+	}
+	sort.Float64s(values)
+	quantile := func(q float64) float64 { return values[int(q*float64(len(values)-1))] }
+
+	col := &profiler.ColumnProfile{
+		Name:      "amount",
+		Count:     len(values),
+		IsNumeric: true,
+		Min:       values[0],
+		Max:       values[len(values)-1],
+		Median:    quantile(0.5),
+	}
+	for _, rank := range profiler.PercentileRanks {
+		col.Percentiles = append(col.Percentiles, profiler.Percentile{Rank: rank, Value: quantile(float64(rank) / 100)})
+	}
+	low, width := values[0], (values[len(values)-1]-values[0])/20
+	for i := 0; i < 20; i++ {
+		col.HistogramBuckets = append(col.HistogramBuckets, profiler.HistogramBucket{LowerBound: low + float64(i)*width, UpperBound: low + float64(i+1)*width})
+	}
+	for _, v := range values {
+		col.HistogramBuckets[min(int((v-low)/width), 19)].Count++
+	}
+	return col
+}
+
+func TestNumericDriftOutlier(t *testing.T) {
+	// One outlier stretches a histogram until nearly every value shares its
+	// first bucket; either way round the distributions match
+	clean, outlier := normalColumn(1, 5000), normalColumn(2, 5000, 10000)
+	for _, pair := range [][2]*profiler.ColumnProfile{{clean, outlier}, {outlier, clean}} {
+		var diff ColumnDiff
+		measureNumericDrift(&diff, pair[0], pair[1])
+		if metrics := driftMetrics(diff, DefaultDriftThresholds()); len(metrics) > 0 || diff.KS >= 0.05 || diff.Drift >= 0.075 {
+			t.Errorf("Expected no drift from one outlier, got %v with ks %.3f psi %.3f tvd %.3f", metrics, diff.KS, diff.PSI, diff.Drift)
+		}
+	}
+
+	// A shift of half a standard deviation still shows
+	shifted := normalColumn(3, 5000, 10000)
+	for i := range shifted.HistogramBuckets {
+		shifted.HistogramBuckets[i].LowerBound += 5
+		shifted.HistogramBuckets[i].UpperBound += 5
+	}
+	for i := range shifted.Percentiles {
+		shifted.Percentiles[i].Value += 5
+	}
+	shifted.Min, shifted.Max, shifted.Median = shifted.Min.(float64)+5, shifted.Max.(float64)+5, shifted.Median+5
+	var diff ColumnDiff
+	measureNumericDrift(&diff, clean, shifted)
+	if diff.KS < 0.15 || diff.PSI < 0.2 || diff.Drift < 0.15 {
+		t.Errorf("Expected the shift to drift, got ks %.3f psi %.3f tvd %.3f", diff.KS, diff.PSI, diff.Drift)
+	}
+}
+
+func TestUpperGamma(t *testing.T) {
+	// Chi-square survival function: P(X > 3.841) = 0.05 with 1 degree of
+	// freedom, P(X > 18.307) = 0.05 with 10
+	cases := []struct{ df, x, expected float64 }{
+		{1, 3.841459, 0.05},
+		{10, 18.307038, 0.05},
+		{4, 1.063623, 0.9},
+	}
+	for _, c := range cases {
+		if got := upperGamma(c.df/2, c.x/2); math.Abs(got-c.expected) > 1e-5 {
+			t.Errorf("Q(%v, %v): expected %f, got %f", c.df/2, c.x/2, c.expected, got)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package compare
+
+import (
+	"fmt"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+const (
+	overlapSameShare      = 0.9 // share of values found in the other version for it to hold the same ones
+	overlapDifferentShare = 0.1 // share below which neither version holds the values of the other
+)
+
+// ValueOverlap is the estimated overlap of the distinct values of a string
+// column in two versions of a dataset, from MinHash signatures of both. It
+// tells the same customers in new rows apart from a different population
+// that happens to have a similar distribution.
+type ValueOverlap struct {
+	Base           string // column in the base
+	Target         string // column in the target, another name when renamed
+	BaseDistinct   float64
+	TargetDistinct float64
+	Shared         float64 // distinct values in both
+	Jaccard        float64 // shared values over the values of either
+	Exact          bool    // every value was hashed, so the figures are counts
+}
+
+// Column names the column, as old → new when it was renamed.
+func (o ValueOverlap) Column() string {
+	if o.Base == o.Target {
+		return o.Base
+	}
+	return o.Base + " → " + o.Target
+}
+
+// Retained is the share of the base values still found in the target.
+func (o ValueOverlap) Retained() float64 {
+	return share(o.Shared, o.BaseDistinct)
+}
+
+// Recurring is the share of the target values already found in the base.
+func (o ValueOverlap) Recurring() float64 {
+	return share(o.Shared, o.TargetDistinct)
+}
+
+// Different reports whether the two versions hold mostly different values.
+func (o ValueOverlap) Different() bool {
+	return o.Retained() < overlapDifferentShare && o.Recurring() < overlapDifferentShare
+}
+
+func (o ValueOverlap) Description() string {
+	retained, recurring := o.Retained(), o.Recurring()
+	switch {
+	case retained >= overlapSameShare && recurring >= overlapSameShare:
+		return "same values"
+	case recurring >= overlapSameShare:
+		return "values seen before, some dropped"
+	case retained >= overlapSameShare:
+		return "values kept, new ones added"
+	case o.Different():
+		return "different values"
+	default:
+		return "partly shared values"
+	}
+}
+
+func (o ValueOverlap) String() string {
+	approx := "~"
+	if o.Exact {
+		approx = ""
+	}
+	return fmt.Sprintf("%s (%.0f%% of old values kept, %.0f%% of new values seen before, Jaccard %s%.2f)",
+		o.Description(), o.Retained()*100, o.Recurring()*100, approx, o.Jaccard)
+}
+
+// valueOverlap estimates the overlap of two string columns from their
+// MinHash signatures, when both have one.
+func valueOverlap(baseCol, targetCol *profiler.ColumnProfile) (ValueOverlap, bool) {
+	if baseCol.DataType != "string" || targetCol.DataType != "string" || baseCol.MinHash == nil || targetCol.MinHash == nil {
+		return ValueOverlap{}, false
+	}
+
+	estimate, err := profiler.EstimateOverlap(baseCol.MinHash, targetCol.MinHash)
+	if err != nil {
+		return ValueOverlap{}, false
+	}
+
+	return ValueOverlap{
+		Base:           baseCol.Name,
+		Target:         targetCol.Name,
+		BaseDistinct:   baseCol.MinHash.Distinct(),
+		TargetDistinct: targetCol.MinHash.Distinct(),
+		Shared:         estimate.Shared,
+		Jaccard:        estimate.Jaccard,
+		Exact:          estimate.Exact,
+	}, true
+}
+
+func share(part, whole float64) float64 {
+	if whole <= 0 {
+		return 0
+	}
+	return min(part/whole, 1)
+}
@@ -0,0 +1,68 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func withMinHash(profile *profiler.DatasetProfile, column string, hashes ...uint64) {
+	profile.Columns[column].MinHash = &profiler.MinHash{Salt: "salt", Size: 64, Hashes: hashes}
+}
+
+func TestCompareValueOverlap(t *testing.T) {
+	testCases := []struct {
+		name        string
+		base        []uint64
+		target      []uint64
+		description string
+		different   bool
+	}{
+		{"same", []uint64{1, 2, 3, 4}, []uint64{1, 2, 3, 4}, "same values", false},
+		{"grown", []uint64{1, 2, 3, 4}, []uint64{1, 2, 3, 4, 5, 6, 7, 8}, "values kept, new ones added", false},
+		{"shrunk", []uint64{1, 2, 3, 4, 5, 6, 7, 8}, []uint64{1, 2, 3, 4}, "values seen before, some dropped", false},
+		{"partly", []uint64{1, 2, 3, 4}, []uint64{3, 4, 5, 6}, "partly shared values", false},
+		{"different", []uint64{1, 2, 3, 4}, []uint64{5, 6, 7, 8}, "different values", true},
+	}
+
+	for _, tc := range testCases {
+		base, target := createProfile(), createProfile()
+		withMinHash(base, "region", tc.base...)
+		withMinHash(target, "region", tc.target...)
+
+		result := Compare(base, target, DefaultOptions())
+		if len(result.Overlaps) != 1 {
+			t.Fatalf("%s: expected the overlap of region, got %v", tc.name, result.Overlaps)
+		}
+		overlap := result.Overlaps[0]
+		if !overlap.Exact || overlap.Description() != tc.description || overlap.Different() != tc.different {
+			t.Errorf("%s: expected %q, got %q for %+v", tc.name, tc.description, overlap.Description(), overlap)
+		}
+
+		changed := false
+		for _, col := range result.ChangedColumns() {
+			if col.Name == "region" {
+				changed = true
+			}
+		}
+		if changed != tc.different {
+			t.Errorf("%s: expected region changed to be %v", tc.name, tc.different)
+		}
+	}
+}
+
+func TestCompareValueOverlapOfRename(t *testing.T) {
+	base, target := createProfile(), createProfile()
+	withMinHash(base, "region", 1, 2)
+	withMinHash(target, "region", 1, 2)
+	renameColumn(target, "region", "sales_region")
+
+	result := Compare(base, target, DefaultOptions())
+	if len(result.Overlaps) != 1 || result.Overlaps[0].Column() != "region → sales_region" || result.Overlaps[0].Jaccard != 1 {
+		t.Errorf("Expected the overlap of the renamed column, got %+v", result.Overlaps)
+	}
+
+	if result = Compare(base, target, Options{SchemaOnly: true}); len(result.Overlaps) != 0 {
+		t.Errorf("Expected no overlaps comparing only schemas, got %+v", result.Overlaps)
+	}
+}
@@ -0,0 +1,143 @@
+package compare
+
+import (
+	"math"
+	"sort"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+const (
+	renameDriftThreshold   = 0.05
+	renameMissingThreshold = 1.0
+	renameUniqueTolerance  = 0.1
+	renameMeanShift        = 0.1
+)
+
+// Rename is a column that disappeared while a new column with a near-identical
+// distribution appeared, most likely the same data under a new name.
+type Rename struct {
+	OldName          string
+	NewName          string
+	DataType         string
+	Drift            float64
+	IdenticalContent bool
+}
+
+type renameCandidate struct {
+	Rename
+	removed int
+	added   int
+}
+
+// detectRenames pairs removed and added columns of the same type whose values
+// match. Identical column digests are certain matches; otherwise missing rate,
+// unique count, mean and distribution must all agree. Each column is used at
+// most once, best matches first.
+func detectRenames(base, target *profiler.DatasetProfile, removed, added []ColumnSchema) []Rename {
+	candidates := make([]renameCandidate, 0)
+
+	for i, old := range removed {
+		for j, next := range added {
+			if old.DataType != next.DataType {
+				continue
+			}
+
+			drift, identical, ok := renameSimilarity(base.Columns[old.Name], target.Columns[next.Name], base.RowCount, target.RowCount)
+			if !ok {
+				continue
+			}
+
+			candidates = append(candidates, renameCandidate{
+				Rename: Rename{
+					OldName:          old.Name,
+					NewName:          next.Name,
+					DataType:         old.DataType,
+					Drift:            drift,
+					IdenticalContent: identical,
+				},
+				removed: i,
+				added:   j,
+			})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].IdenticalContent != candidates[j].IdenticalContent {
+			return candidates[i].IdenticalContent
+		}
+		return candidates[i].Drift < candidates[j].Drift
+	})
+
+	usedRemoved := make(map[int]bool)
+	usedAdded := make(map[int]bool)
+	renames := make([]Rename, 0)
+
+	for _, c := range candidates {
+		if usedRemoved[c.removed] || usedAdded[c.added] {
+			continue
+		}
+		usedRemoved[c.removed] = true
+		usedAdded[c.added] = true
+		renames = append(renames, c.Rename)
+	}
+
+	return renames
+}
+
+func renameSimilarity(oldCol, newCol *profiler.ColumnProfile, oldRows, newRows int) (float64, bool, bool) {
+	if oldCol == nil || newCol == nil || oldCol.Count == 0 || newCol.Count == 0 {
+		return 0, false, false
+	}
+
+	if oldCol.Digest != "" && oldCol.Digest == newCol.Digest && oldCol.Count == newCol.Count {
+		return 0, true, true
+	}
+
+	diff := compareColumn(oldCol, newCol, oldRows, newRows, DefaultDriftThresholds())
+
+	if math.Abs(diff.NewMissingPercent-diff.OldMissingPercent) > renameMissingThreshold {
+		return 0, false, false
+	}
+
+	if relativeChange(float64(oldCol.UniqueCount), float64(newCol.UniqueCount)) > renameUniqueTolerance {
+		return 0, false, false
+	}
+
+	if diff.IsNumeric {
+		if math.Abs(diff.MeanShift) > renameMeanShift || relativeChange(oldCol.StdDev, newCol.StdDev) > renameUniqueTolerance {
+			return 0, false, false
+		}
+	} else if !topValuesOverlap(oldCol.TopValues, newCol.TopValues) {
+		return 0, false, false
+	}
+
+	if diff.Drift > renameDriftThreshold {
+		return 0, false, false
+	}
+
+	return diff.Drift, false, true
+}
+
+// topValuesOverlap requires most of the most frequent values to be shared, so
+// that two unrelated high-cardinality columns are not matched on their "other"
+// bucket alone.
+func topValuesOverlap(oldValues, newValues []profiler.ValueCount) bool {
+	if len(oldValues) == 0 || len(newValues) == 0 {
+		return false
+	}
+
+	present := make(map[string]bool)
+	for _, val := range newValues {
+		present[val.Value] = true
+	}
+
+	shared := 0
+	for _, val := range oldValues {
+		if present[val.Value] {
+			shared++
+		}
+	}
+
+	return shared*2 >= len(oldValues)
+}
@@ -0,0 +1,95 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func renameColumn(profile *profiler.DatasetProfile, oldName, newName string) {
+	col := profile.Columns[oldName]
+	delete(profile.Columns, oldName)
+	copied := *col
+	copied.Name = newName
+	profile.Columns[newName] = &copied
+}
+
+func TestCompareDetectsRename(t *testing.T) {
+	target := createProfile()
+	renameColumn(target, "region", "sales_region")
+
+	result := Compare(createProfile(), target, DefaultOptions())
+
+	if len(result.Renames) != 1 {
+		t.Fatalf("Expected 1 rename, got %v", result.Renames)
+	}
+
+	rename := result.Renames[0]
+	if rename.OldName != "region" || rename.NewName != "sales_region" {
+		t.Errorf("Expected region → sales_region, got %s → %s", rename.OldName, rename.NewName)
+	}
+
+	if len(result.RemovedColumns) != 1 || len(result.AddedColumns) != 1 {
+		t.Error("Expected renamed columns to still be reported as removed and added")
+	}
+
+	if _, ok := result.RenameOf("sales_region"); !ok {
+		t.Error("Expected RenameOf to find the rename by its new name")
+	}
+}
+
+func TestCompareDetectsRenameByDigest(t *testing.T) {
+	base := createProfile()
+	base.Columns["amount"].Digest = "abc"
+
+	target := createProfile()
+	target.Columns["amount"].Digest = "abc"
+	renameColumn(target, "amount", "total")
+	target.Columns["total"].Mean = 90
+
+	result := Compare(base, target, DefaultOptions())
+
+	if len(result.Renames) != 1 || !result.Renames[0].IdenticalContent {
+		t.Fatalf("Expected an identical-content rename, got %v", result.Renames)
+	}
+}
+
+func TestCompareRenameRequiresSimilarDistribution(t *testing.T) {
+	target := createProfile()
+	renameColumn(target, "amount", "total")
+	target.Columns["total"].Mean = 80
+	target.Columns["total"].HistogramBuckets = []profiler.HistogramBucket{
+		{LowerBound: 50, UpperBound: 100, Count: 60},
+		{LowerBound: 100, UpperBound: 150, Count: 60},
+	}
+
+	renameColumn(target, "region", "country")
+	target.Columns["country"].TopValues = []profiler.ValueCount{
+		{Value: "us", Count: 50},
+		{Value: "ca", Count: 50},
+	}
+
+	result := Compare(createProfile(), target, DefaultOptions())
+
+	if len(result.Renames) != 0 {
+		t.Errorf("Expected no renames for different distributions, got %v", result.Renames)
+	}
+}
+
+func TestCompareRenameUsesEachColumnOnce(t *testing.T) {
+	target := createProfile()
+	renameColumn(target, "region", "area")
+	copied := *target.Columns["area"]
+	copied.Name = "zone"
+	copied.TopValues = []profiler.ValueCount{
+		{Value: "east", Count: 55},
+		{Value: "west", Count: 45},
+	}
+	target.Columns["zone"] = &copied
+
+	result := Compare(createProfile(), target, DefaultOptions())
+
+	if len(result.Renames) != 1 || result.Renames[0].NewName != "area" {
+		t.Errorf("Expected only the closest match area, got %v", result.Renames)
+	}
+}
@@ -0,0 +1,95 @@
+package compare
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// sequenceGapSteps is how many average steps the values of a sequential
+// column may skip between two windows.
+const sequenceGapSteps = 10
+
+// Shift is a sudden change between two consecutive windows of a followed
+// file, raised by profile --follow.
+type Shift struct {
+	Column  string
+	Kind    string // added, removed, retyped, missing_rate, mean or distribution
+	Message string
+}
+
+// Key identifies the shift across windows, so that a shift that keeps
+// holding is raised once.
+func (s Shift) Key() string {
+	return s.Column + "\x00" + s.Kind
+}
+
+// Shifts compares the current window of a followed file with the window
+// before it. Columns that appear, disappear or change type shift, and so do
+// columns whose missing rate, mean or distribution changed; a change in
+// spread alone is left out, as it is noisy between small windows. The values
+// of sequential columns, such as ids and timestamps, move on with every
+// window, so only their missing rate is checked.
+func Shifts(previous, current *profiler.DatasetProfile) []Shift {
+	result := Compare(previous, current, DefaultOptions())
+	shifts := make([]Shift, 0)
+	add := func(column, kind, format string, args ...any) {
+		shifts = append(shifts, Shift{Column: column, Kind: kind, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, col := range result.AddedColumns {
+		add(col.Name, "added", "new column '%s' (%s)", col.Name, col.DataType)
+	}
+	for _, col := range result.RemovedColumns {
+		add(col.Name, "removed", "column '%s' disappeared", col.Name)
+	}
+	for _, change := range result.RetypedColumns {
+		add(change.Name, "retyped", "'%s' changed type: %s → %s", change.Name, change.OldType, change.NewType)
+	}
+
+	for _, col := range result.Columns {
+		sequential := isSequential(previous.Columns[col.Name], current.Columns[col.Name])
+		for _, change := range col.Changes {
+			if sequential && change != "missing_rate" {
+				continue
+			}
+			switch change {
+			case "missing_rate":
+				add(col.Name, change, "'%s' missing rate %.1f%% → %.1f%%", col.Name, col.OldMissingPercent, col.NewMissingPercent)
+			case "mean":
+				if col.MeanShift != 0 {
+					add(col.Name, change, "'%s' mean %.4g → %.4g (%+.1f standard deviations)", col.Name, col.OldMean, col.NewMean, col.MeanShift)
+				} else {
+					add(col.Name, change, "'%s' mean %.4g → %.4g", col.Name, col.OldMean, col.NewMean)
+				}
+			case "distribution":
+				add(col.Name, change, "'%s' distribution drifted (%s)", col.Name, strings.Join(col.DriftMetrics, ", "))
+			}
+		}
+	}
+
+	return shifts
+}
+
+// isSequential reports whether a column holds distinct values in both windows
+// and, when numeric, the values of the current window carry on from those of
+// the previous one: they start above its largest value, within
+// sequenceGapSteps of its average step between values.
+func isSequential(previous, current *profiler.ColumnProfile) bool {
+	if !previous.IsUnique || !current.IsUnique || previous.Count == 0 || current.Count == 0 {
+		return false
+	}
+	if !previous.IsNumeric || !current.IsNumeric {
+		return true
+	}
+
+	low, okLow := previous.Min.(float64)
+	high, okHigh := previous.Max.(float64)
+	first, okFirst := current.Min.(float64)
+	if !okLow || !okHigh || !okFirst || previous.Count < 2 || first < high {
+		return false
+	}
+	step := (high - low) / float64(previous.Count-1)
+	return first-high <= step*sequenceGapSteps
+}
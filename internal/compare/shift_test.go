@@ -0,0 +1,61 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func TestShifts(t *testing.T) {
+	if shifts := Shifts(createProfile(), createProfile()); len(shifts) != 0 {
+		t.Errorf("Expected no shifts between identical windows, got %+v", shifts)
+	}
+
+	current := createProfile()
+	current.Columns["amount"].Mean = 80
+	current.Columns["amount"].HistogramBuckets = []profiler.HistogramBucket{
+		{LowerBound: 50, UpperBound: 100, Count: 100},
+	}
+	current.Columns["region"].MissingCount = 20
+	current.Columns["legacy"].DataType = "integer"
+	current.Columns["code"] = &profiler.ColumnProfile{Name: "code", DataType: "string"}
+
+	kinds := make(map[string]string)
+	for _, shift := range Shifts(createProfile(), current) {
+		kinds[shift.Key()] = shift.Message
+	}
+	for _, want := range []struct{ column, kind, message string }{
+		{"amount", "mean", "'amount' mean 50 → 80 (+3.0 standard deviations)"},
+		{"amount", "distribution", "'amount' distribution drifted (tvd, psi, ks)"},
+		{"region", "missing_rate", "'region' missing rate 0.0% → 20.0%"},
+		{"legacy", "retyped", "'legacy' changed type: string → integer"},
+		{"code", "added", "new column 'code' (string)"},
+	} {
+		if got := kinds[Shift{Column: want.column, Kind: want.kind}.Key()]; got != want.message {
+			t.Errorf("Expected %s %s shift %q, got %q", want.column, want.kind, want.message, got)
+		}
+	}
+}
+
+func TestShiftsSequential(t *testing.T) {
+	window := func(low, high float64) *profiler.DatasetProfile {
+		return &profiler.DatasetProfile{RowCount: 10, Columns: map[string]*profiler.ColumnProfile{
+			"id": {
+				Name: "id", DataType: "integer", IsNumeric: true, IsUnique: true, Count: 10, UniqueCount: 10,
+				Min: low, Max: high, Mean: (low + high) / 2, StdDev: 3,
+				HistogramBuckets: []profiler.HistogramBucket{{LowerBound: low, UpperBound: high, Count: 10}},
+			},
+		}}
+	}
+
+	// Ids that move on with every window do not shift
+	if shifts := Shifts(window(1, 10), window(11, 20)); len(shifts) != 0 {
+		t.Errorf("Expected no shifts of a sequence, got %+v", shifts)
+	}
+	if shifts := Shifts(window(11, 20), window(1, 10)); len(shifts) == 0 {
+		t.Error("Expected ids going back to shift")
+	}
+	if shifts := Shifts(window(1, 10), window(500, 509)); len(shifts) == 0 {
+		t.Error("Expected distinct values jumping far ahead to shift")
+	}
+}
@@ -0,0 +1,224 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/kamalm96/datasleuth/internal/validate"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is the config file looked for in the current directory and
+// its parents when no other is given.
+const DefaultFile = ".datasleuth.yaml"
+
+// Config is the project configuration of DataSleuth:
+//
+//	slas:
+//	  - column: email
+//	    completeness: 98
+//	  - dataset: orders_*.csv
+//	    column: customer_id
+//	    completeness: 100
+//	scoring:
+//	  preset: strict
+//	  missing: {per_percent: 4, max: 35}
+//	  columns: {notes: 0.5}
+//	  ignore: [internal_*]
+//	outliers:
+//	  method: iqr
+//	  iqr_multiplier: 3
+//	histogram:
+//	  binning: auto
+//	  buckets: 20
+//	date_formats: ["02.01.2006 15:04", epoch-millis]
+//	defaults:
+//	  delimiter: ";"
+//	  null_values: [NA, "N/A", "-"]
+//	  sample: 100000
+//	  output: json
+//	datasets:
+//	  - dataset: orders_*.csv
+//	    delimiter: "|"
+//	    null_values: [NULL]
+//	    rules: rules/orders.yaml
+//
+// The scoring starts from its preset, or the default weights, and
+// overrides the weights it gives. Outlier thresholds left out keep their
+// defaults: a z-score of 3, 1.5 IQRs and a modified z-score of 3.5.
+// Histograms default to 10 equal-width buckets. Date formats are time
+// layouts, tried before the built-in ones in every column. Defaults stand in
+// for flags not given, and the datasets whose name matches an entry of
+// datasets take its settings over the defaults.
+type Config struct {
+	SLAs        []validate.SLA             `yaml:"slas"`
+	Scoring     *profiler.Scoring          `yaml:"scoring"`
+	Outliers    *profiler.OutlierDetection `yaml:"outliers"`
+	Histogram   *profiler.Histograms       `yaml:"histogram"`
+	DateFormats []string                   `yaml:"date_formats"`
+	Defaults    *Settings                  `yaml:"defaults"`
+	Datasets    []Dataset                  `yaml:"datasets"`
+
+	Path string `yaml:"-"` // file the config was read from, empty for none
+}
+
+// Load reads the config file at path. Unknown keys are rejected so that a
+// misspelled setting is not silently ignored.
+func Load(path string) (*Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var cfg Config
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	if cfg.Scoring != nil {
+		var raw struct {
+			Scoring yaml.Node `yaml:"scoring"`
+		}
+		if err := yaml.Unmarshal(content, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+		if cfg.Scoring, err = overlayScoring(cfg.Scoring.Preset, &raw.Scoring); err != nil {
+			return nil, fmt.Errorf("invalid config %s: %w", path, err)
+		}
+	}
+
+	if cfg.Outliers != nil {
+		if err := cfg.Outliers.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid config %s: %w", path, err)
+		}
+	}
+
+	if cfg.Histogram != nil {
+		if err := cfg.Histogram.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid config %s: %w", path, err)
+		}
+	}
+
+	if err := profiler.ValidateDateFormats(cfg.DateFormats); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	for _, sla := range cfg.SLAs {
+		if err := sla.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid config %s: %w", path, err)
+		}
+	}
+
+	if cfg.Defaults != nil {
+		if err := cfg.Defaults.validate(); err != nil {
+			return nil, fmt.Errorf("invalid config %s: defaults: %w", path, err)
+		}
+	}
+	for i := range cfg.Datasets {
+		d := &cfg.Datasets[i]
+		if _, err := filepath.Match(d.Dataset, ""); err != nil || d.Dataset == "" {
+			return nil, fmt.Errorf("invalid config %s: dataset %d: %q is not a file name pattern", path, i+1, d.Dataset)
+		}
+		if err := d.validate(); err != nil {
+			return nil, fmt.Errorf("invalid config %s: dataset %s: %w", path, d.Dataset, err)
+		}
+		if d.Rules != "" && !filepath.IsAbs(d.Rules) {
+			d.Rules = filepath.Join(filepath.Dir(path), d.Rules)
+		}
+	}
+
+	cfg.Path = path
+	return &cfg, nil
+}
+
+// LoadDefault reads the nearest DefaultFile, looking in the current
+// directory and then in each parent, returning an empty config when there
+// is none.
+func LoadDefault() (*Config, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find config: %w", err)
+	}
+	path, ok := Find(dir)
+	if !ok {
+		return &Config{}, nil
+	}
+	return Load(path)
+}
+
+// Find looks for DefaultFile in dir and then in each of its parents,
+// returning the path of the nearest.
+func Find(dir string) (string, bool) {
+	for {
+		path := filepath.Join(dir, DefaultFile)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// overlayScoring lays the weights of a scoring section, already checked for
+// unknown keys, over its preset.
+func overlayScoring(preset string, node *yaml.Node) (*profiler.Scoring, error) {
+	scoring, err := profiler.ScoringPreset(preset)
+	if err != nil {
+		return nil, err
+	}
+	if err := node.Decode(scoring); err != nil {
+		return nil, err
+	}
+	if err := scoring.Validate(); err != nil {
+		return nil, err
+	}
+	return scoring, nil
+}
+
+// LoadScoring reads the scoring of the quality score from a preset name, or
+// from a YAML file laid out like the scoring section of a config file.
+func LoadScoring(value string) (*profiler.Scoring, error) {
+	if slices.Contains(profiler.ScoringPresets(), value) {
+		return profiler.ScoringPreset(value)
+	}
+
+	content, err := os.ReadFile(value)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%s is neither a scoring preset (%s) nor a file", value, strings.Join(profiler.ScoringPresets(), ", "))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scoring: %w", err)
+	}
+
+	var checked profiler.Scoring
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&checked); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse scoring %s: %w", value, err)
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return nil, fmt.Errorf("failed to parse scoring %s: %w", value, err)
+	}
+	if len(node.Content) == 0 {
+		return profiler.ScoringPreset(checked.Preset)
+	}
+	scoring, err := overlayScoring(checked.Preset, node.Content[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid scoring %s: %w", value, err)
+	}
+	return scoring, nil
+}
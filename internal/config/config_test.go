@@ -0,0 +1,166 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "datasleuth.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `slas:
+  - column: email
+    completeness: 98
+  - dataset: orders_*.csv
+    column: customer_id
+    completeness: 100
+`))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.SLAs) != 2 || cfg.SLAs[0].Column != "email" || cfg.SLAs[0].Completeness != 98 || cfg.SLAs[1].Dataset != "orders_*.csv" {
+		t.Errorf("Unexpected SLAs: %+v", cfg.SLAs)
+	}
+
+	if cfg, err := Load(writeConfig(t, "")); err != nil || len(cfg.SLAs) != 0 {
+		t.Errorf("Expected an empty config, got %+v (%v)", cfg, err)
+	}
+
+	for _, content := range []string{
+		"slas:\n  - column: email\n    completness: 98\n",
+		"slas:\n  - column: email\n    completeness: 120\n",
+		"slas: [",
+	} {
+		if _, err := Load(writeConfig(t, content)); err == nil {
+			t.Errorf("Expected an error loading %q", content)
+		}
+	}
+}
+
+func TestLoadOutliers(t *testing.T) {
+	cfg, err := Load(writeConfig(t, "outliers:\n  method: mad\n  mad_threshold: 5\n"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if o := cfg.Outliers; o == nil || o.Method != profiler.OutlierMethodMAD || o.MADThreshold != 5 || o.IQRMultiplier != 0 {
+		t.Errorf("Unexpected outlier detection: %+v", o)
+	}
+
+	for _, content := range []string{
+		"outliers:\n  method: grubbs\n",
+		"outliers:\n  iqr_multiplier: -1\n",
+		"outliers:\n  treshold: 3\n",
+	} {
+		if _, err := Load(writeConfig(t, content)); err == nil {
+			t.Errorf("Expected an error loading %q", content)
+		}
+	}
+}
+
+func TestLoadHistogram(t *testing.T) {
+	cfg, err := Load(writeConfig(t, "histogram:\n  binning: log\n  buckets: 20\n"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if h := cfg.Histogram; h == nil || h.Binning != profiler.HistogramLog || h.Buckets != 20 {
+		t.Errorf("Unexpected histogram settings: %+v", h)
+	}
+
+	for _, content := range []string{
+		"histogram:\n  binning: sturges\n",
+		"histogram:\n  buckets: -5\n",
+	} {
+		if _, err := Load(writeConfig(t, content)); err == nil {
+			t.Errorf("Expected an error loading %q", content)
+		}
+	}
+}
+
+func TestLoadDateFormats(t *testing.T) {
+	cfg, err := Load(writeConfig(t, "date_formats: [\"02.01.2006 15:04\", epoch-millis]\n"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.DateFormats) != 2 || cfg.DateFormats[1] != profiler.DateFormatEpochMillis {
+		t.Errorf("Unexpected date formats: %v", cfg.DateFormats)
+	}
+
+	for _, content := range []string{
+		"date_formats: [\"15:04\"]\n",
+		"date_formats: [\"%d/%m/%Y\"]\n",
+		"date_formats: [\"\"]\n",
+	} {
+		if _, err := Load(writeConfig(t, content)); err == nil {
+			t.Errorf("Expected an error loading %q", content)
+		}
+	}
+}
+
+func TestLoadScoring(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `scoring:
+  preset: strict
+  missing: {per_percent: 4}
+  columns: {notes: 0.5}
+  ignore: [internal_*]
+`))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Weights not given keep those of the preset
+	s := cfg.Scoring
+	if s == nil || s.Preset != profiler.ScoringStrict || s.Missing.PerPercent != 4 || s.Missing.Max != 40 || s.Issues.High != 8 {
+		t.Fatalf("Expected strict weights with 4 points per percent missing, got %+v", s)
+	}
+	if s.Columns["notes"] != 0.5 || len(s.Ignore) != 1 || s.Label() != "strict with overrides" {
+		t.Errorf("Unexpected column weights %+v (%s)", s, s.Label())
+	}
+
+	if cfg, err := Load(writeConfig(t, "slas: []\n")); err != nil || cfg.Scoring != nil {
+		t.Errorf("Expected no scoring, got %+v (%v)", cfg, err)
+	}
+
+	for _, content := range []string{
+		"scoring:\n  preset: harsh\n",
+		"scoring:\n  missing: {per_percnt: 4}\n",
+		"scoring:\n  issues: {high: -1}\n",
+		"scoring:\n  ignore: ['[']\n",
+	} {
+		if _, err := Load(writeConfig(t, content)); err == nil {
+			t.Errorf("Expected an error loading %q", content)
+		}
+	}
+}
+
+func TestLoadScoringPresetOrFile(t *testing.T) {
+	s, err := LoadScoring("lenient")
+	if err != nil || s.Preset != profiler.ScoringLenient || s.Label() != "lenient" {
+		t.Errorf("Expected the lenient preset, got %+v (%v)", s, err)
+	}
+
+	s, err = LoadScoring(writeConfig(t, "issues: {low: 0}\n"))
+	if err != nil {
+		t.Fatalf("LoadScoring failed: %v", err)
+	}
+	if s.Preset != profiler.ScoringDefault || s.Issues.Low != 0 || s.Issues.Medium != 2 || s.Missing.PerPercent != 3 {
+		t.Errorf("Expected default weights without low severity issues, got %+v", s)
+	}
+
+	if _, err := LoadScoring("nope"); err == nil || !strings.Contains(err.Error(), "neither a scoring preset") {
+		t.Errorf("Expected an unknown preset to be rejected, got %v", err)
+	}
+	if _, err := LoadScoring(writeConfig(t, "isues: {low: 0}\n")); err == nil {
+		t.Error("Expected an unknown key to be rejected")
+	}
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Outputs are the report formats of profile a config can default to.
+var Outputs = []string{"terminal", "json", "html", "markdown", "github"}
+
+// Settings are defaults of profile and validate: flags given on the
+// command line override them.
+type Settings struct {
+	Delimiter  string `yaml:"delimiter,omitempty"`        // a character, or tab
+	NullValues Tokens `yaml:"null_values,omitempty,flow"` // values read as missing, such as NA or NULL
+	Sample     int    `yaml:"sample,omitempty"`           // rows sampled by profile, 0 for all
+	Output     string `yaml:"output,omitempty"`           // report format of profile
+}
+
+// Tokens are values read as written: NULL, ~ and true stay strings rather
+// than reading as YAML nulls, which a list would drop, and booleans.
+type Tokens []string
+
+// UnmarshalYAML reads a list of values as written.
+func (t *Tokens) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.SequenceNode {
+		return fmt.Errorf("line %d: expected a list of values", node.Line)
+	}
+	tokens := make(Tokens, 0, len(node.Content))
+	for _, value := range node.Content {
+		if value.Kind != yaml.ScalarNode {
+			return fmt.Errorf("line %d: expected a value", value.Line)
+		}
+		tokens = append(tokens, value.Value)
+	}
+	*t = tokens
+	return nil
+}
+
+// Dataset overrides the defaults for the datasets whose file name, or file
+// name#table, matches a pattern such as orders_*.csv, and names the rules
+// file validate checks them against.
+type Dataset struct {
+	Dataset  string `yaml:"dataset"`
+	Settings `yaml:",inline"`
+	Rules    string `yaml:"rules,omitempty"` // relative to the config file
+}
+
+// validate reports a delimiter that is not a single character, an empty
+// null value, a negative sample or an unknown output.
+func (s Settings) validate() error {
+	if d := strings.ToLower(s.Delimiter); d != "" && d != "tab" && d != "\\t" && len([]rune(s.Delimiter)) != 1 {
+		return fmt.Errorf("delimiter %q: expected a single character or tab", s.Delimiter)
+	}
+	if slices.Contains(s.NullValues, "") {
+		return fmt.Errorf("null values must not be empty: empty values are always missing")
+	}
+	if s.Sample < 0 {
+		return fmt.Errorf("sample %d must not be negative", s.Sample)
+	}
+	if s.Output != "" && !slices.Contains(Outputs, s.Output) {
+		return fmt.Errorf("output %s is not a report format (want %s)", s.Output, strings.Join(Outputs, ", "))
+	}
+	return nil
+}
+
+// overlay returns s with the settings o gives.
+func (s Settings) overlay(o Settings) Settings {
+	if o.Delimiter != "" {
+		s.Delimiter = o.Delimiter
+	}
+	if o.NullValues != nil {
+		s.NullValues = o.NullValues
+	}
+	if o.Sample != 0 {
+		s.Sample = o.Sample
+	}
+	if o.Output != "" {
+		s.Output = o.Output
+	}
+	return s
+}
+
+// matches reports whether the dataset entry covers source, or its table or
+// sheet when table is set, as an SLA of the same pattern does. No entry
+// covers an empty source.
+func (d Dataset) matches(source, table string) bool {
+	if source == "" {
+		return false
+	}
+	name := filepath.Base(source)
+	if ok, _ := filepath.Match(d.Dataset, name); ok {
+		return true
+	}
+	if table != "" {
+		ok, _ := filepath.Match(d.Dataset, name+"#"+table)
+		return ok
+	}
+	return false
+}
+
+// SettingsFor returns the settings of source, or of its table or sheet:
+// the defaults, overridden in turn by every dataset entry that matches. An
+// empty source has the defaults.
+func (c *Config) SettingsFor(source, table string) Settings {
+	var settings Settings
+	if c.Defaults != nil {
+		settings = *c.Defaults
+	}
+	for _, d := range c.Datasets {
+		if d.matches(source, table) {
+			settings = settings.overlay(d.Settings)
+		}
+	}
+	return settings
+}
+
+// RulesFor returns the rules file of the last dataset entry that matches
+// source, or its table or sheet, with rules, or "" when none has any.
+func (c *Config) RulesFor(source, table string) string {
+	rules := ""
+	for _, d := range c.Datasets {
+		if d.Rules != "" && d.matches(source, table) {
+			rules = d.Rules
+		}
+	}
+	return rules
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadSettings(t *testing.T) {
+	path := writeConfig(t, `defaults:
+  delimiter: ";"
+  null_values: [NA, "-"]
+  sample: 1000
+  output: json
+datasets:
+  - dataset: orders_*.csv
+    delimiter: "|"
+    rules: rules/orders.yaml
+  - dataset: orders_2024.csv
+    null_values: [NULL, ~]
+    sample: 0
+  - dataset: app.db#users
+    output: markdown
+    rules: /etc/datasleuth/users.yaml
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Path != path {
+		t.Errorf("Expected the path of the config, got %q", cfg.Path)
+	}
+
+	tests := []struct {
+		source, table string
+		want          Settings
+		rules         string
+	}{
+		{"data/events.csv", "", Settings{Delimiter: ";", NullValues: Tokens{"NA", "-"}, Sample: 1000, Output: "json"}, ""},
+		{"data/orders_2023.csv", "", Settings{Delimiter: "|", NullValues: Tokens{"NA", "-"}, Sample: 1000, Output: "json"}, filepath.Join(filepath.Dir(path), "rules/orders.yaml")},
+		{"orders_2024.csv", "", Settings{Delimiter: "|", NullValues: Tokens{"NULL", "~"}, Sample: 1000, Output: "json"}, filepath.Join(filepath.Dir(path), "rules/orders.yaml")},
+		{"app.db", "users", Settings{Delimiter: ";", NullValues: Tokens{"NA", "-"}, Sample: 1000, Output: "markdown"}, "/etc/datasleuth/users.yaml"},
+		{"app.db", "", Settings{Delimiter: ";", NullValues: Tokens{"NA", "-"}, Sample: 1000, Output: "json"}, ""},
+		{"", "", *cfg.Defaults, ""},
+	}
+	for _, tt := range tests {
+		if got := cfg.SettingsFor(tt.source, tt.table); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s#%s: expected %+v, got %+v", tt.source, tt.table, tt.want, got)
+		}
+		if got := cfg.RulesFor(tt.source, tt.table); got != tt.rules {
+			t.Errorf("%s#%s: expected rules %q, got %q", tt.source, tt.table, tt.rules, got)
+		}
+	}
+
+	if got := (&Config{}).SettingsFor("orders.csv", ""); !reflect.DeepEqual(got, Settings{}) {
+		t.Errorf("Expected no settings without a config, got %+v", got)
+	}
+
+	for _, content := range []string{
+		"defaults:\n  delimiter: ab\n",
+		"defaults:\n  null_values: [\"\"]\n",
+		"defaults:\n  null_values: NA\n",
+		"defaults:\n  sample: -1\n",
+		"defaults:\n  output: pdf\n",
+		"defaults:\n  delimter: \";\"\n",
+		"datasets:\n  - delimiter: \";\"\n",
+		"datasets:\n  - dataset: \"[\"\n",
+		"datasets:\n  - dataset: a.csv\n    output: pdf\n",
+	} {
+		if _, err := Load(writeConfig(t, content)); err == nil {
+			t.Errorf("Expected an error loading %q", content)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "project", "data", "raw")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("Failed to create directories: %v", err)
+	}
+	if _, ok := Find(nested); ok {
+		// A config above the temporary directory would be found too
+		t.Skip("a config file is present above the temporary directory")
+	}
+
+	path := filepath.Join(root, "project", DefaultFile)
+	if err := os.WriteFile(path, []byte("defaults:\n  sample: 10\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	for _, dir := range []string{nested, filepath.Join(root, "project")} {
+		if got, ok := Find(dir); !ok || got != path {
+			t.Errorf("%s: expected %s, got %q", dir, path, got)
+		}
+	}
+	if _, ok := Find(root); ok {
+		t.Errorf("Expected no config above the project")
+	}
+
+	t.Chdir(nested)
+	cfg, err := LoadDefault()
+	if err != nil {
+		t.Fatalf("LoadDefault failed: %v", err)
+	}
+	if cfg.Defaults == nil || cfg.Defaults.Sample != 10 {
+		t.Errorf("Expected the config of the project, got %+v", cfg.Defaults)
+	}
+}
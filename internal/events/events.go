@@ -0,0 +1,137 @@
+// Package events carries progress, warnings and per-column completion from
+// the profiling backends to whatever is showing them: terminal progress, an
+// event log or the browser in serve mode. Backends publish to the Default bus
+// without knowing who listens, so a new frontend only has to subscribe.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+type Kind string
+
+const (
+	Started    Kind = "started"     // profiling of a source began
+	Progress   Kind = "progress"    // rows read so far
+	ColumnDone Kind = "column_done" // statistics of one column are complete
+	Warning    Kind = "warning"     // something went wrong but profiling goes on, such as a retried request
+	Note       Kind = "note"        // a note added to the profile
+	Finished   Kind = "finished"    // profiling of a source ended, Message holds the error if it failed
+)
+
+// Event is one message on the bus. Source is the file, URL or table being
+// profiled, so subscribers can tell concurrent profiles apart.
+type Event struct {
+	Kind    Kind      `json:"kind"`
+	Source  string    `json:"source,omitempty"`
+	Time    time.Time `json:"time"`
+	Rows    int64     `json:"rows,omitempty"`
+	Columns int       `json:"columns,omitempty"`
+	Column  string    `json:"column,omitempty"`
+	Message string    `json:"message,omitempty"`
+}
+
+// Bus fans events out to its subscribers. It is safe for concurrent use.
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[*subscription]struct{}
+}
+
+// subscription receives events either on a channel or through a handler.
+type subscription struct {
+	ch   chan Event
+	done chan struct{} // closed on unsubscribe, releasing blocked publishers
+
+	handlerMu sync.Mutex
+	handler   func(Event)
+}
+
+// Default is the bus the profiler and remote packages publish to.
+var Default = New()
+
+func New() *Bus {
+	return &Bus{subs: make(map[*subscription]struct{})}
+}
+
+// Publish sends e to every subscriber, stamping it with the current time when
+// it has none. Progress events are dropped for a channel whose buffer is
+// full, since the next one supersedes them; other events wait for room until
+// the subscriber unsubscribes.
+func (b *Bus) Publish(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subs {
+		if sub.handler != nil {
+			sub.handlerMu.Lock()
+			sub.handler(e)
+			sub.handlerMu.Unlock()
+			continue
+		}
+
+		if e.Kind == Progress {
+			select {
+			case sub.ch <- e:
+			default:
+			}
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		case <-sub.done:
+		}
+	}
+}
+
+// Subscribe returns a channel receiving every event published from now on,
+// buffering up to buffer of them, and a function that unsubscribes and
+// closes the channel once the events already buffered are read.
+func (b *Bus) Subscribe(buffer int) (<-chan Event, func()) {
+	sub := &subscription{ch: make(chan Event, buffer), done: make(chan struct{})}
+	b.add(sub)
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			close(sub.done)
+			b.remove(sub)
+			close(sub.ch)
+		})
+	}
+}
+
+// Handle calls fn with every event published from now on, before Publish
+// returns, until the returned function is called. Calls are never concurrent,
+// even when events are published from several goroutines, and fn must not
+// publish itself.
+func (b *Bus) Handle(fn func(Event)) func() {
+	sub := &subscription{handler: fn}
+	b.add(sub)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { b.remove(sub) })
+	}
+}
+
+func (b *Bus) add(sub *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[sub] = struct{}{}
+}
+
+func (b *Bus) remove(sub *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subs, sub)
+}
+
+// Publish sends e on the Default bus.
+func Publish(e Event) {
+	Default.Publish(e)
+}
@@ -0,0 +1,107 @@
+package events
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSubscribe(t *testing.T) {
+	bus := New()
+	ch, cancel := bus.Subscribe(4)
+
+	bus.Publish(Event{Kind: Started, Source: "data.csv"})
+	bus.Publish(Event{Kind: Finished, Source: "data.csv", Rows: 10})
+	cancel()
+
+	var got []Event
+	for e := range ch {
+		got = append(got, e)
+	}
+	if len(got) != 2 || got[0].Kind != Started || got[1].Rows != 10 {
+		t.Fatalf("Expected the started and finished events, got %+v", got)
+	}
+	if got[0].Time.IsZero() {
+		t.Error("Expected the event to be stamped with the time")
+	}
+
+	// Unsubscribed: nothing is delivered and nothing blocks
+	bus.Publish(Event{Kind: Started})
+}
+
+func TestProgressDroppedWhenFull(t *testing.T) {
+	bus := New()
+	ch, cancel := bus.Subscribe(1)
+	defer cancel()
+
+	bus.Publish(Event{Kind: Progress, Rows: 1})
+	bus.Publish(Event{Kind: Progress, Rows: 2})
+
+	if e := <-ch; e.Rows != 1 {
+		t.Errorf("Expected the first progress event, got %+v", e)
+	}
+	select {
+	case e := <-ch:
+		t.Errorf("Expected the second progress event to be dropped, got %+v", e)
+	default:
+	}
+}
+
+func TestUnsubscribeReleasesPublisher(t *testing.T) {
+	bus := New()
+	_, cancel := bus.Subscribe(0)
+
+	done := make(chan struct{})
+	go func() {
+		bus.Publish(Event{Kind: Warning})
+		close(done)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish stayed blocked on a subscriber that unsubscribed")
+	}
+}
+
+func TestHandleIsNotConcurrent(t *testing.T) {
+	bus := New()
+
+	var active, calls int
+	var mu sync.Mutex
+	stop := bus.Handle(func(e Event) {
+		mu.Lock()
+		active++
+		overlap := active > 1
+		mu.Unlock()
+		if overlap {
+			t.Error("Handler called concurrently")
+		}
+		time.Sleep(time.Microsecond)
+		mu.Lock()
+		active--
+		calls++
+		mu.Unlock()
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				bus.Publish(Event{Kind: Progress})
+			}
+		}()
+	}
+	wg.Wait()
+	stop()
+	bus.Publish(Event{Kind: Progress})
+
+	if calls != 80 {
+		t.Errorf("Expected 80 handler calls, got %d", calls)
+	}
+}
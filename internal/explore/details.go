@@ -0,0 +1,148 @@
+package explore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// columnDetails renders the statistics of col as lines at most width wide:
+// the summary, the type-specific statistics with a histogram or the top
+// values, then notes and quality issues.
+func columnDetails(profile *profiler.DatasetProfile, col *profiler.ColumnProfile, width int) []string {
+	var lines []string
+	add := func(format string, args ...interface{}) {
+		lines = append(lines, fit(fmt.Sprintf(format, args...), width))
+	}
+	stat := func(label string, value interface{}) {
+		add("%-12s %v", label, value)
+	}
+
+	lines = append(lines, titleStyle.Render(fit(col.Name, width)), mutedStyle.Render(col.DataType), "")
+
+	missing := 0.0
+	if profile.RowCount > 0 {
+		missing = float64(col.MissingCount) / float64(profile.RowCount) * 100
+	}
+	stat("Values", col.Count)
+	stat("Missing", fmt.Sprintf("%d (%.2f%%)", col.MissingCount, missing))
+	stat("Unique", col.UniqueCount)
+	if col.Nullability != nil {
+		stat("Nulls", col.Nullability)
+	}
+
+	barWidth := width - 36
+	if barWidth < 5 {
+		barWidth = 5
+	}
+
+	switch {
+	case col.IsNumeric:
+		stat("Min", col.Min)
+		stat("Max", col.Max)
+		stat("Mean", fmt.Sprintf("%.4f", col.Mean))
+		stat("Median", fmt.Sprintf("%.4f", col.Median))
+		stat("Std dev", fmt.Sprintf("%.4f", col.StdDev))
+		for _, p := range col.Percentiles {
+			stat(fmt.Sprintf("p%d", p.Rank), fmt.Sprintf("%.4g", p.Value))
+		}
+		if col.Mode != nil {
+			stat("Mode", col.Mode)
+		}
+
+		if len(col.HistogramBuckets) > 0 {
+			lines = append(lines, "", headingStyle.Render("Histogram"))
+			counts := make([]int, len(col.HistogramBuckets))
+			for i, bucket := range col.HistogramBuckets {
+				counts[i] = bucket.Count
+			}
+			for i, bucket := range col.HistogramBuckets {
+				label := fmt.Sprintf("%.4g to %.4g", bucket.LowerBound, bucket.UpperBound)
+				add("%-22s %s %d", fit(label, 22), bar(counts, i, barWidth), bucket.Count)
+			}
+		}
+	case col.DateTime != nil:
+		d := col.DateTime
+		stat("Min", d.Format(d.Min))
+		stat("Max", d.Format(d.Max))
+		stat("Span", profiler.FormatSpan(d.Span()))
+		stat("Granularity", d.Granularity)
+		if d.Gaps > 0 {
+			stat("Gaps", fmt.Sprintf("%d (%d missing periods)", d.Gaps, d.MissingPeriods))
+		}
+
+		if len(d.Histogram) > 0 {
+			lines = append(lines, "", headingStyle.Render("Timeline"))
+			counts := make([]int, len(d.Histogram))
+			for i, bucket := range d.Histogram {
+				counts[i] = bucket.Count
+			}
+			for i, bucket := range d.Histogram {
+				add("%-22s %s %d", fit(d.Format(bucket.Start), 22), bar(counts, i, barWidth), bucket.Count)
+			}
+		}
+	case col.IsOpaque:
+		stat("Avg size", profiler.FormatBytes(col.AvgLength))
+		stat("Max size", profiler.FormatBytes(float64(col.MaxLength)))
+	case col.Text != nil:
+		t := col.Text
+		stat("Length", fmt.Sprintf("%d to %d (avg %.1f)", t.MinLength, t.MaxLength, t.AvgLength))
+		if t.Casing != "" {
+			stat("Casing", fmt.Sprintf("%s (%.1f%%)", t.Casing, t.CasingPercent))
+		}
+		if len(t.Patterns) > 0 {
+			patterns := make([]string, len(t.Patterns))
+			for i, p := range t.Patterns {
+				patterns[i] = p.Value
+			}
+			stat("Patterns", strings.Join(patterns, ", "))
+		}
+	}
+
+	if len(col.TopValues) > 0 && !col.IsOpaque {
+		lines = append(lines, "", headingStyle.Render("Top values"))
+		counts := make([]int, len(col.TopValues))
+		for i, value := range col.TopValues {
+			counts[i] = value.Count
+		}
+		for i, value := range col.TopValues {
+			add("%-22s %s %d", fit(value.Value, 22), bar(counts, i, barWidth), value.Count)
+		}
+	}
+
+	if len(col.QualityIssues) > 0 {
+		lines = append(lines, "", headingStyle.Render("Quality issues"))
+		for _, issue := range col.QualityIssues {
+			severity := issue.Severity
+			if severity < 0 || severity >= len(severityStyle) {
+				severity = 0
+			}
+			lines = append(lines, severityStyle[severity].Render("● ")+fit(issue.Description, width-2))
+		}
+	}
+
+	if len(col.Notes) > 0 {
+		lines = append(lines, "", headingStyle.Render("Notes"))
+		for _, note := range col.Notes {
+			add("%s", note)
+		}
+	}
+
+	return lines
+}
+
+// bar draws counts[i] as a bar scaled so the largest count fills width.
+func bar(counts []int, i, width int) string {
+	largest := 0
+	for _, count := range counts {
+		if count > largest {
+			largest = count
+		}
+	}
+	n := 0
+	if largest > 0 {
+		n = counts[i] * width / largest
+	}
+	return strings.Repeat("█", n) + strings.Repeat(" ", width-n)
+}
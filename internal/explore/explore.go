@@ -0,0 +1,365 @@
+// Package explore is an interactive terminal UI for browsing a profile: a
+// list of the columns on the left, the statistics, histogram and issues of the
+// selected column on the right, with search and sorting for wide datasets.
+package explore
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+type sortMode int
+
+const (
+	sortByName sortMode = iota
+	sortByMissing
+	sortBySeverity
+	sortModes
+)
+
+var sortLabels = [sortModes]string{"name", "missing %", "issue severity"}
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("14"))
+	mutedStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	headingStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	paneStyle     = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("8"))
+	severityStyle = [4]lipgloss.Style{
+		lipgloss.NewStyle(),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("6")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("3")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("1")),
+	}
+)
+
+// Model is the state of the explorer, driven by bubbletea.
+type Model struct {
+	profile *profiler.DatasetProfile
+	columns []*profiler.ColumnProfile // every column, in the current sort order
+	visible []*profiler.ColumnProfile // columns matching the search
+
+	cursor int // selected row of visible
+	offset int // first row of visible shown in the list
+	scroll int // first line of the details shown
+
+	sort      sortMode
+	query     string
+	searching bool
+
+	width, height int
+}
+
+func New(profile *profiler.DatasetProfile) Model {
+	m := Model{profile: profile, width: 100, height: 30}
+	for _, col := range profile.Columns {
+		m.columns = append(m.columns, col)
+	}
+	m.sortColumns()
+	return m
+}
+
+// Run shows the explorer until the user quits.
+func Run(profile *profiler.DatasetProfile) error {
+	_, err := tea.NewProgram(New(profile), tea.WithAltScreen()).Run()
+	return err
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.clampOffset()
+	case tea.KeyMsg:
+		if m.searching {
+			return m.updateSearch(msg), nil
+		}
+		return m.updateBrowse(msg)
+	}
+	return m, nil
+}
+
+func (m Model) updateSearch(msg tea.KeyMsg) Model {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.searching = false
+	case tea.KeyEsc:
+		m.searching = false
+		m.query = ""
+		m.filter()
+	case tea.KeyBackspace:
+		if runes := []rune(m.query); len(runes) > 0 {
+			m.query = string(runes[:len(runes)-1])
+			m.filter()
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.query += string(msg.Runes)
+		m.filter()
+	}
+	return m
+}
+
+func (m Model) updateBrowse(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "up", "k":
+		m.move(-1)
+	case "down", "j":
+		m.move(1)
+	case "pgup", "ctrl+b":
+		m.move(-m.listHeight())
+	case "pgdown", "ctrl+f", " ":
+		m.move(m.listHeight())
+	case "home", "g":
+		m.move(-len(m.visible))
+	case "end", "G":
+		m.move(len(m.visible))
+	case "K", "shift+up":
+		if m.scroll > 0 {
+			m.scroll--
+		}
+	case "J", "shift+down":
+		m.scroll++
+	case "/":
+		m.searching = true
+	case "esc":
+		if m.query != "" {
+			m.query = ""
+			m.filter()
+		}
+	case "s":
+		m.sort = (m.sort + 1) % sortModes
+		m.sortColumns()
+	}
+	return m, nil
+}
+
+func (m *Model) move(delta int) {
+	m.cursor += delta
+	if m.cursor >= len(m.visible) {
+		m.cursor = len(m.visible) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	m.scroll = 0
+	m.clampOffset()
+}
+
+// clampOffset scrolls the list so the cursor stays in view.
+func (m *Model) clampOffset() {
+	height := m.listHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+}
+
+// sortColumns orders the columns by the sort mode, worst first for missing
+// values and issues, and by name among equals.
+func (m *Model) sortColumns() {
+	// Sort a copy: earlier models share the slice
+	m.columns = append([]*profiler.ColumnProfile(nil), m.columns...)
+	sort.SliceStable(m.columns, func(i, j int) bool {
+		a, b := m.columns[i], m.columns[j]
+		switch m.sort {
+		case sortByMissing:
+			if a.MissingCount != b.MissingCount {
+				return a.MissingCount > b.MissingCount
+			}
+		case sortBySeverity:
+			if sa, sb := maxSeverity(a), maxSeverity(b); sa != sb {
+				return sa > sb
+			}
+			if len(a.QualityIssues) != len(b.QualityIssues) {
+				return len(a.QualityIssues) > len(b.QualityIssues)
+			}
+		}
+		return a.Name < b.Name
+	})
+
+	m.filter()
+}
+
+// filter keeps the columns whose name contains the search, ignoring case.
+func (m *Model) filter() {
+	selected := m.selected()
+
+	query := strings.ToLower(m.query)
+	m.visible = make([]*profiler.ColumnProfile, 0, len(m.columns))
+	for _, col := range m.columns {
+		if strings.Contains(strings.ToLower(col.Name), query) {
+			m.visible = append(m.visible, col)
+		}
+	}
+
+	m.reselect(selected)
+}
+
+// reselect moves the cursor back to col after the list changed, or to the
+// top when col is gone.
+func (m *Model) reselect(col *profiler.ColumnProfile) {
+	m.cursor = 0
+	for i, c := range m.visible {
+		if c == col {
+			m.cursor = i
+			break
+		}
+	}
+	m.clampOffset()
+}
+
+func (m Model) selected() *profiler.ColumnProfile {
+	if m.cursor < 0 || m.cursor >= len(m.visible) {
+		return nil
+	}
+	return m.visible[m.cursor]
+}
+
+// listHeight is the number of column rows that fit in the list pane.
+func (m Model) listHeight() int {
+	// Title and footer lines, the pane borders and the list header
+	if h := m.height - 5; h > 1 {
+		return h
+	}
+	return 1
+}
+
+func (m Model) View() string {
+	listWidth := m.width / 3
+	if listWidth > 48 {
+		listWidth = 48
+	}
+	if listWidth < 24 {
+		listWidth = 24
+	}
+	detailWidth := m.width - listWidth - 4
+	if detailWidth < 20 {
+		detailWidth = 20
+	}
+	height := m.listHeight() + 1
+
+	list := paneStyle.Width(listWidth).Height(height).Render(m.listView(listWidth, height))
+	detail := paneStyle.Width(detailWidth).Height(height).Render(m.detailView(detailWidth, height))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		m.titleView(),
+		lipgloss.JoinHorizontal(lipgloss.Top, list, detail),
+		m.footerView(),
+	)
+}
+
+func (m Model) titleView() string {
+	p := m.profile
+	name := p.Filename
+	if p.Table != "" {
+		name += " › " + p.Table
+	}
+	return titleStyle.Render("DataSleuth") + " " + name + mutedStyle.Render(fmt.Sprintf(
+		"  %d rows × %d columns · quality %d/100", p.RowCount, p.ColumnCount, p.QualityScore))
+}
+
+func (m Model) footerView() string {
+	if m.searching {
+		return "/" + m.query + "█" + mutedStyle.Render("  enter keep · esc clear")
+	}
+
+	status := fmt.Sprintf("%d/%d columns · sorted by %s", len(m.visible), len(m.columns), sortLabels[m.sort])
+	if m.query != "" {
+		status += fmt.Sprintf(" · matching %q", m.query)
+	}
+	return mutedStyle.Render(status + "  ↑↓ move · J/K scroll details · / search · s sort · q quit")
+}
+
+func (m Model) listView(width, height int) string {
+	lines := []string{headingStyle.Render(fit(fmt.Sprintf("%-*s %8s %s", width-12, "Column", "Missing", "!"), width))}
+
+	end := m.offset + height - 1
+	if end > len(m.visible) {
+		end = len(m.visible)
+	}
+	for i := m.offset; i < end; i++ {
+		col := m.visible[i]
+		name := fit(col.Name, width-12)
+		line := fmt.Sprintf("%-*s %7.1f%% ", width-12, name, m.missingPercent(col))
+
+		marker := " "
+		if severity := maxSeverity(col); severity > 0 {
+			marker = severityStyle[severity].Render("●")
+		}
+		if i == m.cursor {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line+marker)
+	}
+	if len(m.visible) == 0 {
+		lines = append(lines, mutedStyle.Render("No columns match"))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) detailView(width, height int) string {
+	col := m.selected()
+	if col == nil {
+		return ""
+	}
+
+	lines := columnDetails(m.profile, col, width)
+	scroll := m.scroll
+	if max := len(lines) - height; scroll > max {
+		scroll = max
+	}
+	if scroll < 0 {
+		scroll = 0
+	}
+	lines = lines[scroll:]
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) missingPercent(col *profiler.ColumnProfile) float64 {
+	if m.profile.RowCount == 0 {
+		return 0
+	}
+	return float64(col.MissingCount) / float64(m.profile.RowCount) * 100
+}
+
+func maxSeverity(col *profiler.ColumnProfile) int {
+	severity := 0
+	for _, issue := range col.QualityIssues {
+		if issue.Severity > severity && issue.Severity < len(severityStyle) {
+			severity = issue.Severity
+		}
+	}
+	return severity
+}
+
+// fit truncates s to width runes, marking the cut with an ellipsis.
+func fit(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,109 @@
+package explore
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func testProfile() *profiler.DatasetProfile {
+	return &profiler.DatasetProfile{
+		Filename:    "orders.csv",
+		RowCount:    100,
+		ColumnCount: 3,
+		Columns: map[string]*profiler.ColumnProfile{
+			"amount": {
+				Name: "amount", DataType: "float", Count: 90, MissingCount: 10, IsNumeric: true,
+				Min: 1.0, Max: 99.0, Mean: 50,
+				HistogramBuckets: []profiler.HistogramBucket{{LowerBound: 1, UpperBound: 50, Count: 60}, {LowerBound: 50, UpperBound: 99, Count: 30}},
+			},
+			"customer_email": {
+				Name: "customer_email", DataType: "string", Count: 50, MissingCount: 50,
+				QualityIssues: []profiler.QualityIssue{{Type: "missing_values", Description: "50% missing", Severity: 3}},
+			},
+			"id": {
+				Name: "id", DataType: "integer", Count: 100, IsNumeric: true,
+				QualityIssues: []profiler.QualityIssue{{Type: "outliers", Description: "outliers", Severity: 1}},
+			},
+		},
+	}
+}
+
+func press(m tea.Model, keys ...string) tea.Model {
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		m, _ = m.Update(msg)
+	}
+	return m
+}
+
+func names(m tea.Model) []string {
+	var result []string
+	for _, col := range m.(Model).visible {
+		result = append(result, col.Name)
+	}
+	return result
+}
+
+func TestSort(t *testing.T) {
+	m := tea.Model(New(testProfile()))
+	if got := strings.Join(names(m), ","); got != "amount,customer_email,id" {
+		t.Errorf("Expected columns by name, got %s", got)
+	}
+
+	m = press(m, "s")
+	if got := strings.Join(names(m), ","); got != "customer_email,amount,id" {
+		t.Errorf("Expected columns by missing %%, got %s", got)
+	}
+
+	m = press(m, "s")
+	if got := strings.Join(names(m), ","); got != "customer_email,id,amount" {
+		t.Errorf("Expected columns by issue severity, got %s", got)
+	}
+}
+
+func TestSearchKeepsSelection(t *testing.T) {
+	m := press(New(testProfile()), "down", "/", "E", "M", "enter")
+
+	if got := strings.Join(names(m), ","); got != "customer_email" {
+		t.Errorf("Expected the search to match customer_email only, got %s", got)
+	}
+	if m.(Model).selected().Name != "customer_email" {
+		t.Errorf("Expected customer_email to stay selected, got %s", m.(Model).selected().Name)
+	}
+
+	m = press(m, "esc")
+	if len(names(m)) != 3 || m.(Model).selected().Name != "customer_email" {
+		t.Errorf("Expected esc to clear the search and keep the selection, got %v", names(m))
+	}
+
+	if m, _ := press(m, "/", "z", "z").(Model).Update(tea.KeyMsg{Type: tea.KeyEnter}); m.(Model).selected() != nil {
+		t.Error("Expected no selection when nothing matches")
+	}
+}
+
+func TestView(t *testing.T) {
+	m, _ := New(testProfile()).Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	view := m.View()
+
+	for _, expected := range []string{"orders.csv", "100 rows × 3 columns", "customer_email", "Histogram", "50 to 99", "sorted by name"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected the view to contain %q, got:\n%s", expected, view)
+		}
+	}
+	if lines := strings.Count(view, "\n") + 1; lines > 30 {
+		t.Errorf("Expected the view to fit 30 lines, got %d", lines)
+	}
+}
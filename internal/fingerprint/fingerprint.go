@@ -0,0 +1,153 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// Kind marks a JSON file as a column fingerprint rather than a profile.
+const Kind = "column_fingerprint"
+
+// Fingerprint holds the salted MinHash signatures of the columns of a
+// dataset and nothing else of its values, so that it can be handed to
+// another party to estimate how many identifiers their datasets share.
+type Fingerprint struct {
+	Kind      string   `json:"kind"`
+	Source    string   `json:"source"`
+	Rows      int      `json:"rows"`
+	Salt      string   `json:"salt"` // identifies the salt without revealing it
+	Size      int      `json:"size"` // hashes kept per column
+	CreatedAt string   `json:"created_at"`
+	Columns   []Column `json:"columns"`
+}
+
+type Column struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	Values int      `json:"values"` // non-missing values
+	Hashes []string `json:"hashes"` // ascending, as hex so that no JSON reader rounds them
+}
+
+// FromProfile takes the signatures of a profile made with Options.MinHash.
+func FromProfile(profile *profiler.DatasetProfile) (*Fingerprint, error) {
+	fp := &Fingerprint{
+		Kind:      Kind,
+		Source:    profile.Filename,
+		Rows:      profile.RowCount,
+		CreatedAt: time.Now().Format(time.RFC3339),
+		Columns:   make([]Column, 0, len(profile.Columns)),
+	}
+	if profile.Table != "" {
+		fp.Source += " (" + profile.Table + ")"
+	}
+
+	for _, col := range profile.Columns {
+		if col.MinHash == nil {
+			continue
+		}
+		fp.Salt, fp.Size = col.MinHash.Salt, col.MinHash.Size
+
+		hashes := make([]string, len(col.MinHash.Hashes))
+		for i, h := range col.MinHash.Hashes {
+			hashes[i] = fmt.Sprintf("%016x", h)
+		}
+		fp.Columns = append(fp.Columns, Column{
+			Name:   col.Name,
+			Type:   col.DataType,
+			Values: col.Count,
+			Hashes: hashes,
+		})
+	}
+	if len(fp.Columns) == 0 {
+		return nil, fmt.Errorf("%s has no MinHash signatures", profile.Filename)
+	}
+
+	positions := make(map[string]int, len(profile.Columns))
+	for name, col := range profile.Columns {
+		positions[name] = col.Position
+	}
+	sort.Slice(fp.Columns, func(i, j int) bool {
+		return positions[fp.Columns[i].Name] < positions[fp.Columns[j].Name]
+	})
+	return fp, nil
+}
+
+// MinHash returns the signature of col.
+func (fp *Fingerprint) MinHash(col Column) (*profiler.MinHash, error) {
+	hashes := make([]uint64, len(col.Hashes))
+	for i, text := range col.Hashes {
+		h, err := strconv.ParseUint(text, 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hash %q of column %s", text, col.Name)
+		}
+		hashes[i] = h
+	}
+	if !sort.SliceIsSorted(hashes, func(i, j int) bool { return hashes[i] < hashes[j] }) || len(hashes) > fp.Size {
+		return nil, fmt.Errorf("hashes of column %s are not a signature of size %d", col.Name, fp.Size)
+	}
+	return &profiler.MinHash{Salt: fp.Salt, Size: fp.Size, Hashes: hashes}, nil
+}
+
+// Write saves the fingerprint as indented JSON.
+func Write(fp *Fingerprint, outputPath string) error {
+	data, err := json.MarshalIndent(fp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fingerprint: %w", err)
+	}
+
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write fingerprint to file: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads a fingerprint written by Write.
+func Load(inputPath string) (*Fingerprint, error) {
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fingerprint: %w", err)
+	}
+
+	var fp Fingerprint
+	if err := json.Unmarshal(data, &fp); err != nil {
+		return nil, fmt.Errorf("failed to parse fingerprint: %w", err)
+	}
+	if fp.Kind != Kind {
+		return nil, fmt.Errorf("%s is not a column fingerprint", inputPath)
+	}
+	if fp.Size <= 0 {
+		return nil, fmt.Errorf("%s has no signature size", inputPath)
+	}
+
+	return &fp, nil
+}
+
+// IsFingerprintFile reports whether path is a JSON column fingerprint.
+func IsFingerprintFile(path string) bool {
+	if !strings.EqualFold(filepath.Ext(path), ".json") {
+		return false
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	var header struct {
+		Kind string `json:"kind"`
+	}
+	if err := json.NewDecoder(file).Decode(&header); err != nil {
+		return false
+	}
+	return header.Kind == Kind
+}
@@ -0,0 +1,83 @@
+package fingerprint
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func fingerprintCSV(t *testing.T, content, salt string) *Fingerprint {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "data.csv")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write data: %v", err)
+	}
+
+	profile, err := profiler.ProfileDatasetWithOptions(path, profiler.Options{MinHash: 64, MinHashSalt: salt})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	fp, err := FromProfile(profile)
+	if err != nil {
+		t.Fatalf("FromProfile failed: %v", err)
+	}
+	return fp
+}
+
+func TestWriteAndLoad(t *testing.T) {
+	fp := fingerprintCSV(t, "id,email\n1,a@example.com\n2,b@example.com\n3,\n", "pepper")
+	if len(fp.Columns) != 2 || fp.Columns[0].Name != "id" || fp.Columns[1].Values != 2 || fp.Size != 64 || fp.Rows != 3 {
+		t.Fatalf("Unexpected fingerprint: %+v", fp)
+	}
+
+	path := filepath.Join(t.TempDir(), "data_fingerprint.json")
+	if err := Write(fp, path); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if !IsFingerprintFile(path) {
+		t.Error("Expected the file to be recognized as a fingerprint")
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, fp) {
+		t.Errorf("Expected the fingerprint to round trip, got %+v", loaded)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read fingerprint: %v", err)
+	}
+	for _, value := range []string{"example.com", "pepper"} {
+		if strings.Contains(string(content), value) {
+			t.Errorf("Expected the fingerprint not to hold %q", value)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	ours := fingerprintCSV(t, "customer_id,email\nc1,a@example.com\nc2,b@example.com\nc3,c@example.com\nc4,d@example.com\n", "pepper")
+	theirs := fingerprintCSV(t, "contact,country\nB@Example.com,US\nd@example.com ,DE\ne@example.com,US\n", "pepper")
+
+	overlap, err := Compare(ours, theirs)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(overlap.Matches) != 1 {
+		t.Fatalf("Expected one matching pair of columns, got %+v", overlap.Matches)
+	}
+	match := overlap.Matches[0]
+	if match.Base != "email" || match.Target != "contact" || match.Shared != 2 || !match.Exact ||
+		match.BaseShare() != 0.5 || match.TargetShare() != 2.0/3 || match.Jaccard != 0.4 {
+		t.Errorf("Expected email and contact to share 2 values, got %+v", match)
+	}
+
+	if _, err := Compare(ours, fingerprintCSV(t, "contact\nb@example.com\n", "salt")); err == nil {
+		t.Error("Expected fingerprints of different salts to be rejected")
+	}
+}
@@ -0,0 +1,103 @@
+package fingerprint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// Overlap lists the pairs of columns, one of each fingerprint, that share
+// values.
+type Overlap struct {
+	Base    string
+	Target  string
+	Matches []Match // by descending Jaccard index
+}
+
+// Match is the estimated overlap of a column of each fingerprint. Counts are
+// of distinct values after trimming and lower casing.
+type Match struct {
+	Base           string
+	Target         string
+	BaseDistinct   float64
+	TargetDistinct float64
+	Shared         float64
+	Jaccard        float64
+	Exact          bool // both columns have fewer distinct values than the signature size
+}
+
+// BaseShare is the share of the distinct values of the base column found in
+// the target column, from 0 to 1.
+func (m Match) BaseShare() float64 {
+	return share(m.Shared, m.BaseDistinct)
+}
+
+// TargetShare is the share of the distinct values of the target column found
+// in the base column, from 0 to 1.
+func (m Match) TargetShare() float64 {
+	return share(m.Shared, m.TargetDistinct)
+}
+
+func share(shared, distinct float64) float64 {
+	if distinct == 0 {
+		return 0
+	}
+	return min(shared/distinct, 1)
+}
+
+// Compare estimates the overlap of every column of base with every column of
+// target. Both must be made with the same salt.
+func Compare(base, target *Fingerprint) (*Overlap, error) {
+	if base.Salt != target.Salt {
+		return nil, fmt.Errorf("fingerprints were made with different salts (%s and %s): agree on one salt and fingerprint both datasets with it", base.Salt, target.Salt)
+	}
+
+	baseHashes, err := base.signatures()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", base.Source, err)
+	}
+	targetHashes, err := target.signatures()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", target.Source, err)
+	}
+
+	overlap := &Overlap{Base: base.Source, Target: target.Source, Matches: make([]Match, 0)}
+	for i, baseCol := range base.Columns {
+		for j, targetCol := range target.Columns {
+			estimate, err := profiler.EstimateOverlap(baseHashes[i], targetHashes[j])
+			if err != nil {
+				return nil, err
+			}
+			if estimate.Shared == 0 {
+				continue
+			}
+			overlap.Matches = append(overlap.Matches, Match{
+				Base:           baseCol.Name,
+				Target:         targetCol.Name,
+				BaseDistinct:   baseHashes[i].Distinct(),
+				TargetDistinct: targetHashes[j].Distinct(),
+				Shared:         estimate.Shared,
+				Jaccard:        estimate.Jaccard,
+				Exact:          estimate.Exact,
+			})
+		}
+	}
+
+	sort.SliceStable(overlap.Matches, func(i, j int) bool {
+		return overlap.Matches[i].Jaccard > overlap.Matches[j].Jaccard
+	})
+	return overlap, nil
+}
+
+func (fp *Fingerprint) signatures() ([]*profiler.MinHash, error) {
+	signatures := make([]*profiler.MinHash, len(fp.Columns))
+	for i, col := range fp.Columns {
+		signature, err := fp.MinHash(col)
+		if err != nil {
+			return nil, err
+		}
+		signatures[i] = signature
+	}
+	return signatures, nil
+}
@@ -0,0 +1,430 @@
+package fixture
+
+import (
+	"fmt"
+	"go/format"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+const LangGo = "go"
+
+type Options struct {
+	Package  string // package of the generated file
+	TypeName string // struct name, derived from the dataset file name when empty
+}
+
+// field is one column of the generated struct together with the Go
+// expression that produces a value for it.
+type field struct {
+	column   *profiler.ColumnProfile
+	name     string
+	goType   string
+	nullable bool
+	expr     string
+	vars     []string
+}
+
+type generator struct {
+	prefix  string
+	imports map[string]bool
+	helpers map[string]bool
+}
+
+// GenerateGo renders Go source with a struct mirroring the profiled columns
+// and a function that generates rows shaped like the profile: frequent values
+// keep their observed weights, numbers follow the observed mean and spread
+// within the observed range, unique columns stay unique and missing values
+// appear at the observed rate.
+func GenerateGo(profile *profiler.DatasetProfile, opts Options) ([]byte, error) {
+	if len(profile.Columns) == 0 {
+		return nil, fmt.Errorf("profile has no columns")
+	}
+
+	pkg := opts.Package
+	if pkg == "" {
+		pkg = "fixtures"
+	}
+
+	typeName := opts.TypeName
+	if typeName == "" {
+		base := strings.TrimSuffix(filepath.Base(profile.Filename), filepath.Ext(profile.Filename))
+		typeName = exportedName(base) + "Row"
+	}
+
+	g := &generator{
+		prefix:  unexportedName(typeName),
+		imports: map[string]bool{"math/rand": true},
+		helpers: make(map[string]bool),
+	}
+
+	names := make([]string, 0, len(profile.Columns))
+	for name := range profile.Columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	used := make(map[string]int)
+	fields := make([]*field, 0, len(names))
+	for _, name := range names {
+		f := g.newField(profile.Columns[name])
+
+		used[f.name]++
+		if used[f.name] > 1 {
+			f.name = fmt.Sprintf("%s%d", f.name, used[f.name])
+		}
+		fields = append(fields, f)
+	}
+
+	var src strings.Builder
+
+	src.WriteString(fmt.Sprintf("// Code generated by datasleuth gen-fixture from %s. DO NOT EDIT.\n\n", profile.Filename))
+	src.WriteString(fmt.Sprintf("package %s\n\n", pkg))
+
+	imports := make([]string, 0, len(g.imports))
+	for imp := range g.imports {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+	src.WriteString("import (\n")
+	for _, imp := range imports {
+		src.WriteString(fmt.Sprintf("\t%q\n", imp))
+	}
+	src.WriteString(")\n\n")
+
+	src.WriteString(fmt.Sprintf("// %s is a row of %s.\n", typeName, profile.Filename))
+	src.WriteString(fmt.Sprintf("type %s struct {\n", typeName))
+	for _, f := range fields {
+		goType := f.goType
+		if f.nullable {
+			goType = "*" + goType
+		}
+		src.WriteString(fmt.Sprintf("\t%s %s `json:%q csv:%q`\n", f.name, goType, f.column.Name, f.column.Name))
+	}
+	src.WriteString("}\n\n")
+
+	for _, f := range fields {
+		for _, v := range f.vars {
+			src.WriteString(v + "\n")
+		}
+	}
+	src.WriteString("\n")
+
+	src.WriteString(fmt.Sprintf("// Generate%ss returns n rows shaped like %s (%d rows profiled).\n", typeName, profile.Filename, profile.RowCount))
+	src.WriteString(fmt.Sprintf("func Generate%ss(r *rand.Rand, n int) []%s {\n", typeName, typeName))
+	src.WriteString(fmt.Sprintf("\trows := make([]%s, n)\n", typeName))
+	src.WriteString("\tfor i := range rows {\n")
+	for _, f := range fields {
+		if f.expr == "" {
+			continue
+		}
+		if f.nullable {
+			rate := missingRate(f.column, profile.RowCount)
+			src.WriteString(fmt.Sprintf("\t\tif r.Float64() >= %s {\n", formatFloat(rate)))
+			src.WriteString(fmt.Sprintf("\t\t\tv := %s\n", f.expr))
+			src.WriteString(fmt.Sprintf("\t\t\trows[i].%s = &v\n", f.name))
+			src.WriteString("\t\t}\n")
+		} else {
+			src.WriteString(fmt.Sprintf("\t\trows[i].%s = %s\n", f.name, f.expr))
+		}
+	}
+	src.WriteString("\t}\n")
+	src.WriteString("\treturn rows\n")
+	src.WriteString("}\n")
+
+	for _, helper := range []string{"pick", "normal", "bytes"} {
+		if g.helpers[helper] {
+			src.WriteString("\n" + g.helperSource(helper))
+		}
+	}
+
+	formatted, err := format.Source([]byte(src.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+
+	return formatted, nil
+}
+
+func (g *generator) newField(col *profiler.ColumnProfile) *field {
+	f := &field{
+		column:   col,
+		name:     exportedName(col.Name),
+		nullable: col.MissingCount > 0,
+	}
+	varName := g.prefix + exportedName(col.Name)
+
+	switch {
+	case col.Count == 0:
+		f.goType = "string"
+		f.nullable = true
+	case col.IsOpaque:
+		f.goType = "[]byte"
+		f.nullable = false
+		g.helpers["bytes"] = true
+		f.expr = fmt.Sprintf("%sBytes(r, %d)", g.prefix, int(math.Round(col.AvgLength)))
+	case col.DataType == "integer":
+		f.goType = "int64"
+		if col.IsUnique {
+			f.expr = fmt.Sprintf("int64(%d + i)", int64(toFloat(col.Min)))
+		} else if values, weights, ok := intValues(col); ok && col.IsCategorical {
+			g.helpers["pick"] = true
+			f.vars = append(f.vars,
+				fmt.Sprintf("var %sValues = []int64{%s}", varName, strings.Join(values, ", ")),
+				fmt.Sprintf("var %sWeights = []int{%s}", varName, weights))
+			f.expr = fmt.Sprintf("%sValues[%sPick(r, %sWeights)]", varName, g.prefix, varName)
+		} else {
+			g.imports["math"] = true
+			f.expr = fmt.Sprintf("int64(math.Round(%s))", g.normalExpr(col))
+		}
+	case col.DataType == "float":
+		f.goType = "float64"
+		f.expr = g.normalExpr(col)
+	case col.DataType == "datetime" && !col.IsCategorical:
+		f.goType = "string"
+		if expr, ok := g.dateExpr(col); ok {
+			f.expr = expr
+		} else {
+			f.expr = g.stringExpr(col, f, varName)
+		}
+	default:
+		f.goType = "string"
+		f.expr = g.stringExpr(col, f, varName)
+	}
+
+	return f
+}
+
+func (g *generator) normalExpr(col *profiler.ColumnProfile) string {
+	g.helpers["normal"] = true
+	return fmt.Sprintf("%sNormal(r, %s, %s, %s, %s)", g.prefix,
+		formatFloat(col.Mean), formatFloat(col.StdDev), formatFloat(toFloat(col.Min)), formatFloat(toFloat(col.Max)))
+}
+
+// stringExpr draws from the top values with their observed weights. The share
+// of values outside the top values, and unique columns, get synthetic values.
+func (g *generator) stringExpr(col *profiler.ColumnProfile, f *field, varName string) string {
+	synthetic := fmt.Sprintf("fmt.Sprintf(\"%s-%%d\", r.Intn(%d)+1)", sanitizeLiteral(col.Name), maxInt(col.UniqueCount, 1))
+	if col.IsUnique {
+		g.imports["fmt"] = true
+		return fmt.Sprintf("fmt.Sprintf(\"%s-%%d\", i+1)", sanitizeLiteral(col.Name))
+	}
+	if len(col.TopValues) == 0 {
+		g.imports["fmt"] = true
+		return synthetic
+	}
+
+	values := make([]string, len(col.TopValues))
+	weights := make([]string, len(col.TopValues))
+	covered := 0
+	for i, val := range col.TopValues {
+		values[i] = strconv.Quote(val.Value)
+		weights[i] = strconv.Itoa(val.Count)
+		covered += val.Count
+	}
+
+	g.helpers["pick"] = true
+	f.vars = append(f.vars,
+		fmt.Sprintf("var %sValues = []string{%s}", varName, strings.Join(values, ", ")),
+		fmt.Sprintf("var %sWeights = []int{%s}", varName, strings.Join(weights, ", ")))
+
+	pick := fmt.Sprintf("%sValues[%sPick(r, %sWeights)]", varName, g.prefix, varName)
+	if col.IsCategorical || covered >= col.Count {
+		return pick
+	}
+
+	g.imports["fmt"] = true
+	return fmt.Sprintf("func() string {\n\tif r.Float64() < %s {\n\t\treturn %s\n\t}\n\treturn %s\n}()",
+		formatFloat(float64(covered)/float64(col.Count)), pick, synthetic)
+}
+
+var dateLayouts = []string{time.RFC3339, "2006-01-02", "01/02/2006"}
+
+// dateExpr spreads dates uniformly over the range of the column, in the
+// layout of its most frequent values. Profiles without datetime statistics
+// only bound the range by the top values, an approximation.
+func (g *generator) dateExpr(col *profiler.ColumnProfile) (string, bool) {
+	for _, layout := range dateLayouts {
+		var first, last time.Time
+		parsed := 0
+		for _, val := range col.TopValues {
+			t, err := time.Parse(layout, val.Value)
+			if err != nil {
+				break
+			}
+			if parsed == 0 || t.Before(first) {
+				first = t
+			}
+			if parsed == 0 || t.After(last) {
+				last = t
+			}
+			parsed++
+		}
+		if parsed == 0 || parsed < len(col.TopValues) {
+			continue
+		}
+
+		if col.DateTime != nil {
+			first, last = col.DateTime.Min, col.DateTime.Max
+		}
+
+		g.imports["time"] = true
+		seconds := int64(last.Sub(first).Seconds())
+		return fmt.Sprintf("time.Unix(%d+r.Int63n(%d), 0).UTC().Format(%q)", first.Unix(), seconds+1, layout), true
+	}
+	return "", false
+}
+
+func (g *generator) helperSource(name string) string {
+	switch name {
+	case "pick":
+		return fmt.Sprintf(`func %sPick(r *rand.Rand, weights []int) int {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	n := r.Intn(total)
+	for i, w := range weights {
+		if n < w {
+			return i
+		}
+		n -= w
+	}
+	return len(weights) - 1
+}
+`, g.prefix)
+	case "normal":
+		return fmt.Sprintf(`func %sNormal(r *rand.Rand, mean, stdDev, min, max float64) float64 {
+	v := mean + r.NormFloat64()*stdDev
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+`, g.prefix)
+	default:
+		return fmt.Sprintf(`func %sBytes(r *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	r.Read(b)
+	return b
+}
+`, g.prefix)
+	}
+}
+
+func intValues(col *profiler.ColumnProfile) ([]string, string, bool) {
+	if len(col.TopValues) == 0 {
+		return nil, "", false
+	}
+
+	values := make([]string, len(col.TopValues))
+	weights := make([]string, len(col.TopValues))
+	for i, val := range col.TopValues {
+		if _, err := strconv.ParseInt(val.Value, 10, 64); err != nil {
+			return nil, "", false
+		}
+		values[i] = val.Value
+		weights[i] = strconv.Itoa(val.Count)
+	}
+
+	return values, strings.Join(weights, ", "), true
+}
+
+func missingRate(col *profiler.ColumnProfile, rowCount int) float64 {
+	if rowCount == 0 {
+		return 0
+	}
+	return float64(col.MissingCount) / float64(rowCount)
+}
+
+func toFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	default:
+		return 0
+	}
+}
+
+func formatFloat(f float64) string {
+	s := strconv.FormatFloat(f, 'g', 10, 64)
+	if !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	return s
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func sanitizeLiteral(s string) string {
+	quoted := strconv.Quote(s)
+	return strings.ReplaceAll(quoted[1:len(quoted)-1], "%", "%%")
+}
+
+var initialisms = map[string]bool{
+	"ID": true, "URL": true, "URI": true, "API": true, "HTTP": true, "JSON": true,
+	"UUID": true, "IP": true, "SQL": true, "CSV": true, "HTML": true,
+}
+
+// exportedName turns a column name such as "order_id" into a Go identifier
+// such as "OrderID".
+func exportedName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, word := range words {
+		upper := strings.ToUpper(word)
+		if initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+
+	ident := b.String()
+	if ident == "" {
+		return "Column"
+	}
+	if unicode.IsDigit([]rune(ident)[0]) {
+		ident = "X" + ident
+	}
+	return ident
+}
+
+func unexportedName(name string) string {
+	runes := []rune(name)
+	i := 0
+	for i < len(runes) && unicode.IsUpper(runes[i]) {
+		i++
+	}
+	if i > 1 && i < len(runes) {
+		i--
+	}
+	for j := 0; j < i; j++ {
+		runes[j] = unicode.ToLower(runes[j])
+	}
+	return string(runes)
+}
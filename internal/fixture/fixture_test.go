@@ -0,0 +1,122 @@
+package fixture
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func createProfile() *profiler.DatasetProfile {
+	return &profiler.DatasetProfile{
+		Filename: "order-items.csv",
+		RowCount: 100,
+		Columns: map[string]*profiler.ColumnProfile{
+			"order_id": {Name: "order_id", DataType: "integer", Count: 100, UniqueCount: 100, IsUnique: true, Min: float64(1000)},
+			"status": {
+				Name: "status", DataType: "string", Count: 100, UniqueCount: 2, IsCategorical: true,
+				TopValues: []profiler.ValueCount{{Value: "paid", Count: 70}, {Value: "new \"draft\"", Count: 30}},
+			},
+			"amount": {Name: "amount", DataType: "float", Count: 90, MissingCount: 10, Mean: 50, StdDev: 10, Min: float64(1), Max: float64(99)},
+			"created": {
+				Name: "created", DataType: "datetime", Count: 100, UniqueCount: 80,
+				TopValues: []profiler.ValueCount{{Value: "2024-01-01", Count: 2}, {Value: "2024-03-01", Count: 2}},
+			},
+			"payload":  {Name: "payload", DataType: "blob", Count: 100, IsOpaque: true, AvgLength: 512},
+			"1st note": {Name: "1st note", DataType: "unknown", MissingCount: 100},
+		},
+	}
+}
+
+func typeCheck(t *testing.T, src []byte) *types.Package {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "fixture.go", src, 0)
+	if err != nil {
+		t.Fatalf("Generated code does not parse: %v\n%s", err, src)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("fixtures", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatalf("Generated code does not type check: %v\n%s", err, src)
+	}
+	return pkg
+}
+
+func TestGenerateGo(t *testing.T) {
+	src, err := GenerateGo(createProfile(), Options{})
+	if err != nil {
+		t.Fatalf("GenerateGo failed: %v", err)
+	}
+
+	pkg := typeCheck(t, src)
+
+	row := pkg.Scope().Lookup("OrderItemsRow")
+	if row == nil {
+		t.Fatalf("Expected struct OrderItemsRow in:\n%s", src)
+	}
+	if pkg.Scope().Lookup("GenerateOrderItemsRows") == nil {
+		t.Errorf("Expected generator function in:\n%s", src)
+	}
+
+	fields := make(map[string]string)
+	st := row.Type().Underlying().(*types.Struct)
+	for i := 0; i < st.NumFields(); i++ {
+		fields[st.Field(i).Name()] = st.Field(i).Type().String()
+	}
+
+	expected := map[string]string{
+		"OrderID":  "int64",
+		"Status":   "string",
+		"Amount":   "*float64",
+		"Created":  "string",
+		"Payload":  "[]byte",
+		"X1stNote": "*string",
+	}
+	for name, goType := range expected {
+		if fields[name] != goType {
+			t.Errorf("Expected field %s of type %s, got %q", name, goType, fields[name])
+		}
+	}
+
+	for _, expected := range []string{"int64(1000 + i)", `json:"order_id"`, `"new \"draft\""`} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("Expected generated code to contain %s", expected)
+		}
+	}
+}
+
+func TestGenerateGoOptions(t *testing.T) {
+	src, err := GenerateGo(createProfile(), Options{Package: "testdata", TypeName: "Order"})
+	if err != nil {
+		t.Fatalf("GenerateGo failed: %v", err)
+	}
+
+	pkg := typeCheck(t, src)
+	if pkg.Scope().Lookup("GenerateOrders") == nil || !strings.Contains(string(src), "package testdata") {
+		t.Errorf("Expected package testdata with GenerateOrders in:\n%s", src)
+	}
+}
+
+func TestExportedName(t *testing.T) {
+	tests := map[string]string{
+		"order_id":  "OrderID",
+		"user-name": "UserName",
+		"api url":   "APIURL",
+		"2fa":       "X2fa",
+		"__":        "Column",
+		"createdAt": "CreatedAt",
+	}
+	for name, want := range tests {
+		if got := exportedName(name); got != want {
+			t.Errorf("exportedName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
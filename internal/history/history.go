@@ -0,0 +1,404 @@
+package history
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	_ "modernc.org/sqlite"
+)
+
+// HomeEnv overrides the directory holding the history database.
+const HomeEnv = "DATASLEUTH_HOME"
+
+const schema = `CREATE TABLE IF NOT EXISTS runs (
+	id             INTEGER PRIMARY KEY AUTOINCREMENT,
+	dataset        TEXT    NOT NULL,
+	source         TEXT    NOT NULL,
+	created_at     TEXT    NOT NULL,
+	row_count      INTEGER NOT NULL,
+	column_count   INTEGER NOT NULL,
+	quality_score  INTEGER NOT NULL,
+	missing_cells  INTEGER NOT NULL,
+	duplicate_rows INTEGER NOT NULL,
+	parse_errors   INTEGER NOT NULL DEFAULT 0,
+	issues         INTEGER NOT NULL,
+	content_digest TEXT    NOT NULL,
+	sampled        INTEGER NOT NULL,
+	report         BLOB    NOT NULL
+);
+CREATE INDEX IF NOT EXISTS runs_dataset ON runs (dataset, id);
+CREATE TABLE IF NOT EXISTS slas (
+	run_id       INTEGER NOT NULL REFERENCES runs (id),
+	column_name  TEXT    NOT NULL,
+	target       REAL    NOT NULL,
+	actual       REAL    NOT NULL,
+	met          INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS slas_run ON slas (run_id);`
+
+// addedColumns are the columns of runs added after it was first created,
+// which older databases gain when opened.
+var addedColumns = []struct{ name, definition string }{
+	{"parse_errors", "INTEGER NOT NULL DEFAULT 0"},
+}
+
+// Run is one recorded profile of a dataset. The full JSON report is kept
+// alongside and read with Store.Report.
+type Run struct {
+	ID            int64
+	Dataset       string
+	Source        string
+	CreatedAt     time.Time
+	RowCount      int
+	ColumnCount   int
+	QualityScore  int
+	MissingCells  int
+	DuplicateRows int
+	ParseErrors   int // bad rows and values with a parse error
+	Issues        int
+	ContentDigest string
+	Sampled       bool
+}
+
+// SLA is the attainment of a completeness SLA of a column in a recorded run.
+type SLA struct {
+	RunID  int64
+	Column string
+	Target float64 // percent of values present
+	Actual float64
+	Met    bool
+}
+
+// Dataset summarizes the runs recorded for one dataset.
+type Dataset struct {
+	Name    string
+	Runs    int
+	LastRun time.Time
+	Latest  Run
+}
+
+// Store is the SQLite database of profile runs.
+type Store struct {
+	db *sql.DB
+}
+
+// DefaultPath is history.db in $DATASLEUTH_HOME, or in ~/.datasleuth.
+func DefaultPath() (string, error) {
+	if dir := os.Getenv(HomeEnv); dir != "" {
+		return filepath.Join(dir, "history.db"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".datasleuth", "history.db"), nil
+}
+
+// Open opens the history database at path, creating it when needed.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database: %w", err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize history database: %w", err)
+	}
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to upgrade history database: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// migrate adds the columns a database created by an earlier version lacks.
+func migrate(db *sql.DB) error {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info('runs')`)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, col := range addedColumns {
+		if existing[col.name] {
+			continue
+		}
+		if _, err := db.Exec(`ALTER TABLE runs ADD COLUMN ` + col.name + ` ` + col.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// NewRun summarizes profile as a run of dataset.
+func NewRun(dataset string, profile *profiler.DatasetProfile) Run {
+	createdAt := profile.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	return Run{
+		Dataset:       dataset,
+		Source:        profile.Filename,
+		CreatedAt:     createdAt.UTC(),
+		RowCount:      profile.RowCount,
+		ColumnCount:   profile.ColumnCount,
+		QualityScore:  profile.QualityScore,
+		MissingCells:  profile.MissingCells,
+		DuplicateRows: profile.DuplicateRows,
+		ParseErrors:   profile.ParseBudget().Total,
+		Issues:        len(profile.QualityIssues),
+		ContentDigest: profile.ContentDigest,
+		Sampled:       profile.SampleStrategy != "",
+	}
+}
+
+// Record stores run with its JSON report and returns the run's ID.
+func (s *Store) Record(run Run, report []byte) (int64, error) {
+	result, err := s.db.Exec(`INSERT INTO runs (dataset, source, created_at, row_count, column_count, quality_score,
+		missing_cells, duplicate_rows, parse_errors, issues, content_digest, sampled, report) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		run.Dataset, run.Source, run.CreatedAt.UTC().Format(time.RFC3339Nano), run.RowCount, run.ColumnCount, run.QualityScore,
+		run.MissingCells, run.DuplicateRows, run.ParseErrors, run.Issues, run.ContentDigest, run.Sampled, report)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record run: %w", err)
+	}
+
+	return result.LastInsertId()
+}
+
+// RecordSLAs stores the SLA attainment measured in the run of the given ID.
+func (s *Store) RecordSLAs(runID int64, slas []SLA) error {
+	for _, sla := range slas {
+		if _, err := s.db.Exec(`INSERT INTO slas (run_id, column_name, target, actual, met) VALUES (?, ?, ?, ?, ?)`,
+			runID, sla.Column, sla.Target, sla.Actual, sla.Met); err != nil {
+			return fmt.Errorf("failed to record SLAs: %w", err)
+		}
+	}
+	return nil
+}
+
+// SLAs returns the SLA attainment recorded with runs, by run and then in
+// the order recorded.
+func (s *Store) SLAs(runs []Run) ([]SLA, error) {
+	slas := make([]SLA, 0)
+	if len(runs) == 0 {
+		return slas, nil
+	}
+
+	rows, err := s.db.Query(`SELECT slas.run_id, slas.column_name, slas.target, slas.actual, slas.met FROM slas
+		JOIN runs ON runs.id = slas.run_id WHERE runs.dataset = ? AND slas.run_id BETWEEN ? AND ?
+		ORDER BY slas.run_id, slas.rowid`, runs[0].Dataset, runs[0].ID, runs[len(runs)-1].ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SLAs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sla SLA
+		if err := rows.Scan(&sla.RunID, &sla.Column, &sla.Target, &sla.Actual, &sla.Met); err != nil {
+			return nil, fmt.Errorf("failed to read SLA: %w", err)
+		}
+		slas = append(slas, sla)
+	}
+	return slas, rows.Err()
+}
+
+const runColumns = `id, dataset, source, created_at, row_count, column_count, quality_score,
+	missing_cells, duplicate_rows, parse_errors, issues, content_digest, sampled`
+
+// Runs returns the latest limit runs of dataset, oldest first. A limit of 0
+// returns every run.
+func (s *Store) Runs(dataset string, limit int) ([]Run, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+
+	rows, err := s.db.Query(`SELECT * FROM (SELECT `+runColumns+` FROM runs WHERE dataset = ? ORDER BY id DESC LIMIT ?)
+		ORDER BY id`, dataset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := make([]Run, 0)
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// Run returns the run with the given ID.
+func (s *Store) Run(id int64) (Run, error) {
+	run, err := scanRun(s.db.QueryRow(`SELECT `+runColumns+` FROM runs WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return Run{}, fmt.Errorf("no run with ID %d", id)
+	}
+	return run, err
+}
+
+// Report returns the JSON report recorded with the run of the given ID.
+func (s *Store) Report(id int64) ([]byte, error) {
+	var report []byte
+	err := s.db.QueryRow(`SELECT report FROM runs WHERE id = ?`, id).Scan(&report)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("no run with ID %d", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run %d: %w", id, err)
+	}
+
+	return report, nil
+}
+
+// Datasets lists every dataset with recorded runs, most recently profiled
+// first.
+func (s *Store) Datasets() ([]Dataset, error) {
+	rows, err := s.db.Query(`SELECT dataset, COUNT(*), MAX(id) FROM runs GROUP BY dataset ORDER BY MAX(id) DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list datasets: %w", err)
+	}
+
+	datasets := make([]Dataset, 0)
+	latest := make([]int64, 0)
+	for rows.Next() {
+		var dataset Dataset
+		var id int64
+		if err := rows.Scan(&dataset.Name, &dataset.Runs, &id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to list datasets: %w", err)
+		}
+		datasets = append(datasets, dataset)
+		latest = append(latest, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list datasets: %w", err)
+	}
+
+	for i, id := range latest {
+		run, err := s.Run(id)
+		if err != nil {
+			return nil, err
+		}
+		datasets[i].Latest = run
+		datasets[i].LastRun = run.CreatedAt
+	}
+
+	return datasets, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRun(row scanner) (Run, error) {
+	var run Run
+	var createdAt string
+	err := row.Scan(&run.ID, &run.Dataset, &run.Source, &createdAt, &run.RowCount, &run.ColumnCount, &run.QualityScore,
+		&run.MissingCells, &run.DuplicateRows, &run.ParseErrors, &run.Issues, &run.ContentDigest, &run.Sampled)
+	if err == sql.ErrNoRows {
+		return Run{}, err
+	}
+	if err != nil {
+		return Run{}, fmt.Errorf("failed to read run: %w", err)
+	}
+
+	run.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return Run{}, fmt.Errorf("failed to read run %d: %w", run.ID, err)
+	}
+
+	return run, nil
+}
+
+// DatasetKey names the dataset a source is recorded under: the absolute path
+// of a local file, or the URL without credentials of a remote one, followed
+// by #table when a single table or sheet was profiled.
+func DatasetKey(source, table string) string {
+	key := source
+	if strings.Contains(source, "://") {
+		if u, err := url.Parse(source); err == nil {
+			key = u.Redacted()
+		}
+	} else if abs, err := filepath.Abs(source); err == nil {
+		key = abs
+	}
+
+	if table != "" {
+		key += "#" + table
+	}
+	return key
+}
+
+// Resolve finds the recorded dataset matching name: an exact dataset key,
+// the key of a source path, or a unique suffix such as a file name.
+func (s *Store) Resolve(name, table string) (string, error) {
+	datasets, err := s.Datasets()
+	if err != nil {
+		return "", err
+	}
+
+	candidates := []string{name, DatasetKey(name, table)}
+	if table != "" {
+		candidates[0] = name + "#" + table
+	}
+	for _, candidate := range candidates {
+		for _, dataset := range datasets {
+			if dataset.Name == candidate {
+				return dataset.Name, nil
+			}
+		}
+	}
+
+	suffix := name
+	if table != "" {
+		suffix += "#" + table
+	}
+	matches := make([]string, 0)
+	for _, dataset := range datasets {
+		if strings.HasSuffix(dataset.Name, "/"+suffix) {
+			matches = append(matches, dataset.Name)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no runs recorded for %s", suffix)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%s matches %d datasets: %s", suffix, len(matches), strings.Join(matches, ", "))
+	}
+}
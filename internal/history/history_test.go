@@ -0,0 +1,184 @@
+package history
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestRecordAndListRuns(t *testing.T) {
+	store := openTestStore(t)
+
+	for i, rows := range []int{100, 120, 150} {
+		profile := &profiler.DatasetProfile{
+			Filename:      "orders.csv",
+			RowCount:      rows,
+			ColumnCount:   4,
+			QualityScore:  90 - i,
+			ContentDigest: "digest",
+			CreatedAt:     time.Date(2026, 1, i+1, 0, 0, 0, 0, time.UTC),
+		}
+		if _, err := store.Record(NewRun("/data/orders.csv", profile), []byte(`{}`)); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+	if _, err := store.Record(NewRun("/data/users.csv", &profiler.DatasetProfile{Filename: "users.csv"}), []byte(`{}`)); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	runs, err := store.Runs("/data/orders.csv", 2)
+	if err != nil {
+		t.Fatalf("Runs failed: %v", err)
+	}
+	if len(runs) != 2 || runs[0].RowCount != 120 || runs[1].RowCount != 150 {
+		t.Fatalf("expected the latest two runs oldest first, got %+v", runs)
+	}
+	if !runs[1].CreatedAt.Equal(time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)) || runs[1].QualityScore != 88 {
+		t.Errorf("run not read back as recorded: %+v", runs[1])
+	}
+
+	all, err := store.Runs("/data/orders.csv", 0)
+	if err != nil || len(all) != 3 {
+		t.Fatalf("expected 3 runs without a limit, got %d (%v)", len(all), err)
+	}
+
+	datasets, err := store.Datasets()
+	if err != nil {
+		t.Fatalf("Datasets failed: %v", err)
+	}
+	if len(datasets) != 2 || datasets[0].Name != "/data/users.csv" || datasets[1].Runs != 3 || datasets[1].Latest.RowCount != 150 {
+		t.Errorf("unexpected datasets: %+v", datasets)
+	}
+
+	report, err := store.Report(runs[0].ID)
+	if err != nil || string(report) != `{}` {
+		t.Errorf("expected the recorded report, got %q (%v)", report, err)
+	}
+	if _, err := store.Report(999); err == nil {
+		t.Error("expected an error for an unknown run")
+	}
+}
+
+func TestParseErrorsMigration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+
+	// A database from before parse errors were recorded
+	db, err := sql.Open("sqlite", "file:"+path)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	_, err = db.Exec(`CREATE TABLE runs (id INTEGER PRIMARY KEY AUTOINCREMENT, dataset TEXT NOT NULL, source TEXT NOT NULL,
+		created_at TEXT NOT NULL, row_count INTEGER NOT NULL, column_count INTEGER NOT NULL, quality_score INTEGER NOT NULL,
+		missing_cells INTEGER NOT NULL, duplicate_rows INTEGER NOT NULL, issues INTEGER NOT NULL, content_digest TEXT NOT NULL,
+		sampled INTEGER NOT NULL, report BLOB NOT NULL);
+		INSERT INTO runs (dataset, source, created_at, row_count, column_count, quality_score, missing_cells, duplicate_rows,
+		issues, content_digest, sampled, report) VALUES ('/data/orders.csv', 'orders.csv', '2026-01-01T00:00:00Z', 10, 2, 90, 0, 0, 0, '', 0, '{}')`)
+	db.Close()
+	if err != nil {
+		t.Fatalf("Failed to create old database: %v", err)
+	}
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer store.Close()
+
+	profile := &profiler.DatasetProfile{Filename: "orders.csv", RowCount: 12, ColumnCount: 2, BadRows: 2,
+		Columns: map[string]*profiler.ColumnProfile{"amount": {ParseErrors: profiler.ParseErrors{Unparseable: 3}}}}
+	if _, err := store.Record(NewRun("/data/orders.csv", profile), []byte(`{}`)); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	runs, err := store.Runs("/data/orders.csv", 0)
+	if err != nil {
+		t.Fatalf("Runs failed: %v", err)
+	}
+	if len(runs) != 2 || runs[0].ParseErrors != 0 || runs[1].ParseErrors != 5 {
+		t.Errorf("Expected 0 parse errors for the old run and 5 for the new one, got %+v", runs)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	store := openTestStore(t)
+	for _, dataset := range []string{"/data/a/orders.csv", "/data/b/orders.csv", "/data/users.csv", "/data/app.db#items"} {
+		if _, err := store.Record(NewRun(dataset, &profiler.DatasetProfile{}), []byte(`{}`)); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name, table, want string
+		wantErr           bool
+	}{
+		{name: "/data/users.csv", want: "/data/users.csv"},
+		{name: "users.csv", want: "/data/users.csv"},
+		{name: "app.db", table: "items", want: "/data/app.db#items"},
+		{name: "orders.csv", wantErr: true},
+		{name: "b/orders.csv", want: "/data/b/orders.csv"},
+		{name: "missing.csv", wantErr: true},
+	}
+	for _, tc := range tests {
+		got, err := store.Resolve(tc.name, tc.table)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Errorf("Resolve(%q, %q) = %q, %v; want %q", tc.name, tc.table, got, err, tc.want)
+		}
+	}
+}
+
+func TestDatasetKey(t *testing.T) {
+	if got := DatasetKey("s3://user:secret@bucket/data.csv", ""); got != "s3://user:xxxxx@bucket/data.csv" {
+		t.Errorf("expected credentials redacted, got %s", got)
+	}
+	if got := DatasetKey("/tmp/app.db", "orders"); got != "/tmp/app.db#orders" {
+		t.Errorf("expected table suffix, got %s", got)
+	}
+	if got := DatasetKey("data.csv", ""); !filepath.IsAbs(got) {
+		t.Errorf("expected an absolute path, got %s", got)
+	}
+}
+
+func TestRecordSLAs(t *testing.T) {
+	store := openTestStore(t)
+
+	var runs []Run
+	for i := 0; i < 3; i++ {
+		run := NewRun("/data/orders.csv", &profiler.DatasetProfile{Filename: "orders.csv"})
+		id, err := store.Record(run, []byte(`{}`))
+		if err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+		if i > 0 {
+			if err := store.RecordSLAs(id, []SLA{{RunID: id, Column: "email", Target: 98, Actual: 97 + float64(i), Met: i == 2}}); err != nil {
+				t.Fatalf("RecordSLAs failed: %v", err)
+			}
+		}
+		run.ID = id
+		runs = append(runs, run)
+	}
+
+	slas, err := store.SLAs(runs[1:])
+	if err != nil {
+		t.Fatalf("SLAs failed: %v", err)
+	}
+	if len(slas) != 2 || slas[0].RunID != runs[1].ID || slas[0].Met || !slas[1].Met || slas[1].Actual != 99 {
+		t.Errorf("SLAs not read back as recorded: %+v", slas)
+	}
+
+	if slas, err := store.SLAs(runs[:1]); err != nil || len(slas) != 0 {
+		t.Errorf("Expected no SLAs for the first run, got %+v (%v)", slas, err)
+	}
+}
@@ -0,0 +1,38 @@
+package history
+
+import (
+	"github.com/kamalm96/datasleuth/internal/compare"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// Change is what happened to the columns of a dataset between two
+// consecutive runs.
+type Change struct {
+	From        Run
+	To          Run
+	Renames     []compare.Rename
+	Cardinality []compare.CardinalityAlert
+}
+
+// Changes compares each pair of consecutive runs, oldest first, given the
+// profile recorded with each run, and returns the pairs with probable column
+// renames or cardinality explosions.
+func Changes(runs []Run, profiles []*profiler.DatasetProfile) []Change {
+	changes := make([]Change, 0)
+
+	for i := 1; i < len(runs) && i < len(profiles); i++ {
+		diff := compare.Compare(profiles[i-1], profiles[i], compare.DefaultOptions())
+		if len(diff.Renames) == 0 && len(diff.Cardinality) == 0 {
+			continue
+		}
+
+		changes = append(changes, Change{
+			From:        runs[i-1],
+			To:          runs[i],
+			Renames:     diff.Renames,
+			Cardinality: diff.Cardinality,
+		})
+	}
+
+	return changes
+}
@@ -0,0 +1,296 @@
+// Package k8s generates Kubernetes manifests that run DataSleuth on a
+// schedule inside a cluster.
+package k8s
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/kamalm96/datasleuth/internal/remote"
+)
+
+// DefaultSecret is the Secret the credentials of a source are read from.
+const DefaultSecret = "datasleuth-credentials"
+
+// gcpCredentialsDir is where the service account key of a gs:// source is
+// mounted.
+const gcpCredentialsDir = "/var/run/secrets/datasleuth/gcp"
+
+type Options struct {
+	Source    string
+	Schedule  string   // cron schedule, e.g. "0 2 * * *", or a macro such as @daily
+	Name      string   // CronJob name, derived from the source when empty
+	Namespace string   // left out when empty
+	Image     string   // image with the datasleuth binary on its PATH
+	Secret    string   // DefaultSecret when empty
+	Size      int64    // estimated size of the source in bytes, -1 when unknown
+	Args      []string // further flags of the profile command
+}
+
+// Resources are the requests and limits of the container.
+type Resources struct {
+	CPU         string
+	Memory      string
+	MemoryLimit string
+}
+
+// EstimateResources sizes the container for a source of size bytes. The
+// profiler streams its input and keeps sketches of bounded size, so memory
+// grows with the size class rather than with the data; bigger sources get
+// more room for the correlation sample and exact value counts, and a full
+// core to keep the run short. Unknown sizes get the middle class.
+func EstimateResources(size int64) Resources {
+	switch {
+	case size < 0:
+		return Resources{CPU: "500m", Memory: "512Mi", MemoryLimit: "1Gi"}
+	case size < 100<<20:
+		return Resources{CPU: "250m", Memory: "256Mi", MemoryLimit: "512Mi"}
+	case size < 1<<30:
+		return Resources{CPU: "500m", Memory: "512Mi", MemoryLimit: "1Gi"}
+	case size < 10<<30:
+		return Resources{CPU: "1", Memory: "1Gi", MemoryLimit: "2Gi"}
+	default:
+		return Resources{CPU: "2", Memory: "2Gi", MemoryLimit: "4Gi"}
+	}
+}
+
+// secretEnv is an environment variable read from a key of the Secret.
+type secretEnv struct {
+	Name     string
+	Optional bool
+}
+
+// credentials are the environment variables, and for GCS the mounted key
+// file, that hold the credentials of the provider of source.
+func credentials(source string) (env []secretEnv, gcpKey bool) {
+	scheme, _, _ := strings.Cut(source, "://")
+	switch strings.ToLower(scheme) {
+	case "s3":
+		return []secretEnv{
+			{Name: "AWS_ACCESS_KEY_ID"},
+			{Name: "AWS_SECRET_ACCESS_KEY"},
+			{Name: "AWS_SESSION_TOKEN", Optional: true},
+			{Name: "AWS_REGION", Optional: true},
+		}, false
+	case "gs":
+		return nil, true
+	case "az":
+		return []secretEnv{
+			{Name: "AZURE_STORAGE_SAS_TOKEN", Optional: true},
+			{Name: "AZURE_STORAGE_KEY", Optional: true},
+		}, false
+	}
+	return nil, false
+}
+
+// SecretKeys lists the keys the Secret must hold for source, with optional
+// ones marked.
+func SecretKeys(source string) []string {
+	env, gcpKey := credentials(source)
+	var keys []string
+	for _, e := range env {
+		if e.Optional {
+			keys = append(keys, e.Name+" (optional)")
+		} else {
+			keys = append(keys, e.Name)
+		}
+	}
+	if gcpKey {
+		keys = append(keys, "credentials.json")
+	}
+	return keys
+}
+
+var (
+	cronField   = regexp.MustCompile(`^[0-9A-Za-z*/,\-?]+$`)
+	invalidName = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+// validSchedule accepts five cron fields or a macro such as @daily.
+func validSchedule(schedule string) bool {
+	if strings.HasPrefix(schedule, "@") {
+		return len(schedule) > 1
+	}
+	fields := strings.Fields(schedule)
+	if len(fields) != 5 {
+		return false
+	}
+	for _, field := range fields {
+		if !cronField.MatchString(field) {
+			return false
+		}
+	}
+	return true
+}
+
+// jobName derives a CronJob name from the object name of source: lower
+// case, extensions dropped, and short enough for the suffix Kubernetes adds
+// to the names of the jobs.
+func jobName(source string) string {
+	base := path.Base(remote.Path(source))
+	if i := strings.Index(base, "."); i > 0 {
+		base = base[:i]
+	}
+	name := "datasleuth-" + strings.Trim(invalidName.ReplaceAllString(strings.ToLower(base), "-"), "-")
+	if len(name) > 52 {
+		name = name[:52]
+	}
+	return strings.TrimRight(name, "-")
+}
+
+// CronJob renders a CronJob manifest that profiles the source on the
+// schedule, with the credentials of its provider read from the Secret and
+// resources sized for the source.
+func CronJob(opts Options) ([]byte, error) {
+	if !remote.IsURL(opts.Source) {
+		return nil, fmt.Errorf("source must be a URL the cluster can reach (s3://, gs://, az://, https://), got %q", opts.Source)
+	}
+	if !validSchedule(opts.Schedule) {
+		return nil, fmt.Errorf("invalid schedule %q: expected five cron fields such as \"0 2 * * *\" or a macro such as @daily", opts.Schedule)
+	}
+	if opts.Image == "" {
+		return nil, fmt.Errorf("an image is required")
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = jobName(opts.Source)
+	}
+	secret := opts.Secret
+	if secret == "" {
+		secret = DefaultSecret
+	}
+
+	// The JSON report goes to stdout and so to the job logs; a report file or
+	// the history would not outlive the pod
+	args := append([]string{"profile", opts.Source, "--output", "json", "--output-file", "-", "--quiet", "--no-history", "--no-progress"}, opts.Args...)
+	env, gcpKey := credentials(opts.Source)
+
+	var buf bytes.Buffer
+	err := cronJobTemplate.Execute(&buf, struct {
+		Name, Namespace, Schedule, Image, Secret string
+		Source, Size, SecretKeys                 string
+		Args                                     []string
+		Env                                      []secretEnv
+		GCPKey                                   bool
+		GCPDir                                   string
+		Resources                                Resources
+	}{
+		Name:       name,
+		Namespace:  opts.Namespace,
+		Schedule:   opts.Schedule,
+		Image:      opts.Image,
+		Secret:     secret,
+		Source:     opts.Source,
+		Size:       formatSize(opts.Size),
+		SecretKeys: strings.Join(SecretKeys(opts.Source), ", "),
+		Args:       args,
+		Env:        env,
+		GCPKey:     gcpKey,
+		GCPDir:     gcpCredentialsDir,
+		Resources:  EstimateResources(opts.Size),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render manifest: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func formatSize(size int64) string {
+	switch {
+	case size < 0:
+		return "unknown"
+	case size >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", float64(size)/(1<<30))
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(size)/(1<<20))
+	}
+	return fmt.Sprintf("%d bytes", size)
+}
+
+// quote writes s as a double-quoted YAML scalar, which JSON strings are.
+func quote(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+var cronJobTemplate = template.Must(template.New("cronjob").Funcs(template.FuncMap{"quote": quote}).Parse(`# Profiles {{.Source}} on the schedule {{quote .Schedule}}.
+# Estimated source size: {{.Size}}; resources are sized for it.
+{{- if .SecretKeys}}
+# Credentials come from the Secret {{.Secret}}, with the keys {{.SecretKeys}}.
+{{- end}}
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: {{.Name}}
+{{- if .Namespace}}
+  namespace: {{.Namespace}}
+{{- end}}
+  labels:
+    app.kubernetes.io/name: datasleuth
+    app.kubernetes.io/instance: {{.Name}}
+spec:
+  schedule: {{quote .Schedule}}
+  concurrencyPolicy: Forbid
+  successfulJobsHistoryLimit: 3
+  failedJobsHistoryLimit: 3
+  jobTemplate:
+    spec:
+      backoffLimit: 2
+      template:
+        metadata:
+          labels:
+            app.kubernetes.io/name: datasleuth
+            app.kubernetes.io/instance: {{.Name}}
+        spec:
+          restartPolicy: Never
+          containers:
+            - name: datasleuth
+              image: {{quote .Image}}
+              command: ["datasleuth"]
+              args:
+{{- range .Args}}
+                - {{quote .}}
+{{- end}}
+{{- if or .Env .GCPKey}}
+              env:
+{{- range .Env}}
+                - name: {{.Name}}
+                  valueFrom:
+                    secretKeyRef:
+                      name: {{$.Secret}}
+                      key: {{.Name}}
+{{- if .Optional}}
+                      optional: true
+{{- end}}
+{{- end}}
+{{- if .GCPKey}}
+                - name: GOOGLE_APPLICATION_CREDENTIALS
+                  value: {{.GCPDir}}/credentials.json
+{{- end}}
+{{- end}}
+              resources:
+                requests:
+                  cpu: {{quote .Resources.CPU}}
+                  memory: {{.Resources.Memory}}
+                limits:
+                  memory: {{.Resources.MemoryLimit}}
+{{- if .GCPKey}}
+              volumeMounts:
+                - name: gcp-credentials
+                  mountPath: {{.GCPDir}}
+                  readOnly: true
+          volumes:
+            - name: gcp-credentials
+              secret:
+                secretName: {{.Secret}}
+                items:
+                  - key: credentials.json
+                    path: credentials.json
+{{- end}}
+`))
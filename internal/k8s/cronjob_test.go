@@ -0,0 +1,110 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCronJob(t *testing.T) {
+	manifest, err := CronJob(Options{
+		Source:    "s3://bucket/exports/Daily Orders.csv.gz",
+		Schedule:  "0 2 * * *",
+		Namespace: "data",
+		Image:     "registry.example.com/datasleuth:1.0",
+		Size:      3 << 30,
+		Args:      []string{"--max-severity", "3"},
+	})
+	if err != nil {
+		t.Fatalf("CronJob failed: %v", err)
+	}
+	yaml := string(manifest)
+
+	for _, want := range []string{
+		"kind: CronJob",
+		"  name: datasleuth-daily-orders\n  namespace: data\n",
+		`  schedule: "0 2 * * *"`,
+		"              args:\n" +
+			"                - \"profile\"\n" +
+			"                - \"s3://bucket/exports/Daily Orders.csv.gz\"\n" +
+			"                - \"--output\"\n                - \"json\"\n" +
+			"                - \"--output-file\"\n                - \"-\"\n" +
+			"                - \"--quiet\"\n" +
+			"                - \"--no-history\"\n" +
+			"                - \"--no-progress\"\n" +
+			"                - \"--max-severity\"\n                - \"3\"\n",
+		"                - name: AWS_SECRET_ACCESS_KEY\n                  valueFrom:\n                    secretKeyRef:\n                      name: datasleuth-credentials\n                      key: AWS_SECRET_ACCESS_KEY\n                - name: AWS_SESSION_TOKEN",
+		"                      key: AWS_SESSION_TOKEN\n                      optional: true\n",
+		"                  cpu: \"1\"\n                  memory: 1Gi\n",
+		"# Estimated source size: 3.0 GiB",
+	} {
+		if !strings.Contains(yaml, want) {
+			t.Errorf("Expected the manifest to contain %q, got:\n%s", want, yaml)
+		}
+	}
+	if strings.Contains(yaml, "volumes:") {
+		t.Error("Expected no volumes for an S3 source")
+	}
+}
+
+func TestCronJobGCS(t *testing.T) {
+	manifest, err := CronJob(Options{Source: "gs://bucket/events.parquet", Schedule: "@daily", Image: "datasleuth", Secret: "gcp-sa", Size: -1})
+	if err != nil {
+		t.Fatalf("CronJob failed: %v", err)
+	}
+	yaml := string(manifest)
+
+	for _, want := range []string{
+		"value: /var/run/secrets/datasleuth/gcp/credentials.json",
+		"secretName: gcp-sa",
+		"mountPath: /var/run/secrets/datasleuth/gcp",
+		"memory: 512Mi",
+		"Estimated source size: unknown",
+	} {
+		if !strings.Contains(yaml, want) {
+			t.Errorf("Expected the manifest to contain %q, got:\n%s", want, yaml)
+		}
+	}
+	if strings.Contains(yaml, "namespace:") {
+		t.Error("Expected no namespace when none is given")
+	}
+}
+
+func TestCronJobInvalid(t *testing.T) {
+	for _, opts := range []Options{
+		{Source: "data.csv", Schedule: "0 2 * * *", Image: "datasleuth"},
+		{Source: "s3://bucket/data.csv", Schedule: "every day", Image: "datasleuth"},
+		{Source: "s3://bucket/data.csv", Schedule: "0 2 * *", Image: "datasleuth"},
+		{Source: "s3://bucket/data.csv", Schedule: "0 2 * * *"},
+	} {
+		if _, err := CronJob(opts); err == nil {
+			t.Errorf("Expected an error for %+v", opts)
+		}
+	}
+}
+
+func TestEstimateResources(t *testing.T) {
+	for _, tc := range []struct {
+		size   int64
+		memory string
+	}{
+		{-1, "512Mi"},
+		{10 << 20, "256Mi"},
+		{500 << 20, "512Mi"},
+		{5 << 30, "1Gi"},
+		{50 << 30, "2Gi"},
+	} {
+		if got := EstimateResources(tc.size).Memory; got != tc.memory {
+			t.Errorf("EstimateResources(%d): expected %s, got %s", tc.size, tc.memory, got)
+		}
+	}
+}
+
+func TestJobName(t *testing.T) {
+	long := "s3://bucket/" + strings.Repeat("x", 80) + ".csv"
+	if name := jobName(long); len(name) > 52 {
+		t.Errorf("Expected at most 52 characters, got %d", len(name))
+	}
+	if name := jobName("https://example.com/exports/__Q1_report__.jsonl?sig=abc"); name != "datasleuth-q1-report" {
+		t.Errorf("Unexpected name %q", name)
+	}
+}
@@ -0,0 +1,102 @@
+package mask
+
+import (
+	"github.com/kamalm96/datasleuth/internal/compare"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// joinableShare is the share of its distinct values a masked column keeps
+// apart for joins on it to still match one to one, allowing for distinct
+// counts estimated above 10,000 values.
+const joinableShare = 0.99
+
+// Impact is what a masking plan does to a dataset: how the statistics of each
+// masked column change, and how many rows it makes indistinguishable.
+type Impact struct {
+	Source        string
+	Rows          int
+	OldDuplicates int // duplicate rows before masking
+	NewDuplicates int // duplicate rows after, including rows masking made identical
+	Columns       []ColumnImpact
+}
+
+// ColumnImpact compares a column before and after masking.
+type ColumnImpact struct {
+	Mask        ColumnMask
+	OldType     string
+	NewType     string
+	OldDistinct int
+	NewDistinct int
+	Skipped     int                // values left as they were, not numbers
+	Diff        compare.ColumnDiff // distributions before and after
+}
+
+// Measure compares the profile of a dataset with the profile of its masked
+// copy, column by column in the order of the plan.
+func Measure(original, masked *profiler.DatasetProfile, masker *Masker) *Impact {
+	comparison := compare.Compare(original, masked, compare.DefaultOptions())
+	diffs := make(map[string]compare.ColumnDiff, len(comparison.Columns))
+	for _, diff := range comparison.Columns {
+		diffs[diff.Name] = diff
+	}
+
+	impact := &Impact{
+		Source:        original.Filename,
+		Rows:          original.RowCount,
+		OldDuplicates: original.DuplicateRows,
+		NewDuplicates: masked.DuplicateRows,
+		Columns:       make([]ColumnImpact, 0, len(masker.plan.Columns)),
+	}
+	for _, m := range masker.plan.Columns {
+		oldCol, newCol := original.Columns[m.Name], masked.Columns[m.Name]
+		if oldCol == nil || newCol == nil {
+			continue
+		}
+		impact.Columns = append(impact.Columns, ColumnImpact{
+			Mask:        m,
+			OldType:     oldCol.DataType,
+			NewType:     newCol.DataType,
+			OldDistinct: oldCol.UniqueCount,
+			NewDistinct: newCol.UniqueCount,
+			Skipped:     masker.Skipped(m.Name),
+			Diff:        diffs[m.Name],
+		})
+	}
+	return impact
+}
+
+// Kept is the share of the distinct values of the column that masking keeps
+// apart, 1 when it maps values one to one.
+func (c ColumnImpact) Kept() float64 {
+	if c.OldDistinct == 0 {
+		return 1
+	}
+	return min(float64(c.NewDistinct)/float64(c.OldDistinct), 1)
+}
+
+// Collapsed is the share of the distinct values of the column merged into
+// others by masking: the utility lost for grouping, counting and joining.
+func (c ColumnImpact) Collapsed() float64 {
+	return 1 - c.Kept()
+}
+
+// Joinable reports whether rows still join on the masked column, one to one,
+// with another dataset masked the same way.
+func (c ColumnImpact) Joinable() bool {
+	return c.Kept() >= joinableShare
+}
+
+// Replaced reports whether masking replaces the values of the column with
+// others, so that their distribution is no longer comparable.
+func (c ColumnImpact) Replaced() bool {
+	return c.Mask.Technique == TechniqueHash || c.Mask.Technique == TechniqueRedact
+}
+
+// StdDevChange is the relative change in the standard deviation of a numeric
+// column, positive when masking broadens its distribution.
+func (c ColumnImpact) StdDevChange() (float64, bool) {
+	if !c.Diff.IsNumeric || c.Diff.OldStdDev == 0 {
+		return 0, false
+	}
+	return c.Diff.NewStdDev/c.Diff.OldStdDev - 1, true
+}
@@ -0,0 +1,57 @@
+package mask
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func profileOf(duplicates int, columns ...*profiler.ColumnProfile) *profiler.DatasetProfile {
+	profile := &profiler.DatasetProfile{Filename: "data.csv", RowCount: 100, DuplicateRows: duplicates, Columns: make(map[string]*profiler.ColumnProfile)}
+	for _, col := range columns {
+		col.Count = 100
+		profile.Columns[col.Name] = col
+	}
+	return profile
+}
+
+func TestMeasure(t *testing.T) {
+	plan := &Plan{Columns: []ColumnMask{
+		{Name: "zip", Technique: TechniqueTruncate, Keep: 2},
+		{Name: "email", Technique: TechniqueHash},
+		{Name: "salary", Technique: TechniqueNoise, Scale: 10},
+	}}
+	masker, err := New(&bytes.Buffer{}, plan)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	masker.skipped["salary"] = 3
+
+	original := profileOf(0,
+		&profiler.ColumnProfile{Name: "zip", DataType: "string", UniqueCount: 80},
+		&profiler.ColumnProfile{Name: "email", DataType: "string", UniqueCount: 100},
+		&profiler.ColumnProfile{Name: "salary", DataType: "integer", IsNumeric: true, UniqueCount: 90, Mean: 50, StdDev: 20},
+	)
+	masked := profileOf(6,
+		&profiler.ColumnProfile{Name: "zip", DataType: "string", UniqueCount: 20},
+		&profiler.ColumnProfile{Name: "email", DataType: "string", UniqueCount: 100},
+		&profiler.ColumnProfile{Name: "salary", DataType: "integer", IsNumeric: true, UniqueCount: 90, Mean: 50, StdDev: 25},
+	)
+
+	impact := Measure(original, masked, masker)
+	if impact.Rows != 100 || impact.OldDuplicates != 0 || impact.NewDuplicates != 6 || len(impact.Columns) != 3 {
+		t.Fatalf("Unexpected impact: %+v", impact)
+	}
+
+	zip, email, salary := impact.Columns[0], impact.Columns[1], impact.Columns[2]
+	if zip.Mask.Name != "zip" || zip.Collapsed() != 0.75 || zip.Joinable() || zip.Replaced() {
+		t.Errorf("Expected 75%% of the zip codes collapsed, breaking joins, got %+v", zip)
+	}
+	if !email.Joinable() || email.Collapsed() != 0 || !email.Replaced() {
+		t.Errorf("Expected hashed emails to keep joins, got %+v", email)
+	}
+	if change, ok := salary.StdDevChange(); !ok || change != 0.25 || salary.Skipped != 3 {
+		t.Errorf("Expected the salary spread to broaden by 25%% with 3 values skipped, got %v and %d", change, salary.Skipped)
+	}
+}
@@ -0,0 +1,302 @@
+// Package mask applies a masking plan to the rows of a dataset, so that the
+// profiles of the data before and after masking can be compared to see what
+// the masking costs in utility.
+package mask
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Masking techniques.
+const (
+	TechniqueRedact   = "redact"   // every value replaced by redactedValue
+	TechniqueHash     = "hash"     // salted HMAC-SHA256 of the value, one to one
+	TechniqueTruncate = "truncate" // the first Keep characters of the value
+	TechniqueRound    = "round"    // numbers rounded to the nearest multiple of Step
+	TechniqueNoise    = "noise"    // numbers moved by Laplace noise of scale Scale
+)
+
+const (
+	redactedValue = "***"
+	hashLength    = 16 // hex digits of the HMAC kept
+)
+
+func Techniques() []string {
+	return []string{TechniqueRedact, TechniqueHash, TechniqueTruncate, TechniqueRound, TechniqueNoise}
+}
+
+// Plan is a masking plan, proposed for a dataset before it is shared:
+//
+//	columns:
+//	  - name: email
+//	    technique: hash
+//	  - name: zip
+//	    technique: truncate
+//	    keep: 3
+//	  - name: age
+//	    technique: round
+//	    step: 10
+//	  - name: salary
+//	    technique: noise
+//	    scale: 1000
+//	salt: <secret>
+//	seed: 7
+//
+// Columns not listed are left as they are. Missing values stay missing.
+type Plan struct {
+	Columns []ColumnMask `yaml:"columns"`
+	Salt    string       `yaml:"salt,omitempty"` // salts hashes, so that columns hashed with the same salt still join
+	Seed    int64        `yaml:"seed,omitempty"` // seeds the noise, so that a preview can be repeated
+}
+
+// ColumnMask is how one column is masked.
+type ColumnMask struct {
+	Name      string  `yaml:"name"`
+	Technique string  `yaml:"technique"`
+	Keep      int     `yaml:"keep,omitempty"`  // truncate: characters kept
+	Step      float64 `yaml:"step,omitempty"`  // round: multiple rounded to
+	Scale     float64 `yaml:"scale,omitempty"` // noise: scale of the Laplace noise, its mean absolute value
+}
+
+func (m ColumnMask) Validate() error {
+	if m.Name == "" {
+		return fmt.Errorf("masked column without a name")
+	}
+	switch m.Technique {
+	case TechniqueRedact, TechniqueHash:
+	case TechniqueTruncate:
+		if m.Keep < 1 {
+			return fmt.Errorf("column %s: truncate needs the characters to keep, keep of at least 1", m.Name)
+		}
+	case TechniqueRound:
+		if m.Step <= 0 {
+			return fmt.Errorf("column %s: round needs a positive step", m.Name)
+		}
+	case TechniqueNoise:
+		if m.Scale <= 0 {
+			return fmt.Errorf("column %s: noise needs a positive scale", m.Name)
+		}
+	default:
+		return fmt.Errorf("column %s: unknown technique %q (use %s)", m.Name, m.Technique, strings.Join(Techniques(), ", "))
+	}
+	return nil
+}
+
+// String describes the technique, as round to 10.
+func (m ColumnMask) String() string {
+	switch m.Technique {
+	case TechniqueTruncate:
+		return fmt.Sprintf("truncate to %d", m.Keep)
+	case TechniqueRound:
+		return fmt.Sprintf("round to %g", m.Step)
+	case TechniqueNoise:
+		return fmt.Sprintf("noise of %g", m.Scale)
+	default:
+		return m.Technique
+	}
+}
+
+func (p *Plan) Validate() error {
+	if len(p.Columns) == 0 {
+		return fmt.Errorf("the plan masks no columns")
+	}
+	for i, m := range p.Columns {
+		if err := m.Validate(); err != nil {
+			return err
+		}
+		for _, other := range p.Columns[:i] {
+			if other.Name == m.Name {
+				return fmt.Errorf("column %s is masked twice", m.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadPlan reads a masking plan from a YAML file. Unknown keys are rejected
+// so that a misspelled setting is not silently ignored.
+func LoadPlan(path string) (*Plan, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read masking plan: %w", err)
+	}
+
+	var plan Plan
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&plan); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse masking plan %s: %w", path, err)
+	}
+	if err := plan.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid masking plan %s: %w", path, err)
+	}
+	return &plan, nil
+}
+
+// ParseColumnMask reads a column mask written as column=technique, with the
+// parameter of the technique after a colon: zip=truncate:3, age=round:10 or
+// salary=noise:1000.
+func ParseColumnMask(spec string) (ColumnMask, error) {
+	name, technique, ok := strings.Cut(spec, "=")
+	if !ok {
+		return ColumnMask{}, fmt.Errorf("expected column=technique, got %q", spec)
+	}
+	m := ColumnMask{Name: strings.TrimSpace(name)}
+	technique, param, hasParam := strings.Cut(technique, ":")
+	m.Technique = strings.TrimSpace(technique)
+
+	if hasParam {
+		value, err := strconv.ParseFloat(strings.TrimSpace(param), 64)
+		if err != nil {
+			return ColumnMask{}, fmt.Errorf("column %s: parameter %q is not a number", m.Name, param)
+		}
+		switch m.Technique {
+		case TechniqueTruncate:
+			m.Keep = int(value)
+		case TechniqueRound:
+			m.Step = value
+		case TechniqueNoise:
+			m.Scale = value
+		default:
+			return ColumnMask{}, fmt.Errorf("column %s: %s takes no parameter", m.Name, m.Technique)
+		}
+	}
+	return m, m.Validate()
+}
+
+// Masker writes each row it is given to a CSV with the columns of its plan
+// masked.
+type Masker struct {
+	plan    *Plan
+	out     *csv.Writer
+	rng     *rand.Rand
+	masks   []*ColumnMask // by column index, nil for columns left as they are
+	skipped map[string]int
+	header  bool
+	masked  []string
+}
+
+func New(w io.Writer, plan *Plan) (*Masker, error) {
+	if err := plan.Validate(); err != nil {
+		return nil, err
+	}
+	return &Masker{
+		plan:    plan,
+		out:     csv.NewWriter(w),
+		rng:     rand.New(rand.NewSource(plan.Seed)),
+		skipped: make(map[string]int),
+	}, nil
+}
+
+// Add writes record masked, after the header on the first call. It has the
+// signature of profiler.Options.Rows.
+func (m *Masker) Add(header, record []string) error {
+	if !m.header {
+		if err := m.writeHeader(header); err != nil {
+			return err
+		}
+	}
+
+	m.masked = append(m.masked[:0], record...)
+	for i, value := range m.masked {
+		if i >= len(m.masks) || m.masks[i] == nil || value == "" {
+			continue
+		}
+		masked, ok := m.mask(m.masks[i], value)
+		if !ok {
+			m.skipped[m.masks[i].Name]++
+		}
+		m.masked[i] = masked
+	}
+	return m.out.Write(m.masked)
+}
+
+func (m *Masker) writeHeader(header []string) error {
+	m.masks = make([]*ColumnMask, len(header))
+	for i := range m.plan.Columns {
+		col := &m.plan.Columns[i]
+		index := slices.Index(header, col.Name)
+		if index < 0 {
+			return fmt.Errorf("masked column %s not found", col.Name)
+		}
+		m.masks[index] = col
+	}
+
+	m.header = true
+	return m.out.Write(header)
+}
+
+// mask returns value masked, or as it is when it is not a number a numeric
+// technique can mask.
+func (m *Masker) mask(col *ColumnMask, value string) (string, bool) {
+	switch col.Technique {
+	case TechniqueRedact:
+		return redactedValue, true
+	case TechniqueHash:
+		mac := hmac.New(sha256.New, []byte(m.plan.Salt))
+		mac.Write([]byte(value))
+		return hex.EncodeToString(mac.Sum(nil))[:hashLength], true
+	case TechniqueTruncate:
+		if runes := []rune(value); len(runes) > col.Keep {
+			return string(runes[:col.Keep]), true
+		}
+		return value, true
+	}
+
+	v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || math.IsInf(v, 0) || math.IsNaN(v) {
+		return value, false
+	}
+	if col.Technique == TechniqueRound {
+		return strconv.FormatFloat(math.Round(v/col.Step)*col.Step, 'f', decimals(strconv.FormatFloat(col.Step, 'f', -1, 64)), 64), true
+	}
+	return strconv.FormatFloat(v+m.laplace(col.Scale), 'f', decimals(value), 64), true
+}
+
+// laplace draws from a Laplace distribution of mean 0 and the given scale.
+func (m *Masker) laplace(scale float64) float64 {
+	u := m.rng.Float64() - 0.5
+	for u == -0.5 {
+		u = m.rng.Float64() - 0.5
+	}
+	if u < 0 {
+		return scale * math.Log(1+2*u)
+	}
+	return -scale * math.Log(1-2*u)
+}
+
+// decimals counts the digits after the decimal point of a number, so that
+// masked numbers keep the precision of the originals.
+func decimals(number string) int {
+	if _, fraction, ok := strings.Cut(strings.TrimSpace(number), "."); ok {
+		return len(fraction)
+	}
+	return 0
+}
+
+// Flush writes out the rows buffered.
+func (m *Masker) Flush() error {
+	m.out.Flush()
+	return m.out.Error()
+}
+
+// Skipped returns the values of a column left as they were because a numeric
+// technique could not read them as numbers.
+func (m *Masker) Skipped(column string) int {
+	return m.skipped[column]
+}
@@ -0,0 +1,167 @@
+package mask
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func maskRows(t *testing.T, plan *Plan, header []string, rows ...[]string) (*Masker, [][]string) {
+	t.Helper()
+	var out bytes.Buffer
+	masker, err := New(&out, plan)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	for _, row := range rows {
+		if err := masker.Add(header, row); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	if err := masker.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	masked, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read masked rows: %v", err)
+	}
+	return masker, masked
+}
+
+func TestMaskerTechniques(t *testing.T) {
+	plan := &Plan{Columns: []ColumnMask{
+		{Name: "name", Technique: TechniqueRedact},
+		{Name: "email", Technique: TechniqueHash},
+		{Name: "zip", Technique: TechniqueTruncate, Keep: 3},
+		{Name: "age", Technique: TechniqueRound, Step: 10},
+	}, Salt: "secret"}
+	header := []string{"id", "name", "email", "zip", "age"}
+
+	masker, masked := maskRows(t, plan, header,
+		[]string{"1", "Ann", "ann@example.com", "75011", "34"},
+		[]string{"2", "", "ann@example.com", "69", "unknown"},
+		[]string{"3", "Bob", "bob@example.com", "13001", "36"},
+	)
+	if len(masked) != 4 || strings.Join(masked[0], ",") != strings.Join(header, ",") {
+		t.Fatalf("Expected the header and 3 rows, got %v", masked)
+	}
+
+	first, second, third := masked[1], masked[2], masked[3]
+	if first[0] != "1" || first[1] != "***" || first[3] != "750" || first[4] != "30" {
+		t.Errorf("Expected the id kept, the name redacted, the zip truncated and the age rounded, got %v", first)
+	}
+	if second[1] != "" || second[3] != "69" || second[4] != "unknown" {
+		t.Errorf("Expected missing, short and non-numeric values left as they are, got %v", second)
+	}
+	if third[4] != "40" {
+		t.Errorf("Expected 36 rounded to 40, got %s", third[4])
+	}
+
+	// Hashes are one to one and repeatable
+	if first[2] != second[2] || first[2] == third[2] || len(first[2]) != hashLength || first[2] == "ann@example.com" {
+		t.Errorf("Expected the same email hashed the same way and others differently, got %v", []string{first[2], second[2], third[2]})
+	}
+	if masker.Skipped("age") != 1 || masker.Skipped("zip") != 0 {
+		t.Errorf("Expected the one non-numeric age to be skipped, got %d", masker.Skipped("age"))
+	}
+}
+
+func TestMaskerNoise(t *testing.T) {
+	plan := &Plan{Columns: []ColumnMask{{Name: "salary", Technique: TechniqueNoise, Scale: 100}}, Seed: 7}
+	rows := make([][]string, 2000)
+	for i := range rows {
+		rows[i] = []string{"50000"}
+	}
+
+	_, masked := maskRows(t, plan, []string{"salary"}, rows...)
+	_, again := maskRows(t, plan, []string{"salary"}, rows...)
+
+	sum := 0.0
+	for i, row := range masked[1:] {
+		v, err := strconv.Atoi(row[0])
+		if err != nil {
+			t.Fatalf("Expected whole numbers like the originals, got %s", row[0])
+		}
+		sum += math.Abs(float64(v) - 50000)
+		if row[0] != again[i+1][0] {
+			t.Fatalf("Expected the same seed to repeat the noise, got %s and %s", row[0], again[i+1][0])
+		}
+	}
+	// The mean absolute noise is its scale
+	if mean := sum / 2000; mean < 90 || mean > 110 {
+		t.Errorf("Expected noise of mean absolute value near 100, got %.1f", mean)
+	}
+}
+
+func TestMaskerMissingColumn(t *testing.T) {
+	plan := &Plan{Columns: []ColumnMask{{Name: "email", Technique: TechniqueHash}}}
+	masker, err := New(&bytes.Buffer{}, plan)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := masker.Add([]string{"id"}, []string{"1"}); err == nil || !strings.Contains(err.Error(), "email") {
+		t.Errorf("Expected the missing email column to be reported, got %v", err)
+	}
+}
+
+func TestParseColumnMask(t *testing.T) {
+	testCases := []struct {
+		spec string
+		want ColumnMask
+		ok   bool
+	}{
+		{"email=hash", ColumnMask{Name: "email", Technique: TechniqueHash}, true},
+		{"zip=truncate:3", ColumnMask{Name: "zip", Technique: TechniqueTruncate, Keep: 3}, true},
+		{"age=round:0.5", ColumnMask{Name: "age", Technique: TechniqueRound, Step: 0.5}, true},
+		{"salary=noise:1000", ColumnMask{Name: "salary", Technique: TechniqueNoise, Scale: 1000}, true},
+		{"zip=truncate", ColumnMask{}, false},
+		{"email=hash:3", ColumnMask{}, false},
+		{"age=round:ten", ColumnMask{}, false},
+		{"email=scramble", ColumnMask{}, false},
+		{"email", ColumnMask{}, false},
+	}
+
+	for _, tc := range testCases {
+		got, err := ParseColumnMask(tc.spec)
+		if (err == nil) != tc.ok || (tc.ok && got != tc.want) {
+			t.Errorf("%s: expected %+v (ok %v), got %+v and %v", tc.spec, tc.want, tc.ok, got, err)
+		}
+	}
+}
+
+func TestLoadPlan(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "masking.yaml")
+	content := "columns:\n  - name: email\n    technique: hash\n  - name: age\n    technique: round\n    step: 10\nsalt: secret\nseed: 7\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write plan: %v", err)
+	}
+
+	plan, err := LoadPlan(path)
+	if err != nil {
+		t.Fatalf("LoadPlan failed: %v", err)
+	}
+	if len(plan.Columns) != 2 || plan.Columns[1].String() != "round to 10" || plan.Salt != "secret" || plan.Seed != 7 {
+		t.Errorf("Unexpected plan: %+v", plan)
+	}
+
+	for name, content := range map[string]string{
+		"unknown key": "columns:\n  - name: email\n    technique: hash\n    salt: x\n",
+		"empty":       "",
+		"twice":       "columns:\n  - name: email\n    technique: hash\n  - name: email\n    technique: redact\n",
+		"no step":     "columns:\n  - name: age\n    technique: round\n",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write plan: %v", err)
+		}
+		if _, err := LoadPlan(path); err == nil {
+			t.Errorf("%s: expected the plan to be rejected", name)
+		}
+	}
+}
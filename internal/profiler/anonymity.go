@@ -0,0 +1,213 @@
+package profiler
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// suppressRareValues withholds the values seen fewer than k times from the
+// listings of profile, so that reports do not single out rare individuals:
+// top and bottom values, the modes of columns whose values are all rarer, duplicate
+// groups and duplicate keys. Examples, preview cells, outliers and the min and
+// max of string columns are kept only when listed among the top or bottom
+// values as seen at least k times, the only values whose counts are known.
+// Counts, statistics and the quality score are left as computed. A k below 2
+// withholds nothing.
+func suppressRareValues(profile *DatasetProfile, k int) {
+	if k < 2 {
+		return
+	}
+	profile.KAnonymity = k
+
+	// Listed counts are taken before the listings are cut
+	common := make(map[string]commonValues, len(profile.Columns))
+	for colName, col := range profile.Columns {
+		common[colName] = newCommonValues(col, k)
+	}
+
+	withheld, examples := 0, 0
+	for colName, col := range profile.Columns {
+		// Top values come most frequent first, so the listing ends at the
+		// first rare value
+		kept := 0
+		for _, val := range col.TopValues {
+			if val.Count >= k {
+				col.TopValues[kept] = val
+				kept++
+			}
+		}
+		top := len(col.TopValues) - kept
+		col.TopValues = col.TopValues[:kept]
+
+		// Bottom values come rarest first, so the listing starts after the
+		// last rare value
+		rare := 0
+		for rare < len(col.BottomValues) && col.BottomValues[rare].Count < k {
+			rare++
+		}
+		col.BottomValues = col.BottomValues[rare:]
+
+		// The mode is the most frequent value, rare when every value is
+		modeWithheld := col.Mode != nil && kept == 0
+		if modeWithheld {
+			col.Mode = nil
+		}
+
+		switch {
+		case top > 0 && modeWithheld:
+			col.Notes = append(col.Notes, fmt.Sprintf("%d top values and the mode withheld, each seen fewer than %d times", top, k))
+		case top > 0:
+			col.Notes = append(col.Notes, fmt.Sprintf("%d top values withheld, each seen fewer than %d times", top, k))
+		case modeWithheld:
+			col.Notes = append(col.Notes, fmt.Sprintf("Mode withheld, seen fewer than %d times", k))
+		}
+		if rare > 0 {
+			col.Notes = append(col.Notes, fmt.Sprintf("%d bottom values withheld, each seen fewer than %d times", rare, k))
+		}
+		col.SuppressedValues = top + rare
+		withheld += col.SuppressedValues
+
+		dropped := common[colName].suppressExamples(col)
+		if dropped > 0 {
+			col.Notes = append(col.Notes, fmt.Sprintf("%d examples withheld, not known to be seen %d times or more", dropped, k))
+		}
+		examples += dropped
+	}
+
+	cells := 0
+	if profile.Preview != nil {
+		// The rows may be shared with the preview still reading, so are copied
+		rows := make([][]string, len(profile.Preview.Rows))
+		for r, row := range profile.Preview.Rows {
+			rows[r] = make([]string, len(row))
+			for i, value := range row {
+				if value != "" && value != RedactedValue && !common[profile.Preview.Columns[i]].has(value) {
+					value = WithheldValue
+					cells++
+				}
+				rows[r][i] = value
+			}
+		}
+		profile.Preview = &Preview{Columns: profile.Preview.Columns, Rows: rows}
+	}
+
+	groups := profile.DuplicateGroups[:0]
+	for _, group := range profile.DuplicateGroups {
+		if len(group.Rows) >= k {
+			groups = append(groups, group)
+		}
+	}
+	duplicates := len(profile.DuplicateGroups) - len(groups)
+	profile.DuplicateGroups = groups
+
+	keys := profile.DuplicateKeys[:0]
+	for _, key := range profile.DuplicateKeys {
+		if key.Rows >= k {
+			keys = append(keys, key)
+		}
+	}
+	duplicates += len(profile.DuplicateKeys) - len(keys)
+	profile.DuplicateKeys = keys
+
+	note := fmt.Sprintf("Values seen fewer than %d times withheld from value, example, preview and duplicate listings (k-anonymity)", k)
+	if withheld > 0 || examples > 0 || cells > 0 || duplicates > 0 {
+		note += fmt.Sprintf(": %d top or bottom values, %d examples, %d preview cells, %d duplicate groups or keys", withheld, examples, cells, duplicates)
+	}
+	profile.Notes = append(profile.Notes, note)
+}
+
+// commonValues holds the values of a column listed as seen at least k times,
+// as read and as truncated for examples, and those that read as numbers.
+type commonValues struct {
+	values  map[string]bool
+	numbers map[float64]bool
+}
+
+func newCommonValues(col *ColumnProfile, k int) commonValues {
+	c := commonValues{values: make(map[string]bool), numbers: make(map[float64]bool)}
+	for _, listing := range [][]ValueCount{col.TopValues, col.BottomValues} {
+		for _, v := range listing {
+			if v.Count < k {
+				continue
+			}
+			c.values[v.Value] = true
+			c.values[truncateExample(v.Value)] = true
+			if f, err := strconv.ParseFloat(v.Value, 64); err == nil {
+				c.numbers[f] = true
+			}
+		}
+	}
+	return c
+}
+
+func (c commonValues) has(value string) bool {
+	return c.values[value]
+}
+
+// keep filters values down to the common ones, returning how many it
+// dropped. The values may be shared with an accumulator, so they are copied.
+func (c commonValues) keep(values *[]string) int {
+	var kept []string
+	for _, value := range *values {
+		if c.has(value) {
+			kept = append(kept, value)
+		}
+	}
+	dropped := len(*values) - len(kept)
+	*values = kept
+	return dropped
+}
+
+// suppressExamples withholds the examples of col that are not common, and
+// describes its formatting and mixed type issues again without them. It
+// returns how many values it withheld.
+func (c commonValues) suppressExamples(col *ColumnProfile) int {
+	dropped := c.keep(&col.Examples)
+
+	if min, ok := col.Min.(string); ok && !c.has(min) {
+		col.Min = nil
+		dropped++
+	}
+	if max, ok := col.Max.(string); ok && !c.has(max) {
+		col.Max = nil
+		dropped++
+	}
+
+	if col.Outliers != nil {
+		var kept []float64
+		for _, v := range col.Outliers.Examples {
+			if c.numbers[v] {
+				kept = append(kept, v)
+			}
+		}
+		dropped += len(col.Outliers.Examples) - len(kept)
+		col.Outliers.Examples = kept
+	}
+
+	if col.Text != nil {
+		for i := range col.Text.Formatting {
+			f := &col.Text.Formatting[i]
+			if n := c.keep(&f.Examples); n > 0 {
+				dropped += n
+				for j := range col.QualityIssues {
+					if col.QualityIssues[j].Type == f.Kind {
+						col.QualityIssues[j].Description = f.String()
+					}
+				}
+			}
+		}
+	}
+
+	if col.MixedTypes != nil {
+		if n := c.keep(&col.MixedTypes.Examples); n > 0 {
+			dropped += n
+			for j := range col.QualityIssues {
+				if col.QualityIssues[j].Type == "mixed_types" {
+					col.QualityIssues[j].Description = "Mixed types: " + col.MixedTypes.describe(col.Count)
+				}
+			}
+		}
+	}
+
+	return dropped
+}
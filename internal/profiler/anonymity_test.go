@@ -0,0 +1,227 @@
+package profiler
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestProfileKAnonymity(t *testing.T) {
+	path := writeTestFile(t, "data.csv", "city,score\n"+
+		"paris,7\nparis,7\nparis,1\nparis,2\nparis,3\nparis,4\n"+
+		"lyon,5\nlyon,6\nlyon,8\n"+
+		"nice,9\n")
+
+	full, err := ProfileDatasetWithOptions(path, Options{ExactRows: 1000})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	profile, err := ProfileDatasetWithOptions(path, Options{ExactRows: 1000, KAnonymity: 3})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+
+	if profile.KAnonymity != 3 || full.KAnonymity != 0 {
+		t.Errorf("Expected k-anonymity of 3 only when asked, got %d and %d", profile.KAnonymity, full.KAnonymity)
+	}
+
+	city := profile.Columns["city"]
+	want := []ValueCount{{Value: "paris", Count: 6}, {Value: "lyon", Count: 3}}
+	if !reflect.DeepEqual(city.TopValues, want) || city.SuppressedValues != 1 {
+		t.Errorf("Expected nice to be withheld from %v, got %v with %d withheld", want, city.TopValues, city.SuppressedValues)
+	}
+
+	// Every score is rarer than 3, the mode of 7 included
+	score, fullScore := profile.Columns["score"], full.Columns["score"]
+	if len(score.TopValues) != 0 || score.SuppressedValues != len(fullScore.TopValues) || score.Mode != nil {
+		t.Errorf("Expected every score and the mode to be withheld, got %v and mode %v", score.TopValues, score.Mode)
+	}
+	if fullScore.Mode != 7.0 {
+		t.Errorf("Expected a mode of 7 without k-anonymity, got %v", fullScore.Mode)
+	}
+
+	// The duplicate pair of rows is rarer than 3 too
+	if len(full.DuplicateGroups) != 1 || len(profile.DuplicateGroups) != 0 {
+		t.Errorf("Expected the duplicate group to be withheld, got %v and %v", full.DuplicateGroups, profile.DuplicateGroups)
+	}
+
+	// Counts and statistics are left as computed
+	if profile.DuplicateRows != full.DuplicateRows || profile.QualityScore != full.QualityScore ||
+		score.UniqueCount != fullScore.UniqueCount || score.Mean != fullScore.Mean || city.UniqueCount != 3 {
+		t.Errorf("Expected the statistics to be unchanged, got %+v and %+v", profile, full)
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{KAnonymity: -1}); err == nil {
+		t.Error("Expected a negative k-anonymity threshold to be rejected")
+	}
+}
+
+func TestKAnonymityDuplicateKeys(t *testing.T) {
+	profile, err := ProfileDatasetWithOptions(writeTestFile(t, "data.csv", ordersCSV()), Options{UniqueKey: []string{"order_id"}, ExactRows: 1000, KAnonymity: 3})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+
+	want := []DuplicateKey{{Values: []string{"0"}, Rows: 3}}
+	if !reflect.DeepEqual(profile.DuplicateKeys, want) || profile.DuplicateRows != 4 {
+		t.Errorf("Expected only order 0 to be listed of 4 duplicate rows, got %v of %d", profile.DuplicateKeys, profile.DuplicateRows)
+	}
+	if len(profile.DuplicateGroups) != 1 || len(profile.DuplicateGroups[0].Rows) != 3 {
+		t.Errorf("Expected only the group of order 0, got %v", profile.DuplicateGroups)
+	}
+}
+
+func TestKAnonymityBottomValues(t *testing.T) {
+	profile, err := ProfileDatasetWithOptions(writeTestFile(t, "data.csv", rareCSV()), Options{TopValues: 3, KAnonymity: 2})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+
+	// The rarest categories are seen once, every code 10 times
+	category, code := profile.Columns["category"], profile.Columns["code"]
+	if len(category.BottomValues) != 0 || category.SuppressedValues != 4 {
+		t.Errorf("Expected the bottom categories and a top one withheld, got %v with %d withheld", category.BottomValues, category.SuppressedValues)
+	}
+	if len(code.BottomValues) != 3 || code.SuppressedValues != 0 {
+		t.Errorf("Expected every bottom code kept, got %v with %d withheld", code.BottomValues, code.SuppressedValues)
+	}
+	if category.Rare == nil || category.Rare.Singletons != 11 {
+		t.Errorf("Expected the singletons still counted, got %v", category.Rare)
+	}
+}
+
+// examplesCSV returns rows whose city, amount, code and label each have
+// a value common enough for a k of 3 and a rarer one that stands out.
+func examplesCSV() string {
+	var b strings.Builder
+	b.WriteString("city,amount,code,label\n")
+	for i := 0; i < 36; i++ {
+		code := fmt.Sprint(100 + i%5)
+		if i < 4 {
+			code = "n/a"
+		}
+		label := "ok"
+		if i < 3 {
+			label = " padded"
+		}
+		fmt.Fprintf(&b, "paris,%d,%s,%s\n", 10+i%5, code, label)
+	}
+	b.WriteString("nice,1000,unknown,rare \n")
+	for i := 0; i < 3; i++ {
+		b.WriteString("lyon,900,101,ok\n")
+	}
+	return b.String()
+}
+
+func profileExamples(t *testing.T, k int) *DatasetProfile {
+	t.Helper()
+
+	profile, err := ProfileDatasetWithOptions(writeTestFile(t, "data.csv", examplesCSV()), Options{
+		Examples:   100,
+		Preview:    40,
+		Outliers:   &OutlierDetection{Method: OutlierMethodIQR, IQRMultiplier: 1.5},
+		KAnonymity: k,
+	})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	return profile
+}
+
+func TestKAnonymityExamples(t *testing.T) {
+	full, profile := profileExamples(t, 0), profileExamples(t, 3)
+
+	if !slices.Contains(full.Columns["city"].Examples, "nice") {
+		t.Fatalf("Expected nice among the examples without k-anonymity, got %v", full.Columns["city"].Examples)
+	}
+	city := profile.Columns["city"]
+	if slices.Contains(city.Examples, "nice") || !slices.Contains(city.Examples, "paris") || !slices.Contains(city.Examples, "lyon") {
+		t.Errorf("Expected only nice withheld from the examples, got %v", city.Examples)
+	}
+	if !slices.Contains(city.Notes, "1 examples withheld, not known to be seen 3 times or more") {
+		t.Errorf("Expected a note of the withheld example, got %v", city.Notes)
+	}
+}
+
+func TestKAnonymityPreview(t *testing.T) {
+	full, profile := profileExamples(t, 0), profileExamples(t, 3)
+
+	if got := full.Preview.Rows[36]; !reflect.DeepEqual(got, []string{"nice", "1000", "unknown", "rare "}) {
+		t.Fatalf("Expected the rare row in the preview without k-anonymity, got %v", got)
+	}
+	withheld := []string{WithheldValue, WithheldValue, WithheldValue, WithheldValue}
+	if got := profile.Preview.Rows[36]; !reflect.DeepEqual(got, withheld) {
+		t.Errorf("Expected every value of the rare row withheld, got %v", got)
+	}
+	if got := profile.Preview.Rows[37]; !reflect.DeepEqual(got, []string{"lyon", "900", "101", "ok"}) {
+		t.Errorf("Expected values seen 3 times kept, got %v", got)
+	}
+}
+
+func TestKAnonymityOutlierExamples(t *testing.T) {
+	full, profile := profileExamples(t, 0), profileExamples(t, 3)
+
+	if got := full.Columns["amount"].Outliers; got == nil || !reflect.DeepEqual(got.Examples, []float64{1000, 900, 900, 900}) {
+		t.Fatalf("Expected outliers 1000 and 900 without k-anonymity, got %+v", got)
+	}
+	if got := profile.Columns["amount"].Outliers; !reflect.DeepEqual(got.Examples, []float64{900, 900, 900}) || got.Count != 4 {
+		t.Errorf("Expected 1000 withheld from the 4 outliers, got %+v", got)
+	}
+}
+
+func TestKAnonymityFormattingExamples(t *testing.T) {
+	full, profile := profileExamples(t, 0), profileExamples(t, 3)
+
+	if got := full.Columns["label"].Text.Formatting; len(got) != 1 || !reflect.DeepEqual(got[0].Examples, []string{" padded", "rare "}) {
+		t.Fatalf("Expected both padded labels as examples without k-anonymity, got %+v", got)
+	}
+	label := profile.Columns["label"]
+	f := label.Text.Formatting[0]
+	if !reflect.DeepEqual(f.Examples, []string{" padded"}) || f.Count != 4 {
+		t.Errorf("Expected rare withheld from the 4 padded labels, got %+v", f)
+	}
+	for _, issue := range label.QualityIssues {
+		if strings.Contains(issue.Description, "rare") {
+			t.Errorf("Expected rare withheld from the issue, got %q", issue.Description)
+		}
+	}
+}
+
+func TestKAnonymityMixedTypeExamples(t *testing.T) {
+	full, profile := profileExamples(t, 0), profileExamples(t, 3)
+
+	if got := full.Columns["code"].MixedTypes; got == nil || !reflect.DeepEqual(got.Examples, []string{"n/a", "unknown"}) {
+		t.Fatalf("Expected n/a and unknown as examples without k-anonymity, got %+v", got)
+	}
+	code := profile.Columns["code"]
+	if got := code.MixedTypes; !reflect.DeepEqual(got.Examples, []string{"n/a"}) {
+		t.Errorf("Expected unknown withheld, got %+v", got)
+	}
+	for _, issue := range code.QualityIssues {
+		if issue.Type == "mixed_types" && strings.Contains(issue.Description, "unknown") {
+			t.Errorf("Expected unknown withheld from the issue, got %q", issue.Description)
+		}
+	}
+}
+
+func TestKAnonymityStringMinMax(t *testing.T) {
+	profile := &DatasetProfile{Columns: map[string]*ColumnProfile{
+		"city": {
+			Name:      "city",
+			Min:       "lyon",
+			Max:       "nice",
+			TopValues: []ValueCount{{Value: "paris", Count: 5}, {Value: "lyon", Count: 3}, {Value: "nice", Count: 1}},
+		},
+		"amount": {Name: "amount", Min: 1.0, Max: 1000.0},
+	}}
+	suppressRareValues(profile, 3)
+
+	if city := profile.Columns["city"]; city.Min != "lyon" || city.Max != nil {
+		t.Errorf("Expected the max nice withheld and the min lyon kept, got %v and %v", city.Min, city.Max)
+	}
+	if amount := profile.Columns["amount"]; amount.Min != 1.0 || amount.Max != 1000.0 {
+		t.Errorf("Expected numeric bounds kept, got %v and %v", amount.Min, amount.Max)
+	}
+}
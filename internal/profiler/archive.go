@@ -0,0 +1,626 @@
+package profiler
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// IsArchive reports whether path is a zip or tar archive, judged by
+// extension: .zip, .tar, .tgz, or .tar with a compression extension.
+func IsArchive(path string) bool {
+	switch strings.ToLower(filepath.Ext(trimCompressionExt(path))) {
+	case ".zip", ".tar", ".tgz":
+		return true
+	default:
+		return false
+	}
+}
+
+// archiveEntry is a data file inside an archive.
+type archiveEntry struct {
+	name         string
+	codec        string // zip for deflated zip members, the tar compression otherwise
+	uncompressed int64  // size of a deflated zip member, 0 if unknown
+	open         func() (io.Reader, string, error)
+}
+
+// archiveWalker visits the data files of an archive in archive order. Tar
+// archives are read sequentially, so an entry can only be opened until the
+// next one is requested.
+type archiveWalker interface {
+	next() (*archiveEntry, error)
+	close()
+}
+
+// isArchiveDataFile reports whether an archive member looks like data: a
+// CSV, TSV or JSON Lines file, possibly compressed, or any visible file
+// when --format is given. Hidden files and macOS metadata are left out.
+func isArchiveDataFile(name string, opts Options) bool {
+	base := path.Base(name)
+	if strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(base, ".") {
+		return false
+	}
+	if opts.Format != "" {
+		return true
+	}
+
+	switch strings.ToLower(filepath.Ext(trimCompressionExt(base))) {
+	case ".csv", ".tsv", ".tab", ".jsonl", ".ndjson":
+		return true
+	default:
+		return false
+	}
+}
+
+func openArchive(filePath string, opts Options) (archiveWalker, error) {
+	if strings.ToLower(filepath.Ext(filePath)) == ".zip" {
+		return openZipWalker(filePath, opts)
+	}
+	return openTarWalker(filePath, opts)
+}
+
+type zipWalker struct {
+	archive  *zip.ReadCloser
+	members  []*zip.File
+	password string
+}
+
+func openZipWalker(filePath string, opts Options) (*zipWalker, error) {
+	archive, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip archive: %w", err)
+	}
+
+	w := &zipWalker{archive: archive, password: opts.Password}
+	for _, f := range archive.File {
+		if !f.FileInfo().IsDir() && isArchiveDataFile(f.Name, opts) {
+			w.members = append(w.members, f)
+		}
+	}
+	return w, nil
+}
+
+func (w *zipWalker) next() (*archiveEntry, error) {
+	if len(w.members) == 0 {
+		return nil, io.EOF
+	}
+	f := w.members[0]
+	w.members = w.members[1:]
+
+	entry := &archiveEntry{
+		name: f.Name,
+		open: func() (io.Reader, string, error) { return openZipMember(f, w.password) },
+	}
+	if f.Method != zip.Store {
+		entry.codec = "zip"
+		entry.uncompressed = int64(f.UncompressedSize64)
+	}
+	return entry, nil
+}
+
+func (w *zipWalker) close() {
+	w.archive.Close()
+}
+
+type tarWalker struct {
+	file   *os.File
+	source *decompressor
+	reader *tar.Reader
+	opts   Options
+}
+
+func openTarWalker(filePath string, opts Options) (*tarWalker, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	source, err := decompress(file, filePath)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &tarWalker{file: file, source: source, reader: tar.NewReader(source), opts: opts}, nil
+}
+
+func (w *tarWalker) next() (*archiveEntry, error) {
+	for {
+		header, err := w.reader.Next()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || !isArchiveDataFile(header.Name, w.opts) {
+			continue
+		}
+
+		return &archiveEntry{
+			name:  header.Name,
+			codec: w.source.codec,
+			open:  func() (io.Reader, string, error) { return w.reader, "", nil },
+		}, nil
+	}
+}
+
+func (w *tarWalker) close() {
+	w.source.Close()
+	w.file.Close()
+}
+
+// listArchive returns the data files of an archive in archive order.
+func listArchive(filePath string, opts Options) ([]string, error) {
+	walker, err := openArchive(filePath, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer walker.close()
+
+	names := make([]string, 0)
+	for {
+		entry, err := walker.next()
+		if err == io.EOF {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, entry.name)
+	}
+}
+
+// profileArchive profiles the data file chosen with --member, the only data
+// file of an archive, or all of them merged into one dataset.
+func profileArchive(filePath string, opts Options) (*DatasetProfile, error) {
+	names, err := listArchive(filePath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case opts.Member != "":
+		if !containsString(names, opts.Member) {
+			return nil, fmt.Errorf("member %s not found in archive (data files: %s)", opts.Member, strings.Join(names, ", "))
+		}
+		return profileArchiveMember(filePath, opts.Member, opts)
+	case len(names) == 0:
+		return nil, fmt.Errorf("no CSV, TSV or JSON Lines files found in archive %s", filePath)
+	case len(names) == 1:
+		return profileArchiveMember(filePath, names[0], opts)
+	default:
+		return profileArchiveMerged(filePath, names, opts)
+	}
+}
+
+// archiveMemberFormat picks the reader for a member, rejecting formats that
+// need random access and binary formats.
+func archiveMemberFormat(name string, opts Options) (string, error) {
+	format := fileFormat(name, opts)
+	if IsExcel(name) || format == "parquet" || format == "avro" || format == "orc" || format == "json" || format == FormatXML {
+		return "", fmt.Errorf("%s inside an archive is not supported, extract it first", name)
+	}
+	return format, nil
+}
+
+func profileArchiveMember(filePath, member string, opts Options) (*DatasetProfile, error) {
+	startTime := time.Now()
+
+	name := path.Base(member)
+	format, err := archiveMemberFormat(name, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file stats: %w", err)
+	}
+
+	walker, err := openArchive(filePath, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer walker.close()
+
+	var entry *archiveEntry
+	for entry == nil || entry.name != member {
+		if entry, err = walker.next(); err != nil {
+			return nil, fmt.Errorf("failed to find %s in archive: %w", member, err)
+		}
+	}
+
+	r, encryption, err := entry.open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from archive: %w", member, err)
+	}
+
+	var profile *DatasetProfile
+	switch format {
+	case FormatTSV:
+		profile, err = profileDelimited(r, name, fileInfo.Size(), '\t', "TSV", opts)
+	case FormatJSONL:
+		profile, err = profileJSONL(r, name, fileInfo.Size(), opts)
+	default:
+		profile, err = profileDelimited(r, name, fileInfo.Size(), 0, "CSV", opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	profile.Filename = filepath.Base(filePath) + "/" + member
+	if entry.codec != "" && profile.Compression == "" {
+		profile.Compression = entry.codec
+		profile.UncompressedSize = entry.uncompressed
+	}
+	if encryption != "" {
+		profile.Notes = append(profile.Notes, fmt.Sprintf("Decrypted %s (%s)", member, encryption))
+	}
+
+	profile.ProcessingTime = time.Since(startTime)
+
+	return profile, nil
+}
+
+// openZipMember opens a member of an archive, decrypting ZipCrypto and
+// WinZip AES entries. It also returns the name of the encryption, "" for
+// entries that are not encrypted.
+func openZipMember(f *zip.File, password string) (io.Reader, string, error) {
+	if f.Flags&0x1 == 0 {
+		r, err := f.Open()
+		return r, "", err
+	}
+	if password == "" {
+		return nil, "", ErrPasswordRequired
+	}
+
+	raw, err := f.OpenRaw()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var data io.Reader
+	var encryption string
+	method := f.Method
+	checkCRC := true
+
+	if f.Method == zipAESMethod {
+		extra, ok := parseZipAESExtra(f.Extra)
+		if !ok {
+			return nil, "", fmt.Errorf("%w: AES entry without its extra field", ErrUnsupportedEncryption)
+		}
+		keyLen, ok := zipAESKeyLengths[extra.strength]
+		if !ok {
+			return nil, "", fmt.Errorf("%w: AES strength %d", ErrUnsupportedEncryption, extra.strength)
+		}
+		if data, err = newZipAESReader(raw, f.CompressedSize64, password, keyLen); err != nil {
+			return nil, "", err
+		}
+		encryption = fmt.Sprintf("AES-%d", keyLen*8)
+		method = extra.method
+		// AE-2 entries leave the CRC out and rely on the authentication code
+		checkCRC = extra.version == 1
+	} else {
+		// With a data descriptor the CRC is not known up front, so the
+		// header check byte is the high byte of the modification time
+		check := byte(f.CRC32 >> 24)
+		if f.Flags&0x8 != 0 {
+			check = byte(f.ModifiedTime >> 8)
+		}
+		if data, err = newZipCryptoReader(raw, password, check); err != nil {
+			return nil, "", err
+		}
+		encryption = "ZipCrypto"
+	}
+
+	decrypted := data
+	switch method {
+	case zip.Store:
+	case zip.Deflate:
+		data = flate.NewReader(data)
+	default:
+		return nil, "", fmt.Errorf("unsupported zip compression method %d", method)
+	}
+
+	checked := &zipCheckReader{r: data, decrypted: decrypted, want: f.CRC32}
+	if checkCRC {
+		checked.crc = crc32.NewIEEE()
+	}
+	return checked, encryption, nil
+}
+
+// zipCheckReader turns read errors of decrypted data into integrity errors,
+// since a ZipCrypto password can pass the header check by chance, and
+// compares the CRC at the end. The decrypted stream is read to its end
+// after the data, because a deflate stream can finish before the AES
+// authentication code is reached.
+type zipCheckReader struct {
+	r         io.Reader
+	decrypted io.Reader
+	crc       hash.Hash32
+	want      uint32
+}
+
+func (z *zipCheckReader) Read(p []byte) (int, error) {
+	n, err := z.r.Read(p)
+	if z.crc != nil {
+		z.crc.Write(p[:n])
+	}
+	if err == io.EOF {
+		if _, drainErr := io.Copy(io.Discard, z.decrypted); drainErr != nil {
+			err = drainErr
+		}
+	}
+	switch {
+	case err == io.EOF && z.crc != nil && z.crc.Sum32() != z.want:
+		return n, fmt.Errorf("%w: CRC mismatch, the password may be wrong", ErrIntegrityCheck)
+	case err != nil && err != io.EOF && !errors.Is(err, ErrIntegrityCheck):
+		return n, fmt.Errorf("%w: %v", ErrIntegrityCheck, err)
+	}
+	return n, err
+}
+
+// profileArchiveMerged profiles every data file of an archive as one
+// dataset, for exports split into parts. The members must share a format,
+// and CSV and TSV columns are matched to the header of the first member by
+// name.
+func profileArchiveMerged(filePath string, names []string, opts Options) (*DatasetProfile, error) {
+	startTime := time.Now()
+
+	if opts.MaxBytes > 0 {
+		return nil, fmt.Errorf("--range is not supported when merging archive members, choose one with --member")
+	}
+
+	format := ""
+	for _, name := range names {
+		memberFormat, err := archiveMemberFormat(path.Base(name), opts)
+		if err != nil {
+			return nil, err
+		}
+		if format != "" && memberFormat != format {
+			return nil, fmt.Errorf("archive members have different formats (%s and %s), choose one with --member", format, memberFormat)
+		}
+		format = memberFormat
+	}
+
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file stats: %w", err)
+	}
+
+	walker, err := openArchive(filePath, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer walker.close()
+
+	merge := &archiveMerge{walker: walker, opts: opts}
+	name := filepath.Base(filePath)
+
+	var profile *DatasetProfile
+	switch format {
+	case FormatJSONL:
+		concat := &archiveConcatReader{merge: merge}
+		defer concat.close()
+		profile, err = profileJSONL(concat, trimCompressionExt(name), fileInfo.Size(), opts)
+	case FormatTSV:
+		profile, err = merge.profileDelimited(name, fileInfo.Size(), '\t', "TSV")
+	default:
+		profile, err = merge.profileDelimited(name, fileInfo.Size(), 0, "CSV")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	profile.Filename = name
+	if profile.Compression == "" {
+		profile.Compression = merge.codec
+	}
+	profile.Notes = append([]string{fmt.Sprintf("Merged %d files from the archive: %s", len(names), strings.Join(names, ", "))}, profile.Notes...)
+	profile.Notes = append(profile.Notes, merge.notes...)
+	if merge.decrypted > 0 {
+		profile.Notes = append(profile.Notes, fmt.Sprintf("Decrypted %d files (%s)", merge.decrypted, merge.encryption))
+	}
+
+	profile.ProcessingTime = time.Since(startTime)
+
+	return profile, nil
+}
+
+// archiveMerge opens the members of an archive one after another.
+type archiveMerge struct {
+	walker     archiveWalker
+	opts       Options
+	codec      string
+	encryption string
+	decrypted  int
+	notes      []string
+}
+
+func (m *archiveMerge) open() (*archiveEntry, io.Reader, error) {
+	entry, err := m.walker.next()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, encryption, err := entry.open()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s from archive: %w", entry.name, err)
+	}
+	if m.codec == "" {
+		m.codec = entry.codec
+	}
+	if encryption != "" {
+		m.encryption = encryption
+		m.decrypted++
+	}
+	return entry, r, nil
+}
+
+func (m *archiveMerge) profileDelimited(name string, size int64, comma rune, format string) (*DatasetProfile, error) {
+	entry, r, err := m.open()
+	if err != nil {
+		return nil, err
+	}
+	current, err := newDelimitedReader(r, path.Base(entry.name), comma, format, m.opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", entry.name, err)
+	}
+	currentName := entry.name
+
+	header := current.header
+	index := make(map[string]int, len(header))
+	for i, col := range header {
+		index[col] = i
+	}
+
+	profile := newDatasetProfile(name, size, format, header)
+
+	var mapping []int // nil while reading the first member
+	finish := func() {
+		if current != nil {
+			m.describe(profile, current, currentName)
+			current.close()
+			current = nil
+		}
+	}
+	defer finish()
+
+	next := func() ([]string, error) {
+		for {
+			record, err := current.next()
+			if err != io.EOF {
+				if err != nil || mapping == nil {
+					return record, err
+				}
+				aligned := make([]string, len(header))
+				for i, value := range record {
+					if i < len(mapping) && mapping[i] >= 0 {
+						aligned[mapping[i]] = value
+					}
+				}
+				return aligned, nil
+			}
+
+			finish()
+			entry, r, err := m.open()
+			if err != nil {
+				return nil, err
+			}
+			if current, err = newDelimitedReader(r, path.Base(entry.name), comma, format, m.opts); err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", entry.name, err)
+			}
+			currentName = entry.name
+			mapping = m.align(header, index, current.header, entry.name)
+		}
+	}
+
+	if err := profileRows(profile, header, next, m.opts); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// describe adds the notes of one member, prefixed with its name, and takes
+// the encoding from the first member.
+func (m *archiveMerge) describe(profile *DatasetProfile, reader *delimitedReader, name string) {
+	member := &DatasetProfile{}
+	reader.describe(member)
+
+	if profile.Encoding == "" {
+		profile.Encoding = member.Encoding
+	}
+	profile.BadRows += member.BadRows
+	for _, note := range member.Notes {
+		m.notes = append(m.notes, name+": "+note)
+	}
+}
+
+// align maps the columns of a member to the merged header, noting columns
+// the first member did not have and columns the member lacks.
+func (m *archiveMerge) align(header []string, index map[string]int, memberHeader []string, name string) []int {
+	mapping := make([]int, len(memberHeader))
+	found := make(map[int]bool)
+	var extra, missing []string
+
+	for i, col := range memberHeader {
+		j, ok := index[col]
+		if !ok {
+			mapping[i] = -1
+			extra = append(extra, col)
+			continue
+		}
+		mapping[i] = j
+		found[j] = true
+	}
+	for j, col := range header {
+		if !found[j] {
+			missing = append(missing, col)
+		}
+	}
+
+	if len(extra) > 0 {
+		m.notes = append(m.notes, fmt.Sprintf("%s: columns not in the first file were not profiled: %s", name, strings.Join(extra, ", ")))
+	}
+	if len(missing) > 0 {
+		m.notes = append(m.notes, fmt.Sprintf("%s: missing columns counted as empty: %s", name, strings.Join(missing, ", ")))
+	}
+	return mapping
+}
+
+// archiveConcatReader reads the members of an archive back to back, each
+// decompressed on its own, for JSON Lines where records need no header.
+type archiveConcatReader struct {
+	merge   *archiveMerge
+	current *decompressor
+	done    bool
+}
+
+func (c *archiveConcatReader) Read(p []byte) (int, error) {
+	for !c.done {
+		if c.current == nil {
+			entry, r, err := c.merge.open()
+			if err == io.EOF {
+				c.done = true
+				break
+			}
+			if err != nil {
+				return 0, err
+			}
+			if c.current, err = decompress(r, entry.name); err != nil {
+				return 0, err
+			}
+		}
+
+		n, err := c.current.Read(p)
+		if err == io.EOF {
+			c.close()
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+	return 0, io.EOF
+}
+
+func (c *archiveConcatReader) close() {
+	if c.current != nil {
+		c.current.Close()
+		c.current = nil
+	}
+}
@@ -0,0 +1,415 @@
+package profiler
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const zipTestCSV = "name,amount\nAnn,10\nBob,20\nCy,30\n"
+
+func deflateBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		t.Fatalf("Failed to create deflate writer: %v", err)
+	}
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+// zipCryptoEncrypt encrypts data the way zip -e does, with a header whose
+// last byte is the high byte of the CRC.
+func zipCryptoEncrypt(password string, crc uint32, data []byte) []byte {
+	keys := newZipCryptoKeys(password)
+	plain := append([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, byte(crc >> 24)}, data...)
+
+	out := make([]byte, len(plain))
+	for i, p := range plain {
+		out[i] = p ^ keys.stream()
+		keys.update(p)
+	}
+	return out
+}
+
+// zipAESEncrypt encrypts data as a WinZip AES-256 entry: salt, password
+// verifier, data and authentication code.
+func zipAESEncrypt(t *testing.T, password string, data []byte) []byte {
+	t.Helper()
+
+	salt := bytes.Repeat([]byte{7}, 16)
+	keys, err := pbkdf2.Key(sha1.New, password, salt, 1000, 66)
+	if err != nil {
+		t.Fatalf("Failed to derive key: %v", err)
+	}
+	block, err := aes.NewCipher(keys[:32])
+	if err != nil {
+		t.Fatalf("Failed to create cipher: %v", err)
+	}
+
+	encrypted := make([]byte, len(data))
+	var stream [aes.BlockSize]byte
+	for i := range data {
+		if i%aes.BlockSize == 0 {
+			var counter [aes.BlockSize]byte
+			binary.LittleEndian.PutUint64(counter[:], uint64(i/aes.BlockSize+1))
+			block.Encrypt(stream[:], counter[:])
+		}
+		encrypted[i] = data[i] ^ stream[i%aes.BlockSize]
+	}
+
+	mac := hmac.New(sha1.New, keys[32:64])
+	mac.Write(encrypted)
+
+	out := append(append(salt, keys[64:]...), encrypted...)
+	return append(out, mac.Sum(nil)[:10]...)
+}
+
+type zipTestEntry struct {
+	name       string
+	content    string
+	encryption string // "", zipcrypto or aes
+	deflate    bool
+}
+
+func writeTestZip(t *testing.T, entries ...zipTestEntry) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		data := []byte(entry.content)
+		header := &zip.FileHeader{
+			Name:               entry.name,
+			Method:             zip.Store,
+			CRC32:              crc32.ChecksumIEEE(data),
+			UncompressedSize64: uint64(len(data)),
+			Modified:           time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+		if entry.deflate {
+			header.Method = zip.Deflate
+			data = deflateBytes(t, data)
+		}
+
+		switch entry.encryption {
+		case "zipcrypto":
+			header.Flags |= 0x1
+			data = zipCryptoEncrypt("secret", header.CRC32, data)
+		case "aes":
+			header.Flags |= 0x1
+			// AE-2, AES-256, with the real method after the vendor ID
+			header.Extra = []byte{0x01, 0x99, 7, 0, 2, 0, 'A', 'E', 3, byte(header.Method), 0}
+			header.Method = zipAESMethod
+			header.CRC32 = 0
+			data = zipAESEncrypt(t, "secret", data)
+		}
+		header.CompressedSize64 = uint64(len(data))
+
+		fw, err := w.CreateRaw(header)
+		if err != nil {
+			t.Fatalf("Failed to add zip entry: %v", err)
+		}
+		fw.Write(data)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to write zip: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "export.zip")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	return path
+}
+
+func TestProfileZip(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry zipTestEntry
+		note  string
+	}{
+		{"stored", zipTestEntry{name: "data.csv", content: zipTestCSV}, ""},
+		{"deflated", zipTestEntry{name: "exports/data.csv", content: zipTestCSV, deflate: true}, ""},
+		{"zipcrypto", zipTestEntry{name: "data.csv", content: zipTestCSV, encryption: "zipcrypto"}, "Decrypted data.csv (ZipCrypto)"},
+		{"zipcrypto deflated", zipTestEntry{name: "data.csv", content: zipTestCSV, encryption: "zipcrypto", deflate: true}, "Decrypted data.csv (ZipCrypto)"},
+		{"aes", zipTestEntry{name: "data.csv", content: zipTestCSV, encryption: "aes"}, "Decrypted data.csv (AES-256)"},
+		{"aes deflated", zipTestEntry{name: "data.csv", content: zipTestCSV, encryption: "aes", deflate: true}, "Decrypted data.csv (AES-256)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestZip(t, tt.entry)
+
+			profile, err := ProfileDatasetWithOptions(path, Options{Password: "secret"})
+			if err != nil {
+				t.Fatalf("Failed to profile: %v", err)
+			}
+
+			if profile.RowCount != 3 || profile.ColumnCount != 2 {
+				t.Errorf("Expected 3 rows and 2 columns, got %d and %d", profile.RowCount, profile.ColumnCount)
+			}
+			if profile.Filename != "export.zip/"+tt.entry.name {
+				t.Errorf("Unexpected filename %s", profile.Filename)
+			}
+			if tt.entry.deflate && profile.Compression != "zip" {
+				t.Errorf("Expected zip compression, got %q", profile.Compression)
+			}
+			if tt.note != "" && !containsString(profile.Notes, tt.note) {
+				t.Errorf("Expected note %q, got %v", tt.note, profile.Notes)
+			}
+		})
+	}
+}
+
+func TestProfileZipPasswordErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    zipTestEntry
+		password string
+		want     error
+	}{
+		{"zipcrypto without password", zipTestEntry{name: "data.csv", content: zipTestCSV, encryption: "zipcrypto"}, "", ErrPasswordRequired},
+		{"zipcrypto wrong password", zipTestEntry{name: "data.csv", content: zipTestCSV, encryption: "zipcrypto"}, "wrong", ErrWrongPassword},
+		{"aes without password", zipTestEntry{name: "data.csv", content: zipTestCSV, encryption: "aes"}, "", ErrPasswordRequired},
+		{"aes wrong password", zipTestEntry{name: "data.csv", content: zipTestCSV, encryption: "aes"}, "wrong", ErrWrongPassword},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestZip(t, tt.entry)
+
+			_, err := ProfileDatasetWithOptions(path, Options{Password: tt.password})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestProfileZipTampered(t *testing.T) {
+	path := writeTestZip(t, zipTestEntry{name: "data.csv", content: zipTestCSV, encryption: "aes"})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read zip: %v", err)
+	}
+	// Flip a byte of the encrypted data, after the local header, salt and
+	// password verifier
+	offset := 30 + len("data.csv") + 11 + 18 + 4
+	data[offset] ^= 0xff
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write zip: %v", err)
+	}
+
+	_, err = ProfileDatasetWithOptions(path, Options{Password: "secret"})
+	if !errors.Is(err, ErrIntegrityCheck) {
+		t.Errorf("Expected an integrity error, got %v", err)
+	}
+}
+
+func TestProfileZipMembers(t *testing.T) {
+	path := writeTestZip(t,
+		zipTestEntry{name: "__MACOSX/._data.csv", content: "x"},
+		zipTestEntry{name: "README.md", content: "# Export"},
+		zipTestEntry{name: "data.tsv", content: "a\tb\n1\t2\n"},
+	)
+	profile, err := ProfileDataset(path)
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if profile.Format != "TSV" || profile.ColumnCount != 2 {
+		t.Errorf("Expected a 2-column TSV, got %s with %d columns", profile.Format, profile.ColumnCount)
+	}
+}
+
+func writeTestTar(t *testing.T, name string, compressed bool, files map[string]string, order []string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	tw.WriteHeader(&tar.Header{Name: "data/", Typeflag: tar.TypeDir, Mode: 0755})
+	for _, file := range order {
+		content := files[file]
+		if err := tw.WriteHeader(&tar.Header{Name: file, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))}); err != nil {
+			t.Fatalf("Failed to write tar header: %v", err)
+		}
+		tw.Write([]byte(content))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Failed to write tar: %v", err)
+	}
+
+	data := buf.Bytes()
+	if compressed {
+		data = gzipBytes(t, string(data))
+	}
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	return path
+}
+
+func TestIsArchive(t *testing.T) {
+	for path, want := range map[string]bool{
+		"export.zip":      true,
+		"bundle.tar":      true,
+		"bundle.tar.gz":   true,
+		"bundle.tgz":      true,
+		"bundle.TAR.ZST":  true,
+		"data.csv.gz":     false,
+		"data.csv":        false,
+		"archive.zip.csv": false,
+	} {
+		if got := IsArchive(path); got != want {
+			t.Errorf("IsArchive(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestProfileTarMember(t *testing.T) {
+	files := map[string]string{
+		"data/part1.csv":   "id,amount\n1,10\n2,20\n",
+		"data/part2.csv":   "id,amount\n3,30\n",
+		"data/.hidden.csv": "x\n1\n",
+	}
+	path := writeTestTar(t, "bundle.tar.gz", true, files, []string{"data/part1.csv", "data/.hidden.csv", "data/part2.csv"})
+
+	profile, err := ProfileDatasetWithOptions(path, Options{Member: "data/part2.csv"})
+	if err != nil {
+		t.Fatalf("Failed to profile member: %v", err)
+	}
+	if profile.Filename != "bundle.tar.gz/data/part2.csv" || profile.RowCount != 1 {
+		t.Errorf("Expected the single row of part2.csv, got %s with %d rows", profile.Filename, profile.RowCount)
+	}
+	if profile.Compression != CompressionGzip {
+		t.Errorf("Expected gzip compression, got %q", profile.Compression)
+	}
+
+	_, err = ProfileDatasetWithOptions(path, Options{Member: "data/part3.csv"})
+	if err == nil || !strings.Contains(err.Error(), "data files: data/part1.csv, data/part2.csv") {
+		t.Errorf("Expected an error listing the data files, got %v", err)
+	}
+}
+
+func TestProfileArchiveMerged(t *testing.T) {
+	files := map[string]string{
+		"data/part1.csv": "id,amount,region\n1,10,north\n2,20,south\n",
+		"data/part2.csv": "region,id,amount,extra\nwest,3,30,x\n",
+		"data/part3.csv": "id,amount\n4,40\n",
+	}
+	order := []string{"data/part1.csv", "data/part2.csv", "data/part3.csv"}
+
+	for _, name := range []string{"bundle.tar", "bundle.tgz"} {
+		t.Run(name, func(t *testing.T) {
+			path := writeTestTar(t, name, name == "bundle.tgz", files, order)
+
+			profile, err := ProfileDataset(path)
+			if err != nil {
+				t.Fatalf("Failed to profile archive: %v", err)
+			}
+
+			if profile.Filename != name || profile.RowCount != 4 || profile.ColumnCount != 3 {
+				t.Errorf("Expected 4 merged rows of 3 columns, got %s with %d rows and %d columns", profile.Filename, profile.RowCount, profile.ColumnCount)
+			}
+			if region := profile.Columns["region"]; region == nil || region.MissingCount != 1 {
+				t.Errorf("Expected one missing region from part3.csv, got %+v", region)
+			}
+			if amount := profile.Columns["amount"]; amount == nil || amount.Max != 40.0 || amount.Mean != 25 {
+				t.Errorf("Expected amounts aligned by column name, got %+v", amount)
+			}
+
+			notes := strings.Join(profile.Notes, "\n")
+			for _, want := range []string{
+				"Merged 3 files from the archive: data/part1.csv, data/part2.csv, data/part3.csv",
+				"data/part2.csv: columns not in the first file were not profiled: extra",
+				"data/part3.csv: missing columns counted as empty: region",
+			} {
+				if !strings.Contains(notes, want) {
+					t.Errorf("Expected note %q, got %v", want, profile.Notes)
+				}
+			}
+		})
+	}
+}
+
+func TestProfileArchiveMergedJSONL(t *testing.T) {
+	path := writeTestZip(t,
+		zipTestEntry{name: "events-1.jsonl", content: "{\"id\":1}\n{\"id\":2}\n", encryption: "aes"},
+		zipTestEntry{name: "events-2.jsonl", content: "{\"id\":3}", encryption: "aes", deflate: true},
+	)
+
+	profile, err := ProfileDatasetWithOptions(path, Options{Password: "secret"})
+	if err != nil {
+		t.Fatalf("Failed to profile archive: %v", err)
+	}
+	if profile.Format != "JSONL" || profile.RowCount != 3 {
+		t.Errorf("Expected 3 merged JSONL rows, got %s with %d rows", profile.Format, profile.RowCount)
+	}
+	if !containsString(profile.Notes, "Decrypted 2 files (AES-256)") {
+		t.Errorf("Expected a decryption note, got %v", profile.Notes)
+	}
+}
+
+func TestProfileArchiveMergedErrors(t *testing.T) {
+	path := writeTestZip(t,
+		zipTestEntry{name: "a.csv", content: zipTestCSV},
+		zipTestEntry{name: "b.jsonl", content: "{\"id\":1}\n"},
+	)
+	if _, err := ProfileDataset(path); err == nil || !strings.Contains(err.Error(), "different formats") {
+		t.Errorf("Expected a mixed format error, got %v", err)
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{MaxBytes: 100}); err == nil || !strings.Contains(err.Error(), "--range is not supported when merging") {
+		t.Errorf("Expected a --range error, got %v", err)
+	}
+
+	if _, err := ProfileDatasetWithOptions("data.csv", Options{Member: "a.csv"}); err == nil || !strings.Contains(err.Error(), "--member is only supported") {
+		t.Errorf("Expected a --member error, got %v", err)
+	}
+}
+
+func TestProfileEncryptedExcel(t *testing.T) {
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]interface{}{"name", "amount"})
+	f.SetSheetRow("Sheet1", "A2", &[]interface{}{"Ann", 10})
+	path := filepath.Join(t.TempDir(), "payroll.xlsx")
+	if err := f.SaveAs(path, excelize.Options{Password: "secret"}); err != nil {
+		t.Fatalf("Failed to save workbook: %v", err)
+	}
+	f.Close()
+
+	if _, err := ProfileDataset(path); !errors.Is(err, ErrPasswordRequired) {
+		t.Errorf("Expected ErrPasswordRequired, got %v", err)
+	}
+	if _, err := ProfileDatasetWithOptions(path, Options{Password: "wrong"}); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("Expected ErrWrongPassword, got %v", err)
+	}
+
+	profile, err := ProfileDatasetWithOptions(path, Options{Password: "secret"})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if profile.RowCount != 1 || profile.ColumnCount != 2 {
+		t.Errorf("Expected 1 row and 2 columns, got %d and %d", profile.RowCount, profile.ColumnCount)
+	}
+}
@@ -0,0 +1,714 @@
+package profiler
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+var avroMagic = []byte{'O', 'b', 'j', 1}
+
+// avroSchema is a parsed Avro schema. Named types are shared, so a record
+// that refers to itself is a cycle.
+type avroSchema struct {
+	kind     string // a primitive type, record, enum, array, map, union or fixed
+	name     string
+	fields   []avroField
+	symbols  []string
+	items    *avroSchema // array items and map values
+	branches []*avroSchema
+	size     int
+	logical  string // logical type, such as date or decimal
+	scale    int    // digits after the point of a decimal
+}
+
+type avroField struct {
+	name   string
+	schema *avroSchema
+}
+
+var avroPrimitives = map[string]bool{
+	"null": true, "boolean": true, "int": true, "long": true,
+	"float": true, "double": true, "bytes": true, "string": true,
+}
+
+// parseAvroSchema parses a schema in its JSON form.
+func parseAvroSchema(data []byte) (*avroSchema, error) {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid Avro schema: %w", err)
+	}
+	return newAvroSchemaParser().parse(raw, "")
+}
+
+type avroSchemaParser struct {
+	named map[string]*avroSchema
+}
+
+func newAvroSchemaParser() *avroSchemaParser {
+	return &avroSchemaParser{named: make(map[string]*avroSchema)}
+}
+
+func (p *avroSchemaParser) parse(raw interface{}, namespace string) (*avroSchema, error) {
+	switch v := raw.(type) {
+	case string:
+		if avroPrimitives[v] {
+			return &avroSchema{kind: v}, nil
+		}
+		if s, ok := p.lookup(v, namespace); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("invalid Avro schema: unknown type %q", v)
+	case []interface{}:
+		union := &avroSchema{kind: "union"}
+		for _, branch := range v {
+			s, err := p.parse(branch, namespace)
+			if err != nil {
+				return nil, err
+			}
+			union.branches = append(union.branches, s)
+		}
+		return union, nil
+	case map[string]interface{}:
+		return p.parseComplex(v, namespace)
+	default:
+		return nil, fmt.Errorf("invalid Avro schema: unexpected %v", raw)
+	}
+}
+
+func (p *avroSchemaParser) parseComplex(v map[string]interface{}, namespace string) (*avroSchema, error) {
+	kind, _ := v["type"].(string)
+	if kind == "" {
+		// {"type": {...}} wraps another schema
+		return p.parse(v["type"], namespace)
+	}
+
+	switch kind {
+	case "record", "error", "enum", "fixed":
+		s := &avroSchema{kind: kind}
+		if kind == "error" {
+			s.kind = "record"
+		}
+		name, _ := v["name"].(string)
+		if ns, ok := v["namespace"].(string); ok {
+			namespace = ns
+		}
+		s.name = avroFullName(name, namespace)
+		p.named[s.name] = s
+
+		switch kind {
+		case "enum":
+			for _, symbol := range toSlice(v["symbols"]) {
+				str, _ := symbol.(string)
+				s.symbols = append(s.symbols, str)
+			}
+		case "fixed":
+			size, _ := v["size"].(float64)
+			s.size = int(size)
+			setAvroLogicalType(s, v)
+		default:
+			for _, raw := range toSlice(v["fields"]) {
+				field, _ := raw.(map[string]interface{})
+				fieldName, _ := field["name"].(string)
+				fieldSchema, err := p.parse(field["type"], avroNamespace(s.name))
+				if err != nil {
+					return nil, err
+				}
+				s.fields = append(s.fields, avroField{name: fieldName, schema: fieldSchema})
+			}
+		}
+		return s, nil
+	case "array":
+		items, err := p.parse(v["items"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &avroSchema{kind: "array", items: items}, nil
+	case "map":
+		values, err := p.parse(v["values"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &avroSchema{kind: "map", items: values}, nil
+	default:
+		// Primitives with attributes, such as logical types
+		s, err := p.parse(kind, namespace)
+		if err == nil && avroPrimitives[kind] {
+			setAvroLogicalType(s, v)
+		}
+		return s, err
+	}
+}
+
+func setAvroLogicalType(s *avroSchema, v map[string]interface{}) {
+	s.logical, _ = v["logicalType"].(string)
+	scale, _ := v["scale"].(float64)
+	s.scale = int(scale)
+}
+
+func (p *avroSchemaParser) lookup(name, namespace string) (*avroSchema, bool) {
+	if s, ok := p.named[avroFullName(name, namespace)]; ok {
+		return s, true
+	}
+	s, ok := p.named[name]
+	return s, ok
+}
+
+func avroFullName(name, namespace string) string {
+	if namespace == "" || strings.Contains(name, ".") {
+		return name
+	}
+	return namespace + "." + name
+}
+
+func avroNamespace(fullName string) string {
+	if i := strings.LastIndex(fullName, "."); i >= 0 {
+		return fullName[:i]
+	}
+	return ""
+}
+
+func toSlice(v interface{}) []interface{} {
+	slice, _ := v.([]interface{})
+	return slice
+}
+
+// avroReader reads the records of an Avro object container file. Records
+// decode to map[string]interface{}, with longs and ints as int64, floats as
+// float64, bytes and fixed as []byte and a union as the value of its branch.
+type avroReader struct {
+	r      *bufio.Reader
+	schema *avroSchema
+	meta   map[string][]byte
+	codec  string
+	sync   []byte
+	block  *bytes.Reader
+	left   int64
+}
+
+func newAvroReader(r io.Reader) (*avroReader, error) {
+	a := &avroReader{r: bufio.NewReader(r), meta: make(map[string][]byte)}
+
+	magic := make([]byte, len(avroMagic))
+	if _, err := io.ReadFull(a.r, magic); err != nil || !bytes.Equal(magic, avroMagic) {
+		return nil, fmt.Errorf("not an Avro object container file")
+	}
+
+	meta, err := decodeAvro(a.r, &avroSchema{kind: "map", items: &avroSchema{kind: "bytes"}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Avro header: %w", err)
+	}
+	for key, value := range meta.(map[string]interface{}) {
+		a.meta[key] = value.([]byte)
+	}
+
+	a.sync = make([]byte, 16)
+	if _, err := io.ReadFull(a.r, a.sync); err != nil {
+		return nil, fmt.Errorf("failed to read Avro header: %w", err)
+	}
+
+	if a.schema, err = parseAvroSchema(a.meta["avro.schema"]); err != nil {
+		return nil, err
+	}
+
+	a.codec = string(a.meta["avro.codec"])
+	switch a.codec {
+	case "", "null", "deflate", "snappy", "zstandard":
+	default:
+		return nil, fmt.Errorf("unsupported Avro codec: %s", a.codec)
+	}
+
+	return a, nil
+}
+
+// next returns the next record, or io.EOF after the last block.
+func (a *avroReader) next() (interface{}, error) {
+	if err := a.advance(); err != nil {
+		return nil, err
+	}
+
+	value, err := decodeAvro(a.block, a.schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode Avro record: %w", err)
+	}
+	return value, nil
+}
+
+// advance moves on to the next record, leaving it to be decoded from
+// a.block, or returns io.EOF after the last block.
+func (a *avroReader) advance() error {
+	for a.left == 0 {
+		if err := a.readBlock(); err != nil {
+			return err
+		}
+	}
+	a.left--
+	return nil
+}
+
+// rows counts the records of the blocks left without decoding them.
+func (a *avroReader) rows() (int64, error) {
+	rows := a.left
+	a.left = 0
+	for {
+		count, err := readAvroLong(a.r)
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read Avro block: %w", err)
+		}
+		size, err := readAvroLong(a.r)
+		if err != nil || size < 0 || count < 0 {
+			return 0, fmt.Errorf("failed to read Avro block: invalid size")
+		}
+		if _, err := a.r.Discard(int(size) + len(a.sync)); err != nil {
+			return 0, fmt.Errorf("failed to read Avro block: %w", err)
+		}
+		rows += count
+	}
+}
+
+func (a *avroReader) readBlock() error {
+	count, err := readAvroLong(a.r)
+	if err == io.EOF {
+		return io.EOF
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read Avro block: %w", err)
+	}
+	size, err := readAvroLong(a.r)
+	if err != nil || size < 0 {
+		return fmt.Errorf("failed to read Avro block: invalid size")
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(a.r, data); err != nil {
+		return fmt.Errorf("failed to read Avro block: %w", err)
+	}
+	sync := make([]byte, len(a.sync))
+	if _, err := io.ReadFull(a.r, sync); err != nil || !bytes.Equal(sync, a.sync) {
+		return fmt.Errorf("failed to read Avro block: sync marker mismatch")
+	}
+
+	if data, err = a.decompress(data); err != nil {
+		return fmt.Errorf("failed to decompress Avro block: %w", err)
+	}
+
+	a.block = bytes.NewReader(data)
+	a.left = count
+	return nil
+}
+
+func (a *avroReader) decompress(data []byte) ([]byte, error) {
+	switch a.codec {
+	case "deflate":
+		return io.ReadAll(flate.NewReader(bytes.NewReader(data)))
+	case "snappy":
+		// The block ends with the CRC-32 of the uncompressed data
+		if len(data) < 4 {
+			return nil, errors.New("block too short")
+		}
+		out, err := snappy.Decode(nil, data[:len(data)-4])
+		if err != nil {
+			return nil, err
+		}
+		if crc32.ChecksumIEEE(out) != binary.BigEndian.Uint32(data[len(data)-4:]) {
+			return nil, errors.New("checksum mismatch")
+		}
+		return out, nil
+	case "zstandard":
+		decoder, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		defer decoder.Close()
+		return decoder.DecodeAll(data, nil)
+	default:
+		return data, nil
+	}
+}
+
+type avroByteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+func readAvroLong(r io.ByteReader) (int64, error) {
+	var value uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		b, err := r.ReadByte()
+		if err != nil {
+			if shift > 0 && err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		value |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return int64(value>>1) ^ -int64(value&1), nil
+		}
+	}
+	return 0, errors.New("varint overflows a long")
+}
+
+func readAvroBytes(r avroByteReader) ([]byte, error) {
+	n, err := readAvroLong(r)
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, errors.New("negative length")
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func decodeAvro(r avroByteReader, s *avroSchema) (interface{}, error) {
+	switch s.kind {
+	case "null":
+		return nil, nil
+	case "boolean":
+		b, err := r.ReadByte()
+		return b != 0, err
+	case "int", "long":
+		return readAvroLong(r)
+	case "float":
+		var buf [4]byte
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(buf[:]))), nil
+	case "double":
+		var buf [8]byte
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(buf[:])), nil
+	case "bytes":
+		return readAvroBytes(r)
+	case "string":
+		data, err := readAvroBytes(r)
+		return string(data), err
+	case "fixed":
+		data := make([]byte, s.size)
+		_, err := io.ReadFull(r, data)
+		return data, err
+	case "enum":
+		index, err := readAvroLong(r)
+		if err != nil {
+			return nil, err
+		}
+		if index < 0 || int(index) >= len(s.symbols) {
+			return nil, fmt.Errorf("enum index %d out of range", index)
+		}
+		return s.symbols[index], nil
+	case "union":
+		index, err := readAvroLong(r)
+		if err != nil {
+			return nil, err
+		}
+		if index < 0 || int(index) >= len(s.branches) {
+			return nil, fmt.Errorf("union index %d out of range", index)
+		}
+		return decodeAvro(r, s.branches[index])
+	case "record":
+		record := make(map[string]interface{}, len(s.fields))
+		for _, field := range s.fields {
+			value, err := decodeAvro(r, field.schema)
+			if err != nil {
+				return nil, err
+			}
+			record[field.name] = value
+		}
+		return record, nil
+	case "array":
+		items := make([]interface{}, 0)
+		err := readAvroBlocks(r, func() error {
+			item, err := decodeAvro(r, s.items)
+			items = append(items, item)
+			return err
+		})
+		return items, err
+	case "map":
+		entries := make(map[string]interface{})
+		err := readAvroBlocks(r, func() error {
+			key, err := readAvroBytes(r)
+			if err != nil {
+				return err
+			}
+			value, err := decodeAvro(r, s.items)
+			entries[string(key)] = value
+			return err
+		})
+		return entries, err
+	default:
+		return nil, fmt.Errorf("unsupported Avro type: %s", s.kind)
+	}
+}
+
+// readAvroBlocks reads the blocks of an array or map. A negative count is
+// followed by the block size in bytes.
+func readAvroBlocks(r avroByteReader, item func() error) error {
+	for {
+		count, err := readAvroLong(r)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return nil
+		}
+		if count < 0 {
+			count = -count
+			if _, err := readAvroLong(r); err != nil {
+				return err
+			}
+		}
+		for i := int64(0); i < count; i++ {
+			if err := item(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func profileAvroFile(filePath string, opts Options) (*DatasetProfile, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file stats: %w", err)
+	}
+
+	return profileAvro(file, filepath.Base(filePath), fileInfo.Size(), opts)
+}
+
+// profileAvro profiles the records of an Avro object container file as they
+// stream in, with a column per field of the schema in the file header.
+func profileAvro(r io.Reader, name string, size int64, opts Options) (*DatasetProfile, error) {
+	startTime := time.Now()
+
+	reader, header, columns, err := openAvroRecords(r, name, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := newDatasetProfile(name, size, "Avro", header)
+
+	next := func() ([]string, error) {
+		if err := reader.advance(); err != nil {
+			return nil, err
+		}
+		record := make([]string, len(header))
+		for _, column := range columns {
+			if err := column.read(reader.block, record); err != nil {
+				return nil, fmt.Errorf("failed to decode Avro record: %w", err)
+			}
+		}
+		return record, nil
+	}
+
+	if err := profileRows(profile, header, next, opts); err != nil {
+		return nil, err
+	}
+
+	profile.ProcessingTime = time.Since(startTime)
+
+	return profile, nil
+}
+
+// openAvroRecords reads the header of an Avro file of records and lays out
+// its columns.
+func openAvroRecords(r io.Reader, name string, opts Options) (*avroReader, []string, []*avroColumn, error) {
+	if err := opts.textOnly("Avro files"); err != nil {
+		return nil, nil, nil, err
+	}
+	if codec := compressionExt(name); codec != "" {
+		return nil, nil, nil, fmt.Errorf("%s-compressed Avro files are not supported: Avro compresses its blocks itself", codec)
+	}
+
+	reader, err := newAvroReader(r)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read Avro file: %w", err)
+	}
+	if reader.schema.kind != "record" {
+		return nil, nil, nil, fmt.Errorf("failed to read Avro file: its schema is a %s, not a record", reader.schema.kind)
+	}
+
+	header := make([]string, 0)
+	columns := avroColumns(reader.schema, "", &header, map[*avroSchema]bool{reader.schema: true})
+	if len(header) == 0 {
+		return nil, nil, nil, fmt.Errorf("no columns found in Avro schema")
+	}
+	return reader, header, columns, nil
+}
+
+// avroColumn is a field of the records of an Avro file. The fields of a
+// nested record, nullable or not, are flattened into columns named
+// parent.child; any other field is a column, arrays and maps holding
+// compact JSON.
+type avroColumn struct {
+	schema *avroSchema   // a union when the field is nullable
+	record *avroSchema   // the nested record flattened, nil for a column
+	fields []*avroColumn // columns of the nested record
+	index  int           // position of the column in the header
+}
+
+// avroColumns lays out the columns of the fields of record, appending their
+// names to header. Records already on the path, which refer to themselves,
+// are not flattened again.
+func avroColumns(record *avroSchema, prefix string, header *[]string, path map[*avroSchema]bool) []*avroColumn {
+	columns := make([]*avroColumn, 0, len(record.fields))
+	for _, field := range record.fields {
+		column := &avroColumn{schema: field.schema}
+		if nested := avroNestedRecord(field.schema); nested != nil && !path[nested] {
+			path[nested] = true
+			column.record = nested
+			column.fields = avroColumns(nested, prefix+field.name+".", header, path)
+			delete(path, nested)
+		} else {
+			column.index = len(*header)
+			*header = append(*header, prefix+field.name)
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// avroNestedRecord returns the record of a record field, or of a nullable
+// one.
+func avroNestedRecord(s *avroSchema) *avroSchema {
+	if s.kind == "union" && len(s.branches) == 2 {
+		for i, branch := range s.branches {
+			if branch.kind == "null" {
+				s = s.branches[1-i]
+			}
+		}
+	}
+	if s.kind == "record" {
+		return s
+	}
+	return nil
+}
+
+// read decodes the field from r into record, leaving it empty when null.
+func (c *avroColumn) read(r avroByteReader, record []string) error {
+	s := c.schema
+	if s.kind == "union" {
+		index, err := readAvroLong(r)
+		if err != nil {
+			return err
+		}
+		if index < 0 || int(index) >= len(s.branches) {
+			return fmt.Errorf("union index %d out of range", index)
+		}
+		if s = s.branches[index]; s.kind == "null" {
+			return nil
+		}
+	}
+
+	if c.record != nil {
+		for _, field := range c.fields {
+			if err := field.read(r, record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	value, err := decodeAvro(r, s)
+	if err != nil {
+		return err
+	}
+	record[c.index] = formatAvroValue(value, s)
+	return nil
+}
+
+// formatAvroValue writes a value decoded with s as the profiler reads it:
+// dates and timestamps in ISO 8601, decimals with their scale, and arrays,
+// maps and records as compact JSON.
+func formatAvroValue(value interface{}, s *avroSchema) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		switch s.logical {
+		case "date":
+			return time.Unix(v*86400, 0).UTC().Format("2006-01-02")
+		case "timestamp-millis", "local-timestamp-millis":
+			return time.UnixMilli(v).UTC().Format(time.RFC3339Nano)
+		case "timestamp-micros", "local-timestamp-micros":
+			return time.UnixMicro(v).UTC().Format(time.RFC3339Nano)
+		case "timestamp-nanos", "local-timestamp-nanos":
+			return time.Unix(0, v).UTC().Format(time.RFC3339Nano)
+		case "time-millis":
+			return time.UnixMilli(v).UTC().Format("15:04:05.999")
+		case "time-micros":
+			return time.UnixMicro(v).UTC().Format("15:04:05.999999")
+		}
+		return strconv.FormatInt(v, 10)
+	case float64:
+		if s.kind == "float" {
+			return strconv.FormatFloat(v, 'g', -1, 32)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		return v
+	case []byte:
+		if s.logical == "decimal" {
+			// Two's complement, big-endian
+			unscaled := new(big.Int).SetBytes(v)
+			if len(v) > 0 && v[0]&0x80 != 0 {
+				unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(8*len(v))))
+			}
+			return formatDecimal(unscaled, s.scale)
+		}
+		return string(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+// formatDecimal writes unscaled / 10^scale exactly.
+func formatDecimal(unscaled *big.Int, scale int) string {
+	digits := new(big.Int).Abs(unscaled).String()
+	if scale > 0 {
+		if len(digits) <= scale {
+			digits = strings.Repeat("0", scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+	}
+	if unscaled.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
@@ -0,0 +1,358 @@
+package profiler
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"hash/crc32"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/snappy"
+)
+
+func appendAvroLong(buf []byte, v int64) []byte {
+	u := uint64(v<<1) ^ uint64(v>>63)
+	for u >= 0x80 {
+		buf = append(buf, byte(u)|0x80)
+		u >>= 7
+	}
+	return append(buf, byte(u))
+}
+
+func appendAvroBytes(buf, data []byte) []byte {
+	return append(appendAvroLong(buf, int64(len(data))), data...)
+}
+
+// encodeAvro is the inverse of decodeAvro for the values it returns. A nil
+// value picks the null branch of a union, anything else the first other.
+func encodeAvro(t *testing.T, buf []byte, s *avroSchema, value interface{}) []byte {
+	t.Helper()
+
+	switch s.kind {
+	case "null":
+		return buf
+	case "boolean":
+		if value.(bool) {
+			return append(buf, 1)
+		}
+		return append(buf, 0)
+	case "int", "long":
+		return appendAvroLong(buf, value.(int64))
+	case "float":
+		return binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(value.(float64))))
+	case "double":
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(value.(float64)))
+	case "bytes":
+		return appendAvroBytes(buf, value.([]byte))
+	case "fixed":
+		return append(buf, value.([]byte)...)
+	case "string":
+		return appendAvroBytes(buf, []byte(value.(string)))
+	case "enum":
+		for i, symbol := range s.symbols {
+			if symbol == value.(string) {
+				return appendAvroLong(buf, int64(i))
+			}
+		}
+		t.Fatalf("Unknown enum symbol %v", value)
+	case "union":
+		for i, branch := range s.branches {
+			if (value == nil) == (branch.kind == "null") {
+				return encodeAvro(t, appendAvroLong(buf, int64(i)), branch, value)
+			}
+		}
+		t.Fatalf("No union branch for %v", value)
+	case "record":
+		record := value.(map[string]interface{})
+		for _, field := range s.fields {
+			buf = encodeAvro(t, buf, field.schema, record[field.name])
+		}
+		return buf
+	case "array":
+		items := value.([]interface{})
+		if len(items) > 0 {
+			buf = appendAvroLong(buf, int64(len(items)))
+			for _, item := range items {
+				buf = encodeAvro(t, buf, s.items, item)
+			}
+		}
+		return appendAvroLong(buf, 0)
+	case "map":
+		entries := value.(map[string]interface{})
+		if len(entries) > 0 {
+			buf = appendAvroLong(buf, int64(len(entries)))
+			for key, entry := range entries {
+				buf = appendAvroBytes(buf, []byte(key))
+				buf = encodeAvro(t, buf, s.items, entry)
+			}
+		}
+		return appendAvroLong(buf, 0)
+	}
+	t.Fatalf("Unsupported Avro type %s", s.kind)
+	return nil
+}
+
+// writeTestAvro builds an object container file holding records in two
+// blocks.
+func writeTestAvro(t *testing.T, schema, codec string, records []interface{}) []byte {
+	t.Helper()
+
+	parsed, err := parseAvroSchema([]byte(schema))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	sync := []byte("0123456789abcdef")
+	buf := append([]byte(nil), avroMagic...)
+	buf = appendAvroLong(buf, 2)
+	buf = appendAvroBytes(buf, []byte("avro.schema"))
+	buf = appendAvroBytes(buf, []byte(schema))
+	buf = appendAvroBytes(buf, []byte("avro.codec"))
+	buf = appendAvroBytes(buf, []byte(codec))
+	buf = appendAvroLong(buf, 0)
+	buf = append(buf, sync...)
+
+	half := (len(records) + 1) / 2
+	for _, block := range [][]interface{}{records[:half], records[half:]} {
+		var data []byte
+		for _, record := range block {
+			data = encodeAvro(t, data, parsed, record)
+		}
+
+		switch codec {
+		case "deflate":
+			var out bytes.Buffer
+			w, _ := flate.NewWriter(&out, flate.DefaultCompression)
+			w.Write(data)
+			w.Close()
+			data = out.Bytes()
+		case "snappy":
+			checksum := crc32.ChecksumIEEE(data)
+			data = binary.BigEndian.AppendUint32(snappy.Encode(nil, data), checksum)
+		}
+
+		buf = appendAvroLong(buf, int64(len(block)))
+		buf = appendAvroBytes(buf, data)
+		buf = append(buf, sync...)
+	}
+	return buf
+}
+
+const testAvroSchema = `{
+	"type": "record", "name": "Event", "namespace": "test",
+	"fields": [
+		{"name": "id", "type": "long"},
+		{"name": "name", "type": ["null", "string"]},
+		{"name": "score", "type": "double"},
+		{"name": "ratio", "type": "float"},
+		{"name": "active", "type": "boolean"},
+		{"name": "kind", "type": {"type": "enum", "name": "Kind", "symbols": ["A", "B"]}},
+		{"name": "tags", "type": {"type": "array", "items": "string"}},
+		{"name": "attrs", "type": {"type": "map", "values": "int"}},
+		{"name": "hash", "type": {"type": "fixed", "name": "Hash", "size": 2}},
+		{"name": "day", "type": {"type": "int", "logicalType": "date"}},
+		{"name": "parent", "type": ["null", "Event"]}
+	]
+}`
+
+func testAvroRecords() []interface{} {
+	records := make([]interface{}, 0)
+	for i := 0; i < 5; i++ {
+		var name interface{}
+		if i%2 == 0 {
+			name = "event"
+		}
+		var parent interface{}
+		if i == 4 {
+			parent = records[0]
+		}
+		records = append(records, map[string]interface{}{
+			"id":     int64(i - 2),
+			"name":   name,
+			"score":  float64(i) * 1.5,
+			"ratio":  0.5,
+			"active": i%2 == 1,
+			"kind":   "B",
+			"tags":   []interface{}{"x", "y"},
+			"attrs":  map[string]interface{}{"n": int64(i)},
+			"hash":   []byte{byte(i), 0xff},
+			"day":    int64(19000 + i),
+			"parent": parent,
+		})
+	}
+	return records
+}
+
+func TestAvroReader(t *testing.T) {
+	for _, codec := range []string{"null", "deflate", "snappy"} {
+		records := testAvroRecords()
+		reader, err := newAvroReader(bytes.NewReader(writeTestAvro(t, testAvroSchema, codec, records)))
+		if err != nil {
+			t.Fatalf("%s: failed to open: %v", codec, err)
+		}
+
+		for i, want := range records {
+			got, err := reader.next()
+			if err != nil {
+				t.Fatalf("%s: failed to read record %d: %v", codec, i, err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: record %d: expected %v, got %v", codec, i, want, got)
+			}
+		}
+		if _, err := reader.next(); err == nil {
+			t.Errorf("%s: expected EOF after the last record", codec)
+		}
+	}
+}
+
+func TestAvroReaderErrors(t *testing.T) {
+	if _, err := newAvroReader(bytes.NewReader([]byte("PAR1"))); err == nil {
+		t.Error("Expected an error for a file that is not Avro")
+	}
+
+	data := writeTestAvro(t, testAvroSchema, "null", testAvroRecords())
+	data[len(data)-1] ^= 0xff
+	reader, err := newAvroReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Failed to open: %v", err)
+	}
+	for {
+		if _, err = reader.next(); err != nil {
+			break
+		}
+	}
+	if err == nil || err.Error() == "EOF" {
+		t.Errorf("Expected a sync marker error, got %v", err)
+	}
+}
+
+func TestProfileAvro(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.avro")
+	if err := os.WriteFile(path, writeTestAvro(t, testAvroSchema, "deflate", testAvroRecords()), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	profile, err := ProfileDatasetWithOptions(path, Options{})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if profile.Format != "Avro" || profile.RowCount != 5 || profile.ColumnCount != 11 {
+		t.Errorf("Expected 5 rows and 11 columns of Avro, got %d and %d of %s", profile.RowCount, profile.ColumnCount, profile.Format)
+	}
+	if col := profile.Columns["day"]; col == nil || col.DataType != "datetime" {
+		t.Errorf("Expected day to be a datetime column, got %+v", col)
+	}
+	if col := profile.Columns["name"]; col == nil || col.MissingCount != 2 {
+		t.Errorf("Expected 2 missing names, got %+v", col)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	reader, header, columns, err := openAvroRecords(file, "events.avro", Options{})
+	if err != nil {
+		t.Fatalf("Failed to open: %v", err)
+	}
+	if err := reader.advance(); err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	record := make([]string, len(header))
+	for _, column := range columns {
+		if err := column.read(reader.block, record); err != nil {
+			t.Fatalf("Failed to decode: %v", err)
+		}
+	}
+	want := []string{"-2", "event", "0", "0.5", "false", "B", `["x","y"]`, `{"n":0}`, "\x00\xff", "2022-01-08", ""}
+	if !reflect.DeepEqual(record, want) {
+		t.Errorf("Expected %q, got %q", want, record)
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{SkipRows: 1}); err == nil || !strings.Contains(err.Error(), "--skip-rows is not supported for Avro files") {
+		t.Errorf("Expected --skip-rows to be rejected, got %v", err)
+	}
+}
+
+func TestAvroNestedRecords(t *testing.T) {
+	schema := `{
+		"type": "record", "name": "Order",
+		"fields": [
+			{"name": "id", "type": "long"},
+			{"name": "customer", "type": ["null", {"type": "record", "name": "Customer", "fields": [
+				{"name": "name", "type": "string"},
+				{"name": "address", "type": {"type": "record", "name": "Address", "fields": [
+					{"name": "city", "type": "string"}
+				]}}
+			]}]},
+			{"name": "total", "type": {"type": "bytes", "logicalType": "decimal", "precision": 9, "scale": 2}}
+		]
+	}`
+	records := []interface{}{
+		map[string]interface{}{"id": int64(1), "customer": map[string]interface{}{
+			"name": "Ann", "address": map[string]interface{}{"city": "Oslo"}}, "total": []byte{0x04, 0xd2}},
+		map[string]interface{}{"id": int64(2), "customer": nil, "total": []byte{0xff, 0x38}},
+	}
+
+	reader, header, columns, err := openAvroRecords(bytes.NewReader(writeTestAvro(t, schema, "null", records)), "orders.avro", Options{})
+	if err != nil {
+		t.Fatalf("Failed to open: %v", err)
+	}
+	if want := []string{"id", "customer.name", "customer.address.city", "total"}; !reflect.DeepEqual(header, want) {
+		t.Errorf("Expected header %q, got %q", want, header)
+	}
+
+	want := [][]string{{"1", "Ann", "Oslo", "12.34"}, {"2", "", "", "-2.00"}}
+	for i := range want {
+		if err := reader.advance(); err != nil {
+			t.Fatalf("Failed to read: %v", err)
+		}
+		record := make([]string, len(header))
+		for _, column := range columns {
+			if err := column.read(reader.block, record); err != nil {
+				t.Fatalf("Failed to decode: %v", err)
+			}
+		}
+		if !reflect.DeepEqual(record, want[i]) {
+			t.Errorf("Record %d: expected %q, got %q", i, want[i], record)
+		}
+	}
+}
+
+func TestFormatAvroValue(t *testing.T) {
+	tests := []struct {
+		value  interface{}
+		schema *avroSchema
+		want   string
+	}{
+		{int64(19000), &avroSchema{kind: "int", logical: "date"}, "2022-01-08"},
+		{int64(1500), &avroSchema{kind: "long", logical: "timestamp-millis"}, "1970-01-01T00:00:01.5Z"},
+		{int64(1500), &avroSchema{kind: "long", logical: "timestamp-micros"}, "1970-01-01T00:00:00.0015Z"},
+		{int64(3723000), &avroSchema{kind: "int", logical: "time-millis"}, "01:02:03"},
+		{[]byte{0x01}, &avroSchema{kind: "bytes", logical: "decimal", scale: 3}, "0.001"},
+		{[]byte{0xff}, &avroSchema{kind: "fixed", logical: "decimal"}, "-1"},
+		{0.1, &avroSchema{kind: "float"}, "0.1"},
+		{nil, &avroSchema{kind: "null"}, ""},
+	}
+	for _, tt := range tests {
+		if got := formatAvroValue(tt.value, tt.schema); got != tt.want {
+			t.Errorf("formatAvroValue(%v, %s) = %q, want %q", tt.value, tt.schema.logical, got, tt.want)
+		}
+	}
+}
+
+func TestAvroRows(t *testing.T) {
+	reader, err := newAvroReader(bytes.NewReader(writeTestAvro(t, testAvroSchema, "snappy", testAvroRecords())))
+	if err != nil {
+		t.Fatalf("Failed to open: %v", err)
+	}
+	if rows, err := reader.rows(); err != nil || rows != 5 {
+		t.Errorf("Expected 5 rows, got %d (%v)", rows, err)
+	}
+}
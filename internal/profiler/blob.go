@@ -0,0 +1,144 @@
+package profiler
+
+import (
+	"fmt"
+)
+
+const (
+	blobSampleSize      = 100
+	blobAvgLength       = 1024
+	base64MinLength     = 128
+	blobDetectionRatio  = 0.9
+	opaqueDataType      = "blob"
+	opaqueColumnNoteFmt = "Treated as opaque: values average %s, per-value analysis skipped"
+)
+
+// blobTracker decides, from the first non-empty values of a column, whether
+// the column holds long payloads that should not be analysed value by value.
+type blobTracker struct {
+	sampled     int
+	blobLike    int
+	decided     bool
+	opaque      bool
+	totalLength int64
+	count       int
+	maxLength   int
+}
+
+func newBlobTracker() *blobTracker {
+	return &blobTracker{}
+}
+
+func (b *blobTracker) observe(value string) bool {
+	length := len(value)
+	b.totalLength += int64(length)
+	b.count++
+	if length > b.maxLength {
+		b.maxLength = length
+	}
+
+	if b.decided {
+		return b.opaque
+	}
+
+	b.sampled++
+	if length >= blobAvgLength || isBase64Like(value) {
+		b.blobLike++
+	}
+
+	if b.sampled >= blobSampleSize {
+		b.decide()
+	}
+
+	return b.opaque
+}
+
+func (b *blobTracker) decide() {
+	if b.decided {
+		return
+	}
+	b.decided = true
+
+	if b.sampled == 0 {
+		return
+	}
+
+	avgLength := float64(b.totalLength) / float64(b.count)
+	if avgLength >= blobAvgLength || float64(b.blobLike) >= float64(b.sampled)*blobDetectionRatio {
+		b.opaque = true
+	}
+}
+
+// merge folds in the values o observed. A column is opaque when any part of
+// it was found to be, since that part no longer has per-value state.
+func (b *blobTracker) merge(o *blobTracker) {
+	b.totalLength += o.totalLength
+	b.count += o.count
+	if o.maxLength > b.maxLength {
+		b.maxLength = o.maxLength
+	}
+
+	if !b.decided {
+		b.sampled += o.sampled
+		b.blobLike += o.blobLike
+		if b.sampled >= blobSampleSize {
+			b.decide()
+		}
+	}
+
+	if o.opaque {
+		b.decided = true
+		b.opaque = true
+	}
+}
+
+func (b *blobTracker) avgLength() float64 {
+	if b.count == 0 {
+		return 0
+	}
+	return float64(b.totalLength) / float64(b.count)
+}
+
+func isBase64Like(value string) bool {
+	if len(value) < base64MinLength {
+		return false
+	}
+
+	hasUpper, hasLower := false, false
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c >= 'A' && c <= 'Z':
+			hasUpper = true
+		case c >= 'a' && c <= 'z':
+			hasLower = true
+		case c >= '0' && c <= '9', c == '+', c == '/', c == '=', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+
+	return hasUpper && hasLower
+}
+
+func finishOpaqueColumn(col *ColumnProfile, b *blobTracker, uniqueCount int) {
+	col.IsOpaque = true
+	col.DataType = opaqueDataType
+	col.Count = b.count
+	col.UniqueCount = uniqueCount
+	col.IsUnique = col.UniqueCount == col.Count
+	col.AvgLength = b.avgLength()
+	col.MaxLength = b.maxLength
+	col.Notes = append(col.Notes, fmt.Sprintf(opaqueColumnNoteFmt, FormatBytes(col.AvgLength)))
+}
+
+func FormatBytes(size float64) string {
+	switch {
+	case size >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", size/(1024*1024))
+	case size >= 1024:
+		return fmt.Sprintf("%.1f KB", size/1024)
+	default:
+		return fmt.Sprintf("%.0f B", size)
+	}
+}
@@ -0,0 +1,136 @@
+package profiler
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	CompressionGzip  = "gzip"
+	CompressionZstd  = "zstd"
+	CompressionBzip2 = "bzip2"
+)
+
+var compressionMagic = []struct {
+	codec string
+	magic []byte
+}{
+	{CompressionGzip, []byte{0x1f, 0x8b}},
+	{CompressionZstd, []byte{0x28, 0xb5, 0x2f, 0xfd}},
+	{CompressionBzip2, []byte("BZh")},
+}
+
+// compressionExt returns the codec named by a file's extension, or "".
+func compressionExt(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".gz", ".gzip":
+		return CompressionGzip
+	case ".zst", ".zstd":
+		return CompressionZstd
+	case ".bz2", ".bzip2":
+		return CompressionBzip2
+	default:
+		return ""
+	}
+}
+
+// trimCompressionExt drops a compression extension so that data.csv.gz is
+// recognised as CSV.
+func trimCompressionExt(name string) string {
+	if compressionExt(name) == "" {
+		return name
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// decompressor is a source with any compression removed. It counts the
+// decompressed bytes so the uncompressed size can be reported when the
+// whole stream was read.
+type decompressor struct {
+	io.Reader
+	codec string
+	read  int64
+	eof   bool
+	close func()
+}
+
+// decompress detects gzip, zstd or bzip2 from the magic bytes at the start
+// of r. A compression extension on name that the content does not match is
+// an error rather than a silent attempt to profile binary data.
+func decompress(r io.Reader, name string) (*decompressor, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(4)
+
+	codec := ""
+	for _, c := range compressionMagic {
+		if bytes.HasPrefix(head, c.magic) {
+			codec = c.codec
+			break
+		}
+	}
+
+	if ext := compressionExt(name); ext != "" && ext != codec {
+		return nil, fmt.Errorf("%s is not %s-compressed", name, ext)
+	}
+
+	d := &decompressor{codec: codec, close: func() {}}
+
+	switch codec {
+	case CompressionGzip:
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzip stream: %w", err)
+		}
+		d.Reader = gz
+		d.close = func() { gz.Close() }
+	case CompressionZstd:
+		zr, err := zstd.NewReader(br, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read zstd stream: %w", err)
+		}
+		d.Reader = zr
+		d.close = zr.Close
+	case CompressionBzip2:
+		d.Reader = bzip2.NewReader(br)
+	default:
+		d.Reader = br
+	}
+
+	return d, nil
+}
+
+func (d *decompressor) Read(p []byte) (int, error) {
+	n, err := d.Reader.Read(p)
+	d.read += int64(n)
+	if err == io.EOF {
+		d.eof = true
+	} else if err != nil && d.codec != "" {
+		err = fmt.Errorf("failed to decompress %s stream: %w", d.codec, err)
+	}
+	return n, err
+}
+
+func (d *decompressor) Close() {
+	d.close()
+}
+
+// describe records the compression on the profile, with the uncompressed
+// size only when the stream was read to the end.
+func (d *decompressor) describe(profile *DatasetProfile) {
+	if d.codec == "" {
+		return
+	}
+
+	profile.Compression = d.codec
+	if d.eof {
+		profile.UncompressedSize = d.read
+	}
+}
@@ -0,0 +1,138 @@
+package profiler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const compressTestCSV = "a,b\n1,x\n2,y\n"
+
+// bzip2 of compressTestCSV; the standard library has no bzip2 writer
+var compressTestBzip2 = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0xbc, 0xc7, 0x28, 0x45, 0x00, 0x00,
+	0x04, 0x59, 0x80, 0x00, 0x10, 0x00, 0x04, 0x30, 0x00, 0x30, 0x00, 0x00, 0x60, 0x20, 0x00, 0x31,
+	0x0c, 0x08, 0x23, 0x41, 0x9a, 0x8e, 0x04, 0x22, 0x17, 0x8b, 0xb9, 0x22, 0x9c, 0x28, 0x48, 0x5e,
+	0x63, 0x94, 0x22, 0x80,
+}
+
+func gzipBytes(t *testing.T, content string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(content))
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to gzip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func zstdBytes(t *testing.T, content string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := zstd.NewWriter(&buf)
+	if err != nil {
+		t.Fatalf("Failed to create zstd writer: %v", err)
+	}
+	w.Write([]byte(content))
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to zstd: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestProfileCompressed(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name  string
+		data  []byte
+		codec string
+	}{
+		{"data.csv.gz", gzipBytes(t, compressTestCSV), CompressionGzip},
+		{"data.csv.zst", zstdBytes(t, compressTestCSV), CompressionZstd},
+		{"data.csv.bz2", compressTestBzip2, CompressionBzip2},
+		// Detected from the magic bytes alone
+		{"export.csv", gzipBytes(t, compressTestCSV), CompressionGzip},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name)
+		if err := os.WriteFile(path, tt.data, 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+
+		profile, err := ProfileDataset(path)
+		if err != nil {
+			t.Fatalf("Failed to profile %s: %v", tt.name, err)
+		}
+
+		if profile.Format != "CSV" || profile.RowCount != 2 || profile.ColumnCount != 2 {
+			t.Errorf("%s: expected 2 CSV rows and 2 columns, got %s %d %d", tt.name, profile.Format, profile.RowCount, profile.ColumnCount)
+		}
+		if profile.Compression != tt.codec {
+			t.Errorf("%s: expected compression %s, got %q", tt.name, tt.codec, profile.Compression)
+		}
+		if profile.FileSize != int64(len(tt.data)) || profile.UncompressedSize != int64(len(compressTestCSV)) {
+			t.Errorf("%s: expected sizes %d and %d, got %d and %d", tt.name,
+				len(tt.data), len(compressTestCSV), profile.FileSize, profile.UncompressedSize)
+		}
+	}
+}
+
+func TestProfileCompressedJSONL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl.gz")
+	if err := os.WriteFile(path, gzipBytes(t, "{\"a\": 1}\n{\"a\": 2}\n{\"a\": 3}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	profile, err := ProfileDataset(path)
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if profile.Format != "JSONL" || profile.RowCount != 3 || profile.Compression != CompressionGzip {
+		t.Errorf("Expected 3 gzip JSONL rows, got %s %d %q", profile.Format, profile.RowCount, profile.Compression)
+	}
+}
+
+func TestProfileCompressedMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.csv.gz")
+	if err := os.WriteFile(path, []byte(compressTestCSV), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	_, err := ProfileDataset(path)
+	if err == nil || !strings.Contains(err.Error(), "not gzip-compressed") {
+		t.Errorf("Expected an error for a .gz file that is not gzip, got %v", err)
+	}
+}
+
+func TestProfileCompressedPartialRead(t *testing.T) {
+	var content strings.Builder
+	content.WriteString("id\n")
+	for i := 0; i < 1000; i++ {
+		content.WriteString("12345\n")
+	}
+
+	path := filepath.Join(t.TempDir(), "data.csv.gz")
+	if err := os.WriteFile(path, gzipBytes(t, content.String()), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	profile, err := ProfileDatasetWithOptions(path, Options{MaxBytes: 100})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if profile.Compression != CompressionGzip || profile.UncompressedSize != 0 {
+		t.Errorf("Expected gzip without an uncompressed size for a partial read, got %q %d", profile.Compression, profile.UncompressedSize)
+	}
+	if profile.RowCount != 16 {
+		t.Errorf("Expected 16 rows in the first 100 decompressed bytes, got %d", profile.RowCount)
+	}
+}
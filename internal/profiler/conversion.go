@@ -0,0 +1,103 @@
+package profiler
+
+import (
+	"fmt"
+	"strings"
+)
+
+// conversionShare is the share of the values of a string column that must
+// cast to a stricter type for the cast to be simulated.
+const conversionShare = 0.5
+
+// Conversion is the outcome of casting every value of a column to a
+// stricter type: the inferred type of a typed column, or the type most
+// values of a string column cast to, such as a column of numbers with a
+// few placeholders.
+type Conversion struct {
+	Type      string // integer, float or datetime
+	Converted int    // values that cast cleanly
+	Lost      int    // values that do not parse, which the cast turns into nulls or errors
+	Truncated int    // numbers whose fraction an integer cast drops
+}
+
+// AtRisk is the number of values a cast loses or corrupts.
+func (c *Conversion) AtRisk() int {
+	return c.Lost + c.Truncated
+}
+
+// AtRiskPercent is AtRisk as a percentage of the values cast.
+func (c *Conversion) AtRiskPercent() float64 {
+	total := c.Converted + c.Lost + c.Truncated
+	if total == 0 {
+		return 0
+	}
+	return float64(c.AtRisk()) / float64(total) * 100
+}
+
+func (c *Conversion) String() string {
+	if c.AtRisk() == 0 {
+		return fmt.Sprintf("all %d values cast to %s", c.Converted, c.Type)
+	}
+	var risks []string
+	if c.Lost > 0 {
+		risks = append(risks, fmt.Sprintf("loses %d values", c.Lost))
+	}
+	if c.Truncated > 0 {
+		risks = append(risks, fmt.Sprintf("truncates %d", c.Truncated))
+	}
+	return fmt.Sprintf("casting to %s %s (%.2f%%)", c.Type, strings.Join(risks, " and "), c.AtRiskPercent())
+}
+
+// castShares returns the shares of values that parse as numbers in format
+// and as timestamps in timeFormat.
+func castShares(values []string, format numberFormat, timeFormat dateFormat) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	numbers, dates := 0, 0
+	for _, v := range values {
+		if _, ok := format.parseFloat(v); ok {
+			numbers++
+		}
+		if _, ok := timeFormat.parse(v); ok {
+			dates++
+		}
+	}
+	return float64(numbers) / float64(len(values)), float64(dates) / float64(len(values))
+}
+
+// conversion simulates casting the count values of a column of dataType,
+// of which numbers parse as numbers with fractional of them not whole, and
+// dates parse as timestamps. String columns are cast to the type losing
+// the fewest values, the strictest on a tie, when at least conversionShare
+// of the values cast cleanly.
+func conversion(dataType string, count, numbers, fractional, dates int) *Conversion {
+	if count == 0 {
+		return nil
+	}
+
+	asInteger := &Conversion{Type: "integer", Converted: numbers - fractional, Lost: count - numbers, Truncated: fractional}
+	asFloat := &Conversion{Type: "float", Converted: numbers, Lost: count - numbers}
+	asDateTime := &Conversion{Type: "datetime", Converted: dates, Lost: count - dates}
+
+	switch dataType {
+	case "integer":
+		return asInteger
+	case "float":
+		return asFloat
+	case "datetime":
+		return asDateTime
+	case "string":
+		var best *Conversion
+		for _, c := range []*Conversion{asInteger, asFloat, asDateTime} {
+			if float64(c.Converted) < float64(count)*conversionShare {
+				continue
+			}
+			if best == nil || c.AtRisk() < best.AtRisk() {
+				best = c
+			}
+		}
+		return best
+	}
+	return nil
+}
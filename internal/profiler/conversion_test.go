@@ -0,0 +1,85 @@
+package profiler
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestConversion(t *testing.T) {
+	cases := []struct {
+		dataType                    string
+		count, numbers, frac, dates int
+		want                        *Conversion
+	}{
+		{"integer", 100, 95, 3, 0, &Conversion{Type: "integer", Converted: 92, Lost: 5, Truncated: 3}},
+		{"float", 100, 95, 3, 0, &Conversion{Type: "float", Converted: 95, Lost: 5}},
+		{"datetime", 100, 0, 0, 98, &Conversion{Type: "datetime", Converted: 98, Lost: 2}},
+		// Whole numbers cast to integers, and fractions to floats
+		{"string", 100, 70, 0, 0, &Conversion{Type: "integer", Converted: 70, Lost: 30}},
+		{"string", 100, 70, 20, 0, &Conversion{Type: "float", Converted: 70, Lost: 30}},
+		{"string", 100, 10, 0, 60, &Conversion{Type: "datetime", Converted: 60, Lost: 40}},
+		{"string", 100, 40, 0, 0, nil},
+		{"blob", 100, 0, 0, 0, nil},
+		{"integer", 0, 0, 0, 0, nil},
+	}
+	for _, c := range cases {
+		got := conversion(c.dataType, c.count, c.numbers, c.frac, c.dates)
+		if (got == nil) != (c.want == nil) || (got != nil && *got != *c.want) {
+			t.Errorf("conversion(%s, %d, %d, %d, %d) = %+v, want %+v", c.dataType, c.count, c.numbers, c.frac, c.dates, got, c.want)
+		}
+	}
+
+	c := &Conversion{Type: "integer", Converted: 180, Lost: 10, Truncated: 10}
+	if c.AtRisk() != 20 || c.AtRiskPercent() != 10 || c.String() != "casting to integer loses 10 values and truncates 10 (10.00%)" {
+		t.Errorf("Unexpected risk of %+v: %d, %v, %q", c, c.AtRisk(), c.AtRiskPercent(), c.String())
+	}
+	if s := (&Conversion{Type: "float", Converted: 5}).String(); s != "all 5 values cast to float" {
+		t.Errorf("Unexpected description of a safe cast: %q", s)
+	}
+	if s := (&Conversion{Type: "integer", Converted: 3, Truncated: 1}).String(); s != "casting to integer truncates 1 (25.00%)" {
+		t.Errorf("Unexpected description of a truncating cast: %q", s)
+	}
+}
+
+func TestProfileConversion(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("id,qty,code,seen,name\n")
+	for i := 0; i < 200; i++ {
+		qty, code, seen := fmt.Sprint(i%7), fmt.Sprint(i), fmt.Sprintf("2024-01-%02d", i%28+1)
+		switch i % 20 {
+		case 0:
+			qty = "n/a"
+		case 10:
+			qty = "2.5"
+		}
+		if i%10 < 3 {
+			code = "N/A"
+		}
+		if i%4 == 0 {
+			seen = "unknown"
+		}
+		fmt.Fprintf(&b, "%d,%s,%s,%s,x%d\n", i, qty, code, seen, i)
+	}
+	path := writeTestFile(t, "data.csv", b.String())
+
+	profile, err := ProfileDataset(path)
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+
+	want := map[string]*Conversion{
+		"id":   {Type: "integer", Converted: 200},
+		"qty":  {Type: "integer", Converted: 180, Lost: 10, Truncated: 10},
+		"code": {Type: "integer", Converted: 140, Lost: 60},
+		"seen": {Type: "datetime", Converted: 150, Lost: 50},
+	}
+	for name, col := range profile.Columns {
+		if got := col.Conversion; (got == nil) != (want[name] == nil) || (got != nil && *got != *want[name]) {
+			t.Errorf("Expected %s (%s) to convert as %+v, got %+v", name, col.DataType, want[name], got)
+		}
+	}
+	if profile.Columns["code"].DataType != "string" || profile.Columns["seen"].DataType != "string" {
+		t.Errorf("Expected code and seen to stay strings, got %s and %s", profile.Columns["code"].DataType, profile.Columns["seen"].DataType)
+	}
+}
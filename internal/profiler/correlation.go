@@ -1,189 +1,463 @@
 package profiler
 
 import (
+	"container/heap"
+	"fmt"
 	"math"
 	"sort"
-	"strconv"
+)
+
+const (
+	maxCorrelationColumns  = 30    // columns whose values are kept row by row, in header order
+	DefaultCorrelationRows = 50000 // rows correlations are computed from unless set
+	minCorrelationRows     = 10    // rows with both values needed to correlate a pair
+)
+
+// Methods of correlation pairs.
+const (
+	CorrelationPearson  = "pearson"
+	CorrelationCramersV = "cramers_v"
 )
 
 type CorrelationMatrix struct {
-	Columns  []string
-	Values   map[string]map[string]float64
-	TopPairs []CorrelationPair
+	Columns     []string                      // numeric columns
+	Values      map[string]map[string]float64 // Pearson correlations of the numeric columns
+	Spearman    map[string]map[string]float64 // rank correlations of the numeric columns
+	Categorical []string                      // categorical string columns
+	CramersV    map[string]map[string]float64 // associations of the categorical columns
+	Rows        int                           // rows the correlations were computed from
+	Sampled     bool                          // Rows is a uniform sample of the rows
+	TopPairs    []CorrelationPair
 }
 
 type CorrelationPair struct {
 	Column1     string
 	Column2     string
-	Correlation float64
+	Method      string  // pearson, or cramers_v for categorical columns
+	Correlation float64 // Pearson's r, or Cramér's V from 0 to 1
+	Spearman    float64 // rank correlation of numeric columns, 0 when not computed
 }
 
+// strength is the larger of the linear and rank correlations of a numeric
+// pair, or the association of a categorical one.
+func (p CorrelationPair) strength() float64 {
+	return math.Max(math.Abs(p.Correlation), math.Abs(p.Spearman))
+}
+
+// CalculateCorrelationMatrix ranks the pairs of the correlations computed
+// while profiling, strongest first. It returns nil when there are none.
 func CalculateCorrelationMatrix(profile *DatasetProfile) *CorrelationMatrix {
-	numericColumns := []string{}
-	numericData := make(map[string][]float64)
+	matrix := profile.CorrelationMatrix
+	if matrix == nil {
+		return nil
+	}
 
-	for name, col := range profile.Columns {
-		if col.IsNumeric && col.Count > 0 {
-			numericColumns = append(numericColumns, name)
+	allPairs := []CorrelationPair{}
 
-			values := reconstructNumericValues(col)
-			numericData[name] = values
+	for i, col1 := range matrix.Columns {
+		for _, col2 := range matrix.Columns[i+1:] {
+			if r, ok := matrix.Values[col1][col2]; ok {
+				allPairs = append(allPairs, CorrelationPair{
+					Column1:     col1,
+					Column2:     col2,
+					Method:      CorrelationPearson,
+					Correlation: r,
+					Spearman:    matrix.Spearman[col1][col2],
+				})
+			}
 		}
 	}
 
-	if len(numericColumns) < 2 {
-		return nil
+	for i, col1 := range matrix.Categorical {
+		for _, col2 := range matrix.Categorical[i+1:] {
+			if v, ok := matrix.CramersV[col1][col2]; ok {
+				allPairs = append(allPairs, CorrelationPair{
+					Column1:     col1,
+					Column2:     col2,
+					Method:      CorrelationCramersV,
+					Correlation: v,
+				})
+			}
+		}
 	}
 
-	sort.Strings(numericColumns)
+	sort.SliceStable(allPairs, func(i, j int) bool {
+		return allPairs[i].strength() > allPairs[j].strength()
+	})
 
-	matrix := &CorrelationMatrix{
-		Columns:  numericColumns,
-		Values:   make(map[string]map[string]float64),
-		TopPairs: []CorrelationPair{},
+	topLimit := 10
+	if len(allPairs) < topLimit {
+		topLimit = len(allPairs)
 	}
 
-	for _, col1 := range numericColumns {
-		matrix.Values[col1] = make(map[string]float64)
-		for _, col2 := range numericColumns {
-			matrix.Values[col1][col2] = 0
+	matrix.TopPairs = []CorrelationPair{}
+	for i := 0; i < topLimit; i++ {
+		if allPairs[i].strength() > 0.1 {
+			matrix.TopPairs = append(matrix.TopPairs, allPairs[i])
 		}
 	}
 
-	for i, col1 := range numericColumns {
-		matrix.Values[col1][col1] = 1.0
-
-		data1 := numericData[col1]
-
-		for j, col2 := range numericColumns {
-			if j <= i {
-				continue
-			}
+	return matrix
+}
 
-			data2 := numericData[col2]
+// correlationRows keeps the values of up to limit rows, column by column,
+// for the correlations that need the values of each row side by side.
+// Beyond limit rows it keeps those of smallest row hash: a uniform sample
+// that comes out the same however the rows are split between passes.
+type correlationRows struct {
+	indexes []int      // header indexes of the kept columns
+	values  [][]string // values of each kept column, by slot
+	hashes  []uint64   // row hash of each slot
+	largest *slotHeap  // set once full
+	limit   int
+	rows    int
+}
 
-			corr := calculatePearsonCorrelation(data1, data2)
+func newCorrelationRows(header []string, limit int) *correlationRows {
+	c := &correlationRows{limit: limit}
 
-			matrix.Values[col1][col2] = corr
-			matrix.Values[col2][col1] = corr
+	seen := make(map[string]bool)
+	for i, name := range header {
+		if seen[name] || len(c.indexes) == maxCorrelationColumns {
+			continue
 		}
+		seen[name] = true
+		c.indexes = append(c.indexes, i)
 	}
+	c.values = make([][]string, len(c.indexes))
 
-	allPairs := []CorrelationPair{}
+	return c
+}
 
-	for i, col1 := range numericColumns {
-		for j, col2 := range numericColumns {
-			if j <= i {
-				continue
-			}
+func (c *correlationRows) add(record []string, hash uint64) {
+	c.rows++
+	c.keep(hash, func(k int) string { return recordValue(record, c.indexes[k]) })
+}
 
-			allPairs = append(allPairs, CorrelationPair{
-				Column1:     col1,
-				Column2:     col2,
-				Correlation: matrix.Values[col1][col2],
-			})
-		}
+// merge offers the kept rows of o, which saw other records.
+func (c *correlationRows) merge(o *correlationRows) {
+	for slot, hash := range o.hashes {
+		c.keep(hash, func(k int) string { return o.values[k][slot] })
 	}
+	c.rows += o.rows
+}
 
-	sort.Slice(allPairs, func(i, j int) bool {
-		return math.Abs(allPairs[i].Correlation) > math.Abs(allPairs[j].Correlation)
-	})
-
-	topLimit := 10
-	if len(allPairs) < topLimit {
-		topLimit = len(allPairs)
+// keep adds a row while there is room, and afterwards swaps it for the row
+// of largest hash when its own is smaller.
+func (c *correlationRows) keep(hash uint64, value func(k int) string) {
+	if len(c.hashes) < c.limit {
+		for k := range c.values {
+			c.values[k] = append(c.values[k], value(k))
+		}
+		c.hashes = append(c.hashes, hash)
+		if len(c.hashes) == c.limit {
+			c.largest = &slotHeap{slots: make([]int, c.limit), hashes: c.hashes}
+			for i := range c.largest.slots {
+				c.largest.slots[i] = i
+			}
+			heap.Init(c.largest)
+		}
+		return
 	}
 
-	for i := 0; i < topLimit; i++ {
-		if math.Abs(allPairs[i].Correlation) > 0.1 {
-			matrix.TopPairs = append(matrix.TopPairs, allPairs[i])
-		}
+	slot := c.largest.slots[0]
+	if hash >= c.hashes[slot] {
+		return
 	}
+	for k := range c.values {
+		c.values[k][slot] = value(k)
+	}
+	c.hashes[slot] = hash
+	heap.Fix(c.largest, 0)
+}
 
-	return matrix
+// slotHeap orders the slots of a full correlationRows by their row hash,
+// largest first. It shares the hashes, which no longer grow.
+type slotHeap struct {
+	slots  []int
+	hashes []uint64
 }
 
-func reconstructNumericValues(col *ColumnProfile) []float64 {
-	if !col.IsNumeric || len(col.HistogramBuckets) == 0 {
-		return []float64{}
-	}
+func (h *slotHeap) Len() int           { return len(h.slots) }
+func (h *slotHeap) Less(i, j int) bool { return h.hashes[h.slots[i]] > h.hashes[h.slots[j]] }
+func (h *slotHeap) Swap(i, j int)      { h.slots[i], h.slots[j] = h.slots[j], h.slots[i] }
+func (h *slotHeap) Push(x any)         { h.slots = append(h.slots, x.(int)) }
 
-	values := []float64{}
+func (h *slotHeap) Pop() any {
+	last := h.slots[len(h.slots)-1]
+	h.slots = h.slots[:len(h.slots)-1]
+	return last
+}
 
-	for _, bucket := range col.HistogramBuckets {
-		if bucket.Count <= 0 {
-			continue
+// columnValues returns the kept values of a column ordered by row hash, so
+// sums come out the same however the rows were split between passes.
+func (c *correlationRows) columnValues(k int, order []int) []string {
+	values := make([]string, len(order))
+	for i, slot := range order {
+		values[i] = c.values[k][slot]
+	}
+	return values
+}
+
+// apply computes the correlations of the profiled columns as the
+// correlation matrix of profile: Pearson and Spearman for numeric columns
+// and Cramér's V for categorical string columns, each pair over the rows
+// where both have a value.
+func (c *correlationRows) apply(profile *DatasetProfile, header []string) {
+	order := make([]int, len(c.hashes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return c.hashes[order[i]] < c.hashes[order[j]] })
+
+	numeric := make([]rankedColumn, 0)
+	categorical := make([]codedColumn, 0)
+	for k, index := range c.indexes {
+		col := profile.Columns[header[index]]
+		switch {
+		case col.IsNumeric:
+			numeric = append(numeric, newRankedColumn(col, c.columnValues(k, order)))
+		case col.DataType == "string" && col.IsCategorical && col.UniqueCount > 1:
+			categorical = append(categorical, newCodedColumn(col.Name, c.columnValues(k, order)))
 		}
+	}
+	if len(numeric) < 2 && len(categorical) < 2 {
+		return
+	}
+	sort.Slice(numeric, func(i, j int) bool { return numeric[i].name < numeric[j].name })
+	sort.Slice(categorical, func(i, j int) bool { return categorical[i].name < categorical[j].name })
 
-		bucketMidpoint := (bucket.LowerBound + bucket.UpperBound) / 2
+	matrix := &CorrelationMatrix{
+		Values:  make(map[string]map[string]float64),
+		Rows:    len(c.hashes),
+		Sampled: c.rows > len(c.hashes),
+	}
 
-		for i := 0; i < bucket.Count; i++ {
-			values = append(values, bucketMidpoint)
+	if len(numeric) >= 2 {
+		matrix.Spearman = make(map[string]map[string]float64)
+		for i, a := range numeric {
+			matrix.Columns = append(matrix.Columns, a.name)
+			setCorrelation(matrix.Values, a.name, a.name, 1)
+			setCorrelation(matrix.Spearman, a.name, a.name, 1)
+			for _, b := range numeric[i+1:] {
+				if r, rho, ok := correlate(a, b); ok {
+					setCorrelation(matrix.Values, a.name, b.name, r)
+					setCorrelation(matrix.Spearman, a.name, b.name, rho)
+				}
+			}
 		}
 	}
 
-	if len(col.TopValues) > 0 {
-		for _, topValue := range col.TopValues {
-			val, err := strconv.ParseFloat(topValue.Value, 64)
-			if err == nil {
-				for i := 0; i < topValue.Count; i++ {
-					values = append(values, val)
+	if len(categorical) >= 2 {
+		matrix.CramersV = make(map[string]map[string]float64)
+		for i, a := range categorical {
+			matrix.Categorical = append(matrix.Categorical, a.name)
+			setCorrelation(matrix.CramersV, a.name, a.name, 1)
+			for _, b := range categorical[i+1:] {
+				if v, ok := cramersV(a, b); ok {
+					setCorrelation(matrix.CramersV, a.name, b.name, v)
 				}
 			}
 		}
 	}
 
-	return values
+	profile.CorrelationMatrix = matrix
+	if matrix.Sampled {
+		profile.Notes = append(profile.Notes, fmt.Sprintf(
+			"Correlations computed from a uniform sample of %d of %d rows", matrix.Rows, c.rows))
+	}
 }
 
-func calculatePearsonCorrelation(x, y []float64) float64 {
-	n := len(x)
-	if n != len(y) || n == 0 {
-		return 0
+func setCorrelation(values map[string]map[string]float64, a, b string, value float64) {
+	for _, name := range []string{a, b} {
+		if values[name] == nil {
+			values[name] = make(map[string]float64)
+		}
 	}
+	values[a][b] = value
+	values[b][a] = value
+}
 
-	maxSampleSize := 10000
-	if n > maxSampleSize {
-		sampled_x := []float64{}
-		sampled_y := []float64{}
+// rankedColumn holds the numbers of a column, NaN where a row has none,
+// and their ranks when every row has one.
+type rankedColumn struct {
+	name     string
+	values   []float64
+	ranks    []float64
+	complete bool
+}
 
-		step := n / maxSampleSize
-		if step < 1 {
-			step = 1
+func newRankedColumn(col *ColumnProfile, raw []string) rankedColumn {
+	format := numberFormatNamed(col.NumberFormat)
+	c := rankedColumn{name: col.Name, values: make([]float64, len(raw)), complete: true}
+	for i, value := range raw {
+		x, ok := format.parseFloat(value)
+		if !ok {
+			x = math.NaN()
+			c.complete = false
 		}
+		c.values[i] = x
+	}
+	if c.complete {
+		c.ranks = ranks(c.values)
+	}
+	return c
+}
+
+// correlate returns the Pearson and Spearman correlations of two numeric
+// columns over the rows where both have a number. Ranks of a column with a
+// number on every row are shared by all its pairs with another such
+// column.
+func correlate(a, b rankedColumn) (float64, float64, bool) {
+	if a.complete && b.complete {
+		if len(a.values) < minCorrelationRows {
+			return 0, 0, false
+		}
+		return pearson(a.values, b.values), pearson(a.ranks, b.ranks), true
+	}
 
-		for i := 0; i < n; i += step {
-			sampled_x = append(sampled_x, x[i])
-			sampled_y = append(sampled_y, y[i])
+	x, y := make([]float64, 0), make([]float64, 0)
+	for i := range a.values {
+		if !math.IsNaN(a.values[i]) && !math.IsNaN(b.values[i]) {
+			x = append(x, a.values[i])
+			y = append(y, b.values[i])
 		}
+	}
+	if len(x) < minCorrelationRows {
+		return 0, 0, false
+	}
+	return pearson(x, y), pearson(ranks(x), ranks(y)), true
+}
+
+// pearson computes Pearson's r around the means, 0 when either side is
+// constant.
+func pearson(x, y []float64) float64 {
+	n := float64(len(x))
+	meanX, meanY := 0.0, 0.0
+	for i := range x {
+		meanX += x[i]
+		meanY += y[i]
+	}
+	meanX /= n
+	meanY /= n
+
+	sumXY, sumX2, sumY2 := 0.0, 0.0, 0.0
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		sumXY += dx * dy
+		sumX2 += dx * dx
+		sumY2 += dy * dy
+	}
 
-		x = sampled_x
-		y = sampled_y
-		n = len(x)
+	if sumX2 == 0 || sumY2 == 0 {
+		return 0
 	}
+	return sumXY / math.Sqrt(sumX2*sumY2)
+}
 
-	sumX, sumY, sumXY, sumX2, sumY2 := 0.0, 0.0, 0.0, 0.0, 0.0
+// ranks returns the 1-based ranks of values, ties sharing their average
+// rank.
+func ranks(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return values[order[i]] < values[order[j]] })
 
-	for i := 0; i < n; i++ {
-		sumX += x[i]
-		sumY += y[i]
-		sumXY += x[i] * y[i]
-		sumX2 += x[i] * x[i]
-		sumY2 += y[i] * y[i]
+	result := make([]float64, len(values))
+	for start := 0; start < len(order); {
+		end := start + 1
+		for end < len(order) && values[order[end]] == values[order[start]] {
+			end++
+		}
+		rank := float64(start+end+1) / 2
+		for _, i := range order[start:end] {
+			result[i] = rank
+		}
+		start = end
 	}
+	return result
+}
 
-	nFloat := float64(n)
+// codedColumn numbers the distinct values of a column in order of
+// appearance, -1 where a row has none.
+type codedColumn struct {
+	name       string
+	codes      []int
+	categories int
+}
 
-	numerator := nFloat*sumXY - sumX*sumY
-	denominator := math.Sqrt((nFloat*sumX2 - sumX*sumX) * (nFloat*sumY2 - sumY*sumY))
+func newCodedColumn(name string, raw []string) codedColumn {
+	c := codedColumn{name: name, codes: make([]int, len(raw))}
+	known := make(map[string]int)
+	for i, value := range raw {
+		if value == "" {
+			c.codes[i] = -1
+			continue
+		}
+		code, ok := known[value]
+		if !ok {
+			code = len(known)
+			known[value] = code
+		}
+		c.codes[i] = code
+	}
+	c.categories = len(known)
+	return c
+}
 
-	if denominator == 0 {
-		return 0
+// cramersV measures the association of two categorical columns from 0 for
+// none to 1 when either determines the other, over the rows where both
+// have a value. Categories absent from those rows do not count.
+func cramersV(a, b codedColumn) (float64, bool) {
+	table := make([]int, a.categories*b.categories)
+	rowSums := make([]int, a.categories)
+	colSums := make([]int, b.categories)
+	n := 0
+	for i := range a.codes {
+		x, y := a.codes[i], b.codes[i]
+		if x < 0 || y < 0 {
+			continue
+		}
+		table[x*b.categories+y]++
+		rowSums[x]++
+		colSums[y]++
+		n++
+	}
+	if n < minCorrelationRows {
+		return 0, false
 	}
 
-	correlation := numerator / denominator
+	r, c := 0, 0
+	for _, sum := range rowSums {
+		if sum > 0 {
+			r++
+		}
+	}
+	for _, sum := range colSums {
+		if sum > 0 {
+			c++
+		}
+	}
+	if r < 2 || c < 2 {
+		return 0, false
+	}
+
+	// chi² = n (Σ O² / (row sum × column sum) - 1)
+	total := 0.0
+	for x, rowSum := range rowSums {
+		for y, colSum := range colSums {
+			if o := table[x*b.categories+y]; o > 0 {
+				total += float64(o) * float64(o) / (float64(rowSum) * float64(colSum))
+			}
+		}
+	}
+	phi2 := total - 1
+	if phi2 < 0 {
+		phi2 = 0
+	}
 
-	return correlation
+	return math.Sqrt(phi2 / float64(min(r, c)-1)), true
 }
 
 func GetCorrelationStrength(correlation float64) string {
@@ -0,0 +1,215 @@
+package profiler
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRanks(t *testing.T) {
+	got := ranks([]float64{10, 30, 20, 30, 5})
+	want := []float64{2, 4.5, 3, 4.5, 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected ranks %v, got %v", want, got)
+	}
+}
+
+func TestCorrelate(t *testing.T) {
+	x, cubes, reversed, gappy := make([]string, 50), make([]string, 50), make([]string, 50), make([]string, 50)
+	for i := range x {
+		x[i] = fmt.Sprint(i - 25)
+		cubes[i] = fmt.Sprint(math.Pow(float64(i-25), 3))
+		reversed[i] = fmt.Sprint(100 - i)
+		if i%5 != 0 {
+			gappy[i] = fmt.Sprint(i * i)
+		}
+	}
+
+	column := func(name string, values []string) rankedColumn {
+		return newRankedColumn(&ColumnProfile{Name: name}, values)
+	}
+
+	tests := []struct {
+		name     string
+		a, b     rankedColumn
+		pearson  float64
+		spearman float64
+	}{
+		{"monotonic", column("x", x), column("cubes", cubes), 0.9160, 1},
+		{"reversed", column("x", x), column("reversed", reversed), -1, -1},
+		{"missing", column("gappy", gappy), column("reversed", reversed), -0.9687, -1},
+	}
+	for _, tt := range tests {
+		r, rho, ok := correlate(tt.a, tt.b)
+		if !ok || math.Abs(r-tt.pearson) > 1e-4 || math.Abs(rho-tt.spearman) > 1e-9 {
+			t.Errorf("%s: expected %v and %v, got %v and %v (%v)", tt.name, tt.pearson, tt.spearman, r, rho, ok)
+		}
+	}
+
+	if _, _, ok := correlate(column("a", x[:5]), column("b", cubes[:5])); ok {
+		t.Error("Expected no correlation from fewer than 10 rows")
+	}
+}
+
+func TestCramersV(t *testing.T) {
+	regions := []string{"north", "south", "east", "west"}
+	region, product, shuffled, colour := make([]string, 400), make([]string, 400), make([]string, 400), make([]string, 400)
+	for i := range region {
+		region[i] = regions[i%4]
+		product[i] = "p-" + regions[i%4]
+		shuffled[i] = regions[(i/4)%4]
+		colour[i] = []string{"red", "blue"}[i%2]
+	}
+
+	tests := []struct {
+		name string
+		a, b []string
+		want float64
+	}{
+		{"determined", region, product, 1},
+		{"independent", region, shuffled, 0},
+		{"coarser", region, colour, 1},
+	}
+	for _, tt := range tests {
+		got, ok := cramersV(newCodedColumn("a", tt.a), newCodedColumn("b", tt.b))
+		if !ok || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: expected %v, got %v (%v)", tt.name, tt.want, got, ok)
+		}
+	}
+}
+
+func TestProfileCategoricalCorrelations(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("region,product,channel,units,revenue\n")
+	for i := 0; i < 600; i++ {
+		region := []string{"north", "south", "east", "west"}[i%4]
+		product := map[string]string{"north": "skis", "south": "surfboards", "east": "bikes", "west": "bikes"}[region]
+		channel := []string{"web", "store", "phone"}[(i/4)%3]
+		units := i%37 + 1
+		fmt.Fprintf(&b, "%s,%s,%s,%d,%d\n", region, product, channel, units, units*units*units)
+	}
+	path := filepath.Join(t.TempDir(), "sales.csv")
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	profile, err := ProfileDataset(path)
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+
+	matrix := profile.CorrelationMatrix
+	if matrix == nil {
+		t.Fatal("Expected a correlation matrix")
+	}
+	if matrix.Rows != 600 {
+		t.Errorf("Expected correlations over 600 rows, got %d", matrix.Rows)
+	}
+	if rho := matrix.Spearman["units"]["revenue"]; math.Abs(rho-1) > 1e-9 {
+		t.Errorf("Expected a rank correlation of 1 between units and revenue, got %v", rho)
+	}
+	if v := matrix.CramersV["region"]["product"]; math.Abs(v-1) > 1e-9 {
+		t.Errorf("Expected Cramér's V of 1 between region and product, got %v", v)
+	}
+	if v := matrix.CramersV["region"]["channel"]; v > 0.1 {
+		t.Errorf("Expected no association between region and channel, got %v", v)
+	}
+
+	var found bool
+	for _, pair := range matrix.TopPairs {
+		if pair.Method == CorrelationCramersV && pair.Column1 == "product" && pair.Column2 == "region" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected region and product among the top pairs, got %+v", matrix.TopPairs)
+	}
+
+	var recommended bool
+	for _, r := range profile.Recommendations {
+		recommended = recommended || (r.Type == RuleCorrelatedColumns && strings.Contains(r.Message, "Cramér's V"))
+	}
+	if !recommended {
+		t.Errorf("Expected a recommendation for the associated columns, got %+v", profile.Recommendations)
+	}
+}
+
+func TestCorrelationRowsSample(t *testing.T) {
+	header := []string{"a", "b"}
+	whole := newCorrelationRows(header, 100)
+	first, second := newCorrelationRows(header, 100), newCorrelationRows(header, 100)
+	for i := 0; i < 1000; i++ {
+		record := []string{fmt.Sprint(i), fmt.Sprint(i * 2)}
+		hash := mix64(uint64(i))
+		whole.add(record, hash)
+		if i < 400 {
+			first.add(record, hash)
+		} else {
+			second.add(record, hash)
+		}
+	}
+	first.merge(second)
+
+	if first.rows != 1000 || len(first.hashes) != 100 {
+		t.Fatalf("Expected 100 rows kept of 1000, got %d of %d", len(first.hashes), first.rows)
+	}
+
+	kept := func(c *correlationRows) map[string]bool {
+		values := make(map[string]bool)
+		for _, value := range c.values[0] {
+			values[value] = true
+		}
+		return values
+	}
+	if !reflect.DeepEqual(kept(first), kept(whole)) {
+		t.Error("Expected merged passes to keep the rows a single pass keeps")
+	}
+
+	late := 0
+	for value := range kept(whole) {
+		var i int
+		fmt.Sscan(value, &i)
+		if i >= 500 {
+			late++
+		}
+	}
+	if late < 25 || late > 75 {
+		t.Errorf("Expected about half the sample from the second half of the rows, got %d of 100", late)
+	}
+}
+
+func TestProfileCorrelationSample(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("x,y\n")
+	for i := 0; i < 500; i++ {
+		fmt.Fprintf(&b, "%d,%d\n", i, 3*i+1)
+	}
+	path := filepath.Join(t.TempDir(), "data.csv")
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	profile, err := ProfileDatasetWithOptions(path, Options{CorrelationRows: 100})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+
+	matrix := profile.CorrelationMatrix
+	if matrix == nil || matrix.Rows != 100 || !matrix.Sampled {
+		t.Fatalf("Expected correlations from a sample of 100 rows, got %+v", matrix)
+	}
+	if r := matrix.Values["x"]["y"]; math.Abs(r-1) > 1e-9 {
+		t.Errorf("Expected a Pearson correlation of 1 over paired rows, got %v", r)
+	}
+	if !strings.Contains(strings.Join(profile.Notes, " "), "uniform sample of 100 of 500 rows") {
+		t.Errorf("Expected a sampling note, got %v", profile.Notes)
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{CorrelationRows: -1}); err == nil {
+		t.Error("Expected an error for a negative correlation sample")
+	}
+}
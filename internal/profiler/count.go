@@ -0,0 +1,601 @@
+package profiler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/remote"
+	"github.com/parquet-go/parquet-go"
+)
+
+// countChunk is how much of a text source is scanned for line breaks at a
+// time.
+const countChunk = 256 << 10
+
+// Count methods: how the rows of a source were counted.
+const (
+	CountLines    = "lines"    // line breaks of a text source, without parsing the records
+	CountMetadata = "metadata" // row count stored in the file, such as a Parquet or ORC footer or Avro block headers
+	CountQuery    = "query"    // SELECT COUNT(*) of a database table
+	CountRecords  = "records"  // records read one by one, without statistics
+)
+
+// Count is the size of a source, or of one table or sheet of it, found
+// without profiling it. Bytes is -1 when the size is unknown, as for stdin
+// or a remote object whose server does not say.
+type Count struct {
+	Source  string `json:"source"`
+	Table   string `json:"table,omitempty"` // table or sheet of a database or workbook
+	Format  string `json:"format"`
+	Rows    int64  `json:"rows"`
+	Columns int    `json:"columns"`
+	Bytes   int64  `json:"bytes"`
+	Method  string `json:"method"`
+}
+
+// CountSource counts the rows and columns of source as fast as it can:
+// text files by their line breaks, XML files by reading their records,
+// Parquet and ORC files from their footer, Avro files from their block
+// headers and SQLite tables with a query. Every table of a database and
+// every sheet of a workbook is counted unless opts chooses one. Text rows
+// are not parsed, so rows that would fail to parse are counted, and a
+// preamble and total rows at the end are left out as profile leaves them
+// out. Counting stops with an error wrapping ctx.Err() once ctx is done.
+func CountSource(ctx context.Context, source string, opts Options) ([]Count, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	if err := checkSource(source, opts); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	opts.ctx = ctx
+
+	var count Count
+	var err error
+	switch format := tableFormat(source, opts); {
+	case format != "":
+		return nil, fmt.Errorf("counting %s tables is not supported yet: %s", format, source)
+	case source == StdinSource:
+		count, err = countText(os.Stdin, "stdin", fileFormat("", opts), opts)
+		count.Bytes = -1
+	case remote.IsURL(source):
+		count, err = countRemote(source, opts)
+	case IsSQLite(source):
+		return countSQLite(source, opts)
+	case opts.Table != "":
+		return nil, fmt.Errorf("--table is only supported for SQLite databases and Excel workbooks: %s", source)
+	case IsExcel(source):
+		return countExcel(source, opts)
+	case IsArchive(source):
+		return nil, fmt.Errorf("counting zip and tar archives is not supported yet: %s", source)
+	default:
+		count, err = countFile(source, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	count.Source = source
+	return []Count{count}, nil
+}
+
+func countFile(path string, opts Options) (Count, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Count{}, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return Count{}, fmt.Errorf("failed to get file stats: %w", err)
+	}
+
+	var count Count
+	switch format := fileFormat(path, opts); format {
+	case "parquet":
+		count, err = countParquet(file, filepath.Base(path), info.Size(), opts)
+	case "avro":
+		count, err = countAvro(file, filepath.Base(path), opts)
+	case "orc":
+		count, err = countORC(file, filepath.Base(path), info.Size(), opts)
+	case "json":
+		return Count{}, fmt.Errorf("JSON is not supported yet: %s", path)
+	default:
+		count, err = countText(file, filepath.Base(path), format, opts)
+	}
+	count.Bytes = info.Size()
+	return count, err
+}
+
+// countRemote counts a remote object as profileRemote reads it: Parquet
+// from its footer, fetched with range requests, and text streamed through.
+func countRemote(rawURL string, opts Options) (Count, error) {
+	objectPath := remote.Path(rawURL)
+
+	switch strings.ToLower(filepath.Ext(objectPath)) {
+	case ".sqlite", ".sqlite3", ".db":
+		return Count{}, fmt.Errorf("SQLite databases must be local files: %s", rawURL)
+	case ".xlsx", ".xlsm":
+		return Count{}, fmt.Errorf("Excel workbooks must be local files: %s", rawURL)
+	}
+	if IsArchive(objectPath) {
+		return Count{}, fmt.Errorf("zip and tar archives must be local files: %s", rawURL)
+	}
+
+	switch format := fileFormat(objectPath, opts); format {
+	case "parquet":
+		r, info, err := remote.NewReaderAt(opts.context(), rawURL)
+		if err != nil {
+			return Count{}, err
+		}
+		count, err := countParquet(r, info.Name, info.Size, opts)
+		count.Bytes = info.Size
+		return count, err
+	case "orc":
+		r, info, err := remote.NewReaderAt(opts.context(), rawURL)
+		if err != nil {
+			return Count{}, err
+		}
+		count, err := countORC(r, info.Name, info.Size, opts)
+		count.Bytes = info.Size
+		return count, err
+	case "json":
+		return Count{}, fmt.Errorf("JSON is not supported for remote sources yet: %s", rawURL)
+	default:
+		body, info, err := remote.Open(rawURL, remote.OpenOptions{Context: opts.context()})
+		if err != nil {
+			return Count{}, err
+		}
+		defer body.Close()
+
+		var count Count
+		if format == "avro" {
+			count, err = countAvro(body, info.Name, opts)
+		} else {
+			count, err = countText(body, info.Name, format, opts)
+		}
+		count.Bytes = info.Size
+		return count, err
+	}
+}
+
+// countParquet reads the row count and columns off the footer of a Parquet
+// file, leaving its pages unread.
+func countParquet(r io.ReaderAt, name string, size int64, opts Options) (Count, error) {
+	if err := opts.textOnly("Parquet files"); err != nil {
+		return Count{}, err
+	}
+	if codec := compressionExt(name); codec != "" {
+		return Count{}, fmt.Errorf("%s-compressed Parquet files are not supported: Parquet compresses its pages itself", codec)
+	}
+
+	pf, err := parquet.OpenFile(r, size, parquet.SkipPageIndex(true), parquet.SkipBloomFilters(true))
+	if err != nil {
+		return Count{}, fmt.Errorf("failed to read Parquet file: %w", err)
+	}
+	columns, _ := parquetColumns(pf)
+	if len(columns) == 0 {
+		return Count{}, fmt.Errorf("no flat columns found in Parquet schema")
+	}
+	return Count{Format: "Parquet", Rows: pf.NumRows(), Columns: len(columns), Method: CountMetadata}, nil
+}
+
+// countAvro counts the records of an Avro file from the counts its block
+// headers give, skipping over the blocks without decompressing them.
+func countAvro(r io.Reader, name string, opts Options) (Count, error) {
+	reader, header, _, err := openAvroRecords(r, name, opts)
+	if err != nil {
+		return Count{}, err
+	}
+	rows, err := reader.rows()
+	if err != nil {
+		return Count{}, err
+	}
+	return Count{Format: "Avro", Rows: rows, Columns: len(header), Method: CountMetadata}, nil
+}
+
+func countORC(r io.ReaderAt, name string, size int64, opts Options) (Count, error) {
+	file, _, header, _, err := openORCColumns(r, name, size, opts)
+	if err != nil {
+		return Count{}, err
+	}
+	file.close()
+	return Count{Format: "ORC", Rows: int64(file.rows), Columns: len(header), Method: CountMetadata}, nil
+}
+
+// countText counts the records of a CSV, TSV or JSON Lines stream by their
+// line breaks, or of an XML document by reading them, after decompression
+// and transcoding. The columns are those of the header, or the keys of the
+// leading JSON Lines or XML records.
+func countText(r io.Reader, name, format string, opts Options) (Count, error) {
+	source, err := decompress(r, name)
+	if err != nil {
+		return Count{}, err
+	}
+	defer source.Close()
+
+	text, err := transcode(source, opts.Encoding, 0)
+	if err != nil {
+		return Count{}, err
+	}
+
+	switch format {
+	case FormatJSONL:
+		return countJSONL(text, opts)
+	case FormatXML:
+		return countXML(text, opts)
+	}
+	comma, label := rune(0), "CSV"
+	if format == FormatTSV {
+		comma, label = '\t', "TSV"
+	}
+	return countDelimited(text, comma, label, opts)
+}
+
+func countDelimited(r io.Reader, comma rune, format string, opts Options) (Count, error) {
+	swapper := newQuoteSwapper(opts.Quote)
+	if swapper != nil {
+		r = swapper.reader(r)
+	}
+
+	dialect := &csvDialect{comma: comma, comment: opts.Comment}
+	if opts.Delimiter != 0 {
+		dialect.comma = opts.Delimiter
+	}
+	r, _, err := skipPreamble(r, dialect, opts.SkipRows)
+	if err != nil {
+		return Count{}, fmt.Errorf("failed to read %s: %w", format, err)
+	}
+
+	// The header is parsed, the records after it only counted
+	br := bufio.NewReaderSize(r, countChunk)
+	raw, err := readRecordLines(br, byte(dialect.comment))
+	if err != nil {
+		return Count{}, fmt.Errorf("failed to read %s header: %w", format, err)
+	}
+	header, err := parseDelimitedLine(raw, dialect)
+	if err != nil {
+		return Count{}, fmt.Errorf("failed to read %s header: %w", format, err)
+	}
+	if swapper != nil {
+		swapper.record(header)
+	}
+
+	counter := &recordCounter{comment: byte(dialect.comment)}
+	if err := counter.count(opts.context(), br); err != nil {
+		return Count{}, fmt.Errorf("error reading %s: %w", format, err)
+	}
+
+	rows := counter.records
+	if opts.SkipFooter > 0 {
+		rows -= int64(opts.SkipFooter)
+	} else {
+		rows -= int64(counter.footerRows(len(header), dialect))
+	}
+	return Count{Format: format, Rows: max(rows, 0), Columns: len(header), Method: CountLines}, nil
+}
+
+// readRecordLines reads the lines of the next record, more than one when a
+// quoted field holds line breaks, skipping blank and comment lines.
+func readRecordLines(br *bufio.Reader, comment byte) ([]byte, error) {
+	var record []byte
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(record) == 0 && (len(bytes.TrimSpace(line)) == 0 || comment != 0 && line[0] == comment) {
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		record = append(record, line...)
+		if bytes.Count(record, []byte{'"'})%2 == 0 || err != nil {
+			return record, nil
+		}
+	}
+}
+
+// parseDelimitedLine parses a single record of a delimited source.
+func parseDelimitedLine(line []byte, dialect *csvDialect) ([]string, error) {
+	reader := csv.NewReader(bytes.NewReader(line))
+	reader.Comma = dialect.comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	return reader.Read()
+}
+
+func countJSONL(r io.Reader, opts Options) (Count, error) {
+	if opts.SkipRows > 0 || opts.SkipFooter > 0 {
+		return Count{}, fmt.Errorf("--skip-rows and --skip-footer are not supported for JSON Lines")
+	}
+
+	// The keys of the leading records make up the columns, as in profile
+	br := bufio.NewReaderSize(r, countChunk)
+	columns := make(map[string]bool)
+	var rows int64
+	for rows < jsonlHeaderScan {
+		line, err := br.ReadBytes('\n')
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			rows++
+			if record, err := decodeJSONObject(trimmed); err == nil {
+				for _, key := range record.keys {
+					columns[key] = true
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Count{}, fmt.Errorf("error reading JSON Lines: %w", err)
+		}
+	}
+
+	counter := &recordCounter{jsonl: true}
+	if err := counter.count(opts.context(), br); err != nil {
+		return Count{}, fmt.Errorf("error reading JSON Lines: %w", err)
+	}
+	return Count{Format: "JSONL", Rows: rows + counter.records, Columns: len(columns), Method: CountLines}, nil
+}
+
+// recordCounter counts the records of a text stream by its line breaks,
+// without parsing them. Blank lines, comment lines and line breaks inside
+// double quotes do not end a record; JSON Lines records have no quoted line
+// breaks. Chunks without quotes or blank lines are counted with a single
+// bytes.Count. The last two chunks are kept to look for footer rows.
+type recordCounter struct {
+	jsonl    bool
+	comment  byte
+	quoted   bool // inside a quoted field
+	content  bool // the current line has content
+	skipping bool // inside a comment line
+	records  int64
+	chunks   int
+	tail     [2][]byte
+}
+
+func (c *recordCounter) count(ctx context.Context, r io.Reader) error {
+	buffers := [2][]byte{make([]byte, countChunk), make([]byte, countChunk)}
+	for i := 0; ; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		chunk := buffers[i%2]
+		n, err := io.ReadFull(r, chunk)
+		chunk = chunk[:n]
+		c.add(chunk)
+		c.tail[0], c.tail[1] = c.tail[1], chunk
+		c.chunks++
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			if c.content {
+				c.records++
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (c *recordCounter) add(chunk []byte) {
+	if len(chunk) == 0 {
+		return
+	}
+	if c.plain(chunk) {
+		c.records += int64(bytes.Count(chunk, []byte{'\n'}))
+		end := bytes.TrimRight(chunk, "\r")
+		if len(end) > 0 {
+			c.content = end[len(end)-1] != '\n'
+		}
+		return
+	}
+
+	for _, b := range chunk {
+		switch {
+		case c.quoted:
+			c.quoted = b != '"'
+		case c.skipping:
+			c.skipping = b != '\n'
+		case b == '\n':
+			if c.content {
+				c.records++
+			}
+			c.content = false
+		case b == '\r':
+		case !c.content && c.comment != 0 && b == c.comment:
+			c.skipping = true
+		default:
+			c.content = true
+			c.quoted = b == '"' && !c.jsonl
+		}
+	}
+}
+
+// plain reports whether every line break in chunk ends a record: no quotes,
+// comments or blank lines, nor a blank line straddling the previous chunk.
+func (c *recordCounter) plain(chunk []byte) bool {
+	if c.quoted || c.skipping || c.comment != 0 {
+		return false
+	}
+	if !c.content && (chunk[0] == '\n' || chunk[0] == '\r') {
+		return false
+	}
+	if !c.jsonl && bytes.IndexByte(chunk, '"') >= 0 {
+		return false
+	}
+	return !bytes.Contains(chunk, []byte("\n\n")) && !bytes.Contains(chunk, []byte("\n\r\n"))
+}
+
+// footerRows is the number of trailing records that profile drops as
+// footer rows: those of a different width from the header, or that look
+// like totals. Only lines without quotes are looked at.
+func (c *recordCounter) footerRows(width int, dialect *csvDialect) int {
+	tail := append(append([]byte(nil), c.tail[0]...), c.tail[1]...)
+	lines := bytes.Split(tail, []byte{'\n'})
+
+	// The first line is cut unless the tail holds the whole stream
+	first := 0
+	if c.chunks > 2 {
+		first = 1
+	}
+	dropped, seen := 0, 0
+	for i := len(lines) - 1; i >= first && seen < footerScan && int64(dropped) < c.records; i-- {
+		line := bytes.TrimRight(lines[i], "\r")
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if bytes.IndexByte(line, '"') >= 0 || dialect.comment != 0 && line[0] == byte(dialect.comment) {
+			break
+		}
+		seen++
+		record, err := parseDelimitedLine(line, dialect)
+		if err != nil || len(record) == width && !isFooterRow(record) {
+			break
+		}
+		dropped++
+	}
+	return dropped
+}
+
+// countSQLite counts every table of a database, or the one in opts.Table,
+// with a query.
+func countSQLite(path string, opts Options) ([]Count, error) {
+	if err := opts.textOnly("SQLite databases"); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file stats: %w", err)
+	}
+	db, err := openSQLite(path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	tables, err := sqliteTables(db)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Table != "" {
+		if !containsString(tables, opts.Table) {
+			return nil, fmt.Errorf("table %s not found in SQLite database (tables: %s)", opts.Table, strings.Join(tables, ", "))
+		}
+		tables = []string{opts.Table}
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("no tables found in SQLite database %s", path)
+	}
+
+	counts := make([]Count, 0, len(tables))
+	for _, table := range tables {
+		count := Count{Source: path, Table: table, Format: "SQLite", Bytes: info.Size(), Method: CountQuery}
+		if count.Rows, count.Columns, err = countSQLiteTable(db, table, opts); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, nil
+}
+
+func countSQLiteTable(db *sql.DB, table string, opts Options) (int64, int, error) {
+	var rows int64
+	if err := db.QueryRowContext(opts.context(), "SELECT COUNT(*) FROM "+quoteSQLiteIdentifier(table)).Scan(&rows); err != nil {
+		return 0, 0, fmt.Errorf("failed to count table %s: %w", table, err)
+	}
+
+	result, err := db.QueryContext(opts.context(), "SELECT * FROM "+quoteSQLiteIdentifier(table)+" LIMIT 0")
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query table %s: %w", table, err)
+	}
+	defer result.Close()
+	columns, err := result.Columns()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read columns of table %s: %w", table, err)
+	}
+	return rows, len(columns), nil
+}
+
+// countExcel counts every non-empty sheet of a workbook, or the one in
+// opts.Sheet, reading its rows as profile does without statistics.
+func countExcel(path string, opts Options) ([]Count, error) {
+	if err := opts.textOnly("Excel workbooks"); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file stats: %w", err)
+	}
+	f, err := openExcel(path, opts.Password)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if opts.Sheet != "" {
+		if !containsString(sheets, opts.Sheet) {
+			return nil, fmt.Errorf("sheet %s not found in Excel workbook (sheets: %s)", opts.Sheet, strings.Join(sheets, ", "))
+		}
+		sheets = []string{opts.Sheet}
+	}
+
+	counts := make([]Count, 0, len(sheets))
+	for _, sheet := range sheets {
+		reader, err := newExcelSheetReader(f, sheet, nil)
+		if err != nil {
+			return nil, err
+		}
+		count, err := countExcelSheet(reader, opts)
+		reader.close()
+		if errors.Is(err, errEmptySheet) && opts.Sheet == "" {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to count sheet %s: %w", sheet, err)
+		}
+		count.Source, count.Table, count.Bytes = path, sheet, info.Size()
+		counts = append(counts, count)
+	}
+	if len(counts) == 0 {
+		return nil, fmt.Errorf("no sheets with data found in Excel workbook %s", path)
+	}
+	return counts, nil
+}
+
+func countExcelSheet(reader *excelSheetReader, opts Options) (Count, error) {
+	header, err := reader.header()
+	if err != nil {
+		return Count{}, err
+	}
+
+	count := Count{Format: "Excel", Columns: len(header), Method: CountRecords}
+	ctx := opts.context()
+	for {
+		if _, err := reader.next(); err == io.EOF {
+			return count, nil
+		} else if err != nil {
+			return Count{}, err
+		}
+		count.Rows++
+		if count.Rows%1024 == 0 && ctx.Err() != nil {
+			return Count{}, ctx.Err()
+		}
+	}
+}
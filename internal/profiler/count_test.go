@@ -0,0 +1,120 @@
+package profiler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func countOne(t *testing.T, source string, opts Options) Count {
+	t.Helper()
+
+	counts, err := CountSource(context.Background(), source, opts)
+	if err != nil {
+		t.Fatalf("Failed to count %s: %v", source, err)
+	}
+	if len(counts) != 1 {
+		t.Fatalf("Expected one count of %s, got %+v", source, counts)
+	}
+	return counts[0]
+}
+
+func TestCountMatchesProfile(t *testing.T) {
+	dir := t.TempDir()
+
+	// Quoted line breaks and blank lines past the first chunk, where the
+	// counter leaves its fast path
+	var large strings.Builder
+	large.WriteString("id,comment,amount\n")
+	for i := 0; i < 40000; i++ {
+		comment := "plain"
+		switch {
+		case i%5000 == 0:
+			comment = "\"two\nlines, \"\"quoted\"\"\""
+		case i%7000 == 0:
+			large.WriteString("\r\n\n")
+		}
+		fmt.Fprintf(&large, "%d,%s,%d\r\n", i, comment, i*3)
+	}
+
+	files := map[string]string{
+		"plain.csv":    "a,b\n1,2\n3,4",
+		"preamble.csv": "Exported 2024-01-01\n\nid,name,amount\n1,\"a\nb\",3\n\n2,x,4\n3,\"q\"\"x\",5\nTotal,,12\n",
+		"data.tsv":     "a\tb\tc\n1\t2\t3\n4\t5\t6\n",
+		"large.csv":    large.String(),
+		"empty.csv":    "a,b\n",
+		"events.jsonl": "{\"id\": 1}\n\n{\"id\": 2, \"kind\": \"click\"}\n{\"id\": 3}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+
+		profile, err := ProfileDatasetWithOptions(path, Options{})
+		if err != nil {
+			t.Fatalf("Failed to profile %s: %v", name, err)
+		}
+		count := countOne(t, path, Options{})
+		if count.Rows != int64(profile.RowCount) || count.Columns != profile.ColumnCount || count.Bytes != int64(len(content)) {
+			t.Errorf("%s: expected %d rows, %d columns and %d bytes, got %+v", name, profile.RowCount, profile.ColumnCount, len(content), count)
+		}
+		if count.Method != CountLines {
+			t.Errorf("%s: expected to count lines, got %s", name, count.Method)
+		}
+	}
+}
+
+func TestCountSkipFooter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.csv")
+	if err := os.WriteFile(path, []byte("a,b\n1,2\n3,4\n5,6\n"), 0644); err != nil {
+		t.Fatalf("Failed to write data: %v", err)
+	}
+	if count := countOne(t, path, Options{SkipFooter: 2}); count.Rows != 1 {
+		t.Errorf("Expected 1 row above the footer, got %d", count.Rows)
+	}
+}
+
+func TestCountParquet(t *testing.T) {
+	rows := make([]parquetTestRow, 100)
+	for i := range rows {
+		rows[i] = parquetTestRow{ID: int64(i), Country: "US"}
+	}
+	path := writeTestParquet(t, rows)
+
+	count := countOne(t, path, Options{})
+	if count.Rows != 100 || count.Columns != 5 || count.Method != CountMetadata || count.Format != "Parquet" {
+		t.Errorf("Expected 100 rows and 5 columns from the footer, got %+v", count)
+	}
+}
+
+func TestCountTables(t *testing.T) {
+	counts, err := CountSource(context.Background(), createTestSQLite(t), Options{})
+	if err != nil {
+		t.Fatalf("Failed to count database: %v", err)
+	}
+	if len(counts) != 2 || counts[0].Table != "order items" || counts[0].Rows != 2 || counts[0].Columns != 2 ||
+		counts[1].Table != "users" || counts[1].Rows != 3 || counts[1].Columns != 3 || counts[1].Method != CountQuery {
+		t.Errorf("Unexpected table counts: %+v", counts)
+	}
+
+	workbook := createTestWorkbook(t)
+	counts, err = CountSource(context.Background(), workbook, Options{})
+	if err != nil {
+		t.Fatalf("Failed to count workbook: %v", err)
+	}
+	if len(counts) != 2 || counts[0].Table != "Orders" || counts[0].Rows != 3 || counts[0].Columns != 4 ||
+		counts[1].Table != "Customers" || counts[1].Rows != 2 || counts[1].Columns != 2 {
+		t.Errorf("Unexpected sheet counts: %+v", counts)
+	}
+	if count := countOne(t, workbook, Options{Sheet: "Customers"}); count.Rows != 2 {
+		t.Errorf("Expected the chosen sheet only, got %+v", count)
+	}
+
+	if _, err := CountSource(context.Background(), workbook, Options{Sheet: "Missing"}); err == nil {
+		t.Error("Expected an error for a missing sheet")
+	}
+}
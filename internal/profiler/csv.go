@@ -7,14 +7,23 @@ import (
 	"math"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"time"
 )
 
 func ProfileCSV(filePath string) (*DatasetProfile, error) {
-	startTime := time.Now()
+	return profileCSV(filePath, Options{})
+}
 
+func profileCSV(filePath string, opts Options) (*DatasetProfile, error) {
+	return profileDelimitedFile(filePath, 0, "CSV", opts)
+}
+
+func profileTSV(filePath string, opts Options) (*DatasetProfile, error) {
+	return profileDelimitedFile(filePath, '\t', "TSV", opts)
+}
+
+func profileDelimitedFile(filePath string, comma rune, format string, opts Options) (*DatasetProfile, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
@@ -26,123 +35,179 @@ func ProfileCSV(filePath string) (*DatasetProfile, error) {
 		return nil, fmt.Errorf("failed to get file stats: %w", err)
 	}
 
-	reader := csv.NewReader(file)
+	name := filepath.Base(filePath)
 
-	header, err := reader.Read()
-	if err != nil {
-		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	reason := ""
+	if opts.Parallel > 1 {
+		profile, why, err := profileDelimitedParallel(file, name, fileInfo.Size(), comma, format, opts)
+		if profile != nil || err != nil {
+			return profile, err
+		}
+		reason = why
 	}
 
-	profile := &DatasetProfile{
-		Filename:      filepath.Base(filePath),
-		FileSize:      fileInfo.Size(),
-		Format:        "CSV",
-		ColumnCount:   len(header),
-		Columns:       make(map[string]*ColumnProfile),
-		CreatedAt:     time.Now(),
-		QualityIssues: make([]QualityIssue, 0),
+	profile, err := profileDelimited(file, name, fileInfo.Size(), comma, format, opts)
+	if err == nil && reason != "" {
+		profile.Notes = append(profile.Notes, "Parsed sequentially: "+reason)
 	}
+	return profile, err
+}
 
-	for _, colName := range header {
-		profile.Columns[colName] = &ColumnProfile{
-			Name:          colName,
-			TopValues:     make([]ValueCount, 0),
-			QualityIssues: make([]QualityIssue, 0),
-		}
+func profileDelimited(r io.Reader, name string, size int64, comma rune, format string, opts Options) (*DatasetProfile, error) {
+	startTime := time.Now()
+
+	reader, err := newDelimitedReader(r, name, comma, format, opts)
+	if err != nil {
+		return nil, err
 	}
+	defer reader.close()
 
-	columnValues := make(map[string][]string)
-	valueCounts := make(map[string]map[string]int)
+	profile := newDatasetProfile(name, size, format, reader.header)
 
-	for colName := range profile.Columns {
-		columnValues[colName] = make([]string, 0)
-		valueCounts[colName] = make(map[string]int)
+	if err := profileRows(profile, reader.header, reader.next, opts); err != nil {
+		return nil, err
 	}
 
-	rowHashes := make(map[string]int)
+	reader.describe(profile)
 
-	rowCount := 0
-	missingCells := 0
+	profile.ProcessingTime = time.Since(startTime)
 
-	for {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("error reading CSV: %w", err)
-		}
+	return profile, nil
+}
 
-		rowCount++
+// delimitedReader reads the header and records of one CSV or TSV stream,
+// after decompression, transcoding and preamble detection.
+type delimitedReader struct {
+	header  []string
+	source  *decompressor
+	text    *transcoder
+	limited *lineLimitReader
+	dialect *csvDialect
+	sniffed bool
+	skipped int
+	badRows int // rows skipped for not parsing, with SkipBadRows
+	footer  *footerFilter
+	format  string
+	opts    Options
+}
 
-		rowHash := strings.Join(record, "|")
-		if _, exists := rowHashes[rowHash]; exists {
-			rowHashes[rowHash]++
-		} else {
-			rowHashes[rowHash] = 1
-		}
+func newDelimitedReader(r io.Reader, name string, comma rune, format string, opts Options) (*delimitedReader, error) {
+	d := &delimitedReader{format: format, opts: opts}
 
-		for i, value := range record {
-			if i >= len(header) {
-				continue
-			}
+	source, err := decompress(r, name)
+	if err != nil {
+		return nil, err
+	}
+	d.source = source
 
-			colName := header[i]
+	if d.text, err = transcode(source, opts.Encoding, opts.MaxBytes); err != nil {
+		source.Close()
+		return nil, err
+	}
+	r = d.text
 
-			if value == "" {
-				profile.Columns[colName].MissingCount++
-				missingCells++
-				continue
-			}
+	if opts.MaxBytes > 0 {
+		d.limited = newLineLimitReader(r, opts.MaxBytes)
+		r = d.limited
+	}
 
-			columnValues[colName] = append(columnValues[colName], value)
+	swapper := newQuoteSwapper(opts.Quote)
+	if swapper != nil {
+		r = swapper.reader(r)
+	}
 
-			valueCounts[colName][value]++
-		}
+	d.dialect = &csvDialect{comma: comma, comment: opts.Comment}
+	if opts.Delimiter != 0 {
+		d.dialect.comma = opts.Delimiter
 	}
+	d.sniffed = d.dialect.comma == 0
 
-	duplicateRows := 0
-	for _, count := range rowHashes {
-		if count > 1 {
-			duplicateRows += (count - 1)
-		}
+	r, d.skipped, err = skipPreamble(r, d.dialect, opts.SkipRows)
+	if err != nil {
+		source.Close()
+		return nil, fmt.Errorf("failed to read %s: %w", format, err)
 	}
 
-	profile.RowCount = rowCount
-	profile.MissingCells = missingCells
-	profile.DuplicateRows = duplicateRows
+	reader := csv.NewReader(r)
+	reader.Comma = d.dialect.comma
+	reader.Comment = d.dialect.comment
+	if opts.Quote == NoQuote {
+		reader.LazyQuotes = true
+	}
 
-	for colName, values := range columnValues {
-		col := profile.Columns[colName]
-		col.Count = len(values)
+	read := func() ([]string, error) {
+		record, err := reader.Read()
+		if swapper != nil && record != nil {
+			swapper.record(record)
+		}
+		return record, err
+	}
 
-		col.DataType = inferDataType(values)
-		col.IsNumeric = col.DataType == "integer" || col.DataType == "float"
-		col.IsDateTime = col.DataType == "datetime"
+	if d.header, err = read(); err != nil {
+		source.Close()
+		return nil, fmt.Errorf("failed to read %s header: %w", format, err)
+	}
 
-		col.UniqueCount = len(valueCounts[colName])
-		col.IsCategorical = col.UniqueCount <= profile.RowCount/10 && col.UniqueCount <= 100
-		col.IsUnique = col.UniqueCount == col.Count
+	d.footer = newFooterFilter(read, opts.SkipFooter)
 
-		col.TopValues = getTopValues(valueCounts[colName], 5)
+	return d, nil
+}
 
-		if col.IsNumeric {
-			calculateNumericStats(col, values)
+func (d *delimitedReader) next() ([]string, error) {
+	for {
+		record, err := d.footer.next()
+		if err != nil && err != io.EOF {
+			if !badRow(err) {
+				return nil, fmt.Errorf("error reading %s: %w", d.format, err)
+			}
+			if !d.opts.SkipBadRows {
+				return nil, fmt.Errorf("error reading %s: %w (--skip-bad-rows skips such rows)", d.format, err)
+			}
+			d.badRows++
+			continue
 		}
-
-		detectQualityIssues(col, profile.RowCount)
+		return record, err
 	}
+}
 
-	collectDatasetQualityIssues(profile)
-
-	profile.QualityScore = CalculateQualityScore(profile)
-
-	profile.ProcessingTime = time.Since(startTime)
+func (d *delimitedReader) close() {
+	d.source.Close()
+}
 
-	return profile, nil
+// describe records the compression and encoding of the stream on the
+// profile and notes what was detected or skipped.
+func (d *delimitedReader) describe(profile *DatasetProfile) {
+	d.source.describe(profile)
+	d.text.describe(profile)
+	if d.sniffed && d.dialect.comma != ',' {
+		profile.Notes = append(profile.Notes, fmt.Sprintf("Detected %s as the delimiter", describeDelimiter(d.dialect.comma)))
+	}
+	switch {
+	case d.skipped > 0 && d.opts.SkipRows > 0:
+		profile.Notes = append(profile.Notes, fmt.Sprintf("Skipped %d lines before the header (--skip-rows)", d.skipped))
+	case d.skipped > 0:
+		profile.Notes = append(profile.Notes, fmt.Sprintf("Skipped %d preamble lines before the header", d.skipped))
+	}
+	if note := d.footer.note(); note != "" {
+		profile.Notes = append(profile.Notes, note)
+	}
+	if d.limited != nil && d.limited.truncated {
+		profile.Notes = append(profile.Notes, d.limited.note(profile))
+	}
+	if d.badRows > 0 {
+		profile.BadRows = d.badRows
+		profile.Notes = append(profile.Notes, badRowsNote(d.badRows))
+	}
 }
 
 func inferDataType(values []string) string {
+	return inferDataTypeWith(values, numberFormat{}, dateFormat{})
+}
+
+// inferDataTypeWith infers the type of values, reading numbers in format
+// and timestamps in timeFormat. Integers are timestamps when it reads them,
+// as it does dates such as 20240131 once they are detected.
+func inferDataTypeWith(values []string, format numberFormat, timeFormat dateFormat) string {
 	if len(values) == 0 {
 		return "unknown"
 	}
@@ -152,32 +217,30 @@ func inferDataType(values []string) string {
 	intCount := 0
 	floatCount := 0
 	dateCount := 0
+	integerDates := 0
 
 	for i := 0; i < sampleSize; i++ {
-		if _, err := strconv.ParseInt(values[i], 10, 64); err == nil {
+		if format.parseInt(values[i]) {
 			intCount++
+			if _, ok := timeFormat.parse(values[i]); ok {
+				integerDates++
+			}
 			continue
 		}
 
-		if _, err := strconv.ParseFloat(values[i], 64); err == nil {
+		if _, ok := format.parseFloat(values[i]); ok {
 			floatCount++
 			continue
 		}
 
-		if _, err := time.Parse(time.RFC3339, values[i]); err == nil {
-			dateCount++
-			continue
-		}
-
-		if _, err := time.Parse("2006-01-02", values[i]); err == nil {
+		if _, ok := timeFormat.parse(values[i]); ok {
 			dateCount++
 			continue
 		}
+	}
 
-		if _, err := time.Parse("01/02/2006", values[i]); err == nil {
-			dateCount++
-			continue
-		}
+	if float64(integerDates) >= float64(sampleSize)*0.9 {
+		return "datetime"
 	}
 
 	if float64(intCount) >= float64(sampleSize)*0.9 {
@@ -196,121 +259,13 @@ func inferDataType(values []string) string {
 }
 
 func calculateNumericStats(col *ColumnProfile, values []string) {
-	numValues := make([]float64, 0, len(values))
+	stats := newNumericStats()
 
 	for _, v := range values {
-		f, err := strconv.ParseFloat(v, 64)
-		if err == nil {
-			numValues = append(numValues, f)
-		}
+		stats.addValue(v, numberFormat{})
 	}
 
-	if len(numValues) == 0 {
-		return
-	}
-
-	var sum, sumSquares float64
-	var min, max float64 = numValues[0], numValues[0]
-
-	for i, v := range numValues {
-		sum += v
-		sumSquares += v * v
-
-		if i == 0 || v < min {
-			min = v
-		}
-
-		if i == 0 || v > max {
-			max = v
-		}
-	}
-
-	n := float64(len(numValues))
-	mean := sum / n
-	variance := (sumSquares / n) - (mean * mean)
-	stdDev := math.Sqrt(variance)
-
-	for i := 1; i < len(numValues); i++ {
-		key := numValues[i]
-		j := i - 1
-
-		for j >= 0 && numValues[j] > key {
-			numValues[j+1] = numValues[j]
-			j--
-		}
-
-		numValues[j+1] = key
-	}
-
-	var median float64
-	mid := len(numValues) / 2
-
-	if len(numValues)%2 == 0 {
-		median = (numValues[mid-1] + numValues[mid]) / 2
-	} else {
-		median = numValues[mid]
-	}
-
-	bucketCount := 10
-	bucketSize := (max - min) / float64(bucketCount)
-	buckets := make([]HistogramBucket, bucketCount)
-
-	for i := 0; i < bucketCount; i++ {
-		lower := min + float64(i)*bucketSize
-		upper := min + float64(i+1)*bucketSize
-
-		if i == bucketCount-1 {
-			upper = max
-		}
-
-		buckets[i] = HistogramBucket{
-			LowerBound: lower,
-			UpperBound: upper,
-			Count:      0,
-		}
-	}
-
-	for _, v := range numValues {
-		bucketIndex := int((v - min) / bucketSize)
-		if bucketIndex >= bucketCount {
-			bucketIndex = bucketCount - 1
-		}
-		buckets[bucketIndex].Count++
-	}
-
-	outlierCount := 0
-	if stdDev > 0 {
-		for _, v := range numValues {
-			zScore := math.Abs(v-mean) / stdDev
-			if zScore > 3 {
-				outlierCount++
-			}
-		}
-	}
-
-	col.Min = min
-	col.Max = max
-	col.Mean = mean
-	col.Median = median
-	col.StdDev = stdDev
-	col.HistogramBuckets = buckets
-
-	if outlierCount > 0 {
-		outlierPct := float64(outlierCount) / float64(len(numValues)) * 100
-		severity := 1
-		if outlierPct > 5 {
-			severity = 2
-		}
-		if outlierPct > 10 {
-			severity = 3
-		}
-
-		col.QualityIssues = append(col.QualityIssues, QualityIssue{
-			Type:        "outliers",
-			Description: fmt.Sprintf("%d outliers detected (%.2f%%)", outlierCount, outlierPct),
-			Severity:    severity,
-		})
-	}
+	stats.apply(col, Options{}.histogram(), DefaultThresholds())
 }
 
 func getTopValues(valueCounts map[string]int, limit int) []ValueCount {
@@ -320,17 +275,7 @@ func getTopValues(valueCounts map[string]int, limit int) []ValueCount {
 		topValues = append(topValues, ValueCount{Value: value, Count: count})
 	}
 
-	for i := 1; i < len(topValues); i++ {
-		key := topValues[i]
-		j := i - 1
-
-		for j >= 0 && topValues[j].Count < key.Count {
-			topValues[j+1] = topValues[j]
-			j--
-		}
-
-		topValues[j+1] = key
-	}
+	sortValueCounts(topValues)
 
 	if len(topValues) > limit {
 		topValues = topValues[:limit]
@@ -340,21 +285,15 @@ func getTopValues(valueCounts map[string]int, limit int) []ValueCount {
 }
 
 func detectQualityIssues(col *ColumnProfile, rowCount int) {
+	thresholds := DefaultThresholds()
+
 	if col.MissingCount > 0 {
 		missingPercentage := float64(col.MissingCount) / float64(rowCount) * 100
-		severity := 1
-
-		if missingPercentage > 5 {
-			severity = 2
-		}
-		if missingPercentage > 20 {
-			severity = 3
-		}
 
 		col.QualityIssues = append(col.QualityIssues, QualityIssue{
 			Type:        "missing_values",
 			Description: fmt.Sprintf("Missing values: %.2f%%", missingPercentage),
-			Severity:    severity,
+			Severity:    thresholds.MissingValues.severity(missingPercentage),
 		})
 	}
 
@@ -368,7 +307,7 @@ func detectQualityIssues(col *ColumnProfile, rowCount int) {
 
 	if col.IsCategorical && len(col.TopValues) > 0 {
 		topValuePercentage := float64(col.TopValues[0].Count) / float64(col.Count) * 100
-		if topValuePercentage > 90 {
+		if topValuePercentage > thresholds.ImbalancedPercent {
 			col.QualityIssues = append(col.QualityIssues, QualityIssue{
 				Type:        "imbalanced",
 				Description: fmt.Sprintf("Imbalanced: top value appears in %.1f%% of records", topValuePercentage),
@@ -376,23 +315,39 @@ func detectQualityIssues(col *ColumnProfile, rowCount int) {
 			})
 		}
 	}
+
+	// Many categories seen only once are often typos or free text
+	if col.IsCategorical && col.Rare != nil && col.UniqueCount >= thresholds.SingletonHeavyMinUnique &&
+		col.Rare.Percent > thresholds.SingletonHeavyPercent {
+		col.QualityIssues = append(col.QualityIssues, QualityIssue{
+			Type:        "singleton_heavy",
+			Description: fmt.Sprintf("Singleton-heavy: %.1f%% of the %d distinct values appear once, possibly typos or free text", col.Rare.Percent, col.UniqueCount),
+			Severity:    2,
+		})
+	}
+
+	// Values of several types usually come from placeholders or shifted fields upstream
+	if col.MixedTypes != nil {
+		col.QualityIssues = append(col.QualityIssues, QualityIssue{
+			Type:        "mixed_types",
+			Description: "Mixed types: " + col.MixedTypes.describe(col.Count),
+			Severity:    2,
+		})
+	}
 }
 
 func collectDatasetQualityIssues(profile *DatasetProfile) {
+	thresholds := DefaultThresholds()
+
 	if profile.RowCount > 0 && profile.ColumnCount > 0 {
 		totalCells := profile.RowCount * profile.ColumnCount
 		missingPercentage := float64(profile.MissingCells) / float64(totalCells) * 100
 
-		if missingPercentage > 5 {
-			severity := 2
-			if missingPercentage > 20 {
-				severity = 3
-			}
-
+		if missingPercentage > thresholds.DatasetMissingValues.Medium {
 			profile.QualityIssues = append(profile.QualityIssues, QualityIssue{
 				Type:        "high_missing_values",
 				Description: fmt.Sprintf("High overall missing value rate: %.2f%%", missingPercentage),
-				Severity:    severity,
+				Severity:    thresholds.DatasetMissingValues.severity(missingPercentage),
 			})
 		}
 	}
@@ -400,19 +355,22 @@ func collectDatasetQualityIssues(profile *DatasetProfile) {
 	if profile.RowCount > 0 && profile.DuplicateRows > 0 {
 		duplicatePercentage := float64(profile.DuplicateRows) / float64(profile.RowCount) * 100
 
-		severity := 1
-		if duplicatePercentage > 5 {
-
-			severity = 2
-		}
-		if duplicatePercentage > 20 {
-			severity = 3
+		description := fmt.Sprintf("Duplicate rows detected: %.2f%%", duplicatePercentage)
+		if len(profile.UniqueKey) > 0 {
+			description = fmt.Sprintf("Duplicate keys (%s) detected: %.2f%% of rows", strings.Join(profile.UniqueKey, ", "), duplicatePercentage)
 		}
-
 		profile.QualityIssues = append(profile.QualityIssues, QualityIssue{
 			Type:        "duplicate_rows",
-			Description: fmt.Sprintf("Duplicate rows detected: %.2f%%", duplicatePercentage),
-			Severity:    severity,
+			Description: description,
+			Severity:    thresholds.DuplicateRows.severity(duplicatePercentage),
+		})
+	}
+
+	for _, pair := range profile.RedundantPairs {
+		profile.QualityIssues = append(profile.QualityIssues, QualityIssue{
+			Type:        "redundant_columns",
+			Description: fmt.Sprintf("Columns '%s' and '%s' are redundant: values match on %.1f%% of rows", pair.Column1, pair.Column2, pair.MatchPercent),
+			Severity:    1,
 		})
 	}
 }
@@ -1,8 +1,12 @@
 package profiler
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestProfileCSV(t *testing.T) {
@@ -80,6 +84,9 @@ value3,3,3.3,2023-01-03,value
 	if col.DataType != "datetime" {
 		t.Errorf("Expected col_date to be 'datetime', got '%s'", col.DataType)
 	}
+	if d := col.DateTime; d == nil || d.Granularity != "daily" || d.Span() != 3*24*time.Hour {
+		t.Errorf("Expected a daily series over 3 days for col_date, got %+v", d)
+	}
 
 	col, exists = profile.Columns["col_missing"]
 	if !exists {
@@ -231,3 +238,153 @@ func TestDetectQualityIssues(t *testing.T) {
 		t.Errorf("Expected severity 3, got %d", col.QualityIssues[0].Severity)
 	}
 }
+
+func TestProfileCSVOpaqueColumn(t *testing.T) {
+	tempFile, err := os.CreateTemp("", "test_*.csv")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	var content strings.Builder
+	content.WriteString("id,payload\n")
+	for i := 0; i < 150; i++ {
+		payload := strings.Repeat(fmt.Sprintf("QWxhZGRpbjpvcGVuIHNlc2FtZQ%03d", i), 8)
+		content.WriteString(fmt.Sprintf("%d,%s\n", i, payload))
+	}
+	if _, err := tempFile.Write([]byte(content.String())); err != nil {
+		t.Fatalf("Failed to write to temp file: %v", err)
+	}
+	tempFile.Close()
+
+	profile, err := ProfileCSV(tempFile.Name())
+	if err != nil {
+		t.Fatalf("ProfileCSV failed: %v", err)
+	}
+
+	col := profile.Columns["payload"]
+	if !col.IsOpaque {
+		t.Fatal("Expected payload column to be treated as opaque")
+	}
+
+	if col.DataType != "blob" {
+		t.Errorf("Expected payload to be 'blob', got '%s'", col.DataType)
+	}
+
+	if col.Count != 150 || col.UniqueCount != 150 {
+		t.Errorf("Expected 150 values and 150 unique, got %d and %d", col.Count, col.UniqueCount)
+	}
+
+	if col.AvgLength != 232 {
+		t.Errorf("Expected average length 232, got %.2f", col.AvgLength)
+	}
+
+	if len(col.TopValues) != 0 {
+		t.Errorf("Expected no top values for opaque column, got %d", len(col.TopValues))
+	}
+
+	if len(col.Notes) == 0 {
+		t.Error("Expected a note explaining the opaque treatment")
+	}
+
+	if profile.Columns["id"].IsOpaque {
+		t.Error("Expected id column not to be opaque")
+	}
+}
+
+func TestIsBase64Like(t *testing.T) {
+	if isBase64Like(strings.Repeat("ab12", 40)) {
+		t.Error("Expected lowercase hex-like value not to be base64")
+	}
+
+	if !isBase64Like(strings.Repeat("aB1+", 40)) {
+		t.Error("Expected mixed-case value to be base64")
+	}
+
+	if isBase64Like("aB1+") {
+		t.Error("Expected short value not to be base64")
+	}
+}
+
+func TestProfileCSVStreamingLargeFile(t *testing.T) {
+	tempFile, err := os.CreateTemp("", "large_*.csv")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	rows := 3 * maxTrackedValues
+	var content strings.Builder
+	content.WriteString("id,amount,category\n")
+	for i := 0; i < rows; i++ {
+		content.WriteString(fmt.Sprintf("%d,%d,cat%d\n", i, i%1000, i%4))
+	}
+	for i := 0; i < 25; i++ {
+		content.WriteString(fmt.Sprintf("%d,%d,cat%d\n", i, i%1000, i%4))
+	}
+	if _, err := tempFile.Write([]byte(content.String())); err != nil {
+		t.Fatalf("Failed to write to temp file: %v", err)
+	}
+	tempFile.Close()
+
+	profile, err := ProfileCSV(tempFile.Name())
+	if err != nil {
+		t.Fatalf("ProfileCSV failed: %v", err)
+	}
+
+	if profile.RowCount != rows+25 || profile.DuplicateRows != 25 {
+		t.Errorf("Expected %d rows with 25 duplicates, got %d and %d", rows+25, profile.RowCount, profile.DuplicateRows)
+	}
+
+	id := profile.Columns["id"]
+	if id.Count != rows+25 || len(id.Notes) == 0 {
+		t.Errorf("Expected estimated id column with %d values, got %d values and notes %v", rows+25, id.Count, id.Notes)
+	}
+	if relErr := float64(id.UniqueCount-rows) / float64(rows); relErr > 0.03 || relErr < -0.03 {
+		t.Errorf("Expected about %d unique ids, got %d", rows, id.UniqueCount)
+	}
+
+	amount := profile.Columns["amount"]
+	if amount.UniqueCount != 1000 || amount.Max.(float64) != 999 {
+		t.Errorf("Expected 1000 exact unique amounts up to 999, got %d and %v", amount.UniqueCount, amount.Max)
+	}
+	if amount.Median < 490 || amount.Median > 510 {
+		t.Errorf("Expected median near 500, got %v", amount.Median)
+	}
+
+	category := profile.Columns["category"]
+	if category.UniqueCount != 4 || category.TopValues[0].Count != rows/4+7 {
+		t.Errorf("Unexpected category column: unique=%d top=%v", category.UniqueCount, category.TopValues)
+	}
+}
+
+// Past a million distinct rows, an estimate of the distinct rows off by a
+// fraction of a percent would read as thousands of duplicates.
+func TestProfileCSVUniqueRowsNoDuplicates(t *testing.T) {
+	if testing.Short() {
+		t.Skip("profiles over a million rows")
+	}
+
+	var content strings.Builder
+	content.WriteString("id\n")
+	for i := 0; i < 1100000; i++ {
+		fmt.Fprintf(&content, "%d\n", i)
+	}
+	path := filepath.Join(t.TempDir(), "unique.csv")
+	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	profile, err := ProfileDatasetWithOptions(path, Options{Parallel: 4})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if profile.DuplicateRows != 0 {
+		t.Errorf("Expected no duplicate rows, got %d", profile.DuplicateRows)
+	}
+	for _, issue := range profile.QualityIssues {
+		if issue.Type == "duplicate_rows" {
+			t.Errorf("Unexpected issue %s", issue.Description)
+		}
+	}
+}
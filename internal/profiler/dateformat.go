@@ -0,0 +1,238 @@
+package profiler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats of timestamps written as integers, which are named rather than
+// given as time layouts. Epoch timestamps are only recognized in columns
+// named as times, such as created_at or eventTs, unless the config lists
+// them in DateFormats.
+const (
+	DateFormatEpochSeconds = "epoch-seconds"
+	DateFormatEpochMillis  = "epoch-millis"
+)
+
+// compactDateLayout reads dates written as integers, such as 20240131.
+const compactDateLayout = "20060102"
+
+// integerLayouts are the formats of timestamps written as integers, which
+// a column is only read in once most of its sample is.
+var integerLayouts = []string{compactDateLayout, DateFormatEpochSeconds, DateFormatEpochMillis}
+
+// Integers read as timestamps fall in these years, which keeps counts,
+// amounts and ids from reading as times.
+const (
+	epochFromYear   = 2000
+	epochToYear     = 2100
+	compactFromYear = 1900
+)
+
+// parseLayout reads value in a time layout or an integer format.
+func parseLayout(layout, value string) (time.Time, bool) {
+	switch layout {
+	case DateFormatEpochSeconds, DateFormatEpochMillis:
+		if !isDigits(value) {
+			return time.Time{}, false
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		t := time.Unix(n, 0).UTC()
+		if layout == DateFormatEpochMillis {
+			t = time.UnixMilli(n).UTC()
+		}
+		return t, t.Year() >= epochFromYear && t.Year() < epochToYear
+	case compactDateLayout:
+		if len(value) != len(compactDateLayout) || !isDigits(value) {
+			return time.Time{}, false
+		}
+		t, err := time.Parse(layout, value)
+		return t, err == nil && t.Year() >= compactFromYear && t.Year() < epochToYear
+	}
+	t, err := time.Parse(layout, value)
+	return t, err == nil
+}
+
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] < '0' || value[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateDateFormats checks the date formats of the config: time layouts
+// written with Go's reference time, such as 02/01/2006 15:04 or
+// 2006-01-02T15:04:05.000Z0700, or epoch-seconds or epoch-millis. A layout
+// must at least place the year.
+func ValidateDateFormats(formats []string) error {
+	reference := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	for _, format := range formats {
+		switch format {
+		case DateFormatEpochSeconds, DateFormatEpochMillis:
+			continue
+		case "":
+			return fmt.Errorf("date formats must not be empty")
+		}
+		t, err := time.Parse(format, reference.Format(format))
+		if err != nil || t.Year() != reference.Year() {
+			return fmt.Errorf("invalid date format %q: write a time layout with the year, such as 02/01/2006 for 31/12/2023, or use %s or %s",
+				format, DateFormatEpochSeconds, DateFormatEpochMillis)
+		}
+	}
+	return nil
+}
+
+// dateFormat reads the timestamps of a column: in the formats of the
+// config first, then in the built-in layouts, with dates such as
+// 01/02/2006 read in its order, and last as integers in its integer layout,
+// if it has one.
+type dateFormat struct {
+	custom   []string // Options.DateFormats
+	dayFirst bool
+	integers string // integer layout, empty when integers are not read as timestamps
+	epoch    bool   // integers may be epoch timestamps, in a column named as a time
+}
+
+func (f dateFormat) parse(value string) (time.Time, bool) {
+	_, t, ok := f.read(value)
+	return t, ok
+}
+
+// read returns the layout that reads value, and the timestamp it reads.
+func (f dateFormat) read(value string) (string, time.Time, bool) {
+	if layout, t, ok := f.readText(value); ok {
+		return layout, t, true
+	}
+	if f.integers != "" {
+		if t, ok := parseLayout(f.integers, value); ok {
+			return f.integers, t, true
+		}
+	}
+	return "", time.Time{}, false
+}
+
+// readText reads value in the formats of the config and the built-in
+// layouts, leaving integer layouts out.
+func (f dateFormat) readText(value string) (string, time.Time, bool) {
+	for _, layout := range f.custom {
+		if t, ok := parseLayout(layout, value); ok {
+			return layout, t, true
+		}
+	}
+	layouts := dateTimeLayouts
+	if f.dayFirst {
+		layouts = dayFirstLayouts
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return layout, t, true
+		}
+	}
+	return "", time.Time{}, false
+}
+
+// inOrder returns f reading dates day first or month first.
+func (f dateFormat) inOrder(dayFirst bool) dateFormat {
+	f.dayFirst = dayFirst
+	return f
+}
+
+// orderName is the date order reported for the column.
+func (f dateFormat) orderName() string {
+	if f.dayFirst {
+		return DateOrderDayFirst
+	}
+	return ""
+}
+
+// detectIntegers picks the integer layout at least 90% of sample reads in
+// and no other format does: dates such as 20240131, or epoch timestamps in
+// a column named as a time. It returns the empty layout otherwise.
+func (f dateFormat) detectIntegers(sample []string) string {
+	if len(sample) == 0 {
+		return ""
+	}
+	counts := make(map[string]int)
+	for _, value := range sample {
+		if _, _, ok := f.readText(value); ok {
+			continue
+		}
+		for _, layout := range integerLayouts {
+			if layout != compactDateLayout && !f.epoch {
+				continue
+			}
+			if _, ok := parseLayout(layout, value); ok {
+				counts[layout]++
+				break
+			}
+		}
+	}
+	for _, layout := range integerLayouts {
+		if float64(counts[layout]) >= float64(len(sample))*0.9 {
+			return layout
+		}
+	}
+	return ""
+}
+
+// layoutOf returns the layout most of sample reads in, the first of the
+// most common on a tie, or the empty layout when none of it reads.
+func (f dateFormat) layoutOf(sample []string) string {
+	counts := make(map[string]int)
+	var layouts []string
+	for _, value := range sample {
+		layout, _, ok := f.read(value)
+		if !ok {
+			continue
+		}
+		if counts[layout] == 0 {
+			layouts = append(layouts, layout)
+		}
+		counts[layout]++
+	}
+
+	best := ""
+	for _, layout := range layouts {
+		if counts[layout] > counts[best] {
+			best = layout
+		}
+	}
+	return best
+}
+
+// namedAsTime reports whether a column name says it holds times, such as
+// created_at, createdAt, event_ts, timestamp or epoch, so that its
+// integers may be epoch timestamps.
+func namedAsTime(name string) bool {
+	lower := strings.ToLower(name)
+	for _, word := range []string{"time", "date", "epoch"} {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	for _, suffix := range []string{"_at", "_ts", "-at", "-ts", " at"} {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return lower == "ts" || strings.HasSuffix(name, "At") || strings.HasSuffix(name, "Ts")
+}
+
+// dateFormat is the date format column starts from: the formats of the
+// config, the date order of Locale, and whether its integers may be epoch
+// timestamps. The bool reports whether Locale sets the order rather than
+// leaving it to be detected.
+func (o Options) dateFormat(column string) (dateFormat, bool) {
+	l, ok, _ := lookupLocale(o.Locale)
+	return dateFormat{custom: o.DateFormats, dayFirst: l.dayFirst, epoch: namedAsTime(column)}, ok
+}
@@ -0,0 +1,212 @@
+package profiler
+
+import (
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDateTimeLayouts(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2024-03-01T10:30:00Z", time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)},
+		{"2024-03-01T10:30:00.250+01:00", time.Date(2024, 3, 1, 9, 30, 0, 250e6, time.UTC)},
+		{"2024-03-01T10:30:00+0100", time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)},
+		{"2024-03-01 10:30:00+00", time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)},
+		{"2024-03-01 10:30:00", time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)},
+		{"2024/03/01", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"03/01/2024", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"Mar 1 2024", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"Mar 1, 2024", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"1 March 2024", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"01-Mar-2024", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, ok := ParseDateTime(tt.value)
+		if !ok || !got.Equal(tt.want) {
+			t.Errorf("%s: expected %v, got %v (%v)", tt.value, tt.want, got, ok)
+		}
+	}
+
+	for _, value := range []string{"20240301", "1709288400", "March", "2024-13-01"} {
+		if _, ok := ParseDateTime(value); ok {
+			t.Errorf("Expected %s not to read as a timestamp", value)
+		}
+	}
+}
+
+func TestParseLayout(t *testing.T) {
+	tests := []struct {
+		layout, value string
+		ok            bool
+	}{
+		{compactDateLayout, "20240131", true},
+		{compactDateLayout, "20241301", false},
+		{compactDateLayout, "12345678", false},
+		{compactDateLayout, "2024013", false},
+		{DateFormatEpochSeconds, "1709288400", true},
+		{DateFormatEpochSeconds, "1709288400000", false},
+		{DateFormatEpochSeconds, "12345", false},
+		{DateFormatEpochSeconds, "-1709288400", false},
+		{DateFormatEpochMillis, "1709288400000", true},
+		{DateFormatEpochMillis, "1709288400", false},
+		{"02|01|2006", "31|12|2023", true},
+	}
+	for _, tt := range tests {
+		if _, ok := parseLayout(tt.layout, tt.value); ok != tt.ok {
+			t.Errorf("%s in %s: expected %v, got %v", tt.value, tt.layout, tt.ok, ok)
+		}
+	}
+
+	got, _ := parseLayout(DateFormatEpochMillis, "1709288400250")
+	if want := time.Date(2024, 3, 1, 10, 20, 0, 250e6, time.UTC); !got.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestDetectIntegers(t *testing.T) {
+	epochs := []string{"1709288400", "1709292000", "1709295600"}
+	tests := []struct {
+		name   string
+		format dateFormat
+		sample []string
+		want   string
+	}{
+		{"compact dates", dateFormat{}, []string{"20240131", "20240201", "20231231"}, compactDateLayout},
+		{"epoch unnamed", dateFormat{}, epochs, ""},
+		{"epoch named", dateFormat{epoch: true}, epochs, DateFormatEpochSeconds},
+		{"millis", dateFormat{epoch: true}, []string{"1709288400000", "1709288400500"}, DateFormatEpochMillis},
+		{"counts", dateFormat{epoch: true}, []string{"1", "20", "300"}, ""},
+		{"mixed", dateFormat{epoch: true}, []string{"20240131", "1709288400"}, ""},
+		{"config first", dateFormat{custom: []string{DateFormatEpochSeconds}}, epochs, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.format.detectIntegers(tt.sample); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNamedAsTime(t *testing.T) {
+	for _, name := range []string{"created_at", "createdAt", "event_ts", "ts", "timestamp", "Order Date", "epoch", "UpdatedTime"} {
+		if !namedAsTime(name) {
+			t.Errorf("Expected %s named as a time", name)
+		}
+	}
+	for _, name := range []string{"id", "order_id", "format", "stats", "seat", "amount"} {
+		if namedAsTime(name) {
+			t.Errorf("Expected %s not named as a time", name)
+		}
+	}
+}
+
+func TestValidateDateFormats(t *testing.T) {
+	if err := ValidateDateFormats([]string{"02.01.2006 15:04", "2006-01-02T15:04:05.000Z0700", "060102", DateFormatEpochSeconds}); err != nil {
+		t.Errorf("Expected the formats to be valid, got %v", err)
+	}
+	for _, format := range []string{"", "15:04:05", "%Y-%m-%d", "epoch"} {
+		if err := ValidateDateFormats([]string{format}); err == nil {
+			t.Errorf("Expected %q rejected", format)
+		}
+	}
+	path := writeTestFile(t, "events.csv", dateFormatsCSV(1))
+	if _, err := ProfileDatasetWithOptions(path, Options{DateFormats: []string{"15:04"}}); err == nil || !strings.Contains(err.Error(), "invalid date format") {
+		t.Errorf("Expected Options with an invalid date format rejected, got %v", err)
+	}
+}
+
+func dateFormatsCSV(rows int) string {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	w.Write([]string{"id", "created_at", "order_id", "day", "shipped", "logged", "sent"})
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < rows; i++ {
+		at := start.Add(time.Duration(i) * time.Hour)
+		w.Write([]string{
+			strconv.Itoa(1700000000 + i),
+			strconv.FormatInt(at.Unix(), 10),
+			strconv.FormatInt(at.UnixMilli(), 10),
+			at.Format("20060102"),
+			at.Format("Jan 2, 2006"),
+			at.Format("2006-01-02 15:04:05-0700"),
+			at.Format("2.1.06 15h04"),
+		})
+	}
+	w.Flush()
+	return b.String()
+}
+
+func TestProfileDateFormats(t *testing.T) {
+	path := writeTestFile(t, "events.csv", dateFormatsCSV(500))
+
+	profile, err := ProfileDatasetWithOptions(path, Options{})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	want := map[string]string{
+		"created_at": DateFormatEpochSeconds,
+		"day":        compactDateLayout,
+		"shipped":    "Jan 2, 2006",
+		"logged":     "2006-01-02 15:04:05Z0700",
+	}
+	for name, format := range want {
+		col := profile.Columns[name]
+		if col.DataType != "datetime" || col.DateFormat != format || col.DateTime == nil {
+			t.Errorf("%s: expected a datetime column in %q, got %s in %q", name, format, col.DataType, col.DateFormat)
+		}
+	}
+	if col := profile.Columns["created_at"]; col.DateTime != nil && !col.DateTime.Min.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the first epoch timestamp at 2024-01-01, got %v", col.DateTime.Min)
+	}
+	for _, name := range []string{"id", "order_id"} {
+		if col := profile.Columns[name]; col.DataType != "integer" || col.DateFormat != "" {
+			t.Errorf("%s: expected integers outside of a column named as a time, got %s in %q", name, col.DataType, col.DateFormat)
+		}
+	}
+	if col := profile.Columns["sent"]; col.DataType != "string" {
+		t.Errorf("Expected an unknown layout to stay a string, got %s", col.DataType)
+	}
+
+	profile, err = ProfileDatasetWithOptions(path, Options{DateFormats: []string{"2.1.06 15h04", DateFormatEpochMillis}})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	for name, format := range map[string]string{"sent": "2.1.06 15h04", "order_id": DateFormatEpochMillis} {
+		if col := profile.Columns[name]; col.DataType != "datetime" || col.DateFormat != format {
+			t.Errorf("%s: expected the configured format %q, got %s in %q", name, format, col.DataType, col.DateFormat)
+		}
+	}
+	if col := profile.Columns["sent"]; col.DateTime == nil || col.DateTime.Precision != "hour" {
+		t.Errorf("Expected hourly timestamps read in the configured format, got %+v", col.DateTime)
+	}
+}
+
+func TestProfileDateFormatsParallel(t *testing.T) {
+	withParallelMinChunk(t, 256)
+	path := writeTestFile(t, "events.csv", dateFormatsCSV(2000))
+	opts := Options{DateFormats: []string{"2.1.06 15h04"}}
+
+	want, err := ProfileDatasetWithOptions(path, opts)
+	if err != nil {
+		t.Fatalf("Failed to profile sequentially: %v", err)
+	}
+	opts.Parallel = 8
+	got, err := ProfileDatasetWithOptions(path, opts)
+	if err != nil {
+		t.Fatalf("Failed to profile in parallel: %v", err)
+	}
+
+	for _, name := range []string{"created_at", "day", "shipped", "logged", "sent"} {
+		w, g := want.Columns[name], got.Columns[name]
+		if g.DataType != w.DataType || g.DateFormat != w.DateFormat || g.DateTime == nil || w.DateTime == nil ||
+			!g.DateTime.Min.Equal(w.DateTime.Min) || !g.DateTime.Max.Equal(w.DateTime.Max) || g.ParseErrors.Unparseable != w.ParseErrors.Unparseable {
+			t.Errorf("%s: expected %s in %q, got %s in %q", name, w.DataType, w.DateFormat, g.DataType, g.DateFormat)
+		}
+	}
+}
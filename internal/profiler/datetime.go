@@ -0,0 +1,363 @@
+package profiler
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxTrackedTimes bounds the distinct timestamps kept to look for gaps.
+const maxTrackedTimes = 100000
+
+// isoLayouts are ISO 8601 timestamps, with a T or a space before the time
+// and the offset, if any, written as Z, +01:00, +0100 or +01. Fractions of
+// a second are read whatever the layout.
+var isoLayouts = []string{
+	time.RFC3339, "2006-01-02T15:04:05Z0700", "2006-01-02T15:04:05Z07", "2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00", "2006-01-02 15:04:05Z0700", "2006-01-02 15:04:05Z07", "2006-01-02 15:04:05",
+	"2006-01-02", "2006/01/02",
+}
+
+// namedMonthLayouts are dates with the month named, in English, which read
+// the same in either date order.
+var namedMonthLayouts = []string{
+	"Jan 2 2006", "Jan 2, 2006", "2 Jan 2006", "02-Jan-2006",
+	"January 2 2006", "January 2, 2006", "2 January 2006",
+	time.RFC1123Z, time.RFC1123,
+}
+
+// dateTimeLayouts are the timestamp formats recognized in text sources,
+// tried in order. Dates such as 01/02/2006 are read month first;
+// dayFirstLayouts read them day first, also with dots or dashes.
+var (
+	dateTimeLayouts = slices.Concat(isoLayouts, []string{"01/02/2006"}, namedMonthLayouts)
+	dayFirstLayouts = slices.Concat(isoLayouts, []string{"02/01/2006", "02.01.2006", "02-01-2006"}, namedMonthLayouts)
+)
+
+// ParseDateTime reads a timestamp in one of the formats recognized in text
+// sources.
+func ParseDateTime(value string) (time.Time, bool) {
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Precisions of timestamps, from the finest. A timestamp has the coarsest
+// precision it is a whole number of, so 2024-03-01 is month precise.
+var timePrecisions = []string{"second", "minute", "hour", "day", "month", "year"}
+
+const (
+	precisionSecond = iota
+	precisionMinute
+	precisionHour
+	precisionDay
+	precisionMonth
+	precisionYear
+)
+
+var precisionSeconds = []int64{1, 60, 3600, 86400}
+
+func timePrecision(t time.Time) int {
+	switch {
+	case t.Nanosecond() != 0 || t.Second() != 0:
+		return precisionSecond
+	case t.Minute() != 0:
+		return precisionMinute
+	case t.Hour() != 0:
+		return precisionHour
+	case t.Day() != 1:
+		return precisionDay
+	case t.Month() != time.January:
+		return precisionMonth
+	default:
+		return precisionYear
+	}
+}
+
+// DateTimeStats describes the timestamps of a datetime column. Times are in
+// UTC.
+type DateTimeStats struct {
+	Min            time.Time
+	Max            time.Time
+	Precision      string // most common precision: second, minute, hour, day, month or year
+	Granularity    string // most common step between timestamps, such as daily or hourly
+	Gaps           int    // breaks in a regular series longer than its step
+	MissingPeriods int    // steps missing across the gaps
+	LargestGap     *TimeGap
+	Histogram      []TimeBucket
+}
+
+// Span is the time between the first and last timestamps.
+func (d *DateTimeStats) Span() time.Duration {
+	return d.Max.Sub(d.Min)
+}
+
+// Format prints a timestamp of the column, as a date when the column holds
+// dates.
+func (d *DateTimeStats) Format(t time.Time) string {
+	switch d.Precision {
+	case "day", "month", "year":
+		return t.Format("2006-01-02")
+	default:
+		return t.Format("2006-01-02 15:04:05")
+	}
+}
+
+// TimeGap is a break in a time series between two consecutive timestamps.
+type TimeGap struct {
+	From time.Time
+	To   time.Time
+}
+
+type TimeBucket struct {
+	Start time.Time
+	End   time.Time
+	Count int
+}
+
+// dateTimeStats accumulates the timestamps of a datetime column: their
+// seconds feed a numericStats for the range and histogram, and distinct
+// timestamps are kept up to maxTrackedTimes to find the step and gaps of the
+// series.
+type dateTimeStats struct {
+	seconds    *numericStats
+	precisions []int
+	times      map[int64]bool // nil once there are too many
+}
+
+func newDateTimeStats() *dateTimeStats {
+	return &dateTimeStats{
+		seconds:    newNumericStats(),
+		precisions: make([]int, len(timePrecisions)),
+		times:      make(map[int64]bool),
+	}
+}
+
+func (s *dateTimeStats) add(t time.Time) {
+	sec := t.Unix()
+	s.seconds.add(float64(sec))
+	s.precisions[timePrecision(t)]++
+
+	if s.times != nil && !s.times[sec] {
+		if len(s.times) == maxTrackedTimes {
+			s.times = nil
+			return
+		}
+		s.times[sec] = true
+	}
+}
+
+func (s *dateTimeStats) merge(o *dateTimeStats) {
+	s.seconds.merge(o.seconds)
+	for i, n := range o.precisions {
+		s.precisions[i] += n
+	}
+
+	if s.times == nil || o.times == nil {
+		s.times = nil
+		return
+	}
+	for sec := range o.times {
+		if len(s.times) == maxTrackedTimes && !s.times[sec] {
+			s.times = nil
+			return
+		}
+		s.times[sec] = true
+	}
+}
+
+// apply fills in the datetime statistics of col and flags gaps in a regular
+// series.
+func (s *dateTimeStats) apply(col *ColumnProfile) {
+	if s.seconds.count == 0 {
+		return
+	}
+
+	precision := 0
+	for i, n := range s.precisions {
+		if n > s.precisions[precision] {
+			precision = i
+		}
+	}
+
+	stats := &DateTimeStats{
+		Min:         time.Unix(int64(s.seconds.min), 0).UTC(),
+		Max:         time.Unix(int64(s.seconds.max), 0).UTC(),
+		Precision:   timePrecisions[precision],
+		Granularity: granularity(precision, 1),
+	}
+
+	var buckets []HistogramBucket
+	if s.seconds.approximate() {
+		buckets = s.seconds.estimatedHistogram(s.seconds.histogramBounds(DefaultHistogramBuckets))
+	} else {
+		buckets = s.seconds.exactHistogram(DefaultHistogramBuckets)
+	}
+	stats.Histogram = make([]TimeBucket, len(buckets))
+	for i, bucket := range buckets {
+		stats.Histogram[i] = TimeBucket{
+			Start: time.Unix(int64(math.Round(bucket.LowerBound)), 0).UTC(),
+			End:   time.Unix(int64(math.Round(bucket.UpperBound)), 0).UTC(),
+			Count: bucket.Count,
+		}
+	}
+
+	col.DateTime = stats
+
+	if s.times == nil {
+		col.Notes = append(col.Notes, fmt.Sprintf(
+			"More than %d distinct timestamps: gaps were not checked", maxTrackedTimes))
+		return
+	}
+	s.findGaps(stats, precision)
+
+	if stats.Gaps > 0 {
+		col.QualityIssues = append(col.QualityIssues, QualityIssue{
+			Type: "time_gaps",
+			Description: fmt.Sprintf("%d gaps in the %s series (%d missing periods)",
+				stats.Gaps, stats.Granularity, stats.MissingPeriods),
+			Severity: 1,
+		})
+	}
+}
+
+// findGaps takes the most common difference between consecutive distinct
+// timestamps, in units of the precision, as the step of the series. When at
+// least half of the differences are that step the series is regular, and
+// longer differences are gaps.
+func (s *dateTimeStats) findGaps(stats *DateTimeStats, precision int) {
+	periods := make([]int64, 0, len(s.times))
+	seen := make(map[int64]bool, len(s.times))
+	for sec := range s.times {
+		period := periodIndex(sec, precision)
+		if !seen[period] {
+			seen[period] = true
+			periods = append(periods, period)
+		}
+	}
+	if len(periods) < 3 {
+		return
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i] < periods[j] })
+
+	steps := make(map[int64]int)
+	for i := 1; i < len(periods); i++ {
+		steps[periods[i]-periods[i-1]]++
+	}
+	var step int64
+	for diff, n := range steps {
+		if n > steps[step] || n == steps[step] && diff < step {
+			step = diff
+		}
+	}
+	if steps[step]*2 < len(periods)-1 {
+		return
+	}
+	stats.Granularity = granularity(precision, step)
+
+	var largest int64
+	for i := 1; i < len(periods); i++ {
+		diff := periods[i] - periods[i-1]
+		if diff <= step {
+			continue
+		}
+		stats.Gaps++
+		stats.MissingPeriods += int((diff+step-1)/step) - 1
+		if diff > largest {
+			largest = diff
+			stats.LargestGap = &TimeGap{
+				From: periodTime(periods[i-1], precision),
+				To:   periodTime(periods[i], precision),
+			}
+		}
+	}
+}
+
+// periodIndex numbers the periods of a precision, so that consecutive
+// periods differ by one.
+func periodIndex(sec int64, precision int) int64 {
+	switch precision {
+	case precisionMonth:
+		t := time.Unix(sec, 0).UTC()
+		return int64(t.Year())*12 + int64(t.Month()) - 1
+	case precisionYear:
+		return int64(time.Unix(sec, 0).UTC().Year())
+	default:
+		size := precisionSeconds[precision]
+		index := sec / size
+		if sec < 0 && sec%size != 0 {
+			index--
+		}
+		return index
+	}
+}
+
+func periodTime(period int64, precision int) time.Time {
+	switch precision {
+	case precisionMonth:
+		return time.Date(int(period/12), time.Month(period%12+1), 1, 0, 0, 0, 0, time.UTC)
+	case precisionYear:
+		return time.Date(int(period), time.January, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Unix(period*precisionSeconds[precision], 0).UTC()
+	}
+}
+
+// granularity names a step of a number of periods of a precision.
+func granularity(precision int, step int64) string {
+	names := map[int]map[int64]string{
+		precisionSecond: {1: "every second"},
+		precisionMinute: {1: "every minute"},
+		precisionHour:   {1: "hourly"},
+		precisionDay:    {1: "daily", 7: "weekly"},
+		precisionMonth:  {1: "monthly", 3: "quarterly"},
+		precisionYear:   {1: "yearly"},
+	}
+	if name, ok := names[precision][step]; ok {
+		return name
+	}
+	return fmt.Sprintf("every %d %ss", step, timePrecisions[precision])
+}
+
+// FormatSpan prints a duration in its two largest units, e.g. 3 days 4 hours.
+func FormatSpan(d time.Duration) string {
+	units := []struct {
+		name string
+		size time.Duration
+	}{
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+		{"second", time.Second},
+	}
+
+	parts := make([]string, 0, 2)
+	for _, unit := range units {
+		n := int64(d / unit.size)
+		if n == 0 && len(parts) == 0 {
+			continue
+		}
+		d -= time.Duration(n) * unit.size
+		if n > 0 {
+			name := unit.name
+			if n != 1 {
+				name += "s"
+			}
+			parts = append(parts, fmt.Sprintf("%d %s", n, name))
+		}
+		if len(parts) == 2 || len(parts) > 0 && n == 0 {
+			break
+		}
+	}
+
+	if len(parts) == 0 {
+		return "0 seconds"
+	}
+	return strings.Join(parts, " ")
+}
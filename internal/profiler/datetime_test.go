@@ -0,0 +1,138 @@
+package profiler
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func dateTimeStatsOf(t *testing.T, values []string) *DateTimeStats {
+	t.Helper()
+
+	stats := newDateTimeStats()
+	for _, v := range values {
+		parsed, ok := ParseDateTime(v)
+		if !ok {
+			t.Fatalf("Failed to parse %q", v)
+		}
+		stats.add(parsed)
+	}
+	col := &ColumnProfile{}
+	stats.apply(col)
+	return col.DateTime
+}
+
+func TestDateTimeStatsDailyGaps(t *testing.T) {
+	values := make([]string, 0)
+	for day := 1; day <= 31; day++ {
+		if day >= 10 && day <= 12 || day == 20 {
+			continue
+		}
+		values = append(values, time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC).Format("2006-01-02"))
+	}
+	// Repeated dates do not count as steps
+	values = append(values, "2024-01-05", "2024-01-05")
+
+	stats := newDateTimeStats()
+	for _, v := range values {
+		parsed, _ := ParseDateTime(v)
+		stats.add(parsed)
+	}
+	col := &ColumnProfile{}
+	stats.apply(col)
+	d := col.DateTime
+
+	if d.Precision != "day" || d.Granularity != "daily" {
+		t.Errorf("Expected a daily series, got %s precision and %s", d.Precision, d.Granularity)
+	}
+	if d.Format(d.Min) != "2024-01-01" || d.Format(d.Max) != "2024-01-31" || d.Span() != 30*24*time.Hour {
+		t.Errorf("Expected 2024-01-01 to 2024-01-31, got %s to %s", d.Format(d.Min), d.Format(d.Max))
+	}
+	if d.Gaps != 2 || d.MissingPeriods != 4 {
+		t.Errorf("Expected 2 gaps with 4 missing days, got %d and %d", d.Gaps, d.MissingPeriods)
+	}
+	if d.LargestGap == nil || d.Format(d.LargestGap.From) != "2024-01-09" || d.Format(d.LargestGap.To) != "2024-01-13" {
+		t.Errorf("Expected the largest gap from 2024-01-09 to 2024-01-13, got %+v", d.LargestGap)
+	}
+
+	total := 0
+	for _, bucket := range d.Histogram {
+		total += bucket.Count
+	}
+	if len(d.Histogram) != DefaultHistogramBuckets || total != len(values) {
+		t.Errorf("Expected %d buckets holding %d values, got %v", DefaultHistogramBuckets, len(values), d.Histogram)
+	}
+
+	if len(col.QualityIssues) != 1 || col.QualityIssues[0].Description != "2 gaps in the daily series (4 missing periods)" {
+		t.Errorf("Expected a time gaps issue, got %v", col.QualityIssues)
+	}
+}
+
+func TestDateTimeStatsGranularity(t *testing.T) {
+	tests := []struct {
+		name        string
+		values      []string
+		precision   string
+		granularity string
+	}{
+		{"hourly", []string{"2024-03-10T01:00:00Z", "2024-03-10T02:00:00Z", "2024-03-10T03:00:00Z", "2024-03-10T05:00:00Z"}, "hour", "hourly"},
+		{"quarter hours", []string{"2024-03-10T01:00:00Z", "2024-03-10T01:15:00Z", "2024-03-10T01:30:00Z", "2024-03-10T01:45:00Z"}, "minute", "every 15 minutes"},
+		{"weekly", []string{"2024-01-01", "2024-01-08", "2024-01-15", "2024-01-22"}, "day", "weekly"},
+		{"monthly", []string{"2023-11-01", "2023-12-01", "2024-02-01", "2024-03-01"}, "month", "monthly"},
+		{"irregular", []string{"2024-01-02", "2024-01-03", "2024-01-09", "2024-01-30", "2024-02-25"}, "day", "daily"},
+	}
+
+	for _, test := range tests {
+		d := dateTimeStatsOf(t, test.values)
+		if d.Precision != test.precision || d.Granularity != test.granularity {
+			t.Errorf("%s: expected %s precision and %s, got %s and %s",
+				test.name, test.precision, test.granularity, d.Precision, d.Granularity)
+		}
+	}
+
+	if d := dateTimeStatsOf(t, tests[3].values); d.Gaps != 1 || d.MissingPeriods != 1 {
+		t.Errorf("Expected January missing from the monthly series, got %d gaps and %d missing", d.Gaps, d.MissingPeriods)
+	}
+	if d := dateTimeStatsOf(t, tests[4].values); d.Gaps != 0 || d.LargestGap != nil {
+		t.Errorf("Expected no gaps in an irregular series, got %d", d.Gaps)
+	}
+}
+
+func TestDateTimeStatsMerge(t *testing.T) {
+	values := []string{"2024-01-01T00:00:00Z", "2024-01-01T01:00:00Z", "2024-01-01T04:00:00Z",
+		"2024-01-01T05:00:00Z", "2024-01-01T06:00:00Z", "2024-01-01T07:00:00Z"}
+	want := dateTimeStatsOf(t, values)
+
+	first, second := newDateTimeStats(), newDateTimeStats()
+	for i, v := range values {
+		parsed, _ := ParseDateTime(v)
+		if i < 3 {
+			first.add(parsed)
+		} else {
+			second.add(parsed)
+		}
+	}
+	first.merge(second)
+	col := &ColumnProfile{}
+	first.apply(col)
+
+	if !reflect.DeepEqual(col.DateTime, want) {
+		t.Errorf("Expected merged stats %+v, got %+v", want, col.DateTime)
+	}
+}
+
+func TestFormatSpan(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                              "0 seconds",
+		45 * time.Second:               "45 seconds",
+		time.Hour + 90*time.Second:     "1 hour 1 minute",
+		3*24*time.Hour + 4*time.Hour:   "3 days 4 hours",
+		3*24*time.Hour + 4*time.Minute: "3 days",
+	}
+
+	for d, expected := range tests {
+		if got := FormatSpan(d); got != expected {
+			t.Errorf("FormatSpan(%v): expected %q, got %q", d, expected, got)
+		}
+	}
+}
@@ -0,0 +1,351 @@
+package profiler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/remote"
+	"github.com/parquet-go/parquet-go"
+)
+
+const deltaLogDir = "_delta_log"
+
+// deltaAction is one line of a Delta commit file.
+type deltaAction struct {
+	Add      *deltaAdd      `json:"add"`
+	Remove   *deltaRemove   `json:"remove"`
+	MetaData *deltaMetaData `json:"metaData"`
+}
+
+type deltaAdd struct {
+	Path            string             `json:"path"`
+	PartitionValues map[string]*string `json:"partitionValues"`
+	Size            int64              `json:"size"`
+	DeletionVector  json.RawMessage    `json:"deletionVector"`
+}
+
+type deltaRemove struct {
+	Path string `json:"path"`
+}
+
+type deltaMetaData struct {
+	SchemaString     string   `json:"schemaString"`
+	PartitionColumns []string `json:"partitionColumns"`
+}
+
+// deltaCheckpointRow is one action of a Parquet checkpoint, which holds the
+// table state at its version.
+type deltaCheckpointRow struct {
+	Add *struct {
+		Path            string            `parquet:"path"`
+		PartitionValues map[string]string `parquet:"partitionValues"`
+		Size            int64             `parquet:"size"`
+		DeletionVector  *struct {
+			StorageType string `parquet:"storageType"`
+		} `parquet:"deletionVector,optional"`
+	} `parquet:"add,optional"`
+	Remove *struct {
+		Path string `parquet:"path"`
+	} `parquet:"remove,optional"`
+	MetaData *struct {
+		SchemaString     string   `parquet:"schemaString"`
+		PartitionColumns []string `parquet:"partitionColumns,list"`
+	} `parquet:"metaData,optional"`
+}
+
+type deltaLastCheckpoint struct {
+	Version int64 `json:"version"`
+	Parts   int   `json:"parts"`
+}
+
+// deltaState is the table state built by replaying the log.
+type deltaState struct {
+	version  int64
+	files    map[string]*deltaAdd
+	metaData *deltaMetaData
+}
+
+func (s *deltaState) apply(action deltaAction) {
+	switch {
+	case action.Add != nil:
+		s.files[action.Add.Path] = action.Add
+	case action.Remove != nil:
+		delete(s.files, action.Remove.Path)
+	case action.MetaData != nil:
+		s.metaData = action.MetaData
+	}
+}
+
+// readDeltaSnapshot replays the transaction log of a Delta table: the
+// latest checkpoint, if any, then every later commit, leaving the files
+// added and not since removed. Tables with deletion vectors are refused:
+// the rows they delete would still be counted.
+func readDeltaSnapshot(store *tableStore) (*tableSnapshot, error) {
+	state := &deltaState{version: -1, files: make(map[string]*deltaAdd)}
+
+	checkpoint, err := findDeltaCheckpoint(store)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint != nil {
+		if err := readDeltaCheckpoint(store, checkpoint, state); err != nil {
+			return nil, err
+		}
+		state.version = checkpoint.Version
+	}
+
+	versions, listed := deltaCommits(store, state.version)
+	for i := 0; ; i++ {
+		version := state.version + 1
+		if listed {
+			if i >= len(versions) {
+				break
+			}
+			if versions[i] != version {
+				return nil, fmt.Errorf("Delta log is missing commit %d", version)
+			}
+		}
+
+		data, err := store.readAll(deltaLogDir + "/" + deltaCommitName(version))
+		if errors.Is(err, errTableFileMissing) && !listed {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Delta commit %d: %w", version, err)
+		}
+		if err := applyDeltaCommit(data, state); err != nil {
+			return nil, fmt.Errorf("failed to read Delta commit %d: %w", version, err)
+		}
+		state.version = version
+	}
+
+	if state.version < 0 {
+		return nil, fmt.Errorf("no Delta commits found in %s", store.join(deltaLogDir))
+	}
+	if state.metaData == nil {
+		return nil, fmt.Errorf("Delta log has no table metadata")
+	}
+
+	return deltaSnapshot(store, state)
+}
+
+// findDeltaCheckpoint reads _last_checkpoint, falling back to the newest
+// single-file checkpoint in a local log.
+func findDeltaCheckpoint(store *tableStore) (*deltaLastCheckpoint, error) {
+	data, err := store.readAll(deltaLogDir + "/_last_checkpoint")
+	if err == nil {
+		var last deltaLastCheckpoint
+		if err := json.Unmarshal(data, &last); err != nil {
+			return nil, fmt.Errorf("failed to read Delta _last_checkpoint: %w", err)
+		}
+		return &last, nil
+	}
+	if !errors.Is(err, errTableFileMissing) {
+		return nil, err
+	}
+
+	names, _ := store.list(deltaLogDir)
+	var last *deltaLastCheckpoint
+	for _, name := range names {
+		prefix, ok := strings.CutSuffix(name, ".checkpoint.parquet")
+		if !ok {
+			continue
+		}
+		if version, err := strconv.ParseInt(prefix, 10, 64); err == nil {
+			last = &deltaLastCheckpoint{Version: version}
+		}
+	}
+	return last, nil
+}
+
+// deltaCommits lists the commit versions after the given one in a local
+// log. A remote log cannot be listed, so commits are read until one is
+// missing.
+func deltaCommits(store *tableStore, after int64) ([]int64, bool) {
+	names, listed := store.list(deltaLogDir)
+	if !listed {
+		return nil, false
+	}
+
+	versions := make([]int64, 0)
+	for _, name := range names {
+		prefix, ok := strings.CutSuffix(name, ".json")
+		if !ok || len(prefix) != 20 {
+			continue
+		}
+		if version, err := strconv.ParseInt(prefix, 10, 64); err == nil && version > after {
+			versions = append(versions, version)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions, true
+}
+
+func deltaCommitName(version int64) string {
+	return fmt.Sprintf("%020d.json", version)
+}
+
+func applyDeltaCommit(data []byte, state *deltaState) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var action deltaAction
+		if err := json.Unmarshal(line, &action); err != nil {
+			return err
+		}
+		state.apply(action)
+	}
+	return scanner.Err()
+}
+
+func readDeltaCheckpoint(store *tableStore, checkpoint *deltaLastCheckpoint, state *deltaState) error {
+	names := []string{fmt.Sprintf("%020d.checkpoint.parquet", checkpoint.Version)}
+	if checkpoint.Parts > 1 {
+		names = names[:0]
+		for part := 1; part <= checkpoint.Parts; part++ {
+			names = append(names, fmt.Sprintf("%020d.checkpoint.%010d.%010d.parquet", checkpoint.Version, part, checkpoint.Parts))
+		}
+	}
+
+	for _, name := range names {
+		if err := readDeltaCheckpointFile(store, deltaLogDir+"/"+name, state); err != nil {
+			return fmt.Errorf("failed to read Delta checkpoint %d: %w", checkpoint.Version, err)
+		}
+	}
+	return nil
+}
+
+func readDeltaCheckpointFile(store *tableStore, name string, state *deltaState) error {
+	pf, _, closer, err := store.openParquet(name)
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	reader := parquet.NewGenericReader[deltaCheckpointRow](pf)
+	defer reader.Close()
+
+	rows := make([]deltaCheckpointRow, 256)
+	for {
+		n, err := reader.Read(rows)
+		for _, row := range rows[:n] {
+			state.apply(row.action())
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (row deltaCheckpointRow) action() deltaAction {
+	var action deltaAction
+	switch {
+	case row.Add != nil:
+		add := &deltaAdd{Path: row.Add.Path, Size: row.Add.Size, PartitionValues: make(map[string]*string)}
+		for key, value := range row.Add.PartitionValues {
+			value := value
+			add.PartitionValues[key] = &value
+		}
+		if row.Add.DeletionVector != nil && row.Add.DeletionVector.StorageType != "" {
+			add.DeletionVector = json.RawMessage(`{}`)
+		}
+		action.Add = add
+	case row.Remove != nil:
+		action.Remove = &deltaRemove{Path: row.Remove.Path}
+	case row.MetaData != nil:
+		action.MetaData = &deltaMetaData{SchemaString: row.MetaData.SchemaString, PartitionColumns: row.MetaData.PartitionColumns}
+	}
+	return action
+}
+
+// deltaSchemaField is a field of a Delta schema string. Nested types are
+// objects rather than type names.
+type deltaSchemaField struct {
+	Name     string                 `json:"name"`
+	Type     json.RawMessage        `json:"type"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+func deltaSnapshot(store *tableStore, state *deltaState) (*tableSnapshot, error) {
+	var schema struct {
+		Fields []deltaSchemaField `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(state.metaData.SchemaString), &schema); err != nil {
+		return nil, fmt.Errorf("failed to read Delta schema: %w", err)
+	}
+
+	snapshot := &tableSnapshot{
+		store:      store,
+		format:     FormatDelta,
+		version:    strconv.FormatInt(state.version, 10),
+		partitions: state.metaData.PartitionColumns,
+		physical:   make(map[string]string),
+	}
+
+	for _, field := range schema.Fields {
+		var typeName string
+		if json.Unmarshal(field.Type, &typeName) != nil {
+			snapshot.skipped = append(snapshot.skipped, field.Name)
+			continue
+		}
+		snapshot.columns = append(snapshot.columns, field.Name)
+		if physical, ok := field.Metadata["delta.columnMapping.physicalName"].(string); ok {
+			snapshot.physical[field.Name] = physical
+		}
+	}
+
+	paths := make([]string, 0, len(state.files))
+	for path := range state.files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	deletionVectors := 0
+	for _, path := range paths {
+		add := state.files[path]
+		snapshot.files = append(snapshot.files, tableFile{
+			path:       deltaFilePath(path),
+			size:       add.Size,
+			partitions: add.PartitionValues,
+		})
+		if len(add.DeletionVector) > 0 && string(add.DeletionVector) != "null" {
+			deletionVectors++
+		}
+	}
+
+	if deletionVectors > 0 {
+		return nil, fmt.Errorf("Delta table version %d has deletion vectors on %d data files, which are not supported: purge them with REORG TABLE ... APPLY (PURGE) first", state.version, deletionVectors)
+	}
+
+	snapshot.notes = append(snapshot.notes, fmt.Sprintf(
+		"Delta table version %d: %d active data files", state.version, len(snapshot.files)))
+
+	return snapshot, nil
+}
+
+// deltaFilePath decodes a path from the log, where relative paths are
+// percent-encoded URIs.
+func deltaFilePath(path string) string {
+	if remote.IsURL(path) || strings.HasPrefix(path, "file:") {
+		return path
+	}
+	if decoded, err := url.PathUnescape(path); err == nil {
+		return decoded
+	}
+	return path
+}
@@ -0,0 +1,243 @@
+package profiler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+type tableTestRow struct {
+	ID     int64   `parquet:"id"`
+	Name   *string `parquet:"name,optional"`
+	Amount float64 `parquet:"amount"`
+}
+
+func writeTableTestFile(t *testing.T, path string, first, count int) int64 {
+	t.Helper()
+
+	rows := make([]tableTestRow, count)
+	for i := range rows {
+		name := fmt.Sprintf("user%d", (first+i)%5)
+		rows[i] = tableTestRow{ID: int64(first + i), Name: &name, Amount: float64(first+i) * 2}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := parquet.WriteFile(path, rows); err != nil {
+		t.Fatalf("Failed to write Parquet file: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat file: %v", err)
+	}
+	return info.Size()
+}
+
+const deltaTestSchema = `{"type":"struct","fields":[` +
+	`{"name":"id","type":"long","nullable":false,"metadata":{}},` +
+	`{"name":"name","type":"string","nullable":true,"metadata":{}},` +
+	`{"name":"amount","type":"double","nullable":true,"metadata":{}},` +
+	`{"name":"address","type":{"type":"struct","fields":[]},"nullable":true,"metadata":{}},` +
+	`{"name":"country","type":"string","nullable":true,"metadata":{}}]}`
+
+func deltaLine(t *testing.T, action map[string]interface{}) string {
+	t.Helper()
+
+	data, err := json.Marshal(action)
+	if err != nil {
+		t.Fatalf("Failed to encode action: %v", err)
+	}
+	return string(data) + "\n"
+}
+
+func deltaAddAction(path, country string, size int64, extra map[string]interface{}) map[string]interface{} {
+	add := map[string]interface{}{
+		"path":            path,
+		"partitionValues": map[string]interface{}{"country": country},
+		"size":            size,
+		"dataChange":      true,
+	}
+	for key, value := range extra {
+		add[key] = value
+	}
+	return map[string]interface{}{"add": add}
+}
+
+// writeTestDeltaTable writes a table partitioned by country: version 0 adds
+// two files, version 1 replaces the first.
+func writeTestDeltaTable(t *testing.T) string {
+	t.Helper()
+
+	root := filepath.Join(t.TempDir(), "events")
+	sizeA := writeTableTestFile(t, filepath.Join(root, "country=US", "part-a.parquet"), 0, 10)
+	sizeB := writeTableTestFile(t, filepath.Join(root, "country=FR", "part b.parquet"), 10, 5)
+	sizeC := writeTableTestFile(t, filepath.Join(root, "country=US", "part-c.parquet"), 20, 7)
+
+	commit0 := deltaLine(t, map[string]interface{}{"protocol": map[string]interface{}{"minReaderVersion": 1, "minWriterVersion": 2}}) +
+		deltaLine(t, map[string]interface{}{"metaData": map[string]interface{}{
+			"id": "test", "format": map[string]interface{}{"provider": "parquet"},
+			"schemaString": deltaTestSchema, "partitionColumns": []string{"country"},
+		}}) +
+		deltaLine(t, deltaAddAction("country=US/part-a.parquet", "US", sizeA, nil)) +
+		deltaLine(t, deltaAddAction("country=FR/part%20b.parquet", "FR", sizeB, nil))
+
+	commit1 := deltaLine(t, map[string]interface{}{"remove": map[string]interface{}{"path": "country=US/part-a.parquet", "dataChange": true}}) +
+		deltaLine(t, deltaAddAction("country=US/part-c.parquet", "US", sizeC, nil))
+
+	writeDeltaCommit(t, root, 0, commit0)
+	writeDeltaCommit(t, root, 1, commit1)
+	return root
+}
+
+func writeDeltaCommit(t *testing.T, root string, version int64, content string) {
+	t.Helper()
+
+	dir := filepath.Join(root, deltaLogDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create log directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, deltaCommitName(version)), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write commit: %v", err)
+	}
+}
+
+func checkDeltaProfile(t *testing.T, profile *DatasetProfile, rows int, version string) {
+	t.Helper()
+
+	if profile.Format != "Delta" || profile.Filename != "events" {
+		t.Errorf("Expected a Delta profile of events, got %s of %s", profile.Format, profile.Filename)
+	}
+	if profile.RowCount != rows {
+		t.Errorf("Expected %d rows, got %d", rows, profile.RowCount)
+	}
+	if profile.ColumnCount != 4 {
+		t.Errorf("Expected 4 columns, got %d", profile.ColumnCount)
+	}
+
+	country := profile.Columns["country"]
+	if country == nil || country.MissingCount != 0 || country.UniqueCount != 2 {
+		t.Errorf("Expected partition column country with 2 values, got %+v", country)
+	}
+
+	notes := strings.Join(profile.Notes, " ")
+	for _, note := range []string{
+		"Delta table version " + version,
+		"Partition columns read from the table metadata: country",
+		"Skipped nested or repeated columns: address",
+	} {
+		if !strings.Contains(notes, note) {
+			t.Errorf("Expected note %q, got %v", note, profile.Notes)
+		}
+	}
+}
+
+func TestProfileDeltaTable(t *testing.T) {
+	root := writeTestDeltaTable(t)
+
+	profile, err := ProfileDataset(root)
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	checkDeltaProfile(t, profile, 12, "1: 2 active data files")
+
+	if id := profile.Columns["id"]; id.Min != 10.0 || id.Max != 26.0 {
+		t.Errorf("Expected ids 10 to 26 from the active files, got %v to %v", id.Min, id.Max)
+	}
+}
+
+func TestProfileDeltaCheckpoint(t *testing.T) {
+	root := writeTestDeltaTable(t)
+
+	// Checkpoint version 1, drop the commits it covers and add version 2
+	log := filepath.Join(root, deltaLogDir)
+	sizeB, _ := os.Stat(filepath.Join(root, "country=FR", "part b.parquet"))
+	sizeC, _ := os.Stat(filepath.Join(root, "country=US", "part-c.parquet"))
+	checkpoint := make([]deltaCheckpointRow, 3)
+	checkpoint[0].MetaData = &struct {
+		SchemaString     string   `parquet:"schemaString"`
+		PartitionColumns []string `parquet:"partitionColumns,list"`
+	}{deltaTestSchema, []string{"country"}}
+	for i, file := range []struct {
+		path, country string
+		size          int64
+	}{{"country=FR/part%20b.parquet", "FR", sizeB.Size()}, {"country=US/part-c.parquet", "US", sizeC.Size()}} {
+		checkpoint[i+1].Add = &struct {
+			Path            string            `parquet:"path"`
+			PartitionValues map[string]string `parquet:"partitionValues"`
+			Size            int64             `parquet:"size"`
+			DeletionVector  *struct {
+				StorageType string `parquet:"storageType"`
+			} `parquet:"deletionVector,optional"`
+		}{Path: file.path, PartitionValues: map[string]string{"country": file.country}, Size: file.size}
+	}
+	checkpointFile := filepath.Join(log, "00000000000000000001.checkpoint.parquet")
+	if err := parquet.WriteFile(checkpointFile, checkpoint); err != nil {
+		t.Fatalf("Failed to write checkpoint: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(log, "_last_checkpoint"), []byte(`{"version":1,"size":3}`), 0644); err != nil {
+		t.Fatalf("Failed to write _last_checkpoint: %v", err)
+	}
+	os.Remove(filepath.Join(log, deltaCommitName(0)))
+	os.Remove(filepath.Join(log, deltaCommitName(1)))
+
+	sizeD := writeTableTestFile(t, filepath.Join(root, "country=FR", "part-d.parquet"), 40, 3)
+	writeDeltaCommit(t, root, 2, deltaLine(t, deltaAddAction("country=FR/part-d.parquet", "FR", sizeD, nil)))
+
+	profile, err := ProfileDataset(root)
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	checkDeltaProfile(t, profile, 15, "2: 3 active data files")
+
+	// Deletion vectors in the checkpoint are found too
+	checkpoint[2].Add.DeletionVector = &struct {
+		StorageType string `parquet:"storageType"`
+	}{"u"}
+	if err := parquet.WriteFile(checkpointFile, checkpoint); err != nil {
+		t.Fatalf("Failed to write checkpoint: %v", err)
+	}
+	if _, err := ProfileDataset(root); err == nil || !strings.Contains(err.Error(), "deletion vectors on 1 data files") {
+		t.Errorf("Expected deletion vectors to be refused, got %v", err)
+	}
+}
+
+func TestProfileDeltaRemote(t *testing.T) {
+	root := writeTestDeltaTable(t)
+	server := httptest.NewServer(http.FileServer(http.Dir(filepath.Dir(root))))
+	defer server.Close()
+
+	profile, err := ProfileDatasetWithOptions(server.URL+"/events", Options{Format: FormatDelta})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	checkDeltaProfile(t, profile, 12, "1: 2 active data files")
+}
+
+func TestProfileDeltaErrors(t *testing.T) {
+	root := writeTestDeltaTable(t)
+	sizeD := writeTableTestFile(t, filepath.Join(root, "country=FR", "part-d.parquet"), 40, 3)
+	writeDeltaCommit(t, root, 2, deltaLine(t, deltaAddAction("country=FR/part-d.parquet", "FR", sizeD, map[string]interface{}{
+		"deletionVector": map[string]interface{}{"storageType": "u", "pathOrInlineDv": "ab", "sizeInBytes": 4, "cardinality": 1},
+	})))
+	if _, err := ProfileDataset(root); err == nil || !strings.Contains(err.Error(), "Delta table version 2 has deletion vectors on 1 data files") {
+		t.Errorf("Expected deletion vectors to be refused, got %v", err)
+	}
+
+	os.Remove(filepath.Join(root, deltaLogDir, deltaCommitName(0)))
+
+	if _, err := ProfileDataset(root); err == nil || !strings.Contains(err.Error(), "missing commit 0") {
+		t.Errorf("Expected a missing commit error, got %v", err)
+	}
+
+	if _, err := ProfileDatasetWithOptions(root, Options{Format: FormatDelta, SkipRows: 2}); err == nil || !strings.Contains(err.Error(), "--skip-rows is not supported for Delta") {
+		t.Errorf("Expected --skip-rows to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package profiler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// NoQuote turns off quoting: quote characters are kept as data.
+const NoQuote rune = -1
+
+// sniffDelimiters are the delimiters tried, in order of preference on a tie.
+var sniffDelimiters = []rune{',', '\t', ';', '|'}
+
+// sniffDelimiter picks the delimiter that splits the most leading lines
+// into the same number of fields, falling back to a comma.
+func sniffDelimiter(lines [][]byte, comment rune) rune {
+	delimiter, best := ',', 0
+	for _, candidate := range sniffDelimiters {
+		if _, freq := countMode(fieldCounts(lines, candidate, comment)); freq > best {
+			delimiter, best = candidate, freq
+		}
+	}
+	return delimiter
+}
+
+func describeDelimiter(delimiter rune) string {
+	switch delimiter {
+	case '\t':
+		return "tab"
+	case ' ':
+		return "space"
+	default:
+		return fmt.Sprintf("'%c'", delimiter)
+	}
+}
+
+func (o Options) validateDialect() error {
+	for _, option := range []struct {
+		name  string
+		value rune
+	}{{"delimiter", o.Delimiter}, {"comment", o.Comment}} {
+		if option.value == '"' || option.value == '\r' || option.value == '\n' || option.value < 0 {
+			return fmt.Errorf("invalid %s: %q", option.name, option.value)
+		}
+	}
+
+	if o.Delimiter != 0 && o.Delimiter == o.Comment {
+		return fmt.Errorf("delimiter and comment must differ")
+	}
+
+	if o.Quote != 0 && o.Quote != NoQuote {
+		if o.Quote > 0x7f || o.Quote == '\r' || o.Quote == '\n' {
+			return fmt.Errorf("invalid quote: %q (use a single ASCII character)", o.Quote)
+		}
+		if o.Quote == o.Delimiter || o.Quote == o.Comment {
+			return fmt.Errorf("quote must differ from the delimiter and comment")
+		}
+	}
+
+	return nil
+}
+
+// quoteSwapper lets encoding/csv, which only knows double quotes, read a
+// source quoted with another character: the two are swapped in the stream
+// and swapped back in every parsed field. NoQuote swaps double quotes with
+// a NUL byte, so they no longer open quoted fields.
+type quoteSwapper struct {
+	quote byte
+}
+
+func newQuoteSwapper(quote rune) *quoteSwapper {
+	switch quote {
+	case 0, '"':
+		return nil
+	case NoQuote:
+		return &quoteSwapper{quote: 0}
+	default:
+		return &quoteSwapper{quote: byte(quote)}
+	}
+}
+
+func (s *quoteSwapper) reader(r io.Reader) io.Reader {
+	return &quoteSwapReader{r: r, quote: s.quote}
+}
+
+func (s *quoteSwapper) record(record []string) {
+	for i, field := range record {
+		if strings.IndexByte(field, '"') >= 0 || strings.IndexByte(field, s.quote) >= 0 {
+			record[i] = string(swapQuote([]byte(field), s.quote))
+		}
+	}
+}
+
+type quoteSwapReader struct {
+	r     io.Reader
+	quote byte
+}
+
+func (q *quoteSwapReader) Read(p []byte) (int, error) {
+	n, err := q.r.Read(p)
+	swapQuote(p[:n], q.quote)
+	return n, err
+}
+
+func swapQuote(b []byte, quote byte) []byte {
+	for i, c := range b {
+		switch c {
+		case '"':
+			b[i] = quote
+		case quote:
+			b[i] = '"'
+		}
+	}
+	return b
+}
@@ -0,0 +1,117 @@
+package profiler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSniffDelimiter(t *testing.T) {
+	tests := []struct {
+		content string
+		want    rune
+	}{
+		{"a,b,c\n1,2,3\n4,5,6\n", ','},
+		{"a\tb\tc\n1\t2\t3\n", '\t'},
+		{"name;price;city\nAnn;1,50;Paris\nBob;2,75;Lyon\n", ';'},
+		{"id|label\n1|x\n2|y\n", '|'},
+		{"\"a;b\",c\n\"1;2\",3\n\"4;5\",6\n", ','},
+		{"single\nvalue\n", ','},
+	}
+
+	for _, tt := range tests {
+		lines := make([][]byte, 0)
+		for _, line := range strings.SplitAfter(tt.content, "\n") {
+			if line != "" {
+				lines = append(lines, []byte(line))
+			}
+		}
+		if got := sniffDelimiter(lines, 0); got != tt.want {
+			t.Errorf("sniffDelimiter(%q): expected %q, got %q", tt.content, tt.want, got)
+		}
+	}
+}
+
+func TestProfileSemicolonCSV(t *testing.T) {
+	path := writeTestFile(t, "data.csv", "name;price;city\nAnn;1,50;Paris\nBob;2,75;Lyon\nCy;3,00;Nice\n")
+
+	profile, err := ProfileDataset(path)
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+
+	if profile.ColumnCount != 3 || profile.Columns["city"] == nil {
+		t.Errorf("Expected 3 columns split on semicolons, got %d", profile.ColumnCount)
+	}
+	if notes := strings.Join(profile.Notes, " "); !strings.Contains(notes, "Detected ';' as the delimiter") {
+		t.Errorf("Expected a delimiter note, got %v", profile.Notes)
+	}
+}
+
+func TestProfileDialectOptions(t *testing.T) {
+	path := writeTestFile(t, "data.csv", "# exported 2024-01-31\nid|'name'|note\n1|'Smith| J'|say \"hi\"\n# page break\n2|'Doe'|x\n")
+
+	profile, err := ProfileDatasetWithOptions(path, Options{Delimiter: '|', Quote: '\'', Comment: '#'})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+
+	if profile.RowCount != 2 || profile.ColumnCount != 3 {
+		t.Fatalf("Expected 2 rows and 3 columns, got %d and %d", profile.RowCount, profile.ColumnCount)
+	}
+
+	name := profile.Columns["name"]
+	if name == nil {
+		t.Fatalf("Expected a name column, got %v", profile.Columns)
+	}
+	found := false
+	for _, value := range name.TopValues {
+		if value.Value == "Smith| J" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected the quoted value to keep its delimiter, got %v", name.TopValues)
+	}
+
+	note := profile.Columns["note"]
+	found = false
+	for _, value := range note.TopValues {
+		if value.Value == `say "hi"` {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected double quotes to be kept as data, got %v", note.TopValues)
+	}
+}
+
+func TestProfileNoQuote(t *testing.T) {
+	path := writeTestFile(t, "data.csv", "size,label\n\"12,\"big\n\"3,\"small\n")
+
+	profile, err := ProfileDatasetWithOptions(path, Options{Quote: NoQuote})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if profile.RowCount != 2 || profile.Columns["size"].TopValues[0].Value[0] != '"' {
+		t.Errorf("Expected quotes to be kept as data, got %v", profile.Columns["size"].TopValues)
+	}
+}
+
+func TestValidateDialect(t *testing.T) {
+	invalid := []Options{
+		{Delimiter: '"'},
+		{Delimiter: '\n'},
+		{Delimiter: ';', Comment: ';'},
+		{Quote: '|', Delimiter: '|'},
+		{Quote: 'é'},
+	}
+	for _, opts := range invalid {
+		if err := opts.validate(); err == nil {
+			t.Errorf("Expected %+v to be rejected", opts)
+		}
+	}
+
+	if err := (Options{Delimiter: ';', Quote: '\'', Comment: '#'}).validate(); err != nil {
+		t.Errorf("Expected a valid dialect, got %v", err)
+	}
+}
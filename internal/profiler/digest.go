@@ -0,0 +1,148 @@
+package profiler
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// digestAccumulator builds order-insensitive digests of a dataset. Row and
+// cell hashes are summed, so the same rows in any order (and the same columns
+// in any order) produce the same digest. Values are hashed in a canonical
+// spelling, so that a database table and a file exported from it match.
+type digestAccumulator struct {
+	header     []string
+	nameHashes []uint64
+	rowSum     uint64
+	columnSums []uint64
+}
+
+func newDigestAccumulator(header []string) *digestAccumulator {
+	d := &digestAccumulator{
+		header:     header,
+		nameHashes: make([]uint64, len(header)),
+		columnSums: make([]uint64, len(header)),
+	}
+
+	for i, name := range header {
+		d.nameHashes[i] = hashString(name)
+	}
+
+	return d
+}
+
+// addRecord folds record into the digests and returns its row hash.
+func (d *digestAccumulator) addRecord(record []string) uint64 {
+	var rowSum uint64
+
+	for i := range d.header {
+		value := ""
+		if i < len(record) {
+			value = record[i]
+		}
+
+		valueHash := hashString(digestValue(value))
+		d.columnSums[i] += mix64(valueHash)
+		rowSum += mix64(valueHash ^ d.nameHashes[i])
+	}
+
+	rowHash := mix64(rowSum)
+	d.rowSum += rowHash
+	return rowHash
+}
+
+// merge adds the sums of o, which covers other records with the same header.
+func (d *digestAccumulator) merge(o *digestAccumulator) {
+	d.rowSum += o.rowSum
+	for i := range d.columnSums {
+		d.columnSums[i] += o.columnSums[i]
+	}
+}
+
+func (d *digestAccumulator) apply(profile *DatasetProfile) {
+	profile.ContentDigest = formatDigest(d.rowSum)
+
+	for i, name := range d.header {
+		if col, ok := profile.Columns[name]; ok {
+			col.Digest = formatDigest(d.columnSums[i])
+		}
+	}
+}
+
+// digestTimeLayouts are the timestamp spellings of drivers and exports:
+// RFC 3339, with a space instead of the T, with PostgreSQL's +00 offsets,
+// and without a zone, read as UTC. Fractional seconds are always accepted.
+var digestTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05Z07",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// digestValue spells a value the same way whichever source it came from:
+// booleans as true or false, decimals without trailing zeros, and
+// timestamps in UTC as RFC 3339, or as a date at midnight. Other values are
+// hashed as they are.
+func digestValue(s string) string {
+	if s == "" {
+		return s
+	}
+
+	switch strings.ToLower(s) {
+	case "true", "t":
+		return "true"
+	case "false", "f":
+		return "false"
+	}
+
+	c := s[0]
+	if (c < '0' || c > '9') && c != '-' && c != '+' && c != '.' {
+		return s
+	}
+
+	if len(s) >= 10 && s[4] == '-' && s[7] == '-' {
+		for _, layout := range digestTimeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				t = t.UTC()
+				if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+					return t.Format("2006-01-02")
+				}
+				return t.Format(time.RFC3339Nano)
+			}
+		}
+		return s
+	}
+
+	if strings.ContainsAny(s, ".eE") {
+		if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) {
+			return strconv.FormatFloat(f, 'f', -1, 64)
+		}
+	}
+	return s
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// mix64 is the splitmix64 finalizer; it keeps summed hashes from cancelling
+// out in structured ways.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func formatDigest(sum uint64) string {
+	return fmt.Sprintf("%016x", sum)
+}
@@ -0,0 +1,77 @@
+package profiler
+
+import "testing"
+
+func TestDigestOrderInsensitive(t *testing.T) {
+	first := newDigestAccumulator([]string{"a", "b"})
+	first.addRecord([]string{"1", "x"})
+	first.addRecord([]string{"2", "y"})
+
+	second := newDigestAccumulator([]string{"b", "a"})
+	second.addRecord([]string{"y", "2"})
+	second.addRecord([]string{"x", "1"})
+
+	if first.rowSum != second.rowSum {
+		t.Errorf("Expected equal digests for reordered rows and columns, got %x and %x", first.rowSum, second.rowSum)
+	}
+
+	if first.columnSums[0] != second.columnSums[1] {
+		t.Error("Expected column 'a' digest to match regardless of position")
+	}
+}
+
+func TestDigestCanonicalValues(t *testing.T) {
+	// A PostgreSQL table read through its driver, and exported with COPY
+	table := newDigestAccumulator([]string{"paid", "amount", "created", "day"})
+	table.addRecord([]string{"true", "12.5", "2024-03-01T09:30:00.25Z", "2024-03-01T00:00:00Z"})
+	table.addRecord([]string{"false", "7", "2024-03-02T10:00:00Z", "2024-03-02T00:00:00Z"})
+
+	export := newDigestAccumulator([]string{"paid", "amount", "created", "day"})
+	export.addRecord([]string{"t", "12.50", "2024-03-01 10:30:00.25+01", "2024-03-01"})
+	export.addRecord([]string{"f", "7.0", "2024-03-02 10:00:00", "2024-03-02"})
+
+	if table.rowSum != export.rowSum {
+		t.Errorf("Expected a table and its export to have equal digests, got %x and %x", table.rowSum, export.rowSum)
+	}
+
+	tests := map[string]string{
+		"TRUE":        "true",
+		"1e3":         "1000",
+		"-0.50":       "-0.5",
+		"007":         "007",
+		"1.2.3":       "1.2.3",
+		"2024-13-01":  "2024-13-01",
+		"fast":        "fast",
+		"12345678901": "12345678901",
+	}
+	for value, want := range tests {
+		if got := digestValue(value); got != want {
+			t.Errorf("%q: expected %q, got %q", value, want, got)
+		}
+	}
+}
+
+func TestDigestDetectsChanges(t *testing.T) {
+	first := newDigestAccumulator([]string{"a", "b"})
+	first.addRecord([]string{"1", "x"})
+	first.addRecord([]string{"2", "y"})
+
+	second := newDigestAccumulator([]string{"a", "b"})
+	second.addRecord([]string{"1", "y"})
+	second.addRecord([]string{"2", "x"})
+
+	if first.rowSum == second.rowSum {
+		t.Error("Expected different dataset digests when values move between rows")
+	}
+
+	if first.columnSums[1] != second.columnSums[1] {
+		t.Error("Expected column digest to ignore row order")
+	}
+
+	profile := &DatasetProfile{Columns: map[string]*ColumnProfile{"a": {Name: "a"}, "b": {Name: "b"}}}
+	first.apply(profile)
+
+	if len(profile.ContentDigest) != 16 || profile.Columns["a"].Digest == "" {
+		t.Errorf("Expected digests to be applied, got '%s'", profile.ContentDigest)
+	}
+}
@@ -0,0 +1,807 @@
+package profiler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Profiling engines.
+const (
+	EngineGo     = "go"     // reads every row in Go; the default
+	EngineDuckDB = "duckdb" // leaves counts, distincts, quantiles and group-bys to DuckDB, in builds with the duckdb tag
+)
+
+// errNoDuckDB rejects --engine duckdb in builds without DuckDB.
+var errNoDuckDB = errors.New("this build of datasleuth has no DuckDB engine: build it with -tags duckdb to use --engine duckdb")
+
+// duckDBTable holds the rows being profiled. CSV, TSV and JSONL files are
+// loaded into it once, so that each query scans DuckDB's columnar copy
+// rather than parsing the text again; Parquet is read where it is.
+const duckDBTable = "data"
+
+func (o Options) validateEngine() error {
+	switch o.Engine {
+	case "", EngineGo:
+		return nil
+	case EngineDuckDB:
+		if !duckDBBuilt {
+			return errNoDuckDB
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported engine: %s (use go or duckdb)", o.Engine)
+	}
+}
+
+// duckDBReason is why the DuckDB engine cannot profile the source with
+// opts, as far as can be told without reading it, or empty when it can.
+func (p *Plan) duckDBReason(opts Options) string {
+	switch {
+	case p.Kind != "file" || !slices.Contains([]string{FormatCSV, FormatTSV, FormatJSONL, "parquet"}, p.Format):
+		return "--engine duckdb only applies to local CSV, TSV, JSONL and Parquet files"
+	case p.Compression != "":
+		return fmt.Sprintf("--engine duckdb does not apply to %s-compressed input", p.Compression)
+	case opts.sampling():
+		return "--engine duckdb does not combine with --sample"
+	case opts.MaxBytes > 0:
+		return "--engine duckdb does not combine with --range"
+	case opts.Comment != 0:
+		return "--engine duckdb does not combine with --comment"
+	case opts.Encoding != "":
+		return "--engine duckdb does not combine with --encoding"
+	case opts.SkipFooter > 0:
+		return "--engine duckdb does not combine with --skip-footer"
+	case opts.SkipBadRows:
+		return "--engine duckdb does not combine with --skip-bad-rows"
+	case opts.NumberFormat != "":
+		return "--engine duckdb does not combine with --number-format"
+	case opts.Locale != "":
+		return "--engine duckdb does not combine with --locale"
+	case len(opts.DateFormats) > 0:
+		return "--engine duckdb does not read the date formats of the config"
+	case len(opts.NullValues) > 0:
+		return "--engine duckdb does not combine with --null-values"
+	case opts.Preview > 0:
+		return "--engine duckdb does not combine with --preview"
+	case opts.WeightColumn != "":
+		return "--engine duckdb does not combine with --weight-column"
+	case opts.TimeColumn != "":
+		return "--engine duckdb does not combine with --time-column"
+	case opts.Robust:
+		return "--engine duckdb does not combine with --robust"
+	case opts.MinHash > 0:
+		return "--engine duckdb does not compute MinHash signatures"
+	case opts.Rows != nil:
+		return "rows are passed on one at a time"
+	}
+	return ""
+}
+
+// profileDuckDB profiles a local file with DuckDB. When the DuckDB engine
+// cannot profile it, it returns no profile and the reason, for the Go
+// engine to profile it instead. Files of fewer than opts.ExactRows rows
+// are left to the Go engine for their exact listings.
+func profileDuckDB(filePath string, opts Options) (*DatasetProfile, string, error) {
+	startTime := time.Now()
+
+	plan := &Plan{Source: filePath}
+	plan.resolveSource(opts)
+	if why := plan.duckDBReason(opts); why != "" {
+		return nil, why, nil
+	}
+
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get file stats: %w", err)
+	}
+
+	db, err := openDuckDB()
+	if err != nil {
+		return nil, "", err
+	}
+	defer db.Close()
+	// Settings and the loaded table are kept by the connection
+	db.SetMaxOpenConns(1)
+
+	spill, err := os.MkdirTemp("", "datasleuth-duckdb-")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create a spill directory for DuckDB: %w", err)
+	}
+	defer os.RemoveAll(spill)
+
+	d := &duckDB{db: db, ctx: opts.context()}
+	d.exec("SET temp_directory = " + duckDBString(spill))
+	d.exec("SET preserve_insertion_order = false")
+	var version string
+	d.query("SELECT library_version FROM pragma_version()", func(values []any) { version = duckDBText(values[0]) })
+	if d.err != nil {
+		return nil, "", d.err
+	}
+
+	if err := d.load(filePath, plan.Format, opts); err != nil {
+		if d.ctx.Err() != nil {
+			return nil, "", err
+		}
+		return nil, fmt.Sprintf("DuckDB could not read the file: %v", errors.Unwrap(err)), nil
+	}
+
+	var columns []*duckDBColumn
+	d.query("DESCRIBE "+duckDBTable, func(values []any) {
+		columns = append(columns, newDuckDBColumn(duckDBText(values[0]), duckDBText(values[1])))
+	})
+	rows := d.aggregate(columns, opts)
+	if d.err != nil {
+		return nil, "", d.err
+	}
+	if rows < opts.ExactRows {
+		return nil, fmt.Sprintf("fewer than %d rows are profiled in exact mode", opts.ExactRows), nil
+	}
+
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.name
+	}
+	profile := newDatasetProfile(filepath.Base(filePath), fileInfo.Size(), duckDBFormatName(plan.Format), header)
+	profile.RowCount = rows
+	profile.HistogramBinning = opts.histogramBinning()
+	opts.Outliers.apply(&profile.Thresholds)
+
+	if err := d.duplicates(profile, header, opts); err != nil {
+		return nil, "", err
+	}
+
+	source := opts.tracker(profile.Filename).source
+	for _, c := range columns {
+		col := profile.Columns[c.name]
+		d.finishColumn(col, c, profile, opts)
+		if d.err != nil {
+			return nil, "", d.err
+		}
+		profile.MissingCells += col.MissingCount
+		publishColumnDone(source, c.name)
+	}
+
+	collectDatasetQualityIssues(profile)
+	profile.Notes = append(profile.Notes, fmt.Sprintf(
+		"Profiled with DuckDB %s: column types as DuckDB reads them; correlations, redundant columns, time gaps, parse errors and digests not computed", version))
+	profile.ProcessingTime = time.Since(startTime)
+
+	return profile, "", nil
+}
+
+func duckDBFormatName(format string) string {
+	switch format {
+	case FormatTSV:
+		return "TSV"
+	case FormatJSONL:
+		return "JSONL"
+	case "parquet":
+		return "Parquet"
+	default:
+		return "CSV"
+	}
+}
+
+// duckDB runs the queries of a profile. The first error is kept and later
+// queries are skipped, so that a profile is put together from straight-line
+// queries checked once.
+type duckDB struct {
+	db  *sql.DB
+	ctx context.Context
+	err error
+}
+
+func (d *duckDB) exec(query string) {
+	if d.err != nil {
+		return
+	}
+	if _, err := d.db.ExecContext(d.ctx, query); err != nil {
+		d.err = d.fail(err)
+	}
+}
+
+// query runs query and calls row with the values of each row it returns.
+func (d *duckDB) query(query string, row func(values []any)) {
+	if d.err != nil {
+		return
+	}
+	rows, err := d.db.QueryContext(d.ctx, query)
+	if err != nil {
+		d.err = d.fail(err)
+		return
+	}
+	defer rows.Close()
+
+	names, err := rows.Columns()
+	if err != nil {
+		d.err = d.fail(err)
+		return
+	}
+	values := make([]any, len(names))
+	pointers := make([]any, len(names))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			d.err = d.fail(err)
+			return
+		}
+		row(values)
+	}
+	if err := rows.Err(); err != nil {
+		d.err = d.fail(err)
+	}
+}
+
+func (d *duckDB) fail(err error) error {
+	if stop := d.ctx.Err(); stop != nil {
+		return fmt.Errorf("DuckDB stopped: %w", stop)
+	}
+	return fmt.Errorf("DuckDB query failed: %w", err)
+}
+
+// load makes the file available as duckDBTable.
+func (d *duckDB) load(filePath, format string, opts Options) error {
+	path := duckDBString(filePath)
+	switch format {
+	case "parquet":
+		d.exec(fmt.Sprintf("CREATE VIEW %s AS SELECT * FROM read_parquet(%s)", duckDBTable, path))
+	case FormatJSONL:
+		d.exec(fmt.Sprintf("CREATE TABLE %s AS SELECT * FROM read_json(%s, format = 'newline_delimited')", duckDBTable, path))
+	default:
+		d.exec(fmt.Sprintf("CREATE TABLE %s AS SELECT * FROM read_csv(%s)", duckDBTable, strings.Join(duckDBCSVArgs(path, format, opts), ", ")))
+	}
+	return d.err
+}
+
+// duckDBCSVArgs are the arguments of read_csv for path in the dialect of
+// opts, leaving DuckDB to sniff the delimiter when opts does not set it.
+func duckDBCSVArgs(path, format string, opts Options) []string {
+	args := []string{path, "header = true"}
+
+	delimiter := opts.Delimiter
+	if delimiter == 0 && format == FormatTSV {
+		delimiter = '\t'
+	}
+	if delimiter != 0 {
+		args = append(args, "delim = "+duckDBString(string(delimiter)))
+	}
+
+	switch opts.Quote {
+	case NoQuote:
+		args = append(args, "quote = ''")
+	case 0:
+		args = append(args, `quote = '"'`)
+	default:
+		args = append(args, "quote = "+duckDBString(string(opts.Quote)))
+	}
+
+	if opts.SkipRows > 0 {
+		args = append(args, fmt.Sprintf("skip = %d", opts.SkipRows))
+	}
+	return args
+}
+
+// duckDBColumn is a column of duckDBTable and the results of the
+// aggregates over it.
+type duckDBColumn struct {
+	name     string
+	sqlType  string
+	dataType string // integer, float, datetime or string, as the Go engine names them
+	ident    string // quoted name
+
+	count          int
+	min, max, mean float64 // of numbers, and of datetimes in Unix seconds
+	m2, m3, m4     float64 // central moments, averaged over the values
+	quantiles      map[float64]float64
+	minLength      int
+	maxLength      int
+	totalLength    int64
+	whitespace     int
+}
+
+func newDuckDBColumn(name, sqlType string) *duckDBColumn {
+	return &duckDBColumn{
+		name:      name,
+		sqlType:   sqlType,
+		dataType:  duckDBDataType(sqlType),
+		ident:     duckDBIdentifier(name),
+		quantiles: make(map[float64]float64),
+	}
+}
+
+// duckDBDataType maps a DuckDB type to the data type the Go engine would
+// infer for its values.
+func duckDBDataType(sqlType string) string {
+	switch t := strings.ToUpper(sqlType); {
+	case slices.Contains([]string{"TINYINT", "SMALLINT", "INTEGER", "BIGINT", "HUGEINT",
+		"UTINYINT", "USMALLINT", "UINTEGER", "UBIGINT", "UHUGEINT"}, t):
+		return "integer"
+	case t == "FLOAT", t == "DOUBLE", strings.HasPrefix(t, "DECIMAL"):
+		return "float"
+	case t == "DATE", strings.HasPrefix(t, "TIMESTAMP"):
+		return "datetime"
+	default:
+		return "string"
+	}
+}
+
+// value is the column with empty text as NULL, as the Go engine counts it
+// missing.
+func (c *duckDBColumn) value() string {
+	if c.sqlType == "VARCHAR" {
+		return "NULLIF(" + c.ident + ", '')"
+	}
+	return c.ident
+}
+
+func (c *duckDBColumn) numeric() bool {
+	return c.dataType == "integer" || c.dataType == "float"
+}
+
+// number is the column as a DOUBLE, NULL where the value is NaN or
+// infinite, which the Go engine leaves out of the statistics too.
+func (c *duckDBColumn) number() string {
+	x := "CAST(" + c.ident + " AS DOUBLE)"
+	if c.dataType == "integer" {
+		return x
+	}
+	return fmt.Sprintf("CASE WHEN isfinite(%[1]s) THEN %[1]s END", x)
+}
+
+// seconds is the column of datetimes in Unix seconds.
+func (c *duckDBColumn) seconds() string {
+	return "epoch(CAST(" + c.ident + " AS TIMESTAMP))"
+}
+
+// duckDBScan is a query of aggregates over duckDBTable, each with a function
+// taking its result.
+type duckDBScan struct {
+	exprs []string
+	sinks []func(any)
+}
+
+func (s *duckDBScan) add(expr string, sink func(any)) {
+	s.exprs = append(s.exprs, expr)
+	s.sinks = append(s.sinks, sink)
+}
+
+func (s *duckDBScan) sql() string {
+	return "SELECT " + strings.Join(s.exprs, ", ") + " FROM " + duckDBTable
+}
+
+func (s *duckDBScan) scan(values []any) {
+	for i, sink := range s.sinks {
+		sink(values[i])
+	}
+}
+
+// aggregate counts the rows and the values of each column, and computes
+// the range, mean, quantiles and moments of numbers, the range of datetimes
+// and the lengths of text in two scans. It returns the row count.
+func (d *duckDB) aggregate(columns []*duckDBColumn, opts Options) int {
+	ranks := duckDBQuantiles(opts)
+
+	rows := 0
+	scan := &duckDBScan{}
+	scan.add("count(*)", func(v any) { rows = duckDBInt(v) })
+	for _, c := range columns {
+		scan.add("count("+c.value()+")", func(v any) { c.count = duckDBInt(v) })
+		switch {
+		case c.numeric():
+			x := c.number()
+			scan.add("min("+x+")", func(v any) { c.min = duckDBFloat(v) })
+			scan.add("max("+x+")", func(v any) { c.max = duckDBFloat(v) })
+			scan.add("avg("+x+")", func(v any) { c.mean = duckDBFloat(v) })
+			scan.add(fmt.Sprintf("quantile_cont(%s, [%s])", x, duckDBNumbers(ranks)), func(v any) {
+				values, _ := v.([]any)
+				for i, q := range ranks {
+					if i < len(values) {
+						c.quantiles[q] = duckDBFloat(values[i])
+					}
+				}
+			})
+		case c.dataType == "datetime":
+			scan.add("min("+c.seconds()+")", func(v any) { c.min = duckDBFloat(v) })
+			scan.add("max("+c.seconds()+")", func(v any) { c.max = duckDBFloat(v) })
+		case c.sqlType == "VARCHAR":
+			scan.add("min(length("+c.value()+"))", func(v any) { c.minLength = duckDBInt(v) })
+			scan.add("max(length("+c.value()+"))", func(v any) { c.maxLength = duckDBInt(v) })
+			scan.add("CAST(sum(length("+c.value()+")) AS BIGINT)", func(v any) { c.totalLength = int64(duckDBInt(v)) })
+			scan.add("count(*) FILTER (WHERE trim("+c.value()+") = '')", func(v any) { c.whitespace = duckDBInt(v) })
+		}
+	}
+	d.query(scan.sql(), scan.scan)
+
+	// Central moments need the mean first
+	moments := &duckDBScan{}
+	for _, c := range columns {
+		if !c.numeric() || c.count == 0 {
+			continue
+		}
+		deviation := fmt.Sprintf("(%s - %s)", c.number(), duckDBNumber(c.mean))
+		moments.add("avg(power("+deviation+", 2))", func(v any) { c.m2 = duckDBFloat(v) })
+		moments.add("avg(power("+deviation+", 3))", func(v any) { c.m3 = duckDBFloat(v) })
+		moments.add("avg(power("+deviation+", 4))", func(v any) { c.m4 = duckDBFloat(v) })
+	}
+	if len(moments.exprs) > 0 {
+		d.query(moments.sql(), moments.scan)
+	}
+
+	for _, c := range columns {
+		if c.count == 0 {
+			c.dataType = "unknown"
+		}
+	}
+	return rows
+}
+
+// duckDBQuantiles are the quantiles computed with the first scan: those of
+// the percentiles, the median and, for equal-frequency histograms, their
+// bucket bounds. Others are asked for when needed.
+func duckDBQuantiles(opts Options) []float64 {
+	ranks := []float64{0.5}
+	for _, rank := range PercentileRanks {
+		ranks = append(ranks, float64(rank)/100)
+	}
+	if spec := opts.histogram(); spec.binning == HistogramEqualFrequency || spec.binning == HistogramAuto {
+		for i := 1; i < spec.buckets; i++ {
+			ranks = append(ranks, float64(i)/float64(spec.buckets))
+		}
+	}
+	slices.Sort(ranks)
+	return slices.Compact(ranks)
+}
+
+// quantile returns the quantile function of the numbers of c.
+func (d *duckDB) quantile(c *duckDBColumn) func(q float64) float64 {
+	return func(q float64) float64 {
+		if v, ok := c.quantiles[q]; ok {
+			return v
+		}
+		var v float64
+		d.query(fmt.Sprintf("SELECT quantile_cont(%s, %s) FROM %s", c.number(), duckDBNumber(q), duckDBTable),
+			func(values []any) { v = duckDBFloat(values[0]) })
+		c.quantiles[q] = v
+		return v
+	}
+}
+
+// duplicates counts the rows repeating another, or its unique key, and
+// lists the most repeated keys.
+func (d *duckDB) duplicates(profile *DatasetProfile, header []string, opts Options) error {
+	if len(opts.UniqueKey) == 0 {
+		d.query("SELECT count(*) FROM (SELECT DISTINCT * FROM "+duckDBTable+")", func(values []any) {
+			profile.DuplicateRows = profile.RowCount - duckDBInt(values[0])
+		})
+		return d.err
+	}
+
+	if _, err := keyIndexes(header, opts.UniqueKey); err != nil {
+		return err
+	}
+	keys := make([]string, len(opts.UniqueKey))
+	texts := make([]string, len(opts.UniqueKey))
+	order := make([]string, len(opts.UniqueKey))
+	for i, name := range opts.UniqueKey {
+		keys[i] = duckDBIdentifier(name)
+		texts[i] = "CAST(" + keys[i] + " AS VARCHAR)"
+		order[i] = strconv.Itoa(i + 1)
+	}
+	d.query(fmt.Sprintf("SELECT count(*) FROM (SELECT DISTINCT %s FROM %s)", strings.Join(keys, ", "), duckDBTable), func(values []any) {
+		profile.DuplicateRows = profile.RowCount - duckDBInt(values[0])
+	})
+
+	profile.UniqueKey = opts.UniqueKey
+	profile.DuplicateKeys = make([]DuplicateKey, 0)
+	d.query(fmt.Sprintf("SELECT %s, count(*) AS n FROM %s GROUP BY ALL HAVING n > 1 ORDER BY n DESC, %s LIMIT %d",
+		strings.Join(texts, ", "), duckDBTable, strings.Join(order, ", "), maxDuplicateKeys), func(values []any) {
+		key := DuplicateKey{Values: make([]string, len(keys)), Rows: duckDBInt(values[len(keys)])}
+		for i := range keys {
+			key.Values[i] = duckDBText(values[i])
+		}
+		profile.DuplicateKeys = append(profile.DuplicateKeys, key)
+	})
+	return d.err
+}
+
+// finishColumn fills in col from the aggregates of c and the per-column
+// queries: value counts, examples and the histograms and outliers.
+func (d *duckDB) finishColumn(col *ColumnProfile, c *duckDBColumn, profile *DatasetProfile, opts Options) {
+	col.DataType = c.dataType
+	col.Count = c.count
+	col.MissingCount = profile.RowCount - c.count
+	col.IsNumeric = c.numeric() && c.count > 0
+	col.IsDateTime = c.dataType == "datetime"
+	col.Nullability = inferNullability(col.MissingCount, profile.RowCount, nil, profile.Thresholds)
+
+	// DuckDB has already cast every value of a typed column
+	numbers, dates := 0, 0
+	if col.IsNumeric {
+		numbers = c.count
+	}
+	if col.IsDateTime {
+		dates = c.count
+	}
+	col.Conversion = conversion(col.DataType, col.Count, numbers, 0, dates)
+
+	d.valueCounts(col, c, opts.topValues())
+	col.IsCategorical = profile.Thresholds.isCategorical(col.UniqueCount, profile.RowCount)
+	col.IsUnique = col.UniqueCount == col.Count
+
+	if opts.redacted(c.name) {
+		col.ExamplesRedacted = true
+	} else if opts.Examples > 0 {
+		d.query(fmt.Sprintf("SELECT value FROM (SELECT CAST(%[1]s AS VARCHAR) AS value FROM %[2]s WHERE %[1]s IS NOT NULL) USING SAMPLE reservoir(%[3]d ROWS)",
+			c.value(), duckDBTable, opts.Examples), func(values []any) {
+			col.Examples = append(col.Examples, duckDBText(values[0]))
+		})
+	}
+
+	switch {
+	case col.IsNumeric:
+		d.finishNumeric(col, c, opts, profile.Thresholds)
+	case col.IsDateTime:
+		d.finishDateTime(col, c)
+	case c.sqlType == "VARCHAR" && c.count > 0:
+		d.textStats(c).apply(col, nil)
+	}
+
+	detectQualityIssues(col, profile.RowCount)
+}
+
+// valueCounts lists the most and least frequent values of c, and counts
+// its distinct values and the values seen once, with a single group-by.
+func (d *duckDB) valueCounts(col *ColumnProfile, c *duckDBColumn, limit int) {
+	type rankedValue struct {
+		ValueCount
+		rank int
+	}
+	var top, bottom []rankedValue
+	singletons := 0
+	d.query(fmt.Sprintf(`WITH counts AS (SELECT CAST(%[1]s AS VARCHAR) AS value, count(*) AS n FROM %[2]s WHERE %[1]s IS NOT NULL GROUP BY 1)
+SELECT value, n, row_number() OVER (ORDER BY n DESC, value) AS top, row_number() OVER (ORDER BY n, value) AS bottom,
+	count(*) OVER () AS distinct_values, count(*) FILTER (WHERE n = 1) OVER () AS singletons
+FROM counts QUALIFY top <= %[3]d OR bottom <= %[3]d`, c.value(), duckDBTable, limit), func(values []any) {
+		value := ValueCount{Value: duckDBText(values[0]), Count: duckDBInt(values[1])}
+		if rank := duckDBInt(values[2]); rank <= limit {
+			top = append(top, rankedValue{value, rank})
+		}
+		if rank := duckDBInt(values[3]); rank <= limit {
+			bottom = append(bottom, rankedValue{value, rank})
+		}
+		col.UniqueCount = duckDBInt(values[4])
+		singletons = duckDBInt(values[5])
+	})
+
+	byRank := func(a, b rankedValue) int { return a.rank - b.rank }
+	slices.SortFunc(top, byRank)
+	slices.SortFunc(bottom, byRank)
+	col.TopValues = make([]ValueCount, len(top))
+	for i, v := range top {
+		col.TopValues[i] = v.ValueCount
+	}
+	if col.UniqueCount > len(col.TopValues) {
+		col.BottomValues = make([]ValueCount, len(bottom))
+		for i, v := range bottom {
+			col.BottomValues[i] = v.ValueCount
+		}
+	}
+	if col.UniqueCount > 0 {
+		col.Rare = &RareValues{
+			Singletons: singletons,
+			Percent:    float64(singletons) / float64(col.UniqueCount) * 100,
+		}
+	}
+}
+
+// textStats computes the lengths, casings and patterns of a text column,
+// for the Go engine's textStats to describe.
+func (d *duckDB) textStats(c *duckDBColumn) *textStats {
+	s := newTextStats()
+	s.count, s.minLength, s.maxLength, s.total, s.whitespace = c.count, c.minLength, c.maxLength, c.totalLength, c.whitespace
+
+	d.query(fmt.Sprintf("SELECT casing, count(*) FROM (SELECT %s AS casing FROM %s WHERE %s IS NOT NULL) WHERE casing IS NOT NULL GROUP BY 1",
+		duckDBCasing(c.value()), duckDBTable, c.value()), func(values []any) {
+		s.casings[duckDBText(values[0])] = duckDBInt(values[1])
+	})
+	// The most common patterns, as many as textStats lists
+	d.query(fmt.Sprintf("SELECT pattern, count(*) AS n FROM (SELECT %s AS pattern FROM %s WHERE %s IS NOT NULL) GROUP BY 1 ORDER BY n DESC, pattern LIMIT 5",
+		duckDBPattern(c.value()), duckDBTable, c.value()), func(values []any) {
+		s.patterns[duckDBText(values[0])] = duckDBInt(values[1])
+	})
+	return s
+}
+
+// duckDBCasing classifies the letters of x as valueCasing does, NULL when
+// it has none.
+func duckDBCasing(x string) string {
+	return fmt.Sprintf(`CASE WHEN NOT regexp_matches(%[1]s, '[\p{Lu}\p{Ll}]') THEN NULL
+	WHEN NOT regexp_matches(%[1]s, '\p{Lu}') THEN '%[2]s'
+	WHEN NOT regexp_matches(%[1]s, '\p{Ll}') THEN '%[3]s'
+	WHEN regexp_matches(%[1]s, '(^|[^\pL\p{Nd}])\p{Ll}|[\pL\p{Nd}]\p{Lu}') THEN '%[4]s'
+	ELSE '%[5]s' END`, x, CasingLower, CasingUpper, CasingMixed, CasingTitle)
+}
+
+// duckDBPattern maps the letters of x to A, digits to 9 and whitespace to a
+// space as textPattern does.
+func duckDBPattern(x string) string {
+	return fmt.Sprintf(`regexp_replace(regexp_replace(regexp_replace(left(%[1]s, %[2]d), '\pL', 'A', 'g'), '\p{Nd}', '9', 'g'), '[\s\p{Z}]', ' ', 'g')
+	|| CASE WHEN length(%[1]s) > %[2]d THEN '…' ELSE '' END`, x, maxPatternLength)
+}
+
+func (d *duckDB) finishNumeric(col *ColumnProfile, c *duckDBColumn, opts Options, thresholds Thresholds) {
+	col.Min, col.Max, col.Mean = c.min, c.max, c.mean
+	col.StdDev = math.Sqrt(c.m2)
+	if c.m2 > 0 {
+		col.Skewness = c.m3 / math.Pow(c.m2, 1.5)
+		col.Kurtosis = c.m4/(c.m2*c.m2) - 3
+	}
+
+	quantile := d.quantile(c)
+	col.Median = quantile(0.5)
+	col.Percentiles = percentiles(quantile)
+	if len(col.TopValues) > 0 && col.TopValues[0].Count > 1 {
+		if mode, err := strconv.ParseFloat(col.TopValues[0].Value, 64); err == nil {
+			col.Mode = mode
+		}
+	}
+
+	hist := opts.histogram()
+	binning, note := hist.binningFor(c.min, c.max, col.Skewness, thresholds)
+	col.HistogramBinning = binning
+	if note != "" {
+		col.Notes = append(col.Notes, note)
+	}
+	stats := &numericStats{count: c.count, min: c.min, max: c.max}
+	buckets := stats.bucketBounds(binning, hist.buckets, quantile)
+
+	mad := func(median float64) float64 {
+		var v float64
+		d.query(fmt.Sprintf("SELECT quantile_cont(abs(%s - %s), 0.5) FROM %s", c.number(), duckDBNumber(median), duckDBTable),
+			func(values []any) { v = duckDBFloat(values[0]) })
+		return v
+	}
+	outliers, ok := outlierBounds(thresholds, c.mean, col.StdDev, quantile, mad)
+	if !ok {
+		outliers = nil
+	}
+	col.HistogramBuckets = d.histogram(c.number(), buckets, outliers)
+	if outliers != nil {
+		if outliers.Count > 0 {
+			x, lower, upper := c.number(), duckDBNumber(outliers.Lower), duckDBNumber(outliers.Upper)
+			d.query(fmt.Sprintf("SELECT %[1]s FROM %[2]s WHERE %[1]s < %[3]s OR %[1]s > %[4]s ORDER BY greatest(%[3]s - %[1]s, %[1]s - %[4]s) DESC LIMIT %[5]d",
+				x, duckDBTable, lower, upper, outlierExamples), func(values []any) {
+				outliers.Examples = append(outliers.Examples, duckDBFloat(values[0]))
+			})
+		}
+		col.Outliers = outliers
+	}
+
+	flagNumericIssues(col, c.count, thresholds)
+}
+
+func (d *duckDB) finishDateTime(col *ColumnProfile, c *duckDBColumn) {
+	precision := precisionSecond
+	if strings.EqualFold(c.sqlType, "DATE") {
+		precision = precisionDay
+	}
+	stats := &DateTimeStats{
+		Min:         time.Unix(int64(c.min), 0).UTC(),
+		Max:         time.Unix(int64(c.max), 0).UTC(),
+		Precision:   timePrecisions[precision],
+		Granularity: granularity(precision, 1),
+	}
+
+	seconds := &numericStats{count: c.count, min: c.min, max: c.max}
+	buckets := d.histogram(c.seconds(), seconds.histogramBounds(DefaultHistogramBuckets), nil)
+	stats.Histogram = make([]TimeBucket, len(buckets))
+	for i, bucket := range buckets {
+		stats.Histogram[i] = TimeBucket{
+			Start: time.Unix(int64(math.Round(bucket.LowerBound)), 0).UTC(),
+			End:   time.Unix(int64(math.Round(bucket.UpperBound)), 0).UTC(),
+			Count: bucket.Count,
+		}
+	}
+	col.DateTime = stats
+}
+
+// histogram counts the values of x into buckets, each holding the values
+// from its lower bound up to but not including its upper bound, and the
+// last up to and including it, and counts the outliers of outliers, in one
+// scan.
+func (d *duckDB) histogram(x string, buckets []HistogramBucket, outliers *OutlierSummary) []HistogramBucket {
+	scan := &duckDBScan{}
+	for i := range buckets {
+		filter := fmt.Sprintf("%s >= %s AND %s < %s", x, duckDBNumber(buckets[i].LowerBound), x, duckDBNumber(buckets[i].UpperBound))
+		if i == len(buckets)-1 {
+			filter = fmt.Sprintf("%s >= %s", x, duckDBNumber(buckets[i].LowerBound))
+		}
+		scan.add("count(*) FILTER (WHERE "+filter+")", func(v any) { buckets[i].Count = duckDBInt(v) })
+	}
+	if outliers != nil {
+		filter := fmt.Sprintf("%s < %s OR %s > %s", x, duckDBNumber(outliers.Lower), x, duckDBNumber(outliers.Upper))
+		scan.add("count(*) FILTER (WHERE "+filter+")", func(v any) { outliers.Count = duckDBInt(v) })
+	}
+	if len(scan.exprs) > 0 {
+		d.query(scan.sql(), scan.scan)
+	}
+	return buckets
+}
+
+func duckDBIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func duckDBString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// duckDBNumber writes v as a DOUBLE literal that reads back as v.
+func duckDBNumber(v float64) string {
+	return "CAST(" + duckDBString(strconv.FormatFloat(v, 'g', -1, 64)) + " AS DOUBLE)"
+}
+
+func duckDBNumbers(values []float64) string {
+	literals := make([]string, len(values))
+	for i, v := range values {
+		literals[i] = duckDBNumber(v)
+	}
+	return strings.Join(literals, ", ")
+}
+
+// duckDBInt reads a count or other whole number, 0 for NULL.
+func duckDBInt(v any) int {
+	switch v := v.(type) {
+	case int64:
+		return int(v)
+	case int32:
+		return int(v)
+	case uint64:
+		return int(v)
+	case float64:
+		return int(v)
+	default:
+		return 0
+	}
+}
+
+// duckDBFloat reads a number, 0 for NULL.
+func duckDBFloat(v any) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case int32:
+		return float64(v)
+	default:
+		return 0
+	}
+}
+
+// duckDBText reads a value as text, empty for NULL.
+func duckDBText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
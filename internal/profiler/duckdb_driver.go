@@ -0,0 +1,22 @@
+//go:build duckdb
+
+package profiler
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/marcboeker/go-duckdb"
+)
+
+// duckDBBuilt reports whether this build can profile with DuckDB.
+const duckDBBuilt = true
+
+// openDuckDB opens an in-memory DuckDB database.
+func openDuckDB() (*sql.DB, error) {
+	db, err := sql.Open("duckdb", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DuckDB: %w", err)
+	}
+	return db, nil
+}
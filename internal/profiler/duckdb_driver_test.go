@@ -0,0 +1,173 @@
+//go:build duckdb
+
+package profiler
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func duckDBCSV(rows int) string {
+	var b strings.Builder
+	b.WriteString("id,amount,city,day,note\n")
+	for i := 0; i < rows; i++ {
+		note := ""
+		if i%9 != 0 {
+			note = fmt.Sprintf("Note %d", i%4)
+		}
+		day := time.Date(2024, 1, 1+i%60, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+		fmt.Fprintf(&b, "%d,%.2f,%s,%s,%s\n", i%(rows-3), float64(i%101)*1.25+float64(i%7)/4, []string{"NYC", "LA", "SF"}[i%3], day, note)
+	}
+	return b.String()
+}
+
+func TestProfileDuckDBMatchesGo(t *testing.T) {
+	path := writeTestFile(t, "data.csv", duckDBCSV(3000))
+
+	want, err := ProfileDatasetWithOptions(path, Options{})
+	if err != nil {
+		t.Fatalf("Failed to profile with Go: %v", err)
+	}
+	got, err := ProfileDatasetWithOptions(path, Options{Engine: EngineDuckDB, Examples: 3})
+	if err != nil {
+		t.Fatalf("Failed to profile with DuckDB: %v", err)
+	}
+
+	if !strings.HasPrefix(strings.Join(got.Notes, " "), "Profiled with DuckDB") {
+		t.Errorf("Expected a DuckDB note, got %q", got.Notes)
+	}
+	if got.RowCount != want.RowCount || got.MissingCells != want.MissingCells || got.DuplicateRows != want.DuplicateRows {
+		t.Errorf("Rows, missing cells, duplicates: got %d, %d, %d, want %d, %d, %d",
+			got.RowCount, got.MissingCells, got.DuplicateRows, want.RowCount, want.MissingCells, want.DuplicateRows)
+	}
+
+	close := func(a, b float64) bool { return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b)) }
+	for name, w := range want.Columns {
+		g := got.Columns[name]
+		if g == nil {
+			t.Errorf("%s: column missing", name)
+			continue
+		}
+		if g.DataType != w.DataType || g.Count != w.Count || g.MissingCount != w.MissingCount || g.UniqueCount != w.UniqueCount {
+			t.Errorf("%s: type, count, missing, unique: got %s, %d, %d, %d, want %s, %d, %d, %d",
+				name, g.DataType, g.Count, g.MissingCount, g.UniqueCount, w.DataType, w.Count, w.MissingCount, w.UniqueCount)
+		}
+		if !g.IsNumeric {
+			if !reflect.DeepEqual(g.TopValues, w.TopValues) || !reflect.DeepEqual(g.BottomValues, w.BottomValues) {
+				t.Errorf("%s: top and bottom values: got %v %v, want %v %v", name, g.TopValues, g.BottomValues, w.TopValues, w.BottomValues)
+			}
+		}
+		if len(g.Examples) != 3 {
+			t.Errorf("%s: expected 3 examples, got %q", name, g.Examples)
+		}
+
+		if w.IsNumeric {
+			if !close(g.Mean, w.Mean) || !close(g.StdDev, w.StdDev) || !close(g.Median, w.Median) || !close(g.Skewness, w.Skewness) {
+				t.Errorf("%s: mean, stddev, median, skewness: got %v, %v, %v, %v, want %v, %v, %v, %v",
+					name, g.Mean, g.StdDev, g.Median, g.Skewness, w.Mean, w.StdDev, w.Median, w.Skewness)
+			}
+			for i, p := range w.Percentiles {
+				if !close(g.Percentiles[i].Value, p.Value) {
+					t.Errorf("%s: p%d: got %v, want %v", name, p.Rank, g.Percentiles[i].Value, p.Value)
+				}
+			}
+			for i, bucket := range w.HistogramBuckets {
+				if g.HistogramBuckets[i].Count != bucket.Count {
+					t.Errorf("%s: bucket %d: got %d, want %d", name, i, g.HistogramBuckets[i].Count, bucket.Count)
+				}
+			}
+		}
+		if w.DateTime != nil {
+			if g.DateTime == nil || !g.DateTime.Min.Equal(w.DateTime.Min) || !g.DateTime.Max.Equal(w.DateTime.Max) || g.DateTime.Precision != w.DateTime.Precision {
+				t.Errorf("%s: got datetime stats %+v, want %+v", name, g.DateTime, w.DateTime)
+			}
+		}
+		if w.Text != nil {
+			if g.Text == nil || g.Text.Casing != w.Text.Casing || !reflect.DeepEqual(g.Text.Patterns, w.Text.Patterns) || g.Text.MaxLength != w.Text.MaxLength {
+				t.Errorf("%s: got text stats %+v, want %+v", name, g.Text, w.Text)
+			}
+		}
+	}
+}
+
+func TestProfileDuckDBUniqueKey(t *testing.T) {
+	path := writeTestFile(t, "data.csv", duckDBCSV(3000))
+	opts := Options{UniqueKey: []string{"city", "day"}}
+
+	want, err := ProfileDatasetWithOptions(path, opts)
+	if err != nil {
+		t.Fatalf("Failed to profile with Go: %v", err)
+	}
+	opts.Engine = EngineDuckDB
+	got, err := ProfileDatasetWithOptions(path, opts)
+	if err != nil {
+		t.Fatalf("Failed to profile with DuckDB: %v", err)
+	}
+
+	if got.DuplicateRows != want.DuplicateRows {
+		t.Errorf("Duplicate rows: got %d, want %d", got.DuplicateRows, want.DuplicateRows)
+	}
+	if !reflect.DeepEqual(got.DuplicateKeys[:3], want.DuplicateKeys[:3]) {
+		t.Errorf("Duplicate keys: got %v, want %v", got.DuplicateKeys[:3], want.DuplicateKeys[:3])
+	}
+
+	opts.UniqueKey = []string{"nope"}
+	if _, err := ProfileDatasetWithOptions(path, opts); err == nil {
+		t.Error("Expected an error for a missing key column")
+	}
+}
+
+func TestProfileDuckDBFallsBack(t *testing.T) {
+	path := writeTestFile(t, "data.csv", duckDBCSV(50))
+
+	tests := []struct {
+		opts Options
+		want string
+	}{
+		{Options{Engine: EngineDuckDB, ExactRows: 100}, "Profiled with the Go engine: fewer than 100 rows are profiled in exact mode"},
+		{Options{Engine: EngineDuckDB, SampleSize: 10}, "Profiled with the Go engine: --engine duckdb does not combine with --sample"},
+	}
+	for _, tt := range tests {
+		profile, err := ProfileDatasetWithOptions(path, tt.opts)
+		if err != nil {
+			t.Fatalf("Failed to profile: %v", err)
+		}
+		if !strings.Contains(strings.Join(profile.Notes, "\n"), tt.want) {
+			t.Errorf("Expected note %q, got %q", tt.want, profile.Notes)
+		}
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.parquet")
+	if err := os.WriteFile(bad, []byte("not parquet"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ProfileDatasetWithOptions(bad, Options{Engine: EngineDuckDB}); err == nil {
+		t.Error("Expected the Go engine to reject a broken Parquet file")
+	}
+}
+
+func TestPlanDuckDB(t *testing.T) {
+	path := writeTestFile(t, "data.csv", duckDBCSV(50))
+
+	plan, err := PlanProfile(path, Options{Engine: EngineDuckDB, Parallel: 4})
+	if err != nil {
+		t.Fatalf("Failed to plan: %v", err)
+	}
+	if plan.Algorithms.Engine != EngineDuckDB || plan.Algorithms.Reading != "sequential" || plan.Algorithms.Percentiles != "exact, by DuckDB" {
+		t.Errorf("Unexpected algorithms %+v", plan.Algorithms)
+	}
+
+	plan, err = PlanProfile(path, Options{Engine: EngineDuckDB, Robust: true})
+	if err != nil {
+		t.Fatalf("Failed to plan: %v", err)
+	}
+	if plan.Algorithms.Engine != EngineGo || !reflect.DeepEqual(plan.Warnings, []string{"Profiled with the Go engine: --engine duckdb does not combine with --robust"}) {
+		t.Errorf("Unexpected engine %s and warnings %q", plan.Algorithms.Engine, plan.Warnings)
+	}
+}
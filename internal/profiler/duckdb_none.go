@@ -0,0 +1,13 @@
+//go:build !duckdb
+
+package profiler
+
+import "database/sql"
+
+// duckDBBuilt reports whether this build can profile with DuckDB. DuckDB
+// needs cgo, so it is only linked into builds with the duckdb tag.
+const duckDBBuilt = false
+
+func openDuckDB() (*sql.DB, error) {
+	return nil, errNoDuckDB
+}
@@ -0,0 +1,107 @@
+package profiler
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateEngine(t *testing.T) {
+	for _, engine := range []string{"", EngineGo} {
+		if err := (Options{Engine: engine}).validate(); err != nil {
+			t.Errorf("Engine %q: unexpected error %v", engine, err)
+		}
+	}
+
+	err := Options{Engine: "spark"}.validate()
+	if err == nil || !strings.Contains(err.Error(), "unsupported engine: spark") {
+		t.Errorf("Expected an unsupported engine error, got %v", err)
+	}
+
+	err = Options{Engine: EngineDuckDB}.validate()
+	if duckDBBuilt && err != nil {
+		t.Errorf("Expected DuckDB to be available, got %v", err)
+	}
+	if !duckDBBuilt && !errors.Is(err, errNoDuckDB) {
+		t.Errorf("Expected %v, got %v", errNoDuckDB, err)
+	}
+}
+
+func TestDuckDBReason(t *testing.T) {
+	tests := []struct {
+		name string
+		plan Plan
+		opts Options
+		want string
+	}{
+		{"csv", Plan{Kind: "file", Format: FormatCSV}, Options{}, ""},
+		{"parquet", Plan{Kind: "file", Format: "parquet"}, Options{UniqueKey: []string{"id"}}, ""},
+		{"remote", Plan{Kind: "remote", Format: FormatCSV}, Options{}, "--engine duckdb only applies to local CSV, TSV, JSONL and Parquet files"},
+		{"excel", Plan{Kind: "excel", Format: "excel"}, Options{}, "--engine duckdb only applies to local CSV, TSV, JSONL and Parquet files"},
+		{"compressed", Plan{Kind: "file", Format: FormatCSV, Compression: "gzip"}, Options{}, "--engine duckdb does not apply to gzip-compressed input"},
+		{"sample", Plan{Kind: "file", Format: FormatCSV}, Options{SampleSize: 10}, "--engine duckdb does not combine with --sample"},
+		{"weights", Plan{Kind: "file", Format: FormatTSV}, Options{WeightColumn: "w"}, "--engine duckdb does not combine with --weight-column"},
+		{"rows", Plan{Kind: "file", Format: FormatJSONL}, Options{Rows: func(header, record []string) error { return nil }}, "rows are passed on one at a time"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.plan.duckDBReason(tt.opts); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDuckDBCSVArgs(t *testing.T) {
+	tests := []struct {
+		format string
+		opts   Options
+		want   []string
+	}{
+		{FormatCSV, Options{}, []string{"'a.csv'", "header = true", `quote = '"'`}},
+		{FormatTSV, Options{}, []string{"'a.csv'", "header = true", "delim = '\t'", `quote = '"'`}},
+		{FormatCSV, Options{Delimiter: ';', Quote: '\'', SkipRows: 2}, []string{"'a.csv'", "header = true", "delim = ';'", "quote = ''''", "skip = 2"}},
+		{FormatCSV, Options{Quote: NoQuote}, []string{"'a.csv'", "header = true", "quote = ''"}},
+	}
+
+	for _, tt := range tests {
+		if got := duckDBCSVArgs("'a.csv'", tt.format, tt.opts); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s %+v: got %q, want %q", tt.format, tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestDuckDBDataType(t *testing.T) {
+	tests := map[string]string{
+		"BIGINT":                   "integer",
+		"UTINYINT":                 "integer",
+		"DOUBLE":                   "float",
+		"DECIMAL(18,3)":            "float",
+		"DATE":                     "datetime",
+		"TIMESTAMP WITH TIME ZONE": "datetime",
+		"VARCHAR":                  "string",
+		"BOOLEAN":                  "string",
+		"STRUCT(a INTEGER)":        "string",
+	}
+
+	for sqlType, want := range tests {
+		if got := duckDBDataType(sqlType); got != want {
+			t.Errorf("%s: got %s, want %s", sqlType, got, want)
+		}
+	}
+}
+
+func TestDuckDBLiterals(t *testing.T) {
+	if got := duckDBIdentifier(`say "hi"`); got != `"say ""hi"""` {
+		t.Errorf("Identifier: got %s", got)
+	}
+	if got := duckDBString("it's"); got != "'it''s'" {
+		t.Errorf("String: got %s", got)
+	}
+	if got := duckDBNumber(0.1); got != "CAST('0.1' AS DOUBLE)" {
+		t.Errorf("Number: got %s", got)
+	}
+	if got := duckDBNumber(-1e21); got != "CAST('-1e+21' AS DOUBLE)" {
+		t.Errorf("Number: got %s", got)
+	}
+}
@@ -0,0 +1,184 @@
+package profiler
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+const (
+	EncodingUTF8        = "UTF-8"
+	EncodingUTF16LE     = "UTF-16LE"
+	EncodingUTF16BE     = "UTF-16BE"
+	EncodingLatin1      = "ISO-8859-1"
+	EncodingWindows1252 = "Windows-1252"
+)
+
+// encodingScan is how much of a text source is looked at to detect its
+// encoding when there is no byte order mark.
+const encodingScan = 64 * 1024
+
+var encodingNames = map[string]string{
+	"utf-8":        EncodingUTF8,
+	"utf8":         EncodingUTF8,
+	"utf-16le":     EncodingUTF16LE,
+	"utf-16be":     EncodingUTF16BE,
+	"iso-8859-1":   EncodingLatin1,
+	"latin1":       EncodingLatin1,
+	"latin-1":      EncodingLatin1,
+	"windows-1252": EncodingWindows1252,
+	"cp1252":       EncodingWindows1252,
+}
+
+var byteOrderMarks = []struct {
+	encoding string
+	bom      []byte
+}{
+	{EncodingUTF8, []byte{0xef, 0xbb, 0xbf}},
+	{EncodingUTF16LE, []byte{0xff, 0xfe}},
+	{EncodingUTF16BE, []byte{0xfe, 0xff}},
+}
+
+// lookupEncoding maps an --encoding value such as utf-16le or cp1252 to
+// its canonical name.
+func lookupEncoding(name string) (string, error) {
+	if canonical, ok := encodingNames[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return canonical, nil
+	}
+	return "", fmt.Errorf("unsupported encoding: %s (use utf-8, utf-16le, utf-16be, latin1 or windows-1252)", name)
+}
+
+func textEncoding(name string) encoding.Encoding {
+	switch name {
+	case EncodingUTF16LE:
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+	case EncodingUTF16BE:
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)
+	case EncodingLatin1:
+		return charmap.ISO8859_1
+	case EncodingWindows1252:
+		return charmap.Windows1252
+	default:
+		return nil
+	}
+}
+
+// transcoder is a text source converted to UTF-8, with the encoding it was
+// read in.
+type transcoder struct {
+	io.Reader
+	encoding string
+	override bool
+}
+
+// transcode detects the encoding of r from a byte order mark, or failing
+// that from its first bytes, and converts it to UTF-8. name is the
+// --encoding override, which skips detection. A byte order mark is dropped
+// either way so it does not end up in the first column name. The scan stops
+// at limit bytes when that is smaller, so a --range read does not fetch or
+// decompress data it will not profile.
+func transcode(r io.Reader, name string, limit int64) (*transcoder, error) {
+	scan := encodingScan
+	if limit > 0 && limit < int64(scan) {
+		scan = int(limit)
+	}
+
+	br := bufio.NewReaderSize(r, scan)
+	head, err := br.Peek(scan)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+
+	t := &transcoder{}
+	if name != "" {
+		if t.encoding, err = lookupEncoding(name); err != nil {
+			return nil, err
+		}
+		t.override = true
+	} else {
+		t.encoding = detectEncoding(head, len(head) < scan)
+	}
+
+	for _, mark := range byteOrderMarks {
+		if mark.encoding == t.encoding && bytes.HasPrefix(head, mark.bom) {
+			br.Discard(len(mark.bom))
+		}
+	}
+
+	t.Reader = br
+	if enc := textEncoding(t.encoding); enc != nil {
+		t.Reader = transform.NewReader(br, enc.NewDecoder())
+	}
+	return t, nil
+}
+
+// detectEncoding guesses the encoding of head, which is the whole input when
+// complete is set. Text with many NUL bytes at alternating positions is
+// UTF-16, valid UTF-8 is UTF-8, and anything else is taken as Windows-1252,
+// or Latin-1 when it has bytes that Windows-1252 leaves undefined.
+func detectEncoding(head []byte, complete bool) string {
+	for _, mark := range byteOrderMarks {
+		if bytes.HasPrefix(head, mark.bom) {
+			return mark.encoding
+		}
+	}
+
+	var evenZeros, oddZeros int
+	for i, b := range head {
+		if b != 0 {
+			continue
+		}
+		if i%2 == 0 {
+			evenZeros++
+		} else {
+			oddZeros++
+		}
+	}
+	pairs := len(head) / 2
+	switch {
+	case pairs > 0 && oddZeros > pairs/4 && evenZeros < oddZeros/8:
+		return EncodingUTF16LE
+	case pairs > 0 && evenZeros > pairs/4 && oddZeros < evenZeros/8:
+		return EncodingUTF16BE
+	}
+
+	if !complete {
+		// The scan may end part way through a multi-byte character
+		for i := 0; i < utf8.UTFMax && i < len(head); i++ {
+			if utf8.Valid(head[:len(head)-i]) {
+				return EncodingUTF8
+			}
+		}
+	} else if utf8.Valid(head) {
+		return EncodingUTF8
+	}
+
+	for _, b := range head {
+		switch b {
+		case 0x81, 0x8d, 0x8f, 0x90, 0x9d:
+			return EncodingLatin1
+		}
+	}
+	return EncodingWindows1252
+}
+
+// describe records the encoding on the profile, with a note when the
+// source was converted.
+func (t *transcoder) describe(profile *DatasetProfile) {
+	profile.Encoding = t.encoding
+	switch {
+	case t.encoding == EncodingUTF8:
+	case t.override:
+		profile.Notes = append(profile.Notes, fmt.Sprintf("Converted from %s to UTF-8 (--encoding)", t.encoding))
+	default:
+		profile.Notes = append(profile.Notes, fmt.Sprintf("Detected %s encoding, converted to UTF-8", t.encoding))
+	}
+}
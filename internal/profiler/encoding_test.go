@@ -0,0 +1,112 @@
+package profiler
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func encodeUTF16(s string, bigEndian bool) []byte {
+	units := utf16.Encode([]rune(s))
+	data := make([]byte, 0, len(units)*2)
+	for _, u := range units {
+		if bigEndian {
+			data = append(data, byte(u>>8), byte(u))
+		} else {
+			data = append(data, byte(u), byte(u>>8))
+		}
+	}
+	return data
+}
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"ascii", []byte("name,city\nAnn,Paris\n"), EncodingUTF8},
+		{"utf-8", []byte("name,city\nJosé,München\n"), EncodingUTF8},
+		{"utf-8 bom", append([]byte{0xef, 0xbb, 0xbf}, "a,b\n"...), EncodingUTF8},
+		{"utf-16le bom", append([]byte{0xff, 0xfe}, encodeUTF16("a,b\n", false)...), EncodingUTF16LE},
+		{"utf-16be bom", append([]byte{0xfe, 0xff}, encodeUTF16("a,b\n", true)...), EncodingUTF16BE},
+		{"utf-16le", encodeUTF16("name,city\nAnn,Paris\n", false), EncodingUTF16LE},
+		{"utf-16be", encodeUTF16("name,city\nAnn,Paris\n", true), EncodingUTF16BE},
+		{"windows-1252", []byte("name,quote\nJos\xe9,\x93hi\x94\n"), EncodingWindows1252},
+		{"latin-1", []byte("name,code\nJos\xe9,\x81\n"), EncodingLatin1},
+	}
+
+	for _, tt := range tests {
+		if got := detectEncoding(tt.data, true); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestDetectEncodingPartialScan(t *testing.T) {
+	// A scan that stops inside a multi-byte character is still UTF-8
+	data := []byte(strings.Repeat("a", 10) + "é")
+	if got := detectEncoding(data[:len(data)-1], false); got != EncodingUTF8 {
+		t.Errorf("Expected UTF-8, got %s", got)
+	}
+}
+
+func TestProfileEncodedCSV(t *testing.T) {
+	content := "name,city\nJosé,München\nAnn,Zürich\nJosé,Genève\n"
+
+	tests := []struct {
+		name     string
+		data     []byte
+		encoding string
+		want     string
+		note     string
+	}{
+		{"windows-1252", []byte("name,city\nJos\xe9,M\xfcnchen\nAnn,Z\xfcrich\nJos\xe9,Gen\xe8ve\n"), "", EncodingWindows1252, "Detected Windows-1252 encoding, converted to UTF-8"},
+		{"utf-16le", append([]byte{0xff, 0xfe}, encodeUTF16(content, false)...), "", EncodingUTF16LE, "Detected UTF-16LE encoding, converted to UTF-8"},
+		{"utf-16be", encodeUTF16(content, true), "", EncodingUTF16BE, "Detected UTF-16BE encoding, converted to UTF-8"},
+		{"utf-8 bom", append([]byte{0xef, 0xbb, 0xbf}, content...), "", EncodingUTF8, ""},
+		{"override", []byte("name,city\nJos\xe9,M\xfcnchen\nAnn,Z\xfcrich\nJos\xe9,Gen\xe8ve\n"), "latin1", EncodingLatin1, "Converted from ISO-8859-1 to UTF-8 (--encoding)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, "data.csv", string(tt.data))
+
+			profile, err := ProfileDatasetWithOptions(path, Options{Encoding: tt.encoding})
+			if err != nil {
+				t.Fatalf("Failed to profile: %v", err)
+			}
+
+			if profile.Encoding != tt.want {
+				t.Errorf("Expected encoding %s, got %s", tt.want, profile.Encoding)
+			}
+			col := profile.Columns["name"]
+			if col == nil {
+				t.Fatalf("Expected a name column, got %v", profile.Columns)
+			}
+			if len(col.TopValues) == 0 || col.TopValues[0].Value != "José" {
+				t.Errorf("Expected José as the top name, got %v", col.TopValues)
+			}
+			notes := strings.Join(profile.Notes, " ")
+			if tt.note != "" && !strings.Contains(notes, tt.note) {
+				t.Errorf("Expected note %q, got %v", tt.note, profile.Notes)
+			}
+			if tt.note == "" && strings.Contains(notes, "encoding") {
+				t.Errorf("Expected no encoding note, got %v", profile.Notes)
+			}
+		})
+	}
+}
+
+func TestProfileEncodingErrors(t *testing.T) {
+	path := writeTestFile(t, "data.csv", string([]byte("a,b\n1,2\n")))
+
+	if _, err := ProfileDatasetWithOptions(path, Options{Encoding: "ebcdic"}); err == nil || !strings.Contains(err.Error(), "unsupported encoding") {
+		t.Errorf("Expected an unsupported encoding error, got %v", err)
+	}
+
+	workbook := createTestWorkbook(t)
+	if _, err := ProfileDatasetWithOptions(workbook, Options{Sheet: "Orders", Encoding: "latin1"}); err == nil || !strings.Contains(err.Error(), "--encoding is not supported") {
+		t.Errorf("Expected --encoding to be rejected for Excel, got %v", err)
+	}
+}
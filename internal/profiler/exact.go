@@ -0,0 +1,105 @@
+package profiler
+
+import (
+	"sort"
+	"strings"
+)
+
+// DuplicateGroup is a set of identical rows, or of rows sharing a unique
+// key.
+type DuplicateGroup struct {
+	Rows   []int             // positions among the profiled rows, from 1
+	Values map[string]string // the row, or its key, by column name
+}
+
+// exactRecords keeps every record of a dataset while it has fewer than
+// limit rows, for the complete duplicate listing of exact mode.
+type exactRecords struct {
+	limit   int
+	records [][]string
+}
+
+func newExactRecords(limit int) *exactRecords {
+	return &exactRecords{limit: limit, records: make([][]string, 0)}
+}
+
+// add keeps a record, or returns false once the dataset is too large.
+func (e *exactRecords) add(record []string) bool {
+	if len(e.records)+1 >= e.limit {
+		return false
+	}
+	e.records = append(e.records, append([]string(nil), record...))
+	return true
+}
+
+// merge folds in the records that follow the ones kept here, or returns
+// false when together they are too many.
+func (e *exactRecords) merge(o *exactRecords) bool {
+	if len(e.records)+len(o.records) >= e.limit {
+		return false
+	}
+	e.records = append(e.records, o.records...)
+	return true
+}
+
+// duplicates lists the groups of identical records in order of first
+// appearance, or of records sharing the values at keyIndexes unless nil.
+func (e *exactRecords) duplicates(header []string, keyIndexes []int) []DuplicateGroup {
+	groups := make([]DuplicateGroup, 0)
+	first := make(map[string]int)
+	group := make(map[string]int)
+
+	for i, record := range e.records {
+		key := strings.Join(record, keySeparator)
+		if keyIndexes != nil {
+			key = recordKey(record, keyIndexes)
+		}
+		row, seen := first[key]
+		if !seen {
+			first[key] = i + 1
+			continue
+		}
+
+		index, ok := group[key]
+		if !ok {
+			values := recordValues(header, record)
+			if keyIndexes != nil {
+				values = keyValues(header, record, keyIndexes)
+			}
+			groups = append(groups, DuplicateGroup{Rows: []int{row}, Values: values})
+			index = len(groups) - 1
+			group[key] = index
+		}
+		groups[index].Rows = append(groups[index].Rows, i+1)
+	}
+
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Rows[0] < groups[j].Rows[0] })
+	return groups
+}
+
+// keyValues returns the values of record at keyIndexes by column name.
+func keyValues(header []string, record []string, keyIndexes []int) map[string]string {
+	values := make(map[string]string, len(keyIndexes))
+	for _, index := range keyIndexes {
+		values[header[index]] = ""
+		if index < len(record) {
+			values[header[index]] = record[index]
+		}
+	}
+	return values
+}
+
+func recordValues(header []string, record []string) map[string]string {
+	values := make(map[string]string, len(header))
+	for i, name := range header {
+		if _, ok := values[name]; ok {
+			continue
+		}
+		if i < len(record) {
+			values[name] = record[i]
+		} else {
+			values[name] = ""
+		}
+	}
+	return values
+}
@@ -0,0 +1,104 @@
+package profiler
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExactRecordsDuplicates(t *testing.T) {
+	e := newExactRecords(10)
+	for _, record := range [][]string{{"a", "1"}, {"b", "2"}, {"b", "2"}, {"a", "1"}, {"c"}, {"a", "1"}} {
+		if !e.add(record) {
+			t.Fatal("Expected records below the limit to be kept")
+		}
+	}
+
+	want := []DuplicateGroup{
+		{Rows: []int{1, 4, 6}, Values: map[string]string{"name": "a", "n": "1"}},
+		{Rows: []int{2, 3}, Values: map[string]string{"name": "b", "n": "2"}},
+	}
+	if got := e.duplicates([]string{"name", "n"}, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	// By key, the short record shares the name of none and rows 2 and 3
+	// list only the name
+	want = []DuplicateGroup{
+		{Rows: []int{1, 4, 6}, Values: map[string]string{"name": "a"}},
+		{Rows: []int{2, 3}, Values: map[string]string{"name": "b"}},
+	}
+	if got := e.duplicates([]string{"name", "n"}, []int{0}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v by key, got %v", want, got)
+	}
+
+	small := newExactRecords(3)
+	if !small.add([]string{"x"}) || !small.add([]string{"y"}) || small.add([]string{"z"}) {
+		t.Error("Expected records to be kept up to but not including the limit")
+	}
+}
+
+func exactCSV(rows int) string {
+	var b strings.Builder
+	b.WriteString("id,city,note\n")
+	for i := 0; i < rows; i++ {
+		id := i % (rows - 2)
+		fmt.Fprintf(&b, "%d,city%d,note%d\n", id, id%7, id)
+	}
+	return b.String()
+}
+
+func TestProfileExactMode(t *testing.T) {
+	path := writeTestFile(t, "small.csv", exactCSV(40))
+
+	profile, err := ProfileDatasetWithOptions(path, Options{ExactRows: 50})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if !profile.Exact || !strings.Contains(strings.Join(profile.Notes, " "), "Fewer than 50 rows: exact mode") {
+		t.Fatalf("Expected exact mode with a note, got %v and %v", profile.Exact, profile.Notes)
+	}
+	if note := profile.Columns["note"]; len(note.TopValues) != 38 {
+		t.Errorf("Expected all 38 distinct notes listed, got %d", len(note.TopValues))
+	}
+	if len(profile.DuplicateGroups) != 2 || profile.DuplicateRows != 2 {
+		t.Fatalf("Expected 2 duplicate groups for 2 duplicate rows, got %v and %d", profile.DuplicateGroups, profile.DuplicateRows)
+	}
+	if group := profile.DuplicateGroups[0]; !reflect.DeepEqual(group.Rows, []int{1, 39}) || group.Values["note"] != "note0" {
+		t.Errorf("Expected rows 1 and 39 holding note0, got %+v", group)
+	}
+
+	for _, opts := range []Options{{ExactRows: 40}, {}, {ExactRows: 50, SampleSize: 45}} {
+		profile, err := ProfileDatasetWithOptions(path, opts)
+		if err != nil {
+			t.Fatalf("Failed to profile: %v", err)
+		}
+		if profile.Exact || profile.DuplicateGroups != nil || len(profile.Columns["note"].TopValues) != 5 {
+			t.Errorf("%+v: expected the regular mode, got exact %v with %d top values", opts, profile.Exact, len(profile.Columns["note"].TopValues))
+		}
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{ExactRows: -1}); err == nil {
+		t.Error("Expected an error for a negative threshold")
+	}
+}
+
+func TestProfileTopValues(t *testing.T) {
+	path := writeTestFile(t, "small.csv", exactCSV(40))
+
+	profile, err := ProfileDatasetWithOptions(path, Options{TopValues: 10})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if city := profile.Columns["city"]; len(city.TopValues) != 7 {
+		t.Errorf("Expected all 7 cities listed, got %v", city.TopValues)
+	}
+	if note := profile.Columns["note"]; len(note.TopValues) != 10 {
+		t.Errorf("Expected 10 notes listed, got %d", len(note.TopValues))
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{TopValues: -1}); err == nil {
+		t.Error("Expected an error for a negative top value count")
+	}
+}
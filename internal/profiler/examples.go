@@ -0,0 +1,86 @@
+package profiler
+
+import (
+	"math/rand"
+	"time"
+)
+
+const maxExampleLength = 200
+
+// exampleSampler keeps a uniform random sample of a column's values with
+// reservoir sampling. Long values are truncated to keep the sample small.
+type exampleSampler struct {
+	size   int
+	seen   int
+	values []string
+	rng    *rand.Rand
+}
+
+func newExampleSampler(size int) *exampleSampler {
+	return &exampleSampler{
+		size:   size,
+		values: make([]string, 0, size),
+		rng:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (e *exampleSampler) add(value string) {
+	e.seen++
+
+	if len(e.values) < e.size {
+		e.values = append(e.values, truncateExample(value))
+		return
+	}
+
+	if j := e.rng.Intn(e.seen); j < e.size {
+		e.values[j] = truncateExample(value)
+	}
+}
+
+// merge combines two reservoirs into a uniform sample of both streams: each
+// kept value comes from a side with probability proportional to the values
+// that side has not yet contributed.
+func (e *exampleSampler) merge(o *exampleSampler) {
+	if o.seen == 0 {
+		return
+	}
+
+	mine, theirs := e.values, append([]string(nil), o.values...)
+	seenMine, seenTheirs := e.seen, o.seen
+	merged := make([]string, 0, e.size)
+
+	for len(merged) < e.size && (len(mine) > 0 || len(theirs) > 0) {
+		if len(theirs) == 0 || (len(mine) > 0 && e.rng.Intn(seenMine+seenTheirs) < seenMine) {
+			var value string
+			value, mine = takeRandom(e.rng, mine)
+			merged = append(merged, value)
+			seenMine--
+		} else {
+			var value string
+			value, theirs = takeRandom(e.rng, theirs)
+			merged = append(merged, value)
+			seenTheirs--
+		}
+	}
+
+	e.values = merged
+	e.seen += o.seen
+}
+
+func takeRandom(rng *rand.Rand, values []string) (string, []string) {
+	i := rng.Intn(len(values))
+	value := values[i]
+	values[i] = values[len(values)-1]
+	return value, values[:len(values)-1]
+}
+
+func truncateExample(value string) string {
+	if len(value) <= maxExampleLength {
+		return value
+	}
+	runes := []rune(value)
+	if len(runes) <= maxExampleLength {
+		return value
+	}
+	return string(runes[:maxExampleLength]) + "…"
+}
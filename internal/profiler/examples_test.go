@@ -0,0 +1,63 @@
+package profiler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExampleSampler(t *testing.T) {
+	sampler := newExampleSampler(3)
+	for i := 0; i < 1000; i++ {
+		sampler.add(fmt.Sprintf("v%d", i))
+	}
+
+	if len(sampler.values) != 3 {
+		t.Fatalf("Expected 3 examples, got %d", len(sampler.values))
+	}
+	if sampler.values[0] == "v0" && sampler.values[1] == "v1" && sampler.values[2] == "v2" {
+		t.Error("Expected examples to be sampled beyond the first values")
+	}
+
+	long := strings.Repeat("x", maxExampleLength+50)
+	if got := truncateExample(long); len([]rune(got)) != maxExampleLength+1 {
+		t.Errorf("Expected long example to be truncated, got %d runes", len([]rune(got)))
+	}
+}
+
+func TestProfileExamples(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.csv")
+	var content strings.Builder
+	content.WriteString("name,email\n")
+	for i := 0; i < 50; i++ {
+		content.WriteString(fmt.Sprintf("user%d,user%d@example.com\n", i, i))
+	}
+	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	profile, err := ProfileDatasetWithOptions(path, Options{Examples: 4, Redact: []string{"EMAIL"}})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+
+	name := profile.Columns["name"]
+	if len(name.Examples) != 4 || !strings.HasPrefix(name.Examples[0], "user") {
+		t.Errorf("Expected 4 name examples, got %v", name.Examples)
+	}
+
+	email := profile.Columns["email"]
+	if len(email.Examples) != 0 || !email.ExamplesRedacted {
+		t.Errorf("Expected email examples to be redacted, got %v", email.Examples)
+	}
+
+	profile, err = ProfileDataset(path)
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if len(profile.Columns["name"].Examples) != 0 {
+		t.Error("Expected no examples by default")
+	}
+}
@@ -0,0 +1,427 @@
+package profiler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// excelHeaderScan is the number of data rows read ahead to find how wide a
+// sheet is when the header has trailing blank cells.
+const excelHeaderScan = 100
+
+var errEmptySheet = errors.New("sheet is empty")
+
+// IsExcel reports whether path is an Excel workbook, judged by extension.
+func IsExcel(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xlsx", ".xlsm":
+		return true
+	default:
+		return false
+	}
+}
+
+// openExcel opens a workbook, decrypting it with password when it is
+// protected. excelize reports a wrong password as either an unreadable
+// package or a bad password, so both map to ErrWrongPassword.
+func openExcel(path, password string) (*excelize.File, error) {
+	encrypted := isOLEFile(path)
+	if encrypted && password == "" {
+		return nil, fmt.Errorf("failed to open Excel workbook: %w", ErrPasswordRequired)
+	}
+
+	f, err := excelize.OpenFile(path, excelize.Options{Password: password})
+	switch {
+	case err == nil:
+		return f, nil
+	case encrypted && errors.Is(err, excelize.ErrUnsupportedEncryptMechanism):
+		return nil, fmt.Errorf("failed to open Excel workbook: %w", ErrUnsupportedEncryption)
+	case encrypted && (errors.Is(err, excelize.ErrWorkbookPassword) || errors.Is(err, excelize.ErrWorkbookFileFormat)):
+		return nil, fmt.Errorf("failed to open Excel workbook: %w", ErrWrongPassword)
+	default:
+		return nil, fmt.Errorf("failed to open Excel workbook: %w", err)
+	}
+}
+
+// ListExcelSheets returns the worksheet names of a workbook in tab order.
+func ListExcelSheets(path, password string) ([]string, error) {
+	f, err := openExcel(path, password)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return f.GetSheetList(), nil
+}
+
+// ProfileExcelSheets profiles every non-empty sheet of a workbook until ctx
+// is done. The sheets profiled by then are returned with an error wrapping
+// ctx.Err(), the last one of them partial when it was being read.
+func ProfileExcelSheets(ctx context.Context, path string, opts Options) ([]*DatasetProfile, error) {
+	sheets, err := ListExcelSheets(path, opts.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]*DatasetProfile, 0, len(sheets))
+	for _, sheet := range sheets {
+		opts.Sheet = sheet
+		profile, err := ProfileDatasetContext(ctx, path, opts)
+		if errors.Is(err, errEmptySheet) {
+			continue
+		}
+		if err != nil && ctx.Err() != nil && (profile != nil || len(profiles) > 0) {
+			// Stopped early: keep what was profiled
+			if profile != nil {
+				profiles = append(profiles, profile)
+			}
+			return profiles, fmt.Errorf("profiling stopped at sheet %s: %w", sheet, err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to profile sheet %s: %w", sheet, err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("no sheets with data found in Excel workbook %s", path)
+	}
+
+	return profiles, nil
+}
+
+// profileExcel profiles one sheet of a workbook, or the table, defined name
+// or cell range in opts.Range. The first non-empty row is the header; blank
+// header cells are named after their column letter.
+// Numbers are read unformatted, and numbers in date-formatted cells become
+// ISO dates.
+func profileExcel(filePath string, opts Options) (*DatasetProfile, error) {
+	startTime := time.Now()
+
+	if err := opts.textOnly("Excel workbooks"); err != nil {
+		return nil, err
+	}
+
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file stats: %w", err)
+	}
+
+	f, err := openExcel(filePath, opts.Password)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var bounds *excelRange
+	if opts.Range != "" {
+		if bounds, err = resolveExcelRange(f, opts.Range, opts.Sheet); err != nil {
+			return nil, err
+		}
+		opts.Sheet = bounds.sheet
+	}
+
+	sheets := f.GetSheetList()
+	sheet := opts.Sheet
+	if sheet == "" {
+		if len(sheets) != 1 {
+			return nil, fmt.Errorf("workbook has %d sheets, choose one with --sheet: %s", len(sheets), strings.Join(sheets, ", "))
+		}
+		sheet = sheets[0]
+	} else if !containsString(sheets, sheet) {
+		return nil, fmt.Errorf("sheet %s not found in Excel workbook (sheets: %s)", sheet, strings.Join(sheets, ", "))
+	}
+
+	reader, err := newExcelSheetReader(f, sheet, bounds)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.close()
+
+	header, err := reader.header()
+	if err != nil {
+		return nil, err
+	}
+
+	name := filepath.Base(filePath) + "." + sheet
+	if bounds != nil {
+		name += "." + bounds.label()
+	}
+	profile := newDatasetProfile(name, fileInfo.Size(), "Excel", header)
+	profile.Table = sheet
+
+	if err := profileRows(profile, header, reader.next, opts); err != nil {
+		return nil, err
+	}
+
+	if bounds != nil {
+		profile.Notes = append(profile.Notes, bounds.note())
+	}
+	if reader.overflow > 0 {
+		profile.Notes = append(profile.Notes, fmt.Sprintf(
+			"%d rows had cells beyond the last header column that were not profiled", reader.overflow))
+	}
+
+	profile.ProcessingTime = time.Since(startTime)
+
+	return profile, nil
+}
+
+// excelSheetReader turns the rows of a sheet into records the width of the
+// header.
+type excelSheetReader struct {
+	file     *excelize.File
+	sheet    string
+	bounds   *excelRange // nil for the whole sheet
+	rows     *excelize.Rows
+	row      int
+	buffered []excelRow
+	width    int
+	overflow int // rows with cells past the header width
+	date1904 bool
+	dates    map[int]bool // columns whose cells are date formatted
+}
+
+type excelRow struct {
+	number int
+	cells  []string
+}
+
+func newExcelSheetReader(f *excelize.File, sheet string, bounds *excelRange) (*excelSheetReader, error) {
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sheet %s: %w", sheet, err)
+	}
+
+	r := &excelSheetReader{file: f, sheet: sheet, bounds: bounds, rows: rows}
+	if props, err := f.GetWorkbookProps(); err == nil && props.Date1904 != nil {
+		r.date1904 = *props.Date1904
+	}
+	return r, nil
+}
+
+func (r *excelSheetReader) close() {
+	r.rows.Close()
+}
+
+// readRow returns the raw cells of the next non-empty row, cut to the
+// bounds of the range when there is one.
+func (r *excelSheetReader) readRow() (excelRow, error) {
+	for r.rows.Next() {
+		r.row++
+		if r.bounds != nil && r.bounds.lastRow > 0 && r.row > r.bounds.lastRow {
+			break
+		}
+		if r.bounds != nil && r.row < r.bounds.firstRow {
+			continue
+		}
+		cells, err := r.rows.Columns(excelize.Options{RawCellValue: true})
+		if err != nil {
+			return excelRow{}, fmt.Errorf("error reading sheet %s row %d: %w", r.sheet, r.row, err)
+		}
+		cells = r.clip(cells)
+		for _, cell := range cells {
+			if strings.TrimSpace(cell) != "" {
+				return excelRow{number: r.row, cells: cells}, nil
+			}
+		}
+	}
+
+	if err := r.rows.Error(); err != nil {
+		return excelRow{}, fmt.Errorf("error reading sheet %s: %w", r.sheet, err)
+	}
+	return excelRow{}, io.EOF
+}
+
+// clip cuts a row to the columns of the range.
+func (r *excelSheetReader) clip(cells []string) []string {
+	if r.bounds == nil {
+		return cells
+	}
+	if offset := r.colOffset(); offset > 0 {
+		if len(cells) <= offset {
+			return nil
+		}
+		cells = cells[offset:]
+	}
+	if r.bounds.lastCol > 0 && len(cells) > r.bounds.lastCol-r.colOffset() {
+		cells = cells[:r.bounds.lastCol-r.colOffset()]
+	}
+	return cells
+}
+
+// colOffset is the number of sheet columns left of the range.
+func (r *excelSheetReader) colOffset() int {
+	if r.bounds == nil || r.bounds.firstCol == 0 {
+		return 0
+	}
+	return r.bounds.firstCol - 1
+}
+
+func (r *excelSheetReader) header() ([]string, error) {
+	row, err := r.readRow()
+	if err == io.EOF {
+		return nil, fmt.Errorf("sheet %s: %w", r.sheet, errEmptySheet)
+	}
+	if err != nil {
+		return nil, err
+	}
+	cells := row.cells
+
+	// Trailing blank header cells are trimmed like any row, so the width
+	// also comes from the sheet dimension and the first rows of data
+	width := len(cells)
+	if r.bounds != nil && r.bounds.lastCol > 0 {
+		width = r.bounds.lastCol - r.colOffset()
+	} else if dimension, err := r.file.GetSheetDimension(r.sheet); err == nil {
+		_, last, _ := strings.Cut(dimension, ":")
+		if col, _, err := excelize.CellNameToCoordinates(last); err == nil && col-r.colOffset() > width {
+			width = col - r.colOffset()
+		}
+	}
+	for len(r.buffered) < excelHeaderScan {
+		row, err := r.readRow()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		r.buffered = append(r.buffered, row)
+		if len(row.cells) > width {
+			width = len(row.cells)
+		}
+	}
+
+	header := make([]string, width)
+	for i := range header {
+		if i < len(cells) {
+			header[i] = strings.TrimSpace(cells[i])
+		}
+		if header[i] == "" {
+			header[i], _ = excelize.ColumnNumberToName(r.colOffset() + i + 1)
+		}
+	}
+
+	r.width = len(header)
+	return header, nil
+}
+
+func (r *excelSheetReader) next() ([]string, error) {
+	var row excelRow
+	if len(r.buffered) > 0 {
+		row = r.buffered[0]
+		r.buffered = r.buffered[1:]
+	} else {
+		var err error
+		if row, err = r.readRow(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(row.cells) > r.width {
+		r.overflow++
+	}
+
+	record := make([]string, r.width)
+	copy(record, row.cells)
+
+	for i, value := range record {
+		if value != "" && r.isDateColumn(i, row.number) {
+			record[i] = r.formatDate(value)
+		}
+	}
+
+	return record, nil
+}
+
+// isDateColumn decides from the first non-empty cell of a column whether
+// the column holds dates, by the number format of that cell.
+func (r *excelSheetReader) isDateColumn(col, row int) bool {
+	if isDate, decided := r.dates[col]; decided {
+		return isDate
+	}
+	if r.dates == nil {
+		r.dates = make(map[int]bool)
+	}
+
+	r.dates[col] = r.isDateCell(col, row)
+	return r.dates[col]
+}
+
+func (r *excelSheetReader) isDateCell(col, row int) bool {
+	cell, err := excelize.CoordinatesToCellName(r.colOffset()+col+1, row)
+	if err != nil {
+		return false
+	}
+	if cellType, err := r.file.GetCellType(r.sheet, cell); err != nil || (cellType != excelize.CellTypeNumber && cellType != excelize.CellTypeUnset) {
+		return false
+	}
+
+	styleID, err := r.file.GetCellStyle(r.sheet, cell)
+	if err != nil {
+		return false
+	}
+	style, err := r.file.GetStyle(styleID)
+	if err != nil {
+		return false
+	}
+
+	return isDateNumFmt(style.NumFmt, style.CustomNumFmt)
+}
+
+func (r *excelSheetReader) formatDate(value string) string {
+	serial, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+
+	t, err := excelize.ExcelDateToTime(serial, r.date1904)
+	if err != nil {
+		return value
+	}
+
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format(time.RFC3339)
+}
+
+// isDateNumFmt reports whether a built-in or custom number format displays
+// a date or time.
+func isDateNumFmt(id int, custom *string) bool {
+	switch {
+	case id >= 14 && id <= 22, id >= 27 && id <= 36, id >= 45 && id <= 47, id >= 50 && id <= 58:
+		return true
+	case custom == nil:
+		return false
+	}
+
+	// Ignore quoted literals and bracketed sections such as colours
+	var format strings.Builder
+	quoted, bracketed := false, false
+	for _, c := range strings.ToLower(*custom) {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '[':
+			bracketed = true
+		case c == ']':
+			bracketed = false
+		case !bracketed:
+			format.WriteRune(c)
+		}
+	}
+
+	return strings.ContainsAny(format.String(), "ydh")
+}
@@ -0,0 +1,142 @@
+package profiler
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func createTestWorkbook(t *testing.T) string {
+	t.Helper()
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	f.SetSheetName("Sheet1", "Orders")
+	orders := [][]interface{}{
+		{"order_id", "placed", "amount", nil},
+		{1, time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), 19.99, "gift"},
+		{2, time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC), 5.5, nil},
+		{3, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), 120, "rush"},
+	}
+	for i, row := range orders {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := f.SetSheetRow("Orders", cell, &row); err != nil {
+			t.Fatalf("Failed to write row: %v", err)
+		}
+	}
+
+	if _, err := f.NewSheet("Customers"); err != nil {
+		t.Fatalf("Failed to add sheet: %v", err)
+	}
+	customers := [][]interface{}{
+		{"name", "country"},
+		{"Ann", "US"},
+		{"Bob", "DE"},
+	}
+	for i, row := range customers {
+		// Leave a blank row above the header
+		cell, _ := excelize.CoordinatesToCellName(1, i+3)
+		if err := f.SetSheetRow("Customers", cell, &row); err != nil {
+			t.Fatalf("Failed to write row: %v", err)
+		}
+	}
+
+	if _, err := f.NewSheet("Empty"); err != nil {
+		t.Fatalf("Failed to add sheet: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "sales.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("Failed to save workbook: %v", err)
+	}
+	return path
+}
+
+func TestProfileExcelSheet(t *testing.T) {
+	path := createTestWorkbook(t)
+
+	profile, err := ProfileDatasetWithOptions(path, Options{Sheet: "Orders"})
+	if err != nil {
+		t.Fatalf("Failed to profile sheet: %v", err)
+	}
+
+	if profile.Format != "Excel" || profile.Table != "Orders" || profile.Filename != "sales.xlsx.Orders" {
+		t.Errorf("Unexpected profile identity: %s %s %s", profile.Format, profile.Table, profile.Filename)
+	}
+	if profile.RowCount != 3 || profile.ColumnCount != 4 {
+		t.Errorf("Expected 3 rows and 4 columns, got %d and %d", profile.RowCount, profile.ColumnCount)
+	}
+
+	if amount := profile.Columns["amount"]; amount == nil || !amount.IsNumeric {
+		t.Error("Expected a numeric amount column")
+	}
+	if placed := profile.Columns["placed"]; placed == nil || !placed.IsDateTime {
+		t.Errorf("Expected date-formatted cells to be profiled as dates, got %+v", placed)
+	}
+	if notes := profile.Columns["D"]; notes == nil || notes.MissingCount != 1 {
+		t.Errorf("Expected a blank header cell to be named after its column, got %v", profile.Columns)
+	}
+}
+
+func TestProfileExcelSheetSelection(t *testing.T) {
+	path := createTestWorkbook(t)
+
+	if _, err := ProfileDataset(path); err == nil || !strings.Contains(err.Error(), "--sheet") {
+		t.Errorf("Expected an error asking for --sheet, got %v", err)
+	}
+	if _, err := ProfileDatasetWithOptions(path, Options{Sheet: "Missing"}); err == nil {
+		t.Error("Expected an error for a missing sheet")
+	}
+	if _, err := ProfileDatasetWithOptions("data.csv", Options{Sheet: "Orders"}); err == nil {
+		t.Error("Expected --sheet to be rejected for a CSV file")
+	}
+}
+
+func TestProfileExcelSheets(t *testing.T) {
+	path := createTestWorkbook(t)
+
+	profiles, err := ProfileExcelSheets(context.Background(), path, Options{})
+	if err != nil {
+		t.Fatalf("Failed to profile workbook: %v", err)
+	}
+
+	if len(profiles) != 2 {
+		t.Fatalf("Expected the empty sheet to be skipped, got %d profiles", len(profiles))
+	}
+	if profiles[0].Table != "Orders" || profiles[1].Table != "Customers" {
+		t.Errorf("Expected sheets in tab order, got %s and %s", profiles[0].Table, profiles[1].Table)
+	}
+	if profiles[1].RowCount != 2 || profiles[1].Columns["country"] == nil {
+		t.Errorf("Expected the Customers header below the blank row, got %d rows and %v", profiles[1].RowCount, profiles[1].Columns)
+	}
+}
+
+func TestIsDateNumFmt(t *testing.T) {
+	custom := func(format string) *string { return &format }
+
+	tests := []struct {
+		id     int
+		custom *string
+		want   bool
+	}{
+		{14, nil, true},
+		{22, nil, true},
+		{0, nil, false},
+		{4, nil, false},
+		{164, custom("yyyy-mm-dd"), true},
+		{164, custom("[Red]#,##0.00"), false},
+		{164, custom(`0.00" days"`), false},
+		{164, custom("hh:mm"), true},
+	}
+
+	for _, tt := range tests {
+		if got := isDateNumFmt(tt.id, tt.custom); got != tt.want {
+			t.Errorf("isDateNumFmt(%d, %v): expected %v, got %v", tt.id, tt.custom, tt.want, got)
+		}
+	}
+}
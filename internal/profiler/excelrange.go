@@ -0,0 +1,183 @@
+package profiler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// excelRange is the part of a sheet to profile: an Excel table, a defined
+// name or a cell reference. Zero bounds are open, so A:F covers every row
+// of columns A to F.
+type excelRange struct {
+	kind     string // table, named range or range
+	name     string
+	sheet    string
+	ref      string
+	firstCol int
+	firstRow int
+	lastCol  int
+	lastRow  int
+}
+
+// label names the range in profile names and notes, e.g. Table1 or A1:F500.
+func (r *excelRange) label() string {
+	if r.kind == "range" {
+		return r.ref
+	}
+	return r.name
+}
+
+func (r *excelRange) note() string {
+	if r.kind == "range" {
+		return fmt.Sprintf("Profiled range %s!%s", r.sheet, r.ref)
+	}
+	return fmt.Sprintf("Profiled %s %s (%s!%s)", r.kind, r.name, r.sheet, r.ref)
+}
+
+// resolveExcelRange looks name up as a cell reference such as A1:F5000 or
+// Orders!A1:F5000, then as a table, then as a defined name. sheet is the
+// sheet chosen with --sheet, if any; an unqualified cell reference needs
+// one unless the workbook has a single sheet.
+func resolveExcelRange(f *excelize.File, name, sheet string) (*excelRange, error) {
+	sheets := f.GetSheetList()
+
+	if refSheet, ref := splitSheetRef(name); isCellRef(ref) {
+		if refSheet == "" {
+			refSheet = sheet
+		}
+		if refSheet == "" {
+			if len(sheets) != 1 {
+				return nil, fmt.Errorf("workbook has %d sheets, choose one for range %s with --sheet: %s", len(sheets), ref, strings.Join(sheets, ", "))
+			}
+			refSheet = sheets[0]
+		}
+		return newExcelRange("range", ref, refSheet, ref, sheets, sheet)
+	}
+
+	for _, s := range sheets {
+		tables, err := f.GetTables(s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tables of sheet %s: %w", s, err)
+		}
+		for _, table := range tables {
+			if strings.EqualFold(table.Name, name) {
+				return newExcelRange("table", table.Name, s, table.Range, sheets, sheet)
+			}
+		}
+	}
+
+	var match *excelize.DefinedName
+	for _, defined := range f.GetDefinedName() {
+		if !strings.EqualFold(defined.Name, name) {
+			continue
+		}
+		// A name scoped to the chosen sheet wins over a workbook-wide one
+		if match == nil || (sheet != "" && defined.Scope == sheet) {
+			defined := defined
+			match = &defined
+		}
+	}
+	if match != nil {
+		refSheet, ref := splitSheetRef(strings.TrimPrefix(match.RefersTo, "="))
+		if refSheet == "" || !isCellRef(ref) {
+			return nil, fmt.Errorf("named range %s refers to %s, only a single cell range is supported", match.Name, match.RefersTo)
+		}
+		return newExcelRange("named range", match.Name, refSheet, ref, sheets, sheet)
+	}
+
+	return nil, fmt.Errorf("range %s not found in Excel workbook: use a table, a defined name or a cell range such as A1:F5000", name)
+}
+
+func newExcelRange(kind, name, sheet, ref string, sheets []string, chosen string) (*excelRange, error) {
+	if !containsString(sheets, sheet) {
+		return nil, fmt.Errorf("sheet %s of %s %s not found in Excel workbook (sheets: %s)", sheet, kind, name, strings.Join(sheets, ", "))
+	}
+	if chosen != "" && chosen != sheet {
+		return nil, fmt.Errorf("%s %s is on sheet %s, not %s", kind, name, sheet, chosen)
+	}
+
+	r := &excelRange{kind: kind, name: name, sheet: sheet, ref: strings.ReplaceAll(ref, "$", "")}
+
+	first, last, ok := strings.Cut(r.ref, ":")
+	if !ok {
+		last = first
+	}
+	var err error
+	if r.firstCol, r.firstRow, err = parseCellBound(first); err != nil {
+		return nil, fmt.Errorf("invalid range %s: %w", ref, err)
+	}
+	if r.lastCol, r.lastRow, err = parseCellBound(last); err != nil {
+		return nil, fmt.Errorf("invalid range %s: %w", ref, err)
+	}
+	if r.firstCol > r.lastCol {
+		r.firstCol, r.lastCol = r.lastCol, r.firstCol
+	}
+	if r.firstRow > r.lastRow {
+		r.firstRow, r.lastRow = r.lastRow, r.firstRow
+	}
+
+	return r, nil
+}
+
+// parseCellBound parses a cell such as F500, a column such as F or a row
+// such as 500, returning 0 for the part that is left open.
+func parseCellBound(cell string) (int, int, error) {
+	letters := strings.TrimRight(cell, "0123456789")
+	digits := cell[len(letters):]
+
+	col, row := 0, 0
+	var err error
+	if letters != "" {
+		if col, err = excelize.ColumnNameToNumber(letters); err != nil {
+			return 0, 0, err
+		}
+	}
+	if digits != "" {
+		if row, err = strconv.Atoi(digits); err != nil || row < 1 {
+			return 0, 0, fmt.Errorf("invalid row %s", digits)
+		}
+	}
+	return col, row, nil
+}
+
+// isCellRef reports whether ref looks like A1:F5000, $A$1:$F$5000, A:F or
+// 1:500 rather than a name.
+func isCellRef(ref string) bool {
+	first, last, ok := strings.Cut(strings.ReplaceAll(ref, "$", ""), ":")
+	if !ok {
+		return false
+	}
+	return isCellBound(first) && isCellBound(last)
+}
+
+func isCellBound(cell string) bool {
+	letters := strings.TrimRight(cell, "0123456789")
+	digits := cell[len(letters):]
+	if cell == "" || len(letters) > 3 {
+		return false
+	}
+	for _, c := range letters {
+		if (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') {
+			return false
+		}
+	}
+	return digits == "" || digits[0] != '0'
+}
+
+// splitSheetRef splits Orders!A1:F500 or 'Q1 Sales'!A1:F500 into the sheet
+// and the reference.
+func splitSheetRef(ref string) (string, string) {
+	i := strings.LastIndex(ref, "!")
+	if i < 0 {
+		return "", ref
+	}
+
+	sheet := ref[:i]
+	if len(sheet) >= 2 && strings.HasPrefix(sheet, "'") && strings.HasSuffix(sheet, "'") {
+		sheet = strings.ReplaceAll(sheet[1:len(sheet)-1], "''", "'")
+	}
+	return sheet, ref[i+1:]
+}
@@ -0,0 +1,177 @@
+package profiler
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// createRangeWorkbook writes a report-style sheet: a title, a table of sales
+// at C3:E6 with a comment column beside it, and a total row further down.
+func createRangeWorkbook(t *testing.T) string {
+	t.Helper()
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	f.SetSheetName("Sheet1", "Report")
+	cells := map[string]interface{}{
+		"A1": "Quarterly sales report",
+		"C3": "region", "D3": "units", "E3": "price", "F3": "comment",
+		"C4": "North", "D4": 10, "E4": 2.5, "F4": "checked",
+		"C5": "South", "D5": 20, "E5": 3.5,
+		"C6": "East", "D6": 30, "E6": 4.5,
+		"C8": "Total", "D8": 60,
+	}
+	for cell, value := range cells {
+		if err := f.SetCellValue("Report", cell, value); err != nil {
+			t.Fatalf("Failed to write cell: %v", err)
+		}
+	}
+
+	if err := f.AddTable("Report", &excelize.Table{Range: "C3:E6", Name: "Sales"}); err != nil {
+		t.Fatalf("Failed to add table: %v", err)
+	}
+	if err := f.SetDefinedName(&excelize.DefinedName{Name: "Regions", RefersTo: "Report!$C$3:$D$6"}); err != nil {
+		t.Fatalf("Failed to add defined name: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "report.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("Failed to save workbook: %v", err)
+	}
+	return path
+}
+
+func TestProfileExcelRange(t *testing.T) {
+	path := createRangeWorkbook(t)
+
+	tests := []struct {
+		name     string
+		opts     Options
+		filename string
+		rows     int
+		columns  []string
+		note     string
+	}{
+		{"table", Options{Range: "Sales"}, "report.xlsx.Report.Sales", 3, []string{"region", "units", "price"}, "Profiled table Sales (Report!C3:E6)"},
+		{"table case-insensitive", Options{Range: "sales"}, "report.xlsx.Report.Sales", 3, []string{"region", "units", "price"}, ""},
+		{"defined name", Options{Range: "Regions"}, "report.xlsx.Report.Regions", 3, []string{"region", "units"}, "Profiled named range Regions (Report!C3:D6)"},
+		{"cell range", Options{Range: "C3:D5"}, "report.xlsx.Report.C3:D5", 2, []string{"region", "units"}, "Profiled range Report!C3:D5"},
+		{"absolute cell range", Options{Range: "$C$3:$D$5", Sheet: "Report"}, "report.xlsx.Report.C3:D5", 2, []string{"region", "units"}, ""},
+		{"columns", Options{Range: "Report!C:E"}, "report.xlsx.Report.C:E", 4, []string{"region", "units", "price"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := ProfileDatasetWithOptions(path, tt.opts)
+			if err != nil {
+				t.Fatalf("Failed to profile range: %v", err)
+			}
+
+			if profile.Filename != tt.filename || profile.Table != "Report" {
+				t.Errorf("Unexpected profile identity: %s %s", profile.Filename, profile.Table)
+			}
+			if profile.RowCount != tt.rows {
+				t.Errorf("Expected %d rows, got %d", tt.rows, profile.RowCount)
+			}
+			if profile.ColumnCount != len(tt.columns) {
+				t.Errorf("Expected %d columns, got %d", len(tt.columns), profile.ColumnCount)
+			}
+			for _, name := range tt.columns {
+				if _, ok := profile.Columns[name]; !ok {
+					t.Errorf("Expected column %s", name)
+				}
+			}
+			if tt.note != "" && !containsString(profile.Notes, tt.note) {
+				t.Errorf("Expected note %q, got %v", tt.note, profile.Notes)
+			}
+		})
+	}
+}
+
+func TestProfileExcelRangeErrors(t *testing.T) {
+	path := createRangeWorkbook(t)
+
+	tests := []struct {
+		name string
+		path string
+		opts Options
+		want string
+	}{
+		{"unknown name", path, Options{Range: "Missing"}, "range Missing not found"},
+		{"wrong sheet", path, Options{Range: "Sales", Sheet: "Other"}, "table Sales is on sheet Report, not Other"},
+		{"unknown sheet", path, Options{Range: "Other!A1:B2"}, "sheet Other of range A1:B2 not found"},
+		{"not Excel", "data.csv", Options{Range: "A1:B2"}, "only supported for Excel workbooks"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ProfileDatasetWithOptions(tt.path, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestProfileExcelRangeNeedsSheet(t *testing.T) {
+	path := createTestWorkbook(t)
+
+	_, err := ProfileDatasetWithOptions(path, Options{Range: "A1:B3"})
+	if err == nil || !strings.Contains(err.Error(), "choose one for range A1:B3 with --sheet") {
+		t.Errorf("Expected an error asking for --sheet, got %v", err)
+	}
+
+	profile, err := ProfileDatasetWithOptions(path, Options{Range: "A1:B3", Sheet: "Customers"})
+	if err != nil {
+		t.Fatalf("Failed to profile range: %v", err)
+	}
+	// The range starts above the blank first row of the sheet
+	if profile.RowCount != 0 || profile.ColumnCount != 2 {
+		t.Errorf("Expected a header-only range of 2 columns, got %d rows and %d columns", profile.RowCount, profile.ColumnCount)
+	}
+}
+
+func TestIsCellRef(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want bool
+	}{
+		{"A1:F5000", true},
+		{"$A$1:$F$5000", true},
+		{"a1:f10", true},
+		{"A:F", true},
+		{"1:500", true},
+		{"Table1", false},
+		{"A1", false},
+		{"Sales:Data", false},
+		{"A0:B2", false},
+	}
+
+	for _, tt := range tests {
+		if got := isCellRef(tt.ref); got != tt.want {
+			t.Errorf("isCellRef(%q) = %v, want %v", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestSplitSheetRef(t *testing.T) {
+	tests := []struct {
+		ref, sheet, rest string
+	}{
+		{"A1:B2", "", "A1:B2"},
+		{"Orders!A1:B2", "Orders", "A1:B2"},
+		{"'Q1 Sales'!$A$1:$B$2", "Q1 Sales", "$A$1:$B$2"},
+		{"'Bob''s'!A:B", "Bob's", "A:B"},
+	}
+
+	for _, tt := range tests {
+		sheet, rest := splitSheetRef(tt.ref)
+		if sheet != tt.sheet || rest != tt.rest {
+			t.Errorf("splitSheetRef(%q) = %q, %q, want %q, %q", tt.ref, sheet, rest, tt.sheet, tt.rest)
+		}
+	}
+}
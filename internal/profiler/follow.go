@@ -0,0 +1,356 @@
+package profiler
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/remote"
+)
+
+// followChunk is the most bytes read from a followed file at once.
+const followChunk = 1 << 20
+
+// followSniffLines is the number of leading lines the delimiter of a
+// followed CSV file is detected from.
+const followSniffLines = 50
+
+// FollowOptions tune Follow.
+type FollowOptions struct {
+	Window   int           // records in the latest window
+	Interval time.Duration // how often the file is checked for appended records
+}
+
+func DefaultFollowOptions() FollowOptions {
+	return FollowOptions{Window: 1000, Interval: 2 * time.Second}
+}
+
+// FollowUpdate is the state of a followed file after records were appended.
+type FollowUpdate struct {
+	Window   *DatasetProfile // profile of the latest Window records
+	Previous *DatasetProfile // profile of the Window records before them, nil until the file holds two windows
+	Records  int             // records read, including those in the file when following started
+	Reset    bool            // the file was truncated or replaced and is read again from its start
+	Time     time.Time
+}
+
+// Follow reads a growing CSV, TSV or JSON Lines file like tail -f: the
+// records already in the file, then the records appended to it, checked
+// every Interval until ctx is done. Each time records arrive, update gets a
+// profile of the latest window and of the window before it. Only complete
+// lines are read, so a record must not span lines. Returning an error from
+// update stops following.
+func Follow(ctx context.Context, filePath string, opts Options, follow FollowOptions, update func(FollowUpdate) error) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	if follow.Window <= 0 {
+		return fmt.Errorf("follow window must be positive: %d", follow.Window)
+	}
+	if follow.Interval <= 0 {
+		return fmt.Errorf("follow interval must be positive: %s", follow.Interval)
+	}
+
+	format := fileFormat(filePath, opts)
+	switch {
+	case filePath == StdinSource || remote.IsURL(filePath) || tableFormat(filePath, opts) != "" ||
+		IsSQLite(filePath) || IsExcel(filePath) || IsArchive(filePath) || compressionExt(filePath) != "":
+		return fmt.Errorf("--follow is only supported for local uncompressed files: %s", filePath)
+	case format != FormatCSV && format != FormatTSV && format != FormatJSONL:
+		return fmt.Errorf("--follow is only supported for CSV, TSV and JSON Lines files: %s", filePath)
+	case opts.sampling() || opts.SkipRows > 0 || opts.SkipFooter > 0 || opts.SkipBadRows || opts.MaxBytes > 0 || opts.Parallel > 1:
+		return fmt.Errorf("--sample, --skip-rows, --skip-footer, --skip-bad-rows, --range and --parallel do not combine with --follow")
+	case opts.Encoding != "" || opts.Quote != 0:
+		return fmt.Errorf("--encoding and --quote do not combine with --follow")
+	case format == FormatJSONL && (opts.Delimiter != 0 || opts.Comment != 0):
+		return fmt.Errorf("--delimiter, --quote and --comment are not supported for JSON Lines")
+	}
+
+	f := &follower{path: filePath, format: format, opts: opts, window: follow.Window}
+	defer f.close()
+
+	ticker := time.NewTicker(follow.Interval)
+	defer ticker.Stop()
+
+	first := true
+	for {
+		read, err := f.poll()
+		if err != nil {
+			return err
+		}
+
+		if f.started() && (first || read > 0 || f.reset) {
+			first = false
+			u, err := f.update()
+			if err != nil {
+				return err
+			}
+			f.reset = false
+			if err := update(u); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// follower holds the read position and the latest two windows of records of
+// a followed file.
+type follower struct {
+	path   string
+	format string
+	opts   Options
+	window int
+
+	file    *os.File
+	info    os.FileInfo
+	offset  int64
+	partial []byte // a trailing line still being written
+	reset   bool   // the file was started over since the last update
+
+	header  []string
+	comma   rune
+	rows    [][]string     // CSV and TSV records
+	objects []*jsonlRecord // JSON Lines records
+	keys    map[string]int // JSON Lines keys in the order first seen
+	total   int
+}
+
+func (f *follower) started() bool {
+	return f.header != nil || f.keys != nil
+}
+
+func (f *follower) close() {
+	if f.file != nil {
+		f.file.Close()
+	}
+}
+
+// poll reads the complete lines appended since the last call, starting over
+// when the file was truncated or replaced. A file that is briefly missing,
+// as while it is rotated, is waited for.
+func (f *follower) poll() (int, error) {
+	info, err := os.Stat(f.path)
+	if errors.Is(err, os.ErrNotExist) && f.file != nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get file stats: %w", err)
+	}
+
+	if f.file != nil && (!os.SameFile(info, f.info) || info.Size() < f.offset) {
+		f.close()
+		*f = follower{path: f.path, format: f.format, opts: f.opts, window: f.window, reset: true}
+	}
+	if f.file == nil {
+		if f.file, err = os.Open(f.path); err != nil {
+			return 0, fmt.Errorf("failed to open file: %w", err)
+		}
+		f.info = info
+	}
+
+	read := 0
+	buf := make([]byte, followChunk)
+	for {
+		n, err := f.file.ReadAt(buf, f.offset)
+		if n > 0 {
+			f.offset += int64(n)
+			data := append(f.partial, buf[:n]...)
+			end := bytes.LastIndexByte(data, '\n') + 1
+			f.partial = append([]byte(nil), data[end:]...)
+
+			count, err := f.add(data[:end])
+			if err != nil {
+				return 0, err
+			}
+			read += count
+		}
+		if err == io.EOF || n == 0 {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read file: %w", err)
+		}
+	}
+
+	return read, nil
+}
+
+// add parses complete lines and keeps the latest two windows of records.
+func (f *follower) add(lines []byte) (int, error) {
+	if len(lines) == 0 {
+		return 0, nil
+	}
+	if f.format == FormatJSONL {
+		return f.addJSONL(lines)
+	}
+
+	if f.header == nil {
+		lines = bytes.TrimPrefix(lines, []byte("\ufeff"))
+		f.comma = f.opts.Delimiter
+		if f.comma == 0 && f.format == FormatTSV {
+			f.comma = '\t'
+		}
+		if f.comma == 0 {
+			sniff := bytes.SplitN(lines, []byte("\n"), followSniffLines+1)
+			f.comma = sniffDelimiter(sniff[:min(len(sniff), followSniffLines)], f.opts.Comment)
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(lines))
+	reader.Comma = f.comma
+	reader.Comment = f.opts.Comment
+	reader.FieldsPerRecord = len(f.header)
+
+	count := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("error reading %s record %d: %w", strings.ToUpper(f.format), f.total+1, err)
+		}
+
+		if f.header == nil {
+			f.header = record
+			continue
+		}
+		f.rows = append(f.rows, record)
+		if len(f.rows) > 2*f.window {
+			f.rows = f.rows[len(f.rows)-2*f.window:]
+		}
+		f.total++
+		count++
+	}
+}
+
+func (f *follower) addJSONL(lines []byte) (int, error) {
+	if f.keys == nil {
+		f.keys = make(map[string]int)
+	}
+
+	count := 0
+	for _, line := range bytes.Split(lines, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		record, err := decodeJSONObject(line)
+		if err != nil {
+			return 0, fmt.Errorf("error reading JSON Lines record %d: %w", f.total+1, err)
+		}
+		for _, key := range record.keys {
+			if _, ok := f.keys[key]; !ok {
+				f.keys[key] = len(f.keys)
+			}
+		}
+
+		f.objects = append(f.objects, record)
+		if len(f.objects) > 2*f.window {
+			f.objects = f.objects[len(f.objects)-2*f.window:]
+		}
+		f.total++
+		count++
+	}
+	return count, nil
+}
+
+// update profiles the latest window, and the window before it once there
+// is a full one.
+func (f *follower) update() (FollowUpdate, error) {
+	u := FollowUpdate{Records: f.total, Reset: f.reset, Time: time.Now()}
+
+	held := len(f.rows)
+	if f.format == FormatJSONL {
+		held = len(f.objects)
+	}
+	split := max(held-f.window, 0)
+
+	var err error
+	if u.Window, err = f.profile(split, held); err != nil {
+		return u, err
+	}
+	if split == f.window {
+		if u.Previous, err = f.profile(0, split); err != nil {
+			return u, err
+		}
+	}
+	return u, nil
+}
+
+// profile profiles the records held from index start to end.
+func (f *follower) profile(start, end int) (*DatasetProfile, error) {
+	name := filepath.Base(f.path)
+	startTime := time.Now()
+
+	var header []string
+	var rows [][]string
+	if f.format == FormatJSONL {
+		header, rows = f.jsonlRows(f.objects[start:end])
+	} else {
+		header, rows = f.header, f.rows[start:end]
+	}
+
+	profile := newDatasetProfile(name, f.offset, strings.ToUpper(f.format), header)
+	next := func() ([]string, error) {
+		if len(rows) == 0 {
+			return nil, io.EOF
+		}
+		record := rows[0]
+		rows = rows[1:]
+		return record, nil
+	}
+	if err := profileRows(profile, header, next, f.opts); err != nil {
+		return nil, err
+	}
+	suppressRareValues(profile, f.opts.KAnonymity)
+
+	profile.ProcessingTime = time.Since(startTime)
+	return profile, nil
+}
+
+// jsonlRows turns JSON Lines records into rows, with a column for every key
+// they hold, in the order the keys were first seen in the file.
+func (f *follower) jsonlRows(objects []*jsonlRecord) ([]string, [][]string) {
+	header := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, record := range objects {
+		for _, key := range record.keys {
+			if !seen[key] {
+				seen[key] = true
+				header = append(header, key)
+			}
+		}
+	}
+	sort.Slice(header, func(i, j int) bool { return f.keys[header[i]] < f.keys[header[j]] })
+
+	index := make(map[string]int, len(header))
+	for i, key := range header {
+		index[key] = i
+	}
+
+	rows := make([][]string, len(objects))
+	for i, record := range objects {
+		values := make([]string, len(header))
+		for key, value := range record.values {
+			values[index[key]] = value
+		}
+		rows[i] = values
+	}
+	return header, rows
+}
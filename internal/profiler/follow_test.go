@@ -0,0 +1,151 @@
+package profiler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// followFile follows path in the background, returning the updates as they
+// arrive. Following stops when the test ends.
+func followFile(t *testing.T, path string, opts Options, window int) <-chan FollowUpdate {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan FollowUpdate, 100)
+	done := make(chan error, 1)
+	go func() {
+		done <- Follow(ctx, path, opts, FollowOptions{Window: window, Interval: 10 * time.Millisecond}, func(u FollowUpdate) error {
+			updates <- u
+			return nil
+		})
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Follow failed: %v", err)
+		}
+	})
+	return updates
+}
+
+func nextUpdate(t *testing.T, updates <-chan FollowUpdate) FollowUpdate {
+	t.Helper()
+
+	select {
+	case u := <-updates:
+		return u
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for an update")
+		return FollowUpdate{}
+	}
+}
+
+func appendFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatalf("Failed to append: %v", err)
+	}
+}
+
+func TestFollowCSV(t *testing.T) {
+	var content strings.Builder
+	content.WriteString("id;amount\n")
+	for i := 1; i <= 15; i++ {
+		fmt.Fprintf(&content, "%d;%d\n", i, 10+i%3)
+	}
+	path := writeTestFile(t, "data.csv", content.String())
+
+	updates := followFile(t, path, Options{}, 10)
+
+	// The records already there fill the window but not two of them
+	u := nextUpdate(t, updates)
+	if u.Records != 15 || u.Window.RowCount != 10 || u.Previous != nil || u.Window.Columns["amount"].DataType != "integer" {
+		t.Fatalf("Unexpected first update: %d records, window %+v, previous %v", u.Records, u.Window, u.Previous)
+	}
+	if mean := u.Window.Columns["id"].Mean; mean != 10.5 {
+		t.Errorf("Expected the window to hold ids 6 to 15, got mean %f", mean)
+	}
+
+	// A partial line waits for its end
+	var appended strings.Builder
+	for i := 16; i <= 20; i++ {
+		fmt.Fprintf(&appended, "%d;%d\n", i, 500+i%3)
+	}
+	appendFile(t, path, appended.String()+"21;5")
+
+	u = nextUpdate(t, updates)
+	if u.Records != 20 || u.Previous == nil || u.Previous.RowCount != 10 {
+		t.Fatalf("Expected two full windows after 20 records, got %d records and previous %v", u.Records, u.Previous)
+	}
+	if mean := u.Window.Columns["id"].Mean; mean != 15.5 {
+		t.Errorf("Expected the window to hold ids 11 to 20, got mean %f", mean)
+	}
+
+	appendFile(t, path, "01\n")
+	u = nextUpdate(t, updates)
+	if u.Records != 21 || u.Window.Columns["id"].Max != float64(21) {
+		t.Errorf("Expected the completed line to be read, got %d records and max id %v", u.Records, u.Window.Columns["id"].Max)
+	}
+
+	// Truncating the file starts over from its header
+	if err := os.WriteFile(path, []byte("id;amount\n1;2\n"), 0644); err != nil {
+		t.Fatalf("Failed to truncate: %v", err)
+	}
+	u = nextUpdate(t, updates)
+	if !u.Reset || u.Records != 1 || u.Window.RowCount != 1 {
+		t.Errorf("Expected a reset with one record, got %+v", u)
+	}
+}
+
+func TestFollowJSONL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	if err := os.WriteFile(path, []byte("{\"level\": \"info\", \"ms\": 12}\n{\"level\": \"warn\", \"ms\": 30}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	updates := followFile(t, path, Options{}, 2)
+
+	u := nextUpdate(t, updates)
+	if u.Window.Format != "JSONL" || u.Window.ColumnCount != 2 {
+		t.Fatalf("Expected a JSONL window of 2 columns, got %s with %d", u.Window.Format, u.Window.ColumnCount)
+	}
+
+	// Windows have the keys of their records, in the order first seen
+	appendFile(t, path, "{\"user\": \"ann\", \"level\": \"error\"}\n{\"level\": \"error\", \"user\": \"bob\"}\n")
+	u = nextUpdate(t, updates)
+	if _, ok := u.Window.Columns["ms"]; ok || u.Window.Columns["user"] == nil || u.Window.Columns["user"].Position != 1 {
+		t.Errorf("Expected the latest window to hold level and user, got %+v", u.Window.Columns)
+	}
+	if u.Previous == nil || u.Previous.Columns["ms"] == nil {
+		t.Errorf("Expected the previous window to hold ms, got %+v", u.Previous)
+	}
+}
+
+func TestFollowUnsupported(t *testing.T) {
+	for name, tc := range map[string]struct {
+		path string
+		opts Options
+	}{
+		"stdin":      {StdinSource, Options{}},
+		"compressed": {"data.csv.gz", Options{}},
+		"parquet":    {"data.parquet", Options{}},
+		"sample":     {"data.csv", Options{SampleSize: 10}},
+		"encoding":   {"data.csv", Options{Encoding: "latin1"}},
+	} {
+		err := Follow(context.Background(), tc.path, tc.opts, DefaultFollowOptions(), func(FollowUpdate) error { return nil })
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
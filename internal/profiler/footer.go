@@ -0,0 +1,145 @@
+package profiler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// footerScan is the number of trailing records inspected for total or
+// summary rows.
+const footerScan = 3
+
+var footerLabels = map[string]bool{
+	"total":       true,
+	"totals":      true,
+	"grand total": true,
+	"subtotal":    true,
+	"sub total":   true,
+	"sum":         true,
+	"average":     true,
+	"avg":         true,
+}
+
+type heldRecord struct {
+	record []string
+	err    error
+}
+
+// footerFilter holds back the last records of a delimited source so that
+// trailing summary rows can be dropped once the end is reached. With an
+// explicit count exactly that many records are dropped; otherwise trailing
+// records are dropped while they look like totals ("TOTAL,123456,,") or
+// have a different number of fields from the header.
+type footerFilter struct {
+	read     func() ([]string, error)
+	size     int
+	explicit bool
+	held     []heldRecord
+	done     bool
+	dropped  [][]string
+}
+
+func newFooterFilter(read func() ([]string, error), skip int) *footerFilter {
+	f := &footerFilter{read: read, size: footerScan}
+	if skip > 0 {
+		f.size = skip
+		f.explicit = true
+	}
+	return f
+}
+
+func (f *footerFilter) next() ([]string, error) {
+	for !f.done && len(f.held) <= f.size {
+		record, err := f.read()
+		if err == io.EOF {
+			f.done = true
+			f.trim()
+			break
+		}
+		// The reader returns the record along with a field count error, so
+		// short footer lines can still be recognised
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, err
+		}
+		f.held = append(f.held, heldRecord{record: record, err: err})
+	}
+
+	if len(f.held) == 0 {
+		return nil, io.EOF
+	}
+
+	held := f.held[0]
+	f.held = f.held[1:]
+	if held.err != nil {
+		return nil, held.err
+	}
+	return held.record, nil
+}
+
+func (f *footerFilter) trim() {
+	end := len(f.held)
+	if f.explicit {
+		end = 0
+	} else {
+		for end > 0 && (f.held[end-1].err != nil || isFooterRow(f.held[end-1].record)) {
+			end--
+		}
+	}
+
+	for _, held := range f.held[end:] {
+		f.dropped = append(f.dropped, held.record)
+	}
+	f.held = f.held[:end]
+}
+
+func (f *footerFilter) note() string {
+	if len(f.dropped) == 0 {
+		return ""
+	}
+	if f.explicit {
+		return fmt.Sprintf("Skipped the last %d rows (--skip-footer)", len(f.dropped))
+	}
+
+	rows := make([]string, len(f.dropped))
+	for i, record := range f.dropped {
+		rows[i] = strings.Join(record, ",")
+	}
+	return fmt.Sprintf("Excluded %d footer rows from the end: %s", len(f.dropped), strings.Join(rows, " | "))
+}
+
+// isFooterRow reports whether a record is a summary row: its first non-empty
+// field is a label such as "Total" and every other non-empty field is a
+// number.
+func isFooterRow(record []string) bool {
+	labelled := false
+	for _, field := range record {
+		value := strings.TrimSpace(field)
+		if value == "" {
+			continue
+		}
+
+		if !labelled {
+			label := strings.ToLower(strings.TrimRight(value, ":"))
+			if !footerLabels[label] {
+				return false
+			}
+			labelled = true
+			continue
+		}
+
+		if !isFooterNumber(value) {
+			return false
+		}
+	}
+	return labelled
+}
+
+func isFooterNumber(value string) bool {
+	value = strings.NewReplacer(",", "", "$", "", "€", "", "£", "", "%", "").Replace(value)
+	_, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	return err == nil
+}
@@ -0,0 +1,81 @@
+package profiler
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func TestIsFooterRow(t *testing.T) {
+	tests := map[string]bool{
+		"TOTAL,123456,,":            true,
+		"Grand Total:,\"1,234.50\"": true,
+		",,Subtotal,$99.90":         true,
+		"total,abc,1":               false,
+		"Total Wine,12,3":           false,
+		"5,total,3":                 false,
+		",,,":                       false,
+	}
+
+	for line, expected := range tests {
+		record, err := csv.NewReader(strings.NewReader(line)).Read()
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", line, err)
+		}
+		if got := isFooterRow(record); got != expected {
+			t.Errorf("isFooterRow(%q): expected %v, got %v", line, expected, got)
+		}
+	}
+}
+
+func TestProfileFooter(t *testing.T) {
+	path := writeTestFile(t, "export.csv", "region,amount,notes\nnorth,100,\nsouth,200,\neast,300,\nTOTAL,600,\nGenerated by Billing\n")
+
+	profile, err := ProfileDataset(path)
+	if err != nil {
+		t.Fatalf("Failed to profile file with a footer: %v", err)
+	}
+
+	if profile.RowCount != 3 {
+		t.Errorf("Expected 3 rows without the footer, got %d", profile.RowCount)
+	}
+	if amount := profile.Columns["amount"]; amount == nil || amount.Max != 300.0 {
+		t.Errorf("Expected the total to be excluded from the amount stats, got %+v", amount)
+	}
+	if notes := strings.Join(profile.Notes, " "); !strings.Contains(notes, "Excluded 2 footer rows") || !strings.Contains(notes, "TOTAL,600,") {
+		t.Errorf("Expected a footer note, got %v", profile.Notes)
+	}
+}
+
+func TestProfileFooterNotDetected(t *testing.T) {
+	path := writeTestFile(t, "export.csv", "label,value\nsum,1\ntotal,x\ncount,3\n")
+
+	profile, err := ProfileDataset(path)
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if profile.RowCount != 3 {
+		t.Errorf("Expected every row to be kept, got %d", profile.RowCount)
+	}
+
+	// A short row in the middle is still an error
+	path = writeTestFile(t, "export.csv", "a,b\n1,2\n3\n4,5\n5,6\n6,7\n7,8\n")
+	if _, err := ProfileDataset(path); err == nil {
+		t.Error("Expected a field count error for a short row before the end")
+	}
+}
+
+func TestProfileSkipFooter(t *testing.T) {
+	path := writeTestFile(t, "export.csv", "a,b\n1,2\n3,4\n5,6\n7,8\n")
+
+	profile, err := ProfileDatasetWithOptions(path, Options{SkipFooter: 2})
+	if err != nil {
+		t.Fatalf("Failed to profile with SkipFooter: %v", err)
+	}
+	if profile.RowCount != 2 {
+		t.Errorf("Expected 2 rows, got %d", profile.RowCount)
+	}
+	if notes := strings.Join(profile.Notes, " "); !strings.Contains(notes, "Skipped the last 2 rows") {
+		t.Errorf("Expected a --skip-footer note, got %v", profile.Notes)
+	}
+}
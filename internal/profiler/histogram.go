@@ -0,0 +1,118 @@
+package profiler
+
+import (
+	"fmt"
+	"math"
+)
+
+// Histogram binning modes.
+const (
+	HistogramEqualWidth     = "equal-width"
+	HistogramEqualFrequency = "equal-frequency"
+	HistogramLog            = "log"  // equal width in log scale, for positive values
+	HistogramAuto           = "auto" // log or equal-frequency for heavily skewed columns, equal-width for others
+)
+
+const (
+	// DefaultHistogramBuckets is the number of buckets of a histogram.
+	DefaultHistogramBuckets = 10
+
+	maxHistogramBuckets = 1000
+)
+
+// HistogramBinnings lists the histogram binning modes.
+func HistogramBinnings() []string {
+	return []string{HistogramEqualWidth, HistogramEqualFrequency, HistogramLog, HistogramAuto}
+}
+
+// Histograms chooses how the histograms of numeric columns are binned.
+type Histograms struct {
+	Binning string `yaml:"binning"` // equal-width, equal-frequency, log or auto; equal-width when empty
+	Buckets int    `yaml:"buckets"` // 0 for DefaultHistogramBuckets
+}
+
+// Validate checks the binning and bucket count of h.
+func (h *Histograms) Validate() error {
+	switch h.Binning {
+	case "", HistogramEqualWidth, HistogramEqualFrequency, HistogramLog, HistogramAuto:
+	default:
+		return fmt.Errorf("unsupported histogram binning: %s (use equal-width, equal-frequency, log or auto)", h.Binning)
+	}
+	if h.Buckets < 0 || h.Buckets > maxHistogramBuckets {
+		return fmt.Errorf("histogram buckets must be between 1 and %d: %d", maxHistogramBuckets, h.Buckets)
+	}
+	return nil
+}
+
+// histogramSpec is the binning and bucket count asked for the histograms of
+// a profile, before auto and log are resolved per column.
+type histogramSpec struct {
+	binning string
+	buckets int
+}
+
+func (o Options) histogram() histogramSpec {
+	spec := histogramSpec{binning: o.histogramBinning(), buckets: o.HistogramBuckets}
+	if spec.buckets == 0 {
+		spec.buckets = DefaultHistogramBuckets
+	}
+	return spec
+}
+
+// binningFor resolves the binning of a column with the given range and
+// skewness, with a note when log-scale buckets were asked for but cannot
+// hold its values.
+func (h histogramSpec) binningFor(min, max, skewness float64, t Thresholds) (string, string) {
+	switch h.binning {
+	case HistogramAuto:
+		switch {
+		case math.Abs(skewness) <= t.SkewnessAbs:
+			return HistogramEqualWidth, ""
+		case min > 0 && max > min:
+			return HistogramLog, ""
+		default:
+			return HistogramEqualFrequency, ""
+		}
+	case HistogramLog:
+		if min <= 0 {
+			return HistogramEqualWidth, "Log-scale histogram needs positive values: equal-width buckets used"
+		}
+		return HistogramLog, ""
+	}
+	return h.binning, ""
+}
+
+// bucketBounds returns n empty buckets binned by binning, with quantile
+// bounding equal-frequency ones.
+func (s *numericStats) bucketBounds(binning string, n int, quantile func(q float64) float64) []HistogramBucket {
+	switch binning {
+	case HistogramEqualFrequency:
+		return s.quantileBounds(n, quantile)
+	case HistogramLog:
+		return s.logBounds(n)
+	default:
+		return s.histogramBounds(n)
+	}
+}
+
+// logBounds returns n buckets whose bounds grow by the same factor from the
+// min to the max, which must be positive.
+func (s *numericStats) logBounds(n int) []HistogramBucket {
+	if s.max <= s.min {
+		return s.histogramBounds(n)
+	}
+
+	low, high := math.Log(s.min), math.Log(s.max)
+	step := (high - low) / float64(n)
+	buckets := make([]HistogramBucket, n)
+	lower := s.min
+	for i := range buckets {
+		upper := math.Exp(low + float64(i+1)*step)
+		if i == n-1 {
+			upper = s.max
+		}
+		buckets[i] = HistogramBucket{LowerBound: lower, UpperBound: upper}
+		lower = upper
+	}
+	return buckets
+}
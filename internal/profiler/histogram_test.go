@@ -0,0 +1,94 @@
+package profiler
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestNumericStatsLogHistogram(t *testing.T) {
+	// 1 and 10^6, and three values halfway between each power of ten in log scale
+	stats := newNumericStats()
+	stats.add(1)
+	stats.add(1e6)
+	for i := 0; i < 6; i++ {
+		for j := 0; j < 3; j++ {
+			stats.add(math.Pow(10, float64(i)+0.5))
+		}
+	}
+
+	col := &ColumnProfile{}
+	stats.apply(col, Options{Histogram: HistogramLog, HistogramBuckets: 6}.histogram(), DefaultThresholds())
+	if col.HistogramBinning != HistogramLog || len(col.HistogramBuckets) != 6 {
+		t.Fatalf("Expected 6 log-scale buckets, got %s %v", col.HistogramBinning, col.HistogramBuckets)
+	}
+	for i, bucket := range col.HistogramBuckets {
+		want := 3
+		if i == 0 || i == len(col.HistogramBuckets)-1 {
+			want = 4
+		}
+		if math.Abs(bucket.LowerBound-math.Pow(10, float64(i))) > 1e-6*bucket.LowerBound || bucket.Count != want {
+			t.Errorf("Expected bucket %d to start at 10^%d and hold %d values, got %+v", i, i, want, bucket)
+		}
+	}
+
+	// Log-scale buckets cannot hold zero
+	stats.add(0)
+	col = &ColumnProfile{}
+	stats.apply(col, Options{Histogram: HistogramLog}.histogram(), DefaultThresholds())
+	if col.HistogramBinning != HistogramEqualWidth || len(col.HistogramBuckets) != DefaultHistogramBuckets || len(col.Notes) != 1 {
+		t.Errorf("Expected equal-width buckets with a note, got %s %v", col.HistogramBinning, col.Notes)
+	}
+}
+
+func TestNumericStatsAutoHistogram(t *testing.T) {
+	testCases := []struct {
+		name    string
+		values  func(i int) float64
+		binning string
+	}{
+		{"symmetric", func(i int) float64 { return float64(i) }, HistogramEqualWidth},
+		{"positive long tail", func(i int) float64 { return math.Pow(1.05, float64(i)) }, HistogramLog},
+		{"long tail through zero", func(i int) float64 { return math.Pow(1.05, float64(i)) - 10 }, HistogramEqualFrequency},
+	}
+
+	for _, tc := range testCases {
+		stats := newNumericStats()
+		for i := 0; i < 200; i++ {
+			stats.add(tc.values(i))
+		}
+		col := &ColumnProfile{}
+		stats.apply(col, Options{Histogram: HistogramAuto}.histogram(), DefaultThresholds())
+		if col.HistogramBinning != tc.binning {
+			t.Errorf("%s: expected %s binning for skewness %.2f, got %s", tc.name, tc.binning, col.Skewness, col.HistogramBinning)
+		}
+	}
+}
+
+func TestProfileHistogramBuckets(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("amount,weight\n")
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&b, "%.2f,%d\n", math.Pow(1.05, float64(i)), 1+i%2)
+	}
+	path := writeTestFile(t, "data.csv", b.String())
+
+	for _, opts := range []Options{
+		{Histogram: HistogramAuto, HistogramBuckets: 25},
+		{Histogram: HistogramAuto, HistogramBuckets: 25, WeightColumn: "weight"},
+	} {
+		profile, err := ProfileDatasetWithOptions(path, opts)
+		if err != nil {
+			t.Fatalf("Failed to profile: %v", err)
+		}
+		col := profile.Columns["amount"]
+		if profile.HistogramBinning != HistogramAuto || col.HistogramBinning != HistogramLog || len(col.HistogramBuckets) != 25 {
+			t.Errorf("Expected 25 log-scale buckets (weight column %q), got %s %d", opts.WeightColumn, col.HistogramBinning, len(col.HistogramBuckets))
+		}
+	}
+
+	if err := (&Histograms{Buckets: maxHistogramBuckets + 1}).Validate(); err == nil {
+		t.Error("Expected too many buckets to be rejected")
+	}
+}
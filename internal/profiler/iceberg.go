@@ -0,0 +1,268 @@
+package profiler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/remote"
+)
+
+const icebergMetadataDir = "metadata"
+
+type icebergMetadata struct {
+	FormatVersion     int               `json:"format-version"`
+	Location          string            `json:"location"`
+	CurrentSnapshotID *int64            `json:"current-snapshot-id"`
+	Snapshots         []icebergSnapshot `json:"snapshots"`
+	Schemas           []icebergSchema   `json:"schemas"`
+	CurrentSchemaID   int               `json:"current-schema-id"`
+	Schema            *icebergSchema    `json:"schema"` // format version 1
+}
+
+type icebergSnapshot struct {
+	SnapshotID   int64    `json:"snapshot-id"`
+	ManifestList string   `json:"manifest-list"`
+	Manifests    []string `json:"manifests"` // format version 1 without a manifest list
+}
+
+type icebergSchema struct {
+	SchemaID int `json:"schema-id"`
+	Fields   []struct {
+		Name string          `json:"name"`
+		Type json.RawMessage `json:"type"`
+	} `json:"fields"`
+}
+
+// Iceberg manifest content and entry status values.
+const (
+	icebergContentData   = 0
+	icebergStatusDeleted = 2
+)
+
+// readIcebergSnapshot reads the current snapshot of an Iceberg table from
+// its metadata file, manifest list and manifests. source is the table
+// directory or a metadata file, the latter for tables in a catalog.
+// Snapshots with delete files are refused: the rows they delete would
+// still be counted.
+func readIcebergSnapshot(ctx context.Context, source string) (*tableSnapshot, error) {
+	var store *tableStore
+	var metadataPath string
+
+	if strings.HasSuffix(strings.ToLower(remote.Path(source)), ".metadata.json") {
+		if remote.IsURL(source) {
+			store = newTableStore(ctx, remote.Join(source, "../.."))
+			metadataPath = source
+		} else {
+			dir := filepath.Dir(source)
+			store = newTableStore(ctx, filepath.Dir(dir))
+			metadataPath = filepath.Base(dir) + "/" + filepath.Base(source)
+		}
+	} else {
+		store = newTableStore(ctx, source)
+		name, err := findIcebergMetadata(store)
+		if err != nil {
+			return nil, err
+		}
+		metadataPath = icebergMetadataDir + "/" + name
+	}
+
+	data, err := store.readAll(metadataPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Iceberg metadata: %w", err)
+	}
+	var metadata icebergMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to read Iceberg metadata: %w", err)
+	}
+	store.location = metadata.Location
+
+	snapshot := &tableSnapshot{store: store, format: FormatIceberg}
+	schema := metadata.currentSchema()
+	if schema == nil {
+		return nil, fmt.Errorf("Iceberg metadata has no current schema")
+	}
+	for _, field := range schema.Fields {
+		var typeName string
+		if json.Unmarshal(field.Type, &typeName) != nil {
+			snapshot.skipped = append(snapshot.skipped, field.Name)
+			continue
+		}
+		snapshot.columns = append(snapshot.columns, field.Name)
+	}
+
+	current := metadata.currentSnapshot()
+	if current == nil {
+		snapshot.notes = append(snapshot.notes, "Iceberg table has no snapshot yet")
+		return snapshot, nil
+	}
+	snapshot.version = strconv.FormatInt(current.SnapshotID, 10)
+
+	manifests := current.Manifests
+	if current.ManifestList != "" {
+		if manifests, err = readIcebergManifestList(store, current.ManifestList); err != nil {
+			return nil, err
+		}
+	}
+
+	deleteFiles := 0
+	for _, manifest := range manifests {
+		deletes, err := readIcebergManifest(store, manifest, snapshot)
+		if err != nil {
+			return nil, err
+		}
+		deleteFiles += deletes
+	}
+
+	if deleteFiles > 0 {
+		return nil, fmt.Errorf("Iceberg snapshot %d has %d delete files, which are not supported: apply them with the rewrite_data_files procedure first", current.SnapshotID, deleteFiles)
+	}
+
+	snapshot.notes = append(snapshot.notes, fmt.Sprintf(
+		"Iceberg snapshot %d: %d data files", current.SnapshotID, len(snapshot.files)))
+
+	return snapshot, nil
+}
+
+// findIcebergMetadata picks the current metadata file of a table directory:
+// the one named by version-hint.text, or else the highest numbered one.
+func findIcebergMetadata(store *tableStore) (string, error) {
+	hint, err := store.readAll(icebergMetadataDir + "/version-hint.text")
+	if err == nil {
+		version := strings.TrimSpace(string(hint))
+		if strings.HasSuffix(version, ".metadata.json") {
+			return path.Base(version), nil
+		}
+		return "v" + version + ".metadata.json", nil
+	}
+	if !errors.Is(err, errTableFileMissing) {
+		return "", err
+	}
+
+	names, listed := store.list(icebergMetadataDir)
+	if !listed {
+		return "", fmt.Errorf("Iceberg table has no version-hint.text: pass the URL of its current metadata.json")
+	}
+
+	best, bestVersion := "", int64(-1)
+	for _, name := range names {
+		if !strings.HasSuffix(name, ".metadata.json") {
+			continue
+		}
+		digits := strings.TrimPrefix(name, "v")
+		if i := strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }); i > 0 {
+			digits = digits[:i]
+		}
+		if version, err := strconv.ParseInt(digits, 10, 64); err == nil && version > bestVersion {
+			best, bestVersion = name, version
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("no Iceberg metadata files found in %s", store.join(icebergMetadataDir))
+	}
+	return best, nil
+}
+
+func (m *icebergMetadata) currentSchema() *icebergSchema {
+	for i := range m.Schemas {
+		if m.Schemas[i].SchemaID == m.CurrentSchemaID {
+			return &m.Schemas[i]
+		}
+	}
+	return m.Schema
+}
+
+func (m *icebergMetadata) currentSnapshot() *icebergSnapshot {
+	if m.CurrentSnapshotID == nil || *m.CurrentSnapshotID < 0 {
+		return nil
+	}
+	for i := range m.Snapshots {
+		if m.Snapshots[i].SnapshotID == *m.CurrentSnapshotID {
+			return &m.Snapshots[i]
+		}
+	}
+	return nil
+}
+
+// readIcebergManifestList returns the manifests of a snapshot. Delete
+// manifests are kept so that delete files can be found.
+func readIcebergManifestList(store *tableStore, location string) ([]string, error) {
+	records, err := readIcebergAvro(store, location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Iceberg manifest list: %w", err)
+	}
+
+	manifests := make([]string, 0, len(records))
+	for _, record := range records {
+		if manifest, ok := record["manifest_path"].(string); ok {
+			manifests = append(manifests, manifest)
+		}
+	}
+	return manifests, nil
+}
+
+// readIcebergManifest adds the live data files of a manifest to snapshot and
+// returns how many live delete files it lists.
+func readIcebergManifest(store *tableStore, location string, snapshot *tableSnapshot) (int, error) {
+	records, err := readIcebergAvro(store, location)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read Iceberg manifest: %w", err)
+	}
+
+	deletes := 0
+	for _, entry := range records {
+		if status, _ := entry["status"].(int64); status == icebergStatusDeleted {
+			continue
+		}
+		dataFile, ok := entry["data_file"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if content, _ := dataFile["content"].(int64); content != icebergContentData {
+			deletes++
+			continue
+		}
+
+		filePath, _ := dataFile["file_path"].(string)
+		if fileFormat, _ := dataFile["file_format"].(string); !strings.EqualFold(fileFormat, "parquet") {
+			return 0, fmt.Errorf("Iceberg data files in %s format are not supported: %s", fileFormat, filePath)
+		}
+		size, _ := dataFile["file_size_in_bytes"].(int64)
+		snapshot.files = append(snapshot.files, tableFile{path: filePath, size: size})
+	}
+	return deletes, nil
+}
+
+func readIcebergAvro(store *tableStore, location string) ([]map[string]interface{}, error) {
+	data, err := store.readAll(location)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := newAvroReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]map[string]interface{}, 0)
+	for {
+		value, err := reader.next()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if record, ok := value.(map[string]interface{}); ok {
+			records = append(records, record)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package profiler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const icebergTestManifestListSchema = `{
+	"type": "record", "name": "manifest_file",
+	"fields": [
+		{"name": "manifest_path", "type": "string"},
+		{"name": "manifest_length", "type": "long"},
+		{"name": "content", "type": "int"},
+		{"name": "added_snapshot_id", "type": "long"}
+	]
+}`
+
+const icebergTestManifestSchema = `{
+	"type": "record", "name": "manifest_entry",
+	"fields": [
+		{"name": "status", "type": "int"},
+		{"name": "snapshot_id", "type": ["null", "long"]},
+		{"name": "data_file", "type": {
+			"type": "record", "name": "r2",
+			"fields": [
+				{"name": "content", "type": "int"},
+				{"name": "file_path", "type": "string"},
+				{"name": "file_format", "type": "string"},
+				{"name": "record_count", "type": "long"},
+				{"name": "file_size_in_bytes", "type": "long"}
+			]
+		}}
+	]
+}`
+
+func icebergTestEntry(status, content int64, path, format string, size int64) interface{} {
+	return map[string]interface{}{
+		"status":      status,
+		"snapshot_id": int64(7),
+		"data_file": map[string]interface{}{
+			"content":            content,
+			"file_path":          path,
+			"file_format":        format,
+			"record_count":       int64(0),
+			"file_size_in_bytes": size,
+		},
+	}
+}
+
+// writeTestIcebergTable writes a table whose metadata records it at another
+// location, as after a copy: snapshot 7 has two live data files and a
+// deleted one, plus any extra manifest entries.
+func writeTestIcebergTable(t *testing.T, extra ...interface{}) string {
+	t.Helper()
+
+	root := filepath.Join(t.TempDir(), "orders")
+	location := "s3://warehouse/db/orders"
+	sizeA := writeTableTestFile(t, filepath.Join(root, "data", "a.parquet"), 0, 6)
+	sizeB := writeTableTestFile(t, filepath.Join(root, "data", "b.parquet"), 6, 4)
+	sizeC := writeTableTestFile(t, filepath.Join(root, "data", "c.parquet"), 10, 3)
+
+	metadataDir := filepath.Join(root, icebergMetadataDir)
+	if err := os.MkdirAll(metadataDir, 0755); err != nil {
+		t.Fatalf("Failed to create metadata directory: %v", err)
+	}
+	manifest := writeTestAvro(t, icebergTestManifestSchema, "deflate", append([]interface{}{
+		icebergTestEntry(1, 0, location+"/data/a.parquet", "PARQUET", sizeA),
+		icebergTestEntry(2, 0, location+"/data/b.parquet", "PARQUET", sizeB),
+		icebergTestEntry(0, 0, location+"/data/c.parquet", "PARQUET", sizeC),
+	}, extra...))
+	manifestList := writeTestAvro(t, icebergTestManifestListSchema, "null", []interface{}{
+		map[string]interface{}{
+			"manifest_path":     location + "/metadata/m1.avro",
+			"manifest_length":   int64(len(manifest)),
+			"content":           int64(0),
+			"added_snapshot_id": int64(7),
+		},
+	})
+
+	metadata := `{
+		"format-version": 2,
+		"location": "` + location + `",
+		"current-schema-id": 1,
+		"schemas": [
+			{"schema-id": 0, "fields": [{"id": 1, "name": "id", "type": "long"}]},
+			{"schema-id": 1, "fields": [
+				{"id": 1, "name": "id", "type": "long"},
+				{"id": 2, "name": "name", "type": "string"},
+				{"id": 3, "name": "amount", "type": "double"},
+				{"id": 4, "name": "tags", "type": {"type": "list", "element-id": 5, "element": "string"}},
+				{"id": 6, "name": "note", "type": "string"}
+			]}
+		],
+		"current-snapshot-id": 7,
+		"snapshots": [
+			{"snapshot-id": 3, "manifest-list": "` + location + `/metadata/missing.avro"},
+			{"snapshot-id": 7, "manifest-list": "` + location + `/metadata/snap-7.avro"}
+		]
+	}`
+
+	for name, content := range map[string][]byte{
+		"m1.avro":           manifest,
+		"snap-7.avro":       manifestList,
+		"v1.metadata.json":  []byte(`{"format-version": 2, "location": "` + location + `"}`),
+		"v2.metadata.json":  []byte(metadata),
+		"version-hint.text": []byte("2\n"),
+	} {
+		if err := os.WriteFile(filepath.Join(metadataDir, name), content, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return root
+}
+
+func checkIcebergProfile(t *testing.T, profile *DatasetProfile) {
+	t.Helper()
+
+	if profile.Format != "Iceberg" || profile.Filename != "orders" {
+		t.Errorf("Expected an Iceberg profile of orders, got %s of %s", profile.Format, profile.Filename)
+	}
+	if profile.RowCount != 9 || profile.ColumnCount != 4 {
+		t.Errorf("Expected 9 rows and 4 columns, got %d and %d", profile.RowCount, profile.ColumnCount)
+	}
+	if id := profile.Columns["id"]; id.Min != 0.0 || id.Max != 12.0 {
+		t.Errorf("Expected ids 0 to 12 without the deleted file, got %v to %v", id.Min, id.Max)
+	}
+	if note := profile.Columns["note"]; note == nil || note.MissingCount != 9 {
+		t.Errorf("Expected the note column missing from every file, got %+v", note)
+	}
+
+	notes := strings.Join(profile.Notes, " ")
+	for _, note := range []string{
+		"Iceberg snapshot 7: 2 data files",
+		"Skipped nested or repeated columns: tags",
+	} {
+		if !strings.Contains(notes, note) {
+			t.Errorf("Expected note %q, got %v", note, profile.Notes)
+		}
+	}
+}
+
+func TestProfileIcebergTable(t *testing.T) {
+	root := writeTestIcebergTable(t)
+
+	profile, err := ProfileDataset(root)
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	checkIcebergProfile(t, profile)
+
+	// Without a version hint the highest numbered metadata file is current
+	os.Remove(filepath.Join(root, icebergMetadataDir, "version-hint.text"))
+	if profile, err = ProfileDataset(root); err != nil {
+		t.Fatalf("Failed to profile without a version hint: %v", err)
+	}
+	checkIcebergProfile(t, profile)
+
+	if profile, err = ProfileDataset(filepath.Join(root, icebergMetadataDir, "v2.metadata.json")); err != nil {
+		t.Fatalf("Failed to profile from the metadata file: %v", err)
+	}
+	checkIcebergProfile(t, profile)
+}
+
+func TestProfileIcebergErrors(t *testing.T) {
+	root := writeTestIcebergTable(t)
+	if err := os.WriteFile(filepath.Join(root, icebergMetadataDir, "version-hint.text"), []byte("1"), 0644); err != nil {
+		t.Fatalf("Failed to write version hint: %v", err)
+	}
+	if _, err := ProfileDataset(root); err == nil || !strings.Contains(err.Error(), "no current schema") {
+		t.Errorf("Expected a missing schema error, got %v", err)
+	}
+
+	if _, err := ProfileDatasetWithOptions(t.TempDir(), Options{Format: FormatIceberg}); err == nil || !strings.Contains(err.Error(), "no Iceberg metadata files") {
+		t.Errorf("Expected a missing metadata error, got %v", err)
+	}
+
+	// A position delete file, live, and an equality delete file since deleted
+	root = writeTestIcebergTable(t,
+		icebergTestEntry(1, 1, "s3://warehouse/db/orders/data/d-deletes.parquet", "PARQUET", 10),
+		icebergTestEntry(2, 2, "s3://warehouse/db/orders/data/e-deletes.parquet", "PARQUET", 10))
+	if _, err := ProfileDataset(root); err == nil || !strings.Contains(err.Error(), "Iceberg snapshot 7 has 1 delete files") {
+		t.Errorf("Expected delete files to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package profiler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// context is the context of the source being profiled, done when profiling
+// is to stop.
+func (o Options) context() context.Context {
+	if o.ctx == nil {
+		return context.Background()
+	}
+	return o.ctx
+}
+
+// untilDone wraps next to end the records, as if the source ended there,
+// once the context of opts is done, and marks profile Interrupted. A read
+// failing because the context is done, such as a canceled download or
+// query, ends the records too.
+func untilDone(profile *DatasetProfile, next func() ([]string, error), opts Options) func() ([]string, error) {
+	ctx := opts.context()
+	done := ctx.Done()
+	return func() ([]string, error) {
+		select {
+		case <-done:
+			profile.Interrupted = true
+			return nil, io.EOF
+		default:
+		}
+
+		record, err := next()
+		if err != nil && err != io.EOF && ctx.Err() != nil {
+			profile.Interrupted = true
+			return nil, io.EOF
+		}
+		return record, err
+	}
+}
+
+// stopped returns the error of a profile that stopped early because ctx is
+// done, after noting on it that the statistics cover only the rows read.
+func stopped(ctx context.Context, profile *DatasetProfile) error {
+	rows := max(profile.RowCount, profile.SourceRows) + profile.BadRows
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		profile.Notes = append(profile.Notes, fmt.Sprintf("Timed out after %d rows: the statistics cover only the rows read", rows))
+		return fmt.Errorf("timed out after %d rows: %w", rows, ctx.Err())
+	}
+	profile.Notes = append(profile.Notes, fmt.Sprintf("Interrupted after %d rows: the statistics cover only the rows read", rows))
+	return fmt.Errorf("interrupted after %d rows: %w", rows, ctx.Err())
+}
@@ -0,0 +1,85 @@
+package profiler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/events"
+)
+
+func TestUntilDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rows := 0
+	profile := &DatasetProfile{}
+	next := untilDone(profile, func() ([]string, error) {
+		rows++
+		if rows == 10 {
+			cancel()
+		}
+		return []string{"x"}, nil
+	}, Options{ctx: ctx})
+
+	read := 0
+	for {
+		if _, err := next(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		read++
+	}
+	if read != 10 || !profile.Interrupted {
+		t.Errorf("Expected 10 rows and an interrupted profile, got %d rows, interrupted %v", read, profile.Interrupted)
+	}
+
+	// A read failing once the context is done ends the records
+	profile = &DatasetProfile{}
+	next = untilDone(profile, func() ([]string, error) {
+		return nil, errors.New("connection closed")
+	}, Options{ctx: ctx})
+	if _, err := next(); err != io.EOF || !profile.Interrupted {
+		t.Errorf("Expected the end of the records, got %v, interrupted %v", err, profile.Interrupted)
+	}
+}
+
+func TestProfileDatasetContextStopped(t *testing.T) {
+	withParallelMinChunk(t, 256)
+	path := writeTestFile(t, "data.csv", parallelCSV(2000))
+
+	for _, opts := range []Options{{}, {Parallel: 4}} {
+		// Cancel as profiling starts, before the first row is read
+		ctx, cancel := context.WithCancel(context.Background())
+		stop := events.Default.Handle(func(e events.Event) {
+			if e.Kind == events.Started {
+				cancel()
+			}
+		})
+		profile, err := ProfileDatasetContext(ctx, path, opts)
+		stop()
+		cancel()
+
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("parallel %d: expected a canceled error, got %v", opts.Parallel, err)
+		}
+		if profile == nil || !profile.Interrupted || profile.RowCount >= 2000 {
+			t.Fatalf("parallel %d: expected a partial profile, got %+v", opts.Parallel, profile)
+		}
+		if note := profile.Notes[len(profile.Notes)-1]; !strings.HasPrefix(note, "Interrupted after") {
+			t.Errorf("parallel %d: expected an interrupted note, got %q", opts.Parallel, note)
+		}
+	}
+
+	// A context done before profiling starts returns no profile
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	if profile, err := ProfileDatasetContext(ctx, path, Options{}); profile != nil || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a timeout without a profile, got %v, %v", profile, err)
+	}
+}
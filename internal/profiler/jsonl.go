@@ -0,0 +1,275 @@
+package profiler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// jsonlHeaderScan is the number of leading records whose keys make up the
+// columns of a JSON Lines or XML source. Keys first seen later are not profiled.
+const jsonlHeaderScan = 1000
+
+type jsonlRecord struct {
+	keys   []string
+	values map[string]string
+}
+
+func profileJSONLFile(filePath string, opts Options) (*DatasetProfile, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file stats: %w", err)
+	}
+
+	return profileJSONL(file, filepath.Base(filePath), fileInfo.Size(), opts)
+}
+
+func profileJSONL(r io.Reader, name string, size int64, opts Options) (*DatasetProfile, error) {
+	startTime := time.Now()
+
+	if opts.SkipRows > 0 || opts.SkipFooter > 0 {
+		return nil, fmt.Errorf("--skip-rows and --skip-footer are not supported for JSON Lines")
+	}
+	if opts.Delimiter != 0 || opts.Quote != 0 || opts.Comment != 0 {
+		return nil, fmt.Errorf("--delimiter, --quote and --comment are not supported for JSON Lines")
+	}
+
+	source, err := decompress(r, name)
+	if err != nil {
+		return nil, err
+	}
+	defer source.Close()
+
+	text, err := transcode(source, opts.Encoding, opts.MaxBytes)
+	if err != nil {
+		return nil, err
+	}
+	r = text
+
+	var limited *lineLimitReader
+	if opts.MaxBytes > 0 {
+		limited = newLineLimitReader(r, opts.MaxBytes)
+		r = limited
+	}
+
+	buffer := bufio.NewReader(r)
+	decoder := json.NewDecoder(buffer)
+	decoder.UseNumber()
+
+	line, badRows := 0, 0
+	read := func() (*jsonlRecord, error) {
+		if opts.SkipBadRows {
+			return readJSONLine(buffer, &badRows)
+		}
+
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				return nil, err
+			}
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				return nil, fmt.Errorf("error reading JSON Lines record %d: %w (--skip-bad-rows skips such lines)", line+1, err)
+			}
+			return nil, fmt.Errorf("error reading JSON Lines record %d: %w", line+1, err)
+		}
+		line++
+
+		record, err := decodeJSONObject(raw)
+		if err != nil {
+			return nil, fmt.Errorf("error reading JSON Lines record %d: %w (--skip-bad-rows skips such lines)", line, err)
+		}
+		return record, nil
+	}
+
+	records, err := newKeyedRecords(read)
+	if err != nil {
+		return nil, err
+	}
+	if len(records.header) == 0 {
+		return nil, fmt.Errorf("no JSON objects found")
+	}
+
+	profile := newDatasetProfile(name, size, "JSONL", records.header)
+	if err := profileRows(profile, records.header, records.next, opts); err != nil {
+		return nil, err
+	}
+
+	if note := records.note(); note != "" {
+		profile.Notes = append(profile.Notes, note)
+	}
+
+	source.describe(profile)
+	text.describe(profile)
+	if limited != nil && limited.truncated {
+		profile.Notes = append(profile.Notes, limited.note(profile))
+	}
+	if badRows > 0 {
+		profile.BadRows = badRows
+		profile.Notes = append(profile.Notes, badRowsNote(badRows))
+	}
+
+	profile.ProcessingTime = time.Since(startTime)
+
+	return profile, nil
+}
+
+// keyedRecords turns records of keyed values into rows. The header is the
+// keys of the first jsonlHeaderScan records, in the order they are first
+// seen, which are read ahead and kept until they are profiled.
+type keyedRecords struct {
+	read     func() (*jsonlRecord, error)
+	buffered []*jsonlRecord
+	header   []string
+	index    map[string]int
+	ignored  map[string]bool // keys first seen after the header was made
+}
+
+func newKeyedRecords(read func() (*jsonlRecord, error)) (*keyedRecords, error) {
+	k := &keyedRecords{read: read, header: make([]string, 0), index: make(map[string]int), ignored: make(map[string]bool)}
+	for len(k.buffered) < jsonlHeaderScan {
+		record, err := read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		k.buffered = append(k.buffered, record)
+		for _, key := range record.keys {
+			if _, ok := k.index[key]; !ok {
+				k.index[key] = len(k.header)
+				k.header = append(k.header, key)
+			}
+		}
+	}
+	return k, nil
+}
+
+func (k *keyedRecords) next() ([]string, error) {
+	var record *jsonlRecord
+	if len(k.buffered) > 0 {
+		record = k.buffered[0]
+		k.buffered[0] = nil
+		k.buffered = k.buffered[1:]
+	} else {
+		var err error
+		if record, err = k.read(); err != nil {
+			return nil, err
+		}
+	}
+
+	values := make([]string, len(k.header))
+	for key, value := range record.values {
+		if i, ok := k.index[key]; ok {
+			values[i] = value
+		} else {
+			k.ignored[key] = true
+		}
+	}
+	return values, nil
+}
+
+// note tells of the keys left out of the header, if any.
+func (k *keyedRecords) note() string {
+	if len(k.ignored) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d keys first seen after the first %d records were not profiled", len(k.ignored), jsonlHeaderScan)
+}
+
+// decodeJSONObject flattens one level of a JSON object into strings, keeping
+// the key order. Null becomes missing; nested objects and arrays are kept as
+// compact JSON.
+// readJSONLine reads the next line of r that holds a JSON object, counting
+// the lines it skips for holding anything else in badRows. Unlike the
+// decoder, it expects exactly one record per line, so that a bad line
+// cannot swallow the ones after it.
+func readJSONLine(r *bufio.Reader, badRows *int) (*jsonlRecord, error) {
+	for {
+		text, err := r.ReadBytes('\n')
+		if text = bytes.TrimSpace(text); len(text) > 0 {
+			if json.Valid(text) {
+				if record, decodeErr := decodeJSONObject(text); decodeErr == nil {
+					return record, nil
+				}
+			}
+			*badRows++
+		}
+		if err == io.EOF {
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading JSON Lines: %w", err)
+		}
+	}
+}
+
+func decodeJSONObject(raw json.RawMessage) (*jsonlRecord, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+
+	record := &jsonlRecord{values: make(map[string]string)}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := token.(string)
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		if _, seen := record.values[key]; !seen {
+			record.keys = append(record.keys, key)
+		}
+		record.values[key] = formatJSONValue(value)
+	}
+
+	return record, nil
+}
+
+func formatJSONValue(value json.RawMessage) string {
+	switch {
+	case len(value) == 0, bytes.Equal(value, []byte("null")):
+		return ""
+	case value[0] == '"':
+		var s string
+		if err := json.Unmarshal(value, &s); err == nil {
+			return s
+		}
+		return string(value)
+	case value[0] == '{', value[0] == '[':
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, value); err == nil {
+			return compact.String()
+		}
+		return string(value)
+	default:
+		return string(value)
+	}
+}
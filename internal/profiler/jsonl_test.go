@@ -0,0 +1,78 @@
+package profiler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProfileJSONL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	content := `{"id": 1, "name": "ann", "score": 1.5, "tags": ["a", "b"]}
+{"id": 2, "name": null, "score": 2.5}
+{"id": 3, "name": "cy", "score": 3.5, "extra": true}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	profile, err := ProfileDataset(path)
+	if err != nil {
+		t.Fatalf("Failed to profile JSON Lines: %v", err)
+	}
+
+	if profile.Format != "JSONL" || profile.RowCount != 3 {
+		t.Errorf("Expected 3 JSONL rows, got %s with %d", profile.Format, profile.RowCount)
+	}
+	if profile.ColumnCount != 5 {
+		t.Errorf("Expected 5 columns, got %d", profile.ColumnCount)
+	}
+
+	if profile.Columns["id"].DataType != "integer" || profile.Columns["score"].DataType != "float" {
+		t.Errorf("Unexpected types: id %s, score %s", profile.Columns["id"].DataType, profile.Columns["score"].DataType)
+	}
+	if profile.Columns["name"].MissingCount != 1 {
+		t.Errorf("Expected null name to be missing, got %d missing", profile.Columns["name"].MissingCount)
+	}
+	if profile.Columns["tags"].TopValues[0].Value != `["a","b"]` {
+		t.Errorf("Expected nested array kept as JSON, got %v", profile.Columns["tags"].TopValues)
+	}
+}
+
+func TestProfileJSONLLateKeys(t *testing.T) {
+	var content strings.Builder
+	for i := 0; i < jsonlHeaderScan; i++ {
+		content.WriteString(fmt.Sprintf("{\"id\": %d}\n", i))
+	}
+	content.WriteString("{\"id\": 0, \"late\": 1}\n")
+
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	profile, err := ProfileDataset(path)
+	if err != nil {
+		t.Fatalf("Failed to profile JSON Lines: %v", err)
+	}
+
+	if profile.RowCount != jsonlHeaderScan+1 || profile.ColumnCount != 1 {
+		t.Errorf("Expected %d rows and 1 column, got %d and %d", jsonlHeaderScan+1, profile.RowCount, profile.ColumnCount)
+	}
+	if len(profile.Notes) == 0 || !strings.Contains(profile.Notes[0], "1 keys first seen") {
+		t.Errorf("Expected a note about the late key, got %v", profile.Notes)
+	}
+}
+
+func TestProfileJSONLInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	if err := os.WriteFile(path, []byte("{\"id\": 1}\n[1, 2]\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if _, err := ProfileDataset(path); err == nil || !strings.Contains(err.Error(), "record 2") {
+		t.Errorf("Expected an error for record 2, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package profiler
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxDuplicateKeys is how many of the most repeated key values are listed.
+const maxDuplicateKeys = 10
+
+// keySeparator joins the values of a key into one string.
+const keySeparator = "\x1f"
+
+// DuplicateKey is a value of the unique key shared by several rows.
+type DuplicateKey struct {
+	Values []string // the key, in the order of its columns
+	Rows   int      // rows sharing it
+}
+
+// keyIndexes returns the index of each column of key in header, or an
+// error naming a column that is not there.
+func keyIndexes(header []string, key []string) ([]int, error) {
+	indexes := make([]int, len(key))
+	for i, name := range key {
+		indexes[i] = -1
+		for j, colName := range header {
+			if colName == name {
+				indexes[i] = j
+				break
+			}
+		}
+		if indexes[i] < 0 {
+			return nil, fmt.Errorf("unique key column %s not found", name)
+		}
+	}
+	return indexes, nil
+}
+
+// recordKey joins the values of record at indexes.
+func recordKey(record []string, indexes []int) string {
+	values := make([]string, len(indexes))
+	for i, index := range indexes {
+		if index < len(record) {
+			values[i] = record[index]
+		}
+	}
+	return strings.Join(values, keySeparator)
+}
+
+// duplicateKeys lists the most repeated key values counted by keys. Past
+// the distinct keys kept they come from a sample of the keys, with exact
+// rows.
+func duplicateKeys(keys *duplicateCounter) []DuplicateKey {
+	repeated := keys.repeated()
+	if len(repeated) > maxDuplicateKeys {
+		repeated = repeated[:maxDuplicateKeys]
+	}
+
+	duplicates := make([]DuplicateKey, 0, len(repeated))
+	for _, v := range repeated {
+		duplicates = append(duplicates, DuplicateKey{Values: strings.Split(v.Value, keySeparator), Rows: v.Count})
+	}
+	return duplicates
+}
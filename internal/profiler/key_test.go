@@ -0,0 +1,116 @@
+package profiler
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func ordersCSV() string {
+	var b strings.Builder
+	b.WriteString("order_id,region,updated_at\n")
+	for i := 0; i < 30; i++ {
+		// Orders 0 to 2 are exported again with a later timestamp, order 0 twice
+		fmt.Fprintf(&b, "%d,north,2024-01-01T00:00:%02dZ\n", i, i)
+	}
+	for i, id := range []int{0, 1, 2, 0} {
+		fmt.Fprintf(&b, "%d,north,2024-01-02T00:00:%02dZ\n", id, i)
+	}
+	return b.String()
+}
+
+func TestProfileUniqueKey(t *testing.T) {
+	path := writeTestFile(t, "data.csv", ordersCSV())
+
+	profile, err := ProfileDataset(path)
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if profile.DuplicateRows != 0 || profile.UniqueKey != nil {
+		t.Errorf("Expected no duplicate rows without a key, got %d", profile.DuplicateRows)
+	}
+
+	for _, exactRows := range []int{0, 1000} {
+		profile, err := ProfileDatasetWithOptions(path, Options{UniqueKey: []string{"order_id"}, ExactRows: exactRows})
+		if err != nil {
+			t.Fatalf("Failed to profile: %v", err)
+		}
+
+		if profile.DuplicateRows != 4 || !reflect.DeepEqual(profile.UniqueKey, []string{"order_id"}) {
+			t.Errorf("Expected 4 rows repeating order_id, got %d by %v", profile.DuplicateRows, profile.UniqueKey)
+		}
+		want := []DuplicateKey{{Values: []string{"0"}, Rows: 3}, {Values: []string{"1"}, Rows: 2}, {Values: []string{"2"}, Rows: 2}}
+		if !reflect.DeepEqual(profile.DuplicateKeys, want) {
+			t.Errorf("Expected duplicate keys %v, got %v", want, profile.DuplicateKeys)
+		}
+
+		found := false
+		for _, issue := range profile.QualityIssues {
+			found = found || issue.Description == "Duplicate keys (order_id) detected: 11.76% of rows"
+		}
+		if !found {
+			t.Errorf("Expected a duplicate key issue, got %v", profile.QualityIssues)
+		}
+
+		if exactRows > 0 {
+			if len(profile.DuplicateGroups) != 3 || !reflect.DeepEqual(profile.DuplicateGroups[0], DuplicateGroup{Rows: []int{1, 31, 34}, Values: map[string]string{"order_id": "0"}}) {
+				t.Errorf("Expected groups of rows sharing an order_id, got %v", profile.DuplicateGroups)
+			}
+		}
+	}
+
+	// A composite key with the timestamp tells the exports apart
+	profile, err = ProfileDatasetWithOptions(path, Options{UniqueKey: []string{"order_id", "updated_at"}})
+	if err != nil || profile.DuplicateRows != 0 || len(profile.DuplicateKeys) != 0 {
+		t.Errorf("Expected no duplicates by order_id and updated_at, got %v and %d", err, profile.DuplicateRows)
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{UniqueKey: []string{"order_id", "customer"}}); err == nil || !strings.Contains(err.Error(), "unique key column customer not found") {
+		t.Errorf("Expected a missing key column to be reported, got %v", err)
+	}
+}
+
+func TestProfileUniqueKeyParallel(t *testing.T) {
+	withParallelMinChunk(t, 256)
+	path := writeTestFile(t, "data.csv", parallelCSV(2000))
+	opts := Options{UniqueKey: []string{"name"}}
+
+	want, err := ProfileDatasetWithOptions(path, opts)
+	if err != nil {
+		t.Fatalf("Failed to profile sequentially: %v", err)
+	}
+	opts.Parallel = 8
+	got, err := ProfileDatasetWithOptions(path, opts)
+	if err != nil {
+		t.Fatalf("Failed to profile in parallel: %v", err)
+	}
+
+	if want.DuplicateRows == 0 || got.DuplicateRows != want.DuplicateRows || !reflect.DeepEqual(got.DuplicateKeys, want.DuplicateKeys) {
+		t.Errorf("Expected %d duplicates with keys %v, got %d with %v", want.DuplicateRows, want.DuplicateKeys, got.DuplicateRows, got.DuplicateKeys)
+	}
+}
+
+func TestProfileUniqueKeyManyKeys(t *testing.T) {
+	withParallelMinChunk(t, 256)
+
+	// Far more keys than a sketch tracks, only the last repeated
+	var b strings.Builder
+	b.WriteString("id,region\n")
+	for i := 0; i < 30000; i++ {
+		fmt.Fprintf(&b, "%d,north\n", i)
+	}
+	b.WriteString("7,south\n")
+	path := writeTestFile(t, "data.csv", b.String())
+
+	for _, parallel := range []int{1, 4} {
+		profile, err := ProfileDatasetWithOptions(path, Options{UniqueKey: []string{"id"}, Parallel: parallel})
+		if err != nil {
+			t.Fatalf("Failed to profile: %v", err)
+		}
+		want := []DuplicateKey{{Values: []string{"7"}, Rows: 2}}
+		if profile.DuplicateRows != 1 || !reflect.DeepEqual(profile.DuplicateKeys, want) {
+			t.Errorf("Parallel %d: expected one row repeating id 7, got %d with %v", parallel, profile.DuplicateRows, profile.DuplicateKeys)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package profiler
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// lineLimitReader passes through at most limit bytes of a line-oriented
+// source, ending at the last complete line so that a cut never splits a
+// record. It reads past the limit to tell whether the source was cut.
+type lineLimitReader struct {
+	reader    *bufio.Reader
+	limit     int64
+	remaining int64
+	line      []byte
+	err       error
+	truncated bool
+}
+
+func newLineLimitReader(r io.Reader, limit int64) *lineLimitReader {
+	return &lineLimitReader{
+		reader:    bufio.NewReader(r),
+		limit:     limit,
+		remaining: limit,
+	}
+}
+
+func (l *lineLimitReader) Read(p []byte) (int, error) {
+	for len(l.line) == 0 {
+		if l.err != nil {
+			return 0, l.err
+		}
+
+		line, err := l.reader.ReadBytes('\n')
+		if int64(len(line)) > l.remaining {
+			l.truncated = true
+			l.err = io.EOF
+			continue
+		}
+
+		l.remaining -= int64(len(line))
+		l.line = line
+		if err != nil {
+			l.err = err
+		}
+	}
+
+	n := copy(p, l.line)
+	l.line = l.line[n:]
+	return n, nil
+}
+
+func (l *lineLimitReader) note(profile *DatasetProfile) string {
+	// The file size of a compressed source does not compare with the
+	// decompressed bytes read
+	if profile.FileSize > 0 && profile.Compression == "" {
+		return fmt.Sprintf("Profiled only the first %d of %d bytes (--range)", l.limit-l.remaining, profile.FileSize)
+	}
+	return fmt.Sprintf("Profiled only the first %d bytes (--range)", l.limit-l.remaining)
+}
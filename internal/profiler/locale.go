@@ -0,0 +1,104 @@
+package profiler
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DateOrderDayFirst is the date order of a column whose dates were read
+// day first, as in 31/12/2023. Dates are read month first otherwise, as in
+// 12/31/2023.
+const DateOrderDayFirst = "day-first"
+
+// detectDateOrder picks the date order of a sample. Only dates that read
+// in one order alone, such as 31/12/2023 or 31.12.2023, say anything about
+// it: the sample is read day first when some read day first alone and none
+// month first alone. When both turn up, it is read month first as before,
+// and the note explains why.
+func detectDateOrder(sample []string) (bool, string) {
+	dayOnly, monthOnly := 0, 0
+	for _, value := range sample {
+		_, _, month := dateFormat{}.readText(value)
+		_, _, day := dateFormat{dayFirst: true}.readText(value)
+		switch {
+		case day && !month:
+			dayOnly++
+		case month && !day:
+			monthOnly++
+		}
+	}
+
+	switch {
+	case dayOnly == 0:
+		return false, ""
+	case monthOnly == 0:
+		return true, ""
+	default:
+		return false, fmt.Sprintf("Ambiguous date order: %d dates read only day first and %d only month first, parsed month first, set --locale",
+			dayOnly, monthOnly)
+	}
+}
+
+// locale is how a region writes numbers and dates.
+type locale struct {
+	name     string
+	numbers  string // number format
+	dayFirst bool   // date order
+}
+
+// locales are the supported locales. A language alone, such as de, picks
+// the first locale of the language.
+var locales = []locale{
+	{name: "en-US", numbers: NumberFormatUS},
+	{name: "en-GB", numbers: NumberFormatUS, dayFirst: true},
+	{name: "en-AU", numbers: NumberFormatUS, dayFirst: true},
+	{name: "en-IN", numbers: NumberFormatIN, dayFirst: true},
+	{name: "de-DE", numbers: NumberFormatEU, dayFirst: true},
+	{name: "es-ES", numbers: NumberFormatEU, dayFirst: true},
+	{name: "it-IT", numbers: NumberFormatEU, dayFirst: true},
+	{name: "nl-NL", numbers: NumberFormatEU, dayFirst: true},
+	{name: "pt-BR", numbers: NumberFormatEU, dayFirst: true},
+	{name: "da-DK", numbers: NumberFormatEU, dayFirst: true},
+	{name: "tr-TR", numbers: NumberFormatEU, dayFirst: true},
+}
+
+// LocaleNames lists the supported locales.
+func LocaleNames() []string {
+	names := make([]string, len(locales))
+	for i, l := range locales {
+		names[i] = l.name
+	}
+	return names
+}
+
+// lookupLocale finds a locale by its tag, such as de-DE or de_DE, or by its
+// language alone. The empty name has no locale.
+func lookupLocale(name string) (locale, bool, error) {
+	if name == "" {
+		return locale{}, false, nil
+	}
+	tag := strings.ReplaceAll(name, "_", "-")
+	for _, l := range locales {
+		if strings.EqualFold(l.name, tag) {
+			return l, true, nil
+		}
+	}
+	if !strings.Contains(tag, "-") {
+		for _, l := range locales {
+			if language, _, _ := strings.Cut(l.name, "-"); strings.EqualFold(language, tag) {
+				return l, true, nil
+			}
+		}
+	}
+	return locale{}, false, fmt.Errorf("unsupported locale: %s (use %s)", name, strings.Join(LocaleNames(), ", "))
+}
+
+// numberFormat is the number format every column is read in: NumberFormat,
+// else that of Locale, or empty to detect it per column.
+func (o Options) numberFormat() string {
+	if o.NumberFormat != "" {
+		return o.NumberFormat
+	}
+	l, _, _ := lookupLocale(o.Locale)
+	return l.numbers
+}
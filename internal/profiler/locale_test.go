@@ -0,0 +1,143 @@
+package profiler
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectDateOrder(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample []string
+		want   bool
+		note   bool
+	}{
+		{"iso", []string{"2024-01-31", "2024-02-01"}, false, false},
+		{"month first", []string{"12/31/2023", "01/02/2024"}, false, false},
+		{"day first", []string{"31/12/2023", "01/02/2024"}, true, false},
+		{"dotted", []string{"01.02.2024", "03.04.2024"}, true, false},
+		{"named months", []string{"31 Dec 2023", "02/01/2024"}, false, false},
+		{"ambiguous only", []string{"01/02/2024", "03/04/2024"}, false, false},
+		{"both", []string{"31/12/2023", "12/31/2023"}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dayFirst, note := detectDateOrder(tt.sample)
+			if dayFirst != tt.want || (note != "") != tt.note {
+				t.Errorf("Expected day first %v (note %v), got %v with note %q", tt.want, tt.note, dayFirst, note)
+			}
+		})
+	}
+
+	got, ok := dateFormat{dayFirst: true}.parse("31.12.2023")
+	if want := time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC); !ok || !got.Equal(want) {
+		t.Errorf("Expected 31.12.2023 read as %v, got %v (%v)", want, got, ok)
+	}
+}
+
+func TestLookupLocale(t *testing.T) {
+	tests := []struct {
+		name     string
+		numbers  string
+		dayFirst bool
+	}{
+		{"en-US", NumberFormatUS, false},
+		{"en_gb", NumberFormatUS, true},
+		{"de", NumberFormatEU, true},
+		{"en-IN", NumberFormatIN, true},
+	}
+	for _, tt := range tests {
+		l, ok, err := lookupLocale(tt.name)
+		if err != nil || !ok {
+			t.Fatalf("Failed to look up %s: %v", tt.name, err)
+		}
+		if l.numbers != tt.numbers || l.dayFirst != tt.dayFirst {
+			t.Errorf("%s: expected %s and day first %v, got %+v", tt.name, tt.numbers, tt.dayFirst, l)
+		}
+	}
+
+	for _, name := range []string{"fr-FR", "de-CH", "xx"} {
+		if _, _, err := lookupLocale(name); err == nil {
+			t.Errorf("Expected %s to be unsupported", name)
+		}
+	}
+	if (Options{Locale: "de-DE", NumberFormat: NumberFormatUS}).numberFormat() != NumberFormatUS {
+		t.Error("Expected --number-format to win over the number format of --locale")
+	}
+}
+
+func localeCSV(rows int) string {
+	var content strings.Builder
+	content.WriteString("id;amount;booked;ambiguous\n")
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < rows; i++ {
+		booked := start.AddDate(0, 0, i%365)
+		fmt.Fprintf(&content, "%d;%d.%03d,%02d;%s;%02d/%02d/2024\n",
+			i, 1+i%9, i%1000, i%100, booked.Format("02.01.2006"), 1+i%12, 1+i%12)
+	}
+	return content.String()
+}
+
+func TestProfileLocale(t *testing.T) {
+	path := writeTestFile(t, "umsaetze.csv", localeCSV(500))
+
+	profile, err := ProfileDatasetWithOptions(path, Options{})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	booked := profile.Columns["booked"]
+	if booked.DataType != "datetime" || booked.DateOrder != DateOrderDayFirst || booked.DateTime == nil {
+		t.Fatalf("Expected a day-first datetime column, got %s in %q", booked.DataType, booked.DateOrder)
+	}
+	if want := time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC); !booked.DateTime.Max.Equal(want) {
+		t.Errorf("Expected the latest booking on %v, got %v", want, booked.DateTime.Max)
+	}
+	if col := profile.Columns["ambiguous"]; col.DataType != "datetime" || col.DateOrder != "" {
+		t.Errorf("Expected dates reading the same in both orders read month first, got %s in %q", col.DataType, col.DateOrder)
+	}
+
+	profile, err = ProfileDatasetWithOptions(path, Options{Locale: "en-GB"})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if col := profile.Columns["ambiguous"]; col.DateOrder != DateOrderDayFirst {
+		t.Errorf("Expected --locale en-GB to read dates day first, got %q", col.DateOrder)
+	}
+	if col := profile.Columns["amount"]; col.IsNumeric {
+		t.Errorf("Expected eu amounts not to read as en-GB numbers, got %s", col.DataType)
+	}
+
+	profile, err = ProfileDatasetWithOptions(path, Options{Locale: "de-DE"})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if col := profile.Columns["amount"]; col.DataType != "float" || col.NumberFormat != NumberFormatEU {
+		t.Errorf("Expected --locale de-DE to read eu floats, got %s in %q", col.DataType, col.NumberFormat)
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{Locale: "xx"}); err == nil {
+		t.Error("Expected an error for an unknown locale")
+	}
+}
+
+func TestProfileLocaleParallel(t *testing.T) {
+	withParallelMinChunk(t, 256)
+	path := writeTestFile(t, "umsaetze.csv", localeCSV(2000))
+
+	want, err := ProfileDatasetWithOptions(path, Options{})
+	if err != nil {
+		t.Fatalf("Failed to profile sequentially: %v", err)
+	}
+	got, err := ProfileDatasetWithOptions(path, Options{Parallel: 8})
+	if err != nil {
+		t.Fatalf("Failed to profile in parallel: %v", err)
+	}
+
+	w, g := want.Columns["booked"], got.Columns["booked"]
+	if g.DateOrder != w.DateOrder || g.DateTime == nil || !g.DateTime.Min.Equal(w.DateTime.Min) || !g.DateTime.Max.Equal(w.DateTime.Max) ||
+		g.ParseErrors.Unparseable != w.ParseErrors.Unparseable {
+		t.Errorf("Expected %q %v-%v, got %q %+v", w.DateOrder, w.DateTime.Min, w.DateTime.Max, g.DateOrder, g.DateTime)
+	}
+}
@@ -0,0 +1,184 @@
+package profiler
+
+import (
+	"container/heap"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"math"
+	"slices"
+	"strings"
+)
+
+// SaltEnv is the environment variable read for the salt of MinHash
+// signatures, a secret shared by the parties comparing their datasets.
+const SaltEnv = "DATASLEUTH_SALT"
+
+// DefaultMinHashSize is the number of hashes kept per MinHash signature. The
+// standard error of an overlap estimate is about 1/sqrt(size).
+const DefaultMinHashSize = 256
+
+// MinHash is a bottom-k MinHash signature of the distinct values of a
+// column: the smallest of their salted hashes. Values are trimmed and lower
+// cased before hashing. Two signatures made with the same salt estimate how
+// many values their columns share, without revealing the values to anyone
+// who does not know the salt.
+type MinHash struct {
+	Salt   string   // identifies the salt without revealing it
+	Size   int      // hashes kept
+	Hashes []uint64 // ascending; fewer than Size when the column has fewer distinct values
+}
+
+// SaltID identifies salt in signatures, so that signatures made with
+// different salts are not compared.
+func SaltID(salt string) string {
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte("datasleuth minhash salt"))
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// Complete reports whether m holds the hash of every distinct value.
+func (m *MinHash) Complete() bool {
+	return len(m.Hashes) < m.Size
+}
+
+// Distinct estimates the number of distinct values behind m, exact when m
+// is complete.
+func (m *MinHash) Distinct() float64 {
+	if m.Complete() {
+		return float64(len(m.Hashes))
+	}
+	return float64(m.Size-1) / unitHash(m.Hashes[m.Size-1])
+}
+
+// Overlap is the estimated overlap of the distinct values of two columns.
+type Overlap struct {
+	Jaccard float64 // shared values over the values of either
+	Shared  float64 // distinct values in both
+	Exact   bool    // both signatures hold every value, so the estimates are counts
+}
+
+// EstimateOverlap estimates the distinct values a and b share from the
+// smallest hashes of their union, as many as the smaller signature holds.
+func EstimateOverlap(a, b *MinHash) (Overlap, error) {
+	if a.Salt != b.Salt {
+		return Overlap{}, fmt.Errorf("signatures were made with different salts")
+	}
+
+	k := min(a.Size, b.Size)
+	union := make([]uint64, 0, k)
+	shared := 0
+	i, j := 0, 0
+	for len(union) < k && (i < len(a.Hashes) || j < len(b.Hashes)) {
+		switch {
+		case j == len(b.Hashes) || (i < len(a.Hashes) && a.Hashes[i] < b.Hashes[j]):
+			union = append(union, a.Hashes[i])
+			i++
+		case i == len(a.Hashes) || b.Hashes[j] < a.Hashes[i]:
+			union = append(union, b.Hashes[j])
+			j++
+		default:
+			union = append(union, a.Hashes[i])
+			shared++
+			i++
+			j++
+		}
+	}
+	if len(union) == 0 {
+		return Overlap{Exact: true}, nil
+	}
+
+	// Below the kth smallest hash of the union, both signatures hold every
+	// hash of their column. A union of fewer holds every value of both.
+	overlap := Overlap{Jaccard: float64(shared) / float64(len(union))}
+	if len(union) < k {
+		overlap.Shared = float64(shared)
+		overlap.Exact = true
+		return overlap, nil
+	}
+	overlap.Shared = overlap.Jaccard * float64(k-1) / unitHash(union[k-1])
+	return overlap, nil
+}
+
+// unitHash maps a hash onto (0, 1].
+func unitHash(h uint64) float64 {
+	return (float64(h) + 1) / math.Exp2(64)
+}
+
+// minHashSketch keeps the smallest salted hashes of the values added to it.
+type minHashSketch struct {
+	mac    hash.Hash
+	salt   string
+	size   int
+	hashes hashMaxHeap
+	kept   map[uint64]struct{}
+	sum    []byte
+}
+
+func newMinHashSketch(salt string, size int) *minHashSketch {
+	return &minHashSketch{
+		mac:  hmac.New(sha256.New, []byte(salt)),
+		salt: salt,
+		size: size,
+		kept: make(map[uint64]struct{}, size),
+	}
+}
+
+func (s *minHashSketch) add(value string) {
+	s.mac.Reset()
+	s.mac.Write([]byte(strings.ToLower(strings.TrimSpace(value))))
+	s.sum = s.mac.Sum(s.sum[:0])
+	s.addHash(binary.BigEndian.Uint64(s.sum))
+}
+
+func (s *minHashSketch) addHash(h uint64) {
+	if _, ok := s.kept[h]; ok {
+		return
+	}
+	if len(s.hashes) < s.size {
+		heap.Push(&s.hashes, h)
+		s.kept[h] = struct{}{}
+		return
+	}
+	if h < s.hashes[0] {
+		delete(s.kept, s.hashes[0])
+		s.hashes[0] = h
+		heap.Fix(&s.hashes, 0)
+		s.kept[h] = struct{}{}
+	}
+}
+
+// merge folds in the hashes kept by o.
+func (s *minHashSketch) merge(o *minHashSketch) {
+	for _, h := range o.hashes {
+		s.addHash(h)
+	}
+}
+
+func (s *minHashSketch) signature() *MinHash {
+	hashes := make([]uint64, 0, len(s.hashes))
+	for h := range s.kept {
+		hashes = append(hashes, h)
+	}
+	slices.Sort(hashes)
+	return &MinHash{Salt: SaltID(s.salt), Size: s.size, Hashes: hashes}
+}
+
+// hashMaxHeap is a max-heap of hashes, the largest kept one on top.
+type hashMaxHeap []uint64
+
+func (h hashMaxHeap) Len() int           { return len(h) }
+func (h hashMaxHeap) Less(i, j int) bool { return h[i] > h[j] }
+func (h hashMaxHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *hashMaxHeap) Push(x interface{}) { *h = append(*h, x.(uint64)) }
+
+func (h *hashMaxHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
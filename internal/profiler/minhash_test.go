@@ -0,0 +1,104 @@
+package profiler
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func minHashOf(salt string, size int, values ...string) *MinHash {
+	sketch := newMinHashSketch(salt, size)
+	for _, v := range values {
+		sketch.add(v)
+	}
+	return sketch.signature()
+}
+
+func TestMinHashSketch(t *testing.T) {
+	values := make([]string, 5000)
+	for i := range values {
+		values[i] = fmt.Sprintf("user-%d", i%2000)
+	}
+
+	whole := minHashOf("pepper", 128, values...)
+	if len(whole.Hashes) != 128 || !slices.IsSorted(whole.Hashes) || whole.Salt != SaltID("pepper") {
+		t.Fatalf("Expected 128 sorted hashes, got %d", len(whole.Hashes))
+	}
+	if d := whole.Distinct(); math.Abs(d-2000)/2000 > 0.2 {
+		t.Errorf("Expected about 2000 distinct values, got %v", d)
+	}
+
+	// Merged halves keep the same hashes as a single pass
+	first := newMinHashSketch("pepper", 128)
+	second := newMinHashSketch("pepper", 128)
+	for i, v := range values {
+		if i < len(values)/2 {
+			first.add(v)
+		} else {
+			second.add(v)
+		}
+	}
+	first.merge(second)
+	if merged := first.signature(); !slices.Equal(merged.Hashes, whole.Hashes) {
+		t.Error("Expected merged sketches to keep the hashes of a single pass")
+	}
+
+	// Another salt hashes the same values differently
+	if other := minHashOf("salt", 128, values...); slices.Equal(other.Hashes, whole.Hashes) || other.Salt == whole.Salt {
+		t.Error("Expected another salt to give other hashes")
+	}
+}
+
+func TestEstimateOverlap(t *testing.T) {
+	// Trimmed and lower cased, the columns share b and c
+	small, err := EstimateOverlap(minHashOf("s", 16, "a", "B ", "c", "c"), minHashOf("s", 16, "b", "C", "d"))
+	if err != nil {
+		t.Fatalf("EstimateOverlap failed: %v", err)
+	}
+	if !small.Exact || small.Shared != 2 || small.Jaccard != 0.5 {
+		t.Errorf("Expected 2 values shared exactly, got %+v", small)
+	}
+
+	// 20,000 values each, 5,000 of them shared
+	var ours, theirs []string
+	for i := 0; i < 20000; i++ {
+		ours = append(ours, fmt.Sprintf("customer-%d", i))
+		theirs = append(theirs, fmt.Sprintf("customer-%d", i+15000))
+	}
+	large, err := EstimateOverlap(minHashOf("s", 1024, ours...), minHashOf("s", 1024, theirs...))
+	if err != nil {
+		t.Fatalf("EstimateOverlap failed: %v", err)
+	}
+	if large.Exact || math.Abs(large.Shared-5000)/5000 > 0.25 || math.Abs(large.Jaccard-5000.0/35000) > 0.05 {
+		t.Errorf("Expected about 5000 shared values, got %+v", large)
+	}
+
+	if _, err := EstimateOverlap(minHashOf("s", 16, "a"), minHashOf("t", 16, "a")); err == nil {
+		t.Error("Expected signatures of different salts to be rejected")
+	}
+}
+
+func TestProfileMinHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.csv")
+	content := "email,plan\nA@example.com,free\nb@example.com,pro\n,free\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write data: %v", err)
+	}
+
+	profile, err := ProfileDatasetWithOptions(path, Options{MinHash: 8, MinHashSalt: "pepper"})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	want := minHashOf("pepper", 8, "a@example.com", "b@example.com")
+	if got := profile.Columns["email"].MinHash; got == nil || !slices.Equal(got.Hashes, want.Hashes) {
+		t.Errorf("Expected the signature of the two emails, got %+v", got)
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{MinHash: 8}); err == nil || !strings.Contains(err.Error(), SaltEnv) {
+		t.Errorf("Expected signatures without a salt to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package profiler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// mixedTypeShare is the share of the sample of a string column that must
+// read as numbers or timestamps for them to be counted, so that the column
+// can be told apart from one with a few stray values.
+const mixedTypeShare = 0.1
+
+// mixedTypeExamples is the number of values of the other types kept as
+// examples of a column of mixed types.
+const mixedTypeExamples = 3
+
+// Families of types a column of mixed types mixes: integers and floats are
+// both numbers.
+const (
+	familyNumber   = "number"
+	familyDateTime = "datetime"
+	familyString   = "string"
+)
+
+// MixedTypes breaks a column down by the type its values read as, when a
+// sizeable share of them reads as another type than the rest: numbers with
+// placeholders such as n/a among them, or timestamps mixed with text. These
+// usually come from a bug upstream.
+type MixedTypes struct {
+	Types    []TypeCount // values by type, most common first
+	Examples []string    // most common values of the other types, withheld by --redact
+}
+
+// TypeCount is the number of values of a column that read as a type:
+// integer, float, datetime, or string for those that read as none.
+type TypeCount struct {
+	Type  string
+	Count int
+}
+
+// Percent is the share of the count values of the column that read as the
+// type.
+func (t TypeCount) Percent(count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	return float64(t.Count) / float64(count) * 100
+}
+
+// describe lists the types of a column of count values with their share,
+// then the examples.
+func (m *MixedTypes) describe(count int) string {
+	types := make([]string, len(m.Types))
+	for i, t := range m.Types {
+		types[i] = fmt.Sprintf("%.1f%% %s", t.Percent(count), t.Type)
+	}
+	s := strings.Join(types, ", ")
+	if len(m.Examples) > 0 {
+		s += ", such as '" + strings.Join(m.Examples, "', '") + "'"
+	}
+	return s
+}
+
+func typeFamily(dataType string) string {
+	switch dataType {
+	case "integer", "float":
+		return familyNumber
+	case "datetime":
+		return familyDateTime
+	}
+	return familyString
+}
+
+// typeCounts counts the values of col by the type they read as, from the
+// numbers and timestamps counted of it. Typed columns only count their own
+// type, string columns the numbers and timestamps that made up enough of
+// their sample.
+func (a *columnAccumulator) typeCounts(col *ColumnProfile) []TypeCount {
+	counts := make(map[string]int)
+	if a.numeric != nil && (col.IsNumeric || col.DataType == "string") {
+		counts["integer"] = a.numeric.integers
+		counts["float"] = a.numeric.count - a.numeric.integers + a.numeric.nonFinite
+	}
+	if a.dates != nil && (col.IsDateTime || col.DataType == "string") {
+		counts["datetime"] = a.dates.seconds.count
+	}
+	counts["string"] = max(col.Count-counts["integer"]-counts["float"]-counts["datetime"], 0)
+
+	types := make([]TypeCount, 0, len(counts))
+	for _, name := range []string{"integer", "float", "datetime", "string"} {
+		if counts[name] > 0 {
+			types = append(types, TypeCount{Type: name, Count: counts[name]})
+		}
+	}
+	sort.SliceStable(types, func(i, j int) bool {
+		return types[i].Count > types[j].Count
+	})
+	return types
+}
+
+// familyOf tells the family of the type value reads as in a column of
+// dataType, its own type first.
+func (a *columnAccumulator) familyOf(value, dataType string) string {
+	_, isNumber := a.format.parseFloat(value)
+	isNumber = isNumber || nonFinite(value)
+	_, isDate := a.dateFormat.parse(value)
+	switch {
+	case dataType == "datetime" && isDate:
+		return familyDateTime
+	case isNumber:
+		return familyNumber
+	case isDate:
+		return familyDateTime
+	}
+	return familyString
+}
+
+// mixedTypes breaks col down by type when the second most common family of
+// types holds at least MixedTypePercent of its values, with the most
+// common values of the other families as examples unless redacted.
+func (a *columnAccumulator) mixedTypes(col *ColumnProfile, thresholds Thresholds, redacted bool) *MixedTypes {
+	if col.Count == 0 {
+		return nil
+	}
+	types := a.typeCounts(col)
+
+	families := make(map[string]int)
+	for _, t := range types {
+		families[typeFamily(t.Type)] += t.Count
+	}
+	if len(families) < 2 {
+		return nil
+	}
+	dominant, second := "", 0
+	for family, count := range families {
+		if dominant == "" || count > families[dominant] || (count == families[dominant] && family < dominant) {
+			dominant = family
+		}
+	}
+	for family, count := range families {
+		if family != dominant && count > second {
+			second = count
+		}
+	}
+	if float64(second) < float64(col.Count)*thresholds.MixedTypePercent/100 {
+		return nil
+	}
+
+	mixed := &MixedTypes{Types: types}
+	if redacted {
+		return mixed
+	}
+	for _, v := range a.counter.topValues(maxTrackedValues) {
+		family := a.familyOf(v.Value, col.DataType)
+		if families[family] == 0 {
+			family = familyString // not counted, so counted as a string
+		}
+		if family == dominant {
+			continue
+		}
+		mixed.Examples = append(mixed.Examples, v.Value)
+		if len(mixed.Examples) == mixedTypeExamples {
+			break
+		}
+	}
+	return mixed
+}
@@ -0,0 +1,110 @@
+package profiler
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// mixedCSV returns amounts of which 40% are placeholders, prices with
+// a rare placeholder, and codes that turn to text past the first 100 rows.
+func mixedCSV(rows int) string {
+	var content strings.Builder
+	content.WriteString("amount,price,code\n")
+	for i := 0; i < rows; i++ {
+		amount := fmt.Sprintf("%d.%02d", i%90, i%100)
+		switch i % 10 {
+		case 1, 4, 7:
+			amount = "n/a"
+		case 9:
+			amount = "unknown"
+		}
+		price := fmt.Sprintf("%d.5", i%40)
+		if i%50 == 0 {
+			price = "n/a"
+		}
+		code := fmt.Sprint(1000 + i%300)
+		if i >= 100 && i%5 == 0 {
+			code = fmt.Sprintf("X-%d", i%7)
+		}
+		fmt.Fprintf(&content, "%s,%s,%s\n", amount, price, code)
+	}
+	return content.String()
+}
+
+func TestProfileMixedTypes(t *testing.T) {
+	path := writeTestFile(t, "orders.csv", mixedCSV(1000))
+
+	profile, err := ProfileDatasetWithOptions(path, Options{})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+
+	amount := profile.Columns["amount"]
+	if amount.DataType != "string" || amount.MixedTypes == nil {
+		t.Fatalf("Expected a string column of mixed types, got %s with %+v", amount.DataType, amount.MixedTypes)
+	}
+	want := []TypeCount{{Type: "float", Count: 600}, {Type: "string", Count: 400}}
+	if !reflect.DeepEqual(amount.MixedTypes.Types, want) {
+		t.Errorf("Expected types %v, got %v", want, amount.MixedTypes.Types)
+	}
+	if examples := amount.MixedTypes.Examples; !reflect.DeepEqual(examples, []string{"n/a", "unknown"}) {
+		t.Errorf("Expected the placeholders as examples, got %v", examples)
+	}
+	if !hasIssue(amount, "mixed_types") {
+		t.Errorf("Expected a mixed_types issue, got %+v", amount.QualityIssues)
+	}
+
+	if col := profile.Columns["price"]; col.MixedTypes != nil || hasIssue(col, "mixed_types") {
+		t.Errorf("Expected 2%% placeholders not to mix types, got %+v", col.MixedTypes)
+	}
+
+	code := profile.Columns["code"]
+	if code.DataType != "integer" || code.MixedTypes == nil {
+		t.Fatalf("Expected integers turning to text to mix types, got %s with %+v", code.DataType, code.MixedTypes)
+	}
+	if want := []TypeCount{{Type: "integer", Count: 820}, {Type: "string", Count: 180}}; !reflect.DeepEqual(code.MixedTypes.Types, want) {
+		t.Errorf("Expected types %v, got %v", want, code.MixedTypes.Types)
+	}
+	if len(code.MixedTypes.Examples) != mixedTypeExamples || !strings.HasPrefix(code.MixedTypes.Examples[0], "X-") {
+		t.Errorf("Expected %d codes as examples, got %v", mixedTypeExamples, code.MixedTypes.Examples)
+	}
+
+	profile, err = ProfileDatasetWithOptions(path, Options{Redact: []string{"amount"}})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if col := profile.Columns["amount"]; col.MixedTypes == nil || len(col.MixedTypes.Examples) > 0 {
+		t.Errorf("Expected the examples of a redacted column withheld, got %+v", col.MixedTypes)
+	}
+}
+
+func TestProfileMixedTypesParallel(t *testing.T) {
+	withParallelMinChunk(t, 256)
+	path := writeTestFile(t, "orders.csv", mixedCSV(4000))
+
+	want, err := ProfileDatasetWithOptions(path, Options{})
+	if err != nil {
+		t.Fatalf("Failed to profile sequentially: %v", err)
+	}
+	got, err := ProfileDatasetWithOptions(path, Options{Parallel: 8})
+	if err != nil {
+		t.Fatalf("Failed to profile in parallel: %v", err)
+	}
+
+	for _, name := range []string{"amount", "price", "code"} {
+		if w, g := want.Columns[name].MixedTypes, got.Columns[name].MixedTypes; !reflect.DeepEqual(g, w) {
+			t.Errorf("%s: expected %+v, got %+v", name, w, g)
+		}
+	}
+}
+
+func hasIssue(col *ColumnProfile, issueType string) bool {
+	for _, issue := range col.QualityIssues {
+		if issue.Type == issueType {
+			return true
+		}
+	}
+	return false
+}
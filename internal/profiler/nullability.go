@@ -0,0 +1,292 @@
+package profiler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Nullability kinds.
+const (
+	NullabilityNotNull     = "not_null"
+	NullabilityNullable    = "nullable"
+	NullabilityMostlyNull  = "mostly_null"
+	NullabilityConditional = "conditional"
+)
+
+// Confidence levels of an inferred nullability.
+const (
+	ConfidenceLow    = "low"
+	ConfidenceMedium = "medium"
+	ConfidenceHigh   = "high"
+)
+
+const (
+	maxConditionValues  = 20 // distinct values of a column that can explain nulls in another
+	maxConditionColumns = 50 // columns considered as conditions, in header order
+	minConditionRows    = 20 // rows outside a condition needed before it is trusted
+	minConditionPercent = 50 // share of the condition rows that must be null, %
+)
+
+// Nullability is the null contract inferred for a column: whether it can be
+// declared NOT NULL, is nullable, mostly null, or null only for certain
+// values of another column.
+type Nullability struct {
+	Kind       string
+	Confidence string
+	Condition  *NullCondition // set for NullabilityConditional
+}
+
+// NullCondition says a column is null only in rows where Column holds one
+// of Values. An empty value stands for a missing one.
+type NullCondition struct {
+	Column      string
+	Values      []string
+	NullPercent float64 // share of the rows matching the condition that are null
+}
+
+func (n *Nullability) String() string {
+	var contract string
+	switch n.Kind {
+	case NullabilityNotNull:
+		contract = "NOT NULL"
+	case NullabilityMostlyNull:
+		contract = "mostly null"
+	case NullabilityConditional:
+		contract = "null only when " + n.Condition.String()
+	default:
+		contract = "nullable"
+	}
+	return fmt.Sprintf("%s (%s confidence)", contract, n.Confidence)
+}
+
+func (c *NullCondition) String() string {
+	values := make([]string, len(c.Values))
+	missing := false
+	for i, value := range c.Values {
+		if value == "" {
+			missing = true
+		}
+		values[i] = fmt.Sprintf("'%s'", value)
+	}
+
+	switch {
+	case missing && len(values) == 1:
+		return c.Column + " is missing"
+	case missing:
+		values = values[1:]
+		return fmt.Sprintf("%s is missing or %s", c.Column, joinAlternatives(values))
+	default:
+		return fmt.Sprintf("%s is %s", c.Column, joinAlternatives(values))
+	}
+}
+
+func joinAlternatives(values []string) string {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return strings.Join(values[:len(values)-1], ", ") + " or " + values[len(values)-1]
+}
+
+// rowConfidence grades a judgment by the number of rows behind it.
+func rowConfidence(rows int) string {
+	switch {
+	case rows >= 1000:
+		return ConfidenceHigh
+	case rows >= 100:
+		return ConfidenceMedium
+	default:
+		return ConfidenceLow
+	}
+}
+
+// sampled caps the confidence of a judgment made from a sample of the rows.
+func (n *Nullability) sampled() {
+	if n.Confidence == ConfidenceHigh {
+		n.Confidence = ConfidenceMedium
+	}
+}
+
+// inferNullability classifies a column from its missing count. cond is the
+// best condition explaining its nulls, if any.
+func inferNullability(missing, rows int, cond *nullConditionCandidate, t Thresholds) *Nullability {
+	if rows == 0 {
+		return nil
+	}
+
+	percent := float64(missing) / float64(rows) * 100
+	switch {
+	case missing == 0:
+		return &Nullability{Kind: NullabilityNotNull, Confidence: rowConfidence(rows)}
+	case cond != nil:
+		confidence := rowConfidence(cond.outside)
+		if cond.condition.NullPercent < 95 && confidence == ConfidenceHigh {
+			confidence = ConfidenceMedium
+		}
+		return &Nullability{Kind: NullabilityConditional, Confidence: confidence, Condition: cond.condition}
+	case percent > t.MostlyNullPercent:
+		return &Nullability{Kind: NullabilityMostlyNull, Confidence: rowConfidence(rows)}
+	default:
+		return &Nullability{Kind: NullabilityNullable, Confidence: rowConfidence(rows)}
+	}
+}
+
+// nullTracker counts, for each low-cardinality column, the rows holding each
+// of its values and how many of those rows are null in every other column,
+// which is what it takes to find columns that are null only for certain
+// values of another. Columns with too many distinct values are dropped.
+type nullTracker struct {
+	conditions []*conditionColumn // by header index, nil when not a candidate
+}
+
+type conditionColumn struct {
+	values   map[string]*conditionValue
+	overflow bool
+}
+
+type conditionValue struct {
+	rows  int
+	nulls map[int]int // header index to rows that are null there
+}
+
+// newNullTracker considers the first maxConditionColumns distinct columns of
+// header. Later duplicates of a name are ignored.
+func newNullTracker(header []string) *nullTracker {
+	t := &nullTracker{conditions: make([]*conditionColumn, len(header))}
+
+	seen := make(map[string]bool)
+	for i, name := range header {
+		if seen[name] || len(seen) == maxConditionColumns {
+			continue
+		}
+		seen[name] = true
+		t.conditions[i] = &conditionColumn{values: make(map[string]*conditionValue)}
+	}
+
+	return t
+}
+
+// add records a row whose empty values are at the header indexes in nulls.
+func (t *nullTracker) add(record []string, nulls []int) {
+	for i, cond := range t.conditions {
+		if cond == nil || cond.overflow {
+			continue
+		}
+
+		value := ""
+		if i < len(record) {
+			value = record[i]
+		}
+
+		v := cond.value(value)
+		if v == nil {
+			continue
+		}
+		v.rows++
+		for _, j := range nulls {
+			if j != i {
+				v.nulls[j]++
+			}
+		}
+	}
+}
+
+// value returns the counts of a value, or nil once the column has too many.
+func (c *conditionColumn) value(value string) *conditionValue {
+	v, ok := c.values[value]
+	if ok {
+		return v
+	}
+	if len(c.values) == maxConditionValues {
+		c.overflow = true
+		c.values = nil
+		return nil
+	}
+
+	v = &conditionValue{nulls: make(map[int]int)}
+	c.values[value] = v
+	return v
+}
+
+func (t *nullTracker) merge(o *nullTracker) {
+	for i, cond := range t.conditions {
+		if cond == nil || cond.overflow {
+			continue
+		}
+
+		other := o.conditions[i]
+		if other.overflow {
+			cond.overflow = true
+			cond.values = nil
+			continue
+		}
+
+		for value, ov := range other.values {
+			v := cond.value(value)
+			if v == nil {
+				break
+			}
+			v.rows += ov.rows
+			for j, n := range ov.nulls {
+				v.nulls[j] += n
+			}
+		}
+	}
+}
+
+// nullConditionCandidate is a condition along with the rows outside it, in
+// which the column was never null.
+type nullConditionCandidate struct {
+	condition *NullCondition
+	outside   int
+}
+
+// condition finds the column whose values best explain the nulls of the
+// column at header index col: every null falls on a proper subset of its
+// values, most rows with those values are null, and enough rows fall
+// outside. The highest null share wins, then the most rows outside.
+func (t *nullTracker) condition(col, missing, rows int, header []string, thresholds Thresholds) *nullConditionCandidate {
+	var best *nullConditionCandidate
+	bestPercent := 0.0
+
+	for i, cond := range t.conditions {
+		if i == col || cond == nil || cond.overflow || len(cond.values) < 2 {
+			continue
+		}
+		if !thresholds.isCategorical(len(cond.values), rows) {
+			continue
+		}
+
+		values := make([]string, 0)
+		inside, nulls := 0, 0
+		for value, v := range cond.values {
+			if v.nulls[col] > 0 {
+				values = append(values, value)
+				inside += v.rows
+				nulls += v.nulls[col]
+			}
+		}
+
+		outside := rows - inside
+		if nulls != missing || len(values) == len(cond.values) || outside < minConditionRows {
+			continue
+		}
+
+		percent := float64(nulls) / float64(inside) * 100
+		if percent < minConditionPercent {
+			continue
+		}
+		if best != nil && (percent < bestPercent || percent == bestPercent && outside <= best.outside) {
+			continue
+		}
+
+		sort.Strings(values)
+		best = &nullConditionCandidate{
+			condition: &NullCondition{Column: header[i], Values: values, NullPercent: percent},
+			outside:   outside,
+		}
+		bestPercent = percent
+	}
+
+	return best
+}
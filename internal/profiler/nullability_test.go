@@ -0,0 +1,137 @@
+package profiler
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestInferNullability(t *testing.T) {
+	thresholds := DefaultThresholds()
+	cond := &nullConditionCandidate{
+		condition: &NullCondition{Column: "status", Values: []string{"open"}, NullPercent: 100},
+		outside:   2000,
+	}
+
+	tests := []struct {
+		missing, rows int
+		cond          *nullConditionCandidate
+		want          string
+	}{
+		{0, 5000, nil, "NOT NULL (high confidence)"},
+		{0, 500, nil, "NOT NULL (medium confidence)"},
+		{0, 20, nil, "NOT NULL (low confidence)"},
+		{10, 500, nil, "nullable (medium confidence)"},
+		{400, 500, nil, "mostly null (medium confidence)"},
+		{3000, 5000, cond, "null only when status is 'open' (high confidence)"},
+	}
+
+	for _, tt := range tests {
+		got := inferNullability(tt.missing, tt.rows, tt.cond, thresholds)
+		if got.String() != tt.want {
+			t.Errorf("inferNullability(%d, %d): expected %q, got %q", tt.missing, tt.rows, tt.want, got)
+		}
+	}
+
+	if got := inferNullability(0, 0, nil, thresholds); got != nil {
+		t.Errorf("Expected no nullability without rows, got %v", got)
+	}
+}
+
+func TestNullConditionString(t *testing.T) {
+	tests := []struct {
+		values []string
+		want   string
+	}{
+		{[]string{"a"}, "kind is 'a'"},
+		{[]string{"a", "b", "c"}, "kind is 'a', 'b' or 'c'"},
+		{[]string{""}, "kind is missing"},
+		{[]string{"", "a"}, "kind is missing or 'a'"},
+	}
+
+	for _, tt := range tests {
+		cond := &NullCondition{Column: "kind", Values: tt.values}
+		if got := cond.String(); got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+// nullabilityCSV returns orders where shipped_at is set only for
+// shipped orders, refund_reason is mostly null with no pattern and
+// coupon is sometimes missing in every status.
+func nullabilityCSV(rows int) string {
+	statuses := []string{"pending", "shipped", "shipped", "cancelled"}
+	var b strings.Builder
+	b.WriteString("id,status,shipped_at,refund_reason,coupon\n")
+	for i := 0; i < rows; i++ {
+		status := statuses[i%len(statuses)]
+		shipped, reason, coupon := "", "", "SAVE10"
+		if status == "shipped" {
+			shipped = fmt.Sprintf("2024-01-%02d", i%28+1)
+		}
+		if i%10 == 0 {
+			reason = "damaged"
+		}
+		if i%3 == 0 {
+			coupon = ""
+		}
+		fmt.Fprintf(&b, "%d,%s,%s,%s,%s\n", i, status, shipped, reason, coupon)
+	}
+	return b.String()
+}
+
+func TestProfileNullability(t *testing.T) {
+	profile, err := ProfileDataset(writeTestFile(t, "data.csv", nullabilityCSV(400)))
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+
+	want := map[string]string{
+		"id":            "NOT NULL (medium confidence)",
+		"status":        "NOT NULL (medium confidence)",
+		"shipped_at":    "null only when status is 'cancelled' or 'pending' (medium confidence)",
+		"refund_reason": "mostly null (medium confidence)",
+		"coupon":        "nullable (medium confidence)",
+	}
+	for name, contract := range want {
+		if got := profile.Columns[name].Nullability; got.String() != contract {
+			t.Errorf("%s: expected %q, got %q", name, contract, got)
+		}
+	}
+
+	cond := profile.Columns["shipped_at"].Nullability.Condition
+	if cond.NullPercent != 100 {
+		t.Errorf("Expected shipped_at null in every unshipped order, got %.1f%%", cond.NullPercent)
+	}
+}
+
+func TestProfileNullabilitySampled(t *testing.T) {
+	profile, err := ProfileDatasetWithOptions(writeTestFile(t, "data.csv", nullabilityCSV(4000)), Options{SampleSize: 2000, SampleStrategy: SampleSystematic})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+
+	if got := profile.Columns["id"].Nullability; got.Kind != NullabilityNotNull || got.Confidence != ConfidenceMedium {
+		t.Errorf("Expected NOT NULL with confidence capped at medium for a sample, got %v", got)
+	}
+}
+
+func TestNullTrackerOverflow(t *testing.T) {
+	header := []string{"id", "value"}
+	tracker := newNullTracker(header)
+	for i := 0; i <= maxConditionValues; i++ {
+		tracker.add([]string{fmt.Sprint(i), ""}, []int{1})
+	}
+
+	if !tracker.conditions[0].overflow || tracker.conditions[1].overflow {
+		t.Errorf("Expected only the id column to overflow")
+	}
+
+	other := newNullTracker(header)
+	other.add([]string{"x", "y"}, nil)
+	tracker.merge(other)
+	if got := len(tracker.conditions[1].values); got != 2 {
+		t.Errorf("Expected 2 values of value after the merge, got %d", got)
+	}
+}
@@ -0,0 +1,116 @@
+package profiler
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func nullValuesCSV(rows int) string {
+	var content strings.Builder
+	content.WriteString("id,amount,status\n")
+	for i := 0; i < rows; i++ {
+		amount := fmt.Sprintf("%d.5", i%50)
+		switch i % 10 {
+		case 3:
+			amount = "NA"
+		case 7:
+			amount = "-"
+		}
+		status := "open"
+		if i%4 == 0 {
+			status = "NULL"
+		}
+		fmt.Fprintf(&content, "%d,%s,%s\n", i, amount, status)
+	}
+	return content.String()
+}
+
+func TestProfileNullValues(t *testing.T) {
+	path := writeTestFile(t, "orders.csv", nullValuesCSV(400))
+
+	profile, err := ProfileDatasetWithOptions(path, Options{})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if col := profile.Columns["amount"]; col.DataType == "float" || col.MissingCount != 0 {
+		t.Fatalf("Expected placeholders to be values without null values, got %s with %d missing", col.DataType, col.MissingCount)
+	}
+
+	profile, err = ProfileDatasetWithOptions(path, Options{NullValues: []string{"NA", "-", "NULL"}})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	amount := profile.Columns["amount"]
+	if amount.DataType != "float" || amount.MissingCount != 80 || amount.MixedTypes != nil {
+		t.Errorf("Expected a float column with 80 missing values, got %s with %d missing", amount.DataType, amount.MissingCount)
+	}
+	if status := profile.Columns["status"]; status.MissingCount != 100 || status.UniqueCount != 1 {
+		t.Errorf("Expected NULL read as missing, got %d missing and %d unique", status.MissingCount, status.UniqueCount)
+	}
+	if profile.MissingCells != 180 {
+		t.Errorf("Expected 180 missing cells, got %d", profile.MissingCells)
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{NullValues: []string{""}}); err == nil {
+		t.Errorf("Expected an empty null value rejected")
+	}
+}
+
+func TestProfileNullValuesParallel(t *testing.T) {
+	withParallelMinChunk(t, 256)
+	path := writeTestFile(t, "orders.csv", nullValuesCSV(4000))
+	opts := Options{NullValues: []string{"NA", "-", "NULL"}}
+
+	want, err := ProfileDatasetWithOptions(path, opts)
+	if err != nil {
+		t.Fatalf("Failed to profile sequentially: %v", err)
+	}
+	opts.Parallel = 8
+	got, err := ProfileDatasetWithOptions(path, opts)
+	if err != nil {
+		t.Fatalf("Failed to profile in parallel: %v", err)
+	}
+
+	if got.MissingCells != want.MissingCells {
+		t.Errorf("Expected %d missing cells, got %d", want.MissingCells, got.MissingCells)
+	}
+	for _, name := range []string{"amount", "status"} {
+		w, g := want.Columns[name], got.Columns[name]
+		if g.DataType != w.DataType || g.MissingCount != w.MissingCount || g.UniqueCount != w.UniqueCount {
+			t.Errorf("%s: expected %s with %d missing, got %s with %d missing", name, w.DataType, w.MissingCount, g.DataType, g.MissingCount)
+		}
+	}
+}
+
+func TestProfileParquetNullValues(t *testing.T) {
+	rows := make([]parquetDictRow, 0, 100)
+	for i := 0; i < 100; i++ {
+		region := "north"
+		if i%5 == 0 {
+			region = "unknown"
+		}
+		rows = append(rows, parquetDictRow{ID: int64(i), Region: region, Score: int64(i % 7)})
+	}
+	path := filepath.Join(t.TempDir(), "regions.parquet")
+	if err := parquet.WriteFile(path, rows, parquet.MaxRowsPerRowGroup(40)); err != nil {
+		t.Fatalf("Failed to write Parquet file: %v", err)
+	}
+
+	profile, err := ProfileDatasetWithOptions(path, Options{NullValues: []string{"unknown"}})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	region := profile.Columns["region"]
+	if region.MissingCount != 20 || region.UniqueCount != 1 {
+		t.Errorf("Expected the dictionary value read as missing, got %d missing and %d unique", region.MissingCount, region.UniqueCount)
+	}
+	for _, v := range region.TopValues {
+		if v.Value == "unknown" {
+			t.Errorf("Expected no counts of the null value, got %+v", region.TopValues)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package profiler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Number format profiles: the separators of thousands and decimals.
+const (
+	NumberFormatUS = "us" // 1,234,567.89
+	NumberFormatEU = "eu" // 1.234.567,89
+	NumberFormatIN = "in" // 12,34,567.89, grouped in lakhs and crores
+)
+
+// numberFormat parses numbers written with thousands separators and a
+// decimal separator other than a point. The zero value is the plain format
+// strconv understands.
+type numberFormat struct {
+	name      string
+	thousands byte
+	decimal   byte
+	indian    bool // groups of two digits before the last three
+}
+
+// numberFormats are tried in this order when detecting the format of a
+// column, so a column that reads the same in several picks the first.
+var numberFormats = []numberFormat{
+	{name: NumberFormatUS, thousands: ',', decimal: '.'},
+	{name: NumberFormatIN, thousands: ',', decimal: '.', indian: true},
+	{name: NumberFormatEU, thousands: '.', decimal: ','},
+}
+
+// NumberFormatNames lists the supported number formats.
+func NumberFormatNames() []string {
+	names := make([]string, len(numberFormats))
+	for i, f := range numberFormats {
+		names[i] = f.name
+	}
+	return names
+}
+
+func lookupNumberFormat(name string) (numberFormat, error) {
+	if name == "" {
+		return numberFormat{}, nil
+	}
+	for _, f := range numberFormats {
+		if strings.EqualFold(f.name, name) {
+			return f, nil
+		}
+	}
+	return numberFormat{}, fmt.Errorf("unsupported number format: %s (use %s)", name, strings.Join(NumberFormatNames(), ", "))
+}
+
+// numberFormatNamed returns the format of a column profile, plain for an
+// unknown name.
+func numberFormatNamed(name string) numberFormat {
+	f, _ := lookupNumberFormat(name)
+	return f
+}
+
+// normalize rewrites value in the plain format, checking that its digits
+// are grouped as the format groups them.
+func (f numberFormat) normalize(value string) (string, bool) {
+	if f.name == "" || (strings.IndexByte(value, f.thousands) < 0 && strings.IndexByte(value, f.decimal) < 0) {
+		return value, true
+	}
+
+	sign := ""
+	if value[0] == '-' || value[0] == '+' {
+		sign, value = value[:1], value[1:]
+	}
+
+	integer, fraction, hasFraction := strings.Cut(value, string(f.decimal))
+	if strings.IndexByte(fraction, f.thousands) >= 0 || strings.IndexByte(fraction, f.decimal) >= 0 {
+		return "", false
+	}
+
+	if strings.IndexByte(integer, f.thousands) >= 0 {
+		groups := strings.Split(integer, string(f.thousands))
+		if !f.grouped(groups) {
+			return "", false
+		}
+		integer = strings.Join(groups, "")
+	}
+
+	if hasFraction {
+		return sign + integer + "." + fraction, true
+	}
+	return sign + integer, true
+}
+
+// grouped reports whether the digit groups of an integer part are sized as
+// the format writes them: up to three leading digits then groups of three,
+// or in the Indian format up to two leading digits then groups of two
+// before a last group of three.
+func (f numberFormat) grouped(groups []string) bool {
+	for i, group := range groups {
+		if !allDigits(group) {
+			return false
+		}
+
+		size := 3
+		if f.indian && i < len(groups)-1 {
+			size = 2
+		}
+		if i == 0 {
+			if len(group) == 0 || len(group) > size {
+				return false
+			}
+		} else if len(group) != size {
+			return false
+		}
+	}
+	return true
+}
+
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (f numberFormat) parseFloat(value string) (float64, bool) {
+	plain, ok := f.normalize(value)
+	if !ok {
+		return 0, false
+	}
+	x, err := strconv.ParseFloat(plain, 64)
+	return x, err == nil && !math.IsNaN(x) && !math.IsInf(x, 0)
+}
+
+// nonFinite reports whether value reads as NaN or an infinity, which
+// parseFloat rejects since no statistic can use them. Numbers too large
+// for a float64 overflow to infinity.
+func nonFinite(value string) bool {
+	x, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	return (err == nil || errors.Is(err, strconv.ErrRange)) && (math.IsNaN(x) || math.IsInf(x, 0))
+}
+
+func (f numberFormat) parseInt(value string) bool {
+	plain, ok := f.normalize(value)
+	if !ok {
+		return false
+	}
+	_, err := strconv.ParseInt(plain, 10, 64)
+	return err == nil
+}
+
+// detectNumberFormat picks the format of the numbers in a sample. Only
+// values plain parsing rejects, such as 1,234 or 3,5, say anything about
+// the format. A format fits when the sample reads as numbers in it and it
+// parses every value another format does. The sample is plain when no
+// format fits, or when formats that fit read the values differently, in
+// which case the note explains why.
+func detectNumberFormat(sample []string) (numberFormat, string) {
+	separated := false
+	for _, value := range sample {
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			continue
+		}
+		for _, f := range numberFormats {
+			if _, ok := f.parseFloat(value); ok {
+				separated = true
+			}
+		}
+	}
+	if !separated {
+		return numberFormat{}, ""
+	}
+
+	fits := make([]numberFormat, 0, len(numberFormats))
+	for _, f := range numberFormats {
+		dataType := inferDataTypeWith(sample, f, dateFormat{})
+		if dataType != "integer" && dataType != "float" {
+			continue
+		}
+		if parsesAllNumbers(f, sample) {
+			fits = append(fits, f)
+		}
+	}
+
+	switch {
+	case len(fits) == 0:
+		return numberFormat{}, ""
+	case agree(fits, sample):
+		return fits[0], ""
+	}
+
+	names := make([]string, len(fits))
+	for i, f := range fits {
+		names[i] = f.name
+	}
+	return numberFormat{}, fmt.Sprintf("Ambiguous number format (%s): values parsed as plain numbers, set --number-format",
+		strings.Join(names, ", "))
+}
+
+// parsesAllNumbers reports whether f parses every value of the sample that
+// plain parsing or another format does.
+func parsesAllNumbers(f numberFormat, sample []string) bool {
+	for _, value := range sample {
+		if _, ok := f.parseFloat(value); ok {
+			continue
+		}
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return false
+		}
+		for _, other := range numberFormats {
+			if _, ok := other.parseFloat(value); ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// agree reports whether the formats read every value of the sample as the
+// same number.
+func agree(formats []numberFormat, sample []string) bool {
+	for _, value := range sample {
+		first, ok := formats[0].parseFloat(value)
+		for _, f := range formats[1:] {
+			if x, fok := f.parseFloat(value); fok != ok || x != first {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package profiler
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestNumberFormatParse(t *testing.T) {
+	tests := []struct {
+		format string
+		value  string
+		want   float64
+		ok     bool
+	}{
+		{"", "1234.5", 1234.5, true},
+		{"", "1,234", 0, false},
+		{NumberFormatUS, "1,234,567.89", 1234567.89, true},
+		{NumberFormatUS, "-1,234", -1234, true},
+		{NumberFormatUS, "1234.5", 1234.5, true},
+		{NumberFormatUS, "1,2345", 0, false},
+		{NumberFormatUS, "12,34,567", 0, false},
+		{NumberFormatUS, "1,234.5.6", 0, false},
+		{NumberFormatEU, "1.234.567,89", 1234567.89, true},
+		{NumberFormatEU, "3,5", 3.5, true},
+		{NumberFormatEU, "1.234", 1234, true},
+		{NumberFormatEU, "1.5", 0, false},
+		{NumberFormatIN, "12,34,567.89", 1234567.89, true},
+		{NumberFormatIN, "1,00,00,000", 10000000, true},
+		{NumberFormatIN, "1,234", 1234, true},
+		{NumberFormatIN, "123,456", 0, false},
+		{NumberFormatIN, "1,234,567", 0, false},
+	}
+	for _, tt := range tests {
+		f, err := lookupNumberFormat(tt.format)
+		if err != nil {
+			t.Fatalf("Failed to look up %q: %v", tt.format, err)
+		}
+		got, ok := f.parseFloat(tt.value)
+		if ok != tt.ok || (ok && math.Abs(got-tt.want) > 1e-9) {
+			t.Errorf("%q in %q: expected %v (%v), got %v (%v)", tt.value, tt.format, tt.want, tt.ok, got, ok)
+		}
+	}
+
+	if _, err := lookupNumberFormat("fr"); err == nil {
+		t.Error("Expected an error for an unknown number format")
+	}
+}
+
+func TestDetectNumberFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample []string
+		want   string
+		note   bool
+	}{
+		{"plain", []string{"1", "2.5", "1000"}, "", false},
+		{"us", []string{"1,234.50", "12,500,000", "7"}, NumberFormatUS, false},
+		{"eu", []string{"1.234,50", "3,5", "12"}, NumberFormatEU, false},
+		{"in", []string{"12,34,567", "1,00,000", "999"}, NumberFormatIN, false},
+		{"ambiguous", []string{"1,234", "5,678"}, "", true},
+		{"text", []string{"red, green", "blue", "1,234"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, note := detectNumberFormat(tt.sample)
+			if got.name != tt.want {
+				t.Errorf("Expected format %q, got %q", tt.want, got.name)
+			}
+			if (note != "") != tt.note {
+				t.Errorf("Expected note %v, got %q", tt.note, note)
+			}
+		})
+	}
+}
+
+func numberFormatCSV(rows int) string {
+	var b strings.Builder
+	b.WriteString("id;amount;lakhs;ambiguous\n")
+	for i := 0; i < rows; i++ {
+		fmt.Fprintf(&b, "%d;%d.%03d,%02d;%d,%02d,%03d;%d,%03d\n", i, i%50+1, i%1000, i%100, i%9+1, i%100, i%1000, i%9+1, i%1000)
+	}
+	return b.String()
+}
+
+func TestProfileNumberFormats(t *testing.T) {
+	path := writeTestFile(t, "umsatz.csv", numberFormatCSV(500))
+
+	profile, err := ProfileDatasetWithOptions(path, Options{})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+
+	amount := profile.Columns["amount"]
+	if amount.DataType != "float" || amount.NumberFormat != NumberFormatEU {
+		t.Fatalf("Expected an eu float column, got %s in %q", amount.DataType, amount.NumberFormat)
+	}
+	if min, ok := amount.Min.(float64); !ok || min != 1000 {
+		t.Errorf("Expected a minimum of 1000, got %v", amount.Min)
+	}
+
+	lakhs := profile.Columns["lakhs"]
+	if lakhs.DataType != "integer" || lakhs.NumberFormat != NumberFormatIN {
+		t.Errorf("Expected an in integer column, got %s in %q", lakhs.DataType, lakhs.NumberFormat)
+	}
+
+	ambiguous := profile.Columns["ambiguous"]
+	if ambiguous.DataType != "string" || !strings.Contains(strings.Join(ambiguous.Notes, " "), "Ambiguous number format (us, in, eu)") {
+		t.Errorf("Expected an ambiguous string column, got %s with notes %v", ambiguous.DataType, ambiguous.Notes)
+	}
+
+	profile, err = ProfileDatasetWithOptions(path, Options{NumberFormat: NumberFormatUS})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if col := profile.Columns["ambiguous"]; col.DataType != "integer" || col.NumberFormat != NumberFormatUS {
+		t.Errorf("Expected a us integer column with --number-format us, got %s in %q", col.DataType, col.NumberFormat)
+	}
+	if col := profile.Columns["amount"]; col.IsNumeric {
+		t.Errorf("Expected eu amounts not to read as us numbers, got %s", col.DataType)
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{NumberFormat: "fr"}); err == nil {
+		t.Error("Expected an error for an unknown number format")
+	}
+}
+
+func TestProfileNumberFormatsParallel(t *testing.T) {
+	withParallelMinChunk(t, 256)
+	path := writeTestFile(t, "umsatz.csv", numberFormatCSV(2000))
+
+	want, err := ProfileDatasetWithOptions(path, Options{})
+	if err != nil {
+		t.Fatalf("Failed to profile sequentially: %v", err)
+	}
+	got, err := ProfileDatasetWithOptions(path, Options{Parallel: 8})
+	if err != nil {
+		t.Fatalf("Failed to profile in parallel: %v", err)
+	}
+
+	for _, name := range []string{"amount", "lakhs"} {
+		w, g := want.Columns[name], got.Columns[name]
+		if g.NumberFormat != w.NumberFormat || g.Min != w.Min || g.Max != w.Max || math.Abs(g.Mean-w.Mean) > 1e-6 {
+			t.Errorf("%s: expected %q %v-%v mean %v, got %q %v-%v mean %v",
+				name, w.NumberFormat, w.Min, w.Max, w.Mean, g.NumberFormat, g.Min, g.Max, g.Mean)
+		}
+	}
+}
@@ -0,0 +1,404 @@
+package profiler
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+const exactNumericLimit = 10000
+
+// PercentileRanks are the percentiles reported for numeric columns.
+var PercentileRanks = []int{1, 5, 25, 75, 95, 99}
+
+// numericStats accumulates numeric statistics in a single pass. Mean and
+// the central moments behind variance, skewness and kurtosis are updated
+// with the pairwise formulas of Chan and Pébay, of which Welford's method is
+// the single value case. Values are kept for an exact median and histogram
+// until exactNumericLimit, after which a t-digest takes over.
+type numericStats struct {
+	count      int
+	fractional int // values that are not whole numbers
+	integers   int // values written as integers, such as 42 but not 42.0
+	nonFinite  int // NaN and infinite values, left out of the statistics
+	mean       float64
+	m2         float64
+	m3         float64
+	m4         float64
+	min        float64
+	max        float64
+	exact      []float64
+	digest     *tDigest
+}
+
+func newNumericStats() *numericStats {
+	return &numericStats{exact: make([]float64, 0)}
+}
+
+func (s *numericStats) add(x float64) {
+	s.addN(x, 1)
+}
+
+// addValue adds value when it reads as a number in format, and counts it
+// when it reads as NaN or an infinity.
+func (s *numericStats) addValue(value string, format numberFormat) {
+	if x, ok := format.parseFloat(value); ok {
+		s.add(x)
+		if x == math.Trunc(x) && format.parseInt(value) {
+			s.integers++
+		}
+	} else if nonFinite(value) {
+		s.nonFinite++
+	}
+}
+
+func (s *numericStats) addN(x float64, n int) {
+	if s.count == 0 || x < s.min {
+		s.min = x
+	}
+	if s.count == 0 || x > s.max {
+		s.max = x
+	}
+
+	s.combine(n, x, 0, 0, 0)
+	if x != math.Trunc(x) {
+		s.fractional += n
+	}
+
+	if s.digest == nil && s.count > exactNumericLimit {
+		s.digest = newTDigest(tDigestCompression)
+		for _, v := range s.exact {
+			s.digest.add(v, 1)
+		}
+		s.exact = nil
+	}
+
+	if s.digest != nil {
+		s.digest.add(x, float64(n))
+		return
+	}
+
+	for i := 0; i < n; i++ {
+		s.exact = append(s.exact, x)
+	}
+}
+
+// combine folds in the moments of another group of n values.
+func (s *numericStats) combine(n int, mean, m2, m3, m4 float64) {
+	na, nb := float64(s.count), float64(n)
+	total := na + nb
+	delta := mean - s.mean
+	d2 := delta * delta
+
+	s.m4 += m4 + d2*d2*na*nb*(na*na-na*nb+nb*nb)/(total*total*total) +
+		6*d2*(na*na*m2+nb*nb*s.m2)/(total*total) + 4*delta*(na*m3-nb*s.m3)/total
+	s.m3 += m3 + d2*delta*na*nb*(na-nb)/(total*total) + 3*delta*(na*m2-nb*s.m2)/total
+	s.m2 += m2 + d2*na*nb/total
+	s.mean += delta * nb / total
+	s.count += n
+}
+
+// merge folds in the values counted by o.
+func (s *numericStats) merge(o *numericStats) {
+	s.nonFinite += o.nonFinite
+	if o.count == 0 {
+		return
+	}
+
+	if s.count == 0 || o.min < s.min {
+		s.min = o.min
+	}
+	if s.count == 0 || o.max > s.max {
+		s.max = o.max
+	}
+
+	s.combine(o.count, o.mean, o.m2, o.m3, o.m4)
+	s.fractional += o.fractional
+	s.integers += o.integers
+
+	if s.digest == nil && o.digest == nil && s.count <= exactNumericLimit {
+		s.exact = append(s.exact, o.exact...)
+		return
+	}
+
+	if s.digest == nil {
+		s.digest = newTDigest(tDigestCompression)
+		for _, v := range s.exact {
+			s.digest.add(v, 1)
+		}
+		s.exact = nil
+	}
+
+	if o.digest != nil {
+		s.digest.merge(o.digest)
+		return
+	}
+	for _, v := range o.exact {
+		s.digest.add(v, 1)
+	}
+}
+
+func (s *numericStats) approximate() bool {
+	return s.digest != nil
+}
+
+// apply fills in the numeric statistics of col, with a histogram binned
+// as hist asks and outliers found as thresholds set.
+func (s *numericStats) apply(col *ColumnProfile, hist histogramSpec, thresholds Thresholds) {
+	if s.count == 0 {
+		return
+	}
+
+	stdDev := math.Sqrt(s.m2 / float64(s.count))
+
+	col.Min = s.min
+	col.Max = s.max
+	col.Mean = s.mean
+	col.StdDev = stdDev
+	if s.m2 > 0 {
+		n := float64(s.count)
+		col.Skewness = math.Sqrt(n) * s.m3 / math.Pow(s.m2, 1.5)
+		col.Kurtosis = n*s.m4/(s.m2*s.m2) - 3
+	}
+
+	binning, note := hist.binningFor(s.min, s.max, col.Skewness, thresholds)
+	col.HistogramBinning = binning
+	if note != "" {
+		col.Notes = append(col.Notes, note)
+	}
+
+	if s.digest != nil {
+		col.Median = s.digest.quantile(0.5)
+		col.Percentiles = percentiles(s.digest.quantile)
+		// Values are no longer kept, so the mode is the top counted value
+		if len(col.TopValues) > 0 && col.TopValues[0].Count > 1 {
+			if mode, ok := numberFormatNamed(col.NumberFormat).parseFloat(col.TopValues[0].Value); ok {
+				col.Mode = mode
+			}
+		}
+		col.HistogramBuckets = s.estimatedHistogram(s.bucketBounds(binning, hist.buckets, s.digest.quantile))
+		mad := func(median float64) float64 { return medianAbsoluteDeviation(s.sorted(), median) }
+		if outliers, ok := outlierBounds(thresholds, s.mean, stdDev, s.digest.quantile, mad); ok {
+			s.estimateOutliers(outliers)
+			col.Outliers = outliers
+		}
+		col.Notes = append(col.Notes, fmt.Sprintf(
+			"Median, percentiles, histogram and outliers estimated from a t-digest over %d values", s.count))
+	} else {
+		sorted := append([]float64(nil), s.exact...)
+		sort.Float64s(sorted)
+
+		mid := len(sorted) / 2
+		if len(sorted)%2 == 0 {
+			col.Median = (sorted[mid-1] + sorted[mid]) / 2
+		} else {
+			col.Median = sorted[mid]
+		}
+		col.Percentiles = percentiles(func(q float64) float64 { return sortedQuantile(sorted, q) })
+		if mode, ok := sortedMode(sorted); ok {
+			col.Mode = mode
+		}
+
+		if binning == HistogramEqualWidth {
+			col.HistogramBuckets = s.exactHistogram(hist.buckets)
+		} else {
+			rank := func(q float64) float64 { return sortedRank(sorted, q) }
+			col.HistogramBuckets = sortedHistogram(sorted, s.bucketBounds(binning, hist.buckets, rank))
+		}
+		quantile := func(q float64) float64 { return sortedQuantile(sorted, q) }
+		mad := func(median float64) float64 { return medianAbsoluteDeviation(sorted, median) }
+		if outliers, ok := outlierBounds(thresholds, s.mean, stdDev, quantile, mad); ok {
+			outliers.exactOutliers(sorted)
+			col.Outliers = outliers
+		}
+	}
+
+	flagNumericIssues(col, s.count, thresholds)
+}
+
+// flagNumericIssues adds the quality issues of the skewness and outliers of
+// col, a numeric column of count values.
+func flagNumericIssues(col *ColumnProfile, count int, thresholds Thresholds) {
+	if math.Abs(col.Skewness) > thresholds.SkewnessAbs {
+		direction := "right"
+		if col.Skewness < 0 {
+			direction = "left"
+		}
+		col.QualityIssues = append(col.QualityIssues, QualityIssue{
+			Type:        "skewed",
+			Description: fmt.Sprintf("Heavily %s-skewed distribution (skewness %.2f)", direction, col.Skewness),
+			Severity:    1,
+		})
+	}
+
+	if col.Outliers != nil && col.Outliers.Count > 0 {
+		outlierPct := float64(col.Outliers.Count) / float64(count) * 100
+
+		col.QualityIssues = append(col.QualityIssues, QualityIssue{
+			Type:        "outliers",
+			Description: col.Outliers.description(outlierPct),
+			Severity:    thresholds.Outliers.severity(outlierPct),
+		})
+	}
+}
+
+// estimateOutliers counts the outliers under o from the tails of the
+// t-digest. The min and max are the only values still known, so they are
+// the examples when they lie outside the bounds.
+func (s *numericStats) estimateOutliers(o *OutlierSummary) {
+	tails := s.digest.cdf(o.Lower) + 1 - s.digest.cdf(o.Upper)
+	o.Count = int(math.Round(tails * float64(s.count)))
+
+	for _, v := range []float64{s.max, s.min} {
+		if o.outside(v) {
+			o.Examples = append(o.Examples, v)
+		}
+	}
+	sort.SliceStable(o.Examples, func(i, j int) bool { return o.beyond(o.Examples[i]) > o.beyond(o.Examples[j]) })
+	if o.Count < len(o.Examples) {
+		o.Count = len(o.Examples)
+	}
+}
+
+func percentiles(quantile func(q float64) float64) []Percentile {
+	result := make([]Percentile, len(PercentileRanks))
+	for i, rank := range PercentileRanks {
+		result[i] = Percentile{Rank: rank, Value: quantile(float64(rank) / 100)}
+	}
+	return result
+}
+
+// sortedQuantile interpolates linearly between the closest ranks.
+func sortedQuantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (pos-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// sortedMode returns the most frequent value, the smallest on a tie, or
+// false when no value occurs more than once.
+func sortedMode(sorted []float64) (float64, bool) {
+	mode, best := 0.0, 1
+	for i := 0; i < len(sorted); {
+		j := i + 1
+		for j < len(sorted) && sorted[j] == sorted[i] {
+			j++
+		}
+		if j-i > best {
+			mode, best = sorted[i], j-i
+		}
+		i = j
+	}
+	return mode, best > 1
+}
+
+// sortedRank returns the first value above the lowest q of the values, so
+// that bucket bounds fall on values that occur.
+func sortedRank(sorted []float64, q float64) float64 {
+	index := int(q * float64(len(sorted)))
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
+
+// histogramBounds returns n buckets of equal width from the min to the max.
+func (s *numericStats) histogramBounds(n int) []HistogramBucket {
+	bucketSize := (s.max - s.min) / float64(n)
+	buckets := make([]HistogramBucket, n)
+
+	for i := 0; i < n; i++ {
+		lower := s.min + float64(i)*bucketSize
+		upper := s.min + float64(i+1)*bucketSize
+
+		if i == n-1 {
+			upper = s.max
+		}
+
+		buckets[i] = HistogramBucket{
+			LowerBound: lower,
+			UpperBound: upper,
+		}
+	}
+
+	return buckets
+}
+
+func (s *numericStats) exactHistogram(n int) []HistogramBucket {
+	buckets := s.histogramBounds(n)
+	bucketSize := (s.max - s.min) / float64(n)
+
+	for _, v := range s.exact {
+		bucketIndex := n - 1
+		if bucketSize > 0 {
+			bucketIndex = int((v - s.min) / bucketSize)
+		}
+		if bucketIndex >= n {
+			bucketIndex = n - 1
+		}
+		buckets[bucketIndex].Count++
+	}
+
+	return buckets
+}
+
+// quantileBounds returns n equal-frequency buckets, bounded by quantiles.
+// Buckets that would be empty because of repeated values are left out, so
+// a column with few distinct values gets fewer buckets.
+func (s *numericStats) quantileBounds(n int, quantile func(q float64) float64) []HistogramBucket {
+	buckets := make([]HistogramBucket, 0, n)
+
+	lower := s.min
+	for i := 1; i <= n; i++ {
+		upper := s.max
+		if i < n {
+			upper = quantile(float64(i) / float64(n))
+		}
+		if upper <= lower && i < n {
+			continue
+		}
+		buckets = append(buckets, HistogramBucket{LowerBound: lower, UpperBound: upper})
+		lower = upper
+	}
+
+	return buckets
+}
+
+// sortedHistogram counts sorted values into buckets, each holding the
+// values from its lower bound up to but not including its upper bound, and
+// the last up to and including it.
+func sortedHistogram(sorted []float64, buckets []HistogramBucket) []HistogramBucket {
+	start := 0
+	for i := range buckets {
+		end := len(sorted)
+		if i < len(buckets)-1 {
+			end = sort.SearchFloat64s(sorted, buckets[i].UpperBound)
+		}
+		buckets[i].Count = end - start
+		start = end
+	}
+	return buckets
+}
+
+// estimatedHistogram reads bucket counts off the t-digest CDF. Counts are
+// rounded cumulatively so that they still add up to the value count.
+func (s *numericStats) estimatedHistogram(buckets []HistogramBucket) []HistogramBucket {
+
+	previous := 0
+	for i := range buckets {
+		cumulative := s.count
+		if i < len(buckets)-1 {
+			cumulative = int(math.Round(s.digest.cdf(buckets[i].UpperBound) * float64(s.count)))
+		}
+		if cumulative < previous {
+			cumulative = previous
+		}
+		buckets[i].Count = cumulative - previous
+		previous = cumulative
+	}
+
+	return buckets
+}
@@ -0,0 +1,381 @@
+package profiler
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestNumericStatsWelford(t *testing.T) {
+	stats := newNumericStats()
+	stats.addN(2, 3)
+	stats.add(10)
+
+	col := &ColumnProfile{}
+	stats.apply(col, Options{}.histogram(), DefaultThresholds())
+
+	if col.Mean != 4 {
+		t.Errorf("Expected mean 4, got %v", col.Mean)
+	}
+	if math.Abs(col.StdDev-math.Sqrt(12)) > 1e-12 {
+		t.Errorf("Expected stddev %v, got %v", math.Sqrt(12), col.StdDev)
+	}
+	if col.Median != 2 {
+		t.Errorf("Expected median 2, got %v", col.Median)
+	}
+}
+
+func TestNumericStatsApproximate(t *testing.T) {
+	stats := newNumericStats()
+	n := 3 * exactNumericLimit
+	for i := 0; i < n; i++ {
+		stats.add(float64(i))
+	}
+
+	if !stats.approximate() {
+		t.Fatal("Expected stats to switch to a t-digest")
+	}
+
+	col := &ColumnProfile{}
+	stats.apply(col, Options{}.histogram(), DefaultThresholds())
+
+	if col.Mean != float64(n-1)/2 || col.Min.(float64) != 0 || col.Max.(float64) != float64(n-1) {
+		t.Errorf("Expected exact mean, min and max, got %v, %v, %v", col.Mean, col.Min, col.Max)
+	}
+
+	if math.Abs(col.Median-float64(n)/2) > float64(n)*0.01 {
+		t.Errorf("Expected median near %d, got %v", n/2, col.Median)
+	}
+
+	total := 0
+	for _, bucket := range col.HistogramBuckets {
+		total += bucket.Count
+		if math.Abs(float64(bucket.Count)-float64(n)/10) > float64(n)*0.01 {
+			t.Errorf("Expected uniform bucket of about %d, got %d", n/10, bucket.Count)
+		}
+	}
+	if total != n {
+		t.Errorf("Expected histogram counts to add up to %d, got %d", n, total)
+	}
+
+	if len(col.Notes) == 0 {
+		t.Error("Expected a note about estimated statistics")
+	}
+}
+
+func TestNumericStatsMoments(t *testing.T) {
+	// Right-skewed: 1 repeated, then a long tail
+	values := []float64{1, 1, 1, 2, 2, 3, 4, 8, 15, 40}
+	stats := newNumericStats()
+	for _, v := range values {
+		stats.add(v)
+	}
+	col := &ColumnProfile{}
+	stats.apply(col, Options{}.histogram(), DefaultThresholds())
+
+	var mean, m2, m3, m4 float64
+	for _, v := range values {
+		mean += v / float64(len(values))
+	}
+	for _, v := range values {
+		d := v - mean
+		m2, m3, m4 = m2+d*d, m3+d*d*d, m4+d*d*d*d
+	}
+	n := float64(len(values))
+	wantSkew := math.Sqrt(n) * m3 / math.Pow(m2, 1.5)
+	wantKurt := n*m4/(m2*m2) - 3
+
+	if math.Abs(col.Skewness-wantSkew) > 1e-9 || math.Abs(col.Kurtosis-wantKurt) > 1e-9 {
+		t.Errorf("Expected skewness %v and kurtosis %v, got %v and %v", wantSkew, wantKurt, col.Skewness, col.Kurtosis)
+	}
+	if col.Mode != 1.0 {
+		t.Errorf("Expected mode 1, got %v", col.Mode)
+	}
+
+	skewed := false
+	for _, issue := range col.QualityIssues {
+		skewed = skewed || issue.Type == "skewed"
+	}
+	if !skewed {
+		t.Errorf("Expected a skewed issue for skewness %.2f, got %v", col.Skewness, col.QualityIssues)
+	}
+
+	// Merging halves gives the same moments
+	first, second := newNumericStats(), newNumericStats()
+	for i, v := range values {
+		if i < 4 {
+			first.add(v)
+		} else {
+			second.add(v)
+		}
+	}
+	first.merge(second)
+	merged := &ColumnProfile{}
+	first.apply(merged, Options{}.histogram(), DefaultThresholds())
+	if math.Abs(merged.Skewness-wantSkew) > 1e-9 || math.Abs(merged.Kurtosis-wantKurt) > 1e-9 {
+		t.Errorf("Expected merged skewness %v and kurtosis %v, got %v and %v", wantSkew, wantKurt, merged.Skewness, merged.Kurtosis)
+	}
+
+	unique := newNumericStats()
+	unique.add(1)
+	unique.add(2)
+	col = &ColumnProfile{}
+	unique.apply(col, Options{}.histogram(), DefaultThresholds())
+	if col.Mode != nil {
+		t.Errorf("Expected no mode without repeated values, got %v", col.Mode)
+	}
+}
+
+func TestNumericStatsPercentiles(t *testing.T) {
+	exact := newNumericStats()
+	for i := 0; i <= 100; i++ {
+		exact.add(float64(i))
+	}
+	col := &ColumnProfile{}
+	exact.apply(col, Options{}.histogram(), DefaultThresholds())
+
+	for _, rank := range PercentileRanks {
+		if got, ok := col.Percentile(rank); !ok || got != float64(rank) {
+			t.Errorf("Expected exact p%d of %d, got %v", rank, rank, got)
+		}
+	}
+
+	approx := newNumericStats()
+	n := 3 * exactNumericLimit
+	for i := 0; i < n; i++ {
+		approx.add(float64(i))
+	}
+	col = &ColumnProfile{}
+	approx.apply(col, Options{}.histogram(), DefaultThresholds())
+
+	for _, rank := range PercentileRanks {
+		want := float64(rank) / 100 * float64(n)
+		if got, _ := col.Percentile(rank); math.Abs(got-want) > float64(n)*0.005 {
+			t.Errorf("Expected p%d near %v, got %v", rank, want, got)
+		}
+	}
+}
+
+func TestNumericStatsConstant(t *testing.T) {
+	stats := newNumericStats()
+	stats.addN(7, 5)
+
+	col := &ColumnProfile{}
+	stats.apply(col, Options{}.histogram(), DefaultThresholds())
+
+	if col.Mean != 7 || col.StdDev != 0 || col.Median != 7 {
+		t.Errorf("Expected constant stats of 7, got mean=%v stddev=%v median=%v", col.Mean, col.StdDev, col.Median)
+	}
+
+	last := col.HistogramBuckets[len(col.HistogramBuckets)-1]
+	if last.Count != 5 {
+		t.Errorf("Expected all values in the last bucket, got %v", col.HistogramBuckets)
+	}
+}
+
+func TestNumericStatsEqualFrequency(t *testing.T) {
+	// Exponential-like skew: most values are small
+	exact, approx := newNumericStats(), newNumericStats()
+	for i := 0; i < 1000; i++ {
+		exact.add(math.Pow(1.01, float64(i)))
+	}
+	n := 3 * exactNumericLimit
+	for i := 0; i < n; i++ {
+		approx.add(math.Pow(1.0003, float64(i)))
+	}
+
+	for name, stats := range map[string]*numericStats{"exact": exact, "approximate": approx} {
+		col := &ColumnProfile{}
+		stats.apply(col, Options{Histogram: HistogramEqualFrequency}.histogram(), DefaultThresholds())
+
+		if len(col.HistogramBuckets) != DefaultHistogramBuckets {
+			t.Fatalf("%s: expected %d buckets, got %v", name, DefaultHistogramBuckets, col.HistogramBuckets)
+		}
+		total := 0
+		for i, bucket := range col.HistogramBuckets {
+			total += bucket.Count
+			if math.Abs(float64(bucket.Count)-float64(stats.count)/10) > float64(stats.count)*0.01 {
+				t.Errorf("%s: expected bucket %d to hold about a tenth of the values, got %d", name, i, bucket.Count)
+			}
+			if i > 0 && bucket.LowerBound != col.HistogramBuckets[i-1].UpperBound {
+				t.Errorf("%s: expected contiguous buckets, got %v", name, col.HistogramBuckets)
+			}
+		}
+		if total != stats.count {
+			t.Errorf("%s: expected counts to add up to %d, got %d", name, stats.count, total)
+		}
+	}
+
+	// Repeated values collapse buckets rather than leaving them empty
+	discrete := newNumericStats()
+	discrete.addN(1, 80)
+	discrete.addN(2, 15)
+	discrete.addN(3, 5)
+	col := &ColumnProfile{}
+	discrete.apply(col, Options{Histogram: HistogramEqualFrequency}.histogram(), DefaultThresholds())
+	for _, bucket := range col.HistogramBuckets {
+		if bucket.Count == 0 {
+			t.Errorf("Expected no empty buckets, got %v", col.HistogramBuckets)
+		}
+	}
+}
+
+func TestNumericStatsMerge(t *testing.T) {
+	for _, n := range []int{100, 3 * exactNumericLimit} {
+		whole, first, second := newNumericStats(), newNumericStats(), newNumericStats()
+		for i := 0; i < n; i++ {
+			x := float64(i%97) * 1.5
+			whole.add(x)
+			if i < n/3 {
+				first.add(x)
+			} else {
+				second.add(x)
+			}
+		}
+		first.merge(second)
+
+		want, got := &ColumnProfile{}, &ColumnProfile{}
+		whole.apply(want, Options{}.histogram(), DefaultThresholds())
+		first.apply(got, Options{}.histogram(), DefaultThresholds())
+
+		if got.Min != want.Min || got.Max != want.Max {
+			t.Errorf("n=%d: expected min %v and max %v, got %v and %v", n, want.Min, want.Max, got.Min, got.Max)
+		}
+		if math.Abs(got.Mean-want.Mean) > 1e-9 || math.Abs(got.StdDev-want.StdDev) > 1e-9 {
+			t.Errorf("n=%d: expected mean %v and stddev %v, got %v and %v", n, want.Mean, want.StdDev, got.Mean, got.StdDev)
+		}
+		if first.approximate() != whole.approximate() {
+			t.Errorf("n=%d: expected approximate=%v after merging", n, whole.approximate())
+		}
+		if math.Abs(got.Median-want.Median) > 1.5 {
+			t.Errorf("n=%d: expected median near %v, got %v", n, want.Median, got.Median)
+		}
+	}
+}
+
+func TestProfileHistogramBinning(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("amount\n")
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&b, "%.2f\n", math.Pow(1.05, float64(i)))
+	}
+	path := writeTestFile(t, "data.csv", b.String())
+
+	profile, err := ProfileDatasetWithOptions(path, Options{Histogram: HistogramEqualFrequency})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if profile.HistogramBinning != HistogramEqualFrequency {
+		t.Errorf("Expected equal-frequency binning, got %q", profile.HistogramBinning)
+	}
+	for _, bucket := range profile.Columns["amount"].HistogramBuckets {
+		if bucket.Count != 20 {
+			t.Errorf("Expected 20 values per bucket, got %v", profile.Columns["amount"].HistogramBuckets)
+			break
+		}
+	}
+
+	if profile, err = ProfileDataset(path); err != nil || profile.HistogramBinning != HistogramEqualWidth {
+		t.Errorf("Expected equal-width binning by default, got %v", err)
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{Histogram: "sqrt"}); err == nil {
+		t.Error("Expected an unsupported histogram binning to be rejected")
+	}
+	if _, err := ProfileDatasetWithOptions(path, Options{HistogramBuckets: -1}); err == nil {
+		t.Error("Expected a negative bucket count to be rejected")
+	}
+}
+
+func TestNumericStatsRobust(t *testing.T) {
+	stats := newNumericStats()
+	for i := 1; i <= 19; i++ {
+		stats.add(float64(i))
+	}
+	stats.add(1000)
+
+	col := &ColumnProfile{}
+	stats.apply(col, Options{}.histogram(), DefaultThresholds())
+	stats.applyRobust(col)
+
+	// 5% of 20 values trims 1 and 1000, and winsorizes them to 2 and 19
+	winsorized := 0.0
+	for _, v := range []float64{2, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 19} {
+		winsorized += (v - 10.5) * (v - 10.5)
+	}
+	robust := col.Robust
+	if robust == nil || robust.Trim != RobustTrim || robust.TrimmedMean != 10.5 || robust.MAD != 5 {
+		t.Fatalf("Expected a trimmed mean of 10.5 and a MAD of 5, got %+v", robust)
+	}
+	if math.Abs(robust.WinsorizedStdDev-math.Sqrt(winsorized/20)) > 1e-12 {
+		t.Errorf("Expected winsorized stddev %v, got %v", math.Sqrt(winsorized/20), robust.WinsorizedStdDev)
+	}
+	if col.Mean < 50 || col.StdDev < 200 {
+		t.Errorf("Expected the classical moments to follow the outlier, got mean %v and stddev %v", col.Mean, col.StdDev)
+	}
+
+	// A single value has no spread
+	single := newNumericStats()
+	single.add(7)
+	col = &ColumnProfile{}
+	single.apply(col, Options{}.histogram(), DefaultThresholds())
+	single.applyRobust(col)
+	if *col.Robust != (RobustStats{Trim: RobustTrim, TrimmedMean: 7}) {
+		t.Errorf("Expected a trimmed mean of 7 without spread, got %+v", col.Robust)
+	}
+}
+
+func TestNumericStatsRobustApproximate(t *testing.T) {
+	stats := newNumericStats()
+	n := 3 * exactNumericLimit
+	for i := 0; i < n; i++ {
+		stats.add(float64(i))
+	}
+	for i := 0; i < 10; i++ {
+		stats.add(1e9)
+	}
+
+	col := &ColumnProfile{}
+	stats.apply(col, Options{}.histogram(), DefaultThresholds())
+	stats.applyRobust(col)
+
+	robust := col.Robust
+	if math.Abs(robust.TrimmedMean-float64(n)/2) > float64(n)*0.01 {
+		t.Errorf("Expected a trimmed mean near %d, got %v", n/2, robust.TrimmedMean)
+	}
+	if math.Abs(robust.MAD-float64(n)/4) > float64(n)*0.01 {
+		t.Errorf("Expected a MAD near %d, got %v", n/4, robust.MAD)
+	}
+	// Uniform values clamped at the 5th and 95th percentiles have a
+	// variance of 0.081 times the squared range
+	if expected := math.Sqrt(0.081) * float64(n); math.Abs(robust.WinsorizedStdDev-expected) > float64(n)*0.01 {
+		t.Errorf("Expected a winsorized stddev near %v, got %v", expected, robust.WinsorizedStdDev)
+	}
+	if !strings.Contains(strings.Join(col.Notes, "\n"), "MAD estimated from a t-digest") {
+		t.Errorf("Expected a note on the estimate, got %v", col.Notes)
+	}
+}
+
+func TestProfileRobust(t *testing.T) {
+	path := writeTestFile(t, "data.csv", "amount,w\n1,1\n2,1\n3,1\n100,1\n")
+
+	profile, err := ProfileDatasetWithOptions(path, Options{Robust: true})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if robust := profile.Columns["amount"].Robust; robust == nil || robust.MAD != 1 {
+		t.Errorf("Expected a MAD of 1, got %+v", robust)
+	}
+	if profile.Columns["w"].Robust == nil {
+		t.Error("Expected robust statistics of every numeric column")
+	}
+
+	if profile, err = ProfileDataset(path); err != nil || profile.Columns["amount"].Robust != nil {
+		t.Errorf("Expected no robust statistics by default, got %v", err)
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{Robust: true, WeightColumn: "w"}); err == nil {
+		t.Error("Expected robust statistics to be rejected with a weight column")
+	}
+}
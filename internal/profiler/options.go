@@ -0,0 +1,263 @@
+package profiler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/remote"
+)
+
+type Options struct {
+	SampleSize       int      // rows to profile, 0 for all rows
+	SampleStrategy   string   // head, random or systematic; random when empty
+	Table            string   // table to profile in a database file
+	Sheet            string   // sheet to profile in an Excel workbook
+	Range            string   // Excel table, defined name or cell range such as A1:F5000
+	Password         string   // password of a protected workbook or zip archive
+	Member           string   // file to profile inside a zip or tar archive, empty to merge all
+	Format           string   // csv, tsv, jsonl, xml, delta or iceberg; detected when empty
+	RecordPath       string   // XPath of the elements that are the records of an XML file, the root's children when empty
+	Delimiter        rune     // CSV field delimiter, 0 to sniff (tab for TSV)
+	Quote            rune     // CSV quote character, 0 for '"', NoQuote to turn quoting off
+	Comment          rune     // CSV comment line prefix, 0 for none
+	Encoding         string   // character encoding of text sources, empty to detect
+	Examples         int      // random example values kept per column
+	Redact           []string // columns whose examples are withheld, "*" for all
+	MaxBytes         int64    // profile only the first MaxBytes bytes of text sources, 0 for all
+	SkipRows         int      // lines before the CSV/TSV header, 0 to detect a preamble
+	SkipFooter       int      // CSV/TSV rows to drop from the end, 0 to detect total rows
+	SkipBadRows      bool     // skip CSV/TSV rows and JSONL lines that do not parse instead of failing, counting them
+	Parallel         int      // workers parsing a local CSV/TSV file concurrently, 0 or 1 to read sequentially
+	Histogram        string   // histogram binning: equal-width, equal-frequency, log or auto; equal-width when empty
+	HistogramBuckets int      // buckets per histogram, DefaultHistogramBuckets when 0
+	ExactRows        int      // datasets with fewer rows get complete value and duplicate listings, 0 for never
+	Preview          int      // first rows kept for the report preview, 0 for none
+	PreviewColumns   []string // columns shown in the preview, empty for all
+	NumberFormat     string   // us, eu or in for every column; detected per column when empty
+	Locale           string   // en-US, de-DE and so on: the number format, unless NumberFormat is set, and date order of every column; detected per column when empty
+	DateFormats      []string // time layouts, or epoch-seconds and epoch-millis, tried before the built-in date formats in every column
+	NullValues       []string // values read as missing, such as NA or NULL, besides empty ones
+	Checksum         string   // expected digest of a remote file as algorithm:digest, e.g. sha256:<hex>
+	WeightColumn     string   // column of row weights; means, percentiles, histograms and top values are weighted
+	TopValues        int      // most and least frequent values listed per column, DefaultTopValues when 0
+	KAnonymity       int      // values seen fewer times are withheld from value, example, preview and duplicate listings, 0 to list all
+	UniqueKey        []string // columns that identify a row: duplicates repeat them rather than the whole row
+	Robust           bool     // also compute trimmed means, winsorized standard deviations and MADs of numeric columns
+	MinHash          int      // salted hashes kept per column MinHash signature, 0 for none
+	MinHashSalt      string   // secret salt of the MinHash signatures
+	Engine           string   // go or duckdb for local CSV, TSV, JSONL and Parquet files; go when empty
+
+	// Rows is called with every row profiled, in order and after sampling,
+	// so that a caller can act on the rows of any source as it is read. An
+	// error stops profiling and is returned.
+	Rows func(header, record []string) error
+
+	TimeColumn              string               // timestamps that place rows in time windows
+	TimeWindow              time.Duration        // span of the latest and previous windows of TimeColumn
+	CorrelationRows         int                  // rows sampled for correlations, 0 for DefaultCorrelationRows
+	DisabledRecommendations []string             // recommendation rules turned off by name
+	RecommendationRules     []RecommendationRule // rules run after the built-in ones
+	Scoring                 *Scoring             // weights of the quality score, nil for DefaultScoring
+	Outliers                *OutlierDetection    // outlier method and thresholds, nil for z-scores above 3
+
+	progress *progressTracker // set by ProfileDatasetContext for the source being profiled
+	ctx      context.Context  // set by ProfileDatasetContext, done when profiling is to stop
+}
+
+// scoring is the scoring of the quality score.
+func (o Options) scoring() *Scoring {
+	if o.Scoring == nil {
+		return DefaultScoring()
+	}
+	return o.Scoring
+}
+
+func (o Options) validate() error {
+	if o.Examples < 0 {
+		return fmt.Errorf("example count must not be negative: %d", o.Examples)
+	}
+
+	if err := o.validateDialect(); err != nil {
+		return err
+	}
+
+	if o.Encoding != "" {
+		if _, err := lookupEncoding(o.Encoding); err != nil {
+			return err
+		}
+	}
+
+	if o.MaxBytes < 0 {
+		return fmt.Errorf("byte range must not be negative: %d", o.MaxBytes)
+	}
+
+	if o.SkipRows < 0 {
+		return fmt.Errorf("skip rows must not be negative: %d", o.SkipRows)
+	}
+
+	if o.SkipFooter < 0 {
+		return fmt.Errorf("skip footer must not be negative: %d", o.SkipFooter)
+	}
+
+	if o.Parallel < 0 {
+		return fmt.Errorf("parallel workers must not be negative: %d", o.Parallel)
+	}
+
+	if slices.Contains(o.NullValues, "") {
+		return fmt.Errorf("null values must not be empty: empty values are always missing")
+	}
+
+	if o.Scoring != nil {
+		if err := o.Scoring.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if o.MinHash < 0 {
+		return fmt.Errorf("MinHash size must not be negative: %d", o.MinHash)
+	}
+	if o.MinHash > 0 && o.MinHashSalt == "" {
+		return fmt.Errorf("MinHash signatures need a salt shared with whoever they are compared with: pass --salt or set %s", SaltEnv)
+	}
+
+	if o.Outliers != nil {
+		if err := o.Outliers.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range o.UniqueKey {
+		if name == "" {
+			return fmt.Errorf("unique key column names must not be empty")
+		}
+	}
+
+	if o.Robust && o.WeightColumn != "" {
+		return fmt.Errorf("--robust does not combine with --weight-column: robust statistics are not weighted")
+	}
+
+	if o.TimeWindow < 0 {
+		return fmt.Errorf("time window must not be negative: %s", o.TimeWindow)
+	}
+	if (o.TimeColumn == "") != (o.TimeWindow == 0) {
+		return fmt.Errorf("--time-column and --window go together: give both to compare the latest window with the one before")
+	}
+
+	if o.TopValues < 0 {
+		return fmt.Errorf("top value count must not be negative: %d", o.TopValues)
+	}
+
+	if o.KAnonymity < 0 {
+		return fmt.Errorf("k-anonymity threshold must not be negative: %d", o.KAnonymity)
+	}
+
+	if o.ExactRows < 0 {
+		return fmt.Errorf("exact mode row threshold must not be negative: %d", o.ExactRows)
+	}
+
+	if err := o.validateEngine(); err != nil {
+		return err
+	}
+
+	if _, err := o.recommendationEngine(); err != nil {
+		return err
+	}
+
+	if _, err := lookupNumberFormat(o.NumberFormat); err != nil {
+		return err
+	}
+	if err := ValidateDateFormats(o.DateFormats); err != nil {
+		return err
+	}
+	if _, _, err := lookupLocale(o.Locale); err != nil {
+		return err
+	}
+
+	if o.Checksum != "" {
+		if _, err := remote.ParseChecksum(o.Checksum); err != nil {
+			return err
+		}
+	}
+
+	if o.CorrelationRows < 0 {
+		return fmt.Errorf("correlation rows must not be negative: %d", o.CorrelationRows)
+	}
+
+	if o.Preview < 0 {
+		return fmt.Errorf("preview rows must not be negative: %d", o.Preview)
+	}
+
+	if o.SampleSize < 0 {
+		return fmt.Errorf("sample size must not be negative: %d", o.SampleSize)
+	}
+
+	if err := (&Histograms{Binning: o.Histogram, Buckets: o.HistogramBuckets}).Validate(); err != nil {
+		return err
+	}
+
+	switch o.SampleStrategy {
+	case "", SampleHead, SampleRandom, SampleSystematic:
+	default:
+		return fmt.Errorf("unsupported sample strategy: %s (use head, random or systematic)", o.SampleStrategy)
+	}
+
+	if _, err := parseRecordPath(o.RecordPath); err != nil {
+		return err
+	}
+
+	switch o.Format {
+	case "", FormatCSV, FormatTSV, FormatJSONL, FormatXML, FormatDelta, FormatIceberg, FormatHive:
+		return nil
+	default:
+		return fmt.Errorf("unsupported format: %s (use csv, tsv, jsonl, xml, delta, iceberg or hive)", o.Format)
+	}
+}
+
+// textOnly rejects the options that only apply to text sources.
+func (o Options) textOnly(format string) error {
+	if o.MaxBytes > 0 {
+		return fmt.Errorf("--range is not supported for %s", format)
+	}
+	if o.SkipRows > 0 {
+		return fmt.Errorf("--skip-rows is not supported for %s", format)
+	}
+	if o.SkipFooter > 0 {
+		return fmt.Errorf("--skip-footer is not supported for %s", format)
+	}
+	if o.SkipBadRows {
+		return fmt.Errorf("--skip-bad-rows is not supported for %s", format)
+	}
+	if o.Delimiter != 0 || o.Quote != 0 || o.Comment != 0 {
+		return fmt.Errorf("--delimiter, --quote and --comment are not supported for %s", format)
+	}
+	if o.Encoding != "" {
+		return fmt.Errorf("--encoding is not supported for %s", format)
+	}
+	return nil
+}
+
+func (o Options) correlationRows() int {
+	if o.CorrelationRows == 0 {
+		return DefaultCorrelationRows
+	}
+	return o.CorrelationRows
+}
+
+func (o Options) redacted(column string) bool {
+	for _, name := range o.Redact {
+		if name == "*" || strings.EqualFold(name, column) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o Options) histogramBinning() string {
+	if o.Histogram == "" {
+		return HistogramEqualWidth
+	}
+	return o.Histogram
+}
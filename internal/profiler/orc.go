@@ -0,0 +1,1140 @@
+package profiler
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var orcMagic = []byte("ORC")
+
+// orcTail is how much of the end of an ORC file is read to find its
+// postscript and, usually, its footer.
+const orcTail = 16 << 10
+
+// orcStreamBuffer is the read buffer of each stream of a stripe.
+const orcStreamBuffer = 64 << 10
+
+// orcEpoch is the start of 2015, from which ORC counts timestamp seconds.
+const orcEpoch = 1420070400
+
+// Type kinds, compression kinds, stream kinds and column encodings, as
+// numbered by the ORC specification.
+const (
+	orcBoolean = iota
+	orcByte
+	orcShort
+	orcInt
+	orcLong
+	orcFloat
+	orcDouble
+	orcString
+	orcBinary
+	orcTimestamp
+	orcList
+	orcMap
+	orcStruct
+	orcUnion
+	orcDecimal
+	orcDate
+	orcVarchar
+	orcChar
+	orcTimestampInstant
+)
+
+const (
+	orcNone = iota
+	orcZlib
+	orcSnappy
+	orcLZO
+	orcLZ4
+	orcZstd
+)
+
+const (
+	orcPresent        = 0
+	orcData           = 1
+	orcLength         = 2
+	orcDictionaryData = 3
+	orcSecondary      = 5
+)
+
+const (
+	orcDirect       = 0
+	orcDictionary   = 1
+	orcDirectV2     = 2
+	orcDictionaryV2 = 3
+)
+
+// orcFile is an open ORC file: the postscript and footer read off its end.
+type orcFile struct {
+	r           io.ReaderAt
+	compression uint64
+	blockSize   uint64
+	zstd        *zstd.Decoder
+	stripes     []orcStripe
+	types       []orcType
+	rows        uint64
+}
+
+type orcStripe struct {
+	offset, indexLength, dataLength, footerLength, rows uint64
+}
+
+type orcType struct {
+	kind     uint64
+	subtypes []uint64
+	names    []string
+	scale    uint64
+}
+
+type orcStreamInfo struct {
+	kind, column, length uint64
+}
+
+type orcEncoding struct {
+	kind, dictionarySize uint64
+}
+
+func openORC(r io.ReaderAt, size int64) (*orcFile, error) {
+	if size < int64(len(orcMagic))+1 {
+		return nil, errors.New("not an ORC file")
+	}
+	tail := make([]byte, min(size, orcTail))
+	if _, err := r.ReadAt(tail, size-int64(len(tail))); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	psLength := int(tail[len(tail)-1])
+	if psLength+1 > len(tail) {
+		return nil, errors.New("not an ORC file")
+	}
+	f := &orcFile{r: r}
+	var footerLength uint64
+	var magic []byte
+	err := walkProto(tail[len(tail)-1-psLength:len(tail)-1], func(num protowire.Number, v uint64, b []byte) {
+		switch num {
+		case 1:
+			footerLength = v
+		case 2:
+			f.compression = v
+		case 3:
+			f.blockSize = v
+		case 8000:
+			magic = b
+		}
+	})
+	if err != nil || !bytes.Equal(magic, orcMagic) {
+		return nil, errors.New("not an ORC file")
+	}
+	switch f.compression {
+	case orcNone, orcZlib, orcSnappy, orcLZ4:
+	case orcZstd:
+		if f.zstd, err = zstd.NewReader(nil, zstd.WithDecoderConcurrency(1)); err != nil {
+			return nil, err
+		}
+	case orcLZO:
+		return nil, errors.New("LZO-compressed ORC files are not supported")
+	default:
+		return nil, fmt.Errorf("unsupported ORC compression: %d", f.compression)
+	}
+
+	footerEnd := size - 1 - int64(psLength)
+	if footerLength > uint64(footerEnd) {
+		return nil, errors.New("invalid footer length")
+	}
+	footer := make([]byte, footerLength)
+	if _, err := r.ReadAt(footer, footerEnd-int64(footerLength)); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if footer, err = f.decompressAll(footer); err != nil {
+		return nil, fmt.Errorf("failed to decompress footer: %w", err)
+	}
+	if err := f.readFooter(footer); err != nil {
+		return nil, fmt.Errorf("invalid footer: %w", err)
+	}
+	if len(f.types) == 0 || f.types[0].kind != orcStruct {
+		return nil, errors.New("invalid footer: the root type is not a struct")
+	}
+	return f, nil
+}
+
+func (f *orcFile) close() {
+	if f.zstd != nil {
+		f.zstd.Close()
+	}
+}
+
+func (f *orcFile) readFooter(data []byte) error {
+	var err error
+	walkErr := walkProto(data, func(num protowire.Number, v uint64, b []byte) {
+		switch num {
+		case 3:
+			var stripe orcStripe
+			err = errors.Join(err, walkProto(b, func(num protowire.Number, v uint64, _ []byte) {
+				switch num {
+				case 1:
+					stripe.offset = v
+				case 2:
+					stripe.indexLength = v
+				case 3:
+					stripe.dataLength = v
+				case 4:
+					stripe.footerLength = v
+				case 5:
+					stripe.rows = v
+				}
+			}))
+			f.stripes = append(f.stripes, stripe)
+		case 4:
+			var t orcType
+			err = errors.Join(err, walkProto(b, func(num protowire.Number, v uint64, b []byte) {
+				switch num {
+				case 1:
+					t.kind = v
+				case 2:
+					if b == nil {
+						t.subtypes = append(t.subtypes, v)
+						return
+					}
+					for len(b) > 0 {
+						subtype, n := protowire.ConsumeVarint(b)
+						if n < 0 {
+							err = errors.Join(err, protowire.ParseError(n))
+							return
+						}
+						t.subtypes, b = append(t.subtypes, subtype), b[n:]
+					}
+				case 3:
+					t.names = append(t.names, string(b))
+				case 6:
+					t.scale = v
+				}
+			}))
+			f.types = append(f.types, t)
+		case 6:
+			f.rows = v
+		}
+	})
+	return errors.Join(walkErr, err)
+}
+
+// walkProto calls field with the number and the varint or bytes of each
+// field of a protobuf message; fixed-width fields are skipped.
+func walkProto(data []byte, field func(num protowire.Number, v uint64, b []byte)) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		var v uint64
+		var b []byte
+		switch typ {
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(data)
+		case protowire.BytesType:
+			b, n = protowire.ConsumeBytes(data)
+			if b == nil {
+				b = []byte{}
+			}
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		if typ == protowire.VarintType || typ == protowire.BytesType {
+			field(num, v, b)
+		}
+	}
+	return nil
+}
+
+// decompressAll decompresses a whole section of the file, such as the
+// footer, chunk by chunk.
+func (f *orcFile) decompressAll(data []byte) ([]byte, error) {
+	if f.compression == orcNone {
+		return data, nil
+	}
+	out := make([]byte, 0, len(data))
+	for len(data) > 0 {
+		if len(data) < 3 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		header := uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16
+		length := int(header >> 1)
+		if 3+length > len(data) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		chunk := data[3 : 3+length]
+		data = data[3+length:]
+		if header&1 == 0 {
+			var err error
+			if chunk, err = f.decompress(chunk); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, chunk...)
+	}
+	return out, nil
+}
+
+func (f *orcFile) decompress(chunk []byte) ([]byte, error) {
+	switch f.compression {
+	case orcZlib:
+		return io.ReadAll(flate.NewReader(bytes.NewReader(chunk)))
+	case orcSnappy:
+		return snappy.Decode(nil, chunk)
+	case orcLZ4:
+		out := make([]byte, max(f.blockSize, 256<<10))
+		n, err := lz4.UncompressBlock(chunk, out)
+		return out[:n], err
+	case orcZstd:
+		return f.zstd.DecodeAll(chunk, nil)
+	default:
+		return chunk, nil
+	}
+}
+
+// orcStream reads the decompressed bytes of a stream, a chunk at a time.
+type orcStream struct {
+	file  *orcFile
+	r     *bufio.Reader
+	chunk []byte
+	pos   int
+}
+
+// stream opens the stream of length bytes at offset.
+func (f *orcFile) stream(offset, length uint64) avroByteReader {
+	r := bufio.NewReaderSize(io.NewSectionReader(f.r, int64(offset), int64(length)), orcStreamBuffer)
+	if f.compression == orcNone {
+		return r
+	}
+	return &orcStream{file: f, r: r}
+}
+
+func (s *orcStream) fill() error {
+	var header [3]byte
+	if _, err := io.ReadFull(s.r, header[:]); err != nil {
+		return err
+	}
+	length := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+	chunk := make([]byte, length>>1)
+	if _, err := io.ReadFull(s.r, chunk); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if length&1 == 0 {
+		var err error
+		if chunk, err = s.file.decompress(chunk); err != nil {
+			return fmt.Errorf("failed to decompress: %w", err)
+		}
+	}
+	s.chunk, s.pos = chunk, 0
+	return nil
+}
+
+func (s *orcStream) ReadByte() (byte, error) {
+	for s.pos >= len(s.chunk) {
+		if err := s.fill(); err != nil {
+			return 0, err
+		}
+	}
+	b := s.chunk[s.pos]
+	s.pos++
+	return b, nil
+}
+
+func (s *orcStream) Read(p []byte) (int, error) {
+	for s.pos >= len(s.chunk) {
+		if err := s.fill(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, s.chunk[s.pos:])
+	s.pos += n
+	return n, nil
+}
+
+// orcBytes decodes the byte run-length encoding: a control byte below 128
+// repeats the next byte that many times plus 3, any other is followed by
+// 256 minus it literal bytes.
+type orcBytes struct {
+	r      io.ByteReader
+	values []byte
+	pos    int
+}
+
+func (d *orcBytes) next() (byte, error) {
+	if d.pos == len(d.values) {
+		control, err := d.r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		d.values, d.pos = d.values[:0], 0
+		if control < 128 {
+			b, err := d.r.ReadByte()
+			if err != nil {
+				return 0, err
+			}
+			for i := 0; i < int(control)+3; i++ {
+				d.values = append(d.values, b)
+			}
+		} else {
+			for i := 0; i < 256-int(control); i++ {
+				b, err := d.r.ReadByte()
+				if err != nil {
+					return 0, err
+				}
+				d.values = append(d.values, b)
+			}
+		}
+	}
+	b := d.values[d.pos]
+	d.pos++
+	return b, nil
+}
+
+// orcBits reads booleans, most significant bit first, from bytes in the
+// byte run-length encoding.
+type orcBits struct {
+	bytes orcBytes
+	cur   byte
+	left  int
+}
+
+func (d *orcBits) next() (bool, error) {
+	if d.left == 0 {
+		b, err := d.bytes.next()
+		if err != nil {
+			return false, err
+		}
+		d.cur, d.left = b, 8
+	}
+	d.left--
+	return d.cur>>d.left&1 == 1, nil
+}
+
+// orcInts decodes integers in run-length encoding version 1 or 2,
+// zigzag-encoded when signed.
+type orcInts struct {
+	r      io.ByteReader
+	signed bool
+	v2     bool
+	values []int64
+	pos    int
+}
+
+func (d *orcInts) next() (int64, error) {
+	if d.pos == len(d.values) {
+		d.values, d.pos = d.values[:0], 0
+		var err error
+		if d.v2 {
+			err = d.readRunV2()
+		} else {
+			err = d.readRunV1()
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	v := d.values[d.pos]
+	d.pos++
+	return v, nil
+}
+
+func (d *orcInts) varint() (int64, error) {
+	value, err := readORCVarint(d.r)
+	return d.decode(value), err
+}
+
+func (d *orcInts) decode(value uint64) int64 {
+	if d.signed {
+		return orcZigzag(value)
+	}
+	return int64(value)
+}
+
+func readORCVarint(r io.ByteReader) (uint64, error) {
+	var value uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		b, err := r.ReadByte()
+		if err != nil {
+			if shift > 0 && err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		value |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return value, nil
+		}
+	}
+	return 0, errors.New("varint overflows a long")
+}
+
+func orcZigzag(value uint64) int64 {
+	return int64(value>>1) ^ -int64(value&1)
+}
+
+// readRunV1 reads a run of version 1: a control byte below 128 starts a run
+// of that many values plus 3, from a base varint stepping by a signed delta
+// byte; any other is followed by 256 minus it literal varints.
+func (d *orcInts) readRunV1() error {
+	control, err := d.r.ReadByte()
+	if err != nil {
+		return err
+	}
+	if control >= 128 {
+		for i := 0; i < 256-int(control); i++ {
+			v, err := d.varint()
+			if err != nil {
+				return err
+			}
+			d.values = append(d.values, v)
+		}
+		return nil
+	}
+
+	delta, err := d.r.ReadByte()
+	if err != nil {
+		return err
+	}
+	base, err := d.varint()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < int(control)+3; i++ {
+		d.values = append(d.values, base+int64(i)*int64(int8(delta)))
+	}
+	return nil
+}
+
+// readRunV2 reads a run of version 2, in one of its four sub-encodings:
+// short repeat, direct, patched base and delta.
+func (d *orcInts) readRunV2() error {
+	first, err := d.r.ReadByte()
+	if err != nil {
+		return err
+	}
+
+	if first>>6 == 0 {
+		// Short repeat: a value of 1 to 8 bytes repeated 3 to 10 times
+		v, err := d.bigEndian(int(first>>3&7) + 1)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < int(first&7)+3; i++ {
+			d.values = append(d.values, d.decode(v))
+		}
+		return nil
+	}
+
+	second, err := d.r.ReadByte()
+	if err != nil {
+		return err
+	}
+	width := orcBitWidth(first >> 1 & 0x1f)
+	length := (int(first&1)<<8 | int(second)) + 1
+
+	switch first >> 6 {
+	case 1:
+		// Direct: bit-packed values
+		values, err := d.unpack(length, width)
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			d.values = append(d.values, d.decode(v))
+		}
+		return nil
+	case 2:
+		return d.readPatchedBase(width, length)
+	}
+
+	// Delta: a base, a first delta, and the other deltas bit-packed with
+	// the sign of the first, or all equal to it for a width of 0
+	if first>>1&0x1f == 0 {
+		width = 0
+	}
+	base, err := d.varint()
+	if err != nil {
+		return err
+	}
+	rawStep, err := readORCVarint(d.r)
+	if err != nil {
+		return err
+	}
+	step := orcZigzag(rawStep)
+	d.values = append(d.values, base)
+	if length > 1 {
+		d.values = append(d.values, base+step)
+	}
+	if width == 0 {
+		for i := 2; i < length; i++ {
+			d.values = append(d.values, d.values[len(d.values)-1]+step)
+		}
+		return nil
+	}
+	deltas, err := d.unpack(length-2, width)
+	if err != nil {
+		return err
+	}
+	for _, delta := range deltas {
+		previous := d.values[len(d.values)-1]
+		if step < 0 {
+			d.values = append(d.values, previous-int64(delta))
+		} else {
+			d.values = append(d.values, previous+int64(delta))
+		}
+	}
+	return nil
+}
+
+// readPatchedBase reads values bit-packed above a base, the few values too
+// wide for the width getting their high bits from a list of patches.
+func (d *orcInts) readPatchedBase(width, length int) error {
+	third, err := d.r.ReadByte()
+	if err != nil {
+		return err
+	}
+	fourth, err := d.r.ReadByte()
+	if err != nil {
+		return err
+	}
+	baseBytes := int(third>>5) + 1
+	patchWidth := orcBitWidth(third & 0x1f)
+	gapWidth := int(fourth>>5) + 1
+	patchCount := int(fourth & 0x1f)
+
+	// The base is in sign-magnitude form
+	raw, err := d.bigEndian(baseBytes)
+	if err != nil {
+		return err
+	}
+	sign := uint64(1) << (8*baseBytes - 1)
+	base := int64(raw &^ sign)
+	if raw&sign != 0 {
+		base = -base
+	}
+
+	values, err := d.unpack(length, width)
+	if err != nil {
+		return err
+	}
+	patches, err := d.unpack(patchCount, orcFixedBitWidth(gapWidth+patchWidth))
+	if err != nil {
+		return err
+	}
+	position := 0
+	for _, patch := range patches {
+		gap, high := int(patch>>patchWidth), patch&(1<<patchWidth-1)
+		position += gap
+		if high == 0 {
+			// A gap of more than 255 is split over patches of nothing
+			continue
+		}
+		if position >= length {
+			return errors.New("patch out of range")
+		}
+		values[position] |= high << width
+	}
+	for _, v := range values {
+		d.values = append(d.values, base+int64(v))
+	}
+	return nil
+}
+
+func (d *orcInts) bigEndian(n int) (uint64, error) {
+	var v uint64
+	for i := 0; i < n; i++ {
+		b, err := d.r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		v = v<<8 | uint64(b)
+	}
+	return v, nil
+}
+
+// unpack reads n values of width bits packed most significant bit first,
+// the last byte padded.
+func (d *orcInts) unpack(n, width int) ([]uint64, error) {
+	values := make([]uint64, n)
+	var current uint64
+	bits := 0
+	for i := range values {
+		var v uint64
+		for need := width; need > 0; {
+			if bits == 0 {
+				b, err := d.r.ReadByte()
+				if err != nil {
+					return nil, err
+				}
+				current, bits = uint64(b), 8
+			}
+			take := min(need, bits)
+			v = v<<take | current>>(bits-take)&(1<<take-1)
+			bits -= take
+			need -= take
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// orcBitWidth decodes the 5-bit width of a run of version 2.
+func orcBitWidth(encoded byte) int {
+	if encoded < 24 {
+		return int(encoded) + 1
+	}
+	return []int{26, 28, 30, 32, 40, 48, 56, 64}[encoded-24]
+}
+
+// orcFixedBitWidth rounds bits up to a width a run of version 2 can have.
+func orcFixedBitWidth(bits int) int {
+	if bits <= 24 {
+		return max(bits, 1)
+	}
+	for _, width := range []int{26, 28, 30, 32, 40, 48, 56} {
+		if bits <= width {
+			return width
+		}
+	}
+	return 64
+}
+
+// orcColumn is a column of an ORC file. The fields of a struct are
+// flattened into columns named parent.child.
+type orcColumn struct {
+	id     uint64 // column id, the index of its type
+	kind   uint64
+	scale  uint64
+	index  int          // position of the column in the header
+	fields []*orcColumn // columns of a struct
+
+	// Readers of the current stripe
+	present    *orcBits
+	ints       *orcInts // integers, dates, seconds, dictionary indexes and decimal scales
+	bytes      *orcBytes
+	bits       *orcBits
+	data       avroByteReader
+	lengths    *orcInts
+	nanos      *orcInts
+	dictionary []string
+}
+
+// orcColumns lays out the columns of the struct with type id, appending
+// their names to header and those of lists, maps and unions, which are not
+// read, to skipped.
+func orcColumns(types []orcType, id uint64, prefix string, header, skipped *[]string) ([]*orcColumn, error) {
+	t := types[id]
+	columns := make([]*orcColumn, 0, len(t.subtypes))
+	for i, child := range t.subtypes {
+		if child <= id || child >= uint64(len(types)) || i >= len(t.names) {
+			return nil, errors.New("invalid schema")
+		}
+		name := prefix + t.names[i]
+		column := &orcColumn{id: child, kind: types[child].kind, scale: types[child].scale}
+		switch column.kind {
+		case orcStruct:
+			fields, err := orcColumns(types, child, name+".", header, skipped)
+			if err != nil {
+				return nil, err
+			}
+			column.fields = fields
+		case orcList, orcMap, orcUnion:
+			*skipped = append(*skipped, name)
+			continue
+		default:
+			column.index = len(*header)
+			*header = append(*header, name)
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// openStripe reads the footer of a stripe and opens the streams of columns
+// in it.
+func (f *orcFile) openStripe(stripe orcStripe, columns []*orcColumn) error {
+	footerOffset := stripe.offset + stripe.indexLength + stripe.dataLength
+	footer := make([]byte, stripe.footerLength)
+	if _, err := f.r.ReadAt(footer, int64(footerOffset)); err != nil && err != io.EOF {
+		return err
+	}
+	footer, err := f.decompressAll(footer)
+	if err != nil {
+		return fmt.Errorf("failed to decompress stripe footer: %w", err)
+	}
+
+	// Streams are laid out in the order the footer lists them
+	type section struct{ offset, length uint64 }
+	streams := make(map[[2]uint64]section)
+	encodings := make([]orcEncoding, 0)
+	offset := stripe.offset
+	err = walkProto(footer, func(num protowire.Number, _ uint64, b []byte) {
+		switch num {
+		case 1:
+			var s orcStreamInfo
+			err = errors.Join(err, walkProto(b, func(num protowire.Number, v uint64, _ []byte) {
+				switch num {
+				case 1:
+					s.kind = v
+				case 2:
+					s.column = v
+				case 3:
+					s.length = v
+				}
+			}))
+			streams[[2]uint64{s.column, s.kind}] = section{offset, s.length}
+			offset += s.length
+		case 2:
+			var e orcEncoding
+			err = errors.Join(err, walkProto(b, func(num protowire.Number, v uint64, _ []byte) {
+				switch num {
+				case 1:
+					e.kind = v
+				case 2:
+					e.dictionarySize = v
+				}
+			}))
+			encodings = append(encodings, e)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("invalid stripe footer: %w", err)
+	}
+
+	open := func(column, kind uint64) avroByteReader {
+		s := streams[[2]uint64{column, kind}]
+		return f.stream(s.offset, s.length)
+	}
+	var openColumn func(c *orcColumn) error
+	openColumn = func(c *orcColumn) error {
+		c.present = nil
+		if _, ok := streams[[2]uint64{c.id, orcPresent}]; ok {
+			c.present = &orcBits{bytes: orcBytes{r: open(c.id, orcPresent)}}
+		}
+		for _, field := range c.fields {
+			if err := openColumn(field); err != nil {
+				return err
+			}
+		}
+
+		var encoding orcEncoding
+		if c.id < uint64(len(encodings)) {
+			encoding = encodings[c.id]
+		}
+		v2 := encoding.kind == orcDirectV2 || encoding.kind == orcDictionaryV2
+		switch c.kind {
+		case orcBoolean:
+			c.bits = &orcBits{bytes: orcBytes{r: open(c.id, orcData)}}
+		case orcByte:
+			c.bytes = &orcBytes{r: open(c.id, orcData)}
+		case orcShort, orcInt, orcLong, orcDate:
+			c.ints = &orcInts{r: open(c.id, orcData), signed: true, v2: v2}
+		case orcFloat, orcDouble:
+			c.data = open(c.id, orcData)
+		case orcTimestamp, orcTimestampInstant:
+			c.ints = &orcInts{r: open(c.id, orcData), signed: true, v2: v2}
+			c.nanos = &orcInts{r: open(c.id, orcSecondary), v2: v2}
+		case orcDecimal:
+			c.data = open(c.id, orcData)
+			c.ints = &orcInts{r: open(c.id, orcSecondary), signed: true, v2: v2}
+		case orcString, orcVarchar, orcChar, orcBinary:
+			lengths := &orcInts{r: open(c.id, orcLength), v2: v2}
+			if encoding.kind != orcDictionary && encoding.kind != orcDictionaryV2 {
+				c.data, c.lengths, c.dictionary = open(c.id, orcData), lengths, nil
+				break
+			}
+			c.ints = &orcInts{r: open(c.id, orcData), v2: v2}
+			c.dictionary = make([]string, encoding.dictionarySize)
+			data := open(c.id, orcDictionaryData)
+			for i := range c.dictionary {
+				value, err := readORCString(data, lengths)
+				if err != nil {
+					return fmt.Errorf("failed to read dictionary: %w", err)
+				}
+				c.dictionary[i] = value
+			}
+		}
+		return nil
+	}
+	for _, c := range columns {
+		if err := openColumn(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readORCString(data avroByteReader, lengths *orcInts) (string, error) {
+	n, err := lengths.next()
+	if err != nil {
+		return "", err
+	}
+	if n < 0 {
+		return "", errors.New("negative length")
+	}
+	value := make([]byte, n)
+	if _, err := io.ReadFull(data, value); err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// read decodes the next value of the column into record, leaving it empty
+// when null. A null struct leaves its columns empty.
+func (c *orcColumn) read(record []string) error {
+	if c.present != nil {
+		present, err := c.present.next()
+		if err != nil || !present {
+			return err
+		}
+	}
+	if c.kind == orcStruct {
+		for _, field := range c.fields {
+			if err := field.read(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	value, err := c.value()
+	if err != nil {
+		return err
+	}
+	record[c.index] = value
+	return nil
+}
+
+// value decodes the next value as the profiler reads it: dates and
+// timestamps in ISO 8601 and decimals with their scale.
+func (c *orcColumn) value() (string, error) {
+	switch c.kind {
+	case orcBoolean:
+		b, err := c.bits.next()
+		return strconv.FormatBool(b), err
+	case orcByte:
+		b, err := c.bytes.next()
+		return strconv.Itoa(int(int8(b))), err
+	case orcShort, orcInt, orcLong:
+		v, err := c.ints.next()
+		return strconv.FormatInt(v, 10), err
+	case orcDate:
+		days, err := c.ints.next()
+		return time.Unix(days*86400, 0).UTC().Format("2006-01-02"), err
+	case orcFloat:
+		var buf [4]byte
+		_, err := io.ReadFull(c.data, buf[:])
+		return strconv.FormatFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(buf[:]))), 'g', -1, 32), err
+	case orcDouble:
+		var buf [8]byte
+		_, err := io.ReadFull(c.data, buf[:])
+		return strconv.FormatFloat(math.Float64frombits(binary.LittleEndian.Uint64(buf[:])), 'g', -1, 64), err
+	case orcTimestamp, orcTimestampInstant:
+		seconds, err := c.ints.next()
+		if err != nil {
+			return "", err
+		}
+		encoded, err := c.nanos.next()
+		if err != nil {
+			return "", err
+		}
+		// The low 3 bits count the trailing zeros dropped, less one
+		nanos := encoded >> 3
+		if zeros := encoded & 7; zeros != 0 {
+			nanos *= int64(math.Pow10(int(zeros) + 1))
+		}
+		seconds += orcEpoch
+		if seconds < 0 && nanos > 999999 {
+			seconds--
+		}
+		// Timestamps without a time zone are read as the wall clock
+		// time the writer saw
+		return time.Unix(seconds, nanos).UTC().Format(time.RFC3339Nano), nil
+	case orcDecimal:
+		unscaled, err := readORCDecimal(c.data)
+		if err != nil {
+			return "", err
+		}
+		scale, err := c.ints.next()
+		return formatDecimal(unscaled, int(scale)), err
+	case orcString, orcVarchar, orcChar, orcBinary:
+		var value string
+		if c.dictionary != nil {
+			index, err := c.ints.next()
+			if err != nil {
+				return "", err
+			}
+			if index < 0 || index >= int64(len(c.dictionary)) {
+				return "", fmt.Errorf("dictionary index %d out of range", index)
+			}
+			value = c.dictionary[index]
+		} else {
+			var err error
+			if value, err = readORCString(c.data, c.lengths); err != nil {
+				return "", err
+			}
+		}
+		if c.kind == orcChar {
+			// CHAR values are padded with spaces to their length
+			value = strings.TrimRight(value, " ")
+		}
+		return value, nil
+	default:
+		return "", fmt.Errorf("unsupported ORC type: %d", c.kind)
+	}
+}
+
+// readORCDecimal reads the unscaled value of a decimal, a zigzag-encoded
+// varint of any length.
+func readORCDecimal(r io.ByteReader) (*big.Int, error) {
+	value := new(big.Int)
+	var chunk big.Int
+	for shift := uint(0); ; shift += 7 {
+		if shift > 128 {
+			return nil, errors.New("decimal overflows 128 bits")
+		}
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		value.Or(value, chunk.Lsh(chunk.SetUint64(uint64(b&0x7f)), shift))
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	negative := value.Bit(0) == 1
+	value.Rsh(value, 1)
+	if negative {
+		value.Neg(value).Sub(value, big.NewInt(1))
+	}
+	return value, nil
+}
+
+// orcRecordReader reads the rows of an ORC file stripe by stripe.
+type orcRecordReader struct {
+	file    *orcFile
+	columns []*orcColumn
+	width   int
+	stripe  int
+	left    uint64
+}
+
+func (r *orcRecordReader) next() ([]string, error) {
+	for r.left == 0 {
+		if r.stripe == len(r.file.stripes) {
+			return nil, io.EOF
+		}
+		if err := r.file.openStripe(r.file.stripes[r.stripe], r.columns); err != nil {
+			return nil, fmt.Errorf("failed to read ORC stripe %d: %w", r.stripe+1, err)
+		}
+		r.left = r.file.stripes[r.stripe].rows
+		r.stripe++
+	}
+	r.left--
+
+	record := make([]string, r.width)
+	for _, column := range r.columns {
+		if err := column.read(record); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("failed to read ORC stripe %d: %w", r.stripe, err)
+		}
+	}
+	return record, nil
+}
+
+func profileORC(filePath string, opts Options) (*DatasetProfile, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file stats: %w", err)
+	}
+
+	return profileORCReader(file, filepath.Base(filePath), fileInfo.Size(), opts)
+}
+
+// profileORCReader profiles the rows of an ORC file stripe by stripe, with
+// a column per field of the schema in its footer.
+func profileORCReader(r io.ReaderAt, name string, size int64, opts Options) (*DatasetProfile, error) {
+	startTime := time.Now()
+
+	file, columns, header, skipped, err := openORCColumns(r, name, size, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer file.close()
+
+	profile := newDatasetProfile(name, size, "ORC", header)
+	reader := &orcRecordReader{file: file, columns: columns, width: len(header)}
+	if err := profileRows(profile, header, reader.next, opts); err != nil {
+		return nil, err
+	}
+
+	if len(skipped) > 0 {
+		profile.Notes = append(profile.Notes, fmt.Sprintf(
+			"Skipped nested or repeated columns: %s", strings.Join(skipped, ", ")))
+	}
+
+	profile.ProcessingTime = time.Since(startTime)
+
+	return profile, nil
+}
+
+// openORCColumns reads the footer of an ORC file and lays out its columns,
+// with the names of the list, map and union columns it skips.
+func openORCColumns(r io.ReaderAt, name string, size int64, opts Options) (*orcFile, []*orcColumn, []string, []string, error) {
+	if err := opts.textOnly("ORC files"); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if codec := compressionExt(name); codec != "" {
+		return nil, nil, nil, nil, fmt.Errorf("%s-compressed ORC files are not supported: ORC compresses its streams itself", codec)
+	}
+
+	file, err := openORC(r, size)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to read ORC file: %w", err)
+	}
+	header, skipped := make([]string, 0), make([]string, 0)
+	columns, err := orcColumns(file.types, 0, "", &header, &skipped)
+	if err != nil {
+		file.close()
+		return nil, nil, nil, nil, fmt.Errorf("failed to read ORC file: %w", err)
+	}
+	if len(header) == 0 {
+		file.close()
+		return nil, nil, nil, nil, fmt.Errorf("no flat columns found in ORC schema")
+	}
+	return file, columns, header, skipped, nil
+}
@@ -0,0 +1,329 @@
+package profiler
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestORCIntsV2(t *testing.T) {
+	// The examples of the ORC specification
+	tests := []struct {
+		name    string
+		encoded []byte
+		want    []int64
+	}{
+		{"short repeat", []byte{0x0a, 0x27, 0x10}, []int64{10000, 10000, 10000, 10000, 10000}},
+		{"direct", []byte{0x5e, 0x03, 0x5c, 0xa1, 0xab, 0x1e, 0xde, 0xad, 0xbe, 0xef}, []int64{23713, 43806, 57005, 48879}},
+		{"patched base", []byte{0x8e, 0x13, 0x2b, 0x21, 0x07, 0xd0, 0x1e, 0x00, 0x14, 0x70, 0x28, 0x32, 0x3c, 0x46, 0x50, 0x5a, 0x64, 0x6e, 0x78, 0x82, 0x8c, 0x96, 0xa0, 0xaa, 0xb4, 0xbe, 0xfc, 0xe8},
+			[]int64{2030, 2000, 2020, 1000000, 2040, 2050, 2060, 2070, 2080, 2090, 2100, 2110, 2120, 2130, 2140, 2150, 2160, 2170, 2180, 2190}},
+		{"delta", []byte{0xc6, 0x09, 0x02, 0x02, 0x22, 0x42, 0x42, 0x46}, []int64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &orcInts{r: bytes.NewReader(tt.encoded), v2: true}
+			got := make([]int64, len(tt.want))
+			for i := range got {
+				v, err := d.next()
+				if err != nil {
+					t.Fatalf("Failed to decode value %d: %v", i, err)
+				}
+				got[i] = v
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Got %v, want %v", got, tt.want)
+			}
+			if _, err := d.next(); err == nil {
+				t.Error("Expected the values to end")
+			}
+		})
+	}
+}
+
+func TestORCIntsV1(t *testing.T) {
+	d := &orcInts{r: bytes.NewReader([]byte{0x61, 0x00, 0x07, 0xfb, 0x02, 0x03, 0x06, 0x07, 0x0b})}
+	for i := 0; i < 105; i++ {
+		v, err := d.next()
+		want := int64(7)
+		if i >= 100 {
+			want = []int64{2, 3, 6, 7, 11}[i-100]
+		}
+		if err != nil || v != want {
+			t.Fatalf("Value %d: got %d (%v), want %d", i, v, err, want)
+		}
+	}
+}
+
+func TestORCBytes(t *testing.T) {
+	d := &orcBytes{r: bytes.NewReader([]byte{0x61, 0x00, 0xfe, 0x44, 0x45})}
+	for i := 0; i < 102; i++ {
+		b, err := d.next()
+		want := byte(0)
+		if i >= 100 {
+			want = []byte{0x44, 0x45}[i-100]
+		}
+		if err != nil || b != want {
+			t.Fatalf("Byte %d: got %#x (%v), want %#x", i, b, err, want)
+		}
+	}
+}
+
+func appendORCMessage(buf []byte, num protowire.Number, message []byte) []byte {
+	buf = protowire.AppendTag(buf, num, protowire.BytesType)
+	return protowire.AppendBytes(buf, message)
+}
+
+func appendORCVarint(buf []byte, num protowire.Number, v uint64) []byte {
+	buf = protowire.AppendTag(buf, num, protowire.VarintType)
+	return protowire.AppendVarint(buf, v)
+}
+
+// orcTestLiterals encodes bytes as byte run-length literals.
+func orcTestLiterals(values ...byte) []byte {
+	return append([]byte{byte(256 - len(values))}, values...)
+}
+
+// orcTestIntsV1 encodes signed integers as version 1 literals.
+func orcTestIntsV1(signed bool, values ...int64) []byte {
+	buf := []byte{byte(256 - len(values))}
+	for _, v := range values {
+		if signed {
+			buf = protowire.AppendVarint(buf, protowire.EncodeZigZag(v))
+		} else {
+			buf = protowire.AppendVarint(buf, uint64(v))
+		}
+	}
+	return buf
+}
+
+// orcTestIntsV2 encodes integers in a version 2 direct run of 64-bit
+// values.
+func orcTestIntsV2(signed bool, values ...int64) []byte {
+	buf := []byte{0x40 | 31<<1 | byte((len(values)-1)>>8), byte(len(values) - 1)}
+	for _, v := range values {
+		u := uint64(v)
+		if signed {
+			u = protowire.EncodeZigZag(v)
+		}
+		buf = binary.BigEndian.AppendUint64(buf, u)
+	}
+	return buf
+}
+
+// orcTestChunks splits data into chunks compressed with zlib, the first
+// stored as it is.
+func orcTestChunks(data []byte, compression uint64) []byte {
+	if compression == orcNone {
+		return data
+	}
+	chunk := func(buf, data []byte, original bool) []byte {
+		header := len(data) << 1
+		if original {
+			header |= 1
+		}
+		buf = append(buf, byte(header), byte(header>>8), byte(header>>16))
+		return append(buf, data...)
+	}
+	half := len(data) / 2
+	buf := chunk(nil, data[:half], true)
+	var compressed bytes.Buffer
+	w, _ := flate.NewWriter(&compressed, flate.BestCompression)
+	w.Write(data[half:])
+	w.Close()
+	return chunk(buf, compressed.Bytes(), false)
+}
+
+// writeTestORC builds an ORC file of two stripes of the same three rows:
+//
+//	id     name  price   day         ts                        flag   score  tags  address.city
+//	1      ab    12.50   2022-01-08  2015-01-01T00:00:00.5Z    true   1.5    [x]   NYC
+//	2      ab    -0.05   1970-01-01  1970-01-01T00:00:00Z      false         []    (null city)
+//	(null) c     100.00  1969-12-31  (null)                    true   2.25   [y]   (null address)
+func writeTestORC(t *testing.T, compression uint64) []byte {
+	t.Helper()
+
+	type stream struct {
+		kind, column uint64
+		data         []byte
+	}
+	streams := []stream{
+		{orcPresent, 1, orcTestLiterals(0xc0)},
+		{orcData, 1, orcTestIntsV2(true, 1, 2)},
+		{orcData, 2, orcTestIntsV2(false, 0, 0, 1)},
+		{orcLength, 2, orcTestIntsV2(false, 2, 1)},
+		{orcDictionaryData, 2, []byte("abc")},
+		{orcData, 3, append(append(protowire.AppendVarint(nil, protowire.EncodeZigZag(1250)),
+			protowire.AppendVarint(nil, protowire.EncodeZigZag(-5))...),
+			protowire.AppendVarint(nil, protowire.EncodeZigZag(10000))...)},
+		{orcSecondary, 3, orcTestIntsV1(true, 2, 2, 2)},
+		{orcData, 4, orcTestIntsV1(true, 19000, 0, -1)},
+		{orcPresent, 5, orcTestLiterals(0xc0)},
+		{orcData, 5, orcTestIntsV1(true, 0, -orcEpoch)},
+		{orcSecondary, 5, orcTestIntsV1(false, 5<<3|7, 0)},
+		{orcData, 6, orcTestLiterals(0xa0)},
+		{orcPresent, 7, orcTestLiterals(0xa0)},
+		{orcData, 7, binary.LittleEndian.AppendUint64(binary.LittleEndian.AppendUint64(nil, math.Float64bits(1.5)), math.Float64bits(2.25))},
+		{orcLength, 8, orcTestIntsV1(false, 1, 0, 1)},
+		{orcLength, 9, orcTestIntsV1(false, 1, 1)},
+		{orcData, 9, []byte("xy")},
+		{orcPresent, 10, orcTestLiterals(0xa0)},
+		{orcPresent, 11, orcTestLiterals(0x80)},
+		{orcLength, 11, orcTestIntsV1(false, 3)},
+		{orcData, 11, []byte("NYC")},
+	}
+	encodings := map[uint64][2]uint64{1: {orcDirectV2}, 2: {orcDictionaryV2, 2}}
+
+	var data, stripeFooter []byte
+	for _, s := range streams {
+		chunks := orcTestChunks(s.data, compression)
+		data = append(data, chunks...)
+		var info []byte
+		info = appendORCVarint(info, 1, s.kind)
+		info = appendORCVarint(info, 2, s.column)
+		info = appendORCVarint(info, 3, uint64(len(chunks)))
+		stripeFooter = appendORCMessage(stripeFooter, 1, info)
+	}
+	for column := uint64(0); column < 12; column++ {
+		var encoding []byte
+		encoding = appendORCVarint(encoding, 1, encodings[column][0])
+		encoding = appendORCVarint(encoding, 2, encodings[column][1])
+		stripeFooter = appendORCMessage(stripeFooter, 2, encoding)
+	}
+	stripeFooter = orcTestChunks(stripeFooter, compression)
+
+	file := []byte("ORC")
+	var footer []byte
+	for i := 0; i < 2; i++ {
+		var stripe []byte
+		stripe = appendORCVarint(stripe, 1, uint64(len(file)))
+		stripe = appendORCVarint(stripe, 3, uint64(len(data)))
+		stripe = appendORCVarint(stripe, 4, uint64(len(stripeFooter)))
+		stripe = appendORCVarint(stripe, 5, 3)
+		footer = appendORCMessage(footer, 3, stripe)
+		file = append(append(file, data...), stripeFooter...)
+	}
+
+	types := []struct {
+		kind     uint64
+		subtypes []uint64
+		names    []string
+		scale    uint64
+	}{
+		{orcStruct, []uint64{1, 2, 3, 4, 5, 6, 7, 8, 10}, []string{"id", "name", "price", "day", "ts", "flag", "score", "tags", "address"}, 0},
+		{kind: orcLong}, {kind: orcString}, {kind: orcDecimal, scale: 2}, {kind: orcDate}, {kind: orcTimestamp},
+		{kind: orcBoolean}, {kind: orcDouble}, {kind: orcList, subtypes: []uint64{9}}, {kind: orcString},
+		{orcStruct, []uint64{11}, []string{"city"}, 0}, {kind: orcChar},
+	}
+	for _, typ := range types {
+		var message []byte
+		message = appendORCVarint(message, 1, typ.kind)
+		if len(typ.subtypes) > 0 {
+			var packed []byte
+			for _, subtype := range typ.subtypes {
+				packed = protowire.AppendVarint(packed, subtype)
+			}
+			message = appendORCMessage(message, 2, packed)
+		}
+		for _, name := range typ.names {
+			message = appendORCMessage(message, 3, []byte(name))
+		}
+		message = appendORCVarint(message, 6, typ.scale)
+		footer = appendORCMessage(footer, 4, message)
+	}
+	footer = appendORCVarint(footer, 6, 6)
+	footer = orcTestChunks(footer, compression)
+	file = append(file, footer...)
+
+	var postscript []byte
+	postscript = appendORCVarint(postscript, 1, uint64(len(footer)))
+	postscript = appendORCVarint(postscript, 2, compression)
+	postscript = appendORCVarint(postscript, 3, 256<<10)
+	postscript = appendORCMessage(postscript, 8000, orcMagic)
+	return append(append(file, postscript...), byte(len(postscript)))
+}
+
+func TestORCRecordReader(t *testing.T) {
+	want := [][]string{
+		{"1", "ab", "12.50", "2022-01-08", "2015-01-01T00:00:00.5Z", "true", "1.5", "NYC"},
+		{"2", "ab", "-0.05", "1970-01-01", "1970-01-01T00:00:00Z", "false", "", ""},
+		{"", "c", "100.00", "1969-12-31", "", "true", "2.25", ""},
+	}
+
+	for _, compression := range []uint64{orcNone, orcZlib} {
+		data := writeTestORC(t, compression)
+		file, columns, header, skipped, err := openORCColumns(bytes.NewReader(data), "test.orc", int64(len(data)), Options{})
+		if err != nil {
+			t.Fatalf("Compression %d: failed to open: %v", compression, err)
+		}
+		if want := []string{"id", "name", "price", "day", "ts", "flag", "score", "address.city"}; !reflect.DeepEqual(header, want) {
+			t.Errorf("Compression %d: expected header %q, got %q", compression, want, header)
+		}
+		if !reflect.DeepEqual(skipped, []string{"tags"}) {
+			t.Errorf("Compression %d: expected tags to be skipped, got %q", compression, skipped)
+		}
+		if file.rows != 6 {
+			t.Errorf("Compression %d: expected 6 rows in the footer, got %d", compression, file.rows)
+		}
+
+		reader := &orcRecordReader{file: file, columns: columns, width: len(header)}
+		for i := 0; i < 6; i++ {
+			record, err := reader.next()
+			if err != nil {
+				t.Fatalf("Compression %d: failed to read row %d: %v", compression, i, err)
+			}
+			if !reflect.DeepEqual(record, want[i%3]) {
+				t.Errorf("Compression %d: row %d: expected %q, got %q", compression, i, want[i%3], record)
+			}
+		}
+		if _, err := reader.next(); err != io.EOF {
+			t.Errorf("Compression %d: expected EOF after the last row, got %v", compression, err)
+		}
+	}
+}
+
+func TestProfileORC(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.orc")
+	if err := os.WriteFile(path, writeTestORC(t, orcZlib), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	profile, err := ProfileDatasetWithOptions(path, Options{})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if profile.Format != "ORC" || profile.RowCount != 6 || profile.ColumnCount != 8 {
+		t.Errorf("Expected 6 rows and 8 columns of ORC, got %d and %d of %s", profile.RowCount, profile.ColumnCount, profile.Format)
+	}
+	if col := profile.Columns["price"]; col == nil || col.DataType != "float" || col.Max != 100.0 {
+		t.Errorf("Expected price to be a float column up to 100, got %+v", col)
+	}
+	if col := profile.Columns["address.city"]; col == nil || col.MissingCount != 4 {
+		t.Errorf("Expected 4 missing cities, got %+v", col)
+	}
+	if !containsString(profile.Notes, "Skipped nested or repeated columns: tags") {
+		t.Errorf("Expected a note on the skipped column, got %q", profile.Notes)
+	}
+
+	counts, err := CountSource(context.Background(), path, Options{})
+	if err != nil || len(counts) != 1 || counts[0].Rows != 6 || counts[0].Columns != 8 || counts[0].Method != CountMetadata {
+		t.Errorf("Expected 6 rows and 8 columns from the footer, got %+v (%v)", counts, err)
+	}
+
+	broken := filepath.Join(t.TempDir(), "broken.orc")
+	if err := os.WriteFile(broken, []byte("ORC not really"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ProfileDatasetWithOptions(broken, Options{}); err == nil || !strings.Contains(err.Error(), "failed to read ORC file") {
+		t.Errorf("Expected an error for a file that is not ORC, got %v", err)
+	}
+}
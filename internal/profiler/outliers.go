@@ -0,0 +1,173 @@
+package profiler
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Outlier detection methods.
+const (
+	OutlierMethodZScore = "zscore" // further than a number of standard deviations from the mean
+	OutlierMethodIQR    = "iqr"    // further than a multiple of the interquartile range outside the quartiles (Tukey's fences)
+	OutlierMethodMAD    = "mad"    // modified z-score, scaled by the median absolute deviation, above a threshold
+)
+
+const (
+	// madScale turns a MAD into a modified z-score as Iglewicz and Hoaglin
+	// define it, making it comparable to a z-score for normal data.
+	madScale = 0.6745
+
+	// outlierExamples is the number of outlier values kept per column.
+	outlierExamples = 5
+)
+
+// OutlierMethods lists the outlier detection methods.
+func OutlierMethods() []string {
+	return []string{OutlierMethodZScore, OutlierMethodIQR, OutlierMethodMAD}
+}
+
+// OutlierDetection chooses how numeric columns are checked for outliers.
+// Zero thresholds keep the defaults of DefaultThresholds.
+type OutlierDetection struct {
+	Method        string  `yaml:"method"`         // zscore, iqr or mad; zscore when empty
+	ZScore        float64 `yaml:"z_score"`        // zscore: standard deviations from the mean
+	IQRMultiplier float64 `yaml:"iqr_multiplier"` // iqr: interquartile ranges outside the quartiles
+	MADThreshold  float64 `yaml:"mad_threshold"`  // mad: modified z-score
+}
+
+// Validate checks the method and thresholds of d.
+func (d *OutlierDetection) Validate() error {
+	switch d.Method {
+	case "", OutlierMethodZScore, OutlierMethodIQR, OutlierMethodMAD:
+	default:
+		return fmt.Errorf("unsupported outlier method: %s (use zscore, iqr or mad)", d.Method)
+	}
+	if d.ZScore < 0 || d.IQRMultiplier < 0 || d.MADThreshold < 0 {
+		return fmt.Errorf("outlier thresholds must not be negative")
+	}
+	return nil
+}
+
+// apply sets the outlier method and thresholds of d on t.
+func (d *OutlierDetection) apply(t *Thresholds) {
+	if d == nil {
+		return
+	}
+	if d.Method != "" {
+		t.OutlierMethod = d.Method
+	}
+	if d.ZScore > 0 {
+		t.OutlierZScore = d.ZScore
+	}
+	if d.IQRMultiplier > 0 {
+		t.OutlierIQR = d.IQRMultiplier
+	}
+	if d.MADThreshold > 0 {
+		t.OutlierMAD = d.MADThreshold
+	}
+}
+
+// OutlierSummary reports how the outliers of a numeric column were found:
+// values below Lower or above Upper are outliers.
+type OutlierSummary struct {
+	Method    string
+	Threshold float64 // z-score, IQR multiplier or modified z-score of the method
+	Lower     float64
+	Upper     float64
+	Count     int       // estimated from the t-digest once values are no longer kept
+	Examples  []float64 // the most extreme outliers, furthest first; only the min and max once values are no longer kept
+}
+
+// outlierBounds returns the outlier bounds of t for values with the given
+// mean, standard deviation, quantile function and MAD about a median, or
+// false when their spread is zero and no value stands out.
+func outlierBounds(t Thresholds, mean, stdDev float64, quantile func(q float64) float64, mad func(median float64) float64) (*OutlierSummary, bool) {
+	summary := &OutlierSummary{Method: t.OutlierMethod}
+	var low, high, scale float64
+	switch t.OutlierMethod {
+	case OutlierMethodIQR:
+		low, high = quantile(0.25), quantile(0.75)
+		scale = high - low
+		summary.Threshold = t.OutlierIQR
+	case OutlierMethodMAD:
+		low = quantile(0.5)
+		high = low
+		scale = mad(low) / madScale
+		summary.Threshold = t.OutlierMAD
+	default:
+		// Profiles written before the method was recorded used z-scores
+		summary.Method = OutlierMethodZScore
+		low, high = mean, mean
+		scale = stdDev
+		summary.Threshold = t.OutlierZScore
+	}
+
+	spread := summary.Threshold * scale
+	if spread <= 0 {
+		return nil, false
+	}
+	summary.Lower, summary.Upper = low-spread, high+spread
+	return summary, true
+}
+
+// outside reports whether v is an outlier under s.
+func (s *OutlierSummary) outside(v float64) bool {
+	return v < s.Lower || v > s.Upper
+}
+
+// beyond is how far v lies outside the bounds of s.
+func (s *OutlierSummary) beyond(v float64) float64 {
+	return math.Max(s.Lower-v, v-s.Upper)
+}
+
+// exactOutliers counts the outliers among sorted values and keeps the most
+// extreme of them as examples.
+func (s *OutlierSummary) exactOutliers(sorted []float64) {
+	var outliers []float64
+	for _, v := range sorted {
+		if s.outside(v) {
+			outliers = append(outliers, v)
+		}
+	}
+	s.Count = len(outliers)
+
+	sort.SliceStable(outliers, func(i, j int) bool { return s.beyond(outliers[i]) > s.beyond(outliers[j]) })
+	if len(outliers) > outlierExamples {
+		outliers = outliers[:outlierExamples]
+	}
+	s.Examples = outliers
+}
+
+// description describes the outliers of s for a quality issue.
+func (s *OutlierSummary) description(percent float64) string {
+	return fmt.Sprintf("%d outliers detected (%.2f%%) outside [%.4g, %.4g] by %s", s.Count, percent, s.Lower, s.Upper, s.Rule())
+}
+
+// Rule names the method and threshold of s, e.g. "1.5 × IQR".
+func (s *OutlierSummary) Rule() string {
+	switch s.Method {
+	case OutlierMethodIQR:
+		return fmt.Sprintf("%g × IQR", s.Threshold)
+	case OutlierMethodMAD:
+		return fmt.Sprintf("modified z-score > %g", s.Threshold)
+	default:
+		return fmt.Sprintf("z-score > %g", s.Threshold)
+	}
+}
+
+// medianAbsoluteDeviation is the median absolute deviation of sorted values
+// from median.
+func medianAbsoluteDeviation(sorted []float64, median float64) float64 {
+	n := len(sorted)
+	deviations := make([]float64, n)
+	for i, v := range sorted {
+		deviations[i] = math.Abs(v - median)
+	}
+	sort.Float64s(deviations)
+	mid := n / 2
+	if n%2 == 0 {
+		return (deviations[mid-1] + deviations[mid]) / 2
+	}
+	return deviations[mid]
+}
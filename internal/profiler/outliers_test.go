@@ -0,0 +1,133 @@
+package profiler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// skewedStats holds 1 to 50 and three large values, one of them extreme
+// enough to inflate the standard deviation past the other two.
+func skewedStats() *numericStats {
+	stats := newNumericStats()
+	for i := 1; i <= 50; i++ {
+		stats.add(float64(i))
+	}
+	for _, v := range []float64{300, 200, 5000} {
+		stats.add(v)
+	}
+	return stats
+}
+
+func TestNumericStatsOutlierMethods(t *testing.T) {
+	testCases := []struct {
+		method   string
+		count    int
+		examples []float64
+	}{
+		{OutlierMethodZScore, 1, []float64{5000}},
+		{OutlierMethodIQR, 3, []float64{5000, 300, 200}},
+		{OutlierMethodMAD, 3, []float64{5000, 300, 200}},
+	}
+
+	for _, tc := range testCases {
+		thresholds := DefaultThresholds()
+		(&OutlierDetection{Method: tc.method}).apply(&thresholds)
+
+		col := &ColumnProfile{}
+		skewedStats().apply(col, Options{}.histogram(), thresholds)
+
+		o := col.Outliers
+		if o == nil || o.Method != tc.method || o.Count != tc.count || !slices.Equal(o.Examples, tc.examples) {
+			t.Errorf("%s: expected %d outliers %v, got %+v", tc.method, tc.count, tc.examples, o)
+			continue
+		}
+		if len(col.QualityIssues) == 0 || !strings.Contains(col.QualityIssues[len(col.QualityIssues)-1].Description, fmt.Sprintf("%d outliers detected", tc.count)) {
+			t.Errorf("%s: expected an outlier issue, got %+v", tc.method, col.QualityIssues)
+		}
+	}
+
+	// Tukey's fences lie 1.5 IQRs outside the quartiles
+	thresholds := DefaultThresholds()
+	thresholds.OutlierMethod = OutlierMethodIQR
+	col := &ColumnProfile{}
+	stats := skewedStats()
+	stats.apply(col, Options{}.histogram(), thresholds)
+	sorted := stats.sorted()
+	q1, q3 := sortedQuantile(sorted, 0.25), sortedQuantile(sorted, 0.75)
+	if col.Outliers.Lower != q1-1.5*(q3-q1) || col.Outliers.Upper != q3+1.5*(q3-q1) {
+		t.Errorf("Expected fences around [%v, %v], got %+v", q1, q3, col.Outliers)
+	}
+}
+
+func TestNumericStatsOutliersConstant(t *testing.T) {
+	// Most values equal: the quartiles and MAD are zero, and no value stands out
+	stats := newNumericStats()
+	stats.addN(7, 20)
+	stats.add(8)
+
+	for _, method := range OutlierMethods()[1:] {
+		thresholds := DefaultThresholds()
+		thresholds.OutlierMethod = method
+		col := &ColumnProfile{}
+		stats.apply(col, Options{}.histogram(), thresholds)
+		if col.Outliers != nil {
+			t.Errorf("%s: expected no outlier bounds without spread, got %+v", method, col.Outliers)
+		}
+	}
+}
+
+func TestNumericStatsOutliersApproximate(t *testing.T) {
+	stats := newNumericStats()
+	n := 3 * exactNumericLimit
+	for i := 0; i < n; i++ {
+		stats.add(float64(i))
+	}
+	stats.add(1e9)
+
+	for _, method := range OutlierMethods() {
+		thresholds := DefaultThresholds()
+		thresholds.OutlierMethod = method
+		col := &ColumnProfile{}
+		stats.apply(col, Options{}.histogram(), thresholds)
+
+		// The tails of the t-digest blur the lone outlier with its neighbours
+		o := col.Outliers
+		if o == nil || o.Count < 1 || o.Count > n/1000 || !slices.Equal(o.Examples, []float64{1e9}) {
+			t.Errorf("%s: expected about one outlier, the max, got %+v", method, o)
+		}
+	}
+}
+
+func TestProfileOutlierDetection(t *testing.T) {
+	var content strings.Builder
+	content.WriteString("amount\n")
+	for i := 1; i <= 50; i++ {
+		fmt.Fprintf(&content, "%d\n", i)
+	}
+	content.WriteString("200\n300\n5000\n")
+	path := filepath.Join(t.TempDir(), "skewed.csv")
+	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		t.Fatalf("Failed to write data: %v", err)
+	}
+
+	profile, err := ProfileDatasetWithOptions(path, Options{Outliers: &OutlierDetection{Method: OutlierMethodIQR, IQRMultiplier: 3}})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if profile.Thresholds.OutlierMethod != OutlierMethodIQR || profile.Thresholds.OutlierIQR != 3 || profile.Thresholds.OutlierZScore != 3 {
+		t.Errorf("Expected the IQR method with a multiplier of 3 in the thresholds, got %+v", profile.Thresholds)
+	}
+	if o := profile.Columns["amount"].Outliers; o == nil || o.Method != OutlierMethodIQR || o.Threshold != 3 || o.Count != 3 {
+		t.Errorf("Expected 3 outliers by 3 × IQR, got %+v", o)
+	}
+
+	for _, detection := range []OutlierDetection{{Method: "grubbs"}, {ZScore: -1}} {
+		if _, err := ProfileDatasetWithOptions(path, Options{Outliers: &detection}); err == nil {
+			t.Errorf("Expected %+v to be rejected", detection)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package profiler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/text/transform"
+)
+
+// parallelMinChunk is the smallest byte range given a worker of its own;
+// smaller files are not worth splitting.
+var parallelMinChunk int64 = 4 << 20
+
+// parallelScanBuffer is the read size when scanning for quotes and row
+// boundaries.
+const parallelScanBuffer = 256 * 1024
+
+// profileDelimitedParallel profiles a local CSV or TSV file with up to
+// opts.Parallel workers, each parsing a byte range that starts and ends on a
+// row boundary. Per-range statistics are merged in file order. When the file
+// has to be read sequentially it returns no profile and the reason, which is
+// empty when the file is simply too small to split.
+func profileDelimitedParallel(file *os.File, name string, size int64, comma rune, format string, opts Options) (*DatasetProfile, string, error) {
+	startTime := time.Now()
+
+	switch {
+	case opts.sampling():
+		return nil, "--parallel does not combine with --sample", nil
+	case opts.MaxBytes > 0:
+		return nil, "--parallel does not combine with --range", nil
+	case opts.Comment != 0:
+		return nil, "--parallel does not combine with --comment", nil
+	case opts.WeightColumn != "":
+		return nil, "--parallel does not combine with --weight-column", nil
+	case opts.Rows != nil:
+		return nil, "rows are passed on in file order", nil
+	}
+
+	reader, err := newDelimitedReader(io.NewSectionReader(file, 0, size), name, comma, format, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	reader.close()
+
+	switch reader.text.encoding {
+	case EncodingUTF16LE, EncodingUTF16BE:
+		return nil, fmt.Sprintf("--parallel does not apply to %s input", reader.text.encoding), nil
+	}
+	if reader.source.codec != "" {
+		return nil, fmt.Sprintf("--parallel does not apply to %s-compressed input", reader.source.codec), nil
+	}
+
+	start, err := dataOffset(file, size, reader)
+	if err != nil {
+		return nil, "", err
+	}
+
+	workers := int64(opts.Parallel)
+	if chunks := (size - start) / parallelMinChunk; chunks < workers {
+		workers = chunks
+	}
+	if workers < 2 {
+		return nil, "", nil
+	}
+
+	bounds, err := chunkBounds(file, start, size, int(workers), quoteByte(opts.Quote))
+	if err != nil {
+		return nil, "", err
+	}
+
+	opts.progress = opts.tracker(name)
+
+	chunks := make([]*recordAccumulator, len(bounds)-1)
+	errs := make([]error, len(chunks))
+	badRows := make([]int, len(chunks))
+	interrupted := make([]bool, len(chunks))
+	var footer *footerFilter
+
+	var wg sync.WaitGroup
+	for i := range chunks {
+		chunks[i] = newRecordAccumulator(reader.header, nil, opts)
+		if i > 0 {
+			chunks[i].deferTypes()
+		}
+
+		section := io.NewSectionReader(file, bounds[i], bounds[i+1]-bounds[i])
+		next := newChunkReader(section, reader, opts)
+		if i == len(chunks)-1 {
+			footer = newFooterFilter(next, opts.SkipFooter)
+			next = footer.next
+		}
+
+		// Each range stops where it is when the context is done
+		stop := &DatasetProfile{}
+		next = untilDone(stop, next, opts)
+
+		wg.Add(1)
+		go func(i int, next func() ([]string, error)) {
+			defer wg.Done()
+			for {
+				record, err := next()
+				if err == io.EOF {
+					interrupted[i] = stop.Interrupted
+					return
+				}
+				if err != nil && badRow(err) {
+					if opts.SkipBadRows {
+						badRows[i]++
+						continue
+					}
+					errs[i] = fmt.Errorf("error reading %s at byte %d: %w (--skip-bad-rows skips such rows)", format, bounds[i], err)
+					return
+				}
+				if err != nil {
+					errs[i] = fmt.Errorf("error reading %s at byte %d: %w", format, bounds[i], err)
+					return
+				}
+				chunks[i].add(record)
+				opts.progress.row()
+			}
+		}(i, next)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	for _, chunk := range chunks[1:] {
+		chunks[0].merge(chunk)
+	}
+
+	profile := newDatasetProfile(name, size, format, reader.header)
+	chunks[0].finish(profile)
+	profile.QualityScore = CalculateQualityScore(profile)
+	for _, stopped := range interrupted {
+		profile.Interrupted = profile.Interrupted || stopped
+	}
+
+	reader.footer = footer
+	for _, n := range badRows {
+		reader.badRows += n
+	}
+	reader.describe(profile)
+	profile.Notes = append(profile.Notes, fmt.Sprintf("Parsed in %d byte ranges concurrently (--parallel)", len(chunks)))
+
+	profile.ProcessingTime = time.Since(startTime)
+
+	return profile, "", nil
+}
+
+// dataOffset returns where the first record after the header starts: past
+// any byte order mark, the lines the reader skipped and the header itself.
+// Only encodings with single-byte line breaks get here, so offsets in the
+// file match offsets in the text.
+func dataOffset(file *os.File, size int64, reader *delimitedReader) (int64, error) {
+	var offset int64
+	head := make([]byte, 3)
+	n, _ := file.ReadAt(head, 0)
+	if reader.text.encoding == EncodingUTF8 && bytes.HasPrefix(head[:n], byteOrderMarks[0].bom) {
+		offset = int64(len(byteOrderMarks[0].bom))
+	}
+
+	br := bufio.NewReader(io.NewSectionReader(file, offset, size-offset))
+	for i := 0; i < reader.skipped; i++ {
+		line, err := br.ReadBytes('\n')
+		offset += int64(len(line))
+		if err != nil {
+			break
+		}
+	}
+
+	var r io.Reader = br
+	if swapper := newQuoteSwapper(reader.opts.Quote); swapper != nil {
+		r = swapper.reader(r)
+	}
+	header := csv.NewReader(r)
+	header.Comma = reader.dialect.comma
+	header.LazyQuotes = true
+	if _, err := header.Read(); err != nil {
+		return 0, fmt.Errorf("failed to read %s header: %w", reader.format, err)
+	}
+
+	return offset + header.InputOffset(), nil
+}
+
+// chunkBounds splits [start, size) into workers byte ranges that begin on
+// row boundaries. A newline only ends a row outside quotes, so the quotes
+// in each range are counted first, concurrently, and a boundary is moved to
+// the first newline at which the running count is even.
+func chunkBounds(file *os.File, start, size int64, workers int, quote byte) ([]int64, error) {
+	bounds := make([]int64, workers+1)
+	for i := range bounds {
+		bounds[i] = start + (size-start)*int64(i)/int64(workers)
+	}
+
+	quotes := make([]int64, workers)
+	errs := make([]error, workers)
+	if quote != 0 {
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				quotes[i], errs[i] = countByte(io.NewSectionReader(file, bounds[i], bounds[i+1]-bounds[i]), quote)
+			}(i)
+		}
+		wg.Wait()
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan file: %w", err)
+		}
+	}
+
+	var seen int64
+	for i := 1; i < workers; i++ {
+		seen += quotes[i-1]
+		next, err := rowStart(io.NewSectionReader(file, bounds[i], size-bounds[i]), quote, seen%2 == 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan file: %w", err)
+		}
+		bounds[i] += next
+	}
+
+	return bounds, nil
+}
+
+func countByte(r io.Reader, b byte) (int64, error) {
+	var count int64
+	buf := make([]byte, parallelScanBuffer)
+	for {
+		n, err := r.Read(buf)
+		count += int64(bytes.Count(buf[:n], []byte{b}))
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// rowStart returns the offset in r just past the first newline outside
+// quotes, or the length of r when there is none.
+func rowStart(r io.Reader, quote byte, quoted bool) (int64, error) {
+	var offset int64
+	buf := make([]byte, parallelScanBuffer)
+	for {
+		n, err := r.Read(buf)
+		for i, c := range buf[:n] {
+			switch {
+			case c == quote && quote != 0:
+				quoted = !quoted
+			case c == '\n' && !quoted:
+				return offset + int64(i) + 1, nil
+			}
+		}
+		offset += int64(n)
+		if err == io.EOF {
+			return offset, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+func quoteByte(quote rune) byte {
+	switch quote {
+	case 0:
+		return '"'
+	case NoQuote:
+		return 0
+	default:
+		return byte(quote)
+	}
+}
+
+// newChunkReader reads the records of one byte range with the dialect and
+// encoding found for the whole file.
+func newChunkReader(r io.Reader, reader *delimitedReader, opts Options) func() ([]string, error) {
+	if enc := textEncoding(reader.text.encoding); enc != nil {
+		r = transform.NewReader(r, enc.NewDecoder())
+	}
+
+	swapper := newQuoteSwapper(opts.Quote)
+	if swapper != nil {
+		r = swapper.reader(r)
+	}
+
+	records := csv.NewReader(bufio.NewReaderSize(r, parallelScanBuffer))
+	records.Comma = reader.dialect.comma
+	records.FieldsPerRecord = len(reader.header)
+	if opts.Quote == NoQuote {
+		records.LazyQuotes = true
+	}
+
+	return func() ([]string, error) {
+		record, err := records.Read()
+		if swapper != nil && record != nil {
+			swapper.record(record)
+		}
+		return record, err
+	}
+}
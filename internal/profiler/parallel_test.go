@@ -0,0 +1,178 @@
+package profiler
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func withParallelMinChunk(t *testing.T, size int64) {
+	t.Helper()
+
+	previous := parallelMinChunk
+	parallelMinChunk = size
+	t.Cleanup(func() { parallelMinChunk = previous })
+}
+
+func parallelCSV(rows int) string {
+	var b strings.Builder
+	b.WriteString("Export generated 2024-01-01\n\nid,name,amount,day,comment\n")
+	for i := 0; i < rows; i++ {
+		comment := ""
+		switch i % 7 {
+		case 0:
+			comment = "\"multi\nline, with \"\"quotes\"\"\""
+		case 3:
+			comment = "plain"
+		}
+		day := time.Date(2024, 1, 1+i%400, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+		fmt.Fprintf(&b, "%d,name%d,%.2f,%s,%s\n", i%(rows-5), i%13, float64(i%101)*1.25, day, comment)
+	}
+	b.WriteString("Total,,12345,,\n")
+	return b.String()
+}
+
+func TestProfileParallelMatchesSequential(t *testing.T) {
+	withParallelMinChunk(t, 256)
+	path := writeTestFile(t, "data.csv", parallelCSV(2000))
+
+	want, err := ProfileDatasetWithOptions(path, Options{Preview: 300})
+	if err != nil {
+		t.Fatalf("Failed to profile sequentially: %v", err)
+	}
+	got, err := ProfileDatasetWithOptions(path, Options{Parallel: 8, Preview: 300})
+	if err != nil {
+		t.Fatalf("Failed to profile in parallel: %v", err)
+	}
+
+	if !strings.Contains(strings.Join(got.Notes, " "), "Parsed in 8 byte ranges concurrently") {
+		t.Fatalf("Expected a parallel parsing note, got %v", got.Notes)
+	}
+
+	if got.RowCount != want.RowCount || got.MissingCells != want.MissingCells || got.DuplicateRows != want.DuplicateRows {
+		t.Errorf("Expected %d rows, %d missing and %d duplicates, got %d, %d and %d",
+			want.RowCount, want.MissingCells, want.DuplicateRows, got.RowCount, got.MissingCells, got.DuplicateRows)
+	}
+	if got.ContentDigest != want.ContentDigest {
+		t.Errorf("Expected content digest %s, got %s", want.ContentDigest, got.ContentDigest)
+	}
+	if got.QualityScore != want.QualityScore {
+		t.Errorf("Expected quality score %d, got %d", want.QualityScore, got.QualityScore)
+	}
+	if len(want.Preview.Rows) != 300 || !reflect.DeepEqual(got.Preview, want.Preview) {
+		t.Errorf("Expected the first 300 rows in the preview, got %d rows", len(got.Preview.Rows))
+	}
+	if !reflect.DeepEqual(got.CorrelationMatrix, want.CorrelationMatrix) {
+		t.Errorf("Expected correlations %+v, got %+v", want.CorrelationMatrix, got.CorrelationMatrix)
+	}
+
+	for name, w := range want.Columns {
+		g := got.Columns[name]
+		if g == nil {
+			t.Errorf("Missing column %s", name)
+			continue
+		}
+		if g.DataType != w.DataType || g.Count != w.Count || g.MissingCount != w.MissingCount || g.UniqueCount != w.UniqueCount {
+			t.Errorf("%s: expected %s with %d values, %d missing, %d unique; got %s with %d, %d, %d",
+				name, w.DataType, w.Count, w.MissingCount, w.UniqueCount, g.DataType, g.Count, g.MissingCount, g.UniqueCount)
+		}
+		if fmt.Sprint(g.TopValues) != fmt.Sprint(w.TopValues) {
+			t.Errorf("%s: expected top values %v, got %v", name, w.TopValues, g.TopValues)
+		}
+		if g.Digest != w.Digest {
+			t.Errorf("%s: expected digest %s, got %s", name, w.Digest, g.Digest)
+		}
+		if g.Min != w.Min || g.Max != w.Max || g.Median != w.Median || math.Abs(g.Mean-w.Mean) > 1e-9 {
+			t.Errorf("%s: expected min %v, max %v, median %v, mean %v; got %v, %v, %v, %v",
+				name, w.Min, w.Max, w.Median, w.Mean, g.Min, g.Max, g.Median, g.Mean)
+		}
+		if fmt.Sprint(g.HistogramBuckets) != fmt.Sprint(w.HistogramBuckets) {
+			t.Errorf("%s: expected histogram %v, got %v", name, w.HistogramBuckets, g.HistogramBuckets)
+		}
+		if !reflect.DeepEqual(g.DateTime, w.DateTime) {
+			t.Errorf("%s: expected datetime stats %v, got %v", name, w.DateTime, g.DateTime)
+		}
+		if !reflect.DeepEqual(g.Text, w.Text) {
+			t.Errorf("%s: expected text stats %+v, got %+v", name, w.Text, g.Text)
+		}
+		if fmt.Sprint(g.Nullability) != fmt.Sprint(w.Nullability) {
+			t.Errorf("%s: expected nullability %v, got %v", name, w.Nullability, g.Nullability)
+		}
+		if !reflect.DeepEqual(g.Conversion, w.Conversion) {
+			t.Errorf("%s: expected conversion %+v, got %+v", name, w.Conversion, g.Conversion)
+		}
+	}
+}
+
+func TestProfileParallelFallback(t *testing.T) {
+	withParallelMinChunk(t, 256)
+	path := writeTestFile(t, "data.csv", parallelCSV(500))
+
+	profile, err := ProfileDatasetWithOptions(path, Options{Parallel: 4, SampleSize: 100})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if !strings.Contains(strings.Join(profile.Notes, " "), "Parsed sequentially: --parallel does not combine with --sample") {
+		t.Errorf("Expected a sequential parsing note, got %v", profile.Notes)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	compressed := filepath.Join(t.TempDir(), "data.csv.gz")
+	if err := os.WriteFile(compressed, gzipBytes(t, string(content)), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	profile, err = ProfileDatasetWithOptions(compressed, Options{Parallel: 4})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if profile.RowCount != 500 || !strings.Contains(strings.Join(profile.Notes, " "), "does not apply to gzip-compressed input") {
+		t.Errorf("Expected 500 rows read sequentially, got %d and %v", profile.RowCount, profile.Notes)
+	}
+
+	withParallelMinChunk(t, 1<<20)
+	profile, err = ProfileDatasetWithOptions(path, Options{Parallel: 4})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if strings.Contains(strings.Join(profile.Notes, " "), "arallel") {
+		t.Errorf("Expected a small file to be read sequentially without a note, got %v", profile.Notes)
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{Parallel: -1}); err == nil {
+		t.Error("Expected an error for a negative worker count")
+	}
+}
+
+func TestChunkBoundsSkipQuotedNewlines(t *testing.T) {
+	content := "a,\"x\ny\nz\"\nb,c\nd,\"e\n\"\"f\"\"\"\ng,h\n"
+	path := filepath.Join(t.TempDir(), "data.csv")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer file.Close()
+
+	rowStarts := map[int64]bool{0: true, 10: true, 14: true, 26: true, int64(len(content)): true}
+	for workers := 2; workers <= len(content); workers++ {
+		bounds, err := chunkBounds(file, 0, int64(len(content)), workers, '"')
+		if err != nil {
+			t.Fatalf("Failed to split: %v", err)
+		}
+		for _, bound := range bounds {
+			if !rowStarts[bound] {
+				t.Fatalf("%d workers: bound %d is not a row start in %v", workers, bound, bounds)
+			}
+		}
+	}
+}
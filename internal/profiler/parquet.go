@@ -0,0 +1,591 @@
+package profiler
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+const parquetBatchSize = 1024
+
+// parquetColumn describes a flat leaf column and what the row-group
+// statistics already tell us about it. nullCount is known when counted is
+// set and min and max when bounded is: every row group has statistics.
+type parquetColumn struct {
+	name       string
+	leaf       parquet.LeafColumn
+	allNull    bool
+	constant   bool
+	value      string
+	counted    bool
+	nullCount  int64
+	bounded    bool
+	min, max   parquet.Value
+	dictionary bool
+}
+
+func (c *parquetColumn) fromMetadata() bool {
+	return c.allNull || c.constant
+}
+
+func ProfileParquet(filePath string) (*DatasetProfile, error) {
+	return profileParquet(filePath, Options{})
+}
+
+func profileParquet(filePath string, opts Options) (*DatasetProfile, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file stats: %w", err)
+	}
+
+	return profileParquetReader(file, filepath.Base(filePath), fileInfo.Size(), opts)
+}
+
+func profileParquetReader(r io.ReaderAt, name string, size int64, opts Options) (*DatasetProfile, error) {
+	startTime := time.Now()
+
+	if err := opts.textOnly("Parquet files"); err != nil {
+		return nil, err
+	}
+
+	if codec := compressionExt(name); codec != "" {
+		return nil, fmt.Errorf("%s-compressed Parquet files are not supported: Parquet compresses its pages itself", codec)
+	}
+
+	pf, err := parquet.OpenFile(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Parquet file: %w", err)
+	}
+
+	columns, skipped := parquetColumns(pf)
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no flat columns found in Parquet schema")
+	}
+
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.name
+	}
+
+	profile := newDatasetProfile(name, size, "Parquet", header)
+
+	// Dictionary counts cover every row, null values included, so they are
+	// not used when sampling or reading null values
+	reader := newParquetRecordReader(pf, columns, !opts.sampling() && len(opts.NullValues) == 0)
+
+	next := untilDone(profile, reader.next, opts)
+	var sampler *rowSampler
+	if opts.sampling() {
+		sampler = newRowSampler(next, opts)
+		next = sampler.next
+	}
+
+	if err := profileRecords(profile, header, next, reader.counts, opts); err != nil {
+		return nil, err
+	}
+
+	if sampler != nil {
+		sampler.apply(profile)
+	}
+
+	if profile.SampleStrategy != "" {
+		applyParquetStatistics(profile, columns, pf.NumRows(), opts)
+	}
+
+	answered := 0
+	for _, col := range columns {
+		if col.fromMetadata() {
+			answered++
+		}
+	}
+
+	if answered > 0 {
+		profile.Notes = append(profile.Notes, fmt.Sprintf(
+			"%d of %d columns answered from Parquet row-group statistics without decoding", answered, len(columns)))
+	}
+
+	if len(reader.counts) > 0 {
+		profile.Notes = append(profile.Notes, fmt.Sprintf(
+			"Unique counts and top values of %d dictionary-encoded columns computed from dictionary indexes", len(reader.counts)))
+	}
+
+	if len(skipped) > 0 {
+		profile.Notes = append(profile.Notes, fmt.Sprintf(
+			"Skipped nested or repeated columns: %s", strings.Join(skipped, ", ")))
+	}
+
+	profile.QualityScore = CalculateQualityScore(profile)
+
+	profile.ProcessingTime = time.Since(startTime)
+
+	return profile, nil
+}
+
+// parquetColumns lists the flat leaf columns of the file and reads their
+// null counts and bounds from the row-group statistics. Columns that are
+// entirely null, and columns holding a single value with no nulls, need no
+// decoding at all.
+func parquetColumns(pf *parquet.File) ([]*parquetColumn, []string) {
+	columns := make([]*parquetColumn, 0)
+	skipped := make([]string, 0)
+
+	for _, path := range pf.Schema().Columns() {
+		leaf, ok := pf.Schema().Lookup(path...)
+		if !ok {
+			continue
+		}
+
+		name := strings.Join(path, ".")
+		if leaf.MaxRepetitionLevel > 0 {
+			skipped = append(skipped, name)
+			continue
+		}
+
+		columns = append(columns, &parquetColumn{name: name, leaf: leaf})
+	}
+
+	for _, col := range columns {
+		col.allNull, col.constant = true, true
+		col.counted, col.bounded = true, true
+		columnType := col.leaf.Node.Type()
+
+		for _, rowGroup := range pf.RowGroups() {
+			chunk, ok := rowGroup.ColumnChunks()[col.leaf.ColumnIndex].(*parquet.FileColumnChunk)
+			if !ok || chunk.NumValues() == 0 {
+				col.allNull, col.constant = false, false
+				col.counted, col.bounded = false, false
+				break
+			}
+
+			// A chunk with values but no bounds has no statistics at all,
+			// and its null count of 0 means nothing
+			min, max, hasBounds := chunk.Bounds()
+			nulls := chunk.NullCount()
+			if nulls != chunk.NumValues() {
+				col.allNull = false
+				if !hasBounds {
+					col.counted, col.bounded = false, false
+				}
+			}
+			col.nullCount += nulls
+
+			if hasBounds {
+				if col.min.IsNull() || columnType.Compare(min, col.min) < 0 {
+					col.min = min
+				}
+				if col.max.IsNull() || columnType.Compare(max, col.max) > 0 {
+					col.max = max
+				}
+			}
+
+			if !hasBounds || chunk.NullCount() > 0 {
+				col.constant = false
+				continue
+			}
+
+			minStr := formatParquetValue(min, col.leaf.Node.Type())
+			maxStr := formatParquetValue(max, col.leaf.Node.Type())
+			if minStr != maxStr || (col.value != "" && col.value != minStr) {
+				col.constant = false
+				continue
+			}
+			col.value = minStr
+		}
+
+		if col.allNull {
+			col.constant = false
+		}
+		if col.min.IsNull() || col.max.IsNull() {
+			col.bounded = false
+		}
+
+		col.dictionary = !col.fromMetadata() && dictionaryEncoded(pf, col.leaf.ColumnIndex)
+	}
+
+	return columns, skipped
+}
+
+// applyParquetStatistics gives a sampled profile what the row-group
+// statistics know of all rows of the file: the nullability of each column
+// from its null count, and the min and max of numeric columns. A full read
+// computes the same figures from the values. Null counts leave out the
+// values read as missing, so nullability is left alone when there are any.
+func applyParquetStatistics(profile *DatasetProfile, columns []*parquetColumn, rows int64, opts Options) {
+	bounded := 0
+	for _, col := range columns {
+		p := profile.Columns[col.name]
+
+		if col.counted && len(opts.NullValues) == 0 &&
+			(p.Nullability == nil || p.Nullability.Kind != NullabilityConditional) {
+			p.Nullability = inferNullability(int(col.nullCount), int(rows), nil, profile.Thresholds)
+		}
+
+		if !col.bounded || !p.IsNumeric {
+			continue
+		}
+		columnType := col.leaf.Node.Type()
+		min, minOK := parquetNumber(col.min, columnType)
+		max, maxOK := parquetNumber(col.max, columnType)
+		if minOK && maxOK {
+			p.Min, p.Max = min, max
+			bounded++
+		}
+	}
+
+	if bounded > 0 {
+		profile.Notes = append(profile.Notes, fmt.Sprintf(
+			"Min and max of %d numeric columns cover all %d rows, from Parquet row-group statistics", bounded, rows))
+	}
+}
+
+// parquetNumber converts a statistics bound of a numeric column. Unsigned
+// integers, which Parquet stores in signed types, and non-finite floats are
+// left to the values.
+func parquetNumber(value parquet.Value, columnType parquet.Type) (float64, bool) {
+	var v float64
+	var scale int32
+	if logical := columnType.LogicalType(); logical != nil {
+		switch {
+		case logical.Integer != nil && !logical.Integer.IsSigned:
+			return 0, false
+		case logical.Decimal != nil:
+			scale = logical.Decimal.Scale
+		case logical.Date != nil || logical.Timestamp != nil:
+			return 0, false
+		}
+	}
+
+	switch value.Kind() {
+	case parquet.Int32:
+		v = float64(value.Int32())
+	case parquet.Int64:
+		v = float64(value.Int64())
+	case parquet.Float:
+		v = float64(value.Float())
+	case parquet.Double:
+		v = value.Double()
+	default:
+		return 0, false
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+
+	return v / math.Pow10(int(scale)), true
+}
+
+// dictionaryEncoded reports whether the column has a dictionary in every row
+// group. Pages that fell back to plain encoding are still handled, just
+// without the shortcut.
+func dictionaryEncoded(pf *parquet.File, columnIndex int) bool {
+	rowGroups := pf.Metadata().RowGroups
+	if len(rowGroups) == 0 {
+		return false
+	}
+
+	for _, rowGroup := range rowGroups {
+		if columnIndex >= len(rowGroup.Columns) {
+			return false
+		}
+
+		found := false
+		for _, encoding := range rowGroup.Columns[columnIndex].MetaData.Encoding {
+			if encoding == format.RLEDictionary || encoding == format.PlainDictionary {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parquetRecordReader assembles row-aligned records by reading the pages of
+// every decoded column in lockstep. Columns answered from metadata are filled
+// in without touching their pages.
+//
+// Dictionary-encoded columns are counted here rather than in profileRecords:
+// occurrences are tallied per dictionary index and only turned into value
+// counts once per row group.
+type parquetRecordReader struct {
+	file     *parquet.File
+	columns  []*parquetColumn
+	rowGroup int
+	rowsLeft int64
+	streams  []*parquetValueStream
+	record   []string
+	counts   map[string]*valueCounter
+}
+
+func newParquetRecordReader(pf *parquet.File, columns []*parquetColumn, countDictionaries bool) *parquetRecordReader {
+	r := &parquetRecordReader{
+		file:     pf,
+		columns:  columns,
+		rowGroup: -1,
+		record:   make([]string, len(columns)),
+		counts:   make(map[string]*valueCounter),
+	}
+
+	for _, col := range columns {
+		if col.dictionary && countDictionaries {
+			r.counts[col.name] = newValueCounter()
+		}
+	}
+
+	return r
+}
+
+func (r *parquetRecordReader) next() ([]string, error) {
+	for {
+		if r.streams != nil {
+			record, err := r.readRecord()
+			if err != io.EOF {
+				return record, err
+			}
+			r.closeStreams()
+		}
+
+		r.rowGroup++
+		if r.rowGroup >= len(r.file.RowGroups()) {
+			return nil, io.EOF
+		}
+		r.openStreams()
+	}
+}
+
+func (r *parquetRecordReader) openStreams() {
+	rowGroup := r.file.RowGroups()[r.rowGroup]
+	chunks := rowGroup.ColumnChunks()
+	r.rowsLeft = rowGroup.NumRows()
+	r.streams = make([]*parquetValueStream, len(r.columns))
+
+	for i, col := range r.columns {
+		if col.fromMetadata() {
+			continue
+		}
+		r.streams[i] = &parquetValueStream{
+			pages:              chunks[col.leaf.ColumnIndex].Pages(),
+			columnType:         col.leaf.Node.Type(),
+			maxDefinitionLevel: byte(col.leaf.MaxDefinitionLevel),
+			counts:             r.counts[col.name],
+			buffer:             make([]parquet.Value, parquetBatchSize),
+		}
+	}
+}
+
+func (r *parquetRecordReader) closeStreams() {
+	for _, stream := range r.streams {
+		if stream != nil {
+			stream.flushCounts()
+			stream.pages.Close()
+		}
+	}
+	r.streams = nil
+}
+
+func (r *parquetRecordReader) readRecord() ([]string, error) {
+	if r.rowsLeft == 0 {
+		return nil, io.EOF
+	}
+	r.rowsLeft--
+
+	for i, col := range r.columns {
+		switch {
+		case col.allNull:
+			r.record[i] = ""
+		case col.constant:
+			r.record[i] = col.value
+		default:
+			value, err := r.streams[i].next()
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			if err != nil {
+				return nil, fmt.Errorf("error reading Parquet column '%s': %w", col.name, err)
+			}
+			r.record[i] = value
+		}
+	}
+
+	return append([]string(nil), r.record...), nil
+}
+
+// parquetValueStream yields the formatted values of one column chunk. Values
+// of dictionary-encoded pages are formatted once per dictionary entry; when
+// counts is set they are also tallied by index instead of by value.
+type parquetValueStream struct {
+	pages              parquet.Pages
+	columnType         parquet.Type
+	maxDefinitionLevel byte
+	counts             *valueCounter
+
+	values parquet.ValueReader
+	buffer []parquet.Value
+	pos    int
+	n      int
+
+	dictionary       parquet.Dictionary
+	entries          []string
+	entryCounts      []int
+	indexes          []int32
+	definitionLevels []byte
+	row              int
+	rows             int
+	index            int
+}
+
+func (s *parquetValueStream) next() (string, error) {
+	for {
+		if s.row < s.rows {
+			return s.nextIndexed(), nil
+		}
+
+		if s.pos < s.n {
+			value := formatParquetValue(s.buffer[s.pos], s.columnType)
+			s.pos++
+			if s.counts != nil && value != "" {
+				s.counts.add(value)
+			}
+			return value, nil
+		}
+
+		if s.values != nil {
+			n, err := s.values.ReadValues(s.buffer)
+			s.pos, s.n = 0, n
+			if err == io.EOF {
+				s.values = nil
+			} else if err != nil {
+				return "", err
+			}
+			continue
+		}
+
+		page, err := s.pages.ReadPage()
+		if err != nil {
+			return "", err
+		}
+
+		if dictionary := page.Dictionary(); dictionary != nil {
+			s.useDictionary(dictionary)
+			data := page.Data()
+			s.indexes = data.Int32()
+			s.definitionLevels = page.DefinitionLevels()
+			s.row, s.rows, s.index = 0, int(page.NumRows()), 0
+		} else {
+			s.values = page.Values()
+		}
+	}
+}
+
+func (s *parquetValueStream) nextIndexed() string {
+	row := s.row
+	s.row++
+
+	if s.definitionLevels != nil && s.definitionLevels[row] < s.maxDefinitionLevel {
+		return ""
+	}
+
+	index := s.indexes[s.index]
+	s.index++
+
+	if s.entryCounts != nil {
+		s.entryCounts[index]++
+	}
+	return s.entries[index]
+}
+
+func (s *parquetValueStream) useDictionary(dictionary parquet.Dictionary) {
+	if dictionary == s.dictionary {
+		return
+	}
+	s.flushCounts()
+
+	s.dictionary = dictionary
+	s.entries = make([]string, dictionary.Len())
+	for i := range s.entries {
+		s.entries[i] = formatParquetValue(dictionary.Index(int32(i)), s.columnType)
+	}
+
+	s.entryCounts = nil
+	if s.counts != nil {
+		s.entryCounts = make([]int, dictionary.Len())
+	}
+}
+
+func (s *parquetValueStream) flushCounts() {
+	for i, count := range s.entryCounts {
+		if count > 0 && s.entries[i] != "" {
+			s.counts.addN(s.entries[i], count)
+		}
+		s.entryCounts[i] = 0
+	}
+}
+
+func formatParquetValue(value parquet.Value, columnType parquet.Type) string {
+	if value.IsNull() {
+		return ""
+	}
+
+	if logical := columnType.LogicalType(); logical != nil {
+		switch {
+		case logical.Date != nil:
+			return time.Unix(int64(value.Int32())*86400, 0).UTC().Format("2006-01-02")
+		case logical.Timestamp != nil:
+			return formatParquetTimestamp(value.Int64(), logical.Timestamp.Unit)
+		case logical.Decimal != nil && value.Kind() == parquet.Int32:
+			return formatParquetDecimal(int64(value.Int32()), logical.Decimal.Scale)
+		case logical.Decimal != nil && value.Kind() == parquet.Int64:
+			return formatParquetDecimal(value.Int64(), logical.Decimal.Scale)
+		}
+	}
+
+	switch value.Kind() {
+	case parquet.Boolean:
+		return strconv.FormatBool(value.Boolean())
+	case parquet.Int32:
+		return strconv.FormatInt(int64(value.Int32()), 10)
+	case parquet.Int64:
+		return strconv.FormatInt(value.Int64(), 10)
+	case parquet.Float:
+		return strconv.FormatFloat(float64(value.Float()), 'g', -1, 32)
+	case parquet.Double:
+		return strconv.FormatFloat(value.Double(), 'g', -1, 64)
+	case parquet.ByteArray, parquet.FixedLenByteArray:
+		return string(value.ByteArray())
+	default:
+		return value.String()
+	}
+}
+
+func formatParquetTimestamp(v int64, unit format.TimeUnit) string {
+	var t time.Time
+	switch {
+	case unit.Millis != nil:
+		t = time.UnixMilli(v)
+	case unit.Nanos != nil:
+		t = time.Unix(0, v)
+	default:
+		t = time.UnixMicro(v)
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func formatParquetDecimal(unscaled int64, scale int32) string {
+	return strconv.FormatFloat(float64(unscaled)/math.Pow10(int(scale)), 'f', int(scale), 64)
+}
@@ -0,0 +1,281 @@
+package profiler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+type parquetTestRow struct {
+	ID      int64   `parquet:"id"`
+	Name    *string `parquet:"name,optional"`
+	Amount  float64 `parquet:"amount"`
+	Country string  `parquet:"country"`
+	Comment *string `parquet:"comment,optional"`
+}
+
+func writeTestParquet(t *testing.T, rows []parquetTestRow) string {
+	path := filepath.Join(t.TempDir(), "test.parquet")
+	if err := parquet.WriteFile(path, rows, parquet.MaxRowsPerRowGroup(40)); err != nil {
+		t.Fatalf("Failed to write Parquet file: %v", err)
+	}
+	return path
+}
+
+func TestProfileParquet(t *testing.T) {
+	rows := make([]parquetTestRow, 0, 100)
+	for i := 0; i < 100; i++ {
+		row := parquetTestRow{
+			ID:      int64(i + 1),
+			Amount:  float64(i) + 0.5,
+			Country: "US",
+		}
+		if i%10 != 0 {
+			name := fmt.Sprintf("user%d", i%7)
+			row.Name = &name
+		}
+		rows = append(rows, row)
+	}
+
+	profile, err := ProfileDataset(writeTestParquet(t, rows))
+	if err != nil {
+		t.Fatalf("ProfileDataset failed: %v", err)
+	}
+
+	if profile.Format != "Parquet" {
+		t.Errorf("Expected format 'Parquet', got '%s'", profile.Format)
+	}
+
+	if profile.RowCount != 100 {
+		t.Errorf("Expected 100 rows, got %d", profile.RowCount)
+	}
+
+	if profile.ColumnCount != 5 {
+		t.Errorf("Expected 5 columns, got %d", profile.ColumnCount)
+	}
+
+	id := profile.Columns["id"]
+	if id.DataType != "integer" || id.Min.(float64) != 1 || id.Max.(float64) != 100 {
+		t.Errorf("Unexpected id column: type=%s min=%v max=%v", id.DataType, id.Min, id.Max)
+	}
+
+	amount := profile.Columns["amount"]
+	if amount.DataType != "float" || amount.Mean != 50 {
+		t.Errorf("Unexpected amount column: type=%s mean=%v", amount.DataType, amount.Mean)
+	}
+
+	name := profile.Columns["name"]
+	if name.MissingCount != 10 || name.UniqueCount != 7 {
+		t.Errorf("Expected name to have 10 missing and 7 unique values, got %d and %d", name.MissingCount, name.UniqueCount)
+	}
+
+	country := profile.Columns["country"]
+	if country.UniqueCount != 1 || country.Count != 100 || country.TopValues[0].Value != "US" {
+		t.Errorf("Unexpected constant column: %+v", country)
+	}
+
+	comment := profile.Columns["comment"]
+	if comment.MissingCount != 100 {
+		t.Errorf("Expected comment to be entirely missing, got %d missing", comment.MissingCount)
+	}
+
+	found := false
+	for _, note := range profile.Notes {
+		if strings.HasPrefix(note, "2 of 5 columns answered from Parquet row-group statistics") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected a note about metadata-answered columns, got %v", profile.Notes)
+	}
+}
+
+func TestProfileParquetSampledStatistics(t *testing.T) {
+	rows := make([]parquetTestRow, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		name := fmt.Sprintf("user%d", i%7)
+		row := parquetTestRow{ID: int64(i + 1), Name: &name, Amount: float64(i) + 0.5, Country: "US"}
+		if i == 999 {
+			row.Name = nil
+		}
+		rows = append(rows, row)
+	}
+
+	profile, err := ProfileDatasetWithOptions(writeTestParquet(t, rows), Options{SampleSize: 50, SampleStrategy: SampleHead})
+	if err != nil {
+		t.Fatalf("ProfileDatasetWithOptions failed: %v", err)
+	}
+	if profile.RowCount != 50 {
+		t.Fatalf("Expected 50 sampled rows, got %d", profile.RowCount)
+	}
+
+	// The first 50 rows run to 50, the statistics to the end of the file
+	id := profile.Columns["id"]
+	if id.Min.(float64) != 1 || id.Max.(float64) != 1000 || id.Mean != 25.5 {
+		t.Errorf("Expected the min and max of all rows and the mean of the sample, got %v %v %v", id.Min, id.Max, id.Mean)
+	}
+	if amount := profile.Columns["amount"]; amount.Max.(float64) != 999.5 {
+		t.Errorf("Expected the max amount of all rows, got %v", amount.Max)
+	}
+
+	// The sample holds no null names, the null count of the file one
+	name := profile.Columns["name"]
+	if name.MissingCount != 0 || name.Nullability.Kind != NullabilityNullable || name.Nullability.Confidence != ConfidenceHigh {
+		t.Errorf("Expected name nullable from the null counts, got %d missing and %+v", name.MissingCount, name.Nullability)
+	}
+	if id.Nullability.Kind != NullabilityNotNull || id.Nullability.Confidence != ConfidenceHigh {
+		t.Errorf("Expected id NOT NULL with high confidence, got %+v", id.Nullability)
+	}
+
+	found := false
+	for _, note := range profile.Notes {
+		if note == "Min and max of 2 numeric columns cover all 1000 rows, from Parquet row-group statistics" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected a note about statistics of all rows, got %v", profile.Notes)
+	}
+}
+
+func TestProfileParquetMatchesCSV(t *testing.T) {
+	rows := []parquetTestRow{
+		{ID: 1, Amount: 1.5, Country: "US"},
+		{ID: 2, Amount: 2.5, Country: "CA"},
+		{ID: 3, Amount: 2.5, Country: "CA"},
+	}
+
+	parquetProfile, err := ProfileParquet(writeTestParquet(t, rows))
+	if err != nil {
+		t.Fatalf("ProfileParquet failed: %v", err)
+	}
+
+	csvPath := filepath.Join(t.TempDir(), "test.csv")
+	csvContent := "id,name,amount,country,comment\n1,,1.5,US,\n2,,2.5,CA,\n3,,2.5,CA,\n"
+	if err := os.WriteFile(csvPath, []byte(csvContent), 0644); err != nil {
+		t.Fatalf("Failed to write CSV file: %v", err)
+	}
+
+	csvProfile, err := ProfileCSV(csvPath)
+	if err != nil {
+		t.Fatalf("ProfileCSV failed: %v", err)
+	}
+
+	if parquetProfile.ContentDigest != csvProfile.ContentDigest {
+		t.Errorf("Expected Parquet and CSV digests to match, got %s and %s",
+			parquetProfile.ContentDigest, csvProfile.ContentDigest)
+	}
+}
+
+type parquetDictRow struct {
+	ID     int64   `parquet:"id"`
+	Region string  `parquet:"region,dict"`
+	Code   *string `parquet:"code,optional,dict"`
+	Score  int64   `parquet:"score,dict"`
+}
+
+type parquetPlainRow struct {
+	ID     int64   `parquet:"id"`
+	Region string  `parquet:"region"`
+	Code   *string `parquet:"code,optional"`
+	Score  int64   `parquet:"score"`
+}
+
+func TestProfileParquetDictionaryMatchesDecoded(t *testing.T) {
+	regions := []string{"north", "south", "east", "west", "central"}
+
+	dictRows := make([]parquetDictRow, 0, 300)
+	plainRows := make([]parquetPlainRow, 0, 300)
+	for i := 0; i < 300; i++ {
+		var code *string
+		if i%9 != 0 {
+			c := fmt.Sprintf("C%d", (i*7)%23)
+			code = &c
+		}
+		region := regions[(i*i)%len(regions)]
+		score := int64((i * 13) % 17)
+
+		dictRows = append(dictRows, parquetDictRow{ID: int64(i), Region: region, Code: code, Score: score})
+		plainRows = append(plainRows, parquetPlainRow{ID: int64(i), Region: region, Code: code, Score: score})
+	}
+
+	dir := t.TempDir()
+	dictPath := filepath.Join(dir, "dict.parquet")
+	plainPath := filepath.Join(dir, "plain.parquet")
+	if err := parquet.WriteFile(dictPath, dictRows, parquet.MaxRowsPerRowGroup(40)); err != nil {
+		t.Fatalf("Failed to write Parquet file: %v", err)
+	}
+	if err := parquet.WriteFile(plainPath, plainRows, parquet.MaxRowsPerRowGroup(40)); err != nil {
+		t.Fatalf("Failed to write Parquet file: %v", err)
+	}
+
+	dictProfile, err := ProfileParquet(dictPath)
+	if err != nil {
+		t.Fatalf("ProfileParquet failed: %v", err)
+	}
+
+	plainProfile, err := ProfileParquet(plainPath)
+	if err != nil {
+		t.Fatalf("ProfileParquet failed: %v", err)
+	}
+
+	found := false
+	for _, note := range dictProfile.Notes {
+		if strings.HasPrefix(note, "Unique counts and top values of 3 dictionary-encoded columns") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected a note about dictionary-encoded columns, got %v", dictProfile.Notes)
+	}
+
+	for _, note := range plainProfile.Notes {
+		if strings.Contains(note, "dictionary") {
+			t.Errorf("Did not expect plain file to use dictionary path, got note %q", note)
+		}
+	}
+
+	if dictProfile.ContentDigest != plainProfile.ContentDigest || dictProfile.DuplicateRows != plainProfile.DuplicateRows {
+		t.Errorf("Expected identical row-level results, got digests %s and %s",
+			dictProfile.ContentDigest, plainProfile.ContentDigest)
+	}
+
+	for name, expected := range plainProfile.Columns {
+		actual := dictProfile.Columns[name]
+
+		if actual.DataType != expected.DataType || actual.Count != expected.Count ||
+			actual.MissingCount != expected.MissingCount || actual.UniqueCount != expected.UniqueCount {
+			t.Errorf("Column %s: expected type=%s count=%d missing=%d unique=%d, got type=%s count=%d missing=%d unique=%d",
+				name, expected.DataType, expected.Count, expected.MissingCount, expected.UniqueCount,
+				actual.DataType, actual.Count, actual.MissingCount, actual.UniqueCount)
+		}
+
+		if actual.Digest != expected.Digest {
+			t.Errorf("Column %s: expected digest %s, got %s", name, expected.Digest, actual.Digest)
+		}
+
+		if len(actual.TopValues) != len(expected.TopValues) {
+			t.Fatalf("Column %s: expected %d top values, got %d", name, len(expected.TopValues), len(actual.TopValues))
+		}
+		for i := range expected.TopValues {
+			if actual.TopValues[i].Count != expected.TopValues[i].Count {
+				t.Errorf("Column %s: expected top value counts %v, got %v", name, expected.TopValues, actual.TopValues)
+				break
+			}
+		}
+
+		if expected.IsNumeric {
+			if actual.Min != expected.Min || actual.Max != expected.Max || actual.Median != expected.Median {
+				t.Errorf("Column %s: expected min=%v max=%v median=%v, got min=%v max=%v median=%v",
+					name, expected.Min, expected.Max, expected.Median, actual.Min, actual.Max, actual.Median)
+			}
+			if diff := actual.Mean - expected.Mean; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Column %s: expected mean %v, got %v", name, expected.Mean, actual.Mean)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package profiler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseErrors counts the values of a column that did not read cleanly.
+type ParseErrors struct {
+	Unparseable int // values of an integer, float or datetime column that do not parse as its type
+	NonFinite   int // NaN and infinite numbers, left out of the numeric statistics
+	Encoding    int // values with invalid UTF-8 or replacement characters
+}
+
+// Total is the number of values with a parse error.
+func (e ParseErrors) Total() int {
+	return e.Unparseable + e.NonFinite + e.Encoding
+}
+
+// ParseBudget is the parse error budget of a profile: every problem met
+// reading the source, summed up as one number to track ingestion health
+// across runs, with the columns behind it.
+type ParseBudget struct {
+	BadRows     int
+	Unparseable int
+	NonFinite   int
+	Encoding    int
+	Total       int                    // bad rows plus values with a parse error
+	Percent     float64                // share of the cells read with a parse error, a bad row counting all of its cells
+	Columns     map[string]ParseErrors // columns with parse errors
+}
+
+// ParseBudget sums up the parse errors of the profile.
+func (p *DatasetProfile) ParseBudget() ParseBudget {
+	budget := ParseBudget{BadRows: p.BadRows, Columns: make(map[string]ParseErrors)}
+	for name, col := range p.Columns {
+		if col.ParseErrors.Total() == 0 {
+			continue
+		}
+		budget.Columns[name] = col.ParseErrors
+		budget.Unparseable += col.ParseErrors.Unparseable
+		budget.NonFinite += col.ParseErrors.NonFinite
+		budget.Encoding += col.ParseErrors.Encoding
+	}
+
+	values := budget.Unparseable + budget.NonFinite + budget.Encoding
+	budget.Total = budget.BadRows + values
+	if cells := (p.RowCount + p.BadRows) * p.ColumnCount; cells > 0 {
+		budget.Percent = min(float64(budget.BadRows*p.ColumnCount+values)/float64(cells)*100, 100)
+	}
+	return budget
+}
+
+// parseErrors counts the values of col that did not read cleanly.
+func (a *columnAccumulator) parseErrors(col *ColumnProfile) ParseErrors {
+	errs := ParseErrors{Encoding: a.encoding}
+	switch {
+	case col.IsNumeric && a.numeric != nil:
+		errs.NonFinite = a.numeric.nonFinite
+		errs.Unparseable = max(col.Count-a.numeric.count-a.numeric.nonFinite, 0)
+	case col.IsDateTime && a.dates != nil:
+		errs.Unparseable = max(col.Count-a.dates.seconds.count, 0)
+	}
+	return errs
+}
+
+// badEncoding reports whether value holds invalid UTF-8 or the replacement
+// character an earlier decoding left in place of one it could not read.
+func badEncoding(value string) bool {
+	return strings.ContainsRune(value, utf8.RuneError)
+}
+
+// badRowsNote notes the rows skipped for not parsing.
+func badRowsNote(rows int) string {
+	return fmt.Sprintf("Skipped %d rows that do not parse (--skip-bad-rows)", rows)
+}
+
+// badRow reports whether err is a record the CSV reader could not parse,
+// such as one with the wrong number of fields, which SkipBadRows skips.
+func badRow(err error) bool {
+	var parseErr *csv.ParseError
+	return errors.As(err, &parseErr)
+}
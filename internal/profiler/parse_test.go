@@ -0,0 +1,132 @@
+package profiler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseErrors(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("id,amount,day,note\n")
+	for i := 0; i < 40; i++ {
+		amount, day, note := fmt.Sprintf("%.1f", float64(i)*1.5), fmt.Sprintf("2024-01-%02d", i%28+1), fmt.Sprintf("n%d", i)
+		switch i {
+		case 3:
+			amount = "NaN"
+		case 4:
+			amount = "-Inf"
+		case 5:
+			amount = "abc"
+		case 6:
+			day = "someday"
+		case 7:
+			note = "caf�"
+		}
+		fmt.Fprintf(&b, "%d,%s,%s,%s\n", i, amount, day, note)
+	}
+
+	// Non-finite numbers used to reach the histogram
+	profile, err := ProfileDatasetWithOptions(writeTestFile(t, "data.csv", b.String()), Options{ExactRows: 1000})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+
+	amount := profile.Columns["amount"]
+	if amount.DataType != "float" || amount.ParseErrors != (ParseErrors{Unparseable: 1, NonFinite: 2}) {
+		t.Errorf("Expected a float column with 1 unparseable and 2 non-finite values, got %s with %+v", amount.DataType, amount.ParseErrors)
+	}
+	if amount.Min != 0.0 || amount.Max != 58.5 {
+		t.Errorf("Expected non-finite values left out of the range, got %v to %v", amount.Min, amount.Max)
+	}
+	if day := profile.Columns["day"]; day.ParseErrors != (ParseErrors{Unparseable: 1}) {
+		t.Errorf("Expected 1 unparseable date, got %+v", day.ParseErrors)
+	}
+	if note := profile.Columns["note"]; note.ParseErrors != (ParseErrors{Encoding: 1}) {
+		t.Errorf("Expected 1 encoding error, got %+v", note.ParseErrors)
+	}
+	if id := profile.Columns["id"]; id.ParseErrors.Total() != 0 {
+		t.Errorf("Expected no parse errors in id, got %+v", id.ParseErrors)
+	}
+
+	budget := profile.ParseBudget()
+	if budget.Total != 5 || budget.Unparseable != 2 || budget.NonFinite != 2 || budget.Encoding != 1 || len(budget.Columns) != 3 {
+		t.Errorf("Expected 5 parse errors in 3 columns, got %+v", budget)
+	}
+	if budget.Percent != 5.0/160*100 {
+		t.Errorf("Expected 5 of 160 cells, got %.2f%%", budget.Percent)
+	}
+}
+
+func TestSkipBadRows(t *testing.T) {
+	path := writeTestFile(t, "data.csv", "id,name\n1,a\n2,b,extra\n3,c\n4\n5,e\n")
+
+	if _, err := ProfileDataset(path); err == nil || !strings.Contains(err.Error(), "--skip-bad-rows") {
+		t.Errorf("Expected bad rows to fail the profile with a hint, got %v", err)
+	}
+
+	profile, err := ProfileDatasetWithOptions(path, Options{SkipBadRows: true})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if profile.RowCount != 3 || profile.BadRows != 2 {
+		t.Errorf("Expected 3 rows and 2 bad rows, got %d and %d", profile.RowCount, profile.BadRows)
+	}
+	if !strings.Contains(strings.Join(profile.Notes, " "), "Skipped 2 rows that do not parse") {
+		t.Errorf("Expected a note about the bad rows, got %v", profile.Notes)
+	}
+
+	budget := profile.ParseBudget()
+	if budget.Total != 2 || budget.Percent != 40 {
+		t.Errorf("Expected 2 bad rows of 5, got %+v", budget)
+	}
+}
+
+func TestSkipBadRowsParallel(t *testing.T) {
+	withParallelMinChunk(t, 256)
+
+	var b strings.Builder
+	b.WriteString("id,name\n")
+	for i := 0; i < 2000; i++ {
+		fmt.Fprintf(&b, "%d,name%d\n", i, i%13)
+		if i%500 == 250 {
+			b.WriteString("broken\n")
+		}
+	}
+	path := writeTestFile(t, "data.csv", b.String())
+
+	want, err := ProfileDatasetWithOptions(path, Options{SkipBadRows: true})
+	if err != nil {
+		t.Fatalf("Failed to profile sequentially: %v", err)
+	}
+	got, err := ProfileDatasetWithOptions(path, Options{SkipBadRows: true, Parallel: 4})
+	if err != nil {
+		t.Fatalf("Failed to profile in parallel: %v", err)
+	}
+
+	if !strings.Contains(strings.Join(got.Notes, " "), "concurrently") {
+		t.Fatalf("Expected a parallel profile, got %v", got.Notes)
+	}
+	if got.RowCount != 2000 || got.BadRows != 4 || !reflect.DeepEqual(got.ParseBudget(), want.ParseBudget()) {
+		t.Errorf("Expected 2000 rows and 4 bad rows as read sequentially, got %d and %d", got.RowCount, got.BadRows)
+	}
+}
+
+func TestSkipBadRowsJSONL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	content := "{\"id\": 1}\nnot json\n{\"id\": 2}\n[1, 2]\n{\"id\": 3\n\n{\"id\": 4}\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	profile, err := ProfileDatasetWithOptions(path, Options{SkipBadRows: true})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if profile.RowCount != 3 || profile.BadRows != 3 {
+		t.Errorf("Expected 3 rows and 3 bad lines, got %d and %d", profile.RowCount, profile.BadRows)
+	}
+}
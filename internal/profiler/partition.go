@@ -0,0 +1,575 @@
+package profiler
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+const FormatHive = "hive"
+
+// hiveDefaultPartition is the directory value Hive and Spark write for a
+// null partition key.
+const hiveDefaultPartition = "__HIVE_DEFAULT_PARTITION__"
+
+const (
+	// partitionRowRatio flags partitions with this many times fewer, or
+	// more, rows than the median partition.
+	partitionRowRatio = 10
+	// partitionMissingExcess flags partitions whose missing cell rate is
+	// this many percentage points above that of the whole dataset.
+	partitionMissingExcess = 10
+)
+
+// PartitionProfile is the row count and completeness of one partition of a
+// Hive-partitioned dataset. Issue explains what sets the partition apart
+// from the others, and is empty for a partition that looks like the rest.
+type PartitionProfile struct {
+	Name         string // directory relative to the dataset, e.g. dt=2024-01-01/region=eu
+	Files        int
+	Size         int64
+	RowCount     int
+	MissingCells int
+	Issue        string
+}
+
+// MissingPercent is the share of missing cells among the cells of the
+// partition, given the column count of the dataset.
+func (p PartitionProfile) MissingPercent(columns int) float64 {
+	if p.RowCount == 0 || columns == 0 {
+		return 0
+	}
+	return float64(p.MissingCells) / float64(p.RowCount*columns) * 100
+}
+
+// isPartitioned reports whether dir is a local directory laid out in
+// Hive-style partitions, with subdirectories named key=value.
+func isPartitioned(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && isPartitionDir(entry.Name()) {
+			return true
+		}
+	}
+	return false
+}
+
+func isPartitionDir(name string) bool {
+	if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+		return false
+	}
+	key, _, ok := strings.Cut(name, "=")
+	return ok && key != ""
+}
+
+// hivePartition is one leaf directory of a partitioned dataset and the data
+// files in it.
+type hivePartition struct {
+	name   string
+	values []string
+	files  []string
+	size   int64
+}
+
+// partitionFileFormat returns the format of a data file in a partition, ""
+// for a file that is not data, or an error for data the partitions cannot
+// hold.
+func partitionFileFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(trimCompressionExt(path))) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".tsv", ".tab":
+		return FormatTSV, nil
+	case ".parquet":
+		if codec := compressionExt(path); codec != "" {
+			return "", fmt.Errorf("%s: %s-compressed Parquet files are not supported", path, codec)
+		}
+		return "parquet", nil
+	}
+	if IsDataFile(path) {
+		return "", fmt.Errorf("%s: partitions of CSV, TSV or Parquet files are supported", path)
+	}
+	return "", nil
+}
+
+// scanPartitions lists the partitions of a Hive-partitioned directory by
+// name, with the partition keys and the format of their files, which must
+// all be the same. Hidden files and files starting with _, such as _SUCCESS
+// markers, are left out; a partition directory without data files is a
+// partition with no rows.
+func scanPartitions(root string) ([]string, []*hivePartition, string, error) {
+	var keys []string
+	format := ""
+	byName := make(map[string]*hivePartition)
+	var dirs []string
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, _ := filepath.Rel(root, path)
+		if entry.IsDir() {
+			if !isPartitionDir(name) {
+				return fmt.Errorf("%s is not a key=value partition directory", rel)
+			}
+			dirs = append(dirs, filepath.ToSlash(rel))
+			return nil
+		}
+
+		fileFormat, err := partitionFileFormat(rel)
+		if err != nil || fileFormat == "" {
+			return err
+		}
+		if format != "" && fileFormat != format {
+			return fmt.Errorf("partition files have different formats (%s and %s): %s", format, fileFormat, rel)
+		}
+		format = fileFormat
+
+		dir := filepath.ToSlash(filepath.Dir(rel))
+		if dir == "." {
+			return fmt.Errorf("data file %s is not inside a partition directory", rel)
+		}
+		partKeys, values, err := parsePartition(dir)
+		if err != nil {
+			return err
+		}
+		if keys == nil {
+			keys = partKeys
+		} else if strings.Join(keys, "/") != strings.Join(partKeys, "/") {
+			return fmt.Errorf("partition directories disagree on the keys: %s and %s", strings.Join(keys, "/"), strings.Join(partKeys, "/"))
+		}
+
+		part := byName[dir]
+		if part == nil {
+			part = &hivePartition{name: dir, values: values}
+			byName[dir] = part
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("failed to get file stats: %w", err)
+		}
+		part.files = append(part.files, path)
+		part.size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to read partitioned directory: %w", err)
+	}
+	if format == "" {
+		return nil, nil, "", fmt.Errorf("no data files in partitioned directory %s", root)
+	}
+
+	for _, dir := range dirs {
+		partKeys, values, _ := parsePartition(dir)
+		if byName[dir] == nil && len(partKeys) == len(keys) {
+			byName[dir] = &hivePartition{name: dir, values: values}
+		}
+	}
+
+	partitions := make([]*hivePartition, 0, len(byName))
+	for _, part := range byName {
+		partitions = append(partitions, part)
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].name < partitions[j].name })
+
+	return keys, partitions, format, nil
+}
+
+// parsePartition splits a partition path such as dt=2024-01-01/region=eu
+// into its keys and values. Values are unescaped, and the Hive default
+// partition stands for a missing value.
+func parsePartition(dir string) ([]string, []string, error) {
+	segments := strings.Split(dir, "/")
+	keys := make([]string, len(segments))
+	values := make([]string, len(segments))
+	for i, segment := range segments {
+		if !isPartitionDir(segment) {
+			return nil, nil, fmt.Errorf("%s is not a key=value partition directory", dir)
+		}
+		key, value, _ := strings.Cut(segment, "=")
+		if unescaped, err := url.PathUnescape(value); err == nil {
+			value = unescaped
+		}
+		if value == hiveDefaultPartition {
+			value = ""
+		}
+		keys[i], values[i] = key, value
+	}
+	return keys, values, nil
+}
+
+// profilePartitioned profiles a Hive-partitioned directory as one dataset:
+// the files of every partition are read one after another, their columns
+// lined up by name with those of the first file, and the partition keys are
+// added as columns. Rows and missing cells are counted per partition, and
+// partitions that stand out from the rest are reported as quality issues.
+func profilePartitioned(source string, opts Options) (*DatasetProfile, error) {
+	startTime := time.Now()
+
+	if opts.MaxBytes > 0 {
+		return nil, fmt.Errorf("--range is not supported for partitioned directories")
+	}
+
+	keys, partitions, format, err := scanPartitions(source)
+	if err != nil {
+		return nil, err
+	}
+	if format == "parquet" {
+		if err := opts.textOnly("Parquet partitions"); err != nil {
+			return nil, err
+		}
+	}
+
+	reader := &partitionReader{partitions: partitions, format: format, opts: opts, part: -1}
+	defer reader.close()
+	if err := reader.start(keys); err != nil {
+		return nil, err
+	}
+
+	var size int64
+	for _, part := range partitions {
+		size += part.size
+	}
+
+	formatName := map[string]string{FormatCSV: "CSV", FormatTSV: "TSV", "parquet": "Parquet"}[format]
+	profile := newDatasetProfile(filepath.Base(filepath.Clean(source)), size, "Hive-partitioned "+formatName, reader.header)
+
+	if err := profileRows(profile, reader.header, reader.next, opts); err != nil {
+		return nil, err
+	}
+
+	profile.Partitions = reader.stats
+	partial := opts.sampling() && opts.SampleStrategy == SampleHead
+	if !partial && !profile.Interrupted {
+		flagPartitions(profile)
+	}
+
+	profile.Notes = append(profile.Notes, fmt.Sprintf("Merged %d files from %d partitions; partition keys added as columns: %s",
+		reader.files, len(partitions), strings.Join(keys, ", ")))
+	if partial {
+		profile.Notes = append(profile.Notes, "Partition row counts cover only the rows read for the head sample")
+	}
+	profile.Notes = append(profile.Notes, reader.notes()...)
+	if reader.badRows > 0 {
+		profile.BadRows = reader.badRows
+		profile.Notes = append(profile.Notes, badRowsNote(reader.badRows))
+	}
+
+	profile.QualityScore = CalculateQualityScore(profile)
+	profile.ProcessingTime = time.Since(startTime)
+
+	return profile, nil
+}
+
+// flagPartitions sets the Issue of partitions with no rows, with far fewer
+// or far more rows than the median partition, or with many more missing
+// cells than the dataset as a whole, and adds each as a quality issue.
+func flagPartitions(profile *DatasetProfile) {
+	counts := make([]int, len(profile.Partitions))
+	for i, part := range profile.Partitions {
+		counts[i] = part.RowCount
+	}
+	sort.Ints(counts)
+	median := counts[len(counts)/2]
+
+	missing := 0.0
+	if profile.RowCount > 0 && profile.ColumnCount > 0 {
+		missing = float64(profile.MissingCells) / float64(profile.RowCount*profile.ColumnCount) * 100
+	}
+
+	for i := range profile.Partitions {
+		part := &profile.Partitions[i]
+		severity := 2
+		partMissing := part.MissingPercent(profile.ColumnCount)
+		switch {
+		case part.RowCount == 0:
+			part.Issue = "no rows"
+		case len(counts) >= 3 && part.RowCount*partitionRowRatio <= median:
+			part.Issue = fmt.Sprintf("%d rows against a median of %d", part.RowCount, median)
+		case len(counts) >= 3 && part.RowCount >= median*partitionRowRatio:
+			part.Issue = fmt.Sprintf("%d rows against a median of %d", part.RowCount, median)
+			severity = 1
+		case partMissing >= missing+partitionMissingExcess:
+			part.Issue = fmt.Sprintf("%.1f%% of cells missing against %.1f%% overall", partMissing, missing)
+		default:
+			continue
+		}
+
+		profile.QualityIssues = append(profile.QualityIssues, QualityIssue{
+			Type:        "partition_outlier",
+			Description: fmt.Sprintf("Partition %s: %s", part.Name, part.Issue),
+			Severity:    severity,
+		})
+	}
+}
+
+// partitionFile is an open data file of a partition.
+type partitionFile struct {
+	header []string
+	next   func() ([]string, error)
+	close  func()
+}
+
+// partitionReader reads the files of every partition in turn, lining their
+// columns up by name with the header of the first file followed by the
+// partition keys, and counts rows and missing cells per partition.
+type partitionReader struct {
+	partitions []*hivePartition
+	format     string
+	opts       Options
+	header     []string
+	index      map[string]int
+	keyIndex   []int // header column of each partition key
+	stats      []PartitionProfile
+	part, file int
+	files      int
+	current    *partitionFile
+	mapping    []int
+	fromFile   map[int]bool
+	extra      map[string]int // columns not in the first file, by files
+	absent     map[string]int // header columns a file lacks, by files
+	skipped    map[string]bool
+	badRows    int // rows of delimited files skipped for not parsing
+}
+
+// start opens the first data file and builds the header from its columns
+// and the partition keys it does not hold itself.
+func (r *partitionReader) start(keys []string) error {
+	r.stats = make([]PartitionProfile, len(r.partitions))
+	for i, part := range r.partitions {
+		r.stats[i] = PartitionProfile{Name: part.name, Files: len(part.files), Size: part.size}
+	}
+	r.extra = make(map[string]int)
+	r.absent = make(map[string]int)
+	r.skipped = make(map[string]bool)
+
+	for i, part := range r.partitions {
+		if len(part.files) == 0 {
+			continue
+		}
+		first, err := r.open(part.files[0])
+		if err != nil {
+			return err
+		}
+		r.header = append([]string{}, first.header...)
+		r.index = make(map[string]int)
+		for j, col := range r.header {
+			r.index[col] = j
+		}
+		for _, key := range keys {
+			if _, ok := r.index[key]; !ok {
+				r.index[key] = len(r.header)
+				r.header = append(r.header, key)
+			}
+		}
+		r.keyIndex = make([]int, len(keys))
+		for j, key := range keys {
+			r.keyIndex[j] = r.index[key]
+		}
+
+		r.part, r.file = i, 0
+		r.use(first)
+		return nil
+	}
+	return fmt.Errorf("no data files in partitioned directory")
+}
+
+func (r *partitionReader) next() ([]string, error) {
+	for {
+		if r.current != nil {
+			record, err := r.current.next()
+			if err == nil {
+				return r.align(record), nil
+			}
+			if err != io.EOF {
+				return nil, fmt.Errorf("%s: %w", r.partitions[r.part].files[r.file], err)
+			}
+			r.closeFile()
+			r.file++
+		}
+
+		for r.part < len(r.partitions) && r.file >= len(r.partitions[r.part].files) {
+			r.part++
+			r.file = 0
+		}
+		if r.part >= len(r.partitions) {
+			return nil, io.EOF
+		}
+
+		file, err := r.open(r.partitions[r.part].files[r.file])
+		if err != nil {
+			return nil, err
+		}
+		r.use(file)
+	}
+}
+
+// use makes file the current file and maps its columns to the header.
+func (r *partitionReader) use(file *partitionFile) {
+	r.current = file
+	r.files++
+	r.mapping = make([]int, len(file.header))
+	r.fromFile = make(map[int]bool, len(file.header))
+	for i, col := range file.header {
+		j, ok := r.index[col]
+		if !ok {
+			r.mapping[i] = -1
+			r.extra[col]++
+			continue
+		}
+		r.mapping[i] = j
+		r.fromFile[j] = true
+	}
+	for j, col := range r.header {
+		if !r.fromFile[j] && !r.isKey(j) {
+			r.absent[col]++
+		}
+	}
+}
+
+func (r *partitionReader) isKey(column int) bool {
+	for _, j := range r.keyIndex {
+		if j == column {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *partitionReader) align(record []string) []string {
+	aligned := make([]string, len(r.header))
+	for i, value := range record {
+		if i < len(r.mapping) && r.mapping[i] >= 0 {
+			aligned[r.mapping[i]] = value
+		}
+	}
+	values := r.partitions[r.part].values
+	for k, j := range r.keyIndex {
+		if !r.fromFile[j] {
+			aligned[j] = values[k]
+		}
+	}
+
+	stats := &r.stats[r.part]
+	stats.RowCount++
+	for _, value := range aligned {
+		if value == "" {
+			stats.MissingCells++
+		}
+	}
+	return aligned
+}
+
+// open opens a data file of a partition with the reader for its format.
+func (r *partitionReader) open(path string) (*partitionFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	if r.format != "parquet" {
+		comma, format := rune(0), "CSV"
+		if r.format == FormatTSV {
+			comma, format = '\t', "TSV"
+		}
+		reader, err := newDelimitedReader(file, filepath.Base(path), comma, format, r.opts)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return &partitionFile{header: reader.header, next: reader.next, close: func() {
+			r.badRows += reader.badRows
+			reader.close()
+			file.Close()
+		}}, nil
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to get file stats: %w", err)
+	}
+	pf, err := parquet.OpenFile(file, info.Size())
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read Parquet file %s: %w", path, err)
+	}
+	columns, skipped := parquetColumns(pf)
+	for _, col := range skipped {
+		r.skipped[col] = true
+	}
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.name
+	}
+	reader := newParquetRecordReader(pf, columns, false)
+	return &partitionFile{header: header, next: reader.next, close: func() {
+		reader.closeStreams()
+		file.Close()
+	}}, nil
+}
+
+func (r *partitionReader) closeFile() {
+	if r.current != nil {
+		r.current.close()
+		r.current = nil
+	}
+}
+
+func (r *partitionReader) close() {
+	r.closeFile()
+}
+
+// notes describes the columns that did not line up across files.
+func (r *partitionReader) notes() []string {
+	var notes []string
+	describe := func(counts map[string]int) string {
+		names := make([]string, 0, len(counts))
+		for name := range counts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for i, name := range names {
+			names[i] = fmt.Sprintf("%s (%d files)", name, counts[name])
+		}
+		return strings.Join(names, ", ")
+	}
+
+	if len(r.extra) > 0 {
+		notes = append(notes, "Columns not in the first file were not profiled: "+describe(r.extra))
+	}
+	if len(r.absent) > 0 {
+		notes = append(notes, "Missing columns counted as empty: "+describe(r.absent))
+	}
+	if len(r.skipped) > 0 {
+		names := make([]string, 0, len(r.skipped))
+		for name := range r.skipped {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		notes = append(notes, fmt.Sprintf("Skipped nested or repeated columns: %s", strings.Join(names, ", ")))
+	}
+	return notes
+}
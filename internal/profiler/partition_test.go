@@ -0,0 +1,133 @@
+package profiler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePartitionFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+}
+
+func partitionRows(first, count int, email string) string {
+	var b strings.Builder
+	b.WriteString("id,email\n")
+	for i := first; i < first+count; i++ {
+		fmt.Fprintf(&b, "%d,%s\n", i, email)
+	}
+	return b.String()
+}
+
+func TestProfilePartitionedCSV(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "events")
+	writePartitionFile(t, root, "dt=2024-01-01/region=eu/part-0.csv", partitionRows(0, 50, "a@x"))
+	writePartitionFile(t, root, "dt=2024-01-01/region=us/part-0.csv", partitionRows(50, 30, "b@x"))
+	writePartitionFile(t, root, "dt=2024-01-01/region=us/part-1.csv", partitionRows(80, 20, "c@x"))
+	writePartitionFile(t, root, "dt=2024-01-02/region=eu/part-0.csv", partitionRows(100, 40, ""))
+	writePartitionFile(t, root, "dt=2024-01-02/region=us/part-0.csv", partitionRows(140, 2, "d@x"))
+	writePartitionFile(t, root, "dt=2024-01-02/region=__HIVE_DEFAULT_PARTITION__/part-0.csv", partitionRows(142, 45, "e@x"))
+	writePartitionFile(t, root, "dt=2024-01-03/region=eu/_SUCCESS", "")
+	writePartitionFile(t, root, "_SUCCESS", "")
+
+	if format := tableFormat(root, Options{}); format != FormatHive {
+		t.Fatalf("Expected a Hive-partitioned directory, got %q", format)
+	}
+
+	profile, err := ProfileDatasetWithOptions(root, Options{})
+	if err != nil {
+		t.Fatalf("Failed to profile partitioned directory: %v", err)
+	}
+
+	if profile.Filename != "events" || profile.RowCount != 187 || profile.ColumnCount != 4 {
+		t.Errorf("Expected 187 rows and 4 columns of events, got %s: %d rows, %d columns", profile.Filename, profile.RowCount, profile.ColumnCount)
+	}
+	if col := profile.Columns["dt"]; col == nil || col.UniqueCount != 2 || col.MissingCount != 0 {
+		t.Errorf("Expected dt as a column with 2 values, got %+v", col)
+	}
+	if col := profile.Columns["region"]; col == nil || col.MissingCount != 45 {
+		t.Errorf("Expected the default partition as missing regions, got %+v", col)
+	}
+
+	if len(profile.Partitions) != 6 {
+		t.Fatalf("Expected 6 partitions, got %+v", profile.Partitions)
+	}
+	byName := make(map[string]PartitionProfile)
+	for _, part := range profile.Partitions {
+		byName[part.Name] = part
+	}
+	if part := byName["dt=2024-01-01/region=us"]; part.Files != 2 || part.RowCount != 50 || part.Issue != "" {
+		t.Errorf("Unexpected partition: %+v", part)
+	}
+	if part := byName["dt=2024-01-03/region=eu"]; part.RowCount != 0 || part.Issue != "no rows" {
+		t.Errorf("Expected the partition without files to have no rows, got %+v", part)
+	}
+	if part := byName["dt=2024-01-02/region=us"]; !strings.Contains(part.Issue, "2 rows against a median of") {
+		t.Errorf("Expected the small partition to be flagged, got %+v", part)
+	}
+	if part := byName["dt=2024-01-02/region=eu"]; part.MissingCells != 40 || !strings.Contains(part.Issue, "missing") {
+		t.Errorf("Expected the partition without emails to be flagged, got %+v", part)
+	}
+
+	flagged := 0
+	for _, issue := range profile.QualityIssues {
+		if issue.Type == "partition_outlier" {
+			flagged++
+		}
+	}
+	if flagged != 4 {
+		t.Errorf("Expected 4 partition issues, got %+v", profile.QualityIssues)
+	}
+}
+
+func TestProfilePartitionedParquet(t *testing.T) {
+	root := t.TempDir()
+	writeTableTestFile(t, filepath.Join(root, "country=de", "part-0.parquet"), 0, 10)
+	writeTableTestFile(t, filepath.Join(root, "country=fr", "part-0.parquet"), 10, 5)
+
+	profile, err := ProfileDatasetWithOptions(root, Options{})
+	if err != nil {
+		t.Fatalf("Failed to profile partitioned directory: %v", err)
+	}
+	if profile.RowCount != 15 || profile.Columns["country"] == nil || profile.Columns["amount"] == nil {
+		t.Errorf("Expected 15 rows with a country column, got %d rows: %v", profile.RowCount, profile.Columns)
+	}
+	if profile.Format != "Hive-partitioned Parquet" || profile.Partitions[1].RowCount != 5 {
+		t.Errorf("Unexpected format or partitions: %s %+v", profile.Format, profile.Partitions)
+	}
+}
+
+func TestProfilePartitionedErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"mixed formats", map[string]string{"k=1/a.csv": "id\n1\n", "k=2/a.parquet": ""}, "different formats"},
+		{"mixed keys", map[string]string{"k=1/a.csv": "id\n1\n", "k=2/j=3/a.csv": "id\n1\n"}, "disagree on the keys"},
+		{"file outside partitions", map[string]string{"k=1/a.csv": "id\n1\n", "b.csv": "id\n1\n"}, "not inside a partition"},
+		{"plain directory", map[string]string{"k=1/a.csv": "id\n1\n", "raw/a.csv": "id\n1\n"}, "not a key=value"},
+		{"JSON Lines", map[string]string{"k=1/a.jsonl": "{}\n"}, "CSV, TSV or Parquet"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for rel, content := range tt.files {
+				writePartitionFile(t, root, rel, content)
+			}
+			_, err := ProfileDatasetWithOptions(root, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
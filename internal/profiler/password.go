@@ -0,0 +1,38 @@
+package profiler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+)
+
+// PasswordEnv is the environment variable read for the password of
+// protected workbooks and archives when --password is not given.
+const PasswordEnv = "DATASLEUTH_PASSWORD"
+
+// Errors for protected sources, to be matched with errors.Is.
+var (
+	ErrPasswordRequired      = errors.New("file is password-protected: pass --password or set " + PasswordEnv)
+	ErrWrongPassword         = errors.New("incorrect password")
+	ErrUnsupportedEncryption = errors.New("unsupported encryption method")
+	ErrIntegrityCheck        = errors.New("decrypted data failed its integrity check")
+)
+
+// oleMagic starts a Compound File; an .xlsx in that container instead of a
+// zip package is an encrypted workbook.
+var oleMagic = []byte{0xd0, 0xcf, 0x11, 0xe0, 0xa1, 0xb1, 0x1a, 0xe1}
+
+func isOLEFile(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	header := make([]byte, len(oleMagic))
+	if _, err := io.ReadFull(file, header); err != nil {
+		return false
+	}
+	return bytes.Equal(header, oleMagic)
+}
@@ -0,0 +1,415 @@
+package profiler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/kamalm96/datasleuth/internal/remote"
+	"github.com/parquet-go/parquet-go"
+)
+
+// planSniffBytes is how much of a local text file is read to estimate its
+// row count from the length of its first lines.
+const planSniffBytes = 64 << 10
+
+// Plan is what profiling a source with a set of options will do, resolved
+// without profiling it: the kind and format of the source, the algorithms
+// the options choose and an estimate of the work. Sizes and row counts are
+// -1 when they cannot be known up front.
+type Plan struct {
+	Source      string         `json:"source"`
+	Kind        string         `json:"kind"`   // file, stdin, remote, sqlite, database, excel, archive or table
+	Format      string         `json:"format"` // csv, tsv, jsonl, xml, parquet, avro, orc, json, sqlite, postgres, snowflake, excel, archive, delta, iceberg or hive
+	Compression string         `json:"compression,omitempty"`
+	Tables      []string       `json:"tables,omitempty"` // tables or sheets profiled one by one
+	Algorithms  PlanAlgorithms `json:"algorithms"`
+	Cost        PlanCost       `json:"cost"`
+	Warnings    []string       `json:"warnings,omitempty"`
+}
+
+// PlanAlgorithms are the algorithms and thresholds the options choose.
+type PlanAlgorithms struct {
+	Engine           string   `json:"engine"`  // go or duckdb
+	Reading          string   `json:"reading"` // sequential, parallel or remote
+	Workers          int      `json:"workers,omitempty"`
+	Sampling         string   `json:"sampling"` // none, head, random or systematic
+	SampleRows       int      `json:"sample_rows,omitempty"`
+	ExactBelowRows   int      `json:"exact_below_rows"` // exact mode with complete listings below this many rows, 0 for never
+	DistinctCounts   string   `json:"distinct_counts"`  // exact counts up to a limit, then HyperLogLog
+	Percentiles      string   `json:"percentiles"`      // exact up to a limit, then a t-digest
+	Histogram        string   `json:"histogram"`        // equal-width, equal-frequency, log or auto
+	HistogramBuckets int      `json:"histogram_buckets"`
+	TopValues        int      `json:"top_values"`           // most and least frequent values listed per column
+	Duplicates       string   `json:"duplicates"`           // rows or key
+	UniqueKey        []string `json:"unique_key,omitempty"` // columns of the key
+	Correlations     string   `json:"correlations"`
+	CorrelationRows  int      `json:"correlation_rows"`
+	WeightColumn     string   `json:"weight_column,omitempty"`
+	Robust           bool     `json:"robust,omitempty"`
+	TimeColumn       string   `json:"time_column,omitempty"`
+	TimeWindow       string   `json:"time_window,omitempty"` // Go duration, e.g. 168h0m0s
+	KAnonymity       int      `json:"k_anonymity,omitempty"` // values seen fewer times are withheld from listings
+	Scoring          string   `json:"scoring"`
+}
+
+// PlanCost estimates the work of a run.
+type PlanCost struct {
+	Bytes         int64 `json:"bytes"`          // size of the source
+	BytesRead     int64 `json:"bytes_read"`     // bytes read, less than Bytes with --range or a head sample
+	EstimatedRows int64 `json:"estimated_rows"` // rows of the source
+	RowsProfiled  int64 `json:"rows_profiled"`  // rows whose statistics are computed
+	FullScan      bool  `json:"full_scan"`      // whether every row is read
+	Network       bool  `json:"network"`        // whether the source is read over the network
+}
+
+// PlanProfile resolves what profiling source with opts will do. It rejects
+// the options and sources ProfileDatasetWithOptions rejects, and looks at no
+// more of the source than its size, the first lines of a text file, the
+// footer of a Parquet or ORC file and the table or sheet names of a
+// database or workbook; for a remote object it asks for its size.
+func PlanProfile(source string, opts Options) (*Plan, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	if err := checkSource(source, opts); err != nil {
+		return nil, err
+	}
+	if source != StdinSource && !remote.IsURL(source) && !IsPostgres(source) && !IsSnowflake(source) {
+		if _, err := os.Stat(source); err != nil {
+			return nil, fmt.Errorf("failed to open file: %w", err)
+		}
+	}
+
+	plan := &Plan{
+		Source: source,
+		Cost:   PlanCost{Bytes: -1, BytesRead: -1, EstimatedRows: -1, RowsProfiled: -1},
+	}
+	plan.resolveSource(opts)
+	plan.resolveAlgorithms(opts)
+	plan.resolveCost(opts)
+	return plan, nil
+}
+
+func (p *Plan) resolveSource(opts Options) {
+	source := p.Source
+	switch format := tableFormat(source, opts); {
+	case format != "":
+		p.Kind, p.Format = "table", format
+		p.Cost.Network = remote.IsURL(source)
+	case source == StdinSource:
+		p.Kind, p.Format = "stdin", fileFormat("", opts)
+	case remote.IsURL(source):
+		p.Kind, p.Format = "remote", fileFormat(remote.Path(source), opts)
+		p.Compression = compressionExt(remote.Path(source))
+		p.Cost.Network = true
+	case IsPostgres(source):
+		p.Kind, p.Format = "database", "postgres"
+		p.Source = redactPostgres(source)
+		if pg, err := parsePostgres(source, opts); err == nil && pg.table != "" {
+			p.Tables = []string{pg.table}
+		}
+	case IsSnowflake(source):
+		p.Kind, p.Format = "database", "snowflake"
+		p.Source = redactSnowflake(source)
+		if sf, err := parseSnowflake(source, opts); err == nil && sf.table != "" {
+			p.Tables = []string{sf.table}
+		}
+	case IsSQLite(source):
+		p.Kind, p.Format = "sqlite", "sqlite"
+		p.Tables = []string{opts.Table}
+		if opts.Table == "" {
+			tables, err := ListSQLiteTables(source)
+			p.warn(err)
+			p.Tables = tables
+		}
+	case IsExcel(source):
+		p.Kind, p.Format = "excel", "excel"
+		p.Tables = []string{opts.Sheet}
+		if opts.Sheet == "" && opts.Range == "" {
+			sheets, err := ListExcelSheets(source, opts.Password)
+			p.warn(err)
+			p.Tables = sheets
+		} else if opts.Sheet == "" {
+			p.Tables = nil
+		}
+	case IsArchive(source):
+		p.Kind, p.Format = "archive", "archive"
+		p.Compression = compressionExt(source)
+	default:
+		p.Kind, p.Format = "file", fileFormat(source, opts)
+		p.Compression = compressionExt(source)
+	}
+}
+
+func (p *Plan) resolveAlgorithms(opts Options) {
+	a := &p.Algorithms
+	a.Reading = "sequential"
+	if p.Cost.Network {
+		a.Reading = "remote"
+	}
+	a.Sampling = "none"
+	if opts.sampling() {
+		a.Sampling = opts.SampleStrategy
+		if a.Sampling == "" {
+			a.Sampling = SampleRandom
+		}
+		a.SampleRows = opts.SampleSize
+	}
+
+	a.ExactBelowRows = opts.ExactRows
+	a.DistinctCounts = fmt.Sprintf("exact up to %d values per column, then HyperLogLog", maxTrackedValues)
+	a.Percentiles = fmt.Sprintf("exact up to %d values per column, then a t-digest", exactNumericLimit)
+	a.Histogram = opts.histogramBinning()
+	a.HistogramBuckets = opts.histogram().buckets
+	a.TopValues = opts.topValues()
+	a.Duplicates = fmt.Sprintf("whole rows, exact by 64-bit row hash up to %d distinct rows, then from a hash sample of them", maxTrackedRows)
+	if len(opts.UniqueKey) > 0 {
+		a.Duplicates = fmt.Sprintf("unique key, exact by 64-bit key hash up to %d distinct keys, then from a hash sample of them", maxTrackedRows)
+		a.UniqueKey = opts.UniqueKey
+	}
+	a.Correlations = "pearson and spearman of numeric columns, cramers_v of categorical columns, on a uniform sample of rows"
+	a.CorrelationRows = opts.correlationRows()
+	a.WeightColumn = opts.WeightColumn
+	a.Robust = opts.Robust
+	if opts.TimeColumn != "" {
+		a.TimeColumn, a.TimeWindow = opts.TimeColumn, opts.TimeWindow.String()
+	}
+	a.KAnonymity = opts.KAnonymity
+	a.Scoring = opts.scoring().Label()
+
+	a.Engine = EngineGo
+	if opts.Engine == EngineDuckDB {
+		if why := p.duckDBReason(opts); why != "" {
+			p.Warnings = append(p.Warnings, "Profiled with the Go engine: "+why)
+		} else {
+			a.Engine = EngineDuckDB
+			a.DistinctCounts = "exact, by DuckDB"
+			a.Percentiles = "exact, by DuckDB"
+			a.Duplicates = "whole rows, exact, by DuckDB"
+			if len(opts.UniqueKey) > 0 {
+				a.Duplicates = "unique key, exact, by DuckDB"
+			}
+			a.Correlations = "none with the DuckDB engine"
+		}
+	}
+
+	if opts.Parallel > 1 && a.Engine == EngineGo {
+		if why := p.sequentialReason(opts); why != "" {
+			p.Warnings = append(p.Warnings, "Parsed sequentially: "+why)
+		} else {
+			a.Reading, a.Workers = "parallel", opts.Parallel
+		}
+	}
+}
+
+// sequentialReason is why --parallel cannot split the source, as far as can
+// be told without reading it; a file too small to split is still read
+// sequentially.
+func (p *Plan) sequentialReason(opts Options) string {
+	switch {
+	case p.Kind != "file" || (p.Format != FormatCSV && p.Format != FormatTSV):
+		return "--parallel only applies to local CSV and TSV files"
+	case opts.sampling():
+		return "--parallel does not combine with --sample"
+	case opts.MaxBytes > 0:
+		return "--parallel does not combine with --range"
+	case opts.Comment != 0:
+		return "--parallel does not combine with --comment"
+	case opts.WeightColumn != "":
+		return "--parallel does not combine with --weight-column"
+	case p.Compression != "":
+		return fmt.Sprintf("--parallel does not apply to %s-compressed input", p.Compression)
+	}
+	return ""
+}
+
+func (p *Plan) resolveCost(opts Options) {
+	c := &p.Cost
+	switch {
+	case p.Kind == "stdin" || p.Kind == "database":
+	case c.Network && p.Kind != "table":
+		info, err := remote.Stat(p.Source)
+		if err != nil {
+			p.warn(fmt.Errorf("could not look up the size: %w", err))
+		} else {
+			c.Bytes = info.Size
+		}
+	default:
+		c.Bytes = localSize(p.Source)
+	}
+
+	if p.Kind == "file" && p.Compression == "" && c.Bytes >= 0 {
+		switch p.Format {
+		case FormatCSV, FormatTSV, FormatJSONL:
+			c.EstimatedRows = estimateTextRows(p.Source, c.Bytes, p.Format != FormatJSONL, opts)
+		case "parquet":
+			c.EstimatedRows = parquetRows(p.Source, c.Bytes)
+		case "orc":
+			c.EstimatedRows = orcRows(p.Source, c.Bytes)
+		}
+	}
+	if p.Algorithms.Workers > 0 && c.Bytes >= 0 {
+		// Each worker parses at least parallelMinChunk bytes
+		p.Algorithms.Workers = int(min(int64(p.Algorithms.Workers), c.Bytes/parallelMinChunk))
+		if p.Algorithms.Workers < 2 {
+			p.Algorithms.Reading, p.Algorithms.Workers = "sequential", 0
+		}
+	}
+
+	// A head sample stops reading once it has its rows; random and
+	// systematic samples read every row to choose theirs.
+	c.BytesRead, c.RowsProfiled, c.FullScan = c.Bytes, c.EstimatedRows, true
+	if opts.MaxBytes > 0 && (c.Bytes < 0 || opts.MaxBytes < c.Bytes) {
+		c.FullScan, c.BytesRead = false, opts.MaxBytes
+		if c.EstimatedRows >= 0 && c.Bytes > 0 {
+			c.RowsProfiled = c.EstimatedRows * opts.MaxBytes / c.Bytes
+		}
+	}
+	if opts.sampling() {
+		n := int64(opts.SampleSize)
+		if c.RowsProfiled < 0 || n < c.RowsProfiled {
+			c.RowsProfiled = n
+		}
+		if p.Algorithms.Sampling == SampleHead {
+			c.FullScan = false
+			if c.EstimatedRows > 0 && c.BytesRead >= 0 {
+				c.BytesRead = min(c.BytesRead, c.Bytes*n/c.EstimatedRows)
+			} else {
+				c.BytesRead = -1
+			}
+		}
+	}
+	if (p.Format == "parquet" || p.Format == "orc") && c.Network {
+		// Parquet and ORC are read with range requests for the parts they need
+		c.BytesRead = -1
+	}
+}
+
+func (p *Plan) warn(err error) {
+	if err != nil {
+		p.Warnings = append(p.Warnings, err.Error())
+	}
+}
+
+// localSize is the size of a local file, or the total size of the files of
+// a table directory, -1 when it cannot be told.
+func localSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return -1
+	}
+	if !info.IsDir() {
+		return info.Size()
+	}
+
+	var total int64
+	err = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return -1
+	}
+	return total
+}
+
+// estimateTextRows extrapolates the row count of a text file of size bytes
+// from the line length of planSniffBytes at its start and at its middle,
+// leaving out the header and skipped lines. Quoted line breaks and a
+// preamble make it rougher.
+func estimateTextRows(path string, size int64, header bool, opts Options) int64 {
+	file, err := os.Open(path)
+	if err != nil {
+		return -1
+	}
+	defer file.Close()
+
+	var lines int64
+	if size <= 2*planSniffBytes {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return -1
+		}
+		lines = int64(bytes.Count(data, []byte{'\n'}))
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			lines++
+		}
+	} else {
+		// Only whole lines count towards the average length: those up to the
+		// last line break of the first chunk, and those between the first and
+		// last line breaks of the middle one
+		var count, length int64
+		for _, offset := range []int64{0, size / 2} {
+			buf := make([]byte, planSniffBytes)
+			n, err := file.ReadAt(buf, offset)
+			if err != nil && err != io.EOF {
+				return -1
+			}
+			buf = buf[:n]
+			start := 0
+			if offset > 0 {
+				start = bytes.IndexByte(buf, '\n') + 1
+			}
+			end := bytes.LastIndexByte(buf, '\n') + 1
+			if start == 0 && offset > 0 || end <= start {
+				continue
+			}
+			count += int64(bytes.Count(buf[start:end], []byte{'\n'}))
+			length += int64(end - start)
+		}
+		if count == 0 {
+			return -1
+		}
+		lines = size * count / length
+	}
+
+	lines -= int64(opts.SkipRows + opts.SkipFooter)
+	if header {
+		lines--
+	}
+	return max(lines, 0)
+}
+
+// parquetRows reads the row count off the footer of a Parquet file.
+func parquetRows(path string, size int64) int64 {
+	file, err := os.Open(path)
+	if err != nil {
+		return -1
+	}
+	defer file.Close()
+
+	pf, err := parquet.OpenFile(file, size, parquet.SkipPageIndex(true), parquet.SkipBloomFilters(true))
+	if err != nil {
+		return -1
+	}
+	return pf.NumRows()
+}
+
+// orcRows reads the row count off the footer of an ORC file.
+func orcRows(path string, size int64) int64 {
+	file, err := os.Open(path)
+	if err != nil {
+		return -1
+	}
+	defer file.Close()
+
+	orc, err := openORC(file, size)
+	if err != nil {
+		return -1
+	}
+	orc.close()
+	return int64(orc.rows)
+}
@@ -0,0 +1,199 @@
+package profiler
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// IsPostgres reports whether source is a PostgreSQL connection string.
+func IsPostgres(source string) bool {
+	lower := strings.ToLower(source)
+	return strings.HasPrefix(lower, "postgres://") || strings.HasPrefix(lower, "postgresql://")
+}
+
+// postgresSource is a PostgreSQL connection string taken apart: the
+// connection string to open, without the table parameter lib/pq would pass
+// on to the server, the table to profile, and the database name.
+type postgresSource struct {
+	dsn      string
+	table    string
+	database string
+}
+
+// parsePostgres reads the table from the table parameter of source or from
+// opts.Table. Errors leave out the connection string, which may hold a
+// password.
+func parsePostgres(source string, opts Options) (postgresSource, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return postgresSource{}, fmt.Errorf("invalid PostgreSQL connection string: %w", err)
+	}
+
+	query := u.Query()
+	table := query.Get("table")
+	query.Del("table")
+	u.RawQuery = query.Encode()
+	if opts.Table != "" {
+		if table != "" && table != opts.Table {
+			return postgresSource{}, fmt.Errorf("--table %s differs from the table %s of the connection string", opts.Table, table)
+		}
+		table = opts.Table
+	}
+
+	database := strings.Trim(u.Path, "/")
+	if database == "" {
+		database = u.Hostname()
+	}
+	return postgresSource{dsn: u.String(), table: table, database: database}, nil
+}
+
+// RedactSource returns source with the password of a connection string
+// masked, for printing. Other sources are returned as they are.
+func RedactSource(source string) string {
+	switch {
+	case IsPostgres(source):
+		return redactPostgres(source)
+	case IsSnowflake(source):
+		return redactSnowflake(source)
+	}
+	return source
+}
+
+// redactPostgres returns source with its password masked, for progress
+// events and messages.
+func redactPostgres(source string) string {
+	u, err := url.Parse(source)
+	if err != nil {
+		return "postgres://"
+	}
+	return u.Redacted()
+}
+
+// postgresTables lists the tables of every user schema, those of the public
+// schema by name alone and the others qualified with their schema.
+func postgresTables(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`
+		SELECT table_schema, table_name
+		FROM information_schema.tables
+		WHERE table_type = 'BASE TABLE' AND table_schema NOT IN ('pg_catalog', 'information_schema')
+		ORDER BY table_schema, table_name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PostgreSQL tables: %w", err)
+	}
+	defer rows.Close()
+
+	tables := make([]string, 0)
+	for rows.Next() {
+		var schema, name string
+		if err := rows.Scan(&schema, &name); err != nil {
+			return nil, fmt.Errorf("failed to list PostgreSQL tables: %w", err)
+		}
+		if schema != "public" {
+			name = schema + "." + name
+		}
+		tables = append(tables, name)
+	}
+
+	return tables, rows.Err()
+}
+
+// quotePostgresTable quotes a table name, qualified with its schema or not.
+func quotePostgresTable(table string) string {
+	parts := strings.SplitN(table, ".", 2)
+	for i, part := range parts {
+		parts[i] = pq.QuoteIdentifier(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+func profilePostgres(source string, opts Options) (*DatasetProfile, error) {
+	startTime := time.Now()
+
+	if err := opts.textOnly("PostgreSQL databases"); err != nil {
+		return nil, err
+	}
+
+	pg, err := parsePostgres(source, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("postgres", pg.dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PostgreSQL connection: %w", err)
+	}
+	defer db.Close()
+
+	if err := db.PingContext(opts.context()); err != nil {
+		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+
+	table := pg.table
+	if table == "" {
+		tables, err := postgresTables(db)
+		if err != nil {
+			return nil, err
+		}
+		if len(tables) != 1 {
+			return nil, fmt.Errorf("database has %d tables, choose one with ?table= or --table: %s", len(tables), strings.Join(tables, ", "))
+		}
+		table = tables[0]
+	}
+
+	rows, err := db.QueryContext(opts.context(), "SELECT * FROM "+quotePostgresTable(table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	header, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of table %s: %w", table, err)
+	}
+
+	profile := newDatasetProfile(pg.database+"."+table, 0, "PostgreSQL", header)
+	profile.Table = table
+
+	values := make([]interface{}, len(header))
+	pointers := make([]interface{}, len(header))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	next := func() ([]string, error) {
+		if !rows.Next() {
+			if err := rows.Err(); err != nil {
+				return nil, fmt.Errorf("error reading table %s: %w", table, err)
+			}
+			return nil, io.EOF
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("error reading table %s: %w", table, err)
+		}
+
+		record := make([]string, len(values))
+		for i, value := range values {
+			record[i] = formatSQLValue(value)
+		}
+		return record, nil
+	}
+
+	if err := profileRows(profile, header, next, opts); err != nil {
+		return nil, err
+	}
+
+	profile.ProcessingTime = time.Since(startTime)
+
+	return profile, nil
+}
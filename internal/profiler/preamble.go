@@ -0,0 +1,133 @@
+package profiler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"io"
+)
+
+// preambleScan is the number of leading lines inspected for a preamble and
+// for the delimiter.
+const preambleScan = 25
+
+// csvDialect is how a delimited source is written. A zero comma is sniffed
+// from the leading lines.
+type csvDialect struct {
+	comma   rune
+	comment rune
+}
+
+// skipPreamble drops the lines before the header of a delimited source.
+// With skip > 0 exactly that many lines are dropped. Otherwise the leading
+// lines are inspected: exports often start with banners, titles or blank
+// lines, and the header is taken to be the first line with the field count
+// shared by most of the lines that follow. An unknown delimiter is sniffed
+// from the same lines first. It returns the reader positioned at the header
+// and the number of lines dropped.
+func skipPreamble(r io.Reader, dialect *csvDialect, skip int) (io.Reader, int, error) {
+	br := bufio.NewReader(r)
+
+	skipped := 0
+	for skipped < skip {
+		if _, err := br.ReadBytes('\n'); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, 0, err
+		}
+		skipped++
+	}
+
+	lines := make([][]byte, 0, preambleScan)
+	for len(lines) < preambleScan {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if dialect.comma == 0 {
+		dialect.comma = sniffDelimiter(lines, dialect.comment)
+	}
+
+	if skip == 0 {
+		skipped = preambleLines(lines, dialect)
+		lines = lines[skipped:]
+	}
+
+	rest := bytes.Join(lines, nil)
+	return io.MultiReader(bytes.NewReader(rest), br), skipped, nil
+}
+
+// preambleLines returns the index of the header among the leading lines.
+// Only a field count seen on at least two lines with two or more fields
+// counts, so single-column files and short files are left alone.
+func preambleLines(lines [][]byte, dialect *csvDialect) int {
+	counts := fieldCounts(lines, dialect.comma, dialect.comment)
+
+	mode, best := countMode(counts)
+	if best < 2 {
+		return 0
+	}
+
+	for i, count := range counts {
+		if count == mode {
+			return i
+		}
+	}
+	return 0
+}
+
+func fieldCounts(lines [][]byte, comma, comment rune) []int {
+	counts := make([]int, len(lines))
+	for i, line := range lines {
+		counts[i] = fieldCount(line, comma, comment)
+	}
+	return counts
+}
+
+// countMode returns the most common field count above one and how often it
+// occurs, preferring the larger count on a tie.
+func countMode(counts []int) (int, int) {
+	frequency := make(map[int]int)
+	for _, count := range counts {
+		if count > 1 {
+			frequency[count]++
+		}
+	}
+
+	mode, best := 0, 0
+	for count, freq := range frequency {
+		if freq > best || (freq == best && count > mode) {
+			mode, best = count, freq
+		}
+	}
+	return mode, best
+}
+
+// fieldCount parses a single line, returning 0 for a blank or comment line
+// and -1 for a line that does not parse on its own, such as one opening a
+// multi-line quoted field.
+func fieldCount(line []byte, comma, comment rune) int {
+	reader := csv.NewReader(bytes.NewReader(line))
+	reader.Comma = comma
+	reader.Comment = comment
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	record, err := reader.Read()
+	if err == io.EOF {
+		return 0
+	}
+	if err != nil {
+		return -1
+	}
+	return len(record)
+}
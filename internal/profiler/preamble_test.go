@@ -0,0 +1,71 @@
+package profiler
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSkipPreamble(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		skip    int
+		want    int
+	}{
+		{"no preamble", "a,b,c\n1,2,3\n4,5,6\n", 0, 0},
+		{"banner and blank line", "Sales export\nGenerated 2024-01-31 by admin\n\na,b,c\n1,2,3\n4,5,6\n", 0, 3},
+		{"title with a comma", "Report: Q1, all regions\na,b,c\n1,2,3\n4,5,6\n", 0, 1},
+		{"single column", "Export\nid\n1\n2\n", 0, 0},
+		{"too short to tell", "Export\na,b\n", 0, 0},
+		{"explicit", "x,y,z\nmore,junk,here\na,b,c\n1,2,3\n", 2, 2},
+	}
+
+	for _, tt := range tests {
+		r, skipped, err := skipPreamble(strings.NewReader(tt.content), &csvDialect{comma: ','}, tt.skip)
+		if err != nil {
+			t.Fatalf("%s: skipPreamble failed: %v", tt.name, err)
+		}
+		if skipped != tt.want {
+			t.Errorf("%s: expected %d lines skipped, got %d", tt.name, tt.want, skipped)
+		}
+
+		rest, _ := io.ReadAll(r)
+		lines := strings.SplitAfter(tt.content, "\n")
+		if expected := strings.Join(lines[skipped:], ""); string(rest) != expected {
+			t.Errorf("%s: expected remaining %q, got %q", tt.name, expected, rest)
+		}
+	}
+}
+
+func TestProfilePreamble(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.csv")
+	content := "Exported from Billing on 2024-01-31\n\nid,amount,country\n1,10.5,US\n2,20.0,DE\n3,7.25,FR\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	profile, err := ProfileDataset(path)
+	if err != nil {
+		t.Fatalf("Failed to profile file with a preamble: %v", err)
+	}
+	if profile.RowCount != 3 || profile.ColumnCount != 3 {
+		t.Errorf("Expected 3 rows and 3 columns, got %d and %d", profile.RowCount, profile.ColumnCount)
+	}
+	if profile.Columns["amount"] == nil || !profile.Columns["amount"].IsNumeric {
+		t.Error("Expected a numeric amount column")
+	}
+	if len(profile.Notes) == 0 || !strings.Contains(profile.Notes[0], "Skipped 2 preamble lines") {
+		t.Errorf("Expected a preamble note, got %v", profile.Notes)
+	}
+
+	profile, err = ProfileDatasetWithOptions(path, Options{SkipRows: 2})
+	if err != nil {
+		t.Fatalf("Failed to profile with SkipRows: %v", err)
+	}
+	if profile.RowCount != 3 || !strings.Contains(strings.Join(profile.Notes, " "), "--skip-rows") {
+		t.Errorf("Expected 3 rows and a --skip-rows note, got %d %v", profile.RowCount, profile.Notes)
+	}
+}
@@ -0,0 +1,110 @@
+package profiler
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RedactedValue stands in for the values of redacted columns in a preview.
+const RedactedValue = "[redacted]"
+
+// WithheldValue stands in for preview values seen fewer times than the
+// k-anonymity threshold.
+const WithheldValue = "[withheld]"
+
+// Preview is the first rows of a dataset as read, limited to the previewed
+// columns.
+type Preview struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// previewRows keeps the first rows of a pass over the records. Values of
+// redacted columns are never kept, and long values are truncated like
+// examples.
+type previewRows struct {
+	limit    int
+	columns  []string
+	indexes  []int // header indexes of the columns
+	redacted []bool
+	rows     [][]string
+	missing  []string // requested columns not in the header
+}
+
+// newPreviewRows previews the columns named in opts.PreviewColumns, or every
+// column, in header order. It returns nil when opts.Preview is 0.
+func newPreviewRows(header []string, opts Options) *previewRows {
+	if opts.Preview == 0 {
+		return nil
+	}
+
+	p := &previewRows{limit: opts.Preview}
+	wanted := func(name string) bool {
+		if len(opts.PreviewColumns) == 0 {
+			return true
+		}
+		for _, column := range opts.PreviewColumns {
+			if strings.EqualFold(column, name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	seen := make(map[string]bool)
+	for i, name := range header {
+		if seen[name] || !wanted(name) {
+			continue
+		}
+		seen[name] = true
+		p.columns = append(p.columns, name)
+		p.indexes = append(p.indexes, i)
+		p.redacted = append(p.redacted, opts.redacted(name))
+	}
+
+	for _, column := range opts.PreviewColumns {
+		found := false
+		for name := range seen {
+			found = found || strings.EqualFold(column, name)
+		}
+		if !found {
+			p.missing = append(p.missing, column)
+		}
+	}
+
+	return p
+}
+
+func (p *previewRows) add(record []string) {
+	if len(p.rows) == p.limit {
+		return
+	}
+
+	row := make([]string, len(p.indexes))
+	for i, index := range p.indexes {
+		if p.redacted[i] {
+			row[i] = RedactedValue
+		} else {
+			row[i] = truncateExample(recordValue(record, index))
+		}
+	}
+	p.rows = append(p.rows, row)
+}
+
+// merge appends the rows of o, which previewed the records that follow.
+func (p *previewRows) merge(o *previewRows) {
+	for _, row := range o.rows {
+		if len(p.rows) == p.limit {
+			return
+		}
+		p.rows = append(p.rows, row)
+	}
+}
+
+func (p *previewRows) apply(profile *DatasetProfile) {
+	profile.Preview = &Preview{Columns: p.columns, Rows: p.rows}
+	if len(p.missing) > 0 {
+		profile.Notes = append(profile.Notes, fmt.Sprintf(
+			"Preview columns not found: %s", strings.Join(p.missing, ", ")))
+	}
+}
@@ -0,0 +1,68 @@
+package profiler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPreviewRows(t *testing.T) {
+	header := []string{"id", "email", "note", "id"}
+	opts := Options{Preview: 3, PreviewColumns: []string{"ID", "email", "missing"}, Redact: []string{"email"}}
+
+	first, second := newPreviewRows(header, opts), newPreviewRows(header, opts)
+	first.add([]string{"1", "a@example.com", "x", "dup"})
+	second.add([]string{"2", "b@example.com"})
+	second.add([]string{"3", "c@example.com", strings.Repeat("y", 300), ""})
+	second.add([]string{"4", "d@example.com", "", ""})
+	first.merge(second)
+
+	profile := &DatasetProfile{}
+	first.apply(profile)
+
+	want := &Preview{
+		Columns: []string{"id", "email"},
+		Rows:    [][]string{{"1", RedactedValue}, {"2", RedactedValue}, {"3", RedactedValue}},
+	}
+	if !reflect.DeepEqual(profile.Preview, want) {
+		t.Errorf("Expected preview %v, got %v", want, profile.Preview)
+	}
+	if len(profile.Notes) != 1 || profile.Notes[0] != "Preview columns not found: missing" {
+		t.Errorf("Expected a note on the missing preview column, got %v", profile.Notes)
+	}
+
+	if newPreviewRows(header, Options{}) != nil {
+		t.Error("Expected no preview without a row count")
+	}
+}
+
+func TestProfilePreview(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("id,name\n")
+	for i := 0; i < 20; i++ {
+		fmt.Fprintf(&b, "%d,%s\n", i, strings.Repeat("n", 250))
+	}
+	path := filepath.Join(t.TempDir(), "names.csv")
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	profile, err := ProfileDatasetWithOptions(path, Options{Preview: 5})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	preview := profile.Preview
+	if preview == nil || len(preview.Rows) != 5 || !reflect.DeepEqual(preview.Columns, []string{"id", "name"}) {
+		t.Fatalf("Expected 5 preview rows of id and name, got %+v", preview)
+	}
+	if row := preview.Rows[4]; row[0] != "4" || row[1] != strings.Repeat("n", maxExampleLength)+"…" {
+		t.Errorf("Expected row 4 with its name truncated, got %v", row)
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{Preview: -1}); err == nil {
+		t.Error("Expected an error for a negative preview")
+	}
+}
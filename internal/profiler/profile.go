@@ -1,32 +1,59 @@
 package profiler
 
 import (
+	"context"
 	"fmt"
-	"math"
-	"path/filepath"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/kamalm96/datasleuth/internal/events"
+	"github.com/kamalm96/datasleuth/internal/remote"
 )
 
 type DatasetProfile struct {
 	Filename          string
 	FileSize          int64
+	Compression       string // gzip, zstd or bzip2 when the source was compressed
+	UncompressedSize  int64  // decompressed bytes, 0 if unknown
+	Encoding          string // character encoding of text sources before conversion to UTF-8
 	Format            string
+	Table             string // table or sheet name when profiled from a database or workbook
 	RowCount          int
 	ColumnCount       int
 	MissingCells      int
 	DuplicateRows     int
+	BadRows           int              // rows skipped for not parsing, with SkipBadRows
+	Interrupted       bool             // reading stopped early when the context was done; the statistics cover the rows read
+	DuplicateGroups   []DuplicateGroup // every set of identical rows, in exact mode
+	UniqueKey         []string         // columns that identify a row, empty to compare whole rows
+	DuplicateKeys     []DuplicateKey   // most repeated values of the unique key
+	Exact             bool             // small dataset profiled with complete value listings
+	HistogramBinning  string           // equal-width, equal-frequency, log or auto, as asked for
+	Preview           *Preview         // first rows, with --preview
+	KAnonymity        int              // values seen fewer times were withheld from listings, 0 when none were
 	Columns           map[string]*ColumnProfile
 	QualityIssues     []QualityIssue
 	QualityScore      int
 	CorrelationMatrix *CorrelationMatrix
-	Recommendations   []string
+	RedundantPairs    []RedundantPair    // columns holding the same values
+	Partitions        []PartitionProfile // partitions of a Hive-partitioned directory
+	TimeWindows       *TimeWindows       // latest window of a time column against the one before, with --time-column
+	Recommendations   []Recommendation
+	ContentDigest     string
+	SampleStrategy    string  // set when statistics come from a sample of the rows
+	SourceRows        int     // rows in the source when sampled, 0 if unknown
+	WeightColumn      string  // column of row weights behind the weighted estimates
+	WeightTotal       float64 // sum of the valid row weights
+	Thresholds        Thresholds
+	Notes             []string
 	ProcessingTime    time.Duration
 	CreatedAt         time.Time
 }
 
 type ColumnProfile struct {
 	Name             string
+	Position         int // index of the column in the source, from 0
 	DataType         string
 	Count            int
 	MissingCount     int
@@ -36,13 +63,56 @@ type ColumnProfile struct {
 	Mean             float64
 	Median           float64
 	StdDev           float64
+	Skewness         float64
+	Kurtosis         float64     // excess kurtosis, 0 for a normal distribution
+	Mode             interface{} // most frequent value, nil when none repeats
+	Percentiles      []Percentile
+	Robust           *RobustStats    // trimmed mean, winsorized standard deviation and MAD, with --robust
+	Outliers         *OutlierSummary // bounds outside which values are outliers, nil when the spread is zero
 	HistogramBuckets []HistogramBucket
+	HistogramBinning string // equal-width, equal-frequency or log, as resolved for the column
+	DateTime         *DateTimeStats
+	Text             *TextStats
 	TopValues        []ValueCount
+	BottomValues     []ValueCount // least frequent values, rarest first, when not all values are top values
+	Rare             *RareValues  // values seen once, nil when values were not all counted
+	Examples         []string     // randomly sampled raw values
+	ExamplesRedacted bool         // examples withheld by --redact
+	SuppressedValues int          // top and bottom values withheld for being seen fewer than KAnonymity times
 	IsNumeric        bool
 	IsCategorical    bool
 	IsDateTime       bool
 	IsUnique         bool
+	IsOpaque         bool
+	NumberFormat     string      // us, eu or in when numbers use its separators, empty for plain numbers
+	DateOrder        string      // day-first when dates were read day first, empty for month first
+	DateFormat       string      // time layout most timestamps were read in, such as 2006-01-02, or epoch-seconds or epoch-millis
+	Conversion       *Conversion // values lost casting to the inferred or a stricter type
+	ParseErrors      ParseErrors // values that did not read cleanly
+	MixedTypes       *MixedTypes // values by type, when a sizeable share reads as another type than the rest
+	MinHash          *MinHash    // salted signature of the distinct values, with Options.MinHash
+	Digest           string
+	AvgLength        float64
+	MaxLength        int
+	Nullability      *Nullability
 	QualityIssues    []QualityIssue
+	Notes            []string
+}
+
+// Percentile is the value below which Rank percent of the values fall.
+type Percentile struct {
+	Rank  int
+	Value float64
+}
+
+// Percentile returns the value at rank, if it was computed.
+func (c *ColumnProfile) Percentile(rank int) (float64, bool) {
+	for _, p := range c.Percentiles {
+		if p.Rank == rank {
+			return p.Value, true
+		}
+	}
+	return 0, false
 }
 
 type HistogramBucket struct {
@@ -63,137 +133,219 @@ type QualityIssue struct {
 }
 
 func ProfileDataset(filePath string) (*DatasetProfile, error) {
-	extension := strings.ToLower(filepath.Ext(filePath))
+	return ProfileDatasetWithOptions(filePath, Options{})
+}
+
+func ProfileDatasetWithOptions(filePath string, opts Options) (*DatasetProfile, error) {
+	return ProfileDatasetContext(context.Background(), filePath, opts)
+}
+
+// ProfileDatasetContext profiles filePath until ctx is done. Reading then
+// stops before the next row, and the profile of the rows read so far is
+// returned, marked Interrupted, with an error wrapping ctx.Err(). A source
+// stopped before its rows are reached, such as while downloading, returns
+// no profile.
+func ProfileDatasetContext(ctx context.Context, filePath string, opts Options) (*DatasetProfile, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	if err := checkSource(filePath, opts); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	startTime := time.Now()
 
+	opts.ctx = ctx
+	opts.progress = newProgressTracker(eventSource(filePath, opts))
+	events.Publish(events.Event{Kind: events.Started, Source: opts.progress.source})
+
+	profile, err := profileSource(filePath, opts)
+	if err != nil {
+		events.Publish(events.Event{Kind: events.Finished, Source: opts.progress.source, Message: err.Error()})
+		return nil, err
+	}
+	if profile.Interrupted {
+		err = stopped(ctx, profile)
+	}
+
+	publishNotes(opts.progress.source, profile)
+	finished := events.Event{
+		Kind:    events.Finished,
+		Source:  opts.progress.source,
+		Rows:    int64(profile.RowCount),
+		Columns: profile.ColumnCount,
+	}
+	if err != nil {
+		finished.Message = err.Error()
+	}
+	events.Publish(finished)
+
+	profile.ProcessingTime = time.Since(startTime)
+
+	return profile, err
+}
+
+// checkSource rejects a source that cannot be profiled, or options that do
+// not apply to it.
+func checkSource(filePath string, opts Options) error {
+	if scheme, _, ok := strings.Cut(filePath, "://"); ok && !remote.IsURL(filePath) && !IsPostgres(filePath) && !IsSnowflake(filePath) {
+		// The rest of a connection string may hold a password
+		return fmt.Errorf("%s:// connection strings are not supported, only postgres://, postgresql:// and snowflake://", scheme)
+	}
+
+	if opts.Range != "" && !IsExcel(filePath) {
+		return fmt.Errorf("--range with a table, name or cell range is only supported for Excel workbooks: %s", filePath)
+	}
+	if opts.Member != "" && !IsArchive(filePath) {
+		return fmt.Errorf("--member is only supported for zip and tar archives: %s", filePath)
+	}
+	if opts.RecordPath != "" && !isXML(filePath, opts) {
+		return fmt.Errorf("--record-path is only supported for XML files: %s", filePath)
+	}
+	if opts.Checksum != "" && (!remote.IsURL(filePath) || tableFormat(filePath, opts) != "") {
+		return fmt.Errorf("--checksum is only supported for remote files: %s", filePath)
+	}
+	return nil
+}
+
+// profileSource profiles filePath with DuckDB when opts asks for it and
+// DuckDB can, and otherwise with the backend for its kind and format.
+func profileSource(filePath string, opts Options) (*DatasetProfile, error) {
 	var profile *DatasetProfile
 	var err error
 
-	switch extension {
-	case ".csv":
-		profile, err = ProfileCSV(filePath)
-	case ".parquet":
-		profile = &DatasetProfile{
-			Filename:  filePath,
-			Format:    "Parquet",
-			CreatedAt: time.Now(),
-			QualityIssues: []QualityIssue{
-				{
-					Type:        "unsupported_format",
-					Description: "Parquet support is coming soon",
-					Severity:    2,
-				},
-			},
-		}
-	case ".json":
-		profile = &DatasetProfile{
-			Filename:  filePath,
-			Format:    "JSON",
-			CreatedAt: time.Now(),
-			QualityIssues: []QualityIssue{
-				{
-					Type:        "unsupported_format",
-					Description: "JSON support is coming soon",
-					Severity:    2,
+	reason := ""
+	if opts.Engine == EngineDuckDB {
+		profile, reason, err = profileDuckDB(filePath, opts)
+	}
+
+	switch {
+	case profile != nil || err != nil:
+	case tableFormat(filePath, opts) == FormatHive:
+		profile, err = profilePartitioned(filePath, opts)
+	case tableFormat(filePath, opts) != "":
+		profile, err = profileTable(filePath, tableFormat(filePath, opts), opts)
+	case filePath == StdinSource:
+		profile, err = profileStdin(opts)
+	case remote.IsURL(filePath):
+		profile, err = profileRemote(filePath, opts)
+	case IsPostgres(filePath):
+		profile, err = profilePostgres(filePath, opts)
+	case IsSnowflake(filePath):
+		profile, err = profileSnowflake(filePath, opts)
+	case IsSQLite(filePath):
+		profile, err = profileSQLite(filePath, opts)
+	case opts.Table != "":
+		return nil, fmt.Errorf("--table is only supported for SQLite, PostgreSQL and Snowflake databases: %s", filePath)
+	case IsExcel(filePath):
+		profile, err = profileExcel(filePath, opts)
+	case opts.Sheet != "":
+		return nil, fmt.Errorf("--sheet is only supported for Excel workbooks: %s", filePath)
+	case IsArchive(filePath):
+		profile, err = profileArchive(filePath, opts)
+	default:
+		switch fileFormat(filePath, opts) {
+		case FormatTSV:
+			profile, err = profileTSV(filePath, opts)
+		case FormatJSONL:
+			profile, err = profileJSONLFile(filePath, opts)
+		case FormatXML:
+			profile, err = profileXMLFile(filePath, opts)
+		case "parquet":
+			profile, err = profileParquet(filePath, opts)
+		case "avro":
+			profile, err = profileAvroFile(filePath, opts)
+		case "orc":
+			profile, err = profileORC(filePath, opts)
+		case "json":
+			profile = &DatasetProfile{
+				Filename:  filePath,
+				Format:    "JSON",
+				CreatedAt: time.Now(),
+				QualityIssues: []QualityIssue{
+					{
+						Type:        "unsupported_format",
+						Description: "JSON support is coming soon",
+						Severity:    2,
+					},
 				},
-			},
+			}
+		default:
+			profile, err = profileCSV(filePath, opts)
 		}
-	default:
-		profile, err = ProfileCSV(filePath)
 	}
 
 	if err != nil {
 		return nil, err
 	}
+	if reason != "" {
+		profile.Notes = append(profile.Notes, "Profiled with the Go engine: "+reason)
+	}
 
 	// Calculate the quality score
-	profile.QualityScore = CalculateQualityScore(profile)
+	scoring := opts.scoring()
+	profile.QualityScore = scoring.Score(profile)
+	if !scoring.IsDefault() {
+		profile.Notes = append(profile.Notes, fmt.Sprintf("Quality score computed with the %s scoring profile", scoring.Label()))
+	}
 
 	// Calculate correlations for numeric columns
 	profile.CorrelationMatrix = CalculateCorrelationMatrix(profile)
 
-	// Add correlation insights to recommendations
-	if profile.CorrelationMatrix != nil && len(profile.CorrelationMatrix.TopPairs) > 0 {
-		if profile.Recommendations == nil {
-			profile.Recommendations = []string{}
-		}
-
-		for _, pair := range profile.CorrelationMatrix.TopPairs {
-			if math.Abs(pair.Correlation) >= 0.7 {
-				var recommendation string
-				if pair.Correlation > 0 {
-					recommendation = fmt.Sprintf("Strong positive correlation (%.2f) between '%s' and '%s' - consider if one could be derived from the other",
-						pair.Correlation, pair.Column1, pair.Column2)
-				} else {
-					recommendation = fmt.Sprintf("Strong negative correlation (%.2f) between '%s' and '%s' - these features may provide complementary information",
-						pair.Correlation, pair.Column1, pair.Column2)
-				}
-
-				profile.Recommendations = append(profile.Recommendations, recommendation)
-			}
-		}
+	engine, err := opts.recommendationEngine()
+	if err != nil {
+		return nil, err
 	}
+	profile.Recommendations = engine.Recommend(profile)
 
-	profile.ProcessingTime = time.Since(startTime)
+	suppressRareValues(profile, opts.KAnonymity)
 
 	return profile, nil
 }
 
+// CalculateQualityScore is the quality score of profile under DefaultScoring.
 func CalculateQualityScore(profile *DatasetProfile) int {
-	score := 100
-
-	if profile.RowCount == 0 || profile.ColumnCount == 0 {
-		return 0
-	}
+	return DefaultScoring().Score(profile)
+}
 
-	totalCells := profile.RowCount * profile.ColumnCount
+// IssuesAtOrAbove returns dataset and column issues with at least the given
+// severity, column issues prefixed with their column name.
+func (p *DatasetProfile) IssuesAtOrAbove(severity int) []QualityIssue {
+	issues := make([]QualityIssue, 0)
 
-	// Deduct for missing values (up to 30 points)
-	if totalCells > 0 {
-		missingPercentage := float64(profile.MissingCells) / float64(totalCells) * 100
-		if missingPercentage > 0 {
-			penalty := int(missingPercentage * 3) // 3 points per percent missing
-			if penalty > 30 {
-				penalty = 30 // Cap at 30 points
-			}
-			score -= penalty
+	for _, issue := range p.QualityIssues {
+		if issue.Severity >= severity {
+			issues = append(issues, issue)
 		}
 	}
 
-	// Deduct for quality issues (up to 40 points)
-	issuePenalty := 0
-	for _, issue := range profile.QualityIssues {
-		issuePenalty += issue.Severity * 5 // 5-15 points per issue depending on severity
+	names := make([]string, 0, len(p.Columns))
+	for name := range p.Columns {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	// Add column-level issues
-	for _, col := range profile.Columns {
-		for _, issue := range col.QualityIssues {
-			issuePenalty += issue.Severity
+	for _, name := range names {
+		for _, issue := range p.Columns[name].QualityIssues {
+			if issue.Severity >= severity {
+				issue.Description = fmt.Sprintf("%s: %s", name, issue.Description)
+				issues = append(issues, issue)
+			}
 		}
 	}
 
-	if issuePenalty > 40 {
-		issuePenalty = 40 // Cap at 40 points
-	}
-	score -= issuePenalty
+	return issues
+}
 
-	// Deduct for duplicate rows (up to 15 points)
-	if profile.RowCount > 0 {
-		duplicatePercentage := float64(profile.DuplicateRows) / float64(profile.RowCount) * 100
-		if duplicatePercentage > 0 {
-			penalty := int(duplicatePercentage * 2) // 2 points per percent duplicates
-			if penalty > 15 {
-				penalty = 15 // Cap at 15 points
-			}
-			score -= penalty
+func (p *DatasetProfile) MaxSeverity() int {
+	maxSeverity := 0
+	for _, issue := range p.IssuesAtOrAbove(1) {
+		if issue.Severity > maxSeverity {
+			maxSeverity = issue.Severity
 		}
 	}
-
-	if score < 0 {
-		score = 0
-	}
-
-	return score
+	return maxSeverity
 }
@@ -1,10 +1,24 @@
 package profiler
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
+// writeTestFile writes content to a file of the given name in a temporary
+// directory and returns its path.
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	return path
+}
+
 func TestCalculateQualityScore(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -106,3 +120,70 @@ func TestProfileDataset_EmptyFile(t *testing.T) {
 		t.Error("Expected error for empty file, got nil")
 	}
 }
+
+func TestIssuesAtOrAbove(t *testing.T) {
+	profile := &DatasetProfile{
+		QualityIssues: []QualityIssue{
+			{Type: "duplicate_rows", Description: "Duplicate rows detected: 1.00%", Severity: 1},
+		},
+		Columns: map[string]*ColumnProfile{
+			"age": {
+				Name: "age",
+				QualityIssues: []QualityIssue{
+					{Type: "missing_values", Description: "Missing values: 25.00%", Severity: 3},
+				},
+			},
+			"city": {
+				Name: "city",
+				QualityIssues: []QualityIssue{
+					{Type: "imbalanced", Description: "Imbalanced", Severity: 2},
+				},
+			},
+		},
+	}
+
+	if profile.MaxSeverity() != 3 {
+		t.Errorf("Expected max severity 3, got %d", profile.MaxSeverity())
+	}
+
+	issues := profile.IssuesAtOrAbove(2)
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues of severity >= 2, got %d", len(issues))
+	}
+
+	if issues[0].Description != "age: Missing values: 25.00%" {
+		t.Errorf("Expected column issues to be prefixed with the column name, got %q", issues[0].Description)
+	}
+
+	if len(profile.IssuesAtOrAbove(1)) != 3 {
+		t.Errorf("Expected 3 issues in total, got %d", len(profile.IssuesAtOrAbove(1)))
+	}
+
+	if (&DatasetProfile{}).MaxSeverity() != 0 {
+		t.Error("Expected max severity 0 for a profile without issues")
+	}
+}
+
+func TestProfileRows(t *testing.T) {
+	path := writeTestFile(t, "data.csv", "id,label\n1,a\n2,b\n3,a\n")
+
+	var rows [][]string
+	opts := Options{Rows: func(header, record []string) error {
+		if len(header) != 2 || header[1] != "label" {
+			t.Errorf("Unexpected header %v", header)
+		}
+		rows = append(rows, append([]string(nil), record...))
+		return nil
+	}}
+	if _, err := ProfileDatasetWithOptions(path, opts); err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if len(rows) != 3 || rows[2][0] != "3" {
+		t.Errorf("Expected the three rows in order, got %v", rows)
+	}
+
+	opts.Rows = func(header, record []string) error { return errors.New("disk full") }
+	if _, err := ProfileDatasetWithOptions(path, opts); err == nil || err.Error() != "disk full" {
+		t.Errorf("Expected the error of Rows to stop profiling, got %v", err)
+	}
+}
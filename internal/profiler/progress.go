@@ -0,0 +1,79 @@
+package profiler
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/events"
+)
+
+// progressInterval is the least time between two progress events of a source.
+const progressInterval = 250 * time.Millisecond
+
+// progressTracker counts the rows read from one source and publishes the
+// count now and then. The workers of a parallel parse share one tracker.
+type progressTracker struct {
+	source string
+	rows   atomic.Int64
+	last   atomic.Int64 // UnixNano of the last progress event
+}
+
+func newProgressTracker(source string) *progressTracker {
+	p := &progressTracker{source: source}
+	p.last.Store(time.Now().UnixNano())
+	return p
+}
+
+// row counts one row, checking the clock only every 1024 rows.
+func (p *progressTracker) row() {
+	rows := p.rows.Add(1)
+	if rows%1024 != 0 {
+		return
+	}
+
+	now := time.Now().UnixNano()
+	last := p.last.Load()
+	if now-last < int64(progressInterval) || !p.last.CompareAndSwap(last, now) {
+		return
+	}
+	events.Publish(events.Event{Kind: events.Progress, Source: p.source, Rows: rows})
+}
+
+// publishColumnDone announces that the statistics of column are complete.
+func publishColumnDone(source, column string) {
+	events.Publish(events.Event{Kind: events.ColumnDone, Source: source, Column: column})
+}
+
+// publishNotes sends the notes of profile and its columns as note events.
+func publishNotes(source string, profile *DatasetProfile) {
+	for _, note := range profile.Notes {
+		events.Publish(events.Event{Kind: events.Note, Source: source, Message: note})
+	}
+	for _, name := range sortedColumnNames(profile) {
+		for _, note := range profile.Columns[name].Notes {
+			events.Publish(events.Event{Kind: events.Note, Source: source, Column: name, Message: note})
+		}
+	}
+}
+
+// tracker returns the progress tracker of the source being profiled, or a
+// new one named name when profiling did not start in ProfileDatasetContext.
+func (o Options) tracker(name string) *progressTracker {
+	if o.progress != nil {
+		return o.progress
+	}
+	return newProgressTracker(name)
+}
+
+// eventSource names source in events: the path, URL or connection string
+// without its password, followed by the table or sheet when one was chosen.
+func eventSource(source string, opts Options) string {
+	source = RedactSource(source)
+	switch {
+	case opts.Table != "":
+		return source + "#" + opts.Table
+	case opts.Sheet != "":
+		return source + "#" + opts.Sheet
+	}
+	return source
+}
@@ -0,0 +1,97 @@
+package profiler
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/events"
+)
+
+// captureEvents collects the events published on the default bus until the
+// test ends.
+func captureEvents(t *testing.T) func() []events.Event {
+	t.Helper()
+
+	var mu sync.Mutex
+	var captured []events.Event
+	stop := events.Default.Handle(func(e events.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		captured = append(captured, e)
+	})
+	t.Cleanup(stop)
+
+	return func() []events.Event {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]events.Event(nil), captured...)
+	}
+}
+
+func TestProfilePublishesEvents(t *testing.T) {
+	withParallelMinChunk(t, 256)
+	path := writeTestFile(t, "data.csv", parallelCSV(2000))
+	captured := captureEvents(t)
+
+	for _, opts := range []Options{{}, {Parallel: 4}} {
+		before := len(captured())
+		profile, err := ProfileDatasetWithOptions(path, opts)
+		if err != nil {
+			t.Fatalf("Failed to profile: %v", err)
+		}
+		got := captured()[before:]
+
+		if got[0].Kind != events.Started || got[0].Source != path {
+			t.Errorf("Expected a started event for %s first, got %+v", path, got[0])
+		}
+		last := got[len(got)-1]
+		if last.Kind != events.Finished || last.Rows != int64(profile.RowCount) || last.Columns != profile.ColumnCount {
+			t.Errorf("Expected a finished event with %d rows last, got %+v", profile.RowCount, last)
+		}
+
+		done := make(map[string]bool)
+		notes := 0
+		for _, e := range got {
+			switch e.Kind {
+			case events.ColumnDone:
+				done[e.Column] = true
+			case events.Note:
+				notes++
+			}
+		}
+		if len(done) != profile.ColumnCount {
+			t.Errorf("Expected a column_done event per column, got %v", done)
+		}
+		if notes < len(profile.Notes) {
+			t.Errorf("Expected the %d profile notes as events, got %d", len(profile.Notes), notes)
+		}
+	}
+}
+
+func TestProfilePublishesFailure(t *testing.T) {
+	captured := captureEvents(t)
+
+	if _, err := ProfileDataset("missing.csv"); err == nil {
+		t.Fatal("Expected an error for a missing file")
+	}
+
+	got := captured()
+	if last := got[len(got)-1]; last.Kind != events.Finished || last.Message == "" {
+		t.Errorf("Expected a finished event with the error, got %+v", last)
+	}
+}
+
+func TestProgressTrackerThrottles(t *testing.T) {
+	captured := captureEvents(t)
+
+	tracker := newProgressTracker("data.csv")
+	tracker.last.Store(0)
+	for i := 0; i < 4096; i++ {
+		tracker.row()
+	}
+
+	got := captured()
+	if len(got) != 1 || got[0].Kind != events.Progress || got[0].Rows != 1024 {
+		t.Errorf("Expected a single progress event at 1024 rows, got %+v", got)
+	}
+}
@@ -0,0 +1,43 @@
+package profiler
+
+import "fmt"
+
+// DefaultTopValues is the number of most and least frequent values listed
+// per column unless Options.TopValues says otherwise.
+const DefaultTopValues = 5
+
+func (o Options) topValues() int {
+	if o.TopValues > 0 {
+		return o.TopValues
+	}
+	return DefaultTopValues
+}
+
+// RareValues counts the values of a column seen only once. Singletons are
+// only counted while every distinct value is, so columns with more than
+// maxTrackedValues distinct values have none.
+type RareValues struct {
+	Singletons int     // distinct values seen exactly once
+	Percent    float64 // singletons as a share of the distinct values, %
+}
+
+func (r RareValues) String() string {
+	return fmt.Sprintf("%d values seen once (%.1f%% of distinct values)", r.Singletons, r.Percent)
+}
+
+// applyValueCounts lists the most and least frequent values of col, limit of
+// each, and counts its singletons. Bottom values are only listed when some
+// values are not among the top ones, and only while every value is counted.
+func applyValueCounts(col *ColumnProfile, counter *valueCounter, limit int) {
+	col.TopValues = counter.topValues(limit)
+	if col.UniqueCount > len(col.TopValues) {
+		col.BottomValues = counter.bottomValues(limit)
+	}
+
+	if singletons, ok := counter.singletons(); ok && col.UniqueCount > 0 {
+		col.Rare = &RareValues{
+			Singletons: singletons,
+			Percent:    float64(singletons) / float64(col.UniqueCount) * 100,
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package profiler
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// rareCSV returns 200 rows of a category column whose first two values
+// repeat and whose 11 others appear once, and of a code column of 20 values
+// seen 10 times each.
+func rareCSV() string {
+	var b strings.Builder
+	b.WriteString("category,code\n")
+	for i := 0; i < 200; i++ {
+		category := "retail"
+		switch {
+		case i < 11:
+			category = fmt.Sprintf("retial%02d", i)
+		case i < 60:
+			category = "wholesale"
+		}
+		fmt.Fprintf(&b, "%s,%d\n", category, 100+i%20)
+	}
+	return b.String()
+}
+
+func TestProfileRareValues(t *testing.T) {
+	profile, err := ProfileDatasetWithOptions(writeTestFile(t, "data.csv", rareCSV()), Options{TopValues: 3})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+
+	category := profile.Columns["category"]
+	if want := []ValueCount{{"retail", 140}, {"wholesale", 49}, {"retial00", 1}}; !reflect.DeepEqual(category.TopValues, want) {
+		t.Errorf("Expected top values %v, got %v", want, category.TopValues)
+	}
+	if want := []ValueCount{{"retial00", 1}, {"retial01", 1}, {"retial02", 1}}; !reflect.DeepEqual(category.BottomValues, want) {
+		t.Errorf("Expected bottom values %v, got %v", want, category.BottomValues)
+	}
+	if r := category.Rare; r == nil || r.Singletons != 11 || r.String() != "11 values seen once (84.6% of distinct values)" {
+		t.Errorf("Expected 11 of 13 values seen once, got %v", r)
+	}
+
+	heavy := false
+	for _, issue := range category.QualityIssues {
+		heavy = heavy || issue.Type == "singleton_heavy"
+	}
+	if !category.IsCategorical || !heavy {
+		t.Errorf("Expected category to be flagged singleton-heavy, got %v", category.QualityIssues)
+	}
+
+	// Numeric columns list their values too, and no singletons here
+	code := profile.Columns["code"]
+	if len(code.TopValues) != 3 || code.TopValues[0].Count != 10 || len(code.BottomValues) != 3 {
+		t.Errorf("Expected 3 top and bottom codes, got %v and %v", code.TopValues, code.BottomValues)
+	}
+	if code.Rare == nil || code.Rare.Singletons != 0 {
+		t.Errorf("Expected no code seen once, got %v", code.Rare)
+	}
+	for _, issue := range code.QualityIssues {
+		if issue.Type == "singleton_heavy" {
+			t.Errorf("Expected code not to be singleton-heavy, got %v", issue)
+		}
+	}
+}
+
+func TestProfileBottomValuesOfFewValues(t *testing.T) {
+	// Every value is a top value, so there is nothing to list at the bottom
+	profile, err := ProfileDatasetWithOptions(writeTestFile(t, "data.csv", "city\nparis\nparis\nlyon\n"), Options{})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if city := profile.Columns["city"]; city.BottomValues != nil || city.Rare == nil || city.Rare.Singletons != 1 {
+		t.Errorf("Expected no bottom values and lyon seen once, got %v and %v", city.BottomValues, city.Rare)
+	}
+}
+
+func TestValueCounterRareValuesUncounted(t *testing.T) {
+	counter := newValueCounter()
+	for i := 0; i <= maxTrackedValues; i++ {
+		counter.add(fmt.Sprint(i))
+	}
+
+	if _, ok := counter.singletons(); ok || counter.bottomValues(5) != nil {
+		t.Error("Expected no singletons or bottom values once values are no longer all counted")
+	}
+	if len(counter.topValues(5)) != 5 {
+		t.Error("Expected top values to still be tracked")
+	}
+}
@@ -0,0 +1,351 @@
+package profiler
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Names of the built-in recommendation rules.
+const (
+	RuleImputeMissing      = "impute_missing"
+	RuleCheckOutliers      = "check_outliers"
+	RuleTransformSkewed    = "transform_skewed"
+	RuleTreatAsCategorical = "treat_as_categorical"
+	RuleDropRedundant      = "drop_redundant"
+	RuleCorrelatedColumns  = "correlated_columns"
+	RuleDeduplicate        = "deduplicate"
+	RuleReviewIssues       = "review_issues"
+)
+
+// Suggested actions of the built-in recommendations.
+const (
+	ActionImpute           = "impute"
+	ActionReview           = "review"
+	ActionLogTransform     = "log_transform"
+	ActionPowerTransform   = "power_transform"
+	ActionCategorical      = "convert_to_categorical"
+	ActionDropColumn       = "drop_column"
+	ActionReviewDerivation = "review_derivation"
+	ActionKeep             = "keep"
+	ActionDeduplicate      = "deduplicate"
+)
+
+// maxColumnRecommendations is how many columns a rule names one by one
+// before grouping them into a single recommendation.
+const maxColumnRecommendations = 3
+
+// Recommendation is an action suggested for a dataset, or for some of its
+// columns.
+type Recommendation struct {
+	Type    string   // name of the rule that made it
+	Columns []string // columns concerned, empty for the whole dataset
+	Action  string   // suggested action, such as impute or drop_column
+	Message string
+}
+
+// RecommendationRule looks at a profile and suggests actions. Rules are
+// identified by name, which is also the Type of their recommendations.
+type RecommendationRule interface {
+	Name() string
+	Recommend(profile *DatasetProfile) []Recommendation
+}
+
+type ruleFunc struct {
+	name string
+	fn   func(profile *DatasetProfile) []Recommendation
+}
+
+func (r ruleFunc) Name() string { return r.name }
+
+func (r ruleFunc) Recommend(profile *DatasetProfile) []Recommendation { return r.fn(profile) }
+
+// NewRecommendationRule makes a rule of a function.
+func NewRecommendationRule(name string, fn func(profile *DatasetProfile) []Recommendation) RecommendationRule {
+	return ruleFunc{name: name, fn: fn}
+}
+
+// RecommendationEngine runs its enabled rules in order. When none of them
+// recommends anything, the review_issues rule points at the quality issues
+// of a profile scoring below 90.
+type RecommendationEngine struct {
+	rules    []RecommendationRule
+	disabled map[string]bool
+}
+
+// NewRecommendationEngine returns an engine with the given rules, all
+// enabled.
+func NewRecommendationEngine(rules ...RecommendationRule) *RecommendationEngine {
+	return &RecommendationEngine{
+		rules:    append([]RecommendationRule(nil), rules...),
+		disabled: make(map[string]bool),
+	}
+}
+
+// DefaultRecommendationEngine returns an engine with the built-in rules.
+func DefaultRecommendationEngine() *RecommendationEngine {
+	return NewRecommendationEngine(
+		NewRecommendationRule(RuleImputeMissing, recommendImputation),
+		NewRecommendationRule(RuleCheckOutliers, recommendOutlierChecks),
+		NewRecommendationRule(RuleTransformSkewed, recommendTransforms),
+		NewRecommendationRule(RuleTreatAsCategorical, recommendCategorical),
+		NewRecommendationRule(RuleDropRedundant, recommendDroppingRedundant),
+		NewRecommendationRule(RuleCorrelatedColumns, recommendCorrelated),
+		NewRecommendationRule(RuleDeduplicate, recommendDeduplication),
+	)
+}
+
+// Register adds a rule after the others.
+func (e *RecommendationEngine) Register(rule RecommendationRule) error {
+	if e.has(rule.Name()) {
+		return fmt.Errorf("recommendation rule already registered: %s", rule.Name())
+	}
+	e.rules = append(e.rules, rule)
+	return nil
+}
+
+// Disable turns rules off by name.
+func (e *RecommendationEngine) Disable(names ...string) error {
+	for _, name := range names {
+		if !e.has(name) {
+			return fmt.Errorf("unknown recommendation rule: %s", name)
+		}
+		e.disabled[name] = true
+	}
+	return nil
+}
+
+// Enable turns disabled rules back on.
+func (e *RecommendationEngine) Enable(names ...string) error {
+	for _, name := range names {
+		if !e.has(name) {
+			return fmt.Errorf("unknown recommendation rule: %s", name)
+		}
+		delete(e.disabled, name)
+	}
+	return nil
+}
+
+// RuleNames lists the rules of the engine in order, review_issues last.
+func (e *RecommendationEngine) RuleNames() []string {
+	names := make([]string, 0, len(e.rules)+1)
+	for _, rule := range e.rules {
+		names = append(names, rule.Name())
+	}
+	return append(names, RuleReviewIssues)
+}
+
+func (e *RecommendationEngine) has(name string) bool {
+	for _, known := range e.RuleNames() {
+		if known == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Recommend runs the enabled rules on profile.
+func (e *RecommendationEngine) Recommend(profile *DatasetProfile) []Recommendation {
+	recommendations := make([]Recommendation, 0)
+	for _, rule := range e.rules {
+		if !e.disabled[rule.Name()] {
+			recommendations = append(recommendations, rule.Recommend(profile)...)
+		}
+	}
+
+	if len(recommendations) == 0 && profile.QualityScore < 90 && !e.disabled[RuleReviewIssues] {
+		recommendations = append(recommendations, Recommendation{
+			Type:    RuleReviewIssues,
+			Action:  ActionReview,
+			Message: "Review columns with quality issues (marked with ⚠️) for potential improvements",
+		})
+	}
+
+	return recommendations
+}
+
+// recommendationEngine is the default engine with the rules of the options
+// added and their disabled rules turned off.
+func (o Options) recommendationEngine() (*RecommendationEngine, error) {
+	engine := DefaultRecommendationEngine()
+	for _, rule := range o.RecommendationRules {
+		if err := engine.Register(rule); err != nil {
+			return nil, err
+		}
+	}
+	if err := engine.Disable(o.DisabledRecommendations...); err != nil {
+		return nil, err
+	}
+	return engine, nil
+}
+
+func sortedColumnNames(profile *DatasetProfile) []string {
+	names := make([]string, 0, len(profile.Columns))
+	for name := range profile.Columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// columnsWithIssue lists the columns having an issue of a type.
+func columnsWithIssue(profile *DatasetProfile, issueType string) []string {
+	columns := make([]string, 0)
+	for _, name := range sortedColumnNames(profile) {
+		for _, issue := range profile.Columns[name].QualityIssues {
+			if issue.Type == issueType {
+				columns = append(columns, name)
+				break
+			}
+		}
+	}
+	return columns
+}
+
+// perColumn makes one recommendation per column, or a single grouped one
+// when there are more than maxColumnRecommendations columns.
+func perColumn(rule, action string, columns []string, format, grouped string) []Recommendation {
+	if len(columns) == 0 {
+		return nil
+	}
+	if len(columns) > maxColumnRecommendations {
+		return []Recommendation{{Type: rule, Columns: columns, Action: action, Message: grouped}}
+	}
+
+	recommendations := make([]Recommendation, len(columns))
+	for i, column := range columns {
+		recommendations[i] = Recommendation{
+			Type:    rule,
+			Columns: []string{column},
+			Action:  action,
+			Message: fmt.Sprintf(format, column),
+		}
+	}
+	return recommendations
+}
+
+func recommendImputation(profile *DatasetProfile) []Recommendation {
+	threshold := profile.EffectiveThresholds().ImputeMissingPercent
+	columns := make([]string, 0)
+	for _, name := range sortedColumnNames(profile) {
+		col := profile.Columns[name]
+		if col.MissingCount > 0 && float64(col.MissingCount)/float64(profile.RowCount)*100 > threshold {
+			columns = append(columns, name)
+		}
+	}
+	return perColumn(RuleImputeMissing, ActionImpute, columns,
+		"Consider imputing missing values in '%s' column",
+		"Several columns have high missing value rates and may need imputation")
+}
+
+func recommendOutlierChecks(profile *DatasetProfile) []Recommendation {
+	return perColumn(RuleCheckOutliers, ActionReview, columnsWithIssue(profile, "outliers"),
+		"Check outliers in '%s' column",
+		"Multiple numeric columns contain outliers")
+}
+
+// recommendTransforms suggests a log transform for a right-skewed column of
+// positive values, and a power transform otherwise.
+func recommendTransforms(profile *DatasetProfile) []Recommendation {
+	recommendations := make([]Recommendation, 0)
+	for _, name := range columnsWithIssue(profile, "skewed") {
+		col := profile.Columns[name]
+
+		direction := "right"
+		if col.Skewness < 0 {
+			direction = "left"
+		}
+
+		action, transform := ActionLogTransform, "a log transform"
+		if min, ok := col.Min.(float64); col.Skewness < 0 || !ok || min <= 0 {
+			action, transform = ActionPowerTransform, "a power transform such as Yeo-Johnson"
+		}
+
+		recommendations = append(recommendations, Recommendation{
+			Type:    RuleTransformSkewed,
+			Columns: []string{name},
+			Action:  action,
+			Message: fmt.Sprintf("Column '%s' is heavily %s-skewed (skewness %.2f) - consider %s",
+				name, direction, col.Skewness, transform),
+		})
+	}
+	return recommendations
+}
+
+func recommendCategorical(profile *DatasetProfile) []Recommendation {
+	recommendations := make([]Recommendation, 0)
+	for _, name := range sortedColumnNames(profile) {
+		col := profile.Columns[name]
+		if col.DataType == "string" && !col.IsCategorical && col.UniqueCount > 0 &&
+			col.UniqueCount <= 100 && float64(col.UniqueCount)/float64(col.Count) <= 0.2 {
+			recommendations = append(recommendations, Recommendation{
+				Type:    RuleTreatAsCategorical,
+				Columns: []string{name},
+				Action:  ActionCategorical,
+				Message: fmt.Sprintf("Column '%s' might benefit from being treated as categorical", name),
+			})
+		}
+	}
+	return recommendations
+}
+
+func recommendDroppingRedundant(profile *DatasetProfile) []Recommendation {
+	recommendations := make([]Recommendation, 0, len(profile.RedundantPairs))
+	for _, pair := range profile.RedundantPairs {
+		recommendations = append(recommendations, Recommendation{
+			Type:    RuleDropRedundant,
+			Columns: []string{pair.Column1, pair.Column2},
+			Action:  ActionDropColumn,
+			Message: fmt.Sprintf("Columns '%s' and '%s' hold the same values - consider dropping one", pair.Column1, pair.Column2),
+		})
+	}
+	return recommendations
+}
+
+func recommendCorrelated(profile *DatasetProfile) []Recommendation {
+	if profile.CorrelationMatrix == nil {
+		return nil
+	}
+
+	recommendations := make([]Recommendation, 0)
+	for _, pair := range profile.CorrelationMatrix.TopPairs {
+		if math.Abs(pair.Correlation) < 0.7 {
+			continue
+		}
+
+		r := Recommendation{Type: RuleCorrelatedColumns, Columns: []string{pair.Column1, pair.Column2}}
+		if pair.Method == CorrelationCramersV {
+			r.Action = ActionReviewDerivation
+			r.Message = fmt.Sprintf("Strong association (Cramér's V %.2f) between '%s' and '%s' - one may largely determine the other",
+				pair.Correlation, pair.Column1, pair.Column2)
+		} else if pair.Correlation > 0 {
+			r.Action = ActionReviewDerivation
+			r.Message = fmt.Sprintf("Strong positive correlation (%.2f) between '%s' and '%s' - consider if one could be derived from the other",
+				pair.Correlation, pair.Column1, pair.Column2)
+		} else {
+			r.Action = ActionKeep
+			r.Message = fmt.Sprintf("Strong negative correlation (%.2f) between '%s' and '%s' - these features may provide complementary information",
+				pair.Correlation, pair.Column1, pair.Column2)
+		}
+		recommendations = append(recommendations, r)
+	}
+	return recommendations
+}
+
+func recommendDeduplication(profile *DatasetProfile) []Recommendation {
+	if profile.DuplicateRows == 0 ||
+		float64(profile.DuplicateRows)/float64(profile.RowCount)*100 <= profile.EffectiveThresholds().DeduplicatePercent {
+		return nil
+	}
+	message := "Dataset contains duplicate rows - consider deduplication"
+	if len(profile.UniqueKey) > 0 {
+		message = fmt.Sprintf("Dataset contains rows repeating the key (%s) - consider deduplication", strings.Join(profile.UniqueKey, ", "))
+	}
+	return []Recommendation{{
+		Type:    RuleDeduplicate,
+		Action:  ActionDeduplicate,
+		Columns: profile.UniqueKey,
+		Message: message,
+	}}
+}
@@ -0,0 +1,146 @@
+package profiler
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func recommendTestProfile() *DatasetProfile {
+	return &DatasetProfile{
+		RowCount:      100,
+		DuplicateRows: 5,
+		QualityScore:  80,
+		Thresholds:    DefaultThresholds(),
+		Columns: map[string]*ColumnProfile{
+			"price": {
+				Name: "price", DataType: "float", Count: 90, MissingCount: 10, Skewness: 3.1, Min: 1.0,
+				QualityIssues: []QualityIssue{{Type: "skewed"}, {Type: "outliers"}},
+			},
+			"city": {Name: "city", DataType: "string", Count: 100, UniqueCount: 15},
+		},
+		RedundantPairs: []RedundantPair{{Column1: "state", Column2: "state_code", MatchPercent: 100}},
+		CorrelationMatrix: &CorrelationMatrix{TopPairs: []CorrelationPair{
+			{Column1: "price", Column2: "tax", Correlation: 0.95},
+			{Column1: "price", Column2: "discount", Correlation: -0.3},
+		}},
+	}
+}
+
+func TestRecommendationEngine(t *testing.T) {
+	recommendations := DefaultRecommendationEngine().Recommend(recommendTestProfile())
+
+	want := []Recommendation{
+		{Type: RuleImputeMissing, Columns: []string{"price"}, Action: ActionImpute, Message: "Consider imputing missing values in 'price' column"},
+		{Type: RuleCheckOutliers, Columns: []string{"price"}, Action: ActionReview, Message: "Check outliers in 'price' column"},
+		{Type: RuleTransformSkewed, Columns: []string{"price"}, Action: ActionLogTransform, Message: "Column 'price' is heavily right-skewed (skewness 3.10) - consider a log transform"},
+		{Type: RuleTreatAsCategorical, Columns: []string{"city"}, Action: ActionCategorical, Message: "Column 'city' might benefit from being treated as categorical"},
+		{Type: RuleDropRedundant, Columns: []string{"state", "state_code"}, Action: ActionDropColumn, Message: "Columns 'state' and 'state_code' hold the same values - consider dropping one"},
+		{Type: RuleCorrelatedColumns, Columns: []string{"price", "tax"}, Action: ActionReviewDerivation, Message: "Strong positive correlation (0.95) between 'price' and 'tax' - consider if one could be derived from the other"},
+		{Type: RuleDeduplicate, Action: ActionDeduplicate, Message: "Dataset contains duplicate rows - consider deduplication"},
+	}
+	if !reflect.DeepEqual(recommendations, want) {
+		t.Errorf("Expected recommendations:\n%v\ngot:\n%v", want, recommendations)
+	}
+}
+
+func TestRecommendTransforms(t *testing.T) {
+	tests := []struct {
+		skewness float64
+		min      interface{}
+		action   string
+		message  string
+	}{
+		{3.1, 1.0, ActionLogTransform, "Column 'x' is heavily right-skewed (skewness 3.10) - consider a log transform"},
+		{3.1, 0.0, ActionPowerTransform, "Column 'x' is heavily right-skewed (skewness 3.10) - consider a power transform such as Yeo-Johnson"},
+		{-2.5, 1.0, ActionPowerTransform, "Column 'x' is heavily left-skewed (skewness -2.50) - consider a power transform such as Yeo-Johnson"},
+	}
+
+	for _, test := range tests {
+		profile := &DatasetProfile{Columns: map[string]*ColumnProfile{
+			"x": {Skewness: test.skewness, Min: test.min, QualityIssues: []QualityIssue{{Type: "skewed"}}},
+		}}
+		got := recommendTransforms(profile)
+		if len(got) != 1 || got[0].Action != test.action || got[0].Message != test.message {
+			t.Errorf("Expected %s: %q, got %v", test.action, test.message, got)
+		}
+	}
+}
+
+func TestRecommendationEngineRules(t *testing.T) {
+	engine := DefaultRecommendationEngine()
+	custom := NewRecommendationRule("wide_table", func(profile *DatasetProfile) []Recommendation {
+		return []Recommendation{{Type: "wide_table", Action: "split", Message: "Split the table"}}
+	})
+	if err := engine.Register(custom); err != nil {
+		t.Fatalf("Failed to register a rule: %v", err)
+	}
+	if err := engine.Register(custom); err == nil {
+		t.Error("Expected an error registering a rule twice")
+	}
+
+	names := engine.RuleNames()
+	if names[len(names)-2] != "wide_table" || names[len(names)-1] != RuleReviewIssues {
+		t.Errorf("Expected the custom rule before review_issues, got %v", names)
+	}
+
+	if err := engine.Disable(names[:len(names)-2]...); err != nil {
+		t.Fatalf("Failed to disable rules: %v", err)
+	}
+	if got := engine.Recommend(recommendTestProfile()); len(got) != 1 || got[0].Message != "Split the table" {
+		t.Errorf("Expected only the custom rule, got %v", got)
+	}
+
+	// With nothing else to say, a low score points at the issues
+	if err := engine.Disable("wide_table"); err != nil {
+		t.Fatalf("Failed to disable rule: %v", err)
+	}
+	if got := engine.Recommend(recommendTestProfile()); len(got) != 1 || got[0].Type != RuleReviewIssues {
+		t.Errorf("Expected the review_issues fallback, got %v", got)
+	}
+	if err := engine.Disable(RuleReviewIssues); err != nil {
+		t.Fatalf("Failed to disable rule: %v", err)
+	}
+	if got := engine.Recommend(recommendTestProfile()); len(got) != 0 {
+		t.Errorf("Expected no recommendations, got %v", got)
+	}
+
+	if err := engine.Enable(RuleDeduplicate); err != nil {
+		t.Fatalf("Failed to enable rule: %v", err)
+	}
+	if got := engine.Recommend(recommendTestProfile()); len(got) != 1 || got[0].Type != RuleDeduplicate {
+		t.Errorf("Expected only deduplication, got %v", got)
+	}
+
+	if err := engine.Disable("nope"); err == nil || !strings.Contains(err.Error(), "unknown recommendation rule: nope") {
+		t.Errorf("Expected an unknown rule error, got %v", err)
+	}
+}
+
+func TestProfileRecommendationOptions(t *testing.T) {
+	path := writeTestFile(t, "data.csv", parallelCSV(50))
+
+	profile, err := ProfileDatasetWithOptions(path, Options{DisabledRecommendations: []string{RuleReviewIssues, RuleTreatAsCategorical}})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	for _, r := range profile.Recommendations {
+		if r.Type == RuleTreatAsCategorical || r.Type == RuleReviewIssues {
+			t.Errorf("Expected disabled rules to make no recommendations, got %v", r)
+		}
+	}
+
+	rule := NewRecommendationRule("row_count", func(profile *DatasetProfile) []Recommendation {
+		return []Recommendation{{Type: "row_count", Action: ActionReview, Message: "Rows profiled"}}
+	})
+	if profile, err = ProfileDatasetWithOptions(path, Options{RecommendationRules: []RecommendationRule{rule}}); err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if last := profile.Recommendations[len(profile.Recommendations)-1]; last.Type != "row_count" {
+		t.Errorf("Expected the custom rule to run last, got %v", profile.Recommendations)
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{DisabledRecommendations: []string{"nope"}}); err == nil {
+		t.Error("Expected an error for an unknown rule")
+	}
+}
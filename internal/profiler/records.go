@@ -0,0 +1,860 @@
+package profiler
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"time"
+)
+
+const typeInferenceSampleSize = 100
+
+func newDatasetProfile(filename string, fileSize int64, format string, header []string) *DatasetProfile {
+	profile := &DatasetProfile{
+		Filename:      filename,
+		FileSize:      fileSize,
+		Format:        format,
+		ColumnCount:   len(header),
+		Columns:       make(map[string]*ColumnProfile),
+		CreatedAt:     time.Now(),
+		QualityIssues: make([]QualityIssue, 0),
+		Thresholds:    DefaultThresholds(),
+	}
+
+	for i, colName := range header {
+		profile.Columns[colName] = &ColumnProfile{
+			Name:          colName,
+			Position:      i,
+			TopValues:     make([]ValueCount, 0),
+			QualityIssues: make([]QualityIssue, 0),
+		}
+	}
+
+	return profile
+}
+
+// columnAccumulator holds the bounded per-column state of a single pass over
+// the records. A counter owned by the caller is marked external and is not
+// fed here. With deferType set the type is only decided once accumulators
+// are merged, for chunks that do not start at the first record.
+//
+// Numbers and dates are only parsed once their formats are set, until
+// which the sample holds every value. Deferred chunks that cannot wait keep
+// numeric statistics under every format in byFormat, and dates read in both
+// orders in byOrder and as every integer layout in byIntegers, for the
+// merge to pick from.
+type columnAccumulator struct {
+	sample     []string
+	counter    *valueCounter
+	external   bool
+	numeric    *numericStats
+	dates      *dateTimeStats
+	text       *textStats
+	blob       *blobTracker
+	examples   *exampleSampler
+	weighted   *weightedStats // nil unless rows are weighted
+	minhash    *minHashSketch // nil without MinHash signatures
+	missing    int
+	encoding   int // values with invalid UTF-8 or replacement characters
+	deferType  bool
+	format     numberFormat
+	formatSet  bool
+	formatNote string
+	byFormat   map[string]*numericStats
+	dateFormat dateFormat
+	dateSet    bool
+	orderFixed bool // the date order is that of --locale
+	orderNote  string
+	byOrder    map[bool]*dateTimeStats // by whether dates are read day first
+	byIntegers map[string]*dateTimeStats
+}
+
+func newColumnAccumulator(counter *valueCounter) *columnAccumulator {
+	acc := &columnAccumulator{
+		sample:  make([]string, 0),
+		counter: counter,
+		numeric: newNumericStats(),
+		dates:   newDateTimeStats(),
+		text:    newTextStats(),
+		blob:    newBlobTracker(),
+	}
+
+	if counter != nil {
+		acc.external = true
+	} else {
+		acc.counter = newValueCounter()
+	}
+
+	return acc
+}
+
+func (a *columnAccumulator) add(value string) {
+	if !a.external {
+		a.counter.add(value)
+	}
+	if a.minhash != nil {
+		a.minhash.add(value)
+	}
+
+	// Long payload columns are only measured from here on
+	if a.blob.observe(value) {
+		a.forget()
+		return
+	}
+	if badEncoding(value) {
+		a.encoding++
+	}
+
+	if a.examples != nil {
+		a.examples.add(value)
+	}
+
+	if a.numeric != nil && a.formatSet {
+		a.numeric.addValue(value, a.format)
+	}
+	for name, stats := range a.byFormat {
+		stats.addValue(value, numberFormatNamed(name))
+	}
+	if a.dates != nil && a.dateSet {
+		if t, ok := a.dateFormat.parse(value); ok {
+			a.dates.add(t)
+		}
+	}
+	if a.byOrder != nil {
+		a.addDeferredDate(value)
+	}
+
+	if len(a.sample) < typeInferenceSampleSize {
+		a.sample = append(a.sample, value)
+		if len(a.sample) == typeInferenceSampleSize && !a.deferType {
+			a.decideNumberFormat(a.sample)
+			a.decideDateFormat(a.sample)
+			a.decideType()
+		}
+	}
+
+	if a.text != nil {
+		a.text.add(value)
+	}
+}
+
+// addWeighted counts a value already added with the weight of its row.
+func (a *columnAccumulator) addWeighted(value string, w float64) {
+	if a.weighted != nil {
+		a.weighted.add(value, w, a.format, a.formatSet)
+	}
+}
+
+// forget drops the per-value state of an opaque column.
+func (a *columnAccumulator) forget() {
+	if !a.external {
+		a.counter.forgetValues()
+	}
+	a.sample = nil
+	a.weighted = nil
+	a.numeric = nil
+	a.byFormat = nil
+	a.dates = nil
+	a.byOrder = nil
+	a.byIntegers = nil
+	a.text = nil
+	a.examples = nil
+}
+
+// decideType stops numeric, timestamp and text tracking once the sample
+// shows the column holds none of them. Numbers and timestamps stay counted
+// in a string column partly made of them, to simulate casting it and to
+// tell whether it mixes types.
+func (a *columnAccumulator) decideType() {
+	dataType := inferDataTypeWith(a.sample, a.format, a.dateFormat)
+	numbers, dates := castShares(a.sample, a.format, a.dateFormat)
+	if dataType != "integer" && dataType != "float" {
+		if dataType != "string" || numbers < mixedTypeShare {
+			a.numeric = nil
+		}
+		if a.weighted != nil {
+			a.weighted.dropNumbers()
+		}
+	}
+	if dataType != "datetime" && (dataType != "string" || dates < mixedTypeShare) {
+		a.dates = nil
+	}
+	if dataType != "string" {
+		a.text = nil
+	}
+}
+
+// conversion simulates casting the values of col to a stricter type.
+func (a *columnAccumulator) conversion(col *ColumnProfile) *Conversion {
+	numbers, fractional, dates := 0, 0, 0
+	if a.numeric != nil {
+		numbers, fractional = a.numeric.count, a.numeric.fractional
+	}
+	if a.dates != nil {
+		dates = a.dates.seconds.count
+	}
+	return conversion(col.DataType, col.Count, numbers, fractional, dates)
+}
+
+// setNumberFormat fixes the format numbers are read in from the start.
+func (a *columnAccumulator) setNumberFormat(format numberFormat) {
+	a.format = format
+	a.formatSet = true
+}
+
+// deferNumberFormat has a deferred chunk keep numeric statistics under
+// every format.
+func (a *columnAccumulator) deferNumberFormat() {
+	if a.formatSet {
+		return
+	}
+	a.byFormat = map[string]*numericStats{"": newNumericStats()}
+	for _, f := range numberFormats {
+		a.byFormat[f.name] = newNumericStats()
+	}
+}
+
+// decideNumberFormat detects the number format from sample, unless it was
+// set, and parses the numbers of the sample held back until now.
+func (a *columnAccumulator) decideNumberFormat(sample []string) {
+	if a.formatSet {
+		return
+	}
+	a.format, a.formatNote = detectNumberFormat(sample)
+	a.formatSet = true
+	if a.weighted != nil {
+		a.weighted.setNumberFormat(a.format)
+	}
+
+	if a.numeric != nil {
+		for _, value := range a.sample {
+			a.numeric.addValue(value, a.format)
+		}
+	}
+}
+
+// deferDateFormat has a deferred chunk keep its dates read in both orders,
+// or the order of --locale, and as every integer layout.
+func (a *columnAccumulator) deferDateFormat() {
+	if a.dateSet {
+		return
+	}
+	a.byOrder = map[bool]*dateTimeStats{a.dateFormat.dayFirst: newDateTimeStats()}
+	if !a.orderFixed {
+		a.byOrder[!a.dateFormat.dayFirst] = newDateTimeStats()
+	}
+	a.byIntegers = make(map[string]*dateTimeStats)
+	for _, layout := range integerLayouts {
+		if layout == compactDateLayout || a.dateFormat.epoch {
+			a.byIntegers[layout] = newDateTimeStats()
+		}
+	}
+}
+
+// addDeferredDate reads value as a timestamp in every way a deferred chunk
+// keeps. Integer layouts only read what the formats of the config do not,
+// as they are tried after them.
+func (a *columnAccumulator) addDeferredDate(value string) {
+	for dayFirst, stats := range a.byOrder {
+		if _, t, ok := a.dateFormat.inOrder(dayFirst).readText(value); ok {
+			stats.add(t)
+		}
+	}
+	if !isDigits(value) {
+		return
+	}
+	if _, _, ok := (dateFormat{custom: a.dateFormat.custom}).readText(value); ok {
+		return
+	}
+	for layout, stats := range a.byIntegers {
+		if t, ok := parseLayout(layout, value); ok {
+			stats.add(t)
+		}
+	}
+}
+
+// decideDateFormat detects the date order, unless --locale set it, and the
+// integer layout from sample, and parses the dates of the sample held back
+// until now.
+func (a *columnAccumulator) decideDateFormat(sample []string) {
+	if a.dateSet {
+		return
+	}
+	if !a.orderFixed {
+		a.dateFormat.dayFirst, a.orderNote = detectDateOrder(sample)
+	}
+	a.dateFormat.integers = a.dateFormat.detectIntegers(sample)
+	a.dateSet = true
+
+	if a.dates != nil {
+		for _, value := range a.sample {
+			if t, ok := a.dateFormat.parse(value); ok {
+				a.dates.add(t)
+			}
+		}
+	}
+}
+
+// datesIn returns the dates seen here read in format, which is the format
+// of the accumulator this one merges into.
+func (a *columnAccumulator) datesIn(format dateFormat) *dateTimeStats {
+	if a.dateSet {
+		return a.dates
+	}
+	if a.byOrder != nil && format.integers == "" {
+		return a.byOrder[format.dayFirst]
+	}
+	if a.byOrder != nil {
+		stats := newDateTimeStats()
+		if text := a.byOrder[format.dayFirst]; text != nil {
+			stats.merge(text)
+		}
+		if integers := a.byIntegers[format.integers]; integers != nil {
+			stats.merge(integers)
+		}
+		return stats
+	}
+
+	// Undecided, so the sample holds every value
+	stats := newDateTimeStats()
+	for _, value := range a.sample {
+		if t, ok := format.parse(value); ok {
+			stats.add(t)
+		}
+	}
+	return stats
+}
+
+// numericIn returns the numeric statistics of the values seen here read in
+// format, which is the format of the accumulator this one merges into.
+func (a *columnAccumulator) numericIn(format numberFormat) *numericStats {
+	if a.formatSet {
+		return a.numeric
+	}
+	if a.byFormat != nil {
+		return a.byFormat[format.name]
+	}
+
+	// Undecided, so the sample holds every value
+	stats := newNumericStats()
+	for _, value := range a.sample {
+		stats.addValue(value, format)
+	}
+	return stats
+}
+
+// merge folds in o, which accumulated the records that follow the ones seen
+// here.
+func (a *columnAccumulator) merge(o *columnAccumulator) {
+	a.missing += o.missing
+	a.encoding += o.encoding
+
+	if a.minhash != nil {
+		a.minhash.merge(o.minhash)
+	}
+
+	a.blob.merge(o.blob)
+	if a.blob.opaque {
+		a.forget()
+		o.forget()
+	}
+	if !a.external {
+		a.counter.merge(o.counter)
+	}
+	if a.blob.opaque {
+		return
+	}
+
+	if a.examples != nil && o.examples != nil {
+		a.examples.merge(o.examples)
+	}
+
+	if wanted := typeInferenceSampleSize - len(a.sample); wanted > 0 {
+		if wanted > len(o.sample) {
+			wanted = len(o.sample)
+		}
+		if len(a.sample)+wanted == typeInferenceSampleSize && !a.deferType {
+			sample := append(append([]string(nil), a.sample...), o.sample[:wanted]...)
+			a.decideNumberFormat(sample)
+			a.decideDateFormat(sample)
+		}
+		a.sample = append(a.sample, o.sample[:wanted]...)
+		if len(a.sample) == typeInferenceSampleSize && !a.deferType {
+			a.decideType()
+		}
+	}
+
+	// Until the format is set, the sample holds the values of both
+	if a.numeric != nil && a.formatSet {
+		if stats := o.numericIn(a.format); stats != nil {
+			a.numeric.merge(stats)
+		}
+	}
+	if a.dates != nil && a.dateSet {
+		if stats := o.datesIn(a.dateFormat); stats != nil {
+			a.dates.merge(stats)
+		}
+	}
+	if a.text != nil && o.text != nil {
+		a.text.merge(o.text)
+	}
+}
+
+// recordAccumulator is the state of a single pass over records: the column
+// accumulators plus row counts, duplicate detection and digests. Passes over
+// consecutive parts of a source can be merged.
+type recordAccumulator struct {
+	header       []string
+	columns      map[string]*columnAccumulator
+	byIndex      []*columnAccumulator
+	rows         *duplicateCounter // repeated rows, or key values with a unique key
+	digest       *digestAccumulator
+	nulls        *nullTracker
+	pairs        *pairTracker
+	correlations *correlationRows
+	nullIndexes  []int
+	exact        *exactRecords   // nil when the dataset is not small
+	preview      *previewRows    // nil without --preview
+	weightIndex  int             // column of the row weights, -1 when unweighted
+	keyIndexes   []int           // columns of the unique key, nil to compare whole rows
+	nullValues   map[string]bool // values read as missing besides empty ones, nil for none
+	windows      *timeWindows    // nil without a time column
+	totalWeight  float64
+	badWeights   int // rows whose weight is missing or invalid
+	rowCount     int
+	missingCells int
+	opts         Options
+}
+
+// newRecordAccumulator starts a pass over records with the given header.
+// Columns present in counted have their non-empty values tallied by the
+// caller.
+func newRecordAccumulator(header []string, counted map[string]*valueCounter, opts Options) *recordAccumulator {
+	r := &recordAccumulator{
+		header:       header,
+		columns:      make(map[string]*columnAccumulator),
+		byIndex:      make([]*columnAccumulator, len(header)),
+		rows:         newDuplicateCounter(maxTrackedRows),
+		digest:       newDigestAccumulator(header),
+		nulls:        newNullTracker(header),
+		pairs:        newPairTracker(header, DefaultThresholds()),
+		correlations: newCorrelationRows(header, opts.correlationRows()),
+		weightIndex:  -1,
+		opts:         opts,
+	}
+	if opts.ExactRows > 0 {
+		r.exact = newExactRecords(opts.ExactRows)
+	}
+	r.preview = newPreviewRows(header, opts)
+	if len(opts.NullValues) > 0 {
+		r.nullValues = make(map[string]bool, len(opts.NullValues))
+		for _, value := range opts.NullValues {
+			r.nullValues[value] = true
+		}
+	}
+
+	for i, colName := range header {
+		acc, ok := r.columns[colName]
+		if !ok {
+			acc = newColumnAccumulator(counted[colName])
+			if name := opts.numberFormat(); name != "" {
+				acc.setNumberFormat(numberFormatNamed(name))
+			}
+			acc.dateFormat, acc.orderFixed = opts.dateFormat(colName)
+			if opts.Examples > 0 && !opts.redacted(colName) {
+				acc.examples = newExampleSampler(opts.Examples)
+			}
+			if opts.MinHash > 0 {
+				acc.minhash = newMinHashSketch(opts.MinHashSalt, opts.MinHash)
+			}
+			r.columns[colName] = acc
+		}
+		r.byIndex[i] = acc
+	}
+
+	if len(opts.UniqueKey) > 0 {
+		// A missing column is reported by profileRecords
+		if indexes, err := keyIndexes(header, opts.UniqueKey); err == nil {
+			r.keyIndexes = indexes
+			r.rows = newValueDuplicateCounter(maxTrackedRows)
+		}
+	}
+
+	if opts.TimeColumn != "" {
+		r.windows = newTimeWindows(header, opts)
+	}
+
+	if opts.WeightColumn != "" {
+		for i, colName := range header {
+			if colName == opts.WeightColumn {
+				r.weightIndex = i
+				break
+			}
+		}
+		for colName, acc := range r.columns {
+			if colName != opts.WeightColumn {
+				acc.weighted = newWeightedStats()
+			}
+		}
+	}
+
+	return r
+}
+
+// deferTypes leaves type decisions to the merge, for a pass that does not
+// start at the first record.
+func (r *recordAccumulator) deferTypes() {
+	for _, acc := range r.columns {
+		acc.deferType = true
+		acc.deferNumberFormat()
+		acc.deferDateFormat()
+	}
+}
+
+func (r *recordAccumulator) add(record []string) {
+	record = r.withNulls(record)
+	r.rowCount++
+	hash := r.digest.addRecord(record)
+	if r.keyIndexes != nil {
+		r.rows.addValue(recordKey(record, r.keyIndexes))
+	} else {
+		r.rows.add(hash)
+	}
+	if r.exact != nil && !r.exact.add(record) {
+		r.exact = nil
+	}
+	if r.preview != nil {
+		r.preview.add(record)
+	}
+	if r.windows != nil {
+		r.windows.add(record)
+	}
+
+	weight, weighted := r.weight(record)
+
+	r.nullIndexes = r.nullIndexes[:0]
+	for i, value := range record {
+		if i >= len(r.header) {
+			continue
+		}
+
+		if value == "" {
+			r.byIndex[i].missing++
+			r.missingCells++
+			r.nullIndexes = append(r.nullIndexes, i)
+			continue
+		}
+
+		r.byIndex[i].add(value)
+		if weighted {
+			r.byIndex[i].addWeighted(value, weight)
+		}
+	}
+
+	r.nulls.add(record, r.nullIndexes)
+	r.pairs.add(record)
+	r.correlations.add(record, hash)
+}
+
+// withNulls returns record with its null values emptied, copying it rather
+// than changing the caller's record.
+func (r *recordAccumulator) withNulls(record []string) []string {
+	copied := false
+	for i, value := range record {
+		if !r.nullValues[value] {
+			continue
+		}
+		if !copied {
+			record = slices.Clone(record)
+			copied = true
+		}
+		record[i] = ""
+	}
+	return record
+}
+
+// weight reads the weight of record, reporting false when rows are not
+// weighted. A missing or invalid weight counts as 0, which leaves the row
+// out of the weighted estimates but still lists its values.
+func (r *recordAccumulator) weight(record []string) (float64, bool) {
+	if r.weightIndex < 0 {
+		return 0, false
+	}
+
+	w, ok := 0.0, false
+	if r.weightIndex < len(record) {
+		w, ok = parseWeight(record[r.weightIndex])
+	}
+	if !ok {
+		r.badWeights++
+		return 0, true
+	}
+	r.totalWeight += w
+	return w, true
+}
+
+// merge folds in o, which accumulated the records that follow the ones seen
+// here.
+func (r *recordAccumulator) merge(o *recordAccumulator) {
+	for colName, acc := range r.columns {
+		acc.merge(o.columns[colName])
+	}
+
+	r.rows.merge(o.rows)
+	r.digest.merge(o.digest)
+	r.nulls.merge(o.nulls)
+	r.pairs.merge(o.pairs)
+	r.correlations.merge(o.correlations)
+	if r.exact != nil && (o.exact == nil || !r.exact.merge(o.exact)) {
+		r.exact = nil
+	}
+	if r.preview != nil {
+		r.preview.merge(o.preview)
+	}
+	if r.windows != nil {
+		r.windows.merge(o.windows)
+	}
+	r.totalWeight += o.totalWeight
+	r.badWeights += o.badWeights
+	r.rowCount += o.rowCount
+	r.missingCells += o.missingCells
+}
+
+// profileRows profiles the records returned by next, sampling them first when
+// opts asks for a sample, and scores the result.
+func profileRows(profile *DatasetProfile, header []string, next func() ([]string, error), opts Options) error {
+	next = untilDone(profile, next, opts)
+
+	var sampler *rowSampler
+	if opts.sampling() {
+		sampler = newRowSampler(next, opts)
+		next = sampler.next
+		// A sample is not the whole dataset, however small
+		opts.ExactRows = 0
+	}
+
+	if err := profileRecords(profile, header, next, nil, opts); err != nil {
+		return err
+	}
+
+	if sampler != nil {
+		sampler.apply(profile)
+	}
+
+	profile.QualityScore = opts.scoring().Score(profile)
+
+	return nil
+}
+
+// profileRecords consumes records from next until it returns io.EOF and fills
+// in the row-level and column-level statistics of profile in a single pass
+// with bounded memory. Columns present in counted have their non-empty values
+// tallied by the caller, which must have filled the counters by the time next
+// returns io.EOF.
+func profileRecords(profile *DatasetProfile, header []string, next func() ([]string, error), counted map[string]*valueCounter, opts Options) error {
+	acc := newRecordAccumulator(header, counted, opts)
+	if opts.WeightColumn != "" && acc.weightIndex < 0 {
+		return fmt.Errorf("weight column %s not found", opts.WeightColumn)
+	}
+	if _, err := keyIndexes(header, opts.UniqueKey); err != nil {
+		return err
+	}
+	if acc.windows != nil && acc.windows.timeIndex < 0 {
+		return fmt.Errorf("time column %s not found", opts.TimeColumn)
+	}
+	progress := opts.tracker(profile.Filename)
+
+	for {
+		record, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if opts.Rows != nil {
+			if err := opts.Rows(header, record); err != nil {
+				return err
+			}
+		}
+		acc.add(record)
+		progress.row()
+	}
+
+	acc.finish(profile)
+
+	return nil
+}
+
+// finish fills in the row-level and column-level statistics of profile.
+func (r *recordAccumulator) finish(profile *DatasetProfile) {
+	// Repeats are counted by hash rather than from an estimate of the
+	// distinct rows, which off by a fraction of a percent would read as
+	// thousands of duplicates
+	duplicateRows := r.rows.duplicates()
+	switch {
+	case r.rows.estimated() && r.keyIndexes != nil:
+		profile.Notes = append(profile.Notes, fmt.Sprintf(
+			"Duplicate rows estimated from 1 in %d of the distinct keys, which the listed keys come from: more than %d distinct keys", 1<<r.rows.shift, maxTrackedRows))
+	case r.rows.estimated():
+		profile.Notes = append(profile.Notes, fmt.Sprintf(
+			"Duplicate rows estimated from 1 in %d of the distinct rows: more than %d distinct rows", 1<<r.rows.shift, maxTrackedRows))
+	}
+
+	profile.RowCount = r.rowCount
+	profile.MissingCells = r.missingCells
+	profile.DuplicateRows = duplicateRows
+	profile.HistogramBinning = r.opts.histogramBinning()
+	r.opts.Outliers.apply(&profile.Thresholds)
+	r.digest.apply(profile)
+
+	if r.exact != nil {
+		profile.Exact = true
+		profile.DuplicateGroups = r.exact.duplicates(r.header, r.keyIndexes)
+		profile.Notes = append(profile.Notes, fmt.Sprintf(
+			"Fewer than %d rows: exact mode with complete value and duplicate listings", r.opts.ExactRows))
+	}
+	if r.preview != nil {
+		r.preview.apply(profile)
+	}
+	if r.weightIndex >= 0 {
+		r.applyWeights(profile)
+	}
+	if r.keyIndexes != nil {
+		profile.UniqueKey = r.opts.UniqueKey
+		profile.DuplicateKeys = duplicateKeys(r.rows)
+	}
+
+	source := r.opts.tracker(profile.Filename).source
+	indexes := make(map[string]int, len(r.header))
+	for i := len(r.header) - 1; i >= 0; i-- {
+		indexes[r.header[i]] = i
+	}
+
+	for colName, acc := range r.columns {
+		col := profile.Columns[colName]
+		col.MissingCount = acc.missing
+
+		var cond *nullConditionCandidate
+		if acc.missing > 0 && acc.missing < r.rowCount {
+			cond = r.nulls.condition(indexes[colName], acc.missing, r.rowCount, r.header, profile.Thresholds)
+		}
+		col.Nullability = inferNullability(acc.missing, r.rowCount, cond, profile.Thresholds)
+		if r.opts.redacted(colName) {
+			col.ExamplesRedacted = true
+		}
+		if acc.minhash != nil {
+			col.MinHash = acc.minhash.signature()
+		}
+
+		acc.blob.decide()
+		if acc.blob.opaque {
+			if !acc.external {
+				acc.counter.forgetValues()
+			}
+			finishOpaqueColumn(col, acc.blob, acc.counter.uniqueCount())
+			detectQualityIssues(col, profile.RowCount)
+			publishColumnDone(source, colName)
+			continue
+		}
+
+		col.Count = acc.blob.count
+
+		acc.decideNumberFormat(acc.sample)
+		if acc.formatNote != "" {
+			col.Notes = append(col.Notes, acc.formatNote)
+		}
+		acc.decideDateFormat(acc.sample)
+		col.DataType = inferDataTypeWith(acc.sample, acc.format, acc.dateFormat)
+		col.IsNumeric = col.DataType == "integer" || col.DataType == "float"
+		col.IsDateTime = col.DataType == "datetime"
+		col.Conversion = acc.conversion(col)
+		col.ParseErrors = acc.parseErrors(col)
+		col.MixedTypes = acc.mixedTypes(col, profile.Thresholds, r.opts.redacted(colName))
+
+		col.UniqueCount = acc.counter.uniqueCount()
+		if col.UniqueCount > col.Count {
+			col.UniqueCount = col.Count
+		}
+		col.IsCategorical = profile.Thresholds.isCategorical(col.UniqueCount, profile.RowCount)
+		col.IsUnique = col.UniqueCount == col.Count
+
+		topValues := r.opts.topValues()
+		if profile.Exact {
+			topValues = col.UniqueCount
+		}
+		applyValueCounts(col, acc.counter, topValues)
+		if acc.examples != nil {
+			col.Examples = acc.examples.values
+		}
+
+		if acc.counter.approximate() {
+			col.Notes = append(col.Notes, fmt.Sprintf(
+				"More than %d distinct values: unique count estimated with HyperLogLog, top value counts are upper bounds, bottom and rare values not counted", maxTrackedValues))
+		}
+
+		if col.IsNumeric && acc.format.name != "" {
+			col.NumberFormat = acc.format.name
+		}
+		if col.IsNumeric && acc.numeric != nil {
+			acc.numeric.apply(col, r.opts.histogram(), profile.Thresholds)
+			if r.opts.Robust {
+				acc.numeric.applyRobust(col)
+			}
+		}
+		if col.IsDateTime && acc.dates != nil {
+			col.DateOrder = acc.dateFormat.orderName()
+			col.DateFormat = acc.dateFormat.layoutOf(acc.sample)
+			acc.dates.apply(col)
+		}
+		if col.IsDateTime && acc.orderNote != "" {
+			col.Notes = append(col.Notes, acc.orderNote)
+		}
+		if col.DataType == "string" && acc.text != nil {
+			acc.text.apply(col, acc.counter)
+		}
+		if acc.weighted != nil {
+			acc.weighted.apply(col, r.opts.histogram().buckets, topValues)
+			if acc.weighted.truncated {
+				col.Notes = append(col.Notes, fmt.Sprintf(
+					"More than %d distinct values: weighted top values are lower bounds", maxTrackedValues))
+			}
+		}
+
+		detectQualityIssues(col, profile.RowCount)
+		publishColumnDone(source, colName)
+	}
+
+	if r.windows != nil {
+		r.windows.apply(profile, r.header, r.opts.TimeColumn)
+	}
+
+	profile.RedundantPairs = r.pairs.redundant(r.header, profile.Thresholds)
+	r.correlations.apply(profile, r.header)
+
+	collectDatasetQualityIssues(profile)
+}
+
+// applyWeights records the weight column of profile and notes which
+// statistics are weighted.
+func (r *recordAccumulator) applyWeights(profile *DatasetProfile) {
+	profile.WeightColumn = r.opts.WeightColumn
+	profile.WeightTotal = r.totalWeight
+
+	note := fmt.Sprintf("Means, standard deviations, percentiles, histograms and top values weighted by %s (total weight %s)",
+		r.opts.WeightColumn, formatWeight(r.totalWeight))
+	if r.badWeights > 0 {
+		note += fmt.Sprintf("; rows with a missing or invalid weight left out: %d", r.badWeights)
+	}
+	profile.Notes = append(profile.Notes, note)
+}
+
+// formatWeight prints a total weight without decimals when it is whole.
+func formatWeight(w float64) string {
+	if w == math.Trunc(w) && math.Abs(w) < 1e15 {
+		return fmt.Sprintf("%.0f", w)
+	}
+	return fmt.Sprintf("%.2f", w)
+}
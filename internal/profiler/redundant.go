@@ -0,0 +1,116 @@
+package profiler
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	maxRedundantColumns = 30   // columns compared pairwise, in header order
+	minRedundantRows    = 10   // rows with a value in either column needed to judge a pair
+	redundantPruneRows  = 1000 // rows after which clearly different pairs stop being compared
+)
+
+// RedundantPair is two columns whose values match on almost every row.
+type RedundantPair struct {
+	Column1      string
+	Column2      string
+	MatchPercent float64 // share of the rows with a value in either column where both match
+}
+
+// pairTracker compares every pair of the first maxRedundantColumns columns
+// row by row. Values match when equal ignoring case and surrounding space;
+// rows missing both values are not counted. Most pairs differ from the first
+// rows, so a pair that mismatches more than the threshold allows after
+// redundantPruneRows rows is dropped to keep the cost down.
+type pairTracker struct {
+	indexes []int // header indexes of the compared columns
+	pairs   []*columnPair
+	maxMiss float64 // mismatch share beyond which a pair is dropped
+}
+
+type columnPair struct {
+	i, j    int // positions in indexes
+	rows    int
+	matches int
+	dropped bool
+}
+
+func newPairTracker(header []string, thresholds Thresholds) *pairTracker {
+	t := &pairTracker{maxMiss: (100 - thresholds.RedundantPercent) / 100}
+
+	seen := make(map[string]bool)
+	for i, name := range header {
+		if seen[name] || len(t.indexes) == maxRedundantColumns {
+			continue
+		}
+		seen[name] = true
+		t.indexes = append(t.indexes, i)
+	}
+
+	for i := range t.indexes {
+		for j := i + 1; j < len(t.indexes); j++ {
+			t.pairs = append(t.pairs, &columnPair{i: i, j: j})
+		}
+	}
+
+	return t
+}
+
+func (t *pairTracker) add(record []string) {
+	for _, p := range t.pairs {
+		if p.dropped {
+			continue
+		}
+
+		a, b := recordValue(record, t.indexes[p.i]), recordValue(record, t.indexes[p.j])
+		if a == "" && b == "" {
+			continue
+		}
+
+		p.rows++
+		if a == b || strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b)) {
+			p.matches++
+		} else if p.rows >= redundantPruneRows && float64(p.rows-p.matches) > t.maxMiss*float64(p.rows) {
+			p.dropped = true
+		}
+	}
+}
+
+func recordValue(record []string, index int) string {
+	if index < len(record) {
+		return record[index]
+	}
+	return ""
+}
+
+func (t *pairTracker) merge(o *pairTracker) {
+	for k, p := range t.pairs {
+		other := o.pairs[k]
+		p.rows += other.rows
+		p.matches += other.matches
+		p.dropped = p.dropped || other.dropped
+	}
+}
+
+// redundant returns the pairs matching on at least the threshold share of
+// rows, closest matches first.
+func (t *pairTracker) redundant(header []string, thresholds Thresholds) []RedundantPair {
+	result := make([]RedundantPair, 0)
+	for _, p := range t.pairs {
+		if p.dropped || p.rows < minRedundantRows {
+			continue
+		}
+		percent := float64(p.matches) / float64(p.rows) * 100
+		if percent >= thresholds.RedundantPercent {
+			result = append(result, RedundantPair{
+				Column1:      header[t.indexes[p.i]],
+				Column2:      header[t.indexes[p.j]],
+				MatchPercent: percent,
+			})
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].MatchPercent > result[j].MatchPercent })
+	return result
+}
@@ -0,0 +1,83 @@
+package profiler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPairTrackerRedundant(t *testing.T) {
+	header := []string{"state", "state_copy", "code", "note"}
+	tracker := newPairTracker(header, DefaultThresholds())
+	for i := 0; i < 40; i++ {
+		state := fmt.Sprintf("State%d", i%4)
+		copied := strings.ToUpper(state) + " "
+		if i == 7 {
+			copied = "other"
+		}
+		note := ""
+		if i < 5 {
+			note = state
+		}
+		tracker.add([]string{state, copied, fmt.Sprint(i), note})
+	}
+
+	pairs := tracker.redundant(header, DefaultThresholds())
+	if len(pairs) != 1 {
+		t.Fatalf("Expected one redundant pair, got %v", pairs)
+	}
+	if p := pairs[0]; p.Column1 != "state" || p.Column2 != "state_copy" || p.MatchPercent != 97.5 {
+		t.Errorf("Expected state and state_copy matching on 97.5%% of rows, got %+v", p)
+	}
+}
+
+func TestPairTrackerPruneAndMerge(t *testing.T) {
+	header := []string{"a", "b"}
+	first, second := newPairTracker(header, DefaultThresholds()), newPairTracker(header, DefaultThresholds())
+	for i := 0; i < redundantPruneRows; i++ {
+		first.add([]string{"x", "x"})
+		second.add([]string{"x", "x"})
+	}
+	first.merge(second)
+	if pairs := first.redundant(header, DefaultThresholds()); len(pairs) != 1 || pairs[0].MatchPercent != 100 {
+		t.Errorf("Expected identical columns after merging, got %v", pairs)
+	}
+
+	differ := newPairTracker(header, DefaultThresholds())
+	for i := 0; i < redundantPruneRows; i++ {
+		differ.add([]string{"x", fmt.Sprint(i % 2)})
+	}
+	if !differ.pairs[0].dropped {
+		t.Error("Expected a clearly different pair to be dropped")
+	}
+}
+
+func TestProfileRedundantColumns(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("id,state,state_code\n")
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&b, "%d,s%d,s%d\n", i, i%5, i%5)
+	}
+	path := filepath.Join(t.TempDir(), "states.csv")
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	profile, err := ProfileDataset(path)
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	if len(profile.RedundantPairs) != 1 || profile.RedundantPairs[0].Column1 != "state" {
+		t.Fatalf("Expected state and state_code to be redundant, got %v", profile.RedundantPairs)
+	}
+
+	found := false
+	for _, issue := range profile.QualityIssues {
+		found = found || issue.Description == "Columns 'state' and 'state_code' are redundant: values match on 100.0% of rows"
+	}
+	if !found {
+		t.Errorf("Expected a redundant columns issue, got %v", profile.QualityIssues)
+	}
+}
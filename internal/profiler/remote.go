@@ -0,0 +1,91 @@
+package profiler
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/remote"
+)
+
+// profileRemote streams an object from HTTP(S), S3, GCS or Azure Blob
+// Storage through the profiler without a temporary file. Parquet is read
+// with range requests because its metadata sits at the end of the file.
+func profileRemote(rawURL string, opts Options) (*DatasetProfile, error) {
+	objectPath := remote.Path(rawURL)
+
+	switch strings.ToLower(filepath.Ext(objectPath)) {
+	case ".sqlite", ".sqlite3", ".db":
+		return nil, fmt.Errorf("SQLite databases must be local files: %s", rawURL)
+	case ".xlsx", ".xlsm":
+		return nil, fmt.Errorf("Excel workbooks must be local files: %s", rawURL)
+	}
+	if IsArchive(objectPath) {
+		return nil, fmt.Errorf("zip and tar archives must be local files: %s", rawURL)
+	}
+
+	var checksum *remote.Checksum
+	if opts.Checksum != "" {
+		parsed, err := remote.ParseChecksum(opts.Checksum)
+		if err != nil {
+			return nil, err
+		}
+		checksum = &parsed
+	}
+
+	format := fileFormat(objectPath, opts)
+	switch format {
+	case "parquet":
+		if checksum != nil {
+			return nil, fmt.Errorf("--checksum is not supported for Parquet, which is read in ranges: %s", rawURL)
+		}
+		r, info, err := remote.NewReaderAt(opts.context(), rawURL)
+		if err != nil {
+			return nil, err
+		}
+		return profileParquetReader(r, info.Name, info.Size, opts)
+	case "orc":
+		if checksum != nil {
+			return nil, fmt.Errorf("--checksum is not supported for ORC, which is read in ranges: %s", rawURL)
+		}
+		r, info, err := remote.NewReaderAt(opts.context(), rawURL)
+		if err != nil {
+			return nil, err
+		}
+		return profileORCReader(r, info.Name, info.Size, opts)
+	case "json":
+		return nil, fmt.Errorf("JSON is not supported for remote sources yet: %s", rawURL)
+	}
+
+	// One byte past the range tells a source cut at the limit from one that
+	// ends exactly there. The range counts decompressed bytes, so compressed
+	// objects are streamed until enough has been decompressed.
+	limit := int64(0)
+	if opts.MaxBytes > 0 && compressionExt(objectPath) == "" {
+		limit = opts.MaxBytes + 1
+	}
+
+	body, info, err := remote.Open(rawURL, remote.OpenOptions{Limit: limit, Checksum: checksum, Context: opts.context()})
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	size := info.Size
+	if size < 0 {
+		size = 0
+	}
+
+	switch format {
+	case FormatTSV:
+		return profileDelimited(body, info.Name, size, '\t', "TSV", opts)
+	case FormatJSONL:
+		return profileJSONL(body, info.Name, size, opts)
+	case FormatXML:
+		return profileXML(body, info.Name, size, opts)
+	case "avro":
+		return profileAvro(body, info.Name, size, opts)
+	default:
+		return profileDelimited(body, info.Name, size, 0, "CSV", opts)
+	}
+}
@@ -0,0 +1,162 @@
+package profiler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func serveDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestProfileRemote(t *testing.T) {
+	base := serveDir(t, map[string]string{
+		"data.csv":     "a,b\n1,x\n2,y\n3,z\n",
+		"events.jsonl": "{\"a\": 1}\n{\"a\": 2}\n",
+	})
+
+	profile, err := ProfileDataset(base + "/data.csv?token=abc")
+	if err != nil {
+		t.Fatalf("Failed to profile remote CSV: %v", err)
+	}
+	if profile.Filename != "data.csv" || profile.Format != "CSV" || profile.RowCount != 3 {
+		t.Errorf("Expected 3 CSV rows from data.csv, got %s %s %d", profile.Filename, profile.Format, profile.RowCount)
+	}
+	if profile.FileSize != 16 {
+		t.Errorf("Expected a file size of 16, got %d", profile.FileSize)
+	}
+
+	profile, err = ProfileDataset(base + "/events.jsonl")
+	if err != nil {
+		t.Fatalf("Failed to profile remote JSONL: %v", err)
+	}
+	if profile.Format != "JSONL" || profile.RowCount != 2 {
+		t.Errorf("Expected 2 JSONL rows, got %s %d", profile.Format, profile.RowCount)
+	}
+
+	if _, err := ProfileDataset(base + "/missing.csv"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected a 404 error for a missing object, got %v", err)
+	}
+}
+
+func TestProfileRemoteRange(t *testing.T) {
+	var content strings.Builder
+	content.WriteString("id,value\n")
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&content, "%d,%d\n", i, i*7)
+	}
+
+	var requested string
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "big.csv"), []byte(content.String()), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	files := http.FileServer(http.Dir(dir))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.Header.Get("Range")
+		files.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	profile, err := ProfileDatasetWithOptions(server.URL+"/big.csv", Options{MaxBytes: 100})
+	if err != nil {
+		t.Fatalf("Failed to profile remote CSV range: %v", err)
+	}
+
+	if requested != "bytes=0-100" {
+		t.Errorf("Expected a range request for the first 101 bytes, got %q", requested)
+	}
+	if profile.RowCount == 0 || profile.RowCount >= 1000 {
+		t.Errorf("Expected a partial row count, got %d", profile.RowCount)
+	}
+	if profile.FileSize != int64(content.Len()) {
+		t.Errorf("Expected the full object size %d, got %d", content.Len(), profile.FileSize)
+	}
+	if len(profile.Notes) == 0 || !strings.Contains(profile.Notes[len(profile.Notes)-1], "--range") {
+		t.Errorf("Expected a --range note, got %v", profile.Notes)
+	}
+}
+
+func TestProfileRemoteParquet(t *testing.T) {
+	rows := make([]parquetTestRow, 0, 50)
+	for i := 0; i < 50; i++ {
+		rows = append(rows, parquetTestRow{ID: int64(i + 1), Amount: float64(i), Country: "US"})
+	}
+
+	data, err := os.ReadFile(writeTestParquet(t, rows))
+	if err != nil {
+		t.Fatalf("Failed to read Parquet file: %v", err)
+	}
+	base := serveDir(t, map[string]string{"test.parquet": string(data)})
+
+	profile, err := ProfileDataset(base + "/test.parquet")
+	if err != nil {
+		t.Fatalf("Failed to profile remote Parquet: %v", err)
+	}
+	if profile.Format != "Parquet" || profile.RowCount != 50 || profile.FileSize != int64(len(data)) {
+		t.Errorf("Expected 50 Parquet rows of %d bytes, got %s %d %d", len(data), profile.Format, profile.RowCount, profile.FileSize)
+	}
+
+	if _, err := ProfileDatasetWithOptions(base+"/test.parquet", Options{MaxBytes: 100}); err == nil {
+		t.Error("Expected an error for --range with Parquet")
+	}
+}
+
+func TestLineLimitReader(t *testing.T) {
+	tests := []struct {
+		content   string
+		limit     int64
+		want      string
+		truncated bool
+	}{
+		{"a,b\n1,2\n3,4\n", 10, "a,b\n1,2\n", true},
+		{"a,b\n1,2\n3,4\n", 8, "a,b\n1,2\n", true},
+		{"a,b\n1,2\n3,4\n", 12, "a,b\n1,2\n3,4\n", false},
+		{"a,b\n1,2\n3,4", 11, "a,b\n1,2\n3,4", false},
+		{"a,b\n1,2\n3,4", 10, "a,b\n1,2\n", true},
+	}
+
+	for _, tt := range tests {
+		reader := newLineLimitReader(strings.NewReader(tt.content), tt.limit)
+		got, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("ReadAll failed: %v", err)
+		}
+		if string(got) != tt.want || reader.truncated != tt.truncated {
+			t.Errorf("limit %d of %q: expected %q (truncated %v), got %q (truncated %v)",
+				tt.limit, tt.content, tt.want, tt.truncated, got, reader.truncated)
+		}
+	}
+}
+
+func TestProfileMaxBytesLocal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.csv")
+	if err := os.WriteFile(path, []byte("a,b\n1,x\n2,y\n3,z\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	profile, err := ProfileDatasetWithOptions(path, Options{MaxBytes: 10})
+	if err != nil {
+		t.Fatalf("Failed to profile with MaxBytes: %v", err)
+	}
+	if profile.RowCount != 1 {
+		t.Errorf("Expected 1 complete row in the first 10 bytes, got %d", profile.RowCount)
+	}
+}
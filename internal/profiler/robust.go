@@ -0,0 +1,90 @@
+package profiler
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	// RobustTrim is the fraction of values cut from each end for the
+	// trimmed mean and clamped for the winsorized standard deviation.
+	RobustTrim = 0.05
+
+	// robustGridPoints is the number of evenly spaced quantiles that stand in
+	// for the values once a t-digest holds them.
+	robustGridPoints = 1000
+)
+
+// RobustStats are location and spread estimates that a few extreme values
+// cannot move far, reported next to the mean and standard deviation with
+// --robust.
+type RobustStats struct {
+	Trim             float64 // fraction of values trimmed or winsorized at each end
+	TrimmedMean      float64 // mean of the values left after trimming
+	WinsorizedStdDev float64 // standard deviation after clamping the trimmed values to the nearest kept one
+	MAD              float64 // median absolute deviation from the median
+}
+
+// applyRobust fills in the robust statistics of col, after apply has set
+// its median.
+func (s *numericStats) applyRobust(col *ColumnProfile) {
+	if s.count == 0 {
+		return
+	}
+
+	if s.digest != nil {
+		col.Notes = append(col.Notes, fmt.Sprintf(
+			"Trimmed mean, winsorized standard deviation and MAD estimated from a t-digest over %d values", s.count))
+	}
+	col.Robust = robustStats(s.sorted(), col.Median, RobustTrim)
+}
+
+// sorted returns the values in order, or evenly spaced quantiles of the
+// t-digest once it holds them.
+func (s *numericStats) sorted() []float64 {
+	if s.digest == nil {
+		sorted := append([]float64(nil), s.exact...)
+		sort.Float64s(sorted)
+		return sorted
+	}
+
+	// Evenly spaced quantiles are an equally weighted sample of the
+	// distribution, and already sorted
+	grid := make([]float64, robustGridPoints)
+	for i := range grid {
+		grid[i] = s.digest.quantile((float64(i) + 0.5) / robustGridPoints)
+	}
+	return grid
+}
+
+// robustStats computes the robust statistics of sorted values with the
+// given median, trimming the lowest and highest trim of them.
+func robustStats(sorted []float64, median, trim float64) *RobustStats {
+	n := len(sorted)
+	k := int(trim * float64(n))
+	if 2*k >= n {
+		k = (n - 1) / 2
+	}
+
+	kept := sorted[k : n-k]
+	sum := 0.0
+	for _, v := range kept {
+		sum += v
+	}
+	trimmedMean := sum / float64(len(kept))
+
+	low, high := kept[0], kept[len(kept)-1]
+	mean := (float64(k)*(low+high) + sum) / float64(n)
+	variance := float64(k) * ((low-mean)*(low-mean) + (high-mean)*(high-mean))
+	for _, v := range kept {
+		variance += (v - mean) * (v - mean)
+	}
+
+	return &RobustStats{
+		Trim:             trim,
+		TrimmedMean:      trimmedMean,
+		WinsorizedStdDev: math.Sqrt(variance / float64(n)),
+		MAD:              medianAbsoluteDeviation(sorted, median),
+	}
+}
@@ -0,0 +1,174 @@
+package profiler
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+)
+
+const (
+	SampleHead       = "head"
+	SampleRandom     = "random"
+	SampleSystematic = "systematic"
+)
+
+func (o Options) sampling() bool {
+	return o.SampleSize > 0
+}
+
+// rowSampler wraps a record source and yields at most size records. Head
+// sampling streams; random (reservoir) and systematic sampling consume the
+// whole source on the first call and then replay the kept records.
+type rowSampler struct {
+	source   func() ([]string, error)
+	size     int
+	strategy string
+	rng      *rand.Rand
+	rows     [][]string
+	stride   int
+	seen     int
+	drained  bool
+	pos      int
+}
+
+func newRowSampler(source func() ([]string, error), opts Options) *rowSampler {
+	strategy := opts.SampleStrategy
+	if strategy == "" {
+		strategy = SampleRandom
+	}
+
+	return &rowSampler{
+		source:   source,
+		size:     opts.SampleSize,
+		strategy: strategy,
+		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		rows:     make([][]string, 0),
+		stride:   1,
+	}
+}
+
+func (s *rowSampler) next() ([]string, error) {
+	if s.strategy == SampleHead {
+		if s.seen >= s.size {
+			return nil, io.EOF
+		}
+		record, err := s.source()
+		if err == nil {
+			s.seen++
+		}
+		return record, err
+	}
+
+	if !s.drained {
+		if err := s.drain(); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.pos >= len(s.rows) {
+		return nil, io.EOF
+	}
+	record := s.rows[s.pos]
+	s.rows[s.pos] = nil
+	s.pos++
+	return record, nil
+}
+
+func (s *rowSampler) drain() error {
+	for {
+		record, err := s.source()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if s.strategy == SampleSystematic {
+			s.keepSystematic(record)
+		} else {
+			s.keepRandom(record)
+		}
+		s.seen++
+	}
+
+	if s.strategy == SampleSystematic && len(s.rows) > s.size {
+		kept := make([][]string, s.size)
+		for i := range kept {
+			kept[i] = s.rows[i*len(s.rows)/s.size]
+		}
+		s.rows = kept
+	}
+
+	s.drained = true
+	return nil
+}
+
+// keepRandom is reservoir sampling (Algorithm R): the i-th record replaces a
+// random kept record with probability size/i.
+func (s *rowSampler) keepRandom(record []string) {
+	if len(s.rows) < s.size {
+		s.rows = append(s.rows, record)
+		return
+	}
+
+	if j := s.rng.Intn(s.seen + 1); j < s.size {
+		s.rows[j] = record
+	}
+}
+
+// keepSystematic keeps every stride-th record. The total is unknown up front,
+// so whenever twice the sample size has been kept the stride doubles and every
+// other kept record is dropped.
+func (s *rowSampler) keepSystematic(record []string) {
+	if s.seen%s.stride != 0 {
+		return
+	}
+
+	s.rows = append(s.rows, record)
+	if len(s.rows) < 2*s.size {
+		return
+	}
+
+	for i := 0; i < s.size; i++ {
+		s.rows[i] = s.rows[2*i]
+	}
+	for i := s.size; i < len(s.rows); i++ {
+		s.rows[i] = nil
+	}
+	s.rows = s.rows[:s.size]
+	s.stride *= 2
+}
+
+// apply marks profile as estimated from a sample. Digests of a sample are
+// meaningless for reconciliation and are cleared.
+func (s *rowSampler) apply(profile *DatasetProfile) {
+	if s.strategy == SampleHead {
+		if s.seen < s.size {
+			return
+		}
+		if _, err := s.source(); err == io.EOF {
+			return
+		}
+		profile.SampleStrategy = s.strategy
+		profile.Notes = append(profile.Notes, fmt.Sprintf(
+			"Statistics estimated from the first %d rows", profile.RowCount))
+	} else {
+		if s.seen <= s.size {
+			return
+		}
+		profile.SampleStrategy = s.strategy
+		profile.SourceRows = s.seen
+		profile.Notes = append(profile.Notes, fmt.Sprintf(
+			"Statistics estimated from %d rows sampled (%s) out of %d", profile.RowCount, s.strategy, s.seen))
+	}
+
+	profile.ContentDigest = ""
+	for _, col := range profile.Columns {
+		col.Digest = ""
+		if col.Nullability != nil {
+			col.Nullability.sampled()
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package profiler
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func countingSource(n int) func() ([]string, error) {
+	i := 0
+	return func() ([]string, error) {
+		if i >= n {
+			return nil, io.EOF
+		}
+		i++
+		return []string{strconv.Itoa(i - 1)}, nil
+	}
+}
+
+func drainSampler(t *testing.T, s *rowSampler) []int {
+	rows := make([]int, 0)
+	for {
+		record, err := s.next()
+		if err == io.EOF {
+			return rows
+		}
+		if err != nil {
+			t.Fatalf("Sampler failed: %v", err)
+		}
+		v, _ := strconv.Atoi(record[0])
+		rows = append(rows, v)
+	}
+}
+
+func TestRowSamplerHead(t *testing.T) {
+	rows := drainSampler(t, newRowSampler(countingSource(1000), Options{SampleSize: 10, SampleStrategy: SampleHead}))
+
+	if len(rows) != 10 || rows[0] != 0 || rows[9] != 9 {
+		t.Errorf("Expected the first 10 rows, got %v", rows)
+	}
+}
+
+func TestRowSamplerRandom(t *testing.T) {
+	s := newRowSampler(countingSource(10000), Options{SampleSize: 500, SampleStrategy: SampleRandom})
+	rows := drainSampler(t, s)
+
+	if len(rows) != 500 || s.seen != 10000 {
+		t.Fatalf("Expected 500 of 10000 rows, got %d of %d", len(rows), s.seen)
+	}
+
+	seen := make(map[int]bool)
+	late := 0
+	for _, v := range rows {
+		if seen[v] {
+			t.Fatalf("Row %d sampled twice", v)
+		}
+		seen[v] = true
+		if v >= 5000 {
+			late++
+		}
+	}
+
+	if late < 175 || late > 325 {
+		t.Errorf("Expected about half the sample from the second half, got %d of 500", late)
+	}
+}
+
+func TestRowSamplerSystematic(t *testing.T) {
+	rows := drainSampler(t, newRowSampler(countingSource(10000), Options{SampleSize: 100, SampleStrategy: SampleSystematic}))
+
+	if len(rows) != 100 {
+		t.Fatalf("Expected 100 rows, got %d", len(rows))
+	}
+
+	for i := 1; i < len(rows); i++ {
+		if gap := rows[i] - rows[i-1]; gap < 64 || gap > 256 {
+			t.Fatalf("Expected evenly spaced rows, got gap %d between %d and %d", gap, rows[i-1], rows[i])
+		}
+	}
+
+	if rows[len(rows)-1] < 9000 {
+		t.Errorf("Expected sample to span the whole source, last row %d", rows[len(rows)-1])
+	}
+}
+
+func TestProfileDatasetWithSample(t *testing.T) {
+	var content strings.Builder
+	content.WriteString("id,value\n")
+	for i := 0; i < 1000; i++ {
+		content.WriteString(fmt.Sprintf("%d,%d\n", i, i%10))
+	}
+
+	path := filepath.Join(t.TempDir(), "sample.csv")
+	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		t.Fatalf("Failed to write CSV file: %v", err)
+	}
+
+	for _, strategy := range []string{SampleHead, SampleRandom, SampleSystematic} {
+		profile, err := ProfileDatasetWithOptions(path, Options{SampleSize: 100, SampleStrategy: strategy})
+		if err != nil {
+			t.Fatalf("ProfileDatasetWithOptions failed: %v", err)
+		}
+
+		if profile.RowCount != 100 || profile.SampleStrategy != strategy {
+			t.Errorf("%s: expected 100 sampled rows, got %d (strategy %q)", strategy, profile.RowCount, profile.SampleStrategy)
+		}
+
+		if profile.ContentDigest != "" {
+			t.Errorf("%s: expected no content digest for a sample", strategy)
+		}
+
+		if len(profile.Notes) == 0 || !strings.HasPrefix(profile.Notes[len(profile.Notes)-1], "Statistics estimated from") {
+			t.Errorf("%s: expected a sampling note, got %v", strategy, profile.Notes)
+		}
+	}
+
+	profile, err := ProfileDatasetWithOptions(path, Options{SampleSize: 5000})
+	if err != nil {
+		t.Fatalf("ProfileDatasetWithOptions failed: %v", err)
+	}
+	if profile.RowCount != 1000 || profile.SampleStrategy != "" || profile.ContentDigest == "" {
+		t.Errorf("Expected a full profile when the sample covers every row, got %d rows (strategy %q)",
+			profile.RowCount, profile.SampleStrategy)
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{SampleSize: 10, SampleStrategy: "bogus"}); err == nil {
+		t.Error("Expected error for unsupported sample strategy")
+	}
+}
@@ -0,0 +1,233 @@
+package profiler
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Names of the built-in scoring presets.
+const (
+	ScoringDefault = "default"
+	ScoringStrict  = "strict"
+	ScoringLenient = "lenient"
+)
+
+// Scoring weighs the penalties the quality score deducts from 100: points
+// per percent of missing cells and of duplicate rows, and points per quality
+// issue by severity, each penalty capped at its Max. Columns weigh the
+// missing cells and issues of matching columns (1 by default, 0 leaves them
+// out), and Ignore leaves columns out of the score altogether; both take
+// exact names or patterns such as notes_*.
+type Scoring struct {
+	Preset     string             `yaml:"preset,omitempty"` // preset the weights start from
+	Missing    Penalty            `yaml:"missing"`
+	Duplicates Penalty            `yaml:"duplicates"`
+	Issues     IssuePenalty       `yaml:"issues"`
+	Columns    map[string]float64 `yaml:"columns,omitempty"`
+	Ignore     []string           `yaml:"ignore,omitempty,flow"`
+}
+
+// Penalty deducts PerPercent points per percent, up to Max points.
+type Penalty struct {
+	PerPercent float64 `yaml:"per_percent"`
+	Max        float64 `yaml:"max"`
+}
+
+// IssuePenalty deducts points per quality issue of each severity, Dataset
+// times as many for an issue of the whole dataset, up to Max points.
+type IssuePenalty struct {
+	Low     float64 `yaml:"low"`
+	Medium  float64 `yaml:"medium"`
+	High    float64 `yaml:"high"`
+	Dataset float64 `yaml:"dataset"`
+	Max     float64 `yaml:"max"`
+}
+
+// DefaultScoring is the scoring of profiles unless told otherwise.
+func DefaultScoring() *Scoring {
+	return &Scoring{
+		Preset:     ScoringDefault,
+		Missing:    Penalty{PerPercent: 3, Max: 30},
+		Duplicates: Penalty{PerPercent: 2, Max: 15},
+		Issues:     IssuePenalty{Low: 1, Medium: 2, High: 3, Dataset: 5, Max: 40},
+	}
+}
+
+// ScoringPresets are the names of the built-in presets.
+func ScoringPresets() []string {
+	return []string{ScoringDefault, ScoringStrict, ScoringLenient}
+}
+
+// ScoringPreset returns the preset of the given name: default, strict, which
+// deducts more for every problem and allows larger totals, or lenient, which
+// forgives low severity issues and deducts less.
+func ScoringPreset(name string) (*Scoring, error) {
+	switch name {
+	case ScoringDefault, "":
+		return DefaultScoring(), nil
+	case ScoringStrict:
+		return &Scoring{
+			Preset:     ScoringStrict,
+			Missing:    Penalty{PerPercent: 5, Max: 40},
+			Duplicates: Penalty{PerPercent: 5, Max: 25},
+			Issues:     IssuePenalty{Low: 2, Medium: 4, High: 8, Dataset: 5, Max: 60},
+		}, nil
+	case ScoringLenient:
+		return &Scoring{
+			Preset:     ScoringLenient,
+			Missing:    Penalty{PerPercent: 1, Max: 20},
+			Duplicates: Penalty{PerPercent: 1, Max: 10},
+			Issues:     IssuePenalty{Low: 0, Medium: 1, High: 2, Dataset: 3, Max: 30},
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown scoring preset %s (want %s)", name, strings.Join(ScoringPresets(), ", "))
+}
+
+// Validate reports a negative weight or a malformed column pattern.
+func (s *Scoring) Validate() error {
+	if _, err := ScoringPreset(s.Preset); err != nil {
+		return err
+	}
+	for name, value := range map[string]float64{
+		"missing.per_percent": s.Missing.PerPercent, "missing.max": s.Missing.Max,
+		"duplicates.per_percent": s.Duplicates.PerPercent, "duplicates.max": s.Duplicates.Max,
+		"issues.low": s.Issues.Low, "issues.medium": s.Issues.Medium, "issues.high": s.Issues.High,
+		"issues.dataset": s.Issues.Dataset, "issues.max": s.Issues.Max,
+	} {
+		if value < 0 {
+			return fmt.Errorf("scoring weight %s must not be negative: %g", name, value)
+		}
+	}
+
+	for pattern, weight := range s.Columns {
+		if weight < 0 {
+			return fmt.Errorf("scoring weight of column %s must not be negative: %g", pattern, weight)
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid scoring column pattern %s: %w", pattern, err)
+		}
+	}
+	for _, pattern := range s.Ignore {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid scoring column pattern %s: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Label names the scoring in reports: its preset, with overrides when its
+// weights differ from those of the preset.
+func (s *Scoring) Label() string {
+	preset, err := ScoringPreset(s.Preset)
+	if err != nil {
+		return s.Preset
+	}
+	if reflect.DeepEqual(normalizeScoring(s), normalizeScoring(preset)) {
+		return preset.Preset
+	}
+	return preset.Preset + " with overrides"
+}
+
+func normalizeScoring(s *Scoring) Scoring {
+	n := *s
+	n.Preset = ""
+	if len(n.Columns) == 0 {
+		n.Columns = nil
+	}
+	if len(n.Ignore) == 0 {
+		n.Ignore = nil
+	}
+	return n
+}
+
+// IsDefault reports whether s scores as DefaultScoring does.
+func (s *Scoring) IsDefault() bool {
+	return s == nil || s.Label() == ScoringDefault
+}
+
+// columnWeight is the weight of the missing cells and issues of a column.
+func (s *Scoring) columnWeight(name string) float64 {
+	for _, pattern := range s.Ignore {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return 0
+		}
+	}
+	if weight, ok := s.Columns[name]; ok {
+		return weight
+	}
+
+	// The first matching pattern in sorted order, so that the weight does
+	// not depend on map order
+	patterns := make([]string, 0, len(s.Columns))
+	for pattern := range s.Columns {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return s.Columns[pattern]
+		}
+	}
+	return 1
+}
+
+func (p IssuePenalty) severity(severity int) float64 {
+	switch {
+	case severity <= 0:
+		return 0
+	case severity == 1:
+		return p.Low
+	case severity == 2:
+		return p.Medium
+	default:
+		return p.High
+	}
+}
+
+func (p Penalty) deduct(percent float64) int {
+	if percent <= 0 {
+		return 0
+	}
+	return int(min(percent*p.PerPercent, p.Max))
+}
+
+// Score is the quality score of profile, from 0 to 100.
+func (s *Scoring) Score(profile *DatasetProfile) int {
+	if profile.RowCount == 0 || profile.ColumnCount == 0 {
+		return 0
+	}
+
+	// Missing cells, weighed by column when columns are weighed or ignored
+	missingPercentage := float64(profile.MissingCells) / float64(profile.RowCount*profile.ColumnCount) * 100
+	if len(s.Columns) > 0 || len(s.Ignore) > 0 {
+		missing, weights := 0.0, 0.0
+		for name, col := range profile.Columns {
+			weight := s.columnWeight(name)
+			missing += weight * float64(col.MissingCount)
+			weights += weight
+		}
+		missingPercentage = 0
+		if weights > 0 {
+			missingPercentage = missing / (weights * float64(profile.RowCount)) * 100
+		}
+	}
+
+	issuePenalty := 0.0
+	for _, issue := range profile.QualityIssues {
+		issuePenalty += s.Issues.severity(issue.Severity) * s.Issues.Dataset
+	}
+	for name, col := range profile.Columns {
+		weight := s.columnWeight(name)
+		for _, issue := range col.QualityIssues {
+			issuePenalty += s.Issues.severity(issue.Severity) * weight
+		}
+	}
+
+	duplicatePercentage := float64(profile.DuplicateRows) / float64(profile.RowCount) * 100
+
+	score := 100 - s.Missing.deduct(missingPercentage) - int(min(issuePenalty, s.Issues.Max)) - s.Duplicates.deduct(duplicatePercentage)
+	return max(score, 0)
+}
@@ -0,0 +1,129 @@
+package profiler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// createScoringProfile has 10% missing cells, all in notes, one dataset
+// issue of medium severity, a high severity issue of notes and 2% duplicate
+// rows.
+func createScoringProfile() *DatasetProfile {
+	return &DatasetProfile{
+		RowCount:      100,
+		ColumnCount:   2,
+		MissingCells:  20,
+		DuplicateRows: 2,
+		QualityIssues: []QualityIssue{{Type: "duplicates", Severity: 2}},
+		Columns: map[string]*ColumnProfile{
+			"id":    {Name: "id"},
+			"notes": {Name: "notes", MissingCount: 20, QualityIssues: []QualityIssue{{Type: "missing_values", Severity: 3}}},
+		},
+	}
+}
+
+func TestScoringPresets(t *testing.T) {
+	profile := createScoringProfile()
+
+	// 30 for missing cells, 10 + 3 for issues and 4 for duplicates
+	if score := CalculateQualityScore(profile); score != 53 {
+		t.Errorf("Expected a default score of 53, got %d", score)
+	}
+
+	tests := []struct {
+		preset string
+		want   int
+	}{
+		{ScoringDefault, 53},
+		{ScoringStrict, 22},  // 40 + 28 + 10
+		{ScoringLenient, 83}, // 10 + 5 + 2
+	}
+	for _, tt := range tests {
+		scoring, err := ScoringPreset(tt.preset)
+		if err != nil {
+			t.Fatalf("ScoringPreset(%s) failed: %v", tt.preset, err)
+		}
+		if score := scoring.Score(profile); score != tt.want {
+			t.Errorf("Expected a %s score of %d, got %d", tt.preset, tt.want, score)
+		}
+		if scoring.Label() != tt.preset {
+			t.Errorf("Expected the label %s, got %s", tt.preset, scoring.Label())
+		}
+	}
+
+	if _, err := ScoringPreset("harsh"); err == nil {
+		t.Error("Expected an unknown preset to be rejected")
+	}
+}
+
+func TestScoringColumns(t *testing.T) {
+	profile := createScoringProfile()
+
+	// Ignoring notes leaves no missing cells and only the dataset issue
+	scoring := DefaultScoring()
+	scoring.Ignore = []string{"no*"}
+	if score := scoring.Score(profile); score != 86 {
+		t.Errorf("Expected 86 with notes ignored, got %d", score)
+	}
+	if !strings.Contains(scoring.Label(), "with overrides") || scoring.IsDefault() {
+		t.Errorf("Expected the label to show overrides, got %s", scoring.Label())
+	}
+
+	// Half the weight: 20 × 0.5 of 150 weighted cells missing, 6.7%, and
+	// half the issue of notes
+	scoring = DefaultScoring()
+	scoring.Columns = map[string]float64{"notes": 0.5}
+	if score := scoring.Score(profile); score != 65 {
+		t.Errorf("Expected 65 with notes weighed by half, got %d", score)
+	}
+
+	scoring = DefaultScoring()
+	scoring.Columns = map[string]float64{"*": -1}
+	if err := scoring.Validate(); err == nil {
+		t.Error("Expected a negative column weight to be rejected")
+	}
+	scoring = DefaultScoring()
+	scoring.Issues.High = -2
+	if err := scoring.Validate(); err == nil {
+		t.Error("Expected a negative weight to be rejected")
+	}
+}
+
+func TestProfileScoring(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.csv")
+	if err := os.WriteFile(path, []byte("id,notes\n1,a\n2,\n3,\n4,b\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	base, err := ProfileDatasetWithOptions(path, Options{})
+	if err != nil {
+		t.Fatalf("ProfileDatasetWithOptions failed: %v", err)
+	}
+	strict, _ := ScoringPreset(ScoringStrict)
+	profile, err := ProfileDatasetWithOptions(path, Options{Scoring: strict})
+	if err != nil {
+		t.Fatalf("ProfileDatasetWithOptions failed: %v", err)
+	}
+
+	if profile.QualityScore >= base.QualityScore {
+		t.Errorf("Expected a strict score below %d, got %d", base.QualityScore, profile.QualityScore)
+	}
+	found := false
+	for _, note := range profile.Notes {
+		found = found || note == "Quality score computed with the strict scoring profile"
+	}
+	if !found {
+		t.Errorf("Expected a note on the scoring, got %v", profile.Notes)
+	}
+	for _, note := range base.Notes {
+		if strings.Contains(note, "scoring") {
+			t.Errorf("Expected no note on the default scoring, got %q", note)
+		}
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{Scoring: &Scoring{Preset: "harsh"}}); err == nil {
+		t.Error("Expected an unknown preset to be rejected")
+	}
+}
@@ -0,0 +1,482 @@
+package profiler
+
+import (
+	"container/heap"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+const (
+	maxTrackedValues = 10000
+	maxTrackedRows   = 1000000
+	topValueCapacity = 1000
+	hllPrecision     = 14
+)
+
+func hashValue(value string) uint64 {
+	return mix64(hashString(value))
+}
+
+// distinctCounter counts distinct hashes exactly until maxExact of them have
+// been seen and estimates with HyperLogLog from then on.
+type distinctCounter struct {
+	maxExact int
+	exact    map[uint64]struct{}
+	hll      *hyperLogLog
+}
+
+func newDistinctCounter(maxExact int) *distinctCounter {
+	return &distinctCounter{
+		maxExact: maxExact,
+		exact:    make(map[uint64]struct{}),
+	}
+}
+
+func (d *distinctCounter) add(hash uint64) {
+	if d.hll != nil {
+		d.hll.add(hash)
+		return
+	}
+
+	d.exact[hash] = struct{}{}
+	if len(d.exact) > d.maxExact {
+		d.hll = newHyperLogLog()
+		for h := range d.exact {
+			d.hll.add(h)
+		}
+		d.exact = nil
+	}
+}
+
+func (d *distinctCounter) count() int {
+	if d.hll != nil {
+		return int(math.Round(d.hll.estimate()))
+	}
+	return len(d.exact)
+}
+
+func (d *distinctCounter) estimated() bool {
+	return d.hll != nil
+}
+
+// merge folds in the hashes counted by o.
+func (d *distinctCounter) merge(o *distinctCounter) {
+	if o.hll == nil {
+		for h := range o.exact {
+			d.add(h)
+		}
+		return
+	}
+
+	if d.hll == nil {
+		d.hll = newHyperLogLog()
+		for h := range d.exact {
+			d.hll.add(h)
+		}
+		d.exact = nil
+	}
+	d.hll.merge(o.hll)
+}
+
+// duplicateCounter counts the repeated rows among rows identified by hash.
+// Up to maxExact distinct hashes it keeps every one. Past that it keeps only
+// the hashes below a limit, halved whenever the set fills up again: a
+// uniform sample of the distinct rows, each with all its repeats, whose
+// repeats are scaled up. Rows that never repeat still count none.
+type duplicateCounter struct {
+	maxExact int
+	rows     map[uint64]int    // rows per hash kept
+	values   map[uint64]string // value of each hash kept, nil when not listed
+	repeats  int               // rows kept that repeat an earlier one
+	shift    uint              // hashes are kept when their top shift bits are 0
+}
+
+func newDuplicateCounter(maxExact int) *duplicateCounter {
+	return &duplicateCounter{maxExact: maxExact, rows: make(map[uint64]int)}
+}
+
+func (d *duplicateCounter) kept(hash uint64) bool {
+	return d.shift == 0 || hash>>(64-d.shift) == 0
+}
+
+// newValueDuplicateCounter also keeps the value behind each hash kept, to
+// list the repeated ones.
+func newValueDuplicateCounter(maxExact int) *duplicateCounter {
+	d := newDuplicateCounter(maxExact)
+	d.values = make(map[uint64]string)
+	return d
+}
+
+func (d *duplicateCounter) add(hash uint64) {
+	d.addN(hash, 1)
+}
+
+// addValue counts a row carrying value, kept with its hash.
+func (d *duplicateCounter) addValue(value string) {
+	hash := hashValue(value)
+	if d.kept(hash) {
+		if _, ok := d.values[hash]; !ok {
+			d.values[hash] = value
+		}
+	}
+	d.addN(hash, 1)
+}
+
+func (d *duplicateCounter) addN(hash uint64, n int) {
+	if !d.kept(hash) {
+		return
+	}
+
+	rows, seen := d.rows[hash]
+	d.rows[hash] = rows + n
+	if seen {
+		d.repeats += n
+	} else {
+		d.repeats += n - 1
+	}
+
+	for len(d.rows) > d.maxExact {
+		d.shift++
+		d.prune()
+	}
+}
+
+// prune drops the hashes no longer kept, with their repeats.
+func (d *duplicateCounter) prune() {
+	for hash, rows := range d.rows {
+		if !d.kept(hash) {
+			d.repeats -= rows - 1
+			delete(d.rows, hash)
+			delete(d.values, hash)
+		}
+	}
+}
+
+// duplicates returns the rows that repeat an earlier one, estimated when
+// only a sample of the distinct rows is kept.
+func (d *duplicateCounter) duplicates() int {
+	return d.repeats << d.shift
+}
+
+func (d *duplicateCounter) estimated() bool {
+	return d.shift > 0
+}
+
+// merge folds in the rows counted by o, keeping the smaller sample of the
+// two.
+func (d *duplicateCounter) merge(o *duplicateCounter) {
+	if o.shift > d.shift {
+		d.shift = o.shift
+		d.prune()
+	}
+	for hash, rows := range o.rows {
+		if d.values != nil && d.kept(hash) {
+			if _, ok := d.values[hash]; !ok {
+				d.values[hash] = o.values[hash]
+			}
+		}
+		d.addN(hash, rows)
+	}
+}
+
+// repeated lists the values kept that more than one row carries, with
+// their rows.
+func (d *duplicateCounter) repeated() []ValueCount {
+	repeated := make([]ValueCount, 0)
+	for hash, rows := range d.rows {
+		if rows > 1 {
+			repeated = append(repeated, ValueCount{Value: d.values[hash], Count: rows})
+		}
+	}
+	sortValueCounts(repeated)
+	return repeated
+}
+
+type hyperLogLog struct {
+	registers []uint8
+}
+
+func newHyperLogLog() *hyperLogLog {
+	return &hyperLogLog{registers: make([]uint8, 1<<hllPrecision)}
+}
+
+func (h *hyperLogLog) add(hash uint64) {
+	index := hash >> (64 - hllPrecision)
+	rank := uint8(bits.LeadingZeros64(hash<<hllPrecision|1<<(hllPrecision-1))) + 1
+	if rank > h.registers[index] {
+		h.registers[index] = rank
+	}
+}
+
+func (h *hyperLogLog) merge(o *hyperLogLog) {
+	for i, r := range o.registers {
+		if r > h.registers[i] {
+			h.registers[i] = r
+		}
+	}
+}
+
+func (h *hyperLogLog) estimate() float64 {
+	m := float64(len(h.registers))
+
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	estimate := 0.7213 / (1 + 1.079/m) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+
+	return estimate
+}
+
+// valueCounter tracks value frequencies exactly for up to maxTrackedValues
+// distinct values. Past that, the most frequent values are tracked with the
+// Space-Saving algorithm and the distinct count is estimated.
+type valueCounter struct {
+	counts   map[string]int
+	topK     *spaceSaving
+	distinct *distinctCounter
+}
+
+func newValueCounter() *valueCounter {
+	return &valueCounter{counts: make(map[string]int)}
+}
+
+func (c *valueCounter) add(value string) {
+	c.addN(value, 1)
+}
+
+func (c *valueCounter) addN(value string, n int) {
+	if c.counts != nil {
+		c.counts[value] += n
+		if len(c.counts) > maxTrackedValues {
+			c.overflow()
+		}
+		return
+	}
+
+	c.distinct.add(hashValue(value))
+	if c.topK != nil {
+		c.topK.add(value, n)
+	}
+}
+
+func (c *valueCounter) overflow() {
+	c.topK = newSpaceSaving(topValueCapacity)
+	c.distinct = newDistinctCounter(0)
+
+	for _, val := range getTopValues(c.counts, topValueCapacity) {
+		c.topK.add(val.Value, val.Count)
+	}
+	for value := range c.counts {
+		c.distinct.add(hashValue(value))
+	}
+
+	c.counts = nil
+}
+
+// forgetValues stops tracking frequencies and keeps only the distinct count,
+// for columns whose values are too large to keep around.
+func (c *valueCounter) forgetValues() {
+	if c.counts == nil && c.topK == nil {
+		return
+	}
+
+	if c.counts != nil {
+		c.distinct = newDistinctCounter(maxTrackedValues)
+		for value := range c.counts {
+			c.distinct.add(hashValue(value))
+		}
+	}
+
+	c.counts = nil
+	c.topK = nil
+}
+
+// merge folds in the frequencies counted by o. Once either side has
+// overflowed, the result tracks the most frequent values with Space-Saving,
+// so merged top counts are upper bounds as well.
+func (c *valueCounter) merge(o *valueCounter) {
+	if o.counts != nil {
+		for value, n := range o.counts {
+			c.addN(value, n)
+		}
+		return
+	}
+
+	if c.counts != nil {
+		c.overflow()
+	}
+	c.distinct.merge(o.distinct)
+
+	if c.topK == nil {
+		return
+	}
+	if o.topK == nil {
+		c.topK = nil
+		return
+	}
+	for _, entry := range o.topK.heap {
+		c.topK.add(entry.value, entry.count)
+	}
+}
+
+func (c *valueCounter) uniqueCount() int {
+	if c.counts != nil {
+		return len(c.counts)
+	}
+	return c.distinct.count()
+}
+
+func (c *valueCounter) topValues(limit int) []ValueCount {
+	switch {
+	case c.counts != nil:
+		return getTopValues(c.counts, limit)
+	case c.topK != nil:
+		return c.topK.top(limit)
+	default:
+		return make([]ValueCount, 0)
+	}
+}
+
+// bottomValues returns the least frequent values, rarest first, or none once
+// values are no longer all counted.
+func (c *valueCounter) bottomValues(limit int) []ValueCount {
+	if c.counts == nil {
+		return nil
+	}
+
+	values := make([]ValueCount, 0, len(c.counts))
+	for value, count := range c.counts {
+		values = append(values, ValueCount{Value: value, Count: count})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count < values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+
+	if len(values) > limit {
+		values = values[:limit]
+	}
+	return values
+}
+
+// singletons counts the values seen exactly once, while values are all
+// counted.
+func (c *valueCounter) singletons() (int, bool) {
+	if c.counts == nil {
+		return 0, false
+	}
+
+	n := 0
+	for _, count := range c.counts {
+		if count == 1 {
+			n++
+		}
+	}
+	return n, true
+}
+
+func (c *valueCounter) approximate() bool {
+	return c.counts == nil && c.distinct.estimated()
+}
+
+type spaceSavingEntry struct {
+	value string
+	count int
+	index int
+}
+
+// spaceSaving keeps counts for at most capacity values. A new value evicts
+// the least frequent one and inherits its count, so counts are upper bounds.
+type spaceSaving struct {
+	capacity int
+	entries  map[string]*spaceSavingEntry
+	heap     spaceSavingHeap
+}
+
+func newSpaceSaving(capacity int) *spaceSaving {
+	return &spaceSaving{
+		capacity: capacity,
+		entries:  make(map[string]*spaceSavingEntry, capacity),
+	}
+}
+
+func (s *spaceSaving) add(value string, n int) {
+	if entry, ok := s.entries[value]; ok {
+		entry.count += n
+		heap.Fix(&s.heap, entry.index)
+		return
+	}
+
+	if len(s.heap) < s.capacity {
+		entry := &spaceSavingEntry{value: value, count: n}
+		s.entries[value] = entry
+		heap.Push(&s.heap, entry)
+		return
+	}
+
+	entry := s.heap[0]
+	delete(s.entries, entry.value)
+	entry.value = value
+	entry.count += n
+	s.entries[value] = entry
+	heap.Fix(&s.heap, 0)
+}
+
+func (s *spaceSaving) top(limit int) []ValueCount {
+	values := make([]ValueCount, 0, len(s.heap))
+	for _, entry := range s.heap {
+		values = append(values, ValueCount{Value: entry.value, Count: entry.count})
+	}
+
+	sortValueCounts(values)
+
+	if len(values) > limit {
+		values = values[:limit]
+	}
+	return values
+}
+
+type spaceSavingHeap []*spaceSavingEntry
+
+func (h spaceSavingHeap) Len() int           { return len(h) }
+func (h spaceSavingHeap) Less(i, j int) bool { return h[i].count < h[j].count }
+
+func (h spaceSavingHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *spaceSavingHeap) Push(x interface{}) {
+	entry := x.(*spaceSavingEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *spaceSavingHeap) Pop() interface{} {
+	old := *h
+	entry := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return entry
+}
+
+func sortValueCounts(values []ValueCount) {
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+}
@@ -0,0 +1,211 @@
+package profiler
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestDistinctCounter(t *testing.T) {
+	counter := newDistinctCounter(1000)
+
+	for i := 0; i < 500; i++ {
+		counter.add(hashValue(fmt.Sprintf("v%d", i%250)))
+	}
+	if counter.estimated() || counter.count() != 250 {
+		t.Errorf("Expected exact count of 250, got %d (estimated=%v)", counter.count(), counter.estimated())
+	}
+
+	for i := 0; i < 100000; i++ {
+		counter.add(hashValue(fmt.Sprintf("v%d", i)))
+	}
+	if !counter.estimated() {
+		t.Fatal("Expected counter to switch to HyperLogLog")
+	}
+
+	if relErr := math.Abs(float64(counter.count())-100000) / 100000; relErr > 0.03 {
+		t.Errorf("Expected estimate within 3%% of 100000, got %d", counter.count())
+	}
+}
+
+func TestDuplicateCounter(t *testing.T) {
+	counter := newDuplicateCounter(1000)
+	for i := 0; i < 500; i++ {
+		counter.add(hashValue(fmt.Sprintf("v%d", i%250)))
+	}
+	if counter.estimated() || counter.duplicates() != 250 {
+		t.Errorf("Expected 250 exact duplicates, got %d (estimated=%v)", counter.duplicates(), counter.estimated())
+	}
+
+	// Far more distinct rows than kept: without repeats there are none to
+	// estimate, with every tenth row repeated the sample finds about as many
+	unique, repeated := newDuplicateCounter(1000), newDuplicateCounter(1000)
+	for i := 0; i < 100000; i++ {
+		hash := hashValue(fmt.Sprintf("r%d", i))
+		unique.add(hash)
+		repeated.add(hash)
+		if i%10 == 0 {
+			repeated.add(hash)
+		}
+	}
+	if !unique.estimated() || unique.duplicates() != 0 {
+		t.Errorf("Expected an estimate of no duplicates, got %d (estimated=%v)", unique.duplicates(), unique.estimated())
+	}
+	if relErr := math.Abs(float64(repeated.duplicates())-10000) / 10000; relErr > 0.2 {
+		t.Errorf("Expected about 10000 duplicates, got %d", repeated.duplicates())
+	}
+
+	// Merged parts keep the smaller sample
+	first, second := newDuplicateCounter(1000), newDuplicateCounter(1000)
+	for i := 0; i < 20000; i++ {
+		hash := hashValue(fmt.Sprintf("r%d", i%10000))
+		if i < 10000 {
+			first.add(hash)
+		} else {
+			second.add(hash)
+		}
+	}
+	second.add(hashValue("only"))
+	first.merge(second)
+	if relErr := math.Abs(float64(first.duplicates())-10000) / 10000; relErr > 0.2 {
+		t.Errorf("Expected about 10000 duplicates across parts, got %d", first.duplicates())
+	}
+}
+
+func TestValueDuplicateCounter(t *testing.T) {
+	// Every tenth of 20,000 keys is carried by 3 rows
+	first, second := newValueDuplicateCounter(1000), newValueDuplicateCounter(1000)
+	for i := 0; i < 20000; i++ {
+		first.addValue(fmt.Sprintf("k%d", i))
+		if i%10 == 0 {
+			second.addValue(fmt.Sprintf("k%d", i))
+			second.addValue(fmt.Sprintf("k%d", i))
+		}
+	}
+	first.merge(second)
+	if !first.estimated() {
+		t.Fatal("Expected the keys to be sampled")
+	}
+
+	repeated := first.repeated()
+	if len(repeated) == 0 {
+		t.Fatal("Expected repeated keys from the sample")
+	}
+	for _, v := range repeated {
+		var i int
+		if _, err := fmt.Sscanf(v.Value, "k%d", &i); err != nil || i%10 != 0 || v.Count != 3 {
+			t.Errorf("Expected a repeated key carried by 3 rows, got %+v", v)
+		}
+	}
+	if relErr := math.Abs(float64(first.duplicates())-4000) / 4000; relErr > 0.35 {
+		t.Errorf("Expected about 4000 duplicates, got %d", first.duplicates())
+	}
+}
+
+func TestValueCounterExact(t *testing.T) {
+	counter := newValueCounter()
+	counter.add("a")
+	counter.add("b")
+	counter.addN("a", 4)
+
+	if counter.approximate() || counter.uniqueCount() != 2 {
+		t.Errorf("Expected 2 exact unique values, got %d", counter.uniqueCount())
+	}
+
+	top := counter.topValues(5)
+	if len(top) != 2 || top[0].Value != "a" || top[0].Count != 5 {
+		t.Errorf("Unexpected top values: %v", top)
+	}
+}
+
+func TestValueCounterOverflow(t *testing.T) {
+	counter := newValueCounter()
+
+	for i := 0; i < 3*maxTrackedValues; i++ {
+		counter.add(fmt.Sprintf("id%d", i))
+		if i%10 == 0 {
+			counter.add("frequent")
+		}
+		if i%20 == 0 {
+			counter.add("common")
+		}
+	}
+
+	if !counter.approximate() {
+		t.Fatal("Expected counter to become approximate")
+	}
+
+	expected := 3*maxTrackedValues + 2
+	if relErr := math.Abs(float64(counter.uniqueCount()-expected)) / float64(expected); relErr > 0.03 {
+		t.Errorf("Expected unique estimate near %d, got %d", expected, counter.uniqueCount())
+	}
+
+	top := counter.topValues(2)
+	if top[0].Value != "frequent" || top[1].Value != "common" {
+		t.Errorf("Expected 'frequent' and 'common' on top, got %v", top)
+	}
+	if top[0].Count < 3*maxTrackedValues/10 {
+		t.Errorf("Space-Saving counts should not underestimate, got %d", top[0].Count)
+	}
+}
+
+func TestValueCounterForgetValues(t *testing.T) {
+	counter := newValueCounter()
+	for i := 0; i < 50; i++ {
+		counter.add(fmt.Sprintf("v%d", i%20))
+	}
+
+	counter.forgetValues()
+	counter.add("v1")
+	counter.add("new")
+
+	if counter.uniqueCount() != 21 {
+		t.Errorf("Expected 21 distinct values, got %d", counter.uniqueCount())
+	}
+	if len(counter.topValues(5)) != 0 {
+		t.Error("Expected no top values after forgetting values")
+	}
+}
+
+func TestSpaceSaving(t *testing.T) {
+	s := newSpaceSaving(3)
+	for _, v := range []string{"a", "a", "a", "b", "b", "c", "d", "a"} {
+		s.add(v, 1)
+	}
+
+	top := s.top(3)
+	if len(top) != 3 {
+		t.Fatalf("Expected 3 tracked values, got %d", len(top))
+	}
+	if top[0].Value != "a" || top[0].Count != 4 {
+		t.Errorf("Expected 'a' with count 4 on top, got %v", top)
+	}
+}
+
+func TestValueCounterMerge(t *testing.T) {
+	first, second := newValueCounter(), newValueCounter()
+	for i := 0; i < 2*(maxTrackedValues-1); i++ {
+		counter := first
+		if i%2 == 1 {
+			counter = second
+		}
+		counter.add(fmt.Sprintf("id%d", i))
+		counter.add("frequent")
+	}
+	if first.approximate() || second.approximate() {
+		t.Fatal("Expected both halves to be counted exactly")
+	}
+
+	first.merge(second)
+	if !first.approximate() {
+		t.Fatal("Expected the merged counter to overflow")
+	}
+
+	expected := 2*maxTrackedValues - 1
+	if relErr := math.Abs(float64(first.uniqueCount()-expected)) / float64(expected); relErr > 0.03 {
+		t.Errorf("Expected unique estimate near %d, got %d", expected, first.uniqueCount())
+	}
+	if top := first.topValues(1); top[0].Value != "frequent" || top[0].Count < 2*(maxTrackedValues-1) {
+		t.Errorf("Expected 'frequent' on top with at least %d, got %v", 2*(maxTrackedValues-1), top)
+	}
+}
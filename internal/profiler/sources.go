@@ -0,0 +1,132 @@
+package profiler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/remote"
+)
+
+// IsDataFile reports whether path looks like a dataset the profiler reads,
+// judged by extension: delimited text, JSON Lines and XML, possibly
+// compressed, Parquet, Avro and ORC files, Excel workbooks, SQLite databases and
+// archives. Directories are expanded to these files.
+func IsDataFile(path string) bool {
+	if IsExcel(path) || IsSQLite(path) || IsArchive(path) {
+		return true
+	}
+	switch strings.ToLower(filepath.Ext(trimCompressionExt(path))) {
+	case ".csv", ".tsv", ".tab", ".jsonl", ".ndjson", ".xml", ".parquet", ".avro", ".orc":
+		return true
+	}
+	return false
+}
+
+// IsDelimited reports whether source is read as delimited text with opts: a
+// CSV or TSV file, local or remote and possibly compressed, or stdin, as far
+// as its name tells. Databases, workbooks, archives and tables are not.
+func IsDelimited(source string, opts Options) bool {
+	if remote.IsURL(source) {
+		source = remote.Path(source)
+	}
+	if tableFormat(source, opts) != "" || IsSQLite(source) || IsPostgres(source) || IsSnowflake(source) || IsExcel(source) || IsArchive(source) {
+		return false
+	}
+	format := fileFormat(source, opts)
+	return format == FormatCSV || format == FormatTSV
+}
+
+// ExpandSources resolves the arguments of a profile run into the datasets
+// to profile, in order and without duplicates. Glob patterns the shell left
+// alone are expanded, and a local directory that is not a Delta or Iceberg
+// table or a partitioned dataset stands for the data files directly inside
+// it. Stdin, URLs and connection strings are kept as they are.
+func ExpandSources(args []string) ([]string, error) {
+	var sources []string
+	seen := make(map[string]bool)
+	add := func(source string) {
+		if !seen[source] {
+			seen[source] = true
+			sources = append(sources, source)
+		}
+	}
+
+	for _, arg := range args {
+		if arg == StdinSource || strings.Contains(arg, "://") {
+			add(arg)
+			continue
+		}
+
+		info, err := os.Stat(arg)
+		switch {
+		case err == nil && info.IsDir() && tableFormat(arg, Options{}) == "":
+			files, err := dataFilesIn(arg)
+			if err != nil {
+				return nil, err
+			}
+			if len(files) == 0 {
+				return nil, fmt.Errorf("no data files in directory %s", arg)
+			}
+			for _, file := range files {
+				add(file)
+			}
+		case err != nil && strings.ContainsAny(arg, "*?["):
+			matches, err := filepath.Glob(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %s: %w", arg, err)
+			}
+			// Like a shell, leave out hidden files, and plain directories too
+			hidden := strings.HasPrefix(filepath.Base(arg), ".")
+			found := 0
+			for _, match := range matches {
+				if strings.HasPrefix(filepath.Base(match), ".") && !hidden {
+					continue
+				}
+				if info, err := os.Stat(match); err == nil && info.IsDir() && tableFormat(match, Options{}) == "" {
+					continue
+				}
+				add(match)
+				found++
+			}
+			if found == 0 {
+				return nil, fmt.Errorf("no files match %s", arg)
+			}
+		default:
+			add(arg)
+		}
+	}
+
+	return sources, nil
+}
+
+// dataFilesIn lists the visible data files directly inside dir, sorted by
+// name, and the tables and partitioned datasets among its subdirectories.
+func dataFilesIn(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() {
+			if tableFormat(path, Options{}) != "" {
+				files = append(files, path)
+			}
+			continue
+		}
+		if IsDataFile(path) {
+			files = append(files, path)
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,71 @@
+package profiler
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExpandSources(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.csv", "a.tsv.gz", "c.parquet", "notes.md", ".hidden.csv", "events/_delta_log/00.json", "sales/dt=2024-01-01/part-0.csv", "raw/x.csv"} {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte("id\n1\n"), 0644)
+	}
+	join := func(names ...string) []string {
+		paths := make([]string, len(names))
+		for i, name := range names {
+			paths[i] = filepath.Join(dir, name)
+		}
+		return paths
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"directory", []string{dir}, join("a.tsv.gz", "b.csv", "c.parquet", "events", "sales")},
+		{"glob", []string{filepath.Join(dir, "*.csv")}, join("b.csv")},
+		{"files in order without duplicates", append(join("c.parquet", "b.csv"), join("c.parquet")...), join("c.parquet", "b.csv")},
+		{"table directory", join("events"), join("events")},
+		{"partitioned directory", join("sales"), join("sales")},
+		{"stdin and URLs", []string{"-", "s3://bucket/*.csv"}, []string{"-", "s3://bucket/*.csv"}},
+		{"missing file", []string{"missing.csv"}, []string{"missing.csv"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandSources(tt.args)
+			if err != nil {
+				t.Fatalf("ExpandSources failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpandSources(%v) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+
+	for _, args := range [][]string{{filepath.Join(dir, "*.xlsx")}, {filepath.Join(dir, "events", "_delta_log")}} {
+		if _, err := ExpandSources(args); err == nil {
+			t.Errorf("Expected an error expanding %v", args)
+		}
+	}
+}
+
+func TestIsDelimited(t *testing.T) {
+	for _, source := range []string{"a.csv", "a.tsv.gz", "data.txt", "-", "s3://bucket/a.csv?versionId=1"} {
+		if !IsDelimited(source, Options{}) {
+			t.Errorf("Expected %s read as delimited text", source)
+		}
+	}
+	for _, source := range []string{"a.jsonl", "a.parquet", "a.xlsx", "a.avro", "a.zip", "a.xml", "s3://bucket/a.parquet"} {
+		if IsDelimited(source, Options{}) {
+			t.Errorf("Expected %s not read as delimited text", source)
+		}
+	}
+	if IsDelimited("events.csv", Options{Format: FormatJSONL}) {
+		t.Errorf("Expected --format to take precedence over the extension")
+	}
+}
@@ -0,0 +1,228 @@
+package profiler
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+var sqliteMagic = []byte("SQLite format 3\x00")
+
+// IsSQLite reports whether path is a SQLite database, judged by its file
+// header so that .db files of other formats are not mistaken for one.
+func IsSQLite(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".sqlite", ".sqlite3", ".db":
+	default:
+		return false
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	header := make([]byte, len(sqliteMagic))
+	if _, err := io.ReadFull(file, header); err != nil {
+		return false
+	}
+	return bytes.Equal(header, sqliteMagic)
+}
+
+func openSQLite(path string) (*sql.DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	return db, nil
+}
+
+// ListSQLiteTables returns the user tables of a SQLite database in name order.
+func ListSQLiteTables(path string) ([]string, error) {
+	db, err := openSQLite(path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	return sqliteTables(db)
+}
+
+func sqliteTables(db *sql.DB) ([]string, error) {
+	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SQLite tables: %w", err)
+	}
+	defer rows.Close()
+
+	tables := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to list SQLite tables: %w", err)
+		}
+		tables = append(tables, name)
+	}
+
+	return tables, rows.Err()
+}
+
+// ProfileSQLiteTables profiles every table of a SQLite database until ctx
+// is done. The tables profiled by then are returned with an error wrapping
+// ctx.Err(), the last one of them partial when it was being read.
+func ProfileSQLiteTables(ctx context.Context, path string, opts Options) ([]*DatasetProfile, error) {
+	tables, err := ListSQLiteTables(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("no tables found in SQLite database %s", path)
+	}
+
+	profiles := make([]*DatasetProfile, 0, len(tables))
+	for _, table := range tables {
+		opts.Table = table
+		profile, err := ProfileDatasetContext(ctx, path, opts)
+		if err != nil && ctx.Err() != nil && (profile != nil || len(profiles) > 0) {
+			// Stopped early: keep what was profiled
+			if profile != nil {
+				profiles = append(profiles, profile)
+			}
+			return profiles, fmt.Errorf("profiling stopped at table %s: %w", table, err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to profile table %s: %w", table, err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, nil
+}
+
+func profileSQLite(filePath string, opts Options) (*DatasetProfile, error) {
+	startTime := time.Now()
+
+	if err := opts.textOnly("SQLite databases"); err != nil {
+		return nil, err
+	}
+
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file stats: %w", err)
+	}
+
+	db, err := openSQLite(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	tables, err := sqliteTables(db)
+	if err != nil {
+		return nil, err
+	}
+
+	table := opts.Table
+	if table == "" {
+		if len(tables) != 1 {
+			return nil, fmt.Errorf("database has %d tables, choose one with --table: %s", len(tables), strings.Join(tables, ", "))
+		}
+		table = tables[0]
+	} else if !containsString(tables, table) {
+		return nil, fmt.Errorf("table %s not found in SQLite database (tables: %s)", table, strings.Join(tables, ", "))
+	}
+
+	rows, err := db.QueryContext(opts.context(), "SELECT * FROM "+quoteSQLiteIdentifier(table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	header, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of table %s: %w", table, err)
+	}
+
+	profile := newDatasetProfile(filepath.Base(filePath)+"."+table, fileInfo.Size(), "SQLite", header)
+	profile.Table = table
+
+	values := make([]interface{}, len(header))
+	pointers := make([]interface{}, len(header))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	next := func() ([]string, error) {
+		if !rows.Next() {
+			if err := rows.Err(); err != nil {
+				return nil, fmt.Errorf("error reading table %s: %w", table, err)
+			}
+			return nil, io.EOF
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("error reading table %s: %w", table, err)
+		}
+
+		record := make([]string, len(values))
+		for i, value := range values {
+			record[i] = formatSQLValue(value)
+		}
+		return record, nil
+	}
+
+	if err := profileRows(profile, header, next, opts); err != nil {
+		return nil, err
+	}
+
+	profile.ProcessingTime = time.Since(startTime)
+
+	return profile, nil
+}
+
+func formatSQLValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func quoteSQLiteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package profiler
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func createTestSQLite(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "app.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	statements := []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, score REAL)`,
+		`INSERT INTO users VALUES (1, 'ann', 1.5), (2, 'bob', NULL), (3, 'cy', 3.5)`,
+		`CREATE TABLE "order items" (sku TEXT, quantity INTEGER)`,
+		`INSERT INTO "order items" VALUES ('a', 1), ('a', 1)`,
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("Failed to execute %q: %v", statement, err)
+		}
+	}
+
+	return path
+}
+
+func TestProfileSQLiteTable(t *testing.T) {
+	path := createTestSQLite(t)
+
+	if !IsSQLite(path) {
+		t.Fatal("Expected file to be detected as SQLite")
+	}
+
+	profile, err := ProfileDatasetWithOptions(path, Options{Table: "users"})
+	if err != nil {
+		t.Fatalf("Failed to profile table: %v", err)
+	}
+
+	if profile.Format != "SQLite" || profile.Table != "users" {
+		t.Errorf("Expected SQLite table users, got %s %s", profile.Format, profile.Table)
+	}
+	if profile.RowCount != 3 || profile.ColumnCount != 3 {
+		t.Errorf("Expected 3 rows and 3 columns, got %d and %d", profile.RowCount, profile.ColumnCount)
+	}
+
+	score := profile.Columns["score"]
+	if score.DataType != "float" || score.MissingCount != 1 {
+		t.Errorf("Expected float score with 1 NULL, got %s with %d missing", score.DataType, score.MissingCount)
+	}
+	if profile.Columns["id"].DataType != "integer" {
+		t.Errorf("Expected integer id, got %s", profile.Columns["id"].DataType)
+	}
+}
+
+func TestProfileSQLiteTables(t *testing.T) {
+	path := createTestSQLite(t)
+
+	tables, err := ListSQLiteTables(path)
+	if err != nil {
+		t.Fatalf("Failed to list tables: %v", err)
+	}
+	if len(tables) != 2 || tables[0] != "order items" || tables[1] != "users" {
+		t.Errorf("Unexpected tables: %v", tables)
+	}
+
+	profiles, err := ProfileSQLiteTables(context.Background(), path, Options{})
+	if err != nil {
+		t.Fatalf("Failed to profile tables: %v", err)
+	}
+	if len(profiles) != 2 {
+		t.Fatalf("Expected 2 profiles, got %d", len(profiles))
+	}
+	if profiles[0].DuplicateRows != 1 {
+		t.Errorf("Expected 1 duplicate row in order items, got %d", profiles[0].DuplicateRows)
+	}
+}
+
+func TestProfileSQLiteErrors(t *testing.T) {
+	path := createTestSQLite(t)
+
+	if _, err := ProfileDataset(path); err == nil || !strings.Contains(err.Error(), "--table") {
+		t.Errorf("Expected an error asking for --table, got %v", err)
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{Table: "missing"}); err == nil {
+		t.Error("Expected an error for an unknown table")
+	}
+
+	csvPath := filepath.Join(t.TempDir(), "data.db")
+	if err := os.WriteFile(csvPath, []byte("a,b\n1,2\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if IsSQLite(csvPath) {
+		t.Error("Expected a CSV named .db not to be detected as SQLite")
+	}
+}
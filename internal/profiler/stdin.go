@@ -0,0 +1,57 @@
+package profiler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	StdinSource = "-"
+
+	FormatCSV   = "csv"
+	FormatTSV   = "tsv"
+	FormatJSONL = "jsonl"
+)
+
+// fileFormat picks the reader for a file: the --format option when given,
+// otherwise the file extension.
+func fileFormat(filePath string, opts Options) string {
+	if opts.Format != "" {
+		return opts.Format
+	}
+
+	switch strings.ToLower(filepath.Ext(trimCompressionExt(filePath))) {
+	case ".tsv", ".tab":
+		return FormatTSV
+	case ".jsonl", ".ndjson":
+		return FormatJSONL
+	case ".parquet":
+		return "parquet"
+	case ".avro":
+		return "avro"
+	case ".orc":
+		return "orc"
+	case ".xml":
+		return FormatXML
+	case ".json":
+		return "json"
+	default:
+		return FormatCSV
+	}
+}
+
+// profileStdin profiles data piped to standard input. The input cannot be
+// seeked or measured, so the file size is left at 0 and reports omit it.
+func profileStdin(opts Options) (*DatasetProfile, error) {
+	switch opts.Format {
+	case FormatTSV:
+		return profileDelimited(os.Stdin, "stdin", 0, '\t', "TSV", opts)
+	case FormatJSONL:
+		return profileJSONL(os.Stdin, "stdin", 0, opts)
+	case FormatXML:
+		return profileXML(os.Stdin, "stdin", 0, opts)
+	default:
+		return profileDelimited(os.Stdin, "stdin", 0, 0, "CSV", opts)
+	}
+}
@@ -0,0 +1,82 @@
+package profiler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func withStdin(t *testing.T, content string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "stdin")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+
+	stdin := os.Stdin
+	os.Stdin = file
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		file.Close()
+	})
+}
+
+func TestProfileStdin(t *testing.T) {
+	tests := []struct {
+		format  string
+		content string
+		want    string
+	}{
+		{"", "a,b\n1,x\n2,y\n", "CSV"},
+		{FormatTSV, "a\tb\n1\tx\n2\ty\n", "TSV"},
+		{FormatJSONL, "{\"a\": 1, \"b\": \"x\"}\n{\"a\": 2, \"b\": \"y\"}\n", "JSONL"},
+	}
+
+	for _, tt := range tests {
+		withStdin(t, tt.content)
+
+		profile, err := ProfileDatasetWithOptions(StdinSource, Options{Format: tt.format})
+		if err != nil {
+			t.Fatalf("Failed to profile %s from stdin: %v", tt.want, err)
+		}
+
+		if profile.Format != tt.want || profile.Filename != "stdin" || profile.FileSize != 0 {
+			t.Errorf("Expected %s from stdin without a size, got %s %s %d", tt.want, profile.Format, profile.Filename, profile.FileSize)
+		}
+		if profile.RowCount != 2 || profile.ColumnCount != 2 {
+			t.Errorf("Expected 2 rows and 2 columns for %s, got %d and %d", tt.want, profile.RowCount, profile.ColumnCount)
+		}
+	}
+}
+
+func TestFileFormat(t *testing.T) {
+	tests := map[string]string{
+		"data.csv":         FormatCSV,
+		"data.TSV":         FormatTSV,
+		"events.ndjson":    FormatJSONL,
+		"data.parquet":     "parquet",
+		"data.txt":         FormatCSV,
+		"data.tsv.gz":      FormatTSV,
+		"events.jsonl.zst": FormatJSONL,
+		"feed.xml.gz":      FormatXML,
+	}
+	for path, want := range tests {
+		if got := fileFormat(path, Options{}); got != want {
+			t.Errorf("fileFormat(%s) = %s, want %s", path, got, want)
+		}
+	}
+
+	if got := fileFormat("data.csv", Options{Format: FormatJSONL}); got != FormatJSONL {
+		t.Errorf("Expected --format to override the extension, got %s", got)
+	}
+
+	if err := (Options{Format: "yaml"}).validate(); err == nil {
+		t.Error("Expected an unsupported format to be rejected")
+	}
+}
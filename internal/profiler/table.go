@@ -0,0 +1,384 @@
+package profiler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/remote"
+	"github.com/parquet-go/parquet-go"
+)
+
+const (
+	FormatDelta   = "delta"
+	FormatIceberg = "iceberg"
+)
+
+// errTableFileMissing marks a table file that does not exist, locally or
+// remotely.
+var errTableFileMissing = errors.New("file not found")
+
+// tableFormat returns FormatDelta or FormatIceberg when source is a
+// lakehouse table: a local directory with a _delta_log or Iceberg metadata,
+// an Iceberg metadata file, or any source with --format delta or iceberg.
+// Remote directories cannot be listed, so they need --format. A local
+// directory of key=value partitions is FormatHive.
+func tableFormat(source string, opts Options) string {
+	switch opts.Format {
+	case FormatDelta, FormatIceberg, FormatHive:
+		return opts.Format
+	}
+
+	if strings.HasSuffix(strings.ToLower(remote.Path(source)), ".metadata.json") {
+		return FormatIceberg
+	}
+	if remote.IsURL(source) {
+		return ""
+	}
+
+	if info, err := os.Stat(filepath.Join(source, "_delta_log")); err == nil && info.IsDir() {
+		return FormatDelta
+	}
+	if _, err := os.Stat(filepath.Join(source, "metadata", "version-hint.text")); err == nil {
+		return FormatIceberg
+	}
+	if matches, _ := filepath.Glob(filepath.Join(source, "metadata", "*.metadata.json")); len(matches) > 0 {
+		return FormatIceberg
+	}
+	if isPartitioned(source) {
+		return FormatHive
+	}
+	return ""
+}
+
+// tableStore reads the files of a table rooted at a local directory or a
+// remote URL.
+type tableStore struct {
+	root     string
+	remote   bool
+	location string          // where the table metadata says the table lives
+	ctx      context.Context // cancels remote reads
+}
+
+func newTableStore(ctx context.Context, root string) *tableStore {
+	return &tableStore{root: root, remote: remote.IsURL(root), ctx: ctx}
+}
+
+// resolve maps a path from the table metadata to a local path or URL. Paths
+// are relative to the table root or absolute; absolute paths under the
+// recorded table location are taken relative to the root, so a table that
+// was copied or moved can still be read.
+func (s *tableStore) resolve(location string) string {
+	if s.location != "" {
+		base := strings.TrimSuffix(s.location, "/") + "/"
+		if strings.HasPrefix(location, base) {
+			return s.join(strings.TrimPrefix(location, base))
+		}
+	}
+
+	switch {
+	case remote.IsURL(location):
+		return location
+	case strings.HasPrefix(location, "file:"):
+		if u, err := url.Parse(location); err == nil && u.Path != "" {
+			return filepath.FromSlash(u.Path)
+		}
+		return strings.TrimPrefix(location, "file:")
+	case path.IsAbs(location) || filepath.IsAbs(location):
+		return location
+	default:
+		return s.join(location)
+	}
+}
+
+func (s *tableStore) join(rel string) string {
+	if s.remote {
+		return remote.Join(s.root, rel)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(rel))
+}
+
+// open opens a file named by the table metadata.
+func (s *tableStore) open(location string) (io.ReadCloser, error) {
+	resolved := s.resolve(location)
+
+	if remote.IsURL(resolved) {
+		body, _, err := remote.Open(resolved, remote.OpenOptions{Context: s.ctx})
+		if errors.Is(err, remote.ErrNotFound) {
+			return nil, fmt.Errorf("%s: %w", location, errTableFileMissing)
+		}
+		return body, err
+	}
+
+	file, err := os.Open(resolved)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%s: %w", location, errTableFileMissing)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return file, nil
+}
+
+// readAll reads a whole file named by the table metadata.
+func (s *tableStore) readAll(location string) ([]byte, error) {
+	r, err := s.open(location)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", location, err)
+	}
+	return data, nil
+}
+
+// openParquet opens a data file for random access.
+func (s *tableStore) openParquet(location string) (*parquet.File, int64, func(), error) {
+	resolved := s.resolve(location)
+
+	var r io.ReaderAt
+	var size int64
+	closer := func() {}
+
+	if remote.IsURL(resolved) {
+		ra, info, err := remote.NewReaderAt(s.ctx, resolved)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		r, size = ra, info.Size
+	} else {
+		file, err := os.Open(resolved)
+		if err != nil {
+			return nil, 0, nil, fmt.Errorf("failed to open data file: %w", err)
+		}
+		info, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return nil, 0, nil, fmt.Errorf("failed to get file stats: %w", err)
+		}
+		r, size, closer = file, info.Size(), func() { file.Close() }
+	}
+
+	pf, err := parquet.OpenFile(r, size)
+	if err != nil {
+		closer()
+		return nil, 0, nil, fmt.Errorf("failed to read Parquet file %s: %w", location, err)
+	}
+	return pf, size, closer, nil
+}
+
+// list returns the names of the files in a directory of a local table, or
+// false for a remote table, whose directories cannot be listed.
+func (s *tableStore) list(dir string) ([]string, bool) {
+	if s.remote {
+		return nil, false
+	}
+
+	entries, err := os.ReadDir(s.join(dir))
+	if err != nil {
+		return nil, true
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, true
+}
+
+// tableSnapshot is the state of a table at one version: its columns and the
+// data files that make it up.
+type tableSnapshot struct {
+	store      *tableStore
+	format     string
+	version    string // Delta version or Iceberg snapshot id
+	columns    []string
+	skipped    []string          // nested columns, which are not profiled
+	partitions []string          // columns whose values are kept in the metadata
+	physical   map[string]string // column names in the data files, when they differ
+	files      []tableFile
+	notes      []string
+}
+
+// tableFile is an active data file. Partition values are added as columns
+// when the file itself does not hold them.
+type tableFile struct {
+	path       string
+	size       int64
+	partitions map[string]*string
+}
+
+// profileTable profiles the latest snapshot of a Delta Lake or Iceberg
+// table by reading its active Parquet data files one after another.
+func profileTable(source, format string, opts Options) (*DatasetProfile, error) {
+	startTime := time.Now()
+
+	if err := opts.textOnly(tableFormatName(format)); err != nil {
+		return nil, err
+	}
+	if source == StdinSource {
+		return nil, fmt.Errorf("--format %s needs a table directory, not stdin", format)
+	}
+
+	var snapshot *tableSnapshot
+	var err error
+	if format == FormatDelta {
+		snapshot, err = readDeltaSnapshot(newTableStore(opts.context(), source))
+	} else {
+		snapshot, err = readIcebergSnapshot(opts.context(), source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var size int64
+	for _, file := range snapshot.files {
+		size += file.size
+	}
+
+	name := path.Base(strings.TrimSuffix(remote.Path(source), "/"))
+	if !remote.IsURL(source) {
+		name = filepath.Base(filepath.Clean(source))
+	}
+	if snapshot.format == FormatIceberg && strings.HasSuffix(name, ".metadata.json") {
+		name = path.Base(path.Dir(path.Dir(filepath.ToSlash(source))))
+	}
+
+	profile := newDatasetProfile(name, size, tableFormatName(snapshot.format), snapshot.columns)
+
+	reader := newTableRecordReader(snapshot)
+	defer reader.close()
+
+	if err := profileRows(profile, snapshot.columns, reader.next, opts); err != nil {
+		return nil, err
+	}
+
+	profile.Notes = append(profile.Notes, snapshot.notes...)
+	if len(snapshot.partitions) > 0 {
+		profile.Notes = append(profile.Notes, fmt.Sprintf(
+			"Partition columns read from the table metadata: %s", strings.Join(snapshot.partitions, ", ")))
+	}
+	if len(snapshot.skipped) > 0 {
+		profile.Notes = append(profile.Notes, fmt.Sprintf(
+			"Skipped nested or repeated columns: %s", strings.Join(snapshot.skipped, ", ")))
+	}
+
+	profile.QualityScore = CalculateQualityScore(profile)
+	profile.ProcessingTime = time.Since(startTime)
+
+	return profile, nil
+}
+
+func tableFormatName(format string) string {
+	if format == FormatDelta {
+		return "Delta"
+	}
+	return "Iceberg"
+}
+
+// tableRecordReader reads the records of every data file of a snapshot in
+// turn, lining their columns up by name with the table schema. Columns a
+// file lacks, such as those added after it was written, are empty.
+type tableRecordReader struct {
+	snapshot *tableSnapshot
+	index    int
+	current  *parquetRecordReader
+	closer   func()
+	mapping  []int
+	partVals []string
+}
+
+func newTableRecordReader(snapshot *tableSnapshot) *tableRecordReader {
+	return &tableRecordReader{snapshot: snapshot, index: -1}
+}
+
+func (r *tableRecordReader) next() ([]string, error) {
+	for {
+		if r.current != nil {
+			record, err := r.current.next()
+			if err == nil {
+				return r.align(record), nil
+			}
+			if err != io.EOF {
+				return nil, fmt.Errorf("%s: %w", r.snapshot.files[r.index].path, err)
+			}
+			r.close()
+		}
+
+		r.index++
+		if r.index >= len(r.snapshot.files) {
+			return nil, io.EOF
+		}
+		if err := r.openFile(r.snapshot.files[r.index]); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (r *tableRecordReader) openFile(file tableFile) error {
+	pf, _, closer, err := r.snapshot.store.openParquet(file.path)
+	if err != nil {
+		return err
+	}
+
+	columns, _ := parquetColumns(pf)
+	byName := make(map[string]int, len(columns))
+	for i, col := range columns {
+		byName[col.name] = i
+	}
+
+	r.mapping = make([]int, len(r.snapshot.columns))
+	r.partVals = make([]string, len(r.snapshot.columns))
+	for i, name := range r.snapshot.columns {
+		r.mapping[i] = -1
+		if physical, ok := r.snapshot.physical[name]; ok {
+			name = physical
+		}
+		if index, ok := byName[name]; ok {
+			r.mapping[i] = index
+		} else if value := file.partitions[r.snapshot.columns[i]]; value != nil {
+			r.partVals[i] = *value
+		}
+	}
+
+	r.current = newParquetRecordReader(pf, columns, false)
+	r.closer = closer
+	return nil
+}
+
+func (r *tableRecordReader) align(record []string) []string {
+	aligned := make([]string, len(r.mapping))
+	for i, index := range r.mapping {
+		if index >= 0 {
+			aligned[i] = record[index]
+		} else {
+			aligned[i] = r.partVals[i]
+		}
+	}
+	return aligned
+}
+
+func (r *tableRecordReader) close() {
+	if r.current != nil {
+		r.current.closeStreams()
+		r.current = nil
+	}
+	if r.closer != nil {
+		r.closer()
+		r.closer = nil
+	}
+}
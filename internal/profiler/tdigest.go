@@ -0,0 +1,162 @@
+package profiler
+
+import (
+	"math"
+	"sort"
+)
+
+const tDigestCompression = 100
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// tDigest is a merging t-digest: a sorted set of weighted centroids that are
+// small near the tails and larger in the middle, giving accurate quantiles in
+// bounded memory.
+type tDigest struct {
+	compression float64
+	centroids   []centroid
+	buffer      []centroid
+	count       float64
+	min         float64
+	max         float64
+}
+
+func newTDigest(compression float64) *tDigest {
+	return &tDigest{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+func (t *tDigest) add(x, weight float64) {
+	t.buffer = append(t.buffer, centroid{mean: x, weight: weight})
+	t.count += weight
+	t.min = math.Min(t.min, x)
+	t.max = math.Max(t.max, x)
+
+	if len(t.buffer) >= int(t.compression)*5 {
+		t.compress()
+	}
+}
+
+func (t *tDigest) compress() {
+	if len(t.buffer) == 0 {
+		return
+	}
+
+	all := append(t.centroids, t.buffer...)
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	merged := make([]centroid, 0, len(t.centroids)+1)
+	current := all[0]
+	soFar := 0.0
+
+	for _, c := range all[1:] {
+		q0 := soFar / t.count
+		q2 := (soFar + current.weight + c.weight) / t.count
+
+		if t.scale(q2)-t.scale(q0) <= 1 {
+			current.mean += (c.mean - current.mean) * c.weight / (current.weight + c.weight)
+			current.weight += c.weight
+			continue
+		}
+
+		merged = append(merged, current)
+		soFar += current.weight
+		current = c
+	}
+
+	t.centroids = append(merged, current)
+	t.buffer = t.buffer[:0]
+}
+
+// merge adds the centroids of o as weighted points.
+func (t *tDigest) merge(o *tDigest) {
+	o.compress()
+	for _, c := range o.centroids {
+		t.add(c.mean, c.weight)
+	}
+	t.min = math.Min(t.min, o.min)
+	t.max = math.Max(t.max, o.max)
+}
+
+func (t *tDigest) scale(q float64) float64 {
+	return t.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+func (t *tDigest) quantile(q float64) float64 {
+	t.compress()
+
+	switch {
+	case len(t.centroids) == 0:
+		return math.NaN()
+	case q <= 0:
+		return t.min
+	case q >= 1:
+		return t.max
+	case len(t.centroids) == 1:
+		return t.centroids[0].mean
+	}
+
+	index := q * t.count
+
+	first := t.centroids[0]
+	if index < first.weight/2 {
+		return t.min + index/(first.weight/2)*(first.mean-t.min)
+	}
+
+	cumulative := 0.0
+	for i := 0; i < len(t.centroids)-1; i++ {
+		c, next := t.centroids[i], t.centroids[i+1]
+		mid := cumulative + c.weight/2
+		nextMid := cumulative + c.weight + next.weight/2
+
+		if index < nextMid {
+			return c.mean + (index-mid)/(nextMid-mid)*(next.mean-c.mean)
+		}
+		cumulative += c.weight
+	}
+
+	last := t.centroids[len(t.centroids)-1]
+	lastMid := t.count - last.weight/2
+	return last.mean + (index-lastMid)/(last.weight/2)*(t.max-last.mean)
+}
+
+// cdf returns the estimated fraction of values less than or equal to x.
+func (t *tDigest) cdf(x float64) float64 {
+	t.compress()
+
+	if len(t.centroids) == 0 || x < t.min {
+		return 0
+	}
+	if x >= t.max {
+		return 1
+	}
+	if len(t.centroids) == 1 {
+		return (x - t.min) / (t.max - t.min)
+	}
+
+	first := t.centroids[0]
+	if x < first.mean {
+		return (x - t.min) / (first.mean - t.min) * first.weight / 2 / t.count
+	}
+
+	cumulative := 0.0
+	for i := 0; i < len(t.centroids)-1; i++ {
+		c, next := t.centroids[i], t.centroids[i+1]
+		if x < next.mean {
+			mid := cumulative + c.weight/2
+			nextMid := cumulative + c.weight + next.weight/2
+			return (mid + (x-c.mean)/(next.mean-c.mean)*(nextMid-mid)) / t.count
+		}
+		cumulative += c.weight
+	}
+
+	last := t.centroids[len(t.centroids)-1]
+	lastMid := t.count - last.weight/2
+	return (lastMid + (x-last.mean)/(t.max-last.mean)*last.weight/2) / t.count
+}
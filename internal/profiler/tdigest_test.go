@@ -0,0 +1,63 @@
+package profiler
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestTDigestQuantiles(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	digest := newTDigest(tDigestCompression)
+	values := make([]float64, 0, 50000)
+
+	for i := 0; i < 50000; i++ {
+		v := rng.NormFloat64()*10 + 100
+		values = append(values, v)
+		digest.add(v, 1)
+	}
+	sort.Float64s(values)
+
+	for _, q := range []float64{0.01, 0.1, 0.5, 0.9, 0.99} {
+		expected := values[int(q*float64(len(values)))]
+		if got := digest.quantile(q); math.Abs(got-expected) > 0.5 {
+			t.Errorf("Quantile %.2f: expected about %.3f, got %.3f", q, expected, got)
+		}
+	}
+
+	if digest.quantile(0) != values[0] || digest.quantile(1) != values[len(values)-1] {
+		t.Error("Expected extreme quantiles to be the exact min and max")
+	}
+}
+
+func TestTDigestCDF(t *testing.T) {
+	digest := newTDigest(tDigestCompression)
+	for i := 0; i < 10000; i++ {
+		digest.add(float64(i), 1)
+	}
+
+	for _, x := range []float64{100, 2500, 5000, 9900} {
+		if got := digest.cdf(x); math.Abs(got-x/10000) > 0.01 {
+			t.Errorf("CDF(%v): expected about %.3f, got %.3f", x, x/10000, got)
+		}
+	}
+
+	if digest.cdf(-1) != 0 || digest.cdf(10000) != 1 {
+		t.Error("Expected CDF to be 0 below the minimum and 1 at the maximum")
+	}
+}
+
+func TestTDigestWeighted(t *testing.T) {
+	digest := newTDigest(tDigestCompression)
+	digest.add(1, 90)
+	digest.add(100, 10)
+
+	if got := digest.quantile(0.3); got != 1 {
+		t.Errorf("Expected 30th percentile 1, got %v", got)
+	}
+
+	if got := digest.quantile(0.99); got != 100 {
+		t.Errorf("Expected 99th percentile 100, got %v", got)
+	}
+}
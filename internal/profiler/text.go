@@ -0,0 +1,386 @@
+package profiler
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	maxTextPatterns      = 100 // distinct patterns counted per column
+	maxPatternLength     = 40  // characters of a value turned into its pattern
+	dominantPatternShare = 0.9 // share of values a pattern needs to be expected of all
+)
+
+// Casings of the letters in a value.
+const (
+	CasingLower = "lower"
+	CasingUpper = "upper"
+	CasingTitle = "title"
+	CasingMixed = "mixed"
+)
+
+// Kinds of formatting problems in the values of a string column.
+const (
+	FormattingPadded       = "padded_whitespace"   // leading or trailing whitespace
+	FormattingEmbedded     = "embedded_whitespace" // tabs or line breaks inside the value
+	FormattingNonPrintable = "non_printable"       // control characters such as NUL, or invisible ones such as zero-width spaces
+	FormattingCaseVariants = "inconsistent_casing" // spelled differently, in case or surrounding spaces, from a more common spelling
+)
+
+// formattingKinds are the kinds of formatting problems in the order they
+// are reported.
+var formattingKinds = []string{FormattingPadded, FormattingEmbedded, FormattingNonPrintable, FormattingCaseVariants}
+
+// formattingDescriptions tell what the values of each kind of formatting
+// problem have.
+var formattingDescriptions = map[string]string{
+	FormattingPadded:       "leading or trailing whitespace",
+	FormattingEmbedded:     "tabs or line breaks inside",
+	FormattingNonPrintable: "non-printable characters",
+	FormattingCaseVariants: "a more common spelling differing only in case or surrounding spaces",
+}
+
+// maxFormattingExamples is the number of values kept as examples of each
+// kind of formatting problem.
+const maxFormattingExamples = 3
+
+// invisibleRunes print as nothing: the soft hyphen, the zero-width space,
+// the word joiner and the byte order mark.
+const invisibleRunes = "\u00ad\u200b\u2060\ufeff"
+
+// Formatting counts the values of a string column with a kind of
+// formatting problem, with the first of them as examples.
+type Formatting struct {
+	Kind     string
+	Count    int
+	Examples []string // withheld by --redact
+}
+
+// TextStats describes the values of a string column. Lengths are in
+// characters.
+type TextStats struct {
+	MinLength      int
+	MaxLength      int
+	AvgLength      float64
+	WhitespaceOnly int          // values of nothing but spaces
+	Casing         string       // most common casing of the values with letters, empty when none have any
+	CasingPercent  float64      // share of the values with letters in that casing
+	Patterns       []ValueCount // most common shapes, letters as A and digits as 9
+	Formatting     []Formatting // formatting problems found, in the order of formattingKinds
+}
+
+// textStats accumulates the lengths, casings and patterns of a column.
+// Values of a new pattern once maxTextPatterns are known go uncounted in
+// the patterns but still count towards the total.
+type textStats struct {
+	count      int
+	minLength  int
+	maxLength  int
+	total      int64
+	whitespace int
+	casings    map[string]int
+	patterns   map[string]int
+	formatting map[string]*Formatting
+}
+
+func newTextStats() *textStats {
+	return &textStats{casings: make(map[string]int), patterns: make(map[string]int), formatting: make(map[string]*Formatting)}
+}
+
+func (s *textStats) add(value string) {
+	length := utf8.RuneCountInString(value)
+	if s.count == 0 || length < s.minLength {
+		s.minLength = length
+	}
+	if length > s.maxLength {
+		s.maxLength = length
+	}
+	s.total += int64(length)
+	s.count++
+
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		s.whitespace++
+	} else if trimmed != value {
+		s.addFormatting(FormattingPadded, 1, value)
+	}
+	if strings.ContainsAny(trimmed, "\t\n\r") {
+		s.addFormatting(FormattingEmbedded, 1, value)
+	}
+	if nonPrintable(value) {
+		s.addFormatting(FormattingNonPrintable, 1, value)
+	}
+	if casing := valueCasing(value); casing != "" {
+		s.casings[casing]++
+	}
+	s.addPattern(textPattern(value), 1)
+}
+
+func (s *textStats) addPattern(pattern string, n int) {
+	if _, ok := s.patterns[pattern]; ok || len(s.patterns) < maxTextPatterns {
+		s.patterns[pattern] += n
+	}
+}
+
+// addFormatting counts n values of a kind of formatting problem, keeping
+// the first distinct examples.
+func (s *textStats) addFormatting(kind string, n int, examples ...string) {
+	f := s.formatting[kind]
+	if f == nil {
+		f = &Formatting{Kind: kind}
+		s.formatting[kind] = f
+	}
+	f.Count += n
+	for _, example := range examples {
+		if len(f.Examples) < maxFormattingExamples && !slices.Contains(f.Examples, example) {
+			f.Examples = append(f.Examples, example)
+		}
+	}
+}
+
+func (s *textStats) merge(o *textStats) {
+	if o.count == 0 {
+		return
+	}
+	if s.count == 0 || o.minLength < s.minLength {
+		s.minLength = o.minLength
+	}
+	if o.maxLength > s.maxLength {
+		s.maxLength = o.maxLength
+	}
+	s.total += o.total
+	s.count += o.count
+	s.whitespace += o.whitespace
+
+	for casing, n := range o.casings {
+		s.casings[casing] += n
+	}
+	for pattern, n := range o.patterns {
+		s.addPattern(pattern, n)
+	}
+	for kind, f := range o.formatting {
+		s.addFormatting(kind, f.Count, f.Examples...)
+	}
+}
+
+// apply fills in the text statistics of col and flags whitespace-only values,
+// values that break a pattern almost all others follow, and formatting
+// problems. Spellings of the same value are compared while counter counts
+// every value.
+func (s *textStats) apply(col *ColumnProfile, counter *valueCounter) {
+	if s.count == 0 {
+		return
+	}
+
+	stats := &TextStats{
+		MinLength:      s.minLength,
+		MaxLength:      s.maxLength,
+		AvgLength:      float64(s.total) / float64(s.count),
+		WhitespaceOnly: s.whitespace,
+		Patterns:       getTopValues(s.patterns, 5),
+	}
+
+	lettered := 0
+	for _, casing := range []string{CasingLower, CasingUpper, CasingTitle, CasingMixed} {
+		n := s.casings[casing]
+		lettered += n
+		if n > s.casings[stats.Casing] {
+			stats.Casing = casing
+		}
+	}
+	if lettered > 0 {
+		stats.CasingPercent = float64(s.casings[stats.Casing]) / float64(lettered) * 100
+	}
+
+	formattings := s.formatting
+	if counter != nil && counter.counts != nil {
+		if variants := caseVariants(counter.counts); variants.Count > 0 {
+			formattings = maps.Clone(s.formatting)
+			formattings[FormattingCaseVariants] = variants
+		}
+	}
+	for _, kind := range formattingKinds {
+		if f := formattings[kind]; f != nil {
+			formatting := *f
+			if col.ExamplesRedacted {
+				formatting.Examples = nil
+			}
+			stats.Formatting = append(stats.Formatting, formatting)
+		}
+	}
+
+	col.Text = stats
+
+	if s.whitespace > 0 {
+		col.QualityIssues = append(col.QualityIssues, QualityIssue{
+			Type:        "whitespace_only",
+			Description: fmt.Sprintf("%d whitespace-only values", s.whitespace),
+			Severity:    1,
+		})
+	}
+
+	if len(stats.Patterns) > 1 {
+		dominant := stats.Patterns[0]
+		others := s.count - dominant.Count
+		if float64(dominant.Count) >= float64(s.count)*dominantPatternShare {
+			col.QualityIssues = append(col.QualityIssues, QualityIssue{
+				Type: "pattern_mismatch",
+				Description: fmt.Sprintf("%d values (%.2f%%) do not match the pattern '%s' of the others",
+					others, float64(others)/float64(s.count)*100, dominant.Value),
+				Severity: 1,
+			})
+		}
+	}
+
+	for _, f := range stats.Formatting {
+		col.QualityIssues = append(col.QualityIssues, QualityIssue{
+			Type:        f.Kind,
+			Description: f.String(),
+			Severity:    1,
+		})
+	}
+}
+
+func (f Formatting) String() string {
+	s := fmt.Sprintf("%d values with %s", f.Count, formattingDescriptions[f.Kind])
+	if len(f.Examples) > 0 {
+		quoted := make([]string, len(f.Examples))
+		for i, example := range f.Examples {
+			quoted[i] = fmt.Sprintf("%q", example)
+		}
+		s += ", such as " + strings.Join(quoted, ", ")
+	}
+	return s
+}
+
+// spellingBefore orders tied spellings of a value: without surrounding
+// spaces first, then in byte order.
+func spellingBefore(a, b string) bool {
+	if trimmedA, trimmedB := a == strings.TrimSpace(a), b == strings.TrimSpace(b); trimmedA != trimmedB {
+		return trimmedA
+	}
+	return a < b
+}
+
+// nonPrintable reports whether value holds a control character other than
+// a tab or a line break, or an invisible one.
+func nonPrintable(value string) bool {
+	for _, r := range value {
+		if unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r' {
+			return true
+		}
+		if strings.ContainsRune(invisibleRunes, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// caseVariants counts the values spelled differently, in case or
+// surrounding spaces, from the most common spelling of the same value, on a
+// tie the first in order without surrounding spaces. The examples are the
+// most common of them.
+func caseVariants(counts map[string]int) *Formatting {
+	spellings := make(map[string]ValueCount)
+	for value, n := range counts {
+		key := strings.ToLower(strings.TrimSpace(value))
+		if key == "" {
+			continue
+		}
+		if best, ok := spellings[key]; !ok || n > best.Count || (n == best.Count && spellingBefore(value, best.Value)) {
+			spellings[key] = ValueCount{Value: value, Count: n}
+		}
+	}
+
+	var variants []ValueCount
+	for value, n := range counts {
+		key := strings.ToLower(strings.TrimSpace(value))
+		if best, ok := spellings[key]; ok && best.Value != value {
+			variants = append(variants, ValueCount{Value: value, Count: n})
+		}
+	}
+	sort.Slice(variants, func(i, j int) bool {
+		if variants[i].Count != variants[j].Count {
+			return variants[i].Count > variants[j].Count
+		}
+		return variants[i].Value < variants[j].Value
+	})
+
+	f := &Formatting{Kind: FormattingCaseVariants}
+	for _, v := range variants {
+		f.Count += v.Count
+		if len(f.Examples) < maxFormattingExamples {
+			f.Examples = append(f.Examples, v.Value)
+		}
+	}
+	return f
+}
+
+// valueCasing classifies the letters of a value, or returns "" when it has
+// none.
+func valueCasing(value string) string {
+	upper, lower := 0, 0
+	titled := true
+	start := true
+	for _, r := range value {
+		isLetter := unicode.IsLetter(r)
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+			if !start {
+				titled = false
+			}
+		case unicode.IsLower(r):
+			lower++
+			if start {
+				titled = false
+			}
+		}
+		start = !isLetter && !unicode.IsDigit(r)
+	}
+
+	switch {
+	case upper == 0 && lower == 0:
+		return ""
+	case upper == 0:
+		return CasingLower
+	case lower == 0:
+		return CasingUpper
+	case titled:
+		return CasingTitle
+	default:
+		return CasingMixed
+	}
+}
+
+// textPattern maps letters to A, digits to 9 and whitespace to a space,
+// keeping other characters, so AB-1234 and XY-9876 share the pattern
+// AA-9999. Long values are cut to maxPatternLength characters followed by
+// an ellipsis.
+func textPattern(value string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range value {
+		if n == maxPatternLength {
+			b.WriteString("…")
+			break
+		}
+		switch {
+		case unicode.IsLetter(r):
+			b.WriteByte('A')
+		case unicode.IsDigit(r):
+			b.WriteByte('9')
+		case unicode.IsSpace(r):
+			b.WriteByte(' ')
+		default:
+			b.WriteRune(r)
+		}
+		n++
+	}
+	return b.String()
+}
@@ -0,0 +1,195 @@
+package profiler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValueCasing(t *testing.T) {
+	tests := map[string]string{
+		"abc-12":        CasingLower,
+		"ABC-12":        CasingUpper,
+		"New York":      CasingTitle,
+		"O'Brien-Smith": CasingTitle,
+		"McDonald":      CasingMixed,
+		"iPhone":        CasingMixed,
+		"12-34":         "",
+	}
+	for value, want := range tests {
+		if got := valueCasing(value); got != want {
+			t.Errorf("valueCasing(%q): expected %q, got %q", value, want, got)
+		}
+	}
+}
+
+func TestTextPattern(t *testing.T) {
+	tests := map[string]string{
+		"ABC-1234":                              "AAA-9999",
+		"ab c_9":                                "AA A_9",
+		"Ünïcode 42":                            "AAAAAAA 99",
+		strings.Repeat("x", maxPatternLength+5): strings.Repeat("A", maxPatternLength) + "…",
+	}
+	for value, want := range tests {
+		if got := textPattern(value); got != want {
+			t.Errorf("textPattern(%q): expected %q, got %q", value, want, got)
+		}
+	}
+}
+
+func TestTextStats(t *testing.T) {
+	first, second := newTextStats(), newTextStats()
+	for i := 0; i < 30; i++ {
+		first.add(fmt.Sprintf("ABC-%04d", i))
+	}
+	for i := 0; i < 67; i++ {
+		second.add(fmt.Sprintf("XYZ-%04d", i))
+	}
+	second.add("abc-12")
+	second.add("  ")
+	second.add("Ab")
+	first.merge(second)
+
+	col := &ColumnProfile{Name: "code"}
+	first.apply(col, nil)
+
+	stats := col.Text
+	if stats == nil {
+		t.Fatal("Expected text stats")
+	}
+	if stats.MinLength != 2 || stats.MaxLength != 8 {
+		t.Errorf("Expected lengths 2 to 8, got %d to %d", stats.MinLength, stats.MaxLength)
+	}
+	if want := float64(97*8+6+2+2) / 100; stats.AvgLength != want {
+		t.Errorf("Expected average length %v, got %v", want, stats.AvgLength)
+	}
+	if stats.WhitespaceOnly != 1 {
+		t.Errorf("Expected 1 whitespace-only value, got %d", stats.WhitespaceOnly)
+	}
+	if stats.Casing != CasingUpper || fmt.Sprintf("%.2f", stats.CasingPercent) != "97.98" {
+		t.Errorf("Expected 97.98%% upper case, got %.2f%% %s", stats.CasingPercent, stats.Casing)
+	}
+	if p := stats.Patterns[0]; p.Value != "AAA-9999" || p.Count != 97 || len(stats.Patterns) != 4 {
+		t.Errorf("Expected AAA-9999 as the dominant of 4 patterns, got %v", stats.Patterns)
+	}
+
+	issues := make(map[string]string)
+	for _, issue := range col.QualityIssues {
+		issues[issue.Type] = issue.Description
+	}
+	if issues["whitespace_only"] != "1 whitespace-only values" {
+		t.Errorf("Expected a whitespace-only issue, got %v", col.QualityIssues)
+	}
+	if issues["pattern_mismatch"] != "3 values (3.00%) do not match the pattern 'AAA-9999' of the others" {
+		t.Errorf("Expected a pattern mismatch issue, got %v", col.QualityIssues)
+	}
+}
+
+func TestProfileTextColumns(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("id,sku,name\n")
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&b, "%d,SKU-%03d,Item %d\n", i, i, i)
+	}
+
+	path := filepath.Join(t.TempDir(), "items.csv")
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	profile, err := ProfileDataset(path)
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+
+	if profile.Columns["id"].Text != nil {
+		t.Error("Expected no text stats for a numeric column")
+	}
+	sku := profile.Columns["sku"].Text
+	if sku == nil || len(sku.Patterns) != 1 || sku.Patterns[0].Value != "AAA-999" || sku.Casing != CasingUpper {
+		t.Errorf("Expected every sku to be upper case AAA-999, got %+v", sku)
+	}
+	if name := profile.Columns["name"].Text; name == nil || name.MinLength != 6 || name.MaxLength != 8 || name.Casing != CasingTitle {
+		t.Errorf("Expected title case names of 6 to 8 characters, got %+v", name)
+	}
+}
+
+func TestTextFormatting(t *testing.T) {
+	first, second := newTextStats(), newTextStats()
+	for _, value := range []string{"USA", " USA ", "a\tb", "line\nbreak", "zero\u200bwidth", "nul\x00"} {
+		first.add(value)
+	}
+	for _, value := range []string{"Canada ", "  ", "\ttabbed", "\ufeffBOM"} {
+		second.add(value)
+	}
+	first.merge(second)
+
+	counter := newValueCounter()
+	for _, value := range []string{"USA", "USA", "USA", "usa", " USA ", "Usa", "Usa", "Canada", "CANADA", "  "} {
+		counter.add(value)
+	}
+
+	col := &ColumnProfile{Name: "country"}
+	first.apply(col, counter)
+
+	want := []Formatting{
+		{Kind: FormattingPadded, Count: 3, Examples: []string{" USA ", "Canada ", "\ttabbed"}},
+		{Kind: FormattingEmbedded, Count: 2, Examples: []string{"a\tb", "line\nbreak"}},
+		{Kind: FormattingNonPrintable, Count: 3, Examples: []string{"zero\u200bwidth", "nul\x00", "\ufeffBOM"}},
+		{Kind: FormattingCaseVariants, Count: 5, Examples: []string{"Usa", " USA ", "Canada"}},
+	}
+	if !reflect.DeepEqual(col.Text.Formatting, want) {
+		t.Errorf("Expected formatting %+v, got %+v", want, col.Text.Formatting)
+	}
+
+	issues := make(map[string]string)
+	for _, issue := range col.QualityIssues {
+		issues[issue.Type] = issue.Description
+	}
+	if got := issues[FormattingPadded]; got != `3 values with leading or trailing whitespace, such as " USA ", "Canada ", "\ttabbed"` {
+		t.Errorf("Expected a padded whitespace issue, got %q", got)
+	}
+	if got := issues[FormattingCaseVariants]; !strings.HasPrefix(got, "5 values with a more common spelling differing only in case") {
+		t.Errorf("Expected an inconsistent casing issue, got %q", got)
+	}
+
+	redacted := &ColumnProfile{Name: "country", ExamplesRedacted: true}
+	first.apply(redacted, nil)
+	for _, f := range redacted.Text.Formatting {
+		if len(f.Examples) > 0 || f.Kind == FormattingCaseVariants {
+			t.Errorf("Expected no examples and no spellings compared, got %+v", f)
+		}
+	}
+}
+
+func TestProfileTextFormatting(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("id,country\n")
+	countries := []string{"USA", "Canada", "Mexico", "usa", " USA", "Canada\t"}
+	for i := 0; i < 600; i++ {
+		fmt.Fprintf(&b, "%d,%s\n", i, countries[i%len(countries)])
+	}
+
+	path := filepath.Join(t.TempDir(), "countries.csv")
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	for _, parallel := range []int{0, 4} {
+		withParallelMinChunk(t, 128)
+		profile, err := ProfileDatasetWithOptions(path, Options{Parallel: parallel})
+		if err != nil {
+			t.Fatalf("Failed to profile: %v", err)
+		}
+		want := []Formatting{
+			{Kind: FormattingPadded, Count: 200, Examples: []string{" USA", "Canada\t"}},
+			{Kind: FormattingCaseVariants, Count: 300, Examples: []string{" USA", "Canada\t", "usa"}},
+		}
+		if got := profile.Columns["country"].Text; got == nil || !reflect.DeepEqual(got.Formatting, want) {
+			t.Errorf("parallel %d: expected formatting %+v, got %+v", parallel, want, got)
+		}
+	}
+}
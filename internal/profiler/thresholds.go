@@ -0,0 +1,84 @@
+package profiler
+
+// Thresholds are the cut-offs behind the derived judgments in a profile. They
+// are recorded on the profile so that consumers can re-interpret the raw
+// statistics under a different policy without profiling again.
+type Thresholds struct {
+	MissingValues             SeverityThresholds // column missing rate, %; any missing value is an issue
+	MostlyNullPercent         float64            // column missing rate above which a column is mostly null, %
+	DatasetMissingValues      SeverityThresholds // overall missing rate, %; an issue above Medium
+	DuplicateRows             SeverityThresholds // duplicate row rate, %; any duplicate is an issue
+	Outliers                  SeverityThresholds // share of outliers in a numeric column, %
+	OutlierMethod             string             // zscore, iqr or mad
+	OutlierZScore             float64            // zscore: values further than this many std devs from the mean
+	OutlierIQR                float64            // iqr: values further than this many IQRs outside the quartiles
+	OutlierMAD                float64            // mad: values with a modified z-score above this
+	SkewnessAbs               float64            // absolute skewness above which a numeric column is heavily skewed
+	ImbalancedPercent         float64            // top value share of a categorical column, %
+	SingletonHeavyPercent     float64            // share of the distinct values of a categorical column seen once, %
+	SingletonHeavyMinUnique   int                // distinct values a categorical column needs to be singleton-heavy
+	MixedTypePercent          float64            // share of the values of a column of another family of types than the rest, %
+	RedundantPercent          float64            // share of rows on which two columns match for them to be redundant, %
+	CategoricalMaxUnique      int
+	CategoricalMaxUniqueRatio float64 // unique values per row
+	OpaqueAvgLength           float64 // bytes
+	ImputeMissingPercent      float64 // recommend imputation above this column missing rate, %
+	DeduplicatePercent        float64 // recommend deduplication above this duplicate rate, %
+}
+
+// SeverityThresholds raise an issue to medium severity above Medium and to
+// high severity above High.
+type SeverityThresholds struct {
+	Medium float64
+	High   float64
+}
+
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		MissingValues:             SeverityThresholds{Medium: 5, High: 20},
+		MostlyNullPercent:         50,
+		DatasetMissingValues:      SeverityThresholds{Medium: 5, High: 20},
+		DuplicateRows:             SeverityThresholds{Medium: 5, High: 20},
+		Outliers:                  SeverityThresholds{Medium: 5, High: 10},
+		OutlierMethod:             OutlierMethodZScore,
+		OutlierZScore:             3,
+		OutlierIQR:                1.5,
+		OutlierMAD:                3.5,
+		SkewnessAbs:               2,
+		ImbalancedPercent:         90,
+		SingletonHeavyPercent:     50,
+		SingletonHeavyMinUnique:   10,
+		MixedTypePercent:          10,
+		RedundantPercent:          95,
+		CategoricalMaxUnique:      100,
+		CategoricalMaxUniqueRatio: 0.1,
+		OpaqueAvgLength:           blobAvgLength,
+		ImputeMissingPercent:      5,
+		DeduplicatePercent:        1,
+	}
+}
+
+// EffectiveThresholds returns the thresholds the profile was checked
+// against, or the defaults for profiles that do not record them, such as
+// reports written by older versions.
+func (p *DatasetProfile) EffectiveThresholds() Thresholds {
+	if p.Thresholds == (Thresholds{}) {
+		return DefaultThresholds()
+	}
+	return p.Thresholds
+}
+
+func (s SeverityThresholds) severity(percent float64) int {
+	switch {
+	case percent > s.High:
+		return 3
+	case percent > s.Medium:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func (t Thresholds) isCategorical(uniqueCount, rowCount int) bool {
+	return uniqueCount <= t.CategoricalMaxUnique && float64(uniqueCount) <= float64(rowCount)*t.CategoricalMaxUniqueRatio
+}
@@ -0,0 +1,46 @@
+package profiler
+
+import "testing"
+
+func TestSeverityThresholds(t *testing.T) {
+	s := SeverityThresholds{Medium: 5, High: 20}
+
+	testCases := []struct {
+		percent  float64
+		expected int
+	}{
+		{1, 1},
+		{5, 1},
+		{5.1, 2},
+		{20, 2},
+		{35, 3},
+	}
+
+	for _, tc := range testCases {
+		if got := s.severity(tc.percent); got != tc.expected {
+			t.Errorf("severity(%v): expected %d, got %d", tc.percent, tc.expected, got)
+		}
+	}
+}
+
+func TestIsCategorical(t *testing.T) {
+	thresholds := DefaultThresholds()
+
+	if !thresholds.isCategorical(10, 100) {
+		t.Error("Expected 10 unique values in 100 rows to be categorical")
+	}
+	if thresholds.isCategorical(11, 100) {
+		t.Error("Expected 11 unique values in 100 rows not to be categorical")
+	}
+	if thresholds.isCategorical(101, 100000) {
+		t.Error("Expected more than 100 unique values not to be categorical")
+	}
+}
+
+func TestProfileRecordsThresholds(t *testing.T) {
+	profile := newDatasetProfile("test.csv", 0, "CSV", []string{"a"})
+
+	if profile.Thresholds != DefaultThresholds() {
+		t.Errorf("Expected profiles to record the default thresholds, got %+v", profile.Thresholds)
+	}
+}
@@ -0,0 +1,365 @@
+package profiler
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// windowBuckets is about the number of buckets a time window is split
+	// into. The latest window ends with the bucket of the latest timestamp,
+	// so window boundaries fall on bucket boundaries.
+	windowBuckets = 24
+
+	// maxWindowDistinct bounds the distinct values counted exactly per
+	// column and bucket before estimating with HyperLogLog.
+	maxWindowDistinct = 1000
+
+	windowMissingThreshold = 5.0 // rise in missing percentage points flagged as a regression
+	windowMeanThreshold    = 0.5 // mean shift, in standard deviations of the previous window
+)
+
+// TimeWindows compares the latest window of a time column, such as the last
+// 7 days, with the window before it, to surface recent regressions that the
+// statistics of the whole history wash out.
+type TimeWindows struct {
+	TimeColumn    string
+	Window        time.Duration
+	LatestStart   time.Time
+	LatestEnd     time.Time
+	PreviousStart time.Time
+	LatestRows    int
+	PreviousRows  int
+	Skipped       int           // rows whose time is missing or not a timestamp
+	Columns       []WindowDelta // every other column, in column order
+}
+
+// Regressions are the columns that changed between the two windows.
+func (w *TimeWindows) Regressions() []WindowDelta {
+	changed := make([]WindowDelta, 0)
+	for _, delta := range w.Columns {
+		if len(delta.Changes) > 0 {
+			changed = append(changed, delta)
+		}
+	}
+	return changed
+}
+
+// WindowDelta is a column in the latest and previous windows.
+type WindowDelta struct {
+	Column   string
+	Numeric  bool
+	Latest   WindowStats
+	Previous WindowStats
+	Changes  []string // missing_rate or mean
+}
+
+// MeanShift is the change in mean in standard deviations of the previous
+// window, 0 when it cannot be measured.
+func (d WindowDelta) MeanShift() float64 {
+	if !d.Numeric || d.Previous.StdDev == 0 || d.Previous.Count == 0 || d.Latest.Count == 0 {
+		return 0
+	}
+	return (d.Latest.Mean - d.Previous.Mean) / d.Previous.StdDev
+}
+
+// WindowStats are the statistics of a column within one window.
+type WindowStats struct {
+	Count          int // non-missing values
+	MissingPercent float64
+	Distinct       int
+	Mean           float64 // numeric columns
+	StdDev         float64
+	Min            float64
+	Max            float64
+}
+
+// timeWindows buckets the rows of a pass by their time and keeps the buckets
+// of the latest two windows.
+type timeWindows struct {
+	timeIndex int
+	columns   int
+	width     int64 // bucket width in nanoseconds
+	span      int64 // buckets per window
+	window    time.Duration
+	format    numberFormat
+	dates     dateFormat // of the config and --locale, as timestamps are read before a column's format is detected
+	buckets   map[int64]*windowBucket
+	latest    int64 // bucket of the latest timestamp
+	started   bool
+	skipped   int
+}
+
+type windowBucket struct {
+	rows    int
+	columns []windowColumn
+}
+
+type windowColumn struct {
+	count, missing int
+	numbers        int
+	mean, m2       float64
+	min, max       float64
+	distinct       *distinctCounter
+}
+
+func newTimeWindows(header []string, opts Options) *timeWindows {
+	width := bucketWidth(opts.TimeWindow)
+	dates, _ := opts.dateFormat(opts.TimeColumn)
+	w := &timeWindows{
+		timeIndex: -1,
+		columns:   len(header),
+		width:     width,
+		span:      int64(opts.TimeWindow) / width,
+		window:    opts.TimeWindow,
+		format:    numberFormatNamed(opts.numberFormat()),
+		dates:     dates,
+		buckets:   make(map[int64]*windowBucket),
+	}
+	for i, colName := range header {
+		if colName == opts.TimeColumn {
+			w.timeIndex = i
+			break
+		}
+	}
+	return w
+}
+
+func (w *timeWindows) add(record []string) {
+	var t time.Time
+	ok := false
+	if w.timeIndex < len(record) {
+		t, ok = w.dates.parse(record[w.timeIndex])
+	}
+	if !ok {
+		w.skipped++
+		return
+	}
+
+	index := floorDiv(t.UnixNano(), w.width)
+	if w.started && index <= w.latest-2*w.span {
+		return
+	}
+	if !w.started || index > w.latest {
+		w.latest = index
+		w.started = true
+		w.evict()
+	}
+
+	bucket, ok := w.buckets[index]
+	if !ok {
+		bucket = &windowBucket{columns: make([]windowColumn, w.columns)}
+		w.buckets[index] = bucket
+	}
+	bucket.add(record, w.format)
+}
+
+// evict drops the buckets older than the latest two windows.
+func (w *timeWindows) evict() {
+	for index := range w.buckets {
+		if index <= w.latest-2*w.span {
+			delete(w.buckets, index)
+		}
+	}
+}
+
+// merge folds in the buckets of o, which saw other rows of the same source.
+func (w *timeWindows) merge(o *timeWindows) {
+	w.skipped += o.skipped
+	if !o.started {
+		return
+	}
+	if !w.started || o.latest > w.latest {
+		w.latest = o.latest
+		w.started = true
+	}
+	for index, bucket := range o.buckets {
+		if mine, ok := w.buckets[index]; ok {
+			mine.merge(bucket)
+		} else {
+			w.buckets[index] = bucket
+		}
+	}
+	w.evict()
+}
+
+func (b *windowBucket) add(record []string, format numberFormat) {
+	b.rows++
+	for i := range b.columns {
+		value := ""
+		if i < len(record) {
+			value = record[i]
+		}
+		b.columns[i].add(value, format)
+	}
+}
+
+func (b *windowBucket) merge(o *windowBucket) {
+	b.rows += o.rows
+	for i := range o.columns {
+		b.columns[i].merge(&o.columns[i])
+	}
+}
+
+func (c *windowColumn) add(value string, format numberFormat) {
+	if value == "" {
+		c.missing++
+		return
+	}
+	c.count++
+	if c.distinct == nil {
+		c.distinct = newDistinctCounter(maxWindowDistinct)
+	}
+	c.distinct.add(hashValue(value))
+
+	if x, ok := format.parseFloat(value); ok {
+		if c.numbers == 0 || x < c.min {
+			c.min = x
+		}
+		if c.numbers == 0 || x > c.max {
+			c.max = x
+		}
+		c.numbers++
+		delta := x - c.mean
+		c.mean += delta / float64(c.numbers)
+		c.m2 += delta * (x - c.mean)
+	}
+}
+
+// merge folds in o with the parallel form of Welford's algorithm.
+func (c *windowColumn) merge(o *windowColumn) {
+	c.missing += o.missing
+	c.count += o.count
+	if o.distinct != nil {
+		if c.distinct == nil {
+			c.distinct = newDistinctCounter(maxWindowDistinct)
+		}
+		c.distinct.merge(o.distinct)
+	}
+
+	if o.numbers == 0 {
+		return
+	}
+	if c.numbers == 0 {
+		c.numbers, c.mean, c.m2, c.min, c.max = o.numbers, o.mean, o.m2, o.min, o.max
+		return
+	}
+	n := c.numbers + o.numbers
+	delta := o.mean - c.mean
+	c.m2 += o.m2 + delta*delta*float64(c.numbers)*float64(o.numbers)/float64(n)
+	c.mean += delta * float64(o.numbers) / float64(n)
+	c.min = math.Min(c.min, o.min)
+	c.max = math.Max(c.max, o.max)
+	c.numbers = n
+}
+
+// apply sets the time windows of profile, whose columns are finished.
+func (w *timeWindows) apply(profile *DatasetProfile, header []string, timeColumn string) {
+	end := (w.latest + 1) * w.width
+	windows := &TimeWindows{
+		TimeColumn:    timeColumn,
+		Window:        w.window,
+		LatestEnd:     time.Unix(0, end).UTC(),
+		LatestStart:   time.Unix(0, end-w.span*w.width).UTC(),
+		PreviousStart: time.Unix(0, end-2*w.span*w.width).UTC(),
+		Skipped:       w.skipped,
+		Columns:       make([]WindowDelta, 0, len(header)),
+	}
+	if !w.started {
+		windows.LatestEnd, windows.LatestStart, windows.PreviousStart = time.Time{}, time.Time{}, time.Time{}
+	}
+
+	latest := &windowBucket{columns: make([]windowColumn, len(header))}
+	previous := &windowBucket{columns: make([]windowColumn, len(header))}
+	for index, bucket := range w.buckets {
+		if index > w.latest-w.span {
+			latest.merge(bucket)
+		} else {
+			previous.merge(bucket)
+		}
+	}
+	windows.LatestRows, windows.PreviousRows = latest.rows, previous.rows
+
+	seen := make(map[string]bool, len(header))
+	for i, colName := range header {
+		col, ok := profile.Columns[colName]
+		if !ok || seen[colName] || colName == timeColumn {
+			continue
+		}
+		seen[colName] = true
+
+		delta := WindowDelta{
+			Column:   colName,
+			Numeric:  col.IsNumeric,
+			Latest:   latest.columns[i].stats(latest.rows, col.IsNumeric),
+			Previous: previous.columns[i].stats(previous.rows, col.IsNumeric),
+		}
+		if latest.rows > 0 && previous.rows > 0 {
+			delta.Changes = windowChanges(delta)
+		}
+		windows.Columns = append(windows.Columns, delta)
+	}
+
+	profile.TimeWindows = windows
+	if w.skipped > 0 {
+		profile.Notes = append(profile.Notes, fmt.Sprintf(
+			"Rows left out of the time windows: %d without a timestamp in %s", w.skipped, timeColumn))
+	}
+}
+
+func (c *windowColumn) stats(rows int, numeric bool) WindowStats {
+	s := WindowStats{Count: c.count}
+	if rows > 0 {
+		s.MissingPercent = float64(c.missing) / float64(rows) * 100
+	}
+	if c.distinct != nil {
+		s.Distinct = c.distinct.count()
+	}
+	if numeric && c.numbers > 0 {
+		s.Mean, s.Min, s.Max = c.mean, c.min, c.max
+		if c.numbers > 1 {
+			s.StdDev = math.Sqrt(c.m2 / float64(c.numbers-1))
+		}
+	}
+	return s
+}
+
+// windowChanges lists what regressed between the previous window and the
+// latest one: a rise in missing values or a shift in the mean.
+func windowChanges(d WindowDelta) []string {
+	changes := make([]string, 0)
+	if d.Latest.MissingPercent-d.Previous.MissingPercent >= windowMissingThreshold {
+		changes = append(changes, "missing_rate")
+	}
+	if d.Numeric && d.Previous.Count > 0 && d.Latest.Count > 0 {
+		if math.Abs(d.MeanShift()) >= windowMeanThreshold || (d.Previous.StdDev == 0 && d.Latest.Mean != d.Previous.Mean) {
+			changes = append(changes, "mean")
+		}
+	}
+	return changes
+}
+
+// bucketWidth is the width of the buckets of a window in nanoseconds, about
+// a windowBuckets-th of it. The buckets of a window of whole days divide a
+// day, so that the windows start and end on the hour, at midnight UTC when
+// the buckets are a day wide.
+func bucketWidth(window time.Duration) int64 {
+	width := window / windowBuckets
+	if window%(24*time.Hour) == 0 {
+		for _, hours := range []time.Duration{24, 12, 8, 6, 4, 3, 2, 1} {
+			if hours*time.Hour <= width {
+				return int64(hours * time.Hour)
+			}
+		}
+	}
+	return max(int64(width), 1)
+}
+
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
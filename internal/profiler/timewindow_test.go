@@ -0,0 +1,126 @@
+package profiler
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// windowCSV returns 28 days of hourly events, out of order, whose amount
+// rises by 40 and whose note goes missing every third row in the last week.
+func windowCSV() string {
+	var b strings.Builder
+	b.WriteString("ts,amount,status,note\n")
+	start := time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC)
+	hours := 28 * 24
+	for i := 0; i < hours; i++ {
+		hour := (i * 5) % hours
+		ts := start.Add(time.Duration(hour) * time.Hour)
+		amount, note := 100+float64(hour%10), fmt.Sprintf("n%d", hour%5)
+		if hour >= 21*24 {
+			amount += 40
+			if hour%3 == 0 {
+				note = ""
+			}
+		}
+		fmt.Fprintf(&b, "%s,%.1f,ok,%s\n", ts.Format(time.RFC3339), amount, note)
+	}
+	b.WriteString("not a time,1,ok,x\n")
+	return b.String()
+}
+
+func TestProfileTimeWindows(t *testing.T) {
+	path := writeTestFile(t, "events.csv", windowCSV())
+	profile, err := ProfileDatasetWithOptions(path, Options{TimeColumn: "ts", TimeWindow: 7 * 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("ProfileDatasetWithOptions failed: %v", err)
+	}
+
+	w := profile.TimeWindows
+	if w == nil {
+		t.Fatal("Expected time windows")
+	}
+	if w.LatestRows != 168 || w.PreviousRows != 168 || w.Skipped != 1 {
+		t.Errorf("Expected 168 rows in each window and 1 skipped, got %d, %d and %d", w.LatestRows, w.PreviousRows, w.Skipped)
+	}
+	wantEnd := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	if !w.LatestEnd.Equal(wantEnd) || !w.LatestStart.Equal(wantEnd.AddDate(0, 0, -7)) || !w.PreviousStart.Equal(wantEnd.AddDate(0, 0, -14)) {
+		t.Errorf("Unexpected window bounds %s, %s and %s", w.PreviousStart, w.LatestStart, w.LatestEnd)
+	}
+
+	if len(w.Columns) != 3 {
+		t.Fatalf("Expected every column but the time column, got %+v", w.Columns)
+	}
+	amount, status, note := w.Columns[0], w.Columns[1], w.Columns[2]
+	if amount.Column != "amount" || !reflect.DeepEqual(amount.Changes, []string{"mean"}) {
+		t.Errorf("Expected a mean regression in amount, got %+v", amount)
+	}
+	if math.Abs(amount.Latest.Mean-amount.Previous.Mean-40) > 0.5 || amount.MeanShift() < 10 {
+		t.Errorf("Expected the mean to rise by 40, got %g → %g (%.1fσ)", amount.Previous.Mean, amount.Latest.Mean, amount.MeanShift())
+	}
+	if len(status.Changes) != 0 || status.Latest.Distinct != 1 {
+		t.Errorf("Expected status to hold steady, got %+v", status)
+	}
+	if !reflect.DeepEqual(note.Changes, []string{"missing_rate"}) || note.Previous.MissingPercent != 0 || math.Abs(note.Latest.MissingPercent-100.0/3) > 1e-9 {
+		t.Errorf("Expected a third of note to go missing, got %+v", note)
+	}
+	if len(w.Regressions()) != 2 {
+		t.Errorf("Expected 2 regressions, got %+v", w.Regressions())
+	}
+
+	found := false
+	for _, n := range profile.Notes {
+		found = found || strings.Contains(n, "Rows left out of the time windows: 1 without a timestamp in ts")
+	}
+	if !found {
+		t.Errorf("Expected a note on the skipped row, got %v", profile.Notes)
+	}
+}
+
+func TestProfileTimeWindowsParallel(t *testing.T) {
+	withParallelMinChunk(t, 256)
+	path := writeTestFile(t, "events.csv", windowCSV())
+	opts := Options{TimeColumn: "ts", TimeWindow: 7 * 24 * time.Hour}
+
+	want, err := ProfileDatasetWithOptions(path, opts)
+	if err != nil {
+		t.Fatalf("ProfileDatasetWithOptions failed: %v", err)
+	}
+	opts.Parallel = 4
+	got, err := ProfileDatasetWithOptions(path, opts)
+	if err != nil {
+		t.Fatalf("ProfileDatasetWithOptions failed: %v", err)
+	}
+
+	if got.TimeWindows.LatestRows != want.TimeWindows.LatestRows || got.TimeWindows.PreviousRows != want.TimeWindows.PreviousRows {
+		t.Errorf("Expected the rows of the sequential windows, got %+v", got.TimeWindows)
+	}
+	for i, delta := range got.TimeWindows.Columns {
+		expected := want.TimeWindows.Columns[i]
+		if !reflect.DeepEqual(delta.Changes, expected.Changes) || math.Abs(delta.Latest.Mean-expected.Latest.Mean) > 1e-9 ||
+			math.Abs(delta.Previous.StdDev-expected.Previous.StdDev) > 1e-9 || delta.Latest.MissingPercent != expected.Latest.MissingPercent {
+			t.Errorf("Expected column %s to match the sequential pass: %+v, got %+v", delta.Column, expected, delta)
+		}
+	}
+}
+
+func TestProfileTimeWindowsInvalid(t *testing.T) {
+	path := writeTestFile(t, "events.csv", windowCSV())
+	tests := []struct {
+		opts Options
+		want string
+	}{
+		{Options{TimeColumn: "missing", TimeWindow: time.Hour}, "time column missing not found"},
+		{Options{TimeColumn: "ts"}, "--time-column and --window go together"},
+		{Options{TimeWindow: time.Hour}, "--time-column and --window go together"},
+	}
+	for _, tt := range tests {
+		_, err := ProfileDatasetWithOptions(path, tt.opts)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Expected an error containing %q for %+v, got %v", tt.want, tt.opts, err)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package profiler
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// weightedValue is a value of a column with the weight of its row.
+type weightedValue struct {
+	value  string
+	weight float64
+}
+
+// weightedNumber is a number of a column with the weight of its row.
+type weightedNumber struct {
+	x      float64
+	weight float64
+}
+
+// weightedStats accumulates the estimates of a column weighted by the
+// weight column, for survey and telemetry data where each row stands for a
+// share of a population. The mean and variance use West's update with
+// fractional weights. Weighted numbers are kept for exact quantiles and
+// histogram until exactNumericLimit, after which a t-digest takes over, as
+// in numericStats. Top values sum the weights of each value; past
+// maxTrackedValues the lightest are dropped, so late values are undercounted.
+type weightedStats struct {
+	values    map[string]float64
+	truncated bool
+	numbers   bool
+	pending   []weightedValue // values seen before the number format was set
+	count     int             // numbers seen with a positive weight
+	weight    float64         // total weight of those numbers
+	mean      float64
+	m2        float64
+	min       float64
+	max       float64
+	exact     []weightedNumber
+	digest    *tDigest
+}
+
+func newWeightedStats() *weightedStats {
+	return &weightedStats{values: make(map[string]float64), numbers: true}
+}
+
+// parseWeight reads the weight of a row. Missing, malformed, negative and
+// infinite weights are invalid.
+func parseWeight(value string) (float64, bool) {
+	w, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || w < 0 || math.IsInf(w, 0) || math.IsNaN(w) {
+		return 0, false
+	}
+	return w, true
+}
+
+// add counts value with weight w. Numbers are parsed once the number format
+// of the column is known, until which they are held back.
+func (s *weightedStats) add(value string, w float64, format numberFormat, formatSet bool) {
+	s.values[value] += w
+	if len(s.values) > maxTrackedValues {
+		s.truncate()
+	}
+
+	if !s.numbers {
+		return
+	}
+	if !formatSet {
+		s.pending = append(s.pending, weightedValue{value: value, weight: w})
+		return
+	}
+	if x, ok := format.parseFloat(value); ok {
+		s.addNumber(x, w)
+	}
+}
+
+// truncate keeps the topValueCapacity heaviest values.
+func (s *weightedStats) truncate() {
+	kept := make(map[string]float64, topValueCapacity)
+	for _, v := range s.heaviest(topValueCapacity) {
+		kept[v.value] = v.weight
+	}
+	s.values = kept
+	s.truncated = true
+}
+
+// heaviest returns the limit values of greatest total weight, heaviest
+// first and by value on a tie.
+func (s *weightedStats) heaviest(limit int) []weightedValue {
+	values := make([]weightedValue, 0, len(s.values))
+	for value, w := range s.values {
+		values = append(values, weightedValue{value: value, weight: w})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].weight != values[j].weight {
+			return values[i].weight > values[j].weight
+		}
+		return values[i].value < values[j].value
+	})
+	if len(values) > limit {
+		values = values[:limit]
+	}
+	return values
+}
+
+// setNumberFormat parses the numbers held back until the format was set.
+func (s *weightedStats) setNumberFormat(format numberFormat) {
+	for _, v := range s.pending {
+		if x, ok := format.parseFloat(v.value); ok {
+			s.addNumber(x, v.weight)
+		}
+	}
+	s.pending = nil
+}
+
+// dropNumbers stops numeric tracking of a column that holds no numbers.
+func (s *weightedStats) dropNumbers() {
+	s.numbers = false
+	s.pending = nil
+	s.exact = nil
+	s.digest = nil
+}
+
+func (s *weightedStats) addNumber(x, w float64) {
+	if w == 0 {
+		return
+	}
+	if s.count == 0 || x < s.min {
+		s.min = x
+	}
+	if s.count == 0 || x > s.max {
+		s.max = x
+	}
+	s.count++
+
+	s.weight += w
+	delta := x - s.mean
+	s.mean += delta * w / s.weight
+	s.m2 += w * delta * (x - s.mean)
+
+	if s.digest == nil && s.count > exactNumericLimit {
+		s.digest = newTDigest(tDigestCompression)
+		for _, v := range s.exact {
+			s.digest.add(v.x, v.weight)
+		}
+		s.exact = nil
+	}
+	if s.digest != nil {
+		s.digest.add(x, w)
+		return
+	}
+	s.exact = append(s.exact, weightedNumber{x: x, weight: w})
+}
+
+// apply replaces the mean, standard deviation, median, percentiles and
+// histogram of a numeric col, and its top values, with weighted estimates.
+// Histogram and top value counts are scaled to the values of the column, so
+// that their shares are weighted shares. The histogram keeps the binning
+// the unweighted statistics chose for col.
+func (s *weightedStats) apply(col *ColumnProfile, buckets int, topValues int) {
+	if col.IsNumeric && s.numbers && s.weight > 0 {
+		col.Mean = s.mean
+		col.StdDev = math.Sqrt(s.m2 / s.weight)
+
+		bounds := &numericStats{count: col.Count, min: s.min, max: s.max, digest: s.digest}
+		var quantile func(q float64) float64
+		var sorted []weightedNumber
+		if s.digest != nil {
+			quantile = s.digest.quantile
+		} else {
+			sorted = append(sorted, s.exact...)
+			sort.Slice(sorted, func(i, j int) bool { return sorted[i].x < sorted[j].x })
+			quantile = func(q float64) float64 { return weightedQuantile(sorted, s.weight, q) }
+		}
+
+		col.Median = quantile(0.5)
+		col.Percentiles = percentiles(quantile)
+
+		histogram := bounds.bucketBounds(col.HistogramBinning, buckets, quantile)
+		if s.digest != nil {
+			col.HistogramBuckets = bounds.estimatedHistogram(histogram)
+		} else {
+			col.HistogramBuckets = weightedHistogram(sorted, histogram, s.weight, col.Count)
+		}
+	}
+
+	total := 0.0
+	for _, w := range s.values {
+		total += w
+	}
+	if total == 0 || col.Count == 0 {
+		return
+	}
+	col.TopValues = make([]ValueCount, 0, topValues)
+	for _, v := range s.heaviest(topValues) {
+		col.TopValues = append(col.TopValues, ValueCount{Value: v.value, Count: int(math.Round(v.weight / total * float64(col.Count)))})
+	}
+}
+
+// weightedQuantile returns the first value at which the cumulative weight
+// reaches q of the total, or the midpoint with the next value when it
+// reaches it exactly, which is the median of an even count of equal
+// weights.
+func weightedQuantile(sorted []weightedNumber, total, q float64) float64 {
+	target := q * total
+	cumulative := 0.0
+	for i, v := range sorted {
+		cumulative += v.weight
+		if cumulative < target {
+			continue
+		}
+		if cumulative == target && i+1 < len(sorted) && q > 0 {
+			return (v.x + sorted[i+1].x) / 2
+		}
+		return v.x
+	}
+	return sorted[len(sorted)-1].x
+}
+
+// weightedHistogram sums the weights of sorted values into buckets, each
+// holding the values from its lower bound up to but not including its upper
+// bound, and the last up to and including it. Counts are the weighted
+// shares of count, rounded cumulatively so that they add up to it.
+func weightedHistogram(sorted []weightedNumber, buckets []HistogramBucket, total float64, count int) []HistogramBucket {
+	previous, cumulativeWeight := 0, 0.0
+	start := 0
+	for i := range buckets {
+		end := len(sorted)
+		if i < len(buckets)-1 {
+			upper := buckets[i].UpperBound
+			end = start + sort.Search(len(sorted)-start, func(j int) bool { return sorted[start+j].x >= upper })
+		}
+		for _, v := range sorted[start:end] {
+			cumulativeWeight += v.weight
+		}
+		start = end
+
+		cumulative := count
+		if i < len(buckets)-1 {
+			cumulative = int(math.Round(cumulativeWeight / total * float64(count)))
+		}
+		if cumulative < previous {
+			cumulative = previous
+		}
+		buckets[i].Count = cumulative - previous
+		previous = cumulative
+	}
+	return buckets
+}
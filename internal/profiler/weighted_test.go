@@ -0,0 +1,146 @@
+package profiler
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProfileWeighted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "survey.csv")
+	content := "region,income,sample_weight\n" +
+		"north,100,1\n" +
+		"north,200,1\n" +
+		"south,300,3\n" +
+		"south,400,5\n" +
+		"east,500,0.5\n" +
+		"west,600,\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write CSV file: %v", err)
+	}
+
+	profile, err := ProfileDatasetWithOptions(path, Options{WeightColumn: "sample_weight", ExactRows: 1000})
+	if err != nil {
+		t.Fatalf("ProfileDatasetWithOptions failed: %v", err)
+	}
+
+	if profile.WeightColumn != "sample_weight" || profile.WeightTotal != 10.5 {
+		t.Errorf("Expected weights of sample_weight totalling 10.5, got %q and %g", profile.WeightColumn, profile.WeightTotal)
+	}
+	found := false
+	for _, note := range profile.Notes {
+		found = found || strings.Contains(note, "weighted by sample_weight (total weight 10.50); rows with a missing or invalid weight left out: 1")
+	}
+	if !found {
+		t.Errorf("Expected a note on the weighting, got %v", profile.Notes)
+	}
+
+	income := profile.Columns["income"]
+	if math.Abs(income.Mean-3450/10.5) > 1e-9 {
+		t.Errorf("Expected a weighted mean of %.4f, got %.4f", 3450/10.5, income.Mean)
+	}
+	if income.Median != 400 {
+		t.Errorf("Expected a weighted median of 400, got %g", income.Median)
+	}
+	total := 0
+	for _, bucket := range income.HistogramBuckets {
+		total += bucket.Count
+	}
+	if total != income.Count {
+		t.Errorf("Expected histogram counts to add up to %d, got %d", income.Count, total)
+	}
+
+	region := profile.Columns["region"]
+	want := []ValueCount{{Value: "south", Count: 5}, {Value: "north", Count: 1}, {Value: "east", Count: 0}, {Value: "west", Count: 0}}
+	if len(region.TopValues) != len(want) {
+		t.Fatalf("Expected top values %v, got %v", want, region.TopValues)
+	}
+	for i := range want {
+		if region.TopValues[i] != want[i] {
+			t.Errorf("Expected top value %d to be %v, got %v", i, want[i], region.TopValues[i])
+		}
+	}
+
+	// The weight column itself is not weighted
+	if weights := profile.Columns["sample_weight"]; math.Abs(weights.Mean-2.1) > 1e-9 {
+		t.Errorf("Expected the unweighted mean 2.1 of the weights, got %g", weights.Mean)
+	}
+
+	if _, err := ProfileDatasetWithOptions(path, Options{WeightColumn: "weight"}); err == nil || !strings.Contains(err.Error(), "weight column weight not found") {
+		t.Errorf("Expected an error for a missing weight column, got %v", err)
+	}
+}
+
+func TestProfileWeightedDigest(t *testing.T) {
+	var content strings.Builder
+	content.WriteString("flag,w\n")
+	for i := 0; i < 2*exactNumericLimit; i++ {
+		if i%2 == 0 {
+			content.WriteString("0,1\n")
+		} else {
+			content.WriteString("1,3\n")
+		}
+	}
+	path := filepath.Join(t.TempDir(), "events.csv")
+	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		t.Fatalf("Failed to write CSV file: %v", err)
+	}
+
+	profile, err := ProfileDatasetWithOptions(path, Options{WeightColumn: "w"})
+	if err != nil {
+		t.Fatalf("ProfileDatasetWithOptions failed: %v", err)
+	}
+
+	flag := profile.Columns["flag"]
+	if math.Abs(flag.Mean-0.75) > 1e-9 || math.Abs(flag.StdDev-math.Sqrt(0.1875)) > 1e-9 {
+		t.Errorf("Expected a weighted mean of 0.75 and stddev of %.4f, got %g and %g", math.Sqrt(0.1875), flag.Mean, flag.StdDev)
+	}
+	if len(flag.TopValues) != 2 || flag.TopValues[0].Value != "1" || flag.TopValues[0].Count != 3*exactNumericLimit/2 {
+		t.Errorf("Expected 1 to hold three quarters of the values, got %v", flag.TopValues)
+	}
+}
+
+func TestWeightedQuantile(t *testing.T) {
+	sorted := []weightedNumber{{1, 1}, {2, 1}, {3, 1}, {4, 1}}
+	for _, tt := range []struct {
+		q, want float64
+	}{
+		{0, 1},
+		{0.25, 1.5},
+		{0.5, 2.5},
+		{0.9, 4},
+		{1, 4},
+	} {
+		if got := weightedQuantile(sorted, 4, tt.q); got != tt.want {
+			t.Errorf("weightedQuantile(%g) = %g, want %g", tt.q, got, tt.want)
+		}
+	}
+
+	skewed := []weightedNumber{{1, 1}, {2, 1}, {10, 8}}
+	if got := weightedQuantile(skewed, 10, 0.5); got != 10 {
+		t.Errorf("Expected the heavy value as the weighted median, got %g", got)
+	}
+}
+
+func TestParseWeight(t *testing.T) {
+	for _, tt := range []struct {
+		value string
+		want  float64
+		ok    bool
+	}{
+		{"1.5", 1.5, true},
+		{" 2 ", 2, true},
+		{"0", 0, true},
+		{"", 0, false},
+		{"-1", 0, false},
+		{"Inf", 0, false},
+		{"heavy", 0, false},
+	} {
+		got, ok := parseWeight(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseWeight(%q) = %g, %v, want %g, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package profiler
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/remote"
+)
+
+// FormatXML is the format of XML files, whose records are the elements
+// Options.RecordPath selects.
+const FormatXML = "xml"
+
+// defaultRecordPath selects the children of the root element, the records
+// of most XML feeds.
+const defaultRecordPath = "/*/*"
+
+// isXML reports whether filePath is read as XML, by its extension or
+// --format.
+func isXML(filePath string, opts Options) bool {
+	if remote.IsURL(filePath) {
+		filePath = remote.Path(filePath)
+	}
+	return tableFormat(filePath, opts) == "" && !IsArchive(filePath) && fileFormat(filePath, opts) == FormatXML
+}
+
+// xmlStep is one step of a record path: an element name, or * for any
+// element, that is a child of the element of the step before or, after //,
+// any descendant of it.
+type xmlStep struct {
+	name       string
+	descendant bool
+}
+
+// parseRecordPath parses the subset of XPath that selects records: element
+// names and * separated by / or //, such as //row or /feed/items/item. A
+// relative path such as row selects the elements anywhere, as //row does,
+// and an empty one the children of the root element. Namespace prefixes are
+// dropped, as elements are matched by their local names.
+func parseRecordPath(path string) ([]xmlStep, error) {
+	rest := path
+	if rest == "" {
+		rest = defaultRecordPath
+	}
+	if !strings.HasPrefix(rest, "/") {
+		rest = "//" + rest
+	}
+
+	var steps []xmlStep
+	for rest != "" {
+		var step xmlStep
+		if strings.HasPrefix(rest, "//") {
+			step.descendant, rest = true, rest[2:]
+		} else {
+			rest = rest[1:]
+		}
+
+		end := strings.IndexByte(rest, '/')
+		if end < 0 {
+			end = len(rest)
+		}
+		step.name, rest = rest[:end], rest[end:]
+		if _, local, ok := strings.Cut(step.name, ":"); ok {
+			step.name = local
+		}
+		if step.name == "" || step.name == "." || step.name == ".." || strings.ContainsAny(step.name, "[]()@=:'\" ") {
+			return nil, fmt.Errorf("unsupported record path %q: use element names and * separated by / or //, such as //row", path)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// matchRecordPath reports whether steps select the element whose name and
+// ancestors' names, from the root element, are names.
+func matchRecordPath(steps []xmlStep, names []string) bool {
+	if len(steps) == 0 {
+		return len(names) == 0
+	}
+	step := steps[0]
+	for i := range names {
+		if (step.name == "*" || step.name == names[i]) && matchRecordPath(steps[1:], names[i+1:]) {
+			return true
+		}
+		if !step.descendant {
+			break
+		}
+	}
+	return false
+}
+
+// xmlRecordReader reads the elements a record path selects from an XML
+// document, flattening each into a record. Records do not nest: the
+// elements inside a record are its fields, even those the path would select.
+type xmlRecordReader struct {
+	decoder *xml.Decoder
+	steps   []xmlStep
+	names   []string // local names of the open elements, from the root
+	records int
+}
+
+func newXMLRecordReader(r io.Reader, recordPath string) (*xmlRecordReader, error) {
+	steps, err := parseRecordPath(recordPath)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := xml.NewDecoder(r)
+	// The source has been transcoded to UTF-8 whatever its declaration says
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	return &xmlRecordReader{decoder: decoder, steps: steps}, nil
+}
+
+// next returns the next record, or io.EOF once the document ends.
+func (x *xmlRecordReader) next() (*jsonlRecord, error) {
+	for {
+		token, err := x.decoder.Token()
+		if err == io.EOF {
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading XML: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			x.names = append(x.names, t.Name.Local)
+			if !matchRecordPath(x.steps, x.names) {
+				continue
+			}
+			x.names = x.names[:len(x.names)-1]
+
+			fields := &xmlFields{values: make(map[string][]string)}
+			if err := x.readElement(t, t.Name.Local, "", true, fields); err != nil {
+				return nil, fmt.Errorf("error reading XML record %d: %w", x.records+1, err)
+			}
+			x.records++
+			return fields.record(), nil
+		case xml.EndElement:
+			x.names = x.names[:len(x.names)-1]
+		}
+	}
+}
+
+// readElement reads the element start opens up to its end. Its attributes
+// and child elements become fields named under prefix, nested ones joined
+// with dots as in price.currency, and its text becomes the field key. Whitespace
+// around text is dropped, and the text of the record element itself, or of
+// an element holding others, is only kept when there is some.
+func (x *xmlRecordReader) readElement(start xml.StartElement, key, prefix string, record bool, fields *xmlFields) error {
+	attributes := 0
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+			continue
+		}
+		fields.add(joinXMLName(prefix, attr.Name.Local), attr.Value)
+		attributes++
+	}
+
+	var text strings.Builder
+	children := false
+	for {
+		token, err := x.decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			children = true
+			child := joinXMLName(prefix, t.Name.Local)
+			if err := x.readElement(t, child, child, false, fields); err != nil {
+				return err
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			value := strings.TrimSpace(text.String())
+			if value != "" || (!record && !children && attributes == 0) {
+				fields.add(key, value)
+			}
+			return nil
+		}
+	}
+}
+
+func joinXMLName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// xmlFields collects the fields of a record in the order they are first
+// seen. A field given more than once, by a repeated element, holds all of
+// its values.
+type xmlFields struct {
+	keys   []string
+	values map[string][]string
+}
+
+func (f *xmlFields) add(key, value string) {
+	if _, seen := f.values[key]; !seen {
+		f.keys = append(f.keys, key)
+	}
+	f.values[key] = append(f.values[key], value)
+}
+
+// record flattens the fields into a record, keeping the values of repeated
+// elements as a compact JSON array as JSON Lines keeps nested arrays.
+func (f *xmlFields) record() *jsonlRecord {
+	record := &jsonlRecord{keys: f.keys, values: make(map[string]string, len(f.keys))}
+	for _, key := range f.keys {
+		values := f.values[key]
+		if len(values) == 1 {
+			record.values[key] = values[0]
+			continue
+		}
+		encoded, _ := json.Marshal(values)
+		record.values[key] = string(encoded)
+	}
+	return record
+}
+
+func profileXMLFile(filePath string, opts Options) (*DatasetProfile, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file stats: %w", err)
+	}
+
+	return profileXML(file, filepath.Base(filePath), fileInfo.Size(), opts)
+}
+
+// profileXML profiles the records opts.RecordPath selects from an XML
+// document. The columns are the fields of the leading records, as for JSON
+// Lines.
+func profileXML(r io.Reader, name string, size int64, opts Options) (*DatasetProfile, error) {
+	startTime := time.Now()
+
+	if opts.SkipRows > 0 || opts.SkipFooter > 0 {
+		return nil, fmt.Errorf("--skip-rows and --skip-footer are not supported for XML")
+	}
+	if opts.Delimiter != 0 || opts.Quote != 0 || opts.Comment != 0 {
+		return nil, fmt.Errorf("--delimiter, --quote and --comment are not supported for XML")
+	}
+	if opts.SkipBadRows {
+		return nil, fmt.Errorf("--skip-bad-rows is not supported for XML: a document is not read past its first syntax error")
+	}
+
+	source, err := decompress(r, name)
+	if err != nil {
+		return nil, err
+	}
+	defer source.Close()
+
+	text, err := transcode(source, opts.Encoding, opts.MaxBytes)
+	if err != nil {
+		return nil, err
+	}
+	r = text
+
+	var limited *lineLimitReader
+	if opts.MaxBytes > 0 {
+		limited = newLineLimitReader(r, opts.MaxBytes)
+		r = limited
+	}
+
+	reader, err := newXMLRecordReader(r, opts.RecordPath)
+	if err != nil {
+		return nil, err
+	}
+	read := func() (*jsonlRecord, error) {
+		record, err := reader.next()
+		// A document cut at the range ends in the middle of an element;
+		// the record it cut is dropped
+		if err != nil && limited != nil && limited.truncated {
+			return nil, io.EOF
+		}
+		return record, err
+	}
+
+	records, err := newKeyedRecords(read)
+	if err != nil {
+		return nil, err
+	}
+	if len(records.header) == 0 {
+		recordPath := opts.RecordPath
+		if recordPath == "" {
+			recordPath = defaultRecordPath
+		}
+		return nil, fmt.Errorf("no XML records found at %s (--record-path selects them)", recordPath)
+	}
+
+	profile := newDatasetProfile(name, size, "XML", records.header)
+	if err := profileRows(profile, records.header, records.next, opts); err != nil {
+		return nil, err
+	}
+
+	if note := records.note(); note != "" {
+		profile.Notes = append(profile.Notes, note)
+	}
+	source.describe(profile)
+	text.describe(profile)
+	if limited != nil && limited.truncated {
+		profile.Notes = append(profile.Notes, limited.note(profile))
+	}
+
+	profile.ProcessingTime = time.Since(startTime)
+
+	return profile, nil
+}
+
+// countXML counts the records of an XML document by reading them one by
+// one. The columns are the fields of the leading records, as in profile.
+func countXML(r io.Reader, opts Options) (Count, error) {
+	if opts.SkipRows > 0 || opts.SkipFooter > 0 {
+		return Count{}, fmt.Errorf("--skip-rows and --skip-footer are not supported for XML")
+	}
+
+	reader, err := newXMLRecordReader(r, opts.RecordPath)
+	if err != nil {
+		return Count{}, err
+	}
+	records, err := newKeyedRecords(reader.next)
+	if err != nil {
+		return Count{}, err
+	}
+
+	count := Count{Format: "XML", Columns: len(records.header), Method: CountRecords}
+	ctx := opts.context()
+	for {
+		if _, err := records.next(); err == io.EOF {
+			return count, nil
+		} else if err != nil {
+			return Count{}, err
+		}
+		count.Rows++
+		if count.Rows%1024 == 0 && ctx.Err() != nil {
+			return Count{}, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package profiler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testXMLFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://example.com/feed">
+  <generated>2026-01-01</generated>
+  <items>
+    <item id="1">
+      <name>Widget</name>
+      <price currency="USD">9.99</price>
+      <tags><tag>a</tag><tag>b</tag></tags>
+      <supplier><name>Acme</name><country>US</country></supplier>
+      <note/>
+    </item>
+    <item id="2">
+      <name>Gadget</name>
+      <price currency="EUR">19.50</price>
+      <tags><tag>c</tag></tags>
+      <supplier><name>Globex</name><country>DE</country></supplier>
+      <note>fragile</note>
+    </item>
+  </items>
+</feed>
+`
+
+func TestProfileXML(t *testing.T) {
+	path := writeTestFile(t, "feed.xml", testXMLFeed)
+
+	profile, err := ProfileDatasetWithOptions(path, Options{RecordPath: "//item"})
+	if err != nil {
+		t.Fatalf("Failed to profile XML: %v", err)
+	}
+
+	if profile.Format != "XML" || profile.RowCount != 2 {
+		t.Errorf("Expected 2 XML rows, got %s with %d", profile.Format, profile.RowCount)
+	}
+	want := []string{"id", "name", "price.currency", "price", "tags.tag", "supplier.name", "supplier.country", "note"}
+	if profile.ColumnCount != len(want) {
+		t.Errorf("Expected columns %v, got %d", want, profile.ColumnCount)
+	}
+	for _, name := range want {
+		if profile.Columns[name] == nil {
+			t.Errorf("Expected column %s", name)
+		}
+	}
+	if t.Failed() {
+		return
+	}
+
+	if profile.Columns["id"].DataType != "integer" || profile.Columns["price"].DataType != "float" {
+		t.Errorf("Unexpected types: id %s, price %s", profile.Columns["id"].DataType, profile.Columns["price"].DataType)
+	}
+	if profile.Columns["note"].MissingCount != 1 {
+		t.Errorf("Expected the empty note to be missing, got %d missing", profile.Columns["note"].MissingCount)
+	}
+	tags := make([]string, 0)
+	for _, top := range profile.Columns["tags.tag"].TopValues {
+		tags = append(tags, top.Value)
+	}
+	if !containsString(tags, `["a","b"]`) || !containsString(tags, "c") {
+		t.Errorf("Expected repeated elements kept as a JSON array, got %v", tags)
+	}
+}
+
+func TestProfileXMLDefaultRecordPath(t *testing.T) {
+	path := writeTestFile(t, "feed.xml", `<rows>
+  <row><a>1</a></row>
+  <row><a>2</a><b>x</b></row>
+  <row><a>3</a></row>
+</rows>`)
+
+	profile, err := ProfileDataset(path)
+	if err != nil {
+		t.Fatalf("Failed to profile XML: %v", err)
+	}
+	if profile.RowCount != 3 || profile.ColumnCount != 2 || profile.Columns["b"].MissingCount != 2 {
+		t.Errorf("Expected the children of the root as 3 rows of a and b, got %d rows of %d columns", profile.RowCount, profile.ColumnCount)
+	}
+}
+
+func TestProfileXMLErrors(t *testing.T) {
+	path := writeTestFile(t, "feed.xml", testXMLFeed)
+
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"no records", Options{RecordPath: "/feed/row"}, "no XML records found at /feed/row"},
+		{"predicate", Options{RecordPath: "//item[@id='1']"}, "unsupported record path"},
+		{"skip bad rows", Options{RecordPath: "//item", SkipBadRows: true}, "--skip-bad-rows is not supported for XML"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ProfileDatasetWithOptions(path, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	broken := writeTestFile(t, "feed.xml", "<rows>\n<row><a>1</a></row>\n<row><a>2</b></row>\n</rows>\n")
+	if _, err := ProfileDataset(broken); err == nil || !strings.Contains(err.Error(), "error reading XML record 2") {
+		t.Errorf("Expected the malformed record reported, got %v", err)
+	}
+
+	csvPath := filepath.Join(t.TempDir(), "data.csv")
+	if err := os.WriteFile(csvPath, []byte("a\n1\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if _, err := ProfileDatasetWithOptions(csvPath, Options{RecordPath: "//row"}); err == nil || !strings.Contains(err.Error(), "only supported for XML") {
+		t.Errorf("Expected --record-path rejected for CSV, got %v", err)
+	}
+}
+
+func TestMatchRecordPath(t *testing.T) {
+	tests := []struct {
+		path  string
+		names []string
+		want  bool
+	}{
+		{"//item", []string{"feed", "items", "item"}, true},
+		{"item", []string{"item"}, true},
+		{"/feed/items/item", []string{"feed", "items", "item"}, true},
+		{"/feed/item", []string{"feed", "items", "item"}, false},
+		{"/feed//item", []string{"feed", "items", "item"}, true},
+		{"/*/*", []string{"feed", "items"}, true},
+		{"/*/*", []string{"feed", "items", "item"}, false},
+		{"//items/*", []string{"feed", "items", "item"}, true},
+		{"//f:item", []string{"feed", "items", "item"}, true},
+		{"//item", []string{"feed", "items"}, false},
+	}
+	for _, tt := range tests {
+		steps, err := parseRecordPath(tt.path)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", tt.path, err)
+		}
+		if got := matchRecordPath(steps, tt.names); got != tt.want {
+			t.Errorf("%s matching %v: expected %v, got %v", tt.path, tt.names, tt.want, got)
+		}
+	}
+
+	for _, path := range []string{"//item[1]", "//@id", "/feed/..", "//item/", "//text()"} {
+		if _, err := parseRecordPath(path); err == nil {
+			t.Errorf("Expected %s rejected", path)
+		}
+	}
+}
+
+func TestCountXML(t *testing.T) {
+	path := writeTestFile(t, "feed.xml", testXMLFeed)
+
+	count := countOne(t, path, Options{RecordPath: "item"})
+	if count.Rows != 2 || count.Columns != 8 || count.Method != CountRecords || count.Format != "XML" {
+		t.Errorf("Expected 2 records of 8 columns, got %+v", count)
+	}
+}
@@ -0,0 +1,188 @@
+package profiler
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// zipAESMethod is the compression method of WinZip AES entries; the real
+// method is kept in the 0x9901 extra field.
+const zipAESMethod = 99
+
+var zipAESKeyLengths = map[byte]int{1: 16, 2: 24, 3: 32}
+
+type zipAESExtra struct {
+	version  uint16 // 1 for AE-1, 2 for AE-2
+	strength byte
+	method   uint16
+}
+
+func parseZipAESExtra(extra []byte) (zipAESExtra, bool) {
+	for len(extra) >= 4 {
+		id := binary.LittleEndian.Uint16(extra)
+		size := int(binary.LittleEndian.Uint16(extra[2:]))
+		extra = extra[4:]
+		if size > len(extra) {
+			break
+		}
+		if id == 0x9901 && size >= 7 && string(extra[2:4]) == "AE" {
+			return zipAESExtra{
+				version:  binary.LittleEndian.Uint16(extra),
+				strength: extra[4],
+				method:   binary.LittleEndian.Uint16(extra[5:]),
+			}, true
+		}
+		extra = extra[size:]
+	}
+	return zipAESExtra{}, false
+}
+
+// zipCryptoKeys is the state of the traditional PKWARE stream cipher.
+type zipCryptoKeys [3]uint32
+
+func newZipCryptoKeys(password string) *zipCryptoKeys {
+	keys := &zipCryptoKeys{0x12345678, 0x23456789, 0x34567890}
+	for i := 0; i < len(password); i++ {
+		keys.update(password[i])
+	}
+	return keys
+}
+
+func (k *zipCryptoKeys) update(b byte) {
+	k[0] = crc32Update(k[0], b)
+	k[1] = (k[1]+(k[0]&0xff))*134775813 + 1
+	k[2] = crc32Update(k[2], byte(k[1]>>24))
+}
+
+func (k *zipCryptoKeys) stream() byte {
+	temp := uint32(uint16(k[2] | 2))
+	return byte((temp * (temp ^ 1)) >> 8)
+}
+
+func (k *zipCryptoKeys) decrypt(c byte) byte {
+	p := c ^ k.stream()
+	k.update(p)
+	return p
+}
+
+func crc32Update(crc uint32, b byte) uint32 {
+	return crc32.IEEETable[byte(crc)^b] ^ (crc >> 8)
+}
+
+type zipCryptoReader struct {
+	r    io.Reader
+	keys *zipCryptoKeys
+}
+
+// newZipCryptoReader decrypts a ZipCrypto entry. The last byte of the
+// 12-byte encryption header must equal check, which rejects most wrong
+// passwords before any data is read.
+func newZipCryptoReader(r io.Reader, password string, check byte) (io.Reader, error) {
+	keys := newZipCryptoKeys(password)
+
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("failed to read encryption header: %w", err)
+	}
+	for i := range header {
+		header[i] = keys.decrypt(header[i])
+	}
+	if header[11] != check {
+		return nil, ErrWrongPassword
+	}
+
+	return &zipCryptoReader{r: r, keys: keys}, nil
+}
+
+func (z *zipCryptoReader) Read(p []byte) (int, error) {
+	n, err := z.r.Read(p)
+	for i := range p[:n] {
+		p[i] = z.keys.decrypt(p[i])
+	}
+	return n, err
+}
+
+// zipAESReader decrypts a WinZip AES entry: AES in counter mode with a
+// little-endian counter starting at 1, authenticated by HMAC-SHA1 over the
+// encrypted data.
+type zipAESReader struct {
+	data    io.Reader
+	tail    io.Reader // the authentication code after the data
+	block   cipher.Block
+	mac     hash.Hash
+	counter uint64
+	stream  [aes.BlockSize]byte
+	used    int
+	checked bool
+}
+
+func newZipAESReader(r io.Reader, size uint64, password string, keyLen int) (io.Reader, error) {
+	saltLen := keyLen / 2
+	overhead := uint64(saltLen + 2 + 10)
+	if size < overhead {
+		return nil, fmt.Errorf("%w: encrypted entry is too short", ErrIntegrityCheck)
+	}
+
+	header := make([]byte, saltLen+2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("failed to read encryption header: %w", err)
+	}
+
+	keys, err := pbkdf2.Key(sha1.New, password, header[:saltLen], 1000, 2*keyLen+2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	if !bytes.Equal(keys[2*keyLen:], header[saltLen:]) {
+		return nil, ErrWrongPassword
+	}
+
+	block, err := aes.NewCipher(keys[:keyLen])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return &zipAESReader{
+		data:  io.LimitReader(r, int64(size-overhead)),
+		tail:  r,
+		block: block,
+		mac:   hmac.New(sha1.New, keys[keyLen:2*keyLen]),
+		used:  aes.BlockSize,
+	}, nil
+}
+
+func (z *zipAESReader) Read(p []byte) (int, error) {
+	n, err := z.data.Read(p)
+	z.mac.Write(p[:n])
+	for i := range p[:n] {
+		if z.used == aes.BlockSize {
+			z.counter++
+			var counter [aes.BlockSize]byte
+			binary.LittleEndian.PutUint64(counter[:], z.counter)
+			z.block.Encrypt(z.stream[:], counter[:])
+			z.used = 0
+		}
+		p[i] ^= z.stream[z.used]
+		z.used++
+	}
+
+	if err == io.EOF && !z.checked {
+		z.checked = true
+		code := make([]byte, 10)
+		if _, err := io.ReadFull(z.tail, code); err != nil {
+			return n, fmt.Errorf("failed to read authentication code: %w", err)
+		}
+		if !hmac.Equal(code, z.mac.Sum(nil)[:10]) {
+			return n, fmt.Errorf("%w: authentication code mismatch", ErrIntegrityCheck)
+		}
+	}
+	return n, err
+}
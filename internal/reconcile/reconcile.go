@@ -0,0 +1,200 @@
+package reconcile
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// relativeTolerance absorbs floating point differences between engines that
+// sum values in a different order.
+const relativeTolerance = 1e-9
+
+type Result struct {
+	Source1       string
+	Source2       string
+	RowCount1     int
+	RowCount2     int
+	Digest1       string
+	Digest2       string
+	Columns       []ColumnResult
+	OnlyInSource1 []string
+	OnlyInSource2 []string
+}
+
+type ColumnResult struct {
+	Name        string
+	DigestMatch bool
+	Mismatches  []string
+}
+
+func (r *Result) RowCountMatch() bool {
+	return r.RowCount1 == r.RowCount2
+}
+
+func (r *Result) DigestMatch() bool {
+	return r.Digest1 != "" && r.Digest1 == r.Digest2
+}
+
+func (r *Result) MismatchedColumns() []ColumnResult {
+	mismatched := make([]ColumnResult, 0)
+	for _, col := range r.Columns {
+		if !col.Matched() {
+			mismatched = append(mismatched, col)
+		}
+	}
+	return mismatched
+}
+
+func (r *Result) Matched() bool {
+	return r.RowCountMatch() && len(r.OnlyInSource1) == 0 && len(r.OnlyInSource2) == 0 &&
+		len(r.MismatchedColumns()) == 0
+}
+
+func (c ColumnResult) Matched() bool {
+	return c.DigestMatch && len(c.Mismatches) == 0
+}
+
+func Reconcile(source1, source2 *profiler.DatasetProfile) *Result {
+	result := &Result{
+		Source1:       source1.Filename,
+		Source2:       source2.Filename,
+		RowCount1:     source1.RowCount,
+		RowCount2:     source2.RowCount,
+		Digest1:       source1.ContentDigest,
+		Digest2:       source2.ContentDigest,
+		Columns:       make([]ColumnResult, 0),
+		OnlyInSource1: make([]string, 0),
+		OnlyInSource2: make([]string, 0),
+	}
+
+	names1 := sortedColumnNames(source1)
+	names2 := sortedColumnNames(source2)
+	pairs := matchColumns(names1, names2)
+	matched := make(map[string]bool)
+
+	for _, name := range names1 {
+		col1 := source1.Columns[name]
+
+		col2Name, ok := pairs[name]
+		if !ok {
+			result.OnlyInSource1 = append(result.OnlyInSource1, name)
+			continue
+		}
+		matched[col2Name] = true
+
+		result.Columns = append(result.Columns, compareColumns(name, col1, source2.Columns[col2Name]))
+	}
+
+	for _, name := range names2 {
+		if !matched[name] {
+			result.OnlyInSource2 = append(result.OnlyInSource2, name)
+		}
+	}
+
+	return result
+}
+
+func compareColumns(name string, col1, col2 *profiler.ColumnProfile) ColumnResult {
+	result := ColumnResult{
+		Name:        name,
+		DigestMatch: col1.Digest != "" && col1.Digest == col2.Digest,
+		Mismatches:  make([]string, 0),
+	}
+
+	compareInt := func(metric string, v1, v2 int) {
+		if v1 != v2 {
+			result.Mismatches = append(result.Mismatches, fmt.Sprintf("%s: %d vs %d", metric, v1, v2))
+		}
+	}
+
+	compareFloat := func(metric string, v1, v2 float64) {
+		if !floatsEqual(v1, v2) {
+			result.Mismatches = append(result.Mismatches, fmt.Sprintf("%s: %g vs %g", metric, v1, v2))
+		}
+	}
+
+	compareInt("count", col1.Count, col2.Count)
+	compareInt("missing", col1.MissingCount, col2.MissingCount)
+	compareInt("unique", col1.UniqueCount, col2.UniqueCount)
+
+	if col1.IsNumeric && col2.IsNumeric {
+		compareFloat("min", toFloat(col1.Min), toFloat(col2.Min))
+		compareFloat("max", toFloat(col1.Max), toFloat(col2.Max))
+		compareFloat("mean", col1.Mean, col2.Mean)
+		compareFloat("stddev", col1.StdDev, col2.StdDev)
+	} else if col1.IsNumeric != col2.IsNumeric {
+		result.Mismatches = append(result.Mismatches,
+			fmt.Sprintf("type: %s vs %s", col1.DataType, col2.DataType))
+	}
+
+	return result
+}
+
+// matchColumns pairs the columns of the first source with those of the
+// second, matching names exactly first and then case-insensitively, since
+// databases often fold identifier case. Each column of the second source is
+// paired at most once, so of ID and id only one matches a lone ID and the
+// other is reported as missing.
+func matchColumns(names1, names2 []string) map[string]string {
+	pairs := make(map[string]string)
+	claimed := make(map[string]bool)
+
+	for _, name := range names2 {
+		claimed[name] = false
+	}
+	for _, name := range names1 {
+		if _, ok := claimed[name]; ok {
+			pairs[name] = name
+			claimed[name] = true
+		}
+	}
+
+	for _, name := range names1 {
+		if _, ok := pairs[name]; ok {
+			continue
+		}
+		for _, candidate := range names2 {
+			if !claimed[candidate] && strings.EqualFold(candidate, name) {
+				pairs[name] = candidate
+				claimed[candidate] = true
+				break
+			}
+		}
+	}
+
+	return pairs
+}
+
+func sortedColumnNames(profile *profiler.DatasetProfile) []string {
+	names := make([]string, 0, len(profile.Columns))
+	for name := range profile.Columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func floatsEqual(a, b float64) bool {
+	if a == b {
+		return true
+	}
+	scale := math.Max(math.Abs(a), math.Abs(b))
+	return math.Abs(a-b) <= relativeTolerance*scale
+}
+
+func toFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	default:
+		return 0
+	}
+}
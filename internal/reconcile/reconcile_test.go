@@ -0,0 +1,98 @@
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func createProfile(digest string, columns map[string]*profiler.ColumnProfile) *profiler.DatasetProfile {
+	return &profiler.DatasetProfile{
+		Filename:      "test.csv",
+		RowCount:      100,
+		ColumnCount:   len(columns),
+		ContentDigest: digest,
+		Columns:       columns,
+	}
+}
+
+func TestReconcileIdentical(t *testing.T) {
+	newColumns := func() map[string]*profiler.ColumnProfile {
+		return map[string]*profiler.ColumnProfile{
+			"id":     {Name: "id", Count: 100, UniqueCount: 100, Digest: "aaaa", IsNumeric: true, Min: float64(1), Max: float64(100), Mean: 50.5},
+			"status": {Name: "status", Count: 98, MissingCount: 2, UniqueCount: 3, Digest: "bbbb"},
+		}
+	}
+
+	source2Columns := newColumns()
+	source2Columns["STATUS"] = source2Columns["status"]
+	delete(source2Columns, "status")
+
+	result := Reconcile(createProfile("ffff", newColumns()), createProfile("ffff", source2Columns))
+
+	if !result.Matched() {
+		t.Errorf("Expected sources to reconcile, got mismatches %v", result.MismatchedColumns())
+	}
+
+	if len(result.Columns) != 2 {
+		t.Errorf("Expected 2 reconciled columns, got %d", len(result.Columns))
+	}
+}
+
+func TestReconcileMismatches(t *testing.T) {
+	source1 := createProfile("ffff", map[string]*profiler.ColumnProfile{
+		"id":     {Name: "id", Count: 100, UniqueCount: 100, Digest: "aaaa", IsNumeric: true, Mean: 50.5},
+		"amount": {Name: "amount", Count: 100, UniqueCount: 40, Digest: "cccc", IsNumeric: true, Mean: 10},
+		"legacy": {Name: "legacy", Count: 100, Digest: "dddd"},
+	})
+	source2 := createProfile("eeee", map[string]*profiler.ColumnProfile{
+		"id":     {Name: "id", Count: 100, UniqueCount: 100, Digest: "aaaa", IsNumeric: true, Mean: 50.5},
+		"amount": {Name: "amount", Count: 100, UniqueCount: 40, Digest: "cccd", IsNumeric: true, Mean: 10.5},
+	})
+	source2.RowCount = 99
+
+	result := Reconcile(source1, source2)
+
+	if result.Matched() {
+		t.Fatal("Expected sources not to reconcile")
+	}
+
+	if result.RowCountMatch() {
+		t.Error("Expected row count mismatch")
+	}
+
+	if len(result.OnlyInSource1) != 1 || result.OnlyInSource1[0] != "legacy" {
+		t.Errorf("Expected 'legacy' to be reported as missing, got %v", result.OnlyInSource1)
+	}
+
+	mismatched := result.MismatchedColumns()
+	if len(mismatched) != 1 || mismatched[0].Name != "amount" {
+		t.Fatalf("Expected only 'amount' to mismatch, got %v", mismatched)
+	}
+
+	if len(mismatched[0].Mismatches) != 1 {
+		t.Errorf("Expected a single mean mismatch, got %v", mismatched[0].Mismatches)
+	}
+}
+
+func TestReconcileCaseFoldedColumnsMatchOnce(t *testing.T) {
+	source1 := createProfile("ffff", map[string]*profiler.ColumnProfile{
+		"ID": {Name: "ID", Count: 100, Digest: "aaaa"},
+		"id": {Name: "id", Count: 100, Digest: "aaaa"},
+	})
+	source2 := createProfile("ffff", map[string]*profiler.ColumnProfile{
+		"ID": {Name: "ID", Count: 100, Digest: "aaaa"},
+	})
+
+	result := Reconcile(source1, source2)
+
+	if len(result.Columns) != 1 || result.Columns[0].Name != "ID" {
+		t.Errorf("Expected only 'ID' to be reconciled, got %v", result.Columns)
+	}
+	if len(result.OnlyInSource1) != 1 || result.OnlyInSource1[0] != "id" {
+		t.Errorf("Expected 'id' to be reported as missing, got %v", result.OnlyInSource1)
+	}
+	if result.Matched() {
+		t.Error("Expected sources not to reconcile")
+	}
+}
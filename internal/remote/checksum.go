@@ -0,0 +1,165 @@
+package remote
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrChecksumMismatch is returned, wrapped, when a downloaded object does not
+// match its expected checksum.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// Checksum is an expected digest of a whole object.
+type Checksum struct {
+	Algorithm string // md5, sha1, sha256, crc32 or crc32c
+	Sum       []byte
+	Origin    string // where the expected value came from
+}
+
+func (c Checksum) String() string {
+	return c.Algorithm + ":" + hex.EncodeToString(c.Sum)
+}
+
+// ParseChecksum parses algorithm:digest, with the digest in hex or base64,
+// for example sha256:9f86d08... or md5:CY9rzUYh03PK3k6DJie09g==.
+func ParseChecksum(value string) (Checksum, error) {
+	algorithm, digest, ok := strings.Cut(value, ":")
+	if !ok {
+		return Checksum{}, fmt.Errorf("invalid checksum %q: use algorithm:digest, e.g. sha256:<hex>", value)
+	}
+
+	algorithm = strings.ToLower(algorithm)
+	h := newHash(algorithm)
+	if h == nil {
+		return Checksum{}, fmt.Errorf("invalid checksum %q: unsupported algorithm %s (use md5, sha1, sha256, crc32 or crc32c)", value, algorithm)
+	}
+
+	sum, err := decodeDigest(digest, h.Size())
+	if err != nil {
+		return Checksum{}, fmt.Errorf("invalid checksum %q: %w", value, err)
+	}
+
+	return Checksum{Algorithm: algorithm, Sum: sum, Origin: "--checksum"}, nil
+}
+
+func decodeDigest(digest string, size int) ([]byte, error) {
+	if sum, err := hex.DecodeString(digest); err == nil && len(sum) == size {
+		return sum, nil
+	}
+	if sum, err := base64.StdEncoding.DecodeString(digest); err == nil && len(sum) == size {
+		return sum, nil
+	}
+	return nil, fmt.Errorf("expected a %d-byte digest in hex or base64", size)
+}
+
+func newHash(algorithm string) hash.Hash {
+	switch algorithm {
+	case "md5":
+		return md5.New()
+	case "sha1":
+		return sha1.New()
+	case "sha256":
+		return sha256.New()
+	case "crc32":
+		return crc32.NewIEEE()
+	case "crc32c":
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	default:
+		return nil
+	}
+}
+
+// serverChecksums collects the whole-object checksums a storage service
+// sent with a full response: S3's x-amz-checksum-* (except the composite
+// checksums of multipart uploads), GCS's x-goog-hash, and Content-MD5.
+func serverChecksums(header http.Header) []Checksum {
+	checksums := make([]Checksum, 0)
+	add := func(algorithm, digest, origin string) {
+		h := newHash(algorithm)
+		if sum, err := base64.StdEncoding.DecodeString(digest); err == nil && h != nil && len(sum) == h.Size() {
+			checksums = append(checksums, Checksum{Algorithm: algorithm, Sum: sum, Origin: origin})
+		}
+	}
+
+	for _, algorithm := range []string{"sha256", "sha1", "crc32c", "crc32"} {
+		name := "X-Amz-Checksum-" + algorithm
+		if digest := header.Get(name); digest != "" && !strings.Contains(digest, "-") {
+			add(algorithm, digest, strings.ToLower(name))
+		}
+	}
+
+	for _, value := range header.Values("X-Goog-Hash") {
+		for _, part := range strings.Split(value, ",") {
+			algorithm, digest, ok := strings.Cut(strings.TrimSpace(part), "=")
+			if ok {
+				add(algorithm, digest, "x-goog-hash")
+			}
+		}
+	}
+
+	if digest := header.Get("Content-Md5"); digest != "" {
+		add("md5", digest, "content-md5")
+	}
+
+	return checksums
+}
+
+// verifyingBody hashes everything read and checks the digests once the
+// whole object has been read.
+type verifyingBody struct {
+	io.ReadCloser
+	name      string
+	checksums []Checksum
+	hashes    []hash.Hash
+	writer    io.Writer
+	verified  bool
+}
+
+func newVerifyingBody(body io.ReadCloser, name string, checksums []Checksum) io.ReadCloser {
+	if len(checksums) == 0 {
+		return body
+	}
+
+	hashes := make([]hash.Hash, len(checksums))
+	writers := make([]io.Writer, len(checksums))
+	for i, c := range checksums {
+		hashes[i] = newHash(c.Algorithm)
+		writers[i] = hashes[i]
+	}
+
+	return &verifyingBody{
+		ReadCloser: body,
+		name:       name,
+		checksums:  checksums,
+		hashes:     hashes,
+		writer:     io.MultiWriter(writers...),
+	}
+}
+
+func (v *verifyingBody) Read(p []byte) (int, error) {
+	n, err := v.ReadCloser.Read(p)
+	v.writer.Write(p[:n])
+
+	if err == io.EOF && !v.verified {
+		v.verified = true
+		for i, c := range v.checksums {
+			if got := v.hashes[i].Sum(nil); !bytes.Equal(got, c.Sum) {
+				return n, fmt.Errorf("failed to verify %s: %s is %s, expected %s (from %s): %w",
+					v.name, c.Algorithm, hex.EncodeToString(got), hex.EncodeToString(c.Sum), c.Origin, ErrChecksumMismatch)
+			}
+		}
+	}
+
+	return n, err
+}
@@ -0,0 +1,216 @@
+package remote
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const gcsScope = "https://www.googleapis.com/auth/devstorage.read_only"
+
+type googleCredentials struct {
+	Type         string `json:"type"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	TokenURI     string `json:"token_uri"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// resolveGCS maps gs://bucket/object to the Cloud Storage JSON API media
+// download URL. STORAGE_EMULATOR_HOST redirects requests to an emulator
+// without credentials. Otherwise an access token comes from
+// GOOGLE_OAUTH_ACCESS_TOKEN or from Application Default Credentials
+// (GOOGLE_APPLICATION_CREDENTIALS or the gcloud default file), and requests
+// are anonymous when none are found.
+func resolveGCS(u *url.URL) (*object, error) {
+	bucket := u.Host
+	name := strings.TrimPrefix(u.Path, "/")
+	if bucket == "" || name == "" {
+		return nil, fmt.Errorf("invalid GCS URL %s: use gs://bucket/object", u.String())
+	}
+
+	base := "https://storage.googleapis.com"
+	emulator := os.Getenv("STORAGE_EMULATOR_HOST")
+	if emulator != "" {
+		base = strings.TrimSuffix(emulator, "/")
+		if !strings.Contains(base, "://") {
+			base = "http://" + base
+		}
+	}
+
+	obj := &object{
+		url:  fmt.Sprintf("%s/download/storage/v1/b/%s/o/%s?alt=media", base, url.PathEscape(bucket), url.PathEscape(name)),
+		name: path.Base(name),
+		sign: func(*http.Request) error { return nil },
+	}
+	if emulator != "" {
+		return obj, nil
+	}
+
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		obj.sign = bearer(func() (string, error) { return token, nil })
+		return obj, nil
+	}
+
+	creds, err := loadGoogleCredentials()
+	if err != nil {
+		return nil, err
+	}
+	if creds == nil {
+		return obj, nil
+	}
+
+	var (
+		once  sync.Once
+		token string
+		tErr  error
+	)
+	obj.sign = bearer(func() (string, error) {
+		once.Do(func() { token, tErr = creds.accessToken() })
+		return token, tErr
+	})
+
+	return obj, nil
+}
+
+func bearer(token func() (string, error)) func(*http.Request) error {
+	return func(req *http.Request) error {
+		t, err := token()
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+t)
+		return nil
+	}
+}
+
+func loadGoogleCredentials() (*googleCredentials, error) {
+	file := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	if file == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil
+		}
+		file = filepath.Join(home, ".config", "gcloud", "application_default_credentials.json")
+		if _, err := os.Stat(file); err != nil {
+			return nil, nil
+		}
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Google credentials: %w", err)
+	}
+
+	var creds googleCredentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("failed to parse Google credentials: %w", err)
+	}
+	if creds.TokenURI == "" {
+		creds.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	return &creds, nil
+}
+
+func (c *googleCredentials) accessToken() (string, error) {
+	form := url.Values{}
+
+	switch c.Type {
+	case "service_account":
+		assertion, err := c.jwtAssertion(time.Now())
+		if err != nil {
+			return "", err
+		}
+		form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+		form.Set("assertion", assertion)
+	case "authorized_user":
+		form.Set("grant_type", "refresh_token")
+		form.Set("client_id", c.ClientID)
+		form.Set("client_secret", c.ClientSecret)
+		form.Set("refresh_token", c.RefreshToken)
+	default:
+		return "", fmt.Errorf("unsupported Google credentials type: %s", c.Type)
+	}
+
+	resp, err := client.PostForm(c.TokenURI, form)
+	if err != nil {
+		return "", fmt.Errorf("failed to get Google access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to get Google access token: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get Google access token: %s %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", fmt.Errorf("failed to parse Google access token: %w", err)
+	}
+
+	return token.AccessToken, nil
+}
+
+// jwtAssertion builds the RS256-signed JWT a service account exchanges for
+// an access token.
+func (c *googleCredentials) jwtAssertion(now time.Time) (string, error) {
+	block, _ := pem.Decode([]byte(c.PrivateKey))
+	if block == nil {
+		return "", fmt.Errorf("failed to parse service account private key")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to parse service account private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return "", fmt.Errorf("service account private key is not RSA")
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":   c.ClientEmail,
+		"scope": gcsScope,
+		"aud":   c.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign service account assertion: %w", err)
+	}
+
+	return unsigned + "." + enc.EncodeToString(signature), nil
+}
@@ -0,0 +1,391 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	readerAtBlockSize = 4 << 20
+	readerAtMaxBlocks = 8
+)
+
+var client = http.DefaultClient
+
+// ErrNotFound is returned, wrapped, when the object does not exist.
+var ErrNotFound = errors.New("object not found")
+
+// Info describes a remote object.
+type Info struct {
+	Name string // base name of the object
+	Size int64  // full object size, -1 when the server does not say
+}
+
+// object is a resolved remote source: the HTTP URL to fetch and a function
+// that adds the provider's credentials to a request.
+type object struct {
+	url  string
+	name string
+	sign func(req *http.Request) error
+	ctx  context.Context // cancels requests, nil for none
+}
+
+// IsURL reports whether source is a remote object URL supported by Open.
+func IsURL(source string) bool {
+	scheme, _, ok := strings.Cut(source, "://")
+	if !ok {
+		return false
+	}
+
+	switch strings.ToLower(scheme) {
+	case "http", "https", "s3", "gs", "az":
+		return true
+	default:
+		return false
+	}
+}
+
+// Path returns the object path of a URL without query string, for format
+// detection by extension.
+func Path(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Path
+}
+
+func resolve(rawURL string) (*object, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %s: %w", rawURL, err)
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return &object{
+			url:  u.String(),
+			name: path.Base(u.Path),
+			sign: func(*http.Request) error { return nil },
+		}, nil
+	case "s3":
+		return resolveS3(u)
+	case "gs":
+		return resolveGCS(u)
+	case "az":
+		return resolveAzure(u)
+	default:
+		return nil, fmt.Errorf("unsupported URL scheme: %s", u.Scheme)
+	}
+}
+
+// Join resolves a slash-separated path relative to a remote URL, as for the
+// files of a table directory.
+func Join(rawURL, elem string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return strings.TrimSuffix(rawURL, "/") + "/" + elem
+	}
+	u.Path = path.Join(u.Path, elem)
+	u.RawPath = ""
+	return u.String()
+}
+
+// OpenOptions controls how Open reads an object.
+type OpenOptions struct {
+	Limit    int64           // positive to fetch only the first Limit bytes with a range request
+	Checksum *Checksum       // expected digest of the whole object
+	Context  context.Context // cancels the requests when done, nil for none
+}
+
+// Open streams a remote object. Transient failures are retried; a whole
+// object whose connection breaks part way is resumed where it stopped. The
+// object is verified against opts.Checksum and any whole-object checksum the
+// service sends, when it is read to the end.
+func Open(rawURL string, opts OpenOptions) (io.ReadCloser, *Info, error) {
+	obj, err := resolve(rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	obj.ctx = opts.Context
+	if opts.Checksum != nil && opts.Limit > 0 {
+		return nil, nil, fmt.Errorf("cannot verify the checksum of %s when reading only part of it", obj.name)
+	}
+
+	var byteRange string
+	if opts.Limit > 0 {
+		byteRange = fmt.Sprintf("bytes=0-%d", opts.Limit-1)
+	}
+
+	resp, err := obj.get(byteRange)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := &Info{Name: obj.name, Size: resp.ContentLength}
+	if resp.StatusCode == http.StatusPartialContent {
+		info.Size = totalSize(resp.Header.Get("Content-Range"))
+	}
+
+	if opts.Limit > 0 {
+		// Servers that ignore Range send the whole object
+		body := struct {
+			io.Reader
+			io.Closer
+		}{io.LimitReader(resp.Body, opts.Limit), resp.Body}
+		return body, info, nil
+	}
+
+	validator := resp.Header.Get("ETag")
+	if validator == "" || strings.HasPrefix(validator, "W/") {
+		validator = resp.Header.Get("Last-Modified")
+	}
+	body := &resumingBody{
+		obj:       obj,
+		body:      resp.Body,
+		validator: validator,
+		size:      resp.ContentLength,
+	}
+
+	checksums := serverChecksums(resp.Header)
+	if opts.Checksum != nil {
+		checksums = append([]Checksum{*opts.Checksum}, checksums...)
+	}
+
+	return newVerifyingBody(body, obj.name, checksums), info, nil
+}
+
+func (o *object) get(byteRange string) (*http.Response, error) {
+	return o.getIfRange(byteRange, "")
+}
+
+// getIfRange fetches the object, retrying transient failures. A validator
+// makes the server send the whole object instead of the range when the
+// object changed.
+func (o *object) getIfRange(byteRange, validator string) (*http.Response, error) {
+	var resp *http.Response
+	err := withRetry(o.name, func() error {
+		var err error
+		resp, err = o.do(byteRange, validator)
+		return err
+	})
+	return resp, err
+}
+
+// do makes a single request for the object. Failures worth retrying are
+// returned as transientError.
+func (o *object) do(byteRange, validator string) (*http.Response, error) {
+	ctx := o.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if byteRange != "" {
+		req.Header.Set("Range", byteRange)
+	}
+	if validator != "" {
+		req.Header.Set("If-Range", validator)
+	}
+
+	if err := o.sign(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil && ctx.Err() != nil {
+		// Canceled, not worth retrying
+		return nil, fmt.Errorf("failed to fetch %s: %w", o.name, ctx.Err())
+	}
+	if err != nil {
+		return nil, &transientError{err: fmt.Errorf("failed to fetch %s: %w", o.name, err)}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+		return resp, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch %s: %s: %w", o.name, resp.Status, ErrNotFound)
+	case http.StatusRequestedRangeNotSatisfiable:
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch %s: range %s not satisfiable", o.name, byteRange)
+	default:
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		err := fmt.Errorf("failed to fetch %s: %s %s", o.name, resp.Status, strings.TrimSpace(string(message)))
+		if transientStatus(resp.StatusCode) {
+			return nil, &transientError{err: err, retryAfter: retryAfter(resp.Header.Get("Retry-After"))}
+		}
+		return nil, err
+	}
+}
+
+// totalSize parses the total of a "bytes 0-99/1234" Content-Range header.
+func totalSize(contentRange string) int64 {
+	_, total, ok := strings.Cut(contentRange, "/")
+	if !ok {
+		return -1
+	}
+	size, err := strconv.ParseInt(total, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return size
+}
+
+// Stat returns the name and size of a remote object without reading it.
+func Stat(rawURL string) (*Info, error) {
+	obj, err := resolve(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := obj.size()
+	if err != nil {
+		return nil, err
+	}
+	return &Info{Name: obj.name, Size: size}, nil
+}
+
+// size asks for the first byte of the object and reads the total size off
+// the answer.
+func (obj *object) size() (int64, error) {
+	resp, err := obj.get("bytes=0-0")
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	size := resp.ContentLength
+	if resp.StatusCode == http.StatusPartialContent {
+		size = totalSize(resp.Header.Get("Content-Range"))
+	}
+	if size < 0 {
+		return 0, fmt.Errorf("failed to fetch %s: server did not report the object size", obj.name)
+	}
+	return size, nil
+}
+
+// ReaderAt gives random access to a remote object through range requests,
+// for formats such as Parquet that read the footer first. Reads are served
+// from a small cache of fixed-size blocks.
+type ReaderAt struct {
+	obj  *object
+	size int64
+
+	mu     sync.Mutex
+	blocks map[int64][]byte
+	order  []int64
+}
+
+// NewReaderAt opens a remote object for random access. Its requests are
+// canceled when ctx is done.
+func NewReaderAt(ctx context.Context, rawURL string) (*ReaderAt, *Info, error) {
+	obj, err := resolve(rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	obj.ctx = ctx
+
+	size, err := obj.size()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r := &ReaderAt{
+		obj:    obj,
+		size:   size,
+		blocks: make(map[int64][]byte),
+	}
+	return r, &Info{Name: obj.name, Size: size}, nil
+}
+
+func (r *ReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off >= r.size {
+		return 0, io.EOF
+	}
+
+	n := 0
+	for n < len(p) && off+int64(n) < r.size {
+		pos := off + int64(n)
+		index := pos / readerAtBlockSize
+
+		block, err := r.block(index)
+		if err != nil {
+			return n, err
+		}
+
+		n += copy(p[n:], block[pos-index*readerAtBlockSize:])
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (r *ReaderAt) block(index int64) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if block, ok := r.blocks[index]; ok {
+		return block, nil
+	}
+
+	start := index * readerAtBlockSize
+	end := start + readerAtBlockSize - 1
+	if end >= r.size {
+		end = r.size - 1
+	}
+
+	block, err := r.fetch(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(r.order) >= readerAtMaxBlocks {
+		delete(r.blocks, r.order[0])
+		r.order = r.order[1:]
+	}
+	r.blocks[index] = block
+	r.order = append(r.order, index)
+
+	return block, nil
+}
+
+// fetch reads one block, fetching it again when the transfer breaks.
+func (r *ReaderAt) fetch(start, end int64) ([]byte, error) {
+	var block []byte
+	err := withRetry(r.obj.name, func() error {
+		resp, err := r.obj.do(fmt.Sprintf("bytes=%d-%d", start, end), "")
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusPartialContent {
+			return fmt.Errorf("failed to fetch %s: server does not support range requests", r.obj.name)
+		}
+
+		block, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return &transientError{err: fmt.Errorf("failed to fetch %s: %w", r.obj.name, err)}
+		}
+		if int64(len(block)) != end-start+1 {
+			return &transientError{err: fmt.Errorf("failed to fetch %s: short range response", r.obj.name)}
+		}
+		return nil
+	})
+	return block, err
+}
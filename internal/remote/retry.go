@@ -0,0 +1,204 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/events"
+)
+
+// Retry controls how requests that fail with a transient error are retried.
+// Connection errors, timeouts, 408, 429 and 5xx responses are transient; the
+// delay before each retry doubles from Backoff up to MaxBackoff, or follows
+// the server's Retry-After.
+type Retry struct {
+	Attempts   int // retries after the first try, 0 disables retrying
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+func DefaultRetry() Retry {
+	return Retry{
+		Attempts:   3,
+		Backoff:    time.Second,
+		MaxBackoff: 30 * time.Second,
+	}
+}
+
+var (
+	retryMu     sync.Mutex
+	retryPolicy = DefaultRetry()
+	sleep       = time.Sleep
+)
+
+// SetRetry sets the retry policy of every remote request.
+func SetRetry(r Retry) {
+	retryMu.Lock()
+	defer retryMu.Unlock()
+	retryPolicy = r
+}
+
+func currentRetry() Retry {
+	retryMu.Lock()
+	defer retryMu.Unlock()
+	return retryPolicy
+}
+
+// delay is how long to wait before retry number attempt (from 1).
+func (r Retry) delay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		if r.MaxBackoff > 0 && retryAfter > r.MaxBackoff {
+			return r.MaxBackoff
+		}
+		return retryAfter
+	}
+
+	d := r.Backoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if r.MaxBackoff > 0 && d >= r.MaxBackoff {
+			return r.MaxBackoff
+		}
+	}
+	return d
+}
+
+// transientError is a failure worth retrying.
+type transientError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+func (e *transientError) Unwrap() error { return e.err }
+
+func isTransient(err error) bool {
+	var t *transientError
+	return errors.As(err, &t)
+}
+
+// withRetry calls fn until it succeeds, fails permanently or runs out of
+// retries.
+func withRetry(name string, fn func() error) error {
+	policy := currentRetry()
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = fn()
+		if err == nil || !isTransient(err) {
+			return err
+		}
+		if attempt >= policy.Attempts {
+			break
+		}
+
+		var t *transientError
+		errors.As(err, &t)
+		wait := policy.delay(attempt+1, t.retryAfter)
+		events.Publish(events.Event{
+			Kind:    events.Warning,
+			Source:  name,
+			Message: fmt.Sprintf("%v; retrying in %s (%d of %d)", err, wait, attempt+1, policy.Attempts),
+		})
+		sleep(wait)
+	}
+
+	if policy.Attempts > 0 {
+		return fmt.Errorf("%w (gave up on %s after %d attempts)", err, name, policy.Attempts+1)
+	}
+	return err
+}
+
+func transientStatus(code int) bool {
+	return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500
+}
+
+// retryAfter parses a Retry-After header given in seconds or as a date.
+func retryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	var seconds int
+	if _, err := fmt.Sscanf(header, "%d", &seconds); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+// resumingBody reads a whole object and, when the connection breaks part way,
+// continues from the last byte read with a range request instead of starting
+// over. The If-Range validator makes sure the rest comes from the same version
+// of the object.
+type resumingBody struct {
+	obj       *object
+	body      io.ReadCloser
+	validator string // ETag or Last-Modified of the first response
+	size      int64
+	offset    int64
+	stalled   int // resumes in a row that brought no data
+	err       error
+}
+
+func (r *resumingBody) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	if n > 0 {
+		r.stalled = 0
+	}
+	if err == nil || err == io.EOF {
+		return n, err
+	}
+	r.body.Close()
+
+	if r.size >= 0 && r.offset >= r.size {
+		r.err = io.EOF
+		return n, r.err
+	}
+	if r.size < 0 || r.validator == "" || r.stalled > currentRetry().Attempts || (r.obj.ctx != nil && r.obj.ctx.Err() != nil) {
+		r.err = fmt.Errorf("failed to read %s at byte %d: %w", r.obj.name, r.offset, err)
+		return n, r.err
+	}
+	r.stalled++
+	events.Publish(events.Event{
+		Kind:    events.Warning,
+		Source:  r.obj.name,
+		Message: fmt.Sprintf("connection broke at byte %d (%v); resuming", r.offset, err),
+	})
+
+	if err := r.resume(); err != nil {
+		r.err = err
+		return n, r.err
+	}
+	if n > 0 {
+		return n, nil
+	}
+	return r.Read(p)
+}
+
+func (r *resumingBody) resume() error {
+	resp, err := r.obj.getIfRange(fmt.Sprintf("bytes=%d-", r.offset), r.validator)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return fmt.Errorf("failed to resume %s at byte %d: the object changed or the server ignores ranges", r.obj.name, r.offset)
+	}
+	r.body = resp.Body
+	return nil
+}
+
+func (r *resumingBody) Close() error {
+	return r.body.Close()
+}
@@ -0,0 +1,273 @@
+package remote
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func withoutSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	delays := make([]time.Duration, 0)
+	sleep = func(d time.Duration) { delays = append(delays, d) }
+	t.Cleanup(func() {
+		sleep = time.Sleep
+		SetRetry(DefaultRetry())
+	})
+	return &delays
+}
+
+func TestOpenRetriesTransientFailures(t *testing.T) {
+	delays := withoutSleep(t)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch requests {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			io.WriteString(w, "a,b\n1,2\n")
+		}
+	}))
+	defer server.Close()
+
+	body, _, err := Open(server.URL+"/data.csv", OpenOptions{})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	body.Close()
+
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+	if len(*delays) != 2 || (*delays)[0] != time.Second || (*delays)[1] != 7*time.Second {
+		t.Errorf("Expected a 1s backoff then the 7s Retry-After, got %v", *delays)
+	}
+}
+
+func TestOpenGivesUp(t *testing.T) {
+	withoutSleep(t)
+	SetRetry(Retry{Attempts: 2, Backoff: time.Millisecond})
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	_, _, err := Open(server.URL+"/data.csv", OpenOptions{})
+	if err == nil || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Errorf("Expected to give up after 3 attempts, got %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+
+	// Permanent failures are not retried
+	requests = 0
+	forbidden := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer forbidden.Close()
+
+	if _, _, err := Open(forbidden.URL+"/data.csv", OpenOptions{}); err == nil || requests != 1 {
+		t.Errorf("Expected a single failed request, got %d (%v)", requests, err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	r := Retry{Attempts: 5, Backoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		if got := r.delay(attempt+1, 0); got != want {
+			t.Errorf("delay(%d): expected %v, got %v", attempt+1, want, got)
+		}
+	}
+	if got := r.delay(1, time.Minute); got != 5*time.Second {
+		t.Errorf("Expected Retry-After capped at the maximum backoff, got %v", got)
+	}
+}
+
+// brokenServer sends the first half of content and drops the connection on
+// the first request, then serves ranges normally.
+func brokenServer(t *testing.T, content string, headers map[string]string) (*httptest.Server, *[]string) {
+	t.Helper()
+	ranges := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		for name, value := range headers {
+			w.Header().Set(name, value)
+		}
+		w.Header().Set("ETag", `"v1"`)
+
+		if len(ranges) == 1 {
+			w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+			w.WriteHeader(http.StatusOK)
+			io.WriteString(w, content[:len(content)/2])
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		http.ServeContent(w, r, "data.csv", time.Time{}, strings.NewReader(content))
+	}))
+	t.Cleanup(server.Close)
+	return server, &ranges
+}
+
+func TestOpenResumesBrokenTransfer(t *testing.T) {
+	withoutSleep(t)
+	content := strings.Repeat("id,value\n1,2\n", 500)
+	server, ranges := brokenServer(t, content, nil)
+
+	body, _, err := Open(server.URL+"/data.csv", OpenOptions{})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if string(data) != content {
+		t.Errorf("Expected the whole object after resuming, got %d of %d bytes", len(data), len(content))
+	}
+	if len(*ranges) != 2 || !strings.HasPrefix((*ranges)[1], "bytes=") || (*ranges)[1] == "bytes=0-" {
+		t.Errorf("Expected a second request resuming mid-object, got ranges %q", *ranges)
+	}
+}
+
+func TestOpenCanceled(t *testing.T) {
+	withoutSleep(t)
+	content := strings.Repeat("id,value\n1,2\n", 500)
+	server, ranges := brokenServer(t, content, nil)
+
+	// A transfer broken after the context is done is not resumed
+	ctx, cancel := context.WithCancel(context.Background())
+	body, _, err := Open(server.URL+"/data.csv", OpenOptions{Context: ctx})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer body.Close()
+	cancel()
+
+	if _, err := io.ReadAll(body); err == nil {
+		t.Errorf("Expected the canceled transfer to fail")
+	}
+	if len(*ranges) != 1 {
+		t.Errorf("Expected no request after canceling, got ranges %q", *ranges)
+	}
+
+	// Nor is a failed request retried
+	if _, _, err := Open(server.URL+"/data.csv", OpenOptions{Context: ctx}); !errors.Is(err, context.Canceled) || len(*ranges) != 1 {
+		t.Errorf("Expected a canceled error without a request, got %v after ranges %q", err, *ranges)
+	}
+}
+
+func TestOpenVerifiesChecksums(t *testing.T) {
+	content := "a,b\n1,2\n3,4\n"
+	md5Sum := md5.Sum([]byte(content))
+	sha := sha256.Sum256([]byte(content))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Sum[:]))
+		io.WriteString(w, content)
+	}))
+	defer server.Close()
+
+	read := func(checksum *Checksum) error {
+		body, _, err := Open(server.URL+"/data.csv", OpenOptions{Checksum: checksum})
+		if err != nil {
+			return err
+		}
+		defer body.Close()
+		_, err = io.ReadAll(body)
+		return err
+	}
+
+	good, err := ParseChecksum("sha256:" + hex.EncodeToString(sha[:]))
+	if err != nil {
+		t.Fatalf("ParseChecksum failed: %v", err)
+	}
+	if err := read(&good); err != nil {
+		t.Errorf("Expected matching checksums to pass, got %v", err)
+	}
+
+	bad, _ := ParseChecksum("sha256:" + strings.Repeat("00", 32))
+	if err := read(&bad); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Expected ErrChecksumMismatch, got %v", err)
+	}
+
+	if _, _, err := Open(server.URL+"/data.csv", OpenOptions{Limit: 5, Checksum: &good}); err == nil {
+		t.Error("Expected an error verifying part of an object")
+	}
+}
+
+func TestOpenVerifiesResumedTransfer(t *testing.T) {
+	withoutSleep(t)
+	content := strings.Repeat("x,y\n", 1000)
+	wrong := md5.Sum([]byte("something else"))
+	server, _ := brokenServer(t, content, map[string]string{"Content-MD5": base64.StdEncoding.EncodeToString(wrong[:])})
+
+	body, _, err := Open(server.URL+"/data.csv", OpenOptions{})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer body.Close()
+
+	if _, err := io.ReadAll(body); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Expected the server's Content-MD5 to be checked, got %v", err)
+	}
+}
+
+func TestParseChecksum(t *testing.T) {
+	sum := md5.Sum([]byte("data"))
+	for _, value := range []string{
+		"md5:" + hex.EncodeToString(sum[:]),
+		"MD5:" + base64.StdEncoding.EncodeToString(sum[:]),
+	} {
+		c, err := ParseChecksum(value)
+		if err != nil || c.Algorithm != "md5" || string(c.Sum) != string(sum[:]) {
+			t.Errorf("ParseChecksum(%q) = %v, %v", value, c, err)
+		}
+	}
+
+	for _, value := range []string{"abc", "blake3:00", "sha256:abcd", "crc32c:zz"} {
+		if _, err := ParseChecksum(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestServerChecksums(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Amz-Checksum-Crc32c", "yZRlqg==")
+	header.Set("X-Amz-Checksum-Sha256", "abc-3") // composite checksum of a multipart upload
+	header.Add("X-Goog-Hash", "crc32c=yZRlqg==,md5=jXd/OF09/siBXSD3SWAm3A==")
+
+	checksums := serverChecksums(header)
+	if len(checksums) != 3 {
+		t.Fatalf("Expected 3 checksums, got %v", checksums)
+	}
+	if checksums[0].Algorithm != "crc32c" || checksums[2].Algorithm != "md5" || checksums[2].Origin != "x-goog-hash" {
+		t.Errorf("Unexpected checksums %v", checksums)
+	}
+}
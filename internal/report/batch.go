@@ -0,0 +1,243 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/batch"
+)
+
+type JSONBatchReport struct {
+	Manifest    string            `json:"manifest"`
+	Sources     []JSONBatchSource `json:"sources"`
+	Passed      int               `json:"passed"`
+	Failed      int               `json:"failed"`
+	GeneratedAt string            `json:"generated_at"`
+}
+
+// JSONBatchSource is the outcome of a source of a batch run, with the
+// failed checks of its rules and quality gate.
+type JSONBatchSource struct {
+	Name         string            `json:"name"`
+	Source       string            `json:"source"`
+	Status       string            `json:"status"`
+	Error        string            `json:"error,omitempty"`
+	RowCount     int               `json:"row_count,omitempty"`
+	ColumnCount  int               `json:"column_count,omitempty"`
+	QualityScore int               `json:"quality_score,omitempty"`
+	Rules        string            `json:"rules,omitempty"`
+	RuleChecks   int               `json:"rule_checks,omitempty"`
+	GateChecks   int               `json:"gate_checks,omitempty"`
+	Failures     []JSONBatchFailed `json:"failures,omitempty"`
+	Outputs      []string          `json:"outputs,omitempty"`
+	Elapsed      float64           `json:"processing_time_seconds"`
+}
+
+// JSONBatchFailed is a failed check of the rules or the quality gate.
+type JSONBatchFailed struct {
+	Name    string `json:"name"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// batchStatus is passed, failed, when rules or the quality gate failed, or
+// error, when the source could not be profiled or its reports written.
+func batchStatus(result batch.Result) string {
+	switch {
+	case result.Err != nil:
+		return "error"
+	case !result.Passed():
+		return "failed"
+	}
+	return "passed"
+}
+
+// batchFailures are the failed checks of the rules and quality gate.
+func batchFailures(result batch.Result) []JSONBatchFailed {
+	failures := make([]JSONBatchFailed, 0)
+	if result.Validation != nil {
+		for _, check := range result.Validation.Failures() {
+			failures = append(failures, JSONBatchFailed{Name: check.Name, Column: check.Column, Message: check.Message})
+		}
+	}
+	for _, check := range result.Gate {
+		if !check.Passed {
+			failures = append(failures, JSONBatchFailed{Name: check.Name, Column: check.Column, Message: check.Message})
+		}
+	}
+	return failures
+}
+
+// formatBatchChecks shows the passed checks of the rules or quality gate
+// out of all, or - when there are none.
+func formatBatchChecks(total, failed int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d", total-failed, total)
+}
+
+func formatBatchGate(result batch.Result) string {
+	failed := 0
+	for _, check := range result.Gate {
+		if !check.Passed {
+			failed++
+		}
+	}
+	return formatBatchChecks(len(result.Gate), failed)
+}
+
+func batchCounts(results []batch.Result) (passed, failed int) {
+	for _, result := range results {
+		if result.Passed() {
+			passed++
+		} else {
+			failed++
+		}
+	}
+	return passed, failed
+}
+
+// PrintBatchSummary prints one line per source of a batch run with its rows,
+// quality score, passed rule and gate checks, and status, followed by the
+// failures of each source that did not pass.
+func PrintBatchSummary(results []batch.Result, elapsed time.Duration) {
+	passed, failed := batchCounts(results)
+	fmt.Printf("📦 %d sources in %.2f seconds: %d passed", len(results), elapsed.Seconds(), passed)
+	if failed > 0 {
+		fmt.Printf(", %d failed", failed)
+	}
+	fmt.Println()
+	fmt.Printf("   %-32s %12s %6s %8s %8s %8s  %s\n", "SOURCE", "ROWS", "SCORE", "RULES", "GATE", "TIME", "STATUS")
+	fmt.Printf("   %s\n", strings.Repeat("─", 92))
+
+	for _, result := range results {
+		name := truncateLeft(result.Source.Name, 32)
+		if result.Err != nil && result.Profile == nil {
+			fmt.Printf("   %-32s %12s %6s %8s %8s %7.2fs  %s\n", name, "-", "-", "-", "-", result.Elapsed.Seconds(), errorStyle.Sprint("error"))
+			continue
+		}
+
+		rules := "-"
+		if result.Validation != nil {
+			rules = formatBatchChecks(len(result.Validation.Checks), len(result.Validation.Failures()))
+		}
+		status := successStyle.Sprint("passed")
+		switch batchStatus(result) {
+		case "error":
+			status = errorStyle.Sprint("error")
+		case "failed":
+			status = errorStyle.Sprint("failed")
+		}
+		fmt.Printf("   %-32s %12s %6d %8s %8s %7.2fs  %s\n", name, formatNumber(result.Profile.RowCount), result.Profile.QualityScore,
+			rules, formatBatchGate(result), result.Elapsed.Seconds(), status)
+	}
+	fmt.Println()
+
+	for _, result := range results {
+		if result.Passed() {
+			continue
+		}
+		fmt.Printf("❌ %s:\n", result.Source.Name)
+		if result.Err != nil {
+			errorStyle.Printf("   • %v\n", result.Err)
+		}
+		for _, failure := range batchFailures(result) {
+			if failure.Column != "" {
+				fmt.Printf("   ✗ %s '%s': %s\n", failure.Name, failure.Column, failure.Message)
+			} else {
+				fmt.Printf("   ✗ %s: %s\n", failure.Name, failure.Message)
+			}
+		}
+		fmt.Println()
+	}
+}
+
+// GenerateBatchJSONReport writes the outcome of every source of a batch run
+// from manifest into a single JSON report.
+func GenerateBatchJSONReport(manifest string, results []batch.Result, outputPath string) error {
+	report := JSONBatchReport{
+		Manifest:    manifest,
+		Sources:     make([]JSONBatchSource, 0, len(results)),
+		GeneratedAt: time.Now().Format(time.RFC3339),
+	}
+	report.Passed, report.Failed = batchCounts(results)
+
+	for _, result := range results {
+		entry := JSONBatchSource{
+			Name:     result.Source.Name,
+			Source:   result.Source.Source,
+			Status:   batchStatus(result),
+			Rules:    result.Source.Rules,
+			Failures: batchFailures(result),
+			Outputs:  result.Outputs,
+			Elapsed:  result.Elapsed.Seconds(),
+		}
+		if result.Err != nil {
+			entry.Error = result.Err.Error()
+		}
+		if profile := result.Profile; profile != nil {
+			entry.RowCount, entry.ColumnCount, entry.QualityScore = profile.RowCount, profile.ColumnCount, profile.QualityScore
+		}
+		if result.Validation != nil {
+			entry.RuleChecks = len(result.Validation.Checks)
+		}
+		entry.GateChecks = len(result.Gate)
+		report.Sources = append(report.Sources, entry)
+	}
+	return writeJSON(report, outputPath)
+}
+
+// GenerateBatchMarkdownReport writes a summary table of the sources of a
+// batch run from manifest, followed by the failures of those that did not
+// pass.
+func GenerateBatchMarkdownReport(manifest string, results []batch.Result, outputPath string) error {
+	var content strings.Builder
+
+	passed, failed := batchCounts(results)
+	content.WriteString(fmt.Sprintf("# DataSleuth Batch: %s\n\n", manifest))
+	content.WriteString(fmt.Sprintf("%d sources: %d passed, %d failed.\n\n", len(results), passed, failed))
+	content.WriteString("| Source | Rows | Quality Score | Rules | Gate | Status | Reports |\n")
+	content.WriteString("|--------|------|---------------|-------|------|--------|---------|\n")
+	for _, result := range results {
+		rows, score, rules := "-", "-", "-"
+		if profile := result.Profile; profile != nil {
+			rows, score = formatNumber(profile.RowCount), fmt.Sprintf("%d/100", profile.QualityScore)
+		}
+		if result.Validation != nil {
+			rules = formatBatchChecks(len(result.Validation.Checks), len(result.Validation.Failures()))
+		}
+		content.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n", escapeTableCell(result.Source.Name), rows, score, rules,
+			formatBatchGate(result), batchStatus(result), escapeTableCell(strings.Join(result.Outputs, ", "))))
+	}
+	content.WriteString("\n")
+
+	for _, result := range results {
+		if result.Passed() {
+			continue
+		}
+		content.WriteString(fmt.Sprintf("## %s\n\n", result.Source.Name))
+		if result.Err != nil {
+			content.WriteString(fmt.Sprintf("- Error: %s\n", result.Err))
+		}
+		for _, failure := range batchFailures(result) {
+			if failure.Column != "" {
+				content.WriteString(fmt.Sprintf("- %s `%s`: %s\n", failure.Name, failure.Column, failure.Message))
+			} else {
+				content.WriteString(fmt.Sprintf("- %s: %s\n", failure.Name, failure.Message))
+			}
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("---\n")
+	content.WriteString("Generated by DataSleuth v0.1.0 - Fast dataset profiling and validation from the command line\n")
+
+	if err := os.WriteFile(outputPath, []byte(content.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write Markdown report to file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/batch"
+	"github.com/kamalm96/datasleuth/internal/validate"
+)
+
+func createTestBatch() []batch.Result {
+	return []batch.Result{
+		{Source: batch.Source{Name: "orders", Source: "data/orders.csv"}, Profile: createTestProfile(), Outputs: []string{"reports/orders.json"}},
+		{
+			Source:  batch.Source{Name: "users", Source: "data/users.csv", Rules: "rules/users.yaml"},
+			Profile: createTestProfile(),
+			Validation: &validate.Result{Checks: []validate.Check{
+				{Name: "type", Column: "age", Passed: true},
+				{Name: "range", Column: "age", Message: "max 130, expected at most 120"},
+			}},
+			Gate: []validate.Check{{Name: "quality_score", Passed: true}},
+		},
+		{Source: batch.Source{Name: "broken", Source: "data/broken.csv"}, Err: errors.New("parse error on line 2")},
+	}
+}
+
+func TestGenerateBatchJSONReport(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "batch.json")
+	if err := GenerateBatchJSONReport("manifest.yaml", createTestBatch(), outputPath); err != nil {
+		t.Fatalf("GenerateBatchJSONReport failed: %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	var report JSONBatchReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		t.Fatalf("Failed to parse report: %v", err)
+	}
+
+	if report.Manifest != "manifest.yaml" || report.Passed != 1 || report.Failed != 2 || len(report.Sources) != 3 {
+		t.Fatalf("Expected 1 of 3 sources to pass, got %+v", report)
+	}
+	orders, users, broken := report.Sources[0], report.Sources[1], report.Sources[2]
+	if orders.Status != "passed" || orders.RowCount == 0 || len(orders.Outputs) != 1 {
+		t.Errorf("Unexpected orders entry %+v", orders)
+	}
+	if users.Status != "failed" || users.RuleChecks != 2 || users.GateChecks != 1 || len(users.Failures) != 1 || users.Failures[0].Column != "age" {
+		t.Errorf("Expected users to fail its range rule, got %+v", users)
+	}
+	if broken.Status != "error" || broken.Error != "parse error on line 2" || broken.RowCount != 0 {
+		t.Errorf("Expected the error of broken, got %+v", broken)
+	}
+}
+
+func TestGenerateBatchMarkdownReport(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "batch.md")
+	if err := GenerateBatchMarkdownReport("manifest.yaml", createTestBatch(), outputPath); err != nil {
+		t.Fatalf("GenerateBatchMarkdownReport failed: %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	for _, expected := range []string{
+		"3 sources: 1 passed, 2 failed.",
+		"| orders | 1,000 | 85/100 | - | - | passed | reports/orders.json |",
+		"| users | 1,000 | 85/100 | 1/2 | 1/1 | failed |  |",
+		"- range `age`: max 130, expected at most 120",
+		"- Error: parse error on line 2",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", expected, content)
+		}
+	}
+}
@@ -0,0 +1,573 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// Layout of the charts of the HTML report, in SVG units. Charts scale to the
+// width of their column card, and histograms and box plots share the plot
+// area so that the two read together.
+const (
+	chartWidth                     = 480.0
+	plotLeft, plotRight            = 56.0, 470.0
+	histogramHeight                = 210.0
+	histogramTop, histogramBottom  = 10.0, 180.0 // the x axis, with its labels below
+	boxPlotHeight                  = 70.0
+	boxPlotAxis                    = 54.0
+	valueRowHeight                 = 22.0
+	valueBarLeft, valueBarWidth    = 140.0, 260.0
+	heatmapCellSize                = 28.0
+	heatmapValues                  = 15        // most columns a heatmap prints the values of in its cells
+	chartLabelRunes                = 18        // longest value or column name printed in full
+	chartCharWidth, chartLabelGap  = 6.0, 12.0 // width of a character of a label, and room between two
+	chartGridLines, boxPlotTickGap = 4, 5      // lines across a histogram, and ticks under a box plot
+)
+
+// barChart is a histogram or timeline: a bar for each bucket over equal
+// slots of the x axis, labelled with the bounds of the buckets, against a y
+// axis of counts.
+type barChart struct {
+	Width, Height            float64
+	Left, Right, Top, Bottom float64 // the plot area
+	Bars                     []chartBar
+	XTicks                   []chartTick
+	YTicks                   []chartTick
+}
+
+type chartBar struct {
+	X, Y, Width, Height float64
+	Tip                 string
+}
+
+// chartTick is a labelled point of an axis: a grid line or tick mark, and
+// its label anchored at start, middle or end.
+type chartTick struct {
+	X1, Y1, X2, Y2 float64
+	TextX, TextY   float64
+	Anchor         string
+	Label          string
+}
+
+// boxPlot draws the quartiles of a numeric column over its range: a box from
+// the 25th to the 75th percentile split at the median, whiskers out to 1.5
+// times the interquartile range or the min and max if closer, the min and
+// max beyond them, and the mean.
+type boxPlot struct {
+	Width, Height   float64
+	Left, Right     float64
+	Axis            float64
+	BoxX, BoxWidth  float64
+	BoxEnd          float64
+	MedianX, MeanX  float64
+	LowX, HighX     float64 // ends of the whiskers
+	BoxTip, MeanTip string
+	LowTip, HighTip string
+	Points          []chartPoint
+	Ticks           []chartTick
+}
+
+type chartPoint struct {
+	X   float64
+	Tip string
+}
+
+// valueChart is a horizontal bar for each of the most frequent values of a
+// column, with its count and share of the values.
+type valueChart struct {
+	Width, Height float64
+	Rows          []valueRow
+}
+
+type valueRow struct {
+	Label         string // the value, shortened
+	LabelX, TextY float64
+	BarX, BarY    float64
+	Width         float64
+	CountX        float64
+	Count         string
+	Tip           string
+}
+
+// heatmap colors the correlations of every pair of columns, blue for
+// positive and red for negative, darker the stronger.
+type heatmap struct {
+	Title   string
+	Width   float64
+	Height  float64
+	Cell    float64
+	Columns []heatmapLabel
+	Cells   []heatmapCell
+}
+
+// heatmapLabel is the name of a column, at the start of its row and the top
+// of its column.
+type heatmapLabel struct {
+	Name      string
+	Short     string
+	X, Y      float64 // end of the row label
+	Transform string  // places the column label
+}
+
+type heatmapCell struct {
+	X, Y      float64
+	Fill      string
+	Value     string // printed in the cell, empty when the cells are too small
+	TextX     float64
+	TextY     float64
+	DarkLabel bool // the cell is dark enough to print its value in white
+	Tip       string
+}
+
+// histogramChart charts the histogram of a numeric column, or returns nil
+// when it has none.
+func histogramChart(col *profiler.ColumnProfile) *barChart {
+	buckets := col.HistogramBuckets
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	counts := make([]int, len(buckets))
+	ranges := make([]string, len(buckets))
+	edges := make([]string, len(buckets)+1)
+	for i, bucket := range buckets {
+		counts[i] = bucket.Count
+		ranges[i] = fmt.Sprintf("%s - %s", formatNumberHTML(bucket.LowerBound), formatNumberHTML(bucket.UpperBound))
+		edges[i] = formatAxisValue(bucket.LowerBound)
+	}
+	edges[len(buckets)] = formatAxisValue(buckets[len(buckets)-1].UpperBound)
+	return newBarChart(counts, ranges, edges)
+}
+
+// timelineChart charts the timestamps of a datetime column over time, or
+// returns nil when it has no histogram.
+func timelineChart(d *profiler.DateTimeStats) *barChart {
+	if d == nil || len(d.Histogram) == 0 {
+		return nil
+	}
+
+	counts := make([]int, len(d.Histogram))
+	ranges := make([]string, len(d.Histogram))
+	edges := make([]string, len(d.Histogram)+1)
+	for i, bucket := range d.Histogram {
+		counts[i] = bucket.Count
+		ranges[i] = fmt.Sprintf("%s - %s", d.Format(bucket.Start), d.Format(bucket.End))
+		edges[i] = d.Format(bucket.Start)
+	}
+	edges[len(d.Histogram)] = d.Format(d.Histogram[len(d.Histogram)-1].End)
+	return newBarChart(counts, ranges, edges)
+}
+
+// newBarChart lays out a bar of each count, with the range of its bucket and
+// its count in the tooltip, labelling as many of the edges between buckets
+// as fit under the x axis.
+func newBarChart(counts []int, ranges, edges []string) *barChart {
+	total, highest := 0, 0
+	for _, count := range counts {
+		total += count
+		highest = max(highest, count)
+	}
+	top, yTicks := countTicks(highest)
+
+	chart := &barChart{
+		Width:  chartWidth,
+		Height: histogramHeight,
+		Left:   plotLeft,
+		Right:  plotRight,
+		Top:    histogramTop,
+		Bottom: histogramBottom,
+	}
+	plotHeight := histogramBottom - histogramTop
+	slot := (plotRight - plotLeft) / float64(len(counts))
+	for i, count := range counts {
+		height := plotHeight * float64(count) / float64(top)
+		if count > 0 {
+			height = math.Max(height, 1)
+		}
+		chart.Bars = append(chart.Bars, chartBar{
+			X:      round1(plotLeft + slot*float64(i) + 1),
+			Y:      round1(histogramBottom - height),
+			Width:  round1(math.Max(slot-2, 1)),
+			Height: round1(height),
+			Tip:    fmt.Sprintf("%s: %s (%s)", ranges[i], formatNumber(count), formatPercentHTML(divideFloat(count, total))),
+		})
+	}
+
+	for _, count := range yTicks {
+		y := round1(histogramBottom - plotHeight*float64(count)/float64(top))
+		chart.YTicks = append(chart.YTicks, chartTick{
+			X1: plotLeft, Y1: y, X2: plotRight, Y2: y,
+			TextX: plotLeft - 6, TextY: y + 4,
+			Anchor: "end",
+			Label:  formatAxisValue(float64(count)),
+		})
+	}
+
+	for _, i := range edgeLabels(edges, slot) {
+		x := round1(plotLeft + slot*float64(i))
+		anchor := "middle"
+		switch i {
+		case 0:
+			anchor = "start"
+		case len(edges) - 1:
+			anchor = "end"
+		}
+		chart.XTicks = append(chart.XTicks, chartTick{
+			X1: x, Y1: histogramBottom, X2: x, Y2: histogramBottom + 4,
+			TextX: x, TextY: histogramBottom + 16,
+			Anchor: anchor,
+			Label:  edges[i],
+		})
+	}
+	return chart
+}
+
+// edgeLabels picks the edges between buckets slot apart that can be
+// labelled without their labels running into each other: evenly spaced from
+// the first, always with the last. The first and last labels start and end
+// at their edge rather than centering on it, so labels are spaced a width
+// and a half apart.
+func edgeLabels(edges []string, slot float64) []int {
+	widest := 0
+	for _, edge := range edges {
+		widest = max(widest, len([]rune(edge)))
+	}
+	width := 1.5 * (float64(widest)*chartCharWidth + chartLabelGap)
+	every := max(int(math.Ceil(width/slot)), 1)
+
+	last := len(edges) - 1
+	var picked []int
+	for i := 0; i < last; i += every {
+		picked = append(picked, i)
+	}
+	if len(picked) > 1 && float64(last-picked[len(picked)-1])*slot < width {
+		picked = picked[:len(picked)-1]
+	}
+	return append(picked, last)
+}
+
+// countTicks returns the count at the top of a y axis up to highest, and the
+// counts of its grid lines from 0 up to it.
+func countTicks(highest int) (int, []int) {
+	step := max(int(math.Round(niceStep(float64(highest)/chartGridLines))), 1)
+	top := max((highest+step-1)/step*step, step)
+	var ticks []int
+	for count := 0; count <= top; count += step {
+		ticks = append(ticks, count)
+	}
+	return top, ticks
+}
+
+// niceStep rounds x up to 1, 2 or 5 times a power of ten, for the steps of
+// an axis.
+func niceStep(x float64) float64 {
+	if x <= 0 || math.IsNaN(x) || math.IsInf(x, 0) {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(x)))
+	switch fraction := x / magnitude; {
+	case fraction <= 1:
+		return magnitude
+	case fraction <= 2:
+		return 2 * magnitude
+	case fraction <= 5:
+		return 5 * magnitude
+	}
+	return 10 * magnitude
+}
+
+// newBoxPlot lays out the box plot of a numeric column, or returns nil when
+// its range or quartiles are unknown or it holds a single value.
+func newBoxPlot(col *profiler.ColumnProfile) *boxPlot {
+	low, okLow := col.Min.(float64)
+	high, okHigh := col.Max.(float64)
+	q1, ok1 := col.Percentile(25)
+	q3, ok3 := col.Percentile(75)
+	if !col.IsNumeric || !okLow || !okHigh || !ok1 || !ok3 || !(high > low) {
+		return nil
+	}
+	x := func(v float64) float64 {
+		v = math.Min(math.Max(v, low), high)
+		return round1(plotLeft + (v-low)/(high-low)*(plotRight-plotLeft))
+	}
+
+	iqr := q3 - q1
+	lowWhisker := math.Max(q1-1.5*iqr, low)
+	highWhisker := math.Min(q3+1.5*iqr, high)
+	plot := &boxPlot{
+		Width:    chartWidth,
+		Height:   boxPlotHeight,
+		Left:     plotLeft,
+		Right:    plotRight,
+		Axis:     boxPlotAxis,
+		BoxX:     x(q1),
+		BoxWidth: round1(math.Max(x(q3)-x(q1), 1)),
+		BoxEnd:   x(q3),
+		MedianX:  x(col.Median),
+		MeanX:    x(col.Mean),
+		LowX:     x(lowWhisker),
+		HighX:    x(highWhisker),
+		BoxTip: fmt.Sprintf("P25 %s, median %s, P75 %s (IQR %s)",
+			formatNumberHTML(q1), formatNumberHTML(col.Median), formatNumberHTML(q3), formatNumberHTML(iqr)),
+		MeanTip: "Mean " + formatNumberHTML(col.Mean),
+		LowTip:  whiskerTip(lowWhisker, low, "min", "P25 - 1.5 × IQR"),
+		HighTip: whiskerTip(highWhisker, high, "max", "P75 + 1.5 × IQR"),
+	}
+	if low < lowWhisker {
+		plot.Points = append(plot.Points, chartPoint{X: x(low), Tip: "Min " + formatNumberHTML(low)})
+	}
+	if high > highWhisker {
+		plot.Points = append(plot.Points, chartPoint{X: x(high), Tip: "Max " + formatNumberHTML(high)})
+	}
+
+	step := niceStep((high - low) / boxPlotTickGap)
+	for v := math.Ceil(low/step) * step; v <= high+step/1e6; v += step {
+		tick := x(v)
+		plot.Ticks = append(plot.Ticks, chartTick{
+			X1: tick, Y1: boxPlotAxis, X2: tick, Y2: boxPlotAxis + 4,
+			TextX: tick, TextY: boxPlotAxis + 15,
+			Anchor: "middle",
+			Label:  formatAxisValue(v),
+		})
+	}
+	return plot
+}
+
+// whiskerTip describes the end of a whisker at v: the extreme of the column
+// when the whisker reaches it, or the fence it stops at.
+func whiskerTip(v, extreme float64, extremeName, fence string) string {
+	if v == extreme {
+		return fmt.Sprintf("Whisker to the %s, %s", extremeName, formatNumberHTML(v))
+	}
+	return fmt.Sprintf("Whisker to %s, %s", fence, formatNumberHTML(v))
+}
+
+// newValueChart lays out a bar for each of values, out of the count values
+// of their column, or returns nil when there are none.
+func newValueChart(values []profiler.ValueCount, count int) *valueChart {
+	if len(values) == 0 {
+		return nil
+	}
+	highest := 0
+	for _, v := range values {
+		highest = max(highest, v.Count)
+	}
+
+	chart := &valueChart{Width: chartWidth, Height: float64(len(values))*valueRowHeight + 4}
+	for i, v := range values {
+		width := 0.0
+		if highest > 0 {
+			width = round1(math.Max(valueBarWidth*float64(v.Count)/float64(highest), 1))
+		}
+		share := formatPercentHTML(divideFloat(v.Count, count))
+		middle := float64(i)*valueRowHeight + valueRowHeight/2 + 2
+		chart.Rows = append(chart.Rows, valueRow{
+			Label:  shortLabel(v.Value),
+			LabelX: valueBarLeft - 6,
+			TextY:  round1(middle + 4),
+			BarX:   valueBarLeft,
+			BarY:   round1(middle - 7),
+			Width:  width,
+			CountX: round1(valueBarLeft + width + 6),
+			Count:  fmt.Sprintf("%s (%s)", formatNumber(v.Count), share),
+			Tip:    fmt.Sprintf("%s: %s (%s)", v.Value, formatNumber(v.Count), share),
+		})
+	}
+	return chart
+}
+
+// correlationHeatmaps colors the Pearson correlations of the numeric
+// columns, with their rank correlations in the tooltips, and the Cramér's V
+// associations of the categorical ones.
+func correlationHeatmaps(matrix *profiler.CorrelationMatrix) []heatmap {
+	if matrix == nil {
+		return nil
+	}
+	var heatmaps []heatmap
+	if len(matrix.Columns) > 1 {
+		heatmaps = append(heatmaps, newHeatmap("Pearson correlation", matrix.Columns, func(a, b string) (float64, string, bool) {
+			r, ok := pairValue(matrix.Values, a, b)
+			tip := fmt.Sprintf("r = %.3f", r)
+			if rho, ok := pairValue(matrix.Spearman, a, b); ok && a != b {
+				tip += fmt.Sprintf(", Spearman %.3f", rho)
+			}
+			return r, tip, ok
+		}))
+	}
+	if len(matrix.Categorical) > 1 {
+		heatmaps = append(heatmaps, newHeatmap("Cramér's V", matrix.Categorical, func(a, b string) (float64, string, bool) {
+			v, ok := pairValue(matrix.CramersV, a, b)
+			return v, fmt.Sprintf("Cramér's V = %.3f", v), ok
+		}))
+	}
+	return heatmaps
+}
+
+// newHeatmap lays out a cell of every pair of columns, colored by the value
+// of the pair and described by its tip.
+func newHeatmap(title string, columns []string, value func(a, b string) (float64, string, bool)) heatmap {
+	// Column names go left of the rows and, slanted, above the columns
+	longest := 0
+	for _, name := range columns {
+		longest = max(longest, len([]rune(shortLabel(name))))
+	}
+	labels := float64(longest) * chartCharWidth
+	heatmapMargin := round1(labels + chartLabelGap)
+	cells := float64(len(columns)) * heatmapCellSize
+	h := heatmap{
+		Title:  title,
+		Width:  round1(heatmapMargin + cells + labels*math.Sqrt2/2),
+		Height: heatmapMargin + cells,
+		Cell:   heatmapCellSize - 1,
+	}
+	for i, name := range columns {
+		center := heatmapMargin + (float64(i)+0.5)*heatmapCellSize
+		h.Columns = append(h.Columns, heatmapLabel{
+			Name:      name,
+			Short:     shortLabel(name),
+			X:         heatmapMargin - 6,
+			Y:         round1(center + 4),
+			Transform: fmt.Sprintf("translate(%.1f %.1f) rotate(-45)", center, heatmapMargin-6),
+		})
+	}
+
+	for i, a := range columns {
+		for j, b := range columns {
+			v, tip, ok := value(a, b)
+			cell := heatmapCell{
+				X:     heatmapMargin + float64(j)*heatmapCellSize,
+				Y:     heatmapMargin + float64(i)*heatmapCellSize,
+				Fill:  "#eeeeee",
+				TextX: round1(heatmapMargin + (float64(j)+0.5)*heatmapCellSize),
+				TextY: round1(heatmapMargin + (float64(i)+0.5)*heatmapCellSize + 3),
+				Tip:   fmt.Sprintf("%s & %s: not computed", a, b),
+			}
+			if ok && !math.IsNaN(v) {
+				cell.Fill = heatmapFill(v)
+				cell.DarkLabel = math.Abs(v) > 0.6
+				cell.Tip = fmt.Sprintf("%s & %s: %s", a, b, tip)
+				if len(columns) <= heatmapValues {
+					cell.Value = fmt.Sprintf("%.2f", v)
+				}
+			}
+			h.Cells = append(h.Cells, cell)
+		}
+	}
+	return h
+}
+
+// pairValue looks up the value of a pair of columns whichever way round it
+// was stored. A column is perfectly correlated with itself.
+func pairValue(values map[string]map[string]float64, a, b string) (float64, bool) {
+	if a == b {
+		return 1, true
+	}
+	if v, ok := values[a][b]; ok {
+		return v, true
+	}
+	v, ok := values[b][a]
+	return v, ok
+}
+
+// heatmapFill blends white into the primary color for positive values and
+// the error color for negative ones, in proportion to their size.
+func heatmapFill(v float64) string {
+	r, g, b := 26.0, 115.0, 232.0
+	if v < 0 {
+		r, g, b = 217, 48, 37
+	}
+	t := math.Min(math.Abs(v), 1)
+	blend := func(c float64) int {
+		return int(math.Round(255 + (c-255)*t))
+	}
+	return fmt.Sprintf("rgb(%d, %d, %d)", blend(r), blend(g), blend(b))
+}
+
+// formatAxisValue writes the value of an axis label briefly: large values
+// in thousands, millions or billions, others to 4 significant digits.
+func formatAxisValue(v float64) string {
+	scaled := func(divisor float64, suffix string) string {
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", v/divisor), ".0") + suffix
+	}
+	switch a := math.Abs(v); {
+	case a >= 1e9:
+		return scaled(1e9, "B")
+	case a >= 1e6:
+		return scaled(1e6, "M")
+	case a >= 1e4:
+		return scaled(1e3, "k")
+	case a < 1e-9:
+		return "0"
+	}
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
+
+// shortLabel shortens a value or column name printed in a chart, which its
+// tooltip gives in full.
+func shortLabel(s string) string {
+	runes := []rune(s)
+	if len(runes) <= chartLabelRunes {
+		return s
+	}
+	return string(runes[:chartLabelRunes-1]) + "…"
+}
+
+// round1 rounds a coordinate to a tenth of a unit, which keeps the SVG
+// short.
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+// chartTemplates draw the charts laid out above as inline SVG. Every bar,
+// box and cell has its tooltip as a title, which the report shows as soon
+// as the pointer is over it.
+const chartTemplates = `
+{{define "barChart"}}
+<svg class="chart" viewBox="0 0 {{.Width}} {{.Height}}" width="100%" role="img">
+    {{range .YTicks}}<line class="grid" x1="{{.X1}}" y1="{{.Y1}}" x2="{{.X2}}" y2="{{.Y2}}"/><text x="{{.TextX}}" y="{{.TextY}}" text-anchor="{{.Anchor}}">{{.Label}}</text>{{end}}
+    {{range .Bars}}<rect class="bar" x="{{.X}}" y="{{.Y}}" width="{{.Width}}" height="{{.Height}}"><title>{{.Tip}}</title></rect>{{end}}
+    <line class="axis" x1="{{.Left}}" y1="{{.Bottom}}" x2="{{.Right}}" y2="{{.Bottom}}"/>
+    <line class="axis" x1="{{.Left}}" y1="{{.Top}}" x2="{{.Left}}" y2="{{.Bottom}}"/>
+    {{range .XTicks}}<line class="axis" x1="{{.X1}}" y1="{{.Y1}}" x2="{{.X2}}" y2="{{.Y2}}"/><text x="{{.TextX}}" y="{{.TextY}}" text-anchor="{{.Anchor}}">{{.Label}}</text>{{end}}
+</svg>
+{{end}}
+
+{{define "boxPlot"}}
+<svg class="chart" viewBox="0 0 {{.Width}} {{.Height}}" width="100%" role="img">
+    <line class="whisker" x1="{{.LowX}}" y1="29" x2="{{.BoxX}}" y2="29"><title>{{.LowTip}}</title></line>
+    <line class="whisker" x1="{{.LowX}}" y1="19" x2="{{.LowX}}" y2="39"><title>{{.LowTip}}</title></line>
+    <line class="whisker" x1="{{.BoxEnd}}" y1="29" x2="{{.HighX}}" y2="29"><title>{{.HighTip}}</title></line>
+    <line class="whisker" x1="{{.HighX}}" y1="19" x2="{{.HighX}}" y2="39"><title>{{.HighTip}}</title></line>
+    <rect class="box" x="{{.BoxX}}" y="14" width="{{.BoxWidth}}" height="30"><title>{{.BoxTip}}</title></rect>
+    <line class="median" x1="{{.MedianX}}" y1="14" x2="{{.MedianX}}" y2="44"><title>{{.BoxTip}}</title></line>
+    <circle class="mean" cx="{{.MeanX}}" cy="29" r="4"><title>{{.MeanTip}}</title></circle>
+    {{range .Points}}<circle class="point" cx="{{.X}}" cy="29" r="3"><title>{{.Tip}}</title></circle>{{end}}
+    <line class="axis" x1="{{.Left}}" y1="{{.Axis}}" x2="{{.Right}}" y2="{{.Axis}}"/>
+    {{range .Ticks}}<line class="axis" x1="{{.X1}}" y1="{{.Y1}}" x2="{{.X2}}" y2="{{.Y2}}"/><text x="{{.TextX}}" y="{{.TextY}}" text-anchor="{{.Anchor}}">{{.Label}}</text>{{end}}
+</svg>
+{{end}}
+
+{{define "valueChart"}}
+<svg class="chart" viewBox="0 0 {{.Width}} {{.Height}}" width="100%" role="img">
+    {{range .Rows}}<g><title>{{.Tip}}</title>
+        <text x="{{.LabelX}}" y="{{.TextY}}" text-anchor="end">{{.Label}}</text>
+        <rect class="bar" x="{{.BarX}}" y="{{.BarY}}" width="{{.Width}}" height="14"/>
+        <text x="{{.CountX}}" y="{{.TextY}}">{{.Count}}</text>
+    </g>{{end}}
+</svg>
+{{end}}
+
+{{define "heatmap"}}
+<svg class="chart heatmap" viewBox="0 0 {{.Width}} {{.Height}}" width="{{.Width}}" role="img" aria-label="{{.Title}}">
+    {{range .Columns}}
+    <text x="{{.X}}" y="{{.Y}}" text-anchor="end"><title>{{.Name}}</title>{{.Short}}</text>
+    <text transform="{{.Transform}}"><title>{{.Name}}</title>{{.Short}}</text>
+    {{end}}
+    {{range .Cells}}<g><title>{{.Tip}}</title><rect x="{{.X}}" y="{{.Y}}" width="{{$.Cell}}" height="{{$.Cell}}" fill="{{.Fill}}"/>{{if .Value}}<text class="{{if .DarkLabel}}cell-dark{{else}}cell{{end}}" x="{{.TextX}}" y="{{.TextY}}" text-anchor="middle">{{.Value}}</text>{{end}}</g>{{end}}
+</svg>
+{{end}}
+`
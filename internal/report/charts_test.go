@@ -0,0 +1,203 @@
+package report
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func TestHistogramChart(t *testing.T) {
+	col := &profiler.ColumnProfile{IsNumeric: true}
+	if histogramChart(col) != nil {
+		t.Errorf("Expected no chart without a histogram")
+	}
+
+	for i := 0; i < 40; i++ {
+		col.HistogramBuckets = append(col.HistogramBuckets, profiler.HistogramBucket{
+			LowerBound: float64(i) * 25000,
+			UpperBound: float64(i+1) * 25000,
+			Count:      i * 3,
+		})
+	}
+	chart := histogramChart(col)
+	if len(chart.Bars) != 40 {
+		t.Fatalf("Expected a bar per bucket, got %d", len(chart.Bars))
+	}
+	if bar := chart.Bars[0]; bar.Height != 0 || bar.Tip != "0 - 25000: 0 (0.00%)" {
+		t.Errorf("Expected an empty first bar, got %+v", bar)
+	}
+	if bar := chart.Bars[39]; bar.Y < chart.Top || bar.Y+bar.Height != chart.Bottom {
+		t.Errorf("Expected the tallest bar within the plot, got %+v", bar)
+	}
+
+	var counts []string
+	for _, tick := range chart.YTicks {
+		counts = append(counts, tick.Label)
+	}
+	if want := []string{"0", "50", "100", "150"}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("Expected count ticks %v, got %v", want, counts)
+	}
+
+	first, last := chart.XTicks[0], chart.XTicks[len(chart.XTicks)-1]
+	if first.Label != "0" || first.Anchor != "start" || last.Label != "1M" || last.Anchor != "end" {
+		t.Errorf("Expected the axis to run from 0 to 1M, got %+v and %+v", first, last)
+	}
+	if len(chart.XTicks) >= 41 {
+		t.Errorf("Expected only some of the 41 edges labelled, got %d", len(chart.XTicks))
+	}
+}
+
+func TestEdgeLabels(t *testing.T) {
+	edges := []string{"0", "10", "20", "30", "40", "50"}
+	if got := edgeLabels(edges, 100); !reflect.DeepEqual(got, []int{0, 1, 2, 3, 4, 5}) {
+		t.Errorf("Expected every edge labelled, got %v", got)
+	}
+	// Labels a width and a half apart, 33 units for 2 characters, take 4
+	// slots of 10; the last stands in for the one before it
+	if got := edgeLabels(append(edges, "60", "70", "80", "90"), 10); !reflect.DeepEqual(got, []int{0, 4, 9}) {
+		t.Errorf("Expected every fourth edge and the last, got %v", got)
+	}
+}
+
+func TestNewBoxPlot(t *testing.T) {
+	col := &profiler.ColumnProfile{
+		IsNumeric:   true,
+		Min:         float64(0),
+		Max:         float64(100),
+		Mean:        30,
+		Median:      25,
+		Percentiles: []profiler.Percentile{{Rank: 25, Value: 20}, {Rank: 75, Value: 40}},
+	}
+	plot := newBoxPlot(col)
+	if plot == nil {
+		t.Fatal("Expected a box plot")
+	}
+	// The plot runs from 0 at 56 to 100 at 470
+	if plot.BoxX != 138.8 || plot.BoxEnd != 221.6 || plot.MedianX != 159.5 {
+		t.Errorf("Expected the box from 20 to 40 split at 25, got %+v", plot)
+	}
+	if plot.LowX != 56 || plot.LowTip != "Whisker to the min, 0" {
+		t.Errorf("Expected the lower whisker to reach the min, got %v %q", plot.LowX, plot.LowTip)
+	}
+	if plot.HighX != 345.8 || plot.HighTip != "Whisker to P75 + 1.5 × IQR, 70" {
+		t.Errorf("Expected the upper whisker to stop at 70, got %v %q", plot.HighX, plot.HighTip)
+	}
+	if len(plot.Points) != 1 || plot.Points[0].X != 470 || plot.Points[0].Tip != "Max 100" {
+		t.Errorf("Expected the max beyond the whisker, got %+v", plot.Points)
+	}
+
+	var ticks []string
+	for _, tick := range plot.Ticks {
+		ticks = append(ticks, tick.Label)
+	}
+	if want := []string{"0", "20", "40", "60", "80", "100"}; !reflect.DeepEqual(ticks, want) {
+		t.Errorf("Expected ticks %v, got %v", want, ticks)
+	}
+
+	col.Max = float64(0)
+	if newBoxPlot(col) != nil {
+		t.Errorf("Expected no box plot of a single value")
+	}
+	col.Max, col.Percentiles = float64(100), nil
+	if newBoxPlot(col) != nil {
+		t.Errorf("Expected no box plot without quartiles")
+	}
+}
+
+func TestNewValueChart(t *testing.T) {
+	if newValueChart(nil, 0) != nil {
+		t.Errorf("Expected no chart without values")
+	}
+
+	chart := newValueChart([]profiler.ValueCount{
+		{Value: "a value longer than the label", Count: 50},
+		{Value: "b", Count: 25},
+	}, 100)
+	if len(chart.Rows) != 2 || chart.Height != 48 {
+		t.Fatalf("Expected two rows, got %+v", chart)
+	}
+	first, second := chart.Rows[0], chart.Rows[1]
+	if first.Label != "a value longer th…" || first.Tip != "a value longer than the label: 50 (50.00%)" {
+		t.Errorf("Expected a shortened label with the value in full in the tip, got %+v", first)
+	}
+	if first.Width != valueBarWidth || second.Width != valueBarWidth/2 || second.Count != "25 (25.00%)" {
+		t.Errorf("Expected bars in proportion to their counts, got %+v and %+v", first, second)
+	}
+}
+
+func TestCorrelationHeatmaps(t *testing.T) {
+	matrix := &profiler.CorrelationMatrix{
+		Columns:     []string{"a", "b", "c"},
+		Values:      map[string]map[string]float64{"a": {"b": 0.9}, "c": {"a": -0.5}},
+		Spearman:    map[string]map[string]float64{"a": {"b": 0.8}},
+		Categorical: []string{"region"},
+	}
+	heatmaps := correlationHeatmaps(matrix)
+	if len(heatmaps) != 1 || heatmaps[0].Title != "Pearson correlation" {
+		t.Fatalf("Expected a heatmap of the numeric columns alone, got %+v", heatmaps)
+	}
+
+	h := heatmaps[0]
+	if len(h.Columns) != 3 || len(h.Cells) != 9 {
+		t.Fatalf("Expected a cell of every pair of 3 columns, got %d", len(h.Cells))
+	}
+	tests := []struct {
+		cell  int
+		fill  string
+		value string
+		tip   string
+	}{
+		{0, "rgb(26, 115, 232)", "1.00", "a & a: r = 1.000"},
+		{1, "rgb(49, 129, 234)", "0.90", "a & b: r = 0.900, Spearman 0.800"},
+		{2, "rgb(236, 152, 146)", "-0.50", "a & c: r = -0.500"},
+		{5, "#eeeeee", "", "b & c: not computed"},
+	}
+	for _, tt := range tests {
+		cell := h.Cells[tt.cell]
+		if cell.Fill != tt.fill || cell.Value != tt.value || cell.Tip != tt.tip {
+			t.Errorf("Cell %d: expected %s %q %q, got %+v", tt.cell, tt.fill, tt.value, tt.tip, cell)
+		}
+	}
+	if !h.Cells[1].DarkLabel || h.Cells[2].DarkLabel {
+		t.Errorf("Expected white values on dark cells only")
+	}
+}
+
+func TestChartTemplatesEscape(t *testing.T) {
+	profile := createTestProfile()
+	profile.Columns["test_str"].TopValues = []profiler.ValueCount{{Value: "<script>x</script>", Count: 10}}
+
+	var buf bytes.Buffer
+	if err := WriteHTMLReport(&buf, profile); err != nil {
+		t.Fatalf("WriteHTMLReport failed: %v", err)
+	}
+	content := buf.String()
+	if strings.Contains(content, "<script>x</script>") {
+		t.Errorf("Expected values in charts to be escaped")
+	}
+	if !strings.Contains(content, "<title>&lt;script&gt;x&lt;/script&gt;: 10 (1.02%)</title>") {
+		t.Errorf("Expected the escaped value in the tooltip")
+	}
+}
+
+func TestFormatAxisValue(t *testing.T) {
+	tests := map[float64]string{
+		0:            "0",
+		1e-12:        "0",
+		0.1 + 0.2:    "0.3",
+		12.345:       "12.35",
+		9999:         "9999",
+		25000:        "25k",
+		-1250000:     "-1.2M",
+		3000000000:   "3B",
+		0.0001234567: "0.0001235",
+	}
+	for v, want := range tests {
+		if got := formatAxisValue(v); got != want {
+			t.Errorf("%g: expected %q, got %q", v, want, got)
+		}
+	}
+}
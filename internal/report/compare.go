@@ -0,0 +1,576 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/compare"
+)
+
+type ComparisonHTMLTemplateData struct {
+	Result      *compare.Result
+	GeneratedAt string
+}
+
+func PrintComparisonReport(result *compare.Result) {
+	fmt.Println("📋 Row Counts:")
+	fmt.Printf("   • %s: %s\n", result.Base, formatNumber(result.BaseRowCount))
+	fmt.Printf("   • %s: %s\n", result.Target, formatNumber(result.TargetRowCount))
+	fmt.Printf("   • Delta: %s\n", formatDelta(result.RowCountDelta()))
+	fmt.Println()
+
+	fmt.Println("🧩 Schema Changes:")
+	if !result.SchemaChanged() {
+		fmt.Println("   • No schema changes")
+	}
+	for _, col := range result.AddedColumns {
+		successStyle.Printf("   + %s (%s)\n", col.Name, col.DataType)
+	}
+	for _, col := range result.RemovedColumns {
+		errorStyle.Printf("   - %s (%s)\n", col.Name, col.DataType)
+	}
+	for _, change := range result.RetypedColumns {
+		warnStyle.Printf("   ~ %s: %s → %s\n", change.Name, change.OldType, change.NewType)
+	}
+	fmt.Println()
+
+	if len(result.Renames) > 0 {
+		fmt.Println("🔀 Probable Renames:")
+		for _, rename := range result.Renames {
+			if rename.IdenticalContent {
+				infoStyle.Printf("   • %s → %s (identical values)\n", rename.OldName, rename.NewName)
+			} else {
+				infoStyle.Printf("   • %s → %s (drift %.3f)\n", rename.OldName, rename.NewName, rename.Drift)
+			}
+		}
+		fmt.Println()
+	}
+
+	if result.SchemaOnly {
+		return
+	}
+
+	fmt.Println("📈 Column Changes:")
+	fmt.Printf("   %-16s %-16s %-20s %-20s %-8s %s\n", "NAME", "MISSING", "MEAN", "STDDEV", "DRIFT", "METRICS")
+	fmt.Printf("   %s\n", strings.Repeat("─", 104))
+
+	for _, col := range result.Columns {
+		colName := col.Name
+		if len(colName) > 16 {
+			colName = colName[:13] + "..."
+		}
+
+		missingStr := fmt.Sprintf("%.1f%%→%.1f%%", col.OldMissingPercent, col.NewMissingPercent)
+
+		meanStr, stdDevStr := "-", "-"
+		if col.IsNumeric {
+			meanStr = fmt.Sprintf("%.2f→%.2f", col.OldMean, col.NewMean)
+			stdDevStr = fmt.Sprintf("%.2f→%.2f", col.OldStdDev, col.NewStdDev)
+		}
+
+		line := fmt.Sprintf("   %-16s %-16s %-20s %-20s %-8s %s\n", colName, missingStr, meanStr, stdDevStr, formatDrift(col, col.Drift), formatDriftMetrics(col))
+		if col.Changed() {
+			warnStyle.Print(line)
+		} else {
+			fmt.Print(line)
+		}
+	}
+	fmt.Println()
+
+	fmt.Printf("🌊 Drift Score: %.1f%% of columns drifted%s\n\n", result.DriftScore(), formatUndecidedDrift(result))
+
+	if len(result.Cardinality) > 0 {
+		fmt.Println("💥 Cardinality Explosions:")
+		for _, alert := range result.Cardinality {
+			warnStyle.Printf("   • %s\n", alert.Description())
+		}
+		fmt.Println()
+	}
+
+	if len(result.Overlaps) > 0 {
+		fmt.Println("🔗 Shared Values:")
+		for _, overlap := range result.Overlaps {
+			line := fmt.Sprintf("   • %s: %s\n", overlap.Column(), overlap)
+			if overlap.Different() {
+				warnStyle.Print(line)
+			} else {
+				fmt.Print(line)
+			}
+		}
+		fmt.Println()
+	}
+
+	changed := result.ChangedColumns()
+	if len(changed) > 0 {
+		fmt.Println("⚠️ Significant Changes:")
+		for _, col := range changed {
+			fmt.Printf("   • Column '%s': %s\n", col.Name, formatChanges(col))
+		}
+		fmt.Println()
+	}
+}
+
+func GenerateComparisonHTMLReport(result *compare.Result, outputPath string) error {
+	var buf bytes.Buffer
+	if err := WriteComparisonHTMLReport(&buf, result); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write HTML report to file: %w", err)
+	}
+
+	return nil
+}
+
+// WriteComparisonHTMLReport renders the HTML comparison report to w.
+func WriteComparisonHTMLReport(w io.Writer, result *compare.Result) error {
+	tmpl, err := template.New("comparison").Funcs(template.FuncMap{
+		"formatNumber": formatNumberHTML,
+		"formatDelta":  formatDelta,
+		"drift":        func(col compare.ColumnDiff) string { return formatDrift(col, col.Drift) },
+		"driftMetrics": formatDriftMetrics,
+		"undecided":    formatUndecidedDrift,
+		"changes":      formatChanges,
+		"percent":      func(share float64) float64 { return share * 100 },
+	}).Parse(comparisonHTMLTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse HTML template: %w", err)
+	}
+
+	data := ComparisonHTMLTemplateData{
+		Result:      result,
+		GeneratedAt: time.Now().Format("January 2, 2006 15:04:05"),
+	}
+
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render HTML template: %w", err)
+	}
+
+	return nil
+}
+
+// formatDrift writes a drift metric of col, marked ≥ when it is a lower
+// bound.
+func formatDrift(col compare.ColumnDiff, value float64) string {
+	if col.DriftLowerBound {
+		return fmt.Sprintf("≥%.3f", value)
+	}
+	return fmt.Sprintf("%.3f", value)
+}
+
+// formatUndecidedDrift notes the columns left out of the drift score, or
+// nothing when there are none.
+func formatUndecidedDrift(result *compare.Result) string {
+	undecided := result.UndecidedDrift()
+	if undecided == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d measured on top values alone left out)", undecided)
+}
+
+// formatDriftMetrics lists the drift metrics measured for the column type.
+func formatDriftMetrics(col compare.ColumnDiff) string {
+	if col.IsNumeric {
+		return fmt.Sprintf("psi %.3f, ks %.3f", col.PSI, col.KS)
+	}
+	metrics := "js " + formatDrift(col, col.JSDivergence)
+	if col.ChiSquare > 0 {
+		metrics += fmt.Sprintf(", χ² %.1f (p %.3g)", col.ChiSquare, col.ChiSquarePValue)
+	}
+	return metrics
+}
+
+// formatChanges lists the changes of a column, naming the metrics behind a
+// distribution change.
+func formatChanges(col compare.ColumnDiff) string {
+	changes := make([]string, len(col.Changes))
+	for i, change := range col.Changes {
+		changes[i] = change
+		if change == "distribution" && len(col.DriftMetrics) > 0 {
+			changes[i] += " (" + strings.Join(col.DriftMetrics, ", ") + ")"
+		}
+	}
+	return strings.Join(changes, ", ")
+}
+
+func formatDelta(n int) string {
+	if n > 0 {
+		return "+" + formatNumber(n)
+	}
+	if n < 0 {
+		return "-" + formatNumber(-n)
+	}
+	return "0"
+}
+
+// GenerateComparisonMarkdownReport writes the Markdown comparison report to
+// outputPath.
+func GenerateComparisonMarkdownReport(result *compare.Result, outputPath string) error {
+	var buf bytes.Buffer
+	if err := WriteComparisonMarkdown(&buf, result); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write Markdown report to file: %w", err)
+	}
+
+	return nil
+}
+
+// WriteComparisonMarkdown writes a Markdown comparison report laid out for
+// code review, to paste into a pull request or post as a comment: the
+// schema changes and the columns that changed, with ▲ and ▼ marking which
+// way, up front, and every column, cardinality explosion and shared value
+// in a collapsed section.
+func WriteComparisonMarkdown(w io.Writer, result *compare.Result) error {
+	var content strings.Builder
+
+	content.WriteString(fmt.Sprintf("## 🔍 DataSleuth comparison: `%s` → `%s`\n\n", displaySource(result.Base), displaySource(result.Target)))
+	content.WriteString(fmt.Sprintf("**Rows:** %s → %s (%s) | **Schema:** %d added, %d removed, %d retyped",
+		formatNumber(result.BaseRowCount), formatNumber(result.TargetRowCount), formatRowTrend(result),
+		len(result.AddedColumns), len(result.RemovedColumns), len(result.RetypedColumns)))
+	if !result.SchemaOnly {
+		content.WriteString(fmt.Sprintf(" | **Drift:** %.1f%% of columns%s | **Changed columns:** %d of %d",
+			result.DriftScore(), formatUndecidedDrift(result), len(result.ChangedColumns()), len(result.Columns)))
+	}
+	content.WriteString("\n\n")
+
+	content.WriteString("### Schema Changes\n\n")
+	if !result.SchemaChanged() && len(result.Renames) == 0 {
+		content.WriteString("No schema changes.\n\n")
+	} else {
+		content.WriteString("| Change | Column | Type |\n")
+		content.WriteString("|---|---|---|\n")
+		for _, col := range result.AddedColumns {
+			content.WriteString(fmt.Sprintf("| ➕ Added | `%s` | %s |\n", escapeCodeCell(col.Name), col.DataType))
+		}
+		for _, col := range result.RemovedColumns {
+			content.WriteString(fmt.Sprintf("| ➖ Removed | `%s` | %s |\n", escapeCodeCell(col.Name), col.DataType))
+		}
+		for _, change := range result.RetypedColumns {
+			content.WriteString(fmt.Sprintf("| 🔁 Retyped | `%s` | %s → %s |\n", escapeCodeCell(change.Name), change.OldType, change.NewType))
+		}
+		for _, rename := range result.Renames {
+			detail := fmt.Sprintf("drift %.3f", rename.Drift)
+			if rename.IdenticalContent {
+				detail = "identical values"
+			}
+			content.WriteString(fmt.Sprintf("| 🔀 Probable rename | `%s` → `%s` | %s |\n", escapeCodeCell(rename.OldName), escapeCodeCell(rename.NewName), detail))
+		}
+		content.WriteString("\n")
+	}
+
+	if !result.SchemaOnly {
+		writeMarkdownDrift(&content, result)
+	}
+
+	content.WriteString("---\nGenerated by DataSleuth v0.1.0 - Fast dataset profiling and validation from the command line\n")
+
+	_, err := io.WriteString(w, content.String())
+	return err
+}
+
+// writeMarkdownDrift writes the columns that changed, then every column,
+// the cardinality explosions and the shared values collapsed.
+func writeMarkdownDrift(content *strings.Builder, result *compare.Result) {
+	content.WriteString("### Column Drift\n\n")
+	if changed := result.ChangedColumns(); len(changed) == 0 {
+		content.WriteString("No column changed beyond the thresholds.\n\n")
+	} else {
+		writeMarkdownColumnDiffs(content, changed)
+	}
+
+	content.WriteString(fmt.Sprintf("<details><summary>All columns (%d)</summary>\n\n", len(result.Columns)))
+	writeMarkdownColumnDiffs(content, result.Columns)
+	if len(result.Cardinality) > 0 {
+		content.WriteString("**Cardinality explosions**\n\n")
+		for _, alert := range result.Cardinality {
+			content.WriteString(fmt.Sprintf("- %s\n", escapeTableCell(alert.Description())))
+		}
+		content.WriteString("\n")
+	}
+	if len(result.Overlaps) > 0 {
+		content.WriteString("**Shared values**\n\n")
+		content.WriteString("| Column | Old Values Kept | New Values Seen Before | Jaccard | Verdict |\n")
+		content.WriteString("|---|---:|---:|---:|---|\n")
+		for _, overlap := range result.Overlaps {
+			approx := "~"
+			if overlap.Exact {
+				approx = ""
+			}
+			content.WriteString(fmt.Sprintf("| `%s` | %.1f%% | %.1f%% | %s%.3f | %s |\n", escapeCodeCell(overlap.Column()),
+				overlap.Retained()*100, overlap.Recurring()*100, approx, overlap.Jaccard, escapeTableCell(overlap.Description())))
+		}
+		content.WriteString("\n")
+	}
+	content.WriteString("</details>\n\n")
+}
+
+// writeMarkdownColumnDiffs writes a table of the columns with the way their
+// missing rate, mean and standard deviation moved.
+func writeMarkdownColumnDiffs(content *strings.Builder, columns []compare.ColumnDiff) {
+	content.WriteString("| Column | Missing | Mean | Std Dev | Drift | Metrics | Changes |\n")
+	content.WriteString("|---|---|---|---|---:|---|---|\n")
+	for _, col := range columns {
+		missing := fmt.Sprintf("%.1f%% → %.1f%%%s", col.OldMissingPercent, col.NewMissingPercent,
+			formatTrend(col.NewMissingPercent-col.OldMissingPercent, "%.1f pp"))
+		mean, stdDev := "-", "-"
+		if col.IsNumeric {
+			mean = fmt.Sprintf("%.2f → %.2f%s", col.OldMean, col.NewMean, formatRelativeTrend(col.OldMean, col.NewMean))
+			stdDev = fmt.Sprintf("%.2f → %.2f%s", col.OldStdDev, col.NewStdDev, formatRelativeTrend(col.OldStdDev, col.NewStdDev))
+		}
+		drift := formatDrift(col, col.Drift)
+		if col.Drifted() {
+			drift = "⚠️ " + drift
+		}
+		changes := formatChanges(col)
+		if changes == "" {
+			changes = "-"
+		}
+		content.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s | %s | %s | %s |\n", escapeCodeCell(col.Name),
+			missing, mean, stdDev, drift, escapeTableCell(formatDriftMetrics(col)), escapeTableCell(changes)))
+	}
+	content.WriteString("\n")
+}
+
+// formatTrend marks a change of delta with ▲ or ▼ and its size in format,
+// or with nothing when it rounds to none.
+func formatTrend(delta float64, format string) string {
+	size := fmt.Sprintf(format, math.Abs(delta))
+	switch {
+	case size == fmt.Sprintf(format, 0.0):
+		return ""
+	case delta > 0:
+		return " ▲ " + size
+	}
+	return " ▼ " + size
+}
+
+// formatRelativeTrend marks the change from old to new as a percentage of
+// old, or as the difference when old is 0.
+func formatRelativeTrend(old, new float64) string {
+	if old == 0 {
+		return formatTrend(new-old, "%.2f")
+	}
+	return formatTrend((new-old)/math.Abs(old)*100, "%.1f%%")
+}
+
+// formatRowTrend is the change in row count with its share of the base.
+func formatRowTrend(result *compare.Result) string {
+	delta := result.RowCountDelta()
+	if delta == 0 || result.BaseRowCount == 0 {
+		return formatDelta(delta)
+	}
+	return formatDelta(delta) + formatRelativeTrend(float64(result.BaseRowCount), float64(result.TargetRowCount))
+}
+
+// escapeCodeCell makes a column name safe inside a code span of a table
+// cell.
+func escapeCodeCell(value string) string {
+	return escapeTableCell(strings.ReplaceAll(value, "`", "'"))
+}
+
+const comparisonHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>DataSleuth Comparison: {{.Result.Base}} vs {{.Result.Target}}</title>
+    <style>
+        :root {
+            --primary-color: #1a73e8;
+            --secondary-color: #5f6368;
+            --background-color: #f8f9fa;
+            --card-color: #ffffff;
+            --border-color: #dadce0;
+            --text-color: #202124;
+            --success-color: #0f9d58;
+            --warning-color: #f4b400;
+            --error-color: #d93025;
+        }
+        
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, Oxygen, Ubuntu, Cantarell, "Open Sans", "Helvetica Neue", sans-serif;
+            line-height: 1.6;
+            color: var(--text-color);
+            background-color: var(--background-color);
+            margin: 0;
+            padding: 20px;
+        }
+        
+        .container {
+            max-width: 1200px;
+            margin: 0 auto;
+        }
+        
+        header {
+            background-color: var(--primary-color);
+            color: white;
+            padding: 20px;
+            border-radius: 8px 8px 0 0;
+        }
+        
+        .card {
+            background-color: var(--card-color);
+            border-radius: 8px;
+            box-shadow: 0 2px 5px rgba(0, 0, 0, 0.1);
+            padding: 20px;
+            margin-bottom: 20px;
+        }
+        
+        table {
+            width: 100%;
+            border-collapse: collapse;
+        }
+        
+        th, td {
+            padding: 12px 15px;
+            text-align: left;
+            border-bottom: 1px solid var(--border-color);
+        }
+        
+        th {
+            background-color: var(--background-color);
+        }
+        
+        .added {
+            color: var(--success-color);
+        }
+        
+        .removed {
+            color: var(--error-color);
+        }
+        
+        .renamed {
+            color: var(--primary-color);
+        }
+        
+        .retyped, .changed td {
+            color: var(--warning-color);
+        }
+        
+        .footer {
+            text-align: center;
+            margin-top: 40px;
+            color: var(--secondary-color);
+            font-size: 0.9em;
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <header>
+            <h1>DataSleuth Comparison</h1>
+            <p>{{.Result.Base}} vs {{.Result.Target}} | Generated: {{.GeneratedAt}}</p>
+        </header>
+        
+        <div class="card">
+            <h2>Row Counts</h2>
+            <p><strong>{{.Result.Base}}:</strong> {{formatNumber .Result.BaseRowCount}}</p>
+            <p><strong>{{.Result.Target}}:</strong> {{formatNumber .Result.TargetRowCount}}</p>
+            <p><strong>Delta:</strong> {{formatDelta .Result.RowCountDelta}}</p>
+        </div>
+        
+        <div class="card">
+            <h2>Schema Changes</h2>
+            {{if .Result.SchemaChanged}}
+            <ul>
+                {{range .Result.AddedColumns}}
+                <li class="added">Added: {{.Name}} ({{.DataType}})</li>
+                {{end}}
+                {{range .Result.RemovedColumns}}
+                <li class="removed">Removed: {{.Name}} ({{.DataType}})</li>
+                {{end}}
+                {{range .Result.Renames}}
+                <li class="renamed">Probable rename: {{.OldName}} → {{.NewName}} ({{if .IdenticalContent}}identical values{{else}}drift {{printf "%.3f" .Drift}}{{end}})</li>
+                {{end}}
+                {{range .Result.RetypedColumns}}
+                <li class="retyped">Retyped: {{.Name}} ({{.OldType}} → {{.NewType}})</li>
+                {{end}}
+            </ul>
+            {{else}}
+            <p>No schema changes.</p>
+            {{end}}
+        </div>
+        
+        {{if not .Result.SchemaOnly}}
+        <div class="card">
+            <h2>Column Changes</h2>
+            <p><strong>Drift score:</strong> {{printf "%.1f" .Result.DriftScore}}% of columns drifted{{undecided .Result}}</p>
+            <table>
+                <tr>
+                    <th>Column</th>
+                    <th>Missing</th>
+                    <th>Mean</th>
+                    <th>Std Dev</th>
+                    <th>Drift</th>
+                    <th>Metrics</th>
+                    <th>Changes</th>
+                </tr>
+                {{range .Result.Columns}}
+                <tr{{if .Changed}} class="changed"{{end}}>
+                    <td>{{.Name}}</td>
+                    <td>{{printf "%.2f" .OldMissingPercent}}% → {{printf "%.2f" .NewMissingPercent}}%</td>
+                    {{if .IsNumeric}}
+                    <td>{{formatNumber .OldMean}} → {{formatNumber .NewMean}}</td>
+                    <td>{{formatNumber .OldStdDev}} → {{formatNumber .NewStdDev}}</td>
+                    {{else}}
+                    <td>-</td>
+                    <td>-</td>
+                    {{end}}
+                    <td>{{drift .}}</td>
+                    <td>{{driftMetrics .}}</td>
+                    <td>{{changes .}}</td>
+                </tr>
+                {{end}}
+            </table>
+        </div>
+        {{if .Result.Cardinality}}
+        <div class="card">
+            <h2>Cardinality Explosions</h2>
+            <ul>
+                {{range .Result.Cardinality}}
+                <li class="retyped">{{.Description}}</li>
+                {{end}}
+            </ul>
+        </div>
+        {{end}}
+        {{if .Result.Overlaps}}
+        <div class="card">
+            <h2>Shared Values</h2>
+            <table>
+                <tr>
+                    <th>Column</th>
+                    <th>Old Values Kept</th>
+                    <th>New Values Seen Before</th>
+                    <th>Jaccard</th>
+                    <th>Verdict</th>
+                </tr>
+                {{range .Result.Overlaps}}
+                <tr{{if .Different}} class="changed"{{end}}>
+                    <td>{{.Column}}</td>
+                    <td>{{printf "%.1f" (percent .Retained)}}%</td>
+                    <td>{{printf "%.1f" (percent .Recurring)}}%</td>
+                    <td>{{if not .Exact}}~{{end}}{{printf "%.3f" .Jaccard}}</td>
+                    <td>{{.Description}}</td>
+                </tr>
+                {{end}}
+            </table>
+        </div>
+        {{end}}
+        {{end}}
+        
+        <div class="footer">
+            <p>Generated by DataSleuth v0.1.0 - Fast dataset profiling and validation from the command line</p>
+        </div>
+    </div>
+</body>
+</html>`
@@ -0,0 +1,137 @@
+package report
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/compare"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func createTestComparison() *compare.Result {
+	base := createTestProfile()
+	target := createTestProfile()
+	target.RowCount = 1200
+	delete(target.Columns, "test_float")
+	target.Columns["test_int"].Mean = 90
+	base.Columns["test_str"].MinHash = &profiler.MinHash{Salt: "salt", Size: 16, Hashes: []uint64{1, 2, 3, 4}}
+	target.Columns["test_str"].MinHash = &profiler.MinHash{Salt: "salt", Size: 16, Hashes: []uint64{1, 2, 3, 4, 5, 6, 7, 8}}
+
+	return compare.Compare(base, target, compare.DefaultOptions())
+}
+
+func TestGenerateComparisonHTMLReport(t *testing.T) {
+	tempFile, err := os.CreateTemp("", "compare_*.html")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+	tempFile.Close()
+
+	if err := GenerateComparisonHTMLReport(createTestComparison(), tempFile.Name()); err != nil {
+		t.Fatalf("GenerateComparisonHTMLReport failed: %v", err)
+	}
+
+	content, err := os.ReadFile(tempFile.Name())
+	if err != nil {
+		t.Fatalf("Failed to read report file: %v", err)
+	}
+
+	htmlContent := string(content)
+
+	expectedStrings := []string{
+		"<!DOCTYPE html>",
+		"Schema Changes",
+		"Removed: test_float (float)",
+		"Column Changes",
+		"1200",
+		"Shared Values",
+		"values kept, new ones added",
+	}
+
+	for _, expected := range expectedStrings {
+		if !strings.Contains(htmlContent, expected) {
+			t.Errorf("Expected HTML to contain '%s'", expected)
+		}
+	}
+}
+
+func TestGenerateComparisonMarkdownReport(t *testing.T) {
+	tempFile, err := os.CreateTemp("", "compare_*.md")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+	tempFile.Close()
+
+	if err := GenerateComparisonMarkdownReport(createTestComparison(), tempFile.Name()); err != nil {
+		t.Fatalf("GenerateComparisonMarkdownReport failed: %v", err)
+	}
+
+	content, err := os.ReadFile(tempFile.Name())
+	if err != nil {
+		t.Fatalf("Failed to read report file: %v", err)
+	}
+	mdContent := string(content)
+
+	expectedStrings := []string{
+		"## 🔍 DataSleuth comparison: `test.csv` → `test.csv`",
+		"**Rows:** 1,000 → 1,200 (+200 ▲ 20.0%)",
+		"| ➖ Removed | `test_float` | float |",
+		"| `test_int` | 2.0% → 1.7% ▼ 0.3 pp | 50.00 → 90.00 ▲ 80.0% | 25.00 → 25.00 | 0.000 |",
+		"<details><summary>All columns (2)</summary>",
+		"| `test_str` | 100.0% | 50.0% | 0.500 | values kept, new ones added |",
+		"Generated by DataSleuth",
+	}
+	for _, expected := range expectedStrings {
+		if !strings.Contains(mdContent, expected) {
+			t.Errorf("Expected Markdown to contain '%s'", expected)
+		}
+	}
+
+	// Only the changed columns are listed before the collapsed section
+	drift := mdContent[strings.Index(mdContent, "### Column Drift"):strings.Index(mdContent, "<details>")]
+	if strings.Contains(drift, "test_str") {
+		t.Errorf("Expected only changed columns in the drift table, got:\n%s", drift)
+	}
+}
+
+func TestFormatTrend(t *testing.T) {
+	tests := []struct {
+		old, new float64
+		want     string
+	}{
+		{50, 90, " ▲ 80.0%"},
+		{-50, -25, " ▲ 50.0%"},
+		{10, 5, " ▼ 50.0%"},
+		{0, 2.5, " ▲ 2.50"},
+		{25, 25.001, ""},
+	}
+	for _, tt := range tests {
+		if got := formatRelativeTrend(tt.old, tt.new); got != tt.want {
+			t.Errorf("%g → %g: expected %q, got %q", tt.old, tt.new, tt.want, got)
+		}
+	}
+}
+
+func TestFormatDelta(t *testing.T) {
+	if formatDelta(1500) != "+1,500" || formatDelta(-20) != "-20" || formatDelta(0) != "0" {
+		t.Errorf("Unexpected delta formatting: %s %s %s", formatDelta(1500), formatDelta(-20), formatDelta(0))
+	}
+}
+
+func TestFormatDriftLowerBound(t *testing.T) {
+	col := compare.ColumnDiff{Drift: 0.25, JSDivergence: 0.18, DriftLowerBound: true}
+	if got := formatDrift(col, col.Drift); got != "≥0.250" {
+		t.Errorf("Expected the TVD marked as a lower bound, got %q", got)
+	}
+	if got := formatDriftMetrics(col); got != "js ≥0.180" {
+		t.Errorf("Expected the JS divergence marked as a lower bound, got %q", got)
+	}
+
+	result := &compare.Result{Columns: []compare.ColumnDiff{col, {Name: "exact"}}}
+	if got := formatUndecidedDrift(result); got != " (1 measured on top values alone left out)" {
+		t.Errorf("Expected the undecided column noted, got %q", got)
+	}
+}
@@ -0,0 +1,145 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/compare"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+type JSONConsensus struct {
+	Columns  []JSONConsensusColumn `json:"columns"`
+	Outliers []JSONOutlierFile     `json:"outliers"`
+}
+
+type JSONConsensusColumn struct {
+	Name     string `json:"name"`
+	DataType string `json:"data_type"`
+	Files    int    `json:"files"`
+}
+
+type JSONOutlierFile struct {
+	Source         string                `json:"source"`
+	MissingColumns []string              `json:"missing_columns,omitempty"`
+	ExtraColumns   []string              `json:"extra_columns,omitempty"`
+	RetypedColumns []JSONRetypedColumn   `json:"retyped_columns,omitempty"`
+	Columns        []JSONColumnDeviation `json:"columns,omitempty"`
+}
+
+type JSONRetypedColumn struct {
+	Name          string `json:"name"`
+	DataType      string `json:"data_type"`
+	ConsensusType string `json:"consensus_type"`
+}
+
+type JSONColumnDeviation struct {
+	Name                    string  `json:"name"`
+	Drift                   float64 `json:"drift"`
+	MedianDrift             float64 `json:"median_drift"`
+	MissingPercent          float64 `json:"missing_percent"`
+	ConsensusMissingPercent float64 `json:"consensus_missing_percent"`
+}
+
+// filesConsensus finds the consensus schema of the files, or of their
+// tables and sheets, that were profiled, and the ones that deviate from it.
+// It returns nil when there are too few to compare.
+func filesConsensus(files []FileProfile) *compare.Consensus {
+	var profiles []*profiler.DatasetProfile
+	var names []string
+	for _, row := range fileRows(files) {
+		if row.Err == nil {
+			profiles = append(profiles, row.Profile)
+			names = append(names, row.Name)
+		}
+	}
+	return compare.BuildConsensus(profiles, names)
+}
+
+func newJSONConsensus(consensus *compare.Consensus) *JSONConsensus {
+	if consensus == nil {
+		return nil
+	}
+	result := &JSONConsensus{
+		Columns:  make([]JSONConsensusColumn, 0, len(consensus.Columns)),
+		Outliers: make([]JSONOutlierFile, 0, len(consensus.Outliers)),
+	}
+	for _, col := range consensus.Columns {
+		result.Columns = append(result.Columns, JSONConsensusColumn{Name: col.Name, DataType: col.DataType, Files: col.Present})
+	}
+	for _, outlier := range consensus.Outliers {
+		file := JSONOutlierFile{Source: outlier.Name, MissingColumns: outlier.MissingColumns, ExtraColumns: outlier.ExtraColumns}
+		for _, change := range outlier.RetypedColumns {
+			file.RetypedColumns = append(file.RetypedColumns, JSONRetypedColumn{Name: change.Name, DataType: change.NewType, ConsensusType: change.OldType})
+		}
+		for _, col := range outlier.Columns {
+			file.Columns = append(file.Columns, JSONColumnDeviation{
+				Name:                    col.Name,
+				Drift:                   col.Drift,
+				MedianDrift:             col.MedianDrift,
+				MissingPercent:          col.MissingPercent,
+				ConsensusMissingPercent: col.ConsensusMissing,
+			})
+		}
+		result.Outliers = append(result.Outliers, file)
+	}
+	return result
+}
+
+// PrintConsensus prints the consensus schema of several files and the files
+// that deviate from it, if there are enough files to tell.
+func PrintConsensus(files []FileProfile) {
+	consensus := filesConsensus(files)
+	if consensus == nil {
+		return
+	}
+
+	var columns []string
+	for _, col := range consensus.Columns {
+		columns = append(columns, fmt.Sprintf("%s (%s)", col.Name, col.DataType))
+	}
+	fmt.Printf("🧩 Consensus Schema of %d files: %s\n", consensus.Files, strings.Join(columns, ", "))
+	if len(consensus.Outliers) == 0 {
+		successStyle.Println("   ✓ No file deviates from the others")
+		fmt.Println()
+		return
+	}
+
+	fmt.Printf("   %d outlier files:\n", len(consensus.Outliers))
+	for _, outlier := range consensus.Outliers {
+		warnStyle.Printf("   ⚠️  %s\n", outlier.Name)
+		for _, line := range outlier.Descriptions() {
+			fmt.Printf("      • %s\n", line)
+		}
+	}
+	fmt.Println()
+}
+
+// writeMarkdownConsensus writes the consensus schema and outlier files
+// sections of a report of several files.
+func writeMarkdownConsensus(content *strings.Builder, consensus *compare.Consensus) {
+	if consensus == nil {
+		return
+	}
+
+	content.WriteString("## Consensus Schema\n\n")
+	content.WriteString("| Column | Type | Files |\n")
+	content.WriteString("|--------|------|-------|\n")
+	for _, col := range consensus.Columns {
+		content.WriteString(fmt.Sprintf("| %s | %s | %d/%d |\n", escapeTableCell(col.Name), col.DataType, col.Present, consensus.Files))
+	}
+	content.WriteString("\n")
+
+	content.WriteString("## Outlier Files\n\n")
+	if len(consensus.Outliers) == 0 {
+		content.WriteString("No file deviates from the others.\n\n")
+		return
+	}
+	for _, outlier := range consensus.Outliers {
+		content.WriteString(fmt.Sprintf("**%s**\n\n", outlier.Name))
+		for _, line := range outlier.Descriptions() {
+			content.WriteString(fmt.Sprintf("- %s\n", line))
+		}
+		content.WriteString("\n")
+	}
+}
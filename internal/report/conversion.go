@@ -0,0 +1,35 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// conversionRisks lists the columns of which casting to a stricter type
+// would lose or corrupt values, in column order.
+func conversionRisks(profile *profiler.DatasetProfile) []*profiler.ColumnProfile {
+	risks := make([]*profiler.ColumnProfile, 0)
+	for _, col := range profile.Columns {
+		if col.Conversion != nil && col.Conversion.AtRisk() > 0 {
+			risks = append(risks, col)
+		}
+	}
+	sort.Slice(risks, func(i, j int) bool {
+		if risks[i].Position != risks[j].Position {
+			return risks[i].Position < risks[j].Position
+		}
+		return risks[i].Name < risks[j].Name
+	})
+	return risks
+}
+
+// conversionLine describes the values at risk in a cast of col, naming
+// its inferred type when the cast is to a stricter one.
+func conversionLine(col *profiler.ColumnProfile) string {
+	if col.DataType != col.Conversion.Type {
+		return fmt.Sprintf("%s (%s): %s", col.Name, col.DataType, col.Conversion)
+	}
+	return fmt.Sprintf("%s: %s", col.Name, col.Conversion)
+}
@@ -0,0 +1,235 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+const (
+	DefaultDocsExamples = 3
+	redactedValue       = "[redacted]"
+)
+
+type DocsOptions struct {
+	Descriptions map[string]string // column name to description
+	Redact       []string          // columns whose example values are hidden, "*" for all
+	Examples     int               // example values per column
+}
+
+func (o DocsOptions) redacted(column string) bool {
+	for _, name := range o.Redact {
+		if name == "*" || strings.EqualFold(name, column) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadDataDictionary reads column descriptions from a JSON object of
+// column name to description, or from a CSV file whose first two fields are
+// the column name and its description. A header row is skipped.
+func LoadDataDictionary(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open data dictionary: %w", err)
+	}
+	defer file.Close()
+
+	descriptions := make(map[string]string)
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.NewDecoder(file).Decode(&descriptions); err != nil {
+			return nil, fmt.Errorf("failed to parse data dictionary: %w", err)
+		}
+		return descriptions, nil
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	if strings.EqualFold(filepath.Ext(path), ".tsv") {
+		reader.Comma = '\t'
+	}
+
+	for line := 0; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse data dictionary: %w", err)
+		}
+		if len(record) < 2 {
+			continue
+		}
+
+		name := strings.TrimSpace(record[0])
+		description := strings.TrimSpace(record[1])
+		if line == 0 && strings.EqualFold(description, "description") {
+			continue
+		}
+		descriptions[name] = description
+	}
+
+	return descriptions, nil
+}
+
+// GenerateDocsReport writes a Markdown README for the dataset: a schema
+// table followed by a section per column with its description, example
+// values and key quality statistics.
+func GenerateDocsReport(profile *profiler.DatasetProfile, opts DocsOptions, outputPath string) error {
+	if opts.Examples <= 0 {
+		opts.Examples = DefaultDocsExamples
+	}
+
+	// Columns follow the source schema; names break ties between profiles
+	// read from reports without positions
+	names := make([]string, 0, len(profile.Columns))
+	for name := range profile.Columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sort.SliceStable(names, func(i, j int) bool {
+		return profile.Columns[names[i]].Position < profile.Columns[names[j]].Position
+	})
+
+	var content strings.Builder
+
+	content.WriteString(fmt.Sprintf("# %s\n\n", profile.Filename))
+	content.WriteString(fmt.Sprintf("**Format:** %s | **Rows:** %s | **Columns:** %d | **Quality Score:** %d/100\n\n",
+		profile.Format,
+		formatRowCount(profile),
+		profile.ColumnCount,
+		profile.QualityScore))
+
+	content.WriteString("## Schema\n\n")
+	content.WriteString("| Column | Type | Description | Missing | Examples |\n")
+	content.WriteString("|--------|------|-------------|---------|----------|\n")
+	for _, name := range names {
+		col := profile.Columns[name]
+		content.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+			escapeTableCell(name),
+			col.DataType,
+			escapeTableCell(opts.Descriptions[name]),
+			formatMissing(col, profile.RowCount),
+			escapeTableCell(strings.Join(exampleValues(col, opts), ", "))))
+	}
+	content.WriteString("\n")
+
+	content.WriteString("## Columns\n\n")
+	for _, name := range names {
+		col := profile.Columns[name]
+
+		content.WriteString(fmt.Sprintf("### %s\n\n", name))
+		if description := opts.Descriptions[name]; description != "" {
+			content.WriteString(description + "\n\n")
+		} else {
+			content.WriteString("_No description._\n\n")
+		}
+
+		content.WriteString(fmt.Sprintf("- **Type:** %s\n", col.DataType))
+		content.WriteString(fmt.Sprintf("- **Missing:** %s\n", formatMissing(col, profile.RowCount)))
+		if col.Nullability != nil {
+			content.WriteString(fmt.Sprintf("- **Nullability:** %s\n", col.Nullability))
+		}
+		if col.Count > 0 {
+			content.WriteString(fmt.Sprintf("- **Distinct values:** %s\n", formatNumber(col.UniqueCount)))
+		}
+		if col.IsUnique {
+			content.WriteString("- **Unique:** yes\n")
+		}
+		if col.IsNumeric {
+			content.WriteString(fmt.Sprintf("- **Range:** %v - %v\n", col.Min, col.Max))
+			content.WriteString(fmt.Sprintf("- **Mean:** %.2f\n", col.Mean))
+		}
+		if d := col.DateTime; d != nil {
+			content.WriteString(fmt.Sprintf("- **Range:** %s - %s\n", d.Format(d.Min), d.Format(d.Max)))
+			content.WriteString(fmt.Sprintf("- **Granularity:** %s\n", d.Granularity))
+		}
+		if col.IsOpaque {
+			content.WriteString(fmt.Sprintf("- **Avg Size:** %s\n", profiler.FormatBytes(col.AvgLength)))
+		}
+
+		if examples := exampleValues(col, opts); len(examples) > 0 {
+			content.WriteString(fmt.Sprintf("- **Examples:** %s\n", strings.Join(examples, ", ")))
+		}
+
+		for _, issue := range col.QualityIssues {
+			content.WriteString(fmt.Sprintf("- **Quality issue:** %s\n", issue.Description))
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("## Data Quality\n\n")
+	content.WriteString(fmt.Sprintf("- **Quality Score:** %d/100\n", profile.QualityScore))
+	if totalCells := profile.RowCount * profile.ColumnCount; totalCells > 0 {
+		content.WriteString(fmt.Sprintf("- **Missing cells:** %s (%.2f%%)\n",
+			formatNumber(profile.MissingCells), float64(profile.MissingCells)/float64(totalCells)*100))
+	}
+	if profile.RowCount > 0 {
+		content.WriteString(fmt.Sprintf("- **Duplicate rows:** %s (%.2f%%)\n",
+			formatNumber(profile.DuplicateRows), float64(profile.DuplicateRows)/float64(profile.RowCount)*100))
+	}
+	for _, issue := range profile.QualityIssues {
+		content.WriteString(fmt.Sprintf("- %s\n", issue.Description))
+	}
+	content.WriteString("\n")
+
+	content.WriteString("---\n")
+	content.WriteString(fmt.Sprintf("Generated by DataSleuth v0.1.0 on %s\n", time.Now().Format("January 2, 2006")))
+
+	if err := os.WriteFile(outputPath, []byte(content.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write documentation to file: %w", err)
+	}
+
+	return nil
+}
+
+// exampleValues returns the most frequent values of a column, formatted as
+// inline code, or a single redaction marker for redacted columns.
+func exampleValues(col *profiler.ColumnProfile, opts DocsOptions) []string {
+	if len(col.TopValues) == 0 {
+		return nil
+	}
+	if opts.redacted(col.Name) {
+		return []string{redactedValue}
+	}
+
+	examples := make([]string, 0, opts.Examples)
+	for _, val := range col.TopValues {
+		if len(examples) == opts.Examples {
+			break
+		}
+		examples = append(examples, fmt.Sprintf("`%s`", truncateValue(val.Value, 40)))
+	}
+	return examples
+}
+
+func formatMissing(col *profiler.ColumnProfile, rowCount int) string {
+	if rowCount == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f%%", float64(col.MissingCount)/float64(rowCount)*100)
+}
+
+func escapeTableCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(value, "\n", " ")
+}
+
+func truncateValue(value string, limit int) string {
+	value = strings.ReplaceAll(value, "`", "'")
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit-3]) + "..."
+}
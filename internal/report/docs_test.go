@@ -0,0 +1,128 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateDocsReport(t *testing.T) {
+	profile := createTestProfile()
+	outputPath := filepath.Join(t.TempDir(), "dataset.md")
+
+	opts := DocsOptions{
+		Descriptions: map[string]string{"test_int": "Order quantity | units"},
+		Redact:       []string{"test_float"},
+		Examples:     2,
+	}
+	if err := GenerateDocsReport(profile, opts, outputPath); err != nil {
+		t.Fatalf("GenerateDocsReport failed: %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read documentation: %v", err)
+	}
+	docs := string(content)
+
+	expectedStrings := []string{
+		"# test.csv",
+		"## Schema",
+		"| test_int | integer | Order quantity \\| units |",
+		"`value1`, `value2`",
+		"## Columns",
+		"### test_str",
+		"_No description._",
+		"## Data Quality",
+		"High overall missing value rate: 5.00%",
+	}
+	for _, expected := range expectedStrings {
+		if !strings.Contains(docs, expected) {
+			t.Errorf("Expected documentation to contain '%s'", expected)
+		}
+	}
+
+	if strings.Contains(docs, "`value3`") {
+		t.Error("Expected examples to be limited to 2 values")
+	}
+}
+
+func TestGenerateDocsReportColumnOrder(t *testing.T) {
+	profile := createTestProfile()
+	for position, name := range []string{"test_str", "test_int", "test_float"} {
+		profile.Columns[name].Position = position
+	}
+	outputPath := filepath.Join(t.TempDir(), "dataset.md")
+
+	if err := GenerateDocsReport(profile, DocsOptions{}, outputPath); err != nil {
+		t.Fatalf("GenerateDocsReport failed: %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read documentation: %v", err)
+	}
+	docs := string(content)
+
+	// Both the schema table and the column sections follow the source
+	columns := docs[strings.Index(docs, "## Columns"):]
+	for _, section := range []string{docs, columns} {
+		str, integer, float := strings.Index(section, "test_str"), strings.Index(section, "test_int"), strings.Index(section, "test_float")
+		if str < 0 || !(str < integer && integer < float) {
+			t.Errorf("Expected columns in source order test_str, test_int, test_float, got:\n%s", section)
+		}
+	}
+}
+
+func TestGenerateDocsReportRedactsAll(t *testing.T) {
+	profile := createTestProfile()
+	outputPath := filepath.Join(t.TempDir(), "dataset.md")
+
+	if err := GenerateDocsReport(profile, DocsOptions{Redact: []string{"*"}}, outputPath); err != nil {
+		t.Fatalf("GenerateDocsReport failed: %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read documentation: %v", err)
+	}
+
+	if strings.Contains(string(content), "value1") {
+		t.Error("Expected example values to be redacted")
+	}
+	if !strings.Contains(string(content), redactedValue) {
+		t.Error("Expected redaction marker in documentation")
+	}
+}
+
+func TestLoadDataDictionary(t *testing.T) {
+	dir := t.TempDir()
+
+	csvPath := filepath.Join(dir, "dictionary.csv")
+	csvContent := "column,description\nid,Primary key\namount,\"Total, in USD\"\n"
+	if err := os.WriteFile(csvPath, []byte(csvContent), 0644); err != nil {
+		t.Fatalf("Failed to write dictionary: %v", err)
+	}
+
+	descriptions, err := LoadDataDictionary(csvPath)
+	if err != nil {
+		t.Fatalf("LoadDataDictionary failed: %v", err)
+	}
+	if len(descriptions) != 2 || descriptions["amount"] != "Total, in USD" {
+		t.Errorf("Unexpected CSV dictionary: %v", descriptions)
+	}
+
+	jsonPath := filepath.Join(dir, "dictionary.json")
+	if err := os.WriteFile(jsonPath, []byte(`{"id": "Primary key"}`), 0644); err != nil {
+		t.Fatalf("Failed to write dictionary: %v", err)
+	}
+
+	descriptions, err = LoadDataDictionary(jsonPath)
+	if err != nil {
+		t.Fatalf("LoadDataDictionary failed: %v", err)
+	}
+	if descriptions["id"] != "Primary key" {
+		t.Errorf("Unexpected JSON dictionary: %v", descriptions)
+	}
+}
@@ -0,0 +1,329 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/compare"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// FileProfile is the outcome of profiling one of several files: a profile
+// per table or sheet of a database or workbook, a single one otherwise, or
+// the error that stopped it.
+type FileProfile struct {
+	Source   string
+	Profiles []*profiler.DatasetProfile
+	Err      error
+}
+
+type JSONFilesReport struct {
+	Files       []JSONFileReport `json:"files"`
+	Consensus   *JSONConsensus   `json:"consensus,omitempty"`
+	GeneratedAt string           `json:"generated_at"`
+}
+
+type JSONFileReport struct {
+	Source  string       `json:"source"`
+	Error   string       `json:"error,omitempty"`
+	Reports []JSONReport `json:"reports,omitempty"`
+}
+
+// fileRow is a row of the summary of several files: a file, or a table or
+// sheet of one.
+type fileRow struct {
+	Name    string
+	Profile *profiler.DatasetProfile
+	Err     error
+}
+
+func fileRows(files []FileProfile) []fileRow {
+	var rows []fileRow
+	for _, file := range files {
+		if file.Err != nil {
+			rows = append(rows, fileRow{Name: file.Source, Err: file.Err})
+			continue
+		}
+		for _, profile := range file.Profiles {
+			name := file.Source
+			if profile.Table != "" {
+				name += " (" + profile.Table + ")"
+			}
+			rows = append(rows, fileRow{Name: name, Profile: profile})
+		}
+	}
+	return rows
+}
+
+// PrintFilesSummary prints one line per file, or per table or sheet, with
+// its rows, columns, quality score and issue count, followed by the totals.
+func PrintFilesSummary(files []FileProfile, elapsed time.Duration) {
+	failed := 0
+	for _, file := range files {
+		if file.Err != nil {
+			failed++
+		}
+	}
+
+	fmt.Printf("📁 %d files profiled in %.2f seconds", len(files), elapsed.Seconds())
+	if failed > 0 {
+		fmt.Printf(" (%d failed)", failed)
+	}
+	fmt.Println()
+	fmt.Printf("   %-40s %12s %8s %6s %7s %8s\n", "FILE", "ROWS", "COLUMNS", "SCORE", "ISSUES", "TIME")
+	fmt.Printf("   %s\n", strings.Repeat("─", 86))
+
+	rows, columns, scores, issues, profiles := 0, 0, 0, 0, 0
+	for _, row := range fileRows(files) {
+		name := truncateLeft(row.Name, 40)
+		if row.Err != nil {
+			fmt.Printf("   %-40s %s\n", name, errorStyle.Sprintf("error: %v", row.Err))
+			continue
+		}
+		profile := row.Profile
+		count := len(collectAllIssues(profile))
+		fmt.Printf("   %-40s %12s %8d %6d %7d %7.2fs\n", name, formatNumber(profile.RowCount),
+			profile.ColumnCount, profile.QualityScore, count, profile.ProcessingTime.Seconds())
+
+		rows += profile.RowCount
+		columns += profile.ColumnCount
+		scores += profile.QualityScore
+		issues += count
+		profiles++
+	}
+
+	if profiles > 1 {
+		fmt.Printf("   %s\n", strings.Repeat("─", 86))
+		fmt.Printf("   %-40s %12s %8d %6d %7d\n", "TOTAL (mean score)", formatNumber(rows), columns, scores/profiles, issues)
+	}
+	fmt.Println()
+}
+
+// GenerateFilesJSONReport writes the reports of several files into a single
+// JSON report, with the error of each file that failed and the consensus
+// schema of the others.
+func GenerateFilesJSONReport(files []FileProfile, outputPath string) error {
+	report := JSONFilesReport{
+		Files:       make([]JSONFileReport, 0, len(files)),
+		GeneratedAt: time.Now().Format(time.RFC3339),
+	}
+	for _, file := range files {
+		entry := JSONFileReport{Source: file.Source}
+		if file.Err != nil {
+			entry.Error = file.Err.Error()
+		}
+		for _, profile := range file.Profiles {
+			entry.Reports = append(entry.Reports, newJSONReport(profile))
+		}
+		report.Files = append(report.Files, entry)
+	}
+	report.Consensus = newJSONConsensus(filesConsensus(files))
+	return writeJSON(report, outputPath)
+}
+
+// GenerateFilesMarkdownReport writes a summary table of several files and
+// their consensus schema, followed by the full report of each one.
+func GenerateFilesMarkdownReport(files []FileProfile, outputPath string) error {
+	var content strings.Builder
+
+	content.WriteString(fmt.Sprintf("# DataSleuth Profile: %d files\n\n", len(files)))
+	content.WriteString("| File | Rows | Columns | Quality Score | Issues |\n")
+	content.WriteString("|------|------|---------|---------------|--------|\n")
+	for _, row := range fileRows(files) {
+		if row.Err != nil {
+			content.WriteString(fmt.Sprintf("| %s | error: %s | | | |\n", escapeTableCell(row.Name), escapeTableCell(row.Err.Error())))
+			continue
+		}
+		content.WriteString(fmt.Sprintf("| %s | %s | %d | %d/100 | %d |\n", escapeTableCell(row.Name),
+			formatNumber(row.Profile.RowCount), row.Profile.ColumnCount, row.Profile.QualityScore,
+			len(collectAllIssues(row.Profile))))
+	}
+	content.WriteString("\n")
+	writeMarkdownConsensus(&content, filesConsensus(files))
+
+	for _, row := range fileRows(files) {
+		if row.Err == nil {
+			content.WriteString("---\n\n")
+			writeMarkdownProfile(&content, row.Profile)
+		}
+	}
+
+	content.WriteString("---\n")
+	content.WriteString("Generated by DataSleuth v0.1.0 - Fast dataset profiling and validation from the command line\n")
+
+	if err := os.WriteFile(outputPath, []byte(content.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write Markdown report to file: %w", err)
+	}
+
+	return nil
+}
+
+type filesHTMLRow struct {
+	fileRow
+	Report string
+}
+
+// GenerateFilesHTMLReport writes one HTML report per file, or per table or
+// sheet, next to outputPath and an index page at outputPath linking to them.
+func GenerateFilesHTMLReport(files []FileProfile, outputPath string) error {
+	base := strings.TrimSuffix(outputPath, filepath.Ext(outputPath))
+	replacer := strings.NewReplacer("/", "_", "\\", "_", " ", "_", "(", "", ")", "")
+
+	var rows []filesHTMLRow
+	for i, row := range fileRows(files) {
+		entry := filesHTMLRow{fileRow: row}
+		if row.Err == nil {
+			reportPath := fmt.Sprintf("%s_%03d_%s.html", base, i+1, replacer.Replace(filepath.Base(row.Name)))
+			if err := GenerateHTMLReport(row.Profile, reportPath); err != nil {
+				return fmt.Errorf("failed to generate report for %s: %w", row.Name, err)
+			}
+			entry.Report = filepath.Base(reportPath)
+		}
+		rows = append(rows, entry)
+	}
+
+	tmpl, err := template.New("files").Funcs(template.FuncMap{
+		"formatNumber": formatNumberHTML,
+		"formatSchema": formatSchema,
+	}).Parse(filesHTMLTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse HTML template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct {
+		Files       int
+		Rows        []filesHTMLRow
+		Consensus   *compare.Consensus
+		GeneratedAt string
+	}{len(files), rows, filesConsensus(files), time.Now().Format("January 2, 2006 15:04:05")}); err != nil {
+		return fmt.Errorf("failed to render HTML template: %w", err)
+	}
+
+	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write HTML report to file: %w", err)
+	}
+
+	return nil
+}
+
+const filesHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>DataSleuth Profile: {{.Files}} files</title>
+    <style>
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif;
+            color: #202124;
+            background-color: #f8f9fa;
+            margin: 0;
+            padding: 20px;
+        }
+
+        .container {
+            max-width: 1000px;
+            margin: 0 auto;
+            background: white;
+            border-radius: 8px;
+            box-shadow: 0 1px 3px rgba(0,0,0,0.12);
+            padding: 20px;
+        }
+
+        table {
+            width: 100%;
+            border-collapse: collapse;
+        }
+
+        th, td {
+            padding: 12px 15px;
+            text-align: left;
+            border-bottom: 1px solid #dadce0;
+        }
+
+        a {
+            color: #1a73e8;
+        }
+
+        .schema {
+            font-size: 0.85em;
+            color: #5f6368;
+        }
+
+        .error {
+            color: #d93025;
+        }
+
+        .outlier {
+            margin: 12px 0;
+            padding: 8px 12px;
+            border-left: 4px solid #f9ab00;
+            background-color: #fef7e0;
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1>{{.Files}} files</h1>
+        <p>Profiled on {{.GeneratedAt}}</p>
+        <table>
+            <tr>
+                <th>File</th>
+                <th>Rows</th>
+                <th>Columns</th>
+                <th>Quality Score</th>
+                <th>Schema</th>
+            </tr>
+            {{range .Rows}}
+            <tr>
+            {{if .Err}}
+                <td>{{.Name}}</td>
+                <td colspan="4" class="error">{{.Err}}</td>
+            {{else}}
+                <td><a href="{{.Report}}">{{.Name}}</a></td>
+                <td>{{formatNumber .Profile.RowCount}}</td>
+                <td>{{.Profile.ColumnCount}}</td>
+                <td>{{.Profile.QualityScore}}/100</td>
+                <td class="schema">{{formatSchema .Profile 0}}</td>
+            {{end}}
+            </tr>
+            {{end}}
+        </table>
+        {{with .Consensus}}
+        <h2>Consensus Schema</h2>
+        <table>
+            <tr>
+                <th>Column</th>
+                <th>Type</th>
+                <th>Files</th>
+            </tr>
+            {{range .Columns}}
+            <tr>
+                <td>{{.Name}}</td>
+                <td>{{.DataType}}</td>
+                <td>{{.Present}}/{{$.Consensus.Files}}</td>
+            </tr>
+            {{end}}
+        </table>
+        <h2>Outlier Files</h2>
+        {{range .Outliers}}
+        <div class="outlier">
+            <strong>{{.Name}}</strong>
+            <ul>
+                {{range .Descriptions}}<li>{{.}}</li>{{end}}
+            </ul>
+        </div>
+        {{else}}
+        <p>No file deviates from the others.</p>
+        {{end}}
+        {{end}}
+    </div>
+</body>
+</html>
+`
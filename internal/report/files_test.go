@@ -0,0 +1,121 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func createTestFiles() []FileProfile {
+	return []FileProfile{
+		{Source: "data/a.csv", Profiles: []*profiler.DatasetProfile{createTestProfile()}},
+		{Source: "data/app.db", Profiles: createTestTables()},
+		{Source: "data/broken.csv", Err: errors.New("parse error on line 2")},
+	}
+}
+
+func TestGenerateFilesJSONReport(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "combined.json")
+
+	if err := GenerateFilesJSONReport(createTestFiles(), outputPath); err != nil {
+		t.Fatalf("GenerateFilesJSONReport failed: %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+
+	var report JSONFilesReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		t.Fatalf("Failed to parse report: %v", err)
+	}
+	if len(report.Files) != 3 || len(report.Files[1].Reports) != 2 || report.Files[1].Reports[1].Table != "users" {
+		t.Errorf("Unexpected files in report: %+v", report.Files)
+	}
+	if report.Files[2].Error != "parse error on line 2" || report.Files[2].Reports != nil {
+		t.Errorf("Expected the error of the broken file, got %+v", report.Files[2])
+	}
+	if report.Consensus == nil || len(report.Consensus.Columns) != 3 || len(report.Consensus.Outliers) != 0 {
+		t.Errorf("Expected a consensus of three columns without outliers, got %+v", report.Consensus)
+	}
+}
+
+func TestGenerateFilesMarkdownReport(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "combined.md")
+
+	if err := GenerateFilesMarkdownReport(createTestFiles(), outputPath); err != nil {
+		t.Fatalf("GenerateFilesMarkdownReport failed: %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+
+	for _, expected := range []string{
+		"# DataSleuth Profile: 3 files",
+		"| data/a.csv | 1,000 | 3 | 85/100 | 4 |",
+		"| data/app.db (users) | 1,000 | 3 | 85/100 | 4 |",
+		"| data/broken.csv | error: parse error on line 2 |",
+		"## Consensus Schema",
+		"No file deviates from the others.",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected Markdown to contain '%s'", expected)
+		}
+	}
+}
+
+func TestGenerateFilesHTMLReport(t *testing.T) {
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "combined.html")
+
+	if err := GenerateFilesHTMLReport(createTestFiles(), outputPath); err != nil {
+		t.Fatalf("GenerateFilesHTMLReport failed: %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	for _, expected := range []string{`href="combined_001_a.csv.html"`, `href="combined_003_app.db_users.html"`, "parse error on line 2"} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected index to contain %s", expected)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "combined_002_app.db_orders.html")); err != nil {
+		t.Errorf("Expected per-table report: %v", err)
+	}
+}
+
+func TestFilesConsensusOutlier(t *testing.T) {
+	files := createTestFiles()
+	renamed := createTestProfile()
+	for name, col := range renamed.Columns {
+		delete(renamed.Columns, name)
+		renamed.Columns["legacy_"+name] = col
+		break
+	}
+	files = append(files, FileProfile{Source: "data/partner.csv", Profiles: []*profiler.DatasetProfile{renamed}})
+
+	outputPath := filepath.Join(t.TempDir(), "combined.md")
+	if err := GenerateFilesMarkdownReport(files, outputPath); err != nil {
+		t.Fatalf("GenerateFilesMarkdownReport failed: %v", err)
+	}
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	for _, expected := range []string{"## Outlier Files", "**data/partner.csv**", "- missing columns: ", "- extra columns: legacy_"} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected Markdown to contain '%s'", expected)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/fingerprint"
+)
+
+func PrintFingerprintSummary(fp *fingerprint.Fingerprint) {
+	fmt.Printf("🔏 Column Fingerprint (%d columns, %d hashes each, salt %s):\n", len(fp.Columns), fp.Size, fp.Salt)
+	fmt.Printf("   %-32s %-10s %-12s %s\n", "COLUMN", "TYPE", "VALUES", "HASHES")
+	fmt.Printf("   %s\n", strings.Repeat("─", 76))
+
+	for _, col := range fp.Columns {
+		name := col.Name
+		if len(name) > 32 {
+			name = name[:29] + "..."
+		}
+		fmt.Printf("   %-32s %-10s %-12s %d\n", name, col.Type, formatNumber(col.Values), len(col.Hashes))
+	}
+	fmt.Println()
+}
+
+func PrintOverlapReport(overlap *fingerprint.Overlap) {
+	fmt.Println("🔗 Shared Values:")
+	if len(overlap.Matches) == 0 {
+		successStyle.Println("   • No column shares values with the other dataset")
+		fmt.Println()
+		return
+	}
+
+	fmt.Printf("   %-40s %-12s %-22s %s\n", "COLUMNS", "SHARED", "OF BASE / OF TARGET", "JACCARD")
+	fmt.Printf("   %s\n", strings.Repeat("─", 86))
+	for _, match := range overlap.Matches {
+		columns := match.Base + " ↔ " + match.Target
+		shared := formatNumber(int(math.Round(match.Shared)))
+		if !match.Exact {
+			shared = "~" + shared
+		}
+		shares := fmt.Sprintf("%.1f%% / %.1f%%", match.BaseShare()*100, match.TargetShare()*100)
+		fmt.Printf("   %-40s %-12s %-22s %.3f\n", columns, shared, shares, match.Jaccard)
+	}
+	fmt.Println()
+	fmt.Println("   Counts are of distinct values, trimmed and lower cased; ~ marks estimates.")
+	fmt.Println()
+}
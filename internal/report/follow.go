@@ -0,0 +1,72 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// PrintFollowSummary prints the latest window of a file followed with
+// profile --follow, and the alerts raised while following, oldest first.
+func PrintFollowSummary(update profiler.FollowUpdate, alerts []string) {
+	window := update.Window
+	titleStyle.Printf("📡 Following %s\n", window.Filename)
+	fmt.Printf("   • Records read: %s\n", formatNumber(update.Records))
+	fmt.Printf("   • Window: last %s records, updated %s\n", formatNumber(window.RowCount), update.Time.Local().Format("15:04:05"))
+	fmt.Printf("   • Quality score: %d/100\n", window.QualityScore)
+	if update.Reset {
+		warnStyle.Println("   • The file was truncated or replaced and is read again from its start")
+	}
+	fmt.Println()
+
+	fmt.Println("🔍 Latest Window:")
+	fmt.Printf("   %-16s %-10s %-8s %-8s %s\n", "NAME", "TYPE", "MISSING", "UNIQUE", "STATS")
+	fmt.Printf("   %s\n", strings.Repeat("─", 76))
+
+	names := make([]string, 0, len(window.Columns))
+	for name := range window.Columns {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return window.Columns[names[i]].Position < window.Columns[names[j]].Position
+	})
+
+	for _, name := range names {
+		col := window.Columns[name]
+		colName := name
+		if len(colName) > 16 {
+			colName = colName[:13] + "..."
+		}
+
+		missingStr := "0.00%"
+		if window.RowCount > 0 {
+			missingStr = fmt.Sprintf("%.2f%%", float64(col.MissingCount)/float64(window.RowCount)*100)
+		}
+
+		statsStr := "-"
+		switch {
+		case col.IsNumeric:
+			statsStr = fmt.Sprintf("mean=%.4g, min=%v, max=%v", col.Mean, col.Min, col.Max)
+		case len(col.TopValues) > 0:
+			top := col.TopValues[0]
+			statsStr = fmt.Sprintf("top=%s (%s)", truncateValue(top.Value, 24), formatNumber(top.Count))
+		}
+
+		fmt.Printf("   %-16s %-10s %-8s %-8s %s\n", colName, col.DataType, missingStr, formatNumber(col.UniqueCount), statsStr)
+	}
+	fmt.Println()
+
+	fmt.Println("🚨 Alerts:")
+	switch {
+	case update.Previous == nil:
+		fmt.Println("   • Waiting for two full windows to compare")
+	case len(alerts) == 0:
+		successStyle.Println("   • No shifts between consecutive windows")
+	}
+	for _, alert := range alerts {
+		warnStyle.Printf("   • %s\n", alert)
+	}
+	fmt.Println()
+}
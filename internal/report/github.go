@@ -0,0 +1,255 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/kamalm96/datasleuth/internal/validate"
+)
+
+// GitHubSummaryEnv names the file GitHub Actions shows as the summary of a
+// job step.
+const GitHubSummaryEnv = "GITHUB_STEP_SUMMARY"
+
+// GitHubFile is the path of source as annotations expect it: relative to
+// the checkout in $GITHUB_WORKSPACE, with forward slashes. It is empty for
+// sources that are not files, which are annotated without file context.
+func GitHubFile(source string) string {
+	if source == "-" || strings.Contains(source, "://") {
+		return ""
+	}
+	path := source
+	if workspace := os.Getenv("GITHUB_WORKSPACE"); workspace != "" {
+		if abs, err := filepath.Abs(source); err == nil {
+			if rel, err := filepath.Rel(workspace, abs); err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// githubLevels maps issue severities to annotation levels.
+var githubLevels = map[int]string{1: "notice", 2: "warning", 3: "error"}
+
+// writeGitHubCommand writes a workflow command such as
+// ::error file=data.csv,title=...::message, escaped as the runner expects.
+func writeGitHubCommand(w io.Writer, level, file, title, message string) {
+	var properties []string
+	if file != "" {
+		properties = append(properties, "file="+escapeGitHubProperty(file))
+	}
+	if title != "" {
+		properties = append(properties, "title="+escapeGitHubProperty(title))
+	}
+	command := "::" + level
+	if len(properties) > 0 {
+		command += " " + strings.Join(properties, ",")
+	}
+	fmt.Fprintf(w, "%s::%s\n", command, escapeGitHubData(message))
+}
+
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// WriteGitHubAnnotations writes one annotation per quality issue of the
+// profiles of file: errors for high severity issues, warnings for medium and
+// notices for low ones.
+func WriteGitHubAnnotations(w io.Writer, file string, profiles []*profiler.DatasetProfile) {
+	for _, profile := range profiles {
+		prefix := "DataSleuth"
+		if profile.Table != "" {
+			prefix += " " + profile.Table
+		}
+
+		for _, issue := range profile.QualityIssues {
+			writeGitHubCommand(w, githubLevels[issue.Severity], file, prefix, issue.Description)
+		}
+		for _, name := range sortedColumnNames(profile) {
+			for _, issue := range profile.Columns[name].QualityIssues {
+				writeGitHubCommand(w, githubLevels[issue.Severity], file, prefix+": "+name, issue.Description)
+			}
+		}
+	}
+}
+
+// WriteGitHubFilesAnnotations writes the annotations of several files, with
+// an error annotation for each file that could not be profiled.
+func WriteGitHubFilesAnnotations(w io.Writer, files []FileProfile) {
+	for _, file := range files {
+		if file.Err != nil {
+			writeGitHubCommand(w, "error", GitHubFile(file.Source), "DataSleuth", file.Err.Error())
+			continue
+		}
+		WriteGitHubAnnotations(w, GitHubFile(file.Source), file.Profiles)
+	}
+}
+
+// WriteGitHubValidationAnnotations writes an error annotation per failed
+// check of result.
+func WriteGitHubValidationAnnotations(w io.Writer, file string, result *validate.Result) {
+	for _, check := range result.Failures() {
+		title := "DataSleuth " + check.Name
+		if check.Column != "" {
+			title += ": " + check.Column
+		}
+		writeGitHubCommand(w, "error", file, title, check.Message)
+	}
+}
+
+// WriteGitHubSummary writes a Markdown summary of the profiles for
+// $GITHUB_STEP_SUMMARY: the key numbers of each dataset and its issues.
+func WriteGitHubSummary(w io.Writer, source string, profiles []*profiler.DatasetProfile) error {
+	var content strings.Builder
+
+	content.WriteString(fmt.Sprintf("## 🔍 DataSleuth: `%s`\n\n", displaySource(source)))
+	label := "Dataset"
+	if len(profiles) > 0 && profiles[0].Table != "" {
+		label = tableLabel(profiles[0])
+	}
+	content.WriteString(fmt.Sprintf("| %s | Rows | Columns | Quality Score | Missing Cells | Duplicate Rows | Issues |\n", label))
+	content.WriteString("|---|---:|---:|---:|---:|---:|---:|\n")
+	for _, profile := range profiles {
+		name := profile.Table
+		if name == "" {
+			name = profile.Filename
+		}
+		content.WriteString(fmt.Sprintf("| %s | %s | %d | %s %d/100 | %s | %s | %d |\n",
+			escapeTableCell(name), formatRowCount(profile), profile.ColumnCount,
+			scoreEmoji(profile.QualityScore), profile.QualityScore,
+			formatNumber(profile.MissingCells), formatNumber(profile.DuplicateRows),
+			len(profile.IssuesAtOrAbove(1))))
+	}
+	content.WriteString("\n")
+
+	for _, profile := range profiles {
+		issues := profile.IssuesAtOrAbove(1)
+		if len(issues) == 0 {
+			continue
+		}
+		sort.SliceStable(issues, func(i, j int) bool { return issues[i].Severity > issues[j].Severity })
+
+		summary := "Quality issues"
+		if profile.Table != "" {
+			summary += " in " + profile.Table
+		}
+		content.WriteString(fmt.Sprintf("<details><summary>%s (%d)</summary>\n\n", summary, len(issues)))
+		content.WriteString("| Severity | Issue |\n")
+		content.WriteString("|---|---|\n")
+		for _, issue := range issues {
+			content.WriteString(fmt.Sprintf("| %s | %s |\n", severityLabel(issue.Severity), escapeTableCell(issue.Description)))
+		}
+		content.WriteString("\n</details>\n\n")
+	}
+
+	_, err := io.WriteString(w, content.String())
+	return err
+}
+
+// WriteGitHubValidationSummary writes a Markdown summary of result for
+// $GITHUB_STEP_SUMMARY, listing the failed checks.
+func WriteGitHubValidationSummary(w io.Writer, result *validate.Result) error {
+	var content strings.Builder
+
+	failures := result.Failures()
+	status := "✅ Passed"
+	if len(failures) > 0 {
+		status = fmt.Sprintf("❌ %d of %d checks failed", len(failures), len(result.Checks))
+	}
+	content.WriteString(fmt.Sprintf("## 📏 DataSleuth validation: `%s`\n\n", displaySource(result.Source)))
+	if result.Baseline != "" {
+		content.WriteString(fmt.Sprintf("**Baseline:** `%s` | ", result.Baseline))
+	}
+	if result.Rules != "" {
+		content.WriteString(fmt.Sprintf("**Rules:** `%s` | ", result.Rules))
+	}
+	content.WriteString(fmt.Sprintf("**Checks passed:** %d/%d | **Result:** %s\n\n",
+		len(result.Checks)-len(failures), len(result.Checks), status))
+
+	if len(failures) > 0 {
+		content.WriteString("| Check | Column | Message |\n")
+		content.WriteString("|---|---|---|\n")
+		for _, check := range failures {
+			content.WriteString(fmt.Sprintf("| %s | %s | %s |\n", check.Name, escapeTableCell(check.Column), escapeTableCell(check.Message)))
+		}
+		content.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, content.String())
+	return err
+}
+
+// AppendGitHubSummary appends a summary written by write to outputPath, or
+// to $GITHUB_STEP_SUMMARY when outputPath is empty. It returns the file
+// written, or "" when there is none to write to.
+func AppendGitHubSummary(outputPath string, write func(w io.Writer) error) (string, error) {
+	if outputPath == "" {
+		outputPath = os.Getenv(GitHubSummaryEnv)
+	}
+	if outputPath == "" {
+		return "", nil
+	}
+
+	file, err := os.OpenFile(outputPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to open summary file: %w", err)
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to write summary: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write summary: %w", err)
+	}
+	return outputPath, nil
+}
+
+// displaySource names a source in a summary without its credentials.
+func displaySource(source string) string {
+	if strings.Contains(source, "://") {
+		if u, err := url.Parse(source); err == nil {
+			return u.Redacted()
+		}
+	}
+	return source
+}
+
+func scoreEmoji(score int) string {
+	switch {
+	case score >= 90:
+		return "🟢"
+	case score >= 70:
+		return "🟡"
+	}
+	return "🔴"
+}
+
+func severityLabel(severity int) string {
+	switch severity {
+	case 3:
+		return "🔴 High"
+	case 2:
+		return "🟡 Medium"
+	}
+	return "⚪ Low"
+}
+
+func sortedColumnNames(profile *profiler.DatasetProfile) []string {
+	names := make([]string, 0, len(profile.Columns))
+	for name := range profile.Columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,192 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/history"
+)
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+func PrintHistoryDatasets(datasets []history.Dataset) {
+	fmt.Printf("🗂️  Profile History (%d datasets):\n", len(datasets))
+	if len(datasets) == 0 {
+		fmt.Println("   No runs recorded yet; every profile run is recorded automatically.")
+		fmt.Println()
+		return
+	}
+
+	fmt.Printf("   %-48s %-6s %-12s %-7s %s\n", "DATASET", "RUNS", "ROWS", "SCORE", "LAST RUN")
+	fmt.Printf("   %s\n", strings.Repeat("─", 92))
+	for _, dataset := range datasets {
+		fmt.Printf("   %-48s %-6d %-12s %-7s %s\n", truncateLeft(dataset.Name, 48), dataset.Runs,
+			formatNumber(dataset.Latest.RowCount), fmt.Sprintf("%d/100", dataset.Latest.QualityScore),
+			dataset.LastRun.Local().Format("2006-01-02 15:04"))
+	}
+	fmt.Println()
+}
+
+func PrintHistoryRuns(dataset string, runs []history.Run, changes []history.Change) {
+	fmt.Printf("🕒 Runs of %s (%d shown):\n", dataset, len(runs))
+	fmt.Printf("   %-6s %-17s %-12s %-9s %-9s %-8s %-7s %s\n", "ID", "PROFILED", "ROWS", "Δ ROWS", "COLUMNS", "MISSING", "SCORE", "CONTENT")
+	fmt.Printf("   %s\n", strings.Repeat("─", 92))
+
+	for i, run := range runs {
+		rowDelta, scoreDelta := "-", ""
+		content := "new"
+		if i > 0 {
+			prev := runs[i-1]
+			rowDelta = fmt.Sprintf("%+d", run.RowCount-prev.RowCount)
+			if run.QualityScore != prev.QualityScore {
+				scoreDelta = fmt.Sprintf(" (%+d)", run.QualityScore-prev.QualityScore)
+			}
+			content = "changed"
+			if run.ContentDigest != "" && run.ContentDigest == prev.ContentDigest {
+				content = "unchanged"
+			}
+		}
+		if run.Sampled {
+			content += ", sampled"
+		}
+
+		line := fmt.Sprintf("   %-6d %-17s %-12s %-9s %-9d %-8s %-7s %s", run.ID, run.CreatedAt.Local().Format("2006-01-02 15:04"),
+			formatNumber(run.RowCount), rowDelta, run.ColumnCount, formatNumber(run.MissingCells),
+			fmt.Sprintf("%d%s", run.QualityScore, scoreDelta), content)
+		switch {
+		case run.QualityScore < 70:
+			errorStyle.Println(line)
+		case run.QualityScore < 90:
+			warnStyle.Println(line)
+		default:
+			fmt.Println(line)
+		}
+	}
+	fmt.Println()
+
+	if len(runs) > 1 {
+		rows := make([]float64, len(runs))
+		scores := make([]float64, len(runs))
+		parseErrors := make([]float64, len(runs))
+		for i, run := range runs {
+			rows[i] = float64(run.RowCount)
+			scores[i] = float64(run.QualityScore)
+			parseErrors[i] = float64(run.ParseErrors)
+		}
+		first, last := runs[0], runs[len(runs)-1]
+
+		fmt.Println("📈 Trends:")
+		fmt.Printf("   Rows:          %s  %s → %s\n", sparkline(rows), formatNumber(first.RowCount), formatNumber(last.RowCount))
+		fmt.Printf("   Quality score: %s  %d → %d\n", sparkline(scores), first.QualityScore, last.QualityScore)
+		fmt.Printf("   Parse errors:  %s  %s → %s\n", sparkline(parseErrors), formatNumber(first.ParseErrors), formatNumber(last.ParseErrors))
+		fmt.Println()
+	}
+
+	if len(changes) > 0 {
+		fmt.Println("🔀 Column Changes Between Runs:")
+		for _, change := range changes {
+			fmt.Printf("   Run %d → %d:\n", change.From.ID, change.To.ID)
+			for _, rename := range change.Renames {
+				infoStyle.Printf("     • '%s' probably renamed to '%s'\n", rename.OldName, rename.NewName)
+			}
+			for _, alert := range change.Cardinality {
+				warnStyle.Printf("     • %s\n", alert.Description())
+			}
+		}
+		fmt.Println()
+	}
+}
+
+// PrintHistorySLAs prints how often each column breached its completeness
+// SLA over runs, with the outcome of every run from oldest to newest: ✓ met,
+// ✗ breached and · not evaluated.
+func PrintHistorySLAs(runs []history.Run, slas []history.SLA) {
+	if len(slas) == 0 {
+		return
+	}
+
+	columns := make([]string, 0)
+	byColumn := make(map[string]map[int64]history.SLA)
+	for _, sla := range slas {
+		if byColumn[sla.Column] == nil {
+			byColumn[sla.Column] = make(map[int64]history.SLA)
+			columns = append(columns, sla.Column)
+		}
+		byColumn[sla.Column][sla.RunID] = sla
+	}
+
+	fmt.Println("🎯 Completeness SLAs:")
+	fmt.Printf("   %-24s %-9s %-16s %-8s %s\n", "COLUMN", "TARGET", "BREACHES", "LATEST", "RUNS")
+	fmt.Printf("   %s\n", strings.Repeat("─", 92))
+	for _, column := range columns {
+		var timeline strings.Builder
+		var latest history.SLA
+		evaluated, breaches := 0, 0
+		for _, run := range runs {
+			sla, ok := byColumn[column][run.ID]
+			switch {
+			case !ok:
+				timeline.WriteString("·")
+				continue
+			case sla.Met:
+				timeline.WriteString("✓")
+			default:
+				timeline.WriteString("✗")
+				breaches++
+			}
+			evaluated++
+			latest = sla
+		}
+
+		line := fmt.Sprintf("   %-24s %-9s %-16s %-8s %s", truncateLeft(column, 24), fmt.Sprintf("≥ %.1f%%", latest.Target),
+			fmt.Sprintf("%d/%d (%.0f%%)", breaches, evaluated, float64(breaches)/float64(evaluated)*100),
+			fmt.Sprintf("%.1f%%", latest.Actual), timeline.String())
+		switch {
+		case !latest.Met:
+			errorStyle.Println(line)
+		case breaches > 0:
+			warnStyle.Println(line)
+		default:
+			fmt.Println(line)
+		}
+	}
+	fmt.Println()
+}
+
+// sparkline draws values as block characters scaled between their minimum
+// and maximum.
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	low, high := values[0], values[0]
+	for _, v := range values {
+		if v < low {
+			low = v
+		}
+		if v > high {
+			high = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		idx := len(sparkBlocks) / 2
+		if high > low {
+			idx = int((v - low) / (high - low) * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[idx])
+	}
+	return b.String()
+}
+
+// truncateLeft keeps the end of long names, which for paths is the part
+// that tells datasets apart.
+func truncateLeft(name string, width int) string {
+	runes := []rune(name)
+	if len(runes) <= width {
+		return name
+	}
+	return "..." + string(runes[len(runes)-width+3:])
+}
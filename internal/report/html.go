@@ -5,9 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"io"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/kamalm96/datasleuth/internal/profiler"
 )
@@ -15,9 +19,88 @@ import (
 type HTMLTemplateData struct {
 	Profile         *profiler.DatasetProfile
 	GeneratedAt     string
-	Issues          []string
+	Issues          []HTMLIssue
+	ColumnIDs       map[string]string // anchor ID of each column card
 	Recommendations []string
-	FileSizeMB      float64
+	Conversions     []*profiler.ColumnProfile // columns a cast to a stricter type loses values of
+	FileSize        string
+}
+
+// HTMLIssue is a quality issue in the summary, with its own anchor ID and a
+// link to the card of its column, if any.
+type HTMLIssue struct {
+	ID          string
+	Column      string
+	ColumnID    string
+	Description string
+}
+
+// htmlAnchors gives every column and issue an anchor ID that stays the same
+// across runs: column-<name> and issue-<column>-<type>, or issue-<type> for
+// dataset issues. Names are slugged, and clashes numbered in column order.
+func htmlAnchors(profile *profiler.DatasetProfile) (map[string]string, []HTMLIssue) {
+	used := make(map[string]bool)
+	unique := func(id string) string {
+		candidate := id
+		for n := 2; used[candidate]; n++ {
+			candidate = fmt.Sprintf("%s-%d", id, n)
+		}
+		used[candidate] = true
+		return candidate
+	}
+
+	names := make([]string, 0, len(profile.Columns))
+	for name := range profile.Columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	columnIDs := make(map[string]string, len(names))
+	for _, name := range names {
+		columnIDs[name] = unique("column-" + anchorSlug(name))
+	}
+
+	issues := make([]HTMLIssue, 0)
+	for _, issue := range profile.QualityIssues {
+		issues = append(issues, HTMLIssue{
+			ID:          unique("issue-" + anchorSlug(issue.Type)),
+			Description: issue.Description,
+		})
+	}
+	for _, name := range names {
+		for _, issue := range profile.Columns[name].QualityIssues {
+			issues = append(issues, HTMLIssue{
+				ID:          unique("issue-" + anchorSlug(name) + "-" + anchorSlug(issue.Type)),
+				Column:      name,
+				ColumnID:    columnIDs[name],
+				Description: issue.Description,
+			})
+		}
+	}
+
+	return columnIDs, issues
+}
+
+// anchorSlug lowercases s and joins its runs of letters and digits with
+// dashes.
+func anchorSlug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	if b.Len() == 0 {
+		return "x"
+	}
+	return b.String()
 }
 
 func parseFloat(s string) float64 {
@@ -29,40 +112,75 @@ func parseFloat(s string) float64 {
 }
 
 func GenerateHTMLReport(profile *profiler.DatasetProfile, outputPath string) error {
+	var buf bytes.Buffer
+	if err := WriteHTMLReport(&buf, profile); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write HTML report to file: %w", err)
+	}
+
+	return nil
+}
+
+// WriteHTMLReport renders the HTML report of profile to w.
+func WriteHTMLReport(w io.Writer, profile *profiler.DatasetProfile) error {
 	tmpl, err := template.New("report").Funcs(template.FuncMap{
-		"formatNumber":  formatNumberHTML,
-		"formatPercent": formatPercentHTML,
-		"formatDate":    formatDateHTML,
-		"toJSON":        toJSON,
-		"div":           divideFloat,
-		"mul":           multiplyInts,
-		"percentage":    calculatePercentage,
-		"sub":           subtract,
-		"parseFloat":    parseFloat,
+		"formatNumber":         formatNumberHTML,
+		"formatPercent":        formatPercentHTML,
+		"formatDate":           formatDateHTML,
+		"toJSON":               toJSON,
+		"div":                  divideFloat,
+		"mul":                  multiplyInts,
+		"percentage":           calculatePercentage,
+		"sub":                  subtract,
+		"parseFloat":           parseFloat,
+		"formatBytes":          profiler.FormatBytes,
+		"formatSpan":           profiler.FormatSpan,
+		"formatDuplicateGroup": formatDuplicateGroup,
+		"formatDuplicateKey":   formatDuplicateKey,
+		"duplicatesLabel":      duplicatesLabel,
+		"parseBreakdown":       parseBreakdown,
+		"formatRowCount":       formatRowCount,
+		"formatTextLengths":    formatTextLengths,
+		"formatCasing":         formatCasing,
+		"formatPatterns":       formatPatterns,
+		"formatFormatting":     formatFormatting,
+		"windowTitle":          windowTitle,
+		"windowRange":          windowRange,
+		"formatWindowMissing":  formatWindowMissing,
+		"formatWindowDistinct": formatWindowDistinct,
+		"formatWindowMean":     formatWindowMean,
+		"join":                 strings.Join,
+		"histogramChart":       histogramChart,
+		"timelineChart":        timelineChart,
+		"boxPlot":              newBoxPlot,
+		"valueChart":           newValueChart,
+		"heatmaps":             correlationHeatmaps,
 	}).Parse(htmlTemplate)
+	if err == nil {
+		tmpl, err = tmpl.Parse(chartTemplates)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to parse HTML template: %w", err)
 	}
 
-	fileSizeMB := float64(profile.FileSize) / 1048576.0
-
+	columnIDs, issues := htmlAnchors(profile)
 	data := HTMLTemplateData{
 		Profile:         profile,
 		GeneratedAt:     time.Now().Format("January 2, 2006 15:04:05"),
-		Issues:          collectAllIssues(profile),
-		Recommendations: generateRecommendations(profile),
-		FileSizeMB:      fileSizeMB,
+		Issues:          issues,
+		ColumnIDs:       columnIDs,
+		Recommendations: recommendationMessages(profile),
+		Conversions:     conversionRisks(profile),
+		FileSize:        FormatFileSize(profile),
 	}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
+	if err := tmpl.Execute(w, data); err != nil {
 		return fmt.Errorf("failed to render HTML template: %w", err)
 	}
 
-	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
-		return fmt.Errorf("failed to write HTML report to file: %w", err)
-	}
-
 	return nil
 }
 
@@ -216,35 +334,126 @@ const htmlTemplate = `<!DOCTYPE html>
             background-color: var(--background-color);
         }
         
-        .histogram {
-            height: 200px;
-            background-color: #f5f5f5;
-            border: 1px solid #ddd;
+        .permalink {
+            color: var(--secondary-color);
+            text-decoration: none;
+            font-weight: normal;
+            visibility: hidden;
+        }
+        
+        li:hover > .permalink, h3:hover > .permalink, .permalink:focus {
+            visibility: visible;
+        }
+        
+        .column-card:target, li:target {
+            outline: 2px solid var(--primary-color);
+        }
+        
+        .preview {
+            overflow-x: auto;
+        }
+        
+        .preview td {
+            white-space: nowrap;
+        }
+        
+        .examples code {
+            background-color: var(--background-color);
             border-radius: 4px;
+            padding: 2px 4px;
+            word-break: break-all;
+        }
+        
+        .chart {
+            display: block;
+            margin-top: 10px;
+            overflow: visible;
+        }
+        
+        .chart text {
+            font-size: 11px;
+            fill: var(--secondary-color);
+        }
+        
+        .chart .axis, .chart .whisker {
+            stroke: var(--secondary-color);
+        }
+        
+        .chart .grid {
+            stroke: var(--border-color);
+        }
+        
+        .chart .bar {
+            fill: var(--primary-color);
+        }
+        
+        .chart .box {
+            fill: rgba(26, 115, 232, 0.25);
+            stroke: var(--primary-color);
+        }
+        
+        .chart .median {
+            stroke: var(--primary-color);
+            stroke-width: 3;
+        }
+        
+        .chart .mean {
+            fill: var(--warning-color);
+        }
+        
+        .chart .point {
+            fill: var(--error-color);
+        }
+        
+        .chart .whisker {
+            stroke-width: 2;
+        }
+        
+        .chart .bar:hover, .chart g:hover .bar {
+            fill: #3949ab;
+        }
+        
+        .chart .box:hover {
+            fill: rgba(26, 115, 232, 0.45);
+        }
+        
+        .heatmaps {
             display: flex;
+            flex-wrap: wrap;
             align-items: flex-end;
-            padding: 10px;
-            margin-top: 15px;
+            gap: 30px;
         }
         
-        .histogram-bar {
-            background-color: var(--primary-color);
-            margin-right: 2px;
-            flex: 1;
-            min-height: 1px;
-            transition: height 0.3s ease;
+        .heatmap {
+            max-width: 100%;
+            height: auto;
         }
         
-        .histogram-bar:hover {
-            background-color: #3949ab;
+        .heatmap .cell {
+            font-size: 9px;
+            fill: var(--text-color);
         }
         
-        .histogram-labels {
-            display: flex;
-            justify-content: space-between;
-            margin-top: 5px;
-            color: #666;
-            font-size: 0.8em;
+        .heatmap .cell-dark {
+            font-size: 9px;
+            fill: white;
+        }
+        
+        .heatmap g:hover rect {
+            stroke: var(--text-color);
+            stroke-width: 2;
+        }
+        
+        .chart-tooltip {
+            position: fixed;
+            z-index: 10;
+            pointer-events: none;
+            background-color: var(--text-color);
+            color: white;
+            padding: 4px 8px;
+            border-radius: 4px;
+            font-size: 0.85em;
+            max-width: 400px;
         }
         
         .quality-score {
@@ -291,6 +500,11 @@ const htmlTemplate = `<!DOCTYPE html>
             border-radius: 4px;
         }
         
+        .column-note {
+            color: var(--secondary-color);
+            font-style: italic;
+        }
+        
         .footer {
             text-align: center;
             margin-top: 40px;
@@ -336,7 +550,7 @@ const htmlTemplate = `<!DOCTYPE html>
     <div class="container">
         <header>
             <h1>DataSleuth Profile: {{.Profile.Filename}}</h1>
-            <p>Generated: {{.GeneratedAt}} | Size: {{formatNumber .FileSizeMB}} MB | Rows: {{formatNumber .Profile.RowCount}} | Columns: {{formatNumber .Profile.ColumnCount}}</p>
+            <p>Generated: {{.GeneratedAt}} | {{with .FileSize}}Size: {{.}} | {{end}}Rows: {{formatRowCount .Profile}} | Columns: {{formatNumber .Profile.ColumnCount}}</p>
         </header>
         
         <div class="summary-cards">
@@ -349,11 +563,40 @@ const htmlTemplate = `<!DOCTYPE html>
             
             <div class="card">
                 <h2>Dataset Summary</h2>
-                <p><strong>Rows:</strong> {{formatNumber .Profile.RowCount}}</p>
+                <p><strong>Rows:</strong> {{formatRowCount .Profile}}</p>
                 <p><strong>Columns:</strong> {{formatNumber .Profile.ColumnCount}}</p>
                 <p><strong>Missing cells:</strong> {{formatNumber .Profile.MissingCells}} ({{formatPercent (div .Profile.MissingCells (mul .Profile.RowCount .Profile.ColumnCount))}})</p>
-                <p><strong>Duplicate rows:</strong> {{formatNumber .Profile.DuplicateRows}} ({{formatPercent (div .Profile.DuplicateRows .Profile.RowCount)}})</p>
+                <p><strong>{{duplicatesLabel .Profile}}:</strong> {{formatNumber .Profile.DuplicateRows}} ({{formatPercent (div .Profile.DuplicateRows .Profile.RowCount)}})</p>
+                {{if .Profile.DuplicateGroups}}
+                <ul class="examples">
+                    {{range .Profile.DuplicateGroups}}
+                    <li><code>{{formatDuplicateGroup .}}</code></li>
+                    {{end}}
+                </ul>
+                {{else if .Profile.DuplicateKeys}}
+                <ul class="examples">
+                    {{range .Profile.DuplicateKeys}}
+                    <li><code>{{formatDuplicateKey $.Profile.UniqueKey .}}</code></li>
+                    {{end}}
+                </ul>
+                {{end}}
+                {{with .Profile.ParseBudget}}
+                <p><strong>Parse errors:</strong> {{formatNumber .Total}} ({{printf "%.2f" .Percent}}%)</p>
+                {{with parseBreakdown .}}
+                <ul class="examples">
+                    {{range .}}
+                    <li>{{.}}</li>
+                    {{end}}
+                </ul>
+                {{end}}
+                {{end}}
                 <p><strong>Processing Time:</strong> {{.Profile.ProcessingTime.Seconds}} seconds</p>
+                {{range .Profile.Notes}}
+                <p class="column-note">{{.}}</p>
+                {{end}}
+                {{if .Profile.ContentDigest}}
+                <p><strong>Content digest:</strong> <code>{{.Profile.ContentDigest}}</code></p>
+                {{end}}
             </div>
             
             <div class="card">
@@ -361,7 +604,7 @@ const htmlTemplate = `<!DOCTYPE html>
                 {{if .Issues}}
                 <ul class="issues-list">
                     {{range .Issues}}
-                    <li>{{.}}</li>
+                    <li id="{{.ID}}">{{if .Column}}<a href="#{{.ColumnID}}">Column '{{.Column}}'</a>: {{end}}{{.Description}} <a class="permalink" href="#{{.ID}}" title="Copy link to this issue">#</a></li>
                     {{end}}
                 </ul>
                 {{else}}
@@ -385,7 +628,19 @@ const htmlTemplate = `<!DOCTYPE html>
         {{if gt (len .Profile.CorrelationMatrix.TopPairs) 0}}
         <div class="card">
             <h2>Column Correlations</h2>
-            <p>Statistical relationships between numeric columns:</p>
+            <p>Statistical relationships between numeric columns, and associations between categorical ones, computed from {{if .Profile.CorrelationMatrix.Sampled}}a uniform sample of {{end}}{{.Profile.CorrelationMatrix.Rows}} rows:</p>
+            
+            {{with heatmaps .Profile.CorrelationMatrix}}
+            <div class="heatmaps">
+                {{range .}}
+                <div>
+                    <h3>{{.Title}}</h3>
+                    {{template "heatmap" .}}
+                </div>
+                {{end}}
+            </div>
+            <p class="column-note">Blue cells are positive and red ones negative: the darker the cell, the stronger the relationship.</p>
+            {{end}}
             
             <div class="correlation-grid">
                 {{range $pair := .Profile.CorrelationMatrix.TopPairs}}
@@ -393,6 +648,19 @@ const htmlTemplate = `<!DOCTYPE html>
                     <h3>
                         {{$pair.Column1}} & {{$pair.Column2}}
                     </h3>
+                    {{if eq $pair.Method "cramers_v"}}
+                    <p>
+                        Association (Cramér's V):
+                        {{if ge $pair.Correlation 0.7}}
+                        <span class="correlation-strong">Strong ({{formatNumber $pair.Correlation}})</span>
+                        {{else if ge $pair.Correlation 0.4}}
+                        <span class="correlation-moderate">Moderate ({{formatNumber $pair.Correlation}})</span>
+                        {{else}}
+                        <span class="correlation-weak">Weak ({{formatNumber $pair.Correlation}})</span>
+                        {{end}}
+                    </p>
+                    <p>Knowing the value of one column narrows down the value of the other.</p>
+                    {{else}}
                     <p>
                         Correlation: 
 							{{if ge (printf "%.1f" $pair.Correlation | parseFloat) 0.7}}
@@ -418,18 +686,134 @@ const htmlTemplate = `<!DOCTYPE html>
 						As one variable increases, the other tends to decrease.
 						{{end}}
                     </p>
+                    {{if $pair.Spearman}}
+                    <p>Spearman rank correlation: {{formatNumber $pair.Spearman}}</p>
+                    {{end}}
+                    {{end}}
                 </div>
                 {{end}}
             </div>
         </div>
         {{end}}
         {{end}}
+
+        {{if .Profile.Partitions}}
+        <div class="card">
+            <h2>Partitions</h2>
+            <div class="preview">
+                <table>
+                    <tr>
+                        <th>Partition</th>
+                        <th>Files</th>
+                        <th>Rows</th>
+                        <th>Missing</th>
+                        <th>Issue</th>
+                    </tr>
+                    {{range .Profile.Partitions}}
+                    <tr>
+                        <td><code>{{.Name}}</code></td>
+                        <td>{{.Files}}</td>
+                        <td>{{formatNumber .RowCount}}</td>
+                        <td>{{formatPercent (div .MissingCells (mul .RowCount $.Profile.ColumnCount))}}</td>
+                        <td>{{.Issue}}</td>
+                    </tr>
+                    {{end}}
+                </table>
+            </div>
+        </div>
+        {{end}}
+
+        {{with .Profile.TimeWindows}}
+        <div class="card">
+            <h2>Time Windows</h2>
+            <p>The {{windowTitle .}}, {{windowRange .}}.</p>
+            {{if and .LatestRows .PreviousRows}}
+            <div class="preview">
+                <table>
+                    <tr>
+                        <th>Column</th>
+                        <th>Missing</th>
+                        <th>Distinct</th>
+                        <th>Mean</th>
+                        <th>Regression</th>
+                    </tr>
+                    {{range .Columns}}
+                    <tr>
+                        <td><a href="#{{index $.ColumnIDs .Column}}">{{.Column}}</a></td>
+                        <td>{{formatWindowMissing .}}</td>
+                        <td>{{formatWindowDistinct .}}</td>
+                        <td>{{formatWindowMean .}}</td>
+                        <td>{{join .Changes ", "}}</td>
+                    </tr>
+                    {{end}}
+                </table>
+            </div>
+            {{end}}
+        </div>
+        {{end}}
+
+        {{if .Conversions}}
+        <div class="card">
+            <h2>Type Conversion Risk</h2>
+            <p>Values lost or truncated by casting each column to its inferred type, or a string column to the type most of its values cast to.</p>
+            <div class="preview">
+                <table>
+                    <tr>
+                        <th>Column</th>
+                        <th>Type</th>
+                        <th>Cast To</th>
+                        <th>Converted</th>
+                        <th>Lost</th>
+                        <th>Truncated</th>
+                        <th>At Risk</th>
+                    </tr>
+                    {{range .Conversions}}
+                    <tr>
+                        <td><a href="#{{index $.ColumnIDs .Name}}">{{.Name}}</a></td>
+                        <td>{{.DataType}}</td>
+                        <td>{{.Conversion.Type}}</td>
+                        <td>{{formatNumber .Conversion.Converted}}</td>
+                        <td>{{formatNumber .Conversion.Lost}}</td>
+                        <td>{{formatNumber .Conversion.Truncated}}</td>
+                        <td>{{printf "%.2f%%" .Conversion.AtRiskPercent}}</td>
+                    </tr>
+                    {{end}}
+                </table>
+            </div>
+        </div>
+        {{end}}
+
+        {{with .Profile.Preview}}
+        {{if .Rows}}
+        <div class="card">
+            <h2>Data Preview</h2>
+            <p>First {{len .Rows}} rows:</p>
+            <div class="preview">
+                <table>
+                    <tr>
+                        {{range .Columns}}
+                        <th>{{.}}</th>
+                        {{end}}
+                    </tr>
+                    {{range .Rows}}
+                    <tr>
+                        {{range .}}
+                        <td>{{.}}</td>
+                        {{end}}
+                    </tr>
+                    {{end}}
+                </table>
+            </div>
+        </div>
+        {{end}}
+        {{end}}
         
         <h2>Column Details</h2>
         <div class="column-grid">
             {{range $name, $col := .Profile.Columns}}
-            <div class="column-card">
-                <h3>{{$name}} <small>({{$col.DataType}})</small></h3>
+            {{$id := index $.ColumnIDs $name}}
+            <div class="column-card" id="{{$id}}">
+                <h3>{{$name}} <small>({{$col.DataType}})</small> <a class="permalink" href="#{{$id}}" title="Copy link to this column">#</a></h3>
                 
                 <table>
                     <tr>
@@ -448,6 +832,24 @@ const htmlTemplate = `<!DOCTYPE html>
                         <td>Unique</td>
                         <td>{{formatNumber $col.UniqueCount}} ({{formatPercent (div $col.UniqueCount $col.Count)}})</td>
                     </tr>
+                    {{with $col.Rare}}{{if and .Singletons (not $col.IsUnique)}}
+                    <tr>
+                        <td>Rare</td>
+                        <td>{{.}}</td>
+                    </tr>
+                    {{end}}{{end}}
+                    {{if $col.Nullability}}
+                    <tr>
+                        <td>Nullability</td>
+                        <td>{{$col.Nullability}}</td>
+                    </tr>
+                    {{end}}
+                    {{if $col.Conversion}}
+                    <tr>
+                        <td>Cast</td>
+                        <td>{{$col.Conversion}}</td>
+                    </tr>
+                    {{end}}
                     {{if $col.IsNumeric}}
                     <tr>
                         <td>Min</td>
@@ -469,39 +871,169 @@ const htmlTemplate = `<!DOCTYPE html>
                         <td>Std Dev</td>
                         <td>{{formatNumber $col.StdDev}}</td>
                     </tr>
+                    {{with $col.Robust}}
+                    <tr>
+                        <td>Trimmed Mean</td>
+                        <td>{{formatNumber .TrimmedMean}}</td>
+                    </tr>
+                    <tr>
+                        <td>Winsorized Std Dev</td>
+                        <td>{{formatNumber .WinsorizedStdDev}}</td>
+                    </tr>
+                    <tr>
+                        <td>MAD</td>
+                        <td>{{formatNumber .MAD}}</td>
+                    </tr>
+                    {{end}}
+                    {{range $p := $col.Percentiles}}
+                    <tr>
+                        <td>P{{$p.Rank}}</td>
+                        <td>{{formatNumber $p.Value}}</td>
+                    </tr>
+                    {{end}}
+                    <tr>
+                        <td>Skewness</td>
+                        <td>{{formatNumber $col.Skewness}}</td>
+                    </tr>
+                    <tr>
+                        <td>Kurtosis</td>
+                        <td>{{formatNumber $col.Kurtosis}}</td>
+                    </tr>
+                    {{if $col.Mode}}
+                    <tr>
+                        <td>Mode</td>
+                        <td>{{formatNumber $col.Mode}}</td>
+                    </tr>
+                    {{end}}
+                    {{if $col.NumberFormat}}
+                    <tr>
+                        <td>Number Format</td>
+                        <td>{{$col.NumberFormat}}</td>
+                    </tr>
+                    {{end}}
+                    {{end}}
+                    {{if $col.IsOpaque}}
+                    <tr>
+                        <td>Avg Size</td>
+                        <td>{{formatBytes $col.AvgLength}}</td>
+                    </tr>
+                    {{end}}
+                    {{with $col.Text}}
+                    <tr>
+                        <td>Length</td>
+                        <td>{{formatTextLengths .}}</td>
+                    </tr>
+                    {{if .WhitespaceOnly}}
+                    <tr>
+                        <td>Whitespace-only</td>
+                        <td>{{formatNumber .WhitespaceOnly}}</td>
+                    </tr>
+                    {{end}}
+                    {{if .Formatting}}
+                    <tr>
+                        <td>Formatting</td>
+                        <td>{{formatFormatting .}}</td>
+                    </tr>
+                    {{end}}
+                    <tr>
+                        <td>Casing</td>
+                        <td>{{formatCasing .}}</td>
+                    </tr>
+                    <tr>
+                        <td>Patterns</td>
+                        <td>{{formatPatterns . $col.Count}}</td>
+                    </tr>
+                    {{end}}
+                    {{with $col.DateTime}}
+                    <tr>
+                        <td>Min</td>
+                        <td>{{.Format .Min}}</td>
+                    </tr>
+                    <tr>
+                        <td>Max</td>
+                        <td>{{.Format .Max}}</td>
+                    </tr>
+                    <tr>
+                        <td>Span</td>
+                        <td>{{formatSpan .Span}}</td>
+                    </tr>
+                    <tr>
+                        <td>Granularity</td>
+                        <td>{{.Granularity}}</td>
+                    </tr>
+                    {{if $col.DateFormat}}
+                    <tr>
+                        <td>Date Format</td>
+                        <td>{{$col.DateFormat}}</td>
+                    </tr>
+                    {{end}}
+                    {{if $col.DateOrder}}
+                    <tr>
+                        <td>Date Order</td>
+                        <td>{{$col.DateOrder}}</td>
+                    </tr>
+                    {{end}}
+                    {{if .LargestGap}}
+                    <tr>
+                        <td>Gaps</td>
+                        <td>{{.Gaps}} ({{.MissingPeriods}} missing periods, largest {{.Format .LargestGap.From}} to {{.Format .LargestGap.To}})</td>
+                    </tr>
+                    {{end}}
                     {{end}}
                 </table>
                 
+                {{range $note := $col.Notes}}
+                <p class="column-note">{{$note}}</p>
+                {{end}}
+                
                 {{if $col.IsNumeric}}
-                <div class="histogram">
-                    {{$maxCount := 0}}
-                    {{range $bucket := $col.HistogramBuckets}}
-                        {{if gt $bucket.Count $maxCount}}
-                            {{$maxCount = $bucket.Count}}
-                        {{end}}
-                    {{end}}
-                    
-                    {{range $bucket := $col.HistogramBuckets}}
-                        {{$height := 0}}
-                        {{if gt $maxCount 0}}
-                            {{$height = div (mul $bucket.Count 100) $maxCount}}
-                        {{end}}
-                        <div class="histogram-bar" style="height: {{$height}}%;" title="{{formatNumber $bucket.LowerBound}} - {{formatNumber $bucket.UpperBound}}: {{$bucket.Count}}"></div>
-                    {{end}}
-                </div>
-                <div class="histogram-labels">
-                    <span>{{formatNumber (index $col.HistogramBuckets 0).LowerBound}}</span>
-                    <span style="float: right;">{{formatNumber (index $col.HistogramBuckets (sub (len $col.HistogramBuckets) 1)).UpperBound}}</span>
-                </div>
-                {{else if $col.IsCategorical}}
+                {{with histogramChart $col}}
+                <h4>Histogram{{with or $col.HistogramBinning $.Profile.HistogramBinning}} ({{.}}){{end}}:</h4>
+                {{template "barChart" .}}
+                {{end}}
+                {{with boxPlot $col}}
+                <h4>Box Plot:</h4>
+                {{template "boxPlot" .}}
+                {{end}}
+                {{else if $col.DateTime}}
+                {{with timelineChart $col.DateTime}}
+                <h4>Timeline:</h4>
+                {{template "barChart" .}}
+                {{end}}
+                {{else if and $.Profile.Exact $col.TopValues}}
+                <h4>Frequencies:</h4>
+                {{template "valueChart" (valueChart $col.TopValues $col.Count)}}
+                {{else if and $col.TopValues (or $col.IsCategorical (not $col.IsUnique))}}
                 <h4>Top Values:</h4>
+                {{template "valueChart" (valueChart $col.TopValues $col.Count)}}
+                {{end}}
+
+                {{if and (or $col.IsNumeric $col.DateTime) $col.TopValues (not $col.IsUnique) (not $.Profile.Exact)}}
+                <h4>Top Values:</h4>
+                {{template "valueChart" (valueChart $col.TopValues $col.Count)}}
+                {{end}}
+
+                {{if and $col.BottomValues (not $col.IsUnique)}}
+                <h4>Bottom Values:</h4>
                 <ul>
-                    {{range $val := $col.TopValues}}
+                    {{range $val := $col.BottomValues}}
                     <li>{{$val.Value}}: {{formatNumber $val.Count}} ({{formatPercent (div $val.Count $col.Count)}})</li>
                     {{end}}
                 </ul>
                 {{end}}
                 
+                {{if $col.ExamplesRedacted}}
+                <h4>Examples:</h4>
+                <p class="column-note">Redacted</p>
+                {{else if $col.Examples}}
+                <h4>Examples:</h4>
+                <ul class="examples">
+                    {{range $example := $col.Examples}}
+                    <li><code>{{$example}}</code></li>
+                    {{end}}
+                </ul>
+                {{end}}
+                
                 {{if $col.QualityIssues}}
                 <h4>Quality Issues:</h4>
                 <ul class="issues-list">
@@ -518,5 +1050,45 @@ const htmlTemplate = `<!DOCTYPE html>
             <p>Generated by DataSleuth v0.1.0 - Fast dataset profiling and validation from the command line</p>
         </div>
     </div>
+    <div id="chart-tooltip" class="chart-tooltip" hidden></div>
+    <script>
+        // Show the tooltip of a chart element as soon as the pointer is over
+        // it, rather than the delayed tooltip of its title
+        var tooltip = document.getElementById('chart-tooltip');
+        document.querySelectorAll('svg.chart title').forEach(function (title) {
+            title.parentNode.setAttribute('data-tip', title.textContent);
+            title.remove();
+        });
+        document.addEventListener('mousemove', function (event) {
+            var target = event.target.closest && event.target.closest('[data-tip]');
+            if (!target) {
+                tooltip.hidden = true;
+                return;
+            }
+            tooltip.textContent = target.getAttribute('data-tip');
+            tooltip.hidden = false;
+            var x = event.clientX + 12, y = event.clientY + 12;
+            if (x + tooltip.offsetWidth > window.innerWidth) {
+                x = event.clientX - tooltip.offsetWidth - 12;
+            }
+            if (y + tooltip.offsetHeight > window.innerHeight) {
+                y = event.clientY - tooltip.offsetHeight - 12;
+            }
+            tooltip.style.left = x + 'px';
+            tooltip.style.top = y + 'px';
+        });
+
+
+        // Copy the full link of an anchor while following it
+        document.querySelectorAll('.permalink').forEach(function (link) {
+            link.addEventListener('click', function () {
+                var url = location.href.split('#')[0] + link.getAttribute('href');
+                if (navigator.clipboard) {
+                    navigator.clipboard.writeText(url);
+                    link.title = 'Link copied';
+                }
+            });
+        });
+    </script>
 </body>
 </html>`
@@ -2,13 +2,18 @@ package report
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
 )
 
 func TestGenerateHTMLReport(t *testing.T) {
 	profile := createTestProfile()
+	profile.Preview = &profiler.Preview{Columns: []string{"test_str"}, Rows: [][]string{{"<b>value1</b>"}}}
+	profile.Columns["test_str"].Conversion = &profiler.Conversion{Type: "integer", Converted: 960, Lost: 20}
 
 	tempFile, err := os.CreateTemp("", "report_*.html")
 	if err != nil {
@@ -42,6 +47,24 @@ func TestGenerateHTMLReport(t *testing.T) {
 		"Duplicate rows:",
 		"Quality Issues",
 		"Recommendations",
+		"<td>P99</td>",
+		"<h4>Box Plot:</h4>",
+		"<title>P25 26, median 50, P75 75 (IQR 49)</title>",
+		"<title>value1: 200 (20.41%)</title>",
+		"<td>6 to 7 (avg 6.0)</td>",
+		"<td>lower (100.0%)</td>",
+		"<td>AAAAA9 (96.9%), AAAAA99 (3.1%)</td>",
+		"<h2>Data Preview</h2>",
+		"<h2>Type Conversion Risk</h2>",
+		`<td><a href="#column-test-str">test_str</a></td>`,
+		"<td>2.04%</td>",
+		"<td>casting to integer loses 20 values (2.04%)</td>",
+		`<div class="column-card" id="column-test-str">`,
+		`<li id="issue-test-str-missing-values"><a href="#column-test-str">Column 'test_str'</a>: Missing values: 2.00%`,
+		`<li id="issue-high-missing-values">High overall missing value rate: 5.00%`,
+		`<a class="permalink" href="#column-test-int"`,
+		"<th>test_str</th>",
+		"<td>&lt;b&gt;value1&lt;/b&gt;</td>",
 	}
 
 	for _, expected := range expectedStrings {
@@ -50,11 +73,96 @@ func TestGenerateHTMLReport(t *testing.T) {
 		}
 	}
 
-	if !strings.Contains(htmlContent, "<div class=\"histogram-bar\"") {
+	if !strings.Contains(htmlContent, "<rect class=\"bar\"") {
 		t.Error("Expected HTML to contain histogram bars")
 	}
 }
 
+func TestGenerateHTMLReportDateTime(t *testing.T) {
+	profile := createTestProfile()
+	addTestDateColumn(profile)
+
+	path := filepath.Join(t.TempDir(), "report.html")
+	if err := GenerateHTMLReport(profile, path); err != nil {
+		t.Fatalf("GenerateHTMLReport failed: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read report file: %v", err)
+	}
+
+	for _, expected := range []string{
+		"<td>30 days</td>",
+		"<h4>Timeline:</h4>",
+		"<title>2024-01-16 - 2024-01-31: 550 (55.00%)</title>",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected HTML to contain %q", expected)
+		}
+	}
+}
+
+func TestHTMLAnchors(t *testing.T) {
+	profile := &profiler.DatasetProfile{
+		QualityIssues: []profiler.QualityIssue{{Type: "duplicate_rows", Description: "Duplicates"}},
+		Columns: map[string]*profiler.ColumnProfile{
+			"Order ID": {QualityIssues: []profiler.QualityIssue{{Type: "likely_id"}, {Type: "likely_id"}}},
+			"order-id": {},
+			"数量":       {},
+			"!!":       {},
+		},
+	}
+
+	columnIDs, issues := htmlAnchors(profile)
+
+	wantColumns := map[string]string{
+		"!!":       "column-x",
+		"Order ID": "column-order-id",
+		"order-id": "column-order-id-2",
+		"数量":       "column-数量",
+	}
+	for name, want := range wantColumns {
+		if got := columnIDs[name]; got != want {
+			t.Errorf("Column %q: expected anchor %q, got %q", name, want, got)
+		}
+	}
+
+	wantIssues := []string{"issue-duplicate-rows", "issue-order-id-likely-id", "issue-order-id-likely-id-2"}
+	if len(issues) != len(wantIssues) {
+		t.Fatalf("Expected %d issues, got %v", len(wantIssues), issues)
+	}
+	for i, want := range wantIssues {
+		if issues[i].ID != want {
+			t.Errorf("Issue %d: expected anchor %q, got %q", i, want, issues[i].ID)
+		}
+	}
+	if issues[1].Column != "Order ID" || issues[1].ColumnID != "column-order-id" {
+		t.Errorf("Expected the issue to link to its column card, got %+v", issues[1])
+	}
+}
+
+func TestGenerateHTMLReportExamples(t *testing.T) {
+	profile := createTestProfile()
+	profile.Columns["test_str"].Examples = []string{"<b>raw</b>", "value9"}
+	profile.Columns["test_int"].ExamplesRedacted = true
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	if err := GenerateHTMLReport(profile, outputPath); err != nil {
+		t.Fatalf("GenerateHTMLReport failed: %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read report file: %v", err)
+	}
+
+	for _, expected := range []string{"<code>&lt;b&gt;raw&lt;/b&gt;</code>", "<code>value9</code>", "Redacted"} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected HTML to contain '%s'", expected)
+		}
+	}
+}
+
 func TestFormatNumberHTML(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -3,44 +3,758 @@ package report
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"time"
 
 	"github.com/kamalm96/datasleuth/internal/profiler"
 )
 
 type JSONReport struct {
-	Filename        string                      `json:"filename"`
-	FileSize        int64                       `json:"file_size_bytes"`
-	Format          string                      `json:"format"`
-	RowCount        int                         `json:"row_count"`
-	ColumnCount     int                         `json:"column_count"`
-	MissingCells    int                         `json:"missing_cells"`
-	DuplicateRows   int                         `json:"duplicate_rows"`
-	QualityScore    int                         `json:"quality_score"`
-	QualityIssues   []string                    `json:"quality_issues"`
-	Recommendations []string                    `json:"recommendations"`
-	Columns         map[string]JSONColumnReport `json:"columns"`
-	ProcessingTime  float64                     `json:"processing_time_seconds"`
-	GeneratedAt     string                      `json:"generated_at"`
+	Filename         string                      `json:"filename"`
+	FileSize         int64                       `json:"file_size_bytes,omitempty"`
+	Compression      string                      `json:"compression,omitempty"`
+	Uncompressed     int64                       `json:"uncompressed_size_bytes,omitempty"`
+	Encoding         string                      `json:"encoding,omitempty"`
+	Format           string                      `json:"format"`
+	Table            string                      `json:"table,omitempty"`
+	Sheet            string                      `json:"sheet,omitempty"`
+	RowCount         int                         `json:"row_count"`
+	ColumnCount      int                         `json:"column_count"`
+	MissingCells     int                         `json:"missing_cells"`
+	DuplicateRows    int                         `json:"duplicate_rows"`
+	ParseErrors      JSONParseBudget             `json:"parse_errors"`
+	Interrupted      bool                        `json:"interrupted,omitempty"` // profiling stopped early; the statistics cover only the rows read
+	DuplicateGroups  []JSONDuplicateGroup        `json:"duplicate_groups,omitempty"`
+	UniqueKey        []string                    `json:"unique_key,omitempty"`
+	DuplicateKeys    []JSONDuplicateKey          `json:"duplicate_keys,omitempty"`
+	RedundantColumns []JSONRedundantPair         `json:"redundant_columns,omitempty"`
+	Partitions       []JSONPartition             `json:"partitions,omitempty"`
+	TimeWindows      *JSONTimeWindows            `json:"time_windows,omitempty"`
+	Correlations     *JSONCorrelations           `json:"correlations,omitempty"`
+	Exact            bool                        `json:"exact,omitempty"`
+	HistogramBinning string                      `json:"histogram_binning,omitempty"`
+	Preview          *JSONPreview                `json:"preview,omitempty"`
+	KAnonymity       int                         `json:"k_anonymity,omitempty"`
+	QualityScore     int                         `json:"quality_score"`
+	QualityIssues    []string                    `json:"quality_issues"`
+	Recommendations  []JSONRecommendation        `json:"recommendations"`
+	Columns          map[string]JSONColumnReport `json:"columns"`
+	ColumnFiles      []JSONColumnFile            `json:"column_files,omitempty"` // split report, columns stored in these files
+	ContentDigest    string                      `json:"content_digest,omitempty"`
+	Sample           *JSONSample                 `json:"sample,omitempty"`
+	Weights          *JSONWeights                `json:"weights,omitempty"`
+	Thresholds       JSONThresholds              `json:"thresholds"`
+	Notes            []string                    `json:"notes,omitempty"`
+	ProcessingTime   float64                     `json:"processing_time_seconds"`
+	GeneratedAt      string                      `json:"generated_at"`
+}
+
+// JSONDuplicateGroup is a set of identical rows, listed in exact mode.
+type JSONDuplicateGroup struct {
+	Rows   []int             `json:"rows"`
+	Values map[string]string `json:"values"`
+}
+
+// JSONDuplicateKey is a value of the unique key shared by several rows,
+// with its values in the order of the key columns.
+type JSONDuplicateKey struct {
+	Values []string `json:"values"`
+	Rows   int      `json:"rows"`
+}
+
+// JSONRedundantPair is two columns whose values match on almost every row.
+type JSONRedundantPair struct {
+	Column1      string  `json:"column1"`
+	Column2      string  `json:"column2"`
+	MatchPercent float64 `json:"match_percent"`
+}
+
+// JSONPartition is the row count and completeness of a partition of a
+// Hive-partitioned dataset, with what sets it apart from the others.
+type JSONPartition struct {
+	Name         string `json:"name"`
+	Files        int    `json:"files"`
+	Size         int64  `json:"size_bytes"`
+	RowCount     int    `json:"row_count"`
+	MissingCells int    `json:"missing_cells"`
+	Issue        string `json:"issue,omitempty"`
+}
+
+// JSONTimeWindows compares the latest window of a time column with the
+// window before it.
+type JSONTimeWindows struct {
+	TimeColumn    string            `json:"time_column"`
+	Window        string            `json:"window"`
+	LatestStart   string            `json:"latest_start,omitempty"`
+	LatestEnd     string            `json:"latest_end,omitempty"`
+	PreviousStart string            `json:"previous_start,omitempty"`
+	LatestRows    int               `json:"latest_rows"`
+	PreviousRows  int               `json:"previous_rows"`
+	Skipped       int               `json:"skipped_rows,omitempty"`
+	Columns       []JSONWindowDelta `json:"columns"`
+}
+
+// JSONWindowDelta is a column in the latest and previous windows, with what
+// regressed between them.
+type JSONWindowDelta struct {
+	Column    string          `json:"column"`
+	Numeric   bool            `json:"numeric,omitempty"`
+	Latest    JSONWindowStats `json:"latest"`
+	Previous  JSONWindowStats `json:"previous"`
+	MeanShift float64         `json:"mean_shift,omitempty"`
+	Changes   []string        `json:"changes,omitempty"`
+}
+
+// JSONWindowStats are the statistics of a column within one window.
+type JSONWindowStats struct {
+	Count          int     `json:"count"`
+	MissingPercent float64 `json:"missing_percent"`
+	Distinct       int     `json:"distinct"`
+	Mean           float64 `json:"mean,omitempty"`
+	StdDev         float64 `json:"std_dev,omitempty"`
+	Min            float64 `json:"min,omitempty"`
+	Max            float64 `json:"max,omitempty"`
+}
+
+func newJSONTimeWindows(w *profiler.TimeWindows) *JSONTimeWindows {
+	if w == nil {
+		return nil
+	}
+	j := &JSONTimeWindows{
+		TimeColumn:    w.TimeColumn,
+		Window:        w.Window.String(),
+		LatestStart:   formatJSONTime(w.LatestStart),
+		LatestEnd:     formatJSONTime(w.LatestEnd),
+		PreviousStart: formatJSONTime(w.PreviousStart),
+		LatestRows:    w.LatestRows,
+		PreviousRows:  w.PreviousRows,
+		Skipped:       w.Skipped,
+		Columns:       make([]JSONWindowDelta, 0, len(w.Columns)),
+	}
+	for _, d := range w.Columns {
+		j.Columns = append(j.Columns, JSONWindowDelta{
+			Column:    d.Column,
+			Numeric:   d.Numeric,
+			Latest:    JSONWindowStats(d.Latest),
+			Previous:  JSONWindowStats(d.Previous),
+			MeanShift: d.MeanShift(),
+			Changes:   d.Changes,
+		})
+	}
+	return j
+}
+
+func (j *JSONTimeWindows) toTimeWindows() *profiler.TimeWindows {
+	if j == nil {
+		return nil
+	}
+	window, _ := time.ParseDuration(j.Window)
+	w := &profiler.TimeWindows{
+		TimeColumn:    j.TimeColumn,
+		Window:        window,
+		LatestStart:   parseJSONTime(j.LatestStart),
+		LatestEnd:     parseJSONTime(j.LatestEnd),
+		PreviousStart: parseJSONTime(j.PreviousStart),
+		LatestRows:    j.LatestRows,
+		PreviousRows:  j.PreviousRows,
+		Skipped:       j.Skipped,
+		Columns:       make([]profiler.WindowDelta, 0, len(j.Columns)),
+	}
+	for _, d := range j.Columns {
+		w.Columns = append(w.Columns, profiler.WindowDelta{
+			Column:   d.Column,
+			Numeric:  d.Numeric,
+			Latest:   profiler.WindowStats(d.Latest),
+			Previous: profiler.WindowStats(d.Previous),
+			Changes:  d.Changes,
+		})
+	}
+	return w
+}
+
+func formatJSONTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func parseJSONTime(value string) time.Time {
+	t, _ := time.Parse(time.RFC3339, value)
+	return t
+}
+
+// JSONCorrelations are the strongest correlations between columns, with the
+// rows they were computed from.
+type JSONCorrelations struct {
+	Rows    int                   `json:"rows"`
+	Sampled bool                  `json:"sampled,omitempty"`
+	Pairs   []JSONCorrelationPair `json:"pairs"`
+}
+
+// JSONCorrelationPair is the correlation of two numeric columns, or the
+// association of two categorical ones.
+type JSONCorrelationPair struct {
+	Column1     string  `json:"column1"`
+	Column2     string  `json:"column2"`
+	Method      string  `json:"method"`
+	Correlation float64 `json:"correlation"`
+	Spearman    float64 `json:"spearman,omitempty"`
+}
+
+// JSONRecommendation is an action suggested by a recommendation rule.
+type JSONRecommendation struct {
+	Type    string   `json:"type"`
+	Columns []string `json:"columns,omitempty"`
+	Action  string   `json:"action"`
+	Message string   `json:"message"`
+}
+
+// UnmarshalJSON also reads the plain messages of reports written by older
+// versions.
+func (j *JSONRecommendation) UnmarshalJSON(data []byte) error {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		*j = JSONRecommendation{Message: message}
+		return nil
+	}
+
+	type plain JSONRecommendation
+	return json.Unmarshal(data, (*plain)(j))
+}
+
+func newJSONRecommendations(recommendations []profiler.Recommendation) []JSONRecommendation {
+	result := make([]JSONRecommendation, len(recommendations))
+	for i, r := range recommendations {
+		result[i] = JSONRecommendation(r)
+	}
+	return result
+}
+
+// JSONPreview is the first rows of the dataset, one value per column.
+type JSONPreview struct {
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows"`
 }
 
 type JSONColumnReport struct {
-	Name           string      `json:"name"`
-	DataType       string      `json:"data_type"`
-	Count          int         `json:"count"`
-	MissingCount   int         `json:"missing_count"`
-	MissingPercent float64     `json:"missing_percent"`
-	UniqueCount    int         `json:"unique_count"`
-	UniquePercent  float64     `json:"unique_percent"`
-	Min            interface{} `json:"min,omitempty"`
-	Max            interface{} `json:"max,omitempty"`
-	Mean           float64     `json:"mean,omitempty"`
-	Median         float64     `json:"median,omitempty"`
-	StdDev         float64     `json:"std_dev,omitempty"`
-	TopValues      []TopValue  `json:"top_values,omitempty"`
-	Histogram      []Bucket    `json:"histogram,omitempty"`
-	QualityIssues  []string    `json:"quality_issues"`
+	Name           string             `json:"name"`
+	Position       int                `json:"position"`
+	DataType       string             `json:"data_type"`
+	Count          int                `json:"count"`
+	MissingCount   int                `json:"missing_count"`
+	MissingPercent float64            `json:"missing_percent"`
+	UniqueCount    int                `json:"unique_count"`
+	UniquePercent  float64            `json:"unique_percent"`
+	Min            interface{}        `json:"min,omitempty"`
+	Max            interface{}        `json:"max,omitempty"`
+	Mean           float64            `json:"mean,omitempty"`
+	Median         float64            `json:"median,omitempty"`
+	StdDev         float64            `json:"std_dev,omitempty"`
+	Percentiles    map[string]float64 `json:"percentiles,omitempty"`
+	Robust         *JSONRobust        `json:"robust,omitempty"`
+	Outliers       *JSONOutliers      `json:"outliers,omitempty"`
+	Skewness       float64            `json:"skewness,omitempty"`
+	Kurtosis       float64            `json:"kurtosis,omitempty"`
+	Mode           interface{}        `json:"mode,omitempty"`
+	NumberFormat   string             `json:"number_format,omitempty"`
+	DateOrder      string             `json:"date_order,omitempty"`
+	DateFormat     string             `json:"date_format,omitempty"`
+	Conversion     *JSONConversion    `json:"conversion,omitempty"`
+	ParseErrors    *JSONParseErrors   `json:"parse_errors,omitempty"`
+	MixedTypes     *JSONMixedTypes    `json:"mixed_types,omitempty"`
+	TopValues      []TopValue         `json:"top_values,omitempty"`
+	BottomValues   []TopValue         `json:"bottom_values,omitempty"` // least frequent first
+	RareValues     *JSONRareValues    `json:"rare_values,omitempty"`
+	Suppressed     int                `json:"suppressed_values,omitempty"`
+	Examples       []string           `json:"examples,omitempty"`
+	Redacted       bool               `json:"examples_redacted,omitempty"`
+	Binning        string             `json:"histogram_binning,omitempty"` // equal-width, equal-frequency or log
+	Histogram      []Bucket           `json:"histogram,omitempty"`
+	DateTime       *JSONDateTime      `json:"datetime,omitempty"`
+	Text           *JSONText          `json:"text,omitempty"`
+	IsOpaque       bool               `json:"is_opaque,omitempty"`
+	AvgLength      float64            `json:"avg_length,omitempty"`
+	MaxLength      int                `json:"max_length,omitempty"`
+	Digest         string             `json:"digest,omitempty"`
+	Nullability    *JSONNullability   `json:"nullability,omitempty"`
+	QualityIssues  []string           `json:"quality_issues"`
+	Notes          []string           `json:"notes,omitempty"`
+}
+
+// JSONNullability is the inferred null contract of a column. Condition is
+// set for kind conditional.
+type JSONNullability struct {
+	Kind       string             `json:"kind"`
+	Confidence string             `json:"confidence"`
+	Condition  *JSONNullCondition `json:"condition,omitempty"`
+}
+
+type JSONNullCondition struct {
+	Column      string   `json:"column"`
+	Values      []string `json:"values"`
+	NullPercent float64  `json:"null_percent"`
+}
+
+func newJSONNullability(n *profiler.Nullability) *JSONNullability {
+	if n == nil {
+		return nil
+	}
+	j := &JSONNullability{Kind: n.Kind, Confidence: n.Confidence}
+	if n.Condition != nil {
+		j.Condition = &JSONNullCondition{Column: n.Condition.Column, Values: n.Condition.Values, NullPercent: n.Condition.NullPercent}
+	}
+	return j
+}
+
+func (j *JSONNullability) toNullability() *profiler.Nullability {
+	if j == nil {
+		return nil
+	}
+	n := &profiler.Nullability{Kind: j.Kind, Confidence: j.Confidence}
+	if j.Condition != nil {
+		n.Condition = &profiler.NullCondition{Column: j.Condition.Column, Values: j.Condition.Values, NullPercent: j.Condition.NullPercent}
+	}
+	return n
+}
+
+// JSONConversion holds the simulated cast of a column to a stricter type.
+type JSONConversion struct {
+	Type          string  `json:"type"`
+	Converted     int     `json:"converted"`
+	Lost          int     `json:"lost"`
+	Truncated     int     `json:"truncated"`
+	AtRiskPercent float64 `json:"at_risk_percent"`
+}
+
+func newJSONConversion(c *profiler.Conversion) *JSONConversion {
+	if c == nil {
+		return nil
+	}
+	return &JSONConversion{Type: c.Type, Converted: c.Converted, Lost: c.Lost, Truncated: c.Truncated, AtRiskPercent: c.AtRiskPercent()}
+}
+
+func (j *JSONConversion) toConversion() *profiler.Conversion {
+	if j == nil {
+		return nil
+	}
+	return &profiler.Conversion{Type: j.Type, Converted: j.Converted, Lost: j.Lost, Truncated: j.Truncated}
+}
+
+// JSONMixedTypes breaks a column of mixed types down by type.
+type JSONMixedTypes struct {
+	Types    []JSONTypeCount `json:"types"`
+	Examples []string        `json:"examples,omitempty"`
+}
+
+type JSONTypeCount struct {
+	Type    string  `json:"type"`
+	Count   int     `json:"count"`
+	Percent float64 `json:"percent"`
+}
+
+func newJSONMixedTypes(m *profiler.MixedTypes, count int) *JSONMixedTypes {
+	if m == nil {
+		return nil
+	}
+	j := &JSONMixedTypes{Types: make([]JSONTypeCount, len(m.Types)), Examples: m.Examples}
+	for i, t := range m.Types {
+		j.Types[i] = JSONTypeCount{Type: t.Type, Count: t.Count, Percent: t.Percent(count)}
+	}
+	return j
+}
+
+func (j *JSONMixedTypes) toMixedTypes() *profiler.MixedTypes {
+	if j == nil {
+		return nil
+	}
+	m := &profiler.MixedTypes{Types: make([]profiler.TypeCount, len(j.Types)), Examples: j.Examples}
+	for i, t := range j.Types {
+		m.Types[i] = profiler.TypeCount{Type: t.Type, Count: t.Count}
+	}
+	return m
+}
+
+// JSONRobust holds the robust statistics of a numeric column. Trim is the
+// fraction of values trimmed or winsorized at each end.
+type JSONRobust struct {
+	Trim             float64 `json:"trim"`
+	TrimmedMean      float64 `json:"trimmed_mean"`
+	WinsorizedStdDev float64 `json:"winsorized_std_dev"`
+	MAD              float64 `json:"mad"`
+}
+
+func newJSONRobust(r *profiler.RobustStats) *JSONRobust {
+	if r == nil {
+		return nil
+	}
+	return &JSONRobust{Trim: r.Trim, TrimmedMean: r.TrimmedMean, WinsorizedStdDev: r.WinsorizedStdDev, MAD: r.MAD}
+}
+
+func (j *JSONRobust) toRobustStats() *profiler.RobustStats {
+	if j == nil {
+		return nil
+	}
+	return &profiler.RobustStats{Trim: j.Trim, TrimmedMean: j.TrimmedMean, WinsorizedStdDev: j.WinsorizedStdDev, MAD: j.MAD}
+}
+
+// JSONOutliers holds the bounds outside which values of a numeric column
+// are outliers, with the most extreme of them.
+type JSONOutliers struct {
+	Method    string    `json:"method"`
+	Threshold float64   `json:"threshold"`
+	Lower     float64   `json:"lower"`
+	Upper     float64   `json:"upper"`
+	Count     int       `json:"count"`
+	Examples  []float64 `json:"examples,omitempty"`
+}
+
+func newJSONOutliers(o *profiler.OutlierSummary) *JSONOutliers {
+	if o == nil {
+		return nil
+	}
+	return &JSONOutliers{Method: o.Method, Threshold: o.Threshold, Lower: o.Lower, Upper: o.Upper, Count: o.Count, Examples: o.Examples}
+}
+
+func (j *JSONOutliers) toOutlierSummary() *profiler.OutlierSummary {
+	if j == nil {
+		return nil
+	}
+	return &profiler.OutlierSummary{Method: j.Method, Threshold: j.Threshold, Lower: j.Lower, Upper: j.Upper, Count: j.Count, Examples: j.Examples}
+}
+
+// JSONRareValues counts the values of a column seen only once.
+type JSONRareValues struct {
+	Singletons        int     `json:"singletons"`
+	PercentOfDistinct float64 `json:"percent_of_distinct"`
+}
+
+func newJSONRareValues(r *profiler.RareValues) *JSONRareValues {
+	if r == nil {
+		return nil
+	}
+	return &JSONRareValues{Singletons: r.Singletons, PercentOfDistinct: r.Percent}
+}
+
+func (j *JSONRareValues) toRareValues() *profiler.RareValues {
+	if j == nil {
+		return nil
+	}
+	return &profiler.RareValues{Singletons: j.Singletons, Percent: j.PercentOfDistinct}
+}
+
+// JSONDateTime holds the statistics of a datetime column. Times are UTC.
+type JSONDateTime struct {
+	Min            time.Time        `json:"min"`
+	Max            time.Time        `json:"max"`
+	SpanSeconds    int64            `json:"span_seconds"`
+	Precision      string           `json:"precision"`
+	Granularity    string           `json:"granularity"`
+	Gaps           int              `json:"gaps"`
+	MissingPeriods int              `json:"missing_periods"`
+	LargestGap     *JSONTimeGap     `json:"largest_gap,omitempty"`
+	Histogram      []JSONTimeBucket `json:"histogram"`
+}
+
+type JSONTimeGap struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+type JSONTimeBucket struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Count int       `json:"count"`
+}
+
+func newJSONDateTime(d *profiler.DateTimeStats) *JSONDateTime {
+	if d == nil {
+		return nil
+	}
+	j := &JSONDateTime{
+		Min:            d.Min,
+		Max:            d.Max,
+		SpanSeconds:    int64(d.Span().Seconds()),
+		Precision:      d.Precision,
+		Granularity:    d.Granularity,
+		Gaps:           d.Gaps,
+		MissingPeriods: d.MissingPeriods,
+		Histogram:      make([]JSONTimeBucket, len(d.Histogram)),
+	}
+	if d.LargestGap != nil {
+		j.LargestGap = &JSONTimeGap{From: d.LargestGap.From, To: d.LargestGap.To}
+	}
+	for i, bucket := range d.Histogram {
+		j.Histogram[i] = JSONTimeBucket{Start: bucket.Start, End: bucket.End, Count: bucket.Count}
+	}
+	return j
+}
+
+func (j *JSONDateTime) toDateTimeStats() *profiler.DateTimeStats {
+	if j == nil {
+		return nil
+	}
+	d := &profiler.DateTimeStats{
+		Min:            j.Min,
+		Max:            j.Max,
+		Precision:      j.Precision,
+		Granularity:    j.Granularity,
+		Gaps:           j.Gaps,
+		MissingPeriods: j.MissingPeriods,
+		Histogram:      make([]profiler.TimeBucket, len(j.Histogram)),
+	}
+	if j.LargestGap != nil {
+		d.LargestGap = &profiler.TimeGap{From: j.LargestGap.From, To: j.LargestGap.To}
+	}
+	for i, bucket := range j.Histogram {
+		d.Histogram[i] = profiler.TimeBucket{Start: bucket.Start, End: bucket.End, Count: bucket.Count}
+	}
+	return d
+}
+
+// JSONText holds the statistics of a string column. Lengths are in
+// characters.
+type JSONText struct {
+	MinLength      int              `json:"min_length"`
+	MaxLength      int              `json:"max_length"`
+	AvgLength      float64          `json:"avg_length"`
+	WhitespaceOnly int              `json:"whitespace_only"`
+	Casing         string           `json:"casing,omitempty"`
+	CasingPercent  float64          `json:"casing_percent,omitempty"`
+	Patterns       []JSONPattern    `json:"patterns"`
+	Formatting     []JSONFormatting `json:"formatting,omitempty"`
+}
+
+// JSONFormatting counts the values of a string column with a kind of
+// formatting problem.
+type JSONFormatting struct {
+	Kind     string   `json:"kind"`
+	Count    int      `json:"count"`
+	Examples []string `json:"examples,omitempty"`
+}
+
+type JSONPattern struct {
+	Pattern string `json:"pattern"`
+	Count   int    `json:"count"`
+}
+
+func newJSONText(t *profiler.TextStats) *JSONText {
+	if t == nil {
+		return nil
+	}
+	j := &JSONText{
+		MinLength:      t.MinLength,
+		MaxLength:      t.MaxLength,
+		AvgLength:      t.AvgLength,
+		WhitespaceOnly: t.WhitespaceOnly,
+		Casing:         t.Casing,
+		CasingPercent:  t.CasingPercent,
+		Patterns:       make([]JSONPattern, len(t.Patterns)),
+	}
+	for i, p := range t.Patterns {
+		j.Patterns[i] = JSONPattern{Pattern: p.Value, Count: p.Count}
+	}
+	for _, f := range t.Formatting {
+		j.Formatting = append(j.Formatting, JSONFormatting{Kind: f.Kind, Count: f.Count, Examples: f.Examples})
+	}
+	return j
+}
+
+func (j *JSONText) toTextStats() *profiler.TextStats {
+	if j == nil {
+		return nil
+	}
+	t := &profiler.TextStats{
+		MinLength:      j.MinLength,
+		MaxLength:      j.MaxLength,
+		AvgLength:      j.AvgLength,
+		WhitespaceOnly: j.WhitespaceOnly,
+		Casing:         j.Casing,
+		CasingPercent:  j.CasingPercent,
+		Patterns:       make([]profiler.ValueCount, len(j.Patterns)),
+	}
+	for i, p := range j.Patterns {
+		t.Patterns[i] = profiler.ValueCount{Value: p.Pattern, Count: p.Count}
+	}
+	for _, f := range j.Formatting {
+		t.Formatting = append(t.Formatting, profiler.Formatting{Kind: f.Kind, Count: f.Count, Examples: f.Examples})
+	}
+	return t
+}
+
+// newJSONPercentiles keys percentiles as p1, p5 and so on.
+func newJSONPercentiles(percentiles []profiler.Percentile) map[string]float64 {
+	if len(percentiles) == 0 {
+		return nil
+	}
+	result := make(map[string]float64, len(percentiles))
+	for _, p := range percentiles {
+		result[fmt.Sprintf("p%d", p.Rank)] = p.Value
+	}
+	return result
+}
+
+func loadJSONPercentiles(percentiles map[string]float64) []profiler.Percentile {
+	result := make([]profiler.Percentile, 0, len(percentiles))
+	for key, value := range percentiles {
+		var rank int
+		if _, err := fmt.Sscanf(key, "p%d", &rank); err == nil {
+			result = append(result, profiler.Percentile{Rank: rank, Value: value})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Rank < result[j].Rank })
+	return result
+}
+
+type JSONSample struct {
+	Strategy   string `json:"strategy"`
+	Rows       int    `json:"rows"`
+	SourceRows int    `json:"source_rows,omitempty"`
+}
+
+// JSONWeights names the column of row weights the means, percentiles,
+// histograms and top values are weighted by.
+type JSONWeights struct {
+	Column string  `json:"column"`
+	Total  float64 `json:"total"`
+}
+
+// JSONThresholds lists the cut-offs behind each judgment in the report, so
+// that consumers can re-evaluate the raw statistics under their own policy.
+type JSONThresholds struct {
+	MissingValues        JSONSeverityThresholds `json:"missing_values"`
+	MostlyNull           JSONMostlyNull         `json:"mostly_null"`
+	DatasetMissingValues JSONSeverityThresholds `json:"dataset_missing_values"`
+	DuplicateRows        JSONSeverityThresholds `json:"duplicate_rows"`
+	Outliers             JSONOutlierThresholds  `json:"outliers"`
+	Skewed               JSONSkewed             `json:"skewed"`
+	Imbalanced           JSONImbalanceThreshold `json:"imbalanced"`
+	SingletonHeavy       JSONSingletonHeavy     `json:"singleton_heavy"`
+	MixedTypes           JSONMixedTypeThreshold `json:"mixed_types"`
+	Redundant            JSONRedundant          `json:"redundant_columns"`
+	Categorical          JSONCategorical        `json:"categorical"`
+	Opaque               JSONOpaqueThreshold    `json:"opaque"`
+	Recommendations      JSONRecommendations    `json:"recommendations"`
+}
+
+type JSONSeverityThresholds struct {
+	MediumAbovePercent float64 `json:"medium_above_percent"`
+	HighAbovePercent   float64 `json:"high_above_percent"`
+}
+
+type JSONMostlyNull struct {
+	MissingAbovePercent float64 `json:"missing_above_percent"`
+}
+
+type JSONOutlierThresholds struct {
+	Method        string  `json:"method,omitempty"`
+	ZScore        float64 `json:"z_score"`
+	IQRMultiplier float64 `json:"iqr_multiplier,omitempty"`
+	MADThreshold  float64 `json:"mad_threshold,omitempty"`
+	JSONSeverityThresholds
+}
+
+type JSONSkewed struct {
+	AbsSkewnessAbove float64 `json:"abs_skewness_above"`
+}
+
+type JSONImbalanceThreshold struct {
+	TopValueAbovePercent float64 `json:"top_value_above_percent"`
+}
+
+type JSONSingletonHeavy struct {
+	SingletonsAbovePercent float64 `json:"singletons_above_percent"`
+	MinUnique              int     `json:"min_unique"`
+}
+
+type JSONMixedTypeThreshold struct {
+	MinorityAbovePercent float64 `json:"minority_above_percent"`
+}
+
+type JSONRedundant struct {
+	MinMatchPercent float64 `json:"min_match_percent"`
+}
+
+type JSONCategorical struct {
+	MaxUnique      int     `json:"max_unique"`
+	MaxUniqueRatio float64 `json:"max_unique_ratio"`
+}
+
+type JSONOpaqueThreshold struct {
+	AvgLengthBytes float64 `json:"avg_length_bytes"`
+}
+
+type JSONRecommendations struct {
+	ImputeMissingAbovePercent float64 `json:"impute_missing_above_percent"`
+	DeduplicateAbovePercent   float64 `json:"deduplicate_above_percent"`
+}
+
+func newJSONThresholds(t profiler.Thresholds) JSONThresholds {
+	return JSONThresholds{
+		MissingValues:        newJSONSeverityThresholds(t.MissingValues),
+		MostlyNull:           JSONMostlyNull{MissingAbovePercent: t.MostlyNullPercent},
+		DatasetMissingValues: newJSONSeverityThresholds(t.DatasetMissingValues),
+		DuplicateRows:        newJSONSeverityThresholds(t.DuplicateRows),
+		Outliers: JSONOutlierThresholds{
+			Method:                 t.OutlierMethod,
+			ZScore:                 t.OutlierZScore,
+			IQRMultiplier:          t.OutlierIQR,
+			MADThreshold:           t.OutlierMAD,
+			JSONSeverityThresholds: newJSONSeverityThresholds(t.Outliers),
+		},
+		Skewed:     JSONSkewed{AbsSkewnessAbove: t.SkewnessAbs},
+		Imbalanced: JSONImbalanceThreshold{TopValueAbovePercent: t.ImbalancedPercent},
+		SingletonHeavy: JSONSingletonHeavy{
+			SingletonsAbovePercent: t.SingletonHeavyPercent,
+			MinUnique:              t.SingletonHeavyMinUnique,
+		},
+		MixedTypes: JSONMixedTypeThreshold{MinorityAbovePercent: t.MixedTypePercent},
+		Redundant:  JSONRedundant{MinMatchPercent: t.RedundantPercent},
+		Categorical: JSONCategorical{
+			MaxUnique:      t.CategoricalMaxUnique,
+			MaxUniqueRatio: t.CategoricalMaxUniqueRatio,
+		},
+		Opaque: JSONOpaqueThreshold{AvgLengthBytes: t.OpaqueAvgLength},
+		Recommendations: JSONRecommendations{
+			ImputeMissingAbovePercent: t.ImputeMissingPercent,
+			DeduplicateAbovePercent:   t.DeduplicatePercent,
+		},
+	}
+}
+
+func newJSONSeverityThresholds(s profiler.SeverityThresholds) JSONSeverityThresholds {
+	return JSONSeverityThresholds{MediumAbovePercent: s.Medium, HighAbovePercent: s.High}
+}
+
+func (j JSONThresholds) toThresholds() profiler.Thresholds {
+	return profiler.Thresholds{
+		MissingValues:             j.MissingValues.toSeverityThresholds(),
+		MostlyNullPercent:         j.MostlyNull.MissingAbovePercent,
+		DatasetMissingValues:      j.DatasetMissingValues.toSeverityThresholds(),
+		DuplicateRows:             j.DuplicateRows.toSeverityThresholds(),
+		Outliers:                  j.Outliers.toSeverityThresholds(),
+		OutlierMethod:             j.Outliers.Method,
+		OutlierZScore:             j.Outliers.ZScore,
+		OutlierIQR:                j.Outliers.IQRMultiplier,
+		OutlierMAD:                j.Outliers.MADThreshold,
+		SkewnessAbs:               j.Skewed.AbsSkewnessAbove,
+		ImbalancedPercent:         j.Imbalanced.TopValueAbovePercent,
+		SingletonHeavyPercent:     j.SingletonHeavy.SingletonsAbovePercent,
+		SingletonHeavyMinUnique:   j.SingletonHeavy.MinUnique,
+		MixedTypePercent:          j.MixedTypes.MinorityAbovePercent,
+		RedundantPercent:          j.Redundant.MinMatchPercent,
+		CategoricalMaxUnique:      j.Categorical.MaxUnique,
+		CategoricalMaxUniqueRatio: j.Categorical.MaxUniqueRatio,
+		OpaqueAvgLength:           j.Opaque.AvgLengthBytes,
+		ImputeMissingPercent:      j.Recommendations.ImputeMissingAbovePercent,
+		DeduplicatePercent:        j.Recommendations.DeduplicateAbovePercent,
+	}
+}
+
+func (j JSONSeverityThresholds) toSeverityThresholds() profiler.SeverityThresholds {
+	return profiler.SeverityThresholds{Medium: j.MediumAbovePercent, High: j.HighAbovePercent}
 }
 
 type TopValue struct {
@@ -49,6 +763,32 @@ type TopValue struct {
 	Percent float64 `json:"percent"`
 }
 
+// newTopValues lists values with their share of the count of their column.
+func newTopValues(values []profiler.ValueCount, count int) []TopValue {
+	if len(values) == 0 {
+		return nil
+	}
+
+	topValues := make([]TopValue, len(values))
+	for i, val := range values {
+		percent := 0.0
+		if count > 0 {
+			percent = float64(val.Count) / float64(count) * 100
+		}
+
+		topValues[i] = TopValue{
+			Value:   val.Value,
+			Count:   val.Count,
+			Percent: percent,
+		}
+	}
+	return topValues
+}
+
+func (t TopValue) toValueCount() profiler.ValueCount {
+	return profiler.ValueCount{Value: t.Value, Count: t.Count}
+}
+
 type Bucket struct {
 	Min   float64 `json:"min"`
 	Max   float64 `json:"max"`
@@ -56,90 +796,408 @@ type Bucket struct {
 }
 
 func GenerateJSONReport(profile *profiler.DatasetProfile, outputPath string) error {
+	return writeJSONFile(outputPath, func(w io.Writer) error {
+		return EncodeJSONReport(w, profile)
+	})
+}
+
+// EncodeJSONReport writes the JSON report of profile to w.
+func EncodeJSONReport(w io.Writer, profile *profiler.DatasetProfile) error {
+	return encodeJSONReport(w, profile, "")
+}
+
+func newJSONReport(profile *profiler.DatasetProfile) JSONReport {
+	report := newJSONReportSummary(profile)
+	for name, col := range profile.Columns {
+		report.Columns[name] = newJSONColumn(profile, name, col)
+	}
+	return report
+}
+
+// JSONParseBudget sums up the parse errors of a profile; the columns
+// behind it carry their own parse_errors.
+type JSONParseBudget struct {
+	BadRows     int     `json:"bad_rows"`
+	Unparseable int     `json:"unparseable"`
+	NonFinite   int     `json:"non_finite"`
+	Encoding    int     `json:"encoding"`
+	Total       int     `json:"total"`
+	Percent     float64 `json:"percent"`
+}
+
+func newJSONParseBudget(b profiler.ParseBudget) JSONParseBudget {
+	return JSONParseBudget{
+		BadRows:     b.BadRows,
+		Unparseable: b.Unparseable,
+		NonFinite:   b.NonFinite,
+		Encoding:    b.Encoding,
+		Total:       b.Total,
+		Percent:     b.Percent,
+	}
+}
+
+// JSONParseErrors counts the values of a column that did not read cleanly.
+type JSONParseErrors struct {
+	Unparseable int `json:"unparseable"`
+	NonFinite   int `json:"non_finite"`
+	Encoding    int `json:"encoding"`
+	Total       int `json:"total"`
+}
+
+func newJSONParseErrors(e profiler.ParseErrors) *JSONParseErrors {
+	if e.Total() == 0 {
+		return nil
+	}
+	return &JSONParseErrors{Unparseable: e.Unparseable, NonFinite: e.NonFinite, Encoding: e.Encoding, Total: e.Total()}
+}
+
+func (j *JSONParseErrors) toParseErrors() profiler.ParseErrors {
+	if j == nil {
+		return profiler.ParseErrors{}
+	}
+	return profiler.ParseErrors{Unparseable: j.Unparseable, NonFinite: j.NonFinite, Encoding: j.Encoding}
+}
+
+// newJSONReportSummary is the report without its column details, which are
+// added by newJSONColumn.
+func newJSONReportSummary(profile *profiler.DatasetProfile) JSONReport {
 	report := JSONReport{
-		Filename:        profile.Filename,
-		FileSize:        profile.FileSize,
-		Format:          profile.Format,
-		RowCount:        profile.RowCount,
-		ColumnCount:     profile.ColumnCount,
-		MissingCells:    profile.MissingCells,
-		DuplicateRows:   profile.DuplicateRows,
-		QualityScore:    profile.QualityScore,
-		QualityIssues:   collectAllIssues(profile),
-		Recommendations: generateRecommendations(profile),
-		Columns:         make(map[string]JSONColumnReport),
-		ProcessingTime:  profile.ProcessingTime.Seconds(),
-		GeneratedAt:     time.Now().Format(time.RFC3339),
+		Filename:         profile.Filename,
+		FileSize:         profile.FileSize,
+		Compression:      profile.Compression,
+		Uncompressed:     profile.UncompressedSize,
+		Encoding:         profile.Encoding,
+		Format:           profile.Format,
+		Table:            profile.Table,
+		RowCount:         profile.RowCount,
+		ColumnCount:      profile.ColumnCount,
+		MissingCells:     profile.MissingCells,
+		DuplicateRows:    profile.DuplicateRows,
+		ParseErrors:      newJSONParseBudget(profile.ParseBudget()),
+		UniqueKey:        profile.UniqueKey,
+		Interrupted:      profile.Interrupted,
+		Exact:            profile.Exact,
+		HistogramBinning: profile.HistogramBinning,
+		KAnonymity:       profile.KAnonymity,
+		QualityScore:     profile.QualityScore,
+		QualityIssues:    collectAllIssues(profile),
+		Recommendations:  newJSONRecommendations(profileRecommendations(profile)),
+		Columns:          make(map[string]JSONColumnReport),
+		ContentDigest:    profile.ContentDigest,
+		Thresholds:       newJSONThresholds(profile.EffectiveThresholds()),
+		Notes:            profile.Notes,
+		ProcessingTime:   profile.ProcessingTime.Seconds(),
+		GeneratedAt:      time.Now().Format(time.RFC3339),
 	}
 
-	for name, col := range profile.Columns {
-		jsonCol := JSONColumnReport{
-			Name:          name,
-			DataType:      col.DataType,
-			Count:         col.Count,
-			MissingCount:  col.MissingCount,
-			UniqueCount:   col.UniqueCount,
-			QualityIssues: make([]string, 0),
-		}
+	if tableLabel(profile) == "Sheet" {
+		report.Sheet, report.Table = profile.Table, ""
+	}
 
-		if profile.RowCount > 0 {
-			jsonCol.MissingPercent = float64(col.MissingCount) / float64(profile.RowCount) * 100
+	for _, group := range profile.DuplicateGroups {
+		report.DuplicateGroups = append(report.DuplicateGroups, JSONDuplicateGroup{Rows: group.Rows, Values: group.Values})
+	}
+	for _, key := range profile.DuplicateKeys {
+		report.DuplicateKeys = append(report.DuplicateKeys, JSONDuplicateKey(key))
+	}
+	for _, pair := range profile.RedundantPairs {
+		report.RedundantColumns = append(report.RedundantColumns, JSONRedundantPair(pair))
+	}
+	for _, part := range profile.Partitions {
+		report.Partitions = append(report.Partitions, JSONPartition(part))
+	}
+	report.TimeWindows = newJSONTimeWindows(profile.TimeWindows)
+	if matrix := profile.CorrelationMatrix; matrix != nil {
+		report.Correlations = &JSONCorrelations{Rows: matrix.Rows, Sampled: matrix.Sampled, Pairs: make([]JSONCorrelationPair, 0, len(matrix.TopPairs))}
+		for _, pair := range matrix.TopPairs {
+			report.Correlations.Pairs = append(report.Correlations.Pairs, JSONCorrelationPair(pair))
 		}
+	}
+	if profile.Preview != nil {
+		report.Preview = &JSONPreview{Columns: profile.Preview.Columns, Rows: profile.Preview.Rows}
+	}
 
-		if col.Count > 0 {
-			jsonCol.UniquePercent = float64(col.UniqueCount) / float64(col.Count) * 100
+	if profile.SampleStrategy != "" {
+		report.Sample = &JSONSample{
+			Strategy:   profile.SampleStrategy,
+			Rows:       profile.RowCount,
+			SourceRows: profile.SourceRows,
 		}
+	}
+	if profile.WeightColumn != "" {
+		report.Weights = &JSONWeights{Column: profile.WeightColumn, Total: profile.WeightTotal}
+	}
+
+	return report
+}
+
+func newJSONColumn(profile *profiler.DatasetProfile, name string, col *profiler.ColumnProfile) JSONColumnReport {
+	jsonCol := JSONColumnReport{
+		Name:          name,
+		Position:      col.Position,
+		DataType:      col.DataType,
+		Count:         col.Count,
+		MissingCount:  col.MissingCount,
+		UniqueCount:   col.UniqueCount,
+		Digest:        col.Digest,
+		Nullability:   newJSONNullability(col.Nullability),
+		QualityIssues: make([]string, 0),
+	}
+
+	if profile.RowCount > 0 {
+		jsonCol.MissingPercent = float64(col.MissingCount) / float64(profile.RowCount) * 100
+	}
+
+	if col.Count > 0 {
+		jsonCol.UniquePercent = float64(col.UniqueCount) / float64(col.Count) * 100
+	}
 
-		if col.IsNumeric {
-			jsonCol.Min = col.Min
-			jsonCol.Max = col.Max
-			jsonCol.Mean = col.Mean
-			jsonCol.Median = col.Median
-			jsonCol.StdDev = col.StdDev
-
-			if len(col.HistogramBuckets) > 0 {
-				jsonCol.Histogram = make([]Bucket, len(col.HistogramBuckets))
-				for i, bucket := range col.HistogramBuckets {
-					jsonCol.Histogram[i] = Bucket{
-						Min:   bucket.LowerBound,
-						Max:   bucket.UpperBound,
-						Count: bucket.Count,
-					}
+	if col.IsNumeric {
+		jsonCol.Min = col.Min
+		jsonCol.Max = col.Max
+		jsonCol.Mean = col.Mean
+		jsonCol.Median = col.Median
+		jsonCol.StdDev = col.StdDev
+		jsonCol.Percentiles = newJSONPercentiles(col.Percentiles)
+		jsonCol.Robust = newJSONRobust(col.Robust)
+		jsonCol.Outliers = newJSONOutliers(col.Outliers)
+		jsonCol.Skewness = col.Skewness
+		jsonCol.Kurtosis = col.Kurtosis
+		jsonCol.Mode = col.Mode
+		jsonCol.NumberFormat = col.NumberFormat
+
+		if len(col.HistogramBuckets) > 0 {
+			jsonCol.Binning = col.HistogramBinning
+			jsonCol.Histogram = make([]Bucket, len(col.HistogramBuckets))
+			for i, bucket := range col.HistogramBuckets {
+				jsonCol.Histogram[i] = Bucket{
+					Min:   bucket.LowerBound,
+					Max:   bucket.UpperBound,
+					Count: bucket.Count,
 				}
 			}
 		}
+	}
 
-		if len(col.TopValues) > 0 {
-			jsonCol.TopValues = make([]TopValue, len(col.TopValues))
-			for i, val := range col.TopValues {
-				percent := 0.0
-				if col.Count > 0 {
-					percent = float64(val.Count) / float64(col.Count) * 100
-				}
+	jsonCol.Conversion = newJSONConversion(col.Conversion)
+	jsonCol.ParseErrors = newJSONParseErrors(col.ParseErrors)
+	jsonCol.MixedTypes = newJSONMixedTypes(col.MixedTypes, col.Count)
+	jsonCol.DateTime = newJSONDateTime(col.DateTime)
+	jsonCol.DateOrder = col.DateOrder
+	jsonCol.DateFormat = col.DateFormat
+	jsonCol.Text = newJSONText(col.Text)
 
-				jsonCol.TopValues[i] = TopValue{
-					Value:   val.Value,
-					Count:   val.Count,
-					Percent: percent,
-				}
-			}
+	jsonCol.Examples = col.Examples
+	jsonCol.Redacted = col.ExamplesRedacted
+	jsonCol.Suppressed = col.SuppressedValues
+
+	if col.IsOpaque {
+		jsonCol.IsOpaque = true
+		jsonCol.AvgLength = col.AvgLength
+		jsonCol.MaxLength = col.MaxLength
+	}
+
+	jsonCol.Notes = col.Notes
+
+	jsonCol.TopValues = newTopValues(col.TopValues, col.Count)
+	jsonCol.BottomValues = newTopValues(col.BottomValues, col.Count)
+	jsonCol.RareValues = newJSONRareValues(col.Rare)
+
+	for _, issue := range col.QualityIssues {
+		jsonCol.QualityIssues = append(jsonCol.QualityIssues, issue.Description)
+	}
+
+	return jsonCol
+}
+
+func writeJSON(report interface{}, outputPath string) error {
+	return writeJSONFile(outputPath, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		return nil
+	})
+}
+
+func LoadJSONReport(inputPath string) (*profiler.DatasetProfile, error) {
+	content, err := os.ReadFile(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON report: %w", err)
+	}
+
+	var report JSONReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON report: %w", err)
+	}
+	if len(report.ColumnFiles) > 0 {
+		if err := loadJSONColumnFiles(&report, inputPath); err != nil {
+			return nil, err
 		}
+	}
+
+	return report.toProfile(), nil
+}
+
+// DecodeJSONReport parses a JSON report written by EncodeJSONReport.
+func DecodeJSONReport(content []byte) (*profiler.DatasetProfile, error) {
+	var report JSONReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON report: %w", err)
+	}
+	if len(report.ColumnFiles) > 0 {
+		return nil, fmt.Errorf("failed to parse JSON report: columns are stored in separate files")
+	}
 
-		for _, issue := range col.QualityIssues {
-			jsonCol.QualityIssues = append(jsonCol.QualityIssues, issue.Description)
+	return report.toProfile(), nil
+}
+
+func (report *JSONReport) toProfile() *profiler.DatasetProfile {
+	profile := &profiler.DatasetProfile{
+		Filename:         report.Filename,
+		FileSize:         report.FileSize,
+		Compression:      report.Compression,
+		UncompressedSize: report.Uncompressed,
+		Encoding:         report.Encoding,
+		Format:           report.Format,
+		Table:            report.Table,
+		RowCount:         report.RowCount,
+		ColumnCount:      report.ColumnCount,
+		MissingCells:     report.MissingCells,
+		DuplicateRows:    report.DuplicateRows,
+		BadRows:          report.ParseErrors.BadRows,
+		Interrupted:      report.Interrupted,
+		UniqueKey:        report.UniqueKey,
+		Exact:            report.Exact,
+		HistogramBinning: report.HistogramBinning,
+		KAnonymity:       report.KAnonymity,
+		QualityScore:     report.QualityScore,
+		Columns:          make(map[string]*profiler.ColumnProfile),
+		QualityIssues:    make([]profiler.QualityIssue, 0),
+		ContentDigest:    report.ContentDigest,
+		Notes:            report.Notes,
+		ProcessingTime:   time.Duration(report.ProcessingTime * float64(time.Second)),
+	}
+
+	if report.Sheet != "" {
+		profile.Table = report.Sheet
+	}
+
+	for _, group := range report.DuplicateGroups {
+		profile.DuplicateGroups = append(profile.DuplicateGroups, profiler.DuplicateGroup{Rows: group.Rows, Values: group.Values})
+	}
+	for _, key := range report.DuplicateKeys {
+		profile.DuplicateKeys = append(profile.DuplicateKeys, profiler.DuplicateKey(key))
+	}
+	for _, pair := range report.RedundantColumns {
+		profile.RedundantPairs = append(profile.RedundantPairs, profiler.RedundantPair(pair))
+	}
+	for _, part := range report.Partitions {
+		profile.Partitions = append(profile.Partitions, profiler.PartitionProfile(part))
+	}
+	profile.TimeWindows = report.TimeWindows.toTimeWindows()
+	if c := report.Correlations; c != nil {
+		profile.CorrelationMatrix = &profiler.CorrelationMatrix{Rows: c.Rows, Sampled: c.Sampled}
+		for _, pair := range c.Pairs {
+			profile.CorrelationMatrix.TopPairs = append(profile.CorrelationMatrix.TopPairs, profiler.CorrelationPair(pair))
+		}
+	}
+	if report.Recommendations != nil {
+		profile.Recommendations = make([]profiler.Recommendation, len(report.Recommendations))
+		for i, r := range report.Recommendations {
+			profile.Recommendations[i] = profiler.Recommendation(r)
 		}
+	}
+	if report.Preview != nil {
+		profile.Preview = &profiler.Preview{Columns: report.Preview.Columns, Rows: report.Preview.Rows}
+	}
 
-		report.Columns[name] = jsonCol
+	profile.Thresholds = report.Thresholds.toThresholds()
+	profile.Thresholds = profile.EffectiveThresholds()
+
+	if report.Sample != nil {
+		profile.SampleStrategy = report.Sample.Strategy
+		profile.SourceRows = report.Sample.SourceRows
+	}
+	if report.Weights != nil {
+		profile.WeightColumn = report.Weights.Column
+		profile.WeightTotal = report.Weights.Total
 	}
 
-	jsonData, err := json.MarshalIndent(report, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
+	if generatedAt, err := time.Parse(time.RFC3339, report.GeneratedAt); err == nil {
+		profile.CreatedAt = generatedAt
 	}
 
-	if err := os.WriteFile(outputPath, jsonData, 0644); err != nil {
-		return fmt.Errorf("failed to write JSON report to file: %w", err)
+	for name, jsonCol := range report.Columns {
+		col := &profiler.ColumnProfile{
+			Name:             name,
+			Position:         jsonCol.Position,
+			DataType:         jsonCol.DataType,
+			Count:            jsonCol.Count,
+			MissingCount:     jsonCol.MissingCount,
+			UniqueCount:      jsonCol.UniqueCount,
+			Min:              jsonCol.Min,
+			Max:              jsonCol.Max,
+			Mean:             jsonCol.Mean,
+			Median:           jsonCol.Median,
+			StdDev:           jsonCol.StdDev,
+			Percentiles:      loadJSONPercentiles(jsonCol.Percentiles),
+			Robust:           jsonCol.Robust.toRobustStats(),
+			Outliers:         jsonCol.Outliers.toOutlierSummary(),
+			Skewness:         jsonCol.Skewness,
+			Kurtosis:         jsonCol.Kurtosis,
+			Mode:             jsonCol.Mode,
+			NumberFormat:     jsonCol.NumberFormat,
+			DateOrder:        jsonCol.DateOrder,
+			DateFormat:       jsonCol.DateFormat,
+			Conversion:       jsonCol.Conversion.toConversion(),
+			ParseErrors:      jsonCol.ParseErrors.toParseErrors(),
+			MixedTypes:       jsonCol.MixedTypes.toMixedTypes(),
+			IsNumeric:        jsonCol.DataType == "integer" || jsonCol.DataType == "float",
+			IsDateTime:       jsonCol.DataType == "datetime",
+			DateTime:         jsonCol.DateTime.toDateTimeStats(),
+			Text:             jsonCol.Text.toTextStats(),
+			IsUnique:         jsonCol.Count > 0 && jsonCol.UniqueCount == jsonCol.Count,
+			IsOpaque:         jsonCol.IsOpaque,
+			AvgLength:        jsonCol.AvgLength,
+			MaxLength:        jsonCol.MaxLength,
+			Digest:           jsonCol.Digest,
+			Nullability:      jsonCol.Nullability.toNullability(),
+			TopValues:        make([]profiler.ValueCount, 0, len(jsonCol.TopValues)),
+			Examples:         jsonCol.Examples,
+			ExamplesRedacted: jsonCol.Redacted,
+			SuppressedValues: jsonCol.Suppressed,
+			QualityIssues:    make([]profiler.QualityIssue, 0, len(jsonCol.QualityIssues)),
+			Notes:            jsonCol.Notes,
+		}
+
+		col.IsCategorical = col.UniqueCount <= profile.Thresholds.CategoricalMaxUnique &&
+			float64(col.UniqueCount) <= float64(report.RowCount)*profile.Thresholds.CategoricalMaxUniqueRatio
+
+		col.HistogramBinning = jsonCol.Binning
+		for _, bucket := range jsonCol.Histogram {
+			col.HistogramBuckets = append(col.HistogramBuckets, profiler.HistogramBucket{
+				LowerBound: bucket.Min,
+				UpperBound: bucket.Max,
+				Count:      bucket.Count,
+			})
+		}
+
+		for _, val := range jsonCol.TopValues {
+			col.TopValues = append(col.TopValues, val.toValueCount())
+		}
+		for _, val := range jsonCol.BottomValues {
+			col.BottomValues = append(col.BottomValues, val.toValueCount())
+		}
+		col.Rare = jsonCol.RareValues.toRareValues()
+
+		for _, issue := range jsonCol.QualityIssues {
+			col.QualityIssues = append(col.QualityIssues, profiler.QualityIssue{Description: issue})
+		}
+
+		profile.Columns[name] = col
 	}
 
-	return nil
+	return profile
 }
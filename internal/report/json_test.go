@@ -1,9 +1,15 @@
 package report
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
 )
 
 func TestGenerateJSONReport(t *testing.T) {
@@ -107,3 +113,400 @@ func TestGenerateJSONReport(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadJSONReport(t *testing.T) {
+	profile := createTestProfile()
+	profile.SampleStrategy = "random"
+	profile.SourceRows = 5000
+	profile.WeightColumn = "sample_weight"
+	profile.WeightTotal = 1234.5
+	profile.HistogramBinning = profiler.HistogramAuto
+	profile.Columns["test_int"].HistogramBinning = profiler.HistogramLog
+	profile.KAnonymity = 5
+	profile.Columns["test_str"].SuppressedValues = 2
+	profile.Columns["test_str"].BottomValues = []profiler.ValueCount{{Value: "value9", Count: 1}, {Value: "value8", Count: 2}}
+	profile.Columns["test_str"].Rare = &profiler.RareValues{Singletons: 4, Percent: 40}
+	profile.BadRows = 3
+	profile.Columns["test_int"].ParseErrors = profiler.ParseErrors{Unparseable: 4, NonFinite: 1}
+	addTestDateColumn(profile)
+	profile.Exact = true
+	profile.DuplicateGroups = []profiler.DuplicateGroup{{Rows: []int{3, 8}, Values: map[string]string{"test_str": "a"}}}
+	profile.RedundantPairs = []profiler.RedundantPair{{Column1: "test_int", Column2: "test_float", MatchPercent: 98.5}}
+	profile.UniqueKey = []string{"test_str", "test_int"}
+	profile.DuplicateKeys = []profiler.DuplicateKey{{Values: []string{"a", "1"}, Rows: 4}}
+	profile.CorrelationMatrix = &profiler.CorrelationMatrix{Rows: 1000, Sampled: true, TopPairs: []profiler.CorrelationPair{
+		{Column1: "test_float", Column2: "test_int", Method: profiler.CorrelationPearson, Correlation: 0.82, Spearman: 0.9},
+		{Column1: "region", Column2: "test_str", Method: profiler.CorrelationCramersV, Correlation: 0.4},
+	}}
+	profile.Preview = &profiler.Preview{Columns: []string{"test_str", "test_int"}, Rows: [][]string{{"value1", ""}}}
+	profile.TimeWindows = &profiler.TimeWindows{
+		TimeColumn:    "test_date",
+		Window:        7 * 24 * time.Hour,
+		LatestStart:   time.Date(2024, 2, 23, 0, 0, 0, 0, time.UTC),
+		LatestEnd:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		PreviousStart: time.Date(2024, 2, 16, 0, 0, 0, 0, time.UTC),
+		LatestRows:    40,
+		PreviousRows:  60,
+		Skipped:       2,
+		Columns: []profiler.WindowDelta{{
+			Column:   "test_int",
+			Numeric:  true,
+			Latest:   profiler.WindowStats{Count: 40, Distinct: 40, Mean: 80, StdDev: 10, Min: 60, Max: 99},
+			Previous: profiler.WindowStats{Count: 54, MissingPercent: 10, Distinct: 50, Mean: 50, StdDev: 20, Min: 1, Max: 90},
+			Changes:  []string{"mean"},
+		}},
+	}
+	profile.Recommendations = []profiler.Recommendation{
+		{Type: profiler.RuleDropRedundant, Columns: []string{"test_int", "test_float"}, Action: profiler.ActionDropColumn, Message: "Drop one"},
+		{Type: profiler.RuleDeduplicate, Action: profiler.ActionDeduplicate, Message: "Deduplicate"},
+	}
+	profile.Columns["test_str"].Conversion = &profiler.Conversion{Type: "integer", Converted: 900, Lost: 80}
+	profile.Columns["test_str"].MixedTypes = &profiler.MixedTypes{
+		Types:    []profiler.TypeCount{{Type: "integer", Count: 900}, {Type: "string", Count: 80}},
+		Examples: []string{"n/a", "unknown"},
+	}
+	profile.Columns["test_int"].Robust = &profiler.RobustStats{Trim: 0.05, TrimmedMean: 49.5, WinsorizedStdDev: 26.1, MAD: 25}
+	profile.Columns["test_int"].Outliers = &profiler.OutlierSummary{Method: profiler.OutlierMethodIQR, Threshold: 1.5, Lower: -25, Upper: 125, Count: 2, Examples: []float64{900, 300}}
+	profile.Columns["test_str"].Nullability = &profiler.Nullability{
+		Kind:       profiler.NullabilityConditional,
+		Confidence: profiler.ConfidenceMedium,
+		Condition:  &profiler.NullCondition{Column: "test_int", Values: []string{"", "3"}, NullPercent: 80},
+	}
+
+	tempFile, err := os.CreateTemp("", "report_*.json")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+	tempFile.Close()
+
+	if err := GenerateJSONReport(profile, tempFile.Name()); err != nil {
+		t.Fatalf("GenerateJSONReport failed: %v", err)
+	}
+
+	loaded, err := LoadJSONReport(tempFile.Name())
+	if err != nil {
+		t.Fatalf("LoadJSONReport failed: %v", err)
+	}
+
+	if loaded.RowCount != profile.RowCount || len(loaded.Columns) != len(profile.Columns) {
+		t.Errorf("Expected %d rows and %d columns, got %d and %d",
+			profile.RowCount, len(profile.Columns), loaded.RowCount, len(loaded.Columns))
+	}
+
+	if loaded.HistogramBinning != profiler.HistogramAuto || loaded.Columns["test_int"].HistogramBinning != profiler.HistogramLog {
+		t.Errorf("Expected auto binning resolved to log for test_int after round trip, got %q and %q",
+			loaded.HistogramBinning, loaded.Columns["test_int"].HistogramBinning)
+	}
+
+	if !loaded.Exact || !reflect.DeepEqual(loaded.DuplicateGroups, profile.DuplicateGroups) {
+		t.Errorf("Expected exact mode with duplicate groups %v after round trip, got %v and %v",
+			profile.DuplicateGroups, loaded.Exact, loaded.DuplicateGroups)
+	}
+
+	if !reflect.DeepEqual(loaded.UniqueKey, profile.UniqueKey) || !reflect.DeepEqual(loaded.DuplicateKeys, profile.DuplicateKeys) {
+		t.Errorf("Expected key %v with duplicates %v after round trip, got %v and %v",
+			profile.UniqueKey, profile.DuplicateKeys, loaded.UniqueKey, loaded.DuplicateKeys)
+	}
+
+	if !reflect.DeepEqual(loaded.CorrelationMatrix, profile.CorrelationMatrix) {
+		t.Errorf("Expected correlations %+v after round trip, got %+v", profile.CorrelationMatrix, loaded.CorrelationMatrix)
+	}
+	if !reflect.DeepEqual(loaded.RedundantPairs, profile.RedundantPairs) {
+		t.Errorf("Expected redundant pairs %v after round trip, got %v", profile.RedundantPairs, loaded.RedundantPairs)
+	}
+
+	if !reflect.DeepEqual(loaded.Recommendations, profile.Recommendations) {
+		t.Errorf("Expected recommendations %v after round trip, got %v", profile.Recommendations, loaded.Recommendations)
+	}
+
+	if !reflect.DeepEqual(loaded.TimeWindows, profile.TimeWindows) {
+		t.Errorf("Expected time windows %+v after round trip, got %+v", profile.TimeWindows, loaded.TimeWindows)
+	}
+
+	if !reflect.DeepEqual(loaded.Preview, profile.Preview) {
+		t.Errorf("Expected preview %v after round trip, got %v", profile.Preview, loaded.Preview)
+	}
+
+	if loaded.SampleStrategy != "random" || loaded.SourceRows != 5000 {
+		t.Errorf("Expected random sample of 5000 rows, got %q and %d", loaded.SampleStrategy, loaded.SourceRows)
+	}
+	if loaded.WeightColumn != "sample_weight" || loaded.WeightTotal != 1234.5 {
+		t.Errorf("Expected weights of sample_weight totalling 1234.5, got %q and %g", loaded.WeightColumn, loaded.WeightTotal)
+	}
+
+	intCol := loaded.Columns["test_int"]
+	if !intCol.IsNumeric || intCol.Mean != 50 || len(intCol.HistogramBuckets) != 5 {
+		t.Errorf("Unexpected test_int column after round trip: %+v", intCol)
+	}
+	if p99, ok := intCol.Percentile(99); !ok || p99 != 99.5 || len(intCol.Percentiles) != 6 {
+		t.Errorf("Expected 6 percentiles with p99 99.5 after round trip, got %v", intCol.Percentiles)
+	}
+	if intCol.Skewness != 0.1 || intCol.Kurtosis != -1.2 || intCol.Mode != 42.0 {
+		t.Errorf("Expected skewness 0.1, kurtosis -1.2 and mode 42 after round trip, got %v, %v and %v",
+			intCol.Skewness, intCol.Kurtosis, intCol.Mode)
+	}
+
+	if want, got := profile.Columns["test_int"].Robust, intCol.Robust; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected robust stats %+v after round trip, got %+v", want, got)
+	}
+
+	if want, got := profile.Columns["test_int"].Outliers, intCol.Outliers; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected outliers %+v after round trip, got %+v", want, got)
+	}
+
+	if want, got := profile.Columns["test_str"].Conversion, loaded.Columns["test_str"].Conversion; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected conversion %+v after round trip, got %+v", want, got)
+	}
+
+	if want, got := profile.Columns["test_str"].MixedTypes, loaded.Columns["test_str"].MixedTypes; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected mixed types %+v after round trip, got %+v", want, got)
+	}
+
+	if want, got := profile.Columns["test_date"].DateTime, loaded.Columns["test_date"].DateTime; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected datetime stats %+v after round trip, got %+v", want, got)
+	}
+
+	if want, got := profile.Columns["test_str"].Text, loaded.Columns["test_str"].Text; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected text stats %+v after round trip, got %+v", want, got)
+	}
+
+	strCol := loaded.Columns["test_str"]
+	if strCol.MissingCount != 20 || len(strCol.TopValues) != 3 {
+		t.Errorf("Unexpected test_str column after round trip: %+v", strCol)
+	}
+	if want := profile.Columns["test_str"].BottomValues; !reflect.DeepEqual(strCol.BottomValues, want) {
+		t.Errorf("Expected bottom values %v after round trip, got %v", want, strCol.BottomValues)
+	}
+	if want := profile.Columns["test_str"].Rare; !reflect.DeepEqual(strCol.Rare, want) {
+		t.Errorf("Expected rare values %+v after round trip, got %+v", want, strCol.Rare)
+	}
+	if loaded.KAnonymity != 5 || strCol.SuppressedValues != 2 {
+		t.Errorf("Expected 2 test_str values withheld under k-anonymity of 5 after round trip, got %d and %d", strCol.SuppressedValues, loaded.KAnonymity)
+	}
+	if want, got := profile.ParseBudget(), loaded.ParseBudget(); !reflect.DeepEqual(got, want) || got.Total != 8 {
+		t.Errorf("Expected the parse error budget %+v after round trip, got %+v", want, got)
+	}
+	if n := strCol.Nullability; n == nil || n.String() != "null only when test_int is missing or '3' (medium confidence)" {
+		t.Errorf("Unexpected test_str nullability after round trip: %v", n)
+	}
+}
+
+func TestLoadJSONReportPlainRecommendations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.json")
+	content := `{"filename": "old.csv", "format": "CSV", "recommendations": ["Check outliers in 'price' column"], "columns": {}}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+
+	loaded, err := LoadJSONReport(path)
+	if err != nil {
+		t.Fatalf("LoadJSONReport failed: %v", err)
+	}
+	want := []profiler.Recommendation{{Message: "Check outliers in 'price' column"}}
+	if !reflect.DeepEqual(loaded.Recommendations, want) {
+		t.Errorf("Expected recommendations %v, got %v", want, loaded.Recommendations)
+	}
+}
+
+func TestLoadJSONReportInvalid(t *testing.T) {
+	tempFile, err := os.CreateTemp("", "report_*.json")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+	tempFile.WriteString("not json")
+	tempFile.Close()
+
+	if _, err := LoadJSONReport(tempFile.Name()); err == nil {
+		t.Error("Expected error for invalid JSON report, got nil")
+	}
+}
+
+func TestGenerateJSONReportThresholds(t *testing.T) {
+	profile := createTestProfile()
+	profile.Thresholds = profiler.DefaultThresholds()
+	profile.Thresholds.ImbalancedPercent = 75
+	profile.Thresholds.OutlierMethod = profiler.OutlierMethodMAD
+
+	tempFile, err := os.CreateTemp("", "report_*.json")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+	tempFile.Close()
+
+	if err := GenerateJSONReport(profile, tempFile.Name()); err != nil {
+		t.Fatalf("GenerateJSONReport failed: %v", err)
+	}
+
+	content, err := os.ReadFile(tempFile.Name())
+	if err != nil {
+		t.Fatalf("Failed to read JSON report: %v", err)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(content, &raw); err != nil {
+		t.Fatalf("Failed to parse JSON report: %v", err)
+	}
+
+	thresholds, ok := raw["thresholds"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected a thresholds block in the JSON report")
+	}
+
+	outliers := thresholds["outliers"].(map[string]interface{})
+	if outliers["method"] != "mad" || outliers["z_score"] != 3.0 || outliers["iqr_multiplier"] != 1.5 ||
+		outliers["mad_threshold"] != 3.5 || outliers["high_above_percent"] != 10.0 {
+		t.Errorf("Unexpected outlier thresholds: %v", outliers)
+	}
+
+	if skewed := thresholds["skewed"].(map[string]interface{}); skewed["abs_skewness_above"] != 2.0 {
+		t.Errorf("Expected skewness threshold 2, got %v", skewed["abs_skewness_above"])
+	}
+
+	if redundant := thresholds["redundant_columns"].(map[string]interface{}); redundant["min_match_percent"] != 95.0 {
+		t.Errorf("Expected redundant columns threshold 95, got %v", redundant["min_match_percent"])
+	}
+
+	imbalanced := thresholds["imbalanced"].(map[string]interface{})
+	if imbalanced["top_value_above_percent"] != 75.0 {
+		t.Errorf("Expected imbalance threshold 75, got %v", imbalanced["top_value_above_percent"])
+	}
+
+	if singletons := thresholds["singleton_heavy"].(map[string]interface{}); singletons["singletons_above_percent"] != 50.0 || singletons["min_unique"] != 10.0 {
+		t.Errorf("Expected singleton-heavy thresholds of 50%% and 10 values, got %v", singletons)
+	}
+	if mixed := thresholds["mixed_types"].(map[string]interface{}); mixed["minority_above_percent"] != 10.0 {
+		t.Errorf("Expected mixed types threshold 10, got %v", mixed["minority_above_percent"])
+	}
+
+	loaded, err := LoadJSONReport(tempFile.Name())
+	if err != nil {
+		t.Fatalf("LoadJSONReport failed: %v", err)
+	}
+	if loaded.Thresholds != profile.Thresholds {
+		t.Errorf("Expected thresholds to round trip, got %+v", loaded.Thresholds)
+	}
+}
+
+func TestGenerateJSONReportMatchesMarshalIndent(t *testing.T) {
+	profile := createTestProfile()
+	path := filepath.Join(t.TempDir(), "report.json")
+
+	if err := GenerateJSONReport(profile, path); err != nil {
+		t.Fatalf("GenerateJSONReport failed: %v", err)
+	}
+	streamed, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read report file: %v", err)
+	}
+
+	var report JSONReport
+	if err := json.Unmarshal(streamed, &report); err != nil {
+		t.Fatalf("Failed to parse streamed report: %v", err)
+	}
+
+	expected := newJSONReport(profile)
+	expected.GeneratedAt = report.GeneratedAt
+	marshaled, err := json.MarshalIndent(expected, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal report: %v", err)
+	}
+
+	if string(streamed) != string(marshaled) {
+		t.Errorf("Expected streamed report to match MarshalIndent output:\n%s\ngot:\n%s", marshaled, streamed)
+	}
+}
+
+func TestGenerateJSONReportStdout(t *testing.T) {
+	var buf bytes.Buffer
+	previous := stdout
+	stdout = &buf
+	t.Cleanup(func() { stdout = previous })
+
+	if err := GenerateJSONReport(createTestProfile(), StdoutPath); err != nil {
+		t.Fatalf("GenerateJSONReport failed: %v", err)
+	}
+	if _, err := os.Stat(StdoutPath); err == nil {
+		t.Errorf("Expected no file named %s to be created", StdoutPath)
+	}
+
+	var report JSONReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("Failed to parse report written to stdout: %v", err)
+	}
+	if report.Filename != "test.csv" || len(report.Columns) == 0 {
+		t.Errorf("Expected the report of test.csv, got %+v", report)
+	}
+}
+
+func TestGenerateSplitJSONReport(t *testing.T) {
+	profile := createTestProfile()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wide.json")
+
+	files, err := GenerateSplitJSONReport(profile, path, 2)
+	if err != nil {
+		t.Fatalf("GenerateSplitJSONReport failed: %v", err)
+	}
+	if len(files) != 2 || filepath.Base(files[0]) != "wide_columns_001.json" {
+		t.Fatalf("Expected 2 column files, got %v", files)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read index file: %v", err)
+	}
+	var index JSONReport
+	if err := json.Unmarshal(content, &index); err != nil {
+		t.Fatalf("Failed to parse index file: %v", err)
+	}
+	if len(index.Columns) != 0 || len(index.ColumnFiles) != 2 || len(index.ColumnFiles[1].Columns) != 1 {
+		t.Errorf("Expected an index of 2 column files without columns, got %d columns and %v", len(index.Columns), index.ColumnFiles)
+	}
+
+	loaded, err := LoadJSONReport(path)
+	if err != nil {
+		t.Fatalf("LoadJSONReport failed: %v", err)
+	}
+	if len(loaded.Columns) != len(profile.Columns) {
+		t.Fatalf("Expected %d columns after loading, got %d", len(profile.Columns), len(loaded.Columns))
+	}
+	if loaded.Columns["test_int"].Mean != profile.Columns["test_int"].Mean {
+		t.Errorf("Expected test_int mean %f, got %f", profile.Columns["test_int"].Mean, loaded.Columns["test_int"].Mean)
+	}
+
+	if _, err := GenerateSplitJSONReport(profile, path, 0); err == nil {
+		t.Error("Expected an error for zero columns per file")
+	}
+}
+
+func TestEncodeDecodeJSONReport(t *testing.T) {
+	profile := createTestProfile()
+	profile.Partitions = []profiler.PartitionProfile{
+		{Name: "dt=2024-01-01", Files: 2, Size: 1024, RowCount: 600},
+		{Name: "dt=2024-01-02", Files: 1, Size: 10, RowCount: 0, Issue: "no rows"},
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeJSONReport(&buf, profile); err != nil {
+		t.Fatalf("EncodeJSONReport failed: %v", err)
+	}
+
+	decoded, err := DecodeJSONReport(buf.Bytes())
+	if err != nil {
+		t.Fatalf("DecodeJSONReport failed: %v", err)
+	}
+	if decoded.RowCount != profile.RowCount || len(decoded.Columns) != len(profile.Columns) || decoded.QualityScore != profile.QualityScore {
+		t.Errorf("Expected decoded profile to match, got %d rows, %d columns, score %d",
+			decoded.RowCount, len(decoded.Columns), decoded.QualityScore)
+	}
+	if len(decoded.Partitions) != 2 || decoded.Partitions[0] != profile.Partitions[0] || decoded.Partitions[1].Issue != "no rows" {
+		t.Errorf("Expected partitions to round-trip, got %+v", decoded.Partitions)
+	}
+
+	if _, err := DecodeJSONReport([]byte(`{"column_files": [{"file": "a.json"}]}`)); err == nil {
+		t.Error("Expected an error for a split report index")
+	}
+}
@@ -0,0 +1,222 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// JSONColumnFile lists the columns stored in one file of a split JSON report.
+type JSONColumnFile struct {
+	File    string   `json:"file"` // relative to the index file
+	Columns []string `json:"columns"`
+}
+
+// JSONColumnPart is one column file of a split JSON report.
+type JSONColumnPart struct {
+	Filename string                      `json:"filename"`
+	Columns  map[string]JSONColumnReport `json:"columns"`
+}
+
+// GenerateSplitJSONReport writes the report of a wide dataset as an index
+// file at outputPath, holding everything but the column details, and one
+// file per columnsPerFile columns next to it. It returns the column files.
+func GenerateSplitJSONReport(profile *profiler.DatasetProfile, outputPath string, columnsPerFile int) ([]string, error) {
+	if columnsPerFile <= 0 {
+		return nil, fmt.Errorf("columns per file must be positive, got %d", columnsPerFile)
+	}
+
+	names := make([]string, 0, len(profile.Columns))
+	for name := range profile.Columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	base := strings.TrimSuffix(outputPath, filepath.Ext(outputPath))
+	index := newJSONReportSummary(profile)
+	files := make([]string, 0, (len(names)+columnsPerFile-1)/columnsPerFile)
+
+	for start := 0; start < len(names); start += columnsPerFile {
+		end := start + columnsPerFile
+		if end > len(names) {
+			end = len(names)
+		}
+
+		part := JSONColumnPart{
+			Filename: profile.Filename,
+			Columns:  make(map[string]JSONColumnReport, end-start),
+		}
+		for _, name := range names[start:end] {
+			part.Columns[name] = newJSONColumn(profile, name, profile.Columns[name])
+		}
+
+		file := fmt.Sprintf("%s_columns_%03d.json", base, len(files)+1)
+		if err := writeJSON(part, file); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+		index.ColumnFiles = append(index.ColumnFiles, JSONColumnFile{File: filepath.Base(file), Columns: names[start:end]})
+	}
+
+	if err := writeJSON(index, outputPath); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// loadJSONColumnFiles reads the column files of a split report into its
+// columns. Paths are relative to the index file.
+func loadJSONColumnFiles(report *JSONReport, indexPath string) error {
+	if report.Columns == nil {
+		report.Columns = make(map[string]JSONColumnReport)
+	}
+
+	for _, file := range report.ColumnFiles {
+		content, err := os.ReadFile(filepath.Join(filepath.Dir(indexPath), file.File))
+		if err != nil {
+			return fmt.Errorf("failed to read column file: %w", err)
+		}
+
+		var part JSONColumnPart
+		if err := json.Unmarshal(content, &part); err != nil {
+			return fmt.Errorf("failed to parse column file %s: %w", file.File, err)
+		}
+		for name, col := range part.Columns {
+			report.Columns[name] = col
+		}
+	}
+
+	return nil
+}
+
+// StdoutPath as the output path writes a JSON report to standard output.
+const StdoutPath = "-"
+
+// stdout receives the reports written to StdoutPath: the standard output the
+// process started with, even once --quiet has silenced os.Stdout.
+var stdout io.Writer = os.Stdout
+
+// writeJSONFile creates outputPath, or takes stdout for StdoutPath, and
+// writes it through a buffer with encode.
+func writeJSONFile(outputPath string, encode func(w io.Writer) error) error {
+	if outputPath == StdoutPath {
+		w := bufio.NewWriter(stdout)
+		if err := encode(w); err != nil {
+			return err
+		}
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("failed to write JSON report to stdout: %w", err)
+		}
+		return nil
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to write JSON report to file: %w", err)
+	}
+
+	w := bufio.NewWriter(file)
+	if err := encode(w); err != nil {
+		file.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write JSON report to file: %w", err)
+	}
+
+	return file.Close()
+}
+
+// encodeJSONReport writes the report of profile one column at a time, so that
+// only a single column's details are held in memory. The output matches
+// json.MarshalIndent of the whole report with the given line prefix.
+func encodeJSONReport(w io.Writer, profile *profiler.DatasetProfile, prefix string) error {
+	report := newJSONReportSummary(profile)
+	report.Columns = nil
+
+	names := make([]string, 0, len(profile.Columns))
+	for name := range profile.Columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return encodeSpliced(w, report, prefix, "columns", func(w io.Writer) error {
+		return encodeJSONObject(w, names, prefix+"  ", func(name string) interface{} {
+			return newJSONColumn(profile, name, profile.Columns[name])
+		})
+	})
+}
+
+// encodeSpliced writes value, a struct whose field key is null, with the
+// field's content produced by stream in place of the null.
+func encodeSpliced(w io.Writer, value interface{}, prefix, key string, stream func(w io.Writer) error) error {
+	data, err := json.MarshalIndent(value, prefix, "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	field := []byte("\n" + prefix + `  "` + key + `": `)
+	at := bytes.Index(data, append(field, "null"...))
+	if at < 0 {
+		return fmt.Errorf("failed to marshal JSON: no %s field", key)
+	}
+	at += len(field)
+
+	if _, err := w.Write(data[:at]); err != nil {
+		return fmt.Errorf("failed to write JSON report to file: %w", err)
+	}
+	if err := stream(w); err != nil {
+		return err
+	}
+	if _, err := w.Write(data[at+len("null"):]); err != nil {
+		return fmt.Errorf("failed to write JSON report to file: %w", err)
+	}
+
+	return nil
+}
+
+// encodeJSONObject writes an object whose members are encoded one at a time,
+// indented as json.MarshalIndent would with the object's line prefix.
+func encodeJSONObject(w io.Writer, keys []string, prefix string, value func(key string) interface{}) error {
+	if len(keys) == 0 {
+		_, err := io.WriteString(w, "{}")
+		return err
+	}
+
+	if _, err := io.WriteString(w, "{"); err != nil {
+		return fmt.Errorf("failed to write JSON report to file: %w", err)
+	}
+	for i, key := range keys {
+		name, err := json.Marshal(key)
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		member, err := json.MarshalIndent(value(key), prefix+"  ", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+
+		separator := "\n"
+		if i > 0 {
+			separator = ",\n"
+		}
+		if _, err := io.WriteString(w, separator+prefix+"  "+string(name)+": "+string(member)); err != nil {
+			return fmt.Errorf("failed to write JSON report to file: %w", err)
+		}
+	}
+	if _, err := io.WriteString(w, "\n"+prefix+"}"); err != nil {
+		return fmt.Errorf("failed to write JSON report to file: %w", err)
+	}
+
+	return nil
+}
@@ -12,18 +12,34 @@ import (
 func GenerateMarkdownReport(profile *profiler.DatasetProfile, outputPath string) error {
 	var content strings.Builder
 
+	writeMarkdownProfile(&content, profile)
+
+	content.WriteString("---\n")
+	content.WriteString("Generated by DataSleuth v0.1.0 - Fast dataset profiling and validation from the command line\n")
+
+	if err := os.WriteFile(outputPath, []byte(content.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write Markdown report to file: %w", err)
+	}
+
+	return nil
+}
+
+func writeMarkdownProfile(content *strings.Builder, profile *profiler.DatasetProfile) {
 	content.WriteString(fmt.Sprintf("# DataSleuth Profile: %s\n\n", profile.Filename))
-	content.WriteString(fmt.Sprintf("**Generated:** %s | **Size:** %.2f MB | **Rows:** %s | **Columns:** %d\n\n",
-		time.Now().Format("January 2, 2006"),
-		float64(profile.FileSize)/(1024*1024),
-		formatNumber(profile.RowCount),
-		profile.ColumnCount))
+	content.WriteString(fmt.Sprintf("**Generated:** %s | ", time.Now().Format("January 2, 2006")))
+	if size := FormatFileSize(profile); size != "" {
+		content.WriteString(fmt.Sprintf("**Size:** %s | ", size))
+	}
+	content.WriteString(fmt.Sprintf("**Rows:** %s | **Columns:** %d\n\n", formatRowCount(profile), profile.ColumnCount))
 
 	content.WriteString(fmt.Sprintf("## Dataset Quality Score: %d/100\n\n", profile.QualityScore))
 
 	content.WriteString("## Dataset Summary\n\n")
 	content.WriteString("| Metric | Value |\n")
 	content.WriteString("|--------|-------|\n")
+	if profile.Table != "" {
+		content.WriteString(fmt.Sprintf("| %s | %s |\n", tableLabel(profile), profile.Table))
+	}
 	content.WriteString(fmt.Sprintf("| Rows | %s |\n", formatNumber(profile.RowCount)))
 	content.WriteString(fmt.Sprintf("| Columns | %d |\n", profile.ColumnCount))
 
@@ -38,14 +54,94 @@ func GenerateMarkdownReport(profile *profiler.DatasetProfile, outputPath string)
 
 	if profile.DuplicateRows > 0 {
 		dupPct := float64(profile.DuplicateRows) / float64(profile.RowCount) * 100
-		content.WriteString(fmt.Sprintf("| Duplicate rows | %s (%.2f%%) |\n",
-			formatNumber(profile.DuplicateRows), dupPct))
+		content.WriteString(fmt.Sprintf("| %s | %s (%.2f%%) |\n",
+			duplicatesLabel(profile), formatNumber(profile.DuplicateRows), dupPct))
 	} else {
-		content.WriteString("| Duplicate rows | 0 (0.00%) |\n")
+		content.WriteString(fmt.Sprintf("| %s | 0 (0.00%%) |\n", duplicatesLabel(profile)))
+	}
+
+	budget := profile.ParseBudget()
+	content.WriteString(fmt.Sprintf("| Parse errors | %s (%.2f%%) |\n", formatNumber(budget.Total), budget.Percent))
+
+	if profile.ContentDigest != "" {
+		content.WriteString(fmt.Sprintf("| Content digest | `%s` |\n", profile.ContentDigest))
+	}
+
+	if profile.HistogramBinning != "" {
+		content.WriteString(fmt.Sprintf("| Histogram binning | %s |\n", profile.HistogramBinning))
 	}
 
 	content.WriteString(fmt.Sprintf("| Processing Time | %.2f seconds |\n\n", profile.ProcessingTime.Seconds()))
 
+	if len(profile.Notes) > 0 {
+		content.WriteString("## Notes\n\n")
+		for _, note := range profile.Notes {
+			content.WriteString(fmt.Sprintf("- %s\n", note))
+		}
+		content.WriteString("\n")
+	}
+
+	if len(profile.DuplicateGroups) > 0 {
+		content.WriteString("## Duplicate Rows\n\n")
+		for _, group := range profile.DuplicateGroups {
+			content.WriteString(fmt.Sprintf("- %s\n", formatDuplicateGroup(group)))
+		}
+		content.WriteString("\n")
+	}
+
+	if len(profile.DuplicateKeys) > 0 && len(profile.DuplicateGroups) == 0 {
+		content.WriteString("## Duplicate Keys\n\n")
+		for _, key := range profile.DuplicateKeys {
+			content.WriteString(fmt.Sprintf("- %s\n", formatDuplicateKey(profile.UniqueKey, key)))
+		}
+		content.WriteString("\n")
+	}
+
+	if budget.Total > 0 {
+		content.WriteString("## Parse Errors\n\n")
+		for _, line := range parseBreakdown(budget) {
+			content.WriteString(fmt.Sprintf("- %s\n", line))
+		}
+		content.WriteString("\n")
+	}
+
+	if len(profile.Partitions) > 0 {
+		content.WriteString("## Partitions\n\n")
+		content.WriteString("| Partition | Files | Rows | Missing | Issue |\n")
+		content.WriteString("|-----------|-------|------|---------|-------|\n")
+		for _, part := range profile.Partitions {
+			content.WriteString(fmt.Sprintf("| %s | %d | %s | %.2f%% | %s |\n", escapeTableCell(part.Name), part.Files,
+				formatNumber(part.RowCount), part.MissingPercent(profile.ColumnCount), escapeTableCell(part.Issue)))
+		}
+		content.WriteString("\n")
+	}
+
+	if w := profile.TimeWindows; w != nil {
+		content.WriteString("## Time Windows\n\n")
+		content.WriteString(fmt.Sprintf("The %s, %s.\n\n", windowTitle(w), windowRange(w)))
+		if w.LatestRows > 0 && w.PreviousRows > 0 {
+			content.WriteString("| Column | Missing | Distinct | Mean | Regression |\n")
+			content.WriteString("|--------|---------|----------|------|------------|\n")
+			for _, d := range w.Columns {
+				content.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", escapeTableCell(d.Column), formatWindowMissing(d),
+					formatWindowDistinct(d), formatWindowMean(d), strings.Join(d.Changes, ", ")))
+			}
+			content.WriteString("\n")
+		}
+	}
+
+	if risks := conversionRisks(profile); len(risks) > 0 {
+		content.WriteString("## Type Conversion Risk\n\n")
+		content.WriteString("| Column | Type | Cast To | Converted | Lost | Truncated | At Risk |\n")
+		content.WriteString("|--------|------|---------|-----------|------|-----------|---------|\n")
+		for _, col := range risks {
+			c := col.Conversion
+			content.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %.2f%% |\n", escapeTableCell(col.Name), col.DataType, c.Type,
+				formatNumber(c.Converted), formatNumber(c.Lost), formatNumber(c.Truncated), c.AtRiskPercent()))
+		}
+		content.WriteString("\n")
+	}
+
 	issues := collectAllIssues(profile)
 	if len(issues) > 0 {
 		content.WriteString("## Quality Issues\n\n")
@@ -55,7 +151,7 @@ func GenerateMarkdownReport(profile *profiler.DatasetProfile, outputPath string)
 		content.WriteString("\n")
 	}
 
-	recommendations := generateRecommendations(profile)
+	recommendations := recommendationMessages(profile)
 	if len(recommendations) > 0 {
 		content.WriteString("## Recommendations\n\n")
 		for _, rec := range recommendations {
@@ -75,22 +171,101 @@ func GenerateMarkdownReport(profile *profiler.DatasetProfile, outputPath string)
 			content.WriteString(fmt.Sprintf("- **Missing:** %.2f%%\n", missingPct))
 		}
 
+		if col.Nullability != nil {
+			content.WriteString(fmt.Sprintf("- **Nullability:** %s\n", col.Nullability))
+		}
+
 		if col.Count > 0 {
 			uniquePct := float64(col.UniqueCount) / float64(col.Count) * 100
 			content.WriteString(fmt.Sprintf("- **Unique:** %.2f%%\n", uniquePct))
 		}
 
+		if col.Conversion != nil {
+			content.WriteString(fmt.Sprintf("- **Cast:** %s\n", col.Conversion))
+		}
+
 		if col.IsNumeric {
 			content.WriteString(fmt.Sprintf("- **Range:** %v - %v\n", col.Min, col.Max))
 			content.WriteString(fmt.Sprintf("- **Mean:** %.2f\n", col.Mean))
 			content.WriteString(fmt.Sprintf("- **Median:** %.2f\n", col.Median))
 			content.WriteString(fmt.Sprintf("- **Std Dev:** %.2f\n", col.StdDev))
+			if r := col.Robust; r != nil {
+				content.WriteString(fmt.Sprintf("- **Trimmed Mean (%g%%):** %.2f\n", r.Trim*100, r.TrimmedMean))
+				content.WriteString(fmt.Sprintf("- **Winsorized Std Dev (%g%%):** %.2f\n", r.Trim*100, r.WinsorizedStdDev))
+				content.WriteString(fmt.Sprintf("- **MAD:** %.2f\n", r.MAD))
+			}
+			if len(col.Percentiles) > 0 {
+				content.WriteString(fmt.Sprintf("- **Percentiles:** %s\n", formatPercentiles(col.Percentiles, "%.2f")))
+			}
+			content.WriteString(fmt.Sprintf("- **Skewness:** %.2f\n", col.Skewness))
+			content.WriteString(fmt.Sprintf("- **Kurtosis:** %.2f\n", col.Kurtosis))
+			if col.Mode != nil {
+				content.WriteString(fmt.Sprintf("- **Mode:** %v\n", col.Mode))
+			}
+			if col.NumberFormat != "" {
+				content.WriteString(fmt.Sprintf("- **Number format:** %s\n", col.NumberFormat))
+			}
+		}
+
+		if d := col.DateTime; d != nil {
+			content.WriteString(fmt.Sprintf("- **Range:** %s - %s\n", d.Format(d.Min), d.Format(d.Max)))
+			content.WriteString(fmt.Sprintf("- **Span:** %s\n", profiler.FormatSpan(d.Span())))
+			content.WriteString(fmt.Sprintf("- **Granularity:** %s\n", d.Granularity))
+			if col.DateFormat != "" {
+				content.WriteString(fmt.Sprintf("- **Date format:** `%s`\n", col.DateFormat))
+			}
+			if col.DateOrder != "" {
+				content.WriteString(fmt.Sprintf("- **Date order:** %s\n", col.DateOrder))
+			}
+			if d.LargestGap != nil {
+				content.WriteString(fmt.Sprintf("- **Gaps:** %d (%d missing periods, largest %s to %s)\n",
+					d.Gaps, d.MissingPeriods, d.Format(d.LargestGap.From), d.Format(d.LargestGap.To)))
+			}
+			content.WriteString("- **Timeline:**\n\n")
+			content.WriteString("  | From | To | Count |\n")
+			content.WriteString("  |------|----|-------|\n")
+			for _, bucket := range d.Histogram {
+				content.WriteString(fmt.Sprintf("  | %s | %s | %s |\n", d.Format(bucket.Start), d.Format(bucket.End), formatNumber(bucket.Count)))
+			}
+			content.WriteString("\n")
+		}
+
+		if col.IsOpaque {
+			content.WriteString(fmt.Sprintf("- **Avg Size:** %s\n", profiler.FormatBytes(col.AvgLength)))
+			content.WriteString(fmt.Sprintf("- **Max Size:** %s\n", profiler.FormatBytes(float64(col.MaxLength))))
+		}
+
+		if t := col.Text; t != nil {
+			content.WriteString(fmt.Sprintf("- **Length:** %s\n", formatTextLengths(t)))
+			if t.WhitespaceOnly > 0 {
+				content.WriteString(fmt.Sprintf("- **Whitespace-only:** %s\n", formatNumber(t.WhitespaceOnly)))
+			}
+			if len(t.Formatting) > 0 {
+				content.WriteString(fmt.Sprintf("- **Formatting:** %s\n", formatFormatting(t)))
+			}
+			content.WriteString(fmt.Sprintf("- **Casing:** %s\n", formatCasing(t)))
+			content.WriteString(fmt.Sprintf("- **Patterns:** %s\n", formatPatterns(t, col.Count)))
+		}
+
+		if col.Rare != nil && col.Rare.Singletons > 0 && !col.IsUnique {
+			content.WriteString(fmt.Sprintf("- **Rare:** %s\n", col.Rare))
+		}
+		if len(col.BottomValues) > 0 && !col.IsUnique {
+			content.WriteString(fmt.Sprintf("- **Bottom Values:** %s\n", formatValueCounts(col.BottomValues)))
+		}
+
+		for _, note := range col.Notes {
+			content.WriteString(fmt.Sprintf("- **Note:** %s\n", note))
 		}
 
 		content.WriteString("\n")
 
-		if col.IsCategorical && len(col.TopValues) > 0 {
-			content.WriteString("**Top Values:**\n\n")
+		if (col.IsCategorical || profile.Exact || !col.IsUnique) && len(col.TopValues) > 0 {
+			label := "Top Values"
+			if profile.Exact {
+				label = "Frequencies"
+			}
+			content.WriteString(fmt.Sprintf("**%s:**\n\n", label))
 			for _, val := range col.TopValues {
 				if col.Count > 0 {
 					valPct := float64(val.Count) / float64(col.Count) * 100
@@ -110,13 +285,4 @@ func GenerateMarkdownReport(profile *profiler.DatasetProfile, outputPath string)
 			content.WriteString("\n")
 		}
 	}
-
-	content.WriteString("---\n")
-	content.WriteString("Generated by DataSleuth v0.1.0 - Fast dataset profiling and validation from the command line\n")
-
-	if err := os.WriteFile(outputPath, []byte(content.String()), 0644); err != nil {
-		return fmt.Errorf("failed to write Markdown report to file: %w", err)
-	}
-
-	return nil
 }
@@ -2,12 +2,17 @@ package report
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
 )
 
 func TestGenerateMarkdownReport(t *testing.T) {
 	profile := createTestProfile()
+	profile.HistogramBinning = profiler.HistogramEqualFrequency
+	profile.Columns["test_int"].Conversion = &profiler.Conversion{Type: "integer", Converted: 900, Lost: 45, Truncated: 5}
 
 	tempFile, err := os.CreateTemp("", "report_*.md")
 	if err != nil {
@@ -42,6 +47,17 @@ func TestGenerateMarkdownReport(t *testing.T) {
 		"### test_float",
 		"**Type:** float",
 		"**Top Values:**",
+		"**Percentiles:** p1 2.00, p5 6.00, p25 26.00, p75 75.00, p95 95.00, p99 99.50",
+		"**Skewness:** 0.10",
+		"**Kurtosis:** -1.20",
+		"**Mode:** 42",
+		"## Type Conversion Risk",
+		"| test_int | integer | integer | 900 | 45 | 5 | 5.26% |",
+		"**Cast:** casting to integer loses 45 values and truncates 5 (5.26%)",
+		"| Histogram binning | equal-frequency |",
+		"**Whitespace-only:** 3",
+		"**Formatting:** 12 padded",
+		"**Patterns:** AAAAA9 (96.9%), AAAAA99 (3.1%)",
 		"Generated by DataSleuth",
 	}
 
@@ -51,3 +67,57 @@ func TestGenerateMarkdownReport(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateMarkdownReportDateTime(t *testing.T) {
+	profile := createTestProfile()
+	addTestDateColumn(profile)
+
+	path := filepath.Join(t.TempDir(), "report.md")
+	if err := GenerateMarkdownReport(profile, path); err != nil {
+		t.Fatalf("GenerateMarkdownReport failed: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read report file: %v", err)
+	}
+
+	for _, expected := range []string{
+		"| Columns | 4 |",
+		"### test_date",
+		"**Range:** 2024-01-01 - 2024-01-31",
+		"**Span:** 30 days",
+		"**Granularity:** daily",
+		"| 2024-01-16 | 2024-01-31 | 550 |",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected Markdown to contain %q", expected)
+		}
+	}
+}
+
+func TestGenerateMarkdownReportExact(t *testing.T) {
+	profile := createTestProfile()
+	profile.Exact = true
+	profile.DuplicateGroups = []profiler.DuplicateGroup{{Rows: []int{3, 8}, Values: map[string]string{"test_str": "a", "test_int": "1"}}}
+
+	path := filepath.Join(t.TempDir(), "report.md")
+	if err := GenerateMarkdownReport(profile, path); err != nil {
+		t.Fatalf("GenerateMarkdownReport failed: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read report file: %v", err)
+	}
+
+	for _, expected := range []string{
+		"## Duplicate Rows\n\n- rows 3, 8: test_int=1, test_str=a\n",
+		"**Frequencies:**",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected Markdown to contain %q", expected)
+		}
+	}
+	if strings.Contains(string(content), "**Top Values:**") {
+		t.Error("Expected complete frequencies instead of top values in exact mode")
+	}
+}
@@ -0,0 +1,82 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/mask"
+)
+
+// PrintMaskImpact prints what a masking plan does to the statistics of each
+// masked column and to the join-ability of the dataset.
+func PrintMaskImpact(impact *mask.Impact) {
+	fmt.Printf("\n📊 Dataset: %s (%s rows)\n\n", impact.Source, formatNumber(impact.Rows))
+
+	fmt.Println("🎭 Masking Impact:")
+	fmt.Printf("   %-16s %-16s %-24s %-22s %s\n", "COLUMN", "TECHNIQUE", "DISTINCT", "JOINS", "DISTRIBUTION")
+	fmt.Printf("   %s\n", strings.Repeat("─", 104))
+	for _, col := range impact.Columns {
+		name := col.Mask.Name
+		if len(name) > 16 {
+			name = name[:13] + "..."
+		}
+		fmt.Printf("   %-16s %-16s %-24s %-22s %s\n", name, col.Mask, formatMaskedDistinct(col), formatJoins(col), formatMaskedDistribution(col))
+	}
+	fmt.Println()
+
+	fmt.Println("🔒 Privacy:")
+	fmt.Printf("   • Duplicate rows: %s → %s (%s made indistinguishable by masking)\n",
+		formatNumber(impact.OldDuplicates), formatNumber(impact.NewDuplicates), formatDelta(impact.NewDuplicates-impact.OldDuplicates))
+	for _, col := range impact.Columns {
+		if col.Skipped > 0 {
+			warnStyle.Printf("   ⚠️ %s values of %s left as they were: not numbers\n", formatNumber(col.Skipped), col.Mask.Name)
+		}
+	}
+	fmt.Println()
+}
+
+// formatMaskedDistinct gives the distinct values of a column before and after
+// masking, with the share collapsed, as 412 → 38 (-90.8%).
+func formatMaskedDistinct(col mask.ColumnImpact) string {
+	distinct := formatNumber(col.OldDistinct) + " → " + formatNumber(col.NewDistinct)
+	if collapsed := col.Collapsed(); collapsed > 0 {
+		distinct += fmt.Sprintf(" (-%.1f%%)", collapsed*100)
+	}
+	return distinct
+}
+
+func formatJoins(col mask.ColumnImpact) string {
+	switch {
+	case col.Joinable():
+		return "kept"
+	case col.NewDistinct <= 1:
+		return "lost"
+	default:
+		return fmt.Sprintf("%.1f%% of keys merged", col.Collapsed()*100)
+	}
+}
+
+// formatMaskedDistribution tells how the distribution of a column moved:
+// the change in spread and the drift of numeric columns, the drift of
+// generalized values, or that the values were replaced.
+func formatMaskedDistribution(col mask.ColumnImpact) string {
+	switch {
+	case col.Replaced():
+		return "values replaced"
+	case col.OldType != col.NewType:
+		return fmt.Sprintf("%s → %s", col.OldType, col.NewType)
+	}
+
+	parts := make([]string, 0, 3)
+	if change, ok := col.StdDevChange(); ok {
+		// Shifts that round to zero print as +0.00 rather than -0.00
+		shift := math.Round(col.Diff.MeanShift*100) / 100
+		parts = append(parts, fmt.Sprintf("stddev %+.1f%%", change*100), fmt.Sprintf("mean %+.2fσ", shift+0))
+	}
+	parts = append(parts, formatDriftMetrics(col.Diff))
+	if col.Diff.Drifted() {
+		parts = append(parts, "drifted")
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,63 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/events"
+)
+
+// ProgressPrinter draws a progress line for each source being profiled and
+// prints warnings above it. It handles the events of a bus, so it must be
+// registered with Handle: the line is gone by the time profiling returns and
+// the report is printed.
+type ProgressPrinter struct {
+	w       io.Writer
+	started map[string]time.Time
+	drawn   bool
+}
+
+func NewProgressPrinter(w io.Writer) *ProgressPrinter {
+	return &ProgressPrinter{w: w, started: make(map[string]time.Time)}
+}
+
+func (p *ProgressPrinter) Handle(e events.Event) {
+	switch e.Kind {
+	case events.Started:
+		p.started[e.Source] = e.Time
+	case events.Progress:
+		p.clear()
+		elapsed := e.Time.Sub(p.started[e.Source])
+		fmt.Fprintf(p.w, "⏳ %s: %s rows (%.1fs)", truncateLeft(e.Source, 50), formatNumber(int(e.Rows)), elapsed.Seconds())
+		p.drawn = true
+	case events.Warning:
+		p.clear()
+		fmt.Fprintf(p.w, "%s %s: %s\n", warnStyle.Sprint("⚠️ "), e.Source, e.Message)
+	case events.Finished:
+		p.clear()
+		delete(p.started, e.Source)
+	}
+}
+
+// clear erases the progress line, if one is drawn.
+func (p *ProgressPrinter) clear() {
+	if p.drawn {
+		fmt.Fprint(p.w, "\r\033[K")
+		p.drawn = false
+	}
+}
+
+// EventLogger writes every event to w as a line of JSON.
+type EventLogger struct {
+	encoder *json.Encoder
+}
+
+func NewEventLogger(w io.Writer) *EventLogger {
+	return &EventLogger{encoder: json.NewEncoder(w)}
+}
+
+func (l *EventLogger) Handle(e events.Event) {
+	l.encoder.Encode(e)
+}
@@ -0,0 +1,67 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/reconcile"
+)
+
+func PrintReconciliationReport(result *reconcile.Result) {
+	fmt.Println("🔁 Reconciliation Summary:")
+
+	rowMark := successStyle.Sprint("✓")
+	if !result.RowCountMatch() {
+		rowMark = errorStyle.Sprint("✗")
+	}
+	fmt.Printf("   %s Rows: %s vs %s\n", rowMark, formatNumber(result.RowCount1), formatNumber(result.RowCount2))
+
+	digestMark := successStyle.Sprint("✓")
+	if !result.DigestMatch() {
+		digestMark = errorStyle.Sprint("✗")
+	}
+	fmt.Printf("   %s Content digest: %s vs %s\n", digestMark, result.Digest1, result.Digest2)
+	fmt.Println()
+
+	if len(result.OnlyInSource1) > 0 || len(result.OnlyInSource2) > 0 {
+		fmt.Println("🧩 Schema Differences:")
+		for _, name := range result.OnlyInSource1 {
+			fmt.Printf("   • '%s' only in %s\n", name, result.Source1)
+		}
+		for _, name := range result.OnlyInSource2 {
+			fmt.Printf("   • '%s' only in %s\n", name, result.Source2)
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("🔍 Column Reconciliation:")
+	fmt.Printf("   %-20s %-8s %s\n", "NAME", "DIGEST", "MISMATCHES")
+	fmt.Printf("   %s\n", strings.Repeat("─", 76))
+
+	for _, col := range result.Columns {
+		colName := col.Name
+		if len(colName) > 20 {
+			colName = colName[:17] + "..."
+		}
+
+		digestStr := "match"
+		if !col.DigestMatch {
+			digestStr = "differ"
+		}
+
+		mismatchStr := "-"
+		if len(col.Mismatches) > 0 {
+			mismatchStr = strings.Join(col.Mismatches, "; ")
+		}
+
+		fmt.Printf("   %-20s %-8s %s\n", colName, digestStr, mismatchStr)
+	}
+
+	fmt.Println()
+
+	if result.Matched() {
+		successStyle.Println("✅ Sources reconcile")
+	} else {
+		errorStyle.Printf("❌ %d mismatched column(s)\n", len(result.MismatchedColumns()))
+	}
+}
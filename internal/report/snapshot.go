@@ -0,0 +1,68 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/snapshot"
+)
+
+func PrintSnapshotSummary(snap *snapshot.Snapshot) {
+	fmt.Printf("🗄️  Schema Snapshot (%s, %d tables):\n", snap.Engine, len(snap.Tables))
+	fmt.Printf("   %-32s %-8s %-14s %s\n", "TABLE", "COLUMNS", "ROWS (EST.)", "PRIMARY KEY")
+	fmt.Printf("   %s\n", strings.Repeat("─", 76))
+
+	for _, table := range snap.Tables {
+		name := table.QualifiedName()
+		if len(name) > 32 {
+			name = name[:29] + "..."
+		}
+
+		primaryKey := "-"
+		if len(table.PrimaryKey) > 0 {
+			primaryKey = strings.Join(table.PrimaryKey, ", ")
+		}
+
+		fmt.Printf("   %-32s %-8d %-14s %s\n", name, len(table.Columns), formatNumber(int(table.RowEstimate)), primaryKey)
+	}
+	fmt.Println()
+}
+
+func PrintSchemaDiffReport(diff *snapshot.Diff) {
+	fmt.Println("🧩 Schema Drift:")
+	if !diff.Changed() {
+		successStyle.Println("   • No schema changes")
+		fmt.Println()
+		return
+	}
+
+	for _, name := range diff.AddedTables {
+		successStyle.Printf("   + table %s\n", name)
+	}
+	for _, name := range diff.RemovedTables {
+		errorStyle.Printf("   - table %s\n", name)
+	}
+	if len(diff.AddedTables) > 0 || len(diff.RemovedTables) > 0 {
+		fmt.Println()
+	}
+
+	for _, table := range diff.Tables {
+		fmt.Printf("   %s:\n", table.Name)
+		for _, col := range table.AddedColumns {
+			successStyle.Printf("     + %s (%s)\n", col.Name, col.Type)
+		}
+		for _, col := range table.RemovedColumns {
+			errorStyle.Printf("     - %s (%s)\n", col.Name, col.Type)
+		}
+		for _, change := range table.ChangedColumns {
+			warnStyle.Printf("     ~ %s: %s\n", change.Name, strings.Join(change.Changes, ", "))
+		}
+		for _, constraint := range table.AddedConstraints {
+			successStyle.Printf("     + %s\n", constraint.Key())
+		}
+		for _, constraint := range table.RemovedConstraints {
+			errorStyle.Printf("     - %s\n", constraint.Key())
+		}
+		fmt.Println()
+	}
+}
@@ -0,0 +1,72 @@
+package report
+
+import (
+	"fmt"
+
+	"github.com/kamalm96/datasleuth/internal/split"
+)
+
+// maxSplitStrata is the number of strata listed in the terminal.
+const maxSplitStrata = 20
+
+// PrintSplitReport prints where the rows of a split went, the shares of its
+// strata, and whether the train and test sets are comparable.
+func PrintSplitReport(result *split.Result) {
+	fmt.Printf("\n📊 Dataset: %s (%s rows)\n\n", result.Source, formatNumber(result.Rows))
+	fmt.Printf("✂️ Split (seed %d):\n", result.Seed)
+	fmt.Printf("   • Train: %s (%s rows, %.1f%%)\n", result.TrainFile, formatNumber(result.TrainRows), percentOf(result.TrainRows, result.Rows))
+	fmt.Printf("   • Test:  %s (%s rows, %.1f%%)\n", result.TestFile, formatNumber(result.TestRows), percentOf(result.TestRows, result.Rows))
+	fmt.Println()
+
+	if result.StratifyBy != "" {
+		fmt.Printf("⚖️ Strata of %s:\n", result.StratifyBy)
+		fmt.Printf("   %-24s %10s %10s %10s %8s\n", "VALUE", "ROWS", "TRAIN", "TEST", "TRAIN %")
+		for i, stratum := range result.Strata {
+			if i == maxSplitStrata {
+				fmt.Printf("   … and %d more\n", len(result.Strata)-maxSplitStrata)
+				break
+			}
+			value := stratum.Value
+			if value == "" {
+				value = "(missing)"
+			}
+			if len(value) > 24 {
+				value = value[:21] + "..."
+			}
+			fmt.Printf("   %-24s %10s %10s %10s %7.1f%%\n", value, formatNumber(stratum.Rows()),
+				formatNumber(stratum.Train), formatNumber(stratum.Test), stratum.TrainShare()*100)
+		}
+		fmt.Println()
+	}
+
+	comparison := result.Comparison
+	fmt.Println("🔍 Comparability (train vs test):")
+	for _, col := range comparison.AddedColumns {
+		warnStyle.Printf("   • %s (%s) appears only in the test set\n", col.Name, col.DataType)
+	}
+	for _, col := range comparison.RemovedColumns {
+		warnStyle.Printf("   • %s (%s) appears only in the train set\n", col.Name, col.DataType)
+	}
+	for _, change := range comparison.RetypedColumns {
+		warnStyle.Printf("   • %s is %s in the train set and %s in the test set\n", change.Name, change.OldType, change.NewType)
+	}
+	for _, col := range comparison.Columns {
+		if col.Drifted() {
+			warnStyle.Printf("   • %s drifted (%s)\n", col.Name, formatDriftMetrics(col))
+		}
+	}
+	fmt.Printf("   • Drift score: %.1f%% of columns drifted\n", comparison.DriftScore())
+	if result.Comparable() {
+		successStyle.Println("   ✅ The train and test sets are comparable")
+	} else {
+		warnStyle.Println("   ⚠️ The train and test sets differ")
+	}
+	fmt.Println()
+}
+
+func percentOf(part, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return float64(part) / float64(whole) * 100
+}
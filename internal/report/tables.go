@@ -0,0 +1,271 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+type JSONTablesReport struct {
+	Source      string       `json:"source"`
+	Tables      []JSONReport `json:"tables"`
+	GeneratedAt string       `json:"generated_at"`
+}
+
+type TablesHTMLTemplateData struct {
+	Label       string // Table or Sheet
+	SourceLabel string // Database or Workbook
+	Source      string
+	Profiles    []*profiler.DatasetProfile
+	Reports     []string
+	GeneratedAt string
+}
+
+// tableLabel names what profile.Table refers to: a table of a database or
+// a sheet of a workbook.
+func tableLabel(profile *profiler.DatasetProfile) string {
+	if profile.Format == "Excel" {
+		return "Sheet"
+	}
+	return "Table"
+}
+
+// sourceLabels names the parts and the whole of a multi-table source:
+// Table and Database, or Sheet and Workbook.
+func sourceLabels(profiles []*profiler.DatasetProfile) (string, string) {
+	if len(profiles) > 0 && profiles[0].Format == "Excel" {
+		return "Sheet", "Workbook"
+	}
+	return "Table", "Database"
+}
+
+// formatSchema lists the columns of a profile with their detected types in
+// column name order, cut to at most width characters when width > 0.
+func formatSchema(profile *profiler.DatasetProfile, width int) string {
+	names := make([]string, 0, len(profile.Columns))
+	for name := range profile.Columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s (%s)", name, profile.Columns[name].DataType)
+	}
+
+	schema := strings.Join(parts, ", ")
+	if width > 0 && len([]rune(schema)) > width {
+		schema = string([]rune(schema)[:width-3]) + "..."
+	}
+	return schema
+}
+
+// PrintTablesReport prints a summary of every table or sheet, with its
+// schema, followed by the full report of each one.
+func PrintTablesReport(source string, profiles []*profiler.DatasetProfile, verbose bool) {
+	label, _ := sourceLabels(profiles)
+
+	fmt.Printf("🗄️  %ss in %s:\n", label, source)
+	fmt.Printf("   %-24s %12s %8s %8s %8s\n", strings.ToUpper(label), "ROWS", "COLUMNS", "SCORE", "ISSUES")
+	fmt.Printf("   %s\n", strings.Repeat("─", 64))
+	for _, profile := range profiles {
+		name := profile.Table
+		if len(name) > 24 {
+			name = name[:21] + "..."
+		}
+		fmt.Printf("   %-24s %12s %8d %8d %8d\n", name, formatNumber(profile.RowCount),
+			profile.ColumnCount, profile.QualityScore, len(collectAllIssues(profile)))
+		fmt.Printf("     └─ %s\n", formatSchema(profile, 70))
+	}
+	fmt.Println()
+
+	for _, profile := range profiles {
+		headerStyle.Printf("━━━ %s: %s ━━━\n\n", tableLabel(profile), profile.Table)
+		PrintTerminalReport(profile, verbose)
+	}
+}
+
+func GenerateTablesJSONReport(source string, profiles []*profiler.DatasetProfile, outputPath string) error {
+	report := JSONTablesReport{
+		Source:      source,
+		GeneratedAt: time.Now().Format(time.RFC3339),
+	}
+
+	// Each table report is encoded in turn rather than all of them at once
+	return writeJSONFile(outputPath, func(w io.Writer) error {
+		return encodeSpliced(w, report, "", "tables", func(w io.Writer) error {
+			if len(profiles) == 0 {
+				_, err := io.WriteString(w, "[]")
+				return err
+			}
+			for i, profile := range profiles {
+				separator := "[\n    "
+				if i > 0 {
+					separator = ",\n    "
+				}
+				if _, err := io.WriteString(w, separator); err != nil {
+					return fmt.Errorf("failed to write JSON report to file: %w", err)
+				}
+				if err := encodeJSONReport(w, profile, "    "); err != nil {
+					return err
+				}
+			}
+			_, err := io.WriteString(w, "\n  ]")
+			return err
+		})
+	})
+}
+
+func GenerateTablesMarkdownReport(source string, profiles []*profiler.DatasetProfile, outputPath string) error {
+	var content strings.Builder
+
+	label, sourceLabel := sourceLabels(profiles)
+
+	content.WriteString(fmt.Sprintf("# DataSleuth %s Profile: %s\n\n", sourceLabel, filepath.Base(source)))
+	content.WriteString(fmt.Sprintf("| %s | Rows | Columns | Quality Score | Schema |\n", label))
+	content.WriteString("|-------|------|---------|---------------|--------|\n")
+	for _, profile := range profiles {
+		content.WriteString(fmt.Sprintf("| %s | %s | %d | %d/100 | %s |\n", escapeTableCell(profile.Table),
+			formatNumber(profile.RowCount), profile.ColumnCount, profile.QualityScore, escapeTableCell(formatSchema(profile, 0))))
+	}
+	content.WriteString("\n")
+
+	for _, profile := range profiles {
+		content.WriteString("---\n\n")
+		writeMarkdownProfile(&content, profile)
+	}
+
+	content.WriteString("---\n")
+	content.WriteString("Generated by DataSleuth v0.1.0 - Fast dataset profiling and validation from the command line\n")
+
+	if err := os.WriteFile(outputPath, []byte(content.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write Markdown report to file: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateTablesHTMLReport writes one HTML report per table next to
+// outputPath and an index page at outputPath linking to them.
+func GenerateTablesHTMLReport(source string, profiles []*profiler.DatasetProfile, outputPath string) error {
+	base := strings.TrimSuffix(outputPath, filepath.Ext(outputPath))
+
+	label, sourceLabel := sourceLabels(profiles)
+
+	data := TablesHTMLTemplateData{
+		Label:       label,
+		SourceLabel: sourceLabel,
+		Source:      filepath.Base(source),
+		Profiles:    profiles,
+		Reports:     make([]string, 0, len(profiles)),
+		GeneratedAt: time.Now().Format("January 2, 2006 15:04:05"),
+	}
+
+	for _, profile := range profiles {
+		tablePath := fmt.Sprintf("%s_%s.html", base, strings.NewReplacer("/", "_", "\\", "_").Replace(profile.Table))
+		if err := GenerateHTMLReport(profile, tablePath); err != nil {
+			return fmt.Errorf("failed to generate report for %s %s: %w", strings.ToLower(label), profile.Table, err)
+		}
+		data.Reports = append(data.Reports, filepath.Base(tablePath))
+	}
+
+	tmpl, err := template.New("tables").Funcs(template.FuncMap{
+		"formatNumber": formatNumberHTML,
+		"formatSchema": formatSchema,
+		"lower":        strings.ToLower,
+	}).Parse(tablesHTMLTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse HTML template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to render HTML template: %w", err)
+	}
+
+	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write HTML report to file: %w", err)
+	}
+
+	return nil
+}
+
+const tablesHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>DataSleuth {{.SourceLabel}} Profile: {{.Source}}</title>
+    <style>
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif;
+            color: #202124;
+            background-color: #f8f9fa;
+            margin: 0;
+            padding: 20px;
+        }
+
+        .container {
+            max-width: 1000px;
+            margin: 0 auto;
+            background: white;
+            border-radius: 8px;
+            box-shadow: 0 1px 3px rgba(0,0,0,0.12);
+            padding: 20px;
+        }
+
+        table {
+            width: 100%;
+            border-collapse: collapse;
+        }
+
+        th, td {
+            padding: 12px 15px;
+            text-align: left;
+            border-bottom: 1px solid #dadce0;
+        }
+
+        a {
+            color: #1a73e8;
+        }
+
+        .schema {
+            font-size: 0.85em;
+            color: #5f6368;
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1>{{.Source}}</h1>
+        <p>{{len .Profiles}} {{lower .Label}}s profiled on {{.GeneratedAt}}</p>
+        <table>
+            <tr>
+                <th>{{.Label}}</th>
+                <th>Rows</th>
+                <th>Columns</th>
+                <th>Quality Score</th>
+                <th>Schema</th>
+            </tr>
+            {{range $i, $profile := .Profiles}}
+            <tr>
+                <td><a href="{{index $.Reports $i}}">{{$profile.Table}}</a></td>
+                <td>{{formatNumber $profile.RowCount}}</td>
+                <td>{{$profile.ColumnCount}}</td>
+                <td>{{$profile.QualityScore}}/100</td>
+                <td class="schema">{{formatSchema $profile 0}}</td>
+            </tr>
+            {{end}}
+        </table>
+    </div>
+</body>
+</html>
+`
@@ -0,0 +1,128 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func createTestTables() []*profiler.DatasetProfile {
+	users := createTestProfile()
+	users.Table = "users"
+	orders := createTestProfile()
+	orders.Table = "orders"
+	return []*profiler.DatasetProfile{orders, users}
+}
+
+func TestGenerateTablesJSONReport(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "app.json")
+
+	if err := GenerateTablesJSONReport("app.db", createTestTables(), outputPath); err != nil {
+		t.Fatalf("GenerateTablesJSONReport failed: %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+
+	var report JSONTablesReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		t.Fatalf("Failed to parse report: %v", err)
+	}
+	if len(report.Tables) != 2 || report.Tables[1].Table != "users" {
+		t.Errorf("Unexpected tables in report: %+v", report.Tables)
+	}
+}
+
+func TestGenerateTablesMarkdownReport(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "app.md")
+
+	if err := GenerateTablesMarkdownReport("app.db", createTestTables(), outputPath); err != nil {
+		t.Fatalf("GenerateTablesMarkdownReport failed: %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+
+	for _, expected := range []string{"# DataSleuth Database Profile: app.db", "| users | 1,000 | 3 | 85/100 |", "| Table | orders |"} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected Markdown to contain '%s'", expected)
+		}
+	}
+}
+
+func TestGenerateTablesHTMLReport(t *testing.T) {
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "app.html")
+
+	if err := GenerateTablesHTMLReport("app.db", createTestTables(), outputPath); err != nil {
+		t.Fatalf("GenerateTablesHTMLReport failed: %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	if !strings.Contains(string(content), `href="app_users.html"`) {
+		t.Error("Expected index to link to the users report")
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "app_users.html")); err != nil {
+		t.Errorf("Expected per-table report: %v", err)
+	}
+}
+
+func TestGenerateTablesMarkdownReportWorkbook(t *testing.T) {
+	profiles := createTestTables()
+	for _, profile := range profiles {
+		profile.Format = "Excel"
+	}
+	outputPath := filepath.Join(t.TempDir(), "sales.md")
+
+	if err := GenerateTablesMarkdownReport("sales.xlsx", profiles, outputPath); err != nil {
+		t.Fatalf("GenerateTablesMarkdownReport failed: %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+
+	expected := []string{
+		"# DataSleuth Workbook Profile: sales.xlsx",
+		"| Sheet | Rows | Columns | Quality Score | Schema |",
+		"| Sheet | orders |",
+		formatSchema(profiles[0], 0),
+	}
+	for _, e := range expected {
+		if !strings.Contains(string(content), e) {
+			t.Errorf("Expected Markdown to contain '%s'", e)
+		}
+	}
+
+	report := newJSONReport(profiles[0])
+	if report.Sheet != "orders" || report.Table != "" {
+		t.Errorf("Expected the JSON report to name the sheet, got sheet %q table %q", report.Sheet, report.Table)
+	}
+}
+
+func TestFormatSchema(t *testing.T) {
+	profile := &profiler.DatasetProfile{Columns: map[string]*profiler.ColumnProfile{
+		"name": {Name: "name", DataType: "string"},
+		"age":  {Name: "age", DataType: "integer"},
+	}}
+
+	if got := formatSchema(profile, 0); got != "age (integer), name (string)" {
+		t.Errorf("Unexpected schema: %s", got)
+	}
+	if got := formatSchema(profile, 12); got != "age (inte..." {
+		t.Errorf("Unexpected truncated schema: %s", got)
+	}
+}
@@ -3,6 +3,8 @@ package report
 import (
 	"fmt"
 	"math"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/fatih/color"
@@ -27,7 +29,10 @@ func PrintTerminalReport(profile *profiler.DatasetProfile, verbose bool) {
 	}
 
 	fmt.Println("📋 Dataset Summary:")
-	fmt.Printf("   • Rows: %s\n", formatNumber(profile.RowCount))
+	if profile.Table != "" {
+		fmt.Printf("   • %s: %s\n", tableLabel(profile), profile.Table)
+	}
+	fmt.Printf("   • Rows: %s\n", formatRowCount(profile))
 	fmt.Printf("   • Columns: %d\n", profile.ColumnCount)
 
 	if profile.MissingCells > 0 {
@@ -40,9 +45,31 @@ func PrintTerminalReport(profile *profiler.DatasetProfile, verbose bool) {
 
 	if profile.DuplicateRows > 0 {
 		dupPct := float64(profile.DuplicateRows) / float64(profile.RowCount) * 100
-		fmt.Printf("   • Duplicate rows: %s (%.2f%%)\n", formatNumber(profile.DuplicateRows), dupPct)
+		fmt.Printf("   • %s: %s (%.2f%%)\n", duplicatesLabel(profile), formatNumber(profile.DuplicateRows), dupPct)
 	} else {
-		fmt.Printf("   • Duplicate rows: 0 (0.00%%)\n")
+		fmt.Printf("   • %s: 0 (0.00%%)\n", duplicatesLabel(profile))
+	}
+	for _, group := range profile.DuplicateGroups {
+		fmt.Printf("       %s\n", formatDuplicateGroup(group))
+	}
+	if len(profile.DuplicateGroups) == 0 {
+		for _, key := range profile.DuplicateKeys {
+			fmt.Printf("       %s\n", formatDuplicateKey(profile.UniqueKey, key))
+		}
+	}
+
+	budget := profile.ParseBudget()
+	fmt.Printf("   • Parse errors: %s (%.2f%%)\n", formatNumber(budget.Total), budget.Percent)
+	for _, line := range parseBreakdown(budget) {
+		fmt.Printf("       %s\n", line)
+	}
+
+	for _, note := range profile.Notes {
+		fmt.Printf("   ℹ️  %s\n", note)
+	}
+
+	if verbose && profile.ContentDigest != "" {
+		fmt.Printf("   • Content digest: %s\n", profile.ContentDigest)
 	}
 
 	fmt.Println()
@@ -78,8 +105,12 @@ func PrintTerminalReport(profile *profiler.DatasetProfile, verbose bool) {
 		var statsStr string
 		if col.IsNumeric {
 			statsStr = fmt.Sprintf("mean=%.1f, stddev=%.1f", col.Mean, col.StdDev)
+		} else if col.DateTime != nil {
+			statsStr = fmt.Sprintf("%s, %s", col.DateTime.Granularity, profiler.FormatSpan(col.DateTime.Span()))
 		} else if col.IsDateTime {
 			statsStr = "datetime"
+		} else if col.IsOpaque {
+			statsStr = fmt.Sprintf("opaque, avg %s", profiler.FormatBytes(col.AvgLength))
 		} else if col.IsCategorical && len(col.TopValues) > 0 {
 			topValuesStr := "["
 			for i, val := range col.TopValues {
@@ -113,22 +144,31 @@ func PrintTerminalReport(profile *profiler.DatasetProfile, verbose bool) {
 
 	// Add correlation insights if available
 	if profile.CorrelationMatrix != nil && len(profile.CorrelationMatrix.TopPairs) > 0 {
-		fmt.Println("📊 Correlations:")
+		fmt.Printf("📊 Correlations (%s):\n", correlationRows(profile.CorrelationMatrix))
 		for _, pair := range profile.CorrelationMatrix.TopPairs {
-			if pair.Correlation > 0.7 {
-				fmt.Printf("   • Strong positive correlation (%.2f) between '%s' and '%s'\n",
-					pair.Correlation, pair.Column1, pair.Column2)
-			} else if pair.Correlation < -0.7 {
-				fmt.Printf("   • Strong negative correlation (%.2f) between '%s' and '%s'\n",
-					pair.Correlation, pair.Column1, pair.Column2)
-			} else if math.Abs(pair.Correlation) > 0.5 {
-				fmt.Printf("   • Moderate correlation (%.2f) between '%s' and '%s'\n",
-					pair.Correlation, pair.Column1, pair.Column2)
+			if line := correlationLine(pair); line != "" {
+				fmt.Printf("   • %s\n", line)
 			}
 		}
 		fmt.Println()
 	}
 
+	if len(profile.Partitions) > 0 {
+		printPartitions(profile, verbose)
+	}
+
+	if profile.TimeWindows != nil {
+		printTimeWindows(profile.TimeWindows)
+	}
+
+	if risks := conversionRisks(profile); len(risks) > 0 {
+		fmt.Println("🔁 Type Conversion Risk:")
+		for _, col := range risks {
+			fmt.Printf("   • %s\n", conversionLine(col))
+		}
+		fmt.Println()
+	}
+
 	allIssues := collectAllIssues(profile)
 	if len(allIssues) > 0 {
 		fmt.Println("⚠️ Potential Data Quality Issues:")
@@ -138,7 +178,7 @@ func PrintTerminalReport(profile *profiler.DatasetProfile, verbose bool) {
 		fmt.Println()
 	}
 
-	recommendations := generateRecommendations(profile)
+	recommendations := recommendationMessages(profile)
 	if len(recommendations) > 0 {
 		fmt.Println("💡 Recommendations:")
 		for _, rec := range recommendations {
@@ -153,6 +193,21 @@ func PrintTerminalReport(profile *profiler.DatasetProfile, verbose bool) {
 			fmt.Printf("\n   %s (%s)\n", boldStyle.Sprint(name), col.DataType)
 			fmt.Printf("   ├── Missing: %d (%.2f%%)\n", col.MissingCount, float64(col.MissingCount)/float64(profile.RowCount)*100)
 			fmt.Printf("   ├── Unique:  %d (%.2f%%)\n", col.UniqueCount, float64(col.UniqueCount)/float64(col.Count)*100)
+			if col.Nullability != nil {
+				fmt.Printf("   ├── Nulls:   %s\n", col.Nullability)
+			}
+			if col.Digest != "" {
+				fmt.Printf("   ├── Digest:  %s\n", col.Digest)
+			}
+			if col.Conversion != nil {
+				fmt.Printf("   ├── Cast:    %s\n", col.Conversion)
+			}
+			if col.Rare != nil && col.Rare.Singletons > 0 && !col.IsUnique {
+				fmt.Printf("   ├── Rare:    %s\n", col.Rare)
+			}
+			if len(col.BottomValues) > 0 && !col.IsUnique {
+				fmt.Printf("   ├── Bottom:  %s\n", formatValueCounts(col.BottomValues))
+			}
 
 			if col.IsNumeric {
 				fmt.Printf("   ├── Min:     %v\n", col.Min)
@@ -160,9 +215,30 @@ func PrintTerminalReport(profile *profiler.DatasetProfile, verbose bool) {
 				fmt.Printf("   ├── Mean:    %.4f\n", col.Mean)
 				fmt.Printf("   ├── Median:  %.4f\n", col.Median)
 				fmt.Printf("   ├── StdDev:  %.4f\n", col.StdDev)
+				if r := col.Robust; r != nil {
+					fmt.Printf("   ├── Robust:  trimmed mean %.4f, winsorized stddev %.4f, MAD %.4f (%g%% trim)\n",
+						r.TrimmedMean, r.WinsorizedStdDev, r.MAD, r.Trim*100)
+				}
+				if o := col.Outliers; o != nil {
+					fmt.Printf("   ├── Outlier: %d outside [%.4g, %.4g] by %s%s\n", o.Count, o.Lower, o.Upper, o.Rule(), formatOutlierExamples(o.Examples))
+				}
+				if len(col.Percentiles) > 0 {
+					fmt.Printf("   ├── Pctl:    %s\n", formatPercentiles(col.Percentiles, "%.4g"))
+				}
+				fmt.Printf("   ├── Skew:    %.4f\n", col.Skewness)
+				fmt.Printf("   ├── Kurt:    %.4f\n", col.Kurtosis)
+				if col.Mode != nil {
+					fmt.Printf("   ├── Mode:    %v\n", col.Mode)
+				}
+				if col.NumberFormat != "" {
+					fmt.Printf("   ├── Format:  %s\n", col.NumberFormat)
+				}
+				if len(col.TopValues) > 0 && !col.IsUnique && !profile.Exact {
+					fmt.Printf("   ├── Top:     %s\n", formatValueCounts(col.TopValues))
+				}
 
 				if len(col.HistogramBuckets) > 0 {
-					fmt.Printf("   └── Histogram:\n\n")
+					fmt.Printf("   └── %s:\n\n", histogramLabel(profile, col))
 					maxCount := 0
 					for _, bucket := range col.HistogramBuckets {
 						if bucket.Count > maxCount {
@@ -189,8 +265,49 @@ func PrintTerminalReport(profile *profiler.DatasetProfile, verbose bool) {
 				} else {
 					fmt.Printf("   └── No histogram available\n")
 				}
-			} else if col.IsCategorical && len(col.TopValues) > 0 {
-				fmt.Printf("   └── Top values:\n")
+			} else if d := col.DateTime; d != nil {
+				fmt.Printf("   ├── Min:     %s\n", d.Format(d.Min))
+				fmt.Printf("   ├── Max:     %s\n", d.Format(d.Max))
+				fmt.Printf("   ├── Span:    %s\n", profiler.FormatSpan(d.Span()))
+				fmt.Printf("   ├── Granularity: %s\n", d.Granularity)
+				if col.DateFormat != "" {
+					fmt.Printf("   ├── Format:  %s\n", col.DateFormat)
+				}
+				if col.DateOrder != "" {
+					fmt.Printf("   ├── Order:   %s\n", col.DateOrder)
+				}
+				if d.LargestGap != nil {
+					fmt.Printf("   ├── Gaps:    %d (%d missing periods, largest %s to %s)\n",
+						d.Gaps, d.MissingPeriods, d.Format(d.LargestGap.From), d.Format(d.LargestGap.To))
+				}
+				if len(col.TopValues) > 0 && !col.IsUnique && !profile.Exact {
+					fmt.Printf("   ├── Top:     %s\n", formatValueCounts(col.TopValues))
+				}
+				fmt.Printf("   └── Timeline:\n\n")
+
+				maxCount := 0
+				for _, bucket := range d.Histogram {
+					if bucket.Count > maxCount {
+						maxCount = bucket.Count
+					}
+				}
+
+				maxBarWidth := 40
+				for _, bucket := range d.Histogram {
+					barWidth := 0
+					if maxCount > 0 {
+						barWidth = int(float64(bucket.Count) / float64(maxCount) * float64(maxBarWidth))
+					}
+					fmt.Printf("        [%s to %s] %s %d\n", d.Format(bucket.Start), d.Format(bucket.End), strings.Repeat("█", barWidth), bucket.Count)
+				}
+			} else if col.IsOpaque {
+				fmt.Printf("   ├── Avg size: %s\n", profiler.FormatBytes(col.AvgLength))
+				fmt.Printf("   └── Max size: %s\n", profiler.FormatBytes(float64(col.MaxLength)))
+			} else if (col.IsCategorical || profile.Exact || !col.IsUnique) && len(col.TopValues) > 0 {
+				if col.Text != nil {
+					printTextStats(col, false)
+				}
+				fmt.Printf("   └── %s:\n", topValuesLabel(profile))
 
 				maxCount := 0
 				for _, val := range col.TopValues {
@@ -220,10 +337,16 @@ func PrintTerminalReport(profile *profiler.DatasetProfile, verbose bool) {
 						fmt.Printf("        %-20s %s %d (%.2f%%)\n", valueStr, bar, val.Count, valuePct)
 					}
 				}
+			} else if col.Text != nil {
+				printTextStats(col, true)
 			} else {
 				fmt.Printf("   └── No detailed statistics available\n")
 			}
 
+			for _, note := range col.Notes {
+				fmt.Printf("   ℹ️  %s\n", note)
+			}
+
 			if len(col.QualityIssues) > 0 {
 				fmt.Println("\n   Quality Issues:")
 				for _, issue := range col.QualityIssues {
@@ -237,6 +360,12 @@ func PrintTerminalReport(profile *profiler.DatasetProfile, verbose bool) {
 				}
 			}
 		}
+
+		if profile.Preview != nil && len(profile.Preview.Rows) > 0 {
+			fmt.Println()
+			headerStyle.Println("📄 DATA PREVIEW")
+			printPreview(profile.Preview)
+		}
 	}
 }
 
@@ -270,8 +399,14 @@ func collectAllIssues(profile *profiler.DatasetProfile) []string {
 		issues = append(issues, issue.Description)
 	}
 
-	for colName, col := range profile.Columns {
-		for _, issue := range col.QualityIssues {
+	names := make([]string, 0, len(profile.Columns))
+	for name := range profile.Columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, colName := range names {
+		for _, issue := range profile.Columns[colName].QualityIssues {
 			issues = append(issues, fmt.Sprintf("Column '%s': %s", colName, issue.Description))
 		}
 	}
@@ -279,69 +414,357 @@ func collectAllIssues(profile *profiler.DatasetProfile) []string {
 	return issues
 }
 
-func generateRecommendations(profile *profiler.DatasetProfile) []string {
-	recommendations := make([]string, 0)
+// profileRecommendations returns the recommendations made when profiling,
+// or runs the built-in rules for profiles that carry none.
+func profileRecommendations(profile *profiler.DatasetProfile) []profiler.Recommendation {
+	if profile.Recommendations != nil {
+		return profile.Recommendations
+	}
+	return profiler.DefaultRecommendationEngine().Recommend(profile)
+}
+
+// recommendationMessages lists the messages of the recommendations of a
+// profile.
+func recommendationMessages(profile *profiler.DatasetProfile) []string {
+	recommendations := profileRecommendations(profile)
+	messages := make([]string, len(recommendations))
+	for i, r := range recommendations {
+		messages[i] = r.Message
+	}
+	return messages
+}
+
+// printTimeWindows compares the latest time window with the one before it.
+func printTimeWindows(w *profiler.TimeWindows) {
+	fmt.Printf("🕒 Time Windows (%s):\n", windowTitle(w))
+	fmt.Printf("   • %s\n", windowRange(w))
+	if w.LatestRows == 0 || w.PreviousRows == 0 {
+		fmt.Println()
+		return
+	}
 
-	columnsWithMissing := make([]string, 0)
-	for colName, col := range profile.Columns {
-		if col.MissingCount > 0 && float64(col.MissingCount)/float64(profile.RowCount) > 0.05 {
-			columnsWithMissing = append(columnsWithMissing, colName)
+	fmt.Printf("   %-16s %-16s %-16s %s\n", "NAME", "MISSING", "DISTINCT", "MEAN")
+	fmt.Printf("   %s\n", strings.Repeat("─", 76))
+	for _, d := range w.Columns {
+		colName := d.Column
+		if len(colName) > 16 {
+			colName = colName[:13] + "..."
+		}
+		line := fmt.Sprintf("   %-16s %-16s %-16s %s\n", colName, formatWindowMissing(d), formatWindowDistinct(d), formatWindowMean(d))
+		if len(d.Changes) > 0 {
+			warnStyle.Print(line)
+		} else {
+			fmt.Print(line)
 		}
 	}
 
-	if len(columnsWithMissing) > 0 {
-		if len(columnsWithMissing) <= 3 {
-			for _, colName := range columnsWithMissing {
-				recommendations = append(recommendations,
-					fmt.Sprintf("Consider imputing missing values in '%s' column", colName))
-			}
+	if regressions := w.Regressions(); len(regressions) > 0 {
+		fmt.Println("   Recent regressions:")
+		for _, d := range regressions {
+			warnStyle.Printf("   • %s\n", windowRegressionLine(d))
+		}
+	}
+	fmt.Println()
+}
+
+// maxPartitionRows is how many partitions are listed without --verbose
+// before only the flagged ones are.
+const maxPartitionRows = 20
+
+// printPartitions lists the partitions of a partitioned dataset with their
+// rows and missing cells. Past maxPartitionRows, only the partitions with
+// an issue are listed unless verbose.
+func printPartitions(profile *profiler.DatasetProfile, verbose bool) {
+	flagged := 0
+	for _, part := range profile.Partitions {
+		if part.Issue != "" {
+			flagged++
+		}
+	}
+
+	fmt.Printf("🗂️  Partitions (%d, %d flagged):\n", len(profile.Partitions), flagged)
+	fmt.Printf("   %-36s %6s %12s %8s  %s\n", "PARTITION", "FILES", "ROWS", "MISSING", "ISSUE")
+	fmt.Printf("   %s\n", strings.Repeat("─", 76))
+
+	all := verbose || len(profile.Partitions) <= maxPartitionRows
+	for _, part := range profile.Partitions {
+		if !all && part.Issue == "" {
+			continue
+		}
+		line := fmt.Sprintf("   %-36s %6d %12s %7.2f%%  %s", truncateLeft(part.Name, 36), part.Files,
+			formatNumber(part.RowCount), part.MissingPercent(profile.ColumnCount), part.Issue)
+		if part.Issue != "" {
+			warnStyle.Println(line)
 		} else {
-			recommendations = append(recommendations,
-				"Several columns have high missing value rates and may need imputation")
+			fmt.Println(line)
 		}
 	}
+	if !all {
+		fmt.Printf("   … %d more partitions without issues (--verbose lists all)\n", len(profile.Partitions)-flagged)
+	}
+	fmt.Println()
+}
 
-	columnsWithOutliers := make([]string, 0)
-	for colName, col := range profile.Columns {
-		for _, issue := range col.QualityIssues {
-			if issue.Type == "outliers" {
-				columnsWithOutliers = append(columnsWithOutliers, colName)
-				break
-			}
+func formatRowCount(profile *profiler.DatasetProfile) string {
+	switch {
+	case profile.SampleStrategy == "":
+		return formatNumber(profile.RowCount)
+	case profile.SourceRows > 0:
+		return fmt.Sprintf("%s (%s sample of %s)", formatNumber(profile.RowCount), profile.SampleStrategy, formatNumber(profile.SourceRows))
+	default:
+		return fmt.Sprintf("%s (%s sample)", formatNumber(profile.RowCount), profile.SampleStrategy)
+	}
+}
+
+// FormatFileSize describes the size of the profiled source in MB, with the
+// codec and uncompressed size of compressed sources. It returns "" when the
+// size is unknown.
+func FormatFileSize(profile *profiler.DatasetProfile) string {
+	mb := func(size int64) string {
+		return fmt.Sprintf("%.2f MB", float64(size)/(1024*1024))
+	}
+
+	switch {
+	case profile.Compression == "" && profile.FileSize > 0:
+		return mb(profile.FileSize)
+	case profile.Compression == "":
+		return ""
+	case profile.FileSize > 0 && profile.UncompressedSize > 0:
+		return fmt.Sprintf("%s (%s, %s uncompressed)", mb(profile.FileSize), profile.Compression, mb(profile.UncompressedSize))
+	case profile.FileSize > 0:
+		return fmt.Sprintf("%s (%s)", mb(profile.FileSize), profile.Compression)
+	case profile.UncompressedSize > 0:
+		return fmt.Sprintf("%s uncompressed (%s)", mb(profile.UncompressedSize), profile.Compression)
+	default:
+		return ""
+	}
+}
+
+// histogramLabel names the histogram binning of col, or of the profile when
+// the column does not record it.
+func histogramLabel(profile *profiler.DatasetProfile, col *profiler.ColumnProfile) string {
+	binning := col.HistogramBinning
+	if binning == "" {
+		binning = profile.HistogramBinning
+	}
+	if binning == "" {
+		return "Histogram"
+	}
+	return fmt.Sprintf("Histogram (%s)", binning)
+}
+
+// topValuesLabel names the value listing of a column, which is complete in
+// exact mode.
+func topValuesLabel(profile *profiler.DatasetProfile) string {
+	if profile.Exact {
+		return "Frequencies"
+	}
+	return "Top values"
+}
+
+// formatDuplicateGroup prints a set of identical rows as
+// rows 2, 9: city=Paris, id=4.
+func formatDuplicateGroup(group profiler.DuplicateGroup) string {
+	rows := make([]string, len(group.Rows))
+	for i, row := range group.Rows {
+		rows[i] = strconv.Itoa(row)
+	}
+
+	names := make([]string, 0, len(group.Values))
+	for name := range group.Values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make([]string, len(names))
+	for i, name := range names {
+		values[i] = fmt.Sprintf("%s=%s", name, group.Values[name])
+	}
+	return fmt.Sprintf("rows %s: %s", strings.Join(rows, ", "), strings.Join(values, ", "))
+}
+
+// duplicatesLabel names the duplicate count of profile, with the columns
+// of its unique key.
+func duplicatesLabel(profile *profiler.DatasetProfile) string {
+	if len(profile.UniqueKey) == 0 {
+		return "Duplicate rows"
+	}
+	return fmt.Sprintf("Duplicate rows by key (%s)", strings.Join(profile.UniqueKey, ", "))
+}
+
+// parseBreakdown lists where the parse errors of budget come from: the
+// bad rows skipped, then each column by name, as
+// "amount: 3 unparseable, 1 non-finite".
+func parseBreakdown(budget profiler.ParseBudget) []string {
+	var lines []string
+	if budget.BadRows > 0 {
+		lines = append(lines, fmt.Sprintf("%s bad rows skipped", formatNumber(budget.BadRows)))
+	}
+
+	names := make([]string, 0, len(budget.Columns))
+	for name := range budget.Columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		lines = append(lines, name+": "+formatParseErrors(budget.Columns[name]))
+	}
+	return lines
+}
+
+// formatParseErrors prints the parse errors of a column by kind.
+func formatParseErrors(e profiler.ParseErrors) string {
+	var parts []string
+	if e.Unparseable > 0 {
+		parts = append(parts, fmt.Sprintf("%s unparseable", formatNumber(e.Unparseable)))
+	}
+	if e.NonFinite > 0 {
+		parts = append(parts, fmt.Sprintf("%s non-finite", formatNumber(e.NonFinite)))
+	}
+	if e.Encoding > 0 {
+		parts = append(parts, fmt.Sprintf("%s with encoding errors", formatNumber(e.Encoding)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatDuplicateKey prints a repeated key value as
+// "order_id=1042: 3 rows".
+func formatDuplicateKey(columns []string, key profiler.DuplicateKey) string {
+	values := make([]string, len(key.Values))
+	for i, value := range key.Values {
+		if i < len(columns) {
+			value = columns[i] + "=" + value
 		}
+		values[i] = value
 	}
+	return fmt.Sprintf("%s: %d rows", strings.Join(values, ", "), key.Rows)
+}
+
+// formatPercentiles lists percentiles as p1 1.5, p5 2, ...
+func formatPercentiles(percentiles []profiler.Percentile, format string) string {
+	parts := make([]string, len(percentiles))
+	for i, p := range percentiles {
+		parts[i] = fmt.Sprintf("p%d "+format, p.Rank, p.Value)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printPreview prints the preview rows as a table, cutting values to 20
+// characters.
+func printPreview(preview *profiler.Preview) {
+	const maxWidth = 20
 
-	if len(columnsWithOutliers) > 0 {
-		if len(columnsWithOutliers) <= 3 {
-			for _, colName := range columnsWithOutliers {
-				recommendations = append(recommendations,
-					fmt.Sprintf("Check outliers in '%s' column", colName))
+	cut := func(value string) string {
+		if runes := []rune(value); len(runes) > maxWidth {
+			return string(runes[:maxWidth-3]) + "..."
+		}
+		return value
+	}
+
+	widths := make([]int, len(preview.Columns))
+	for i, name := range preview.Columns {
+		widths[i] = len([]rune(cut(name)))
+	}
+	for _, row := range preview.Rows {
+		for i, value := range row {
+			if n := len([]rune(cut(value))); n > widths[i] {
+				widths[i] = n
 			}
-		} else {
-			recommendations = append(recommendations,
-				"Multiple numeric columns contain outliers")
 		}
 	}
 
-	for colName, col := range profile.Columns {
-		if col.DataType == "string" && !col.IsCategorical && col.UniqueCount > 0 &&
-			col.UniqueCount <= 100 && float64(col.UniqueCount)/float64(col.Count) <= 0.2 {
-			recommendations = append(recommendations,
-				fmt.Sprintf("Column '%s' might benefit from being treated as categorical", colName))
+	printRow := func(values []string) {
+		cells := make([]string, len(values))
+		for i, value := range values {
+			value = cut(value)
+			cells[i] = value + strings.Repeat(" ", widths[i]-len([]rune(value)))
 		}
+		fmt.Printf("   %s\n", strings.TrimRight(strings.Join(cells, "  "), " "))
 	}
 
-	if profile.DuplicateRows > 0 && float64(profile.DuplicateRows)/float64(profile.RowCount) > 0.01 {
-		recommendations = append(recommendations,
-			"Dataset contains duplicate rows - consider deduplication")
+	fmt.Println()
+	printRow(preview.Columns)
+	total := 0
+	for _, width := range widths {
+		total += width + 2
+	}
+	fmt.Printf("   %s\n", strings.Repeat("─", total-2))
+	for _, row := range preview.Rows {
+		printRow(row)
+	}
+}
+
+// formatTextLengths prints the character lengths of a string column as
+// 3 to 12 (avg 7.5).
+func formatTextLengths(t *profiler.TextStats) string {
+	return fmt.Sprintf("%d to %d (avg %.1f)", t.MinLength, t.MaxLength, t.AvgLength)
+}
+
+// formatCasing prints the dominant casing of a string column and its share.
+func formatCasing(t *profiler.TextStats) string {
+	if t.Casing == "" {
+		return "no letters"
+	}
+	return fmt.Sprintf("%s (%.1f%%)", t.Casing, t.CasingPercent)
+}
+
+// formatPatterns lists the dominant patterns of a string column as
+// AAA-9999 (95.0%), AAA-999 (5.0%).
+func formatPatterns(t *profiler.TextStats, count int) string {
+	parts := make([]string, len(t.Patterns))
+	for i, p := range t.Patterns {
+		percent := 0.0
+		if count > 0 {
+			percent = float64(p.Count) / float64(count) * 100
+		}
+		parts[i] = fmt.Sprintf("%s (%.1f%%)", p.Value, percent)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formattingLabels name the kinds of formatting problems in reports.
+var formattingLabels = map[string]string{
+	profiler.FormattingPadded:       "padded",
+	profiler.FormattingEmbedded:     "with tabs or line breaks",
+	profiler.FormattingNonPrintable: "non-printable",
+	profiler.FormattingCaseVariants: "case variants",
+}
+
+// formatFormatting counts the values of a string column with each kind of
+// formatting problem, as 12 padded, 40 case variants.
+func formatFormatting(t *profiler.TextStats) string {
+	parts := make([]string, len(t.Formatting))
+	for i, f := range t.Formatting {
+		parts[i] = formatNumber(f.Count) + " " + formattingLabels[f.Kind]
 	}
+	return strings.Join(parts, ", ")
+}
 
-	if len(recommendations) == 0 && profile.QualityScore < 90 {
-		recommendations = append(recommendations,
-			"Review columns with quality issues (marked with ⚠️) for potential improvements")
+// printTextStats prints the lengths, casing and patterns of a string column
+// in the verbose column details, closing the tree when last.
+func printTextStats(col *profiler.ColumnProfile, last bool) {
+	t := col.Text
+	fmt.Printf("   ├── Length:  %s\n", formatTextLengths(t))
+	if t.WhitespaceOnly > 0 {
+		fmt.Printf("   ├── Whitespace-only: %d\n", t.WhitespaceOnly)
+	}
+	if len(t.Formatting) > 0 {
+		fmt.Printf("   ├── Formatting: %s\n", formatFormatting(t))
 	}
+	fmt.Printf("   ├── Casing:  %s\n", formatCasing(t))
 
-	return recommendations
+	branch := "├──"
+	if last {
+		branch = "└──"
+	}
+	fmt.Printf("   %s Patterns:\n", branch)
+	for _, p := range t.Patterns {
+		pattern := p.Value
+		if len([]rune(pattern)) > 20 {
+			pattern = string([]rune(pattern)[:17]) + "..."
+		}
+		fmt.Printf("        %-20s %d (%.2f%%)\n", pattern, p.Count, float64(p.Count)/float64(col.Count)*100)
+	}
 }
 
 func formatNumber(n int) string {
@@ -359,3 +782,72 @@ func formatNumber(n int) string {
 
 	return result
 }
+
+// correlationLine describes a notable correlation, or returns "" for a weak
+// one. Numeric pairs give their rank correlation too, which also catches
+// relationships that are monotonic but not linear.
+func correlationLine(pair profiler.CorrelationPair) string {
+	between := fmt.Sprintf("between '%s' and '%s'", pair.Column1, pair.Column2)
+
+	if pair.Method == profiler.CorrelationCramersV {
+		switch {
+		case pair.Correlation > 0.7:
+			return fmt.Sprintf("Strong association (Cramér's V %.2f) %s", pair.Correlation, between)
+		case pair.Correlation > 0.5:
+			return fmt.Sprintf("Moderate association (Cramér's V %.2f) %s", pair.Correlation, between)
+		}
+		return ""
+	}
+
+	value := fmt.Sprintf("%.2f", pair.Correlation)
+	if pair.Spearman != 0 {
+		value += fmt.Sprintf(", Spearman %.2f", pair.Spearman)
+	}
+
+	switch {
+	case pair.Correlation > 0.7:
+		return fmt.Sprintf("Strong positive correlation (%s) %s", value, between)
+	case pair.Correlation < -0.7:
+		return fmt.Sprintf("Strong negative correlation (%s) %s", value, between)
+	case math.Abs(pair.Correlation) > 0.5:
+		return fmt.Sprintf("Moderate correlation (%s) %s", value, between)
+	case math.Abs(pair.Spearman) > 0.7:
+		return fmt.Sprintf("Strong monotonic, non-linear correlation (%s) %s", value, between)
+	}
+	return ""
+}
+
+// correlationRows says how many rows the correlations were computed from.
+func correlationRows(matrix *profiler.CorrelationMatrix) string {
+	if matrix.Sampled {
+		return fmt.Sprintf("from a sample of %s rows", formatNumber(matrix.Rows))
+	}
+	return fmt.Sprintf("from %s rows", formatNumber(matrix.Rows))
+}
+
+// formatValueCounts lists values with their counts on one line, as
+// a (3), b (2).
+func formatValueCounts(values []profiler.ValueCount) string {
+	parts := make([]string, len(values))
+	for i, val := range values {
+		value := val.Value
+		if len([]rune(value)) > 20 {
+			value = string([]rune(value)[:17]) + "..."
+		}
+		parts[i] = fmt.Sprintf("%s (%d)", value, val.Count)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatOutlierExamples lists outlier values after the bounds, e.g.
+// ", e.g. 5000, 300".
+func formatOutlierExamples(examples []float64) string {
+	if len(examples) == 0 {
+		return ""
+	}
+	values := make([]string, len(examples))
+	for i, v := range examples {
+		values[i] = fmt.Sprintf("%.6g", v)
+	}
+	return ", e.g. " + strings.Join(values, ", ")
+}
@@ -13,19 +13,17 @@ import (
 
 func TestPrintTerminalReport(t *testing.T) {
 	profile := createTestProfile()
+	profile.Preview = &profiler.Preview{
+		Columns: []string{"test_str", "test_int"},
+		Rows:    [][]string{{"value1", "42"}, {"a much longer value than fits", "7"}},
+	}
+	profile.Columns["test_str"].Conversion = &profiler.Conversion{Type: "integer", Converted: 960, Lost: 20}
+	profile.Columns["test_int"].Conversion = &profiler.Conversion{Type: "integer", Converted: 980}
+	profile.Columns["test_int"].TopValues = []profiler.ValueCount{{Value: "42", Count: 5}, {Value: "7", Count: 3}}
+	profile.Columns["test_str"].BottomValues = []profiler.ValueCount{{Value: "value9", Count: 1}}
+	profile.Columns["test_str"].Rare = &profiler.RareValues{Singletons: 4, Percent: 40}
 
-	originalStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
-	PrintTerminalReport(profile, false)
-
-	w.Close()
-	os.Stdout = originalStdout
-
-	var buf bytes.Buffer
-	io.Copy(&buf, r)
-	output := buf.String()
+	output := captureTerminalReport(profile, false)
 
 	expectedStrings := []string{
 		"Dataset Summary",
@@ -44,6 +42,8 @@ func TestPrintTerminalReport(t *testing.T) {
 		"Quality Issues",
 		"Column 'test_int': Missing values: 2.00%",
 		"Recommendations",
+		"Type Conversion Risk",
+		"test_str (string): casting to integer loses 20 values (2.04%)",
 	}
 
 	for _, expected := range expectedStrings {
@@ -51,6 +51,68 @@ func TestPrintTerminalReport(t *testing.T) {
 			t.Errorf("Expected output to contain '%s'", expected)
 		}
 	}
+
+	if strings.Contains(output, "a much longer") {
+		t.Error("Expected no preview outside verbose output")
+	}
+
+	output = captureTerminalReport(profile, true)
+	for _, expected := range []string{
+		"test_str              test_int",
+		"a much longer val...  7",
+		"Length:  6 to 7 (avg 6.0)",
+		"Whitespace-only: 3",
+		"Formatting: 12 padded",
+		"Casing:  lower (100.0%)",
+		"AAAAA99              30 (3.06%)",
+		"Top values:",
+		"Cast:    all 980 values cast to integer",
+		"Top:     42 (5), 7 (3)",
+		"Bottom:  value9 (1)",
+		"Rare:    4 values seen once (40.0% of distinct values)",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected verbose output to contain '%s'", expected)
+		}
+	}
+}
+
+func TestPrintTerminalReportDateTime(t *testing.T) {
+	profile := createTestProfile()
+	addTestDateColumn(profile)
+
+	output := captureTerminalReport(profile, false)
+	for _, expected := range []string{"Columns: 4", "test_date", "daily, 30 days"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s'", expected)
+		}
+	}
+
+	output = captureTerminalReport(profile, true)
+	for _, expected := range []string{
+		"Span:    30 days",
+		"Gaps:    1 (2 missing periods, largest 2024-01-09 to 2024-01-12)",
+		"[2024-01-16 to 2024-01-31]",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected verbose output to contain '%s'", expected)
+		}
+	}
+}
+
+func captureTerminalReport(profile *profiler.DatasetProfile, verbose bool) string {
+	originalStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	PrintTerminalReport(profile, verbose)
+
+	w.Close()
+	os.Stdout = originalStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	return buf.String()
 }
 
 func TestCollectAllIssues(t *testing.T) {
@@ -86,14 +148,94 @@ func TestCollectAllIssues(t *testing.T) {
 func TestGenerateRecommendations(t *testing.T) {
 	profile := createTestProfile()
 
-	recommendations := generateRecommendations(profile)
+	profile.RedundantPairs = []profiler.RedundantPair{{Column1: "state", Column2: "state_code", MatchPercent: 100}}
+
+	recommendations := profileRecommendations(profile)
 
 	if len(recommendations) < 1 {
 		t.Errorf("Expected at least 1 recommendation, got %d", len(recommendations))
 		return
 	}
+	found := false
+	for _, r := range recommendations {
+		if r.Type == profiler.RuleDropRedundant && r.Action == profiler.ActionDropColumn &&
+			r.Message == "Columns 'state' and 'state_code' hold the same values - consider dropping one" {
+			found = true
+			if len(r.Columns) != 2 || r.Columns[0] != "state" || r.Columns[1] != "state_code" {
+				t.Errorf("Expected the redundant recommendation to name both columns, got %v", r.Columns)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Expected a recommendation to drop a redundant column, got %+v", recommendations)
+	}
+}
+
+func TestRecommendationMessages(t *testing.T) {
+	profile := createTestProfile()
+	profile.RedundantPairs = []profiler.RedundantPair{{Column1: "state", Column2: "state_code", MatchPercent: 100}}
+
+	messages := recommendationMessages(profile)
+	if !strings.Contains(strings.Join(messages, "\n"), "Columns 'state' and 'state_code' hold the same values - consider dropping one") {
+		t.Errorf("Expected a recommendation to drop a redundant column, got %v", messages)
+	}
+
+	profile.Recommendations = []profiler.Recommendation{{Type: "custom", Action: "review", Message: "Check the export"}}
+	if messages := recommendationMessages(profile); len(messages) != 1 || messages[0] != "Check the export" {
+		t.Errorf("Expected the recommendations of the profile, got %v", messages)
+	}
+
+	profile.Recommendations = []profiler.Recommendation{}
+	if messages := recommendationMessages(profile); len(messages) != 0 {
+		t.Errorf("Expected no recommendations when all rules are disabled, got %v", messages)
+	}
+}
 
-	t.Logf("Recommendations generated: %v", recommendations)
+func TestCorrelationLine(t *testing.T) {
+	tests := []struct {
+		pair profiler.CorrelationPair
+		want string
+	}{
+		{profiler.CorrelationPair{Column1: "a", Column2: "b", Correlation: 0.92, Spearman: 0.95},
+			"Strong positive correlation (0.92, Spearman 0.95) between 'a' and 'b'"},
+		{profiler.CorrelationPair{Column1: "a", Column2: "b", Correlation: 0.4, Spearman: 0.98},
+			"Strong monotonic, non-linear correlation (0.40, Spearman 0.98) between 'a' and 'b'"},
+		{profiler.CorrelationPair{Column1: "region", Column2: "product", Method: profiler.CorrelationCramersV, Correlation: 0.81},
+			"Strong association (Cramér's V 0.81) between 'region' and 'product'"},
+		{profiler.CorrelationPair{Column1: "a", Column2: "b", Method: profiler.CorrelationCramersV, Correlation: 0.2}, ""},
+		{profiler.CorrelationPair{Column1: "a", Column2: "b", Correlation: -0.3}, ""},
+	}
+	for _, tt := range tests {
+		if got := correlationLine(tt.pair); got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+
+	if got := correlationRows(&profiler.CorrelationMatrix{Rows: 50000, Sampled: true}); got != "from a sample of 50,000 rows" {
+		t.Errorf("Expected the sample size, got %q", got)
+	}
+}
+
+func TestFormatDuplicateKey(t *testing.T) {
+	key := profiler.DuplicateKey{Values: []string{"1042", "EU"}, Rows: 3}
+	if got, want := formatDuplicateKey([]string{"order_id", "region"}, key), "order_id=1042, region=EU: 3 rows"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	profile := &profiler.DatasetProfile{UniqueKey: []string{"order_id", "region"}}
+	if got := duplicatesLabel(profile); got != "Duplicate rows by key (order_id, region)" {
+		t.Errorf("Expected the key in the label, got %q", got)
+	}
+	if got := duplicatesLabel(&profiler.DatasetProfile{}); got != "Duplicate rows" {
+		t.Errorf("Expected whole rows without a key, got %q", got)
+	}
+}
+
+func TestFormatDuplicateGroup(t *testing.T) {
+	group := profiler.DuplicateGroup{Rows: []int{2, 9}, Values: map[string]string{"id": "4", "city": "Paris"}}
+	if got, want := formatDuplicateGroup(group), "rows 2, 9: city=Paris, id=4"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
 }
 
 func TestFormatNumber(t *testing.T) {
@@ -165,6 +307,16 @@ func createTestProfile() *profiler.DatasetProfile {
 					{Value: "value2", Count: 180},
 					{Value: "value3", Count: 150},
 				},
+				Text: &profiler.TextStats{
+					MinLength:      6,
+					MaxLength:      7,
+					AvgLength:      6.03,
+					WhitespaceOnly: 3,
+					Casing:         profiler.CasingLower,
+					CasingPercent:  100,
+					Patterns:       []profiler.ValueCount{{Value: "AAAAA9", Count: 950}, {Value: "AAAAA99", Count: 30}},
+					Formatting:     []profiler.Formatting{{Kind: profiler.FormattingPadded, Count: 12, Examples: []string{" value1"}}},
+				},
 				IsNumeric:     false,
 				IsCategorical: true,
 				QualityIssues: []profiler.QualityIssue{
@@ -186,6 +338,13 @@ func createTestProfile() *profiler.DatasetProfile {
 				Mean:         float64(50),
 				Median:       float64(50),
 				StdDev:       float64(25),
+				Percentiles: []profiler.Percentile{
+					{Rank: 1, Value: 2}, {Rank: 5, Value: 6}, {Rank: 25, Value: 26},
+					{Rank: 75, Value: 75}, {Rank: 95, Value: 95}, {Rank: 99, Value: 99.5},
+				},
+				Skewness: 0.1,
+				Kurtosis: -1.2,
+				Mode:     float64(42),
 				HistogramBuckets: []profiler.HistogramBucket{
 					{LowerBound: 1, UpperBound: 20, Count: 200},
 					{LowerBound: 21, UpperBound: 40, Count: 200},
@@ -234,3 +393,48 @@ func createTestProfile() *profiler.DatasetProfile {
 		},
 	}
 }
+
+func TestFormatFileSize(t *testing.T) {
+	tests := []struct {
+		profile profiler.DatasetProfile
+		want    string
+	}{
+		{profiler.DatasetProfile{FileSize: 1048576}, "1.00 MB"},
+		{profiler.DatasetProfile{}, ""},
+		{profiler.DatasetProfile{FileSize: 524288, Compression: "gzip", UncompressedSize: 3145728}, "0.50 MB (gzip, 3.00 MB uncompressed)"},
+		{profiler.DatasetProfile{FileSize: 524288, Compression: "zstd"}, "0.50 MB (zstd)"},
+		{profiler.DatasetProfile{Compression: "gzip", UncompressedSize: 2097152}, "2.00 MB uncompressed (gzip)"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatFileSize(&tt.profile); got != tt.want {
+			t.Errorf("FormatFileSize: expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+// addTestDateColumn adds a daily datetime column with a two day gap.
+func addTestDateColumn(profile *profiler.DatasetProfile) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	profile.Columns["test_date"] = &profiler.ColumnProfile{
+		Name:        "test_date",
+		DataType:    "datetime",
+		Count:       1000,
+		UniqueCount: 29,
+		IsDateTime:  true,
+		DateTime: &profiler.DateTimeStats{
+			Min:            day(1),
+			Max:            day(31),
+			Precision:      "day",
+			Granularity:    "daily",
+			Gaps:           1,
+			MissingPeriods: 2,
+			LargestGap:     &profiler.TimeGap{From: day(9), To: day(12)},
+			Histogram: []profiler.TimeBucket{
+				{Start: day(1), End: day(16), Count: 450},
+				{Start: day(16), End: day(31), Count: 550},
+			},
+		},
+	}
+	profile.ColumnCount++
+}
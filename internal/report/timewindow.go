@@ -0,0 +1,71 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// windowTitle names the time column and span of the windows.
+func windowTitle(w *profiler.TimeWindows) string {
+	return fmt.Sprintf("latest %s of %s", profiler.FormatSpan(w.Window), w.TimeColumn)
+}
+
+// windowRange describes the latest window and its rows against the
+// previous window.
+func windowRange(w *profiler.TimeWindows) string {
+	if w.LatestEnd.IsZero() {
+		return "no row has a timestamp"
+	}
+	return fmt.Sprintf("%s to %s UTC: %s rows, %s in the window before (%s)",
+		formatWindowTime(w.LatestStart), formatWindowTime(w.LatestEnd),
+		formatNumber(w.LatestRows), formatNumber(w.PreviousRows), formatRowChange(w.LatestRows, w.PreviousRows))
+}
+
+func formatWindowTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04")
+}
+
+func formatRowChange(latest, previous int) string {
+	if previous == 0 {
+		return "no rows before"
+	}
+	return fmt.Sprintf("%+.1f%%", float64(latest-previous)/float64(previous)*100)
+}
+
+// formatWindowMean shows the mean of a numeric column in both windows and
+// the shift in standard deviations of the previous one.
+func formatWindowMean(d profiler.WindowDelta) string {
+	if !d.Numeric {
+		return "-"
+	}
+	mean := fmt.Sprintf("%.4g→%.4g", d.Previous.Mean, d.Latest.Mean)
+	if shift := d.MeanShift(); shift != 0 {
+		mean += fmt.Sprintf(" (%+.1fσ)", shift)
+	}
+	return mean
+}
+
+func formatWindowMissing(d profiler.WindowDelta) string {
+	return fmt.Sprintf("%.1f%%→%.1f%%", d.Previous.MissingPercent, d.Latest.MissingPercent)
+}
+
+func formatWindowDistinct(d profiler.WindowDelta) string {
+	return fmt.Sprintf("%s→%s", formatNumber(d.Previous.Distinct), formatNumber(d.Latest.Distinct))
+}
+
+// windowRegressionLine describes what regressed in a column.
+func windowRegressionLine(d profiler.WindowDelta) string {
+	parts := make([]string, 0, len(d.Changes))
+	for _, change := range d.Changes {
+		switch change {
+		case "missing_rate":
+			parts = append(parts, "missing "+formatWindowMissing(d))
+		case "mean":
+			parts = append(parts, "mean "+formatWindowMean(d))
+		}
+	}
+	return fmt.Sprintf("'%s': %s", d.Column, strings.Join(parts, ", "))
+}
@@ -0,0 +1,93 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/validate"
+)
+
+type JSONValidationReport struct {
+	Source      string                `json:"source"`
+	Baseline    string                `json:"baseline"`
+	Rules       string                `json:"rules,omitempty"`
+	Passed      bool                  `json:"passed"`
+	Failures    int                   `json:"failures"`
+	Checks      []JSONValidationCheck `json:"checks"`
+	GeneratedAt string                `json:"generated_at"`
+}
+
+type JSONValidationCheck struct {
+	Name    string `json:"name"`
+	Column  string `json:"column,omitempty"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message"`
+}
+
+func PrintValidationReport(result *validate.Result) {
+	if result.Baseline != "" {
+		fmt.Printf("📏 Validation against baseline: %s\n", result.Baseline)
+	}
+	if result.Rules != "" {
+		fmt.Printf("📏 Validation against rules: %s\n", result.Rules)
+	}
+
+	passed := 0
+	for _, check := range result.Checks {
+		if check.Passed {
+			passed++
+		}
+	}
+	fmt.Printf("   Checks passed: %s/%s\n", formatNumber(passed), formatNumber(len(result.Checks)))
+	fmt.Println()
+
+	failures := result.Failures()
+	if len(failures) > 0 {
+		fmt.Println("❌ Failed Checks:")
+		for _, check := range failures {
+			if check.Column != "" {
+				fmt.Printf("   %s %s '%s': %s\n", errorStyle.Sprint("✗"), check.Name, check.Column, check.Message)
+			} else {
+				fmt.Printf("   %s %s: %s\n", errorStyle.Sprint("✗"), check.Name, check.Message)
+			}
+		}
+		fmt.Println()
+	}
+
+	switch {
+	case result.Passed() && result.Baseline == "":
+		successStyle.Println("✅ Dataset meets the rules")
+	case result.Passed():
+		successStyle.Println("✅ Dataset is within tolerance of the baseline")
+	default:
+		errorStyle.Printf("❌ %d of %d checks failed\n", len(failures), len(result.Checks))
+	}
+}
+
+func GenerateValidationJSONReport(result *validate.Result, outputPath string) error {
+	return writeJSON(NewJSONValidationReport(result), outputPath)
+}
+
+// NewJSONValidationReport converts result to its JSON form.
+func NewJSONValidationReport(result *validate.Result) JSONValidationReport {
+	report := JSONValidationReport{
+		Source:      result.Source,
+		Baseline:    result.Baseline,
+		Rules:       result.Rules,
+		Passed:      result.Passed(),
+		Failures:    len(result.Failures()),
+		Checks:      make([]JSONValidationCheck, 0, len(result.Checks)),
+		GeneratedAt: time.Now().Format(time.RFC3339),
+	}
+
+	for _, check := range result.Checks {
+		report.Checks = append(report.Checks, JSONValidationCheck{
+			Name:    check.Name,
+			Column:  check.Column,
+			Passed:  check.Passed,
+			Message: check.Message,
+		})
+	}
+
+	return report
+}
@@ -0,0 +1,52 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/validate"
+)
+
+func TestGenerateValidationJSONReport(t *testing.T) {
+	profile := createTestProfile()
+	baseline := createTestProfile()
+	baseline.Columns["test_int"].Mean = 80
+
+	result := validate.AgainstBaseline(profile, baseline, validate.DefaultTolerances())
+
+	tempFile, err := os.CreateTemp("", "validation_*.json")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+	tempFile.Close()
+
+	if err := GenerateValidationJSONReport(result, tempFile.Name()); err != nil {
+		t.Fatalf("GenerateValidationJSONReport failed: %v", err)
+	}
+
+	content, err := os.ReadFile(tempFile.Name())
+	if err != nil {
+		t.Fatalf("Failed to read validation report: %v", err)
+	}
+
+	var parsed JSONValidationReport
+	if err := json.Unmarshal(content, &parsed); err != nil {
+		t.Fatalf("Failed to parse validation report: %v", err)
+	}
+
+	if parsed.Passed || parsed.Failures == 0 {
+		t.Errorf("Expected failed validation, got passed=%v failures=%d", parsed.Passed, parsed.Failures)
+	}
+
+	found := false
+	for _, check := range parsed.Checks {
+		if check.Name == "mean" && check.Column == "test_int" && !check.Passed {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected failed mean check for test_int")
+	}
+}
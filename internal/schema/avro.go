@@ -0,0 +1,120 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+type avroRecord struct {
+	Type      string      `json:"type"`
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace,omitempty"`
+	Doc       string      `json:"doc"`
+	Fields    []avroField `json:"fields"`
+}
+
+type avroField struct {
+	Name    string      `json:"name"`
+	Type    interface{} `json:"type"`
+	Doc     string      `json:"doc,omitempty"`
+	Default interface{} `json:"default,omitempty"`
+}
+
+// avroNullDefault marshals as the null default of a nullable field, which
+// a nil Default would leave out.
+type avroNullDefault struct{}
+
+func (avroNullDefault) MarshalJSON() ([]byte, error) {
+	return []byte("null"), nil
+}
+
+var nonAvroName = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// avroName makes name a valid Avro name: letters, digits and underscores,
+// not starting with a digit.
+func avroName(name string) string {
+	name = strings.Trim(nonAvroName.ReplaceAllString(name, "_"), "_")
+	if name == "" {
+		return "_"
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// Avro writes an Avro record schema of profile with a field per column.
+// Nullable columns are unions with null that default to it, dates and
+// timestamps use the date and timestamp-micros logical types, and 32 bit
+// integers are int, larger ones long. Column names that are not valid Avro
+// names are rewritten, with the original in the doc of the field. A
+// qualified name such as com.example.Orders sets the namespace.
+func Avro(profile *profiler.DatasetProfile, opts DocumentOptions) (string, error) {
+	if len(profile.Columns) == 0 {
+		return "", fmt.Errorf("profile has no columns")
+	}
+
+	record := avroRecord{Type: "record", Name: opts.Name, Doc: strings.Join(provenance(profile), ". ")}
+	if record.Name == "" {
+		record.Name = TableName(profile)
+	}
+	if i := strings.LastIndex(record.Name, "."); i >= 0 {
+		record.Namespace = record.Name[:i]
+		record.Name = record.Name[i+1:]
+	}
+	record.Name = avroName(record.Name)
+
+	// Valid names are kept; rewritten ones take a suffix if they clash
+	columns := Columns(profile, opts.Options)
+	used := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		if avroName(col.Name) == col.Name {
+			used[col.Name] = true
+		}
+	}
+	for _, col := range columns {
+		field := avroField{Name: col.Name, Type: avroType(col.Type)}
+		if avroName(col.Name) != col.Name {
+			field.Name = avroName(col.Name)
+			for base, n := field.Name, 2; used[field.Name]; n++ {
+				field.Name = fmt.Sprintf("%s_%d", base, n)
+			}
+			used[field.Name] = true
+			field.Doc = fmt.Sprintf("Column %q", col.Name)
+		}
+		if col.Nullable {
+			field.Type = []interface{}{"null", field.Type}
+			field.Default = avroNullDefault{}
+		}
+		record.Fields = append(record.Fields, field)
+	}
+
+	content, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(content) + "\n", nil
+}
+
+// avroType is the Avro type of a logical type.
+func avroType(logical string) interface{} {
+	switch logical {
+	case TypeBoolean:
+		return "boolean"
+	case TypeInteger:
+		return "int"
+	case TypeBigInt:
+		return "long"
+	case TypeFloat:
+		return "double"
+	case TypeDate:
+		return map[string]string{"type": "int", "logicalType": "date"}
+	case TypeTimestamp:
+		return map[string]string{"type": "long", "logicalType": "timestamp-micros"}
+	}
+	return "string"
+}
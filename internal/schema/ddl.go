@@ -0,0 +1,218 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// Dialects of the CREATE TABLE statements DDL writes.
+const (
+	DialectPostgres  = "postgres"
+	DialectMySQL     = "mysql"
+	DialectBigQuery  = "bigquery"
+	DialectSnowflake = "snowflake"
+)
+
+// Dialects lists the supported dialects, in the order they are documented.
+var Dialects = []string{DialectPostgres, DialectMySQL, DialectBigQuery, DialectSnowflake}
+
+// DDLOptions tune the CREATE TABLE statement of a profile.
+type DDLOptions struct {
+	Options
+	Dialect string
+	Table   string // table name, derived from the dataset when empty
+}
+
+// maxVarchar is the longest VARCHAR of a dialect; longer strings are
+// declared with its unbounded text type.
+var maxVarchar = map[string]int{
+	DialectPostgres:  10485760,
+	DialectMySQL:     16383, // utf8mb4 in a 65535 byte row
+	DialectSnowflake: 16777216,
+}
+
+// DDL writes a CREATE TABLE statement for the columns of profile. A column
+// without missing values is NOT NULL, and strings are sized from the
+// longest value seen, so a load of a later file with longer or missing
+// values can still be rejected; profile the whole file rather than a
+// sample to keep that unlikely.
+func DDL(profile *profiler.DatasetProfile, opts DDLOptions) (string, error) {
+	if !IsDialect(opts.Dialect) {
+		return "", fmt.Errorf("unknown dialect %q (want %s)", opts.Dialect, strings.Join(Dialects, ", "))
+	}
+	if len(profile.Columns) == 0 {
+		return "", fmt.Errorf("profile has no columns")
+	}
+
+	table := opts.Table
+	if table == "" {
+		table = TableName(profile)
+	}
+
+	var b strings.Builder
+	for _, line := range provenance(profile) {
+		fmt.Fprintf(&b, "-- %s\n", line)
+	}
+
+	fmt.Fprintf(&b, "CREATE TABLE %s (\n", quoteTable(opts.Dialect, table))
+	columns := Columns(profile, opts.Options)
+	for i, col := range columns {
+		fmt.Fprintf(&b, "    %s %s", quote(opts.Dialect, col.Name), typeName(opts.Dialect, col))
+		if !col.Nullable {
+			b.WriteString(" NOT NULL")
+		}
+		if i < len(columns)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(");\n")
+	return b.String(), nil
+}
+
+// IsDialect reports whether dialect is one of Dialects.
+func IsDialect(dialect string) bool {
+	for _, d := range Dialects {
+		if d == dialect {
+			return true
+		}
+	}
+	return false
+}
+
+// typeName is the name of the type of col in dialect.
+func typeName(dialect string, col Column) string {
+	switch dialect {
+	case DialectPostgres:
+		switch col.Type {
+		case TypeBoolean:
+			return "BOOLEAN"
+		case TypeInteger:
+			return "INTEGER"
+		case TypeBigInt:
+			return "BIGINT"
+		case TypeFloat:
+			return "DOUBLE PRECISION"
+		case TypeDate:
+			return "DATE"
+		case TypeTimestamp:
+			return "TIMESTAMP"
+		}
+		return varchar(dialect, col.Length, "TEXT")
+	case DialectMySQL:
+		switch col.Type {
+		case TypeBoolean:
+			return "BOOLEAN"
+		case TypeInteger:
+			return "INT"
+		case TypeBigInt:
+			return "BIGINT"
+		case TypeFloat:
+			return "DOUBLE"
+		case TypeDate:
+			return "DATE"
+		case TypeTimestamp:
+			return "DATETIME"
+		}
+		// MySQL has no unbounded VARCHAR; a column of unknown length
+		// gets the default of 255.
+		if col.Length == 0 {
+			return "VARCHAR(255)"
+		}
+		return varchar(dialect, col.Length, "MEDIUMTEXT")
+	case DialectBigQuery:
+		switch col.Type {
+		case TypeBoolean:
+			return "BOOL"
+		case TypeInteger, TypeBigInt:
+			return "INT64"
+		case TypeFloat:
+			return "FLOAT64"
+		case TypeDate:
+			return "DATE"
+		case TypeTimestamp:
+			return "DATETIME"
+		}
+		return "STRING"
+	default: // DialectSnowflake
+		switch col.Type {
+		case TypeBoolean:
+			return "BOOLEAN"
+		case TypeInteger, TypeBigInt:
+			return "NUMBER(38,0)"
+		case TypeFloat:
+			return "FLOAT"
+		case TypeDate:
+			return "DATE"
+		case TypeTimestamp:
+			return "TIMESTAMP_NTZ"
+		}
+		return varchar(dialect, col.Length, "VARCHAR")
+	}
+}
+
+// varchar is VARCHAR(length), or unbounded when the length is unknown or
+// beyond the longest VARCHAR of dialect.
+func varchar(dialect string, length int, unbounded string) string {
+	if length <= 0 || length > maxVarchar[dialect] {
+		return unbounded
+	}
+	return fmt.Sprintf("VARCHAR(%d)", length)
+}
+
+var simpleIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reserved are the keywords common to the dialects that cannot name a
+// column unquoted, and that header rows tend to use.
+var reserved = map[string]bool{
+	"all": true, "and": true, "as": true, "between": true, "by": true,
+	"case": true, "check": true, "column": true, "constraint": true,
+	"create": true, "cross": true, "current_date": true, "current_time": true,
+	"current_timestamp": true, "default": true, "distinct": true, "else": true,
+	"end": true, "exists": true, "false": true, "for": true, "foreign": true,
+	"from": true, "full": true, "group": true, "having": true, "in": true,
+	"inner": true, "insert": true, "interval": true, "into": true, "is": true,
+	"join": true, "left": true, "like": true, "limit": true, "not": true,
+	"null": true, "on": true, "or": true, "order": true, "outer": true,
+	"primary": true, "references": true, "right": true, "select": true,
+	"table": true, "then": true, "to": true, "true": true, "union": true,
+	"unique": true, "update": true, "user": true, "using": true, "values": true,
+	"when": true, "where": true, "with": true,
+}
+
+// quote quotes name as an identifier of dialect when it has to be: when it
+// is not a plain identifier, is a keyword, or would otherwise change case.
+// Postgres folds unquoted names to lower case and Snowflake to upper case,
+// so a mixed case header is quoted to keep it.
+func quote(dialect, name string) string {
+	needed := !simpleIdentifier.MatchString(name) || reserved[strings.ToLower(name)]
+	switch dialect {
+	case DialectPostgres:
+		needed = needed || name != strings.ToLower(name)
+	case DialectSnowflake:
+		needed = needed || (name != strings.ToLower(name) && name != strings.ToUpper(name))
+	}
+	if !needed {
+		return name
+	}
+
+	switch dialect {
+	case DialectMySQL, DialectBigQuery:
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	default:
+		return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	}
+}
+
+// quoteTable quotes each part of a qualified table name such as
+// analytics.orders.
+func quoteTable(dialect, name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = quote(dialect, part)
+	}
+	return strings.Join(parts, ".")
+}
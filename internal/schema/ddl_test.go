@@ -0,0 +1,161 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func createProfile() *profiler.DatasetProfile {
+	return &profiler.DatasetProfile{
+		Filename: "data/Order Items.csv.gz",
+		RowCount: 100,
+		Columns: map[string]*profiler.ColumnProfile{
+			"order_id": {Name: "order_id", Position: 0, DataType: "integer", Count: 100, Min: float64(1), Max: float64(5000000000)},
+			"quantity": {Name: "quantity", Position: 1, DataType: "integer", Count: 95, MissingCount: 5, Min: float64(1), Max: float64(40)},
+			"price": {
+				Name: "price", Position: 2, DataType: "float", Count: 100,
+				Nullability: &profiler.Nullability{Kind: profiler.NullabilityNotNull},
+			},
+			"Status": {
+				Name: "Status", Position: 3, DataType: "string", Count: 100,
+				Text: &profiler.TextStats{MaxLength: 9},
+			},
+			"paid": {
+				Name: "paid", Position: 4, DataType: "string", Count: 100, UniqueCount: 2,
+				TopValues: []profiler.ValueCount{{Value: "TRUE", Count: 60}, {Value: "false", Count: 40}},
+			},
+			"ordered_on": {
+				Name: "ordered_on", Position: 5, DataType: "datetime", Count: 100,
+				DateTime: &profiler.DateTimeStats{Precision: "day"},
+			},
+			"shipped_at": {
+				Name: "shipped_at", Position: 6, DataType: "datetime", Count: 80, MissingCount: 20,
+				DateTime: &profiler.DateTimeStats{Precision: "second"},
+			},
+			"order": {Name: "order", Position: 7, DataType: "unknown", MissingCount: 100},
+		},
+	}
+}
+
+func TestColumns(t *testing.T) {
+	columns := Columns(createProfile(), Options{})
+
+	want := []Column{
+		{Name: "order_id", Type: TypeBigInt},
+		{Name: "quantity", Type: TypeInteger, Nullable: true},
+		{Name: "price", Type: TypeFloat},
+		{Name: "Status", Type: TypeString, Length: 16},
+		{Name: "paid", Type: TypeBoolean},
+		{Name: "ordered_on", Type: TypeDate},
+		{Name: "shipped_at", Type: TypeTimestamp, Nullable: true},
+		{Name: "order", Type: TypeString, Nullable: true},
+	}
+	if len(columns) != len(want) {
+		t.Fatalf("Expected %d columns, got %+v", len(want), columns)
+	}
+	for i := range want {
+		if columns[i] != want[i] {
+			t.Errorf("Column %d: expected %+v, got %+v", i, want[i], columns[i])
+		}
+	}
+
+	exact := Columns(createProfile(), Options{ExactLengths: true})
+	if exact[3].Length != 9 {
+		t.Errorf("Expected the exact length 9 with ExactLengths, got %d", exact[3].Length)
+	}
+}
+
+func TestDDL(t *testing.T) {
+	tests := []struct {
+		dialect string
+		want    []string
+	}{
+		{DialectPostgres, []string{
+			"CREATE TABLE order_items (\n",
+			"    order_id BIGINT NOT NULL,\n",
+			"    quantity INTEGER,\n",
+			"    price DOUBLE PRECISION NOT NULL,\n",
+			`    "Status" VARCHAR(16) NOT NULL,`,
+			"    paid BOOLEAN NOT NULL,\n",
+			"    ordered_on DATE NOT NULL,\n",
+			"    shipped_at TIMESTAMP,\n",
+			"    \"order\" TEXT\n);\n",
+		}},
+		{DialectMySQL, []string{
+			"    quantity INT,\n",
+			"    price DOUBLE NOT NULL,\n",
+			"    Status VARCHAR(16) NOT NULL,\n",
+			"    shipped_at DATETIME,\n",
+			"    `order` VARCHAR(255)\n",
+		}},
+		{DialectBigQuery, []string{
+			"    order_id INT64 NOT NULL,\n",
+			"    Status STRING NOT NULL,\n",
+			"    paid BOOL NOT NULL,\n",
+			"    `order` STRING\n",
+		}},
+		{DialectSnowflake, []string{
+			"    order_id NUMBER(38,0) NOT NULL,\n",
+			`    "Status" VARCHAR(16) NOT NULL,`,
+			"    shipped_at TIMESTAMP_NTZ,\n",
+			"    \"order\" VARCHAR\n",
+		}},
+	}
+
+	for _, tt := range tests {
+		ddl, err := DDL(createProfile(), DDLOptions{Dialect: tt.dialect})
+		if err != nil {
+			t.Fatalf("DDL(%s) failed: %v", tt.dialect, err)
+		}
+		if !strings.HasPrefix(ddl, "-- Generated by DataSleuth from data/Order Items.csv.gz (100 rows)\n") {
+			t.Errorf("Expected a header comment in the %s DDL, got:\n%s", tt.dialect, ddl)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(ddl, want) {
+				t.Errorf("Expected the %s DDL to contain %q, got:\n%s", tt.dialect, want, ddl)
+			}
+		}
+	}
+}
+
+func TestDDLOptions(t *testing.T) {
+	profile := createProfile()
+	profile.SampleStrategy = "random"
+
+	ddl, err := DDL(profile, DDLOptions{Dialect: DialectPostgres, Table: "staging.Orders"})
+	if err != nil {
+		t.Fatalf("DDL failed: %v", err)
+	}
+	for _, want := range []string{
+		"-- Types, nullability and lengths are inferred from a random sample\n",
+		`CREATE TABLE staging."Orders" (`,
+	} {
+		if !strings.Contains(ddl, want) {
+			t.Errorf("Expected the DDL to contain %q, got:\n%s", want, ddl)
+		}
+	}
+
+	if _, err := DDL(profile, DDLOptions{Dialect: "oracle"}); err == nil {
+		t.Error("Expected an error for an unknown dialect")
+	}
+}
+
+func TestTableName(t *testing.T) {
+	tests := []struct {
+		filename, table, want string
+	}{
+		{"data/Order Items.csv.gz", "", "order_items"},
+		{"2024-events.parquet", "", "t_2024_events"},
+		{"warehouse.db", "Customers", "customers"},
+		{"s3://bucket/exports/daily.orders.tsv", "", "daily_orders"},
+		{"---.csv", "", "dataset"},
+	}
+	for _, tt := range tests {
+		got := TableName(&profiler.DatasetProfile{Filename: tt.filename, Table: tt.table})
+		if got != tt.want {
+			t.Errorf("TableName(%q, %q) = %q, want %q", tt.filename, tt.table, got, tt.want)
+		}
+	}
+}
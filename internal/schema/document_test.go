@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	content, err := JSONSchema(createProfile(), DocumentOptions{})
+	if err != nil {
+		t.Fatalf("JSONSchema failed: %v", err)
+	}
+
+	// Properties keep the column order
+	if strings.Index(content, `"order_id"`) > strings.Index(content, `"quantity"`) || strings.Index(content, `"paid"`) > strings.Index(content, `"ordered_on"`) {
+		t.Errorf("Expected properties in column order, got:\n%s", content)
+	}
+
+	var doc struct {
+		Schema      string                            `json:"$schema"`
+		Title       string                            `json:"title"`
+		Description string                            `json:"description"`
+		Properties  map[string]map[string]interface{} `json:"properties"`
+		Required    []string                          `json:"required"`
+	}
+	if err := json.Unmarshal([]byte(content), &doc); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, content)
+	}
+	if doc.Schema != jsonSchemaDraft || doc.Title != "order_items" || doc.Description != "Generated by DataSleuth from data/Order Items.csv.gz (100 rows)" {
+		t.Errorf("Unexpected header %q, %q, %q", doc.Schema, doc.Title, doc.Description)
+	}
+
+	want := map[string]map[string]interface{}{
+		"order_id":   {"type": "integer"},
+		"quantity":   {"type": []interface{}{"integer", "null"}},
+		"price":      {"type": "number"},
+		"Status":     {"type": "string", "maxLength": float64(16)},
+		"paid":       {"type": "boolean"},
+		"ordered_on": {"type": "string", "format": "date"},
+		"shipped_at": {"type": []interface{}{"string", "null"}, "format": "date-time"},
+		"order":      {"type": []interface{}{"string", "null"}},
+	}
+	if !reflect.DeepEqual(doc.Properties, want) {
+		t.Errorf("Expected properties %v, got %v", want, doc.Properties)
+	}
+	if got := strings.Join(doc.Required, ","); got != "order_id,price,Status,paid,ordered_on" {
+		t.Errorf("Unexpected required properties %s", got)
+	}
+}
+
+func TestAvro(t *testing.T) {
+	profile := createProfile()
+	profile.Columns["unit price"] = profile.Columns["price"]
+	profile.Columns["unit_price"] = profile.Columns["quantity"]
+
+	content, err := Avro(profile, DocumentOptions{Name: "com.example.Orders"})
+	if err != nil {
+		t.Fatalf("Avro failed: %v", err)
+	}
+
+	var record struct {
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+		Fields    []struct {
+			Name    string      `json:"name"`
+			Type    interface{} `json:"type"`
+			Doc     string      `json:"doc"`
+			Default interface{} `json:"default"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(content), &record); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, content)
+	}
+	if record.Type != "record" || record.Name != "Orders" || record.Namespace != "com.example" {
+		t.Errorf("Unexpected record %q %q in %q", record.Type, record.Name, record.Namespace)
+	}
+
+	types := make(map[string]interface{})
+	for _, field := range record.Fields {
+		types[field.Name] = field.Type
+	}
+	want := map[string]interface{}{
+		"order_id":     "long",
+		"quantity":     []interface{}{"null", "int"},
+		"price":        "double",
+		"paid":         "boolean",
+		"ordered_on":   map[string]interface{}{"type": "int", "logicalType": "date"},
+		"shipped_at":   []interface{}{"null", map[string]interface{}{"type": "long", "logicalType": "timestamp-micros"}},
+		"order":        []interface{}{"null", "string"},
+		"Status":       "string",
+		"unit_price":   []interface{}{"null", "int"},
+		"unit_price_2": "double",
+	}
+	for name, typ := range want {
+		if !reflect.DeepEqual(types[name], typ) {
+			t.Errorf("Expected field %s of type %v, got %v", name, typ, types[name])
+		}
+	}
+
+	// Nullable fields default to null, and renamed ones keep their column
+	if !strings.Contains(content, `"default": null`) {
+		t.Errorf("Expected null defaults, got:\n%s", content)
+	}
+	for _, field := range record.Fields {
+		if field.Name == "unit_price_2" && field.Doc != `Column "unit price"` {
+			t.Errorf("Expected the original column name in the doc, got %q", field.Doc)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// DocumentOptions tune a JSON Schema or Avro schema of a profile.
+type DocumentOptions struct {
+	Options
+	Name string // title or record name, derived from the dataset when empty
+}
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+type jsonSchemaProperty struct {
+	Type      interface{} `json:"type"` // a type name, or the name and "null" when nullable
+	Format    string      `json:"format,omitempty"`
+	MaxLength int         `json:"maxLength,omitempty"`
+}
+
+// jsonSchemaProperties keeps the properties in column order, which a map
+// would lose.
+type jsonSchemaProperties struct {
+	names      []string
+	properties []jsonSchemaProperty
+}
+
+func (p jsonSchemaProperties) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("{")
+	for i, name := range p.names {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(p.properties[i])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteString(":")
+		b.Write(value)
+	}
+	b.WriteString("}")
+	return b.Bytes(), nil
+}
+
+type jsonSchemaDocument struct {
+	Schema      string               `json:"$schema"`
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	Type        string               `json:"type"`
+	Properties  jsonSchemaProperties `json:"properties"`
+	Required    []string             `json:"required"`
+}
+
+// JSONSchema writes a JSON Schema (draft 2020-12) of a record of profile: an
+// object with a property per column. Columns without missing values are
+// required and do not allow null, dates and timestamps are strings of
+// format date and date-time, and strings have the maxLength a VARCHAR of
+// DDL would have. As with DDL, later records may break the limits of the
+// one profiled.
+func JSONSchema(profile *profiler.DatasetProfile, opts DocumentOptions) (string, error) {
+	if len(profile.Columns) == 0 {
+		return "", fmt.Errorf("profile has no columns")
+	}
+
+	doc := jsonSchemaDocument{
+		Schema:      jsonSchemaDraft,
+		Title:       opts.Name,
+		Description: strings.Join(provenance(profile), ". "),
+		Type:        "object",
+		Required:    make([]string, 0),
+	}
+	if doc.Title == "" {
+		doc.Title = TableName(profile)
+	}
+
+	for _, col := range Columns(profile, opts.Options) {
+		property := jsonSchemaProperty{}
+		typeName := "string"
+		switch col.Type {
+		case TypeBoolean:
+			typeName = "boolean"
+		case TypeInteger, TypeBigInt:
+			typeName = "integer"
+		case TypeFloat:
+			typeName = "number"
+		case TypeDate:
+			property.Format = "date"
+		case TypeTimestamp:
+			property.Format = "date-time"
+		default:
+			property.MaxLength = col.Length
+		}
+
+		property.Type = typeName
+		if col.Nullable {
+			property.Type = []string{typeName, "null"}
+		} else {
+			doc.Required = append(doc.Required, col.Name)
+		}
+		doc.Properties.names = append(doc.Properties.names, col.Name)
+		doc.Properties.properties = append(doc.Properties.properties, property)
+	}
+
+	content, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(content) + "\n", nil
+}
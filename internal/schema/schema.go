@@ -0,0 +1,208 @@
+// Package schema exports the inferred schema of a profiled dataset as the
+// table definitions of databases and warehouses, JSON Schema or Avro.
+package schema
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// Logical types a profiled column is exported as, before they are named
+// in a target dialect.
+const (
+	TypeBoolean   = "boolean"
+	TypeInteger   = "integer"
+	TypeBigInt    = "bigint"
+	TypeFloat     = "float"
+	TypeDate      = "date"
+	TypeTimestamp = "timestamp"
+	TypeString    = "string"
+)
+
+// Formats of the schema documents the schema command writes.
+const (
+	FormatSQL        = "sql"
+	FormatJSONSchema = "json-schema"
+	FormatAvro       = "avro"
+)
+
+// Formats lists the supported formats, in the order they are documented.
+var Formats = []string{FormatSQL, FormatJSONSchema, FormatAvro}
+
+// IsFormat reports whether format is one of Formats.
+func IsFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Column is a column of a profiled dataset as it is exported: its logical
+// type, whether it may be null, and for strings the length to declare.
+type Column struct {
+	Name     string
+	Type     string
+	Nullable bool
+	Length   int // characters, for TypeString; 0 when unknown
+}
+
+// Options tune how columns are derived from a profile.
+type Options struct {
+	ExactLengths bool // declare the longest string seen rather than the next power of two
+}
+
+// Columns derives the exported columns of profile, in source order.
+func Columns(profile *profiler.DatasetProfile, opts Options) []Column {
+	names := make([]string, 0, len(profile.Columns))
+	for name := range profile.Columns {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := profile.Columns[names[i]], profile.Columns[names[j]]
+		if a.Position != b.Position {
+			return a.Position < b.Position
+		}
+		return names[i] < names[j]
+	})
+
+	columns := make([]Column, 0, len(names))
+	for _, name := range names {
+		col := profile.Columns[name]
+		column := Column{Name: name, Type: logicalType(col), Nullable: nullable(profile, col)}
+		if column.Type == TypeString {
+			column.Length = stringLength(col, opts)
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+func logicalType(col *profiler.ColumnProfile) string {
+	switch col.DataType {
+	case "integer":
+		if fitsInt32(col.Min) && fitsInt32(col.Max) {
+			return TypeInteger
+		}
+		return TypeBigInt
+	case "float":
+		return TypeFloat
+	case "datetime":
+		if col.DateTime != nil {
+			switch col.DateTime.Precision {
+			case "day", "month", "year":
+				return TypeDate
+			}
+		}
+		return TypeTimestamp
+	}
+	if isBoolean(col) {
+		return TypeBoolean
+	}
+	return TypeString
+}
+
+func fitsInt32(v interface{}) bool {
+	f, ok := v.(float64)
+	if !ok {
+		return false
+	}
+	return f >= math.MinInt32 && f <= math.MaxInt32
+}
+
+// isBoolean reports whether every value of col is true or false, in any
+// case. Only a complete list of top values can tell.
+func isBoolean(col *profiler.ColumnProfile) bool {
+	if col.DataType != "string" || len(col.TopValues) == 0 || len(col.TopValues) != col.UniqueCount {
+		return false
+	}
+	for _, v := range col.TopValues {
+		switch strings.ToLower(v.Value) {
+		case "true", "false":
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// nullable is false when the column had no missing values, trusting the
+// inferred nullability when there is one.
+func nullable(profile *profiler.DatasetProfile, col *profiler.ColumnProfile) bool {
+	if profile.RowCount == 0 {
+		return true
+	}
+	if col.Nullability != nil {
+		return col.Nullability.Kind != profiler.NullabilityNotNull
+	}
+	return col.MissingCount > 0
+}
+
+// stringLength is the longest value of col, rounded up to the next power
+// of two to leave room for longer values unless exact lengths are asked
+// for.
+func stringLength(col *profiler.ColumnProfile, opts Options) int {
+	longest := col.MaxLength
+	if col.Text != nil && col.Text.MaxLength > longest {
+		longest = col.Text.MaxLength
+	}
+	if longest <= 0 || opts.ExactLengths {
+		return longest
+	}
+
+	length := 1
+	for length < longest {
+		length *= 2
+	}
+	return length
+}
+
+// provenance describes where the schema of profile was inferred from, for
+// the header comment or description of an exported schema.
+func provenance(profile *profiler.DatasetProfile) []string {
+	source := profile.Filename
+	if profile.Table != "" {
+		source += " (" + profile.Table + ")"
+	}
+	lines := []string{fmt.Sprintf("Generated by DataSleuth from %s (%d rows)", source, profile.RowCount)}
+	if profile.SampleStrategy != "" {
+		lines = append(lines, fmt.Sprintf("Types, nullability and lengths are inferred from a %s sample", profile.SampleStrategy))
+	}
+	return lines
+}
+
+var nonIdentifier = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// compressionExts are dropped along with the format extension, so that
+// orders.csv.gz is named orders.
+var compressionExts = map[string]bool{".gz": true, ".gzip": true, ".zst": true, ".zstd": true, ".bz2": true, ".bzip2": true}
+
+// TableName derives a table name from the file name of profile, or the
+// table or sheet it was read from: lower case, with runs of other
+// characters replaced by underscores.
+func TableName(profile *profiler.DatasetProfile) string {
+	name := profile.Table
+	if name == "" {
+		name = filepath.Base(profile.Filename)
+		if compressionExts[strings.ToLower(filepath.Ext(name))] {
+			name = strings.TrimSuffix(name, filepath.Ext(name))
+		}
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+
+	name = strings.Trim(nonIdentifier.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "dataset"
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "t_" + name
+	}
+	return name
+}
@@ -0,0 +1,317 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/kamalm96/datasleuth/internal/report"
+	"github.com/kamalm96/datasleuth/internal/validate"
+)
+
+// maxRequestBody bounds the JSON body of an API request, which may carry a
+// baseline report.
+const maxRequestBody = 64 << 20
+
+// APIHandler routes the JSON API for services that call DataSleuth over HTTP
+// instead of running the CLI. It shares the profiles, their IDs and the
+// history with the web UI. When Config.Token is set every request must send
+// it as a bearer token.
+func (s *Server) APIHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("POST /profile", s.handleAPIProfile)
+	mux.HandleFunc("GET /profiles", s.handleAPIList)
+	mux.HandleFunc("GET /profiles/{id}", s.handleAPIReport)
+	mux.HandleFunc("POST /validate", s.handleAPIValidate)
+	mux.HandleFunc("GET /events", s.handleEvents)
+
+	if s.config.Token == "" {
+		return mux
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.config.Token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("missing or wrong bearer token"))
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// profileRequest is the JSON body of POST /profile. Source is anything the
+// profile command accepts, read by the server; Table picks a table or sheet.
+type profileRequest struct {
+	Source string `json:"source"`
+	Table  string `json:"table"`
+}
+
+// profileResponse is one profile created by POST /profile.
+type profileResponse struct {
+	profileSummary
+	Report json.RawMessage `json:"report"`
+}
+
+type healthResponse struct {
+	Status  string `json:"status"`
+	Version string `json:"version"`
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, healthResponse{Status: "ok", Version: s.config.Version})
+}
+
+func (s *Server) handleAPIList(w http.ResponseWriter, r *http.Request) {
+	summaries := s.sessionSummaries()
+
+	datasets, err := s.historyRuns()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	for _, dataset := range datasets {
+		summaries = append(summaries, dataset.Runs...)
+	}
+
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+func (s *Server) handleAPIReport(w http.ResponseWriter, r *http.Request) {
+	e, status, err := s.find(r.PathValue("id"))
+	if err != nil {
+		writeError(w, status, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := report.EncodeJSONReport(&buf, e.Profile); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(buf.Bytes())
+}
+
+// handleAPIProfile profiles the source of a JSON body, or the file of a
+// multipart upload, and answers with the profiles and their reports.
+// Databases and workbooks without a table get one profile per table.
+func (s *Server) handleAPIProfile(w http.ResponseWriter, r *http.Request) {
+	var source string
+	var profiles []*profiler.DatasetProfile
+	var record bool
+	var err error
+
+	if isMultipart(r) {
+		source, profiles, err = s.profileUpload(w, r)
+	} else {
+		var req profileRequest
+		if err := decodeRequest(w, r, &req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		source, record = strings.TrimSpace(req.Source), true
+		profiles, err = s.profileRequested(r.Context(), source, req.Table)
+	}
+	if err != nil {
+		writeError(w, profileErrorStatus(err), err)
+		return
+	}
+
+	created := make([]profileResponse, 0, len(profiles))
+	for _, profile := range profiles {
+		var buf bytes.Buffer
+		if err := report.EncodeJSONReport(&buf, profile); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		e := s.add(source, profile, record)
+		created = append(created, profileResponse{profileSummary: e.summary(), Report: buf.Bytes()})
+	}
+
+	w.Header().Set("Location", "/profiles/"+created[0].ID)
+	writeJSON(w, http.StatusCreated, map[string][]profileResponse{"profiles": created})
+}
+
+// profileRequested profiles source, or its table or sheet, until ctx is
+// done.
+func (s *Server) profileRequested(ctx context.Context, source, table string) ([]*profiler.DatasetProfile, error) {
+	if source == "" {
+		return nil, errBadRequest{errors.New("source is required: a file path, URL or postgres:// or snowflake:// connection string")}
+	}
+
+	opts := s.config.Options
+	opts.Table = strings.TrimSpace(table)
+	if profiler.IsExcel(source) {
+		opts.Sheet, opts.Table = opts.Table, ""
+	}
+	return profileSource(ctx, source, opts)
+}
+
+// validateRequest is the JSON body of POST /validate. The dataset is either
+// profiled from Source (and Table) or an existing profile given by
+// ProfileID; the baseline is an existing profile given by BaselineID or a
+// JSON report inline. Tolerances left out keep the defaults of the validate
+// command.
+type validateRequest struct {
+	Source     string          `json:"source"`
+	Table      string          `json:"table"`
+	ProfileID  string          `json:"profile_id"`
+	BaselineID string          `json:"baseline_id"`
+	Baseline   json.RawMessage `json:"baseline"`
+	Tolerances struct {
+		MissingRate  *float64 `json:"missing_rate"`
+		MeanShift    *float64 `json:"mean_shift"`
+		StdDevChange *float64 `json:"stddev_change"`
+		Drift        *float64 `json:"drift"`
+		RowCount     *float64 `json:"row_count"`
+	} `json:"tolerances"`
+}
+
+func (req *validateRequest) tolerances() validate.Tolerances {
+	tol := validate.DefaultTolerances()
+	for _, t := range []struct {
+		value  *float64
+		target *float64
+	}{
+		{req.Tolerances.MissingRate, &tol.MissingRate},
+		{req.Tolerances.MeanShift, &tol.MeanShift},
+		{req.Tolerances.StdDevChange, &tol.StdDevChange},
+		{req.Tolerances.Drift, &tol.Drift},
+		{req.Tolerances.RowCount, &tol.RowCount},
+	} {
+		if t.value != nil {
+			*t.target = *t.value
+		}
+	}
+	return tol
+}
+
+// handleAPIValidate validates a dataset against a baseline profile. The
+// answer is 200 with passed set to false when checks fail, so that only
+// broken requests get an error status.
+func (s *Server) handleAPIValidate(w http.ResponseWriter, r *http.Request) {
+	var req validateRequest
+	if err := decodeRequest(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var baseline *profiler.DatasetProfile
+	switch {
+	case req.BaselineID != "" && len(req.Baseline) > 0:
+		writeError(w, http.StatusBadRequest, errors.New("give either baseline_id or baseline, not both"))
+		return
+	case req.BaselineID != "":
+		e, status, err := s.find(req.BaselineID)
+		if err != nil {
+			writeError(w, status, err)
+			return
+		}
+		baseline = e.Profile
+	case len(req.Baseline) > 0:
+		profile, err := report.DecodeJSONReport(req.Baseline)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid baseline: %w", err))
+			return
+		}
+		baseline = profile
+	default:
+		writeError(w, http.StatusBadRequest, errors.New("baseline_id or baseline is required"))
+		return
+	}
+
+	var profile *profiler.DatasetProfile
+	switch {
+	case req.ProfileID != "" && req.Source != "":
+		writeError(w, http.StatusBadRequest, errors.New("give either profile_id or source, not both"))
+		return
+	case req.ProfileID != "":
+		e, status, err := s.find(req.ProfileID)
+		if err != nil {
+			writeError(w, status, err)
+			return
+		}
+		profile = e.Profile
+	default:
+		profiles, err := s.profileRequested(r.Context(), strings.TrimSpace(req.Source), req.Table)
+		if err != nil {
+			writeError(w, profileErrorStatus(err), err)
+			return
+		}
+		if len(profiles) > 1 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("%s has %d tables: choose one with table", req.Source, len(profiles)))
+			return
+		}
+		profile = profiles[0]
+		s.add(req.Source, profile, true)
+	}
+
+	result := validate.AgainstBaseline(profile, baseline, req.tolerances())
+	writeJSON(w, http.StatusOK, report.NewJSONValidationReport(result))
+}
+
+func (e *entry) summary() profileSummary {
+	return profileSummary{
+		ID:           e.ID,
+		Name:         e.Name,
+		Source:       e.Source,
+		CreatedAt:    e.CreatedAt,
+		RowCount:     e.Profile.RowCount,
+		ColumnCount:  e.Profile.ColumnCount,
+		QualityScore: e.Profile.QualityScore,
+	}
+}
+
+// errBadRequest marks an error in the request itself rather than in the data.
+type errBadRequest struct{ error }
+
+// profileErrorStatus is 400 for an invalid request, 413 for an upload over
+// the limit and 422 for a source that could not be profiled.
+func profileErrorStatus(err error) int {
+	var bad errBadRequest
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &bad):
+		return http.StatusBadRequest
+	case errors.As(err, &tooLarge):
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusUnprocessableEntity
+}
+
+func isMultipart(r *http.Request) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mediaType == "multipart/form-data"
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON request: %w", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kamalm96/datasleuth/internal/events"
+)
+
+// eventBuffer is how many events a slow browser may fall behind by before
+// progress events are dropped for it.
+const eventBuffer = 64
+
+// handleEvents streams the events of every profile run as server-sent events,
+// named after their kind with the event as JSON data. ?source= keeps only the
+// events of one source.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	source := r.URL.Query().Get("source")
+
+	ch, cancel := events.Default.Subscribe(eventBuffer)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-ch:
+			if !ok {
+				return
+			}
+			if source != "" && e.Source != source {
+				continue
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Kind, data)
+			flusher.Flush()
+		}
+	}
+}
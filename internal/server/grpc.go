@@ -0,0 +1,377 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	datasleuthv1 "github.com/kamalm96/datasleuth/api/datasleuth/v1"
+	"github.com/kamalm96/datasleuth/internal/compare"
+	"github.com/kamalm96/datasleuth/internal/events"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/kamalm96/datasleuth/internal/report"
+	"github.com/kamalm96/datasleuth/internal/validate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GRPCServer serves the gRPC API of api/datasleuth/v1, the typed and
+// streaming counterpart of APIHandler. Both share the profiles, their IDs and
+// the history. When Config.Token is set every call must send it as a bearer
+// token in the authorization metadata.
+func (s *Server) GRPCServer() *grpc.Server {
+	var opts []grpc.ServerOption
+	if s.config.Token != "" {
+		opts = append(opts, grpc.StreamInterceptor(s.authorizeStream))
+	}
+	g := grpc.NewServer(opts...)
+	datasleuthv1.RegisterDataSleuthServer(g, &grpcService{server: s})
+	return g
+}
+
+func (s *Server) authorizeStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	for _, value := range md.Get("authorization") {
+		token, ok := strings.CutPrefix(value, "Bearer ")
+		if ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.config.Token)) == 1 {
+			return handler(srv, stream)
+		}
+	}
+	return status.Error(codes.Unauthenticated, "missing or wrong bearer token")
+}
+
+type grpcService struct {
+	datasleuthv1.UnimplementedDataSleuthServer
+	server *Server
+}
+
+func (g *grpcService) Profile(req *datasleuthv1.ProfileRequest, stream datasleuthv1.DataSleuth_ProfileServer) error {
+	source := strings.TrimSpace(req.GetSource())
+
+	var profiles []*profiler.DatasetProfile
+	err := withProgress(stream.Context(), source, func() error {
+		var err error
+		profiles, err = g.server.profileRequested(stream.Context(), source, req.GetTable())
+		return err
+	}, func(p *datasleuthv1.Progress) error {
+		return stream.Send(&datasleuthv1.ProfileResponse{Update: &datasleuthv1.ProfileResponse_Progress{Progress: p}})
+	})
+	if err != nil {
+		return err
+	}
+
+	result := &datasleuthv1.ProfileResult{}
+	for _, profile := range profiles {
+		p, err := newGRPCProfile(g.server.add(source, profile, true))
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		result.Profiles = append(result.Profiles, p)
+	}
+	return stream.Send(&datasleuthv1.ProfileResponse{Update: &datasleuthv1.ProfileResponse_Result{Result: result}})
+}
+
+func (g *grpcService) Validate(req *datasleuthv1.ValidateRequest, stream datasleuthv1.DataSleuth_ValidateServer) error {
+	send := func(p *datasleuthv1.Progress) error {
+		return stream.Send(&datasleuthv1.ValidateResponse{Update: &datasleuthv1.ValidateResponse_Progress{Progress: p}})
+	}
+
+	var baseline *profiler.DatasetProfile
+	switch {
+	case req.GetBaseline() != nil && len(req.GetBaselineReport()) > 0:
+		return status.Error(codes.InvalidArgument, "give either baseline or baseline_report, not both")
+	case len(req.GetBaselineReport()) > 0:
+		profile, err := report.DecodeJSONReport(req.GetBaselineReport())
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid baseline_report: %v", err)
+		}
+		baseline = profile
+	case req.GetBaseline() != nil:
+		e, err := g.dataset(stream.Context(), "baseline", req.GetBaseline(), send)
+		if err != nil {
+			return err
+		}
+		baseline = e.Profile
+	default:
+		return status.Error(codes.InvalidArgument, "baseline or baseline_report is required")
+	}
+
+	e, err := g.dataset(stream.Context(), "dataset", req.GetDataset(), send)
+	if err != nil {
+		return err
+	}
+
+	result := validate.AgainstBaseline(e.Profile, baseline, grpcTolerances(req.GetTolerances()))
+	validation := &datasleuthv1.ValidationResult{
+		Source:    result.Source,
+		Baseline:  result.Baseline,
+		Passed:    result.Passed(),
+		ProfileId: e.ID,
+	}
+	for _, check := range result.Checks {
+		validation.Checks = append(validation.Checks, &datasleuthv1.Check{
+			Name:    check.Name,
+			Column:  check.Column,
+			Passed:  check.Passed,
+			Message: check.Message,
+		})
+	}
+	return stream.Send(&datasleuthv1.ValidateResponse{Update: &datasleuthv1.ValidateResponse_Result{Result: validation}})
+}
+
+func (g *grpcService) Compare(req *datasleuthv1.CompareRequest, stream datasleuthv1.DataSleuth_CompareServer) error {
+	send := func(p *datasleuthv1.Progress) error {
+		return stream.Send(&datasleuthv1.CompareResponse{Update: &datasleuthv1.CompareResponse_Progress{Progress: p}})
+	}
+
+	base, err := g.dataset(stream.Context(), "base", req.GetBase(), send)
+	if err != nil {
+		return err
+	}
+	target, err := g.dataset(stream.Context(), "target", req.GetTarget(), send)
+	if err != nil {
+		return err
+	}
+
+	opts := compare.DefaultOptions()
+	opts.SchemaOnly = req.GetSchemaOnly()
+	result := compare.Compare(base.Profile, target.Profile, opts)
+	return stream.Send(&datasleuthv1.CompareResponse{Update: &datasleuthv1.CompareResponse_Result{Result: newGRPCComparison(result)}})
+}
+
+// dataset returns the existing profile of d, or profiles its source, streaming
+// the progress with send. The name of the field is used in errors.
+func (g *grpcService) dataset(ctx context.Context, field string, d *datasleuthv1.Dataset, send func(*datasleuthv1.Progress) error) (*entry, error) {
+	switch {
+	case d == nil:
+		return nil, status.Errorf(codes.InvalidArgument, "%s is required", field)
+	case d.GetProfileId() != "" && d.GetSource() != "":
+		return nil, status.Errorf(codes.InvalidArgument, "%s: give either profile_id or source, not both", field)
+	case d.GetProfileId() != "":
+		e, code, err := g.server.find(d.GetProfileId())
+		if err != nil {
+			return nil, status.Error(grpcCode(code), err.Error())
+		}
+		return e, nil
+	}
+
+	source := strings.TrimSpace(d.GetSource())
+	var profiles []*profiler.DatasetProfile
+	err := withProgress(ctx, source, func() error {
+		var err error
+		profiles, err = g.server.profileRequested(ctx, source, d.GetTable())
+		return err
+	}, send)
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) > 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "%s: %s has %d tables: choose one with table", field, source, len(profiles))
+	}
+	return g.server.add(source, profiles[0], true), nil
+}
+
+// withProgress runs profile while sending the events of source, and of its
+// tables and sheets, with send. Sending is left to the calling goroutine as a
+// stream allows a single sender. A profiling error is returned as a status.
+func withProgress(ctx context.Context, source string, profile func() error, send func(*datasleuthv1.Progress) error) error {
+	ch, cancel := events.Default.Subscribe(eventBuffer)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- profile() }()
+
+	for {
+		select {
+		case err := <-done:
+			// Events are published before profiling returns: send the rest
+			if sendErr := drain(ch, source, send); sendErr != nil {
+				return sendErr
+			}
+			if err != nil {
+				return status.Error(grpcCode(profileErrorStatus(err)), err.Error())
+			}
+			return nil
+		case e := <-ch:
+			if err := sendProgress(e, source, send); err != nil {
+				// The client is gone; profiling finishes in the background
+				return err
+			}
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+func drain(ch <-chan events.Event, source string, send func(*datasleuthv1.Progress) error) error {
+	for {
+		select {
+		case e := <-ch:
+			if err := sendProgress(e, source, send); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+func sendProgress(e events.Event, source string, send func(*datasleuthv1.Progress) error) error {
+	if e.Source != source && !strings.HasPrefix(e.Source, source+"#") {
+		return nil
+	}
+	return send(newGRPCProgress(e))
+}
+
+var grpcProgressKinds = map[events.Kind]datasleuthv1.Progress_Kind{
+	events.Started:    datasleuthv1.Progress_STARTED,
+	events.Progress:   datasleuthv1.Progress_PROGRESS,
+	events.ColumnDone: datasleuthv1.Progress_COLUMN_DONE,
+	events.Warning:    datasleuthv1.Progress_WARNING,
+	events.Note:       datasleuthv1.Progress_NOTE,
+	events.Finished:   datasleuthv1.Progress_FINISHED,
+}
+
+func newGRPCProgress(e events.Event) *datasleuthv1.Progress {
+	return &datasleuthv1.Progress{
+		Kind:    grpcProgressKinds[e.Kind],
+		Source:  e.Source,
+		Time:    timestamppb.New(e.Time),
+		Rows:    e.Rows,
+		Columns: int32(e.Columns),
+		Column:  e.Column,
+		Message: e.Message,
+	}
+}
+
+func newGRPCProfile(e *entry) (*datasleuthv1.Profile, error) {
+	var buf bytes.Buffer
+	if err := report.EncodeJSONReport(&buf, e.Profile); err != nil {
+		return nil, fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	p := &datasleuthv1.Profile{
+		Id:           e.ID,
+		Name:         e.Name,
+		Source:       e.Source,
+		CreatedAt:    timestamppb.New(e.CreatedAt),
+		RowCount:     int64(e.Profile.RowCount),
+		ColumnCount:  int32(e.Profile.ColumnCount),
+		QualityScore: int32(e.Profile.QualityScore),
+		Report:       buf.Bytes(),
+	}
+
+	names := make([]string, 0, len(e.Profile.Columns))
+	for name := range e.Profile.Columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		col := e.Profile.Columns[name]
+		column := &datasleuthv1.Column{
+			Name:         name,
+			DataType:     col.DataType,
+			MissingCount: int64(col.MissingCount),
+			UniqueCount:  int64(col.UniqueCount),
+		}
+		for _, issue := range col.QualityIssues {
+			column.QualityIssues = append(column.QualityIssues, &datasleuthv1.QualityIssue{
+				Type:        issue.Type,
+				Description: issue.Description,
+				Severity:    int32(issue.Severity),
+			})
+		}
+		p.Columns = append(p.Columns, column)
+	}
+	return p, nil
+}
+
+func newGRPCComparison(result *compare.Result) *datasleuthv1.CompareResult {
+	c := &datasleuthv1.CompareResult{
+		Base:           result.Base,
+		Target:         result.Target,
+		BaseRowCount:   int64(result.BaseRowCount),
+		TargetRowCount: int64(result.TargetRowCount),
+		DriftScore:     result.DriftScore(),
+	}
+	for _, col := range result.AddedColumns {
+		c.AddedColumns = append(c.AddedColumns, &datasleuthv1.ColumnSchema{Name: col.Name, DataType: col.DataType})
+	}
+	for _, col := range result.RemovedColumns {
+		c.RemovedColumns = append(c.RemovedColumns, &datasleuthv1.ColumnSchema{Name: col.Name, DataType: col.DataType})
+	}
+	for _, change := range result.RetypedColumns {
+		c.RetypedColumns = append(c.RetypedColumns, &datasleuthv1.TypeChange{Name: change.Name, OldType: change.OldType, NewType: change.NewType})
+	}
+	for _, rename := range result.Renames {
+		c.Renames = append(c.Renames, &datasleuthv1.Rename{
+			OldName:          rename.OldName,
+			NewName:          rename.NewName,
+			DataType:         rename.DataType,
+			Drift:            rename.Drift,
+			IdenticalContent: rename.IdenticalContent,
+		})
+	}
+	for _, col := range result.Columns {
+		c.Columns = append(c.Columns, &datasleuthv1.ColumnDiff{
+			Name:              col.Name,
+			Numeric:           col.IsNumeric,
+			OldMissingPercent: col.OldMissingPercent,
+			NewMissingPercent: col.NewMissingPercent,
+			OldMean:           col.OldMean,
+			NewMean:           col.NewMean,
+			OldStddev:         col.OldStdDev,
+			NewStddev:         col.NewStdDev,
+			MeanShift:         col.MeanShift,
+			Drift:             col.Drift,
+			DriftMetrics:      col.DriftMetrics,
+			Changes:           col.Changes,
+		})
+	}
+	return c
+}
+
+func grpcTolerances(t *datasleuthv1.Tolerances) validate.Tolerances {
+	tol := validate.DefaultTolerances()
+	if t == nil {
+		return tol
+	}
+	if t.MissingRate != nil {
+		tol.MissingRate = t.GetMissingRate()
+	}
+	if t.MeanShift != nil {
+		tol.MeanShift = t.GetMeanShift()
+	}
+	if t.StddevChange != nil {
+		tol.StdDevChange = t.GetStddevChange()
+	}
+	if t.Drift != nil {
+		tol.Drift = t.GetDrift()
+	}
+	if t.RowCount != nil {
+		tol.RowCount = t.GetRowCount()
+	}
+	return tol
+}
+
+// grpcCode is the status code that goes with the HTTP status of the JSON API.
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusRequestEntityTooLarge:
+		return codes.ResourceExhausted
+	case http.StatusUnprocessableEntity:
+		return codes.FailedPrecondition
+	}
+	return codes.Internal
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	datasleuthv1 "github.com/kamalm96/datasleuth/api/datasleuth/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestGRPC(t *testing.T, config Config) datasleuthv1.DataSleuthClient {
+	t.Helper()
+	config.Version = "test"
+
+	listener := bufconn.Listen(1 << 20)
+	server := New(config).GRPCServer()
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return datasleuthv1.NewDataSleuthClient(conn)
+}
+
+// receive collects the progress updates of a stream until its result.
+func receive[T interface {
+	GetProgress() *datasleuthv1.Progress
+}](t *testing.T, recv func() (T, error)) ([]*datasleuthv1.Progress, T, error) {
+	t.Helper()
+	var progress []*datasleuthv1.Progress
+	for {
+		resp, err := recv()
+		if err != nil {
+			var zero T
+			return progress, zero, err
+		}
+		if p := resp.GetProgress(); p != nil {
+			progress = append(progress, p)
+			continue
+		}
+		if _, err := recv(); err != io.EOF {
+			t.Errorf("Expected the stream to end after the result, got %v", err)
+		}
+		return progress, resp, nil
+	}
+}
+
+func TestGRPCProfileValidateCompare(t *testing.T) {
+	client := newTestGRPC(t, Config{})
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), "people.csv")
+	if err := os.WriteFile(path, []byte(testCSV), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stream, err := client.Profile(ctx, &datasleuthv1.ProfileRequest{Source: path})
+	if err != nil {
+		t.Fatalf("Profile failed: %v", err)
+	}
+	progress, resp, err := receive(t, stream.Recv)
+	if err != nil {
+		t.Fatalf("Profile failed: %v", err)
+	}
+
+	profiles := resp.GetResult().GetProfiles()
+	if len(profiles) != 1 {
+		t.Fatalf("Expected one profile, got %d", len(profiles))
+	}
+	profile := profiles[0]
+	if profile.GetId() != "s1" || profile.GetRowCount() != 3 || len(profile.GetColumns()) != 2 || len(profile.GetReport()) == 0 {
+		t.Errorf("Unexpected profile: %v", profile)
+	}
+	if profile.GetColumns()[0].GetName() != "age" || profile.GetColumns()[0].GetMissingCount() != 1 {
+		t.Errorf("Expected the columns by name, got %v", profile.GetColumns())
+	}
+
+	kinds := make(map[datasleuthv1.Progress_Kind]int)
+	for _, p := range progress {
+		if p.GetSource() != path {
+			t.Errorf("Expected the progress of %s only, got %v", path, p)
+		}
+		kinds[p.GetKind()]++
+	}
+	if kinds[datasleuthv1.Progress_STARTED] != 1 || kinds[datasleuthv1.Progress_COLUMN_DONE] != 2 || kinds[datasleuthv1.Progress_FINISHED] != 1 {
+		t.Errorf("Expected started, a column_done per column and finished, got %v", kinds)
+	}
+
+	validation, err := client.Validate(ctx, &datasleuthv1.ValidateRequest{
+		Dataset:  &datasleuthv1.Dataset{Source: path},
+		Baseline: &datasleuthv1.Dataset{ProfileId: "s1"},
+	})
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	_, validated, err := receive(t, validation.Recv)
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if result := validated.GetResult(); !result.GetPassed() || len(result.GetChecks()) == 0 || result.GetProfileId() != "s2" {
+		t.Errorf("Expected the unchanged dataset to pass, got %v", result)
+	}
+
+	comparison, err := client.Compare(ctx, &datasleuthv1.CompareRequest{
+		Base:   &datasleuthv1.Dataset{ProfileId: "s1"},
+		Target: &datasleuthv1.Dataset{ProfileId: "s2"},
+	})
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	progress, compared, err := receive(t, comparison.Recv)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if result := compared.GetResult(); result.GetBaseRowCount() != 3 || len(result.GetColumns()) != 2 || result.GetDriftScore() != 0 {
+		t.Errorf("Unexpected comparison: %v", result)
+	}
+	if len(progress) != 0 {
+		t.Errorf("Expected no progress when comparing existing profiles, got %v", progress)
+	}
+}
+
+func TestGRPCErrors(t *testing.T) {
+	client := newTestGRPC(t, Config{Token: "s3cret"})
+
+	call := func(ctx context.Context, req *datasleuthv1.ValidateRequest) codes.Code {
+		stream, err := client.Validate(ctx, req)
+		if err == nil {
+			_, _, err = receive(t, stream.Recv)
+		}
+		return status.Code(err)
+	}
+
+	if code := call(context.Background(), &datasleuthv1.ValidateRequest{}); code != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated without the token, got %v", code)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer s3cret")
+	for _, tc := range []struct {
+		name string
+		req  *datasleuthv1.ValidateRequest
+		code codes.Code
+	}{
+		{"no baseline", &datasleuthv1.ValidateRequest{Dataset: &datasleuthv1.Dataset{Source: "people.csv"}}, codes.InvalidArgument},
+		{"unknown baseline", &datasleuthv1.ValidateRequest{Baseline: &datasleuthv1.Dataset{ProfileId: "s9"}}, codes.NotFound},
+		{"invalid report", &datasleuthv1.ValidateRequest{BaselineReport: []byte("{")}, codes.InvalidArgument},
+		{"missing source", &datasleuthv1.ValidateRequest{
+			Dataset:        &datasleuthv1.Dataset{Source: "/does/not/exist.csv"},
+			BaselineReport: []byte(`{"filename": "people.csv", "columns": {}}`),
+		}, codes.FailedPrecondition},
+	} {
+		if code := call(ctx, tc.req); code != tc.code {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.code, code)
+		}
+	}
+}
@@ -0,0 +1,635 @@
+package server
+
+import (
+	"html/template"
+	"time"
+)
+
+var funcs = template.FuncMap{
+	"formatTime": func(t time.Time) string {
+		return t.Local().Format("2006-01-02 15:04")
+	},
+	"scoreClass": func(score int) string {
+		switch {
+		case score >= 90:
+			return "score-good"
+		case score >= 70:
+			return "score-warning"
+		default:
+			return "score-bad"
+		}
+	},
+}
+
+var (
+	indexTemplate   = template.Must(template.New("index").Funcs(funcs).Parse(pageStyle + indexHTML))
+	profileTemplate = template.Must(template.New("profile").Funcs(funcs).Parse(pageStyle + profileHTML))
+	datasetTemplate = template.Must(template.New("dataset").Funcs(funcs).Parse(pageStyle + datasetHTML))
+)
+
+const pageStyle = `{{define "style"}}
+    <style>
+        :root {
+            --primary-color: #1a73e8;
+            --secondary-color: #5f6368;
+            --background-color: #f8f9fa;
+            --card-color: #ffffff;
+            --border-color: #dadce0;
+            --text-color: #202124;
+            --success-color: #0f9d58;
+            --warning-color: #f4b400;
+            --error-color: #d93025;
+        }
+
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif;
+            line-height: 1.5;
+            color: var(--text-color);
+            background-color: var(--background-color);
+            margin: 0;
+        }
+
+        header {
+            background-color: var(--primary-color);
+            color: white;
+            padding: 12px 24px;
+            display: flex;
+            align-items: baseline;
+            gap: 16px;
+        }
+
+        header a {
+            color: white;
+            text-decoration: none;
+            font-weight: bold;
+            font-size: 1.2em;
+        }
+
+        main {
+            max-width: 1200px;
+            margin: 0 auto;
+            padding: 20px;
+        }
+
+        .card {
+            background-color: var(--card-color);
+            border: 1px solid var(--border-color);
+            border-radius: 8px;
+            padding: 16px 20px;
+            margin-bottom: 20px;
+        }
+
+        .error {
+            background-color: rgba(217, 48, 37, 0.1);
+            border-left: 4px solid var(--error-color);
+            padding: 10px;
+            border-radius: 4px;
+            margin-bottom: 20px;
+        }
+
+        table {
+            width: 100%;
+            border-collapse: collapse;
+        }
+
+        th, td {
+            text-align: left;
+            padding: 6px 10px;
+            border-bottom: 1px solid var(--border-color);
+        }
+
+        th.sortable {
+            cursor: pointer;
+            user-select: none;
+        }
+
+        th.sortable:hover {
+            color: var(--primary-color);
+        }
+
+        tr.selectable {
+            cursor: pointer;
+        }
+
+        tr.selectable:hover, tr.selected {
+            background-color: rgba(26, 115, 232, 0.08);
+        }
+
+        input[type=text], select {
+            padding: 6px 8px;
+            border: 1px solid var(--border-color);
+            border-radius: 4px;
+            font-size: 1em;
+        }
+
+        button {
+            padding: 6px 14px;
+            background-color: var(--primary-color);
+            color: white;
+            border: none;
+            border-radius: 4px;
+            cursor: pointer;
+            font-size: 1em;
+        }
+
+        .muted {
+            color: var(--secondary-color);
+        }
+
+        .score-good { color: var(--success-color); }
+        .score-warning { color: var(--warning-color); }
+        .score-bad { color: var(--error-color); }
+
+        .stats {
+            display: grid;
+            grid-template-columns: repeat(auto-fill, minmax(150px, 1fr));
+            gap: 10px;
+        }
+
+        .stat .value {
+            font-size: 1.4em;
+            font-weight: bold;
+        }
+
+        .stat .label {
+            color: var(--secondary-color);
+            font-size: 0.85em;
+        }
+
+        .toolbar {
+            display: flex;
+            gap: 10px;
+            margin-bottom: 10px;
+            flex-wrap: wrap;
+        }
+
+        .chart rect.bar {
+            fill: var(--primary-color);
+        }
+
+        .chart rect.bar:hover {
+            fill: #3949ab;
+        }
+
+        .chart text {
+            font-size: 11px;
+            fill: var(--secondary-color);
+        }
+
+        .chart line {
+            stroke: var(--border-color);
+        }
+
+        .issues li {
+            color: var(--error-color);
+        }
+
+        .trend polyline {
+            fill: none;
+            stroke: var(--primary-color);
+            stroke-width: 2;
+        }
+
+        .trend circle {
+            fill: var(--primary-color);
+        }
+
+        .trend circle:hover {
+            fill: #3949ab;
+        }
+
+        .trend text {
+            font-size: 11px;
+            fill: var(--secondary-color);
+        }
+
+        .trend line {
+            stroke: var(--border-color);
+        }
+
+        .sparkline polyline {
+            fill: none;
+            stroke: var(--primary-color);
+            stroke-width: 1.5;
+        }
+    </style>
+{{end}}`
+
+const indexHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>DataSleuth</title>
+    {{template "style"}}
+</head>
+<body>
+    <header><a href="/">DataSleuth</a><span>v{{.Version}}</span></header>
+    <main>
+        {{if .Error}}<div class="error">{{.Error}}</div>{{end}}
+
+        <div class="card">
+            <h2>Profile a dataset</h2>
+            <form method="post" action="/profile" class="toolbar">
+                <input type="text" name="source" placeholder="Path, URL or connection string, e.g. data/orders.csv or s3://bucket/events.csv" size="60" required>
+                <input type="text" name="table" placeholder="Table or sheet (optional)">
+                <button type="submit">Profile</button>
+            </form>
+            <form method="post" action="/upload" enctype="multipart/form-data" class="toolbar">
+                <input type="file" name="file" required>
+                <button type="submit">Upload and profile</button>
+            </form>
+            <p id="progress" class="muted"></p>
+        </div>
+
+        {{if or .Session .Datasets}}
+        <div class="card">
+            <h2>Compare two profiles</h2>
+            <form method="get" action="/compare" class="toolbar">
+                <select name="base">
+                    {{range .Session}}<option value="{{.ID}}">{{.Name}} ({{formatTime .CreatedAt}})</option>{{end}}
+                    {{range .Datasets}}{{range .Runs}}<option value="{{.ID}}">{{.Name}} #{{.ID}} ({{formatTime .CreatedAt}})</option>{{end}}{{end}}
+                </select>
+                <span>→</span>
+                <select name="target">
+                    {{range .Session}}<option value="{{.ID}}">{{.Name}} ({{formatTime .CreatedAt}})</option>{{end}}
+                    {{range .Datasets}}{{range .Runs}}<option value="{{.ID}}">{{.Name}} #{{.ID}} ({{formatTime .CreatedAt}})</option>{{end}}{{end}}
+                </select>
+                <button type="submit">Compare</button>
+            </form>
+        </div>
+        {{end}}
+
+        {{if .Session}}
+        <div class="card">
+            <h2>This session</h2>
+            <table>
+                <tr><th>Dataset</th><th>Profiled</th><th>Rows</th><th>Columns</th><th>Score</th></tr>
+                {{range .Session}}
+                <tr>
+                    <td><a href="/profiles/{{.ID}}">{{.Name}}</a></td>
+                    <td>{{formatTime .CreatedAt}}</td>
+                    <td>{{.RowCount}}</td>
+                    <td>{{.ColumnCount}}</td>
+                    <td>{{.QualityScore}}/100</td>
+                </tr>
+                {{end}}
+            </table>
+        </div>
+        {{end}}
+
+        <div class="card">
+            <h2>History</h2>
+            {{if not .History}}
+            <p class="muted">The profile history is turned off.</p>
+            {{else if not .Datasets}}
+            <p class="muted">No runs recorded yet. Profiles of a path or URL are recorded automatically.</p>
+            {{else}}
+            {{range .Datasets}}
+            <h3><a href="/dataset?name={{.Name}}">{{.Name}}</a>
+                {{if gt (len .Runs) 1}}<svg class="sparkline" viewBox="0 0 120 24" width="120" height="24"><title>Quality score of the latest runs</title><polyline points="{{.Sparkline}}"/></svg>{{end}}</h3>
+            <table>
+                <tr><th>Run</th><th>Profiled</th><th>Rows</th><th>Columns</th><th>Score</th></tr>
+                {{range .Runs}}
+                <tr>
+                    <td><a href="/profiles/{{.ID}}">#{{.ID}}</a></td>
+                    <td>{{formatTime .CreatedAt}}</td>
+                    <td>{{.RowCount}}</td>
+                    <td>{{.ColumnCount}}</td>
+                    <td class="{{scoreClass .QualityScore}}">{{.QualityScore}}/100</td>
+                </tr>
+                {{end}}
+            </table>
+            {{end}}
+            {{end}}
+        </div>
+    </main>
+    <script>
+        // Show how far the profiler has got while the form is submitting
+        document.querySelectorAll('form[method="post"]').forEach(function (form) {
+            form.addEventListener('submit', function () {
+                var progress = document.getElementById('progress');
+                progress.textContent = 'Profiling...';
+                if (!window.EventSource) {
+                    return;
+                }
+                var stream = new EventSource('/events');
+                stream.addEventListener('progress', function (e) {
+                    var event = JSON.parse(e.data);
+                    progress.textContent = 'Profiling ' + event.source + ': ' + event.rows.toLocaleString() + ' rows read';
+                });
+                stream.addEventListener('column_done', function (e) {
+                    progress.textContent = 'Finishing column ' + JSON.parse(e.data).column;
+                });
+                stream.addEventListener('warning', function (e) {
+                    var event = JSON.parse(e.data);
+                    progress.textContent = event.source + ': ' + event.message;
+                });
+            });
+        });
+    </script>
+</body>
+</html>`
+
+const profileHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Entry.Name}} - DataSleuth</title>
+    {{template "style"}}
+</head>
+<body>
+    <header><a href="/">DataSleuth</a><span>v{{.Version}}</span></header>
+    <main>
+        <div class="card">
+            <h2>{{.Entry.Name}}</h2>
+            <p class="muted">{{.Entry.Source}} · profiled {{formatTime .Entry.CreatedAt}} ·
+                <a href="/profiles/{{.Entry.ID}}/report">static report</a> ·
+                <a href="/api/profiles/{{.Entry.ID}}">JSON</a>{{if .Dataset}} ·
+                <a href="/dataset?name={{.Dataset}}">trends</a>{{end}}</p>
+            <div class="stats" id="summary"></div>
+            {{if .Others}}
+            <form method="get" action="/compare" class="toolbar" style="margin-top: 16px">
+                <input type="hidden" name="target" value="{{.Entry.ID}}">
+                <span>Compare with</span>
+                <select name="base">
+                    {{range .Others}}<option value="{{.ID}}">{{.Name}} #{{.ID}} ({{formatTime .CreatedAt}})</option>{{end}}
+                </select>
+                <button type="submit">Compare</button>
+            </form>
+            {{end}}
+        </div>
+
+        <div class="card">
+            <h2>Columns</h2>
+            <div class="toolbar">
+                <input type="text" id="filter" placeholder="Filter columns">
+                <select id="type-filter"><option value="">All types</option></select>
+                <label><input type="checkbox" id="issues-only"> With issues only</label>
+            </div>
+            <table id="columns">
+                <thead><tr>
+                    <th class="sortable" data-key="name">Name</th>
+                    <th class="sortable" data-key="data_type">Type</th>
+                    <th class="sortable" data-key="missing_percent">Missing</th>
+                    <th class="sortable" data-key="unique_percent">Unique</th>
+                    <th class="sortable" data-key="mean">Mean</th>
+                    <th>Min</th>
+                    <th>Max</th>
+                    <th class="sortable" data-key="issues">Issues</th>
+                </tr></thead>
+                <tbody></tbody>
+            </table>
+        </div>
+
+        <div class="card" id="detail" hidden></div>
+    </main>
+    <script>
+        var report = {{.Report}};
+
+        var columns = Object.keys(report.columns || {}).map(function (name) {
+            var col = report.columns[name];
+            col.issues = (col.quality_issues || []).length;
+            return col;
+        });
+        var sortKey = 'name', sortAsc = true;
+
+        function el(tag, attrs, text) {
+            var node = document.createElement(tag);
+            for (var k in attrs || {}) node.setAttribute(k, attrs[k]);
+            if (text !== undefined) node.textContent = text;
+            return node;
+        }
+
+        function svg(tag, attrs) {
+            var node = document.createElementNS('http://www.w3.org/2000/svg', tag);
+            for (var k in attrs || {}) node.setAttribute(k, attrs[k]);
+            return node;
+        }
+
+        function fmt(v) {
+            if (v === undefined || v === null || v === '') return '-';
+            if (typeof v === 'number') return Math.abs(v) >= 1000 || Number.isInteger(v) ? v.toLocaleString() : v.toPrecision(4);
+            return String(v);
+        }
+
+        function renderSummary() {
+            var score = report.quality_score;
+            var stats = [
+                ['Rows', fmt(report.row_count)],
+                ['Columns', fmt(report.column_count)],
+                ['Missing cells', fmt(report.missing_cells)],
+                ['Duplicate rows', fmt(report.duplicate_rows)],
+                ['Parse errors', report.parse_errors ? fmt(report.parse_errors.total) : '-'],
+                ['Quality score', score + '/100', score >= 90 ? 'score-good' : score >= 70 ? 'score-warning' : 'score-bad'],
+                ['Format', report.format]
+            ];
+            var summary = document.getElementById('summary');
+            stats.forEach(function (s) {
+                var stat = el('div', {'class': 'stat'});
+                stat.appendChild(el('div', {'class': 'value ' + (s[2] || '')}, s[1]));
+                stat.appendChild(el('div', {'class': 'label'}, s[0]));
+                summary.appendChild(stat);
+            });
+
+            var types = {};
+            columns.forEach(function (c) { types[c.data_type] = true; });
+            Object.keys(types).sort().forEach(function (t) {
+                document.getElementById('type-filter').appendChild(el('option', {value: t}, t));
+            });
+        }
+
+        function renderColumns() {
+            var text = document.getElementById('filter').value.toLowerCase();
+            var type = document.getElementById('type-filter').value;
+            var issuesOnly = document.getElementById('issues-only').checked;
+
+            var rows = columns.filter(function (c) {
+                return c.name.toLowerCase().indexOf(text) >= 0 && (!type || c.data_type === type) && (!issuesOnly || c.issues > 0);
+            });
+            rows.sort(function (a, b) {
+                var x = a[sortKey], y = b[sortKey];
+                if (x === undefined) x = -Infinity;
+                if (y === undefined) y = -Infinity;
+                var order = x < y ? -1 : x > y ? 1 : 0;
+                return sortAsc ? order : -order;
+            });
+
+            var body = document.querySelector('#columns tbody');
+            body.textContent = '';
+            rows.forEach(function (c) {
+                var tr = el('tr', {'class': 'selectable'});
+                [c.name, c.data_type, c.missing_percent.toFixed(1) + '%', c.unique_percent.toFixed(1) + '%',
+                 fmt(c.mean), fmt(c.min), fmt(c.max), c.issues || ''].forEach(function (v) {
+                    tr.appendChild(el('td', {}, v));
+                });
+                tr.addEventListener('click', function () {
+                    document.querySelectorAll('#columns tr.selected').forEach(function (r) { r.classList.remove('selected'); });
+                    tr.classList.add('selected');
+                    renderDetail(c);
+                });
+                body.appendChild(tr);
+            });
+        }
+
+        // barChart draws labelled vertical bars with the count on hover.
+        function barChart(bars) {
+            var width = 720, height = 220, left = 50, bottom = 40;
+            var max = Math.max.apply(null, bars.map(function (b) { return b.count; }).concat([1]));
+            var chart = svg('svg', {'class': 'chart', viewBox: '0 0 ' + width + ' ' + height, width: '100%'});
+            var plot = height - bottom - 10, step = (width - left) / bars.length;
+
+            [0, 0.5, 1].forEach(function (f) {
+                var y = 10 + plot * (1 - f);
+                chart.appendChild(svg('line', {x1: left, x2: width, y1: y, y2: y}));
+                var label = svg('text', {x: left - 6, y: y + 4, 'text-anchor': 'end'});
+                label.textContent = Math.round(max * f).toLocaleString();
+                chart.appendChild(label);
+            });
+
+            bars.forEach(function (b, i) {
+                var h = plot * b.count / max;
+                var bar = svg('rect', {'class': 'bar', x: left + i * step + 1, y: 10 + plot - h, width: Math.max(step - 2, 1), height: Math.max(h, 0.5)});
+                var title = svg('title');
+                title.textContent = b.label + ': ' + b.count.toLocaleString();
+                bar.appendChild(title);
+                chart.appendChild(bar);
+
+                var every = Math.ceil(bars.length / 8);
+                if (i % every === 0) {
+                    var text = svg('text', {x: left + i * step + step / 2, y: height - bottom + 16, 'text-anchor': 'middle'});
+                    text.textContent = b.tick;
+                    chart.appendChild(text);
+                }
+            });
+            return chart;
+        }
+
+        function renderDetail(c) {
+            var detail = document.getElementById('detail');
+            detail.hidden = false;
+            detail.textContent = '';
+            detail.appendChild(el('h2', {}, c.name));
+            detail.appendChild(el('p', {'class': 'muted'}, c.data_type + ' · ' + fmt(c.count) + ' values · ' +
+                fmt(c.missing_count) + ' missing · ' + fmt(c.unique_count) + ' unique'));
+
+            var stats = el('div', {'class': 'stats'});
+            [['Min', c.min], ['Max', c.max], ['Mean', c.mean], ['Median', c.median], ['Std dev', c.std_dev],
+             ['Skewness', c.skewness], ['Mode', c.mode], ['Avg length', c.avg_length]].forEach(function (s) {
+                if (s[1] === undefined) return;
+                var stat = el('div', {'class': 'stat'});
+                stat.appendChild(el('div', {'class': 'value'}, fmt(s[1])));
+                stat.appendChild(el('div', {'class': 'label'}, s[0]));
+                stats.appendChild(stat);
+            });
+            detail.appendChild(stats);
+
+            if (c.histogram && c.histogram.length) {
+                detail.appendChild(el('h3', {}, 'Distribution'));
+                detail.appendChild(barChart(c.histogram.map(function (b) {
+                    return {count: b.count, label: fmt(b.min) + ' – ' + fmt(b.max), tick: fmt(b.min)};
+                })));
+            } else if (c.datetime && c.datetime.histogram && c.datetime.histogram.length) {
+                detail.appendChild(el('h3', {}, 'Timeline'));
+                detail.appendChild(barChart(c.datetime.histogram.map(function (b) {
+                    var day = String(b.start).slice(0, 10);
+                    return {count: b.count, label: b.start, tick: day};
+                })));
+            }
+
+            if (c.top_values && c.top_values.length) {
+                detail.appendChild(el('h3', {}, 'Top values'));
+                detail.appendChild(barChart(c.top_values.map(function (v) {
+                    var label = v.value.length > 12 ? v.value.slice(0, 11) + '…' : v.value;
+                    return {count: v.count, label: v.value + ' (' + v.percent.toFixed(1) + '%)', tick: label};
+                })));
+            }
+
+            if (c.quality_issues && c.quality_issues.length) {
+                detail.appendChild(el('h3', {}, 'Quality issues'));
+                var list = el('ul', {'class': 'issues'});
+                c.quality_issues.forEach(function (issue) { list.appendChild(el('li', {}, issue)); });
+                detail.appendChild(list);
+            }
+
+            if (c.examples && c.examples.length) {
+                detail.appendChild(el('h3', {}, 'Examples'));
+                detail.appendChild(el('p', {}, c.examples.join(', ')));
+            }
+            detail.scrollIntoView({behavior: 'smooth'});
+        }
+
+        document.querySelectorAll('th.sortable').forEach(function (th) {
+            th.addEventListener('click', function () {
+                var key = th.getAttribute('data-key');
+                sortAsc = key === sortKey ? !sortAsc : true;
+                sortKey = key;
+                renderColumns();
+            });
+        });
+        ['filter', 'type-filter', 'issues-only'].forEach(function (id) {
+            document.getElementById(id).addEventListener('input', renderColumns);
+        });
+
+        renderSummary();
+        renderColumns();
+    </script>
+</body>
+</html>`
+
+const datasetHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Dataset}} - DataSleuth</title>
+    {{template "style"}}
+</head>
+<body>
+    <header><a href="/">DataSleuth</a><span>v{{.Version}}</span></header>
+    <main>
+        <div class="card">
+            <h2>{{.Dataset}}</h2>
+            <p class="muted">{{len .Runs}} runs charted · latest {{with index .Runs 0}}<a href="/profiles/{{.ProfileID}}">#{{.ID}}</a> ({{formatTime .CreatedAt}}) ·
+                <a href="/profiles/{{.ProfileID}}/report">static report</a>{{end}}</p>
+        </div>
+
+        {{range .Charts}}
+        <div class="card">
+            <h3>{{.Title}} <span class="muted">{{.Latest}}</span></h3>
+            <svg class="trend" viewBox="0 0 720 140" width="100%">
+                <line x1="70" x2="720" y1="10" y2="10"/>
+                <line x1="70" x2="720" y1="130" y2="130"/>
+                <text x="64" y="14" text-anchor="end">{{.High}}</text>
+                <text x="64" y="134" text-anchor="end">{{.Low}}</text>
+                <polyline points="{{.Line}}"/>
+                {{range .Points}}<a href="/profiles/{{.ID}}"><circle cx="{{.X}}" cy="{{.Y}}" r="4"><title>{{.Title}}</title></circle></a>{{end}}
+            </svg>
+        </div>
+        {{end}}
+
+        <div class="card">
+            <h2>Runs</h2>
+            <table>
+                <tr><th>Run</th><th>Profiled</th><th>Rows</th><th>Columns</th><th>Missing cells</th><th>Duplicate rows</th><th>Parse errors</th><th>Issues</th><th>Score</th><th></th></tr>
+                {{range .Runs}}
+                <tr>
+                    <td><a href="/profiles/{{.ProfileID}}">#{{.ID}}</a></td>
+                    <td>{{formatTime .CreatedAt}}</td>
+                    <td>{{.RowCount}}</td>
+                    <td>{{.ColumnCount}}</td>
+                    <td>{{.MissingCells}}</td>
+                    <td>{{.DuplicateRows}}</td>
+                    <td>{{.ParseErrors}}</td>
+                    <td>{{.Issues}}</td>
+                    <td class="{{scoreClass .QualityScore}}">{{.QualityScore}}/100</td>
+                    <td><a href="/profiles/{{.ProfileID}}/report">report</a>{{if .PreviousID}} ·
+                        <a href="/compare?base={{.PreviousID}}&target={{.ProfileID}}">changes</a>{{end}}</td>
+                </tr>
+                {{end}}
+            </table>
+        </div>
+    </main>
+</body>
+</html>`
@@ -0,0 +1,496 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/compare"
+	"github.com/kamalm96/datasleuth/internal/history"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"github.com/kamalm96/datasleuth/internal/report"
+)
+
+// DefaultMaxUpload is the largest file accepted for upload.
+const DefaultMaxUpload = 512 << 20
+
+const recentRuns = 10 // runs listed per dataset on the index page
+
+// Config configures a Server.
+type Config struct {
+	Version   string
+	Options   profiler.Options // options of every profile run
+	History   *history.Store   // records runs and lists past ones; nil to keep profiles in memory only
+	MaxUpload int64            // DefaultMaxUpload when 0
+	Token     string           // bearer token required by the API handler, empty for none
+}
+
+// Server serves profiles as interactive pages: profiles run during the
+// session, from a path, URL or uploaded file, and runs from the profile
+// history. Profile IDs are s<n> for session profiles and r<run ID> for
+// history runs.
+type Server struct {
+	config Config
+
+	mu      sync.Mutex
+	session []*entry
+	cache   map[string]*entry
+}
+
+type entry struct {
+	ID        string
+	Name      string
+	Source    string
+	CreatedAt time.Time
+	Profile   *profiler.DatasetProfile
+}
+
+func New(config Config) *Server {
+	if config.MaxUpload <= 0 {
+		config.MaxUpload = DefaultMaxUpload
+	}
+	return &Server{
+		config: config,
+		cache:  make(map[string]*entry),
+	}
+}
+
+// Handler routes the pages, the JSON API and the event stream.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("POST /profile", s.handleProfile)
+	mux.HandleFunc("POST /upload", s.handleUpload)
+	mux.HandleFunc("GET /profiles/{id}", s.handleProfilePage)
+	mux.HandleFunc("GET /profiles/{id}/report", s.handleReport)
+	mux.HandleFunc("GET /dataset", s.handleDataset)
+	mux.HandleFunc("GET /api/profiles", s.handleList)
+	mux.HandleFunc("GET /api/profiles/{id}", s.handleJSON)
+	mux.HandleFunc("GET /compare", s.handleCompare)
+	mux.HandleFunc("GET /events", s.handleEvents)
+	return mux
+}
+
+// profileSummary is one profile in the index and the API listing.
+type profileSummary struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Source       string    `json:"source"`
+	Dataset      string    `json:"dataset,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	RowCount     int       `json:"row_count"`
+	ColumnCount  int       `json:"column_count"`
+	QualityScore int       `json:"quality_score"`
+}
+
+type datasetRuns struct {
+	Name      string
+	Runs      []profileSummary
+	Sparkline string // of the quality scores of Runs
+}
+
+type indexData struct {
+	Version  string
+	Session  []profileSummary
+	Datasets []datasetRuns
+	History  bool
+	Error    string
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	data := indexData{
+		Version: s.config.Version,
+		Session: s.sessionSummaries(),
+		History: s.config.History != nil,
+		Error:   r.URL.Query().Get("error"),
+	}
+
+	datasets, err := s.historyRuns()
+	if err != nil {
+		data.Error = err.Error()
+	}
+	data.Datasets = datasets
+
+	s.render(w, indexTemplate, data)
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	summaries := s.sessionSummaries()
+
+	datasets, err := s.historyRuns()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, dataset := range datasets {
+		summaries = append(summaries, dataset.Runs...)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summaries)
+}
+
+// handleProfile profiles a local path, remote URL or PostgreSQL connection
+// string. Databases and workbooks
+// without a table or sheet get one profile per table or sheet.
+func (s *Server) handleProfile(w http.ResponseWriter, r *http.Request) {
+	source := strings.TrimSpace(r.FormValue("source"))
+	if source == "" {
+		redirectError(w, r, "enter a file path, URL or connection string to profile")
+		return
+	}
+
+	opts := s.config.Options
+	opts.Table = strings.TrimSpace(r.FormValue("table"))
+	if profiler.IsExcel(source) {
+		opts.Sheet, opts.Table = opts.Table, ""
+	}
+
+	profiles, err := profileSource(r.Context(), source, opts)
+	if err != nil {
+		redirectError(w, r, err.Error())
+		return
+	}
+
+	var first string
+	for _, profile := range profiles {
+		e := s.add(source, profile, true)
+		if first == "" {
+			first = e.ID
+		}
+	}
+	http.Redirect(w, r, "/profiles/"+first, http.StatusSeeOther)
+}
+
+// handleUpload profiles an uploaded file. Uploads are not recorded in the
+// history, which is keyed by path.
+func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
+	name, profiles, err := s.profileUpload(w, r)
+	if err != nil {
+		redirectError(w, r, err.Error())
+		return
+	}
+
+	var first string
+	for _, profile := range profiles {
+		e := s.add(name, profile, false)
+		if first == "" {
+			first = e.ID
+		}
+	}
+	http.Redirect(w, r, "/profiles/"+first, http.StatusSeeOther)
+}
+
+// profileUpload stores the file of a multipart upload in a temporary
+// directory, profiles it and returns its name and profiles.
+func (s *Server) profileUpload(w http.ResponseWriter, r *http.Request) (string, []*profiler.DatasetProfile, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.config.MaxUpload)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	defer file.Close()
+
+	dir, err := os.MkdirTemp("", "datasleuth_upload_*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to store upload: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Keep the name, whose extension tells the format
+	name := filepath.Base(filepath.Clean("/" + header.Filename))
+	if name == "/" || name == "." {
+		name = "upload.csv"
+	}
+	path := filepath.Join(dir, name)
+
+	out, err := os.Create(path)
+	if err == nil {
+		_, err = io.Copy(out, file)
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to store upload: %w", err)
+	}
+
+	profiles, err := profileSource(r.Context(), path, s.config.Options)
+	if err != nil {
+		return "", nil, err
+	}
+	return name, profiles, nil
+}
+
+type profilePageData struct {
+	Version string
+	Entry   *entry
+	Dataset string // dataset of a history run, whose trends are linked
+	Report  template.JS
+	Others  []profileSummary
+}
+
+func (s *Server) handleProfilePage(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.lookup(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := report.EncodeJSONReport(&buf, e.Profile); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	others := make([]profileSummary, 0)
+	for _, summary := range s.sessionSummaries() {
+		if summary.ID != e.ID {
+			others = append(others, summary)
+		}
+	}
+	if datasets, err := s.historyRuns(); err == nil {
+		for _, dataset := range datasets {
+			for _, run := range dataset.Runs {
+				if run.ID != e.ID {
+					others = append(others, run)
+				}
+			}
+		}
+	}
+
+	data := profilePageData{
+		Version: s.config.Version,
+		Entry:   e,
+		Report:  template.JS(buf.String()),
+		Others:  others,
+	}
+	if strings.HasPrefix(e.ID, "r") {
+		data.Dataset = e.Source
+	}
+	s.render(w, profileTemplate, data)
+}
+
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.lookup(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteHTMLReport(&buf, e.Profile); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+func (s *Server) handleJSON(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.lookup(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := report.EncodeJSONReport(&buf, e.Profile); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(buf.Bytes())
+}
+
+func (s *Server) handleCompare(w http.ResponseWriter, r *http.Request) {
+	base, ok := s.lookup(w, r.URL.Query().Get("base"))
+	if !ok {
+		return
+	}
+	target, ok := s.lookup(w, r.URL.Query().Get("target"))
+	if !ok {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteComparisonHTMLReport(&buf, compare.Compare(base.Profile, target.Profile, compare.DefaultOptions())); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// profileSource profiles every table or sheet of source, or source itself,
+// until ctx is done, such as when the client goes away.
+func profileSource(ctx context.Context, source string, opts profiler.Options) ([]*profiler.DatasetProfile, error) {
+	switch {
+	case opts.Table == "" && profiler.IsSQLite(source):
+		return profiler.ProfileSQLiteTables(ctx, source, opts)
+	case opts.Sheet == "" && profiler.IsExcel(source):
+		return profiler.ProfileExcelSheets(ctx, source, opts)
+	}
+
+	profile, err := profiler.ProfileDatasetContext(ctx, source, opts)
+	if err != nil {
+		return nil, err
+	}
+	return []*profiler.DatasetProfile{profile}, nil
+}
+
+// add keeps profile for the session. Profiles of a path or URL are recorded
+// in the history when there is one, and then served as history runs.
+func (s *Server) add(source string, profile *profiler.DatasetProfile, record bool) *entry {
+	e := &entry{
+		Name:      profileName(profile),
+		Source:    profiler.RedactSource(source),
+		CreatedAt: profile.CreatedAt,
+		Profile:   profile,
+	}
+
+	if record && s.config.History != nil {
+		var buf bytes.Buffer
+		if err := report.EncodeJSONReport(&buf, profile); err == nil {
+			run := history.NewRun(history.DatasetKey(source, profile.Table), profile)
+			if id, err := s.config.History.Record(run, buf.Bytes()); err == nil {
+				e.ID = runProfileID(id)
+				e.Source = run.Dataset
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e.ID == "" {
+		e.ID = "s" + strconv.Itoa(len(s.session)+1)
+		s.session = append(s.session, e)
+	}
+	s.cache[e.ID] = e
+	return e
+}
+
+// lookup finds a session profile or loads a history run, answering 404 when
+// there is none.
+func (s *Server) lookup(w http.ResponseWriter, id string) (*entry, bool) {
+	e, status, err := s.find(id)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return nil, false
+	}
+	return e, true
+}
+
+// find returns the profile with id, or an error with the HTTP status that
+// goes with it.
+func (s *Server) find(id string) (*entry, int, error) {
+	s.mu.Lock()
+	e, ok := s.cache[id]
+	s.mu.Unlock()
+	if ok {
+		return e, http.StatusOK, nil
+	}
+
+	runID, err := strconv.ParseInt(strings.TrimPrefix(id, "r"), 10, 64)
+	if !strings.HasPrefix(id, "r") || err != nil || s.config.History == nil {
+		return nil, http.StatusNotFound, fmt.Errorf("no profile %q", id)
+	}
+
+	run, err := s.config.History.Run(runID)
+	if err != nil {
+		return nil, http.StatusNotFound, err
+	}
+	content, err := s.config.History.Report(runID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	profile, err := report.DecodeJSONReport(content)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	e = &entry{ID: id, Name: profileName(profile), Source: run.Dataset, CreatedAt: run.CreatedAt, Profile: profile}
+	s.mu.Lock()
+	s.cache[id] = e
+	s.mu.Unlock()
+	return e, http.StatusOK, nil
+}
+
+func (s *Server) sessionSummaries() []profileSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	summaries := make([]profileSummary, 0, len(s.session))
+	for i := len(s.session) - 1; i >= 0; i-- {
+		summaries = append(summaries, s.session[i].summary())
+	}
+	return summaries
+}
+
+// historyRuns lists the latest runs of every dataset in the history, most
+// recent first.
+func (s *Server) historyRuns() ([]datasetRuns, error) {
+	if s.config.History == nil {
+		return nil, nil
+	}
+
+	datasets, err := s.config.History.Datasets()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]datasetRuns, 0, len(datasets))
+	for _, dataset := range datasets {
+		runs, err := s.config.History.Runs(dataset.Name, recentRuns)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(runs, func(i, j int) bool { return runs[i].ID > runs[j].ID })
+
+		summaries := make([]profileSummary, len(runs))
+		for i, run := range runs {
+			summaries[i] = profileSummary{
+				ID:           runProfileID(run.ID),
+				Name:         filepath.Base(dataset.Name),
+				Source:       run.Source,
+				Dataset:      dataset.Name,
+				CreatedAt:    run.CreatedAt,
+				RowCount:     run.RowCount,
+				ColumnCount:  run.ColumnCount,
+				QualityScore: run.QualityScore,
+			}
+		}
+		result = append(result, datasetRuns{Name: dataset.Name, Runs: summaries, Sparkline: sparkline(summaries)})
+	}
+	return result, nil
+}
+
+func profileName(profile *profiler.DatasetProfile) string {
+	if profile.Table != "" {
+		return profile.Filename + " › " + profile.Table
+	}
+	return profile.Filename
+}
+
+func (s *Server) render(w http.ResponseWriter, tmpl *template.Template, data interface{}) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		http.Error(w, fmt.Sprintf("failed to render page: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+func redirectError(w http.ResponseWriter, r *http.Request, message string) {
+	http.Redirect(w, r, "/?error="+url.QueryEscape(message), http.StatusSeeOther)
+}
@@ -0,0 +1,225 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/events"
+	"github.com/kamalm96/datasleuth/internal/history"
+)
+
+const testCSV = "name,age\nalice,30\nbob,41\ncarol,\n"
+
+func newTestServer(t *testing.T, withHistory bool) (*httptest.Server, *history.Store) {
+	t.Helper()
+
+	config := Config{Version: "test"}
+	var store *history.Store
+	if withHistory {
+		var err error
+		store, err = history.Open(filepath.Join(t.TempDir(), "history.db"))
+		if err != nil {
+			t.Fatalf("history.Open failed: %v", err)
+		}
+		t.Cleanup(func() { store.Close() })
+		config.History = store
+	}
+
+	server := httptest.NewServer(New(config).Handler())
+	t.Cleanup(server.Close)
+	return server, store
+}
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestUploadAndBrowse(t *testing.T) {
+	server, _ := newTestServer(t, false)
+
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	part, _ := writer.CreateFormFile("file", "people.csv")
+	io.WriteString(part, testCSV)
+	writer.Close()
+
+	resp, err := http.Post(server.URL+"/upload", writer.FormDataContentType(), &form)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.Request.URL.Path != "/profiles/s1" {
+		t.Fatalf("Expected a redirect to the profile page, got %s", resp.Request.URL)
+	}
+
+	status, page := get(t, server.URL+"/profiles/s1")
+	if status != http.StatusOK || !strings.Contains(page, "people.csv") || !strings.Contains(page, `"row_count": 3`) {
+		t.Errorf("Expected the profile page with the embedded report, got %d:\n%s", status, page)
+	}
+
+	status, body := get(t, server.URL+"/api/profiles/s1")
+	var report struct {
+		RowCount int                        `json:"row_count"`
+		Columns  map[string]json.RawMessage `json:"columns"`
+	}
+	if err := json.Unmarshal([]byte(body), &report); err != nil || status != http.StatusOK {
+		t.Fatalf("Expected a JSON report, got %d %v", status, err)
+	}
+	if report.RowCount != 3 || len(report.Columns) != 2 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+
+	if status, page := get(t, server.URL+"/"); status != http.StatusOK || !strings.Contains(page, `href="/profiles/s1"`) {
+		t.Errorf("Expected the index to list the upload, got %d", status)
+	}
+	if status, _ := get(t, server.URL+"/profiles/s9"); status != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown profile, got %d", status)
+	}
+}
+
+func TestProfilePathRecordsHistory(t *testing.T) {
+	server, store := newTestServer(t, true)
+
+	path := filepath.Join(t.TempDir(), "people.csv")
+	if err := os.WriteFile(path, []byte(testCSV), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := http.PostForm(server.URL+"/profile", url.Values{"source": {path}})
+		if err != nil {
+			t.Fatalf("profile failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	runs, err := store.Runs(history.DatasetKey(path, ""), 0)
+	if err != nil || len(runs) != 2 {
+		t.Fatalf("Expected 2 recorded runs, got %d (%v)", len(runs), err)
+	}
+
+	status, page := get(t, server.URL+"/compare?base=r1&target=r2")
+	if status != http.StatusOK || !strings.Contains(page, "people.csv") {
+		t.Errorf("Expected a comparison report, got %d", status)
+	}
+
+	status, body := get(t, server.URL+"/api/profiles")
+	if status != http.StatusOK || !strings.Contains(body, `"id":"r2"`) {
+		t.Errorf("Expected the runs in the listing, got %d %s", status, body)
+	}
+}
+
+func TestDatasetTrends(t *testing.T) {
+	server, _ := newTestServer(t, true)
+
+	path := filepath.Join(t.TempDir(), "people.csv")
+	for _, content := range []string{testCSV, testCSV + "dave,\n,\n"} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.PostForm(server.URL+"/profile", url.Values{"source": {path}})
+		if err != nil {
+			t.Fatalf("profile failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	trends := "/dataset?name=" + url.QueryEscape(history.DatasetKey(path, ""))
+	status, page := get(t, server.URL+"/")
+	if status != http.StatusOK || !strings.Contains(page, `href="/dataset?name=%2f`) || !strings.Contains(page, `class="sparkline"`) {
+		t.Errorf("Expected the index to link the trends of the dataset with a sparkline, got %d:\n%s", status, page)
+	}
+
+	status, page = get(t, server.URL+trends)
+	for _, expected := range []string{"Quality score", "Missing cells", `href="/compare?base=r1&target=r2"`, `<td>5</td>`, "<title>#2 "} {
+		if !strings.Contains(page, expected) {
+			t.Errorf("Expected the dataset page to contain %q, got %d:\n%s", expected, status, page)
+		}
+	}
+	if status, page := get(t, server.URL+"/profiles/r2"); status != http.StatusOK || !strings.Contains(page, `people.csv">trends</a>`) {
+		t.Errorf("Expected the run to link the trends of its dataset, got %d", status)
+	}
+
+	if status, _ := get(t, server.URL+"/dataset?name=other.csv"); status != http.StatusNotFound {
+		t.Errorf("Expected 404 for a dataset without runs, got %d", status)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	runs := []profileSummary{{QualityScore: 100}, {QualityScore: 50}, {QualityScore: 0}}
+	if line := sparkline(runs); line != "1.0,23.0 60.0,12.0 119.0,1.0" {
+		t.Errorf("Expected the scores oldest first from the bottom up, got %s", line)
+	}
+	if line := sparkline(runs[:1]); line != "60.0,1.0" {
+		t.Errorf("Expected a single run in the middle, got %s", line)
+	}
+	if formatCount(-1234567) != "-1,234,567" || formatCount(999) != "999" {
+		t.Errorf("Unexpected counts %s and %s", formatCount(-1234567), formatCount(999))
+	}
+}
+
+func TestProfileErrorRedirects(t *testing.T) {
+	server, _ := newTestServer(t, false)
+
+	resp, err := http.PostForm(server.URL+"/profile", url.Values{"source": {"/does/not/exist.csv"}})
+	if err != nil {
+		t.Fatalf("profile failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if resp.Request.URL.Path != "/" || !strings.Contains(string(body), `class="error"`) {
+		t.Errorf("Expected the index with an error, got %s", resp.Request.URL)
+	}
+}
+
+func TestEventStream(t *testing.T) {
+	server, _ := newTestServer(t, false)
+
+	resp, err := http.Get(server.URL + "/events?source=people.csv")
+	if err != nil {
+		t.Fatalf("GET /events failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %s", resp.Header.Get("Content-Type"))
+	}
+
+	events.Publish(events.Event{Kind: events.Started, Source: "other.csv"})
+	events.Publish(events.Event{Kind: events.ColumnDone, Source: "people.csv", Column: "age"})
+	events.Publish(events.Event{Kind: events.Finished, Source: "people.csv", Rows: 3})
+
+	scanner := bufio.NewScanner(resp.Body)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if strings.HasPrefix(scanner.Text(), "event: finished") {
+			break
+		}
+	}
+
+	stream := strings.Join(lines, "\n")
+	if strings.Contains(stream, "other.csv") {
+		t.Error("Expected the events of other sources to be filtered out")
+	}
+	if !strings.Contains(stream, "event: column_done\ndata: {\"kind\":\"column_done\",\"source\":\"people.csv\"") {
+		t.Errorf("Expected the column_done event, got:\n%s", stream)
+	}
+}
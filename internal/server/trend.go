@@ -0,0 +1,182 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/history"
+)
+
+const trendRuns = 100 // runs charted on a dataset page
+
+// Sizes of the trend charts, in SVG units. Charts scale to the width of the
+// page; sparklines are drawn as they are.
+const (
+	chartWidth, chartHeight          = 720.0, 140.0
+	chartLeft, chartTop, chartBottom = 70.0, 10.0, 10.0
+	sparkWidth, sparkHeight          = 120.0, 24.0
+)
+
+// trendChart is a line chart of one measure of a dataset across its runs.
+type trendChart struct {
+	Title     string
+	Line      string // points of the polyline
+	Points    []trendPoint
+	High, Low string // the values at the top and bottom of the chart
+	Latest    string
+}
+
+type trendPoint struct {
+	X, Y  float64
+	ID    string
+	Title string
+}
+
+// trendRun is a run listed on a dataset page, with the run before it to
+// compare with.
+type trendRun struct {
+	history.Run
+	ProfileID  string
+	PreviousID string
+}
+
+type datasetPageData struct {
+	Version string
+	Dataset string
+	Runs    []trendRun // most recent first
+	Charts  []trendChart
+}
+
+func (s *Server) handleDataset(w http.ResponseWriter, r *http.Request) {
+	if s.config.History == nil {
+		http.Error(w, "the profile history is turned off", http.StatusNotFound)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	runs, err := s.config.History.Runs(name, trendRuns)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(runs) == 0 {
+		http.Error(w, fmt.Sprintf("no runs recorded for %q", name), http.StatusNotFound)
+		return
+	}
+
+	data := datasetPageData{
+		Version: s.config.Version,
+		Dataset: name,
+		Runs:    make([]trendRun, len(runs)),
+		Charts: []trendChart{
+			newTrendChart("Quality score", runs, func(run history.Run) float64 { return float64(run.QualityScore) }, true),
+			newTrendChart("Rows", runs, func(run history.Run) float64 { return float64(run.RowCount) }, false),
+			newTrendChart("Missing cells", runs, func(run history.Run) float64 { return float64(run.MissingCells) }, false),
+			newTrendChart("Duplicate rows", runs, func(run history.Run) float64 { return float64(run.DuplicateRows) }, false),
+			newTrendChart("Parse errors", runs, func(run history.Run) float64 { return float64(run.ParseErrors) }, false),
+		},
+	}
+	for i, run := range runs {
+		listed := trendRun{Run: run, ProfileID: runProfileID(run.ID)}
+		if i > 0 {
+			listed.PreviousID = runProfileID(runs[i-1].ID)
+		}
+		data.Runs[len(runs)-1-i] = listed
+	}
+
+	s.render(w, datasetTemplate, data)
+}
+
+func runProfileID(id int64) string {
+	return "r" + strconv.FormatInt(id, 10)
+}
+
+// newTrendChart charts value across runs, oldest first. Scores are charted
+// from 0 to 100; other measures from their smallest to their largest value,
+// so that a small change still shows.
+func newTrendChart(title string, runs []history.Run, value func(history.Run) float64, score bool) trendChart {
+	values := make([]float64, len(runs))
+	for i, run := range runs {
+		values[i] = value(run)
+	}
+
+	low, high := 0.0, 100.0
+	if !score {
+		low, high = values[0], values[0]
+		for _, v := range values {
+			low, high = math.Min(low, v), math.Max(high, v)
+		}
+	}
+
+	chart := trendChart{
+		Title:  title,
+		High:   formatCount(high),
+		Low:    formatCount(low),
+		Latest: formatCount(values[len(values)-1]),
+		Points: make([]trendPoint, len(runs)),
+	}
+	xs, ys := plotTrend(values, low, high, chartLeft, chartTop, chartWidth-chartLeft, chartHeight-chartTop-chartBottom)
+	for i, run := range runs {
+		chart.Points[i] = trendPoint{
+			X:     xs[i],
+			Y:     ys[i],
+			ID:    runProfileID(run.ID),
+			Title: fmt.Sprintf("#%d %s: %s", run.ID, run.CreatedAt.Local().Format("2006-01-02 15:04"), formatCount(values[i])),
+		}
+	}
+	chart.Line = polyline(xs, ys)
+	return chart
+}
+
+// sparkline is the polyline of the quality scores of runs, listed most
+// recent first, from 0 to 100.
+func sparkline(runs []profileSummary) string {
+	scores := make([]float64, len(runs))
+	for i, run := range runs {
+		scores[len(runs)-1-i] = float64(run.QualityScore)
+	}
+	xs, ys := plotTrend(scores, 0, 100, 1, 1, sparkWidth-2, sparkHeight-2)
+	return polyline(xs, ys)
+}
+
+// plotTrend places values evenly from left to left+width, and from top+height
+// for low up to top for high. A single value sits in the middle.
+func plotTrend(values []float64, low, high, left, top, width, height float64) ([]float64, []float64) {
+	xs := make([]float64, len(values))
+	ys := make([]float64, len(values))
+	for i, v := range values {
+		xs[i] = left + width/2
+		if len(values) > 1 {
+			xs[i] = left + width*float64(i)/float64(len(values)-1)
+		}
+		ys[i] = top + height/2
+		if high > low {
+			ys[i] = top + height*(high-v)/(high-low)
+		}
+	}
+	return xs, ys
+}
+
+func polyline(xs, ys []float64) string {
+	points := make([]string, len(xs))
+	for i := range xs {
+		points[i] = fmt.Sprintf("%.1f,%.1f", xs[i], ys[i])
+	}
+	return strings.Join(points, " ")
+}
+
+// formatCount writes a whole number with thousands separators.
+func formatCount(v float64) string {
+	digits := strconv.FormatInt(int64(math.Round(v)), 10)
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + "," + digits[i:]
+	}
+	return sign + digits
+}
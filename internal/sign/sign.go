@@ -0,0 +1,257 @@
+// Package sign signs the JSON reports DataSleuth writes and verifies them,
+// so that profiles and validation results kept as audit evidence can be
+// shown to be unchanged since they were written.
+//
+// A signature is a detached file next to the report, <report>.sig, so the
+// report itself stays readable by everything that reads it today.
+package sign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Extension is appended to the path of a report to name its signature.
+const Extension = ".sig"
+
+// version is the format of the signed message, and of the signature file.
+const version = 1
+
+// Signature is the content of a signature file.
+type Signature struct {
+	Version   int       `json:"version"`
+	Algorithm string    `json:"algorithm"`
+	KeyID     string    `json:"key_id"` // SHA-256 of the public key, hex
+	File      string    `json:"file"`   // base name of the signed report
+	SHA256    string    `json:"sha256"` // digest of the report, hex
+	SignedAt  time.Time `json:"signed_at"`
+	Value     []byte    `json:"signature"`
+}
+
+// ErrInvalid is returned when a signature does not match its report or key.
+var ErrInvalid = errors.New("invalid signature")
+
+// Path is where the signature of the report at path is written.
+func Path(path string) string {
+	return path + Extension
+}
+
+// LoadPrivateKey reads an Ed25519, ECDSA or RSA private key from a PEM file,
+// in PKCS #8, SEC 1 or PKCS #1 form.
+func LoadPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%s holds a %s, not a private key", path, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key %s: %w", path, err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok || algorithm(signer.Public()) == "" {
+		return nil, fmt.Errorf("unsupported key type %T in %s: use Ed25519, ECDSA or RSA", key, path)
+	}
+	return signer, nil
+}
+
+// LoadPublicKey reads the key to verify with from a PEM file: a public key,
+// or the private key itself.
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type != "PUBLIC KEY" {
+		signer, err := LoadPrivateKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return signer.Public(), nil
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key %s: %w", path, err)
+	}
+	if algorithm(key) == "" {
+		return nil, fmt.Errorf("unsupported key type %T in %s: use Ed25519, ECDSA or RSA", key, path)
+	}
+	return key, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", path)
+	}
+	return block, nil
+}
+
+func algorithm(key crypto.PublicKey) string {
+	switch key.(type) {
+	case ed25519.PublicKey:
+		return "ed25519"
+	case *ecdsa.PublicKey:
+		return "ecdsa-sha256"
+	case *rsa.PublicKey:
+		return "rsa-pkcs1v15-sha256"
+	}
+	return ""
+}
+
+// KeyID is the SHA-256 fingerprint of the public key, in hex.
+func KeyID(key crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// message is what is signed: the format version, the name and digest of the
+// report and the signing time, so that none of them can be changed alone.
+func (s *Signature) message() []byte {
+	return []byte(fmt.Sprintf("datasleuth-signature-v%d\n%s\n%s\n%s\n",
+		s.Version, s.File, s.SHA256, s.SignedAt.UTC().Format(time.RFC3339Nano)))
+}
+
+// SignFile signs the report at path with key and writes the signature to
+// Path(path).
+func SignFile(path string, key crypto.Signer) (*Signature, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+	keyID, err := KeyID(key.Public())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fingerprint key: %w", err)
+	}
+
+	sum := sha256.Sum256(content)
+	sig := &Signature{
+		Version:   version,
+		Algorithm: algorithm(key.Public()),
+		KeyID:     keyID,
+		File:      filepath.Base(path),
+		SHA256:    hex.EncodeToString(sum[:]),
+		SignedAt:  time.Now().UTC(),
+	}
+
+	message := sig.message()
+	if sig.Algorithm == "ed25519" {
+		sig.Value, err = key.Sign(nil, message, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(message)
+		sig.Value, err = key.Sign(nil, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign %s: %w", path, err)
+	}
+
+	encoded, err := json.MarshalIndent(sig, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(Path(path), append(encoded, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write signature: %w", err)
+	}
+	return sig, nil
+}
+
+// VerifyFile checks the report at path against the signature at sigPath, or
+// Path(path) when sigPath is empty, and the public key. It returns the
+// signature when it holds, and an error wrapping ErrInvalid when it does not.
+func VerifyFile(path, sigPath string, key crypto.PublicKey) (*Signature, error) {
+	if sigPath == "" {
+		sigPath = Path(path)
+	}
+	encoded, err := os.ReadFile(sigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature: %w", err)
+	}
+	var sig Signature
+	if err := json.Unmarshal(encoded, &sig); err != nil {
+		return nil, fmt.Errorf("failed to parse signature %s: %w", sigPath, err)
+	}
+	if sig.Version != version {
+		return nil, fmt.Errorf("unsupported signature version %d in %s", sig.Version, sigPath)
+	}
+
+	keyID, err := KeyID(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fingerprint key: %w", err)
+	}
+	if sig.KeyID != keyID {
+		return nil, fmt.Errorf("%w: signed with key %s, not %s", ErrInvalid, short(sig.KeyID), short(keyID))
+	}
+	if sig.Algorithm != algorithm(key) {
+		return nil, fmt.Errorf("%w: algorithm %s does not match the key", ErrInvalid, sig.Algorithm)
+	}
+	if !verify(key, sig.message(), sig.Value) {
+		return nil, fmt.Errorf("%w: the signature file was altered or not made with this key", ErrInvalid)
+	}
+
+	// The signature holds, so its digest is the one of the signed report
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+	sum := sha256.Sum256(content)
+	want, err := hex.DecodeString(sig.SHA256)
+	if err != nil || !bytes.Equal(sum[:], want) {
+		return nil, fmt.Errorf("%w: %s was modified after it was signed", ErrInvalid, path)
+	}
+	return &sig, nil
+}
+
+func verify(key crypto.PublicKey, message, signature []byte) bool {
+	switch key := key.(type) {
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, message, signature)
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(message)
+		return ecdsa.VerifyASN1(key, digest[:], signature)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(message)
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	}
+	return false
+}
+
+// short abbreviates a key ID for messages.
+func short(keyID string) string {
+	if len(keyID) > 16 {
+		return keyID[:16]
+	}
+	return keyID
+}
@@ -0,0 +1,139 @@
+package sign
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeKey(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	return path
+}
+
+func TestSignAndVerify(t *testing.T) {
+	dir := t.TempDir()
+
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	edDER, _ := x509.MarshalPKCS8PrivateKey(edKey)
+	ecDER, _ := x509.MarshalECPrivateKey(ecKey)
+
+	for _, tc := range []struct {
+		name      string
+		keyPath   string
+		algorithm string
+	}{
+		{"ed25519", writeKey(t, dir, "ed.pem", "PRIVATE KEY", edDER), "ed25519"},
+		{"ecdsa", writeKey(t, dir, "ec.pem", "EC PRIVATE KEY", ecDER), "ecdsa-sha256"},
+		{"rsa", writeKey(t, dir, "rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)), "rsa-pkcs1v15-sha256"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := LoadPrivateKey(tc.keyPath)
+			if err != nil {
+				t.Fatalf("LoadPrivateKey failed: %v", err)
+			}
+			pubDER, _ := x509.MarshalPKIXPublicKey(key.Public())
+			pubPath := writeKey(t, dir, tc.name+".pub", "PUBLIC KEY", pubDER)
+
+			reportPath := filepath.Join(dir, tc.name+"_profile.json")
+			os.WriteFile(reportPath, []byte(`{"row_count": 8}`), 0644)
+
+			sig, err := SignFile(reportPath, key)
+			if err != nil {
+				t.Fatalf("SignFile failed: %v", err)
+			}
+			if sig.Algorithm != tc.algorithm || sig.File != tc.name+"_profile.json" {
+				t.Errorf("Unexpected signature %+v", sig)
+			}
+
+			for _, path := range []string{pubPath, tc.keyPath} {
+				pub, err := LoadPublicKey(path)
+				if err != nil {
+					t.Fatalf("LoadPublicKey(%s) failed: %v", path, err)
+				}
+				if _, err := VerifyFile(reportPath, "", pub); err != nil {
+					t.Errorf("VerifyFile with %s failed: %v", path, err)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	dir := t.TempDir()
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	reportPath := filepath.Join(dir, "report.json")
+	os.WriteFile(reportPath, []byte(`{"passed": false}`), 0644)
+	if _, err := SignFile(reportPath, edKey); err != nil {
+		t.Fatalf("SignFile failed: %v", err)
+	}
+	signature, _ := os.ReadFile(Path(reportPath))
+
+	for _, tc := range []struct {
+		name   string
+		key    crypto.PublicKey
+		tamper func()
+	}{
+		{"intact", edKey.Public(), func() {}},
+		{"report changed", edKey.Public(), func() {
+			os.WriteFile(reportPath, []byte(`{"passed": true}`), 0644)
+		}},
+		{"digest replaced", edKey.Public(), func() {
+			os.WriteFile(reportPath, []byte(`{"passed": true}`), 0644)
+			var sig Signature
+			json.Unmarshal(signature, &sig)
+			sig.SHA256 = "3c1e6aaeb79c6de47b1b4c1c5ff4d8a0f0e6c1c1fbc1a6a1f08a5b0d1f3d2c4e"
+			encoded, _ := json.Marshal(sig)
+			os.WriteFile(Path(reportPath), encoded, 0644)
+		}},
+		{"other key", otherKey.Public(), func() {}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			os.WriteFile(reportPath, []byte(`{"passed": false}`), 0644)
+			os.WriteFile(Path(reportPath), signature, 0644)
+			tc.tamper()
+
+			_, err := VerifyFile(reportPath, "", tc.key)
+			if tc.name == "intact" {
+				if err != nil {
+					t.Errorf("Expected the signature to hold, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("Expected ErrInvalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadPrivateKeyErrors(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "key.txt")
+	os.WriteFile(notPEM, []byte("not a key"), 0600)
+	public := writeKey(t, dir, "key.pub", "PUBLIC KEY", []byte{0})
+
+	for _, path := range []string{notPEM, public, filepath.Join(dir, "missing.pem")} {
+		if _, err := LoadPrivateKey(path); err == nil {
+			t.Errorf("Expected an error loading %s", path)
+		}
+	}
+}
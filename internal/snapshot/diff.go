@@ -0,0 +1,144 @@
+package snapshot
+
+import "fmt"
+
+// Diff is the DDL drift between two snapshots: tables, columns and
+// constraints that were added, removed or changed.
+type Diff struct {
+	Base          string
+	Target        string
+	AddedTables   []string
+	RemovedTables []string
+	Tables        []TableDiff // tables in both snapshots whose definition differs
+}
+
+type TableDiff struct {
+	Name               string
+	AddedColumns       []Column
+	RemovedColumns     []Column
+	ChangedColumns     []ColumnChange
+	AddedConstraints   []Constraint
+	RemovedConstraints []Constraint
+}
+
+// ColumnChange lists what changed in the definition of a column, such as
+// "type integer → bigint".
+type ColumnChange struct {
+	Name    string
+	Changes []string
+}
+
+// Changed reports whether the snapshots differ at all.
+func (d *Diff) Changed() bool {
+	return len(d.AddedTables) > 0 || len(d.RemovedTables) > 0 || len(d.Tables) > 0
+}
+
+func (d TableDiff) changed() bool {
+	return len(d.AddedColumns) > 0 || len(d.RemovedColumns) > 0 || len(d.ChangedColumns) > 0 ||
+		len(d.AddedConstraints) > 0 || len(d.RemovedConstraints) > 0
+}
+
+// Compare returns the schema changes from base to target. Constraints are
+// matched by type, columns and definition, not by name.
+func Compare(base, target *Snapshot) *Diff {
+	diff := &Diff{
+		Base:          base.Source,
+		Target:        target.Source,
+		AddedTables:   make([]string, 0),
+		RemovedTables: make([]string, 0),
+		Tables:        make([]TableDiff, 0),
+	}
+
+	for i := range base.Tables {
+		baseTable := &base.Tables[i]
+		targetTable, ok := target.Table(baseTable.QualifiedName())
+		if !ok {
+			diff.RemovedTables = append(diff.RemovedTables, baseTable.QualifiedName())
+			continue
+		}
+
+		if tableDiff := compareTable(baseTable, targetTable); tableDiff.changed() {
+			diff.Tables = append(diff.Tables, tableDiff)
+		}
+	}
+
+	for i := range target.Tables {
+		if _, ok := base.Table(target.Tables[i].QualifiedName()); !ok {
+			diff.AddedTables = append(diff.AddedTables, target.Tables[i].QualifiedName())
+		}
+	}
+
+	return diff
+}
+
+func compareTable(base, target *Table) TableDiff {
+	diff := TableDiff{Name: base.QualifiedName()}
+
+	for _, baseCol := range base.Columns {
+		targetCol, ok := target.Column(baseCol.Name)
+		if !ok {
+			diff.RemovedColumns = append(diff.RemovedColumns, baseCol)
+			continue
+		}
+		if change := compareColumn(baseCol, targetCol); len(change.Changes) > 0 {
+			diff.ChangedColumns = append(diff.ChangedColumns, change)
+		}
+	}
+
+	for _, targetCol := range target.Columns {
+		if _, ok := base.Column(targetCol.Name); !ok {
+			diff.AddedColumns = append(diff.AddedColumns, targetCol)
+		}
+	}
+
+	diff.RemovedConstraints = missingConstraints(base.Constraints, target.Constraints)
+	diff.AddedConstraints = missingConstraints(target.Constraints, base.Constraints)
+
+	return diff
+}
+
+func compareColumn(base, target Column) ColumnChange {
+	change := ColumnChange{Name: base.Name}
+
+	if base.Type != target.Type {
+		change.Changes = append(change.Changes, fmt.Sprintf("type %s → %s", base.Type, target.Type))
+	}
+	if base.Nullable != target.Nullable {
+		change.Changes = append(change.Changes, fmt.Sprintf("%s → %s", nullability(base.Nullable), nullability(target.Nullable)))
+	}
+	if base.Default != target.Default {
+		change.Changes = append(change.Changes, fmt.Sprintf("default %s → %s", defaultValue(base.Default), defaultValue(target.Default)))
+	}
+
+	return change
+}
+
+// missingConstraints returns the constraints of from that to does not have.
+func missingConstraints(from, to []Constraint) []Constraint {
+	keys := make(map[string]bool, len(to))
+	for _, constraint := range to {
+		keys[constraint.Key()] = true
+	}
+
+	missing := make([]Constraint, 0)
+	for _, constraint := range from {
+		if !keys[constraint.Key()] {
+			missing = append(missing, constraint)
+		}
+	}
+	return missing
+}
+
+func nullability(nullable bool) string {
+	if nullable {
+		return "nullable"
+	}
+	return "not null"
+}
+
+func defaultValue(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
@@ -0,0 +1,160 @@
+package snapshot
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/lib/pq"
+)
+
+const postgresUserSchemas = `n.nspname NOT IN ('pg_catalog', 'information_schema') AND n.nspname NOT LIKE 'pg_toast%' AND n.nspname NOT LIKE 'pg_temp%'`
+
+func isPostgres(source string) bool {
+	lower := strings.ToLower(source)
+	return strings.HasPrefix(lower, "postgres://") || strings.HasPrefix(lower, "postgresql://")
+}
+
+// capturePostgres reads the catalog of every user schema. Row estimates come
+// from the planner statistics in pg_class, so no table is scanned.
+func capturePostgres(source string) (*Snapshot, error) {
+	db, err := sql.Open("postgres", source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PostgreSQL connection: %w", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+
+	snapshot := &Snapshot{Engine: "PostgreSQL", Tables: make([]Table, 0)}
+	tables := make(map[string]*Table)
+
+	rows, err := db.Query(`
+		SELECT n.nspname, c.relname, GREATEST(c.reltuples, 0)::bigint
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind IN ('r', 'p') AND ` + postgresUserSchemas + `
+		ORDER BY n.nspname, c.relname`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PostgreSQL tables: %w", err)
+	}
+	for rows.Next() {
+		var table Table
+		if err := rows.Scan(&table.Schema, &table.Name, &table.RowEstimate); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to list PostgreSQL tables: %w", err)
+		}
+		table.Columns = make([]Column, 0)
+		snapshot.Tables = append(snapshot.Tables, table)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list PostgreSQL tables: %w", err)
+	}
+
+	for i := range snapshot.Tables {
+		tables[snapshot.Tables[i].QualifiedName()] = &snapshot.Tables[i]
+	}
+
+	if err := postgresColumns(db, tables); err != nil {
+		return nil, err
+	}
+	if err := postgresConstraints(db, tables); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+func postgresColumns(db *sql.DB, tables map[string]*Table) error {
+	rows, err := db.Query(`
+		SELECT n.nspname, c.relname, a.attname, format_type(a.atttypid, a.atttypmod),
+			NOT a.attnotnull, COALESCE(pg_get_expr(d.adbin, d.adrelid), '')
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE a.attnum > 0 AND NOT a.attisdropped AND c.relkind IN ('r', 'p') AND ` + postgresUserSchemas + `
+		ORDER BY n.nspname, c.relname, a.attnum`)
+	if err != nil {
+		return fmt.Errorf("failed to read PostgreSQL columns: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schema, name string
+		var col Column
+		if err := rows.Scan(&schema, &name, &col.Name, &col.Type, &col.Nullable, &col.Default); err != nil {
+			return fmt.Errorf("failed to read PostgreSQL columns: %w", err)
+		}
+		if table, ok := tables[schema+"."+name]; ok {
+			table.Columns = append(table.Columns, col)
+		}
+	}
+
+	return rows.Err()
+}
+
+func postgresConstraints(db *sql.DB, tables map[string]*Table) error {
+	rows, err := db.Query(`
+		SELECT n.nspname, c.relname, con.conname, con.contype,
+			COALESCE(array_to_string(ARRAY(
+				SELECT a.attname FROM unnest(con.conkey) WITH ORDINALITY k(attnum, ord)
+				JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+				ORDER BY k.ord), ','), ''),
+			COALESCE(fn.nspname || '.' || fc.relname, ''),
+			COALESCE(array_to_string(ARRAY(
+				SELECT a.attname FROM unnest(con.confkey) WITH ORDINALITY k(attnum, ord)
+				JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum
+				ORDER BY k.ord), ','), ''),
+			pg_get_constraintdef(con.oid)
+		FROM pg_constraint con
+		JOIN pg_class c ON c.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_class fc ON fc.oid = con.confrelid
+		LEFT JOIN pg_namespace fn ON fn.oid = fc.relnamespace
+		WHERE con.contype IN ('p', 'u', 'f', 'c') AND ` + postgresUserSchemas + `
+		ORDER BY n.nspname, c.relname, con.conname`)
+	if err != nil {
+		return fmt.Errorf("failed to read PostgreSQL constraints: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schema, name, conName, conType, columns, refTable, refColumns, definition string
+		if err := rows.Scan(&schema, &name, &conName, &conType, &columns, &refTable, &refColumns, &definition); err != nil {
+			return fmt.Errorf("failed to read PostgreSQL constraints: %w", err)
+		}
+		table, ok := tables[schema+"."+name]
+		if !ok {
+			continue
+		}
+
+		constraint := Constraint{Name: conName, Columns: splitColumns(columns)}
+		switch conType {
+		case "p":
+			constraint.Type = ConstraintPrimaryKey
+			table.PrimaryKey = constraint.Columns
+		case "u":
+			constraint.Type = ConstraintUnique
+		case "f":
+			constraint.Type = ConstraintForeignKey
+			constraint.References = refTable + "(" + refColumns + ")"
+		case "c":
+			constraint.Type = ConstraintCheck
+			constraint.Definition = definition
+		}
+		table.Constraints = append(table.Constraints, constraint)
+	}
+
+	return rows.Err()
+}
+
+func splitColumns(columns string) []string {
+	if columns == "" {
+		return nil
+	}
+	return strings.Split(columns, ",")
+}
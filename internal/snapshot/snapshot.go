@@ -0,0 +1,194 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// Kind marks a JSON file as a schema snapshot rather than a profile.
+const Kind = "schema_snapshot"
+
+// Snapshot is the schema metadata of a database captured at one point in
+// time: its tables, columns, constraints and row estimates.
+type Snapshot struct {
+	Kind       string  `json:"kind"`
+	Source     string  `json:"source"`
+	Engine     string  `json:"engine"`
+	CapturedAt string  `json:"captured_at"`
+	Tables     []Table `json:"tables"`
+}
+
+type Table struct {
+	Schema      string       `json:"schema,omitempty"`
+	Name        string       `json:"name"`
+	RowEstimate int64        `json:"row_estimate"`
+	Columns     []Column     `json:"columns"`
+	PrimaryKey  []string     `json:"primary_key,omitempty"`
+	Constraints []Constraint `json:"constraints,omitempty"`
+}
+
+type Column struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+	Default  string `json:"default,omitempty"`
+}
+
+// Constraint is a primary key, unique, foreign key or check constraint.
+type Constraint struct {
+	Name       string   `json:"name,omitempty"`
+	Type       string   `json:"type"`
+	Columns    []string `json:"columns,omitempty"`
+	References string   `json:"references,omitempty"` // table(columns) of a foreign key
+	Definition string   `json:"definition,omitempty"` // expression of a check constraint
+}
+
+const (
+	ConstraintPrimaryKey = "PRIMARY KEY"
+	ConstraintUnique     = "UNIQUE"
+	ConstraintForeignKey = "FOREIGN KEY"
+	ConstraintCheck      = "CHECK"
+)
+
+// QualifiedName is schema.name, or name alone for databases without schemas.
+func (t *Table) QualifiedName() string {
+	if t.Schema == "" {
+		return t.Name
+	}
+	return t.Schema + "." + t.Name
+}
+
+// Column returns the named column, if the table has it.
+func (t *Table) Column(name string) (Column, bool) {
+	for _, col := range t.Columns {
+		if col.Name == name {
+			return col, true
+		}
+	}
+	return Column{}, false
+}
+
+// Table returns the table with the given qualified name, if captured.
+func (s *Snapshot) Table(name string) (*Table, bool) {
+	for i := range s.Tables {
+		if s.Tables[i].QualifiedName() == name {
+			return &s.Tables[i], true
+		}
+	}
+	return nil, false
+}
+
+// Key identifies a constraint by what it does rather than by its name, which
+// databases often generate and which differs between environments.
+func (c Constraint) Key() string {
+	key := c.Type + "(" + strings.Join(c.Columns, ",") + ")"
+	if c.References != "" {
+		key += " → " + c.References
+	}
+	if c.Definition != "" {
+		key += " " + c.Definition
+	}
+	return key
+}
+
+// Capture reads the schema of a PostgreSQL database, given a postgres://
+// connection string, or of a SQLite database file.
+func Capture(source string) (*Snapshot, error) {
+	var snapshot *Snapshot
+	var err error
+
+	switch {
+	case isPostgres(source):
+		snapshot, err = capturePostgres(source)
+	case profiler.IsSQLite(source):
+		snapshot, err = captureSQLite(source)
+	default:
+		return nil, fmt.Errorf("unsupported source %s: expected a postgres:// connection string or a SQLite database", redactSource(source))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.Kind = Kind
+	snapshot.Source = redactSource(source)
+	snapshot.CapturedAt = time.Now().Format(time.RFC3339)
+	sort.Slice(snapshot.Tables, func(i, j int) bool {
+		return snapshot.Tables[i].QualifiedName() < snapshot.Tables[j].QualifiedName()
+	})
+
+	return snapshot, nil
+}
+
+// Write saves the snapshot as indented JSON.
+func Write(snapshot *Snapshot, outputPath string) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot to file: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads a snapshot written by Write.
+func Load(inputPath string) (*Snapshot, error) {
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	if snapshot.Kind != Kind {
+		return nil, fmt.Errorf("%s is not a schema snapshot", inputPath)
+	}
+
+	return &snapshot, nil
+}
+
+// IsSnapshotFile reports whether path is a JSON schema snapshot.
+func IsSnapshotFile(path string) bool {
+	if !strings.EqualFold(filepath.Ext(path), ".json") {
+		return false
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	var header struct {
+		Kind string `json:"kind"`
+	}
+	if err := json.NewDecoder(file).Decode(&header); err != nil {
+		return false
+	}
+	return header.Kind == Kind
+}
+
+// redactSource hides the password of a connection string so that it is not
+// written to the snapshot.
+func redactSource(source string) string {
+	if !strings.Contains(source, "://") {
+		return source
+	}
+	u, err := url.Parse(source)
+	if err != nil {
+		return source
+	}
+	return u.Redacted()
+}
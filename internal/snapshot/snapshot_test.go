@@ -0,0 +1,180 @@
+package snapshot
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func createTestSQLite(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "app.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	statements := []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL UNIQUE, status TEXT DEFAULT 'active')`,
+		`CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users(id), amount REAL)`,
+		`CREATE INDEX orders_amount ON orders(amount)`,
+		`INSERT INTO users VALUES (1, 'a@example.com', 'active'), (2, 'b@example.com', NULL)`,
+		`INSERT INTO orders VALUES (1, 1, 9.5)`,
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("Failed to execute %q: %v", statement, err)
+		}
+	}
+
+	return path
+}
+
+func TestCaptureSQLite(t *testing.T) {
+	snap, err := Capture(createTestSQLite(t))
+	if err != nil {
+		t.Fatalf("Failed to capture schema: %v", err)
+	}
+
+	if snap.Kind != Kind || snap.Engine != "SQLite" || len(snap.Tables) != 2 {
+		t.Fatalf("Expected a SQLite snapshot of 2 tables, got %s %s with %d", snap.Kind, snap.Engine, len(snap.Tables))
+	}
+
+	users, ok := snap.Table("users")
+	if !ok {
+		t.Fatal("Expected table users")
+	}
+	if users.RowEstimate != 2 || len(users.Columns) != 3 {
+		t.Errorf("Expected 2 rows and 3 columns, got %d and %d", users.RowEstimate, len(users.Columns))
+	}
+	if strings.Join(users.PrimaryKey, ",") != "id" {
+		t.Errorf("Expected primary key id, got %v", users.PrimaryKey)
+	}
+
+	email, _ := users.Column("email")
+	if email.Type != "TEXT" || email.Nullable {
+		t.Errorf("Expected non-nullable TEXT email, got %+v", email)
+	}
+	status, _ := users.Column("status")
+	if !status.Nullable || status.Default != "'active'" {
+		t.Errorf("Expected nullable status defaulting to 'active', got %+v", status)
+	}
+
+	var unique bool
+	for _, constraint := range users.Constraints {
+		if constraint.Type == ConstraintUnique && strings.Join(constraint.Columns, ",") == "email" {
+			unique = true
+		}
+	}
+	if !unique {
+		t.Errorf("Expected a unique constraint on email, got %v", users.Constraints)
+	}
+
+	orders, _ := snap.Table("orders")
+	var foreignKey *Constraint
+	for i, constraint := range orders.Constraints {
+		if constraint.Type == ConstraintUnique {
+			t.Errorf("Expected CREATE INDEX not to be captured as a constraint, got %v", constraint)
+		}
+		if constraint.Type == ConstraintForeignKey {
+			foreignKey = &orders.Constraints[i]
+		}
+	}
+	if foreignKey == nil || foreignKey.References != "users(id)" || strings.Join(foreignKey.Columns, ",") != "user_id" {
+		t.Errorf("Expected foreign key user_id → users(id), got %v", orders.Constraints)
+	}
+}
+
+func TestWriteAndLoad(t *testing.T) {
+	snap, err := Capture(createTestSQLite(t))
+	if err != nil {
+		t.Fatalf("Failed to capture schema: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := Write(snap, path); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+
+	if !IsSnapshotFile(path) {
+		t.Error("Expected written file to be detected as a snapshot")
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Failed to load snapshot: %v", err)
+	}
+	if diff := Compare(snap, loaded); diff.Changed() {
+		t.Errorf("Expected loaded snapshot to match, got %+v", diff)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	base := &Snapshot{Tables: []Table{
+		{Schema: "public", Name: "users", Columns: []Column{
+			{Name: "id", Type: "integer"},
+			{Name: "email", Type: "text", Nullable: true},
+			{Name: "legacy", Type: "text", Nullable: true},
+		}, Constraints: []Constraint{{Name: "users_pkey", Type: ConstraintPrimaryKey, Columns: []string{"id"}}}},
+		{Schema: "public", Name: "audit", Columns: []Column{{Name: "id", Type: "integer"}}},
+	}}
+	target := &Snapshot{Tables: []Table{
+		{Schema: "public", Name: "users", Columns: []Column{
+			{Name: "id", Type: "bigint"},
+			{Name: "email", Type: "text"},
+			{Name: "created_at", Type: "timestamp with time zone", Default: "now()"},
+		}, Constraints: []Constraint{
+			{Name: "users_pkey1", Type: ConstraintPrimaryKey, Columns: []string{"id"}},
+			{Name: "users_email_key", Type: ConstraintUnique, Columns: []string{"email"}},
+		}},
+		{Schema: "public", Name: "orders", Columns: []Column{{Name: "id", Type: "integer"}}},
+	}}
+
+	diff := Compare(base, target)
+
+	if len(diff.AddedTables) != 1 || diff.AddedTables[0] != "public.orders" {
+		t.Errorf("Expected public.orders to be added, got %v", diff.AddedTables)
+	}
+	if len(diff.RemovedTables) != 1 || diff.RemovedTables[0] != "public.audit" {
+		t.Errorf("Expected public.audit to be removed, got %v", diff.RemovedTables)
+	}
+	if len(diff.Tables) != 1 {
+		t.Fatalf("Expected one changed table, got %d", len(diff.Tables))
+	}
+
+	users := diff.Tables[0]
+	if len(users.AddedColumns) != 1 || users.AddedColumns[0].Name != "created_at" {
+		t.Errorf("Expected created_at to be added, got %v", users.AddedColumns)
+	}
+	if len(users.RemovedColumns) != 1 || users.RemovedColumns[0].Name != "legacy" {
+		t.Errorf("Expected legacy to be removed, got %v", users.RemovedColumns)
+	}
+	if len(users.ChangedColumns) != 2 {
+		t.Fatalf("Expected id and email to change, got %v", users.ChangedColumns)
+	}
+	if users.ChangedColumns[0].Changes[0] != "type integer → bigint" {
+		t.Errorf("Expected id type change, got %v", users.ChangedColumns[0].Changes)
+	}
+	if users.ChangedColumns[1].Changes[0] != "nullable → not null" {
+		t.Errorf("Expected email nullability change, got %v", users.ChangedColumns[1].Changes)
+	}
+	if len(users.AddedConstraints) != 1 || users.AddedConstraints[0].Type != ConstraintUnique {
+		t.Errorf("Expected only the unique constraint to be added (renamed primary key matches), got %v", users.AddedConstraints)
+	}
+	if len(users.RemovedConstraints) != 0 {
+		t.Errorf("Expected no removed constraints, got %v", users.RemovedConstraints)
+	}
+}
+
+func TestRedactSource(t *testing.T) {
+	got := redactSource("postgres://admin:secret@db:5432/shop")
+	if strings.Contains(got, "secret") {
+		t.Errorf("Expected password to be redacted, got %s", got)
+	}
+	if redactSource("app.db") != "app.db" {
+		t.Error("Expected file paths to be left unchanged")
+	}
+}
@@ -0,0 +1,226 @@
+package snapshot
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+// captureSQLite reads the schema of a SQLite database file. SQLite keeps no
+// row estimates, so tables are counted; check constraints are not captured
+// because SQLite only exposes them inside the CREATE TABLE text.
+func captureSQLite(path string) (*Snapshot, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	defer db.Close()
+
+	names, err := sqliteTableNames(db)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{Engine: "SQLite", Tables: make([]Table, 0, len(names))}
+	for _, name := range names {
+		table, err := sqliteTable(db, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read table %s: %w", name, err)
+		}
+		snapshot.Tables = append(snapshot.Tables, *table)
+	}
+
+	return snapshot, nil
+}
+
+func sqliteTableNames(db *sql.DB) ([]string, error) {
+	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SQLite tables: %w", err)
+	}
+	defer rows.Close()
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to list SQLite tables: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+
+func sqliteTable(db *sql.DB, name string) (*Table, error) {
+	table := &Table{Name: name, Columns: make([]Column, 0)}
+	quoted := quoteIdentifier(name)
+
+	if err := db.QueryRow("SELECT COUNT(*) FROM " + quoted).Scan(&table.RowEstimate); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query("PRAGMA table_info(" + quoted + ")")
+	if err != nil {
+		return nil, err
+	}
+	primaryKey := make(map[int]string)
+	for rows.Next() {
+		var cid, notNull, pk int
+		var col Column
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &col.Name, &col.Type, &notNull, &defaultValue, &pk); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		col.Nullable = notNull == 0 && pk == 0
+		col.Default = defaultValue.String
+		table.Columns = append(table.Columns, col)
+		if pk > 0 {
+			primaryKey[pk] = col.Name
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := 1; i <= len(primaryKey); i++ {
+		table.PrimaryKey = append(table.PrimaryKey, primaryKey[i])
+	}
+	if len(table.PrimaryKey) > 0 {
+		table.Constraints = append(table.Constraints, Constraint{Type: ConstraintPrimaryKey, Columns: table.PrimaryKey})
+	}
+
+	unique, err := sqliteUniqueConstraints(db, quoted)
+	if err != nil {
+		return nil, err
+	}
+	table.Constraints = append(table.Constraints, unique...)
+
+	foreignKeys, err := sqliteForeignKeys(db, quoted)
+	if err != nil {
+		return nil, err
+	}
+	table.Constraints = append(table.Constraints, foreignKeys...)
+
+	return table, nil
+}
+
+// sqliteUniqueConstraints returns the UNIQUE constraints declared in the table
+// definition, skipping indexes created separately with CREATE INDEX.
+func sqliteUniqueConstraints(db *sql.DB, quoted string) ([]Constraint, error) {
+	rows, err := db.Query("PRAGMA index_list(" + quoted + ")")
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]string, 0)
+	for rows.Next() {
+		var seq, unique, partial int
+		var name, origin string
+		if err := rows.Scan(&seq, &name, &unique, &origin, &partial); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if unique == 1 && origin == "u" {
+			indexes = append(indexes, name)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(indexes)
+
+	constraints := make([]Constraint, 0, len(indexes))
+	for _, index := range indexes {
+		columns, err := sqliteIndexColumns(db, index)
+		if err != nil {
+			return nil, err
+		}
+		constraints = append(constraints, Constraint{Name: index, Type: ConstraintUnique, Columns: columns})
+	}
+
+	return constraints, nil
+}
+
+func sqliteIndexColumns(db *sql.DB, index string) ([]string, error) {
+	rows, err := db.Query("PRAGMA index_info(" + quoteIdentifier(index) + ")")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make([]string, 0)
+	for rows.Next() {
+		var seqno, cid int
+		var name sql.NullString
+		if err := rows.Scan(&seqno, &cid, &name); err != nil {
+			return nil, err
+		}
+		columns = append(columns, name.String)
+	}
+
+	return columns, rows.Err()
+}
+
+func sqliteForeignKeys(db *sql.DB, quoted string) ([]Constraint, error) {
+	rows, err := db.Query("PRAGMA foreign_key_list(" + quoted + ")")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type foreignKey struct {
+		table string
+		from  []string
+		to    []string
+	}
+	keys := make(map[int]*foreignKey)
+	ids := make([]int, 0)
+
+	for rows.Next() {
+		var id, seq int
+		var table, from string
+		var to, onUpdate, onDelete, match sql.NullString
+		if err := rows.Scan(&id, &seq, &table, &from, &to, &onUpdate, &onDelete, &match); err != nil {
+			return nil, err
+		}
+		key, ok := keys[id]
+		if !ok {
+			key = &foreignKey{table: table}
+			keys[id] = key
+			ids = append(ids, id)
+		}
+		key.from = append(key.from, from)
+		key.to = append(key.to, to.String)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Ints(ids)
+
+	constraints := make([]Constraint, 0, len(ids))
+	for _, id := range ids {
+		key := keys[id]
+		constraints = append(constraints, Constraint{
+			Type:       ConstraintForeignKey,
+			Columns:    key.from,
+			References: key.table + "(" + strings.Join(key.to, ",") + ")",
+		})
+	}
+
+	return constraints, nil
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,187 @@
+// Package split divides the rows of a dataset into train and test sets, at
+// random or stratified by the values of a column, as they are read.
+package split
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+
+	"github.com/kamalm96/datasleuth/internal/compare"
+)
+
+// Options chooses how rows are divided.
+type Options struct {
+	Train      float64 // share of the rows in the train set, between 0 and 1
+	StratifyBy string  // column whose values keep the same shares in both sets, empty for none
+	Seed       int64   // seeds the random assignment, so that a split can be repeated
+}
+
+func (o Options) Validate() error {
+	if o.Train <= 0 || o.Train >= 1 {
+		return fmt.Errorf("train share must be between 0 and 1: %g", o.Train)
+	}
+	return nil
+}
+
+// Stratum counts the rows of one value of the stratify column in each set.
+type Stratum struct {
+	Value string
+	Train int
+	Test  int
+}
+
+func (s Stratum) Rows() int {
+	return s.Train + s.Test
+}
+
+// TrainShare is the share of the rows of the stratum in the train set.
+func (s Stratum) TrainShare() float64 {
+	if s.Rows() == 0 {
+		return 0
+	}
+	return float64(s.Train) / float64(s.Rows())
+}
+
+// Splitter writes each row it is given to the train or the test CSV. Within
+// each stratum, the whole dataset when not stratified, a row goes to the
+// train set with a probability corrected by how far the stratum is from its
+// share so far, so that every stratum ends up within a row of its share
+// while the rows picked stay random.
+type Splitter struct {
+	opts   Options
+	train  *csv.Writer
+	test   *csv.Writer
+	rng    *rand.Rand
+	column int // index of the stratify column, -1 for none
+	strata map[string]*Stratum
+	header bool
+}
+
+func New(train, test io.Writer, opts Options) (*Splitter, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return &Splitter{
+		opts:   opts,
+		train:  csv.NewWriter(train),
+		test:   csv.NewWriter(test),
+		rng:    rand.New(rand.NewSource(opts.Seed)),
+		column: -1,
+		strata: make(map[string]*Stratum),
+	}, nil
+}
+
+// Add writes record to one of the sets, after the header on the first call.
+// It has the signature of profiler.Options.Rows.
+func (s *Splitter) Add(header, record []string) error {
+	if !s.header {
+		if err := s.writeHeader(header); err != nil {
+			return err
+		}
+	}
+
+	value := ""
+	if s.column >= 0 && s.column < len(record) {
+		value = record[s.column]
+	}
+	stratum, ok := s.strata[value]
+	if !ok {
+		stratum = &Stratum{Value: value}
+		s.strata[value] = stratum
+	}
+
+	p := s.opts.Train + s.opts.Train*float64(stratum.Rows()) - float64(stratum.Train)
+	if s.rng.Float64() < p {
+		stratum.Train++
+		return s.train.Write(record)
+	}
+	stratum.Test++
+	return s.test.Write(record)
+}
+
+func (s *Splitter) writeHeader(header []string) error {
+	if s.opts.StratifyBy != "" {
+		for i, name := range header {
+			if name == s.opts.StratifyBy {
+				s.column = i
+			}
+		}
+		if s.column < 0 {
+			return fmt.Errorf("stratify column %s not found", s.opts.StratifyBy)
+		}
+	}
+
+	s.header = true
+	if err := s.train.Write(header); err != nil {
+		return err
+	}
+	return s.test.Write(header)
+}
+
+// Flush writes out the rows buffered for both sets.
+func (s *Splitter) Flush() error {
+	s.train.Flush()
+	s.test.Flush()
+	if err := s.train.Error(); err != nil {
+		return err
+	}
+	return s.test.Error()
+}
+
+// Strata returns the row counts of each stratum, largest first, or of the
+// whole dataset when not stratified.
+func (s *Splitter) Strata() []Stratum {
+	strata := make([]Stratum, 0, len(s.strata))
+	for _, stratum := range s.strata {
+		strata = append(strata, *stratum)
+	}
+	sort.Slice(strata, func(i, j int) bool {
+		if strata[i].Rows() != strata[j].Rows() {
+			return strata[i].Rows() > strata[j].Rows()
+		}
+		return strata[i].Value < strata[j].Value
+	})
+	return strata
+}
+
+// Totals returns the rows written to the train and the test set.
+func (s *Splitter) Totals() (int, int) {
+	train, test := 0, 0
+	for _, stratum := range s.strata {
+		train += stratum.Train
+		test += stratum.Test
+	}
+	return train, test
+}
+
+// Result is a finished split: where the sets went, their strata, and how the
+// profiles of the two sets compare.
+type Result struct {
+	Source     string
+	Rows       int
+	TrainFile  string
+	TestFile   string
+	TrainRows  int
+	TestRows   int
+	StratifyBy string
+	Seed       int64
+	Strata     []Stratum
+	Comparison *compare.Result // train as the base, test as the target
+}
+
+// Comparable reports whether the two sets hold the same columns and no
+// column drifted between them.
+func (r *Result) Comparable() bool {
+	if r.Comparison.SchemaChanged() {
+		return false
+	}
+	for _, col := range r.Comparison.Columns {
+		if col.Drifted() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+package split
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func splitRows(t *testing.T, opts Options, labels []string) (*Splitter, [][]string, [][]string) {
+	t.Helper()
+	var train, test bytes.Buffer
+	splitter, err := New(&train, &test, opts)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	for i, label := range labels {
+		if err := splitter.Add([]string{"id", "label"}, []string{fmt.Sprint(i), label}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	if err := splitter.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	trainRows, err := csv.NewReader(&train).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read train set: %v", err)
+	}
+	testRows, err := csv.NewReader(&test).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read test set: %v", err)
+	}
+	return splitter, trainRows, testRows
+}
+
+func TestSplitterStratified(t *testing.T) {
+	// 1,000 rows, of which 970 negative, 25 positive and 5 rare
+	labels := make([]string, 1000)
+	for i := range labels {
+		switch {
+		case i%200 == 7:
+			labels[i] = "rare"
+		case i%40 == 3:
+			labels[i] = "pos"
+		default:
+			labels[i] = "neg"
+		}
+	}
+
+	splitter, train, test := splitRows(t, Options{Train: 0.8, StratifyBy: "label", Seed: 1}, labels)
+	if len(train)+len(test) != 1002 || train[0][1] != "label" || test[0][1] != "label" {
+		t.Fatalf("Expected both sets to start with the header and hold every row, got %d and %d rows", len(train), len(test))
+	}
+
+	strata := splitter.Strata()
+	if len(strata) != 3 || strata[0].Value != "neg" || strata[2].Value != "rare" {
+		t.Fatalf("Expected three strata, largest first, got %+v", strata)
+	}
+	for _, stratum := range strata {
+		if math.Abs(float64(stratum.Train)-0.8*float64(stratum.Rows())) >= 1 {
+			t.Errorf("Expected %s to keep 80%% of its rows in the train set within a row, got %+v", stratum.Value, stratum)
+		}
+	}
+	if trainRows, testRows := splitter.Totals(); trainRows != len(train)-1 || testRows != len(test)-1 {
+		t.Errorf("Expected totals of %d and %d, got %d and %d", len(train)-1, len(test)-1, trainRows, testRows)
+	}
+
+	// The same seed repeats the split, another one picks other rows
+	_, again, _ := splitRows(t, Options{Train: 0.8, StratifyBy: "label", Seed: 1}, labels)
+	_, other, _ := splitRows(t, Options{Train: 0.8, StratifyBy: "label", Seed: 2}, labels)
+	if fmt.Sprint(again) != fmt.Sprint(train) || fmt.Sprint(other) == fmt.Sprint(train) {
+		t.Error("Expected a split to depend on its seed alone")
+	}
+}
+
+func TestSplitterErrors(t *testing.T) {
+	for _, share := range []float64{0, 1, -0.5} {
+		if _, err := New(&bytes.Buffer{}, &bytes.Buffer{}, Options{Train: share}); err == nil {
+			t.Errorf("Expected a train share of %g to be rejected", share)
+		}
+	}
+
+	splitter, err := New(&bytes.Buffer{}, &bytes.Buffer{}, Options{Train: 0.5, StratifyBy: "class"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := splitter.Add([]string{"id", "label"}, []string{"1", "a"}); err == nil {
+		t.Error("Expected a missing stratify column to be rejected")
+	}
+}
@@ -0,0 +1,465 @@
+package validate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"gopkg.in/yaml.v3"
+)
+
+// Distribution is the reference distribution a column is expected to
+// follow, to catch broken randomization or a drifting sensor:
+//
+//	distribution:
+//	  reference: normal
+//	  mean: 50
+//	  stddev: 10
+//	  min_p_value: 0.01
+//
+// The column fails when a goodness-of-fit test rejects the reference: its
+// p-value falls below MinPValue, or its distance exceeds MaxDistance.
+type Distribution struct {
+	Reference   string   `yaml:"reference"`
+	Min         *float64 `yaml:"min,omitempty"` // bounds of a uniform reference, none for one over the column's values
+	Max         *float64 `yaml:"max,omitempty"`
+	Mean        *float64 `yaml:"mean,omitempty"`
+	StdDev      *float64 `yaml:"stddev,omitempty"`
+	Histogram   string   `yaml:"histogram,omitempty"` // file of reference bins, relative to the rules file
+	Test        string   `yaml:"test,omitempty"`      // ks or chi_squared
+	MinPValue   *float64 `yaml:"min_p_value,omitempty"`
+	MaxDistance *float64 `yaml:"max_distance,omitempty"` // KS distance, or total variation distance for chi_squared
+
+	bins []ReferenceBin // read from Histogram by LoadRules
+}
+
+// ReferenceBin is a bin of a reference histogram: the values from Lower up
+// to Upper, spread evenly, seen Count times.
+type ReferenceBin struct {
+	Lower float64 `yaml:"lower"`
+	Upper float64 `yaml:"upper"`
+	Count float64 `yaml:"count"`
+}
+
+const (
+	referenceUniform   = "uniform"
+	referenceNormal    = "normal"
+	referenceHistogram = "histogram"
+
+	testKS         = "ks"
+	testChiSquared = "chi_squared"
+
+	defaultMinP    = 0.01
+	minExpectedBin = 5 // chi-squared bins expecting fewer values are merged into a neighbour
+)
+
+var (
+	references = []string{referenceUniform, referenceNormal, referenceHistogram}
+	tests      = []string{testKS, testChiSquared}
+)
+
+// categorical reports whether the reference is a uniform one over the
+// distinct values of the column rather than over a range.
+func (d *Distribution) categorical() bool {
+	return d.Reference == referenceUniform && d.Min == nil && d.Max == nil
+}
+
+// test is the goodness-of-fit test to run: KS by default, chi-squared for
+// a categorical reference.
+func (d *Distribution) test() string {
+	switch {
+	case d.Test != "":
+		return d.Test
+	case d.categorical():
+		return testChiSquared
+	default:
+		return testKS
+	}
+}
+
+// minPValue is the smallest p-value passing, 0.01 unless only a distance
+// is bounded.
+func (d *Distribution) minPValue() float64 {
+	switch {
+	case d.MinPValue != nil:
+		return *d.MinPValue
+	case d.MaxDistance != nil:
+		return 0
+	default:
+		return defaultMinP
+	}
+}
+
+func (d *Distribution) validate() error {
+	if !containsValue(references, d.Reference) {
+		return fmt.Errorf("unknown reference %q (want %s)", d.Reference, strings.Join(references, ", "))
+	}
+
+	// Parameters of another reference are a mistake, not a default
+	switch {
+	case (d.Min != nil || d.Max != nil) && d.Reference != referenceUniform:
+		return fmt.Errorf("min and max are not parameters of a %s reference", d.Reference)
+	case (d.Mean != nil || d.StdDev != nil) && d.Reference != referenceNormal:
+		return fmt.Errorf("mean and stddev are not parameters of a %s reference", d.Reference)
+	case d.Histogram != "" && d.Reference != referenceHistogram:
+		return fmt.Errorf("histogram is not a parameter of a %s reference", d.Reference)
+	}
+
+	switch d.Reference {
+	case referenceUniform:
+		if (d.Min == nil) != (d.Max == nil) {
+			return fmt.Errorf("uniform reference needs both min and max, or neither for one over the column's values")
+		}
+		if d.Min != nil && *d.Min >= *d.Max {
+			return fmt.Errorf("uniform reference min %g is not below max %g", *d.Min, *d.Max)
+		}
+	case referenceNormal:
+		if d.Mean == nil || d.StdDev == nil {
+			return fmt.Errorf("normal reference needs mean and stddev")
+		}
+		if *d.StdDev <= 0 {
+			return fmt.Errorf("normal reference stddev %g is not positive", *d.StdDev)
+		}
+	case referenceHistogram:
+		if d.Histogram == "" {
+			return fmt.Errorf("histogram reference needs a histogram file")
+		}
+	}
+
+	if d.Test != "" && !containsValue(tests, d.Test) {
+		return fmt.Errorf("unknown test %q (want %s)", d.Test, strings.Join(tests, ", "))
+	}
+	if d.categorical() && d.test() == testKS {
+		return fmt.Errorf("a uniform reference over the column's values is tested with chi_squared, not ks")
+	}
+	if d.MinPValue != nil && (*d.MinPValue < 0 || *d.MinPValue > 1) {
+		return fmt.Errorf("min_p_value %g is not between 0 and 1", *d.MinPValue)
+	}
+	if d.MaxDistance != nil && (*d.MaxDistance < 0 || *d.MaxDistance > 1) {
+		return fmt.Errorf("max_distance %g is not between 0 and 1", *d.MaxDistance)
+	}
+	return nil
+}
+
+// loadHistogram reads the reference bins of a histogram reference, from a
+// path relative to the directory of the rules file:
+//
+//	bins:
+//	  - {lower: 0, upper: 10, count: 120}
+//	  - {lower: 10, upper: 20, count: 340}
+func (d *Distribution) loadHistogram(rulesPath string) error {
+	path := d.Histogram
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(rulesPath), path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read histogram: %w", err)
+	}
+
+	var histogram struct {
+		Bins []ReferenceBin `yaml:"bins"`
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&histogram); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse histogram %s: %w", path, err)
+	}
+
+	bins := histogram.Bins
+	if len(bins) == 0 {
+		return fmt.Errorf("histogram %s has no bins", path)
+	}
+	sort.Slice(bins, func(i, j int) bool { return bins[i].Lower < bins[j].Lower })
+	total := 0.0
+	for i, bin := range bins {
+		if bin.Lower >= bin.Upper || bin.Count < 0 {
+			return fmt.Errorf("histogram %s: bin %g to %g with count %g is not a bin", path, bin.Lower, bin.Upper, bin.Count)
+		}
+		if i > 0 && bin.Lower < bins[i-1].Upper {
+			return fmt.Errorf("histogram %s: bins %g to %g and %g to %g overlap", path, bins[i-1].Lower, bins[i-1].Upper, bin.Lower, bin.Upper)
+		}
+		total += bin.Count
+	}
+	if total == 0 {
+		return fmt.Errorf("histogram %s has no counts", path)
+	}
+	d.bins = bins
+	return nil
+}
+
+// String names the reference, with its parameters.
+func (d *Distribution) String() string {
+	switch {
+	case d.Reference == referenceNormal:
+		return fmt.Sprintf("normal(%g, %g)", *d.Mean, *d.StdDev)
+	case d.Reference == referenceHistogram:
+		return fmt.Sprintf("histogram %s", d.Histogram)
+	case d.categorical():
+		return "uniform over the values"
+	default:
+		return fmt.Sprintf("uniform(%g, %g)", *d.Min, *d.Max)
+	}
+}
+
+// cdf is the share of the reference at or below x.
+func (d *Distribution) cdf(x float64) float64 {
+	switch d.Reference {
+	case referenceNormal:
+		return 0.5 * math.Erfc(-(x-*d.Mean)/(*d.StdDev*math.Sqrt2))
+	case referenceHistogram:
+		total, below := 0.0, 0.0
+		for _, bin := range d.bins {
+			total += bin.Count
+			switch {
+			case x >= bin.Upper:
+				below += bin.Count
+			case x > bin.Lower:
+				below += bin.Count * (x - bin.Lower) / (bin.Upper - bin.Lower)
+			}
+		}
+		return below / total
+	default:
+		return math.Min(math.Max((x-*d.Min)/(*d.Max-*d.Min), 0), 1)
+	}
+}
+
+// checkDistribution tests the column of rule against its reference
+// distribution. A continuous reference is tested on the histogram and
+// percentiles of the profile, so the KS distance is measured at the bucket
+// bounds and percentiles only; a categorical one on the counts of its top
+// values.
+func checkDistribution(r *Result, rule ColumnRule, col *profiler.ColumnProfile) {
+	d := rule.Distribution
+	if d.categorical() {
+		checkCategorical(r, rule, col)
+		return
+	}
+
+	buckets := col.HistogramBuckets
+	if !col.IsNumeric || len(buckets) == 0 {
+		if col.Count > 0 {
+			r.add("distribution", rule.Name, false, "no numeric values to test against %s (type %s)", d, col.DataType)
+		}
+		return
+	}
+
+	n := 0
+	for _, b := range buckets {
+		n += b.Count
+	}
+	if n == 0 {
+		return
+	}
+
+	var distance, pValue float64
+	var statistic string
+	if d.test() == testKS {
+		distance = ksDistance(d, col, n)
+		pValue = ksPValue(distance, n)
+		statistic = "KS distance"
+	} else {
+		observed := make([]float64, len(buckets))
+		expected := make([]float64, len(buckets))
+		for i, b := range buckets {
+			observed[i] = float64(b.Count)
+			// The tails of the reference fall into the outer buckets
+			low, high := d.cdf(b.LowerBound), d.cdf(b.UpperBound)
+			if i == 0 {
+				low = 0
+			}
+			if i == len(buckets)-1 {
+				high = 1
+			}
+			expected[i] = float64(n) * (high - low)
+		}
+		var ok bool
+		if distance, pValue, ok = chiSquaredTest(observed, expected); !ok {
+			r.add("distribution", rule.Name, false, "too few values for a chi-squared test against %s", d)
+			return
+		}
+		statistic = "distance"
+	}
+	addDistributionCheck(r, rule, statistic, distance, pValue)
+}
+
+// checkCategorical tests that each value of the column is seen equally
+// often: the allowed values when the rule lists them, or else the values
+// of the column, which the profile must list in full.
+func checkCategorical(r *Result, rule ColumnRule, col *profiler.ColumnProfile) {
+	counts := make(map[string]int, len(col.TopValues))
+	for _, v := range col.TopValues {
+		counts[v.Value] = v.Count
+	}
+
+	values := rule.AllowedValues
+	if len(values) == 0 {
+		listed, ok := allValues(col)
+		if !ok {
+			if col.Count > 0 {
+				r.add("distribution", rule.Name, false, "%d distinct values, not all listed to test; list them in allowed_values", col.UniqueCount)
+			}
+			return
+		}
+		values = listed
+	}
+
+	observed := make([]float64, len(values))
+	n := 0.0
+	for i, value := range values {
+		observed[i] = float64(counts[value])
+		n += observed[i]
+	}
+	if n == 0 {
+		return
+	}
+	expected := make([]float64, len(values))
+	for i := range expected {
+		expected[i] = n / float64(len(values))
+	}
+
+	distance, pValue, ok := chiSquaredTest(observed, expected)
+	if !ok {
+		r.add("distribution", rule.Name, false, "too few values for a chi-squared test against %s", rule.Distribution)
+		return
+	}
+	addDistributionCheck(r, rule, "distance", distance, pValue)
+}
+
+func addDistributionCheck(r *Result, rule ColumnRule, statistic string, distance, pValue float64) {
+	d := rule.Distribution
+	minP := d.minPValue()
+	passed := pValue >= minP && (d.MaxDistance == nil || distance <= *d.MaxDistance)
+
+	var bounds []string
+	if minP > 0 {
+		bounds = append(bounds, fmt.Sprintf("minimum p %g", minP))
+	}
+	if d.MaxDistance != nil {
+		bounds = append(bounds, fmt.Sprintf("maximum distance %g", *d.MaxDistance))
+	}
+	r.add("distribution", rule.Name, passed, "%s %.3f from %s (%s, p = %.3g; %s)",
+		statistic, distance, d, strings.ReplaceAll(d.test(), "_", "-"), pValue, strings.Join(bounds, ", "))
+}
+
+// ksDistance is the largest gap between the observed and reference
+// distributions where the profile tells the observed one: at the bucket
+// bounds of the histogram of n values, and at the percentiles.
+func ksDistance(d *Distribution, col *profiler.ColumnProfile, n int) float64 {
+	buckets := col.HistogramBuckets
+	distance := d.cdf(buckets[0].LowerBound)
+	below := 0
+	for _, b := range buckets {
+		below += b.Count
+		distance = math.Max(distance, math.Abs(float64(below)/float64(n)-d.cdf(b.UpperBound)))
+	}
+	for _, p := range col.Percentiles {
+		distance = math.Max(distance, math.Abs(float64(p.Rank)/100-d.cdf(p.Value)))
+	}
+	return distance
+}
+
+// ksPValue is the probability of a KS distance of at least distance
+// between n values and the distribution they are drawn from, by the
+// asymptotic Kolmogorov distribution with Stephens' correction for small n.
+func ksPValue(distance float64, n int) float64 {
+	sqrtN := math.Sqrt(float64(n))
+	lambda := (sqrtN + 0.12 + 0.11/sqrtN) * distance
+	if lambda < 1.18 {
+		if lambda == 0 {
+			return 1
+		}
+		y := math.Exp(-math.Pi * math.Pi / (8 * lambda * lambda))
+		return math.Min(math.Max(1-math.Sqrt(2*math.Pi)/lambda*(y+math.Pow(y, 9)+math.Pow(y, 25)+math.Pow(y, 49)), 0), 1)
+	}
+	x := math.Exp(-2 * lambda * lambda)
+	return math.Min(math.Max(2*(x-math.Pow(x, 4)+math.Pow(x, 9)), 0), 1)
+}
+
+// chiSquaredTest runs Pearson's chi-squared test of observed against
+// expected counts of the same total, after merging bins expecting fewer
+// than five values into a neighbour. It returns the total variation
+// distance between the two and the p-value, or false when fewer than two
+// bins remain.
+func chiSquaredTest(observed, expected []float64) (float64, float64, bool) {
+	var obs, exp []float64
+	o, e := 0.0, 0.0
+	for i := range observed {
+		o, e = o+observed[i], e+expected[i]
+		if e >= minExpectedBin {
+			obs, exp = append(obs, o), append(exp, e)
+			o, e = 0, 0
+		}
+	}
+	if len(exp) > 0 {
+		obs[len(obs)-1] += o
+		exp[len(exp)-1] += e
+	}
+	if len(exp) < 2 {
+		return 0, 0, false
+	}
+
+	n, statistic, distance := 0.0, 0.0, 0.0
+	for _, e := range exp {
+		n += e
+	}
+	for i := range exp {
+		statistic += (obs[i] - exp[i]) * (obs[i] - exp[i]) / exp[i]
+		distance += math.Abs(obs[i]-exp[i]) / n
+	}
+	return distance / 2, chiSquaredPValue(statistic, len(exp)-1), true
+}
+
+// chiSquaredPValue is the probability of a chi-squared statistic of at
+// least x with df degrees of freedom: the regularized upper incomplete
+// gamma function Q(df/2, x/2).
+func chiSquaredPValue(x float64, df int) float64 {
+	a, x := float64(df)/2, x/2
+	if x <= 0 {
+		return 1
+	}
+	lgamma, _ := math.Lgamma(a)
+	prefix := math.Exp(-x + a*math.Log(x) - lgamma)
+
+	if x < a+1 {
+		// Series for the lower function P
+		sum, term := 1/a, 1/a
+		for k := 1; k < 500 && term > sum*1e-15; k++ {
+			term *= x / (a + float64(k))
+			sum += term
+		}
+		return math.Max(1-sum*prefix, 0)
+	}
+
+	// Continued fraction for Q, by the modified Lentz method
+	const tiny = 1e-300
+	b := x + 1 - a
+	c, dd := 1/tiny, 1/b
+	h := dd
+	for k := 1; k < 500; k++ {
+		an := -float64(k) * (float64(k) - a)
+		b += 2
+		dd = an*dd + b
+		if math.Abs(dd) < tiny {
+			dd = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		dd = 1 / dd
+		delta := dd * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return math.Min(h*prefix, 1)
+}
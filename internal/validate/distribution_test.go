@@ -0,0 +1,175 @@
+package validate
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func TestPValues(t *testing.T) {
+	for _, tc := range []struct {
+		statistic float64
+		df        int
+		want      float64
+	}{
+		{3.841, 1, 0.05},
+		{18.307, 10, 0.05},
+		{0.5, 3, 0.919},
+		{100, 4, 0},
+	} {
+		if got := chiSquaredPValue(tc.statistic, tc.df); math.Abs(got-tc.want) > 0.001 {
+			t.Errorf("chi-squared %g with %d degrees of freedom: expected p %g, got %g", tc.statistic, tc.df, tc.want, got)
+		}
+	}
+
+	// The 5% critical value of the Kolmogorov distribution is 1.358
+	if got := ksPValue(1.358/math.Sqrt(10000), 10000); math.Abs(got-0.05) > 0.002 {
+		t.Errorf("Expected a KS p-value of 0.05, got %g", got)
+	}
+	if got := ksPValue(0, 100); got != 1 {
+		t.Errorf("Expected a p-value of 1 without distance, got %g", got)
+	}
+}
+
+func profileDistribution(t *testing.T) *profiler.DatasetProfile {
+	random := rand.New(rand.NewSource(7))
+	var b strings.Builder
+	b.WriteString("score,variant,skewed\n")
+	for i := 0; i < 5000; i++ {
+		variant, skewed := "a", "a"
+		if i%2 == 1 {
+			variant = "b"
+		}
+		if i%10 >= 7 {
+			skewed = "b"
+		}
+		fmt.Fprintf(&b, "%.3f,%s,%s\n", 50+10*random.NormFloat64(), variant, skewed)
+	}
+
+	path := filepath.Join(t.TempDir(), "scores.csv")
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatalf("Failed to write data: %v", err)
+	}
+	profile, err := profiler.ProfileDatasetWithOptions(path, profiler.Options{ExactRows: 10000})
+	if err != nil {
+		t.Fatalf("Failed to profile: %v", err)
+	}
+	return profile
+}
+
+func TestCheckDistribution(t *testing.T) {
+	profile := profileDistribution(t)
+
+	dir := t.TempDir()
+	histogram := "bins:\n"
+	for x := 0; x < 100; x += 5 {
+		// The normal density of 50, 10 at the middle of each bin
+		mid := float64(x) + 2.5
+		histogram += fmt.Sprintf("  - {lower: %d, upper: %d, count: %.0f}\n", x, x+5, 10000*math.Exp(-(mid-50)*(mid-50)/200))
+	}
+	if err := os.WriteFile(filepath.Join(dir, "scores.yaml"), []byte(histogram), 0644); err != nil {
+		t.Fatalf("Failed to write histogram: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		rule   string
+		passed bool
+	}{
+		{"normal", "reference: normal\n      mean: 50\n      stddev: 10", true},
+		{"normal chi-squared", "reference: normal\n      mean: 50\n      stddev: 10\n      test: chi_squared", true},
+		{"shifted normal", "reference: normal\n      mean: 53\n      stddev: 10", false},
+		{"uniform", "reference: uniform\n      min: 0\n      max: 100", false},
+		{"histogram", "reference: histogram\n      histogram: scores.yaml", true},
+		{"distance", "reference: normal\n      mean: 51\n      stddev: 10\n      max_distance: 0.1", true},
+	} {
+		path := filepath.Join(dir, "rules.yaml")
+		content := "columns:\n  - name: score\n    distribution:\n      " + tc.rule + "\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write rules: %v", err)
+		}
+		rules, err := LoadRules(path)
+		if err != nil {
+			t.Fatalf("%s: LoadRules failed: %v", tc.name, err)
+		}
+
+		result := AgainstRules(profile, rules, path)
+		if len(result.Checks) != 1 || result.Checks[0].Name != "distribution" || result.Passed() != tc.passed {
+			t.Errorf("%s: expected passed %v, got %+v", tc.name, tc.passed, result.Checks)
+		}
+	}
+}
+
+func TestCheckCategoricalDistribution(t *testing.T) {
+	profile := profileDistribution(t)
+	profile.Columns["score"].TopValues = profile.Columns["score"].TopValues[:5]
+	uniform := &Distribution{Reference: referenceUniform}
+
+	result := AgainstRules(profile, &Rules{Columns: []ColumnRule{
+		{Name: "variant", Distribution: uniform},
+		{Name: "skewed", Distribution: uniform},
+		{Name: "score", Distribution: uniform},
+	}}, "rules.yaml")
+
+	checks := make(map[string]Check)
+	for _, check := range result.Checks {
+		checks[check.Column] = check
+	}
+	if !checks["variant"].Passed || !strings.HasPrefix(checks["variant"].Message, "distance 0.000 from uniform over the values (chi-squared, p = 1") {
+		t.Errorf("Expected an even split to pass, got %+v", checks["variant"])
+	}
+	if checks["skewed"].Passed || !strings.HasPrefix(checks["skewed"].Message, "distance 0.200") {
+		t.Errorf("Expected a 70/30 split to fail, got %+v", checks["skewed"])
+	}
+	if checks["score"].Passed || !strings.Contains(checks["score"].Message, "not all listed") {
+		t.Errorf("Expected a column of many values to fail untested, got %+v", checks["score"])
+	}
+
+	// Allowed values the column lacks are expected as often as the others
+	result = AgainstRules(profile, &Rules{Columns: []ColumnRule{
+		{Name: "variant", AllowedValues: []string{"a", "b", "c"}, Distribution: uniform},
+	}}, "rules.yaml")
+	if result.Passed() {
+		t.Errorf("Expected a missing variant to fail, got %+v", result.Checks)
+	}
+}
+
+func TestLoadRulesInvalidDistribution(t *testing.T) {
+	for name, content := range map[string]string{
+		"unknown reference":  "reference: poisson",
+		"stray parameter":    "reference: uniform\n      mean: 5",
+		"half bounds":        "reference: uniform\n      min: 5",
+		"no stddev":          "reference: normal\n      mean: 5",
+		"negative stddev":    "reference: normal\n      mean: 5\n      stddev: -1",
+		"unknown test":       "reference: normal\n      mean: 5\n      stddev: 1\n      test: anderson",
+		"ks on values":       "reference: uniform\n      test: ks",
+		"p-value":            "reference: uniform\n      min_p_value: 5",
+		"no histogram":       "reference: histogram",
+		"missing histogram":  "reference: histogram\n      histogram: missing.yaml",
+		"overlapping bins":   "reference: histogram\n      histogram: overlap.yaml",
+		"unknown bin fields": "reference: histogram\n      histogram: weights.yaml",
+	} {
+		dir := t.TempDir()
+		for file, bins := range map[string]string{
+			"overlap.yaml": "bins:\n  - {lower: 0, upper: 10, count: 1}\n  - {lower: 5, upper: 15, count: 1}\n",
+			"weights.yaml": "bins:\n  - {lower: 0, upper: 10, weight: 1}\n",
+		} {
+			if err := os.WriteFile(filepath.Join(dir, file), []byte(bins), 0644); err != nil {
+				t.Fatalf("Failed to write histogram: %v", err)
+			}
+		}
+		path := filepath.Join(dir, "rules.yaml")
+		if err := os.WriteFile(path, []byte("columns:\n  - name: x\n    distribution:\n      "+content+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write rules: %v", err)
+		}
+		if _, err := LoadRules(path); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
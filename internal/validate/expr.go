@@ -0,0 +1,610 @@
+package validate
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// Row expressions are the language of row rules: columns of the row,
+// numbers, 'quoted' strings, true and false, combined with
+//
+//	||  &&  !                 logic
+//	==  !=  <  <=  >  >=      comparison
+//	+  -  *  /  %             arithmetic
+//	abs(x) len(s) lower(s) upper(s)
+//
+// Operators bind as in Go, except that ! negates a whole comparison, so
+// !a > b is !(a > b), and comparisons do not chain. Column names that are
+// not plain words, such as order id, are written in backquotes. Values are
+// compared as numbers when both sides read as numbers, as timestamps when
+// both read as timestamps, and as text otherwise; numbers are equal within
+// floating point error, so that price * quantity == total holds for
+// 19.99 * 3 and 59.97. A missing value makes whatever it takes part in
+// unknown, as in SQL, except that false && unknown is false and
+// true || unknown is true.
+type expr interface {
+	eval(row []string) (any, error)
+}
+
+// equalTolerance is the relative difference below which numbers are equal.
+const equalTolerance = 1e-9
+
+// parseExpr parses a row expression. Columns are bound to the positions of
+// a header with bind before it is evaluated.
+func parseExpr(source string) (expr, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %s", p.tokens[p.pos].text)
+	}
+	return e, nil
+}
+
+type tokenKind int
+
+const (
+	tokenNumber tokenKind = iota
+	tokenString
+	tokenIdent
+	tokenColumn // a column name in backquotes
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// operators are the operator tokens, two characters first.
+var operators = []string{"||", "&&", "==", "!=", "<=", ">=", "<", ">", "!", "+", "-", "*", "/", "%", "(", ")", ","}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokenNumber, string(runes[start:i])})
+		case r == '\'' || r == '"' || r == '`':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated %c", r)
+			}
+			kind := tokenString
+			if r == '`' {
+				kind = tokenColumn
+			}
+			tokens = append(tokens, token{kind, string(runes[i+1 : end])})
+			i = end + 1
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokenIdent, string(runes[start:i])})
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					op = candidate
+					break
+				}
+			}
+			switch {
+			case op == "" && r == '=':
+				return nil, fmt.Errorf("unexpected = (compare with ==)")
+			case op == "":
+				return nil, fmt.Errorf("unexpected %c", r)
+			}
+			tokens = append(tokens, token{tokenOperator, op})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+// accept consumes the next token when it is one of the operators given.
+func (p *exprParser) accept(ops ...string) (string, bool) {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenOperator {
+		for _, op := range ops {
+			if p.tokens[p.pos].text == op {
+				p.pos++
+				return op, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	for err == nil {
+		if _, ok := p.accept("||"); !ok {
+			return left, nil
+		}
+		var right expr
+		right, err = p.parseAnd()
+		left = &logicExpr{and: false, left: left, right: right}
+	}
+	return nil, err
+}
+
+func (p *exprParser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	for err == nil {
+		if _, ok := p.accept("&&"); !ok {
+			return left, nil
+		}
+		var right expr
+		right, err = p.parseNot()
+		left = &logicExpr{and: true, left: left, right: right}
+	}
+	return nil, err
+}
+
+func (p *exprParser) parseNot() (expr, error) {
+	if _, ok := p.accept("!"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (expr, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("==", "!=", "<=", ">=", "<", ">")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	return &compareExpr{op: op, left: left, right: right}, nil
+}
+
+func (p *exprParser) parseSum() (expr, error) {
+	left, err := p.parseProduct()
+	for err == nil {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+		var right expr
+		right, err = p.parseProduct()
+		left = &arithmeticExpr{op: op, left: left, right: right}
+	}
+	return nil, err
+}
+
+func (p *exprParser) parseProduct() (expr, error) {
+	left, err := p.parseUnary()
+	for err == nil {
+		op, ok := p.accept("*", "/", "%")
+		if !ok {
+			return left, nil
+		}
+		var right expr
+		right, err = p.parseUnary()
+		left = &arithmeticExpr{op: op, left: left, right: right}
+	}
+	return nil, err
+}
+
+func (p *exprParser) parseUnary() (expr, error) {
+	if _, ok := p.accept("-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &arithmeticExpr{op: "-", left: literal{0.0}, right: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (expr, error) {
+	if p.pos == len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+
+	switch t.kind {
+	case tokenNumber:
+		v, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s", t.text)
+		}
+		return literal{v}, nil
+	case tokenString:
+		return literal{t.text}, nil
+	case tokenColumn:
+		return &columnExpr{name: t.text, index: -1}, nil
+	case tokenIdent:
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(t.text)
+		}
+		switch t.text {
+		case "true":
+			return literal{true}, nil
+		case "false":
+			return literal{false}, nil
+		}
+		return &columnExpr{name: t.text, index: -1}, nil
+	}
+
+	if t.text == "(" {
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.accept(")"); !ok {
+			return nil, fmt.Errorf("missing )")
+		}
+		return e, nil
+	}
+	return nil, fmt.Errorf("unexpected %s", t.text)
+}
+
+func (p *exprParser) parseCall(name string) (expr, error) {
+	fn, ok := exprFunctions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s", name)
+	}
+	var args []expr
+	if _, ok := p.accept(")"); !ok {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if _, ok := p.accept(")"); ok {
+				break
+			}
+			if _, ok := p.accept(","); !ok {
+				return nil, fmt.Errorf("missing ) after the arguments of %s", name)
+			}
+		}
+	}
+	if len(args) != 1 {
+		return nil, fmt.Errorf("%s takes one argument, got %d", name, len(args))
+	}
+	return &callExpr{name: name, fn: fn, arg: args[0]}, nil
+}
+
+// exprFunctions are the functions of row expressions, each of one value
+// that is not missing.
+var exprFunctions = map[string]func(v any) (any, error){
+	"abs": func(v any) (any, error) {
+		n, err := asNumber(v)
+		return math.Abs(n), err
+	},
+	"len": func(v any) (any, error) {
+		return float64(len([]rune(asText(v)))), nil
+	},
+	"lower": func(v any) (any, error) {
+		return strings.ToLower(asText(v)), nil
+	},
+	"upper": func(v any) (any, error) {
+		return strings.ToUpper(asText(v)), nil
+	},
+}
+
+type literal struct {
+	value any
+}
+
+func (l literal) eval([]string) (any, error) {
+	return l.value, nil
+}
+
+// columnExpr is the value of a column in the row, nil when it is missing.
+type columnExpr struct {
+	name  string
+	index int
+}
+
+func (c *columnExpr) eval(row []string) (any, error) {
+	if c.index >= len(row) || row[c.index] == "" {
+		return nil, nil
+	}
+	return row[c.index], nil
+}
+
+type callExpr struct {
+	name string
+	fn   func(v any) (any, error)
+	arg  expr
+}
+
+func (c *callExpr) eval(row []string) (any, error) {
+	v, err := c.arg.eval(row)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	result, err := c.fn(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", c.name, err)
+	}
+	return result, nil
+}
+
+type notExpr struct {
+	operand expr
+}
+
+func (n *notExpr) eval(row []string) (any, error) {
+	v, err := n.operand.eval(row)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	b, err := asBool(v)
+	return !b, err
+}
+
+// logicExpr is && or ||, which skips its right side once its left side
+// decides it.
+type logicExpr struct {
+	and         bool
+	left, right expr
+}
+
+func (l *logicExpr) eval(row []string) (any, error) {
+	left, err := l.evalSide(l.left, row)
+	if err != nil {
+		return nil, err
+	}
+	if left != nil && *left != l.and {
+		return *left, nil
+	}
+	right, err := l.evalSide(l.right, row)
+	switch {
+	case err != nil:
+		return nil, err
+	case right != nil && *right != l.and:
+		return *right, nil
+	case left == nil || right == nil:
+		return nil, nil
+	}
+	return l.and, nil
+}
+
+func (l *logicExpr) evalSide(side expr, row []string) (*bool, error) {
+	v, err := side.eval(row)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	b, err := asBool(v)
+	return &b, err
+}
+
+type compareExpr struct {
+	op          string
+	left, right expr
+}
+
+func (c *compareExpr) eval(row []string) (any, error) {
+	left, right, err := evalBoth(c.left, c.right, row)
+	if err != nil || left == nil || right == nil {
+		return nil, err
+	}
+
+	order, err := compareValues(left, right)
+	if err != nil {
+		return nil, err
+	}
+	switch c.op {
+	case "==":
+		return order == 0, nil
+	case "!=":
+		return order != 0, nil
+	case "<":
+		return order < 0, nil
+	case "<=":
+		return order <= 0, nil
+	case ">":
+		return order > 0, nil
+	default:
+		return order >= 0, nil
+	}
+}
+
+// compareValues orders a and b as numbers, timestamps or text, whichever
+// both read as.
+func compareValues(a, b any) (int, error) {
+	if x, ok := readNumber(a); ok {
+		if y, ok := readNumber(b); ok {
+			if x == y || math.Abs(x-y) <= equalTolerance*math.Max(math.Abs(x), math.Abs(y)) {
+				return 0, nil
+			}
+			if x < y {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+
+	if x, ok := a.(bool); ok {
+		if y, ok := b.(bool); ok {
+			return compareBools(x, y), nil
+		}
+	}
+	x, isText := a.(string)
+	y, bothText := b.(string)
+	if !isText || !bothText {
+		return 0, fmt.Errorf("cannot compare %s with %s", quoteValue(a), quoteValue(b))
+	}
+	if tx, ok := profiler.ParseDateTime(x); ok {
+		if ty, ok := profiler.ParseDateTime(y); ok {
+			return tx.Compare(ty), nil
+		}
+	}
+	return strings.Compare(x, y), nil
+}
+
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+type arithmeticExpr struct {
+	op          string
+	left, right expr
+}
+
+func (a *arithmeticExpr) eval(row []string) (any, error) {
+	left, right, err := evalBoth(a.left, a.right, row)
+	if err != nil || left == nil || right == nil {
+		return nil, err
+	}
+	x, err := asNumber(left)
+	if err != nil {
+		return nil, err
+	}
+	y, err := asNumber(right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch a.op {
+	case "+":
+		return x + y, nil
+	case "-":
+		return x - y, nil
+	case "*":
+		return x * y, nil
+	}
+	if y == 0 {
+		return nil, fmt.Errorf("division by zero")
+	}
+	if a.op == "/" {
+		return x / y, nil
+	}
+	return math.Mod(x, y), nil
+}
+
+func evalBoth(left, right expr, row []string) (any, any, error) {
+	l, err := left.eval(row)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := right.eval(row)
+	if err != nil {
+		return nil, nil, err
+	}
+	return l, r, nil
+}
+
+// readNumber reads v as a number, as a float64 or text holding one.
+func readNumber(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil && !math.IsNaN(n)
+	}
+	return 0, false
+}
+
+func asNumber(v any) (float64, error) {
+	if n, ok := readNumber(v); ok {
+		return n, nil
+	}
+	return 0, fmt.Errorf("%s is not a number", quoteValue(v))
+}
+
+func asBool(v any) (bool, error) {
+	if b, ok := v.(bool); ok {
+		return b, nil
+	}
+	return false, fmt.Errorf("%s is not true or false", quoteValue(v))
+}
+
+func asText(v any) string {
+	if n, ok := v.(float64); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+func quoteValue(v any) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return asText(v)
+}
+
+// bind points the columns of e at their positions in header, reporting the
+// first column header lacks.
+func bind(e expr, header []string) error {
+	switch e := e.(type) {
+	case *columnExpr:
+		for i, name := range header {
+			if name == e.name {
+				e.index = i
+				return nil
+			}
+		}
+		return fmt.Errorf("column %s not found", e.name)
+	case *callExpr:
+		return bind(e.arg, header)
+	case *notExpr:
+		return bind(e.operand, header)
+	case *logicExpr:
+		if err := bind(e.left, header); err != nil {
+			return err
+		}
+		return bind(e.right, header)
+	case *compareExpr:
+		if err := bind(e.left, header); err != nil {
+			return err
+		}
+		return bind(e.right, header)
+	case *arithmeticExpr:
+		if err := bind(e.left, header); err != nil {
+			return err
+		}
+		return bind(e.right, header)
+	}
+	return nil
+}
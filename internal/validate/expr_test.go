@@ -0,0 +1,183 @@
+package validate
+
+import (
+	"testing"
+)
+
+func TestParseExpr(t *testing.T) {
+	header := []string{"start", "end", "price", "quantity", "total", "country", "state", "order id"}
+	row := []string{"2024-01-05", "2024-01-20", "19.99", "3", "59.97", "US", "", "A-1"}
+
+	for source, want := range map[string]any{
+		"end >= start":                          true,
+		"start > '2024-01-10'":                  false,
+		"price * quantity == total":             true,
+		"price * quantity != total":             false,
+		"-price + 20 > 0 && quantity % 2 == 1":  true,
+		"(quantity + 1) / 2 == 2":               true,
+		"country == 'US' || state == 'CA'":      true,
+		"country != 'US' && state == 'CA'":      false,
+		"state == 'CA'":                         nil, // missing values are unknown
+		"country == 'US' && state == 'CA'":      nil,
+		"country == 'US' || state == 'CA' && 1": true, // decided before the error on the right
+		"!(country == 'DE')":                    true,
+		"len(`order id`) == 3":                  true,
+		"lower(country) == 'us'":                true,
+		"abs(total - 60) < 0.05":                true,
+		"quantity == '03'":                      true,
+		"country < 'VA'":                        true,
+	} {
+		e, err := parseExpr(source)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", source, err)
+			continue
+		}
+		if err := bind(e, header); err != nil {
+			t.Errorf("%s: unexpected error %v", source, err)
+			continue
+		}
+		if got, err := e.eval(row); err != nil || got != want {
+			t.Errorf("%s: expected %v, got %v (%v)", source, want, got, err)
+		}
+	}
+}
+
+func TestParseExprErrors(t *testing.T) {
+	for _, source := range []string{"a = b", "a >", "(a > b", "a > b c", "'open", "sum(a) > 1", "abs(a, b) > 1", "a # b", "a < b < c", "a == b == true"} {
+		if _, err := parseExpr(source); err == nil {
+			t.Errorf("%s: expected a parse error", source)
+		}
+	}
+
+	header := []string{"a", "b"}
+	for source, message := range map[string]string{
+		"a * 2 > 1":     `"x" is not a number`,
+		"b / 0 > 1":     "division by zero",
+		"a != 'y' && b": `"2" is not true or false`,
+	} {
+		e, _ := parseExpr(source)
+		if err := bind(e, header); err != nil {
+			t.Fatalf("%s: unexpected error %v", source, err)
+		}
+		if _, err := e.eval([]string{"x", "2"}); err == nil || err.Error() != message {
+			t.Errorf("%s: expected %q, got %v", source, message, err)
+		}
+	}
+
+	e, _ := parseExpr("a > c")
+	if err := bind(e, header); err == nil || err.Error() != "column c not found" {
+		t.Errorf("Expected the missing column to be reported, got %v", err)
+	}
+}
+
+func TestExprPrecedence(t *testing.T) {
+	header := []string{"a", "b"}
+	row := []string{"2", "3"}
+
+	for source, want := range map[string]any{
+		"1 + 2 * 3 == 7":              true,
+		"(1 + 2) * 3 == 9":            true,
+		"10 - 4 - 3 == 3":             true,
+		"12 / 3 / 2 == 2":             true,
+		"7 % 4 * 2 == 6":              true,
+		"-a * b == -6":                true,
+		"- -a == 2":                   true,
+		"a - -b == 5":                 true,
+		"a + b * 2 > 7":               true,
+		"a + b * 2 > 8":               false,
+		"true || false && false":      true,
+		"false && true || true":       true,
+		"(true || false) && false":    false,
+		"!false && false":             false,
+		"!(false && false)":           true,
+		"!a > b":                      true, // !(a > b)
+		"!!true":                      true,
+		"a * 2 < b + 2 && b % 2 == 1": true,
+	} {
+		e, err := parseExpr(source)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", source, err)
+			continue
+		}
+		if err := bind(e, header); err != nil {
+			t.Errorf("%s: unexpected error %v", source, err)
+			continue
+		}
+		if got, err := e.eval(row); err != nil || got != want {
+			t.Errorf("%s: expected %v, got %v (%v)", source, want, got, err)
+		}
+	}
+}
+
+func TestExprTypeErrors(t *testing.T) {
+	header := []string{"a", "b", "day"}
+	row := []string{"x", "2", "2024-01-05"}
+
+	for source, message := range map[string]string{
+		"a + 1 > 0":               `"x" is not a number`,
+		"-a < 0":                  `"x" is not a number`,
+		"abs(a) > 1":              `abs: "x" is not a number`,
+		"true + 1 > 0":            "true is not a number",
+		"!b":                      `"2" is not true or false`,
+		"b || true":               `"2" is not true or false`,
+		"1 && true":               "1 is not true or false",
+		"true < 'x'":              `cannot compare true with "x"`,
+		"(b > 1) == 'yes'":        `cannot compare true with "yes"`,
+		"day + 1 > 0":             `"2024-01-05" is not a number`,
+		"b % (b - 2) == 0":        "division by zero",
+		"len(a) / 0 > 1":          "division by zero",
+		"a == 'x' && b == 2 && a": `"x" is not true or false`,
+	} {
+		e, err := parseExpr(source)
+		if err != nil {
+			t.Errorf("%s: unexpected parse error %v", source, err)
+			continue
+		}
+		if err := bind(e, header); err != nil {
+			t.Fatalf("%s: unexpected error %v", source, err)
+		}
+		if _, err := e.eval(row); err == nil || err.Error() != message {
+			t.Errorf("%s: expected %q, got %v", source, message, err)
+		}
+	}
+
+	// A missing value leaves the expression unknown before its type is checked
+	e, _ := parseExpr("a + 1 > 0")
+	bind(e, header)
+	if got, err := e.eval([]string{"", "2", ""}); err != nil || got != nil {
+		t.Errorf("Expected a missing value to be unknown, got %v (%v)", got, err)
+	}
+}
+
+func TestExprUnknownIdentifiers(t *testing.T) {
+	for source, message := range map[string]string{
+		"round(a) > 1": "unknown function round",
+		"Abs(a) > 1":   "unknown function Abs",
+	} {
+		if _, err := parseExpr(source); err == nil || err.Error() != message {
+			t.Errorf("%s: expected %q, got %v", source, message, err)
+		}
+	}
+
+	// Any other name is a column, which must be in the header even where
+	// evaluation would never reach it
+	header := []string{"a", "b", "order id"}
+	for source, message := range map[string]string{
+		"c > 1":               "column c not found",
+		"a == tru":            "column tru not found",
+		"`order_id` == 'A-1'": "column order_id not found",
+		"len(status) > 1":     "column status not found",
+		"true || missing > 1": "column missing not found",
+		"!(a > 1 && B < 2)":   "column B not found",
+		"a + b * (c - 1) > 0": "column c not found",
+	} {
+		e, err := parseExpr(source)
+		if err != nil {
+			t.Errorf("%s: unexpected parse error %v", source, err)
+			continue
+		}
+		if err := bind(e, header); err == nil || err.Error() != message {
+			t.Errorf("%s: expected %q, got %v", source, message, err)
+		}
+	}
+}
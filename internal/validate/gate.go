@@ -0,0 +1,86 @@
+package validate
+
+import (
+	"sort"
+
+	"github.com/kamalm96/datasleuth/internal/compare"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// Gate is a set of thresholds a dataset must meet for a pipeline to go on,
+// checked by profile, validate and compare. A negative threshold, or a
+// MinScore of 0, is not checked.
+type Gate struct {
+	MinScore     int     // quality score, 0-100
+	MaxMissing   float64 // missing values of any column, percent
+	MaxDuplicate float64 // duplicate rows, percent
+	MaxDrift     float64 // columns that drifted, percent
+}
+
+// NoGate checks nothing.
+func NoGate() Gate {
+	return Gate{MaxMissing: -1, MaxDuplicate: -1, MaxDrift: -1}
+}
+
+func (g Gate) Enabled() bool {
+	return g.MinScore > 0 || g.MaxMissing >= 0 || g.MaxDuplicate >= 0 || g.MaxDrift >= 0
+}
+
+// Check holds profile to the score, missing and duplicate thresholds, with
+// one check per column over MaxMissing.
+func (g Gate) Check(profile *profiler.DatasetProfile) []Check {
+	result := &Result{Checks: make([]Check, 0)}
+
+	if g.MinScore > 0 {
+		result.add("quality_score", "", profile.QualityScore >= g.MinScore, "quality score %d/100 (minimum %d)",
+			profile.QualityScore, g.MinScore)
+	}
+
+	if g.MaxMissing >= 0 {
+		names := make([]string, 0, len(profile.Columns))
+		for name := range profile.Columns {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		worst := 0.0
+		for _, name := range names {
+			missing := percent(profile.Columns[name].MissingCount, profile.RowCount)
+			if missing > g.MaxMissing {
+				result.add("max_missing", name, false, "%.1f%% missing (maximum %.1f%%)", missing, g.MaxMissing)
+			}
+			if missing > worst {
+				worst = missing
+			}
+		}
+		if worst <= g.MaxMissing {
+			result.add("max_missing", "", true, "at most %.1f%% missing in any column (maximum %.1f%%)", worst, g.MaxMissing)
+		}
+	}
+
+	if g.MaxDuplicate >= 0 {
+		duplicates := percent(profile.DuplicateRows, profile.RowCount)
+		result.add("max_duplicates", "", duplicates <= g.MaxDuplicate, "%.1f%% duplicate rows (maximum %.1f%%)",
+			duplicates, g.MaxDuplicate)
+	}
+
+	return result.Checks
+}
+
+// CheckDrift holds a comparison to the drift threshold.
+func (g Gate) CheckDrift(diff *compare.Result) []Check {
+	if g.MaxDrift < 0 || diff.SchemaOnly {
+		return nil
+	}
+	result := &Result{Checks: make([]Check, 0)}
+	score := diff.DriftScore()
+	result.add("max_drift", "", score <= g.MaxDrift, "%.1f%% of columns drifted (maximum %.1f%%)", score, g.MaxDrift)
+	return result.Checks
+}
+
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
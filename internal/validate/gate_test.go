@@ -0,0 +1,71 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/compare"
+)
+
+func TestGateCheck(t *testing.T) {
+	profile := createBaseline()
+	profile.QualityScore = 80
+	profile.DuplicateRows = 5
+
+	if NoGate().Enabled() || len(NoGate().Check(profile)) != 0 {
+		t.Error("Expected NoGate to check nothing")
+	}
+
+	gate := NoGate()
+	gate.MinScore = 90
+	gate.MaxMissing = 1
+	gate.MaxDuplicate = 5
+	checks := gate.Check(profile)
+
+	failed := make(map[string]string)
+	for _, check := range checks {
+		if !check.Passed {
+			failed[check.Name] = check.Column
+		}
+	}
+	if len(checks) != 3 || len(failed) != 2 {
+		t.Fatalf("Expected 3 checks with 2 failures, got %+v", checks)
+	}
+	if _, ok := failed["quality_score"]; !ok {
+		t.Error("Expected the quality score to fail")
+	}
+	if failed["max_missing"] != "amount" {
+		t.Errorf("Expected amount to fail max_missing, got %q", failed["max_missing"])
+	}
+
+	gate.MaxMissing = 0
+	profile.Columns["amount"].MissingCount = 0
+	for _, check := range gate.Check(profile) {
+		if check.Name == "max_missing" && !check.Passed {
+			t.Errorf("Expected no missing values to meet a maximum of 0%%: %+v", check)
+		}
+	}
+}
+
+func TestGateCheckDrift(t *testing.T) {
+	baseline := createBaseline()
+	current := createBaseline()
+	current.Columns["region"].TopValues[0].Count = 95
+	current.Columns["region"].TopValues[1].Count = 5
+
+	diff := compare.Compare(baseline, current, compare.DefaultOptions())
+	gate := NoGate()
+	if len(gate.CheckDrift(diff)) != 0 {
+		t.Error("Expected no drift check without a maximum")
+	}
+
+	gate.MaxDrift = 10
+	checks := gate.CheckDrift(diff)
+	if len(checks) != 1 || checks[0].Passed {
+		t.Errorf("Expected the drifted region column to fail, got %+v", checks)
+	}
+
+	gate.MaxDrift = 50
+	if checks := gate.CheckDrift(diff); checks[0].Passed != true {
+		t.Errorf("Expected one drifted column out of two to pass 50%%, got %+v", checks)
+	}
+}
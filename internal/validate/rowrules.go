@@ -0,0 +1,232 @@
+package validate
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxRowExamples is the number of failing rows listed per row rule.
+const maxRowExamples = 5
+
+// RowRule is an expectation of every row, spanning its columns, checked as
+// the rows are read:
+//
+//	row_rules:
+//	  - name: ends_after_start
+//	    expr: end_date >= start_date
+//	  - name: total_adds_up
+//	    expr: price * quantity == total
+//	  - name: us_state
+//	    when: country == 'US'
+//	    required: [state]
+//
+// A row fails when Expr is false, or a Required column is missing. When
+// restricts the rule to the rows it is true for. A row whose Expr is
+// unknown, because a value it takes is missing, passes; Required is what
+// holds columns to being present.
+type RowRule struct {
+	Name     string   `yaml:"name"`
+	Expr     string   `yaml:"expr,omitempty"`
+	When     string   `yaml:"when,omitempty"`
+	Required []string `yaml:"required,omitempty,flow"`
+}
+
+func (r RowRule) validate() error {
+	if r.Expr == "" && len(r.Required) == 0 {
+		return fmt.Errorf("nothing to check, give expr or required")
+	}
+	for _, source := range []string{r.Expr, r.When} {
+		if source == "" {
+			continue
+		}
+		if _, err := parseExpr(source); err != nil {
+			return fmt.Errorf("invalid expression %q: %w", source, err)
+		}
+	}
+	return nil
+}
+
+// String describes what the rule expects, as state present where
+// country == 'US'.
+func (r RowRule) String() string {
+	parts := make([]string, 0, 2)
+	if r.Expr != "" {
+		parts = append(parts, r.Expr)
+	}
+	if len(r.Required) > 0 {
+		parts = append(parts, strings.Join(r.Required, ", ")+" present")
+	}
+	description := strings.Join(parts, " and ")
+	if r.When != "" {
+		description += " where " + r.When
+	}
+	return description
+}
+
+// RowChecker checks the row rules and unique rules of a dataset against
+// each row it is given, keeping the rows that fail them.
+type RowChecker struct {
+	rules  []*rowRuleState
+	unique []*uniqueKeyState
+	rows   int
+}
+
+type rowRuleState struct {
+	RowRule
+	expr, when expr
+	required   []int
+	missing    error // set when the header lacks a column of the rule
+
+	checked, failed int
+	examples        []int // first failing rows, counted from 1
+	errors          int   // rows failed because the rule could not be evaluated
+	firstError      error
+}
+
+// newRowChecker checks the row rules and unique rules of rules, which must
+// be valid.
+func newRowChecker(rules *Rules) *RowChecker {
+	checker := &RowChecker{rules: make([]*rowRuleState, len(rules.RowRules))}
+	for i, rule := range rules.RowRules {
+		state := &rowRuleState{RowRule: rule}
+		if rule.Expr != "" {
+			state.expr, _ = parseExpr(rule.Expr)
+		}
+		if rule.When != "" {
+			state.when, _ = parseExpr(rule.When)
+		}
+		checker.rules[i] = state
+	}
+	for _, rule := range rules.Unique {
+		checker.unique = append(checker.unique, newUniqueKeyState(rule))
+	}
+	return checker
+}
+
+// Add checks record against every rule. It has the signature of
+// profiler.Options.Rows, and never stops profiling: a rule naming a column
+// the header lacks fails rather than being checked.
+func (c *RowChecker) Add(header, record []string) error {
+	if c.rows == 0 {
+		for _, rule := range c.rules {
+			rule.missing = rule.bind(header)
+		}
+		for _, rule := range c.unique {
+			rule.missing = rule.bind(header)
+		}
+	}
+	c.rows++
+
+	for _, rule := range c.rules {
+		if rule.missing == nil {
+			rule.check(c.rows, record)
+		}
+	}
+	for _, rule := range c.unique {
+		if rule.missing == nil {
+			rule.add(record)
+		}
+	}
+	return nil
+}
+
+func (r *rowRuleState) bind(header []string) error {
+	for _, e := range []expr{r.expr, r.when} {
+		if e == nil {
+			continue
+		}
+		if err := bind(e, header); err != nil {
+			return err
+		}
+	}
+	for _, name := range r.Required {
+		index := slices.Index(header, name)
+		if index < 0 {
+			return fmt.Errorf("column %s not found", name)
+		}
+		r.required = append(r.required, index)
+	}
+	return nil
+}
+
+func (r *rowRuleState) check(row int, record []string) {
+	if r.when != nil {
+		applies, err := r.when.eval(record)
+		if err == nil && applies != true {
+			return
+		}
+		if err != nil {
+			r.checked++
+			r.fail(row, err)
+			return
+		}
+	}
+	r.checked++
+
+	for _, index := range r.required {
+		if index >= len(record) || record[index] == "" {
+			r.fail(row, nil)
+			return
+		}
+	}
+	if r.expr == nil {
+		return
+	}
+	passed, err := r.expr.eval(record)
+	if err == nil && passed != nil {
+		_, err = asBool(passed)
+	}
+	if err != nil || passed == false {
+		r.fail(row, err)
+	}
+}
+
+func (r *rowRuleState) fail(row int, err error) {
+	r.failed++
+	if len(r.examples) < maxRowExamples {
+		r.examples = append(r.examples, row)
+	}
+	if err != nil {
+		r.errors++
+		if r.firstError == nil {
+			r.firstError = err
+		}
+	}
+}
+
+// CheckRows adds a check of each rule of checker, once it has been given
+// every row.
+func (r *Result) CheckRows(checker *RowChecker) {
+	for _, rule := range checker.rules {
+		switch {
+		case rule.missing != nil:
+			r.add("row_rule", rule.Name, false, "%v", rule.missing)
+		case rule.failed == 0 && rule.When != "" && rule.checked == 0:
+			r.add("row_rule", rule.Name, true, "no rows where %s", rule.When)
+		case rule.failed == 0:
+			r.add("row_rule", rule.Name, true, "all %d rows pass %s", rule.checked, rule.RowRule)
+		default:
+			examples := make([]string, len(rule.examples))
+			for i, row := range rule.examples {
+				examples[i] = strconv.Itoa(row)
+			}
+			if rule.failed > len(rule.examples) {
+				examples = append(examples, "...")
+			}
+			label := "rows"
+			if len(examples) == 1 {
+				label = "row"
+			}
+			message := fmt.Sprintf("%d of %d rows fail %s (%s %s)", rule.failed, rule.checked, rule.RowRule, label, strings.Join(examples, ", "))
+			if rule.errors > 0 {
+				message += fmt.Sprintf(", %d could not be evaluated: %v", rule.errors, rule.firstError)
+			}
+			r.add("row_rule", rule.Name, false, "%s", message)
+		}
+	}
+	for _, rule := range checker.unique {
+		rule.check(r)
+	}
+}
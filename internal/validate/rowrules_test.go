@@ -0,0 +1,71 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRowChecker(t *testing.T) {
+	content := `row_rules:
+  - name: ends_after_start
+    expr: end_date >= start_date
+  - name: total_adds_up
+    expr: price * quantity == total
+  - name: us_state
+    when: country == 'US'
+    required: [state]
+  - name: eu_vat
+    when: country == 'FR'
+    required: [vat]
+  - name: typo
+    expr: discount < price
+`
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write rules: %v", err)
+	}
+	rules, err := LoadRules(path)
+	if err != nil {
+		t.Fatalf("LoadRules failed: %v", err)
+	}
+
+	header := []string{"start_date", "end_date", "price", "quantity", "total", "country", "state", "vat"}
+	checker := rules.RowChecker()
+	for _, record := range [][]string{
+		{"2024-01-01", "2024-01-05", "19.99", "3", "59.97", "US", "CA", ""},
+		{"2024-02-01", "2024-01-20", "5", "2", "10", "US", "", ""},
+		{"2024-03-01", "2024-03-02", "2.5", "4", "11", "DE", "", ""},
+		{"2024-03-01", "", "abc", "1", "3", "US", "NY", ""},
+	} {
+		if err := checker.Add(header, record); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	result := &Result{}
+	result.CheckRows(checker)
+	want := map[string]struct {
+		passed  bool
+		message string
+	}{
+		"ends_after_start": {false, "1 of 4 rows fail end_date >= start_date (row 2)"},
+		"total_adds_up":    {false, `2 of 4 rows fail price * quantity == total (rows 3, 4), 1 could not be evaluated: "abc" is not a number`},
+		"us_state":         {false, "1 of 3 rows fail state present where country == 'US' (row 2)"},
+		"eu_vat":           {true, "no rows where country == 'FR'"},
+		"typo":             {false, "column discount not found"},
+	}
+	if len(result.Checks) != len(want) {
+		t.Fatalf("Expected a check per row rule, got %+v", result.Checks)
+	}
+	for _, check := range result.Checks {
+		expected := want[check.Column]
+		if check.Name != "row_rule" || check.Passed != expected.passed || check.Message != expected.message {
+			t.Errorf("Expected %s to be %+v, got %+v", check.Column, expected, check)
+		}
+	}
+
+	if (&Rules{}).RowChecker() != nil {
+		t.Error("Expected no row checker without row rules")
+	}
+}
@@ -0,0 +1,365 @@
+package validate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+	"gopkg.in/yaml.v3"
+)
+
+// Rules are the expectations of a dataset, checked by validate --config and
+// scaffolded from a profile by generate-rules:
+//
+//	columns:
+//	  - name: age
+//	    type: integer
+//	    not_null: true
+//	    min: 14
+//	    max: 71
+//	  - name: department
+//	    type: string
+//	    allowed_values: [Engineering, Marketing, Sales]
+//	  - name: score
+//	    distribution:
+//	      reference: normal
+//	      mean: 50
+//	      stddev: 10
+//	row_rules:
+//	  - name: ends_after_start
+//	    expr: end_date >= start_date
+//	unique:
+//	  - columns: [user_id, date]
+//
+// Every column listed must be present; columns not listed are not checked.
+// Row rules span the columns of each row, and unique rules the rows, and
+// both are checked as the rows are read.
+type Rules struct {
+	Columns  []ColumnRule `yaml:"columns"`
+	RowRules []RowRule    `yaml:"row_rules,omitempty"`
+	Unique   []UniqueRule `yaml:"unique,omitempty"`
+}
+
+// ColumnRule holds the expectations of a column. Unset fields are not
+// checked.
+type ColumnRule struct {
+	Name          string        `yaml:"name"`
+	Type          string        `yaml:"type,omitempty"`
+	NotNull       bool          `yaml:"not_null,omitempty"`
+	Min           *float64      `yaml:"min,omitempty"`
+	Max           *float64      `yaml:"max,omitempty"`
+	AllowedValues []string      `yaml:"allowed_values,omitempty,flow"`
+	Distribution  *Distribution `yaml:"distribution,omitempty"`
+}
+
+// ruleTypes are the data types a rule can expect, as the profiler names
+// them.
+var ruleTypes = []string{"integer", "float", "datetime", "string", "blob"}
+
+// RuleOptions tune the rules generated from a profile.
+type RuleOptions struct {
+	Margin    float64 // ranges are widened by this fraction of the observed range on each side
+	MaxValues int     // categorical columns with at most this many distinct values get allowed values
+}
+
+func DefaultRuleOptions() RuleOptions {
+	return RuleOptions{Margin: 0.1, MaxValues: 20}
+}
+
+// GenerateRules scaffolds rules from profile: the observed type of each
+// column, not null for columns without missing values, the observed range
+// of numeric columns widened by the margin, and the values of categorical
+// string columns when the profile lists all of them. The rules describe
+// the profiled data and are meant to be reviewed.
+func GenerateRules(profile *profiler.DatasetProfile, opts RuleOptions) *Rules {
+	names := make([]string, 0, len(profile.Columns))
+	for name := range profile.Columns {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := profile.Columns[names[i]], profile.Columns[names[j]]
+		if a.Position != b.Position {
+			return a.Position < b.Position
+		}
+		return names[i] < names[j]
+	})
+
+	rules := &Rules{Columns: make([]ColumnRule, 0, len(names))}
+	for _, name := range names {
+		col := profile.Columns[name]
+		rule := ColumnRule{Name: name, NotNull: profile.RowCount > 0 && col.MissingCount == 0}
+		if col.DataType != "unknown" {
+			rule.Type = col.DataType
+		}
+
+		if low, high, ok := numericRange(col); ok {
+			margin := (high - low) * opts.Margin
+			if margin == 0 {
+				margin = math.Abs(high) * opts.Margin
+			}
+			low, high = low-margin, high+margin
+			if col.DataType == "integer" {
+				low, high = math.Floor(low), math.Ceil(high)
+			}
+			rule.Min, rule.Max = &low, &high
+		}
+
+		if values, ok := allValues(col); ok && col.DataType == "string" && col.IsCategorical && len(values) <= opts.MaxValues {
+			sort.Strings(values)
+			rule.AllowedValues = values
+		}
+
+		rules.Columns = append(rules.Columns, rule)
+	}
+	return rules
+}
+
+// numericRange returns the smallest and largest value of a numeric column.
+func numericRange(col *profiler.ColumnProfile) (float64, float64, bool) {
+	if !col.IsNumeric {
+		return 0, 0, false
+	}
+	low, okLow := col.Min.(float64)
+	high, okHigh := col.Max.(float64)
+	return low, high, okLow && okHigh
+}
+
+// allValues returns the distinct values of col when its top values list
+// all of them.
+func allValues(col *profiler.ColumnProfile) ([]string, bool) {
+	if col.Count == 0 || len(col.TopValues) != col.UniqueCount {
+		return nil, false
+	}
+	covered := 0
+	values := make([]string, 0, len(col.TopValues))
+	for _, v := range col.TopValues {
+		covered += v.Count
+		values = append(values, v.Value)
+	}
+	return values, covered == col.Count
+}
+
+// MaxAllowedValues is the size of the largest allowed value set. A profile
+// checked against the rules should list one more top value than this, so
+// that a column holding further values shows them.
+func (r *Rules) MaxAllowedValues() int {
+	largest := 0
+	for _, rule := range r.Columns {
+		if len(rule.AllowedValues) > largest {
+			largest = len(rule.AllowedValues)
+		}
+	}
+	return largest
+}
+
+// Validate reports a rule without a column name, a column listed twice, an
+// unknown type, a minimum above the maximum, an invalid distribution, a row
+// rule without a name, listed twice or with an invalid expression, or a
+// unique rule without columns.
+func (r *Rules) Validate() error {
+	seen := make(map[string]bool, len(r.Columns))
+	for _, rule := range r.Columns {
+		if rule.Name == "" {
+			return fmt.Errorf("rule without a column name")
+		}
+		if seen[rule.Name] {
+			return fmt.Errorf("column %s has more than one rule", rule.Name)
+		}
+		seen[rule.Name] = true
+
+		if rule.Type != "" && !containsValue(ruleTypes, rule.Type) {
+			return fmt.Errorf("rule of %s: unknown type %s (want %s)", rule.Name, rule.Type, strings.Join(ruleTypes, ", "))
+		}
+		if rule.Min != nil && rule.Max != nil && *rule.Min > *rule.Max {
+			return fmt.Errorf("rule of %s: min %g is above max %g", rule.Name, *rule.Min, *rule.Max)
+		}
+		if rule.Distribution != nil {
+			if err := rule.Distribution.validate(); err != nil {
+				return fmt.Errorf("rule of %s: %w", rule.Name, err)
+			}
+		}
+	}
+
+	names := make(map[string]bool, len(r.RowRules))
+	for _, rule := range r.RowRules {
+		if rule.Name == "" {
+			return fmt.Errorf("row rule without a name")
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("row rule %s is listed more than once", rule.Name)
+		}
+		names[rule.Name] = true
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("row rule %s: %w", rule.Name, err)
+		}
+	}
+
+	for _, rule := range r.Unique {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RowChecker checks the row rules and unique rules as the rows are read, nil
+// when there are none.
+func (r *Rules) RowChecker() *RowChecker {
+	if len(r.RowRules) == 0 && len(r.Unique) == 0 {
+		return nil
+	}
+	return newRowChecker(r)
+}
+
+// ApplyTo sets the options a profile checked against the rules needs, and
+// returns the checker that reads the rows for the row and unique rules, nil
+// when there are none.
+func (r *Rules) ApplyTo(opts *profiler.Options) *RowChecker {
+	// List enough values to tell whether a column holds others than allowed
+	if n := r.MaxAllowedValues(); n > 0 {
+		opts.TopValues = n + 1
+	}
+	rows := r.RowChecker()
+	if rows != nil {
+		opts.Rows = rows.Add
+	}
+	return rows
+}
+
+// LoadRules reads a rules file. Unknown keys are rejected so that a
+// misspelled expectation is not silently ignored.
+func LoadRules(path string) (*Rules, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+
+	var rules Rules
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&rules); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse rules %s: %w", path, err)
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rules %s: %w", path, err)
+	}
+	for _, rule := range rules.Columns {
+		if d := rule.Distribution; d != nil && d.Reference == referenceHistogram {
+			if err := d.loadHistogram(path); err != nil {
+				return nil, fmt.Errorf("invalid rules %s: rule of %s: %w", path, rule.Name, err)
+			}
+		}
+	}
+	return &rules, nil
+}
+
+// Marshal writes the rules as YAML.
+func (r *Rules) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(r); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// AgainstRules checks profile against rules, read from path.
+func AgainstRules(profile *profiler.DatasetProfile, rules *Rules, path string) *Result {
+	result := &Result{
+		Source: profile.Filename,
+		Checks: make([]Check, 0),
+	}
+	result.CheckRules(profile, rules, path)
+	return result
+}
+
+// CheckRules adds the checks of rules, read from path, against profile, to
+// validate a dataset against a baseline and rules at once.
+func (r *Result) CheckRules(profile *profiler.DatasetProfile, rules *Rules, path string) {
+	r.Rules = path
+	for _, rule := range rules.Columns {
+		col, ok := profile.Columns[rule.Name]
+		if !ok {
+			r.add("schema", rule.Name, false, "column missing")
+			continue
+		}
+
+		if rule.Type != "" {
+			// Integers are valid floats
+			passed := col.DataType == rule.Type || (rule.Type == "float" && col.DataType == "integer")
+			r.add("type", rule.Name, passed, "type %s, expected %s", col.DataType, rule.Type)
+		}
+
+		if rule.NotNull {
+			r.add("not_null", rule.Name, col.MissingCount == 0, "%d missing values", col.MissingCount)
+		}
+
+		if rule.Min != nil || rule.Max != nil {
+			checkRange(r, rule, col)
+		}
+
+		if len(rule.AllowedValues) > 0 {
+			checkAllowedValues(r, rule, col)
+		}
+
+		if rule.Distribution != nil {
+			checkDistribution(r, rule, col)
+		}
+	}
+}
+
+func checkRange(r *Result, rule ColumnRule, col *profiler.ColumnProfile) {
+	low, high, ok := numericRange(col)
+	if !ok {
+		if col.Count > 0 {
+			r.add("range", rule.Name, false, "no numeric range to check (type %s)", col.DataType)
+		}
+		return
+	}
+
+	expected := fmt.Sprintf("%s to %s", formatBound(rule.Min, "-∞"), formatBound(rule.Max, "∞"))
+	passed := (rule.Min == nil || low >= *rule.Min) && (rule.Max == nil || high <= *rule.Max)
+	r.add("range", rule.Name, passed, "values from %g to %g, expected %s", low, high, expected)
+}
+
+func formatBound(bound *float64, unbounded string) string {
+	if bound == nil {
+		return unbounded
+	}
+	return fmt.Sprintf("%g", *bound)
+}
+
+// checkAllowedValues fails a column with more distinct values than allowed,
+// or with a listed value that is not allowed. The profile must list one
+// more top value than there are allowed values for the check to be
+// complete.
+func checkAllowedValues(r *Result, rule ColumnRule, col *profiler.ColumnProfile) {
+	var unexpected []string
+	for _, v := range col.TopValues {
+		if !containsValue(rule.AllowedValues, v.Value) {
+			unexpected = append(unexpected, fmt.Sprintf("%q (%d)", v.Value, v.Count))
+		}
+	}
+
+	switch {
+	case len(unexpected) > 0:
+		if len(unexpected) > 5 {
+			unexpected = append(unexpected[:5], "...")
+		}
+		r.add("allowed_values", rule.Name, false, "unexpected values %s", strings.Join(unexpected, ", "))
+	case col.UniqueCount > len(rule.AllowedValues):
+		r.add("allowed_values", rule.Name, false, "%d distinct values, %d allowed", col.UniqueCount, len(rule.AllowedValues))
+	default:
+		r.add("allowed_values", rule.Name, true, "%d distinct values, all allowed", col.UniqueCount)
+	}
+}
@@ -0,0 +1,164 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func createRulesProfile() *profiler.DatasetProfile {
+	return &profiler.DatasetProfile{
+		Filename: "orders.csv",
+		RowCount: 100,
+		Columns: map[string]*profiler.ColumnProfile{
+			"id":     {Name: "id", Position: 0, DataType: "integer", IsNumeric: true, Count: 100, UniqueCount: 100, Min: float64(1), Max: float64(100)},
+			"amount": {Name: "amount", Position: 1, DataType: "float", IsNumeric: true, Count: 95, MissingCount: 5, Min: 2.5, Max: 12.5},
+			"status": {
+				Name: "status", Position: 2, DataType: "string", Count: 100, UniqueCount: 2, IsCategorical: true,
+				TopValues: []profiler.ValueCount{{Value: "paid", Count: 70}, {Value: "open", Count: 30}},
+			},
+			"note": {
+				Name: "note", Position: 3, DataType: "string", Count: 100, UniqueCount: 90,
+				TopValues: []profiler.ValueCount{{Value: "n/a", Count: 5}},
+			},
+			"empty": {Name: "empty", Position: 4, DataType: "unknown", MissingCount: 100},
+		},
+	}
+}
+
+func TestGenerateRules(t *testing.T) {
+	rules := GenerateRules(createRulesProfile(), DefaultRuleOptions())
+
+	if len(rules.Columns) != 5 {
+		t.Fatalf("Expected a rule per column, got %+v", rules.Columns)
+	}
+	id, amount, status, note, empty := rules.Columns[0], rules.Columns[1], rules.Columns[2], rules.Columns[3], rules.Columns[4]
+
+	// 10% of the range of 99 on each side, rounded outwards for integers
+	if id.Name != "id" || id.Type != "integer" || !id.NotNull || *id.Min != -9 || *id.Max != 110 {
+		t.Errorf("Unexpected rule for id: %+v", id)
+	}
+	if amount.NotNull || *amount.Min != 1.5 || *amount.Max != 13.5 {
+		t.Errorf("Unexpected rule for amount: %+v", amount)
+	}
+	if strings.Join(status.AllowedValues, ",") != "open,paid" || status.Min != nil {
+		t.Errorf("Expected the statuses allowed in order, got %+v", status)
+	}
+	if note.AllowedValues != nil || empty.Type != "" || empty.NotNull {
+		t.Errorf("Expected no allowed values for notes and nothing for the empty column, got %+v and %+v", note, empty)
+	}
+
+	content, err := rules.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	for _, expected := range []string{"  - name: id\n    type: integer\n    not_null: true\n    min: -9\n    max: 110\n", "    allowed_values: [open, paid]\n"} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected the YAML to contain %q, got:\n%s", expected, content)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatalf("Failed to write rules: %v", err)
+	}
+	loaded, err := LoadRules(path)
+	if err != nil {
+		t.Fatalf("LoadRules failed: %v", err)
+	}
+
+	// The rules hold for the profile they came from
+	if result := AgainstRules(createRulesProfile(), loaded, path); !result.Passed() || result.Rules != path {
+		t.Errorf("Expected the profile to meet its own rules, got %+v", result.Failures())
+	}
+}
+
+func TestAgainstRules(t *testing.T) {
+	minAmount, maxAmount := 0.0, 10.0
+	rules := &Rules{Columns: []ColumnRule{
+		{Name: "id", Type: "float", NotNull: true},
+		{Name: "amount", NotNull: true, Min: &minAmount, Max: &maxAmount},
+		{Name: "status", AllowedValues: []string{"paid"}},
+		{Name: "note", Type: "datetime"},
+		{Name: "customer"},
+	}}
+
+	result := AgainstRules(createRulesProfile(), rules, "rules.yaml")
+
+	failures := make(map[string]string)
+	for _, check := range result.Failures() {
+		failures[check.Name+" "+check.Column] = check.Message
+	}
+	want := map[string]string{
+		"not_null amount":       "5 missing values",
+		"range amount":          "values from 2.5 to 12.5, expected 0 to 10",
+		"allowed_values status": `unexpected values "open" (30)`,
+		"type note":             "type string, expected datetime",
+		"schema customer":       "column missing",
+	}
+	if len(failures) != len(want) {
+		t.Errorf("Expected %d failures, got %v", len(want), failures)
+	}
+	for key, message := range want {
+		if failures[key] != message {
+			t.Errorf("Expected %s to fail with %q, got %q", key, message, failures[key])
+		}
+	}
+
+	// More distinct values than allowed fail even when the listed ones are allowed
+	profile := createRulesProfile()
+	profile.Columns["status"].UniqueCount = 3
+	result = AgainstRules(profile, &Rules{Columns: []ColumnRule{{Name: "status", AllowedValues: []string{"open", "paid"}}}}, "rules.yaml")
+	if result.Passed() || result.Checks[0].Message != "3 distinct values, 2 allowed" {
+		t.Errorf("Expected too many distinct values to fail, got %+v", result.Checks)
+	}
+}
+
+func TestLoadRulesInvalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"unknown key":  "columns:\n  - name: id\n    nullable: false\n",
+		"no name":      "columns:\n  - type: integer\n",
+		"duplicate":    "columns:\n  - name: id\n  - name: id\n",
+		"unknown type": "columns:\n  - name: id\n    type: number\n",
+		"bad range":    "columns:\n  - name: id\n    min: 5\n    max: 1\n",
+		"bad row rule": "row_rules:\n  - name: total\n    expr: price * quantity = total\n",
+		"empty rule":   "row_rules:\n  - name: total\n",
+		"unnamed rule": "row_rules:\n  - expr: a > b\n",
+		"empty unique": "unique:\n  - columns: []\n",
+	} {
+		path := filepath.Join(t.TempDir(), "rules.yaml")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write rules: %v", err)
+		}
+		if _, err := LoadRules(path); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestRulesApplyTo(t *testing.T) {
+	rules := &Rules{
+		Columns: []ColumnRule{
+			{Name: "status", AllowedValues: []string{"new", "paid", "shipped"}},
+			{Name: "region", AllowedValues: []string{"east", "west"}},
+		},
+		RowRules: []RowRule{{Name: "positive", Expr: "amount > 0"}},
+	}
+
+	var opts profiler.Options
+	rows := rules.ApplyTo(&opts)
+	if opts.TopValues != 4 {
+		t.Errorf("Expected one top value more than the largest allowed set, got %d", opts.TopValues)
+	}
+	if rows == nil || opts.Rows == nil {
+		t.Error("Expected the row checker to read the rows")
+	}
+
+	opts = profiler.Options{}
+	if rows := (&Rules{}).ApplyTo(&opts); rows != nil || opts.TopValues != 0 || opts.Rows != nil {
+		t.Errorf("Expected no options without rules, got %+v", opts)
+	}
+}
@@ -0,0 +1,81 @@
+package validate
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// SLA is a completeness service level of a column: at least Completeness
+// percent of its values must be present. Dataset is a file name pattern such
+// as orders_*.csv, or app.db#users for a table or sheet; an empty Dataset
+// applies the SLA to every dataset with the column.
+type SLA struct {
+	Dataset      string  `yaml:"dataset,omitempty"`
+	Column       string  `yaml:"column"`
+	Completeness float64 `yaml:"completeness"`
+}
+
+// SLAResult is the attainment of an SLA by one profile. A column the
+// profile lacks is 0% complete.
+type SLAResult struct {
+	SLA
+	Actual  float64
+	Missing bool
+	Met     bool
+}
+
+// Validate reports an SLA without a column, with a completeness outside
+// 0-100 or with a malformed dataset pattern.
+func (s SLA) Validate() error {
+	if s.Column == "" {
+		return fmt.Errorf("SLA without a column")
+	}
+	if s.Completeness < 0 || s.Completeness > 100 {
+		return fmt.Errorf("SLA of %s: completeness %g is not a percentage from 0 to 100", s.Column, s.Completeness)
+	}
+	if _, err := filepath.Match(s.Dataset, ""); err != nil {
+		return fmt.Errorf("SLA of %s: invalid dataset pattern %s: %w", s.Column, s.Dataset, err)
+	}
+	return nil
+}
+
+// Applies reports whether the SLA covers profile: any dataset with the
+// column when the SLA names no dataset, otherwise the datasets whose file
+// name, or file name#table, matches.
+func (s SLA) Applies(profile *profiler.DatasetProfile) bool {
+	if s.Dataset == "" {
+		_, ok := profile.Columns[s.Column]
+		return ok
+	}
+	if ok, _ := filepath.Match(s.Dataset, profile.Filename); ok {
+		return true
+	}
+	if profile.Table != "" {
+		ok, _ := filepath.Match(s.Dataset, profile.Filename+"#"+profile.Table)
+		return ok
+	}
+	return false
+}
+
+// EvaluateSLAs measures profile against the SLAs that apply to it, in the
+// order they are given.
+func EvaluateSLAs(slas []SLA, profile *profiler.DatasetProfile) []SLAResult {
+	results := make([]SLAResult, 0)
+	for _, sla := range slas {
+		if !sla.Applies(profile) {
+			continue
+		}
+
+		result := SLAResult{SLA: sla}
+		if col, ok := profile.Columns[sla.Column]; ok {
+			result.Actual = 100 - percent(col.MissingCount, profile.RowCount)
+		} else {
+			result.Missing = true
+		}
+		result.Met = !result.Missing && result.Actual >= sla.Completeness
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,52 @@
+package validate
+
+import (
+	"testing"
+)
+
+func TestEvaluateSLAs(t *testing.T) {
+	profile := createBaseline()
+
+	slas := []SLA{
+		{Column: "amount", Completeness: 99},
+		{Column: "region", Completeness: 100},
+		{Column: "email", Completeness: 95},
+		{Dataset: "baseline.*", Column: "email", Completeness: 95},
+		{Dataset: "orders.csv", Column: "amount", Completeness: 50},
+	}
+	results := EvaluateSLAs(slas, profile)
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 SLAs to apply, got %+v", results)
+	}
+
+	if results[0].Met || results[0].Actual != 98 {
+		t.Errorf("Expected amount to breach at 98%%, got %+v", results[0])
+	}
+	if !results[1].Met || results[1].Actual != 100 {
+		t.Errorf("Expected region to meet its SLA, got %+v", results[1])
+	}
+	if results[2].Met || !results[2].Missing {
+		t.Errorf("Expected the missing email column to breach, got %+v", results[2])
+	}
+
+	profile.Filename = "app.db"
+	profile.Table = "payments"
+	if !(SLA{Dataset: "app.db#pay*", Column: "amount"}).Applies(profile) {
+		t.Error("Expected a table pattern to apply")
+	}
+}
+
+func TestSLAValidate(t *testing.T) {
+	for _, sla := range []SLA{
+		{Completeness: 90},
+		{Column: "email", Completeness: 101},
+		{Dataset: "[", Column: "email", Completeness: 90},
+	} {
+		if err := sla.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", sla)
+		}
+	}
+	if err := (SLA{Column: "email", Completeness: 98}).Validate(); err != nil {
+		t.Errorf("Expected a valid SLA, got %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package validate
+
+import (
+	"fmt"
+	"hash/maphash"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// maxUniqueOffenders is the number of repeated keys listed per unique rule.
+const maxUniqueOffenders = 5
+
+// UniqueRule expects no two rows to share the values of its columns, such
+// as one row per user and date:
+//
+//	unique:
+//	  - columns: [user_id, date]
+//
+// Rows with a missing value in the columns are not checked, as NULLs do not
+// break a SQL UNIQUE constraint.
+type UniqueRule struct {
+	Columns []string `yaml:"columns,flow"`
+}
+
+func (u UniqueRule) validate() error {
+	if len(u.Columns) == 0 {
+		return fmt.Errorf("unique rule without columns")
+	}
+	for i, name := range u.Columns {
+		if slices.Contains(u.Columns[:i], name) {
+			return fmt.Errorf("unique rule of (%s): column %s is listed twice", u, name)
+		}
+	}
+	return nil
+}
+
+func (u UniqueRule) String() string {
+	return strings.Join(u.Columns, ", ")
+}
+
+// uniqueKeyState counts the rows of each key of a unique rule by a hash of
+// its values, keeping the values of the keys seen more than once.
+type uniqueKeyState struct {
+	UniqueRule
+	indexes []int
+	missing error // set when the header lacks a column of the rule
+
+	seed     maphash.Seed
+	counts   map[uint64]int
+	repeated map[uint64][]string // values of the keys of more than one row
+	skipped  int                 // rows with a missing key value
+	checked  int
+}
+
+func newUniqueKeyState(rule UniqueRule) *uniqueKeyState {
+	return &uniqueKeyState{
+		UniqueRule: rule,
+		seed:       maphash.MakeSeed(),
+		counts:     make(map[uint64]int),
+		repeated:   make(map[uint64][]string),
+	}
+}
+
+func (u *uniqueKeyState) bind(header []string) error {
+	for _, name := range u.Columns {
+		index := slices.Index(header, name)
+		if index < 0 {
+			return fmt.Errorf("column %s not found", name)
+		}
+		u.indexes = append(u.indexes, index)
+	}
+	return nil
+}
+
+func (u *uniqueKeyState) add(record []string) {
+	var h maphash.Hash
+	h.SetSeed(u.seed)
+	for _, index := range u.indexes {
+		if index >= len(record) || record[index] == "" {
+			u.skipped++
+			return
+		}
+		h.WriteString(record[index])
+		h.WriteByte(0)
+	}
+	u.checked++
+
+	key := h.Sum64()
+	u.counts[key]++
+	if u.counts[key] == 2 {
+		values := make([]string, len(u.indexes))
+		for i, index := range u.indexes {
+			values[i] = record[index]
+		}
+		u.repeated[key] = values
+	}
+}
+
+// duplicateKey is a key of a unique rule shared by several rows.
+type duplicateKey struct {
+	values []string
+	rows   int
+}
+
+// offenders returns the repeated keys, most rows first, and the rows that
+// share a key with another.
+func (u *uniqueKeyState) offenders() ([]duplicateKey, int) {
+	keys := make([]duplicateKey, 0, len(u.repeated))
+	rows := 0
+	for hash, values := range u.repeated {
+		keys = append(keys, duplicateKey{values: values, rows: u.counts[hash]})
+		rows += u.counts[hash]
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].rows != keys[j].rows {
+			return keys[i].rows > keys[j].rows
+		}
+		return strings.Join(keys[i].values, "\x00") < strings.Join(keys[j].values, "\x00")
+	})
+	return keys, rows
+}
+
+// check adds the result of the rule to r.
+func (u *uniqueKeyState) check(r *Result) {
+	name := u.String()
+	if u.missing != nil {
+		r.add("unique", name, false, "%v", u.missing)
+		return
+	}
+
+	var skipped string
+	if u.skipped > 0 {
+		skipped = fmt.Sprintf(" (%d rows with a missing value not checked)", u.skipped)
+	}
+	keys, rows := u.offenders()
+	if len(keys) == 0 {
+		r.add("unique", name, true, "all %d rows unique%s", u.checked, skipped)
+		return
+	}
+
+	worst := make([]string, 0, maxUniqueOffenders+1)
+	for i, key := range keys {
+		if i == maxUniqueOffenders {
+			worst = append(worst, "...")
+			break
+		}
+		worst = append(worst, fmt.Sprintf("(%s) × %d", strings.Join(key.values, ", "), key.rows))
+	}
+	r.add("unique", name, false, "%d keys repeated by %d of %d rows%s, most: %s",
+		len(keys), rows, u.checked, skipped, strings.Join(worst, ", "))
+}
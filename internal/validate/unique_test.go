@@ -0,0 +1,58 @@
+package validate
+
+import (
+	"testing"
+)
+
+func TestUniqueRules(t *testing.T) {
+	rules := &Rules{Unique: []UniqueRule{
+		{Columns: []string{"user_id", "date"}},
+		{Columns: []string{"user_id"}},
+		{Columns: []string{"event_id"}},
+		{Columns: []string{"session"}},
+	}}
+	if err := rules.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	header := []string{"user_id", "date", "event_id"}
+	checker := rules.RowChecker()
+	for _, record := range [][]string{
+		{"1", "2024-01-01", "a"},
+		{"1", "2024-01-01", "b"},
+		{"1", "2024-01-02", "c"},
+		{"2", "2024-01-01", "d"},
+		{"2", "2024-01-01", "e"},
+		{"1", "2024-01-01", "f"},
+		{"3", "", "g"},
+	} {
+		checker.Add(header, record)
+	}
+
+	result := &Result{}
+	result.CheckRows(checker)
+	want := map[string]struct {
+		passed  bool
+		message string
+	}{
+		"user_id, date": {false, "2 keys repeated by 5 of 6 rows (1 rows with a missing value not checked), most: (1, 2024-01-01) × 3, (2, 2024-01-01) × 2"},
+		"user_id":       {false, "2 keys repeated by 6 of 7 rows, most: (1) × 4, (2) × 2"},
+		"event_id":      {true, "all 7 rows unique"},
+		"session":       {false, "column session not found"},
+	}
+	if len(result.Checks) != len(want) {
+		t.Fatalf("Expected a check per unique rule, got %+v", result.Checks)
+	}
+	for _, check := range result.Checks {
+		expected := want[check.Column]
+		if check.Name != "unique" || check.Passed != expected.passed || check.Message != expected.message {
+			t.Errorf("Expected %s to be %+v, got %+v", check.Column, expected, check)
+		}
+	}
+
+	for _, invalid := range []UniqueRule{{}, {Columns: []string{"a", "a"}}} {
+		if err := (&Rules{Unique: []UniqueRule{invalid}}).Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", invalid)
+		}
+	}
+}
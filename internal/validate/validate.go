@@ -0,0 +1,187 @@
+package validate
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/kamalm96/datasleuth/internal/compare"
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+// Tolerances bound how far a dataset may move away from its baseline before
+// validation fails. A zero RowCount disables the row count check.
+type Tolerances struct {
+	MissingRate  float64 // percentage points
+	MeanShift    float64 // baseline standard deviations
+	StdDevChange float64 // relative change
+	Drift        float64 // total variation distance (0-1)
+	RowCount     float64 // relative change
+}
+
+func DefaultTolerances() Tolerances {
+	return Tolerances{
+		MissingRate:  5,
+		MeanShift:    0.5,
+		StdDevChange: 0.25,
+		Drift:        0.1,
+	}
+}
+
+type Check struct {
+	Name    string
+	Column  string
+	Passed  bool
+	Message string
+}
+
+type Result struct {
+	Source   string
+	Baseline string // empty when validated against rules alone
+	Rules    string // rules file, empty when validated against a baseline alone
+	Checks   []Check
+}
+
+func (r *Result) Passed() bool {
+	return len(r.Failures()) == 0
+}
+
+func (r *Result) Failures() []Check {
+	failures := make([]Check, 0)
+	for _, check := range r.Checks {
+		if !check.Passed {
+			failures = append(failures, check)
+		}
+	}
+	return failures
+}
+
+func (r *Result) add(name, column string, passed bool, format string, args ...interface{}) {
+	r.Checks = append(r.Checks, Check{
+		Name:    name,
+		Column:  column,
+		Passed:  passed,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func AgainstBaseline(profile, baseline *profiler.DatasetProfile, tol Tolerances) *Result {
+	result := &Result{
+		Source:   profile.Filename,
+		Baseline: baseline.Filename,
+		Checks:   make([]Check, 0),
+	}
+
+	diff := compare.Compare(baseline, profile, compare.DefaultOptions())
+
+	for _, col := range diff.RemovedColumns {
+		if rename, ok := diff.RenameOf(col.Name); ok {
+			result.add("schema", col.Name, false, "column missing (baseline type %s), probably renamed to %s", col.DataType, rename.NewName)
+			continue
+		}
+		result.add("schema", col.Name, false, "column missing (baseline type %s)", col.DataType)
+	}
+	for _, col := range diff.AddedColumns {
+		result.add("schema", col.Name, false, "unexpected column of type %s", col.DataType)
+	}
+	for _, change := range diff.RetypedColumns {
+		result.add("schema", change.Name, false, "type changed from %s to %s", change.OldType, change.NewType)
+	}
+	if !diff.SchemaChanged() {
+		result.add("schema", "", true, "%d columns match the baseline", len(baseline.Columns))
+	}
+
+	if tol.RowCount > 0 {
+		change := relativeChange(float64(baseline.RowCount), float64(profile.RowCount))
+		result.add("row_count", "", change <= tol.RowCount, "%d rows vs %d in baseline (%.1f%% change, tolerance %.1f%%)",
+			profile.RowCount, baseline.RowCount, change*100, tol.RowCount*100)
+	}
+
+	for _, col := range diff.Columns {
+		missingDelta := col.NewMissingPercent - col.OldMissingPercent
+		result.add("missing_rate", col.Name, math.Abs(missingDelta) <= tol.MissingRate,
+			"%.1f%% missing vs %.1f%% in baseline (tolerance %.1f pp)",
+			col.NewMissingPercent, col.OldMissingPercent, tol.MissingRate)
+
+		checkNullability(result, col.Name, baseline.Columns[col.Name], profile.Columns[col.Name])
+
+		if col.IsNumeric {
+			meanPassed := math.Abs(col.MeanShift) <= tol.MeanShift
+			if col.OldStdDev == 0 {
+				meanPassed = col.NewMean == col.OldMean
+			}
+			result.add("mean", col.Name, meanPassed, "mean %.4g vs %.4g in baseline (%+.2f std devs, tolerance %.2f)",
+				col.NewMean, col.OldMean, col.MeanShift, tol.MeanShift)
+
+			stdDevChange := relativeChange(col.OldStdDev, col.NewStdDev)
+			result.add("stddev", col.Name, stdDevChange <= tol.StdDevChange,
+				"std dev %.4g vs %.4g in baseline (tolerance %.0f%%)",
+				col.NewStdDev, col.OldStdDev, tol.StdDevChange*100)
+		}
+
+		result.add("drift", col.Name, col.Drift <= tol.Drift, "distribution drift %.3f (tolerance %.3f)",
+			col.Drift, tol.Drift)
+	}
+
+	for _, alert := range diff.Cardinality {
+		result.add("cardinality", alert.Column, false, "%d distinct values vs %d in baseline", alert.NewUnique, alert.OldUnique)
+	}
+
+	return result
+}
+
+// checkNullability holds the data to the null contract inferred for the
+// baseline, when it was inferred with medium or high confidence: a NOT NULL
+// column stays free of nulls, and a conditionally null column is null only
+// for the baseline's values of its condition column.
+func checkNullability(result *Result, name string, baseCol, col *profiler.ColumnProfile) {
+	contract := baseCol.Nullability
+	if contract == nil || contract.Confidence == profiler.ConfidenceLow {
+		return
+	}
+
+	switch contract.Kind {
+	case profiler.NullabilityNotNull:
+		result.add("nullability", name, col.MissingCount == 0, "%d missing values, baseline is %s", col.MissingCount, contract)
+	case profiler.NullabilityConditional:
+		if col.MissingCount == 0 {
+			result.add("nullability", name, true, "no missing values, baseline is %s", contract)
+			return
+		}
+		// Nulls no longer tied to the condition column break the contract
+		current := col.Nullability
+		if current == nil {
+			result.add("nullability", name, false, "%d missing values with no null contract, baseline is %s", col.MissingCount, contract)
+			return
+		}
+		if current.Kind != profiler.NullabilityConditional || current.Condition.Column != contract.Condition.Column {
+			result.add("nullability", name, false, "%d missing values, now %s, baseline is %s", col.MissingCount, current, contract)
+			return
+		}
+		passed := true
+		for _, value := range current.Condition.Values {
+			if !containsValue(contract.Condition.Values, value) {
+				passed = false
+			}
+		}
+		result.add("nullability", name, passed, "null only when %s, baseline is %s", current.Condition, contract)
+	}
+}
+
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func relativeChange(old, new float64) float64 {
+	if old == 0 {
+		if new == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return math.Abs(new-old) / math.Abs(old)
+}
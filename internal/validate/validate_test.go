@@ -0,0 +1,172 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/kamalm96/datasleuth/internal/profiler"
+)
+
+func createBaseline() *profiler.DatasetProfile {
+	return &profiler.DatasetProfile{
+		Filename:    "baseline.csv",
+		RowCount:    100,
+		ColumnCount: 2,
+		Columns: map[string]*profiler.ColumnProfile{
+			"amount": {
+				Name:         "amount",
+				DataType:     "float",
+				Count:        98,
+				MissingCount: 2,
+				IsNumeric:    true,
+				Mean:         50,
+				StdDev:       10,
+				HistogramBuckets: []profiler.HistogramBucket{
+					{LowerBound: 0, UpperBound: 50, Count: 49},
+					{LowerBound: 50, UpperBound: 100, Count: 49},
+				},
+			},
+			"region": {
+				Name:     "region",
+				DataType: "string",
+				Count:    100,
+				TopValues: []profiler.ValueCount{
+					{Value: "east", Count: 50},
+					{Value: "west", Count: 50},
+				},
+			},
+		},
+	}
+}
+
+func failedChecks(result *Result) map[string]bool {
+	failed := make(map[string]bool)
+	for _, check := range result.Failures() {
+		failed[check.Name+":"+check.Column] = true
+	}
+	return failed
+}
+
+func TestAgainstBaselineIdentical(t *testing.T) {
+	result := AgainstBaseline(createBaseline(), createBaseline(), DefaultTolerances())
+
+	if !result.Passed() {
+		t.Errorf("Expected validation to pass, got failures: %v", result.Failures())
+	}
+}
+
+func TestAgainstBaselineSchemaMismatch(t *testing.T) {
+	profile := createBaseline()
+	delete(profile.Columns, "region")
+	profile.Columns["amount"].DataType = "integer"
+	profile.Columns["extra"] = &profiler.ColumnProfile{Name: "extra", DataType: "string"}
+
+	failed := failedChecks(AgainstBaseline(profile, createBaseline(), DefaultTolerances()))
+
+	for _, key := range []string{"schema:region", "schema:amount", "schema:extra"} {
+		if !failed[key] {
+			t.Errorf("Expected failed check %s, got %v", key, failed)
+		}
+	}
+}
+
+func TestAgainstBaselineTolerances(t *testing.T) {
+	profile := createBaseline()
+	profile.Columns["amount"].MissingCount = 12
+	profile.Columns["amount"].Mean = 58
+	profile.Columns["region"].TopValues = []profiler.ValueCount{
+		{Value: "east", Count: 80},
+		{Value: "west", Count: 20},
+	}
+
+	failed := failedChecks(AgainstBaseline(profile, createBaseline(), DefaultTolerances()))
+
+	for _, key := range []string{"missing_rate:amount", "mean:amount", "drift:region"} {
+		if !failed[key] {
+			t.Errorf("Expected failed check %s, got %v", key, failed)
+		}
+	}
+	if failed["stddev:amount"] {
+		t.Error("Did not expect stddev check to fail")
+	}
+
+	loose := Tolerances{MissingRate: 20, MeanShift: 1, StdDevChange: 0.25, Drift: 0.5}
+	if result := AgainstBaseline(profile, createBaseline(), loose); !result.Passed() {
+		t.Errorf("Expected validation to pass with loose tolerances, got %v", result.Failures())
+	}
+}
+
+func TestAgainstBaselineRowCount(t *testing.T) {
+	profile := createBaseline()
+	profile.RowCount = 150
+
+	if result := AgainstBaseline(profile, createBaseline(), DefaultTolerances()); !result.Passed() {
+		t.Errorf("Row count should not be checked by default, got %v", result.Failures())
+	}
+
+	tol := DefaultTolerances()
+	tol.RowCount = 0.2
+	if failed := failedChecks(AgainstBaseline(profile, createBaseline(), tol)); !failed["row_count:"] {
+		t.Errorf("Expected row count check to fail, got %v", failed)
+	}
+}
+
+func TestAgainstBaselineNullability(t *testing.T) {
+	baseline := createBaseline()
+	baseline.Columns["region"].Nullability = &profiler.Nullability{Kind: profiler.NullabilityNotNull, Confidence: profiler.ConfidenceMedium}
+	baseline.Columns["amount"].Nullability = &profiler.Nullability{
+		Kind:       profiler.NullabilityConditional,
+		Confidence: profiler.ConfidenceHigh,
+		Condition:  &profiler.NullCondition{Column: "region", Values: []string{"east"}, NullPercent: 100},
+	}
+
+	unchanged := createBaseline()
+	unchanged.Columns["amount"].Nullability = baseline.Columns["amount"].Nullability
+	if failed := failedChecks(AgainstBaseline(unchanged, baseline, DefaultTolerances())); len(failed) > 0 {
+		t.Errorf("Expected the nullability contracts to hold, got %v", failed)
+	}
+
+	profile := createBaseline()
+	profile.Columns["region"].MissingCount = 1
+	profile.Columns["amount"].Nullability = &profiler.Nullability{
+		Kind:       profiler.NullabilityConditional,
+		Confidence: profiler.ConfidenceHigh,
+		Condition:  &profiler.NullCondition{Column: "region", Values: []string{"east", "west"}, NullPercent: 100},
+	}
+
+	failed := failedChecks(AgainstBaseline(profile, baseline, DefaultTolerances()))
+	for _, key := range []string{"nullability:region", "nullability:amount"} {
+		if !failed[key] {
+			t.Errorf("Expected failed check %s, got %v", key, failed)
+		}
+	}
+
+	// Nulls that no longer depend on the condition column break the contract
+	profile = createBaseline()
+	profile.Columns["amount"].MissingCount = 5
+	profile.Columns["amount"].Nullability = &profiler.Nullability{Kind: profiler.NullabilityNullable, Confidence: profiler.ConfidenceHigh}
+	if failed := failedChecks(AgainstBaseline(profile, baseline, DefaultTolerances())); !failed["nullability:amount"] {
+		t.Errorf("Expected unconditional nulls to break a conditional contract, got %v", failed)
+	}
+	profile.Columns["amount"].Nullability = nil
+	if failed := failedChecks(AgainstBaseline(profile, baseline, DefaultTolerances())); !failed["nullability:amount"] {
+		t.Errorf("Expected nulls without a contract to break a conditional contract, got %v", failed)
+	}
+
+	// Low confidence contracts are not enforced
+	profile.Columns["region"].MissingCount = 1
+	baseline.Columns["region"].Nullability.Confidence = profiler.ConfidenceLow
+	if failed := failedChecks(AgainstBaseline(profile, baseline, DefaultTolerances())); failed["nullability:region"] {
+		t.Error("Expected a low confidence contract to be skipped")
+	}
+}
+
+func TestAgainstBaselineCardinality(t *testing.T) {
+	baseline := createBaseline()
+	baseline.Columns["region"].UniqueCount = 2
+	profile := createBaseline()
+	profile.Columns["region"].UniqueCount = 97
+
+	if failed := failedChecks(AgainstBaseline(profile, baseline, DefaultTolerances())); !failed["cardinality:region"] {
+		t.Errorf("Expected cardinality check to fail, got %v", failed)
+	}
+}